@@ -0,0 +1,320 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build embedded
+// +build embedded
+
+// Package embedded runs the proxy together with all the coordinators and the nodes in process, exposing the milvus
+// grpc service without the external etcd, message queue and object storage: the meta is kept in an embedded etcd,
+// the messages in rocksmq and the data in the local storage, all under one data dir. It is meant for the tests and
+// the edge deployments, and is built only with the embedded build tag.
+//
+// The coordinators and the nodes run with the lite profile of the configs, fewer threads and smaller pools than the
+// ones of a dedicated deployment, overridable by Config.Params.
+//
+// The configs and the embedded etcd are process wide, so only one server may be started in a process.
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	grpcdatacoord "github.com/milvus-io/milvus/internal/distributed/datacoord"
+	grpcdatanode "github.com/milvus-io/milvus/internal/distributed/datanode"
+	grpcindexnode "github.com/milvus-io/milvus/internal/distributed/indexnode"
+	grpcproxy "github.com/milvus-io/milvus/internal/distributed/proxy"
+	grpcquerycoord "github.com/milvus-io/milvus/internal/distributed/querycoord"
+	grpcquerynode "github.com/milvus-io/milvus/internal/distributed/querynode"
+	grpcrootcoord "github.com/milvus-io/milvus/internal/distributed/rootcoord"
+	rocksmqimpl "github.com/milvus-io/milvus/internal/mq/mqimpl/rocksmq/server"
+	"github.com/milvus-io/milvus/internal/util/dependency"
+	kvfactory "github.com/milvus-io/milvus/internal/util/dependency/kv"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/etcd"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// startTimeout is how long Start waits for the server to be healthy.
+const startTimeout = 2 * time.Minute
+
+// liteParams are the configs of the lite profile, the coordinators and the nodes share the resources of one process,
+// which is usually a test or an edge device.
+var liteParams = map[string]string{
+	"common.threadCoreCoefficient.highPriority":    "2",
+	"common.threadCoreCoefficient.middlePriority":  "1",
+	"common.threadCoreCoefficient.lowPriority":     "1",
+	"queryNode.segcore.knowhereThreadPoolNumRatio": "1",
+	"dataNode.dataSync.maxParallelSyncMgrTasks":    "16",
+	"indexNode.scheduler.buildParallel":            "1",
+}
+
+// Config is the config of the embedded server.
+type Config struct {
+	// DataDir is the dir of the embedded etcd, rocksmq and the local storage, a temp dir removed on stop if empty.
+	DataDir string
+	// Port is the port of the milvus grpc service, a free port if 0.
+	Port int
+	// Params overrides the milvus configs by their keys, such as "proxy.maxTaskNum".
+	Params map[string]string
+}
+
+type component interface {
+	Run() error
+	Stop() error
+}
+
+// Server is the embedded milvus server.
+type Server struct {
+	dataDir    string
+	removeDir  bool
+	port       int
+	proxy      *grpcproxy.Server
+	components []component
+
+	stopOnce sync.Once
+}
+
+var (
+	startMu sync.Mutex
+	started bool
+)
+
+// Start starts the embedded server and waits until it is healthy.
+func Start(ctx context.Context, cfg Config) (*Server, error) {
+	startMu.Lock()
+	defer startMu.Unlock()
+	if started {
+		return nil, errors.New("the embedded server can be started only once in a process")
+	}
+	started = true
+
+	s := &Server{dataDir: cfg.DataDir}
+	if s.dataDir == "" {
+		dir, err := os.MkdirTemp("", "milvus-embedded-")
+		if err != nil {
+			return nil, err
+		}
+		s.dataDir = dir
+		s.removeDir = true
+	}
+	if err := s.initParams(cfg); err != nil {
+		s.cleanup()
+		return nil, err
+	}
+
+	params := paramtable.Get()
+	if err := etcd.InitEtcdServer(true,
+		params.EtcdCfg.ConfigPath.GetValue(),
+		params.EtcdCfg.DataDir.GetValue(),
+		params.EtcdCfg.EtcdLogPath.GetValue(),
+		params.EtcdCfg.EtcdLogLevel.GetValue()); err != nil {
+		s.cleanup()
+		return nil, err
+	}
+
+	if err := s.startComponents(ctx); err != nil {
+		s.Stop()
+		return nil, err
+	}
+	if err := s.waitHealthy(ctx); err != nil {
+		s.Stop()
+		return nil, err
+	}
+	log.Info("embedded milvus started", zap.String("addr", s.Addr()), zap.String("dataDir", s.dataDir))
+	return s, nil
+}
+
+// initParams sets the configs of the standalone deployment with the embedded dependencies under the data dir.
+func (s *Server) initParams(cfg Config) error {
+	// the embedded etcd is allowed only in the standalone mode, which is checked when the params are initialized
+	if err := os.Setenv(metricsinfo.DeployModeEnvKey, metricsinfo.StandaloneDeployMode); err != nil {
+		return err
+	}
+	paramtable.Init()
+	params := paramtable.Get()
+
+	ports, err := getFreePorts(10)
+	if err != nil {
+		return err
+	}
+	s.port = cfg.Port
+	if s.port == 0 {
+		s.port = ports[0]
+	}
+	etcdConfigPath := path.Join(s.dataDir, "etcd.yaml")
+	etcdConfig := fmt.Sprintf("name: embedded\n"+
+		"listen-client-urls: http://localhost:%[1]d\n"+
+		"advertise-client-urls: http://localhost:%[1]d\n"+
+		"listen-peer-urls: http://localhost:%[2]d\n"+
+		"initial-advertise-peer-urls: http://localhost:%[2]d\n"+
+		"initial-cluster: embedded=http://localhost:%[2]d\n", ports[1], ports[2])
+	if err := os.WriteFile(etcdConfigPath, []byte(etcdConfig), 0o600); err != nil {
+		return err
+	}
+
+	values := make(map[string]string)
+	for key, value := range liteParams {
+		values[key] = value
+	}
+	for key, value := range map[string]string{
+		params.EtcdCfg.UseEmbedEtcd.Key:            "true",
+		params.EtcdCfg.ConfigPath.Key:              etcdConfigPath,
+		params.EtcdCfg.DataDir.Key:                 path.Join(s.dataDir, "etcd"),
+		params.MQCfg.Type.Key:                      "rocksmq",
+		params.RocksmqCfg.Path.Key:                 path.Join(s.dataDir, "rocksmq"),
+		params.CommonCfg.StorageType.Key:           "local",
+		params.LocalStorageCfg.Path.Key:            path.Join(s.dataDir, "data"),
+		params.HTTPCfg.Enabled.Key:                 "false",
+		params.ProxyGrpcServerCfg.Port.Key:         strconv.Itoa(s.port),
+		params.ProxyGrpcServerCfg.InternalPort.Key: strconv.Itoa(ports[3]),
+		params.RootCoordGrpcServerCfg.Port.Key:     strconv.Itoa(ports[4]),
+		params.DataCoordGrpcServerCfg.Port.Key:     strconv.Itoa(ports[5]),
+		params.QueryCoordGrpcServerCfg.Port.Key:    strconv.Itoa(ports[6]),
+		params.DataNodeGrpcServerCfg.Port.Key:      strconv.Itoa(ports[7]),
+		params.QueryNodeGrpcServerCfg.Port.Key:     strconv.Itoa(ports[8]),
+		params.IndexNodeGrpcServerCfg.Port.Key:     strconv.Itoa(ports[9]),
+	} {
+		values[key] = value
+	}
+	for key, value := range cfg.Params {
+		values[key] = value
+	}
+	for key, value := range values {
+		params.Save(key, value)
+	}
+	paramtable.SetRole(typeutil.StandaloneRole)
+	return nil
+}
+
+// startComponents creates and runs the coordinators, the nodes and at last the proxy.
+func (s *Server) startComponents(ctx context.Context) error {
+	newFactory := func() dependency.Factory {
+		return dependency.NewFactory(true)
+	}
+
+	rootCoord, err := grpcrootcoord.NewServer(ctx, newFactory())
+	if err != nil {
+		return err
+	}
+	dataCoord := grpcdatacoord.NewServer(ctx, newFactory())
+	queryCoord, err := grpcquerycoord.NewServer(ctx, newFactory())
+	if err != nil {
+		return err
+	}
+	dataNode, err := grpcdatanode.NewServer(ctx, newFactory())
+	if err != nil {
+		return err
+	}
+	queryNode, err := grpcquerynode.NewServer(ctx, newFactory())
+	if err != nil {
+		return err
+	}
+	indexNode, err := grpcindexnode.NewServer(ctx, newFactory())
+	if err != nil {
+		return err
+	}
+	s.proxy, err = grpcproxy.NewServer(ctx, newFactory())
+	if err != nil {
+		return err
+	}
+
+	for _, c := range []component{rootCoord, dataCoord, queryCoord, dataNode, queryNode, indexNode, s.proxy} {
+		if err := c.Run(); err != nil {
+			return err
+		}
+		s.components = append(s.components, c)
+	}
+	return nil
+}
+
+func (s *Server) waitHealthy(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		resp, err := s.proxy.CheckHealth(ctx, &milvuspb.CheckHealthRequest{})
+		if err := merr.CheckRPCCall(resp, err); err == nil && resp.GetIsHealthy() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "embedded milvus is not healthy")
+		case <-ticker.C:
+		}
+	}
+}
+
+// Addr returns the address of the milvus grpc service.
+func (s *Server) Addr() string {
+	return net.JoinHostPort("localhost", strconv.Itoa(s.port))
+}
+
+// MilvusService returns the milvus service to call in process without grpc.
+func (s *Server) MilvusService() milvuspb.MilvusServiceServer {
+	return s.proxy
+}
+
+// Stop stops the components in the reverse order of their start, and the embedded dependencies.
+func (s *Server) Stop() {
+	s.stopOnce.Do(func() {
+		for i := len(s.components) - 1; i >= 0; i-- {
+			if err := s.components[i].Stop(); err != nil {
+				log.Warn("failed to stop embedded milvus component", zap.Error(err))
+			}
+		}
+		kvfactory.CloseEtcdClient()
+		rocksmqimpl.CloseRocksMQ()
+		etcd.StopEtcdServer()
+		s.cleanup()
+		log.Info("embedded milvus stopped")
+	})
+}
+
+func (s *Server) cleanup() {
+	if s.removeDir {
+		if err := os.RemoveAll(s.dataDir); err != nil {
+			log.Warn("failed to remove the data dir of embedded milvus", zap.String("dataDir", s.dataDir), zap.Error(err))
+		}
+	}
+}
+
+// getFreePorts returns n distinct free ports.
+func getFreePorts(n int) ([]int, error) {
+	ports := typeutil.NewSet[int]()
+	for ports.Len() < n {
+		listener, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			return nil, err
+		}
+		ports.Insert(listener.Addr().(*net.TCPAddr).Port)
+		listener.Close()
+	}
+	return ports.Collect(), nil
+}
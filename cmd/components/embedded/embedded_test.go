@@ -0,0 +1,60 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build embedded
+// +build embedded
+
+package embedded
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestEmbeddedServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := Start(ctx, Config{DataDir: t.TempDir()})
+	require.NoError(t, err)
+	defer s.Stop()
+
+	assert.NotEmpty(t, s.Addr())
+
+	resp, err := s.MilvusService().CheckHealth(ctx, &milvuspb.CheckHealthRequest{})
+	assert.NoError(t, merr.CheckRPCCall(resp, err))
+	assert.True(t, resp.GetIsHealthy())
+
+	showResp, err := s.MilvusService().ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{})
+	assert.NoError(t, merr.CheckRPCCall(showResp, err))
+	assert.Empty(t, showResp.GetCollectionNames())
+
+	_, err = Start(ctx, Config{})
+	assert.Error(t, err)
+}
+
+func TestGetFreePorts(t *testing.T) {
+	ports, err := getFreePorts(3)
+	assert.NoError(t, err)
+	assert.Len(t, ports, 3)
+	assert.NotEqual(t, ports[0], ports[1])
+	assert.NotEqual(t, ports[1], ports[2])
+	assert.NotEqual(t, ports[0], ports[2])
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -261,6 +262,33 @@ func setupPrometheusHTTPServer(r *internalmetrics.MilvusRegistry) {
 	})
 }
 
+// setupOTLPMetricsExporter starts pushing the metrics to the OpenTelemetry collector if enabled, with the role and
+// the node id as the resource attributes.
+func setupOTLPMetricsExporter(r *internalmetrics.MilvusRegistry) *internalmetrics.OTLPExporter {
+	params := paramtable.Get()
+	if !params.MetricsCfg.OtlpEnable.GetAsBool() {
+		return nil
+	}
+	exporter, err := internalmetrics.NewOTLPExporter(r,
+		params.MetricsCfg.OtlpEndpoint.GetValue(),
+		params.MetricsCfg.OtlpSecure.GetAsBool(),
+		params.MetricsCfg.OtlpInterval.GetAsDuration(time.Second),
+		func() map[string]string {
+			return map[string]string{
+				"service.name": "milvus",
+				"role":         paramtable.GetRole(),
+				"node_id":      strconv.FormatInt(paramtable.GetNodeID(), 10),
+			}
+		})
+	if err != nil {
+		log.Warn("failed to setup the otlp metrics exporter", zap.Error(err))
+		return nil
+	}
+	log.Info("setupOTLPMetricsExporter", zap.String("endpoint", params.MetricsCfg.OtlpEndpoint.GetValue()))
+	exporter.Start()
+	return exporter
+}
+
 func (mr *MilvusRoles) handleSignals() func() {
 	sign := make(chan struct{})
 	done := make(chan struct{})
@@ -396,6 +424,10 @@ func (mr *MilvusRoles) Run() {
 	paramtable.SetCreateTime(time.Now())
 	paramtable.SetUpdateTime(time.Now())
 
+	if exporter := setupOTLPMetricsExporter(Registry); exporter != nil {
+		defer exporter.Stop()
+	}
+
 	<-mr.closed
 
 	// stop coordinators first
@@ -0,0 +1,63 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/util/ddlnotify"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+)
+
+const ddlNotificationPrefix = "datacoord/ddl_notification/pending"
+
+// notifyIndexDDL emits the index ddl executed successfully. The timestamp is allocated and the event is queued
+// under the lock, so the concurrent index ddls are emitted in the order of the timestamp.
+func (s *Server) notifyIndexDDL(ctx context.Context, msgType commonpb.MsgType, collectionID, fieldID int64, indexName string, params []*commonpb.KeyValuePair) {
+	if s.ddlNotifier == nil || !Params.CommonCfg.DDLNotificationEnabled.GetAsBool() {
+		return
+	}
+	event := &ddlnotify.Event{
+		Type:         msgType.String(),
+		CollectionID: collectionID,
+		IndexName:    indexName,
+		Properties:   funcutil.KeyValuePair2Map(params),
+	}
+	if collection := s.meta.GetCollection(collectionID); collection != nil {
+		event.DbName, event.CollectionName = collection.DatabaseName, collection.Schema.GetName()
+		for _, field := range collection.Schema.GetFields() {
+			if field.GetFieldID() == fieldID {
+				event.FieldName = field.GetName()
+			}
+		}
+	}
+
+	s.ddlNotifyMu.Lock()
+	defer s.ddlNotifyMu.Unlock()
+	ts, err := s.allocator.allocTimestamp(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to allocate the timestamp of the index ddl event, the event is dropped",
+			zap.String("type", event.Type), zap.Int64("collectionID", collectionID), zap.String("indexName", indexName), zap.Error(err))
+		return
+	}
+	event.Timestamp = ts
+	s.ddlNotifier.Notify(event)
+}
@@ -0,0 +1,81 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	memkv "github.com/milvus-io/milvus/internal/kv/mem"
+	"github.com/milvus-io/milvus/internal/util/ddlnotify"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestNotifyIndexDDL(t *testing.T) {
+	paramtable.Init()
+	metaKV := memkv.NewMemoryKV()
+	s := &Server{
+		meta: &meta{
+			collections: map[UniqueID]*collectionInfo{
+				1: {
+					ID:           1,
+					DatabaseName: "db",
+					Schema: &schemapb.CollectionSchema{
+						Name:   "coll",
+						Fields: []*schemapb.FieldSchema{{FieldID: 100, Name: "vec"}},
+					},
+				},
+			},
+		},
+		allocator:   newMockAllocator(),
+		ddlNotifier: ddlnotify.NewNotifier(metaKV, ddlNotificationPrefix),
+	}
+
+	// not persisted if disabled
+	s.notifyIndexDDL(context.Background(), commonpb.MsgType_CreateIndex, 1, 100, "idx", nil)
+	_, values, err := metaKV.LoadWithPrefix(ddlNotificationPrefix)
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+
+	paramtable.Get().Save(Params.CommonCfg.DDLNotificationEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.CommonCfg.DDLNotificationEnabled.Key)
+	s.notifyIndexDDL(context.Background(), commonpb.MsgType_CreateIndex, 1, 100, "idx",
+		[]*commonpb.KeyValuePair{{Key: "index_type", Value: "HNSW"}})
+	s.notifyIndexDDL(context.Background(), commonpb.MsgType_DropIndex, 1, 100, "idx", nil)
+	_, values, err = metaKV.LoadWithPrefix(ddlNotificationPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(values))
+	events := make([]*ddlnotify.Event, 0, len(values))
+	for _, value := range values {
+		event := &ddlnotify.Event{}
+		assert.NoError(t, json.Unmarshal([]byte(value), event))
+		events = append(events, event)
+	}
+	assert.Equal(t, "CreateIndex", events[0].Type)
+	assert.Equal(t, "db", events[0].DbName)
+	assert.Equal(t, "coll", events[0].CollectionName)
+	assert.Equal(t, "vec", events[0].FieldName)
+	assert.Equal(t, "idx", events[0].IndexName)
+	assert.Equal(t, map[string]string{"index_type": "HNSW"}, events[0].Properties)
+	assert.Equal(t, "DropIndex", events[1].Type)
+	assert.Less(t, events[0].Timestamp, events[1].Timestamp)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/milvus-io/milvus/internal/datacoord/broker"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/util/importutilv2"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/util/tsoutil"
@@ -253,7 +254,8 @@ func (c *importChecker) checkImportingJob(job ImportJob) {
 
 	// Verify completion of index building for imported segments.
 	unindexed := c.meta.indexMeta.GetUnindexedSegments(job.GetCollectionID(), segmentIDs)
-	if Params.DataCoordCfg.WaitForIndex.GetAsBool() && len(unindexed) > 0 {
+	waitForIndex := Params.DataCoordCfg.WaitForIndex.GetAsBool() || importutilv2.IsWaitForIndex(job.GetOptions())
+	if waitForIndex && len(unindexed) > 0 {
 		log.Debug("waiting for import segments building index...", zap.Int64s("unindexed", unindexed))
 		return
 	}
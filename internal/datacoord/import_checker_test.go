@@ -30,8 +30,11 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	broker2 "github.com/milvus-io/milvus/internal/datacoord/broker"
 	"github.com/milvus-io/milvus/internal/metastore/mocks"
+	"github.com/milvus-io/milvus/internal/metastore/model"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/util/importutilv2"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/tsoutil"
 )
 
@@ -208,6 +211,60 @@ func (s *ImportCheckerSuite) TestCheckJob() {
 	s.Equal(internalpb.ImportJobState_Completed, s.imeta.GetJob(job.GetJobID()).GetState())
 }
 
+func (s *ImportCheckerSuite) TestCheckImportingJob_WaitForIndex() {
+	paramtable.Get().Save(Params.DataCoordCfg.WaitForIndex.Key, "false")
+	defer paramtable.Get().Reset(Params.DataCoordCfg.WaitForIndex.Key)
+
+	catalog := s.imeta.(*importMeta).catalog.(*mocks.DataCoordCatalog)
+	catalog.EXPECT().SaveImportJob(mock.Anything).Return(nil)
+	catalog.EXPECT().SaveImportTask(mock.Anything).Return(nil)
+	catalog.EXPECT().AddSegment(mock.Anything, mock.Anything).Return(nil)
+	catalog.EXPECT().AlterSegments(mock.Anything, mock.Anything).Return(nil)
+	catalog.EXPECT().SaveChannelCheckpoint(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	job := s.imeta.GetJob(s.jobID)
+	s.checker.meta.indexMeta.indexes[job.GetCollectionID()] = map[UniqueID]*model.Index{
+		100: {CollectionID: job.GetCollectionID(), FieldID: 101, IndexID: 100, IndexName: "_default_idx"},
+	}
+	segment := &SegmentInfo{
+		SegmentInfo: &datapb.SegmentInfo{
+			ID:            rand.Int63(),
+			CollectionID:  job.GetCollectionID(),
+			State:         commonpb.SegmentState_Flushed,
+			IsImporting:   true,
+			InsertChannel: "ch0",
+		},
+	}
+	s.NoError(s.checker.meta.AddSegment(context.Background(), segment))
+	s.NoError(s.checker.meta.UpdateChannelCheckpoint(segment.GetInsertChannel(), &msgpb.MsgPosition{MsgID: []byte{0}}))
+	s.NoError(s.imeta.AddTask(&importTask{
+		ImportTaskV2: &datapb.ImportTaskV2{
+			JobID:      job.GetJobID(),
+			TaskID:     1,
+			SegmentIDs: []int64{segment.GetID()},
+			State:      datapb.ImportTaskStateV2_Completed,
+		},
+	}))
+
+	// wait for the index of the imported segment built
+	setOptions := func(options ...*commonpb.KeyValuePair) {
+		s.NoError(s.imeta.UpdateJob(job.GetJobID(), UpdateJobState(internalpb.ImportJobState_Importing), func(job ImportJob) {
+			job.(*importJob).ImportJob.Options = options
+		}))
+	}
+	setOptions(&commonpb.KeyValuePair{Key: importutilv2.AutoLoadFlag, Value: "true"})
+	s.checker.checkImportingJob(s.imeta.GetJob(job.GetJobID()))
+	s.Equal(internalpb.ImportJobState_Importing, s.imeta.GetJob(job.GetJobID()).GetState())
+
+	setOptions(&commonpb.KeyValuePair{Key: importutilv2.WaitForIndexFlag, Value: "true"})
+	s.checker.checkImportingJob(s.imeta.GetJob(job.GetJobID()))
+	s.Equal(internalpb.ImportJobState_Importing, s.imeta.GetJob(job.GetJobID()).GetState())
+
+	setOptions()
+	s.checker.checkImportingJob(s.imeta.GetJob(job.GetJobID()))
+	s.Equal(internalpb.ImportJobState_Completed, s.imeta.GetJob(job.GetJobID()).GetState())
+}
+
 func (s *ImportCheckerSuite) TestCheckJob_Failed() {
 	mockErr := errors.New("mock err")
 	job := s.imeta.GetJob(s.jobID)
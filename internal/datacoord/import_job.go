@@ -25,6 +25,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/util/importutilv2"
 	"github.com/milvus-io/milvus/pkg/util/tsoutil"
 )
 
@@ -48,6 +49,8 @@ func UpdateJobState(state internalpb.ImportJobState) UpdateJobAction {
 			dur := Params.DataCoordCfg.ImportTaskRetention.GetAsDuration(time.Second)
 			cleanupTs := tsoutil.ComposeTSByTime(time.Now().Add(dur), 0)
 			job.(*importJob).ImportJob.CleanupTs = cleanupTs
+			// the credentials of the external storage are not kept once the job is done
+			job.(*importJob).ImportJob.Options = importutilv2.RemoveStorageCredentials(job.GetOptions())
 		}
 	}
 }
@@ -23,9 +23,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/internal/metastore/mocks"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/util/importutilv2"
 )
 
 func TestImportMeta_Restore(t *testing.T) {
@@ -88,6 +90,11 @@ func TestImportMeta_ImportJob(t *testing.T) {
 			PartitionIDs: []int64{2},
 			Vchannels:    []string{"ch0"},
 			State:        internalpb.ImportJobState_Pending,
+			Options: []*commonpb.KeyValuePair{
+				{Key: importutilv2.StorageBucket, Value: "bucket"},
+				{Key: importutilv2.StorageAccessKeyID, Value: "ak"},
+				{Key: importutilv2.StorageSecretAccessKey, Value: "sk"},
+			},
 		},
 	}
 
@@ -109,6 +116,9 @@ func TestImportMeta_ImportJob(t *testing.T) {
 	assert.Equal(t, job.GetCollectionID(), job2.GetCollectionID())
 	assert.Equal(t, job.GetPartitionIDs(), job2.GetPartitionIDs())
 	assert.Equal(t, job.GetVchannels(), job2.GetVchannels())
+	// the credentials are removed once the job is done
+	assert.Equal(t, []*commonpb.KeyValuePair{{Key: importutilv2.StorageBucket, Value: "bucket"}}, job2.GetOptions())
+	assert.Equal(t, 3, len(job.GetOptions()))
 
 	err = im.RemoveJob(job.GetJobID())
 	assert.NoError(t, err)
@@ -263,6 +263,7 @@ func (s *Server) CreateIndex(ctx context.Context, req *indexpb.CreateIndexReques
 		zap.String("IndexName", req.GetIndexName()), zap.Int64("fieldID", req.GetFieldID()),
 		zap.Int64("IndexID", indexID))
 	metrics.IndexRequestCounter.WithLabelValues(metrics.SuccessLabel).Inc()
+	s.notifyIndexDDL(ctx, commonpb.MsgType_CreateIndex, req.GetCollectionID(), req.GetFieldID(), req.GetIndexName(), req.GetUserIndexParams())
 	return merr.Success(), nil
 }
 
@@ -355,6 +356,9 @@ func (s *Server) AlterIndex(ctx context.Context, req *indexpb.AlterIndexRequest)
 		return merr.Status(err), nil
 	}
 
+	for _, index := range indexes {
+		s.notifyIndexDDL(ctx, commonpb.MsgType_AlterIndex, req.GetCollectionID(), index.FieldID, index.IndexName, req.GetParams())
+	}
 	return merr.Success(), nil
 }
 
@@ -834,6 +838,11 @@ func (s *Server) DropIndex(ctx context.Context, req *indexpb.DropIndexRequest) (
 
 	log.Debug("DropIndex success", zap.Int64s("partitionIDs", req.GetPartitionIDs()),
 		zap.String("indexName", req.GetIndexName()), zap.Int64s("indexIDs", indexIDs))
+	if len(req.GetPartitionIDs()) == 0 {
+		for _, index := range indexes {
+			s.notifyIndexDDL(ctx, commonpb.MsgType_DropIndex, req.GetCollectionID(), index.FieldID, index.IndexName, nil)
+		}
+	}
 	return merr.Success(), nil
 }
 
@@ -18,6 +18,7 @@ package datacoord
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/cockroachdb/errors"
 	"go.uber.org/zap"
@@ -25,6 +26,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/hardware"
@@ -140,6 +142,34 @@ func (s *Server) getSystemInfoMetrics(
 	return resp, nil
 }
 
+// getImportSegmentsMetrics returns the segments of the import jobs not completed, the segments of the importing jobs
+// are unset importing one by one when the jobs complete, the searches may exclude them until the jobs complete.
+func (s *Server) getImportSegmentsMetrics() (*milvuspb.GetMetricsResponse, error) {
+	importSegments := metricsinfo.ImportSegments{
+		Collections: make(map[int64][]int64),
+	}
+	jobs := s.importMeta.GetJobBy(func(job ImportJob) bool {
+		return job.GetState() == internalpb.ImportJobState_Importing
+	})
+	for _, job := range jobs {
+		tasks := s.importMeta.GetTaskBy(WithType(ImportTaskType), WithJob(job.GetJobID()))
+		for _, task := range tasks {
+			importSegments.Collections[job.GetCollectionID()] = append(importSegments.Collections[job.GetCollectionID()],
+				task.(*importTask).GetSegmentIDs()...)
+		}
+	}
+	resp := &milvuspb.GetMetricsResponse{
+		Status:        merr.Success(),
+		ComponentName: metricsinfo.ConstructComponentName(typeutil.DataCoordRole, paramtable.GetNodeID()),
+	}
+	bs, err := json.Marshal(importSegments)
+	if err != nil {
+		return nil, err
+	}
+	resp.Response = string(bs)
+	return resp, nil
+}
+
 // getDataCoordMetrics composes datacoord infos
 func (s *Server) getDataCoordMetrics(ctx context.Context) metricsinfo.DataCoordInfos {
 	ret := metricsinfo.DataCoordInfos{
@@ -18,6 +18,7 @@ package datacoord
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/cockroachdb/errors"
@@ -25,6 +26,9 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/metastore/mocks"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
@@ -198,3 +202,32 @@ func TestGetIndexNodeMetrics(t *testing.T) {
 	assert.False(t, info.HasError)
 	assert.Equal(t, metricsinfo.ConstructComponentName(typeutil.IndexNodeRole, 100), info.BaseComponentInfos.Name)
 }
+
+func TestGetImportSegmentsMetrics(t *testing.T) {
+	catalog := mocks.NewDataCoordCatalog(t)
+	catalog.EXPECT().ListImportJobs().Return([]*datapb.ImportJob{
+		{JobID: 1, CollectionID: 100, State: internalpb.ImportJobState_Importing},
+		{JobID: 2, CollectionID: 100, State: internalpb.ImportJobState_Completed},
+		{JobID: 3, CollectionID: 200, State: internalpb.ImportJobState_Importing},
+	}, nil)
+	catalog.EXPECT().ListPreImportTasks().Return([]*datapb.PreImportTask{{JobID: 1, TaskID: 10}}, nil)
+	catalog.EXPECT().ListImportTasks().Return([]*datapb.ImportTaskV2{
+		{JobID: 1, TaskID: 11, SegmentIDs: []int64{1001, 1002}},
+		{JobID: 1, TaskID: 12, SegmentIDs: []int64{1003}},
+		{JobID: 2, TaskID: 13, SegmentIDs: []int64{1004}},
+		{JobID: 3, TaskID: 14, SegmentIDs: []int64{2001}},
+	}, nil)
+	importMeta, err := NewImportMeta(catalog)
+	assert.NoError(t, err)
+
+	svr := &Server{importMeta: importMeta}
+	resp, err := svr.getImportSegmentsMetrics()
+	assert.NoError(t, err)
+	assert.True(t, merr.Ok(resp.GetStatus()))
+
+	importSegments := metricsinfo.ImportSegments{}
+	assert.NoError(t, json.Unmarshal([]byte(resp.GetResponse()), &importSegments))
+	assert.Len(t, importSegments.Collections, 2)
+	assert.ElementsMatch(t, []int64{1001, 1002, 1003}, importSegments.Collections[100])
+	assert.ElementsMatch(t, []int64{2001}, importSegments.Collections[200])
+}
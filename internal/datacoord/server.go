@@ -45,6 +45,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/ddlnotify"
 	"github.com/milvus-io/milvus/internal/util/dependency"
 	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/pkg/log"
@@ -124,6 +125,10 @@ type Server struct {
 	importScheduler  ImportScheduler
 	importChecker    ImportChecker
 
+	// ddlNotifier emits the index ddls, ddlNotifyMu orders the concurrent ones by the timestamp
+	ddlNotifier *ddlnotify.Notifier
+	ddlNotifyMu sync.Mutex
+
 	compactionTrigger     trigger
 	compactionHandler     compactionPlanContext
 	compactionViewManager *CompactionViewManager
@@ -355,6 +360,9 @@ func (s *Server) initDataCoord() error {
 		return err
 	}
 
+	if s.kv != nil {
+		s.ddlNotifier = ddlnotify.NewNotifier(s.kv, ddlNotificationPrefix)
+	}
 	s.handler = newServerHandler(s)
 
 	// check whether old node exist, if yes suspend auto balance until all old nodes down
@@ -704,6 +712,9 @@ func (s *Server) startServerLoop() {
 	go s.importScheduler.Start()
 	go s.importChecker.Start()
 	s.garbageCollector.start()
+	if err := s.ddlNotifier.Start(s.serverLoopCtx); err != nil {
+		log.Warn("failed to start the ddl notification", zap.Error(err))
+	}
 }
 
 // startDataNodeTtLoop start a goroutine to recv data node tt msg from msgstream
@@ -1094,6 +1105,7 @@ func (s *Server) Stop() error {
 
 	s.importScheduler.Close()
 	s.importChecker.Close()
+	s.ddlNotifier.Close()
 
 	if Params.DataCoordCfg.EnableCompaction.GetAsBool() {
 		s.stopCompactionTrigger()
@@ -1056,6 +1056,17 @@ func (s *Server) GetMetrics(ctx context.Context, req *milvuspb.GetMetricsRequest
 		return metrics, nil
 	}
 
+	if metricType == metricsinfo.ImportSegmentsMetrics {
+		metrics, err := s.getImportSegmentsMetrics()
+		if err != nil {
+			log.Warn("DataCoord GetMetrics failed to get import segments", zap.Error(err))
+			return &milvuspb.GetMetricsResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+		return metrics, nil
+	}
+
 	log.RatedWarn(60.0, "DataCoord.GetMetrics failed, request metric type is not implemented yet",
 		zap.Int64("nodeID", paramtable.GetNodeID()),
 		zap.String("req", req.Request),
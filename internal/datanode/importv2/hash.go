@@ -17,11 +17,15 @@
 package importv2
 
 import (
+	"fmt"
+
 	"github.com/samber/lo"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/importutilv2"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
@@ -53,10 +57,11 @@ func HashData(task Task, rows *storage.InsertData) (HashedData, error) {
 	if err != nil {
 		return nil, err
 	}
-	partKeyField, _ := typeutil.GetPartitionKeyFieldSchema(schema)
-
 	f1 := hashByVChannel(int64(channelNum), pkField)
-	f2 := hashByPartition(int64(partitionNum), partKeyField)
+	f2, err := partitionHasher(task, schema)
+	if err != nil {
+		return nil, err
+	}
 
 	res, err := newHashedData(schema, channelNum, partitionNum)
 	if err != nil {
@@ -65,7 +70,11 @@ func HashData(task Task, rows *storage.InsertData) (HashedData, error) {
 
 	for i := 0; i < rows.GetRowNum(); i++ {
 		row := rows.GetRow(i)
-		p1, p2 := f1(row), f2(row)
+		p1 := f1(row)
+		p2, err := f2(row)
+		if err != nil {
+			return nil, err
+		}
 		err = res[p1][p2].Append(row)
 		if err != nil {
 			return nil, err
@@ -85,7 +94,10 @@ func GetRowsStats(task Task, rows *storage.InsertData) (map[string]*datapb.Parti
 	if err != nil {
 		return nil, err
 	}
-	partKeyField, _ := typeutil.GetPartitionKeyFieldSchema(schema)
+	fn2, err := partitionHasher(task, schema)
+	if err != nil {
+		return nil, err
+	}
 
 	hashRowsCount := make([][]int, channelNum)
 	hashDataSize := make([][]int, channelNum)
@@ -99,22 +111,28 @@ func GetRowsStats(task Task, rows *storage.InsertData) (map[string]*datapb.Parti
 		id := int64(0)
 		num := int64(channelNum)
 		fn1 := hashByID()
-		fn2 := hashByPartition(int64(partitionNum), partKeyField)
 		rows.Data = lo.PickBy(rows.Data, func(fieldID int64, _ storage.FieldData) bool {
 			return fieldID != pkField.GetFieldID()
 		})
 		for i := 0; i < rowNum; i++ {
-			p1, p2 := fn1(id, num), fn2(rows.GetRow(i))
+			p1 := fn1(id, num)
+			p2, err := fn2(rows.GetRow(i))
+			if err != nil {
+				return nil, err
+			}
 			hashRowsCount[p1][p2]++
 			hashDataSize[p1][p2] += rows.GetRowSize(i)
 			id++
 		}
 	} else {
 		f1 := hashByVChannel(int64(channelNum), pkField)
-		f2 := hashByPartition(int64(partitionNum), partKeyField)
 		for i := 0; i < rowNum; i++ {
 			row := rows.GetRow(i)
-			p1, p2 := f1(row), f2(row)
+			p1 := f1(row)
+			p2, err := fn2(row)
+			if err != nil {
+				return nil, err
+			}
 			hashRowsCount[p1][p2]++
 			hashDataSize[p1][p2] += rows.GetRowSize(i)
 		}
@@ -186,6 +204,47 @@ func hashByPartition(partitionNum int64, partField *schemapb.FieldSchema) func(r
 	}
 }
 
+// partitionHasher returns the function to pick the index of the partition in the task to import the row into,
+// by the partition column if specified in the import options, otherwise by the partition key.
+func partitionHasher(task Task, schema *schemapb.CollectionSchema) (func(row map[int64]interface{}) (int64, error), error) {
+	column, names, err := importutilv2.ParsePartitionColumn(task.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+	if column == "" {
+		partKeyField, _ := typeutil.GetPartitionKeyFieldSchema(schema)
+		f := hashByPartition(int64(len(task.GetPartitionIDs())), partKeyField)
+		return func(row map[int64]interface{}) (int64, error) {
+			return f(row), nil
+		}, nil
+	}
+	field := typeutil.GetFieldByName(schema, column)
+	if field == nil {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("partition column %s not found", column))
+	}
+	if len(names) != len(task.GetPartitionIDs()) {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("%d partitions expected by %s, but got %d",
+			len(names), importutilv2.PartitionNames, len(task.GetPartitionIDs())))
+	}
+	return hashByPartitionColumn(field, names), nil
+}
+
+func hashByPartitionColumn(field *schemapb.FieldSchema, names []string) func(row map[int64]interface{}) (int64, error) {
+	indexes := make(map[string]int64, len(names))
+	for i, name := range names {
+		indexes[name] = int64(i)
+	}
+	return func(row map[int64]interface{}) (int64, error) {
+		value := fmt.Sprint(row[field.GetFieldID()])
+		index, ok := indexes[value]
+		if !ok {
+			return 0, merr.WrapErrImportFailed(fmt.Sprintf("the value %s of partition column %s is not in the partitions %v",
+				value, field.GetName(), names))
+		}
+		return index, nil
+	}
+}
+
 func hashByID() func(id int64, shardNum int64) int64 {
 	return func(id int64, shardNum int64) int64 {
 		hash, _ := typeutil.Hash32Int64(id)
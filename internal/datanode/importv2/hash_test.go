@@ -0,0 +1,69 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/importutilv2"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestGetRowsStats_PartitionColumn(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "tenant", DataType: schemapb.DataType_VarChar},
+		},
+	}
+	newTask := func(partitionNames string, partitionIDs ...int64) Task {
+		return NewPreImportTask(&datapb.PreImportRequest{
+			Schema:       schema,
+			Vchannels:    []string{"ch0"},
+			PartitionIDs: partitionIDs,
+			Options: []*commonpb.KeyValuePair{
+				{Key: importutilv2.PartitionColumn, Value: "tenant"},
+				{Key: importutilv2.PartitionNames, Value: partitionNames},
+			},
+		})
+	}
+	newRows := func() *storage.InsertData {
+		return &storage.InsertData{Data: map[int64]storage.FieldData{
+			100: &storage.Int64FieldData{Data: []int64{1, 2, 3}},
+			101: &storage.StringFieldData{Data: []string{"a", "b", "a"}},
+		}}
+	}
+
+	stats, err := GetRowsStats(newTask("a,b", 10, 20), newRows())
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, stats["ch0"].GetPartitionRows()[10])
+	assert.EqualValues(t, 1, stats["ch0"].GetPartitionRows()[20])
+
+	// the value not in the partitions
+	_, err = GetRowsStats(newTask("a", 10), newRows())
+	assert.ErrorIs(t, err, merr.ErrImportFailed)
+
+	// the partitions mismatched
+	_, err = GetRowsStats(newTask("a,b", 10), newRows())
+	assert.ErrorIs(t, err, merr.ErrImportFailed)
+}
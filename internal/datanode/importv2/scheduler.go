@@ -144,6 +144,15 @@ func tryFreeFutures(futures map[int64][]*conc.Future[any]) {
 	}
 }
 
+// getChunkManager returns the chunk manager of the external storage if specified in the import options,
+// otherwise the chunk manager of the cluster.
+func (s *scheduler) getChunkManager(task Task) (storage.ChunkManager, error) {
+	if importutilv2.IsExternalStorage(task.GetOptions()) {
+		return importutilv2.NewExternalChunkManager(task.GetCtx(), task.GetOptions())
+	}
+	return s.cm, nil
+}
+
 func (s *scheduler) handleErr(task Task, err error, msg string) {
 	log.Warn(msg, WrapLogFields(task, zap.Error(err))...)
 	s.manager.Update(task.GetTaskID(), UpdateState(datapb.ImportTaskStateV2_Failed), UpdateReason(err.Error()))
@@ -161,7 +170,12 @@ func (s *scheduler) PreImport(task Task) []*conc.Future[any] {
 		})
 
 	fn := func(i int, file *internalpb.ImportFile) error {
-		reader, err := importutilv2.NewReader(task.GetCtx(), s.cm, task.GetSchema(), file, task.GetOptions(), bufferSize)
+		cm, err := s.getChunkManager(task)
+		if err != nil {
+			s.handleErr(task, err, "get chunk manager failed")
+			return err
+		}
+		reader, err := importutilv2.NewReader(task.GetCtx(), cm, task.GetSchema(), file, task.GetOptions(), bufferSize)
 		if err != nil {
 			s.handleErr(task, err, "new reader failed")
 			return err
@@ -250,7 +264,12 @@ func (s *scheduler) Import(task Task) []*conc.Future[any] {
 	req := task.(*ImportTask).req
 
 	fn := func(file *internalpb.ImportFile) error {
-		reader, err := importutilv2.NewReader(task.GetCtx(), s.cm, task.GetSchema(), file, task.GetOptions(), bufferSize)
+		cm, err := s.getChunkManager(task)
+		if err != nil {
+			s.handleErr(task, err, "get chunk manager failed")
+			return err
+		}
+		reader, err := importutilv2.NewReader(task.GetCtx(), cm, task.GetSchema(), file, task.GetOptions(), bufferSize)
 		if err != nil {
 			s.handleErr(task, err, fmt.Sprintf("new reader failed, file: %s", file.String()))
 			return err
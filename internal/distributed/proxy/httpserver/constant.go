@@ -32,10 +32,13 @@ const (
 	GetAction            = "get"
 	DeleteAction         = "delete"
 	InsertAction         = "insert"
+	InsertStreamAction   = "insert_stream"
+	SubscribeAction      = "subscribe"
 	UpsertAction         = "upsert"
 	SearchAction         = "search"
 	AdvancedSearchAction = "advanced_search"
 	HybridSearchAction   = "hybrid_search"
+	BatchSearchAction    = "batch_search"
 
 	UpdatePasswordAction  = "update_password"
 	GrantRoleAction       = "grant_role"
@@ -59,6 +62,8 @@ const (
 	VectorQueryPath               = "/vector/query"
 	VectorDeletePath              = "/vector/delete"
 
+	DebugPathPrefix = "/debug"
+
 	ShardNumDefault = 1
 
 	EnableDynamic = true
@@ -113,6 +118,10 @@ const (
 
 	HTTPReturnRowCount = "rowCount"
 
+	HTTPReturnWatermark = "watermark"
+	HTTPReturnTimestamp = "timestamp"
+	HTTPReturnFinished  = "finished"
+
 	HTTPReturnObjectType = "objectType"
 	HTTPReturnObjectName = "objectName"
 	HTTPReturnPrivilege  = "privilege"
@@ -136,4 +145,8 @@ const (
 	ParamRangeFilter  = "range_filter"
 	ParamGroupByField = "group_by_field"
 	BoundedTimestamp  = 2
+
+	ParamEnrichCollection   = "enrich_collection"
+	ParamEnrichKeyField     = "enrich_key_field"
+	ParamEnrichOutputFields = "enrich_output_fields"
 )
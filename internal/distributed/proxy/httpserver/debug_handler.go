@@ -0,0 +1,44 @@
+package httpserver
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/proxy"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// RegisterDebugRoutes registers the pprof, trace and expvar endpoints under the debug path prefix,
+// the router should have the authentication middleware installed when authorization is enabled.
+func RegisterDebugRoutes(router gin.IRouter) {
+	debug := router.Group(DebugPathPrefix, checkAdminPrivilege)
+	debug.GET("/pprof/", gin.WrapF(pprof.Index))
+	debug.GET("/pprof/cmdline", gin.WrapF(pprof.Cmdline))
+	debug.GET("/pprof/profile", gin.WrapF(pprof.Profile))
+	debug.GET("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	debug.POST("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	debug.GET("/pprof/trace", gin.WrapF(pprof.Trace))
+	debug.GET("/pprof/:name", func(c *gin.Context) {
+		pprof.Handler(c.Param("name")).ServeHTTP(c.Writer, c.Request)
+	})
+	debug.GET("/vars", gin.WrapH(expvar.Handler()))
+}
+
+// checkAdminPrivilege only allows the root user and the users with the admin role when authorization is enabled.
+func checkAdminPrivilege(c *gin.Context) {
+	if !proxy.Params.CommonCfg.AuthorizationEnabled.GetAsBool() {
+		return
+	}
+	username := c.GetString(ContextUsername)
+	isAdmin, err := proxy.IsAdminUser(username)
+	if err != nil || !isAdmin {
+		log.Warn("access to debug endpoints denied", zap.String("username", username), zap.String("path", c.Request.URL.Path), zap.Error(err))
+		err := merr.WrapErrPrivilegeNotPermitted("admin privilege is required to access %s", c.Request.URL.Path)
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{HTTPReturnCode: merr.Code(err), HTTPReturnMessage: err.Error()})
+	}
+}
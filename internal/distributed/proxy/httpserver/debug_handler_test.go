@@ -0,0 +1,49 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/internal/proxy"
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestDebugRoutes(t *testing.T) {
+	paramtable.Init()
+
+	newRouter := func(username string) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set(ContextUsername, username)
+		})
+		RegisterDebugRoutes(router)
+		return router
+	}
+	get := func(router *gin.Engine, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("authorization disabled", func(t *testing.T) {
+		router := newRouter("")
+		assert.Equal(t, http.StatusOK, get(router, DebugPathPrefix+"/vars").Code)
+		assert.Equal(t, http.StatusOK, get(router, DebugPathPrefix+"/pprof/").Code)
+		assert.Equal(t, http.StatusOK, get(router, DebugPathPrefix+"/pprof/goroutine").Code)
+		assert.Equal(t, http.StatusOK, get(router, DebugPathPrefix+"/pprof/cmdline").Code)
+	})
+
+	t.Run("authorization enabled", func(t *testing.T) {
+		paramtable.Get().Save(proxy.Params.CommonCfg.AuthorizationEnabled.Key, "true")
+		defer paramtable.Get().Reset(proxy.Params.CommonCfg.AuthorizationEnabled.Key)
+
+		assert.Equal(t, http.StatusOK, get(newRouter(util.UserRoot), DebugPathPrefix+"/vars").Code)
+		assert.Equal(t, http.StatusForbidden, get(newRouter("foo"), DebugPathPrefix+"/vars").Code)
+		assert.Equal(t, http.StatusForbidden, get(newRouter("foo"), DebugPathPrefix+"/pprof/heap").Code)
+	})
+}
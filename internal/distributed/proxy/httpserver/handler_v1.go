@@ -82,6 +82,15 @@ func NewHandlersV1(proxyComponent types.ProxyComponent) *HandlersV1 {
 			}
 			return handler(ctx, req)
 		})
+	h.interceptors = append(h.interceptors,
+		// reject the suspended operations
+		func(ctx context.Context, ginCtx *gin.Context, req any, handler func(reqCtx context.Context, req any) (any, error)) (any, error) {
+			if err := proxy.CheckOperationSuspended(ctx, req); err != nil {
+				ginCtx.AbortWithStatusJSON(http.StatusOK, gin.H{HTTPReturnCode: merr.Code(err), HTTPReturnMessage: err.Error()})
+				return nil, RestRequestInterceptorErr
+			}
+			return handler(ctx, req)
+		})
 	h.interceptors = append(h.interceptors,
 		// trace request
 		func(ctx context.Context, ginCtx *gin.Context, req any, handler func(reqCtx context.Context, req any) (any, error)) (any, error) {
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 	"github.com/gin-gonic/gin"
@@ -57,7 +58,7 @@ func (h *HandlersV2) RegisterRoutesToV2(router gin.IRouter) {
 	router.POST(CollectionCategory+CreateAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionReq{AutoID: DisableAutoID} }, wrapperTraceLog(h.wrapperCheckDatabase(h.createCollection)))))
 	router.POST(CollectionCategory+DropAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.wrapperCheckDatabase(h.dropCollection)))))
 	router.POST(CollectionCategory+RenameAction, timeoutMiddleware(wrapperPost(func() any { return &RenameCollectionReq{} }, wrapperTraceLog(h.wrapperCheckDatabase(h.renameCollection)))))
-	router.POST(CollectionCategory+LoadAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.wrapperCheckDatabase(h.loadCollection)))))
+	router.POST(CollectionCategory+LoadAction, timeoutMiddleware(wrapperPost(func() any { return &LoadCollectionReq{} }, wrapperTraceLog(h.wrapperCheckDatabase(h.loadCollection)))))
 	router.POST(CollectionCategory+ReleaseAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.wrapperCheckDatabase(h.releaseCollection)))))
 
 	router.POST(EntityCategory+QueryAction, timeoutMiddleware(wrapperPost(func() any {
@@ -77,6 +78,14 @@ func (h *HandlersV2) RegisterRoutesToV2(router gin.IRouter) {
 	router.POST(EntityCategory+InsertAction, timeoutMiddleware(wrapperPost(func() any {
 		return &CollectionDataReq{}
 	}, wrapperTraceLog(h.wrapperCheckDatabase(h.insert)))))
+	// the streaming insert is long-lived, so it is not bounded by the request timeout
+	router.POST(EntityCategory+InsertStreamAction, wrapperStream(func() any {
+		return &StreamInsertReq{}
+	}, wrapperTraceLog(h.wrapperCheckDatabase(h.insertStream))))
+	// the subscription is long-lived as well, it lasts until the client disconnects
+	router.POST(EntityCategory+SubscribeAction, wrapperPost(func() any {
+		return &SubscribeReq{}
+	}, wrapperTraceLog(h.wrapperCheckDatabase(h.subscribe))))
 	router.POST(EntityCategory+UpsertAction, timeoutMiddleware(wrapperPost(func() any {
 		return &CollectionDataReq{}
 	}, wrapperTraceLog(h.wrapperCheckDatabase(h.upsert)))))
@@ -95,6 +104,9 @@ func (h *HandlersV2) RegisterRoutesToV2(router gin.IRouter) {
 			Limit: 100,
 		}
 	}, wrapperTraceLog(h.wrapperCheckDatabase(h.advancedSearch)))))
+	router.POST(EntityCategory+BatchSearchAction, timeoutMiddleware(wrapperPost(func() any {
+		return &BatchSearchReq{}
+	}, wrapperTraceLog(h.wrapperCheckDatabase(h.batchSearch)))))
 
 	router.POST(PartitionCategory+ListAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.wrapperCheckDatabase(h.listPartitions)))))
 	router.POST(PartitionCategory+HasAction, timeoutMiddleware(wrapperPost(func() any { return &PartitionReq{} }, wrapperTraceLog(h.wrapperCheckDatabase(h.hasPartitions)))))
@@ -102,7 +114,7 @@ func (h *HandlersV2) RegisterRoutesToV2(router gin.IRouter) {
 
 	router.POST(PartitionCategory+CreateAction, timeoutMiddleware(wrapperPost(func() any { return &PartitionReq{} }, wrapperTraceLog(h.wrapperCheckDatabase(h.createPartition)))))
 	router.POST(PartitionCategory+DropAction, timeoutMiddleware(wrapperPost(func() any { return &PartitionReq{} }, wrapperTraceLog(h.wrapperCheckDatabase(h.dropPartition)))))
-	router.POST(PartitionCategory+LoadAction, timeoutMiddleware(wrapperPost(func() any { return &PartitionsReq{} }, wrapperTraceLog(h.wrapperCheckDatabase(h.loadPartitions)))))
+	router.POST(PartitionCategory+LoadAction, timeoutMiddleware(wrapperPost(func() any { return &LoadPartitionsReq{} }, wrapperTraceLog(h.wrapperCheckDatabase(h.loadPartitions)))))
 	router.POST(PartitionCategory+ReleaseAction, timeoutMiddleware(wrapperPost(func() any { return &PartitionsReq{} }, wrapperTraceLog(h.wrapperCheckDatabase(h.releasePartitions)))))
 
 	router.POST(UserCategory+ListAction, timeoutMiddleware(wrapperPost(func() any { return &DatabaseReq{} }, wrapperTraceLog(h.listUsers))))
@@ -170,29 +182,35 @@ func wrapperPost(newReq newReqFunc, v2 handlerFuncV2) gin.HandlerFunc {
 			}
 			return
 		}
-		dbName := ""
-		if getter, ok := req.(requestutil.DBNameGetter); ok {
-			dbName = getter.GetDbName()
-		}
-		if dbName == "" {
-			dbName = c.Request.Header.Get(HTTPHeaderDBName)
-			if dbName == "" {
-				dbName = DefaultDbName
-			}
-		}
-		username, _ := c.Get(ContextUsername)
-		ctx, span := otel.Tracer(typeutil.ProxyRole).Start(context.Background(), c.Request.URL.Path)
+		ctx, span, dbName := newRequestContext(c, req)
 		defer span.End()
-		ctx = proxy.NewContextWithMetadata(ctx, username.(string), dbName)
-		traceID := span.SpanContext().TraceID().String()
-		ctx = log.WithTraceID(ctx, traceID)
-		c.Keys["traceID"] = traceID
 		log.Ctx(ctx).Debug("high level restful api, read parameters from request body, then start to handle.",
 			zap.Any("url", c.Request.URL.Path), zap.Any("request", req))
 		v2(ctx, c, req, dbName)
 	}
 }
 
+// newRequestContext starts the trace span of the request and carries the user and database in the returned context.
+func newRequestContext(c *gin.Context, req any) (context.Context, trace.Span, string) {
+	dbName := ""
+	if getter, ok := req.(requestutil.DBNameGetter); ok {
+		dbName = getter.GetDbName()
+	}
+	if dbName == "" {
+		dbName = c.Request.Header.Get(HTTPHeaderDBName)
+		if dbName == "" {
+			dbName = DefaultDbName
+		}
+	}
+	username, _ := c.Get(ContextUsername)
+	ctx, span := otel.Tracer(typeutil.ProxyRole).Start(context.Background(), c.Request.URL.Path)
+	ctx = proxy.NewContextWithMetadata(ctx, username.(string), dbName)
+	traceID := span.SpanContext().TraceID().String()
+	ctx = log.WithTraceID(ctx, traceID)
+	c.Keys["traceID"] = traceID
+	return ctx, span, dbName
+}
+
 func wrapperTraceLog(v2 handlerFuncV2) handlerFuncV2 {
 	return func(ctx context.Context, c *gin.Context, req any, dbName string) (interface{}, error) {
 		switch proxy.Params.CommonCfg.TraceLogMode.GetAsInt() {
@@ -256,6 +274,12 @@ func wrapperProxy(ctx context.Context, c *gin.Context, req any, checkAuth bool,
 			return nil, err
 		}
 	}
+	if err := proxy.CheckOperationSuspended(ctx, req); err != nil {
+		if !ignoreErr {
+			c.AbortWithStatusJSON(http.StatusOK, gin.H{HTTPReturnCode: merr.Code(err), HTTPReturnMessage: err.Error()})
+		}
+		return nil, err
+	}
 	log.Ctx(ctx).Debug("high level restful api, try to do a grpc call", zap.Any("grpcRequest", req))
 	response, err := handler(ctx, req)
 	if err == nil {
@@ -525,11 +549,20 @@ func (h *HandlersV2) renameCollection(ctx context.Context, c *gin.Context, anyRe
 	return resp, err
 }
 
+// loadMmapBase returns the msg base with the mmap option of the load request, nil if not set.
+func loadMmapBase(mmapEnabled *bool) *commonpb.MsgBase {
+	if mmapEnabled == nil {
+		return nil
+	}
+	return &commonpb.MsgBase{Properties: map[string]string{common.MmapEnabledKey: strconv.FormatBool(*mmapEnabled)}}
+}
+
 func (h *HandlersV2) loadCollection(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
-	getter, _ := anyReq.(requestutil.CollectionNameGetter)
+	httpReq := anyReq.(*LoadCollectionReq)
 	req := &milvuspb.LoadCollectionRequest{
+		Base:           loadMmapBase(httpReq.MmapEnabled),
 		DbName:         dbName,
-		CollectionName: getter.GetCollectionName(),
+		CollectionName: httpReq.CollectionName,
 	}
 	resp, err := wrapperProxy(ctx, c, req, h.checkAuth, false, func(reqCtx context.Context, req any) (interface{}, error) {
 		return h.proxy.LoadCollection(reqCtx, req.(*milvuspb.LoadCollectionRequest))
@@ -572,6 +605,13 @@ func (h *HandlersV2) query(ctx context.Context, c *gin.Context, anyReq any, dbNa
 	if httpReq.Limit > 0 {
 		req.QueryParams = append(req.QueryParams, &commonpb.KeyValuePair{Key: ParamLimit, Value: strconv.FormatInt(int64(httpReq.Limit), 10)})
 	}
+	if httpReq.Enrich != nil {
+		req.QueryParams = append(req.QueryParams,
+			&commonpb.KeyValuePair{Key: ParamEnrichCollection, Value: httpReq.Enrich.CollectionName},
+			&commonpb.KeyValuePair{Key: ParamEnrichKeyField, Value: httpReq.Enrich.KeyField},
+			&commonpb.KeyValuePair{Key: ParamEnrichOutputFields, Value: strings.Join(httpReq.Enrich.OutputFields, ",")},
+		)
+	}
 	resp, err := wrapperProxy(ctx, c, req, h.checkAuth, false, func(reqCtx context.Context, req any) (interface{}, error) {
 		return h.proxy.Query(reqCtx, req.(*milvuspb.QueryRequest))
 	})
@@ -1322,8 +1362,9 @@ func (h *HandlersV2) dropPartition(ctx context.Context, c *gin.Context, anyReq a
 }
 
 func (h *HandlersV2) loadPartitions(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
-	httpReq := anyReq.(*PartitionsReq)
+	httpReq := anyReq.(*LoadPartitionsReq)
 	req := &milvuspb.LoadPartitionsRequest{
+		Base:           loadMmapBase(httpReq.MmapEnabled),
 		DbName:         dbName,
 		CollectionName: httpReq.CollectionName,
 		PartitionNames: httpReq.PartitionNames,
@@ -0,0 +1,108 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/proxy"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// batchSearcher is implemented by the proxy, the batch search has no grpc api.
+type batchSearcher interface {
+	BatchSearch(ctx context.Context, requests []*milvuspb.SearchRequest) ([]*milvuspb.SearchResults, error)
+}
+
+const defaultBatchSearchLimit = 100
+
+func (h *HandlersV2) batchSearch(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*BatchSearchReq)
+	searcher, ok := h.proxy.(batchSearcher)
+	if !ok {
+		err := merr.WrapErrServiceUnavailable("batch search is not supported")
+		c.AbortWithStatusJSON(http.StatusOK, gin.H{HTTPReturnCode: merr.Code(err), HTTPReturnMessage: err.Error()})
+		return nil, err
+	}
+	collSchema, err := h.GetCollectionSchema(ctx, c, dbName, httpReq.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+	body, _ := c.Get(gin.BodyBytesKey)
+	searchArray := gjson.Get(string(body.([]byte)), "search").Array()
+	requests := make([]*milvuspb.SearchRequest, 0, len(httpReq.Search))
+	for i, subReq := range httpReq.Search {
+		searchParams, err := generateSearchParams(ctx, c, subReq.Params)
+		if err != nil {
+			return nil, err
+		}
+		limit := subReq.Limit
+		if limit == 0 {
+			limit = defaultBatchSearchLimit
+		}
+		searchParams = append(searchParams, &commonpb.KeyValuePair{Key: common.TopKKey, Value: strconv.FormatInt(int64(limit), 10)})
+		searchParams = append(searchParams, &commonpb.KeyValuePair{Key: ParamOffset, Value: strconv.FormatInt(int64(subReq.Offset), 10)})
+		searchParams = append(searchParams, &commonpb.KeyValuePair{Key: ParamGroupByField, Value: subReq.GroupByField})
+		searchParams = append(searchParams, &commonpb.KeyValuePair{Key: proxy.AnnsFieldKey, Value: subReq.AnnsField})
+		searchParams = append(searchParams, &commonpb.KeyValuePair{Key: ParamRoundDecimal, Value: "-1"})
+		placeholderGroup, err := generatePlaceholderGroup(ctx, searchArray[i].Raw, collSchema, subReq.AnnsField)
+		if err != nil {
+			log.Ctx(ctx).Warn("high level restful api, search with vector invalid", zap.Int("search", i), zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusOK, gin.H{
+				HTTPReturnCode:    merr.Code(merr.ErrIncorrectParameterFormat),
+				HTTPReturnMessage: merr.ErrIncorrectParameterFormat.Error() + ", error: " + err.Error(),
+			})
+			return nil, err
+		}
+		requests = append(requests, &milvuspb.SearchRequest{
+			DbName:             dbName,
+			CollectionName:     httpReq.CollectionName,
+			Dsl:                subReq.Filter,
+			PlaceholderGroup:   placeholderGroup,
+			DslType:            commonpb.DslType_BoolExprV1,
+			OutputFields:       httpReq.OutputFields,
+			PartitionNames:     httpReq.PartitionNames,
+			SearchParams:       searchParams,
+			GuaranteeTimestamp: BoundedTimestamp,
+		})
+	}
+	// the privilege of the batch is the one of a search of the collection
+	req := &milvuspb.SearchRequest{DbName: dbName, CollectionName: httpReq.CollectionName}
+	resp, err := wrapperProxy(ctx, c, req, h.checkAuth, false, func(reqCtx context.Context, req any) (interface{}, error) {
+		return searcher.BatchSearch(reqCtx, requests)
+	})
+	if err == nil {
+		allowJS, _ := strconv.ParseBool(c.Request.Header.Get(HTTPHeaderAllowInt64))
+		data := make([]gin.H, 0, len(requests))
+		for _, searchResp := range resp.([]*milvuspb.SearchResults) {
+			if err := merr.Error(searchResp.GetStatus()); err != nil {
+				data = append(data, gin.H{HTTPReturnCode: merr.Code(err), HTTPReturnMessage: err.Error()})
+				continue
+			}
+			if searchResp.GetResults().GetTopK() == int64(0) {
+				data = append(data, gin.H{HTTPReturnCode: http.StatusOK, HTTPReturnData: []interface{}{}})
+				continue
+			}
+			outputData, err := buildQueryResp(searchResp.Results.TopK, searchResp.Results.OutputFields, searchResp.Results.FieldsData, searchResp.Results.Ids, searchResp.Results.Scores, allowJS)
+			if err != nil {
+				log.Ctx(ctx).Warn("high level restful api, fail to deal with search result", zap.Any("result", searchResp.Results), zap.Error(err))
+				data = append(data, gin.H{
+					HTTPReturnCode:    merr.Code(merr.ErrInvalidSearchResult),
+					HTTPReturnMessage: merr.ErrInvalidSearchResult.Error() + ", error: " + err.Error(),
+				})
+				continue
+			}
+			data = append(data, gin.H{HTTPReturnCode: http.StatusOK, HTTPReturnData: outputData})
+		}
+		c.JSON(http.StatusOK, gin.H{HTTPReturnCode: http.StatusOK, HTTPReturnData: data})
+	}
+	return resp, err
+}
@@ -0,0 +1,115 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type batchSearchProxy struct {
+	*mocks.MockProxy
+	requests []*milvuspb.SearchRequest
+}
+
+func (p *batchSearchProxy) BatchSearch(ctx context.Context, requests []*milvuspb.SearchRequest) ([]*milvuspb.SearchResults, error) {
+	p.requests = requests
+	results := make([]*milvuspb.SearchResults, 0, len(requests))
+	for i := range requests {
+		if i == 1 {
+			results = append(results, &milvuspb.SearchResults{Status: merr.Status(merr.ErrCollectionNotLoaded)})
+			continue
+		}
+		results = append(results, &milvuspb.SearchResults{
+			Status: merr.Success(),
+			Results: &schemapb.SearchResultData{
+				NumQueries: 1,
+				TopK:       2,
+				Topks:      []int64{2},
+				Scores:     []float32{0.9, 0.8},
+				Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2}}}},
+			},
+		})
+	}
+	return results, nil
+}
+
+func TestBatchSearch(t *testing.T) {
+	paramtable.Init()
+	path := versionalV2(EntityCategory, BatchSearchAction)
+	newMockProxy := func(t *testing.T) *mocks.MockProxy {
+		mp := mocks.NewMockProxy(t)
+		mp.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+			CollectionName: DefaultCollectionName,
+			Schema:         generateCollectionSchema(schemapb.DataType_Int64),
+			ShardsNum:      ShardNumDefault,
+			Status:         &StatusSuccess,
+		}, nil).Maybe()
+		return mp
+	}
+	body := []byte(`{"collectionName": "book", "outputFields": ["word_count"], "search": [
+		{"data": [[0.1, 0.2]], "annsField": "book_intro", "limit": 2},
+		{"data": [[0.3, 0.4]], "annsField": "book_intro", "filter": "word_count > 10"},
+		{"data": [[0.5, 0.6]], "annsField": "book_intro", "limit": 2}
+	]}`)
+
+	t.Run("batch search", func(t *testing.T) {
+		p := &batchSearchProxy{MockProxy: newMockProxy(t)}
+		testEngine := initHTTPServerV2(p, false)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		testEngine.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		rsp := struct {
+			Code int32 `json:"code"`
+			Data []struct {
+				Code    int32            `json:"code"`
+				Message string           `json:"message"`
+				Data    []map[string]any `json:"data"`
+			} `json:"data"`
+		}{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rsp))
+		assert.Equal(t, int32(http.StatusOK), rsp.Code)
+		assert.Equal(t, 3, len(rsp.Data))
+		assert.Equal(t, int32(http.StatusOK), rsp.Data[0].Code)
+		assert.Equal(t, 2, len(rsp.Data[0].Data))
+		assert.Equal(t, merr.Code(merr.ErrCollectionNotLoaded), rsp.Data[1].Code)
+		assert.Equal(t, 2, len(rsp.Data[2].Data))
+
+		assert.Equal(t, 3, len(p.requests))
+		assert.Equal(t, "word_count > 10", p.requests[1].GetDsl())
+		assert.Equal(t, []string{"word_count"}, p.requests[1].GetOutputFields())
+		for _, kv := range p.requests[1].GetSearchParams() {
+			if kv.GetKey() == ParamLimit || kv.GetKey() == "topk" {
+				assert.Equal(t, "100", kv.GetValue())
+			}
+		}
+		placeholders := &commonpb.PlaceholderGroup{}
+		assert.NoError(t, proto.Unmarshal(p.requests[2].GetPlaceholderGroup(), placeholders))
+		assert.Equal(t, 1, len(placeholders.GetPlaceholders()[0].GetValues()))
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		testEngine := initHTTPServerV2(newMockProxy(t), false)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		testEngine.ServeHTTP(w, req)
+		returnBody := &ReturnErrMsg{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), returnBody))
+		assert.Equal(t, merr.Code(merr.ErrServiceUnavailable), returnBody.Code)
+	})
+}
@@ -0,0 +1,341 @@
+package httpserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/samber/lo"
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proxy"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+const contextStreamReader = "streamReader"
+
+// readStreamLine returns the next non-empty line of a newline delimited json stream,
+// the line is read fragment by fragment so that it is never buffered beyond maxSize.
+func readStreamLine(reader *bufio.Reader, maxSize int) ([]byte, error) {
+	for {
+		var line []byte
+		for {
+			fragment, isPrefix, err := reader.ReadLine()
+			if err != nil {
+				return nil, err
+			}
+			if len(line)+len(fragment) > maxSize {
+				return nil, merr.WrapErrParameterTooLarge(fmt.Sprintf("the line of the stream exceeds the max size %d bytes", maxSize))
+			}
+			line = append(line, fragment...)
+			if !isPrefix {
+				break
+			}
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			return line, nil
+		}
+	}
+}
+
+// wrapperStream is the wrapperPost of newline delimited json requests,
+// the first line is bound to the request and the reader of the remaining lines is kept in the gin context.
+func wrapperStream(newReq newReqFunc, v2 handlerFuncV2) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := newReq()
+		reader := bufio.NewReader(c.Request.Body)
+		line, err := readStreamLine(reader, proxy.Params.HTTPCfg.StreamInsertMaxLineSize.GetAsInt())
+		if err == nil {
+			err = json.Unmarshal(line, req)
+		}
+		if err == nil {
+			err = binding.Validator.ValidateStruct(req)
+		}
+		if err != nil {
+			log.Warn("high level restful api, read parameters from the first line of the stream fail", zap.Error(err),
+				zap.Any("url", c.Request.URL.Path), zap.Any("request", req))
+			if err == io.EOF {
+				c.AbortWithStatusJSON(http.StatusOK, gin.H{
+					HTTPReturnCode:    merr.Code(merr.ErrIncorrectParameterFormat),
+					HTTPReturnMessage: merr.ErrIncorrectParameterFormat.Error() + ", the request body should not be empty",
+				})
+			} else {
+				c.AbortWithStatusJSON(http.StatusOK, gin.H{
+					HTTPReturnCode:    merr.Code(merr.ErrIncorrectParameterFormat),
+					HTTPReturnMessage: merr.ErrIncorrectParameterFormat.Error() + ", error: " + err.Error(),
+				})
+			}
+			return
+		}
+		c.Set(contextStreamReader, reader)
+		ctx, span, dbName := newRequestContext(c, req)
+		defer span.End()
+		log.Ctx(ctx).Debug("high level restful api, read parameters from the stream, then start to handle.",
+			zap.Any("url", c.Request.URL.Path), zap.Any("request", req))
+		v2(ctx, c, req, dbName)
+	}
+}
+
+// streamInsertAck accumulates the inserted rows which are not acknowledged yet.
+type streamInsertAck struct {
+	watermark   int64
+	timestamp   uint64
+	insertCount int64
+	intIDs      []int64
+	strIDs      []string
+	chunks      int
+}
+
+func (ack *streamInsertAck) add(result *milvuspb.MutationResult, rows int) {
+	ack.watermark += int64(rows)
+	ack.timestamp = result.GetTimestamp()
+	ack.insertCount += result.GetInsertCnt()
+	switch result.GetIDs().GetIdField().(type) {
+	case *schemapb.IDs_IntId:
+		ack.intIDs = append(ack.intIDs, result.GetIDs().GetIntId().GetData()...)
+	case *schemapb.IDs_StrId:
+		ack.strIDs = append(ack.strIDs, result.GetIDs().GetStrId().GetData()...)
+	}
+	ack.chunks++
+}
+
+// flush returns the acknowledgement of the pending rows and resets them, the watermark is the number of rows inserted so far.
+func (ack *streamInsertAck) flush(allowJS bool, finished bool) gin.H {
+	data := gin.H{
+		HTTPReturnWatermark: ack.watermark,
+		HTTPReturnTimestamp: ack.timestamp,
+		"insertCount":       ack.insertCount,
+	}
+	switch {
+	case len(ack.strIDs) > 0:
+		data["insertIds"] = ack.strIDs
+	case allowJS:
+		data["insertIds"] = ack.intIDs
+	default:
+		data["insertIds"] = formatInt64(ack.intIDs)
+	}
+	if finished {
+		data[HTTPReturnFinished] = true
+	}
+	ack.insertCount = 0
+	ack.intIDs = nil
+	ack.strIDs = nil
+	ack.chunks = 0
+	return gin.H{HTTPReturnCode: http.StatusOK, HTTPReturnData: data}
+}
+
+func writeStreamLine(c *gin.Context, obj gin.H) {
+	bs, _ := json.Marshal(obj)
+	c.Writer.Write(append(bs, '\n'))
+	c.Writer.Flush()
+}
+
+// insertStream inserts the rows of a newline delimited json stream chunk by chunk, each chunk is an insert request
+// so that the ids and timestamps are allocated per chunk. The watermark is acknowledged every AckChunks chunks,
+// once an error happens, the error is written with the last watermark and the stream is stopped,
+// so the producer is able to resume from the watermark.
+func (h *HandlersV2) insertStream(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*StreamInsertReq)
+	collSchema, err := h.GetCollectionSchema(ctx, c, dbName, httpReq.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+	chunkRows := httpReq.ChunkRows
+	if chunkRows <= 0 {
+		chunkRows = proxy.Params.HTTPCfg.StreamInsertChunkRows.GetAsInt()
+	}
+	if maxChunkRows := proxy.Params.HTTPCfg.StreamInsertMaxChunkRows.GetAsInt(); chunkRows > maxChunkRows {
+		err := merr.WrapErrParameterInvalidRange(1, maxChunkRows, chunkRows, "chunkRows is out of range")
+		c.AbortWithStatusJSON(http.StatusOK, gin.H{HTTPReturnCode: merr.Code(err), HTTPReturnMessage: err.Error()})
+		return nil, err
+	}
+	ackChunks := httpReq.AckChunks
+	if ackChunks <= 0 {
+		ackChunks = proxy.Params.HTTPCfg.StreamInsertAckChunks.GetAsInt()
+	}
+	allowJS, _ := strconv.ParseBool(c.Request.Header.Get(HTTPHeaderAllowInt64))
+	value, _ := c.Get(contextStreamReader)
+	reader := value.(*bufio.Reader)
+
+	ack := &streamInsertAck{}
+	failed := func(err error) (interface{}, error) {
+		log.Ctx(ctx).Warn("high level restful api, streaming insert stopped", zap.Int64("watermark", ack.watermark), zap.Error(err))
+		writeStreamLine(c, gin.H{
+			HTTPReturnCode:    merr.Code(err),
+			HTTPReturnMessage: err.Error(),
+			HTTPReturnData:    gin.H{HTTPReturnWatermark: ack.watermark},
+		})
+		return ack.watermark, err
+	}
+	rows := make([]string, 0, chunkRows)
+	insertChunk := func() error {
+		body := `{"` + HTTPRequestData + `":[` + strings.Join(rows, ",") + `]}`
+		err, data := checkAndSetData(body, collSchema)
+		if err != nil {
+			return errors.Wrap(merr.ErrInvalidInsertData, err.Error())
+		}
+		req := &milvuspb.InsertRequest{
+			DbName:         dbName,
+			CollectionName: httpReq.CollectionName,
+			PartitionName:  httpReq.PartitionName,
+			NumRows:        uint32(len(data)),
+		}
+		req.FieldsData, err = anyToColumns(data, collSchema)
+		if err != nil {
+			return errors.Wrap(merr.ErrInvalidInsertData, err.Error())
+		}
+		resp, err := wrapperProxy(ctx, c, req, h.checkAuth, true, func(reqCtx context.Context, req any) (interface{}, error) {
+			return h.proxy.Insert(reqCtx, req.(*milvuspb.InsertRequest))
+		})
+		if err != nil {
+			return err
+		}
+		ack.add(resp.(*milvuspb.MutationResult), len(rows))
+		rows = rows[:0]
+		return nil
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+	maxLineSize := proxy.Params.HTTPCfg.StreamInsertMaxLineSize.GetAsInt()
+	for {
+		line, err := readStreamLine(reader, maxLineSize)
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, merr.ErrParameterTooLarge) {
+			return failed(err)
+		}
+		if err != nil {
+			return failed(merr.WrapErrIoFailedReason(err.Error()))
+		}
+		if !gjson.ValidBytes(line) || !gjson.ParseBytes(line).IsObject() {
+			return failed(errors.Wrapf(merr.ErrInvalidInsertData, "row %d is not a json object", ack.watermark+int64(len(rows))))
+		}
+		rows = append(rows, string(line))
+		if len(rows) < chunkRows {
+			continue
+		}
+		if err := insertChunk(); err != nil {
+			return failed(err)
+		}
+		if ack.chunks >= ackChunks {
+			writeStreamLine(c, ack.flush(allowJS, false))
+		}
+	}
+	if len(rows) > 0 {
+		if err := insertChunk(); err != nil {
+			return failed(err)
+		}
+	}
+	writeStreamLine(c, ack.flush(allowJS, true))
+	return ack.watermark, nil
+}
+
+// subscribeChanges is replaceable for the tests.
+var subscribeChanges = proxy.SubscribeChanges
+
+// changeEventToLine converts the change event to a line of the subscription stream.
+func changeEventToLine(event *proxy.ChangeEvent, allowJS bool) (gin.H, error) {
+	data := gin.H{
+		"type":        event.Type,
+		"timestamp":   event.Timestamp,
+		"partitionId": event.PartitionID,
+	}
+	switch event.Type {
+	case proxy.ChangeEventInsert:
+		// the dynamic field is expanded as well
+		fieldNames := lo.Map(event.FieldsData, func(field *schemapb.FieldData, _ int) string { return field.GetFieldName() })
+		rows, err := buildQueryResp(event.NumRows, fieldNames, event.FieldsData, nil, nil, allowJS)
+		if err != nil {
+			return nil, err
+		}
+		data["rows"] = rows
+	case proxy.ChangeEventDelete:
+		switch event.IDs.GetIdField().(type) {
+		case *schemapb.IDs_IntId:
+			if allowJS {
+				data["ids"] = event.IDs.GetIntId().GetData()
+			} else {
+				data["ids"] = formatInt64(event.IDs.GetIntId().GetData())
+			}
+		case *schemapb.IDs_StrId:
+			data["ids"] = event.IDs.GetStrId().GetData()
+		}
+	}
+	return gin.H{HTTPReturnCode: http.StatusOK, HTTPReturnData: data}, nil
+}
+
+// subscribe streams the inserts and deletes of the collection as newline delimited json until the client disconnects,
+// the timestamp of the last received change plus one is the startTs to resume the subscription.
+func (h *HandlersV2) subscribe(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*SubscribeReq)
+	if h.checkAuth {
+		// subscribing the changes requires the same privilege as the query
+		err := checkAuthorizationV2(ctx, c, false, &milvuspb.QueryRequest{
+			DbName:         dbName,
+			CollectionName: httpReq.CollectionName,
+			PartitionNames: httpReq.PartitionNames,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	allowJS, _ := strconv.ParseBool(c.Request.Header.Get(HTTPHeaderAllowInt64))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-c.Request.Context().Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	started := false
+	err := subscribeChanges(ctx, &proxy.ChangeSubscription{
+		DbName:         dbName,
+		CollectionName: httpReq.CollectionName,
+		PartitionNames: httpReq.PartitionNames,
+		StartTs:        httpReq.StartTs,
+	}, func(event *proxy.ChangeEvent) error {
+		line, err := changeEventToLine(event, allowJS)
+		if err != nil {
+			return err
+		}
+		if !started {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+			started = true
+		}
+		bs, _ := json.Marshal(line)
+		if _, err := c.Writer.Write(append(bs, '\n')); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		log.Ctx(ctx).Warn("high level restful api, change subscription stopped", zap.String("collection", httpReq.CollectionName), zap.Error(err))
+		if started {
+			writeStreamLine(c, gin.H{HTTPReturnCode: merr.Code(err), HTTPReturnMessage: err.Error()})
+		} else {
+			c.AbortWithStatusJSON(http.StatusOK, gin.H{HTTPReturnCode: merr.Code(err), HTTPReturnMessage: err.Error()})
+		}
+	}
+	return nil, err
+}
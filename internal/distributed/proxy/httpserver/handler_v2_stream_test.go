@@ -0,0 +1,249 @@
+package httpserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proxy"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type streamInsertLine struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Watermark   int64    `json:"watermark"`
+		Timestamp   uint64   `json:"timestamp"`
+		InsertCount int64    `json:"insertCount"`
+		InsertIDs   []string `json:"insertIds"`
+		Finished    bool     `json:"finished"`
+	} `json:"data"`
+}
+
+func readStreamInsertLines(t *testing.T, body []byte) []*streamInsertLine {
+	lines := make([]*streamInsertLine, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := &streamInsertLine{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func genStreamInsertBody(header string, rows int) []byte {
+	lines := []string{header}
+	for i := 0; i < rows; i++ {
+		lines = append(lines, `{"book_id": 0, "word_count": 0, "book_intro": [0.11825, 0.6]}`)
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func TestInsertStream(t *testing.T) {
+	paramtable.Init()
+	path := versionalV2(EntityCategory, InsertStreamAction)
+
+	newMockProxy := func(t *testing.T) *mocks.MockProxy {
+		mp := mocks.NewMockProxy(t)
+		mp.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+			CollectionName: DefaultCollectionName,
+			Schema:         generateCollectionSchema(schemapb.DataType_Int64),
+			ShardsNum:      ShardNumDefault,
+			Status:         &StatusSuccess,
+		}, nil).Maybe()
+		return mp
+	}
+	mutationResult := func(req *milvuspb.InsertRequest) *milvuspb.MutationResult {
+		ids := make([]int64, req.GetNumRows())
+		return &milvuspb.MutationResult{
+			Status:    commonSuccessStatus,
+			InsertCnt: int64(req.GetNumRows()),
+			IDs:       &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+			Timestamp: 100,
+		}
+	}
+
+	t.Run("insert by chunks", func(t *testing.T) {
+		mp := newMockProxy(t)
+		numRows := make([]uint32, 0)
+		mp.EXPECT().Insert(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+			numRows = append(numRows, req.GetNumRows())
+			return mutationResult(req), nil
+		}).Times(3)
+		testEngine := initHTTPServerV2(mp, false)
+		body := genStreamInsertBody(`{"collectionName": "book", "chunkRows": 2, "ackChunks": 2}`, 5)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		testEngine.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []uint32{2, 2, 1}, numRows)
+
+		lines := readStreamInsertLines(t, w.Body.Bytes())
+		assert.Equal(t, 2, len(lines))
+		assert.Equal(t, int32(http.StatusOK), lines[0].Code)
+		assert.Equal(t, int64(4), lines[0].Data.Watermark)
+		assert.Equal(t, int64(4), lines[0].Data.InsertCount)
+		assert.Equal(t, 4, len(lines[0].Data.InsertIDs))
+		assert.False(t, lines[0].Data.Finished)
+		assert.Equal(t, int64(5), lines[1].Data.Watermark)
+		assert.Equal(t, int64(1), lines[1].Data.InsertCount)
+		assert.Equal(t, uint64(100), lines[1].Data.Timestamp)
+		assert.True(t, lines[1].Data.Finished)
+	})
+
+	t.Run("insert failed", func(t *testing.T) {
+		mp := newMockProxy(t)
+		mp.EXPECT().Insert(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+			return mutationResult(req), nil
+		}).Once()
+		mp.EXPECT().Insert(mock.Anything, mock.Anything).Return(&milvuspb.MutationResult{Status: commonErrorStatus}, nil).Once()
+		testEngine := initHTTPServerV2(mp, false)
+		body := genStreamInsertBody(`{"collectionName": "book", "chunkRows": 2}`, 5)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		testEngine.ServeHTTP(w, req)
+
+		lines := readStreamInsertLines(t, w.Body.Bytes())
+		assert.Equal(t, 2, len(lines))
+		assert.Equal(t, int64(2), lines[0].Data.Watermark)
+		assert.Equal(t, int32(65535), lines[1].Code)
+		assert.Equal(t, int64(2), lines[1].Data.Watermark)
+	})
+
+	t.Run("invalid row", func(t *testing.T) {
+		mp := newMockProxy(t)
+		testEngine := initHTTPServerV2(mp, false)
+		body := []byte(`{"collectionName": "book"}` + "\n" + `[1, 2]`)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		testEngine.ServeHTTP(w, req)
+
+		lines := readStreamInsertLines(t, w.Body.Bytes())
+		assert.Equal(t, 1, len(lines))
+		assert.Equal(t, merr.Code(merr.ErrInvalidInsertData), lines[0].Code)
+		assert.Equal(t, int64(0), lines[0].Data.Watermark)
+	})
+
+	t.Run("chunk rows out of range", func(t *testing.T) {
+		mp := newMockProxy(t)
+		testEngine := initHTTPServerV2(mp, false)
+		body := genStreamInsertBody(`{"collectionName": "book", "chunkRows": 10001}`, 5)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		testEngine.ServeHTTP(w, req)
+		returnBody := &ReturnErrMsg{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), returnBody))
+		assert.Equal(t, merr.Code(merr.ErrParameterInvalid), returnBody.Code)
+	})
+
+	t.Run("line too large", func(t *testing.T) {
+		paramtable.Get().Save(paramtable.Get().HTTPCfg.StreamInsertMaxLineSize.Key, "64")
+		defer paramtable.Get().Reset(paramtable.Get().HTTPCfg.StreamInsertMaxLineSize.Key)
+		mp := newMockProxy(t)
+		testEngine := initHTTPServerV2(mp, false)
+		row := `{"book_id": 0, "word_count": 0, "book_intro": [` + strings.Repeat("0.1, ", 20) + `0.1]}`
+		body := []byte(`{"collectionName": "book"}` + "\n" + row)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		testEngine.ServeHTTP(w, req)
+
+		lines := readStreamInsertLines(t, w.Body.Bytes())
+		assert.Equal(t, 1, len(lines))
+		assert.Equal(t, merr.Code(merr.ErrParameterTooLarge), lines[0].Code)
+		assert.Equal(t, int64(0), lines[0].Data.Watermark)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		mp := newMockProxy(t)
+		testEngine := initHTTPServerV2(mp, false)
+		for _, body := range [][]byte{nil, []byte(`{"dbName": "default"}`), []byte(`{"collectionName": 1}`)} {
+			req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+			w := httptest.NewRecorder()
+			testEngine.ServeHTTP(w, req)
+			returnBody := &ReturnErrMsg{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), returnBody))
+			assert.Equal(t, merr.Code(merr.ErrIncorrectParameterFormat), returnBody.Code)
+		}
+	})
+}
+
+func TestSubscribe(t *testing.T) {
+	paramtable.Init()
+	path := versionalV2(EntityCategory, SubscribeAction)
+	defer func() { subscribeChanges = proxy.SubscribeChanges }()
+
+	t.Run("subscribe", func(t *testing.T) {
+		subscribeChanges = func(ctx context.Context, req *proxy.ChangeSubscription, send func(event *proxy.ChangeEvent) error) error {
+			assert.Equal(t, DefaultCollectionName, req.CollectionName)
+			assert.Equal(t, uint64(100), req.StartTs)
+			assert.NoError(t, send(&proxy.ChangeEvent{
+				Type:      proxy.ChangeEventInsert,
+				Timestamp: 100,
+				NumRows:   2,
+				FieldsData: []*schemapb.FieldData{{
+					FieldName: FieldBookID,
+					Type:      schemapb.DataType_Int64,
+					Field: &schemapb.FieldData_Scalars{
+						Scalars: &schemapb.ScalarField{Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{1, 2}}}},
+					},
+				}},
+			}))
+			assert.NoError(t, send(&proxy.ChangeEvent{
+				Type:      proxy.ChangeEventDelete,
+				Timestamp: 101,
+				NumRows:   1,
+				IDs:       &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1}}}},
+			}))
+			return merr.WrapErrServiceInternal("mock")
+		}
+		testEngine := initHTTPServerV2(mocks.NewMockProxy(t), false)
+		body := []byte(`{"collectionName": "book", "startTs": 100}`)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		testEngine.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		lines := make([]map[string]any, 0)
+		scanner := bufio.NewScanner(bytes.NewReader(w.Body.Bytes()))
+		for scanner.Scan() {
+			line := make(map[string]any)
+			assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+		assert.Equal(t, 3, len(lines))
+		insert := lines[0][HTTPReturnData].(map[string]any)
+		assert.Equal(t, "insert", insert["type"])
+		assert.Equal(t, 2, len(insert["rows"].([]any)))
+		del := lines[1][HTTPReturnData].(map[string]any)
+		assert.Equal(t, "delete", del["type"])
+		assert.Equal(t, []any{"1"}, del["ids"])
+		assert.Equal(t, float64(merr.Code(merr.ErrServiceInternal)), lines[2][HTTPReturnCode])
+	})
+
+	t.Run("subscribe failed", func(t *testing.T) {
+		subscribeChanges = func(ctx context.Context, req *proxy.ChangeSubscription, send func(event *proxy.ChangeEvent) error) error {
+			return merr.WrapErrCollectionNotFound(req.CollectionName)
+		}
+		testEngine := initHTTPServerV2(mocks.NewMockProxy(t), false)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader([]byte(`{"collectionName": "book"}`)))
+		w := httptest.NewRecorder()
+		testEngine.ServeHTTP(w, req)
+		returnBody := &ReturnErrMsg{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), returnBody))
+		assert.Equal(t, merr.Code(merr.ErrCollectionNotFound), returnBody.Code)
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proxy"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
@@ -1398,3 +1399,9 @@ func TestSearchV2(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadMmapBase(t *testing.T) {
+	assert.Nil(t, loadMmapBase(nil))
+	enabled := true
+	assert.Equal(t, "true", loadMmapBase(&enabled).GetProperties()[common.MmapEnabledKey])
+}
@@ -33,6 +33,16 @@ func (req *CollectionNameReq) GetPartitionNames() []string {
 	return req.PartitionNames
 }
 
+type LoadCollectionReq struct {
+	DbName         string `json:"dbName"`
+	CollectionName string `json:"collectionName" binding:"required"`
+	MmapEnabled    *bool  `json:"mmapEnabled"`
+}
+
+func (req *LoadCollectionReq) GetDbName() string { return req.DbName }
+
+func (req *LoadCollectionReq) GetCollectionName() string { return req.CollectionName }
+
 type OptionalCollectionNameReq struct {
 	DbName         string `json:"dbName"`
 	CollectionName string `json:"collectionName"`
@@ -108,10 +118,18 @@ type QueryReqV2 struct {
 	Filter         string   `json:"filter" binding:"required"`
 	Limit          int32    `json:"limit"`
 	Offset         int32    `json:"offset"`
+	// Enrich enriches the results with the fields of the rows of another collection keyed by a field of the results.
+	Enrich *QueryEnrichReq `json:"enrich"`
 }
 
 func (req *QueryReqV2) GetDbName() string { return req.DbName }
 
+type QueryEnrichReq struct {
+	CollectionName string   `json:"collectionName" binding:"required"`
+	KeyField       string   `json:"keyField" binding:"required"`
+	OutputFields   []string `json:"outputFields" binding:"required"`
+}
+
 type CollectionIDReq struct {
 	DbName         string      `json:"dbName"`
 	CollectionName string      `json:"collectionName" binding:"required"`
@@ -141,6 +159,27 @@ type CollectionDataReq struct {
 
 func (req *CollectionDataReq) GetDbName() string { return req.DbName }
 
+// StreamInsertReq is the first line of a streaming insert request,
+// the following lines are the rows to insert, one json object per line.
+type StreamInsertReq struct {
+	DbName         string `json:"dbName"`
+	CollectionName string `json:"collectionName" binding:"required"`
+	PartitionName  string `json:"partitionName"`
+	ChunkRows      int    `json:"chunkRows"`
+	AckChunks      int    `json:"ackChunks"`
+}
+
+func (req *StreamInsertReq) GetDbName() string { return req.DbName }
+
+type SubscribeReq struct {
+	DbName         string   `json:"dbName"`
+	CollectionName string   `json:"collectionName" binding:"required"`
+	PartitionNames []string `json:"partitionNames"`
+	StartTs        uint64   `json:"startTs"`
+}
+
+func (req *SubscribeReq) GetDbName() string { return req.DbName }
+
 type SearchReqV2 struct {
 	DbName         string             `json:"dbName"`
 	CollectionName string             `json:"collectionName" binding:"required"`
@@ -186,6 +225,16 @@ type HybridSearchReq struct {
 
 func (req *HybridSearchReq) GetDbName() string { return req.DbName }
 
+type BatchSearchReq struct {
+	DbName         string         `json:"dbName"`
+	CollectionName string         `json:"collectionName" binding:"required"`
+	PartitionNames []string       `json:"partitionNames"`
+	Search         []SubSearchReq `json:"search" binding:"required"`
+	OutputFields   []string       `json:"outputFields"`
+}
+
+func (req *BatchSearchReq) GetDbName() string { return req.DbName }
+
 type ReturnErrMsg struct {
 	Code    int32  `json:"code"`
 	Message string `json:"message"`
@@ -200,6 +249,15 @@ type PartitionsReq struct {
 
 func (req *PartitionsReq) GetDbName() string { return req.DbName }
 
+type LoadPartitionsReq struct {
+	DbName         string   `json:"dbName"`
+	CollectionName string   `json:"collectionName" binding:"required"`
+	PartitionNames []string `json:"partitionNames" binding:"required"`
+	MmapEnabled    *bool    `json:"mmapEnabled"`
+}
+
+func (req *LoadPartitionsReq) GetDbName() string { return req.DbName }
+
 type UserReq struct {
 	UserName string `json:"userName" binding:"required"`
 }
@@ -44,6 +44,7 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
@@ -75,6 +76,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 var (
@@ -88,6 +90,19 @@ var (
 	enableRegisterProxyServer = false
 )
 
+// internalAuthorizedRoles are the components permitted to call the internal methods which change the proxy state,
+// the other internal methods are allowed for all the signed components.
+var internalAuthorizedRoles = func() map[string][]string {
+	coordRoles := []string{typeutil.RootCoordRole, typeutil.StandaloneRole, typeutil.MixtureRole, typeutil.EmbeddedRole}
+	return map[string][]string{
+		"/milvus.proto.proxy.Proxy/InvalidateCollectionMetaCache": coordRoles,
+		"/milvus.proto.proxy.Proxy/InvalidateCredentialCache":     coordRoles,
+		"/milvus.proto.proxy.Proxy/UpdateCredentialCache":         coordRoles,
+		"/milvus.proto.proxy.Proxy/RefreshPolicyInfoCache":        coordRoles,
+		"/milvus.proto.proxy.Proxy/SetRates":                      coordRoles,
+	}
+}()
+
 const apiPathPrefix = "/api/v1"
 
 // Server is the Proxy Server
@@ -126,20 +141,36 @@ func NewServer(ctx context.Context, factory dependency.Factory) (*Server, error)
 	return server, err
 }
 
+// setAuthenticatedUser sets the authenticated user if the client address is allowed by the network policy.
+// The address is the remote address of the connection, since the forwarded headers can be set by any client.
+func setAuthenticatedUser(c *gin.Context, username string) {
+	if err := proxy.CheckNetworkPolicy(c, username, c.RemoteIP()); err != nil {
+		log.Warn("rejected by network policy", zap.String("username", username), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{httpserver.HTTPReturnCode: merr.Code(err), httpserver.HTTPReturnMessage: err.Error()})
+		return
+	}
+	c.Set(httpserver.ContextUsername, username)
+}
+
 func authenticate(c *gin.Context) {
 	username, password, ok := httpserver.ParseUsernamePassword(c)
 	if ok {
-		if proxy.PasswordVerify(c, username, password) {
+		// the user with expired password is only allowed to update the password
+		allowExpired := strings.HasSuffix(c.Request.URL.Path, "/"+httpserver.UpdatePasswordAction)
+		err := proxy.AuthenticatePassword(c, username, password, allowExpired)
+		if err == nil {
 			log.Debug("auth successful", zap.String("username", username))
-			c.Set(httpserver.ContextUsername, username)
+			setAuthenticatedUser(c, username)
 			return
 		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{httpserver.HTTPReturnCode: merr.Code(merr.ErrNeedAuthenticate), httpserver.HTTPReturnMessage: err.Error()})
+		return
 	}
 	rawToken := httpserver.GetAuthorization(c)
 	if rawToken != "" && !strings.Contains(rawToken, util.CredentialSeperator) {
 		user, err := proxy.VerifyAPIKey(rawToken)
 		if err == nil {
-			c.Set(httpserver.ContextUsername, user)
+			setAuthenticatedUser(c, user)
 			return
 		}
 		log.Warn("fail to verify apikey", zap.Error(err))
@@ -217,9 +248,17 @@ func (s *Server) startHTTPServer(errChan chan error) {
 	ginHandler.Use(func(c *gin.Context) {
 		c.Set(httpserver.ContextUsername, "")
 	})
+	ginHandler.Use(func(c *gin.Context) {
+		for key, value := range proxy.SessionAffinity(c.Request.Header.Get(proxy.SessionAffinityProxyKey)) {
+			c.Writer.Header().Set(key, value)
+		}
+	})
 	if proxy.Params.CommonCfg.AuthorizationEnabled.GetAsBool() {
 		ginHandler.Use(authenticate)
 	}
+	if proxy.Params.HTTPCfg.EnableDebugEndpoints.GetAsBool() {
+		httpserver.RegisterDebugRoutes(ginHandler)
+	}
 	app := ginHandler.Group("/v1")
 	httpserver.NewHandlersV1(s.proxy).RegisterRoutesToV1(app)
 	appV2 := ginHandler.Group("/v2/vectordb")
@@ -268,16 +307,24 @@ func (s *Server) startExternalGrpc(grpcPort int, errChan chan error) {
 	opts := tracer.GetInterceptorOpts()
 
 	var unaryServerOption grpc.ServerOption
+	// without the interceptors nothing enforces the request size limits but grpc itself
+	maxRecvMsgSize := proxy.MaxRequestSize()
 	if enableCustomInterceptor {
+		maxRecvMsgSize = proxy.MaxRecvMsgSize()
 		unaryServerOption = grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			accesslog.UnaryAccessLogInterceptor,
+			proxy.FailureReasonInterceptor(),
 			otelgrpc.UnaryServerInterceptor(opts...),
 			grpc_auth.UnaryServerInterceptor(proxy.AuthenticationInterceptor),
 			proxy.DatabaseInterceptor(),
 			proxy.UnaryServerHookInterceptor(),
 			proxy.UnaryServerInterceptor(proxy.PrivilegeInterceptor),
 			logutil.UnaryTraceLoggerInterceptor,
+			proxy.OperationSuspendInterceptor(),
+			proxy.RequestSizeLimitInterceptor(),
 			proxy.RateLimitInterceptor(limiter),
+			proxy.UsageAccountingInterceptor(),
+			proxy.SessionAffinityInterceptor(),
 			accesslog.UnaryUpdateAccessInfoInterceptor,
 			proxy.TraceLogInterceptor,
 			connection.KeepActiveInterceptor,
@@ -289,8 +336,9 @@ func (s *Server) startExternalGrpc(grpcPort int, errChan chan error) {
 	grpcOpts := []grpc.ServerOption{
 		grpc.KeepaliveEnforcementPolicy(kaep),
 		grpc.KeepaliveParams(kasp),
-		grpc.MaxRecvMsgSize(Params.ServerMaxRecvSize.GetAsInt()),
+		grpc.MaxRecvMsgSize(maxRecvMsgSize),
 		grpc.MaxSendMsgSize(Params.ServerMaxSendSize.GetAsInt()),
+		grpc.StatsHandler(proxy.UsageStatsHandler{}),
 		unaryServerOption,
 	}
 
@@ -340,6 +388,9 @@ func (s *Server) startExternalGrpc(grpcPort int, errChan chan error) {
 
 	milvuspb.RegisterMilvusServiceServer(s.grpcExternalServer, s)
 	grpc_health_v1.RegisterHealthServer(s.grpcExternalServer, s)
+	if proxy.Params.ProxyCfg.GrpcReflectionEnabled.GetAsBool() {
+		reflection.Register(s.grpcExternalServer)
+	}
 	errChan <- nil
 
 	log.Debug("create Proxy grpc server",
@@ -392,6 +443,7 @@ func (s *Server) startInternalGrpc(grpcPort int, errChan chan error) {
 				}
 				return s.serverID.Load()
 			}),
+			interceptor.SignatureValidationUnaryServerInterceptor(internalAuthorizedRoles),
 		)),
 		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
 			interceptor.ClusterValidationStreamServerInterceptor(),
@@ -1181,9 +1233,7 @@ func (s *Server) ListIndexedSegment(ctx context.Context, req *federpb.ListIndexe
 }
 
 func (s *Server) DescribeSegmentIndexData(ctx context.Context, req *federpb.DescribeSegmentIndexDataRequest) (*federpb.DescribeSegmentIndexDataResponse, error) {
-	return &federpb.DescribeSegmentIndexDataResponse{
-		Status: merr.Status(merr.WrapErrServiceUnavailable("DescribeSegmentIndexData unimplemented")),
-	}, nil
+	return s.proxy.DescribeSegmentIndexData(ctx, req)
 }
 
 func (s *Server) Connect(ctx context.Context, req *milvuspb.ConnectRequest) (*milvuspb.ConnectResponse, error) {
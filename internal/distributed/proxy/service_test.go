@@ -621,6 +621,12 @@ func Test_NewServer(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("DescribeSegmentIndexData", func(t *testing.T) {
+		mockProxy.EXPECT().DescribeSegmentIndexData(mock.Anything, mock.Anything).Return(nil, nil)
+		_, err := server.DescribeSegmentIndexData(ctx, nil)
+		assert.NoError(t, err)
+	})
+
 	t.Run("DescribeResourceGroup", func(t *testing.T) {
 		mockProxy.EXPECT().DescribeResourceGroup(mock.Anything, mock.Anything).Return(nil, nil)
 		_, err := server.DescribeResourceGroup(ctx, nil)
@@ -1129,15 +1135,6 @@ func TestNotImplementedAPIs(t *testing.T) {
 			assert.Equal(t, commonpb.ErrorCode_UnexpectedError, resp.GetStatus().GetErrorCode())
 		})
 	})
-
-	t.Run("DescribeSegmentIndexData", func(t *testing.T) {
-		assert.NotPanics(t, func() {
-			resp, err := server.DescribeSegmentIndexData(context.TODO(), &federpb.DescribeSegmentIndexDataRequest{})
-			assert.NoError(t, err)
-			assert.NotNil(t, resp)
-			assert.Equal(t, commonpb.ErrorCode_UnexpectedError, resp.GetStatus().GetErrorCode())
-		})
-	})
 }
 
 func TestHttpAuthenticate(t *testing.T) {
@@ -169,6 +169,23 @@ func TestAccessLogger_Basic(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestAccessLogger_Audit(t *testing.T) {
+	once = sync.Once{}
+	_globalL = nil
+	auditInfo := info.NewAuditInfo(context.Background(), "full_scan_delete", &milvuspb.DeleteRequest{Expr: "pk >= 0"}, "")
+	assert.False(t, Audit(auditInfo))
+
+	var Params paramtable.ComponentParam
+	Params.Init(paramtable.NewBaseTable(paramtable.SkipRemote(true)))
+	testPath := "/tmp/accesstest"
+	Params.Save(Params.ProxyCfg.AccessLog.Enable.Key, "true")
+	Params.Save(Params.ProxyCfg.AccessLog.LocalPath.Key, testPath)
+	defer os.RemoveAll(testPath)
+
+	InitAccessLogger(&Params)
+	assert.True(t, Audit(auditInfo))
+}
+
 func TestAccessLogger_WriteFailed(t *testing.T) {
 	once = sync.Once{}
 	var Params paramtable.ComponentParam
@@ -0,0 +1,64 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package info
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// AuditInfo is the access info of an audit event, which is not an rpc but an operation inside one, such as a
+// confirmed full collection delete or a locked account. It's written into the access log with the event as the
+// method name, so the audit events are kept, rotated and uploaded along with the access log.
+type AuditInfo struct {
+	*GrpcAccessInfo
+	detail string
+}
+
+// NewAuditInfo returns the AuditInfo of the event, req is the request of the rpc the event happens in, could be nil.
+func NewAuditInfo(ctx context.Context, event string, req interface{}, detail string) *AuditInfo {
+	now := time.Now()
+	return &AuditInfo{
+		GrpcAccessInfo: &GrpcAccessInfo{
+			ctx:      ctx,
+			req:      req,
+			grpcInfo: &grpc.UnaryServerInfo{FullMethod: event},
+			start:    now,
+			end:      now,
+		},
+		detail: detail,
+	}
+}
+
+func (i *AuditInfo) MethodStatus() string {
+	return "Audit"
+}
+
+func (i *AuditInfo) ResponseSize() string {
+	return Unknown
+}
+
+func (i *AuditInfo) ErrorCode() string {
+	return "0"
+}
+
+// ErrorMsg returns the detail of the event.
+func (i *AuditInfo) ErrorMsg() string {
+	return i.detail
+}
@@ -0,0 +1,43 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package info
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+)
+
+func TestAuditInfo(t *testing.T) {
+	req := &milvuspb.DeleteRequest{DbName: "db1", CollectionName: "c1", Expr: "pk >= 0"}
+	i := NewAuditInfo(context.Background(), "full_scan_delete", req, "user=u1")
+	assert.Equal(t, "full_scan_delete", i.MethodName())
+	assert.Equal(t, "Audit", i.MethodStatus())
+	assert.Equal(t, "0", i.ErrorCode())
+	assert.Equal(t, "user=u1", i.ErrorMsg())
+	assert.Equal(t, "db1", i.DbName())
+	assert.Equal(t, "c1", i.CollectionName())
+	assert.Equal(t, "pk >= 0", i.Expression())
+	assert.Equal(t, Unknown, i.ResponseSize())
+	assert.NotEqual(t, Unknown, i.TimeCost())
+
+	i = NewAuditInfo(context.Background(), "account_locked", nil, "")
+	assert.Equal(t, Unknown, i.DbName())
+}
@@ -38,6 +38,14 @@ func UnaryAccessLogInterceptor(ctx context.Context, req any, rpcInfo *grpc.Unary
 	return resp, err
 }
 
+// Audit writes the audit event into the access log, returns false if the access log is not enabled.
+func Audit(auditInfo *info.AuditInfo) bool {
+	if _globalL == nil {
+		return false
+	}
+	return _globalL.Write(auditInfo)
+}
+
 func UnaryUpdateAccessInfoInterceptor(ctx context.Context, req any, rpcInfonfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	accessInfo := ctx.Value(AccessKey{}).(*info.GrpcAccessInfo)
 	accessInfo.UpdateCtx(ctx)
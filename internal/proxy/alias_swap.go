@@ -0,0 +1,335 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	// aliasSwapCleanupPrefix is the etcd prefix(under meta root path) of the pending cleanups of alias swap.
+	aliasSwapCleanupPrefix = "proxy/alias-swap-cleanups"
+
+	aliasSwapCleanupInterval = 10 * time.Second
+)
+
+// AliasSwapRequest retargets the alias to the target collection,
+// the source collection could be released or dropped after the grace period.
+type AliasSwapRequest struct {
+	DbName             string `json:"db_name"`
+	Alias              string `json:"alias"`
+	TargetCollection   string `json:"target_collection"`
+	ReleaseSource      bool   `json:"release_source"`
+	DropSource         bool   `json:"drop_source"`
+	GracePeriodSeconds int64  `json:"grace_period_seconds"`
+}
+
+func (r *AliasSwapRequest) validate() error {
+	if r.Alias == "" {
+		return merr.WrapErrParameterMissing("alias")
+	}
+	if r.TargetCollection == "" {
+		return merr.WrapErrParameterMissing("target_collection")
+	}
+	if r.GracePeriodSeconds < 0 {
+		return merr.WrapErrParameterInvalidMsg("grace_period_seconds should not be negative, got %d", r.GracePeriodSeconds)
+	}
+	return nil
+}
+
+// AliasSwapResult is the result of the alias swap.
+type AliasSwapResult struct {
+	Alias            string `json:"alias"`
+	SourceCollection string `json:"source_collection"`
+	TargetCollection string `json:"target_collection"`
+	// CleanupTime is the unix time to release or drop the source collection, 0 if no cleanup.
+	CleanupTime int64 `json:"cleanup_time"`
+}
+
+func (node *Proxy) getLoadState(ctx context.Context, dbName, collectionName string) (commonpb.LoadState, error) {
+	resp, err := node.GetLoadState(ctx, &milvuspb.GetLoadStateRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return commonpb.LoadState_LoadStateNotExist, err
+	}
+	return resp.GetState(), nil
+}
+
+// atomicSwapAlias retargets the alias in one AlterAlias, which is atomic in meta,
+// so the alias is always resolved to either the source or the target collection.
+// The target collection must be loaded if the source one is, to keep the alias searchable during the swap.
+// The privileges of the cleanup are checked here, since the cleanup is done later without the identity of user.
+func (node *Proxy) atomicSwapAlias(ctx context.Context, request *AliasSwapRequest) (*AliasSwapResult, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+	if (request.ReleaseSource || request.DropSource) && !globalAliasSwapCleaner.Enabled() {
+		return nil, merr.WrapErrServiceUnavailable("alias swap cleaner is not initialized", "failed to schedule the cleanup of source collection")
+	}
+
+	describeReq := &milvuspb.DescribeAliasRequest{
+		DbName: request.DbName,
+		Alias:  request.Alias,
+	}
+	if err := checkMgrPrivilege(ctx, describeReq); err != nil {
+		return nil, err
+	}
+	aliasResp, err := node.DescribeAlias(ctx, describeReq)
+	if err := merr.CheckRPCCall(aliasResp, err); err != nil {
+		return nil, err
+	}
+	result := &AliasSwapResult{
+		Alias:            request.Alias,
+		SourceCollection: aliasResp.GetCollection(),
+		TargetCollection: request.TargetCollection,
+	}
+	if result.SourceCollection == result.TargetCollection {
+		return result, nil
+	}
+
+	alterReq := &milvuspb.AlterAliasRequest{
+		DbName:         request.DbName,
+		Alias:          request.Alias,
+		CollectionName: request.TargetCollection,
+	}
+	privilegeReqs := []any{
+		&milvuspb.GetLoadStateRequest{DbName: request.DbName, CollectionName: result.SourceCollection},
+		&milvuspb.GetLoadStateRequest{DbName: request.DbName, CollectionName: result.TargetCollection},
+		alterReq,
+	}
+	if request.ReleaseSource || request.DropSource {
+		privilegeReqs = append(privilegeReqs, &milvuspb.ReleaseCollectionRequest{DbName: request.DbName, CollectionName: result.SourceCollection})
+	}
+	if request.DropSource {
+		privilegeReqs = append(privilegeReqs, &milvuspb.DropCollectionRequest{DbName: request.DbName, CollectionName: result.SourceCollection})
+	}
+	if err := checkMgrPrivilege(ctx, privilegeReqs...); err != nil {
+		return nil, err
+	}
+
+	sourceState, err := node.getLoadState(ctx, request.DbName, result.SourceCollection)
+	if err != nil {
+		return nil, err
+	}
+	targetState, err := node.getLoadState(ctx, request.DbName, result.TargetCollection)
+	if err != nil {
+		return nil, err
+	}
+	if sourceState != commonpb.LoadState_LoadStateNotLoad && targetState != commonpb.LoadState_LoadStateLoaded {
+		return nil, merr.WrapErrCollectionNotFullyLoaded(result.TargetCollection,
+			"the target collection must be loaded before swapping the alias of a loaded collection")
+	}
+	sourceID, err := globalMetaCache.GetCollectionID(ctx, request.DbName, result.SourceCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := node.AlterAlias(ctx, alterReq)
+	if err := merr.CheckRPCCall(status, err); err != nil {
+		return nil, err
+	}
+	log.Ctx(ctx).Info("alias swapped",
+		zap.String("alias", request.Alias),
+		zap.String("source", result.SourceCollection),
+		zap.String("target", result.TargetCollection))
+
+	if request.ReleaseSource || request.DropSource {
+		cleanup := &AliasSwapCleanup{
+			DBName:         request.DbName,
+			Alias:          request.Alias,
+			CollectionName: result.SourceCollection,
+			CollectionID:   sourceID,
+			Drop:           request.DropSource,
+			CleanupTime:    time.Now().Add(time.Duration(request.GracePeriodSeconds) * time.Second).Unix(),
+		}
+		// the alias is swapped anyway, report the failure of scheduling the cleanup
+		if err := globalAliasSwapCleaner.Add(cleanup); err != nil {
+			return result, merr.WrapErrServiceInternal("alias swapped but failed to schedule the cleanup of source collection", err.Error())
+		}
+		result.CleanupTime = cleanup.CleanupTime
+	}
+	return result, nil
+}
+
+// AliasSwapCleanup is the release or drop of the collection swapped out of alias, which is persisted in etcd,
+// so it survives the restart of proxy.
+type AliasSwapCleanup struct {
+	DBName         string `json:"db_name"`
+	Alias          string `json:"alias"`
+	CollectionName string `json:"collection_name"`
+	CollectionID   int64  `json:"collection_id"`
+	Drop           bool   `json:"drop"`
+	CleanupTime    int64  `json:"cleanup_time"`
+}
+
+func (c *AliasSwapCleanup) key() string {
+	return path.Join(aliasSwapCleanupPrefix, c.DBName, strconv.FormatInt(c.CollectionID, 10))
+}
+
+// aliasSwapCleaner does the due cleanups of alias swap. The cleanups are done by all proxies, which is harmless
+// since the release and drop are idempotent.
+type aliasSwapCleaner struct {
+	kv         kv.BaseKV
+	rootCoord  types.RootCoordClient
+	queryCoord types.QueryCoordClient
+}
+
+var globalAliasSwapCleaner = &aliasSwapCleaner{}
+
+func newAliasSwapCleaner(baseKV kv.BaseKV, rootCoord types.RootCoordClient, queryCoord types.QueryCoordClient) *aliasSwapCleaner {
+	return &aliasSwapCleaner{
+		kv:         baseKV,
+		rootCoord:  rootCoord,
+		queryCoord: queryCoord,
+	}
+}
+
+// Enabled returns whether the cleanups could be persisted.
+func (c *aliasSwapCleaner) Enabled() bool {
+	return c.kv != nil
+}
+
+// start starts the loop which does the due cleanups.
+func (c *aliasSwapCleaner) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(aliasSwapCleanupInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("alias swap cleanup loop exit")
+				return
+			case <-ticker.C:
+				c.run(ctx)
+			}
+		}
+	}()
+}
+
+// Add persists the cleanup, which is done after the cleanup time.
+func (c *aliasSwapCleaner) Add(cleanup *AliasSwapCleanup) error {
+	value, err := json.Marshal(cleanup)
+	if err != nil {
+		return err
+	}
+	return c.kv.Save(cleanup.key(), string(value))
+}
+
+func (c *aliasSwapCleaner) run(ctx context.Context) {
+	_, values, err := c.kv.LoadWithPrefix(aliasSwapCleanupPrefix)
+	if err != nil {
+		log.Warn("failed to load alias swap cleanups", zap.Error(err))
+		return
+	}
+	now := time.Now().Unix()
+	for _, value := range values {
+		cleanup := &AliasSwapCleanup{}
+		if err := json.Unmarshal([]byte(value), cleanup); err != nil {
+			log.Warn("skip invalid alias swap cleanup", zap.String("value", value), zap.Error(err))
+			continue
+		}
+		if cleanup.CleanupTime > now {
+			continue
+		}
+		if err := c.cleanup(ctx, cleanup); err != nil {
+			log.Warn("failed to clean up the collection swapped out of alias", zap.Any("cleanup", cleanup), zap.Error(err))
+			continue
+		}
+		if err := c.kv.Remove(cleanup.key()); err != nil {
+			log.Warn("failed to remove alias swap cleanup", zap.String("key", cleanup.key()), zap.Error(err))
+		}
+	}
+}
+
+// cleanup releases the collection swapped out of alias, and drops it if required. Nothing is done if the collection
+// is recreated, or referred by any alias again, e.g. the swap is rolled back.
+func (c *aliasSwapCleaner) cleanup(ctx context.Context, cleanup *AliasSwapCleanup) error {
+	log := log.Ctx(ctx).With(zap.String("db", cleanup.DBName), zap.String("collection", cleanup.CollectionName))
+
+	describeResp, err := c.rootCoord.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		Base: commonpbutil.NewMsgBase(
+			commonpbutil.WithMsgType(commonpb.MsgType_DescribeCollection),
+			commonpbutil.WithSourceID(paramtable.GetNodeID()),
+		),
+		DbName:         cleanup.DBName,
+		CollectionName: cleanup.CollectionName,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		if errors.Is(err, merr.ErrCollectionNotFound) {
+			log.Info("the collection swapped out of alias is already dropped")
+			return nil
+		}
+		return err
+	}
+	if describeResp.GetCollectionID() != cleanup.CollectionID {
+		log.Info("the collection swapped out of alias is recreated, skip cleanup")
+		return nil
+	}
+	if len(describeResp.GetAliases()) > 0 {
+		log.Info("the collection swapped out of alias is referred by alias again, skip cleanup", zap.Strings("aliases", describeResp.GetAliases()))
+		return nil
+	}
+
+	err = merr.CheckRPCCall(c.queryCoord.ReleaseCollection(ctx, &querypb.ReleaseCollectionRequest{
+		Base: commonpbutil.NewMsgBase(
+			commonpbutil.WithMsgType(commonpb.MsgType_ReleaseCollection),
+			commonpbutil.WithSourceID(paramtable.GetNodeID()),
+		),
+		CollectionID: cleanup.CollectionID,
+	}))
+	if err != nil {
+		return err
+	}
+	if !cleanup.Drop {
+		log.Info("released the collection swapped out of alias")
+		return nil
+	}
+
+	// rootcoord refuses to drop the collection if it's referred by alias again in the meantime
+	err = merr.CheckRPCCall(c.rootCoord.DropCollection(ctx, &milvuspb.DropCollectionRequest{
+		Base: commonpbutil.NewMsgBase(
+			commonpbutil.WithMsgType(commonpb.MsgType_DropCollection),
+			commonpbutil.WithSourceID(paramtable.GetNodeID()),
+		),
+		DbName:         cleanup.DBName,
+		CollectionName: cleanup.CollectionName,
+	}))
+	if err != nil {
+		return err
+	}
+	log.Info("dropped the collection swapped out of alias")
+	return nil
+}
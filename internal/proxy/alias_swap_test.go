@@ -0,0 +1,118 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	kvmocks "github.com/milvus-io/milvus/internal/kv/mocks"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestAliasSwapRequest_validate(t *testing.T) {
+	assert.NoError(t, (&AliasSwapRequest{Alias: "alias", TargetCollection: "green"}).validate())
+	assert.Error(t, (&AliasSwapRequest{TargetCollection: "green"}).validate())
+	assert.Error(t, (&AliasSwapRequest{Alias: "alias"}).validate())
+	assert.Error(t, (&AliasSwapRequest{Alias: "alias", TargetCollection: "green", GracePeriodSeconds: -1}).validate())
+}
+
+func TestProxy_AtomicSwapAlias(t *testing.T) {
+	paramtable.Init()
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	globalMetaCache = &MetaCache{}
+	node := &Proxy{}
+
+	req, _ := http.NewRequest(http.MethodPost, mgrSwapAlias, strings.NewReader("invalid"))
+	recorder := httptest.NewRecorder()
+	node.AtomicSwapAlias(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, mgrSwapAlias, strings.NewReader(`{"alias": "alias"}`))
+	recorder = httptest.NewRecorder()
+	node.AtomicSwapAlias(recorder, req)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "target_collection")
+
+	// the cleanup can't be scheduled without etcd
+	req, _ = http.NewRequest(http.MethodPost, mgrSwapAlias, strings.NewReader(`{"alias": "alias", "target_collection": "green", "drop_source": true}`))
+	recorder = httptest.NewRecorder()
+	node.AtomicSwapAlias(recorder, req)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "cleanup")
+}
+
+func TestAliasSwapCleaner(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	watchKV := kvmocks.NewWatchKV(t)
+	rootCoord := mocks.NewMockRootCoordClient(t)
+	queryCoord := mocks.NewMockQueryCoordClient(t)
+	cleaner := newAliasSwapCleaner(watchKV, rootCoord, queryCoord)
+	assert.True(t, cleaner.Enabled())
+
+	now := time.Now().Unix()
+	cleanups := []*AliasSwapCleanup{
+		{DBName: "db1", Alias: "alias", CollectionName: "blue", CollectionID: 100, Drop: true, CleanupTime: now},
+		{DBName: "db1", Alias: "alias", CollectionName: "rollback", CollectionID: 101, Drop: true, CleanupTime: now},
+		{DBName: "db1", Alias: "alias", CollectionName: "recreated", CollectionID: 102, Drop: true, CleanupTime: now},
+		{DBName: "db1", Alias: "alias", CollectionName: "dropped", CollectionID: 103, CleanupTime: now},
+		{DBName: "db1", Alias: "alias", CollectionName: "pending", CollectionID: 104, CleanupTime: now + 3600},
+	}
+	values := make([]string, 0, len(cleanups))
+	for _, cleanup := range cleanups {
+		value, _ := json.Marshal(cleanup)
+		values = append(values, string(value))
+	}
+	watchKV.EXPECT().Save(cleanups[0].key(), mock.Anything).Return(nil).Once()
+	assert.NoError(t, cleaner.Add(cleanups[0]))
+	watchKV.EXPECT().LoadWithPrefix(aliasSwapCleanupPrefix).Return(nil, append(values, "invalid"), nil).Once()
+
+	rootCoord.EXPECT().DescribeCollection(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, req *milvuspb.DescribeCollectionRequest, opts ...grpc.CallOption) (*milvuspb.DescribeCollectionResponse, error) {
+			switch req.GetCollectionName() {
+			case "blue":
+				return &milvuspb.DescribeCollectionResponse{Status: merr.Success(), CollectionID: 100}, nil
+			case "rollback":
+				return &milvuspb.DescribeCollectionResponse{Status: merr.Success(), CollectionID: 101, Aliases: []string{"alias"}}, nil
+			case "recreated":
+				return &milvuspb.DescribeCollectionResponse{Status: merr.Success(), CollectionID: 200}, nil
+			default:
+				return &milvuspb.DescribeCollectionResponse{Status: merr.Status(merr.WrapErrCollectionNotFound(req.GetCollectionName()))}, nil
+			}
+		}).Times(4)
+	// only the collection swapped out is released and dropped
+	queryCoord.EXPECT().ReleaseCollection(mock.Anything, mock.Anything).Return(merr.Success(), nil).Once()
+	rootCoord.EXPECT().DropCollection(mock.Anything, mock.Anything).Return(merr.Success(), nil).Once()
+	for _, cleanup := range cleanups[:4] {
+		watchKV.EXPECT().Remove(cleanup.key()).Return(nil).Once()
+	}
+	cleaner.run(ctx)
+}
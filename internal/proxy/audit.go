@@ -0,0 +1,67 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/milvus-io/milvus/internal/proxy/accesslog"
+	"github.com/milvus-io/milvus/internal/proxy/accesslog/info"
+	"github.com/milvus-io/milvus/pkg/log"
+)
+
+const (
+	auditAuthFailure     = "auth_failure"
+	auditAccountLocked   = "account_locked"
+	auditPasswordExpired = "password_expired"
+	auditPasswordChanged = "password_changed"
+	auditNetworkDenied   = "network_denied"
+	auditFullScanDelete  = "full_scan_delete"
+)
+
+// auditLog writes the security related event into the access log, with the event as the method name and the fields
+// as the message, so the events are kept, rotated and uploaded along with the access log. req is the request of the
+// rpc the event happens in, whose database, collection and expression are written, could be nil.
+// The event is also logged with the `audit` field.
+func auditLog(ctx context.Context, event string, req any, fields ...zap.Field) {
+	accesslog.Audit(info.NewAuditInfo(ctx, event, req, auditDetail(fields)))
+	log.Ctx(ctx).Info("audit event", append([]zap.Field{zap.String("audit", event)}, fields...)...)
+}
+
+// auditDetail formats the fields as `key=value` sorted by key.
+func auditDetail(fields []zap.Field) string {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	pairs := make([]string, 0, len(encoder.Fields))
+	for key, value := range encoder.Fields {
+		// the verbose error with the stack trace is kept in the log only
+		if strings.HasSuffix(key, "Verbose") {
+			continue
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,35 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestAuditDetail(t *testing.T) {
+	assert.Equal(t, "", auditDetail(nil))
+	assert.Equal(t, "count=3 error=mock username=u1",
+		auditDetail([]zap.Field{zap.String("username", "u1"), zap.Int("count", 3), zap.Error(errors.New("mock"))}))
+
+	// access log not initialized
+	auditLog(context.Background(), auditAuthFailure, nil, zap.String("username", "u1"))
+}
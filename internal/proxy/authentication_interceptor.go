@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cockroachdb/errors"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
@@ -42,6 +44,25 @@ func validSourceID(ctx context.Context, authorization []string) bool {
 	return sourceID == util.MemberCredID
 }
 
+// AuthenticatePassword verifies the username and password with the lockout and expiration policies, it's shared by
+// the grpc and restful authentications. The expired password is accepted only if allowExpired.
+func AuthenticatePassword(ctx context.Context, username, password string, allowExpired bool) error {
+	if err := globalPasswordPolicy.CheckLocked(username); err != nil {
+		log.Warn("account locked", zap.String("username", username))
+		return err
+	}
+	if !authenticate(ctx, username, password) {
+		log.Warn("fail to verify password", zap.String("username", username))
+		globalPasswordPolicy.OnAuthFailure(ctx, username)
+		return errors.New("auth check failure, please check username and password are correct")
+	}
+	globalPasswordPolicy.OnAuthSuccess(username)
+	if allowExpired {
+		return nil
+	}
+	return globalPasswordPolicy.CheckExpired(ctx, username)
+}
+
 // AuthenticationInterceptor verify based on kv pair <"authorization": "token"> in header
 func AuthenticationInterceptor(ctx context.Context) (context.Context, error) {
 	// The keys within metadata.MD are normalized to lowercase.
@@ -58,6 +79,7 @@ func AuthenticationInterceptor(ctx context.Context) (context.Context, error) {
 	// 	2. if rpc call from sdk
 	if Params.CommonCfg.AuthorizationEnabled.GetAsBool() {
 		if !validSourceID(ctx, md[strings.ToLower(util.HeaderSourceID)]) {
+			var username string
 			authStrArr := md[strings.ToLower(util.HeaderAuthorize)]
 
 			if len(authStrArr) < 1 {
@@ -81,19 +103,27 @@ func AuthenticationInterceptor(ctx context.Context) (context.Context, error) {
 					return nil, status.Error(codes.Unauthenticated, "auth check failure, please check api key is correct")
 				}
 				metrics.UserRPCCounter.WithLabelValues(user).Inc()
+				username = user
 				userToken := fmt.Sprintf("%s%s%s", user, util.CredentialSeperator, util.PasswordHolder)
 				md[strings.ToLower(util.HeaderAuthorize)] = []string{crypto.Base64Encode(userToken)}
 				ctx = metadata.NewIncomingContext(ctx, md)
 			} else {
 				// username+password authentication
-				username, password := parseMD(rawToken)
-				if !passwordVerify(ctx, username, password, globalMetaCache) {
-					log.Warn("fail to verify password", zap.String("username", username))
+				var password string
+				username, password = parseMD(rawToken)
+				// the user with expired password is only allowed to update the credential
+				method, _ := grpc.Method(ctx)
+				if err := AuthenticatePassword(ctx, username, password, strings.HasSuffix(method, "/UpdateCredential")); err != nil {
 					// NOTE: don't use the merr, because it will cause the wrong retry behavior in the sdk
-					return nil, status.Error(codes.Unauthenticated, "auth check failure, please check username and password are correct")
+					return nil, status.Error(codes.Unauthenticated, err.Error())
 				}
 				metrics.UserRPCCounter.WithLabelValues(username).Inc()
 			}
+
+			if err := globalNetworkPolicy.Check(ctx, username); err != nil {
+				log.Warn("rejected by network policy", zap.String("username", username), zap.Error(err))
+				return nil, status.Error(codes.PermissionDenied, err.Error())
+			}
 		}
 	}
 	return ctx, nil
@@ -143,3 +143,23 @@ func TestAuthenticationInterceptor(t *testing.T) {
 	}
 	hoo = hookutil.DefaultHook{}
 }
+
+func TestAuthenticatePassword(t *testing.T) {
+	ctx := context.Background()
+	paramtable.Get().Save(Params.ProxyCfg.AuthLockoutMaxFailures.Key, "1")
+	defer paramtable.Get().Reset(Params.ProxyCfg.AuthLockoutMaxFailures.Key)
+	policy := globalPasswordPolicy
+	defer func() { globalPasswordPolicy = policy }()
+	globalPasswordPolicy = newPasswordPolicy()
+
+	rootCoord := &MockRootCoordClientInterface{}
+	queryCoord := &mocks.MockQueryCoordClient{}
+	err := InitMetaCache(ctx, rootCoord, queryCoord, newShardClientMgr())
+	assert.NoError(t, err)
+
+	assert.NoError(t, AuthenticatePassword(ctx, "mockUser", "mockPass", false))
+	assert.Error(t, AuthenticatePassword(ctx, "mockUser", "wrongPass", false))
+	// locked, the correct password is rejected as well
+	assert.Error(t, AuthenticatePassword(ctx, "mockUser", "mockPass", false))
+	assert.Error(t, globalPasswordPolicy.CheckLocked("mockUser"))
+}
@@ -0,0 +1,219 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/parameterutil"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	autoIDStrategyAllocator = "allocator"
+	autoIDStrategyUUIDv7    = "uuidv7"
+	autoIDStrategySnowflake = "snowflake"
+
+	// defaultSnowflakeEpoch is 2010-11-04T01:42:54.657Z, the epoch of the twitter snowflake ids
+	defaultSnowflakeEpoch = int64(1288834974657)
+
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	uuidLength            = 36
+)
+
+// autoIDStrategy is the auto id strategy of the collection with its snowflake epoch.
+type autoIDStrategy struct {
+	name           string
+	snowflakeEpoch int64
+}
+
+// getAutoIDStrategy returns the auto id strategy in the collection properties, checked against the primary field.
+func getAutoIDStrategy(pkField *schemapb.FieldSchema, properties []*commonpb.KeyValuePair) (*autoIDStrategy, error) {
+	strategy := &autoIDStrategy{name: autoIDStrategyAllocator, snowflakeEpoch: defaultSnowflakeEpoch}
+	if value, err := funcutil.GetAttrByKeyFromRepeatedKV(common.CollectionAutoIDStrategyKey, properties); err == nil {
+		strategy.name = strings.ToLower(strings.TrimSpace(value))
+	}
+	if value, err := funcutil.GetAttrByKeyFromRepeatedKV(common.CollectionAutoIDSnowflakeEpochKey, properties); err == nil {
+		epoch, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || epoch < 0 || epoch > time.Now().UnixMilli() {
+			return nil, merr.WrapErrParameterInvalidMsg("invalid %s %s, expect the unix milliseconds not after now",
+				common.CollectionAutoIDSnowflakeEpochKey, value)
+		}
+		strategy.snowflakeEpoch = epoch
+	}
+
+	switch strategy.name {
+	case autoIDStrategyAllocator:
+	case autoIDStrategyUUIDv7:
+		if pkField.GetDataType() != schemapb.DataType_VarChar {
+			return nil, merr.WrapErrParameterInvalidMsg("the uuidv7 auto id requires the varchar primary key")
+		}
+		if maxLength, err := parameterutil.GetMaxLength(pkField); err == nil && maxLength < uuidLength {
+			return nil, merr.WrapErrParameterInvalidMsg("the uuidv7 auto id requires the max length of the primary key at least %d", uuidLength)
+		}
+	case autoIDStrategySnowflake:
+		if pkField.GetDataType() != schemapb.DataType_Int64 {
+			return nil, merr.WrapErrParameterInvalidMsg("the snowflake auto id requires the int64 primary key")
+		}
+	default:
+		return nil, merr.WrapErrParameterInvalid("allocator, uuidv7 or snowflake", strategy.name, "invalid auto id strategy")
+	}
+	return strategy, nil
+}
+
+// validateAutoIDStrategy checks the auto id strategy in the properties of the collection to create.
+func validateAutoIDStrategy(schema *schemapb.CollectionSchema, properties []*commonpb.KeyValuePair) error {
+	_, strategyErr := funcutil.GetAttrByKeyFromRepeatedKV(common.CollectionAutoIDStrategyKey, properties)
+	_, epochErr := funcutil.GetAttrByKeyFromRepeatedKV(common.CollectionAutoIDSnowflakeEpochKey, properties)
+	if strategyErr != nil && epochErr != nil {
+		return nil
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(schema)
+	if err != nil {
+		return err
+	}
+	if !pkField.GetAutoID() {
+		return merr.WrapErrParameterInvalidMsg("the auto id strategy requires the auto id primary key")
+	}
+	_, err = getAutoIDStrategy(pkField, properties)
+	return err
+}
+
+// snowflakeGenerator generates the snowflake ids of the proxy, composed of the milliseconds since the epoch, the
+// worker id and the sequence in the millisecond. The last millisecond is shared by all the epochs, so the clock moving
+// backwards never repeats the ids.
+type snowflakeGenerator struct {
+	mu       sync.Mutex
+	lastMs   int64
+	sequence int64
+	now      func() time.Time
+
+	// worker is the worker id held by the proxy, -1 if not held, which is valid until the deadline, or forever if
+	// the deadline is zero.
+	worker   int64
+	deadline time.Time
+}
+
+var globalSnowflakeGenerator = newSnowflakeGenerator()
+
+func newSnowflakeGenerator() *snowflakeGenerator {
+	return &snowflakeGenerator{now: time.Now, worker: -1}
+}
+
+// setWorker sets the worker id held until the deadline, -1 if the worker id is released.
+func (g *snowflakeGenerator) setWorker(worker int64, deadline time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.worker, g.deadline = worker, deadline
+}
+
+// next returns n snowflake ids since the epoch, fails if the worker id is not held, as another proxy may hold it.
+func (g *snowflakeGenerator) next(epoch int64, n int) ([]int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.worker < 0 || (!g.deadline.IsZero() && g.now().After(g.deadline)) {
+		return nil, merr.WrapErrServiceUnavailable("the snowflake worker id of the proxy is not held")
+	}
+	node := g.worker
+	ids := make([]int64, 0, n)
+	for len(ids) < n {
+		ms := g.now().UnixMilli()
+		if ms < g.lastMs {
+			// the clock moved backwards, keep on the last millisecond to avoid the collisions
+			ms = g.lastMs
+		}
+		if ms == g.lastMs {
+			g.sequence++
+			if g.sequence >= 1<<snowflakeSequenceBits {
+				// the sequence of the millisecond is exhausted, wait for the next one
+				for ms <= g.lastMs {
+					time.Sleep(100 * time.Microsecond)
+					ms = g.now().UnixMilli()
+				}
+				g.sequence = 0
+			}
+		} else {
+			g.sequence = 0
+		}
+		g.lastMs = ms
+		ids = append(ids, (ms-epoch)<<(snowflakeNodeBits+snowflakeSequenceBits)|node<<snowflakeSequenceBits|g.sequence)
+	}
+	return ids, nil
+}
+
+// newUUIDv7 returns a uuid of version 7, the first 48 bits are the unix milliseconds so the ids sort by time.
+func newUUIDv7(now time.Time) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+	ms := uint64(now.UnixMilli())
+	for i := 0; i < 6; i++ {
+		b[i] = byte(ms >> (40 - 8*i))
+	}
+	b[6] = b[6]&0x0f | 0x70
+	b[8] = b[8]&0x3f | 0x80
+	s := hex.EncodeToString(b[:])
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:], nil
+}
+
+// autoGenPrimaryFieldDataByStrategy generates the auto ids of the rows by the auto id strategy of the collection,
+// the row ids are the auto ids for the allocator strategy.
+func autoGenPrimaryFieldDataByStrategy(schema *schemapb.CollectionSchema, pkField *schemapb.FieldSchema, rowIDs []int64) (*schemapb.FieldData, error) {
+	strategy, err := getAutoIDStrategy(pkField, schema.GetProperties())
+	if err != nil {
+		return nil, err
+	}
+	switch strategy.name {
+	case autoIDStrategyUUIDv7:
+		now := time.Now()
+		ids := make([]string, len(rowIDs))
+		for i := range ids {
+			if ids[i], err = newUUIDv7(now); err != nil {
+				return nil, err
+			}
+		}
+		return &schemapb.FieldData{
+			FieldName: pkField.GetName(),
+			Type:      pkField.GetDataType(),
+			Field: &schemapb.FieldData_Scalars{
+				Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: ids}},
+				},
+			},
+		}, nil
+	case autoIDStrategySnowflake:
+		ids, err := globalSnowflakeGenerator.next(strategy.snowflakeEpoch, len(rowIDs))
+		if err != nil {
+			return nil, err
+		}
+		return autoGenPrimaryFieldData(pkField, ids)
+	default:
+		return autoGenPrimaryFieldData(pkField, rowIDs)
+	}
+}
@@ -0,0 +1,146 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func autoIDStrategySchema(dataType schemapb.DataType, strategy string) *schemapb.CollectionSchema {
+	return &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{
+				FieldID: 100, Name: "pk", IsPrimaryKey: true, AutoID: true, DataType: dataType,
+				TypeParams: []*commonpb.KeyValuePair{{Key: common.MaxLengthKey, Value: "64"}},
+			},
+		},
+		Properties: []*commonpb.KeyValuePair{{Key: common.CollectionAutoIDStrategyKey, Value: strategy}},
+	}
+}
+
+func TestValidateAutoIDStrategy(t *testing.T) {
+	assert.NoError(t, validateAutoIDStrategy(autoIDStrategySchema(schemapb.DataType_Int64, ""), nil))
+	for _, schema := range []*schemapb.CollectionSchema{
+		autoIDStrategySchema(schemapb.DataType_Int64, "allocator"),
+		autoIDStrategySchema(schemapb.DataType_Int64, "Snowflake"),
+		autoIDStrategySchema(schemapb.DataType_VarChar, "uuidv7"),
+	} {
+		assert.NoError(t, validateAutoIDStrategy(schema, schema.GetProperties()))
+	}
+
+	short := autoIDStrategySchema(schemapb.DataType_VarChar, "uuidv7")
+	short.Fields[0].TypeParams[0].Value = "32"
+	notAutoID := autoIDStrategySchema(schemapb.DataType_Int64, "snowflake")
+	notAutoID.Fields[0].AutoID = false
+	badEpoch := autoIDStrategySchema(schemapb.DataType_Int64, "snowflake")
+	badEpoch.Properties = append(badEpoch.Properties, &commonpb.KeyValuePair{
+		Key: common.CollectionAutoIDSnowflakeEpochKey, Value: "4102444800000",
+	})
+	for _, schema := range []*schemapb.CollectionSchema{
+		autoIDStrategySchema(schemapb.DataType_Int64, "uuidv7"),
+		autoIDStrategySchema(schemapb.DataType_VarChar, "snowflake"),
+		autoIDStrategySchema(schemapb.DataType_Int64, "random"),
+		short,
+		notAutoID,
+		badEpoch,
+	} {
+		assert.ErrorIs(t, validateAutoIDStrategy(schema, schema.GetProperties()), merr.ErrParameterInvalid)
+	}
+}
+
+func TestSnowflakeGenerator(t *testing.T) {
+	paramtable.Init()
+	now := time.UnixMilli(defaultSnowflakeEpoch + 1000)
+	g := newSnowflakeGenerator()
+	g.now = func() time.Time { return now }
+	_, err := g.next(defaultSnowflakeEpoch, 1)
+	assert.ErrorIs(t, err, merr.ErrServiceUnavailable)
+
+	node := int64(5)
+	g.setWorker(node, now.Add(time.Second))
+	ids, err := g.next(defaultSnowflakeEpoch, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{
+		1000<<22 | node<<12,
+		1000<<22 | node<<12 | 1,
+		1000<<22 | node<<12 | 2,
+	}, ids)
+
+	// the clock moving backwards keeps on the last millisecond
+	now = now.Add(-time.Second)
+	ids, err = g.next(defaultSnowflakeEpoch, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000<<22|node<<12|3, ids[0])
+
+	// the worker id is not held after the deadline
+	now = now.Add(3 * time.Second)
+	_, err = g.next(defaultSnowflakeEpoch, 1)
+	assert.ErrorIs(t, err, merr.ErrServiceUnavailable)
+
+	// the exhausted sequence waits for the next millisecond
+	generator := newSnowflakeGenerator()
+	generator.setWorker(node, time.Time{})
+	ids, err = generator.next(defaultSnowflakeEpoch, 2*(1<<snowflakeSequenceBits)+1)
+	assert.NoError(t, err)
+	seen := make(map[int64]struct{})
+	for i, id := range ids {
+		seen[id] = struct{}{}
+		if i > 0 {
+			assert.Greater(t, id, ids[i-1])
+		}
+	}
+	assert.Len(t, seen, len(ids))
+}
+
+func TestAutoGenPrimaryFieldDataByStrategy(t *testing.T) {
+	paramtable.Init()
+	schema := autoIDStrategySchema(schemapb.DataType_VarChar, "uuidv7")
+	data, err := autoGenPrimaryFieldDataByStrategy(schema, schema.Fields[0], []int64{1, 2})
+	assert.NoError(t, err)
+	ids := data.GetScalars().GetStringData().GetData()
+	assert.Len(t, ids, 2)
+	assert.NotEqual(t, ids[0], ids[1])
+	for _, id := range ids {
+		assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), id)
+	}
+
+	schema = autoIDStrategySchema(schemapb.DataType_Int64, "snowflake")
+	globalSnowflakeGenerator.setWorker(-1, time.Time{})
+	_, err = autoGenPrimaryFieldDataByStrategy(schema, schema.Fields[0], []int64{1, 2})
+	assert.ErrorIs(t, err, merr.ErrServiceUnavailable)
+	globalSnowflakeGenerator.setWorker(1, time.Time{})
+	defer globalSnowflakeGenerator.setWorker(-1, time.Time{})
+	data, err = autoGenPrimaryFieldDataByStrategy(schema, schema.Fields[0], []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Len(t, data.GetScalars().GetLongData().GetData(), 2)
+	assert.NotEqual(t, int64(1), data.GetScalars().GetLongData().GetData()[0])
+
+	schema = autoIDStrategySchema(schemapb.DataType_Int64, "allocator")
+	data, err = autoGenPrimaryFieldDataByStrategy(schema, schema.Fields[0], []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, data.GetScalars().GetLongData().GetData())
+}
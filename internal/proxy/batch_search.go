@@ -0,0 +1,218 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// batchSearchGroup is the searches of a batch only differing in the vectors, searched as one of their total nq.
+type batchSearchGroup struct {
+	// key is the search request without the vectors
+	key         *milvuspb.SearchRequest
+	placeholder *commonpb.PlaceholderValue
+	members     []int
+	nqs         []int64
+	totalNQ     int64
+	mergeable   bool
+}
+
+// batchSearchPlaceholder returns the only placeholder of the search request if the request could be merged with
+// the others, or nil.
+func batchSearchPlaceholder(req *milvuspb.SearchRequest) *commonpb.PlaceholderValue {
+	if req.GetSearchByPrimaryKeys() {
+		return nil
+	}
+	// the grouped and the iterator results are not split by the query
+	if groupBy, _ := funcutil.GetAttrByKeyFromRepeatedKV(GroupByFieldKey, req.GetSearchParams()); groupBy != "" {
+		return nil
+	}
+	if iterator, _ := funcutil.GetAttrByKeyFromRepeatedKV(IteratorField, req.GetSearchParams()); iterator != "" {
+		return nil
+	}
+	group := &commonpb.PlaceholderGroup{}
+	if err := proto.Unmarshal(req.GetPlaceholderGroup(), group); err != nil || len(group.GetPlaceholders()) != 1 {
+		return nil
+	}
+	return group.GetPlaceholders()[0]
+}
+
+// groupBatchSearch groups the searches only differing in the vectors, up to the nq limit of a search.
+func groupBatchSearch(requests []*milvuspb.SearchRequest) []*batchSearchGroup {
+	nqLimit := Params.QuotaConfig.NQLimit.GetAsInt64()
+	groups := make([]*batchSearchGroup, 0)
+	for i, req := range requests {
+		key := proto.Clone(req).(*milvuspb.SearchRequest)
+		key.Base, key.PlaceholderGroup, key.Nq = nil, nil, 0
+		placeholder := batchSearchPlaceholder(req)
+		nq := int64(len(placeholder.GetValues()))
+		if placeholder != nil && nq > 0 {
+			merged := false
+			for _, group := range groups {
+				if group.mergeable && group.placeholder.GetType() == placeholder.GetType() &&
+					group.placeholder.GetTag() == placeholder.GetTag() &&
+					group.totalNQ+nq <= nqLimit && proto.Equal(group.key, key) {
+					group.placeholder.Values = append(group.placeholder.Values, placeholder.GetValues()...)
+					group.members = append(group.members, i)
+					group.nqs = append(group.nqs, nq)
+					group.totalNQ += nq
+					merged = true
+					break
+				}
+			}
+			if merged {
+				continue
+			}
+		}
+		groups = append(groups, &batchSearchGroup{
+			key:         key,
+			placeholder: placeholder,
+			members:     []int{i},
+			nqs:         []int64{nq},
+			totalNQ:     nq,
+			mergeable:   placeholder != nil && nq > 0,
+		})
+	}
+	return groups
+}
+
+// request returns the search of the group, the original request if not merged.
+func (g *batchSearchGroup) request(requests []*milvuspb.SearchRequest) (*milvuspb.SearchRequest, error) {
+	if len(g.members) == 1 {
+		return requests[g.members[0]], nil
+	}
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{Placeholders: []*commonpb.PlaceholderValue{g.placeholder}})
+	if err != nil {
+		return nil, err
+	}
+	req := proto.Clone(g.key).(*milvuspb.SearchRequest)
+	req.Base = requests[g.members[0]].GetBase()
+	req.PlaceholderGroup = placeholderGroup
+	req.Nq = g.totalNQ
+	return req, nil
+}
+
+// splitSearchResults splits the results of the merged search by the nq of the searches.
+func splitSearchResults(rsp *milvuspb.SearchResults, nqs []int64) []*milvuspb.SearchResults {
+	ret := make([]*milvuspb.SearchResults, len(nqs))
+	if len(nqs) == 1 {
+		ret[0] = rsp
+		return ret
+	}
+	data := rsp.GetResults()
+	query, offset := 0, int64(0)
+	for i, nq := range nqs {
+		sub := &schemapb.SearchResultData{
+			NumQueries:     nq,
+			TopK:           data.GetTopK(),
+			OutputFields:   data.GetOutputFields(),
+			FieldsData:     typeutil.PrepareResultFieldData(data.GetFieldsData(), nq*data.GetTopK()),
+			Ids:            &schemapb.IDs{},
+			Topks:          make([]int64, 0, nq),
+			AllSearchCount: data.GetAllSearchCount(),
+		}
+		for end := query + int(nq); query < end; query++ {
+			topk := int64(0)
+			if query < len(data.GetTopks()) {
+				topk = data.GetTopks()[query]
+			}
+			for idx := offset; idx < offset+topk; idx++ {
+				typeutil.AppendIDs(sub.Ids, data.GetIds(), int(idx))
+				sub.Scores = append(sub.Scores, data.GetScores()[idx])
+				typeutil.AppendFieldData(sub.FieldsData, data.GetFieldsData(), idx)
+			}
+			sub.Topks = append(sub.Topks, topk)
+			offset += topk
+		}
+		ret[i] = &milvuspb.SearchResults{
+			Status:         rsp.GetStatus(),
+			Results:        sub,
+			CollectionName: rsp.GetCollectionName(),
+		}
+	}
+	return ret
+}
+
+// BatchSearch executes the independent searches of a collection in one call for the offline scoring jobs. The
+// collection is resolved and its shard leaders are fetched once for the batch, and the searches only differing in
+// the vectors are merged into one search of their total nq, so they are planned, validated and fanned out to the
+// shards once. The error is returned for the batch, while the failures of the searches are in their results.
+func (node *Proxy) BatchSearch(ctx context.Context, requests []*milvuspb.SearchRequest) ([]*milvuspb.SearchResults, error) {
+	if err := merr.CheckHealthy(node.GetStateCode()); err != nil {
+		return nil, err
+	}
+	if maxRequests := Params.ProxyCfg.BatchSearchMaxRequests.GetAsInt(); len(requests) == 0 || len(requests) > maxRequests {
+		return nil, merr.WrapErrParameterInvalidRange(1, maxRequests, len(requests), "invalid number of the searches of a batch")
+	}
+	dbName, collectionName := requests[0].GetDbName(), requests[0].GetCollectionName()
+	for _, req := range requests {
+		if req.GetDbName() != dbName || req.GetCollectionName() != collectionName {
+			return nil, merr.WrapErrParameterInvalidMsg("the searches of a batch must be of the same collection")
+		}
+	}
+	collectionID, err := globalMetaCache.GetCollectionID(ctx, dbName, collectionName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := globalMetaCache.GetShards(ctx, true, dbName, collectionName, collectionID); err != nil {
+		return nil, err
+	}
+
+	groups := groupBatchSearch(requests)
+	log.Ctx(ctx).Debug("batch search", zap.String("collection", collectionName),
+		zap.Int("requests", len(requests)), zap.Int("searches", len(groups)))
+	results := make([]*milvuspb.SearchResults, len(requests))
+	group := errgroup.Group{}
+	group.SetLimit(Params.ProxyCfg.BatchSearchConcurrency.GetAsInt())
+	for _, g := range groups {
+		g := g
+		group.Go(func() error {
+			var rsp *milvuspb.SearchResults
+			req, err := g.request(requests)
+			if err == nil {
+				rsp, err = node.Search(ctx, req)
+			}
+			if err != nil {
+				rsp = &milvuspb.SearchResults{Status: merr.Status(err)}
+			}
+			if !merr.Ok(rsp.GetStatus()) {
+				for _, member := range g.members {
+					results[member] = &milvuspb.SearchResults{Status: rsp.GetStatus(), CollectionName: rsp.GetCollectionName()}
+				}
+				return nil
+			}
+			for i, sub := range splitSearchResults(rsp, g.nqs) {
+				results[g.members[i]] = sub
+			}
+			return nil
+		})
+	}
+	_ = group.Wait()
+	return results, nil
+}
@@ -0,0 +1,153 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func newBatchSearchRequest(t *testing.T, expr string, vectors ...[]byte) *milvuspb.SearchRequest {
+	placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{Placeholders: []*commonpb.PlaceholderValue{{
+		Tag:    "$0",
+		Type:   commonpb.PlaceholderType_FloatVector,
+		Values: vectors,
+	}}})
+	assert.NoError(t, err)
+	return &milvuspb.SearchRequest{
+		CollectionName:   "c1",
+		Dsl:              expr,
+		PlaceholderGroup: placeholderGroup,
+		Nq:               int64(len(vectors)),
+		SearchParams:     []*commonpb.KeyValuePair{{Key: TopKKey, Value: "2"}},
+	}
+}
+
+func TestGroupBatchSearch(t *testing.T) {
+	paramtable.Init()
+	grouped := newBatchSearchRequest(t, "a > 1", []byte("v1"))
+	grouped.SearchParams = append(grouped.SearchParams, &commonpb.KeyValuePair{Key: GroupByFieldKey, Value: "a"})
+	requests := []*milvuspb.SearchRequest{
+		newBatchSearchRequest(t, "a > 1", []byte("v0")),
+		newBatchSearchRequest(t, "a > 2", []byte("v1")),
+		newBatchSearchRequest(t, "a > 1", []byte("v2"), []byte("v3")),
+		grouped,
+		newBatchSearchRequest(t, "a > 1", []byte("v4")),
+	}
+	groups := groupBatchSearch(requests)
+	assert.Equal(t, 3, len(groups))
+	assert.Equal(t, []int{0, 2, 4}, groups[0].members)
+	assert.Equal(t, []int64{1, 2, 1}, groups[0].nqs)
+	assert.Equal(t, []int{1}, groups[1].members)
+	assert.Equal(t, []int{3}, groups[2].members)
+
+	req, err := groups[0].request(requests)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), req.GetNq())
+	assert.Equal(t, "a > 1", req.GetDsl())
+	placeholders := &commonpb.PlaceholderGroup{}
+	assert.NoError(t, proto.Unmarshal(req.GetPlaceholderGroup(), placeholders))
+	assert.Equal(t, [][]byte{[]byte("v0"), []byte("v2"), []byte("v3"), []byte("v4")}, placeholders.GetPlaceholders()[0].GetValues())
+
+	req, err = groups[1].request(requests)
+	assert.NoError(t, err)
+	assert.Same(t, requests[1], req)
+
+	// the merged nq is limited
+	paramtable.Get().Save(Params.QuotaConfig.NQLimit.Key, "3")
+	defer paramtable.Get().Reset(Params.QuotaConfig.NQLimit.Key)
+	groups = groupBatchSearch(requests)
+	assert.Equal(t, 4, len(groups))
+	assert.Equal(t, []int{0, 2}, groups[0].members)
+	assert.Equal(t, []int{4}, groups[3].members)
+}
+
+func TestSplitSearchResults(t *testing.T) {
+	rsp := &milvuspb.SearchResults{
+		Status:         merr.Success(),
+		CollectionName: "c1",
+		Results: &schemapb.SearchResultData{
+			NumQueries: 3,
+			TopK:       2,
+			Topks:      []int64{2, 1, 2},
+			Scores:     []float32{0.9, 0.8, 0.7, 0.6, 0.5},
+			Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2, 3, 4, 5}}}},
+			FieldsData: []*schemapb.FieldData{{
+				Type:      schemapb.DataType_Int64,
+				FieldName: "a",
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{10, 20, 30, 40, 50}}},
+				}},
+			}},
+			OutputFields: []string{"a"},
+		},
+	}
+	results := splitSearchResults(rsp, []int64{2, 1})
+	assert.Equal(t, 2, len(results))
+	assert.Equal(t, int64(2), results[0].GetResults().GetNumQueries())
+	assert.Equal(t, []int64{2, 1}, results[0].GetResults().GetTopks())
+	assert.Equal(t, []int64{1, 2, 3}, results[0].GetResults().GetIds().GetIntId().GetData())
+	assert.Equal(t, []float32{0.9, 0.8, 0.7}, results[0].GetResults().GetScores())
+	assert.Equal(t, []int64{10, 20, 30}, results[0].GetResults().GetFieldsData()[0].GetScalars().GetLongData().GetData())
+	assert.Equal(t, []int64{2}, results[1].GetResults().GetTopks())
+	assert.Equal(t, []int64{4, 5}, results[1].GetResults().GetIds().GetIntId().GetData())
+	assert.Equal(t, []int64{40, 50}, results[1].GetResults().GetFieldsData()[0].GetScalars().GetLongData().GetData())
+	assert.Equal(t, []string{"a"}, results[1].GetResults().GetOutputFields())
+	assert.Equal(t, "c1", results[1].GetCollectionName())
+
+	assert.Same(t, rsp, splitSearchResults(rsp, []int64{3})[0])
+}
+
+func TestBatchSearchValidate(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	node := &Proxy{}
+	node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := node.BatchSearch(ctx, []*milvuspb.SearchRequest{newBatchSearchRequest(t, "", []byte("v0"))})
+	assert.ErrorIs(t, err, merr.ErrServiceNotReady)
+
+	node.UpdateStateCode(commonpb.StateCode_Healthy)
+	_, err = node.BatchSearch(ctx, nil)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	paramtable.Get().Save(Params.ProxyCfg.BatchSearchMaxRequests.Key, "1")
+	_, err = node.BatchSearch(ctx, []*milvuspb.SearchRequest{newBatchSearchRequest(t, "", []byte("v0")), newBatchSearchRequest(t, "", []byte("v1"))})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	paramtable.Get().Reset(Params.ProxyCfg.BatchSearchMaxRequests.Key)
+
+	other := newBatchSearchRequest(t, "", []byte("v1"))
+	other.CollectionName = "c2"
+	_, err = node.BatchSearch(ctx, []*milvuspb.SearchRequest{newBatchSearchRequest(t, "", []byte("v0")), other})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetCollectionID(mock.Anything, mock.Anything, "c1").Return(0, merr.WrapErrCollectionNotFound("c1"))
+	globalMetaCache = mockCache
+	defer func() { globalMetaCache = nil }()
+	_, err = node.BatchSearch(ctx, []*milvuspb.SearchRequest{newBatchSearchRequest(t, "", []byte("v0"))})
+	assert.ErrorIs(t, err, merr.ErrCollectionNotFound)
+}
@@ -0,0 +1,264 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proxy/connection"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// canaryConfig is the canary routing configured in the properties of the stable collection,
+// which applies to the requests through the aliases of the stable collection.
+type canaryConfig struct {
+	target            string
+	percentage        int
+	recallSampleRatio float64
+}
+
+// parseCanaryConfig returns nil if the canary target is not set in the properties.
+func parseCanaryConfig(props []*commonpb.KeyValuePair) (*canaryConfig, error) {
+	config := &canaryConfig{}
+	for _, p := range props {
+		var err error
+		switch p.GetKey() {
+		case common.CollectionCanaryTargetKey:
+			config.target = p.GetValue()
+		case common.CollectionCanaryPercentageKey:
+			config.percentage, err = strconv.Atoi(p.GetValue())
+			if err != nil || config.percentage < 0 || config.percentage > 100 {
+				return nil, merr.WrapErrParameterInvalidMsg("invalid %s value %s, should be an integer in [0, 100]", p.GetKey(), p.GetValue())
+			}
+		case common.CollectionCanaryRecallSampleRatioKey:
+			config.recallSampleRatio, err = strconv.ParseFloat(p.GetValue(), 64)
+			if err != nil || config.recallSampleRatio < 0 || config.recallSampleRatio > 1 {
+				return nil, merr.WrapErrParameterInvalidMsg("invalid %s value %s, should be in [0, 1]", p.GetKey(), p.GetValue())
+			}
+		}
+	}
+	if config.target == "" {
+		return nil, nil
+	}
+	return config, nil
+}
+
+// validateCanaryConfig checks the canary properties to alter, the collection cannot be the canary of itself.
+func validateCanaryConfig(collectionName string, props []*commonpb.KeyValuePair) error {
+	config, err := parseCanaryConfig(props)
+	if err != nil {
+		return err
+	}
+	if config != nil && config.target == collectionName {
+		return merr.WrapErrParameterInvalidMsg("collection %s cannot be the canary of itself", collectionName)
+	}
+	return nil
+}
+
+// canaryRouteKey returns the key to route the requests of the same client to the same collection,
+// empty if the client is unknown.
+func canaryRouteKey(ctx context.Context) string {
+	if identifier, err := connection.GetIdentifierFromContext(ctx); err == nil {
+		return strconv.FormatInt(identifier, 10)
+	}
+	if username, err := GetCurUserFromContext(ctx); err == nil {
+		return username
+	}
+	return ""
+}
+
+// isCanaryChosen decides whether the request is routed to the candidate collection,
+// the clients are sticky to the decision as long as the percentage is unchanged.
+func isCanaryChosen(key, alias string, percentage int) bool {
+	if key == "" {
+		return rand.Intn(100) < percentage
+	}
+	h := fnv.New32a()
+	h.Write([]byte(alias))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return int(h.Sum32()%100) < percentage
+}
+
+// canaryRoute is the routing decision of a search or query request through the alias with canary configured.
+type canaryRoute struct {
+	msgType string
+	dbName  string
+	alias   string
+	canary  bool
+	start   time.Time
+	// stableRequest is the copy of the canary search to compute the recall against the stable collection
+	stableRequest *milvuspb.SearchRequest
+}
+
+// routeCanary replaces the collection name of the request with the candidate collection if chosen,
+// returns nil if the request is not through an alias with canary configured.
+func routeCanary(ctx context.Context, request proto.Message) *canaryRoute {
+	if globalMetaCache == nil || isMirroredRequest(ctx) {
+		return nil
+	}
+	var dbName, name, msgType string
+	switch r := request.(type) {
+	case *milvuspb.SearchRequest:
+		dbName, name, msgType = r.GetDbName(), r.GetCollectionName(), metrics.SearchLabel
+	case *milvuspb.QueryRequest:
+		dbName, name, msgType = r.GetDbName(), r.GetCollectionName(), metrics.QueryLabel
+	default:
+		return nil
+	}
+	if name == "" {
+		return nil
+	}
+
+	schema, err := globalMetaCache.GetCollectionSchema(ctx, dbName, name)
+	if err != nil || schema.GetName() == name {
+		return nil
+	}
+	config, err := parseCanaryConfig(schema.GetProperties())
+	if err != nil || config == nil {
+		return nil
+	}
+
+	route := &canaryRoute{
+		msgType: msgType,
+		dbName:  dbName,
+		alias:   name,
+		canary:  isCanaryChosen(canaryRouteKey(ctx), name, config.percentage),
+		start:   time.Now(),
+	}
+	if !route.canary {
+		return route
+	}
+	// the privilege is checked against the alias by the interceptor, check it against the candidate as well,
+	// the request is routed to the stable collection if the user has no privilege on the candidate.
+	routed := proto.Clone(request)
+	switch r := routed.(type) {
+	case *milvuspb.SearchRequest:
+		r.CollectionName = config.target
+	case *milvuspb.QueryRequest:
+		r.CollectionName = config.target
+	}
+	if _, err := PrivilegeInterceptor(ctx, routed); err != nil {
+		log.Ctx(ctx).RatedWarn(10, "no privilege on the canary collection, route to the stable one",
+			zap.String("alias", name), zap.String("canary", config.target), zap.Error(err))
+		route.canary = false
+		return route
+	}
+	switch r := request.(type) {
+	case *milvuspb.SearchRequest:
+		if rand.Float64() < config.recallSampleRatio {
+			route.stableRequest = proto.Clone(r).(*milvuspb.SearchRequest)
+		}
+		r.CollectionName = config.target
+	case *milvuspb.QueryRequest:
+		r.CollectionName = config.target
+	}
+	return route
+}
+
+// finish records the latency by route target, and restores the alias in the result, nil route is a no-op.
+func (r *canaryRoute) finish(node *Proxy, result proto.Message, err error) {
+	if r == nil {
+		return
+	}
+	var status *commonpb.Status
+	switch res := result.(type) {
+	case *milvuspb.SearchResults:
+		status = res.GetStatus()
+		if res != nil {
+			res.CollectionName = r.alias
+		}
+	case *milvuspb.QueryResults:
+		status = res.GetStatus()
+		if res != nil {
+			res.CollectionName = r.alias
+		}
+	}
+
+	target, statusLabel := metrics.CanaryStableLabel, metrics.SuccessLabel
+	if r.canary {
+		target = metrics.CanaryCandidateLabel
+	}
+	if err != nil || !merr.Ok(status) {
+		statusLabel = metrics.FailLabel
+	}
+	nodeID := paramtable.GetStringNodeID()
+	metrics.ProxyCanaryRequestLatency.WithLabelValues(nodeID, r.msgType, r.alias, target, statusLabel).
+		Observe(float64(time.Since(r.start).Milliseconds()))
+
+	if r.stableRequest != nil && statusLabel == metrics.SuccessLabel {
+		r.computeRecall(node, result.(*milvuspb.SearchResults).GetResults())
+	}
+}
+
+// computeRecall searches the stable collection in background and records the recall of the canary results against it.
+func (r *canaryRoute) computeRecall(node *Proxy, canaryResults *schemapb.SearchResultData) {
+	select {
+	case getMirrorSlots() <- struct{}{}:
+	default:
+		return
+	}
+
+	go func() {
+		defer func() { <-getMirrorSlots() }()
+		ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), mirroredRequestKey{}, true),
+			Params.ProxyCfg.MirrorTimeout.GetAsDuration(time.Millisecond))
+		defer cancel()
+
+		resp, err := node.Search(ctx, r.stableRequest)
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			log.Ctx(ctx).RatedWarn(10, "failed to search stable collection for canary recall", zap.String("alias", r.alias), zap.Error(err))
+			return
+		}
+		metrics.ProxyCanarySearchRecall.WithLabelValues(paramtable.GetStringNodeID(), r.alias).
+			Observe(idsRecall(canaryResults.GetIds(), resp.GetResults().GetIds()))
+	}()
+}
+
+// idsRecall returns the ratio of the ground truth ids found in the ids, 1 if the ground truth is empty.
+func idsRecall(ids, groundTruth *schemapb.IDs) float64 {
+	total := typeutil.GetSizeOfIDs(groundTruth)
+	if total == 0 {
+		return 1
+	}
+	set := typeutil.NewSet[any]()
+	for i := 0; i < typeutil.GetSizeOfIDs(ids); i++ {
+		set.Insert(typeutil.GetPK(ids, int64(i)))
+	}
+	found := 0
+	for i := 0; i < total; i++ {
+		if set.Contain(typeutil.GetPK(groundTruth, int64(i))) {
+			found++
+		}
+	}
+	return float64(found) / float64(total)
+}
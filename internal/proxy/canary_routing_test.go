@@ -0,0 +1,137 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestParseCanaryConfig(t *testing.T) {
+	config, err := parseCanaryConfig(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	config, err = parseCanaryConfig([]*commonpb.KeyValuePair{
+		{Key: common.CollectionCanaryTargetKey, Value: "green"},
+		{Key: common.CollectionCanaryPercentageKey, Value: "10"},
+		{Key: common.CollectionCanaryRecallSampleRatioKey, Value: "0.5"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &canaryConfig{target: "green", percentage: 10, recallSampleRatio: 0.5}, config)
+
+	for _, kv := range []*commonpb.KeyValuePair{
+		{Key: common.CollectionCanaryPercentageKey, Value: "101"},
+		{Key: common.CollectionCanaryPercentageKey, Value: "0.5"},
+		{Key: common.CollectionCanaryRecallSampleRatioKey, Value: "-1"},
+	} {
+		_, err = parseCanaryConfig([]*commonpb.KeyValuePair{kv})
+		assert.Error(t, err, kv.GetValue())
+	}
+
+	assert.NoError(t, validateCanaryConfig("blue", []*commonpb.KeyValuePair{{Key: common.CollectionCanaryTargetKey, Value: "green"}}))
+	assert.Error(t, validateCanaryConfig("blue", []*commonpb.KeyValuePair{{Key: common.CollectionCanaryTargetKey, Value: "blue"}}))
+}
+
+func TestIsCanaryChosen(t *testing.T) {
+	chosen := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint(i)
+		if isCanaryChosen(key, "alias", 30) {
+			chosen++
+		}
+		// sticky to the same client
+		assert.Equal(t, isCanaryChosen(key, "alias", 30), isCanaryChosen(key, "alias", 30))
+		// the clients chosen keep chosen with larger percentage
+		if isCanaryChosen(key, "alias", 30) {
+			assert.True(t, isCanaryChosen(key, "alias", 50))
+		}
+	}
+	assert.InDelta(t, 300, chosen, 60)
+
+	assert.False(t, isCanaryChosen("", "alias", 0))
+	assert.True(t, isCanaryChosen("", "alias", 100))
+}
+
+func TestRouteCanary(t *testing.T) {
+	paramtable.Init()
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetCollectionSchema(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, dbName string, name string) (*schemaInfo, error) {
+			// both "alias" and "blue" are resolved to the stable collection "blue"
+			return newSchemaInfo(&schemapb.CollectionSchema{
+				Name: "blue",
+				Properties: []*commonpb.KeyValuePair{
+					{Key: common.CollectionCanaryTargetKey, Value: "green"},
+					{Key: common.CollectionCanaryPercentageKey, Value: "100"},
+				},
+			}), nil
+		})
+	globalMetaCache = mockCache
+
+	// requests by the collection name are not routed
+	assert.Nil(t, routeCanary(context.Background(), &milvuspb.SearchRequest{CollectionName: "blue"}))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(util.IdentifierKey, "1"))
+	request := &milvuspb.QueryRequest{CollectionName: "alias"}
+	route := routeCanary(ctx, request)
+	assert.NotNil(t, route)
+	assert.True(t, route.canary)
+	assert.Equal(t, "green", request.GetCollectionName())
+
+	result := &milvuspb.QueryResults{Status: &commonpb.Status{}, CollectionName: "green"}
+	route.finish(nil, result, nil)
+	assert.Equal(t, "alias", result.GetCollectionName())
+
+	// nil route is a no-op
+	var nilRoute *canaryRoute
+	nilRoute.finish(nil, result, nil)
+
+	// routed to the stable collection without the privilege on the candidate
+	paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+	request = &milvuspb.QueryRequest{CollectionName: "alias"}
+	route = routeCanary(ctx, request)
+	assert.NotNil(t, route)
+	assert.False(t, route.canary)
+	assert.Equal(t, "alias", request.GetCollectionName())
+}
+
+func TestIDsRecall(t *testing.T) {
+	intIDs := func(ids ...int64) *schemapb.IDs {
+		return &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}}
+	}
+	assert.Equal(t, float64(1), idsRecall(intIDs(1), nil))
+	assert.Equal(t, 0.5, idsRecall(intIDs(1, 3), intIDs(1, 2)))
+	assert.Equal(t, float64(1), idsRecall(intIDs(2, 1, 3), intIDs(1, 2)))
+	assert.Equal(t, float64(0), idsRecall(nil, intIDs(1, 2)))
+}
@@ -0,0 +1,292 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+type ChangeEventType string
+
+const (
+	ChangeEventInsert ChangeEventType = "insert"
+	ChangeEventDelete ChangeEventType = "delete"
+)
+
+// ChangeSubscription subscribes the changes of a collection, or of the partitions if specified.
+// The changes are tailed from StartTs, or from now if StartTs is zero. StartTs must not be older than
+// the channel checkpoints of the collection, since the channels are seeked from the checkpoints.
+type ChangeSubscription struct {
+	DbName         string
+	CollectionName string
+	PartitionNames []string
+	StartTs        uint64
+}
+
+// ChangeEvent is an insert or a delete of the subscribed collection, the columns of the inserted rows
+// are in FieldsData and the primary keys of the deleted rows are in IDs.
+type ChangeEvent struct {
+	Type        ChangeEventType
+	Timestamp   uint64
+	PartitionID int64
+	NumRows     int64
+	FieldsData  []*schemapb.FieldData
+	IDs         *schemapb.IDs
+}
+
+// changeStreamer taps the dml channels for the change subscriptions.
+type changeStreamer struct {
+	mu            sync.Mutex
+	factory       msgstream.Factory
+	chMgr         channelsMgr
+	dataCoord     types.DataCoordClient
+	subscriptions int
+}
+
+var globalChangeStreamer = &changeStreamer{}
+
+func (s *changeStreamer) init(factory msgstream.Factory, chMgr channelsMgr, dataCoord types.DataCoordClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.factory = factory
+	s.chMgr = chMgr
+	s.dataCoord = dataCoord
+}
+
+// acquire reserves a subscription, the number of the concurrent subscriptions is limited since each of them
+// consumes all the dml channels of the collection.
+func (s *changeStreamer) acquire() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !Params.ProxyCfg.ChangeStreamEnabled.GetAsBool() {
+		return merr.WrapErrServiceUnavailable("change stream is disabled")
+	}
+	if s.factory == nil || s.chMgr == nil || s.dataCoord == nil {
+		return merr.WrapErrServiceNotReady(paramtable.GetRole(), paramtable.GetNodeID(), "change stream")
+	}
+	if limit := Params.ProxyCfg.ChangeStreamMaxSubscriptions.GetAsInt(); s.subscriptions >= limit {
+		return merr.WrapErrServiceQuotaExceeded(fmt.Sprintf("too many change subscriptions, the limit is %d", limit))
+	}
+	s.subscriptions++
+	return nil
+}
+
+func (s *changeStreamer) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscriptions--
+}
+
+// changeFilter picks the changes of the subscribed collection out of the shared dml channels.
+type changeFilter struct {
+	collectionID int64
+	vchannels    typeutil.Set[string]
+	partitionIDs typeutil.Set[int64]
+	startTs      uint64
+}
+
+func (f *changeFilter) match(collectionID int64, vchannel string, partitionID int64, ts uint64) bool {
+	return collectionID == f.collectionID &&
+		f.vchannels.Contain(vchannel) &&
+		(f.partitionIDs.Len() == 0 || f.partitionIDs.Contain(partitionID)) &&
+		ts >= f.startTs
+}
+
+// events converts the dml messages of the pack to the change events matched.
+func (f *changeFilter) events(pack *msgstream.MsgPack) []*ChangeEvent {
+	events := make([]*ChangeEvent, 0)
+	for _, msg := range pack.Msgs {
+		switch msg := msg.(type) {
+		case *msgstream.InsertMsg:
+			if !msg.IsColumnBased() || !f.match(msg.GetCollectionID(), msg.GetShardName(), msg.GetPartitionID(), msg.EndTs()) {
+				continue
+			}
+			events = append(events, &ChangeEvent{
+				Type:        ChangeEventInsert,
+				Timestamp:   msg.EndTs(),
+				PartitionID: msg.GetPartitionID(),
+				NumRows:     int64(msg.NRows()),
+				FieldsData:  msg.GetFieldsData(),
+			})
+		case *msgstream.DeleteMsg:
+			if !f.match(msg.GetCollectionID(), msg.GetShardName(), msg.GetPartitionID(), msg.EndTs()) {
+				continue
+			}
+			events = append(events, &ChangeEvent{
+				Type:        ChangeEventDelete,
+				Timestamp:   msg.EndTs(),
+				PartitionID: msg.GetPartitionID(),
+				NumRows:     msg.GetNumRows(),
+				IDs:         msg.GetPrimaryKeys(),
+			})
+		}
+	}
+	return events
+}
+
+func (s *changeStreamer) newFilter(ctx context.Context, req *ChangeSubscription) (*changeFilter, []string, error) {
+	collectionID, err := globalMetaCache.GetCollectionID(ctx, req.DbName, req.CollectionName)
+	if err != nil {
+		return nil, nil, err
+	}
+	vchannels, err := s.chMgr.getVChannels(collectionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	partitionIDs := typeutil.NewSet[int64]()
+	for _, partitionName := range req.PartitionNames {
+		partitionID, err := globalMetaCache.GetPartitionID(ctx, req.DbName, req.CollectionName, partitionName)
+		if err != nil {
+			return nil, nil, err
+		}
+		partitionIDs.Insert(partitionID)
+	}
+	pchannels := typeutil.NewSet[string]()
+	for _, vchannel := range vchannels {
+		pchannels.Insert(funcutil.ToPhysicalChannel(vchannel))
+	}
+	return &changeFilter{
+		collectionID: collectionID,
+		vchannels:    typeutil.NewSet(vchannels...),
+		partitionIDs: partitionIDs,
+		startTs:      req.StartTs,
+	}, pchannels.Collect(), nil
+}
+
+// seekPositions returns the positions of the physical channels to consume the changes since startTs from,
+// which are the earliest channel checkpoints of the collection on each of them.
+func (s *changeStreamer) seekPositions(ctx context.Context, collectionID int64, startTs uint64) ([]*msgpb.MsgPosition, error) {
+	resp, err := s.dataCoord.GetRecoveryInfoV2(ctx, &datapb.GetRecoveryInfoRequestV2{CollectionID: collectionID})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	positions := make(map[string]*msgpb.MsgPosition)
+	for _, channel := range resp.GetChannels() {
+		position := channel.GetSeekPosition()
+		if position == nil {
+			return nil, merr.WrapErrChannelNotAvailable(channel.GetChannelName(), "no checkpoint to seek the changes from")
+		}
+		if position.GetTimestamp() > startTs {
+			return nil, merr.WrapErrParameterInvalidMsg("startTs %d is older than the checkpoint %d of channel %s, the changes before the checkpoint can't be subscribed",
+				startTs, position.GetTimestamp(), channel.GetChannelName())
+		}
+		pchannel := funcutil.ToPhysicalChannel(position.GetChannelName())
+		if old, ok := positions[pchannel]; !ok || position.GetTimestamp() < old.GetTimestamp() {
+			positions[pchannel] = &msgpb.MsgPosition{
+				ChannelName: pchannel,
+				MsgID:       position.GetMsgID(),
+				MsgGroup:    position.GetMsgGroup(),
+				Timestamp:   position.GetTimestamp(),
+			}
+		}
+	}
+	return lo.Values(positions), nil
+}
+
+// SubscribeChanges tails the inserts and deletes of a collection by the dml channels, ordered by the timestamp,
+// until the context is done or send fails. If a start timestamp is given, the channels are seeked from the channel
+// checkpoints before it, so the changes since then are sent. The inserted rows are masked for the current user.
+func SubscribeChanges(ctx context.Context, req *ChangeSubscription, send func(event *ChangeEvent) error) error {
+	return globalChangeStreamer.subscribe(ctx, req, send)
+}
+
+func (s *changeStreamer) subscribe(ctx context.Context, req *ChangeSubscription, send func(event *ChangeEvent) error) error {
+	if err := s.acquire(); err != nil {
+		return err
+	}
+	defer s.release()
+
+	filter, pchannels, err := s.newFilter(ctx, req)
+	if err != nil {
+		return err
+	}
+	schema, err := globalMetaCache.GetCollectionSchema(ctx, req.DbName, req.CollectionName)
+	if err != nil {
+		return err
+	}
+	var positions []*msgpb.MsgPosition
+	if req.StartTs > 0 {
+		positions, err = s.seekPositions(ctx, filter.collectionID, req.StartTs)
+		if err != nil {
+			return err
+		}
+	}
+	stream, err := s.factory.NewTtMsgStream(ctx)
+	if err != nil {
+		return err
+	}
+	subName := fmt.Sprintf("%s-proxy-%d-change-stream-%s", Params.CommonCfg.ClusterPrefix.GetValue(), paramtable.GetNodeID(), funcutil.RandomString(8))
+	// the subscription is removed after the stream closed
+	defer func() {
+		if err := s.factory.NewMsgStreamDisposer(context.Background())(pchannels, subName); err != nil {
+			log.Warn("failed to remove the subscription of the change stream", zap.String("subName", subName), zap.Error(err))
+		}
+	}()
+	defer stream.Close()
+	position := mqwrapper.SubscriptionPositionLatest
+	if len(positions) > 0 {
+		position = mqwrapper.SubscriptionPositionUnknown
+	}
+	if err := stream.AsConsumer(ctx, pchannels, subName, position); err != nil {
+		return err
+	}
+	if len(positions) > 0 {
+		if err := stream.Seek(ctx, positions); err != nil {
+			return err
+		}
+	}
+	log.Ctx(ctx).Info("change subscription started", zap.String("collection", req.CollectionName),
+		zap.Strings("pchannels", pchannels), zap.Uint64("startTs", req.StartTs))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case pack, ok := <-stream.Chan():
+			if !ok {
+				return merr.WrapErrServiceInternal("the dml stream of the change subscription closed")
+			}
+			for _, event := range filter.events(pack) {
+				if event.Type == ChangeEventInsert {
+					if err := globalMaskingPolicy.Apply(ctx, req.DbName, schema.CollectionSchema, event.FieldsData); err != nil {
+						return err
+					}
+				}
+				if err := send(event); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
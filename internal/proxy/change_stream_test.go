@@ -0,0 +1,251 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+func newChangeStreamInsertMsg(collectionID, partitionID int64, vchannel string, ts uint64) *msgstream.InsertMsg {
+	return &msgstream.InsertMsg{
+		BaseMsg: msgstream.BaseMsg{EndTimestamp: ts},
+		InsertRequest: msgpb.InsertRequest{
+			Base:         &commonpb.MsgBase{MsgType: commonpb.MsgType_Insert},
+			CollectionID: collectionID,
+			PartitionID:  partitionID,
+			ShardName:    vchannel,
+			Version:      msgpb.InsertDataVersion_ColumnBased,
+			NumRows:      2,
+			FieldsData: []*schemapb.FieldData{
+				{FieldName: "pk", Type: schemapb.DataType_Int64, Field: &schemapb.FieldData_Scalars{
+					Scalars: &schemapb.ScalarField{Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{1, 2}}}},
+				}},
+			},
+		},
+	}
+}
+
+func newChangeStreamDeleteMsg(collectionID, partitionID int64, vchannel string, ts uint64) *msgstream.DeleteMsg {
+	return &msgstream.DeleteMsg{
+		BaseMsg: msgstream.BaseMsg{EndTimestamp: ts},
+		DeleteRequest: msgpb.DeleteRequest{
+			Base:         &commonpb.MsgBase{MsgType: commonpb.MsgType_Delete},
+			CollectionID: collectionID,
+			PartitionID:  partitionID,
+			ShardName:    vchannel,
+			NumRows:      1,
+			PrimaryKeys:  &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1}}}},
+		},
+	}
+}
+
+func TestChangeFilter(t *testing.T) {
+	filter := &changeFilter{
+		collectionID: 1,
+		vchannels:    typeutil.NewSet("dml_0_1v0"),
+		partitionIDs: typeutil.NewSet[int64](),
+		startTs:      100,
+	}
+	pack := &msgstream.MsgPack{Msgs: []msgstream.TsMsg{
+		newChangeStreamInsertMsg(1, 10, "dml_0_1v0", 100),
+		newChangeStreamInsertMsg(2, 20, "dml_0_2v0", 100),
+		newChangeStreamInsertMsg(1, 10, "dml_0_1v0", 99),
+		newChangeStreamDeleteMsg(1, 11, "dml_0_1v0", 101),
+		&msgstream.TimeTickMsg{},
+	}}
+	events := filter.events(pack)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, ChangeEventInsert, events[0].Type)
+	assert.Equal(t, uint64(100), events[0].Timestamp)
+	assert.Equal(t, int64(2), events[0].NumRows)
+	assert.Equal(t, 1, len(events[0].FieldsData))
+	assert.Equal(t, ChangeEventDelete, events[1].Type)
+	assert.Equal(t, int64(11), events[1].PartitionID)
+	assert.Equal(t, []int64{1}, events[1].IDs.GetIntId().GetData())
+
+	filter.partitionIDs.Insert(11)
+	events = filter.events(pack)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, ChangeEventDelete, events[0].Type)
+}
+
+func TestSubscribeChanges(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(Params.ProxyCfg.ChangeStreamEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.ChangeStreamEnabled.Key)
+
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	mc := NewMockCache(t)
+	mc.EXPECT().GetCollectionID(mock.Anything, mock.Anything, "coll").Return(1, nil).Maybe()
+	mc.EXPECT().GetCollectionID(mock.Anything, mock.Anything, "unknown").Return(0, merr.WrapErrCollectionNotFound("unknown")).Maybe()
+	mc.EXPECT().GetCollectionSchema(mock.Anything, mock.Anything, "coll").Return(newSchemaInfo(&schemapb.CollectionSchema{Name: "coll"}), nil).Maybe()
+	mc.EXPECT().GetUserRole("alice").Return([]string{"analyst"}).Maybe()
+	globalMetaCache = mc
+
+	chMgr := NewMockChannelsMgr(t)
+	chMgr.EXPECT().getVChannels(int64(1)).Return([]string{"dml_0_1v0", "dml_1_1v1"}, nil).Maybe()
+
+	// the checkpoints of the vchannels, dml_0 is seeked from the earlier one of its vchannels
+	checkpoints := []*datapb.VchannelInfo{
+		{ChannelName: "dml_0_1v0", SeekPosition: &msgpb.MsgPosition{ChannelName: "dml_0_1v0", MsgID: []byte{1}, Timestamp: 50}},
+		{ChannelName: "dml_1_1v1", SeekPosition: &msgpb.MsgPosition{ChannelName: "dml_1_1v1", MsgID: []byte{2}, Timestamp: 60}},
+	}
+	dc := mocks.NewMockDataCoordClient(t)
+	dc.EXPECT().GetRecoveryInfoV2(mock.Anything, mock.Anything).Return(&datapb.GetRecoveryInfoResponseV2{
+		Status:   merr.Success(),
+		Channels: checkpoints,
+	}, nil).Maybe()
+
+	var seeked []*msgpb.MsgPosition
+	var subPosition mqwrapper.SubscriptionInitialPosition
+	newStreamer := func(t *testing.T, packs ...*msgstream.MsgPack) *changeStreamer {
+		seeked, subPosition = nil, mqwrapper.SubscriptionPositionUnknown
+		ch := make(chan *msgstream.MsgPack, len(packs))
+		for _, pack := range packs {
+			ch <- pack
+		}
+		stream := msgstream.NewMockMsgStream(t)
+		stream.EXPECT().AsConsumer(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, channels []string, subName string, position mqwrapper.SubscriptionInitialPosition) error {
+				assert.ElementsMatch(t, []string{"dml_0", "dml_1"}, channels)
+				subPosition = position
+				return nil
+			}).Maybe()
+		stream.EXPECT().Seek(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, positions []*msgpb.MsgPosition) error {
+			seeked = positions
+			return nil
+		}).Maybe()
+		stream.EXPECT().Chan().Return(ch).Maybe()
+		stream.EXPECT().Close().Maybe()
+		factory := msgstream.NewMockFactory(t)
+		factory.EXPECT().NewTtMsgStream(mock.Anything).Return(stream, nil).Maybe()
+		factory.EXPECT().NewMsgStreamDisposer(mock.Anything).Return(func(channels []string, subName string) error { return nil }).Maybe()
+		s := &changeStreamer{}
+		s.init(factory, chMgr, dc)
+		return s
+	}
+
+	t.Run("subscribe", func(t *testing.T) {
+		s := newStreamer(t,
+			&msgstream.MsgPack{Msgs: []msgstream.TsMsg{newChangeStreamInsertMsg(1, 10, "dml_0_1v0", 100)}},
+			&msgstream.MsgPack{Msgs: []msgstream.TsMsg{newChangeStreamDeleteMsg(1, 10, "dml_1_1v1", 200)}},
+		)
+		events := make([]*ChangeEvent, 0)
+		stop := errors.New("stop")
+		err := s.subscribe(context.Background(), &ChangeSubscription{CollectionName: "coll", StartTs: 60}, func(event *ChangeEvent) error {
+			events = append(events, event)
+			if len(events) == 2 {
+				return stop
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, ChangeEventInsert, events[0].Type)
+		assert.Equal(t, ChangeEventDelete, events[1].Type)
+		assert.Equal(t, 0, s.subscriptions)
+		assert.Equal(t, mqwrapper.SubscriptionPositionUnknown, subPosition)
+		assert.ElementsMatch(t, []*msgpb.MsgPosition{
+			{ChannelName: "dml_0", MsgID: []byte{1}, Timestamp: 50},
+			{ChannelName: "dml_1", MsgID: []byte{2}, Timestamp: 60},
+		}, seeked)
+	})
+
+	t.Run("subscribe from now", func(t *testing.T) {
+		s := newStreamer(t, &msgstream.MsgPack{Msgs: []msgstream.TsMsg{newChangeStreamInsertMsg(1, 10, "dml_0_1v0", 100)}})
+		stop := errors.New("stop")
+		err := s.subscribe(context.Background(), &ChangeSubscription{CollectionName: "coll"}, func(event *ChangeEvent) error {
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, mqwrapper.SubscriptionPositionLatest, subPosition)
+		assert.Nil(t, seeked)
+	})
+
+	t.Run("start ts before checkpoint", func(t *testing.T) {
+		s := newStreamer(t)
+		err := s.subscribe(context.Background(), &ChangeSubscription{CollectionName: "coll", StartTs: 55}, nil)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("masked", func(t *testing.T) {
+		paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+		defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+		policy := globalMaskingPolicy
+		defer func() { globalMaskingPolicy = policy }()
+		globalMaskingPolicy = newMaskingPolicy()
+		assert.NoError(t, globalMaskingPolicy.Set(&MaskingRule{Role: "analyst", FieldName: "email", Mode: MaskingModeRedact}))
+
+		msg := newChangeStreamInsertMsg(1, 10, "dml_0_1v0", 100)
+		msg.FieldsData = append(msg.FieldsData, &schemapb.FieldData{FieldName: "email", Type: schemapb.DataType_VarChar, Field: &schemapb.FieldData_Scalars{
+			Scalars: &schemapb.ScalarField{Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"a@b.com", "c@d.com"}}}},
+		}})
+		s := newStreamer(t, &msgstream.MsgPack{Msgs: []msgstream.TsMsg{msg}})
+		stop := errors.New("stop")
+		var event *ChangeEvent
+		err := s.subscribe(GetContext(context.Background(), "alice:pwd"), &ChangeSubscription{CollectionName: "coll"}, func(e *ChangeEvent) error {
+			event = e
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, []string{maskedValue, maskedValue}, event.FieldsData[1].GetScalars().GetStringData().GetData())
+	})
+
+	t.Run("collection not found", func(t *testing.T) {
+		s := newStreamer(t)
+		err := s.subscribe(context.Background(), &ChangeSubscription{CollectionName: "unknown"}, nil)
+		assert.ErrorIs(t, err, merr.ErrCollectionNotFound)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		paramtable.Get().Save(Params.ProxyCfg.ChangeStreamMaxSubscriptions.Key, "1")
+		defer paramtable.Get().Reset(Params.ProxyCfg.ChangeStreamMaxSubscriptions.Key)
+		s := newStreamer(t)
+		assert.NoError(t, s.acquire())
+		assert.ErrorIs(t, s.acquire(), merr.ErrServiceQuotaExceeded)
+		s.release()
+		assert.NoError(t, s.acquire())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		paramtable.Get().Save(Params.ProxyCfg.ChangeStreamEnabled.Key, "false")
+		defer paramtable.Get().Save(Params.ProxyCfg.ChangeStreamEnabled.Key, "true")
+		s := newStreamer(t)
+		assert.ErrorIs(t, s.acquire(), merr.ErrServiceUnavailable)
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		s := &changeStreamer{}
+		assert.ErrorIs(t, s.acquire(), merr.ErrServiceNotReady)
+	})
+}
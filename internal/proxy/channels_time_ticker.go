@@ -18,12 +18,16 @@ package proxy
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
@@ -42,19 +46,98 @@ type channelsTimeTicker interface {
 	getMinTsStatistics() (map[pChan]Timestamp, Timestamp, error)
 	// getMinTick returns the minimum last write timestamp between all pchans.
 	getMinTick() Timestamp
+	// getPChanLags returns the lag between the latest timestamp and the last write timestamp of the pchans.
+	getPChanLags() map[pChan]time.Duration
 }
 
 // make sure channelsTimeTickerImpl implements channelsTimeTicker.
 var _ channelsTimeTicker = (*channelsTimeTickerImpl)(nil)
 
+// channelsTimeTickerShard keeps the timestamp statistics of the pchans hashed to it,
+// so that the readers of different pchans don't contend on the same lock.
+type channelsTimeTickerShard struct {
+	mtx             sync.RWMutex
+	minTsStatistics map[pChan]Timestamp // pchan -> min Timestamp
+	currents        map[pChan]Timestamp
+	lags            map[pChan]time.Duration
+}
+
+func newChannelsTimeTickerShard() *channelsTimeTickerShard {
+	return &channelsTimeTickerShard{
+		minTsStatistics: make(map[pChan]Timestamp),
+		currents:        make(map[pChan]Timestamp),
+		lags:            make(map[pChan]time.Duration),
+	}
+}
+
+// tick updates the statistics of the pchans in the shard, and returns the min timestamp of them,
+// now is returned if the shard has no pchan.
+func (shard *channelsTimeTickerShard) tick(now Timestamp, stats map[pChan]*pChanStatistics, interval Timestamp) Timestamp {
+	shard.mtx.Lock()
+	defer shard.mtx.Unlock()
+
+	minTs := now
+	for pchan := range shard.currents {
+		current := shard.currents[pchan]
+		stat, ok := stats[pchan]
+
+		if !ok {
+			delete(shard.minTsStatistics, pchan)
+			delete(shard.currents, pchan)
+		} else {
+			if stat.minTs > current {
+				shard.minTsStatistics[pchan] = stat.minTs - 1
+				next := now + interval
+				if next > stat.maxTs {
+					next = stat.maxTs
+				}
+				shard.currents[pchan] = next
+			}
+			lastMin := shard.minTsStatistics[pchan]
+			if minTs > lastMin {
+				minTs = lastMin
+			}
+		}
+	}
+
+	for pchan, value := range stats {
+		if value.minTs == typeutil.ZeroTimestamp {
+			log.Warn("channelsTimeTickerImpl.tick, stats contains physical channel which min ts is zero ",
+				zap.String("pchan", pchan))
+			continue
+		}
+		_, ok := shard.currents[pchan]
+		if !ok {
+			shard.minTsStatistics[pchan] = value.minTs - 1
+			shard.currents[pchan] = now
+		}
+		if minTs > value.minTs-1 {
+			minTs = value.minTs - 1
+		}
+	}
+
+	nodeID := paramtable.GetStringNodeID()
+	for pchan := range shard.lags {
+		if _, ok := shard.minTsStatistics[pchan]; !ok {
+			delete(shard.lags, pchan)
+			metrics.ProxyPChannelTimeTickLag.DeleteLabelValues(nodeID, pchan)
+		}
+	}
+	for pchan, ts := range shard.minTsStatistics {
+		lag := time.Duration(tsoutil.CalculateDuration(now, ts)) * time.Millisecond
+		shard.lags[pchan] = lag
+		metrics.ProxyPChannelTimeTickLag.WithLabelValues(nodeID, pchan).Set(float64(lag.Milliseconds()))
+	}
+	return minTs
+}
+
 // channelsTimeTickerImpl implements channelsTimeTicker.
 type channelsTimeTickerImpl struct {
-	interval          time.Duration       // interval to synchronize
-	minTsStatistics   map[pChan]Timestamp // pchan -> min Timestamp
-	statisticsMtx     sync.RWMutex
+	interval          time.Duration // interval to synchronize
+	shards            []*channelsTimeTickerShard
+	statisticsMtx     sync.RWMutex // protects defaultTimestamp and minTimestamp
 	getStatisticsFunc getPChanStatisticsFuncType
 	tso               tsoAllocator
-	currents          map[pChan]Timestamp
 	wg                sync.WaitGroup
 	ctx               context.Context
 	cancel            context.CancelFunc
@@ -62,31 +145,54 @@ type channelsTimeTickerImpl struct {
 	minTimestamp      Timestamp
 }
 
-func (ticker *channelsTimeTickerImpl) getMinTsStatistics() (map[pChan]Timestamp, Timestamp, error) {
+func (ticker *channelsTimeTickerImpl) shardIndex(pchan pChan) int {
+	return int(typeutil.HashString2Uint32(pchan) % uint32(len(ticker.shards)))
+}
+
+func (ticker *channelsTimeTickerImpl) getShard(pchan pChan) *channelsTimeTickerShard {
+	return ticker.shards[ticker.shardIndex(pchan)]
+}
+
+// getDefaultTimestamp must be called before reading the shards, the default timestamp is updated after
+// the shards, so it's never newer than the statistics read later.
+func (ticker *channelsTimeTickerImpl) getDefaultTimestamp() Timestamp {
 	ticker.statisticsMtx.RLock()
 	defer ticker.statisticsMtx.RUnlock()
+	return ticker.defaultTimestamp
+}
 
+func (ticker *channelsTimeTickerImpl) getMinTsStatistics() (map[pChan]Timestamp, Timestamp, error) {
+	defaultTimestamp := ticker.getDefaultTimestamp()
 	ret := make(map[pChan]Timestamp)
-	for k, v := range ticker.minTsStatistics {
-		if v > 0 {
-			ret[k] = v
+	for _, shard := range ticker.shards {
+		shard.mtx.RLock()
+		for k, v := range shard.minTsStatistics {
+			if v > 0 {
+				ret[k] = v
+			}
 		}
+		shard.mtx.RUnlock()
 	}
-	return ret, ticker.defaultTimestamp, nil
+	return ret, defaultTimestamp, nil
 }
 
 func (ticker *channelsTimeTickerImpl) initStatistics() {
-	ticker.statisticsMtx.Lock()
-	defer ticker.statisticsMtx.Unlock()
-
-	for pchan := range ticker.minTsStatistics {
-		ticker.minTsStatistics[pchan] = 0
+	for _, shard := range ticker.shards {
+		shard.mtx.Lock()
+		for pchan := range shard.minTsStatistics {
+			shard.minTsStatistics[pchan] = 0
+		}
+		shard.mtx.Unlock()
 	}
 }
 
 func (ticker *channelsTimeTickerImpl) initCurrents(current Timestamp) {
-	for pchan := range ticker.currents {
-		ticker.currents[pchan] = current
+	for _, shard := range ticker.shards {
+		shard.mtx.Lock()
+		for pchan := range shard.currents {
+			shard.currents[pchan] = current
+		}
+		shard.mtx.Unlock()
 	}
 }
 
@@ -103,50 +209,30 @@ func (ticker *channelsTimeTickerImpl) tick() error {
 		return nil
 	}
 
-	ticker.statisticsMtx.Lock()
-	defer ticker.statisticsMtx.Unlock()
+	shardStats := make([]map[pChan]*pChanStatistics, len(ticker.shards))
+	for i := range shardStats {
+		shardStats[i] = make(map[pChan]*pChanStatistics)
+	}
+	for pchan, stat := range stats {
+		shardStats[ticker.shardIndex(pchan)][pchan] = stat
+	}
 
-	ticker.defaultTimestamp = now
+	nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	interval := Timestamp(Params.ProxyCfg.TimeTickInterval.GetAsDuration(time.Millisecond))
 	minTs := now
-
-	for pchan := range ticker.currents {
-		current := ticker.currents[pchan]
-		stat, ok := stats[pchan]
-
-		if !ok {
-			delete(ticker.minTsStatistics, pchan)
-			delete(ticker.currents, pchan)
-		} else {
-			if stat.minTs > current {
-				ticker.minTsStatistics[pchan] = stat.minTs - 1
-				next := now + Timestamp(Params.ProxyCfg.TimeTickInterval.GetAsDuration(time.Millisecond))
-				if next > stat.maxTs {
-					next = stat.maxTs
-				}
-				ticker.currents[pchan] = next
-			}
-			lastMin := ticker.minTsStatistics[pchan]
-			if minTs > lastMin {
-				minTs = lastMin
-			}
+	for i, shard := range ticker.shards {
+		shardMinTs := shard.tick(now, shardStats[i], interval)
+		if minTs > shardMinTs {
+			minTs = shardMinTs
 		}
+		metrics.ProxyTimeTickLag.WithLabelValues(nodeID, strconv.Itoa(i)).Set(float64(tsoutil.CalculateDuration(now, shardMinTs)))
 	}
 
-	for pchan, value := range stats {
-		if value.minTs == typeutil.ZeroTimestamp {
-			log.Warn("channelsTimeTickerImpl.tick, stats contains physical channel which min ts is zero ",
-				zap.String("pchan", pchan))
-			continue
-		}
-		_, ok := ticker.currents[pchan]
-		if !ok {
-			ticker.minTsStatistics[pchan] = value.minTs - 1
-			ticker.currents[pchan] = now
-		}
-		if minTs > value.minTs-1 {
-			minTs = value.minTs - 1
-		}
-	}
+	// update the default timestamp after all the shards, otherwise the new pchans not added to
+	// their shards yet may get the default timestamp which is greater than their pending tasks.
+	ticker.statisticsMtx.Lock()
+	defer ticker.statisticsMtx.Unlock()
+	ticker.defaultTimestamp = now
 	ticker.minTimestamp = minTs
 
 	return nil
@@ -193,17 +279,31 @@ func (ticker *channelsTimeTickerImpl) close() error {
 }
 
 func (ticker *channelsTimeTickerImpl) getLastTick(pchan pChan) (Timestamp, error) {
-	ticker.statisticsMtx.RLock()
-	defer ticker.statisticsMtx.RUnlock()
+	defaultTimestamp := ticker.getDefaultTimestamp()
+	shard := ticker.getShard(pchan)
+	shard.mtx.RLock()
+	defer shard.mtx.RUnlock()
 
-	ts, ok := ticker.minTsStatistics[pchan]
+	ts, ok := shard.minTsStatistics[pchan]
 	if !ok {
-		return ticker.defaultTimestamp, nil
+		return defaultTimestamp, nil
 	}
 
 	return ts, nil
 }
 
+func (ticker *channelsTimeTickerImpl) getPChanLags() map[pChan]time.Duration {
+	ret := make(map[pChan]time.Duration)
+	for _, shard := range ticker.shards {
+		shard.mtx.RLock()
+		for pchan, lag := range shard.lags {
+			ret[pchan] = lag
+		}
+		shard.mtx.RUnlock()
+	}
+	return ret
+}
+
 func (ticker *channelsTimeTickerImpl) getMinTick() Timestamp {
 	ticker.statisticsMtx.RLock()
 	defer ticker.statisticsMtx.RUnlock()
@@ -221,19 +321,26 @@ func newChannelsTimeTicker(
 ) *channelsTimeTickerImpl {
 	ctx1, cancel := context.WithCancel(ctx)
 
+	shardNum := Params.ProxyCfg.TimeTickShardNum.GetAsInt()
+	if shardNum <= 0 {
+		shardNum = 1
+	}
 	ticker := &channelsTimeTickerImpl{
 		interval:          interval,
-		minTsStatistics:   make(map[pChan]Timestamp),
+		shards:            make([]*channelsTimeTickerShard, shardNum),
 		getStatisticsFunc: getStatisticsFunc,
 		tso:               tso,
-		currents:          make(map[pChan]Timestamp),
 		ctx:               ctx1,
 		cancel:            cancel,
 	}
+	for i := range ticker.shards {
+		ticker.shards[i] = newChannelsTimeTickerShard()
+	}
 
 	for _, pchan := range pchans {
-		ticker.minTsStatistics[pchan] = 0
-		ticker.currents[pchan] = 0
+		shard := ticker.getShard(pchan)
+		shard.minTsStatistics[pchan] = 0
+		shard.currents[pchan] = 0
 	}
 
 	return ticker
@@ -18,6 +18,7 @@ package proxy
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"sync"
 	"testing"
@@ -28,6 +29,8 @@ import (
 
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
@@ -236,3 +239,73 @@ func TestChannelsTimeTickerImpl_getMinTick(t *testing.T) {
 
 	time.Sleep(100 * time.Millisecond)
 }
+
+func TestChannelsTimeTickerImpl_shards(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(Params.ProxyCfg.TimeTickShardNum.Key, "4")
+	defer paramtable.Get().Reset(Params.ProxyCfg.TimeTickShardNum.Key)
+
+	pchans := make([]pChan, 0, 100)
+	for i := 0; i < 100; i++ {
+		pchans = append(pchans, fmt.Sprintf("by-dev-rootcoord-dml_%d", i))
+	}
+	stats := make(map[pChan]*pChanStatistics)
+	for i, pchan := range pchans {
+		stats[pchan] = &pChanStatistics{minTs: Timestamp(100 + i), maxTs: Timestamp(1000 + i)}
+	}
+	getStatisticsFunc := func() (map[pChan]*pChanStatistics, error) {
+		return stats, nil
+	}
+
+	channelTicker := newChannelsTimeTicker(context.Background(), time.Hour, nil, getStatisticsFunc, newMockTsoAllocator())
+	assert.Len(t, channelTicker.shards, 4)
+	assert.NoError(t, channelTicker.tick())
+
+	// every shard has some of the pchans
+	for _, shard := range channelTicker.shards {
+		assert.NotEmpty(t, shard.minTsStatistics)
+	}
+	minTsStatistics, defaultTs, err := channelTicker.getMinTsStatistics()
+	assert.NoError(t, err)
+	assert.Len(t, minTsStatistics, len(pchans))
+	for i, pchan := range pchans {
+		assert.Equal(t, Timestamp(100+i-1), minTsStatistics[pchan])
+		ts, err := channelTicker.getLastTick(pchan)
+		assert.NoError(t, err)
+		assert.Equal(t, Timestamp(100+i-1), ts)
+	}
+	assert.Equal(t, Timestamp(99), channelTicker.getMinTick())
+
+	// the pchans without statistics are removed
+	delete(stats, pchans[0])
+	assert.NoError(t, channelTicker.tick())
+	ts, err := channelTicker.getLastTick(pchans[0])
+	assert.NoError(t, err)
+	assert.Greater(t, ts, defaultTs)
+	assert.Equal(t, Timestamp(100), channelTicker.getMinTick())
+}
+
+func TestChannelsTimeTickerImpl_getPChanLags(t *testing.T) {
+	paramtable.Init()
+	now := time.Now()
+	stats := map[pChan]*pChanStatistics{
+		"p1": {minTs: tsoutil.ComposeTSByTime(now.Add(-time.Minute), 0), maxTs: tsoutil.ComposeTSByTime(now, 0)},
+		"p2": {minTs: tsoutil.ComposeTSByTime(now, 0), maxTs: tsoutil.ComposeTSByTime(now, 0)},
+	}
+	getStatisticsFunc := func() (map[pChan]*pChanStatistics, error) {
+		return stats, nil
+	}
+
+	channelTicker := newChannelsTimeTicker(context.Background(), time.Hour, nil, getStatisticsFunc, newMockTsoAllocator())
+	assert.NoError(t, channelTicker.tick())
+	lags := channelTicker.getPChanLags()
+	assert.Len(t, lags, 2)
+	assert.GreaterOrEqual(t, lags["p1"], time.Minute)
+	assert.Less(t, lags["p2"], time.Minute)
+
+	delete(stats, "p1")
+	assert.NoError(t, channelTicker.tick())
+	lags = channelTicker.getPChanLags()
+	assert.Len(t, lags, 1)
+	assert.Contains(t, lags, "p2")
+}
@@ -0,0 +1,214 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/pkg/eventlog"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+type breakerState int32
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreaker trips to open when the downstream error rate of the collection in the window is too high,
+// the requests are failed fast in the cooldown, then a single probe request decides whether to close it.
+type circuitBreaker struct {
+	mu          sync.Mutex
+	state       breakerState
+	windowStart time.Time
+	total       int
+	failed      int
+	openUntil   time.Time
+	probing     bool
+}
+
+// allow returns the remaining cooldown if the request should be failed fast.
+func (b *circuitBreaker) allow(now time.Time) (time.Duration, bool, breakerState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if now.Before(b.openUntil) {
+			return b.openUntil.Sub(now), false, b.state
+		}
+		b.state, b.probing = breakerHalfOpen, true
+		return 0, true, b.state
+	case breakerHalfOpen:
+		if b.probing {
+			return 0, false, b.state
+		}
+		b.probing = true
+	}
+	return 0, true, b.state
+}
+
+// record counts the result of the request, returns the new state and whether it is changed.
+func (b *circuitBreaker) record(now time.Time, failure bool) (breakerState, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerHalfOpen:
+		b.probing = false
+		if failure {
+			b.trip(now)
+		} else {
+			b.state = breakerClosed
+			b.resetWindow(now)
+		}
+		return b.state, true
+	case breakerClosed:
+		if now.Sub(b.windowStart) > Params.ProxyCfg.CircuitBreakerWindow.GetAsDuration(time.Second) {
+			b.resetWindow(now)
+		}
+		b.total++
+		if failure {
+			b.failed++
+		}
+		if b.total >= Params.ProxyCfg.CircuitBreakerMinRequests.GetAsInt() &&
+			float64(b.failed)/float64(b.total) >= Params.ProxyCfg.CircuitBreakerErrorRateThreshold.GetAsFloat() {
+			b.trip(now)
+			return b.state, true
+		}
+	}
+	return b.state, false
+}
+
+func (b *circuitBreaker) trip(now time.Time) {
+	b.state = breakerOpen
+	b.openUntil = now.Add(Params.ProxyCfg.CircuitBreakerCooldown.GetAsDuration(time.Second))
+}
+
+func (b *circuitBreaker) resetWindow(now time.Time) {
+	b.windowStart, b.total, b.failed = now, 0, 0
+}
+
+// circuitBreakers holds the circuit breakers of the search and query requests by collection.
+type circuitBreakers struct {
+	breakers *typeutil.ConcurrentMap[string, *circuitBreaker]
+}
+
+var globalCircuitBreakers = newCircuitBreakers()
+
+func newCircuitBreakers() *circuitBreakers {
+	return &circuitBreakers{
+		breakers: typeutil.NewConcurrentMap[string, *circuitBreaker](),
+	}
+}
+
+func circuitBreakerKey(dbName, collectionName string) string {
+	return dbName + "/" + collectionName
+}
+
+// allow returns the error to fail the request fast if the breaker of the collection is open.
+func (c *circuitBreakers) allow(dbName, collectionName, msgType string) error {
+	if !Params.ProxyCfg.CircuitBreakerEnabled.GetAsBool() {
+		return nil
+	}
+	breaker, ok := c.breakers.Get(circuitBreakerKey(dbName, collectionName))
+	if !ok {
+		return nil
+	}
+	cooldown, allowed, state := breaker.allow(time.Now())
+	if allowed {
+		if state == breakerHalfOpen {
+			c.onStateChanged(dbName, collectionName, state)
+		}
+		return nil
+	}
+	metrics.ProxyCircuitBreakerRejectCount.WithLabelValues(paramtable.GetStringNodeID(), msgType, dbName, collectionName).Inc()
+	reason := fmt.Sprintf("too many downstream errors of collection %s", collectionName)
+	if state == breakerHalfOpen {
+		return merr.WrapErrServiceCircuitBreakerOpen(reason, "probing the recovery, please retry later")
+	}
+	return merr.WrapErrServiceCircuitBreakerOpen(reason, fmt.Sprintf("please retry after %s", cooldown.Round(time.Second)))
+}
+
+// record counts the result of the request to the breaker of the collection.
+func (c *circuitBreakers) record(dbName, collectionName string, err error) {
+	if !Params.ProxyCfg.CircuitBreakerEnabled.GetAsBool() {
+		return
+	}
+	failure := isDownstreamFailure(err)
+	key := circuitBreakerKey(dbName, collectionName)
+	breaker, ok := c.breakers.Get(key)
+	if !ok {
+		if !failure {
+			return
+		}
+		breaker, _ = c.breakers.GetOrInsert(key, &circuitBreaker{windowStart: time.Now()})
+	}
+	if state, changed := breaker.record(time.Now(), failure); changed {
+		c.onStateChanged(dbName, collectionName, state)
+	}
+}
+
+func (c *circuitBreakers) onStateChanged(dbName, collectionName string, state breakerState) {
+	msg := fmt.Sprintf("circuit breaker of collection %s/%s is %s", dbName, collectionName, state)
+	log.Info(msg)
+	eventlog.Record(eventlog.NewRawEvt(eventlog.Level_Info, msg))
+	metrics.ProxyCircuitBreakerState.WithLabelValues(paramtable.GetStringNodeID(), dbName, collectionName).Set(float64(state))
+}
+
+// isDownstreamFailure returns whether the error is caused by the unavailable downstream nodes,
+// other than the invalid requests.
+func isDownstreamFailure(err error) bool {
+	if err == nil || errors.Is(err, merr.ErrServiceCircuitBreakerOpen) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || merr.Code(err) == merr.TimeoutCode {
+		return true
+	}
+	return errors.IsAny(err,
+		merr.ErrServiceNotReady,
+		merr.ErrServiceUnavailable,
+		merr.ErrServiceInternal,
+		merr.ErrChannelLack,
+		merr.ErrChannelNotAvailable,
+		merr.ErrReplicaNotAvailable,
+		merr.ErrNodeNotFound,
+		merr.ErrNodeOffline,
+		merr.ErrNodeNotAvailable,
+		merr.ErrIoFailed,
+	)
+}
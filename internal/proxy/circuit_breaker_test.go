@@ -0,0 +1,112 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(Params.ProxyCfg.CircuitBreakerMinRequests.Key, "4")
+	params.Save(Params.ProxyCfg.CircuitBreakerErrorRateThreshold.Key, "0.5")
+	defer params.Reset(Params.ProxyCfg.CircuitBreakerMinRequests.Key)
+	defer params.Reset(Params.ProxyCfg.CircuitBreakerErrorRateThreshold.Key)
+
+	now := time.Now()
+	b := &circuitBreaker{windowStart: now}
+	_, allowed, _ := b.allow(now)
+	assert.True(t, allowed)
+
+	b.record(now, false)
+	b.record(now, true)
+	_, changed := b.record(now, false)
+	assert.False(t, changed)
+	// 2 of 4 requests failed
+	state, changed := b.record(now, true)
+	assert.True(t, changed)
+	assert.Equal(t, breakerOpen, state)
+
+	cooldown, allowed, _ := b.allow(now.Add(time.Second))
+	assert.False(t, allowed)
+	assert.Greater(t, cooldown, time.Duration(0))
+
+	// only one probe is allowed after cooldown
+	after := b.openUntil.Add(time.Millisecond)
+	_, allowed, state = b.allow(after)
+	assert.True(t, allowed)
+	assert.Equal(t, breakerHalfOpen, state)
+	_, allowed, _ = b.allow(after)
+	assert.False(t, allowed)
+
+	// probe failed, open again
+	state, _ = b.record(after, true)
+	assert.Equal(t, breakerOpen, state)
+
+	// probe succeeded, closed
+	after = b.openUntil.Add(time.Millisecond)
+	_, allowed, _ = b.allow(after)
+	assert.True(t, allowed)
+	state, _ = b.record(after, false)
+	assert.Equal(t, breakerClosed, state)
+	assert.Equal(t, 0, b.total)
+}
+
+func TestCircuitBreakers(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(Params.ProxyCfg.CircuitBreakerMinRequests.Key, "2")
+	defer params.Reset(Params.ProxyCfg.CircuitBreakerMinRequests.Key)
+
+	breakers := newCircuitBreakers()
+	failure := merr.WrapErrServiceUnavailable("querynode down")
+
+	// disabled by default
+	breakers.record("db", "coll", failure)
+	breakers.record("db", "coll", failure)
+	assert.NoError(t, breakers.allow("db", "coll", "search"))
+
+	params.Save(Params.ProxyCfg.CircuitBreakerEnabled.Key, "true")
+	defer params.Reset(Params.ProxyCfg.CircuitBreakerEnabled.Key)
+
+	// invalid requests are not counted
+	breakers.record("db", "coll", merr.WrapErrParameterInvalidMsg("invalid expr"))
+	breakers.record("db", "coll", merr.WrapErrParameterInvalidMsg("invalid expr"))
+	assert.NoError(t, breakers.allow("db", "coll", "search"))
+
+	breakers.record("db", "coll", failure)
+	breakers.record("db", "coll", failure)
+	err := breakers.allow("db", "coll", "search")
+	assert.ErrorIs(t, err, merr.ErrServiceCircuitBreakerOpen)
+	assert.NoError(t, breakers.allow("db", "other", "search"))
+}
+
+func TestIsDownstreamFailure(t *testing.T) {
+	assert.False(t, isDownstreamFailure(nil))
+	assert.False(t, isDownstreamFailure(merr.WrapErrParameterInvalidMsg("invalid")))
+	assert.False(t, isDownstreamFailure(merr.WrapErrServiceCircuitBreakerOpen("open")))
+	assert.True(t, isDownstreamFailure(merr.Error(merr.Status(merr.WrapErrServiceUnavailable("down")))))
+	assert.True(t, isDownstreamFailure(context.DeadlineExceeded))
+}
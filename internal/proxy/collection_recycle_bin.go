@@ -0,0 +1,337 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	// recycleBinPrefix is the etcd prefix(under meta root path) of the dropped collections in recycle bin.
+	recycleBinPrefix = "proxy/recycle-bin"
+	// recycledCollectionPrefix is the name prefix of the collections in recycle bin, which are hidden from users.
+	recycledCollectionPrefix = "__recycled_"
+
+	recycleBinGCInterval = time.Minute
+)
+
+// DroppedCollection is a collection dropped into recycle bin, which could be restored before expiration.
+type DroppedCollection struct {
+	DBName         string `json:"db_name"`
+	CollectionName string `json:"collection_name"`
+	CollectionID   int64  `json:"collection_id"`
+	RecycledName   string `json:"recycled_name"`
+	DropTime       int64  `json:"drop_time"`
+	ExpireTime     int64  `json:"expire_time"`
+}
+
+func (c *DroppedCollection) key() string {
+	return path.Join(recycleBinPrefix, c.DBName, strconv.FormatInt(c.CollectionID, 10))
+}
+
+func isRecycledCollection(name string) bool {
+	return strings.HasPrefix(name, recycledCollectionPrefix)
+}
+
+// checkNotRecycledCollection rejects the name of the collections in recycle bin, which are only accessible through
+// the recycle bin.
+func checkNotRecycledCollection(name string) error {
+	if isRecycledCollection(name) {
+		return merr.WrapErrParameterInvalidMsg("the collection name prefix %s is reserved for the collections in recycle bin, got %s",
+			recycledCollectionPrefix, name)
+	}
+	return nil
+}
+
+// collectionRecycleBin renames the dropped collection to a hidden name instead of dropping it,
+// the collection is dropped permanently after the retention.
+type collectionRecycleBin struct {
+	kv         kv.BaseKV
+	rootCoord  types.RootCoordClient
+	queryCoord types.QueryCoordClient
+}
+
+var globalCollectionRecycleBin = &collectionRecycleBin{}
+
+func newCollectionRecycleBin(baseKV kv.BaseKV, rootCoord types.RootCoordClient, queryCoord types.QueryCoordClient) *collectionRecycleBin {
+	return &collectionRecycleBin{
+		kv:         baseKV,
+		rootCoord:  rootCoord,
+		queryCoord: queryCoord,
+	}
+}
+
+// Enabled returns whether DropCollection should move the collection into recycle bin.
+func (b *collectionRecycleBin) Enabled() bool {
+	return b.kv != nil && Params.ProxyCfg.CollectionRecycleBinEnabled.GetAsBool()
+}
+
+// start starts the loop which drops the expired collections permanently.
+func (b *collectionRecycleBin) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(recycleBinGCInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("collection recycle bin gc loop exit")
+				return
+			case <-ticker.C:
+				b.gc(ctx)
+			}
+		}
+	}()
+}
+
+// Recycle releases the collection and renames it to a hidden name.
+func (b *collectionRecycleBin) Recycle(ctx context.Context, dbName, collectionName string) error {
+	collectionID, err := globalMetaCache.GetCollectionID(ctx, dbName, collectionName)
+	if err != nil {
+		return err
+	}
+
+	// keep the same semantic as DropCollection
+	aliasResp, err := b.rootCoord.ListAliases(ctx, &milvuspb.ListAliasesRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	if err := merr.CheckRPCCall(aliasResp, err); err != nil {
+		return err
+	}
+	if len(aliasResp.GetAliases()) > 0 {
+		return merr.WrapErrParameterInvalidMsg("unable to drop the collection [%s] because it has associated aliases %v, please remove all aliases before dropping the collection",
+			collectionName, aliasResp.GetAliases())
+	}
+
+	releaseResp, err := b.queryCoord.ReleaseCollection(ctx, &querypb.ReleaseCollectionRequest{
+		Base: commonpbutil.NewMsgBase(
+			commonpbutil.WithMsgType(commonpb.MsgType_ReleaseCollection),
+			commonpbutil.WithSourceID(paramtable.GetNodeID()),
+		),
+		CollectionID: collectionID,
+	})
+	if err := merr.CheckRPCCall(releaseResp, err); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	dropped := &DroppedCollection{
+		DBName:         dbName,
+		CollectionName: collectionName,
+		CollectionID:   collectionID,
+		RecycledName:   fmt.Sprintf("%s%d", recycledCollectionPrefix, collectionID),
+		DropTime:       now.Unix(),
+		ExpireTime:     now.Add(Params.ProxyCfg.CollectionRecycleBinRetention.GetAsDuration(time.Second)).Unix(),
+	}
+	value, err := json.Marshal(dropped)
+	if err != nil {
+		return err
+	}
+	if err := b.kv.Save(dropped.key(), string(value)); err != nil {
+		return err
+	}
+
+	if err := b.rename(ctx, dbName, collectionName, dropped.RecycledName); err != nil {
+		if removeErr := b.kv.Remove(dropped.key()); removeErr != nil {
+			log.Ctx(ctx).Warn("failed to remove recycle bin entry", zap.String("key", dropped.key()), zap.Error(removeErr))
+		}
+		return err
+	}
+	// the old name is not reachable anymore, don't wait for the invalidation from rootcoord
+	globalMetaCache.RemoveCollection(ctx, dbName, collectionName)
+	log.Ctx(ctx).Info("collection moved into recycle bin", zap.Any("collection", dropped))
+	return nil
+}
+
+// List returns the dropped collections in the database, all databases if dbName is empty.
+func (b *collectionRecycleBin) List(dbName string) ([]*DroppedCollection, error) {
+	prefix := recycleBinPrefix
+	if dbName != "" {
+		prefix = path.Join(recycleBinPrefix, dbName) + "/"
+	}
+	_, values, err := b.kv.LoadWithPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	collections := make([]*DroppedCollection, 0, len(values))
+	for _, value := range values {
+		dropped := &DroppedCollection{}
+		if err := json.Unmarshal([]byte(value), dropped); err != nil {
+			log.Warn("skip invalid recycle bin entry", zap.String("value", value), zap.Error(err))
+			continue
+		}
+		collections = append(collections, dropped)
+	}
+	sort.Slice(collections, func(i, j int) bool {
+		return collections[i].DropTime > collections[j].DropTime
+	})
+	return collections, nil
+}
+
+// Restore renames the latest dropped collection back, newName is the original name if empty.
+func (b *collectionRecycleBin) Restore(ctx context.Context, dbName, collectionName string, newName string) (*DroppedCollection, error) {
+	collections, err := b.List(dbName)
+	if err != nil {
+		return nil, err
+	}
+	var target *DroppedCollection
+	for _, dropped := range collections {
+		if dropped.CollectionName == collectionName {
+			target = dropped
+			break
+		}
+	}
+	if target == nil {
+		return nil, merr.WrapErrCollectionNotFoundWithDB(dbName, collectionName, "collection not found in recycle bin")
+	}
+	if newName == "" {
+		newName = target.CollectionName
+	}
+	if err := validateCollectionName(newName); err != nil {
+		return nil, err
+	}
+
+	if err := b.rename(ctx, target.DBName, target.RecycledName, newName); err != nil {
+		return nil, err
+	}
+	if err := b.kv.Remove(target.key()); err != nil {
+		return nil, err
+	}
+	log.Ctx(ctx).Info("collection restored from recycle bin", zap.Any("collection", target), zap.String("newName", newName))
+	return target, nil
+}
+
+func (b *collectionRecycleBin) rename(ctx context.Context, dbName, oldName, newName string) error {
+	return merr.CheckRPCCall(b.rootCoord.RenameCollection(ctx, &milvuspb.RenameCollectionRequest{
+		Base: commonpbutil.NewMsgBase(
+			commonpbutil.WithMsgType(commonpb.MsgType_RenameCollection),
+			commonpbutil.WithSourceID(paramtable.GetNodeID()),
+		),
+		DbName:    dbName,
+		OldName:   oldName,
+		NewDBName: dbName,
+		NewName:   newName,
+	}))
+}
+
+// gc drops the expired collections permanently, the entries of the collections already dropped are removed.
+func (b *collectionRecycleBin) gc(ctx context.Context) {
+	collections, err := b.List("")
+	if err != nil {
+		log.Warn("failed to list recycle bin", zap.Error(err))
+		return
+	}
+	now := time.Now().Unix()
+	for _, dropped := range collections {
+		if dropped.ExpireTime > now {
+			continue
+		}
+		err := merr.CheckRPCCall(b.rootCoord.DropCollection(ctx, &milvuspb.DropCollectionRequest{
+			Base: commonpbutil.NewMsgBase(
+				commonpbutil.WithMsgType(commonpb.MsgType_DropCollection),
+				commonpbutil.WithSourceID(paramtable.GetNodeID()),
+			),
+			DbName:         dropped.DBName,
+			CollectionName: dropped.RecycledName,
+		}))
+		if err != nil && !errors.Is(err, merr.ErrCollectionNotFound) {
+			log.Warn("failed to drop expired collection in recycle bin", zap.Any("collection", dropped), zap.Error(err))
+			continue
+		}
+		if err := b.kv.Remove(dropped.key()); err != nil {
+			log.Warn("failed to remove recycle bin entry", zap.String("key", dropped.key()), zap.Error(err))
+			continue
+		}
+		log.Info("expired collection in recycle bin dropped", zap.Any("collection", dropped))
+	}
+}
+
+// filterRecycledCollections removes the collections in recycle bin from ShowCollections response.
+func filterRecycledCollections(resp *milvuspb.ShowCollectionsResponse) {
+	names := resp.GetCollectionNames()
+	keep := make([]int, 0, len(names))
+	for i, name := range names {
+		if !isRecycledCollection(name) {
+			keep = append(keep, i)
+		}
+	}
+	if len(keep) == len(names) {
+		return
+	}
+
+	filterInt64 := func(s []int64) []int64 {
+		if len(s) != len(names) {
+			return s
+		}
+		result := make([]int64, 0, len(keep))
+		for _, i := range keep {
+			result = append(result, s[i])
+		}
+		return result
+	}
+	filterUint64 := func(s []uint64) []uint64 {
+		if len(s) != len(names) {
+			return s
+		}
+		result := make([]uint64, 0, len(keep))
+		for _, i := range keep {
+			result = append(result, s[i])
+		}
+		return result
+	}
+	filterBool := func(s []bool) []bool {
+		if len(s) != len(names) {
+			return s
+		}
+		result := make([]bool, 0, len(keep))
+		for _, i := range keep {
+			result = append(result, s[i])
+		}
+		return result
+	}
+
+	collectionNames := make([]string, 0, len(keep))
+	for _, i := range keep {
+		collectionNames = append(collectionNames, names[i])
+	}
+	resp.CollectionIds = filterInt64(resp.CollectionIds)
+	resp.CreatedTimestamps = filterUint64(resp.CreatedTimestamps)
+	resp.CreatedUtcTimestamps = filterUint64(resp.CreatedUtcTimestamps)
+	resp.InMemoryPercentages = filterInt64(resp.InMemoryPercentages)
+	resp.QueryServiceAvailable = filterBool(resp.QueryServiceAvailable)
+	resp.CollectionNames = collectionNames
+}
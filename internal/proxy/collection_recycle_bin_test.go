@@ -0,0 +1,132 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	kvmocks "github.com/milvus-io/milvus/internal/kv/mocks"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestCollectionRecycleBin(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	mockCache := NewMockCache(t)
+	globalMetaCache = mockCache
+
+	t.Run("recycle", func(t *testing.T) {
+		watchKV := kvmocks.NewWatchKV(t)
+		rootCoord := mocks.NewMockRootCoordClient(t)
+		queryCoord := mocks.NewMockQueryCoordClient(t)
+		bin := newCollectionRecycleBin(watchKV, rootCoord, queryCoord)
+
+		assert.False(t, bin.Enabled())
+		paramtable.Get().Save(Params.ProxyCfg.CollectionRecycleBinEnabled.Key, "true")
+		defer paramtable.Get().Reset(Params.ProxyCfg.CollectionRecycleBinEnabled.Key)
+		assert.True(t, bin.Enabled())
+
+		mockCache.EXPECT().GetCollectionID(mock.Anything, "db1", "c1").Return(100, nil).Once()
+		rootCoord.EXPECT().ListAliases(mock.Anything, mock.Anything).Return(&milvuspb.ListAliasesResponse{Status: merr.Success()}, nil).Once()
+		queryCoord.EXPECT().ReleaseCollection(mock.Anything, mock.Anything).Return(merr.Success(), nil).Once()
+		watchKV.EXPECT().Save("proxy/recycle-bin/db1/100", mock.Anything).Return(nil).Once()
+		rootCoord.EXPECT().RenameCollection(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *milvuspb.RenameCollectionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+			assert.Equal(t, "c1", req.GetOldName())
+			assert.Equal(t, "__recycled_100", req.GetNewName())
+			return merr.Success(), nil
+		}).Once()
+		mockCache.EXPECT().RemoveCollection(mock.Anything, "db1", "c1").Return().Once()
+		assert.NoError(t, bin.Recycle(ctx, "db1", "c1"))
+
+		// collection with aliases can't be dropped
+		mockCache.EXPECT().GetCollectionID(mock.Anything, "db1", "c2").Return(101, nil).Once()
+		rootCoord.EXPECT().ListAliases(mock.Anything, mock.Anything).Return(&milvuspb.ListAliasesResponse{Status: merr.Success(), Aliases: []string{"a1"}}, nil).Once()
+		assert.Error(t, bin.Recycle(ctx, "db1", "c2"))
+	})
+
+	t.Run("restore and gc", func(t *testing.T) {
+		watchKV := kvmocks.NewWatchKV(t)
+		rootCoord := mocks.NewMockRootCoordClient(t)
+		bin := newCollectionRecycleBin(watchKV, rootCoord, nil)
+
+		now := time.Now()
+		expired, _ := json.Marshal(&DroppedCollection{DBName: "db1", CollectionName: "c1", CollectionID: 100, RecycledName: "__recycled_100", DropTime: now.Unix() - 10, ExpireTime: now.Unix() - 1})
+		alive, _ := json.Marshal(&DroppedCollection{DBName: "db1", CollectionName: "c2", CollectionID: 101, RecycledName: "__recycled_101", DropTime: now.Unix(), ExpireTime: now.Unix() + 3600})
+		watchKV.EXPECT().LoadWithPrefix(mock.Anything).Return([]string{"k1", "k2"}, []string{string(expired), string(alive)}, nil)
+
+		collections, err := bin.List("db1")
+		assert.NoError(t, err)
+		assert.Len(t, collections, 2)
+		assert.Equal(t, "c2", collections[0].CollectionName)
+
+		_, err = bin.Restore(ctx, "db1", "c3", "")
+		assert.Error(t, err)
+
+		rootCoord.EXPECT().RenameCollection(mock.Anything, mock.Anything).Return(merr.Success(), nil).Once()
+		watchKV.EXPECT().Remove("proxy/recycle-bin/db1/101").Return(nil).Once()
+		restored, err := bin.Restore(ctx, "db1", "c2", "")
+		assert.NoError(t, err)
+		assert.EqualValues(t, 101, restored.CollectionID)
+
+		rootCoord.EXPECT().DropCollection(mock.Anything, mock.Anything).Return(merr.Success(), nil).Once()
+		watchKV.EXPECT().Remove("proxy/recycle-bin/db1/100").Return(nil).Once()
+		bin.gc(ctx)
+
+		// the entry is kept if failed to drop
+		rootCoord.EXPECT().DropCollection(mock.Anything, mock.Anything).Return(merr.Status(merr.ErrServiceNotReady), nil).Once()
+		bin.gc(ctx)
+
+		// the collection is already dropped, the entry is removed
+		rootCoord.EXPECT().DropCollection(mock.Anything, mock.Anything).Return(merr.Status(merr.WrapErrCollectionNotFound("__recycled_100")), nil).Once()
+		watchKV.EXPECT().Remove("proxy/recycle-bin/db1/100").Return(nil).Once()
+		bin.gc(ctx)
+	})
+}
+
+func TestCheckNotRecycledCollection(t *testing.T) {
+	assert.NoError(t, checkNotRecycledCollection("c1"))
+	assert.ErrorIs(t, checkNotRecycledCollection("__recycled_100"), merr.ErrParameterInvalid)
+	assert.Error(t, validateCollectionName("__recycled_100"))
+}
+
+func TestFilterRecycledCollections(t *testing.T) {
+	resp := &milvuspb.ShowCollectionsResponse{
+		CollectionNames:      []string{"c1", "__recycled_100", "c2"},
+		CollectionIds:        []int64{1, 100, 2},
+		CreatedTimestamps:    []uint64{1, 2, 3},
+		CreatedUtcTimestamps: []uint64{1, 2, 3},
+	}
+	filterRecycledCollections(resp)
+	assert.Equal(t, []string{"c1", "c2"}, resp.GetCollectionNames())
+	assert.Equal(t, []int64{1, 2}, resp.GetCollectionIds())
+	assert.Equal(t, []uint64{1, 3}, resp.GetCreatedTimestamps())
+	assert.Empty(t, resp.GetInMemoryPercentages())
+}
@@ -0,0 +1,503 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// CollectionSpec is the desired state of a collection.
+type CollectionSpec struct {
+	DbName         string            `json:"db_name,omitempty"`
+	CollectionName string            `json:"collection_name"`
+	Schema         json.RawMessage   `json:"schema,omitempty"`
+	ShardsNum      int32             `json:"shards_num,omitempty"`
+	Indexes        []*IndexSpec      `json:"indexes,omitempty"`
+	ReplicaNumber  int32             `json:"replica_number,omitempty"`
+	Aliases        []string          `json:"aliases,omitempty"`
+	Properties     map[string]string `json:"properties,omitempty"`
+	// DryRun only computes the changes without executing them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+const (
+	specActionCreateCollection = "create_collection"
+	specActionSchemaConflict   = "schema_conflict"
+	specActionAlterProperties  = "alter_properties"
+	specActionCreateIndex      = "create_index"
+	specActionAlterIndex       = "alter_index"
+	specActionCreateAlias      = "create_alias"
+	specActionAlterAlias       = "alter_alias"
+	specActionLoad             = "load"
+	specActionUpdateReplicas   = "update_replicas"
+)
+
+// SpecChange is a change required to reconcile the collection to the spec.
+type SpecChange struct {
+	Action  string `json:"action"`
+	Target  string `json:"target"`
+	Detail  string `json:"detail,omitempty"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+
+	apply func(ctx context.Context) error
+}
+
+// SpecReport is the change report of applyCollectionSpec.
+type SpecReport struct {
+	CollectionName string        `json:"collection_name"`
+	DryRun         bool          `json:"dry_run"`
+	Changes        []*SpecChange `json:"changes"`
+}
+
+// applyCollectionSpec computes the difference between the spec and the current state of the collection,
+// then executes the changes in order. The execution stops at the first failed change.
+// Each request issued is checked against the privileges of the user in ctx, as if it came from the sdk.
+func (node *Proxy) applyCollectionSpec(ctx context.Context, spec *CollectionSpec) (*SpecReport, error) {
+	if spec.CollectionName == "" {
+		return nil, merr.WrapErrParameterMissing("collection_name")
+	}
+
+	changes, err := node.diffCollectionSpec(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SpecReport{
+		CollectionName: spec.CollectionName,
+		DryRun:         spec.DryRun,
+		Changes:        changes,
+	}
+	if spec.DryRun {
+		return report, nil
+	}
+	for _, change := range changes {
+		if err := change.apply(ctx); err != nil {
+			change.Error = err.Error()
+			log.Ctx(ctx).Warn("failed to apply collection spec", zap.String("collection", spec.CollectionName),
+				zap.String("action", change.Action), zap.String("target", change.Target), zap.Error(err))
+			break
+		}
+		change.Applied = true
+	}
+	return report, nil
+}
+
+func (node *Proxy) diffCollectionSpec(ctx context.Context, spec *CollectionSpec) ([]*SpecChange, error) {
+	hasReq := &milvuspb.HasCollectionRequest{
+		DbName:         spec.DbName,
+		CollectionName: spec.CollectionName,
+	}
+	if err := checkMgrPrivilege(ctx, hasReq); err != nil {
+		return nil, err
+	}
+	hasResp, err := node.HasCollection(ctx, hasReq)
+	if err := merr.CheckRPCCall(hasResp, err); err != nil {
+		return nil, err
+	}
+
+	changes := make([]*SpecChange, 0)
+	existIndexes := make(map[string]*milvuspb.IndexDescription)
+	aliases := make(map[string]struct{})
+	loaded := false
+	replicaNumber := 0
+
+	if !hasResp.GetValue() {
+		change, err := node.diffCreateCollection(spec)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	} else {
+		describeReq := &milvuspb.DescribeCollectionRequest{
+			DbName:         spec.DbName,
+			CollectionName: spec.CollectionName,
+		}
+		indexReq := &milvuspb.DescribeIndexRequest{
+			DbName:         spec.DbName,
+			CollectionName: spec.CollectionName,
+		}
+		aliasReq := &milvuspb.ListAliasesRequest{
+			DbName:         spec.DbName,
+			CollectionName: spec.CollectionName,
+		}
+		stateReq := &milvuspb.GetLoadStateRequest{
+			DbName:         spec.DbName,
+			CollectionName: spec.CollectionName,
+		}
+		if err := checkMgrPrivilege(ctx, describeReq, indexReq, aliasReq, stateReq); err != nil {
+			return nil, err
+		}
+
+		describeResp, err := node.DescribeCollection(ctx, describeReq)
+		if err := merr.CheckRPCCall(describeResp, err); err != nil {
+			return nil, err
+		}
+		change, err := node.diffSchema(spec, describeResp.GetSchema(), describeResp.GetShardsNum())
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, change)
+		}
+		if change := node.diffProperties(spec, describeResp.GetProperties()); change != nil {
+			changes = append(changes, change)
+		}
+
+		indexResp, err := node.DescribeIndex(ctx, indexReq)
+		if err := merr.CheckRPCCall(indexResp, err); err != nil && !errors.Is(err, merr.ErrIndexNotFound) {
+			return nil, err
+		}
+		for _, index := range indexResp.GetIndexDescriptions() {
+			existIndexes[index.GetIndexName()] = index
+			existIndexes[index.GetFieldName()] = index
+		}
+
+		aliasResp, err := node.ListAliases(ctx, aliasReq)
+		if err := merr.CheckRPCCall(aliasResp, err); err != nil {
+			return nil, err
+		}
+		for _, alias := range aliasResp.GetAliases() {
+			aliases[alias] = struct{}{}
+		}
+
+		stateResp, err := node.GetLoadState(ctx, stateReq)
+		if err := merr.CheckRPCCall(stateResp, err); err != nil {
+			return nil, err
+		}
+		loaded = stateResp.GetState() == commonpb.LoadState_LoadStateLoaded ||
+			stateResp.GetState() == commonpb.LoadState_LoadStateLoading
+		if loaded && spec.ReplicaNumber > 0 {
+			replicaReq := &milvuspb.GetReplicasRequest{
+				DbName:         spec.DbName,
+				CollectionName: spec.CollectionName,
+				CollectionID:   describeResp.GetCollectionID(),
+			}
+			if err := checkMgrPrivilege(ctx, replicaReq); err != nil {
+				return nil, err
+			}
+			replicaResp, err := node.GetReplicas(ctx, replicaReq)
+			if err := merr.CheckRPCCall(replicaResp, err); err != nil {
+				return nil, err
+			}
+			replicaNumber = len(replicaResp.GetReplicas())
+		}
+	}
+
+	for _, index := range spec.Indexes {
+		key := index.IndexName
+		if key == "" {
+			key = index.FieldName
+		}
+		if exist, ok := existIndexes[key]; ok {
+			if change := node.diffIndexParams(spec, index, exist); change != nil {
+				changes = append(changes, change)
+			}
+			continue
+		}
+		changes = append(changes, node.diffCreateIndex(spec, index))
+	}
+
+	for _, alias := range spec.Aliases {
+		if _, ok := aliases[alias]; ok {
+			continue
+		}
+		changes = append(changes, node.diffAlias(ctx, spec, alias))
+	}
+
+	loadReq := &milvuspb.LoadCollectionRequest{
+		DbName:         spec.DbName,
+		CollectionName: spec.CollectionName,
+		ReplicaNumber:  spec.ReplicaNumber,
+	}
+	if spec.ReplicaNumber > 0 && !loaded {
+		changes = append(changes, &SpecChange{
+			Action: specActionLoad,
+			Target: spec.CollectionName,
+			Detail: fmt.Sprintf("replica_number=%d", spec.ReplicaNumber),
+			apply: func(ctx context.Context) error {
+				if err := checkMgrPrivilege(ctx, loadReq); err != nil {
+					return err
+				}
+				return merr.CheckRPCCall(node.LoadCollection(ctx, loadReq))
+			},
+		})
+	} else if spec.ReplicaNumber > 0 && replicaNumber != int(spec.ReplicaNumber) {
+		// the replica number of a loaded collection can only be changed by loading it again
+		releaseReq := &milvuspb.ReleaseCollectionRequest{
+			DbName:         spec.DbName,
+			CollectionName: spec.CollectionName,
+		}
+		changes = append(changes, &SpecChange{
+			Action: specActionUpdateReplicas,
+			Target: spec.CollectionName,
+			Detail: fmt.Sprintf("replica_number=%d -> %d, the collection is released and loaded again", replicaNumber, spec.ReplicaNumber),
+			apply: func(ctx context.Context) error {
+				if err := checkMgrPrivilege(ctx, releaseReq, loadReq); err != nil {
+					return err
+				}
+				if err := merr.CheckRPCCall(node.ReleaseCollection(ctx, releaseReq)); err != nil {
+					return err
+				}
+				return merr.CheckRPCCall(node.LoadCollection(ctx, loadReq))
+			},
+		})
+	}
+	return changes, nil
+}
+
+func parseSpecSchema(spec *CollectionSpec) (*schemapb.CollectionSchema, error) {
+	schema := &schemapb.CollectionSchema{}
+	if err := jsonpb.UnmarshalString(string(spec.Schema), schema); err != nil {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid schema, %s", err.Error())
+	}
+	schema.Name = spec.CollectionName
+	return schema, nil
+}
+
+func (node *Proxy) diffCreateCollection(spec *CollectionSpec) (*SpecChange, error) {
+	if len(spec.Schema) == 0 {
+		return nil, merr.WrapErrParameterMissing("schema", "schema is required to create collection")
+	}
+	schema, err := parseSpecSchema(spec)
+	if err != nil {
+		return nil, err
+	}
+	schemaBytes, err := proto.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &milvuspb.CreateCollectionRequest{
+		DbName:         spec.DbName,
+		CollectionName: spec.CollectionName,
+		Schema:         schemaBytes,
+		ShardsNum:      spec.ShardsNum,
+		Properties:     specProperties(spec.Properties),
+	}
+	return &SpecChange{
+		Action: specActionCreateCollection,
+		Target: spec.CollectionName,
+		Detail: fmt.Sprintf("fields=%d shards_num=%d", len(schema.GetFields()), spec.ShardsNum),
+		apply: func(ctx context.Context) error {
+			if err := checkMgrPrivilege(ctx, req); err != nil {
+				return err
+			}
+			return merr.CheckRPCCall(node.CreateCollection(ctx, req))
+		},
+	}, nil
+}
+
+// diffSchema returns the conflict between the schema in spec and the existing one. The schema of an existing
+// collection can't be reconciled, so the change always fails and stops the execution before the others.
+func (node *Proxy) diffSchema(spec *CollectionSpec, existing *schemapb.CollectionSchema, shardsNum int32) (*SpecChange, error) {
+	diffs := make([]string, 0)
+	if len(spec.Schema) > 0 {
+		schema, err := parseSpecSchema(spec)
+		if err != nil {
+			return nil, err
+		}
+		diffs = diffCollectionSchema(existing, schema)
+	}
+	if spec.ShardsNum > 0 && spec.ShardsNum != shardsNum {
+		diffs = append(diffs, fmt.Sprintf("shards_num: existing %d, requested %d", shardsNum, spec.ShardsNum))
+	}
+	if len(diffs) == 0 {
+		return nil, nil
+	}
+
+	conflict := merr.WrapErrCollectionConflict(spec.CollectionName, diffs...)
+	return &SpecChange{
+		Action: specActionSchemaConflict,
+		Target: spec.CollectionName,
+		Detail: strings.Join(diffs, "; "),
+		apply: func(ctx context.Context) error {
+			return conflict
+		},
+	}, nil
+}
+
+func (node *Proxy) diffProperties(spec *CollectionSpec, current []*commonpb.KeyValuePair) *SpecChange {
+	currentProperties := make(map[string]string, len(current))
+	for _, kv := range current {
+		currentProperties[kv.GetKey()] = kv.GetValue()
+	}
+	changed := make(map[string]string)
+	for key, value := range spec.Properties {
+		if old, ok := currentProperties[key]; !ok || old != value {
+			changed[key] = value
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	req := &milvuspb.AlterCollectionRequest{
+		DbName:         spec.DbName,
+		CollectionName: spec.CollectionName,
+		Properties:     specProperties(changed),
+	}
+	return &SpecChange{
+		Action: specActionAlterProperties,
+		Target: spec.CollectionName,
+		Detail: fmt.Sprint(changed),
+		apply: func(ctx context.Context) error {
+			if err := checkMgrPrivilege(ctx, req); err != nil {
+				return err
+			}
+			return merr.CheckRPCCall(node.AlterCollection(ctx, req))
+		},
+	}
+}
+
+func (node *Proxy) diffCreateIndex(spec *CollectionSpec, index *IndexSpec) *SpecChange {
+	target := index.IndexName
+	if target == "" {
+		target = index.FieldName
+	}
+	req := &milvuspb.CreateIndexRequest{
+		DbName:         spec.DbName,
+		CollectionName: spec.CollectionName,
+		FieldName:      index.FieldName,
+		IndexName:      index.IndexName,
+		ExtraParams:    specProperties(index.Params),
+	}
+	return &SpecChange{
+		Action: specActionCreateIndex,
+		Target: target,
+		Detail: fmt.Sprintf("field=%s params=%v", index.FieldName, index.Params),
+		apply: func(ctx context.Context) error {
+			if err := checkMgrPrivilege(ctx, req); err != nil {
+				return err
+			}
+			return merr.CheckRPCCall(node.CreateIndex(ctx, req))
+		},
+	}
+}
+
+// diffIndexParams alters the params of the existing index which differ from the spec, the params not in the spec are
+// kept. The params not alterable are rejected by AlterIndex and reported in the change.
+func (node *Proxy) diffIndexParams(spec *CollectionSpec, index *IndexSpec, exist *milvuspb.IndexDescription) *SpecChange {
+	current := make(map[string]string, len(exist.GetParams()))
+	for _, kv := range exist.GetParams() {
+		current[kv.GetKey()] = kv.GetValue()
+	}
+	changed := make(map[string]string)
+	for key, value := range index.Params {
+		if old, ok := current[key]; !ok || old != value {
+			changed[key] = value
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	req := &milvuspb.AlterIndexRequest{
+		DbName:         spec.DbName,
+		CollectionName: spec.CollectionName,
+		IndexName:      exist.GetIndexName(),
+		ExtraParams:    specProperties(changed),
+	}
+	return &SpecChange{
+		Action: specActionAlterIndex,
+		Target: exist.GetIndexName(),
+		Detail: fmt.Sprint(changed),
+		apply: func(ctx context.Context) error {
+			if err := checkMgrPrivilege(ctx, req); err != nil {
+				return err
+			}
+			return merr.CheckRPCCall(node.AlterIndex(ctx, req))
+		},
+	}
+}
+
+// diffAlias creates the alias if it doesn't exist, or moves it to the collection if it points to another one.
+func (node *Proxy) diffAlias(ctx context.Context, spec *CollectionSpec, alias string) *SpecChange {
+	describeReq := &milvuspb.DescribeAliasRequest{
+		DbName: spec.DbName,
+		Alias:  alias,
+	}
+	var describeResp *milvuspb.DescribeAliasResponse
+	err := checkMgrPrivilege(ctx, describeReq)
+	if err == nil {
+		describeResp, err = node.DescribeAlias(ctx, describeReq)
+		err = merr.CheckRPCCall(describeResp, err)
+	}
+	if err == nil && describeResp.GetCollection() != "" {
+		req := &milvuspb.AlterAliasRequest{
+			DbName:         spec.DbName,
+			CollectionName: spec.CollectionName,
+			Alias:          alias,
+		}
+		return &SpecChange{
+			Action: specActionAlterAlias,
+			Target: alias,
+			Detail: fmt.Sprintf("%s -> %s", describeResp.GetCollection(), spec.CollectionName),
+			apply: func(ctx context.Context) error {
+				if err := checkMgrPrivilege(ctx, req); err != nil {
+					return err
+				}
+				return merr.CheckRPCCall(node.AlterAlias(ctx, req))
+			},
+		}
+	}
+
+	req := &milvuspb.CreateAliasRequest{
+		DbName:         spec.DbName,
+		CollectionName: spec.CollectionName,
+		Alias:          alias,
+	}
+	return &SpecChange{
+		Action: specActionCreateAlias,
+		Target: alias,
+		apply: func(ctx context.Context) error {
+			if err := checkMgrPrivilege(ctx, req); err != nil {
+				return err
+			}
+			return merr.CheckRPCCall(node.CreateAlias(ctx, req))
+		},
+	}
+}
+
+// specProperties converts the map to key value pairs sorted by key.
+func specProperties(m map[string]string) []*commonpb.KeyValuePair {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	kvs := make([]*commonpb.KeyValuePair, 0, len(keys))
+	for _, key := range keys {
+		kvs = append(kvs, &commonpb.KeyValuePair{Key: key, Value: m[key]})
+	}
+	return kvs
+}
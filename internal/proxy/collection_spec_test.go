@@ -0,0 +1,120 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestSpecProperties(t *testing.T) {
+	kvs := specProperties(map[string]string{"b": "2", "a": "1"})
+	assert.Equal(t, []*commonpb.KeyValuePair{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}, kvs)
+	assert.Empty(t, specProperties(nil))
+}
+
+func TestDiffCollectionSpec(t *testing.T) {
+	node := &Proxy{}
+
+	t.Run("properties", func(t *testing.T) {
+		spec := &CollectionSpec{
+			CollectionName: "c1",
+			Properties:     map[string]string{"a": "1", "b": "2"},
+		}
+		change := node.diffProperties(spec, []*commonpb.KeyValuePair{{Key: "a", Value: "1"}, {Key: "b", Value: "1"}})
+		assert.NotNil(t, change)
+		assert.Equal(t, specActionAlterProperties, change.Action)
+		assert.Equal(t, "map[b:2]", change.Detail)
+
+		change = node.diffProperties(spec, []*commonpb.KeyValuePair{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}})
+		assert.Nil(t, change)
+	})
+
+	t.Run("create collection", func(t *testing.T) {
+		_, err := node.diffCreateCollection(&CollectionSpec{CollectionName: "c1"})
+		assert.Error(t, err)
+
+		_, err = node.diffCreateCollection(&CollectionSpec{CollectionName: "c1", Schema: []byte(`invalid`)})
+		assert.Error(t, err)
+
+		change, err := node.diffCreateCollection(&CollectionSpec{
+			CollectionName: "c1",
+			Schema:         []byte(`{"fields": [{"name": "pk", "is_primary_key": true, "data_type": "Int64"}]}`),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, specActionCreateCollection, change.Action)
+		assert.Equal(t, "c1", change.Target)
+	})
+
+	t.Run("index params", func(t *testing.T) {
+		spec := &CollectionSpec{CollectionName: "c1"}
+		exist := &milvuspb.IndexDescription{
+			IndexName: "idx",
+			Params:    []*commonpb.KeyValuePair{{Key: "index_type", Value: "HNSW"}, {Key: "mmap.enabled", Value: "false"}},
+		}
+		change := node.diffIndexParams(spec, &IndexSpec{IndexName: "idx", Params: map[string]string{"index_type": "HNSW"}}, exist)
+		assert.Nil(t, change)
+
+		change = node.diffIndexParams(spec, &IndexSpec{IndexName: "idx", Params: map[string]string{"index_type": "HNSW", "mmap.enabled": "true"}}, exist)
+		assert.NotNil(t, change)
+		assert.Equal(t, specActionAlterIndex, change.Action)
+		assert.Equal(t, "idx", change.Target)
+		assert.Equal(t, "map[mmap.enabled:true]", change.Detail)
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		existing := &schemapb.CollectionSchema{
+			Name: "c1",
+			Fields: []*schemapb.FieldSchema{
+				{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+				{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: "dim", Value: "8"}}},
+			},
+		}
+		spec := &CollectionSpec{
+			CollectionName: "c1",
+			Schema:         []byte(`{"fields": [{"name": "pk", "is_primary_key": true, "data_type": "Int64"}, {"name": "vec", "data_type": "FloatVector", "type_params": [{"key": "dim", "value": "8"}]}]}`),
+			ShardsNum:      2,
+		}
+		change, err := node.diffSchema(spec, existing, 2)
+		assert.NoError(t, err)
+		assert.Nil(t, change)
+
+		spec.Schema = []byte(`{"fields": [{"name": "pk", "is_primary_key": true, "data_type": "Int64"}, {"name": "vec", "data_type": "FloatVector", "type_params": [{"key": "dim", "value": "16"}]}]}`)
+		change, err = node.diffSchema(spec, existing, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, specActionSchemaConflict, change.Action)
+		assert.Contains(t, change.Detail, "dim")
+		assert.Contains(t, change.Detail, "shards_num: existing 1, requested 2")
+		assert.ErrorIs(t, change.apply(context.Background()), merr.ErrCollectionConflict)
+
+		spec.Schema = []byte(`invalid`)
+		_, err = node.diffSchema(spec, existing, 2)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing collection name", func(t *testing.T) {
+		_, err := node.applyCollectionSpec(context.Background(), &CollectionSpec{})
+		assert.Error(t, err)
+	})
+}
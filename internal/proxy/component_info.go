@@ -0,0 +1,195 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/config"
+	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// The keys of the extra info of the proxy component info, the values of the queue depths, the cache sizes and the
+// rate limiter are json.
+const (
+	componentBuildVersionKey = "build_version"
+	componentGitCommitKey    = "git_commit"
+	componentBuildTimeKey    = "build_time"
+	componentStartTimeKey    = "start_time"
+	componentConfigHashKey   = "config_hash"
+	componentQueueDepthsKey  = "queue_depths"
+	componentCacheSizesKey   = "cache_sizes"
+	componentRateLimiterKey  = "rate_limiter"
+)
+
+type taskQueueDepth struct {
+	MaxTaskNum int64 `json:"max_task_num"`
+	Unissued   int   `json:"unissued"`
+	Active     int   `json:"active"`
+}
+
+type metaCacheSizes struct {
+	Collections  int `json:"collections"`
+	ShardLeaders int `json:"shard_leaders"`
+	Credentials  int `json:"credentials"`
+	Privileges   int `json:"privileges"`
+	UserRoles    int `json:"user_roles"`
+}
+
+// rateLimiterSummary is the cluster level rates and quota states, the rates of the databases, the collections and
+// the partitions are in the state dump.
+type rateLimiterSummary struct {
+	Enabled bool               `json:"enabled"`
+	Rates   map[string]float64 `json:"rates,omitempty"`
+	States  map[string]string  `json:"states,omitempty"`
+}
+
+var (
+	configHashOnce   sync.Once
+	cachedConfigHash = atomic.NewString("")
+)
+
+// depth only reads the task counters, GetComponentStates is called by the health checks and can't wait for the
+// queue locks.
+func (queue *baseTaskQueue) depth() *taskQueueDepth {
+	return &taskQueueDepth{
+		MaxTaskNum: queue.getMaxTaskNum(),
+		Unissued:   int(queue.unissuedNum.Load()),
+		Active:     int(queue.activeNum.Load()),
+	}
+}
+
+func (sched *taskScheduler) queueDepths() map[string]*taskQueueDepth {
+	return map[string]*taskQueueDepth{
+		"dd_queue": sched.ddQueue.depth(),
+		"dm_queue": sched.dmQueue.depth(),
+		"dq_queue": sched.dqQueue.depth(),
+		"dc_queue": sched.dcQueue.depth(),
+	}
+}
+
+// sizes returns nil if the cache is being updated, the health checks don't wait for the cache locks.
+func (m *MetaCache) sizes() *metaCacheSizes {
+	sizes := &metaCacheSizes{}
+	if !m.mu.TryRLock() {
+		return nil
+	}
+	for _, collections := range m.collInfo {
+		sizes.Collections += len(collections)
+	}
+	sizes.Privileges = len(m.privilegeInfos)
+	sizes.UserRoles = len(m.userToRoles)
+	m.mu.RUnlock()
+
+	if !m.leaderMut.TryRLock() {
+		return nil
+	}
+	for _, leaders := range m.collLeader {
+		sizes.ShardLeaders += len(leaders)
+	}
+	m.leaderMut.RUnlock()
+
+	if !m.credMut.TryRLock() {
+		return nil
+	}
+	sizes.Credentials = len(m.credMap)
+	m.credMut.RUnlock()
+	return sizes
+}
+
+// configHash is the sha256 of the sorted configs, the proxies with different configs have different hashes.
+func configHash(configs map[string]string) string {
+	keys := make([]string, 0, len(configs))
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write([]byte{'='})
+		h.Write([]byte(configs[key]))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// getConfigHash returns the config hash cached, which is refreshed on the config changes.
+func getConfigHash() string {
+	configHashOnce.Do(func() {
+		paramtable.Get().WatchKeyPrefix("", config.NewHandler("proxy.componentInfo.configHash", func(*config.Event) {
+			cachedConfigHash.Store(configHash(paramtable.Get().GetAll()))
+		}))
+		cachedConfigHash.Store(configHash(paramtable.Get().GetAll()))
+	})
+	return cachedConfigHash.Load()
+}
+
+// componentExtraInfo returns the build info, the start time, the config hash, the depths of the task queues, the
+// sizes of the meta cache and the state of the rate limiter, reported in the component info of the proxy.
+func (node *Proxy) componentExtraInfo() []*commonpb.KeyValuePair {
+	info := map[string]string{
+		componentBuildVersionKey: common.Version.String(),
+		componentGitCommitKey:    os.Getenv(metricsinfo.GitCommitEnvKey),
+		componentBuildTimeKey:    os.Getenv(metricsinfo.MilvusBuildTimeEnvKey),
+		componentStartTimeKey:    paramtable.GetCreateTime().Format(time.RFC3339),
+		componentConfigHashKey:   getConfigHash(),
+	}
+	marshal := func(key string, v any) {
+		if bs, err := json.Marshal(v); err == nil {
+			info[key] = string(bs)
+		}
+	}
+	if node.sched != nil {
+		marshal(componentQueueDepthsKey, node.sched.queueDepths())
+	}
+	if cache, ok := globalMetaCache.(*MetaCache); ok {
+		if sizes := cache.sizes(); sizes != nil {
+			marshal(componentCacheSizesKey, sizes)
+		}
+	}
+	limiter := &rateLimiterSummary{Enabled: Params.QuotaConfig.QuotaAndLimitsEnabled.GetAsBool()}
+	if node.simpleLimiter != nil {
+		if rates := node.simpleLimiter.GetRootRates(); rates != nil {
+			limiter.Rates = rates.Rates
+			limiter.States = rates.States
+		}
+	}
+	marshal(componentRateLimiterKey, limiter)
+
+	keys := make([]string, 0, len(info))
+	for key := range info {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	kvs := make([]*commonpb.KeyValuePair, 0, len(keys))
+	for _, key := range keys {
+		kvs = append(kvs, &commonpb.KeyValuePair{Key: key, Value: info[key]})
+	}
+	return kvs
+}
@@ -0,0 +1,81 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestConfigHash(t *testing.T) {
+	hash := configHash(map[string]string{"a": "1", "b": "2"})
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, configHash(map[string]string{"b": "2", "a": "1"}))
+	assert.NotEqual(t, hash, configHash(map[string]string{"a": "1", "b": "3"}))
+	assert.NotEqual(t, hash, configHash(map[string]string{"a": "1=b", "": "2"}))
+}
+
+func TestMetaCacheSizes(t *testing.T) {
+	cache := &MetaCache{
+		collInfo: map[string]map[string]*collectionInfo{
+			"db1": {"c1": {}, "c2": {}},
+			"db2": {"c3": {}},
+		},
+		collLeader:     map[string]map[string]*shardLeaders{"db1": {"c1": {}}},
+		credMap:        map[string]*internalpb.CredentialInfo{"root": {}},
+		privilegeInfos: map[string]struct{}{},
+		userToRoles:    map[string]map[string]struct{}{"root": {"admin": {}}},
+	}
+	assert.Equal(t, &metaCacheSizes{Collections: 3, ShardLeaders: 1, Credentials: 1, UserRoles: 1}, cache.sizes())
+
+	cache.leaderMut.Lock()
+	assert.Nil(t, cache.sizes())
+	cache.leaderMut.Unlock()
+}
+
+func TestGetComponentStatesExtraInfo(t *testing.T) {
+	paramtable.Init()
+	queue := newBaseTaskQueue(nil)
+	assert.NoError(t, queue.addUnissuedTask(&createCollectionTask{Condition: NewTaskCondition(context.Background())}))
+	assert.NoError(t, queue.addUnissuedTask(&createCollectionTask{Condition: NewTaskCondition(context.Background())}))
+	queue.AddActiveTask(queue.PopUnissuedTask())
+	assert.Equal(t, &taskQueueDepth{MaxTaskNum: queue.getMaxTaskNum(), Unissued: 1, Active: 1}, queue.depth())
+
+	node := &Proxy{simpleLimiter: NewSimpleLimiter()}
+	node.UpdateStateCode(commonpb.StateCode_Healthy)
+	resp, err := node.GetComponentStates(context.Background(), &milvuspb.GetComponentStatesRequest{})
+	assert.NoError(t, err)
+	info := funcutil.KeyValuePair2Map(resp.GetState().GetExtraInfo())
+	assert.NotEmpty(t, info[componentBuildVersionKey])
+	assert.NotEmpty(t, info[componentStartTimeKey])
+	assert.Equal(t, configHash(paramtable.Get().GetAll()), info[componentConfigHashKey])
+	assert.Equal(t, info[componentConfigHashKey], getConfigHash())
+
+	limiter := &rateLimiterSummary{}
+	assert.NoError(t, json.Unmarshal([]byte(info[componentRateLimiterKey]), limiter))
+	assert.Equal(t, Params.QuotaConfig.QuotaAndLimitsEnabled.GetAsBool(), limiter.Enabled)
+	assert.Equal(t, node.simpleLimiter.GetRootRates().Rates, limiter.Rates)
+}
@@ -0,0 +1,187 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	// compositeKeyFunc is the filter function of the composite primary key of the values, such as
+	// pk in [composite_key(1, "doc-1"), composite_key(1, "doc-2")]
+	compositeKeyFunc = "composite_key"
+	// compositeKeySeparator separates the values of the composite primary key, escaped in the varchar values
+	compositeKeySeparator = ':'
+)
+
+// getCompositePrimaryKey returns the fields composing the primary key of the collection, nil if not composite.
+func getCompositePrimaryKey(schema *schemapb.CollectionSchema, properties []*commonpb.KeyValuePair) ([]*schemapb.FieldSchema, error) {
+	value, err := funcutil.GetAttrByKeyFromRepeatedKV(common.CollectionCompositePrimaryKeyKey, properties)
+	if err != nil {
+		return nil, nil
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	if pkField.GetDataType() != schemapb.DataType_VarChar || pkField.GetAutoID() {
+		return nil, merr.WrapErrParameterInvalidMsg("the composite primary key %s must be a varchar field without auto id", pkField.GetName())
+	}
+	names := strings.Split(value, ",")
+	if len(names) != 2 {
+		return nil, merr.WrapErrParameterInvalidMsg("the composite primary key is composed of 2 fields, got %q", value)
+	}
+	fields := make([]*schemapb.FieldSchema, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		field := typeutil.GetFieldByName(schema, name)
+		if field == nil {
+			return nil, merr.WrapErrFieldNotFound(name, "the field of the composite primary key not found")
+		}
+		if field.GetIsPrimaryKey() || field.GetIsDynamic() ||
+			(field.GetDataType() != schemapb.DataType_Int64 && field.GetDataType() != schemapb.DataType_VarChar) {
+			return nil, merr.WrapErrParameterInvalidMsg("the field %s of the composite primary key must be an int64 or varchar field", name)
+		}
+		fields = append(fields, field)
+	}
+	if fields[0].GetName() == fields[1].GetName() {
+		return nil, merr.WrapErrParameterInvalidMsg("the fields of the composite primary key are duplicated")
+	}
+	return fields, nil
+}
+
+// encodeCompositeKey returns the primary key composed of the values, the separator and the escape character are
+// escaped in the varchar values so the different values never compose the same key.
+func encodeCompositeKey(values ...any) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case int64:
+			parts = append(parts, strconv.FormatInt(v, 10))
+		case string:
+			v = strings.ReplaceAll(v, `\`, `\\`)
+			parts = append(parts, strings.ReplaceAll(v, string(compositeKeySeparator), `\`+string(compositeKeySeparator)))
+		}
+	}
+	return strings.Join(parts, string(compositeKeySeparator))
+}
+
+// fillCompositePrimaryKey composes the primary keys of the rows by the fields of the composite primary key, which
+// must not be provided by the request.
+func fillCompositePrimaryKey(schema *schemapb.CollectionSchema, insertMsg *msgstream.InsertMsg) error {
+	fields, err := getCompositePrimaryKey(schema, schema.GetProperties())
+	if err != nil || fields == nil {
+		return err
+	}
+	pkField, _ := typeutil.GetPrimaryFieldSchema(schema)
+	if typeutil.IsPrimaryFieldDataExist(insertMsg.GetFieldsData(), pkField) {
+		return merr.WrapErrParameterInvalidMsg("the primary key %s is composed of %s and %s, it can not be provided",
+			pkField.GetName(), fields[0].GetName(), fields[1].GetName())
+	}
+
+	columns := make([][]any, len(fields))
+	for i, field := range fields {
+		for _, data := range insertMsg.GetFieldsData() {
+			if data.GetFieldName() != field.GetName() {
+				continue
+			}
+			switch field.GetDataType() {
+			case schemapb.DataType_Int64:
+				for _, v := range data.GetScalars().GetLongData().GetData() {
+					columns[i] = append(columns[i], v)
+				}
+			case schemapb.DataType_VarChar:
+				for _, v := range data.GetScalars().GetStringData().GetData() {
+					columns[i] = append(columns[i], v)
+				}
+			}
+		}
+		if len(columns[i]) != int(insertMsg.NRows()) {
+			return merr.WrapErrParameterInvalidMsg("the field %s of the composite primary key has %d values, expect %d",
+				field.GetName(), len(columns[i]), insertMsg.NRows())
+		}
+	}
+
+	keys := make([]string, insertMsg.NRows())
+	for row := range keys {
+		keys[row] = encodeCompositeKey(columns[0][row], columns[1][row])
+	}
+	insertMsg.FieldsData = append(insertMsg.FieldsData, &schemapb.FieldData{
+		Type:      schemapb.DataType_VarChar,
+		FieldName: pkField.GetName(),
+		FieldId:   pkField.GetFieldID(),
+		Field: &schemapb.FieldData_Scalars{
+			Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: keys}},
+			},
+		},
+	})
+	return nil
+}
+
+// expandCompositeKeyFunctions replaces the composite_key functions outside the string literals of the expression
+// with the string literals of the composite primary keys of their int and string literal arguments.
+func expandCompositeKeyFunctions(expr string) (string, error) {
+	if !strings.Contains(expr, compositeKeyFunc) {
+		return expr, nil
+	}
+	isFunc := func(name string) bool { return name == compositeKeyFunc }
+	return replaceExprFunctions(expr, isFunc, func(_ string, j int) (string, int, error) {
+		var values []any
+		j = skipExprSpaces(expr, j)
+		for j < len(expr) && expr[j] != ')' {
+			if len(values) > 0 {
+				if expr[j] != ',' {
+					return "", 0, merr.WrapErrParameterInvalidMsg("invalid arguments of %s()", compositeKeyFunc)
+				}
+				j = skipExprSpaces(expr, j+1)
+			}
+			if j < len(expr) && (expr[j] == '"' || expr[j] == '\'') {
+				value, end, ok := readStringLiteral(expr, j)
+				if !ok {
+					return "", 0, merr.WrapErrParameterInvalidMsg("invalid string argument of %s()", compositeKeyFunc)
+				}
+				values = append(values, value)
+				j = skipExprSpaces(expr, end)
+				continue
+			}
+			end := j
+			for end < len(expr) && (expr[end] == '-' || ('0' <= expr[end] && expr[end] <= '9')) {
+				end++
+			}
+			value, err := strconv.ParseInt(expr[j:end], 10, 64)
+			if err != nil {
+				return "", 0, merr.WrapErrParameterInvalidMsg("the arguments of %s() must be int or string literals", compositeKeyFunc)
+			}
+			values = append(values, value)
+			j = skipExprSpaces(expr, end)
+		}
+		if j >= len(expr) || len(values) != 2 {
+			return "", 0, merr.WrapErrParameterInvalidMsg("%s() expects 2 int or string literals", compositeKeyFunc)
+		}
+		return strconv.Quote(encodeCompositeKey(values...)), j + 1, nil
+	})
+}
@@ -0,0 +1,110 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// expandCompositeKeyDelete returns the delete expression of the composite primary keys, if the expression selects the
+// rows by the values of both the fields composing the primary key, such as tenant_id == 1 and doc_id in ["a", "b"],
+// so the rows are deleted by their primary keys without querying them. The composite_key functions of the expression
+// are expanded too.
+func expandCompositeKeyDelete(schema *schemaInfo, expr string) (string, error) {
+	expr, err := expandCompositeKeyFunctions(expr)
+	if err != nil {
+		return "", err
+	}
+	fields, err := getCompositePrimaryKey(schema.CollectionSchema, schema.GetProperties())
+	if err != nil || fields == nil {
+		return expr, err
+	}
+	plan, err := planparserv2.CreateRetrievePlan(schema.schemaHelper, expr)
+	if err != nil {
+		// reported on creating the delete plan
+		return expr, nil
+	}
+	binaryExpr := plan.GetQuery().GetPredicates().GetBinaryExpr()
+	if binaryExpr.GetOp() != planpb.BinaryExpr_LogicalAnd {
+		return expr, nil
+	}
+
+	values := make([][]any, len(fields))
+	for _, operand := range []*planpb.Expr{binaryExpr.GetLeft(), binaryExpr.GetRight()} {
+		column, operandValues := getCompositeKeyFieldValues(operand)
+		for i, field := range fields {
+			if column.GetFieldId() == field.GetFieldID() && values[i] == nil {
+				values[i] = operandValues
+			}
+		}
+	}
+	if values[0] == nil || values[1] == nil {
+		return expr, nil
+	}
+
+	keys := make([]string, 0, len(values[0])*len(values[1]))
+	for _, first := range values[0] {
+		for _, second := range values[1] {
+			keys = append(keys, strconv.Quote(encodeCompositeKey(first, second)))
+		}
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(schema.CollectionSchema)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s in [%s]", pkField.GetName(), strings.Join(keys, ", ")), nil
+}
+
+// getCompositeKeyFieldValues returns the field and the values of the equal or the in expression, nil if the
+// expression is of other kinds.
+func getCompositeKeyFieldValues(expr *planpb.Expr) (*planpb.ColumnInfo, []any) {
+	var column *planpb.ColumnInfo
+	var genericValues []*planpb.GenericValue
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryRangeExpr:
+		if e.UnaryRangeExpr.GetOp() != planpb.OpType_Equal {
+			return nil, nil
+		}
+		column, genericValues = e.UnaryRangeExpr.GetColumnInfo(), []*planpb.GenericValue{e.UnaryRangeExpr.GetValue()}
+	case *planpb.Expr_TermExpr:
+		column, genericValues = e.TermExpr.GetColumnInfo(), e.TermExpr.GetValues()
+	default:
+		return nil, nil
+	}
+	if len(column.GetNestedPath()) > 0 {
+		return nil, nil
+	}
+
+	values := make([]any, 0, len(genericValues))
+	for _, value := range genericValues {
+		switch v := value.GetVal().(type) {
+		case *planpb.GenericValue_Int64Val:
+			values = append(values, v.Int64Val)
+		case *planpb.GenericValue_StringVal:
+			values = append(values, v.StringVal)
+		default:
+			return nil, nil
+		}
+	}
+	return column, values
+}
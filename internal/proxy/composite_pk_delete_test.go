@@ -0,0 +1,57 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestExpandCompositeKeyDelete(t *testing.T) {
+	schema := newSchemaInfo(compositePrimaryKeySchema("tenant_id, doc_id"))
+
+	cases := []struct {
+		expr     string
+		expanded string
+	}{
+		{`tenant_id == 1 and doc_id == "a:b"`, `pk in ["1:a\\:b"]`},
+		{`doc_id in ["a", "b"] and tenant_id in [1, 2]`, `pk in ["1:a", "1:b", "2:a", "2:b"]`},
+		{`pk in [composite_key(1, "a")]`, `pk in ["1:a"]`},
+		{`tenant_id == 1`, `tenant_id == 1`},
+		{`tenant_id == 1 and score > 0.5`, `tenant_id == 1 and score > 0.5`},
+		{`tenant_id == 1 or doc_id == "a"`, `tenant_id == 1 or doc_id == "a"`},
+		{`tenant_id > 1 and doc_id == "a"`, `tenant_id > 1 and doc_id == "a"`},
+	}
+	for _, c := range cases {
+		expanded, err := expandCompositeKeyDelete(schema, c.expr)
+		assert.NoError(t, err, c.expr)
+		assert.Equal(t, c.expanded, expanded, c.expr)
+	}
+
+	_, err := expandCompositeKeyDelete(schema, `pk in [composite_key(1)]`)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	// not composite
+	schema = newSchemaInfo(compositePrimaryKeySchema("tenant_id, doc_id"))
+	schema.Properties = nil
+	expanded, err := expandCompositeKeyDelete(schema, `tenant_id == 1 and doc_id == "a"`)
+	assert.NoError(t, err)
+	assert.Equal(t, `tenant_id == 1 and doc_id == "a"`, expanded)
+}
@@ -0,0 +1,130 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func compositePrimaryKeySchema(value string) *schemapb.CollectionSchema {
+	return &schemapb.CollectionSchema{
+		Name: "coll",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_VarChar},
+			{FieldID: 101, Name: "tenant_id", DataType: schemapb.DataType_Int64},
+			{FieldID: 102, Name: "doc_id", DataType: schemapb.DataType_VarChar},
+			{FieldID: 103, Name: "score", DataType: schemapb.DataType_Float},
+		},
+		Properties: []*commonpb.KeyValuePair{{Key: common.CollectionCompositePrimaryKeyKey, Value: value}},
+	}
+}
+
+func TestGetCompositePrimaryKey(t *testing.T) {
+	schema := compositePrimaryKeySchema("tenant_id, doc_id")
+	fields, err := getCompositePrimaryKey(schema, schema.GetProperties())
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant_id", fields[0].GetName())
+	assert.Equal(t, "doc_id", fields[1].GetName())
+
+	fields, err = getCompositePrimaryKey(schema, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, fields)
+
+	for _, value := range []string{"tenant_id", "tenant_id,doc_id,score", "tenant_id,score", "tenant_id,pk", "tenant_id,tenant_id"} {
+		schema = compositePrimaryKeySchema(value)
+		_, err = getCompositePrimaryKey(schema, schema.GetProperties())
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid, value)
+	}
+	schema = compositePrimaryKeySchema("tenant_id,user")
+	_, err = getCompositePrimaryKey(schema, schema.GetProperties())
+	assert.ErrorIs(t, err, merr.ErrFieldNotFound)
+
+	schema = compositePrimaryKeySchema("tenant_id,doc_id")
+	schema.Fields[0].AutoID = true
+	_, err = getCompositePrimaryKey(schema, schema.GetProperties())
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestEncodeCompositeKey(t *testing.T) {
+	assert.Equal(t, "1:doc", encodeCompositeKey(int64(1), "doc"))
+	assert.Equal(t, `a\:b:c\\`, encodeCompositeKey("a:b", `c\`))
+	assert.NotEqual(t, encodeCompositeKey("a:b", "c"), encodeCompositeKey("a", "b:c"))
+}
+
+func TestFillCompositePrimaryKey(t *testing.T) {
+	schema := compositePrimaryKeySchema("tenant_id,doc_id")
+	insertMsg := &msgstream.InsertMsg{
+		InsertRequest: msgpb.InsertRequest{
+			Version: msgpb.InsertDataVersion_ColumnBased,
+			NumRows: 2,
+			FieldsData: []*schemapb.FieldData{
+				{
+					Type:      schemapb.DataType_Int64,
+					FieldName: "tenant_id",
+					Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{1, 2}}},
+					}},
+				},
+				{
+					Type:      schemapb.DataType_VarChar,
+					FieldName: "doc_id",
+					Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"a", "b:c"}}},
+					}},
+				},
+			},
+		},
+	}
+	assert.NoError(t, fillCompositePrimaryKey(schema, insertMsg))
+	pk := insertMsg.GetFieldsData()[2]
+	assert.Equal(t, "pk", pk.GetFieldName())
+	assert.Equal(t, []string{"1:a", `2:b\:c`}, pk.GetScalars().GetStringData().GetData())
+
+	// the primary key can not be provided
+	assert.ErrorIs(t, fillCompositePrimaryKey(schema, insertMsg), merr.ErrParameterInvalid)
+
+	insertMsg.FieldsData = insertMsg.FieldsData[:1]
+	assert.ErrorIs(t, fillCompositePrimaryKey(schema, insertMsg), merr.ErrParameterInvalid)
+
+	schema.Properties = nil
+	assert.NoError(t, fillCompositePrimaryKey(schema, insertMsg))
+	assert.Len(t, insertMsg.GetFieldsData(), 1)
+}
+
+func TestExpandCompositeKeyFunctions(t *testing.T) {
+	expr, err := expandCompositeKeyFunctions(`pk in [composite_key(1, "a"), composite_key( -2 ,'b:c')] and doc_id != "composite_key(1, 2)"`)
+	assert.NoError(t, err)
+	assert.Equal(t, `pk in ["1:a", "-2:b\\:c"] and doc_id != "composite_key(1, 2)"`, expr)
+
+	expr, err = expandCompositeKeyFunctions(`pk == "x"`)
+	assert.NoError(t, err)
+	assert.Equal(t, `pk == "x"`, expr)
+
+	for _, invalid := range []string{`pk == composite_key(1)`, `pk == composite_key(1, 2, 3)`, `pk == composite_key(a, 1)`, `pk == composite_key(1 2)`, `pk == composite_key(1, "a"`} {
+		_, err = expandCompositeKeyFunctions(invalid)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid, invalid)
+	}
+}
@@ -0,0 +1,195 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// ifNotExistsKey is the request metadata key to succeed the creation if the object already exists with the identical spec.
+const ifNotExistsKey = "if_not_exists"
+
+// diffKeyValuePairs returns the differences between the existing and requested key value pairs.
+func diffKeyValuePairs(prefix string, existing, requested []*commonpb.KeyValuePair) []string {
+	existingMap := make(map[string]string, len(existing))
+	for _, kv := range existing {
+		existingMap[kv.GetKey()] = kv.GetValue()
+	}
+	requestedMap := make(map[string]string, len(requested))
+	for _, kv := range requested {
+		requestedMap[kv.GetKey()] = kv.GetValue()
+	}
+
+	diffs := make([]string, 0)
+	for key, value := range requestedMap {
+		if existingValue, ok := existingMap[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%s%s: not set, requested %s", prefix, key, value))
+		} else if existingValue != value {
+			diffs = append(diffs, fmt.Sprintf("%s%s: existing %s, requested %s", prefix, key, existingValue, value))
+		}
+	}
+	for key, value := range existingMap {
+		if _, ok := requestedMap[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%s%s: existing %s, not requested", prefix, key, value))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
+
+func diffFieldSchema(existing, requested *schemapb.FieldSchema) []string {
+	prefix := fmt.Sprintf("field %s ", requested.GetName())
+	diffs := make([]string, 0)
+	diff := func(name string, existingValue, requestedValue any) {
+		if existingValue != requestedValue {
+			diffs = append(diffs, fmt.Sprintf("%s%s: existing %v, requested %v", prefix, name, existingValue, requestedValue))
+		}
+	}
+	diff("data_type", existing.GetDataType(), requested.GetDataType())
+	diff("element_type", existing.GetElementType(), requested.GetElementType())
+	diff("is_primary_key", existing.GetIsPrimaryKey(), requested.GetIsPrimaryKey())
+	diff("auto_id", existing.GetAutoID(), requested.GetAutoID())
+	diff("is_partition_key", existing.GetIsPartitionKey(), requested.GetIsPartitionKey())
+	diff("is_clustering_key", existing.GetIsClusteringKey(), requested.GetIsClusteringKey())
+	if !proto.Equal(existing.GetDefaultValue(), requested.GetDefaultValue()) {
+		diffs = append(diffs, fmt.Sprintf("%sdefault_value: existing %v, requested %v", prefix, existing.GetDefaultValue(), requested.GetDefaultValue()))
+	}
+	return append(diffs, diffKeyValuePairs(prefix, existing.GetTypeParams(), requested.GetTypeParams())...)
+}
+
+// diffCollectionSchema returns the differences of the user fields between the existing and requested schema.
+func diffCollectionSchema(existing, requested *schemapb.CollectionSchema) []string {
+	existingFields := make(map[string]*schemapb.FieldSchema)
+	for _, field := range existing.GetFields() {
+		if field.GetFieldID() >= common.StartOfUserFieldID && !field.GetIsDynamic() {
+			existingFields[field.GetName()] = field
+		}
+	}
+
+	diffs := make([]string, 0)
+	if existing.GetEnableDynamicField() != requested.GetEnableDynamicField() {
+		diffs = append(diffs, fmt.Sprintf("enable_dynamic_field: existing %t, requested %t", existing.GetEnableDynamicField(), requested.GetEnableDynamicField()))
+	}
+	for _, field := range requested.GetFields() {
+		existingField, ok := existingFields[field.GetName()]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("field %s: not exist, requested", field.GetName()))
+			continue
+		}
+		delete(existingFields, field.GetName())
+		diffs = append(diffs, diffFieldSchema(existingField, field)...)
+	}
+	missing := make([]string, 0, len(existingFields))
+	for name := range existingFields {
+		missing = append(missing, fmt.Sprintf("field %s: existing, not requested", name))
+	}
+	sort.Strings(missing)
+	return append(diffs, missing...)
+}
+
+// checkExistingCollection returns true if the collection already exists with the identical schema and attributes,
+// or the conflict error with the differences if any of them differs.
+func (t *createCollectionTask) checkExistingCollection(ctx context.Context) (bool, error) {
+	resp, err := t.rootCoord.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		Base:           commonpbutil.NewMsgBase(commonpbutil.WithMsgType(commonpb.MsgType_DescribeCollection)),
+		DbName:         t.GetDbName(),
+		CollectionName: t.GetCollectionName(),
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		if errors.Is(err, merr.ErrCollectionNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if resp.GetCollectionName() != t.GetCollectionName() {
+		return false, merr.WrapErrCollectionConflict(t.GetCollectionName(),
+			fmt.Sprintf("name is an alias of collection %s", resp.GetCollectionName()))
+	}
+
+	diffs := diffCollectionSchema(resp.GetSchema(), t.schema)
+	if resp.GetSchema().GetDescription() != t.schema.GetDescription() {
+		diffs = append(diffs, fmt.Sprintf("description: existing %q, requested %q", resp.GetSchema().GetDescription(), t.schema.GetDescription()))
+	}
+	if t.GetShardsNum() > 0 && t.GetShardsNum() != resp.GetShardsNum() {
+		diffs = append(diffs, fmt.Sprintf("shards_num: existing %d, requested %d", resp.GetShardsNum(), t.GetShardsNum()))
+	}
+	if t.GetNumPartitions() > 0 && t.GetNumPartitions() != resp.GetNumPartitions() {
+		diffs = append(diffs, fmt.Sprintf("num_partitions: existing %d, requested %d", resp.GetNumPartitions(), t.GetNumPartitions()))
+	}
+	if t.GetConsistencyLevel() != resp.GetConsistencyLevel() {
+		diffs = append(diffs, fmt.Sprintf("consistency_level: existing %s, requested %s", resp.GetConsistencyLevel(), t.GetConsistencyLevel()))
+	}
+	diffs = append(diffs, diffKeyValuePairs("property ", resp.GetProperties(), t.GetProperties())...)
+	if len(diffs) > 0 {
+		return false, merr.WrapErrCollectionConflict(t.GetCollectionName(), diffs...)
+	}
+	return true, nil
+}
+
+// checkExistingPartition returns true if the partition already exists.
+func (t *createPartitionTask) checkExistingPartition(ctx context.Context) bool {
+	_, err := globalMetaCache.GetPartitionID(ctx, t.GetDbName(), t.GetCollectionName(), t.GetPartitionName())
+	return err == nil
+}
+
+// checkExistingIndex returns true if the index already exists on the field with the identical params,
+// or the duplicate error with the differences if the params differ.
+func (cit *createIndexTask) checkExistingIndex(ctx context.Context) (bool, error) {
+	resp, err := cit.datacoord.DescribeIndex(ctx, &indexpb.DescribeIndexRequest{
+		CollectionID: cit.collectionID,
+		IndexName:    cit.req.GetIndexName(),
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		if errors.Is(err, merr.ErrIndexNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, index := range resp.GetIndexInfos() {
+		if index.GetFieldID() != cit.fieldSchema.GetFieldID() {
+			if index.GetIndexName() == cit.req.GetIndexName() {
+				return false, merr.WrapErrIndexDuplicate(index.GetIndexName(),
+					fmt.Sprintf("field: existing %d, requested %d", index.GetFieldID(), cit.fieldSchema.GetFieldID()))
+			}
+			continue
+		}
+		if cit.req.GetIndexName() != "" && index.GetIndexName() != cit.req.GetIndexName() {
+			return false, merr.WrapErrIndexDuplicate(cit.req.GetIndexName(),
+				fmt.Sprintf("index name: existing %s on field %s", index.GetIndexName(), cit.req.GetFieldName()))
+		}
+		diffs := diffKeyValuePairs("index param ", index.GetIndexParams(), cit.newIndexParams)
+		if len(diffs) > 0 {
+			return false, merr.WrapErrIndexDuplicate(index.GetIndexName(), diffs...)
+		}
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,247 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func newIfNotExistsTestSchema(dim string) *schemapb.CollectionSchema {
+	return &schemapb.CollectionSchema{
+		Name: "test",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{
+				{Key: common.DimKey, Value: dim},
+			}},
+		},
+	}
+}
+
+func TestDiffKeyValuePairs(t *testing.T) {
+	diffs := diffKeyValuePairs("",
+		[]*commonpb.KeyValuePair{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}, {Key: "c", Value: "3"}},
+		[]*commonpb.KeyValuePair{{Key: "b", Value: "2"}, {Key: "a", Value: "10"}, {Key: "d", Value: "4"}})
+	assert.Equal(t, []string{
+		"a: existing 1, requested 10",
+		"c: existing 3, not requested",
+		"d: not set, requested 4",
+	}, diffs)
+
+	assert.Empty(t, diffKeyValuePairs("", nil, nil))
+}
+
+func TestDiffCollectionSchema(t *testing.T) {
+	existing := newIfNotExistsTestSchema("128")
+	// the system and dynamic fields are ignored
+	existing.Fields = append(existing.Fields,
+		&schemapb.FieldSchema{FieldID: common.RowIDField, Name: common.RowIDFieldName, DataType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{FieldID: 102, Name: common.MetaFieldName, DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	assert.Empty(t, diffCollectionSchema(existing, newIfNotExistsTestSchema("128")))
+
+	requested := newIfNotExistsTestSchema("256")
+	requested.EnableDynamicField = true
+	requested.Fields[0].AutoID = true
+	requested.Fields = append(requested.Fields, &schemapb.FieldSchema{Name: "extra", DataType: schemapb.DataType_Int64})
+	assert.Equal(t, []string{
+		"enable_dynamic_field: existing false, requested true",
+		"field pk auto_id: existing false, requested true",
+		"field vec dim: existing 128, requested 256",
+		"field extra: not exist, requested",
+	}, diffCollectionSchema(existing, requested))
+
+	requested = newIfNotExistsTestSchema("128")
+	requested.Fields = requested.Fields[:1]
+	assert.Equal(t, []string{"field vec: existing, not requested"}, diffCollectionSchema(existing, requested))
+}
+
+func TestCreateCollectionTask_ifNotExists(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ifNotExistsKey, "true"))
+	newTask := func(rc *mocks.MockRootCoordClient, dim string) *createCollectionTask {
+		schema := newIfNotExistsTestSchema(dim)
+		marshaled, err := proto.Marshal(schema)
+		assert.NoError(t, err)
+		return &createCollectionTask{
+			CreateCollectionRequest: &milvuspb.CreateCollectionRequest{
+				Base:           &commonpb.MsgBase{},
+				CollectionName: "test",
+				Schema:         marshaled,
+			},
+			rootCoord: rc,
+			schema:    schema,
+		}
+	}
+
+	t.Run("identical", func(t *testing.T) {
+		rc := mocks.NewMockRootCoordClient(t)
+		rc.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+			Status:         merr.Success(),
+			CollectionName: "test",
+			Schema:         newIfNotExistsTestSchema("128"),
+		}, nil)
+		task := newTask(rc, "128")
+		assert.NoError(t, task.Execute(ctx))
+		assert.True(t, merr.Ok(task.result))
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		rc := mocks.NewMockRootCoordClient(t)
+		rc.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+			Status:         merr.Success(),
+			CollectionName: "test",
+			Schema:         newIfNotExistsTestSchema("128"),
+		}, nil)
+		err := newTask(rc, "256").Execute(ctx)
+		assert.ErrorIs(t, err, merr.ErrCollectionConflict)
+		assert.Contains(t, err.Error(), "field vec dim: existing 128, requested 256")
+	})
+
+	t.Run("attributes conflict", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			existing func(resp *milvuspb.DescribeCollectionResponse)
+			diff     string
+		}{
+			{"description", func(resp *milvuspb.DescribeCollectionResponse) {
+				resp.Schema.Description = "existing"
+			}, `description: existing "existing", requested ""`},
+			{"shards_num", func(resp *milvuspb.DescribeCollectionResponse) {
+				resp.ShardsNum = 1
+			}, "shards_num: existing 1, requested 2"},
+			{"num_partitions", func(resp *milvuspb.DescribeCollectionResponse) {
+				resp.NumPartitions = 16
+			}, "num_partitions: existing 16, requested 64"},
+			{"consistency_level", func(resp *milvuspb.DescribeCollectionResponse) {
+				resp.ConsistencyLevel = commonpb.ConsistencyLevel_Strong
+			}, "consistency_level: existing Strong, requested Bounded"},
+			{"properties", func(resp *milvuspb.DescribeCollectionResponse) {
+				resp.Properties = []*commonpb.KeyValuePair{{Key: common.CollectionTTLConfigKey, Value: "100"}}
+			}, "property " + common.CollectionTTLConfigKey + ": existing 100, requested 10"},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				rc := mocks.NewMockRootCoordClient(t)
+				newResp := func() *milvuspb.DescribeCollectionResponse {
+					return &milvuspb.DescribeCollectionResponse{
+						Status:           merr.Success(),
+						CollectionName:   "test",
+						Schema:           newIfNotExistsTestSchema("128"),
+						ShardsNum:        2,
+						NumPartitions:    64,
+						ConsistencyLevel: commonpb.ConsistencyLevel_Bounded,
+						Properties:       []*commonpb.KeyValuePair{{Key: common.CollectionTTLConfigKey, Value: "10"}},
+					}
+				}
+				resp := newResp()
+				c.existing(resp)
+				rc.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(resp, nil).Once()
+				rc.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(newResp(), nil).Once()
+
+				task := newTask(rc, "128")
+				task.ShardsNum = 2
+				task.NumPartitions = 64
+				task.ConsistencyLevel = commonpb.ConsistencyLevel_Bounded
+				task.Properties = []*commonpb.KeyValuePair{{Key: common.CollectionTTLConfigKey, Value: "10"}}
+				err := task.Execute(ctx)
+				assert.ErrorIs(t, err, merr.ErrCollectionConflict)
+				assert.Contains(t, err.Error(), c.diff)
+
+				// identical with all the attributes
+				assert.NoError(t, task.Execute(ctx))
+				assert.True(t, merr.Ok(task.result))
+			})
+		}
+	})
+
+	t.Run("not exist", func(t *testing.T) {
+		rc := mocks.NewMockRootCoordClient(t)
+		rc.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+			Status: merr.Status(merr.WrapErrCollectionNotFound("test")),
+		}, nil)
+		rc.EXPECT().CreateCollection(mock.Anything, mock.Anything).Return(merr.Success(), nil)
+		assert.NoError(t, newTask(rc, "128").Execute(ctx))
+	})
+
+	t.Run("without metadata", func(t *testing.T) {
+		rc := mocks.NewMockRootCoordClient(t)
+		rc.EXPECT().CreateCollection(mock.Anything, mock.Anything).Return(merr.Success(), nil)
+		assert.NoError(t, newTask(rc, "128").Execute(context.Background()))
+	})
+}
+
+func TestCreateIndexTask_checkExistingIndex(t *testing.T) {
+	newTask := func(dc *mocks.MockDataCoordClient, indexName string, indexType string) *createIndexTask {
+		return &createIndexTask{
+			req: &milvuspb.CreateIndexRequest{
+				CollectionName: "test",
+				FieldName:      "vec",
+				IndexName:      indexName,
+			},
+			datacoord:      dc,
+			collectionID:   1,
+			fieldSchema:    &schemapb.FieldSchema{FieldID: 101, Name: "vec"},
+			newIndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: indexType}},
+		}
+	}
+	newDataCoord := func(indexInfos ...*indexpb.IndexInfo) *mocks.MockDataCoordClient {
+		dc := mocks.NewMockDataCoordClient(t)
+		dc.EXPECT().DescribeIndex(mock.Anything, mock.Anything).Return(&indexpb.DescribeIndexResponse{
+			Status:     merr.Success(),
+			IndexInfos: indexInfos,
+		}, nil)
+		return dc
+	}
+	existing := &indexpb.IndexInfo{
+		FieldID:     101,
+		IndexName:   "vec_index",
+		IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: "HNSW"}},
+	}
+
+	exists, err := newTask(newDataCoord(existing), "", "HNSW").checkExistingIndex(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	_, err = newTask(newDataCoord(existing), "vec_index", "IVF_FLAT").checkExistingIndex(context.Background())
+	assert.ErrorIs(t, err, merr.ErrIndexDuplicate)
+	assert.Contains(t, err.Error(), "index param index_type: existing HNSW, requested IVF_FLAT")
+
+	_, err = newTask(newDataCoord(existing), "other_index", "HNSW").checkExistingIndex(context.Background())
+	assert.ErrorIs(t, err, merr.ErrIndexDuplicate)
+
+	dc := mocks.NewMockDataCoordClient(t)
+	dc.EXPECT().DescribeIndex(mock.Anything, mock.Anything).Return(&indexpb.DescribeIndexResponse{
+		Status: merr.Status(merr.WrapErrIndexNotFound("vec_index")),
+	}, nil)
+	exists, err = newTask(dc, "vec_index", "HNSW").checkExistingIndex(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,242 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"sort"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+// The state dump only contains the metadata of the internal structures, the request payloads like the expressions
+// and the vectors of the tasks, and the credentials in the meta cache are redacted.
+
+type taskState struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	BeginTs uint64 `json:"begin_ts"`
+	Age     string `json:"age"`
+}
+
+type taskQueueState struct {
+	MaxTaskNum int64        `json:"max_task_num"`
+	Unissued   []*taskState `json:"unissued"`
+	Active     []*taskState `json:"active"`
+}
+
+type schedulerState struct {
+	DdQueue *taskQueueState `json:"dd_queue"`
+	DmQueue *taskQueueState `json:"dm_queue"`
+	DqQueue *taskQueueState `json:"dq_queue"`
+	DcQueue *taskQueueState `json:"dc_queue"`
+}
+
+type cachedCollectionState struct {
+	Database      string `json:"database"`
+	Collection    string `json:"collection"`
+	CollectionID  int64  `json:"collection_id"`
+	NumPartitions int    `json:"num_partitions"`
+	Age           string `json:"age"`
+}
+
+type cachedShardLeadersState struct {
+	Database   string              `json:"database"`
+	Collection string              `json:"collection"`
+	Deprecated bool                `json:"deprecated"`
+	Leaders    map[string][]string `json:"leaders"` // channel -> leader addresses
+	Age        string              `json:"age"`
+}
+
+type metaCacheState struct {
+	Collections    []*cachedCollectionState     `json:"collections"`
+	Aliases        map[string]map[string]string `json:"aliases"`
+	ShardLeaders   []*cachedShardLeadersState   `json:"shard_leaders"`
+	NumCredentials int                          `json:"num_credentials"`
+	NumPrivileges  int                          `json:"num_privileges"`
+	NumUserRoles   int                          `json:"num_user_roles"`
+}
+
+type dmlStreamState struct {
+	CollectionID int64    `json:"collection_id"`
+	VChannels    []string `json:"vchannels"`
+	PChannels    []string `json:"pchannels"`
+}
+
+// ProxyState is the dump of the proxy internal state for diagnosis.
+type ProxyState struct {
+	NodeID      int64             `json:"node_id"`
+	StateCode   string            `json:"state_code"`
+	Scheduler   *schedulerState   `json:"scheduler,omitempty"`
+	MetaCache   *metaCacheState   `json:"meta_cache,omitempty"`
+	DmlStreams  []*dmlStreamState `json:"dml_streams,omitempty"`
+	RateLimiter *LimiterRates     `json:"rate_limiter,omitempty"`
+}
+
+func dumpTaskState(t task, now time.Time) *taskState {
+	return &taskState{
+		ID:      t.ID(),
+		Name:    t.Name(),
+		Type:    t.Type().String(),
+		BeginTs: t.BeginTs(),
+		Age:     now.Sub(tsoutil.PhysicalTime(t.BeginTs())).String(),
+	}
+}
+
+func (queue *baseTaskQueue) dumpState() *taskQueueState {
+	now := time.Now()
+	state := &taskQueueState{
+		MaxTaskNum: queue.getMaxTaskNum(),
+		Unissued:   make([]*taskState, 0),
+		Active:     make([]*taskState, 0),
+	}
+
+	queue.utLock.RLock()
+	for e := queue.unissuedTasks.Front(); e != nil; e = e.Next() {
+		state.Unissued = append(state.Unissued, dumpTaskState(e.Value.(task), now))
+	}
+	queue.utLock.RUnlock()
+
+	queue.atLock.RLock()
+	for _, t := range queue.activeTasks {
+		state.Active = append(state.Active, dumpTaskState(t, now))
+	}
+	queue.atLock.RUnlock()
+	sort.Slice(state.Active, func(i, j int) bool {
+		return state.Active[i].BeginTs < state.Active[j].BeginTs
+	})
+	return state
+}
+
+func (sched *taskScheduler) dumpState() *schedulerState {
+	return &schedulerState{
+		DdQueue: sched.ddQueue.dumpState(),
+		DmQueue: sched.dmQueue.dumpState(),
+		DqQueue: sched.dqQueue.dumpState(),
+		DcQueue: sched.dcQueue.dumpState(),
+	}
+}
+
+func (m *MetaCache) dumpState() *metaCacheState {
+	now := time.Now()
+	state := &metaCacheState{
+		Collections:  make([]*cachedCollectionState, 0),
+		Aliases:      make(map[string]map[string]string),
+		ShardLeaders: make([]*cachedShardLeadersState, 0),
+	}
+
+	m.mu.RLock()
+	for database, collections := range m.collInfo {
+		for name, info := range collections {
+			numPartitions := 0
+			if info.partInfo != nil {
+				numPartitions = len(info.partInfo.partitionInfos)
+			}
+			state.Collections = append(state.Collections, &cachedCollectionState{
+				Database:      database,
+				Collection:    name,
+				CollectionID:  info.collID,
+				NumPartitions: numPartitions,
+				Age:           now.Sub(info.updateTime).String(),
+			})
+		}
+	}
+	for database, aliases := range m.collAlias {
+		state.Aliases[database] = make(map[string]string, len(aliases))
+		for alias, collection := range aliases {
+			state.Aliases[database][alias] = collection
+		}
+	}
+	state.NumPrivileges = len(m.privilegeInfos)
+	state.NumUserRoles = len(m.userToRoles)
+	m.mu.RUnlock()
+
+	m.leaderMut.RLock()
+	for database, collections := range m.collLeader {
+		for name, leaders := range collections {
+			addresses := make(map[string][]string, len(leaders.shardLeaders))
+			for channel, nodes := range leaders.shardLeaders {
+				for _, node := range nodes {
+					addresses[channel] = append(addresses[channel], node.address)
+				}
+			}
+			state.ShardLeaders = append(state.ShardLeaders, &cachedShardLeadersState{
+				Database:   database,
+				Collection: name,
+				Deprecated: leaders.deprecated.Load(),
+				Leaders:    addresses,
+				Age:        now.Sub(leaders.updateTime).String(),
+			})
+		}
+	}
+	m.leaderMut.RUnlock()
+
+	m.credMut.RLock()
+	state.NumCredentials = len(m.credMap)
+	m.credMut.RUnlock()
+
+	sort.Slice(state.Collections, func(i, j int) bool {
+		return state.Collections[i].CollectionID < state.Collections[j].CollectionID
+	})
+	sort.Slice(state.ShardLeaders, func(i, j int) bool {
+		if state.ShardLeaders[i].Database != state.ShardLeaders[j].Database {
+			return state.ShardLeaders[i].Database < state.ShardLeaders[j].Database
+		}
+		return state.ShardLeaders[i].Collection < state.ShardLeaders[j].Collection
+	})
+	return state
+}
+
+func (mgr *singleTypeChannelsMgr) dumpState() []*dmlStreamState {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	state := make([]*dmlStreamState, 0, len(mgr.infos))
+	for collectionID, info := range mgr.infos {
+		state = append(state, &dmlStreamState{
+			CollectionID: collectionID,
+			VChannels:    info.channelInfos.vchans,
+			PChannels:    info.channelInfos.pchans,
+		})
+	}
+	sort.Slice(state, func(i, j int) bool {
+		return state[i].CollectionID < state[j].CollectionID
+	})
+	return state
+}
+
+// dumpState collects the state of the task scheduler, the meta cache, the dml streams and the rate limiter.
+func (node *Proxy) dumpState() *ProxyState {
+	state := &ProxyState{
+		NodeID:    paramtable.GetNodeID(),
+		StateCode: node.GetStateCode().String(),
+	}
+	if node.sched != nil {
+		state.Scheduler = node.sched.dumpState()
+	}
+	if cache, ok := globalMetaCache.(*MetaCache); ok {
+		state.MetaCache = cache.dumpState()
+	}
+	if chMgr, ok := node.chMgr.(*channelsMgrImpl); ok {
+		state.DmlStreams = chMgr.dmlChannelsMgr.dumpState()
+	}
+	if node.simpleLimiter != nil {
+		state.RateLimiter = node.simpleLimiter.GetRates()
+	}
+	return state
+}
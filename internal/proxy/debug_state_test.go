@@ -0,0 +1,96 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+func TestDumpState(t *testing.T) {
+	ctx := context.Background()
+	sched, err := newTaskScheduler(ctx, newMockTsoAllocator(), nil)
+	assert.NoError(t, err)
+	ts := tsoutil.ComposeTSByTime(time.Now().Add(-time.Minute), 0)
+	err = sched.ddQueue.addUnissuedTask(&createCollectionTask{
+		Condition: NewTaskCondition(ctx),
+		CreateCollectionRequest: &milvuspb.CreateCollectionRequest{
+			Base: &commonpb.MsgBase{MsgID: 1, MsgType: commonpb.MsgType_CreateCollection, Timestamp: ts},
+		},
+	})
+	assert.NoError(t, err)
+
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	globalMetaCache = &MetaCache{
+		collInfo: map[string]map[string]*collectionInfo{
+			"default": {"coll": {collID: 1, updateTime: time.Now()}},
+		},
+		collAlias: map[string]map[string]string{"default": {"alias": "coll"}},
+		collLeader: map[string]map[string]*shardLeaders{
+			"default": {"coll": {
+				idx:          atomic.NewInt64(0),
+				deprecated:   atomic.NewBool(false),
+				updateTime:   time.Now(),
+				shardLeaders: map[string][]nodeInfo{"ch1": {{nodeID: 1, address: "localhost:19530"}}},
+			}},
+		},
+		credMap: map[string]*internalpb.CredentialInfo{"root": {Username: "root", EncryptedPassword: "secret"}},
+	}
+
+	node := &Proxy{
+		sched:         sched,
+		simpleLimiter: NewSimpleLimiter(),
+		chMgr: &channelsMgrImpl{dmlChannelsMgr: &singleTypeChannelsMgr{
+			infos: map[UniqueID]streamInfos{1: {channelInfos: channelInfos{vchans: []vChan{"ch1_v0"}, pchans: []pChan{"ch1"}}}},
+		}},
+	}
+	node.UpdateStateCode(commonpb.StateCode_Healthy)
+
+	state := node.dumpState()
+	assert.Equal(t, commonpb.StateCode_Healthy.String(), state.StateCode)
+	assert.Len(t, state.Scheduler.DdQueue.Unissued, 1)
+	assert.Equal(t, int64(1), state.Scheduler.DdQueue.Unissued[0].ID)
+	assert.Empty(t, state.Scheduler.DmQueue.Unissued)
+	assert.Len(t, state.MetaCache.Collections, 1)
+	assert.Equal(t, "coll", state.MetaCache.Aliases["default"]["alias"])
+	assert.Equal(t, []string{"localhost:19530"}, state.MetaCache.ShardLeaders[0].Leaders["ch1"])
+	assert.Equal(t, 1, state.MetaCache.NumCredentials)
+	assert.Equal(t, []string{"ch1_v0"}, state.DmlStreams[0].VChannels)
+	assert.NotNil(t, state.RateLimiter)
+
+	recorder := httptest.NewRecorder()
+	node.DumpState(recorder, httptest.NewRequest(http.MethodGet, mgrDumpState, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.False(t, strings.Contains(recorder.Body.String(), "secret"))
+	decoded := &ProxyState{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), decoded))
+	assert.Equal(t, int64(1), decoded.Scheduler.DdQueue.Unissued[0].ID)
+}
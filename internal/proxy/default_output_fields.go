@@ -0,0 +1,71 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// parseDefaultOutputFields returns the default output fields in the collection properties, nil if not set.
+func parseDefaultOutputFields(properties []*commonpb.KeyValuePair) []string {
+	value, err := funcutil.GetAttrByKeyFromRepeatedKV(common.CollectionDefaultOutputFieldsKey, properties)
+	if err != nil {
+		return nil
+	}
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// validateDefaultOutputFields checks the default output fields are the fields of the collection, or the dynamic
+// fields if enabled, the empty value clears the default output fields.
+func validateDefaultOutputFields(schema *schemapb.CollectionSchema, properties []*commonpb.KeyValuePair) error {
+	for _, name := range parseDefaultOutputFields(properties) {
+		if name == "*" || schema.GetEnableDynamicField() {
+			continue
+		}
+		found := false
+		for _, field := range schema.GetFields() {
+			if field.GetName() == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return merr.WrapErrParameterInvalidMsg("default output field %s not exist in collection %s", name, schema.GetName())
+		}
+	}
+	return nil
+}
+
+// withDefaultOutputFields returns the default output fields of the collection if no output field is requested.
+func withDefaultOutputFields(outputFields []string, schema *schemaInfo) []string {
+	if len(outputFields) > 0 {
+		return outputFields
+	}
+	return parseDefaultOutputFields(schema.GetProperties())
+}
@@ -0,0 +1,59 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestDefaultOutputFields(t *testing.T) {
+	props := func(value string) []*commonpb.KeyValuePair {
+		return []*commonpb.KeyValuePair{{Key: common.CollectionDefaultOutputFieldsKey, Value: value}}
+	}
+	assert.Nil(t, parseDefaultOutputFields(nil))
+	assert.Nil(t, parseDefaultOutputFields(props(" , ")))
+	assert.Equal(t, []string{"title", "price"}, parseDefaultOutputFields(props(" title, ,price")))
+
+	schema := &schemapb.CollectionSchema{
+		Name: "coll",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "title", DataType: schemapb.DataType_VarChar},
+			{FieldID: 102, Name: "vec", DataType: schemapb.DataType_FloatVector},
+		},
+	}
+	assert.NoError(t, validateDefaultOutputFields(schema, nil))
+	assert.NoError(t, validateDefaultOutputFields(schema, props("")))
+	assert.NoError(t, validateDefaultOutputFields(schema, props("title,*")))
+	assert.ErrorIs(t, validateDefaultOutputFields(schema, props("title,price")), merr.ErrParameterInvalid)
+	schema.EnableDynamicField = true
+	assert.NoError(t, validateDefaultOutputFields(schema, props("title,price")))
+
+	schema.Properties = props("title")
+	info := newSchemaInfo(schema)
+	assert.Equal(t, []string{"title"}, withDefaultOutputFields(nil, info))
+	assert.Equal(t, []string{"vec"}, withDefaultOutputFields([]string{"vec"}, info))
+	schema.Properties = nil
+	assert.Nil(t, withDefaultOutputFields(nil, newSchemaInfo(schema)))
+}
@@ -0,0 +1,280 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"math"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	distributedRateLimitPrefix = "proxy/rate-limit"
+
+	// rateBackendTimeout is the timeout of acquiring the tokens from the backend.
+	rateBackendTimeout = 500 * time.Millisecond
+	// rateBackendMaxRetries is the max retries of acquiring the tokens on the conflicts of the proxies.
+	rateBackendMaxRetries = 5
+	// proxyCountInterval is the interval to refresh the number of the proxies.
+	proxyCountInterval = 10 * time.Second
+)
+
+// errRateAcquiring is returned while the tokens of the rate type are acquired by another request, which falls back
+// to the local limiters instead of waiting for the backend.
+var errRateAcquiring = merr.WrapErrServiceUnavailable("the tokens of the distributed rate limit are being acquired")
+
+// rateBackend is the store of the token buckets shared by the proxies.
+type rateBackend interface {
+	// acquire acquires up to n tokens from the bucket of the key in the window, of which at most limit tokens are
+	// acquired by all the proxies, and returns the acquired tokens.
+	acquire(ctx context.Context, key string, window int64, limit int64, n int64) (int64, error)
+	// proxies returns the number of the proxies sharing the buckets.
+	proxies(ctx context.Context) (int64, error)
+}
+
+// etcdRateBackend keeps the tokens acquired in a window as a counter in etcd, which is removed with the lease after
+// the window.
+type etcdRateBackend struct {
+	cli  *clientv3.Client
+	root string
+	// sessionRoot is the root of the sessions, the proxies are counted by their sessions.
+	sessionRoot string
+
+	mu          sync.Mutex
+	leaseWindow int64
+	leaseID     clientv3.LeaseID
+}
+
+func newEtcdRateBackend(cli *clientv3.Client, root string, sessionRoot string) *etcdRateBackend {
+	return &etcdRateBackend{cli: cli, root: root, sessionRoot: sessionRoot}
+}
+
+func (b *etcdRateBackend) proxies(ctx context.Context) (int64, error) {
+	resp, err := b.cli.Get(ctx, path.Join(b.sessionRoot, typeutil.ProxyRole)+"-", clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+// lease returns the lease of the counters of the window.
+func (b *etcdRateBackend) lease(ctx context.Context, window int64) (clientv3.LeaseID, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.leaseID != 0 && b.leaseWindow == window {
+		return b.leaseID, nil
+	}
+	ttl := int64(math.Ceil(Params.ProxyCfg.DistributedRateLimitWindow.GetAsDuration(time.Millisecond).Seconds())) * 2
+	if ttl < 5 {
+		ttl = 5
+	}
+	resp, err := b.cli.Grant(ctx, ttl)
+	if err != nil {
+		return 0, err
+	}
+	b.leaseWindow, b.leaseID = window, resp.ID
+	return resp.ID, nil
+}
+
+func (b *etcdRateBackend) acquire(ctx context.Context, key string, window int64, limit int64, n int64) (int64, error) {
+	counterKey := path.Join(b.root, key, strconv.FormatInt(window, 10))
+	for i := 0; i < rateBackendMaxRetries; i++ {
+		resp, err := b.cli.Get(ctx, counterKey)
+		if err != nil {
+			return 0, err
+		}
+		acquired, revision := int64(0), int64(0)
+		if len(resp.Kvs) > 0 {
+			acquired, err = strconv.ParseInt(string(resp.Kvs[0].Value), 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			revision = resp.Kvs[0].ModRevision
+		}
+		granted := n
+		if acquired+granted > limit {
+			granted = limit - acquired
+		}
+		if granted <= 0 {
+			return 0, nil
+		}
+		leaseID, err := b.lease(ctx, window)
+		if err != nil {
+			return 0, err
+		}
+		txn, err := b.cli.Txn(ctx).
+			If(clientv3.Compare(clientv3.ModRevision(counterKey), "=", revision)).
+			Then(clientv3.OpPut(counterKey, strconv.FormatInt(acquired+granted, 10), clientv3.WithLease(leaseID))).
+			Commit()
+		if err != nil {
+			return 0, err
+		}
+		if txn.Succeeded {
+			return granted, nil
+		}
+	}
+	return 0, merr.WrapErrServiceInternal("too many conflicts acquiring the tokens of " + counterKey)
+}
+
+// tokenBucket is the tokens of a rate type acquired by the proxy in a window.
+type tokenBucket struct {
+	window int64
+	tokens int64
+	// exhausted is true if the tokens of the window are all acquired by the proxies
+	exhausted bool
+	// acquiring is true while the tokens are acquired from the backend
+	acquiring bool
+}
+
+// distributedRateLimiter enforces the cluster level rates across the proxies. The proxy acquires the tokens from the
+// buckets shared by the proxies in batches, and consumes them locally. The buckets are refilled by the window, and
+// the caller falls back to the local limiters if the backend fails. The lock is never held across the backend.
+type distributedRateLimiter struct {
+	backend rateBackend
+
+	mu       sync.Mutex
+	buckets  map[internalpb.RateType]*tokenBucket
+	failedAt time.Time
+	now      func() time.Time
+
+	// proxyNum is the number of the proxies refreshed in background by the interval, at least 1.
+	proxyNum  int64
+	countedAt time.Time
+	counting  bool
+}
+
+func newDistributedRateLimiter(backend rateBackend) *distributedRateLimiter {
+	return &distributedRateLimiter{
+		backend:  backend,
+		buckets:  make(map[internalpb.RateType]*tokenBucket),
+		now:      time.Now,
+		proxyNum: 1,
+	}
+}
+
+// refreshProxyNum refreshes the number of the proxies in background if outdated, must be called with the lock.
+func (l *distributedRateLimiter) refreshProxyNum(now time.Time) {
+	if l.counting || now.Sub(l.countedAt) < proxyCountInterval {
+		return
+	}
+	l.counting = true
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), rateBackendTimeout)
+		defer cancel()
+		num, err := l.backend.proxies(ctx)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.counting = false
+		if err != nil {
+			log.RatedWarn(60, "failed to count the proxies of the distributed rate limit", zap.Error(err))
+			return
+		}
+		l.countedAt = now
+		if num > 0 {
+			l.proxyNum = num
+		}
+	}()
+}
+
+// check returns true if the n tokens of the rate type exceed the rate of the cluster, which is the share of the
+// proxy allocated by the quota center multiplied by the number of the proxies. The error is returned if the backend
+// fails, for the caller to fall back to the local limiters.
+func (l *distributedRateLimiter) check(rt internalpb.RateType, n int, share float64) (bool, error) {
+	l.mu.Lock()
+	now := l.now()
+	fallbackInterval := Params.ProxyCfg.DistributedRateLimitFallbackInterval.GetAsDuration(time.Second)
+	if !l.failedAt.IsZero() && now.Sub(l.failedAt) < fallbackInterval {
+		l.mu.Unlock()
+		return false, merr.WrapErrServiceUnavailable("the distributed rate limit backend failed recently")
+	}
+	l.refreshProxyNum(now)
+	windowDuration := Params.ProxyCfg.DistributedRateLimitWindow.GetAsDuration(time.Millisecond)
+	if windowDuration <= 0 {
+		windowDuration = time.Second
+	}
+	window := now.UnixNano() / windowDuration.Nanoseconds()
+	bucket, ok := l.buckets[rt]
+	if !ok || bucket.window != window {
+		bucket = &tokenBucket{window: window}
+		l.buckets[rt] = bucket
+	}
+	if bucket.tokens >= int64(n) {
+		bucket.tokens -= int64(n)
+		l.mu.Unlock()
+		return false, nil
+	}
+	if bucket.exhausted {
+		l.mu.Unlock()
+		return true, nil
+	}
+	if bucket.acquiring {
+		l.mu.Unlock()
+		return false, errRateAcquiring
+	}
+
+	tokens := share * float64(l.proxyNum) * windowDuration.Seconds()
+	if tokens >= math.MaxInt32 {
+		// practically unlimited
+		l.mu.Unlock()
+		return false, nil
+	}
+	limit := int64(math.Max(tokens, 1))
+	batch := int64(float64(limit) * Params.ProxyCfg.DistributedRateLimitBatchRatio.GetAsFloat())
+	if need := int64(n) - bucket.tokens; batch < need {
+		batch = need
+	}
+	bucket.acquiring = true
+	l.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), rateBackendTimeout)
+	defer cancel()
+	acquired, err := l.backend.acquire(ctx, rt.String(), window, limit, batch)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	bucket.acquiring = false
+	if err != nil {
+		l.failedAt = now
+		metrics.ProxyDistributedRateLimitFallbackCount.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10)).Inc()
+		log.Warn("failed to acquire the tokens of the distributed rate limit, fall back to the local limiters",
+			zap.String("rateType", rt.String()), zap.Duration("fallback", fallbackInterval), zap.Error(err))
+		return false, err
+	}
+	l.failedAt = time.Time{}
+	bucket.tokens += acquired
+	if acquired < batch {
+		bucket.exhausted = true
+	}
+	if bucket.tokens >= int64(n) {
+		bucket.tokens -= int64(n)
+		return false, nil
+	}
+	return true, nil
+}
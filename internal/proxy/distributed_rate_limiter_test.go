@@ -0,0 +1,237 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/util/etcd"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/ratelimitutil"
+)
+
+type memoryRateBackend struct {
+	mu       sync.Mutex
+	acquired map[string]int64
+	proxyNum int64
+	err      error
+	// block blocks the acquiring until closed if not nil
+	block chan struct{}
+}
+
+func (b *memoryRateBackend) proxies(ctx context.Context) (int64, error) {
+	return b.proxyNum, nil
+}
+
+func (b *memoryRateBackend) acquire(ctx context.Context, key string, window int64, limit int64, n int64) (int64, error) {
+	if b.block != nil {
+		<-b.block
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return 0, b.err
+	}
+	k := fmt.Sprintf("%s/%d", key, window)
+	granted := n
+	if b.acquired[k]+granted > limit {
+		granted = limit - b.acquired[k]
+	}
+	b.acquired[k] += granted
+	return granted, nil
+}
+
+// countProxies refreshes the number of the proxies of the limiter and waits for it.
+func countProxies(t *testing.T, l *distributedRateLimiter, num int64) {
+	l.mu.Lock()
+	l.refreshProxyNum(l.now())
+	l.mu.Unlock()
+	assert.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.proxyNum == num
+	}, time.Second, time.Millisecond)
+}
+
+func TestDistributedRateLimiter(t *testing.T) {
+	paramtable.Init()
+	backend := &memoryRateBackend{acquired: make(map[string]int64), proxyNum: 2}
+	now := time.Unix(100, 0)
+	newLimiter := func() *distributedRateLimiter {
+		l := newDistributedRateLimiter(backend)
+		l.now = func() time.Time { return now }
+		countProxies(t, l, 2)
+		return l
+	}
+	proxy1, proxy2 := newLimiter(), newLimiter()
+
+	// the 10 tokens of the window are the shares of the 2 proxies, shared by them and acquired 1 at a time
+	for i := 0; i < 5; i++ {
+		limited, err := proxy1.check(internalpb.RateType_DQLSearch, 1, 5)
+		assert.NoError(t, err)
+		assert.False(t, limited)
+		limited, err = proxy2.check(internalpb.RateType_DQLSearch, 1, 5)
+		assert.NoError(t, err)
+		assert.False(t, limited)
+	}
+	limited, err := proxy1.check(internalpb.RateType_DQLSearch, 1, 5)
+	assert.NoError(t, err)
+	assert.True(t, limited)
+	limited, err = proxy2.check(internalpb.RateType_DQLSearch, 1, 5)
+	assert.NoError(t, err)
+	assert.True(t, limited)
+
+	// the other rate types have their own buckets
+	limited, err = proxy1.check(internalpb.RateType_DQLQuery, 3, 5)
+	assert.NoError(t, err)
+	assert.False(t, limited)
+
+	// the buckets are refilled by the window
+	now = now.Add(time.Second)
+	limited, err = proxy1.check(internalpb.RateType_DQLSearch, 4, 5)
+	assert.NoError(t, err)
+	assert.False(t, limited)
+
+	// falls back on the failures of the backend until the fallback interval passes
+	backend.err = merr.WrapErrServiceUnavailable("etcd down")
+	now = now.Add(time.Second)
+	_, err = proxy1.check(internalpb.RateType_DQLSearch, 1, 5)
+	assert.Error(t, err)
+	backend.err = nil
+	_, err = proxy1.check(internalpb.RateType_DQLSearch, 1, 5)
+	assert.Error(t, err)
+	now = now.Add(Params.ProxyCfg.DistributedRateLimitFallbackInterval.GetAsDuration(time.Second))
+	limited, err = proxy1.check(internalpb.RateType_DQLSearch, 1, 5)
+	assert.NoError(t, err)
+	assert.False(t, limited)
+}
+
+func TestDistributedRateLimiterAcquiring(t *testing.T) {
+	paramtable.Init()
+	backend := &memoryRateBackend{acquired: make(map[string]int64), proxyNum: 1, block: make(chan struct{})}
+	l := newDistributedRateLimiter(backend)
+	now := time.Unix(100, 0)
+	l.now = func() time.Time { return now }
+	countProxies(t, l, 1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		limited, err := l.check(internalpb.RateType_DQLSearch, 1, 10)
+		assert.NoError(t, err)
+		assert.False(t, limited)
+	}()
+	assert.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		bucket, ok := l.buckets[internalpb.RateType_DQLSearch]
+		return ok && bucket.acquiring
+	}, time.Second, time.Millisecond)
+	// not blocked by the acquiring, but falls back to the local limiters
+	_, err := l.check(internalpb.RateType_DQLSearch, 1, 10)
+	assert.ErrorIs(t, err, errRateAcquiring)
+	// the other rate types are not blocked either
+	l.mu.Lock()
+	l.buckets[internalpb.RateType_DQLQuery] = &tokenBucket{window: 100, tokens: 1}
+	l.mu.Unlock()
+	limited, err := l.check(internalpb.RateType_DQLQuery, 1, 10)
+	assert.NoError(t, err)
+	assert.False(t, limited)
+
+	close(backend.block)
+	<-done
+	limited, err = l.check(internalpb.RateType_DQLSearch, 1, 10)
+	assert.NoError(t, err)
+	assert.False(t, limited)
+}
+
+func TestSimpleLimiterDistributedCheck(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(Params.QuotaConfig.QuotaAndLimitsEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.QuotaConfig.QuotaAndLimitsEnabled.Key)
+	paramtable.Get().Save(Params.QuotaConfig.DQLLimitEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.QuotaConfig.DQLLimitEnabled.Key)
+	paramtable.Get().Save(Params.ProxyCfg.DistributedRateLimitEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.DistributedRateLimitEnabled.Key)
+
+	limiter := NewSimpleLimiter()
+	// the share of the proxy allocated by the quota center is 1 of the rate 5 of the 5 proxies
+	limiter.rateLimiter.GetRootLimiters().GetLimiters().Insert(internalpb.RateType_DQLSearch, ratelimitutil.NewLimiter(1, 1))
+	backend := &memoryRateBackend{acquired: make(map[string]int64), proxyNum: 5}
+	distributed := newDistributedRateLimiter(backend)
+	now := time.Unix(100, 0)
+	distributed.now = func() time.Time { return now }
+	countProxies(t, distributed, 5)
+	limiter.SetDistributedLimiter(distributed)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, limiter.Check(0, nil, internalpb.RateType_DQLSearch, 1))
+	}
+	assert.ErrorIs(t, limiter.Check(0, nil, internalpb.RateType_DQLSearch, 1), merr.ErrServiceRateLimit)
+
+	// the requests denied by the quota center are rejected
+	limiter.rateLimiter.GetRootLimiters().GetLimiters().Insert(internalpb.RateType_DQLSearch, ratelimitutil.NewLimiter(0, 0))
+	assert.ErrorIs(t, limiter.Check(0, nil, internalpb.RateType_DQLSearch, 1), merr.ErrServiceQuotaExceeded)
+}
+
+func TestEtcdRateBackend(t *testing.T) {
+	paramtable.Init()
+	cli, err := etcd.GetEtcdClient(
+		Params.EtcdCfg.UseEmbedEtcd.GetAsBool(),
+		Params.EtcdCfg.EtcdUseSSL.GetAsBool(),
+		Params.EtcdCfg.Endpoints.GetAsStrings(),
+		Params.EtcdCfg.EtcdTLSCert.GetValue(),
+		Params.EtcdCfg.EtcdTLSKey.GetValue(),
+		Params.EtcdCfg.EtcdTLSCACert.GetValue(),
+		Params.EtcdCfg.EtcdTLSMinVersion.GetValue())
+	assert.NoError(t, err)
+	defer cli.Close()
+	root := fmt.Sprintf("/test/proxy/rate-limit/%d", time.Now().UnixNano())
+	defer cli.Delete(context.Background(), root, clientv3.WithPrefix())
+
+	ctx := context.Background()
+	sessionRoot := root + "/session"
+	backend1, backend2 := newEtcdRateBackend(cli, root, sessionRoot), newEtcdRateBackend(cli, root, sessionRoot)
+	acquired, err := backend1.acquire(ctx, "DQLSearch", 1, 10, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), acquired)
+	acquired, err = backend2.acquire(ctx, "DQLSearch", 1, 10, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), acquired)
+	acquired, err = backend1.acquire(ctx, "DQLSearch", 1, 10, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), acquired)
+	acquired, err = backend1.acquire(ctx, "DQLSearch", 2, 10, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), acquired)
+
+	for _, key := range []string{"proxy-1", "proxy-2", "querynode-3"} {
+		_, err = cli.Put(ctx, sessionRoot+"/"+key, "{}")
+		assert.NoError(t, err)
+	}
+	num, err := backend1.proxies(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), num)
+}
@@ -0,0 +1,150 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"strconv"
+	"strings"
+)
+
+// the scanner of the filter expressions shared by the convenience functions expanded by the proxy, which are
+// replaced outside the string literals only.
+
+// isExprIdentChar returns whether the character is of an identifier of the expression.
+func isExprIdentChar(c byte) bool {
+	return c == '_' || c == '$' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// skipExprSpaces returns the index of the first non-space character of the expression from i.
+func skipExprSpaces(expr string, i int) int {
+	for i < len(expr) && strings.IndexByte(" \t\r\n", expr[i]) >= 0 {
+		i++
+	}
+	return i
+}
+
+// stringLiteralEnd returns the index after the string literal starting at i, and whether it is terminated, the
+// unterminated one extends to the end of the expression.
+func stringLiteralEnd(expr string, i int) (int, bool) {
+	quote := expr[i]
+	for j := i + 1; j < len(expr); j++ {
+		switch expr[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1, true
+		}
+	}
+	return len(expr), false
+}
+
+// readStringLiteral returns the unquoted string literal starting at i and the index after it, the escapes are
+// unquoted as the plan parser does for both the double and the single quoted strings.
+func readStringLiteral(expr string, i int) (string, int, bool) {
+	end, ok := stringLiteralEnd(expr, i)
+	if !ok {
+		return "", end, false
+	}
+	literal := expr[i:end]
+	if expr[i] == '\'' {
+		// the single quoted strings of the expressions are not go char literals, requote them with double quotes
+		var sb strings.Builder
+		sb.WriteByte('"')
+		for j := 1; j < len(literal)-1; j++ {
+			switch {
+			case literal[j] == '\\' && literal[j+1] == '\'':
+				sb.WriteByte('\'')
+				j++
+			case literal[j] == '\\':
+				sb.WriteString(literal[j : j+2])
+				j++
+			case literal[j] == '"':
+				sb.WriteString(`\"`)
+			default:
+				sb.WriteByte(literal[j])
+			}
+		}
+		sb.WriteByte('"')
+		literal = sb.String()
+	}
+	unquoted, err := strconv.Unquote(literal)
+	return unquoted, end, err == nil
+}
+
+// splitStringLiterals calls fn with the parts of the expression in order, literal is set for the string literals
+// including their quotes.
+func splitStringLiterals(expr string, fn func(part string, literal bool)) {
+	start := 0
+	for i := 0; i < len(expr); i++ {
+		if expr[i] != '"' && expr[i] != '\'' {
+			continue
+		}
+		if start < i {
+			fn(expr[start:i], false)
+		}
+		end, _ := stringLiteralEnd(expr, i)
+		fn(expr[i:end], true)
+		start, i = end, end-1
+	}
+	if start < len(expr) {
+		fn(expr[start:], false)
+	}
+}
+
+// replaceExprFunctions replaces the calls of the functions outside the string literals of the expression. The
+// replace is called with the name of the function and the index after its open parenthesis, and returns the
+// replacement of the call and the index after its close parenthesis. The expression is returned as is if no call is
+// replaced.
+func replaceExprFunctions(expr string, isFunc func(name string) bool, replace func(name string, args int) (string, int, error)) (string, error) {
+	var sb strings.Builder
+	replaced := false
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		if c == '"' || c == '\'' {
+			end, _ := stringLiteralEnd(expr, i)
+			sb.WriteString(expr[i:end])
+			i = end
+			continue
+		}
+		if !isExprIdentChar(c) {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+		start := i
+		for i < len(expr) && isExprIdentChar(expr[i]) {
+			i++
+		}
+		name := expr[start:i]
+		open := skipExprSpaces(expr, i)
+		if !isFunc(name) || open >= len(expr) || expr[open] != '(' {
+			sb.WriteString(name)
+			continue
+		}
+		value, end, err := replace(name, open+1)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(value)
+		i = end
+		replaced = true
+	}
+	if !replaced {
+		return expr, nil
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,88 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadStringLiteral(t *testing.T) {
+	cases := []struct {
+		expr  string
+		value string
+		end   int
+		ok    bool
+	}{
+		{`"abc" and`, "abc", 5, true},
+		{`"a\"b\\c"`, `a"b\c`, 9, true},
+		{`'it\'s "x"'`, `it's "x"`, 11, true},
+		{`'a\\'`, `a\`, 5, true},
+		{`"abc`, "", 4, false},
+	}
+	for _, c := range cases {
+		value, end, ok := readStringLiteral(c.expr, 0)
+		assert.Equal(t, c.ok, ok, c.expr)
+		assert.Equal(t, c.end, end, c.expr)
+		assert.Equal(t, c.value, value, c.expr)
+	}
+}
+
+func TestSplitStringLiterals(t *testing.T) {
+	var parts []string
+	var literals []bool
+	splitStringLiterals(`a == "x'y" or b == 'z\'' and c`, func(part string, literal bool) {
+		parts = append(parts, part)
+		literals = append(literals, literal)
+	})
+	assert.Equal(t, []string{`a == `, `"x'y"`, ` or b == `, `'z\''`, ` and c`}, parts)
+	assert.Equal(t, []bool{false, true, false, true, false}, literals)
+
+	parts = nil
+	splitStringLiterals(`a == "unterminated`, func(part string, literal bool) {
+		parts = append(parts, part)
+	})
+	assert.Equal(t, []string{`a == `, `"unterminated`}, parts)
+}
+
+func TestReplaceExprFunctions(t *testing.T) {
+	isFunc := func(name string) bool { return name == "upper" }
+	upper := func(expr string) (string, error) {
+		return replaceExprFunctions(expr, isFunc, func(_ string, j int) (string, int, error) {
+			value, end, ok := readStringLiteral(expr, skipExprSpaces(expr, j))
+			end = skipExprSpaces(expr, end)
+			if !ok || end >= len(expr) || expr[end] != ')' {
+				return "", 0, assert.AnError
+			}
+			return `"` + strings.ToUpper(value) + `"`, end + 1, nil
+		})
+	}
+
+	expr, err := upper(`a == upper ( "x" ) or b == "upper('y')" or upper_b == upper('z')`)
+	assert.NoError(t, err)
+	assert.Equal(t, `a == "X" or b == "upper('y')" or upper_b == "Z"`, expr)
+
+	expr = `upper == 1`
+	replaced, err := upper(expr)
+	assert.NoError(t, err)
+	assert.Equal(t, expr, replaced)
+
+	_, err = upper(`a == upper("x"`)
+	assert.Error(t, err)
+}
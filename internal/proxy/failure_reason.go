@@ -0,0 +1,128 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"path"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// The classes of the failure reasons, the label values of the failure counter.
+const (
+	failureRateLimited        = "rate_limited"
+	failureNotFound           = "not_found"
+	failureTimeout            = "timeout"
+	failureCanceled           = "canceled"
+	failureInvalidParameter   = "invalid_parameter"
+	failurePermissionDenied   = "permission_denied"
+	failureServiceUnavailable = "service_unavailable"
+	failureOther              = "other"
+)
+
+var notFoundErrors = []error{
+	merr.ErrDatabaseNotFound,
+	merr.ErrCollectionNotFound,
+	merr.ErrPartitionNotFound,
+	merr.ErrAliasNotFound,
+	merr.ErrFieldNotFound,
+	merr.ErrIndexNotFound,
+	merr.ErrResourceGroupNotFound,
+	merr.ErrReplicaNotFound,
+	merr.ErrChannelNotFound,
+	merr.ErrSegmentNotFound,
+	merr.ErrNodeNotFound,
+}
+
+// failureReason returns the class of the failure reason of the error, the errors of the grpc status are classified
+// by their codes.
+func failureReason(err error) string {
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+		switch s.Code() {
+		case codes.ResourceExhausted:
+			return failureRateLimited
+		case codes.NotFound:
+			return failureNotFound
+		case codes.DeadlineExceeded:
+			return failureTimeout
+		case codes.Canceled:
+			return failureCanceled
+		case codes.InvalidArgument:
+			return failureInvalidParameter
+		case codes.Unauthenticated, codes.PermissionDenied:
+			return failurePermissionDenied
+		case codes.Unavailable:
+			return failureServiceUnavailable
+		default:
+			return failureOther
+		}
+	}
+
+	switch code := merr.Code(err); {
+	case code == merr.TimeoutCode:
+		return failureTimeout
+	case code == merr.CanceledCode:
+		return failureCanceled
+	case errors.Is(err, merr.ErrServiceRateLimit),
+		errors.Is(err, merr.ErrServiceQuotaExceeded),
+		errors.Is(err, merr.ErrServiceMemoryLimitExceeded),
+		errors.Is(err, merr.ErrServiceDiskLimitExceeded):
+		return failureRateLimited
+	case errors.Is(err, merr.ErrParameterInvalid),
+		errors.Is(err, merr.ErrParameterMissing),
+		errors.Is(err, merr.ErrParameterTooLarge):
+		return failureInvalidParameter
+	case errors.Is(err, merr.ErrPrivilegeNotAuthenticated),
+		errors.Is(err, merr.ErrPrivilegeNotPermitted):
+		return failurePermissionDenied
+	case errors.Is(err, merr.ErrServiceNotReady),
+		errors.Is(err, merr.ErrServiceUnavailable),
+		errors.Is(err, merr.ErrServiceCircuitBreakerOpen):
+		return failureServiceUnavailable
+	}
+	for _, target := range notFoundErrors {
+		if errors.Is(err, target) {
+			return failureNotFound
+		}
+	}
+	return failureOther
+}
+
+// FailureReasonInterceptor returns a new unary server interceptor that counts the failed requests by the class of
+// the failure reason, either the error or the status of the response.
+func FailureReasonInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if failure := merr.CheckRPCCall(resp, err); failure != nil {
+			metrics.ProxyFunctionFailureCount.WithLabelValues(
+				strconv.FormatInt(paramtable.GetNodeID(), 10),
+				path.Base(info.FullMethod),
+				failureReason(failure),
+			).Inc()
+		}
+		return resp, err
+	}
+}
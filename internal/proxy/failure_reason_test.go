@@ -0,0 +1,86 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestFailureReason(t *testing.T) {
+	cases := []struct {
+		err    error
+		reason string
+	}{
+		{merr.WrapErrServiceRateLimit(100), failureRateLimited},
+		{merr.WrapErrServiceQuotaExceeded("disk quota"), failureRateLimited},
+		{merr.WrapErrCollectionNotFound("coll"), failureNotFound},
+		{merr.WrapErrDatabaseNotFound("db"), failureNotFound},
+		{context.DeadlineExceeded, failureTimeout},
+		{errors.Wrap(context.Canceled, "search"), failureCanceled},
+		{merr.WrapErrParameterInvalidMsg("bad"), failureInvalidParameter},
+		{merr.WrapErrPrivilegeNotPermitted("no"), failurePermissionDenied},
+		{merr.WrapErrServiceNotReady("proxy", 1, "init"), failureServiceUnavailable},
+		{errors.New("unknown"), failureOther},
+		{status.Error(codes.Unauthenticated, "auth"), failurePermissionDenied},
+		{status.Error(codes.ResourceExhausted, "limited"), failureRateLimited},
+		{status.Error(codes.Internal, "internal"), failureOther},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.reason, failureReason(c.err), c.err.Error())
+		// the errors returned in the response status
+		if _, ok := status.FromError(c.err); !ok {
+			assert.Equal(t, c.reason, failureReason(merr.Error(merr.Status(c.err))), c.err.Error())
+		}
+	}
+}
+
+func TestFailureReasonInterceptor(t *testing.T) {
+	paramtable.Init()
+	nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	info := &grpc.UnaryServerInfo{FullMethod: "/milvus.proto.milvus.MilvusService/Search"}
+	counter := func(reason string) float64 {
+		return testutil.ToFloat64(metrics.ProxyFunctionFailureCount.WithLabelValues(nodeID, "Search", reason))
+	}
+	limited, notFound := counter(failureRateLimited), counter(failureNotFound)
+
+	interceptor := FailureReasonInterceptor()
+	respond := func(resp any, err error) {
+		_, _ = interceptor(context.Background(), &milvuspb.SearchRequest{}, info, func(ctx context.Context, req any) (any, error) {
+			return resp, err
+		})
+	}
+	respond(&milvuspb.SearchResults{Status: merr.Status(merr.WrapErrServiceRateLimit(10))}, nil)
+	respond(&milvuspb.SearchResults{Status: merr.Status(merr.WrapErrCollectionNotFound("coll"))}, nil)
+	respond(&milvuspb.SearchResults{Status: merr.Success()}, nil)
+	respond(nil, status.Error(codes.NotFound, "not found"))
+	assert.Equal(t, limited+1, counter(failureRateLimited))
+	assert.Equal(t, notFound+2, counter(failureNotFound))
+}
@@ -0,0 +1,826 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/proto/rootcoordpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// anyMethod scripts the behaviors of all the methods of a fake coordinator.
+const anyMethod = ""
+
+// coordScript scripts the behaviors of the calls to a fake coordinator, which stay the same across the runs:
+// the latency of a method is injected into every call to it, the errors of a method fail its calls in order, a nil
+// one lets the call through, and during a leader change all the calls fail as the coordinator is not ready.
+type coordScript struct {
+	mu sync.Mutex
+
+	role        string
+	leaderID    int64
+	unavailable int
+
+	latencies map[string]time.Duration
+	errs      map[string][]error
+	calls     map[string]int
+}
+
+func newCoordScript(role string, leaderID int64) *coordScript {
+	return &coordScript{
+		role:      role,
+		leaderID:  leaderID,
+		latencies: make(map[string]time.Duration),
+		errs:      make(map[string][]error),
+		calls:     make(map[string]int),
+	}
+}
+
+// setLatency injects the latency into the calls to the method, or to all the methods by anyMethod.
+func (s *coordScript) setLatency(method string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencies[method] = latency
+}
+
+// failNext fails the next calls to the method, or to any method by anyMethod, with the errors in order.
+func (s *coordScript) failNext(method string, errs ...error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errs[method] = append(s.errs[method], errs...)
+}
+
+// changeLeader simulates a new leader elected after the given number of calls failed, returns the new leader id.
+func (s *coordScript) changeLeader(unavailableCalls int) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leaderID++
+	s.unavailable = unavailableCalls
+	return s.leaderID
+}
+
+func (s *coordScript) getLeaderID() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leaderID
+}
+
+// callCount returns the number of the calls to the method, or to all the methods by anyMethod.
+func (s *coordScript) callCount(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if method == anyMethod {
+		count := 0
+		for _, n := range s.calls {
+			count += n
+		}
+		return count
+	}
+	return s.calls[method]
+}
+
+// reset clears the scripted behaviors and the call counts, the leader is kept.
+func (s *coordScript) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unavailable = 0
+	s.latencies = make(map[string]time.Duration)
+	s.errs = make(map[string][]error)
+	s.calls = make(map[string]int)
+}
+
+func (s *coordScript) popErr(method string) error {
+	for _, key := range []string{method, anyMethod} {
+		if errs := s.errs[key]; len(errs) > 0 {
+			s.errs[key] = errs[1:]
+			return errs[0]
+		}
+	}
+	return nil
+}
+
+// call applies the scripted behaviors to a call to the method, the call fails if an error is returned.
+func (s *coordScript) call(ctx context.Context, method string) error {
+	s.mu.Lock()
+	s.calls[method]++
+	latency, ok := s.latencies[method]
+	if !ok {
+		latency = s.latencies[anyMethod]
+	}
+	var err error
+	if s.unavailable > 0 {
+		s.unavailable--
+		err = merr.WrapErrServiceNotReady(s.role, s.leaderID, commonpb.StateCode_Initializing.String())
+	} else {
+		err = s.popErr(method)
+	}
+	s.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// fakeRootCoord applies the script to the calls of the proxy to the RootCoordMock.
+type fakeRootCoord struct {
+	*RootCoordMock
+	script *coordScript
+}
+
+func newFakeRootCoord() *fakeRootCoord {
+	rc := NewRootCoordMock()
+	return &fakeRootCoord{
+		RootCoordMock: rc,
+		script:        newCoordScript(typeutil.RootCoordRole, rc.nodeID),
+	}
+}
+
+func (c *fakeRootCoord) GetComponentStates(ctx context.Context, req *milvuspb.GetComponentStatesRequest, opts ...grpc.CallOption) (*milvuspb.ComponentStates, error) {
+	if err := c.script.call(ctx, "GetComponentStates"); err != nil {
+		return nil, err
+	}
+	resp, err := c.RootCoordMock.GetComponentStates(ctx, req, opts...)
+	if resp.GetState() != nil {
+		resp.State.NodeID = c.script.getLeaderID()
+	}
+	return resp, err
+}
+
+// fakeQueryCoord applies the script to the calls of the proxy to the QueryCoordMock.
+type fakeQueryCoord struct {
+	*QueryCoordMock
+	script *coordScript
+}
+
+func newFakeQueryCoord() *fakeQueryCoord {
+	qc := NewQueryCoordMock()
+	return &fakeQueryCoord{
+		QueryCoordMock: qc,
+		script:         newCoordScript(typeutil.QueryCoordRole, qc.nodeID),
+	}
+}
+
+func (c *fakeQueryCoord) GetComponentStates(ctx context.Context, req *milvuspb.GetComponentStatesRequest, opts ...grpc.CallOption) (*milvuspb.ComponentStates, error) {
+	if err := c.script.call(ctx, "GetComponentStates"); err != nil {
+		return nil, err
+	}
+	resp, err := c.QueryCoordMock.GetComponentStates(ctx, req, opts...)
+	if resp.GetState() != nil {
+		resp.State.NodeID = c.script.getLeaderID()
+	}
+	return resp, err
+}
+
+// fakeDataCoord applies the script to the calls of the proxy to the DataCoordMock, the index methods included.
+type fakeDataCoord struct {
+	*DataCoordMock
+	script *coordScript
+}
+
+func newFakeDataCoord() *fakeDataCoord {
+	dc := NewDataCoordMock()
+	return &fakeDataCoord{
+		DataCoordMock: dc,
+		script:        newCoordScript(typeutil.DataCoordRole, dc.nodeID),
+	}
+}
+
+func (c *fakeDataCoord) GetComponentStates(ctx context.Context, req *milvuspb.GetComponentStatesRequest, opts ...grpc.CallOption) (*milvuspb.ComponentStates, error) {
+	if err := c.script.call(ctx, "GetComponentStates"); err != nil {
+		return nil, err
+	}
+	resp, err := c.DataCoordMock.GetComponentStates(ctx, req, opts...)
+	if resp.GetState() != nil {
+		resp.State.NodeID = c.script.getLeaderID()
+	}
+	return resp, err
+}
+
+// AssignSegmentID assigns the rows to segment 1 of the channel, the assignments never expire as the harness has no
+// time tick to expire them by.
+func (c *fakeDataCoord) AssignSegmentID(ctx context.Context, req *datapb.AssignSegmentIDRequest, opts ...grpc.CallOption) (*datapb.AssignSegmentIDResponse, error) {
+	if err := c.script.call(ctx, "AssignSegmentID"); err != nil {
+		return nil, err
+	}
+	resp := &datapb.AssignSegmentIDResponse{Status: merr.Success()}
+	for _, r := range req.GetSegmentIDRequests() {
+		resp.SegIDAssignments = append(resp.SegIDAssignments, &datapb.SegmentIDAssignment{
+			SegID:        1,
+			ChannelName:  r.GetChannelName(),
+			Count:        r.GetCount(),
+			CollectionID: r.GetCollectionID(),
+			PartitionID:  r.GetPartitionID(),
+			ExpireTime:   math.MaxUint64,
+			Status:       merr.Success(),
+		})
+	}
+	return resp, nil
+}
+
+// fakeQueryNode serves the searches and the queries of the channels it leads from its rows, which only have the
+// int64 primary keys. A search hits the first topk rows of every query with descending scores, and a query returns
+// all the rows for every output field. The calls to the channels it doesn't lead fail as the delegator is not found,
+// like a query node after the shard leader moved away.
+type fakeQueryNode struct {
+	*mocks.MockQueryNodeClient
+	nodeID int64
+	script *coordScript
+
+	mu       sync.Mutex
+	channels typeutil.Set[string]
+	rows     []int64
+}
+
+func newFakeQueryNode(nodeID int64) *fakeQueryNode {
+	return &fakeQueryNode{
+		MockQueryNodeClient: &mocks.MockQueryNodeClient{},
+		nodeID:              nodeID,
+		script:              newCoordScript(typeutil.QueryNodeRole, nodeID),
+		channels:            typeutil.NewSet[string](),
+	}
+}
+
+// lead makes the node the delegator of the channels.
+func (n *fakeQueryNode) lead(channels ...string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.channels.Insert(channels...)
+}
+
+// resign makes the node no longer the delegator of the channels.
+func (n *fakeQueryNode) resign(channels ...string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.channels.Remove(channels...)
+}
+
+// setRows sets the primary keys of the rows the node serves, in ascending order as the query results are merged by.
+func (n *fakeQueryNode) setRows(pks ...int64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.rows = append([]int64{}, pks...)
+	sort.Slice(n.rows, func(i, j int) bool { return n.rows[i] < n.rows[j] })
+}
+
+// serve returns the rows if the node leads all the channels.
+func (n *fakeQueryNode) serve(channels []string) ([]int64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, channel := range channels {
+		if !n.channels.Contain(channel) {
+			return nil, merr.WrapErrChannelNotFound(channel, fmt.Sprintf("node %d is not the shard leader", n.nodeID))
+		}
+	}
+	return n.rows, nil
+}
+
+func (n *fakeQueryNode) Close() error {
+	return nil
+}
+
+func (n *fakeQueryNode) GetComponentStates(ctx context.Context, req *milvuspb.GetComponentStatesRequest, opts ...grpc.CallOption) (*milvuspb.ComponentStates, error) {
+	if err := n.script.call(ctx, "GetComponentStates"); err != nil {
+		return nil, err
+	}
+	return &milvuspb.ComponentStates{
+		State: &milvuspb.ComponentInfo{
+			NodeID:    n.nodeID,
+			Role:      typeutil.QueryNodeRole,
+			StateCode: commonpb.StateCode_Healthy,
+		},
+		Status: merr.Success(),
+	}, nil
+}
+
+func (n *fakeQueryNode) Search(ctx context.Context, req *querypb.SearchRequest, opts ...grpc.CallOption) (*internalpb.SearchResults, error) {
+	if err := n.script.call(ctx, "Search"); err != nil {
+		return nil, err
+	}
+	rows, err := n.serve(req.GetDmlChannels())
+	if err != nil {
+		return &internalpb.SearchResults{Status: merr.Status(err)}, nil
+	}
+
+	nq, topk := req.GetReq().GetNq(), req.GetReq().GetTopk()
+	hits := rows
+	if int64(len(hits)) > topk {
+		hits = hits[:topk]
+	}
+	data := &schemapb.SearchResultData{
+		NumQueries: nq,
+		TopK:       topk,
+		Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{}}},
+	}
+	for i := int64(0); i < nq; i++ {
+		data.Ids.GetIntId().Data = append(data.Ids.GetIntId().Data, hits...)
+		for j := range hits {
+			data.Scores = append(data.Scores, float32(len(hits)-j))
+		}
+		data.Topks = append(data.Topks, int64(len(hits)))
+	}
+	blob, err := proto.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &internalpb.SearchResults{
+		Status:         merr.Success(),
+		MetricType:     req.GetReq().GetMetricType(),
+		NumQueries:     nq,
+		TopK:           topk,
+		SlicedBlob:     blob,
+		SlicedNumCount: 1,
+	}, nil
+}
+
+func (n *fakeQueryNode) Query(ctx context.Context, req *querypb.QueryRequest, opts ...grpc.CallOption) (*internalpb.RetrieveResults, error) {
+	if err := n.script.call(ctx, "Query"); err != nil {
+		return nil, err
+	}
+	rows, err := n.serve(req.GetDmlChannels())
+	if err != nil {
+		return &internalpb.RetrieveResults{Status: merr.Status(err)}, nil
+	}
+
+	resp := &internalpb.RetrieveResults{
+		Status: merr.Success(),
+		Ids:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: rows}}},
+	}
+	for _, fieldID := range req.GetReq().GetOutputFieldsId() {
+		resp.FieldsData = append(resp.FieldsData, &schemapb.FieldData{
+			Type:    schemapb.DataType_Int64,
+			FieldId: fieldID,
+			Field: &schemapb.FieldData_Scalars{
+				Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: rows}},
+				},
+			},
+		})
+	}
+	return resp, nil
+}
+
+func (c *fakeRootCoord) AllocID(ctx context.Context, req *rootcoordpb.AllocIDRequest, opts ...grpc.CallOption) (*rootcoordpb.AllocIDResponse, error) {
+	if err := c.script.call(ctx, "AllocID"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.AllocID(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) AllocTimestamp(ctx context.Context, req *rootcoordpb.AllocTimestampRequest, opts ...grpc.CallOption) (*rootcoordpb.AllocTimestampResponse, error) {
+	if err := c.script.call(ctx, "AllocTimestamp"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.AllocTimestamp(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) AlterAlias(ctx context.Context, req *milvuspb.AlterAliasRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "AlterAlias"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.AlterAlias(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) AlterCollection(ctx context.Context, req *milvuspb.AlterCollectionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "AlterCollection"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.AlterCollection(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) AlterDatabase(ctx context.Context, req *rootcoordpb.AlterDatabaseRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "AlterDatabase"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.AlterDatabase(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) CheckHealth(ctx context.Context, req *milvuspb.CheckHealthRequest, opts ...grpc.CallOption) (*milvuspb.CheckHealthResponse, error) {
+	if err := c.script.call(ctx, "CheckHealth"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.CheckHealth(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) CreateAlias(ctx context.Context, req *milvuspb.CreateAliasRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "CreateAlias"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.CreateAlias(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) CreateCollection(ctx context.Context, req *milvuspb.CreateCollectionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "CreateCollection"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.CreateCollection(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) CreateCredential(ctx context.Context, req *internalpb.CredentialInfo, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "CreateCredential"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.CreateCredential(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) CreateDatabase(ctx context.Context, req *milvuspb.CreateDatabaseRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "CreateDatabase"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.CreateDatabase(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) CreatePartition(ctx context.Context, req *milvuspb.CreatePartitionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "CreatePartition"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.CreatePartition(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) CreateRole(ctx context.Context, req *milvuspb.CreateRoleRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "CreateRole"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.CreateRole(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) DeleteCredential(ctx context.Context, req *milvuspb.DeleteCredentialRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "DeleteCredential"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.DeleteCredential(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) DescribeAlias(ctx context.Context, req *milvuspb.DescribeAliasRequest, opts ...grpc.CallOption) (*milvuspb.DescribeAliasResponse, error) {
+	if err := c.script.call(ctx, "DescribeAlias"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.DescribeAlias(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) DescribeCollection(ctx context.Context, req *milvuspb.DescribeCollectionRequest, opts ...grpc.CallOption) (*milvuspb.DescribeCollectionResponse, error) {
+	if err := c.script.call(ctx, "DescribeCollection"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.DescribeCollection(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) DescribeCollectionInternal(ctx context.Context, req *milvuspb.DescribeCollectionRequest, opts ...grpc.CallOption) (*milvuspb.DescribeCollectionResponse, error) {
+	if err := c.script.call(ctx, "DescribeCollectionInternal"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.DescribeCollectionInternal(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) DescribeDatabase(ctx context.Context, req *rootcoordpb.DescribeDatabaseRequest, opts ...grpc.CallOption) (*rootcoordpb.DescribeDatabaseResponse, error) {
+	if err := c.script.call(ctx, "DescribeDatabase"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.DescribeDatabase(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) DropAlias(ctx context.Context, req *milvuspb.DropAliasRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "DropAlias"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.DropAlias(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) DropCollection(ctx context.Context, req *milvuspb.DropCollectionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "DropCollection"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.DropCollection(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) DropDatabase(ctx context.Context, req *milvuspb.DropDatabaseRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "DropDatabase"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.DropDatabase(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) DropPartition(ctx context.Context, req *milvuspb.DropPartitionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "DropPartition"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.DropPartition(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) DropRole(ctx context.Context, req *milvuspb.DropRoleRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "DropRole"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.DropRole(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) GetCredential(ctx context.Context, req *rootcoordpb.GetCredentialRequest, opts ...grpc.CallOption) (*rootcoordpb.GetCredentialResponse, error) {
+	if err := c.script.call(ctx, "GetCredential"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.GetCredential(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) GetMetrics(ctx context.Context, req *milvuspb.GetMetricsRequest, opts ...grpc.CallOption) (*milvuspb.GetMetricsResponse, error) {
+	if err := c.script.call(ctx, "GetMetrics"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.GetMetrics(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) HasCollection(ctx context.Context, req *milvuspb.HasCollectionRequest, opts ...grpc.CallOption) (*milvuspb.BoolResponse, error) {
+	if err := c.script.call(ctx, "HasCollection"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.HasCollection(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) HasPartition(ctx context.Context, req *milvuspb.HasPartitionRequest, opts ...grpc.CallOption) (*milvuspb.BoolResponse, error) {
+	if err := c.script.call(ctx, "HasPartition"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.HasPartition(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) ListAliases(ctx context.Context, req *milvuspb.ListAliasesRequest, opts ...grpc.CallOption) (*milvuspb.ListAliasesResponse, error) {
+	if err := c.script.call(ctx, "ListAliases"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.ListAliases(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) ListCredUsers(ctx context.Context, req *milvuspb.ListCredUsersRequest, opts ...grpc.CallOption) (*milvuspb.ListCredUsersResponse, error) {
+	if err := c.script.call(ctx, "ListCredUsers"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.ListCredUsers(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) ListDatabases(ctx context.Context, req *milvuspb.ListDatabasesRequest, opts ...grpc.CallOption) (*milvuspb.ListDatabasesResponse, error) {
+	if err := c.script.call(ctx, "ListDatabases"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.ListDatabases(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) ListPolicy(ctx context.Context, req *internalpb.ListPolicyRequest, opts ...grpc.CallOption) (*internalpb.ListPolicyResponse, error) {
+	if err := c.script.call(ctx, "ListPolicy"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.ListPolicy(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) OperatePrivilege(ctx context.Context, req *milvuspb.OperatePrivilegeRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "OperatePrivilege"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.OperatePrivilege(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) OperateUserRole(ctx context.Context, req *milvuspb.OperateUserRoleRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "OperateUserRole"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.OperateUserRole(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) RenameCollection(ctx context.Context, req *milvuspb.RenameCollectionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "RenameCollection"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.RenameCollection(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) SelectGrant(ctx context.Context, req *milvuspb.SelectGrantRequest, opts ...grpc.CallOption) (*milvuspb.SelectGrantResponse, error) {
+	if err := c.script.call(ctx, "SelectGrant"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.SelectGrant(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) SelectRole(ctx context.Context, req *milvuspb.SelectRoleRequest, opts ...grpc.CallOption) (*milvuspb.SelectRoleResponse, error) {
+	if err := c.script.call(ctx, "SelectRole"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.SelectRole(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) SelectUser(ctx context.Context, req *milvuspb.SelectUserRequest, opts ...grpc.CallOption) (*milvuspb.SelectUserResponse, error) {
+	if err := c.script.call(ctx, "SelectUser"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.SelectUser(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) ShowCollections(ctx context.Context, req *milvuspb.ShowCollectionsRequest, opts ...grpc.CallOption) (*milvuspb.ShowCollectionsResponse, error) {
+	if err := c.script.call(ctx, "ShowCollections"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.ShowCollections(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) ShowPartitions(ctx context.Context, req *milvuspb.ShowPartitionsRequest, opts ...grpc.CallOption) (*milvuspb.ShowPartitionsResponse, error) {
+	if err := c.script.call(ctx, "ShowPartitions"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.ShowPartitions(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) ShowPartitionsInternal(ctx context.Context, req *milvuspb.ShowPartitionsRequest, opts ...grpc.CallOption) (*milvuspb.ShowPartitionsResponse, error) {
+	if err := c.script.call(ctx, "ShowPartitionsInternal"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.ShowPartitionsInternal(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) UpdateChannelTimeTick(ctx context.Context, req *internalpb.ChannelTimeTickMsg, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "UpdateChannelTimeTick"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.UpdateChannelTimeTick(ctx, req, opts...)
+}
+
+func (c *fakeRootCoord) UpdateCredential(ctx context.Context, req *internalpb.CredentialInfo, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "UpdateCredential"); err != nil {
+		return nil, err
+	}
+	return c.RootCoordMock.UpdateCredential(ctx, req, opts...)
+}
+
+func (c *fakeQueryCoord) CheckHealth(ctx context.Context, req *milvuspb.CheckHealthRequest, opts ...grpc.CallOption) (*milvuspb.CheckHealthResponse, error) {
+	if err := c.script.call(ctx, "CheckHealth"); err != nil {
+		return nil, err
+	}
+	return c.QueryCoordMock.CheckHealth(ctx, req, opts...)
+}
+
+func (c *fakeQueryCoord) GetReplicas(ctx context.Context, req *milvuspb.GetReplicasRequest, opts ...grpc.CallOption) (*milvuspb.GetReplicasResponse, error) {
+	if err := c.script.call(ctx, "GetReplicas"); err != nil {
+		return nil, err
+	}
+	return c.QueryCoordMock.GetReplicas(ctx, req, opts...)
+}
+
+func (c *fakeQueryCoord) GetShardLeaders(ctx context.Context, req *querypb.GetShardLeadersRequest, opts ...grpc.CallOption) (*querypb.GetShardLeadersResponse, error) {
+	if err := c.script.call(ctx, "GetShardLeaders"); err != nil {
+		return nil, err
+	}
+	return c.QueryCoordMock.GetShardLeaders(ctx, req, opts...)
+}
+
+func (c *fakeQueryCoord) LoadCollection(ctx context.Context, req *querypb.LoadCollectionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "LoadCollection"); err != nil {
+		return nil, err
+	}
+	return c.QueryCoordMock.LoadCollection(ctx, req, opts...)
+}
+
+func (c *fakeQueryCoord) LoadPartitions(ctx context.Context, req *querypb.LoadPartitionsRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "LoadPartitions"); err != nil {
+		return nil, err
+	}
+	return c.QueryCoordMock.LoadPartitions(ctx, req, opts...)
+}
+
+func (c *fakeQueryCoord) ReleaseCollection(ctx context.Context, req *querypb.ReleaseCollectionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "ReleaseCollection"); err != nil {
+		return nil, err
+	}
+	return c.QueryCoordMock.ReleaseCollection(ctx, req, opts...)
+}
+
+func (c *fakeQueryCoord) ReleasePartitions(ctx context.Context, req *querypb.ReleasePartitionsRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "ReleasePartitions"); err != nil {
+		return nil, err
+	}
+	return c.QueryCoordMock.ReleasePartitions(ctx, req, opts...)
+}
+
+func (c *fakeQueryCoord) ShowCollections(ctx context.Context, req *querypb.ShowCollectionsRequest, opts ...grpc.CallOption) (*querypb.ShowCollectionsResponse, error) {
+	if err := c.script.call(ctx, "ShowCollections"); err != nil {
+		return nil, err
+	}
+	return c.QueryCoordMock.ShowCollections(ctx, req, opts...)
+}
+
+func (c *fakeQueryCoord) ShowPartitions(ctx context.Context, req *querypb.ShowPartitionsRequest, opts ...grpc.CallOption) (*querypb.ShowPartitionsResponse, error) {
+	if err := c.script.call(ctx, "ShowPartitions"); err != nil {
+		return nil, err
+	}
+	return c.QueryCoordMock.ShowPartitions(ctx, req, opts...)
+}
+
+func (c *fakeDataCoord) CheckHealth(ctx context.Context, req *milvuspb.CheckHealthRequest, opts ...grpc.CallOption) (*milvuspb.CheckHealthResponse, error) {
+	if err := c.script.call(ctx, "CheckHealth"); err != nil {
+		return nil, err
+	}
+	return c.DataCoordMock.CheckHealth(ctx, req, opts...)
+}
+
+func (c *fakeDataCoord) CreateIndex(ctx context.Context, req *indexpb.CreateIndexRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "CreateIndex"); err != nil {
+		return nil, err
+	}
+	return c.DataCoordMock.CreateIndex(ctx, req, opts...)
+}
+
+func (c *fakeDataCoord) DescribeIndex(ctx context.Context, req *indexpb.DescribeIndexRequest, opts ...grpc.CallOption) (*indexpb.DescribeIndexResponse, error) {
+	if err := c.script.call(ctx, "DescribeIndex"); err != nil {
+		return nil, err
+	}
+	return c.DataCoordMock.DescribeIndex(ctx, req, opts...)
+}
+
+func (c *fakeDataCoord) DropIndex(ctx context.Context, req *indexpb.DropIndexRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if err := c.script.call(ctx, "DropIndex"); err != nil {
+		return nil, err
+	}
+	return c.DataCoordMock.DropIndex(ctx, req, opts...)
+}
+
+func (c *fakeDataCoord) GetFlushAllState(ctx context.Context, req *milvuspb.GetFlushAllStateRequest, opts ...grpc.CallOption) (*milvuspb.GetFlushAllStateResponse, error) {
+	if err := c.script.call(ctx, "GetFlushAllState"); err != nil {
+		return nil, err
+	}
+	return c.DataCoordMock.GetFlushAllState(ctx, req, opts...)
+}
+
+func (c *fakeDataCoord) GetFlushState(ctx context.Context, req *datapb.GetFlushStateRequest, opts ...grpc.CallOption) (*milvuspb.GetFlushStateResponse, error) {
+	if err := c.script.call(ctx, "GetFlushState"); err != nil {
+		return nil, err
+	}
+	return c.DataCoordMock.GetFlushState(ctx, req, opts...)
+}
+
+func (c *fakeDataCoord) GetIndexBuildProgress(ctx context.Context, req *indexpb.GetIndexBuildProgressRequest, opts ...grpc.CallOption) (*indexpb.GetIndexBuildProgressResponse, error) {
+	if err := c.script.call(ctx, "GetIndexBuildProgress"); err != nil {
+		return nil, err
+	}
+	return c.DataCoordMock.GetIndexBuildProgress(ctx, req, opts...)
+}
+
+func (c *fakeDataCoord) GetIndexInfos(ctx context.Context, req *indexpb.GetIndexInfoRequest, opts ...grpc.CallOption) (*indexpb.GetIndexInfoResponse, error) {
+	if err := c.script.call(ctx, "GetIndexInfos"); err != nil {
+		return nil, err
+	}
+	return c.DataCoordMock.GetIndexInfos(ctx, req, opts...)
+}
+
+func (c *fakeDataCoord) GetIndexState(ctx context.Context, req *indexpb.GetIndexStateRequest, opts ...grpc.CallOption) (*indexpb.GetIndexStateResponse, error) {
+	if err := c.script.call(ctx, "GetIndexState"); err != nil {
+		return nil, err
+	}
+	return c.DataCoordMock.GetIndexState(ctx, req, opts...)
+}
+
+func (c *fakeDataCoord) GetIndexStatistics(ctx context.Context, req *indexpb.GetIndexStatisticsRequest, opts ...grpc.CallOption) (*indexpb.GetIndexStatisticsResponse, error) {
+	if err := c.script.call(ctx, "GetIndexStatistics"); err != nil {
+		return nil, err
+	}
+	return c.DataCoordMock.GetIndexStatistics(ctx, req, opts...)
+}
+
+func (c *fakeDataCoord) GetMetrics(ctx context.Context, req *milvuspb.GetMetricsRequest, opts ...grpc.CallOption) (*milvuspb.GetMetricsResponse, error) {
+	if err := c.script.call(ctx, "GetMetrics"); err != nil {
+		return nil, err
+	}
+	return c.DataCoordMock.GetMetrics(ctx, req, opts...)
+}
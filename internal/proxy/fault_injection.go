@@ -0,0 +1,218 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// the points of the request pipeline to inject the faults
+const (
+	// faultPointEnqueue is before a task is enqueued
+	faultPointEnqueue = "enqueue"
+	// faultPointCoordCall is before each call of a ddl or dcl task to the coordinators
+	faultPointCoordCall = "coord_call"
+	// faultPointProduce is before each produce of the dml messages to the msgstream
+	faultPointProduce = "produce"
+	// faultPointReduce is before a dql task reduces the results
+	faultPointReduce = "reduce"
+)
+
+const (
+	faultActionLatency = "latency"
+	faultActionError   = "error"
+	faultActionDrop    = "drop"
+)
+
+// faultInjectionAllowed is set only in the builds with the test tag, the faults are never injected in other builds
+// whatever proxy.faultInjection.enabled is.
+var faultInjectionAllowed = false
+
+var globalFaultInjector = newFaultInjector()
+
+// faultRule injects a fault into the requests at a point.
+type faultRule struct {
+	Point string `json:"point"`
+	// Collection is the name of the collection of the requests, all the requests if empty
+	Collection string `json:"collection,omitempty"`
+	// Percentage is the percentage of the requests to inject, all the requests if 0
+	Percentage float64 `json:"percentage,omitempty"`
+	Action     string  `json:"action"`
+	LatencyMs  int64   `json:"latencyMs,omitempty"`
+	Message    string  `json:"message,omitempty"`
+}
+
+func parseFaultRules(value string) ([]*faultRule, error) {
+	if value == "" {
+		return nil, nil
+	}
+	rules := make([]*faultRule, 0)
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		switch rule.Point {
+		case faultPointEnqueue, faultPointCoordCall, faultPointProduce, faultPointReduce:
+		default:
+			return nil, fmt.Errorf("unknown fault point %q", rule.Point)
+		}
+		switch rule.Action {
+		case faultActionError, faultActionDrop:
+		case faultActionLatency:
+			if rule.LatencyMs <= 0 {
+				return nil, fmt.Errorf("invalid latency %d ms of fault point %q", rule.LatencyMs, rule.Point)
+			}
+		default:
+			return nil, fmt.Errorf("unknown fault action %q", rule.Action)
+		}
+		if rule.Percentage < 0 || rule.Percentage > 100 {
+			return nil, fmt.Errorf("invalid percentage %v of fault point %q", rule.Percentage, rule.Point)
+		}
+	}
+	return rules, nil
+}
+
+// faultInjector injects the faults of proxy.faultInjection.rules, the rules are parsed again once changed.
+type faultInjector struct {
+	mu    sync.Mutex
+	value string
+	rules []*faultRule
+	rand  *rand.Rand
+}
+
+func newFaultInjector() *faultInjector {
+	return &faultInjector{
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// match returns the first rule at the point applying to the request of the collection, nil if none.
+func (fi *faultInjector) match(point string, collection string) *faultRule {
+	if !faultInjectionAllowed || !Params.ProxyCfg.FaultInjectionEnabled.GetAsBool() {
+		return nil
+	}
+	value := Params.ProxyCfg.FaultInjectionRules.GetValue()
+
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	if value != fi.value {
+		rules, err := parseFaultRules(value)
+		if err != nil {
+			log.Warn("invalid fault injection rules, inject no fault", zap.String("rules", value), zap.Error(err))
+		}
+		fi.value, fi.rules = value, rules
+	}
+	for _, rule := range fi.rules {
+		if rule.Point != point || (rule.Collection != "" && rule.Collection != collection) {
+			continue
+		}
+		if rule.Percentage > 0 && fi.rand.Float64()*100 >= rule.Percentage {
+			continue
+		}
+		return rule
+	}
+	return nil
+}
+
+// inject injects the fault at the point into the request of the collection, returns true if the request is to be
+// dropped.
+func (fi *faultInjector) inject(ctx context.Context, point string, collection string) (bool, error) {
+	rule := fi.match(point, collection)
+	if rule == nil {
+		return false, nil
+	}
+	log.Ctx(ctx).RatedInfo(10, "inject fault",
+		zap.String("point", point),
+		zap.String("collection", collection),
+		zap.String("action", rule.Action))
+
+	switch rule.Action {
+	case faultActionLatency:
+		timer := time.NewTimer(time.Duration(rule.LatencyMs) * time.Millisecond)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-timer.C:
+			return false, nil
+		}
+	case faultActionError:
+		msg := rule.Message
+		if msg == "" {
+			msg = "injected fault"
+		}
+		return false, merr.WrapErrServiceInternal(msg, fmt.Sprintf("fault point %s", point))
+	case faultActionDrop:
+		return true, nil
+	}
+	return false, nil
+}
+
+// injectFault injects the fault at the point where a dropped request is lost, the request then waits until it is
+// canceled or timeouts as no response would come.
+func injectFault(ctx context.Context, point string, collection string) error {
+	dropped, err := globalFaultInjector.inject(ctx, point, collection)
+	if dropped {
+		<-ctx.Done()
+		return errors.Wrapf(ctx.Err(), "request dropped at fault point %s", point)
+	}
+	return err
+}
+
+// taskCollectionName returns the name of the collection of the task, empty if the task is not of a collection.
+func taskCollectionName(t task) string {
+	switch t := t.(type) {
+	case *searchTask:
+		return t.request.GetCollectionName()
+	case *queryTask:
+		return t.request.GetCollectionName()
+	case *insertTask:
+		if t.insertMsg == nil {
+			return ""
+		}
+		return t.insertMsg.GetCollectionName()
+	case *deleteTask:
+		return t.req.GetCollectionName()
+	case *upsertTask:
+		return t.req.GetCollectionName()
+	case interface{ GetCollectionName() string }:
+		return t.GetCollectionName()
+	}
+	return ""
+}
+
+// msgPackCollectionName returns the name of the collection of the dml messages.
+func msgPackCollectionName(msgPack *msgstream.MsgPack) string {
+	for _, msg := range msgPack.Msgs {
+		if named, ok := msg.(interface{ GetCollectionName() string }); ok {
+			return named.GetCollectionName()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,247 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func enableFaultInjection(t *testing.T, rules string) {
+	paramtable.Init()
+	params := paramtable.Get()
+	allowed := faultInjectionAllowed
+	faultInjectionAllowed = true
+	params.Save(Params.ProxyCfg.FaultInjectionEnabled.Key, "true")
+	params.Save(Params.ProxyCfg.FaultInjectionRules.Key, rules)
+	t.Cleanup(func() {
+		faultInjectionAllowed = allowed
+		params.Reset(Params.ProxyCfg.FaultInjectionEnabled.Key)
+		params.Reset(Params.ProxyCfg.FaultInjectionRules.Key)
+	})
+}
+
+func TestParseFaultRules(t *testing.T) {
+	rules, err := parseFaultRules("")
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+
+	rules, err = parseFaultRules(`[{"point": "enqueue", "action": "latency", "latencyMs": 10},
+		{"point": "produce", "collection": "c1", "percentage": 50, "action": "error", "message": "mock"}]`)
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "c1", rules[1].Collection)
+	assert.Equal(t, float64(50), rules[1].Percentage)
+
+	for _, value := range []string{
+		`{`,
+		`[{"point": "unknown", "action": "error"}]`,
+		`[{"point": "reduce", "action": "unknown"}]`,
+		`[{"point": "reduce", "action": "latency"}]`,
+		`[{"point": "reduce", "action": "error", "percentage": 101}]`,
+	} {
+		_, err = parseFaultRules(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestFaultInjector(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not allowed", func(t *testing.T) {
+		enableFaultInjection(t, `[{"point": "enqueue", "action": "error"}]`)
+		faultInjectionAllowed = false
+		dropped, err := newFaultInjector().inject(ctx, faultPointEnqueue, "c1")
+		assert.False(t, dropped)
+		assert.NoError(t, err)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		enableFaultInjection(t, `[{"point": "enqueue", "action": "error"}]`)
+		paramtable.Get().Save(Params.ProxyCfg.FaultInjectionEnabled.Key, "false")
+		dropped, err := newFaultInjector().inject(ctx, faultPointEnqueue, "c1")
+		assert.False(t, dropped)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		enableFaultInjection(t, `[{"point": "enqueue", "action": "unknown"}]`)
+		dropped, err := newFaultInjector().inject(ctx, faultPointEnqueue, "c1")
+		assert.False(t, dropped)
+		assert.NoError(t, err)
+	})
+
+	t.Run("actions", func(t *testing.T) {
+		enableFaultInjection(t, `[{"point": "enqueue", "collection": "c1", "action": "error", "message": "mock"},
+			{"point": "enqueue", "action": "drop"},
+			{"point": "reduce", "action": "latency", "latencyMs": 20}]`)
+		fi := newFaultInjector()
+
+		_, err := fi.inject(ctx, faultPointEnqueue, "c1")
+		assert.ErrorIs(t, err, merr.ErrServiceInternal)
+		assert.Contains(t, err.Error(), "mock")
+
+		dropped, err := fi.inject(ctx, faultPointEnqueue, "c2")
+		assert.True(t, dropped)
+		assert.NoError(t, err)
+
+		dropped, err = fi.inject(ctx, faultPointProduce, "c1")
+		assert.False(t, dropped)
+		assert.NoError(t, err)
+
+		start := time.Now()
+		_, err = fi.inject(ctx, faultPointReduce, "c1")
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+		defer cancel()
+		_, err = fi.inject(timeoutCtx, faultPointReduce, "c1")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("rules changed", func(t *testing.T) {
+		enableFaultInjection(t, `[{"point": "enqueue", "action": "error"}]`)
+		fi := newFaultInjector()
+		_, err := fi.inject(ctx, faultPointEnqueue, "c1")
+		assert.Error(t, err)
+
+		paramtable.Get().Save(Params.ProxyCfg.FaultInjectionRules.Key, `[{"point": "reduce", "action": "error"}]`)
+		_, err = fi.inject(ctx, faultPointEnqueue, "c1")
+		assert.NoError(t, err)
+		_, err = fi.inject(ctx, faultPointReduce, "c1")
+		assert.Error(t, err)
+	})
+
+	t.Run("percentage", func(t *testing.T) {
+		enableFaultInjection(t, `[{"point": "produce", "percentage": 30, "action": "drop"}]`)
+		fi := newFaultInjector()
+		fi.rand = rand.New(rand.NewSource(1))
+		count := 0
+		for i := 0; i < 1000; i++ {
+			if dropped, _ := fi.inject(ctx, faultPointProduce, "c1"); dropped {
+				count++
+			}
+		}
+		assert.Greater(t, count, 200)
+		assert.Less(t, count, 400)
+	})
+}
+
+func TestInjectFault(t *testing.T) {
+	enableFaultInjection(t, `[{"point": "coord_call", "action": "drop"}]`)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := injectFault(ctx, faultPointCoordCall, "c1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NoError(t, injectFault(ctx, faultPointEnqueue, "c1"))
+}
+
+// namedMockDmlTask is a dml task of a collection.
+type namedMockDmlTask struct {
+	*mockDmlTask
+	collectionName string
+}
+
+func (t *namedMockDmlTask) GetCollectionName() string {
+	return t.collectionName
+}
+
+func TestFaultInjectionEnqueue(t *testing.T) {
+	enableFaultInjection(t, `[{"point": "enqueue", "collection": "c1", "action": "drop"}]`)
+	queues := map[string]taskQueue{
+		"dd": newDdTaskQueue(newMockTsoAllocator()),
+		"dm": newDmTaskQueue(newMockTsoAllocator()),
+		"dq": newDqTaskQueue(newMockTsoAllocator()),
+	}
+	for name, queue := range queues {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			dropped := make(chan error, 1)
+			go func() {
+				dropped <- queue.Enqueue(&namedMockDmlTask{mockDmlTask: newMockDmlTask(ctx), collectionName: "c1"})
+			}()
+
+			// the dropped task blocks no other task from being enqueued
+			task := &namedMockDmlTask{mockDmlTask: newDefaultMockDmlTask(), collectionName: "c2"}
+			assert.NoError(t, queue.Enqueue(task))
+			assert.NotZero(t, task.BeginTs())
+			cancel()
+			assert.ErrorIs(t, <-dropped, context.Canceled)
+			assert.Equal(t, task, queue.PopUnissuedTask())
+			assert.True(t, queue.utEmpty())
+		})
+	}
+}
+
+func TestFaultInjectionCoordCall(t *testing.T) {
+	h := newProxyHarness(t)
+	enableFaultInjection(t, `[{"point": "coord_call", "collection": "c1", "action": "error"}]`)
+
+	// the fault is injected at the call to the coordinator, which is never called then
+	status, err := h.createCollection(h.ctx, "c1")
+	assert.ErrorIs(t, merr.CheckRPCCall(status, err), merr.ErrServiceInternal)
+	assert.Equal(t, 0, h.rc.script.callCount("CreateCollection"))
+
+	status, err = h.createCollection(h.ctx, "c2")
+	assert.NoError(t, merr.CheckRPCCall(status, err))
+	assert.Equal(t, 1, h.rc.script.callCount("CreateCollection"))
+}
+
+func TestFaultInjectionProduce(t *testing.T) {
+	enableFaultInjection(t, `[{"point": "produce", "collection": "c1", "action": "drop"},
+		{"point": "produce", "collection": "c2", "action": "error"}]`)
+	paramtable.Get().Save(Params.ProxyCfg.ProduceRetryMaxAttempts.Key, "1")
+	defer paramtable.Get().Reset(Params.ProxyCfg.ProduceRetryMaxAttempts.Key)
+
+	newMsgPack := func(collectionName string) *msgstream.MsgPack {
+		return &msgstream.MsgPack{
+			Msgs: []msgstream.TsMsg{&msgstream.DeleteMsg{DeleteRequest: msgpb.DeleteRequest{CollectionName: collectionName}}},
+		}
+	}
+	ctx := context.Background()
+	stream := msgstream.NewMockMsgStream(t)
+	stream.EXPECT().Produce(mock.Anything).Return(nil).Once()
+
+	// dropped silently
+	assert.NoError(t, produceWithRetry(ctx, stream, newMsgPack("c1"), metrics.DeleteLabel))
+	assert.Error(t, produceWithRetry(ctx, stream, newMsgPack("c2"), metrics.DeleteLabel))
+	assert.NoError(t, produceWithRetry(ctx, stream, newMsgPack("c3"), metrics.DeleteLabel))
+}
+
+func TestTaskCollectionName(t *testing.T) {
+	assert.Equal(t, "c1", taskCollectionName(&searchTask{request: &milvuspb.SearchRequest{CollectionName: "c1"}}))
+	assert.Equal(t, "c1", taskCollectionName(&queryTask{request: &milvuspb.QueryRequest{CollectionName: "c1"}}))
+	assert.Equal(t, "c1", taskCollectionName(&deleteTask{req: &milvuspb.DeleteRequest{CollectionName: "c1"}}))
+	assert.Equal(t, "c1", taskCollectionName(&upsertTask{req: &milvuspb.UpsertRequest{CollectionName: "c1"}}))
+	assert.Equal(t, "c1", taskCollectionName(&insertTask{insertMsg: &msgstream.InsertMsg{InsertRequest: msgpb.InsertRequest{CollectionName: "c1"}}}))
+	assert.Equal(t, "", taskCollectionName(&insertTask{}))
+	assert.Equal(t, "c1", taskCollectionName(&createCollectionTask{CreateCollectionRequest: &milvuspb.CreateCollectionRequest{CollectionName: "c1"}}))
+	assert.Equal(t, "", taskCollectionName(newDefaultMockTask()))
+}
@@ -0,0 +1,350 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// The fields with common.FieldEncryptionKeyIDKey in type params are envelope-encrypted by proxy:
+// each batch of a field is encrypted with a fresh data key, which is wrapped by the master key in KMS
+// and stored along with the ciphertext. The encrypted fields are opaque to the other components,
+// so they can't be used in filter expressions, and bulk insert bypasses the encryption.
+// The max length of an encrypted VarChar field limits the ciphertext, which is longer than the plaintext.
+
+const (
+	encryptedValuePrefix = "enc:v1:"
+	dataKeySize          = 32
+)
+
+// KMS manages the master keys of field encryption.
+type KMS interface {
+	// GenerateDataKey returns a new data key in plaintext and the one wrapped by master key keyID.
+	GenerateDataKey(ctx context.Context, keyID string) (plaintext []byte, wrapped []byte, err error)
+	// DecryptDataKey unwraps the data key wrapped by master key keyID.
+	DecryptDataKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+var (
+	kmsMu     sync.RWMutex
+	globalKMS KMS = &localKMS{}
+)
+
+// RegisterKMS replaces the KMS of field encryption, the built-in one reads master keys from config.
+func RegisterKMS(kms KMS) {
+	kmsMu.Lock()
+	defer kmsMu.Unlock()
+	globalKMS = kms
+}
+
+func getKMS() KMS {
+	kmsMu.RLock()
+	defer kmsMu.RUnlock()
+	return globalKMS
+}
+
+// localKMS wraps the data keys with the master keys in proxy.encryption.localMasterKeys.
+type localKMS struct{}
+
+func (k *localKMS) masterKey(keyID string) ([]byte, error) {
+	for _, pair := range strings.Split(Params.ProxyCfg.EncryptionLocalMasterKeys.GetValue(), ",") {
+		id, value, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || id != keyID {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, merr.WrapErrServiceInternal(fmt.Sprintf("invalid master key %s", keyID), err.Error())
+		}
+		return key, nil
+	}
+	return nil, merr.WrapErrServiceInternal(fmt.Sprintf("master key %s not found", keyID))
+}
+
+func (k *localKMS) GenerateDataKey(ctx context.Context, keyID string) ([]byte, []byte, error) {
+	masterKey, err := k.masterKey(keyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	plaintext := make([]byte, dataKeySize)
+	if _, err := rand.Read(plaintext); err != nil {
+		return nil, nil, err
+	}
+	wrapped, err := sealAESGCM(masterKey, plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return plaintext, wrapped, nil
+}
+
+func (k *localKMS) DecryptDataKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	masterKey, err := k.masterKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return openAESGCM(masterKey, wrapped)
+}
+
+// sealAESGCM returns nonce | ciphertext.
+func sealAESGCM(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func openAESGCM(key, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+}
+
+// getFieldEncryptionKeyID returns the master key id of field, empty if the field is not encrypted.
+func getFieldEncryptionKeyID(field *schemapb.FieldSchema) string {
+	keyID, _ := funcutil.GetAttrByKeyFromRepeatedKV(common.FieldEncryptionKeyIDKey, field.GetTypeParams())
+	return keyID
+}
+
+func hasEncryptedField(schema *schemapb.CollectionSchema) bool {
+	for _, field := range schema.GetFields() {
+		if getFieldEncryptionKeyID(field) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFieldEncryption checks the encryption metadata of field.
+func validateFieldEncryption(field *schemapb.FieldSchema) error {
+	params := funcutil.KeyValuePair2Map(field.GetTypeParams())
+	keyID, ok := params[common.FieldEncryptionKeyIDKey]
+	if !ok {
+		if _, ok := params[common.FieldEncryptionDecryptRolesKey]; ok {
+			return merr.WrapErrParameterInvalidMsg("%s of field %s is set without %s", common.FieldEncryptionDecryptRolesKey, field.GetName(), common.FieldEncryptionKeyIDKey)
+		}
+		return nil
+	}
+	if keyID == "" {
+		return merr.WrapErrParameterInvalidMsg("%s of field %s is empty", common.FieldEncryptionKeyIDKey, field.GetName())
+	}
+	if field.GetDataType() != schemapb.DataType_VarChar && field.GetDataType() != schemapb.DataType_JSON {
+		return merr.WrapErrParameterInvalidMsg("only VarChar and JSON field can be encrypted, field %s is %s", field.GetName(), field.GetDataType().String())
+	}
+	if field.GetIsPrimaryKey() || field.GetIsPartitionKey() || field.GetIsClusteringKey() {
+		return merr.WrapErrParameterInvalidMsg("primary key, partition key or clustering key field %s can't be encrypted", field.GetName())
+	}
+	return nil
+}
+
+func getSchemaField(schema *schemapb.CollectionSchema, fieldData *schemapb.FieldData) *schemapb.FieldSchema {
+	for _, field := range schema.GetFields() {
+		if (fieldData.GetFieldId() != 0 && field.GetFieldID() == fieldData.GetFieldId()) || field.GetName() == fieldData.GetFieldName() {
+			return field
+		}
+	}
+	return nil
+}
+
+// encryptFieldsData encrypts the data of encrypted fields in place.
+func encryptFieldsData(ctx context.Context, schema *schemapb.CollectionSchema, fieldsData []*schemapb.FieldData) error {
+	if !hasEncryptedField(schema) {
+		return nil
+	}
+	for _, fieldData := range fieldsData {
+		field := getSchemaField(schema, fieldData)
+		keyID := getFieldEncryptionKeyID(field)
+		if keyID == "" {
+			continue
+		}
+		plaintext, wrapped, err := getKMS().GenerateDataKey(ctx, keyID)
+		if err != nil {
+			return err
+		}
+		encrypt := func(value []byte) (string, error) {
+			sealed, err := sealAESGCM(plaintext, value)
+			if err != nil {
+				return "", err
+			}
+			envelope := binary.AppendUvarint(nil, uint64(len(wrapped)))
+			envelope = append(append(envelope, wrapped...), sealed...)
+			return encryptedValuePrefix + base64.StdEncoding.EncodeToString(envelope), nil
+		}
+
+		switch field.GetDataType() {
+		case schemapb.DataType_VarChar:
+			data := fieldData.GetScalars().GetStringData().GetData()
+			for i, value := range data {
+				if data[i], err = encrypt([]byte(value)); err != nil {
+					return err
+				}
+			}
+		case schemapb.DataType_JSON:
+			// keep the encrypted value a valid json
+			data := fieldData.GetScalars().GetJsonData().GetData()
+			for i, value := range data {
+				encrypted, err := encrypt(value)
+				if err != nil {
+					return err
+				}
+				if data[i], err = json.Marshal(encrypted); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// canDecryptField returns whether the current user is granted to read the plaintext of field.
+func canDecryptField(ctx context.Context, field *schemapb.FieldSchema) (bool, error) {
+	if !Params.CommonCfg.AuthorizationEnabled.GetAsBool() {
+		return true, nil
+	}
+	value, _ := funcutil.GetAttrByKeyFromRepeatedKV(common.FieldEncryptionDecryptRolesKey, field.GetTypeParams())
+	allowed := typeutil.NewSet[string]()
+	for _, role := range strings.Split(value, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			allowed.Insert(role)
+		}
+	}
+	if allowed.Len() == 0 {
+		return true, nil
+	}
+	username, err := GetCurUserFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	if username == util.UserRoot {
+		return true, nil
+	}
+	roles, err := GetRole(username)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roles {
+		if allowed.Contain(role) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// decryptFieldsData decrypts the data of encrypted fields in place if the current user is allowed,
+// otherwise the ciphertext is returned.
+func decryptFieldsData(ctx context.Context, schema *schemapb.CollectionSchema, fieldsData []*schemapb.FieldData) error {
+	if !hasEncryptedField(schema) {
+		return nil
+	}
+	for _, fieldData := range fieldsData {
+		field := getSchemaField(schema, fieldData)
+		keyID := getFieldEncryptionKeyID(field)
+		if keyID == "" {
+			continue
+		}
+		allowed, err := canDecryptField(ctx, field)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			continue
+		}
+
+		// the values of one insert batch share the data key
+		dataKeys := make(map[string][]byte)
+		decrypt := func(value string) ([]byte, error) {
+			if !strings.HasPrefix(value, encryptedValuePrefix) {
+				// written before the field was encrypted
+				return []byte(value), nil
+			}
+			envelope, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedValuePrefix))
+			if err != nil {
+				return nil, err
+			}
+			size, n := binary.Uvarint(envelope)
+			if n <= 0 || uint64(len(envelope)-n) < size {
+				return nil, fmt.Errorf("invalid encrypted value of field %s", field.GetName())
+			}
+			wrapped := envelope[n : n+int(size)]
+			dataKey, ok := dataKeys[string(wrapped)]
+			if !ok {
+				if dataKey, err = getKMS().DecryptDataKey(ctx, keyID, wrapped); err != nil {
+					return nil, err
+				}
+				dataKeys[string(wrapped)] = dataKey
+			}
+			return openAESGCM(dataKey, envelope[n+int(size):])
+		}
+
+		switch field.GetDataType() {
+		case schemapb.DataType_VarChar:
+			data := fieldData.GetScalars().GetStringData().GetData()
+			for i, value := range data {
+				plaintext, err := decrypt(value)
+				if err != nil {
+					return merr.WrapErrServiceInternal(fmt.Sprintf("failed to decrypt field %s", field.GetName()), err.Error())
+				}
+				data[i] = string(plaintext)
+			}
+		case schemapb.DataType_JSON:
+			data := fieldData.GetScalars().GetJsonData().GetData()
+			for i, value := range data {
+				var encrypted string
+				if err := json.Unmarshal(value, &encrypted); err != nil || !strings.HasPrefix(encrypted, encryptedValuePrefix) {
+					continue
+				}
+				if data[i], err = decrypt(encrypted); err != nil {
+					return merr.WrapErrServiceInternal(fmt.Sprintf("failed to decrypt field %s", field.GetName()), err.Error())
+				}
+			}
+		}
+	}
+	return nil
+}
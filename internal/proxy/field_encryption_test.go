@@ -0,0 +1,133 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func newEncryptionTestSchema(decryptRoles string) *schemapb.CollectionSchema {
+	return &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "ssn", DataType: schemapb.DataType_VarChar, TypeParams: []*commonpb.KeyValuePair{
+				{Key: common.MaxLengthKey, Value: "1024"},
+				{Key: common.FieldEncryptionKeyIDKey, Value: "key1"},
+				{Key: common.FieldEncryptionDecryptRolesKey, Value: decryptRoles},
+			}},
+			{FieldID: 102, Name: "profile", DataType: schemapb.DataType_JSON, TypeParams: []*commonpb.KeyValuePair{
+				{Key: common.FieldEncryptionKeyIDKey, Value: "key1"},
+			}},
+			{FieldID: 103, Name: "name", DataType: schemapb.DataType_VarChar},
+		},
+	}
+}
+
+func newEncryptionTestFieldsData() []*schemapb.FieldData {
+	return []*schemapb.FieldData{
+		{FieldName: "ssn", Type: schemapb.DataType_VarChar, Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"123-45-6789", "987-65-4321"}}},
+		}}},
+		{FieldName: "profile", Type: schemapb.DataType_JSON, Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_JsonData{JsonData: &schemapb.JSONArray{Data: [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`)}}},
+		}}},
+		{FieldName: "name", Type: schemapb.DataType_VarChar, Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"alice", "bob"}}},
+		}}},
+	}
+}
+
+func TestFieldEncryption(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	masterKey := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	paramtable.Get().Save(Params.ProxyCfg.EncryptionLocalMasterKeys.Key, "key1:"+masterKey)
+	defer paramtable.Get().Reset(Params.ProxyCfg.EncryptionLocalMasterKeys.Key)
+
+	schema := newEncryptionTestSchema("")
+	fieldsData := newEncryptionTestFieldsData()
+	assert.NoError(t, encryptFieldsData(ctx, schema, fieldsData))
+
+	ssn := fieldsData[0].GetScalars().GetStringData().GetData()
+	assert.True(t, strings.HasPrefix(ssn[0], encryptedValuePrefix))
+	assert.NotEqual(t, ssn[0], ssn[1])
+	for _, value := range fieldsData[1].GetScalars().GetJsonData().GetData() {
+		assert.True(t, json.Valid(value))
+	}
+	assert.Equal(t, []string{"alice", "bob"}, fieldsData[2].GetScalars().GetStringData().GetData())
+
+	assert.NoError(t, decryptFieldsData(ctx, schema, fieldsData))
+	assert.Equal(t, newEncryptionTestFieldsData(), fieldsData)
+
+	// unknown master key
+	schema.Fields[1].TypeParams[1].Value = "key2"
+	assert.Error(t, encryptFieldsData(ctx, schema, newEncryptionTestFieldsData()))
+}
+
+func TestFieldEncryption_DecryptRoles(t *testing.T) {
+	paramtable.Init()
+	masterKey := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	paramtable.Get().Save(Params.ProxyCfg.EncryptionLocalMasterKeys.Key, "key1:"+masterKey)
+	defer paramtable.Get().Reset(Params.ProxyCfg.EncryptionLocalMasterKeys.Key)
+	paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetUserRole("alice").Return([]string{"auditor"}).Maybe()
+	mockCache.EXPECT().GetUserRole("bob").Return([]string{"public"}).Maybe()
+	globalMetaCache = mockCache
+	defer func() { globalMetaCache = nil }()
+
+	schema := newEncryptionTestSchema("auditor")
+	fieldsData := newEncryptionTestFieldsData()
+	assert.NoError(t, encryptFieldsData(context.Background(), schema, fieldsData))
+	encrypted := fieldsData[0].GetScalars().GetStringData().GetData()[0]
+
+	assert.NoError(t, decryptFieldsData(GetContext(context.Background(), "bob:123456"), schema, fieldsData))
+	assert.Equal(t, encrypted, fieldsData[0].GetScalars().GetStringData().GetData()[0])
+
+	assert.NoError(t, decryptFieldsData(GetContext(context.Background(), "alice:123456"), schema, fieldsData))
+	assert.Equal(t, "123-45-6789", fieldsData[0].GetScalars().GetStringData().GetData()[0])
+}
+
+func TestValidateFieldEncryption(t *testing.T) {
+	keyID := &commonpb.KeyValuePair{Key: common.FieldEncryptionKeyIDKey, Value: "key1"}
+	assert.NoError(t, validateFieldEncryption(&schemapb.FieldSchema{Name: "f", DataType: schemapb.DataType_Int64}))
+	assert.NoError(t, validateFieldEncryption(&schemapb.FieldSchema{Name: "f", DataType: schemapb.DataType_VarChar, TypeParams: []*commonpb.KeyValuePair{keyID}}))
+	assert.NoError(t, validateFieldEncryption(&schemapb.FieldSchema{Name: "f", DataType: schemapb.DataType_JSON, TypeParams: []*commonpb.KeyValuePair{keyID}}))
+
+	assert.Error(t, validateFieldEncryption(&schemapb.FieldSchema{Name: "f", DataType: schemapb.DataType_Int64, TypeParams: []*commonpb.KeyValuePair{keyID}}))
+	assert.Error(t, validateFieldEncryption(&schemapb.FieldSchema{Name: "f", DataType: schemapb.DataType_VarChar, IsPrimaryKey: true, TypeParams: []*commonpb.KeyValuePair{keyID}}))
+	assert.Error(t, validateFieldEncryption(&schemapb.FieldSchema{Name: "f", DataType: schemapb.DataType_VarChar, IsPartitionKey: true, TypeParams: []*commonpb.KeyValuePair{keyID}}))
+	assert.Error(t, validateFieldEncryption(&schemapb.FieldSchema{Name: "f", DataType: schemapb.DataType_VarChar, TypeParams: []*commonpb.KeyValuePair{
+		{Key: common.FieldEncryptionKeyIDKey, Value: ""},
+	}}))
+	assert.Error(t, validateFieldEncryption(&schemapb.FieldSchema{Name: "f", DataType: schemapb.DataType_VarChar, TypeParams: []*commonpb.KeyValuePair{
+		{Key: common.FieldEncryptionDecryptRolesKey, Value: "r1"},
+	}}))
+}
@@ -0,0 +1,212 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/log"
+)
+
+const (
+	// maxCachedFilterSelectivities bounds the filters the selectivity is cached for.
+	maxCachedFilterSelectivities = 4096
+	// filterSelectivityRefreshTimeout bounds the count queries refreshing the selectivity in the background.
+	filterSelectivityRefreshTimeout = time.Minute
+	// filterSelectivityRefreshInterval is the min interval between the count queries refreshing the selectivity, so
+	// the searches of many distinct filters do not flood the query nodes with the count queries.
+	filterSelectivityRefreshInterval = 100 * time.Millisecond
+)
+
+// overFetchBoundParams are the search params the topk must not exceed, such as the ef of hnsw and the search list
+// of diskann, the over-fetch never raises the topk above them.
+var overFetchBoundParams = []string{"ef", "search_list"}
+
+type filterKey struct {
+	dbName         string
+	collectionName string
+	expr           string
+}
+
+type filterSelectivity struct {
+	selectivity float64
+	updated     time.Time
+}
+
+// filterSelectivityCache caches the estimated selectivity of the filters of the searches, counted by the filter stats.
+type filterSelectivityCache struct {
+	mu         sync.Mutex
+	entries    map[filterKey]*filterSelectivity
+	refreshing map[filterKey]struct{}
+	// lastRefresh is when the last refresh started
+	lastRefresh time.Time
+}
+
+func newFilterSelectivityCache() *filterSelectivityCache {
+	return &filterSelectivityCache{
+		entries:    make(map[filterKey]*filterSelectivity),
+		refreshing: make(map[filterKey]struct{}),
+	}
+}
+
+var globalFilterSelectivityCache = newFilterSelectivityCache()
+
+// get returns the cached selectivity of the filter, false if not cached within the ttl.
+func (c *filterSelectivityCache) get(dbName, collectionName, expr string, now time.Time) (float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[filterKey{dbName: dbName, collectionName: collectionName, expr: expr}]
+	if !ok || now.Sub(entry.updated) > Params.ProxyCfg.FilterSelectivityCacheTTL.GetAsDuration(time.Second) {
+		return 0, false
+	}
+	return entry.selectivity, true
+}
+
+// put caches the selectivity of the filter, the expired entries are evicted if the cache is full, and any entry if
+// still full.
+func (c *filterSelectivityCache) put(dbName, collectionName, expr string, selectivity float64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := filterKey{dbName: dbName, collectionName: collectionName, expr: expr}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCachedFilterSelectivities {
+		ttl := Params.ProxyCfg.FilterSelectivityCacheTTL.GetAsDuration(time.Second)
+		for k, entry := range c.entries {
+			if now.Sub(entry.updated) > ttl {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < maxCachedFilterSelectivities {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = &filterSelectivity{selectivity: selectivity, updated: now}
+}
+
+// startRefresh marks the filter refreshing, false if it is being refreshed already, or another refresh started
+// within the refresh interval.
+func (c *filterSelectivityCache) startRefresh(key filterKey, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.refreshing[key]; ok || now.Sub(c.lastRefresh) < filterSelectivityRefreshInterval {
+		return false
+	}
+	c.refreshing[key] = struct{}{}
+	c.lastRefresh = now
+	return true
+}
+
+func (c *filterSelectivityCache) finishRefresh(key filterKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.refreshing, key)
+}
+
+// overFetchFactor returns the factor of the topk to search the shards for by the filter of the estimated selectivity,
+// the square root of the inverse selectivity within the configured bounds. The searches of the filters not estimated
+// yet are over-fetched by the min factor.
+func overFetchFactor(selectivity float64, estimated bool) int64 {
+	minFactor := Params.ProxyCfg.FilterOverFetchMinFactor.GetAsInt64()
+	maxFactor := Params.ProxyCfg.FilterOverFetchMaxFactor.GetAsInt64()
+	if minFactor < 1 {
+		minFactor = 1
+	}
+	if maxFactor < minFactor {
+		maxFactor = minFactor
+	}
+	if !estimated || selectivity <= 0 {
+		return minFactor
+	}
+	factor := int64(math.Ceil(1 / math.Sqrt(selectivity)))
+	if factor < minFactor {
+		return minFactor
+	}
+	if factor > maxFactor {
+		return maxFactor
+	}
+	return factor
+}
+
+// overFetchTopK returns the topk to search the shards for by the search with filter, within the topk limit and the
+// bounds of the search params.
+func overFetchTopK(dbName, collectionName, expr string, topK int64, searchParams string) int64 {
+	if expr == "" {
+		return topK
+	}
+	selectivity, estimated := globalFilterSelectivityCache.get(dbName, collectionName, expr, time.Now())
+	overFetched := topK * overFetchFactor(selectivity, estimated)
+	if limit := Params.QuotaConfig.TopKLimit.GetAsInt64(); overFetched > limit {
+		overFetched = limit
+	}
+	params := make(map[string]any)
+	if searchParams != "" {
+		// the invalid search params are reported by the query nodes
+		_ = json.Unmarshal([]byte(searchParams), &params)
+	}
+	for _, key := range overFetchBoundParams {
+		if bound, ok := params[key].(float64); ok && int64(bound) < overFetched {
+			overFetched = int64(bound)
+		}
+	}
+	if overFetched < topK {
+		return topK
+	}
+	return overFetched
+}
+
+// refreshFilterSelectivity counts the filter stats of the search in the background if the selectivity of its filter
+// is not cached, for the over-fetch of the later searches with the filter.
+func (node *Proxy) refreshFilterSelectivity(request *milvuspb.SearchRequest) {
+	if request.GetDsl() == "" || Params.ProxyCfg.FilterOverFetchMaxFactor.GetAsInt64() <= 1 {
+		return
+	}
+	key := filterKey{dbName: request.GetDbName(), collectionName: request.GetCollectionName(), expr: request.GetDsl()}
+	if _, ok := globalFilterSelectivityCache.get(key.dbName, key.collectionName, key.expr, time.Now()); ok {
+		return
+	}
+	if !globalFilterSelectivityCache.startRefresh(key, time.Now()) {
+		return
+	}
+	// the estimation does not wait for the recent mutations, nor holds the vectors of the search
+	countRequest := &milvuspb.SearchRequest{
+		DbName:           request.GetDbName(),
+		CollectionName:   request.GetCollectionName(),
+		Dsl:              request.GetDsl(),
+		PartitionNames:   request.GetPartitionNames(),
+		SearchParams:     request.GetSearchParams(),
+		ConsistencyLevel: commonpb.ConsistencyLevel_Eventually,
+	}
+	go func() {
+		defer globalFilterSelectivityCache.finishRefresh(key)
+		ctx, cancel := context.WithTimeout(node.ctx, filterSelectivityRefreshTimeout)
+		defer cancel()
+		if _, err := node.getFilterStats(ctx, countRequest); err != nil {
+			log.Warn("failed to count the selectivity of the filter", zap.String("collection", key.collectionName),
+				zap.String("expr", key.expr), zap.Error(err))
+		}
+	}()
+}
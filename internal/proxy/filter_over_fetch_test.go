@@ -0,0 +1,105 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestFilterSelectivityCache(t *testing.T) {
+	paramtable.Init()
+	cache := newFilterSelectivityCache()
+	now := time.Now()
+	_, ok := cache.get("db", "coll", "a > 1", now)
+	assert.False(t, ok)
+	cache.put("db", "coll", "a > 1", 0.01, now)
+	selectivity, ok := cache.get("db", "coll", "a > 1", now)
+	assert.True(t, ok)
+	assert.Equal(t, 0.01, selectivity)
+	_, ok = cache.get("db", "coll", "a > 2", now)
+	assert.False(t, ok)
+	_, ok = cache.get("db", "coll", "a > 1", now.Add(Params.ProxyCfg.FilterSelectivityCacheTTL.GetAsDuration(time.Second)+time.Second))
+	assert.False(t, ok)
+
+	for i := 0; i < maxCachedFilterSelectivities+10; i++ {
+		cache.put("db", "coll", "a > "+strconv.Itoa(i), 0.5, now)
+	}
+	assert.Len(t, cache.entries, maxCachedFilterSelectivities)
+
+	key := filterKey{dbName: "db", collectionName: "coll", expr: "a > 1"}
+	assert.True(t, cache.startRefresh(key, now))
+	now = now.Add(filterSelectivityRefreshInterval)
+	assert.False(t, cache.startRefresh(key, now))
+	cache.finishRefresh(key)
+	assert.True(t, cache.startRefresh(key, now))
+
+	// the refreshes are rate limited
+	cache.finishRefresh(key)
+	assert.False(t, cache.startRefresh(filterKey{dbName: "db", collectionName: "coll", expr: "a > 2"}, now))
+	now = now.Add(filterSelectivityRefreshInterval)
+	assert.True(t, cache.startRefresh(filterKey{dbName: "db", collectionName: "coll", expr: "a > 2"}, now))
+}
+
+func TestOverFetchFactor(t *testing.T) {
+	paramtable.Init()
+	// disabled by default
+	assert.Equal(t, int64(1), overFetchFactor(0.0001, true))
+
+	paramtable.Get().Save(Params.ProxyCfg.FilterOverFetchMaxFactor.Key, "4")
+	assert.Equal(t, int64(1), overFetchFactor(0, false))
+	assert.Equal(t, int64(1), overFetchFactor(1, true))
+	assert.Equal(t, int64(2), overFetchFactor(0.25, true))
+	// capped by the max factor
+	assert.Equal(t, int64(4), overFetchFactor(0.0001, true))
+
+	paramtable.Get().Save(Params.ProxyCfg.FilterOverFetchMinFactor.Key, "2")
+	defer paramtable.Get().Reset(Params.ProxyCfg.FilterOverFetchMinFactor.Key)
+	assert.Equal(t, int64(2), overFetchFactor(0, false))
+	assert.Equal(t, int64(2), overFetchFactor(1, true))
+
+	paramtable.Get().Save(Params.ProxyCfg.FilterOverFetchMaxFactor.Key, "1")
+	defer paramtable.Get().Reset(Params.ProxyCfg.FilterOverFetchMaxFactor.Key)
+	assert.Equal(t, int64(2), overFetchFactor(0.0001, true))
+}
+
+func TestOverFetchTopK(t *testing.T) {
+	paramtable.Init()
+	cache := globalFilterSelectivityCache
+	defer func() { globalFilterSelectivityCache = cache }()
+	globalFilterSelectivityCache = newFilterSelectivityCache()
+	paramtable.Get().Save(Params.ProxyCfg.FilterOverFetchMaxFactor.Key, "4")
+	defer paramtable.Get().Reset(Params.ProxyCfg.FilterOverFetchMaxFactor.Key)
+
+	assert.Equal(t, int64(10), overFetchTopK("db", "coll", "a > 1", 10, ""))
+	globalFilterSelectivityCache.put("db", "coll", "a > 1", 0.1, time.Now())
+	assert.Equal(t, int64(40), overFetchTopK("db", "coll", "a > 1", 10, ""))
+	assert.Equal(t, int64(10), overFetchTopK("db", "coll", "", 10, ""))
+	// within the topk limit
+	limit := Params.QuotaConfig.TopKLimit.GetAsInt64()
+	assert.Equal(t, limit, overFetchTopK("db", "coll", "a > 1", limit-1, ""))
+	// within the ef and the search list of the user
+	assert.Equal(t, int64(16), overFetchTopK("db", "coll", "a > 1", 10, `{"ef": 16}`))
+	assert.Equal(t, int64(20), overFetchTopK("db", "coll", "a > 1", 10, `{"search_list": 20, "ef": 64}`))
+	assert.Equal(t, int64(10), overFetchTopK("db", "coll", "a > 1", 10, `{"ef": 8}`))
+	assert.Equal(t, int64(40), overFetchTopK("db", "coll", "a > 1", 10, `{"nprobe": 8}`))
+}
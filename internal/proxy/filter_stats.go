@@ -0,0 +1,228 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// filterStatsInfoKey is set in the extra info of the search result status if the filter stats are requested, the
+// value is the json of the filterStats.
+const filterStatsInfoKey = "filter_stats"
+
+// maxAdvisedSearchParam caps the advised ef, search_list and nprobe.
+const maxAdvisedSearchParam = 65536
+
+// shardFilterStats is the rows of a shard and the rows kept by the filter, the candidates of the search of the shard.
+type shardFilterStats struct {
+	Channel      string  `json:"channel"`
+	TotalRows    int64   `json:"total_rows"`
+	FilteredRows int64   `json:"filtered_rows"`
+	Selectivity  float64 `json:"selectivity"`
+}
+
+// filterStats is the selectivity of the filter of the search by shard, with the advice on the search params if the
+// filter is selective enough to drop the recall.
+type filterStats struct {
+	TotalRows    int64               `json:"total_rows"`
+	FilteredRows int64               `json:"filtered_rows"`
+	Selectivity  float64             `json:"selectivity"`
+	Shards       []*shardFilterStats `json:"shards"`
+	Advice       []string            `json:"advice,omitempty"`
+}
+
+func selectivity(filtered, total int64) float64 {
+	if total <= 0 {
+		return 1
+	}
+	return float64(filtered) / float64(total)
+}
+
+// collectFilterStats returns the filter stats by the counts of the rows and the filtered rows by channel.
+func collectFilterStats(filtered, total map[string]int64) *filterStats {
+	stats := &filterStats{Shards: make([]*shardFilterStats, 0, len(total))}
+	for channel, totalRows := range total {
+		stats.TotalRows += totalRows
+		stats.FilteredRows += filtered[channel]
+		stats.Shards = append(stats.Shards, &shardFilterStats{
+			Channel:      channel,
+			TotalRows:    totalRows,
+			FilteredRows: filtered[channel],
+			Selectivity:  selectivity(filtered[channel], totalRows),
+		})
+	}
+	sort.Slice(stats.Shards, func(i, j int) bool {
+		return stats.Shards[i].Channel < stats.Shards[j].Channel
+	})
+	stats.Selectivity = selectivity(stats.FilteredRows, stats.TotalRows)
+	return stats
+}
+
+// shardSelectivity returns the selectivity of the most selective shard with any row kept, as each shard is searched
+// for the topk.
+func (s *filterStats) shardSelectivity() float64 {
+	ratio := 1.0
+	for _, shard := range s.Shards {
+		if shard.FilteredRows > 0 && shard.Selectivity < ratio {
+			ratio = shard.Selectivity
+		}
+	}
+	return ratio
+}
+
+// adviseSearchParams suggests the search params to collect the topk among the filtered rows of the most selective
+// shard. The graph indexes visit about ef candidates, ef over topk / selectivity is expected to visit the topk
+// filtered rows; the ivf indexes scan nprobe clusters, nprobe / selectivity keeps the filtered rows scanned as many
+// as without the filter.
+func adviseSearchParams(stats *filterStats, searchParams []*commonpb.KeyValuePair, threshold float64) []string {
+	ratio := stats.shardSelectivity()
+	if ratio >= threshold {
+		return nil
+	}
+
+	topK := int64(defaultTuneTopK)
+	if value, err := funcutil.GetAttrByKeyFromRepeatedKV(TopKKey, searchParams); err == nil {
+		if k, err := strconv.ParseInt(value, 10, 64); err == nil && k > 0 {
+			topK = k
+		}
+	}
+	params := make(map[string]any)
+	if value, err := funcutil.GetAttrByKeyFromRepeatedKV(SearchParamsKey, searchParams); err == nil && value != "" {
+		decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+		decoder.UseNumber()
+		_ = decoder.Decode(&params)
+	}
+	current := func(key string) (int64, bool) {
+		number, ok := params[key].(json.Number)
+		if !ok {
+			return 0, false
+		}
+		value, err := number.Int64()
+		return value, err == nil
+	}
+	suggest := func(value float64) int64 {
+		return int64(math.Min(math.Ceil(value), maxAdvisedSearchParam))
+	}
+
+	percentage := ratio * 100
+	advice := make([]string, 0)
+	for _, key := range []string{"ef", "search_list"} {
+		if value, ok := current(key); ok {
+			if suggested := suggest(float64(topK) / ratio); suggested > value {
+				advice = append(advice, fmt.Sprintf("the filter keeps %.2f%% of the rows of a shard, raise %s from %d to %d", percentage, key, value, suggested))
+			}
+		}
+	}
+	if value, ok := current("nprobe"); ok {
+		if suggested := suggest(float64(value) / ratio); suggested > value {
+			advice = append(advice, fmt.Sprintf("the filter keeps %.2f%% of the rows of a shard, raise nprobe from %d to %d", percentage, value, suggested))
+		}
+	}
+	if len(advice) == 0 && len(params) == 0 {
+		advice = append(advice, fmt.Sprintf("the filter keeps %.2f%% of the rows of a shard, raise ef or search_list to %d for the graph indexes, or nprobe for the ivf indexes",
+			percentage, suggest(float64(topK)/ratio)))
+	}
+	return advice
+}
+
+// newFilterCountRequest returns the count(*) query of the partitions and consistency of the search by the expr.
+func newFilterCountRequest(request *milvuspb.SearchRequest, expr string) *milvuspb.QueryRequest {
+	countRequest := &milvuspb.QueryRequest{
+		DbName:                request.GetDbName(),
+		CollectionName:        request.GetCollectionName(),
+		Expr:                  expr,
+		OutputFields:          []string{"count(*)"},
+		PartitionNames:        request.GetPartitionNames(),
+		GuaranteeTimestamp:    request.GetGuaranteeTimestamp(),
+		ConsistencyLevel:      request.GetConsistencyLevel(),
+		UseDefaultConsistency: request.GetUseDefaultConsistency(),
+	}
+	if value, err := funcutil.GetAttrByKeyFromRepeatedKV(IgnoreGrowingKey, request.GetSearchParams()); err == nil {
+		countRequest.QueryParams = []*commonpb.KeyValuePair{{Key: IgnoreGrowingKey, Value: value}}
+	}
+	return countRequest
+}
+
+// getFilterStats counts the rows and the filtered rows of the shards by two count queries in parallel.
+func (node *Proxy) getFilterStats(ctx context.Context, request *milvuspb.SearchRequest) (*filterStats, error) {
+	group, gctx := errgroup.WithContext(ctx)
+	filtered := node.newQueryTask(gctx, newFilterCountRequest(request, request.GetDsl()))
+	total := node.newQueryTask(gctx, newFilterCountRequest(request, ""))
+	for _, qt := range []*queryTask{filtered, total} {
+		qt := qt
+		group.Go(func() error {
+			rsp, err := node.query(gctx, qt)
+			return merr.CheckRPCCall(rsp, err)
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	counts := func(qt *queryTask) map[string]int64 {
+		ret := make(map[string]int64)
+		qt.channelCounts.Range(func(channel string, count int64) bool {
+			ret[channel] = count
+			return true
+		})
+		return ret
+	}
+	stats := collectFilterStats(counts(filtered), counts(total))
+	globalFilterSelectivityCache.put(request.GetDbName(), request.GetCollectionName(), request.GetDsl(), stats.shardSelectivity(), time.Now())
+	stats.Advice = adviseSearchParams(stats, request.GetSearchParams(), Params.ProxyCfg.FilterStatsAdvisorSelectivity.GetAsFloat())
+	return stats, nil
+}
+
+// attachFilterStats sets the filter stats in the extra info of the search results, the search results are returned
+// without the stats if failed to count the rows.
+func (node *Proxy) attachFilterStats(ctx context.Context, request *milvuspb.SearchRequest, rsp *milvuspb.SearchResults) *milvuspb.SearchResults {
+	if !merr.Ok(rsp.GetStatus()) || request.GetDsl() == "" {
+		return rsp
+	}
+	stats, err := node.getFilterStats(ctx, request)
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to get the filter stats of the search", zap.String("expr", request.GetDsl()), zap.Error(err))
+		return rsp
+	}
+	bs, err := json.Marshal(stats)
+	if err != nil {
+		return rsp
+	}
+	if rsp.Status == nil {
+		rsp.Status = merr.Success()
+	}
+	if rsp.Status.ExtraInfo == nil {
+		rsp.Status.ExtraInfo = make(map[string]string)
+	}
+	rsp.Status.ExtraInfo[filterStatsInfoKey] = string(bs)
+	return rsp
+}
@@ -0,0 +1,87 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+)
+
+func TestCollectFilterStats(t *testing.T) {
+	stats := collectFilterStats(map[string]int64{"ch1": 10}, map[string]int64{"ch2": 0, "ch1": 1000})
+	assert.Equal(t, int64(1000), stats.TotalRows)
+	assert.Equal(t, int64(10), stats.FilteredRows)
+	assert.InDelta(t, 0.01, stats.Selectivity, 1e-9)
+	assert.Len(t, stats.Shards, 2)
+	assert.Equal(t, "ch1", stats.Shards[0].Channel)
+	assert.InDelta(t, 0.01, stats.Shards[0].Selectivity, 1e-9)
+	// the empty shard is not selective
+	assert.Equal(t, 1.0, stats.Shards[1].Selectivity)
+}
+
+func TestAdviseSearchParams(t *testing.T) {
+	stats := collectFilterStats(map[string]int64{"ch1": 10, "ch2": 500}, map[string]int64{"ch1": 1000, "ch2": 1000})
+	searchParams := func(params string) []*commonpb.KeyValuePair {
+		return []*commonpb.KeyValuePair{{Key: TopKKey, Value: "10"}, {Key: SearchParamsKey, Value: params}}
+	}
+
+	advice := adviseSearchParams(stats, searchParams(`{"ef": 64}`), 0.05)
+	assert.Len(t, advice, 1)
+	assert.Contains(t, advice[0], "raise ef from 64 to 1000")
+
+	advice = adviseSearchParams(stats, searchParams(`{"nprobe": 16}`), 0.05)
+	assert.Len(t, advice, 1)
+	assert.Contains(t, advice[0], "raise nprobe from 16 to 1600")
+
+	// large enough already
+	assert.Empty(t, adviseSearchParams(stats, searchParams(`{"ef": 2000}`), 0.05))
+
+	advice = adviseSearchParams(stats, searchParams(""), 0.05)
+	assert.Len(t, advice, 1)
+	assert.Contains(t, advice[0], "ef or search_list to 1000")
+
+	// not selective enough to advise
+	assert.Empty(t, adviseSearchParams(stats, searchParams(`{"ef": 64}`), 0.001))
+	// the shards without any row kept are not searched for the topk
+	assert.Empty(t, adviseSearchParams(collectFilterStats(nil, map[string]int64{"ch1": 1000}), searchParams(`{"ef": 64}`), 0.05))
+}
+
+func TestNewFilterCountRequest(t *testing.T) {
+	request := &milvuspb.SearchRequest{
+		DbName:           "db",
+		CollectionName:   "coll",
+		Dsl:              "a > 1",
+		PartitionNames:   []string{"p1"},
+		ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+		SearchParams: []*commonpb.KeyValuePair{
+			{Key: TopKKey, Value: "10"},
+			{Key: IgnoreGrowingKey, Value: "true"},
+			{Key: FilterStatsKey, Value: "true"},
+		},
+	}
+	countRequest := newFilterCountRequest(request, request.GetDsl())
+	assert.Equal(t, "a > 1", countRequest.GetExpr())
+	assert.Equal(t, []string{"count(*)"}, countRequest.GetOutputFields())
+	assert.Equal(t, []string{"p1"}, countRequest.GetPartitionNames())
+	assert.Equal(t, commonpb.ConsistencyLevel_Strong, countRequest.GetConsistencyLevel())
+	assert.Equal(t, []*commonpb.KeyValuePair{{Key: IgnoreGrowingKey, Value: "true"}}, countRequest.GetQueryParams())
+	assert.Empty(t, newFilterCountRequest(request, "").GetExpr())
+}
@@ -0,0 +1,119 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// geoBBoxPattern matches geo_bbox(lat_field, lon_field, min_lat, min_lon, max_lat, max_lon), the points of the
+// latitude and longitude float fields within the bounding box in degrees.
+var geoBBoxPattern = func() *regexp.Regexp {
+	field := `([A-Za-z_][A-Za-z0-9_]*)`
+	number := `([-+]?\d+(?:\.\d+)?(?:[eE][-+]?\d+)?)`
+	sep := `\s*,\s*`
+	return regexp.MustCompile(`\bgeo_bbox\s*\(\s*` + field + sep + field + sep + number + sep + number + sep + number + sep + number + `\s*\)`)
+}()
+
+// expandFilterFunctions expands the convenience functions of the filter expression into the expressions the plan
+// parser accepts.
+func expandFilterFunctions(ctx context.Context, schema *schemapb.CollectionSchema, expr string) (string, error) {
+	expr, err := expandNullPredicates(schema, expr)
+	if err != nil {
+		return "", err
+	}
+	expr, err = expandGeoFunctions(expr)
+	if err != nil {
+		return "", err
+	}
+	expr, err = expandCompositeKeyFunctions(expr)
+	if err != nil {
+		return "", err
+	}
+	return expandRequestTimeFunctions(ctx, expr)
+}
+
+// geoBBoxExpr returns the range expression of the bounding box, the box crossing the antimeridian has the min
+// longitude greater than the max one.
+func geoBBoxExpr(latField, lonField string, minLat, minLon, maxLat, maxLon float64) (string, error) {
+	for _, lat := range []float64{minLat, maxLat} {
+		if lat < -90 || lat > 90 {
+			return "", merr.WrapErrParameterInvalidMsg("invalid latitude %v of geo_bbox, expect in [-90, 90]", lat)
+		}
+	}
+	for _, lon := range []float64{minLon, maxLon} {
+		if lon < -180 || lon > 180 {
+			return "", merr.WrapErrParameterInvalidMsg("invalid longitude %v of geo_bbox, expect in [-180, 180]", lon)
+		}
+	}
+	if minLat > maxLat {
+		return "", merr.WrapErrParameterInvalidMsg("the min latitude %v of geo_bbox is greater than the max latitude %v", minLat, maxLat)
+	}
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	latRange := fmt.Sprintf("%s <= %s <= %s", format(minLat), latField, format(maxLat))
+	if minLon <= maxLon {
+		return fmt.Sprintf("(%s and %s <= %s <= %s)", latRange, format(minLon), lonField, format(maxLon)), nil
+	}
+	return fmt.Sprintf("(%s and (%s >= %s or %s <= %s))", latRange, lonField, format(minLon), lonField, format(maxLon)), nil
+}
+
+// expandGeoFunctions replaces the geo_bbox functions outside the string literals of the expression with the range
+// expressions on the latitude and longitude fields, as there is no geo field type to filter. The radius and the
+// shape filters are not expressible as the range expressions, so the other geo functions are rejected.
+func expandGeoFunctions(expr string) (string, error) {
+	if !strings.Contains(expr, "geo_") {
+		return expr, nil
+	}
+	isUnsupported := func(name string) bool { return strings.HasPrefix(name, "geo_") && name != "geo_bbox" }
+	if _, err := replaceExprFunctions(expr, isUnsupported, func(name string, _ int) (string, int, error) {
+		return "", 0, merr.WrapErrParameterInvalidMsg("%s() is not supported, filter by geo_bbox(lat_field, lon_field, "+
+			"min_lat, min_lon, max_lat, max_lon) instead", name)
+	}); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	var expandErr error
+	splitStringLiterals(expr, func(part string, literal bool) {
+		if literal {
+			sb.WriteString(part)
+			return
+		}
+		sb.WriteString(geoBBoxPattern.ReplaceAllStringFunc(part, func(match string) string {
+			groups := geoBBoxPattern.FindStringSubmatch(match)
+			values := make([]float64, 4)
+			for i := range values {
+				values[i], _ = strconv.ParseFloat(groups[i+3], 64)
+			}
+			replaced, err := geoBBoxExpr(groups[1], groups[2], values[0], values[1], values[2], values[3])
+			if err != nil && expandErr == nil {
+				expandErr = err
+			}
+			return replaced
+		}))
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,72 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+func TestExpandGeoFunctions(t *testing.T) {
+	expr, err := expandGeoFunctions(`geo_bbox(lat, lon, 30, 120.5, 31.25, 121) and name == "geo_bbox(lat, lon, 100, 0, 0, 0)"`)
+	assert.NoError(t, err)
+	assert.Equal(t, `(30 <= lat <= 31.25 and 120.5 <= lon <= 121) and name == "geo_bbox(lat, lon, 100, 0, 0, 0)"`, expr)
+
+	// crossing the antimeridian
+	expr, err = expandGeoFunctions(`geo_bbox( lat ,lon, -10, 170, 10, -170 )`)
+	assert.NoError(t, err)
+	assert.Equal(t, `(-10 <= lat <= 10 and (lon >= 170 or lon <= -170))`, expr)
+
+	expr, err = expandGeoFunctions(`name == "geo_within_radius(lat, lon, 30, 120, 1000)"`)
+	assert.NoError(t, err)
+	assert.Equal(t, `name == "geo_within_radius(lat, lon, 30, 120, 1000)"`, expr)
+
+	expr, err = expandGeoFunctions(`id > 1`)
+	assert.NoError(t, err)
+	assert.Equal(t, `id > 1`, expr)
+
+	for _, invalid := range []string{
+		`geo_bbox(lat, lon, -91, 0, 0, 0)`,
+		`geo_bbox(lat, lon, 0, 0, 0, 181)`,
+		`geo_bbox(lat, lon, 10, 0, 0, 0)`,
+		`id > 1 and geo_within_radius(lat, lon, 30, 120, 1000)`,
+	} {
+		_, err = expandGeoFunctions(invalid)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid, invalid)
+	}
+
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "lat", DataType: schemapb.DataType_Double},
+			{FieldID: 102, Name: "lon", DataType: schemapb.DataType_Double},
+		},
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	assert.NoError(t, err)
+	expr, err = expandFilterFunctions(context.Background(), schema, `pk > 0 and geo_bbox(lat, lon, -10, 170, 10, -170)`)
+	assert.NoError(t, err)
+	_, err = planparserv2.CreateRetrievePlan(helper, expr)
+	assert.NoError(t, err)
+}
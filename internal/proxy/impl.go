@@ -82,6 +82,7 @@ func (node *Proxy) GetComponentStates(ctx context.Context, req *milvuspb.GetComp
 		NodeID:    nodeID,
 		Role:      typeutil.ProxyRole,
 		StateCode: code,
+		ExtraInfo: node.componentExtraInfo(),
 	}
 	stats.State = info
 	return stats, nil
@@ -142,6 +143,14 @@ func (node *Proxy) InvalidateCollectionMetaCache(ctx context.Context, request *p
 			}
 		case commonpb.MsgType_DropDatabase:
 			globalMetaCache.RemoveDatabase(ctx, request.GetDbName())
+		case commonpb.MsgType_AlterCollection:
+			// collection properties changed, shard leaders are still valid
+			if collectionName != "" {
+				globalMetaCache.RemoveCollection(ctx, request.GetDbName(), collectionName)
+			}
+			if request.CollectionID != UniqueID(0) {
+				globalMetaCache.RemoveCollectionsByID(ctx, collectionID)
+			}
 		default:
 			log.Warn("receive unexpected msgType of invalidate collection meta cache", zap.String("msgType", request.GetBase().GetMsgType().String()))
 
@@ -576,7 +585,7 @@ func (node *Proxy) DropCollection(ctx context.Context, request *milvuspb.DropCol
 	)
 
 	if err := dct.WaitToFinish(); err != nil {
-		log.Warn("DropCollection failed to WaitToFinish",
+		log.Warn(rpcFailedToWaitToFinish(method),
 			zap.Error(err),
 			zap.Uint64("BeginTs", dct.BeginTs()),
 			zap.Uint64("EndTs", dct.EndTs()))
@@ -660,7 +669,7 @@ func (node *Proxy) HasCollection(ctx context.Context, request *milvuspb.HasColle
 	)
 
 	if err := hct.WaitToFinish(); err != nil {
-		log.Warn("HasCollection failed to WaitToFinish",
+		log.Warn(rpcFailedToWaitToFinish(method),
 			zap.Error(err),
 			zap.Uint64("BeginTS", hct.BeginTs()),
 			zap.Uint64("EndTS", hct.EndTs()))
@@ -715,6 +724,7 @@ func (node *Proxy) LoadCollection(ctx context.Context, request *milvuspb.LoadCol
 		ctx:                   ctx,
 		Condition:             NewTaskCondition(ctx),
 		LoadCollectionRequest: request,
+		rootCoord:             node.rootCoord,
 		queryCoord:            node.queryCoord,
 		datacoord:             node.dataCoord,
 		replicateMsgStream:    node.replicateMsgStream,
@@ -729,6 +739,12 @@ func (node *Proxy) LoadCollection(ctx context.Context, request *milvuspb.LoadCol
 
 	log.Debug("LoadCollection received")
 
+	priority, err := getLoadPriority(ctx, request.GetBase())
+	if err != nil {
+		return merr.Status(err), nil
+	}
+	setLoadPriority(request, priority)
+
 	if err := node.sched.ddQueue.Enqueue(lct); err != nil {
 		log.Warn("LoadCollection failed to enqueue",
 			zap.Error(err))
@@ -745,7 +761,7 @@ func (node *Proxy) LoadCollection(ctx context.Context, request *milvuspb.LoadCol
 	)
 
 	if err := lct.WaitToFinish(); err != nil {
-		log.Warn("LoadCollection failed to WaitToFinish",
+		log.Warn(rpcFailedToWaitToFinish(method),
 			zap.Error(err),
 			zap.Uint64("BeginTS", lct.BeginTs()),
 			zap.Uint64("EndTS", lct.EndTs()))
@@ -772,6 +788,12 @@ func (node *Proxy) LoadCollection(ctx context.Context, request *milvuspb.LoadCol
 		method,
 	).Observe(float64(tr.ElapseSpan().Milliseconds()))
 
+	if merr.Ok(lct.result) {
+		node.addLoadJob(request.GetDbName(), request.GetCollectionName(), nil)
+		if isConfirmedByMetadata(ctx, loadWarmupKey) {
+			globalLoadWarmups.add(request)
+		}
+	}
 	return lct.result, nil
 }
 
@@ -791,6 +813,7 @@ func (node *Proxy) ReleaseCollection(ctx context.Context, request *milvuspb.Rele
 		ctx:                      ctx,
 		Condition:                NewTaskCondition(ctx),
 		ReleaseCollectionRequest: request,
+		rootCoord:                node.rootCoord,
 		queryCoord:               node.queryCoord,
 		replicateMsgStream:       node.replicateMsgStream,
 	}
@@ -885,7 +908,7 @@ func (node *Proxy) DescribeCollection(ctx context.Context, request *milvuspb.Des
 		zap.Uint64("EndTS", dct.EndTs()))
 
 	if err := dct.WaitToFinish(); err != nil {
-		log.Warn("DescribeCollection failed to WaitToFinish",
+		log.Warn(rpcFailedToWaitToFinish(method),
 			zap.Error(err),
 			zap.Uint64("BeginTS", dct.BeginTs()),
 			zap.Uint64("EndTS", dct.EndTs()))
@@ -934,6 +957,7 @@ func (node *Proxy) GetStatistics(ctx context.Context, request *milvuspb.GetStati
 		dc:        node.dataCoord,
 		qc:        node.queryCoord,
 		lb:        node.lbPolicy,
+		breakdown: isConfirmedByMetadata(ctx, statisticsBreakdownKey),
 	}
 
 	log := log.Ctx(ctx).With(
@@ -1020,6 +1044,29 @@ func (node *Proxy) GetCollectionStatistics(ctx context.Context, request *milvusp
 
 	log.Debug(rpcReceived(method))
 
+	consistency, err := getStatisticsConsistency(ctx)
+	if err == nil && consistency == statisticsConsistencyAccurate {
+		var stats []*commonpb.KeyValuePair
+		stats, err = node.getAccurateStatistics(ctx, request.GetDbName(), request.GetCollectionName(), nil)
+		if err == nil {
+			metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method,
+				metrics.SuccessLabel, request.GetDbName(), request.GetCollectionName()).Inc()
+			metrics.ProxyReqLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method).Observe(float64(tr.ElapseSpan().Milliseconds()))
+			return &milvuspb.GetCollectionStatisticsResponse{
+				Status: merr.Success(),
+				Stats:  stats,
+			}, nil
+		}
+	}
+	if err != nil {
+		log.Warn("failed to get accurate statistics", zap.Error(err))
+		metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method,
+			metrics.FailLabel, request.GetDbName(), request.GetCollectionName()).Inc()
+		return &milvuspb.GetCollectionStatisticsResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
 	if err := node.sched.ddQueue.Enqueue(g); err != nil {
 		log.Warn(
 			rpcFailedToEnqueue(method),
@@ -1061,6 +1108,9 @@ func (node *Proxy) GetCollectionStatistics(ctx context.Context, request *milvusp
 	metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method,
 		metrics.SuccessLabel, request.GetDbName(), request.GetCollectionName()).Inc()
 	metrics.ProxyReqLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method).Observe(float64(tr.ElapseSpan().Milliseconds()))
+	if consistency == statisticsConsistencyFast {
+		g.result.Stats = withStatisticsTimestamp(g.result.GetStats(), consistency, g.BeginTs())
+	}
 	return g.result, nil
 }
 
@@ -1113,7 +1163,7 @@ func (node *Proxy) ShowCollections(ctx context.Context, request *milvuspb.ShowCo
 
 	err = sct.WaitToFinish()
 	if err != nil {
-		log.Warn("ShowCollections failed to WaitToFinish",
+		log.Warn(rpcFailedToWaitToFinish(method),
 			zap.Error(err),
 			zap.Any("CollectionNames", request.CollectionNames))
 
@@ -1423,6 +1473,7 @@ func (node *Proxy) LoadPartitions(ctx context.Context, request *milvuspb.LoadPar
 		ctx:                   ctx,
 		Condition:             NewTaskCondition(ctx),
 		LoadPartitionsRequest: request,
+		rootCoord:             node.rootCoord,
 		queryCoord:            node.queryCoord,
 		datacoord:             node.dataCoord,
 		replicateMsgStream:    node.replicateMsgStream,
@@ -1474,6 +1525,9 @@ func (node *Proxy) LoadPartitions(ctx context.Context, request *milvuspb.LoadPar
 	metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method,
 		metrics.SuccessLabel, request.GetDbName(), request.GetCollectionName()).Inc()
 	metrics.ProxyReqLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method).Observe(float64(tr.ElapseSpan().Milliseconds()))
+	if merr.Ok(lpt.result) {
+		node.addLoadJob(request.GetDbName(), request.GetCollectionName(), request.GetPartitionNames())
+	}
 	return lpt.result, nil
 }
 
@@ -1577,6 +1631,29 @@ func (node *Proxy) GetPartitionStatistics(ctx context.Context, request *milvuspb
 
 	log.Debug(rpcReceived(method))
 
+	consistency, err := getStatisticsConsistency(ctx)
+	if err == nil && consistency == statisticsConsistencyAccurate {
+		var stats []*commonpb.KeyValuePair
+		stats, err = node.getAccurateStatistics(ctx, request.GetDbName(), request.GetCollectionName(), []string{request.GetPartitionName()})
+		if err == nil {
+			metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method,
+				metrics.SuccessLabel, request.GetDbName(), request.GetCollectionName()).Inc()
+			metrics.ProxyReqLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method).Observe(float64(tr.ElapseSpan().Milliseconds()))
+			return &milvuspb.GetPartitionStatisticsResponse{
+				Status: merr.Success(),
+				Stats:  stats,
+			}, nil
+		}
+	}
+	if err != nil {
+		log.Warn("failed to get accurate statistics", zap.Error(err))
+		metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method,
+			metrics.FailLabel, request.GetDbName(), request.GetCollectionName()).Inc()
+		return &milvuspb.GetPartitionStatisticsResponse{
+			Status: merr.Status(err),
+		}, nil
+	}
+
 	if err := node.sched.ddQueue.Enqueue(g); err != nil {
 		log.Warn(
 			rpcFailedToEnqueue(method),
@@ -1618,6 +1695,9 @@ func (node *Proxy) GetPartitionStatistics(ctx context.Context, request *milvuspb
 	metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method,
 		metrics.SuccessLabel, request.GetDbName(), request.GetCollectionName()).Inc()
 	metrics.ProxyReqLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method).Observe(float64(tr.ElapseSpan().Milliseconds()))
+	if consistency == statisticsConsistencyFast {
+		g.result.Stats = withStatisticsTimestamp(g.result.GetStats(), consistency, g.BeginTs())
+	}
 	return g.result, nil
 }
 
@@ -1949,6 +2029,9 @@ func (node *Proxy) CreateIndex(ctx context.Context, request *milvuspb.CreateInde
 	metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method,
 		metrics.SuccessLabel, request.GetDbName(), request.GetCollectionName()).Inc()
 	metrics.ProxyReqLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method).Observe(float64(tr.ElapseSpan().Milliseconds()))
+	if merr.Ok(cit.result) {
+		node.addIndexJob(cit.req)
+	}
 	return cit.result, nil
 }
 
@@ -2405,6 +2488,12 @@ func (node *Proxy) Insert(ctx context.Context, request *milvuspb.InsertRequest)
 			Status: merr.Status(err),
 		}, nil
 	}
+	if err := node.checkTimeTickLagAdmission(); err != nil {
+		return &milvuspb.MutationResult{
+			Status: merr.Status(err),
+		}, nil
+	}
+	mirror := node.newTrafficMirror(ctx, request)
 	log := log.Ctx(ctx).With(
 		zap.String("role", typeutil.ProxyRole),
 		zap.String("db", request.DbName),
@@ -2416,9 +2505,11 @@ func (node *Proxy) Insert(ctx context.Context, request *milvuspb.InsertRequest)
 	)
 	method := "Insert"
 	tr := timerecord.NewTimeRecorder(method)
+	size := proto.Size(request)
 	metrics.ProxyReceiveBytes.WithLabelValues(
 		strconv.FormatInt(paramtable.GetNodeID(), 10),
-		metrics.InsertLabel, request.GetCollectionName()).Add(float64(proto.Size(request)))
+		metrics.InsertLabel, request.GetCollectionName()).Add(float64(size))
+	observeMutationBatch(metrics.InsertLabel, request.GetCollectionName(), request.GetNumRows(), size, request.GetFieldsData())
 	metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method, metrics.TotalLabel, request.GetDbName(), request.GetCollectionName()).Inc()
 
 	it := &insertTask{
@@ -2460,6 +2551,13 @@ func (node *Proxy) Insert(ctx context.Context, request *milvuspb.InsertRequest)
 		}
 	}
 
+	if err := node.throttleInsert(ctx, request.GetDbName(), request.GetCollectionName(), size); err != nil {
+		log.Warn("Failed to throttle insert request", zap.Error(err))
+		metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method,
+			metrics.AbandonLabel, request.GetDbName(), request.GetCollectionName()).Inc()
+		return constructFailedResponse(err), nil
+	}
+
 	log.Debug("Enqueue insert request in Proxy")
 
 	if err := node.sched.dmQueue.Enqueue(it); err != nil {
@@ -2514,6 +2612,7 @@ func (node *Proxy) Insert(ctx context.Context, request *milvuspb.InsertRequest)
 		hookutil.FailCntKey:         len(it.result.ErrIndex),
 	})
 	SetReportValue(it.result.GetStatus(), v)
+	recordSessionMutation(ctx, it.insertMsg.GetCollectionID(), it.result.GetStatus(), it.result.GetTimestamp())
 	if merr.Ok(it.result.GetStatus()) {
 		metrics.ProxyReportValue.WithLabelValues(nodeID, hookutil.OpTypeInsert, request.DbName, username).Add(float64(v))
 	}
@@ -2526,6 +2625,7 @@ func (node *Proxy) Insert(ctx context.Context, request *milvuspb.InsertRequest)
 	metrics.ProxyCollectionMutationLatency.
 		WithLabelValues(nodeID, metrics.InsertLabel, collectionName).
 		Observe(float64(tr.ElapseSpan().Milliseconds()))
+	mirror.run(it.result, nil)
 	return it.result, nil
 }
 
@@ -2552,6 +2652,12 @@ func (node *Proxy) Delete(ctx context.Context, request *milvuspb.DeleteRequest)
 			Status: merr.Status(err),
 		}, nil
 	}
+	if err := node.checkTimeTickLagAdmission(); err != nil {
+		return &milvuspb.MutationResult{
+			Status: merr.Status(err),
+		}, nil
+	}
+	mirror := node.newTrafficMirror(ctx, request)
 
 	method := "Delete"
 	tr := timerecord.NewTimeRecorder(method)
@@ -2611,6 +2717,8 @@ func (node *Proxy) Delete(ctx context.Context, request *milvuspb.DeleteRequest)
 		hookutil.RelatedCntKey: dr.allQueryCnt.Load(),
 	})
 	SetReportValue(dr.result.GetStatus(), v)
+	setChannelPositions(dr.result.GetStatus(), dr.positions)
+	recordSessionMutation(ctx, dr.collectionID, dr.result.GetStatus(), dr.result.GetTimestamp())
 
 	if merr.Ok(dr.result.GetStatus()) {
 		metrics.ProxyReportValue.WithLabelValues(nodeID, hookutil.OpTypeDelete, dbName, username).Add(float64(v))
@@ -2621,6 +2729,7 @@ func (node *Proxy) Delete(ctx context.Context, request *milvuspb.DeleteRequest)
 		WithLabelValues(nodeID, metrics.DeleteLabel, dbName, collectionName).
 		Observe(float64(tr.ElapseSpan().Milliseconds()))
 	metrics.ProxyCollectionMutationLatency.WithLabelValues(nodeID, metrics.DeleteLabel, collectionName).Observe(float64(tr.ElapseSpan().Milliseconds()))
+	mirror.run(dr.result, nil)
 	return dr.result, nil
 }
 
@@ -2643,12 +2752,20 @@ func (node *Proxy) Upsert(ctx context.Context, request *milvuspb.UpsertRequest)
 			Status: merr.Status(err),
 		}, nil
 	}
+	if err := node.checkTimeTickLagAdmission(); err != nil {
+		return &milvuspb.MutationResult{
+			Status: merr.Status(err),
+		}, nil
+	}
+	mirror := node.newTrafficMirror(ctx, request)
 	method := "Upsert"
 	tr := timerecord.NewTimeRecorder(method)
 
+	size := proto.Size(request)
 	metrics.ProxyReceiveBytes.WithLabelValues(
 		strconv.FormatInt(paramtable.GetNodeID(), 10),
-		metrics.UpsertLabel, request.GetCollectionName()).Add(float64(proto.Size(request)))
+		metrics.UpsertLabel, request.GetCollectionName()).Add(float64(size))
+	observeMutationBatch(metrics.UpsertLabel, request.GetCollectionName(), request.GetNumRows(), size, request.GetFieldsData())
 	metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method, metrics.TotalLabel, request.GetDbName(), request.GetCollectionName()).Inc()
 
 	request.Base = commonpbutil.NewMsgBase(
@@ -2676,6 +2793,15 @@ func (node *Proxy) Upsert(ctx context.Context, request *milvuspb.UpsertRequest)
 		chTicker:      node.chTicker,
 	}
 
+	if err := node.throttleInsert(ctx, request.GetDbName(), request.GetCollectionName(), size); err != nil {
+		log.Info("Failed to throttle upsert request", zap.Error(err))
+		metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method,
+			metrics.AbandonLabel, request.GetDbName(), request.GetCollectionName()).Inc()
+		return &milvuspb.MutationResult{
+			Status: merr.Status(err),
+		}, nil
+	}
+
 	log.Debug("Enqueue upsert request in Proxy",
 		zap.Int("len(FieldsData)", len(request.FieldsData)),
 		zap.Int("len(HashKeys)", len(request.HashKeys)))
@@ -2745,6 +2871,7 @@ func (node *Proxy) Upsert(ctx context.Context, request *milvuspb.UpsertRequest)
 		hookutil.FailCntKey:         len(it.result.ErrIndex),
 	})
 	SetReportValue(it.result.GetStatus(), v)
+	recordSessionMutation(ctx, it.collectionID, it.result.GetStatus(), it.result.GetTimestamp())
 	if merr.Ok(it.result.GetStatus()) {
 		metrics.ProxyReportValue.WithLabelValues(nodeID, hookutil.OpTypeUpsert, dbName, username).Add(float64(v))
 	}
@@ -2765,6 +2892,7 @@ func (node *Proxy) Upsert(ctx context.Context, request *milvuspb.UpsertRequest)
 	metrics.ProxyCollectionMutationLatency.WithLabelValues(nodeID, metrics.UpsertLabel, collectionName).Observe(float64(tr.ElapseSpan().Milliseconds()))
 
 	log.Debug("Finish processing upsert request in Proxy")
+	mirror.run(it.result, nil)
 	return it.result, nil
 }
 
@@ -2781,15 +2909,39 @@ func GetCollectionRateSubLabel(req any) string {
 }
 
 // Search searches the most similar records of requests.
-func (node *Proxy) Search(ctx context.Context, request *milvuspb.SearchRequest) (*milvuspb.SearchResults, error) {
-	var err error
-	rsp := &milvuspb.SearchResults{
+func (node *Proxy) Search(ctx context.Context, request *milvuspb.SearchRequest) (rsp *milvuspb.SearchResults, err error) {
+	// the search over the nq or the size limits is executed as the sub searches within the limits
+	if requests := splitSearchRequest(request); len(requests) > 1 {
+		return node.searchSplit(ctx, request, requests)
+	}
+	dbName, collectionName := request.GetDbName(), request.GetCollectionName()
+	if err := globalCircuitBreakers.allow(dbName, collectionName, metrics.SearchLabel); err != nil {
+		return &milvuspb.SearchResults{
+			Status: merr.Status(err),
+		}, nil
+	}
+	// every allowed request must be recorded, otherwise the probe of half-open breaker is never finished
+	defer func() {
+		globalCircuitBreakers.record(dbName, collectionName, merr.CheckRPCCall(rsp, err))
+	}()
+
+	withFilterStats, err := parseBoolSearchParam(FilterStatsKey, request.GetSearchParams())
+	if err != nil {
+		return &milvuspb.SearchResults{
+			Status: merr.Status(err),
+		}, nil
+	}
+	rsp = &milvuspb.SearchResults{
 		Status: merr.Success(),
 	}
+	route := routeCanary(ctx, request)
+	mirror := node.newTrafficMirror(ctx, request)
+	retrier := &schemaMismatchRetrier{}
 	err2 := retry.Handle(ctx, func() (bool, error) {
 		rsp, err = node.
 			search(ctx, request)
-		if errors.Is(merr.Error(rsp.GetStatus()), merr.ErrInconsistentRequery) {
+		if errors.Is(merr.Error(rsp.GetStatus()), merr.ErrInconsistentRequery) ||
+			retrier.shouldRetry(ctx, dbName, collectionName, rsp.GetStatus()) {
 			return true, merr.Error(rsp.GetStatus())
 		}
 		return false, nil
@@ -2797,6 +2949,17 @@ func (node *Proxy) Search(ctx context.Context, request *milvuspb.SearchRequest)
 	if err2 != nil {
 		rsp.Status = merr.Status(err2)
 	}
+	route.finish(node, rsp, err)
+	mirror.run(rsp, err)
+	if err == nil && withFilterStats {
+		rsp = node.attachFilterStats(ctx, request, rsp)
+	} else if err == nil && merr.Ok(rsp.GetStatus()) {
+		node.refreshFilterSelectivity(request)
+	}
+	// the result of the sub search is limited once merged
+	if err == nil && ctx.Value(searchSplitKey{}) == nil {
+		rsp = limitSearchResultSize(ctx, rsp, request.GetSearchParams())
+	}
 	return rsp, err
 }
 
@@ -2847,6 +3010,13 @@ func (node *Proxy) search(ctx context.Context, request *milvuspb.SearchRequest)
 		request.PlaceholderGroup = placeholderGroupBytes
 	}
 
+	// the preset must be applied before enqueue, it may change the consistency level
+	if err := applySearchPreset(ctx, request); err != nil {
+		return &milvuspb.SearchResults{
+			Status: merr.Status(err),
+		}, nil
+	}
+
 	qt := &searchTask{
 		ctx:       ctx,
 		Condition: NewTaskCondition(ctx),
@@ -2860,6 +3030,7 @@ func (node *Proxy) search(ctx context.Context, request *milvuspb.SearchRequest)
 		request:                request,
 		tr:                     timerecord.NewTimeRecorder("search"),
 		qc:                     node.queryCoord,
+		dc:                     node.dataCoord,
 		node:                   node,
 		lb:                     node.lbPolicy,
 		enableMaterializedView: node.enableMaterializedView,
@@ -3003,9 +3174,11 @@ func (node *Proxy) HybridSearch(ctx context.Context, request *milvuspb.HybridSea
 	rsp := &milvuspb.SearchResults{
 		Status: merr.Success(),
 	}
+	retrier := &schemaMismatchRetrier{}
 	err2 := retry.Handle(ctx, func() (bool, error) {
 		rsp, err = node.hybridSearch(ctx, request)
-		if errors.Is(merr.Error(rsp.GetStatus()), merr.ErrInconsistentRequery) {
+		if errors.Is(merr.Error(rsp.GetStatus()), merr.ErrInconsistentRequery) ||
+			retrier.shouldRetry(ctx, request.GetDbName(), request.GetCollectionName(), rsp.GetStatus()) {
 			return true, merr.Error(rsp.GetStatus())
 		}
 		return false, nil
@@ -3013,6 +3186,9 @@ func (node *Proxy) HybridSearch(ctx context.Context, request *milvuspb.HybridSea
 	if err2 != nil {
 		rsp.Status = merr.Status(err2)
 	}
+	if err == nil {
+		rsp = limitSearchResultSize(ctx, rsp, request.GetRankParams())
+	}
 	return rsp, err
 }
 
@@ -3063,6 +3239,7 @@ func (node *Proxy) hybridSearch(ctx context.Context, request *milvuspb.HybridSea
 		request:             newSearchReq,
 		tr:                  timerecord.NewTimeRecorder(method),
 		qc:                  node.queryCoord,
+		dc:                  node.dataCoord,
 		node:                node,
 		lb:                  node.lbPolicy,
 		mustUsePartitionKey: Params.ProxyCfg.MustUsePartitionKey.GetAsBool(),
@@ -3319,6 +3496,7 @@ func (node *Proxy) Flush(ctx context.Context, request *milvuspb.FlushRequest) (*
 
 	metrics.ProxyFunctionCall.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method, metrics.SuccessLabel, request.GetDbName(), "").Inc()
 	metrics.ProxyReqLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), method).Observe(float64(tr.ElapseSpan().Milliseconds()))
+	node.addFlushJobs(ft.result)
 	return ft.result, nil
 }
 
@@ -3463,9 +3641,8 @@ func (node *Proxy) query(ctx context.Context, qt *queryTask) (*milvuspb.QueryRes
 	return qt.result, nil
 }
 
-// Query get the records by primary keys.
-func (node *Proxy) Query(ctx context.Context, request *milvuspb.QueryRequest) (*milvuspb.QueryResults, error) {
-	qt := &queryTask{
+func (node *Proxy) newQueryTask(ctx context.Context, request *milvuspb.QueryRequest) *queryTask {
+	return &queryTask{
 		ctx:       ctx,
 		Condition: NewTaskCondition(ctx),
 		RetrieveRequest: &internalpb.RetrieveRequest{
@@ -3477,10 +3654,46 @@ func (node *Proxy) Query(ctx context.Context, request *milvuspb.QueryRequest) (*
 		},
 		request:             request,
 		qc:                  node.queryCoord,
+		dc:                  node.dataCoord,
 		lb:                  node.lbPolicy,
 		mustUsePartitionKey: Params.ProxyCfg.MustUsePartitionKey.GetAsBool(),
 	}
-	res, err := node.query(ctx, qt)
+}
+
+// Query get the records by primary keys.
+func (node *Proxy) Query(ctx context.Context, request *milvuspb.QueryRequest) (res *milvuspb.QueryResults, err error) {
+	dbName, collectionName := request.GetDbName(), request.GetCollectionName()
+	if err := globalCircuitBreakers.allow(dbName, collectionName, metrics.QueryLabel); err != nil {
+		return &milvuspb.QueryResults{
+			Status: merr.Status(err),
+		}, nil
+	}
+	// every allowed request must be recorded, otherwise the probe of half-open breaker is never finished
+	defer func() {
+		globalCircuitBreakers.record(dbName, collectionName, merr.CheckRPCCall(res, err))
+	}()
+
+	route := routeCanary(ctx, request)
+	totalCount, err := node.startQueryTotalCount(ctx, request)
+	if err != nil {
+		return &milvuspb.QueryResults{
+			Status: merr.Status(err),
+		}, nil
+	}
+	mirror := node.newTrafficMirror(ctx, request)
+	qt := node.newQueryTask(ctx, request)
+	res, err = node.query(ctx, qt)
+	if err == nil && (&schemaMismatchRetrier{}).shouldRetry(ctx, dbName, collectionName, res.GetStatus()) {
+		qt = node.newQueryTask(ctx, request)
+		res, err = node.query(ctx, qt)
+	}
+	route.finish(node, res, err)
+	mirror.run(res, err)
+	if err == nil {
+		res = node.enrichQueryResults(ctx, request, res)
+		res = totalCount.attach(res)
+		res = limitQueryResultSize(ctx, res, request.GetQueryParams())
+	}
 	if merr.Ok(res.Status) && err == nil {
 		username := GetCurUserFromContextOrDefault(ctx)
 		nodeID := paramtable.GetStringNodeID()
@@ -4216,6 +4429,21 @@ func (node *Proxy) GetMetrics(ctx context.Context, req *milvuspb.GetMetricsReque
 		return metrics, nil
 	}
 
+	if metricType == metricsinfo.TaskEventsMetrics {
+		metrics, err := getTaskEventsMetrics(node)
+		if err != nil {
+			log.Warn("Proxy.GetMetrics failed to getTaskEventsMetrics",
+				zap.Int64("nodeID", paramtable.GetNodeID()),
+				zap.Error(err))
+
+			return &milvuspb.GetMetricsResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+
+		return metrics, nil
+	}
+
 	log.RatedWarn(60, "Proxy.GetMetrics failed, request metric type is not implemented yet",
 		zap.Int64("nodeID", paramtable.GetNodeID()),
 		zap.String("req", req.Request),
@@ -4277,6 +4505,20 @@ func (node *Proxy) GetProxyMetrics(ctx context.Context, req *milvuspb.GetMetrics
 		return proxyMetrics, nil
 	}
 
+	if metricType == metricsinfo.TaskEventsMetrics {
+		metrics, err := getTaskEventsMetrics(node)
+		if err != nil {
+			log.Warn("Proxy.GetProxyMetrics failed to getTaskEventsMetrics",
+				zap.Error(err))
+
+			return &milvuspb.GetMetricsResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+
+		return metrics, nil
+	}
+
 	log.Warn("Proxy.GetProxyMetrics failed, request metric type is not implemented yet",
 		zap.String("metricType", metricType))
 
@@ -4422,6 +4664,9 @@ func (node *Proxy) ManualCompaction(ctx context.Context, req *milvuspb.ManualCom
 	log.Info("received ManualCompaction response",
 		zap.Any("resp", resp),
 		zap.Error(err))
+	if err == nil && merr.Ok(resp.GetStatus()) {
+		node.addCompactionJob(ctx, resp.GetCompactionID(), req.GetCollectionID())
+	}
 	return resp, err
 }
 
@@ -4677,6 +4922,7 @@ func (node *Proxy) InvalidateCredentialCache(ctx context.Context, request *proxy
 	if globalMetaCache != nil {
 		globalMetaCache.RemoveCredential(username) // no need to return error, though credential may be not cached
 	}
+	globalPasswordPolicy.Invalidate(username)
 	log.Debug("complete to invalidate credential cache")
 
 	return merr.Success(), nil
@@ -4703,6 +4949,7 @@ func (node *Proxy) UpdateCredentialCache(ctx context.Context, request *proxypb.U
 	if globalMetaCache != nil {
 		globalMetaCache.UpdateCredential(credInfo) // no need to return error, though credential may be not cached
 	}
+	globalPasswordPolicy.Invalidate(request.GetUsername())
 	log.Debug("complete to update credential cache")
 
 	return merr.Success(), nil
@@ -4732,7 +4979,7 @@ func (node *Proxy) CreateCredential(ctx context.Context, req *milvuspb.CreateCre
 		err = errors.Wrap(err, "decode password fail")
 		return merr.Status(err), nil
 	}
-	if err = ValidatePassword(rawPassword); err != nil {
+	if err = globalPasswordPolicy.Validate(username, rawPassword); err != nil {
 		log.Error("illegal password",
 			zap.Error(err))
 		return merr.Status(err), nil
@@ -4756,6 +5003,9 @@ func (node *Proxy) CreateCredential(ctx context.Context, req *milvuspb.CreateCre
 			zap.Error(err))
 		return merr.Status(err), nil
 	}
+	if merr.Ok(result) {
+		globalPasswordPolicy.Record(ctx, username, encryptedPassword)
+	}
 	return result, err
 }
 
@@ -4786,7 +5036,7 @@ func (node *Proxy) UpdateCredential(ctx context.Context, req *milvuspb.UpdateCre
 		return merr.Status(err), nil
 	}
 	// valid new password
-	if err = ValidatePassword(rawNewPassword); err != nil {
+	if err = globalPasswordPolicy.Validate(req.GetUsername(), rawNewPassword); err != nil {
 		log.Error("illegal password",
 			zap.Error(err))
 		return merr.Status(err), nil
@@ -4824,6 +5074,9 @@ func (node *Proxy) UpdateCredential(ctx context.Context, req *milvuspb.UpdateCre
 			zap.Error(err))
 		return merr.Status(err), nil
 	}
+	if merr.Ok(result) {
+		globalPasswordPolicy.Record(ctx, req.GetUsername(), encryptedPassword)
+	}
 	return result, err
 }
 
@@ -4850,6 +5103,11 @@ func (node *Proxy) DeleteCredential(ctx context.Context, req *milvuspb.DeleteCre
 			zap.Error(err))
 		return merr.Status(err), nil
 	}
+	if merr.Ok(result) {
+		if err := globalPasswordPolicy.Remove(req.GetUsername()); err != nil {
+			log.Warn("failed to remove credential record", zap.Error(err))
+		}
+	}
 	return result, err
 }
 
@@ -4933,6 +5191,13 @@ func (node *Proxy) DropRole(ctx context.Context, req *milvuspb.DropRoleRequest)
 			zap.Error(err))
 		return merr.Status(err), nil
 	}
+	if merr.Ok(result) {
+		if err := globalMaskingPolicy.RemoveRole(req.GetRoleName()); err != nil {
+			log.Warn("fail to remove masking rules of the dropped role",
+				zap.String("role_name", req.RoleName),
+				zap.Error(err))
+		}
+	}
 	return result, nil
 }
 
@@ -5187,11 +5452,13 @@ func (node *Proxy) SetRates(ctx context.Context, request *proxypb.SetRatesReques
 	}
 
 	err := node.simpleLimiter.SetRates(request.GetRootLimiter())
-	// TODO: set multiple rate limiter rates
 	if err != nil {
 		resp = merr.Status(err)
 		return resp, nil
 	}
+	if err := globalRatesSnapshot.Save(request.GetRootLimiter()); err != nil {
+		log.Ctx(ctx).Warn("failed to persist rates", zap.Error(err))
+	}
 
 	return resp, nil
 }
@@ -5248,6 +5515,7 @@ func (node *Proxy) CheckHealth(ctx context.Context, request *milvuspb.CheckHealt
 	})
 
 	err := group.Wait()
+	errReasons = append(errReasons, node.timeTickLagReasons()...)
 	if err != nil || len(errReasons) != 0 {
 		return &milvuspb.CheckHealthResponse{
 			Status:    merr.Success(),
@@ -5338,7 +5606,7 @@ func (node *Proxy) CreateResourceGroup(ctx context.Context, request *milvuspb.Cr
 		zap.Uint64("EndTS", t.EndTs()))
 
 	if err := t.WaitToFinish(); err != nil {
-		log.Warn("CreateResourceGroup failed to WaitToFinish",
+		log.Warn(rpcFailedToWaitToFinish(method),
 			zap.Error(err),
 			zap.Uint64("BeginTS", t.BeginTs()),
 			zap.Uint64("EndTS", t.EndTs()))
@@ -5398,7 +5666,7 @@ func (node *Proxy) UpdateResourceGroups(ctx context.Context, request *milvuspb.U
 		zap.Uint64("EndTS", t.EndTs()))
 
 	if err := t.WaitToFinish(); err != nil {
-		log.Warn("UpdateResourceGroups failed to WaitToFinish",
+		log.Warn(rpcFailedToWaitToFinish(method),
 			zap.Error(err),
 			zap.Uint64("BeginTS", t.BeginTs()),
 			zap.Uint64("EndTS", t.EndTs()))
@@ -5456,7 +5724,7 @@ func (node *Proxy) DropResourceGroup(ctx context.Context, request *milvuspb.Drop
 		zap.Uint64("EndTS", t.EndTs()))
 
 	if err := t.WaitToFinish(); err != nil {
-		log.Warn("DropResourceGroup failed to WaitToFinish",
+		log.Warn(rpcFailedToWaitToFinish(method),
 			zap.Error(err),
 			zap.Uint64("BeginTS", t.BeginTs()),
 			zap.Uint64("EndTS", t.EndTs()))
@@ -5523,7 +5791,7 @@ func (node *Proxy) TransferNode(ctx context.Context, request *milvuspb.TransferN
 		zap.Uint64("EndTS", t.EndTs()))
 
 	if err := t.WaitToFinish(); err != nil {
-		log.Warn("TransferNode failed to WaitToFinish",
+		log.Warn(rpcFailedToWaitToFinish(method),
 			zap.Error(err),
 			zap.Uint64("BeginTS", t.BeginTs()),
 			zap.Uint64("EndTS", t.EndTs()))
@@ -5590,7 +5858,7 @@ func (node *Proxy) TransferReplica(ctx context.Context, request *milvuspb.Transf
 		zap.Uint64("EndTS", t.EndTs()))
 
 	if err := t.WaitToFinish(); err != nil {
-		log.Warn("TransferReplica failed to WaitToFinish",
+		log.Warn(rpcFailedToWaitToFinish(method),
 			zap.Error(err),
 			zap.Uint64("BeginTS", t.BeginTs()),
 			zap.Uint64("EndTS", t.EndTs()))
@@ -5649,7 +5917,7 @@ func (node *Proxy) ListResourceGroups(ctx context.Context, request *milvuspb.Lis
 		zap.Uint64("EndTS", t.EndTs()))
 
 	if err := t.WaitToFinish(); err != nil {
-		log.Warn("ListResourceGroups failed to WaitToFinish",
+		log.Warn(rpcFailedToWaitToFinish(method),
 			zap.Error(err),
 			zap.Uint64("BeginTS", t.BeginTs()),
 			zap.Uint64("EndTS", t.EndTs()))
@@ -5716,7 +5984,7 @@ func (node *Proxy) DescribeResourceGroup(ctx context.Context, request *milvuspb.
 		zap.Uint64("EndTS", t.EndTs()))
 
 	if err := t.WaitToFinish(); err != nil {
-		log.Warn("DescribeResourceGroup failed to WaitToFinish",
+		log.Warn(rpcFailedToWaitToFinish(method),
 			zap.Error(err),
 			zap.Uint64("BeginTS", t.BeginTs()),
 			zap.Uint64("EndTS", t.EndTs()))
@@ -5739,10 +6007,28 @@ func (node *Proxy) ListIndexedSegment(ctx context.Context, request *federpb.List
 	}, nil
 }
 
+// DescribeSegmentIndexData returns the index type, the build version, the files and the state of the index on the
+// segments, with whether and why the segments are searched by brute force.
 func (node *Proxy) DescribeSegmentIndexData(ctx context.Context, request *federpb.DescribeSegmentIndexDataRequest) (*federpb.DescribeSegmentIndexDataResponse, error) {
-	return &federpb.DescribeSegmentIndexDataResponse{
-		Status: merr.Status(merr.WrapErrServiceUnavailable("unimp")),
-	}, nil
+	ctx, sp := otel.Tracer(typeutil.ProxyRole).Start(ctx, "Proxy-DescribeSegmentIndexData")
+	defer sp.End()
+
+	log := log.Ctx(ctx).With(
+		zap.String("collection", request.GetCollectionName()),
+		zap.String("index", request.GetIndexName()),
+		zap.Int64s("segments", request.GetSegmentsIDs()))
+
+	log.Debug("received DescribeSegmentIndexData request")
+	if err := merr.CheckHealthy(node.GetStateCode()); err != nil {
+		return &federpb.DescribeSegmentIndexDataResponse{Status: merr.Status(err)}, nil
+	}
+
+	resp, err := node.describeSegmentIndexData(ctx, request)
+	if err != nil {
+		log.Warn("failed to describe segment index data", zap.Error(err))
+		return &federpb.DescribeSegmentIndexDataResponse{Status: merr.Status(err)}, nil
+	}
+	return resp, nil
 }
 
 func (node *Proxy) Connect(ctx context.Context, request *milvuspb.ConnectRequest) (*milvuspb.ConnectResponse, error) {
@@ -5787,20 +6073,11 @@ func (node *Proxy) Connect(ctx context.Context, request *milvuspb.ConnectRequest
 		}, nil
 	}
 
-	serverInfo := &commonpb.ServerInfo{
-		BuildTags:  os.Getenv(metricsinfo.GitBuildTagsEnvKey),
-		BuildTime:  os.Getenv(metricsinfo.MilvusBuildTimeEnvKey),
-		GitCommit:  os.Getenv(metricsinfo.GitCommitEnvKey),
-		GoVersion:  os.Getenv(metricsinfo.MilvusUsedGoVersion),
-		DeployMode: os.Getenv(metricsinfo.DeployModeEnvKey),
-		Reserved:   make(map[string]string),
-	}
-
 	connection.GetManager().Register(ctx, int64(ts), request.GetClientInfo())
 
 	return &milvuspb.ConnectResponse{
 		Status:     merr.Success(),
-		ServerInfo: serverInfo,
+		ServerInfo: getServerInfo().toProto(),
 		Identifier: int64(ts),
 	}, nil
 }
@@ -5992,6 +6269,41 @@ func (node *Proxy) ImportV2(ctx context.Context, req *internalpb.ImportRequest)
 
 	isBackup := importutilv2.IsBackup(req.GetOptions())
 	hasPartitionKey := typeutil.HasPartitionKey(schema.CollectionSchema)
+	// the partition to load after import, the whole collection is loaded if not specified
+	loadPartitionName := req.GetPartitionName()
+
+	if importutilv2.IsExternalStorage(req.GetOptions()) {
+		if !Params.DataCoordCfg.ImportExternalStorage.GetAsBool() {
+			resp.Status = merr.Status(merr.WrapErrImportFailed("import from external storage is disabled"))
+			return resp, nil
+		}
+		if isBackup {
+			resp.Status = merr.Status(merr.WrapErrImportFailed("not allow to import backup from external storage"))
+			return resp, nil
+		}
+		if _, err := importutilv2.ParseExternalStorage(req.GetOptions()); err != nil {
+			resp.Status = merr.Status(err)
+			return resp, nil
+		}
+		// the credentials are persisted in the import job until it's done
+		options, err := importutilv2.EncryptStorageCredentials(req.GetOptions())
+		if err != nil {
+			resp.Status = merr.Status(err)
+			return resp, nil
+		}
+		req.Options = options
+	}
+
+	if err := checkImportPartitionOptions(req.GetOptions(), hasPartitionKey, isBackup); err != nil {
+		resp.Status = merr.Status(err)
+		return resp, nil
+	}
+	autoLoad := importutilv2.IsAutoLoad(req.GetOptions())
+	if err := node.checkImportAutoLoad(ctx, req, collectionID, schema.CollectionSchema,
+		importutilv2.IsWaitForIndex(req.GetOptions()), autoLoad); err != nil {
+		resp.Status = merr.Status(err)
+		return resp, nil
+	}
 
 	var partitionIDs []int64
 	if isBackup {
@@ -6019,15 +6331,30 @@ func (node *Proxy) ImportV2(ctx context.Context, req *internalpb.ImportRequest)
 			}
 			partitionIDs = lo.Values(partitions)
 		} else {
-			if req.GetPartitionName() == "" {
-				req.PartitionName = Params.CommonCfg.DefaultPartitionName.GetValue()
-			}
-			partitionID, err := globalMetaCache.GetPartitionID(ctx, req.GetDbName(), req.GetCollectionName(), req.PartitionName)
-			if err != nil {
-				resp.Status = merr.Status(err)
-				return resp, nil
+			partitionColumn, partitionNames, _ := importutilv2.ParsePartitionColumn(req.GetOptions())
+			createPartition := importutilv2.IsCreatePartition(req.GetOptions())
+			if partitionColumn != "" {
+				if req.GetPartitionName() != "" {
+					resp.Status = merr.Status(merr.WrapErrImportFailed("not allow to set partition name when import by partition column"))
+					return resp, nil
+				}
+				partitionIDs, err = node.getImportPartitionIDsByColumn(ctx, req.GetDbName(), req.GetCollectionName(),
+					schema.CollectionSchema, partitionColumn, partitionNames, createPartition)
+				if err != nil {
+					resp.Status = merr.Status(err)
+					return resp, nil
+				}
+			} else {
+				if req.GetPartitionName() == "" {
+					req.PartitionName = Params.CommonCfg.DefaultPartitionName.GetValue()
+				}
+				partitionID, err := node.getImportPartitionID(ctx, req.GetDbName(), req.GetCollectionName(), req.PartitionName, createPartition)
+				if err != nil {
+					resp.Status = merr.Status(err)
+					return resp, nil
+				}
+				partitionIDs = []UniqueID{partitionID}
 			}
-			partitionIDs = []UniqueID{partitionID}
 		}
 	}
 
@@ -6066,6 +6393,21 @@ func (node *Proxy) ImportV2(ctx context.Context, req *internalpb.ImportRequest)
 	if err != nil {
 		log.Warn("import failed", zap.Error(err))
 		metrics.ProxyFunctionCall.WithLabelValues(nodeID, method, metrics.FailLabel, req.GetDbName(), req.GetCollectionName()).Inc()
+	} else if merr.Ok(resp.GetStatus()) && autoLoad {
+		err := globalImportAutoLoader.save(&ImportAutoLoad{
+			JobID:          resp.GetJobID(),
+			DBName:         req.GetDbName(),
+			CollectionName: req.GetCollectionName(),
+			PartitionName:  loadPartitionName,
+		})
+		// the import job is started anyway, only the loading after it is skipped
+		if err != nil {
+			log.Warn("failed to save import auto load, the collection is not loaded after import",
+				zap.String("jobID", resp.GetJobID()), zap.Error(err))
+		}
+	}
+	if err == nil && merr.Ok(resp.GetStatus()) {
+		node.addImportJob(resp.GetJobID(), req.GetDbName(), req.GetCollectionName())
 	}
 	metrics.ProxyReqLatency.WithLabelValues(nodeID, method).Observe(float64(tr.ElapseSpan().Milliseconds()))
 	return resp, err
@@ -6086,6 +6428,9 @@ func (node *Proxy) GetImportProgress(ctx context.Context, req *internalpb.GetImp
 
 	nodeID := fmt.Sprint(paramtable.GetNodeID())
 	resp, err := node.dataCoord.GetImportProgress(ctx, req)
+	if err == nil {
+		node.advanceImportAutoLoad(ctx, req.GetJobID(), resp)
+	}
 	if resp.GetStatus().GetCode() != 0 || err != nil {
 		log.Warn("get import progress failed", zap.String("reason", resp.GetStatus().GetReason()), zap.Error(err))
 		metrics.ProxyFunctionCall.WithLabelValues(nodeID, method, metrics.FailLabel, req.GetDbName(), "").Inc()
@@ -42,6 +42,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	"github.com/milvus-io/milvus/internal/proto/rootcoordpb"
 	"github.com/milvus-io/milvus/internal/util/dependency"
+	"github.com/milvus-io/milvus/internal/util/importutilv2"
 	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
@@ -1647,6 +1648,57 @@ func TestProxy_ImportV2(t *testing.T) {
 		})
 		assert.NoError(t, err)
 		assert.Equal(t, int32(0), rsp.GetStatus().GetCode())
+
+		// external storage
+		externalOptions := []*commonpb.KeyValuePair{
+			{Key: importutilv2.StorageCloudProvider, Value: "aws"},
+			{Key: importutilv2.StorageAddress, Value: "s3.us-west-2.amazonaws.com"},
+			{Key: importutilv2.StorageBucket, Value: "bucket"},
+			{Key: importutilv2.StorageAccessKeyID, Value: "ak"},
+			{Key: importutilv2.StorageSecretAccessKey, Value: "sk"},
+		}
+		externalReq := &internalpb.ImportRequest{
+			CollectionName: "aaa",
+			Files: []*internalpb.ImportFile{{
+				Id:    1,
+				Paths: []string{"a.json"},
+			}},
+			Options: externalOptions,
+		}
+		rsp, err = node.ImportV2(ctx, externalReq)
+		assert.NoError(t, err)
+		assert.True(t, errors.Is(merr.Error(rsp.GetStatus()), merr.ErrImportFailed))
+
+		paramtable.Get().Save(Params.DataCoordCfg.ImportExternalStorage.Key, "true")
+		defer paramtable.Get().Reset(Params.DataCoordCfg.ImportExternalStorage.Key)
+		// the address is not allowed
+		rsp, err = node.ImportV2(ctx, externalReq)
+		assert.NoError(t, err)
+		assert.True(t, errors.Is(merr.Error(rsp.GetStatus()), merr.ErrImportFailed))
+
+		paramtable.Get().Save(Params.DataCoordCfg.ImportExternalStorageAllowedAddresses.Key, "*.amazonaws.com")
+		defer paramtable.Get().Reset(Params.DataCoordCfg.ImportExternalStorageAllowedAddresses.Key)
+		// no credential key to encrypt the credentials
+		rsp, err = node.ImportV2(ctx, externalReq)
+		assert.NoError(t, err)
+		assert.True(t, errors.Is(merr.Error(rsp.GetStatus()), merr.ErrImportFailed))
+
+		paramtable.Get().Save(Params.DataCoordCfg.ImportExternalStorageCredentialKey.Key, base64.StdEncoding.EncodeToString(make([]byte, 32)))
+		defer paramtable.Get().Reset(Params.DataCoordCfg.ImportExternalStorageCredentialKey.Key)
+		rsp, err = node.ImportV2(ctx, externalReq)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), rsp.GetStatus().GetCode())
+
+		externalReq.Options = externalOptions[:2]
+		rsp, err = node.ImportV2(ctx, externalReq)
+		assert.NoError(t, err)
+		assert.True(t, errors.Is(merr.Error(rsp.GetStatus()), merr.ErrImportFailed))
+
+		externalReq.Options = append(externalOptions, &commonpb.KeyValuePair{Key: importutilv2.BackupFlag, Value: "true"})
+		externalReq.PartitionName = "bbb"
+		rsp, err = node.ImportV2(ctx, externalReq)
+		assert.NoError(t, err)
+		assert.True(t, errors.Is(merr.Error(rsp.GetStatus()), merr.ErrImportFailed))
 	})
 
 	t.Run("GetImportProgress", func(t *testing.T) {
@@ -0,0 +1,324 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	importAutoLoadCheckInterval = 3 * time.Second
+	importAutoLoadPrefix        = "proxy/import-auto-loads"
+)
+
+type importAutoLoadStage int
+
+const (
+	// importAutoLoadPending is waiting for the import job, including the index building, completed.
+	importAutoLoadPending importAutoLoadStage = iota
+	importAutoLoadLoading
+	importAutoLoadLoaded
+	importAutoLoadFailed
+)
+
+// ImportAutoLoad is the loading of the collection or the partition once the import job is completed, which is
+// persisted in etcd, so the composite state is served by any proxy and the loading survives the proxy restart.
+type ImportAutoLoad struct {
+	JobID          string              `json:"job_id"`
+	DBName         string              `json:"db_name"`
+	CollectionName string              `json:"collection_name"`
+	PartitionName  string              `json:"partition_name"`
+	Stage          importAutoLoadStage `json:"stage"`
+	Reason         string              `json:"reason"`
+	DoneTime       int64               `json:"done_time"`
+}
+
+func (j *ImportAutoLoad) key() string {
+	return path.Join(importAutoLoadPrefix, j.JobID)
+}
+
+func (j *ImportAutoLoad) target() string {
+	if j.PartitionName != "" {
+		return fmt.Sprintf("partition %s of collection %s", j.PartitionName, j.CollectionName)
+	}
+	return fmt.Sprintf("collection %s", j.CollectionName)
+}
+
+func (j *ImportAutoLoad) done(stage importAutoLoadStage, reason string) {
+	j.Stage, j.Reason, j.DoneTime = stage, reason, time.Now().Unix()
+}
+
+func (j *ImportAutoLoad) isDone() bool {
+	return j.Stage == importAutoLoadLoaded || j.Stage == importAutoLoadFailed
+}
+
+func (j *ImportAutoLoad) expired(retention time.Duration) bool {
+	return j.DoneTime > 0 && time.Since(time.Unix(j.DoneTime, 0)) > retention
+}
+
+// importAutoLoader advances the persisted auto loads. They are advanced by all proxies, which is harmless since
+// the loading is idempotent.
+type importAutoLoader struct {
+	kv kv.BaseKV
+	// load triggers the loading, and loadingProgress returns the loading progress in percentage.
+	load            func(ctx context.Context, job *ImportAutoLoad) error
+	loadingProgress func(ctx context.Context, job *ImportAutoLoad) (int64, error)
+}
+
+var globalImportAutoLoader = &importAutoLoader{}
+
+// newImportAutoLoader loads the partition of the auto load if specified, otherwise the collection. The privileges
+// to load are checked when the import is requested.
+func (node *Proxy) newImportAutoLoader(baseKV kv.BaseKV) *importAutoLoader {
+	return &importAutoLoader{
+		kv: baseKV,
+		load: func(ctx context.Context, job *ImportAutoLoad) error {
+			if job.PartitionName != "" {
+				return merr.CheckRPCCall(node.LoadPartitions(ctx, &milvuspb.LoadPartitionsRequest{
+					DbName:         job.DBName,
+					CollectionName: job.CollectionName,
+					PartitionNames: []string{job.PartitionName},
+				}))
+			}
+			return merr.CheckRPCCall(node.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+				DbName:         job.DBName,
+				CollectionName: job.CollectionName,
+			}))
+		},
+		loadingProgress: func(ctx context.Context, job *ImportAutoLoad) (int64, error) {
+			req := &milvuspb.GetLoadingProgressRequest{DbName: job.DBName, CollectionName: job.CollectionName}
+			if job.PartitionName != "" {
+				req.PartitionNames = []string{job.PartitionName}
+			}
+			resp, err := node.GetLoadingProgress(ctx, req)
+			if err := merr.CheckRPCCall(resp, err); err != nil {
+				return 0, err
+			}
+			return resp.GetProgress(), nil
+		},
+	}
+}
+
+// Enabled returns whether the auto loads could be persisted.
+func (l *importAutoLoader) Enabled() bool {
+	return l.kv != nil
+}
+
+func (l *importAutoLoader) save(job *ImportAutoLoad) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return l.kv.Save(job.key(), string(value))
+}
+
+// get returns the auto load of the import job, nil if not requested.
+func (l *importAutoLoader) get(jobID string) (*ImportAutoLoad, error) {
+	job := &ImportAutoLoad{JobID: jobID}
+	value, err := l.kv.Load(job.key())
+	if err != nil {
+		if errors.Is(err, merr.ErrIoKeyNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(value), job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// pending returns the auto loads not done and removes the ones done for longer than the retention.
+func (l *importAutoLoader) pending(retention time.Duration) ([]*ImportAutoLoad, error) {
+	_, values, err := l.kv.LoadWithPrefix(importAutoLoadPrefix)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*ImportAutoLoad, 0)
+	for _, value := range values {
+		job := &ImportAutoLoad{}
+		if err := json.Unmarshal([]byte(value), job); err != nil {
+			log.Warn("skip invalid import auto load", zap.String("value", value), zap.Error(err))
+			continue
+		}
+		if job.expired(retention) {
+			if err := l.kv.Remove(job.key()); err != nil {
+				log.Warn("failed to remove import auto load", zap.String("key", job.key()), zap.Error(err))
+			}
+			continue
+		}
+		if !job.isDone() {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// advance moves the auto load forward by the state of the import job, and overrides the state of the import job
+// with the composite one, the import job is reported as importing until the loading done.
+func (l *importAutoLoader) advance(ctx context.Context, job *ImportAutoLoad, resp *internalpb.GetImportProgressResponse) {
+	stage := job.Stage
+	switch resp.GetState() {
+	case internalpb.ImportJobState_Failed:
+		if job.Stage < importAutoLoadLoaded {
+			job.done(importAutoLoadFailed, resp.GetReason())
+		}
+	case internalpb.ImportJobState_Completed:
+		if job.Stage == importAutoLoadPending {
+			if err := l.load(ctx, job); err != nil {
+				job.done(importAutoLoadFailed, fmt.Sprintf("import completed but failed to load %s: %s", job.target(), err.Error()))
+			} else {
+				log.Ctx(ctx).Info("import completed, start loading", zap.String("target", job.target()))
+				job.Stage = importAutoLoadLoading
+			}
+		}
+	default:
+		return
+	}
+
+	progress := int64(0)
+	if job.Stage == importAutoLoadLoading {
+		var err error
+		progress, err = l.loadingProgress(ctx, job)
+		switch {
+		case err != nil:
+			job.done(importAutoLoadFailed, fmt.Sprintf("import completed but failed to get loading progress of %s: %s", job.target(), err.Error()))
+		case progress >= 100:
+			job.done(importAutoLoadLoaded, "")
+		}
+	}
+	if job.Stage != stage {
+		if err := l.save(job); err != nil {
+			log.Ctx(ctx).Warn("failed to save import auto load", zap.String("jobID", job.JobID), zap.Error(err))
+		}
+	}
+
+	switch job.Stage {
+	case importAutoLoadLoading:
+		resp.State = internalpb.ImportJobState_Importing
+		resp.Reason = fmt.Sprintf("import completed, loading %s, progress %d%%", job.target(), progress)
+		if resp.GetProgress() >= 100 {
+			resp.Progress = 99
+		}
+	case importAutoLoadFailed:
+		resp.State = internalpb.ImportJobState_Failed
+		resp.Reason = job.Reason
+	}
+}
+
+// checkImportAutoLoad checks the import options of waiting for the indexes and the auto load. The indexes of the
+// imported segments are built by the declared ones, so all the vector fields must have their indexes declared,
+// and the caller must be privileged to load, since the loading is done by the proxy later.
+func (node *Proxy) checkImportAutoLoad(ctx context.Context, req *internalpb.ImportRequest, collectionID int64,
+	schema *schemapb.CollectionSchema, waitForIndex, autoLoad bool,
+) error {
+	if autoLoad {
+		if !globalImportAutoLoader.Enabled() {
+			return merr.WrapErrServiceUnavailable("import auto loader is not initialized", "failed to load after import")
+		}
+		var loadReq any = &milvuspb.LoadCollectionRequest{DbName: req.GetDbName(), CollectionName: req.GetCollectionName()}
+		if req.GetPartitionName() != "" {
+			loadReq = &milvuspb.LoadPartitionsRequest{
+				DbName:         req.GetDbName(),
+				CollectionName: req.GetCollectionName(),
+				PartitionNames: []string{req.GetPartitionName()},
+			}
+		}
+		if _, err := PrivilegeInterceptor(ctx, loadReq); err != nil {
+			return err
+		}
+	}
+	if !waitForIndex {
+		return nil
+	}
+
+	resp, err := node.dataCoord.DescribeIndex(ctx, &indexpb.DescribeIndexRequest{CollectionID: collectionID})
+	if err := merr.CheckRPCCall(resp, err); err != nil && !errors.Is(err, merr.ErrIndexNotFound) {
+		return err
+	}
+	indexed := typeutil.NewSet[int64]()
+	for _, index := range resp.GetIndexInfos() {
+		indexed.Insert(index.GetFieldID())
+	}
+	for _, field := range schema.GetFields() {
+		if typeutil.IsVectorType(field.GetDataType()) && !indexed.Contain(field.GetFieldID()) {
+			return merr.WrapErrImportFailed(fmt.Sprintf("index of vector field %s is not declared, which is required to wait for index or auto load", field.GetName()))
+		}
+	}
+	return nil
+}
+
+// advanceImportAutoLoad applies the auto load of the import job if requested, see importAutoLoader.advance.
+func (node *Proxy) advanceImportAutoLoad(ctx context.Context, jobID string, resp *internalpb.GetImportProgressResponse) {
+	if !globalImportAutoLoader.Enabled() || !merr.Ok(resp.GetStatus()) {
+		return
+	}
+	job, err := globalImportAutoLoader.get(jobID)
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to get import auto load", zap.String("jobID", jobID), zap.Error(err))
+		return
+	}
+	if job != nil {
+		globalImportAutoLoader.advance(ctx, job, resp)
+	}
+}
+
+// importAutoLoadLoop loads the collections of the completed import jobs even if nobody polls the progress.
+func (node *Proxy) importAutoLoadLoop() {
+	defer node.wg.Done()
+	ticker := time.NewTicker(importAutoLoadCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-node.ctx.Done():
+			return
+		case <-ticker.C:
+			if !globalImportAutoLoader.Enabled() {
+				continue
+			}
+			jobs, err := globalImportAutoLoader.pending(Params.DataCoordCfg.ImportTaskRetention.GetAsDuration(time.Second))
+			if err != nil {
+				log.RatedWarn(60, "failed to load pending import auto loads", zap.Error(err))
+				continue
+			}
+			for _, job := range jobs {
+				resp, err := node.dataCoord.GetImportProgress(node.ctx, &internalpb.GetImportProgressRequest{JobID: job.JobID})
+				if err := merr.CheckRPCCall(resp, err); err != nil {
+					log.RatedWarn(60, "failed to get import progress for auto load", zap.String("jobID", job.JobID), zap.Error(err))
+					continue
+				}
+				globalImportAutoLoader.advance(node.ctx, job, resp)
+			}
+		}
+	}
+}
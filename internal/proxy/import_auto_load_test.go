@@ -0,0 +1,186 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	memkv "github.com/milvus-io/milvus/internal/kv/mem"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func newTestImportAutoLoader(loadErr error, progress *int64) (*importAutoLoader, *int) {
+	loads := 0
+	return &importAutoLoader{
+		kv: memkv.NewMemoryKV(),
+		load: func(ctx context.Context, job *ImportAutoLoad) error {
+			loads++
+			return loadErr
+		},
+		loadingProgress: func(ctx context.Context, job *ImportAutoLoad) (int64, error) {
+			return *progress, nil
+		},
+	}, &loads
+}
+
+func TestImportAutoLoader_advance(t *testing.T) {
+	ctx := context.Background()
+	newResp := func(state internalpb.ImportJobState) *internalpb.GetImportProgressResponse {
+		return &internalpb.GetImportProgressResponse{Status: merr.Success(), State: state, Progress: 100}
+	}
+	// the job is reloaded before each advance, as it's advanced by any proxy
+	reload := func(loader *importAutoLoader) *ImportAutoLoad {
+		job, err := loader.get("1")
+		assert.NoError(t, err)
+		return job
+	}
+
+	t.Run("normal", func(t *testing.T) {
+		progress := int64(0)
+		loader, loads := newTestImportAutoLoader(nil, &progress)
+		assert.NoError(t, loader.save(&ImportAutoLoad{JobID: "1", CollectionName: "test"}))
+
+		// importing
+		resp := newResp(internalpb.ImportJobState_Importing)
+		resp.Progress = 50
+		loader.advance(ctx, reload(loader), resp)
+		assert.Equal(t, internalpb.ImportJobState_Importing, resp.GetState())
+		assert.EqualValues(t, 50, resp.GetProgress())
+		assert.Equal(t, 0, *loads)
+
+		// import completed, loading
+		resp = newResp(internalpb.ImportJobState_Completed)
+		loader.advance(ctx, reload(loader), resp)
+		assert.Equal(t, internalpb.ImportJobState_Importing, resp.GetState())
+		assert.EqualValues(t, 99, resp.GetProgress())
+		assert.Contains(t, resp.GetReason(), "loading")
+		assert.Equal(t, 1, *loads)
+		assert.Equal(t, importAutoLoadLoading, reload(loader).Stage)
+
+		// loaded
+		progress = 100
+		resp = newResp(internalpb.ImportJobState_Completed)
+		loader.advance(ctx, reload(loader), resp)
+		assert.Equal(t, internalpb.ImportJobState_Completed, resp.GetState())
+		assert.Empty(t, resp.GetReason())
+		assert.Equal(t, 1, *loads)
+		assert.True(t, reload(loader).isDone())
+	})
+
+	t.Run("load failed", func(t *testing.T) {
+		progress := int64(0)
+		loader, _ := newTestImportAutoLoader(errors.New("mock"), &progress)
+		assert.NoError(t, loader.save(&ImportAutoLoad{JobID: "1", CollectionName: "test"}))
+		resp := newResp(internalpb.ImportJobState_Completed)
+		loader.advance(ctx, reload(loader), resp)
+		assert.Equal(t, internalpb.ImportJobState_Failed, resp.GetState())
+		assert.Contains(t, resp.GetReason(), "mock")
+		assert.True(t, reload(loader).isDone())
+
+		// the failure is kept
+		resp = newResp(internalpb.ImportJobState_Completed)
+		loader.advance(ctx, reload(loader), resp)
+		assert.Equal(t, internalpb.ImportJobState_Failed, resp.GetState())
+		assert.Contains(t, resp.GetReason(), "mock")
+	})
+
+	t.Run("import failed", func(t *testing.T) {
+		progress := int64(0)
+		loader, loads := newTestImportAutoLoader(nil, &progress)
+		assert.NoError(t, loader.save(&ImportAutoLoad{JobID: "1", CollectionName: "test"}))
+		resp := newResp(internalpb.ImportJobState_Failed)
+		resp.Reason = "import failed"
+		loader.advance(ctx, reload(loader), resp)
+		assert.Equal(t, internalpb.ImportJobState_Failed, resp.GetState())
+		assert.Equal(t, "import failed", resp.GetReason())
+		assert.Equal(t, 0, *loads)
+		assert.True(t, reload(loader).isDone())
+	})
+}
+
+func TestImportAutoLoader_pending(t *testing.T) {
+	progress := int64(0)
+	loader, _ := newTestImportAutoLoader(nil, &progress)
+	done := &ImportAutoLoad{JobID: "2"}
+	done.done(importAutoLoadLoaded, "")
+	expired := &ImportAutoLoad{JobID: "3"}
+	expired.done(importAutoLoadFailed, "")
+	expired.DoneTime = time.Now().Add(-time.Hour).Unix()
+
+	assert.NoError(t, loader.save(&ImportAutoLoad{JobID: "1"}))
+	assert.NoError(t, loader.save(done))
+	assert.NoError(t, loader.save(expired))
+	job, err := loader.get("1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", job.JobID)
+	job, err = loader.get("4")
+	assert.NoError(t, err)
+	assert.Nil(t, job)
+
+	jobs, err := loader.pending(time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, "1", jobs[0].JobID)
+	job, _ = loader.get("2")
+	assert.NotNil(t, job)
+	job, _ = loader.get("3")
+	assert.Nil(t, job)
+}
+
+func TestCheckImportAutoLoad(t *testing.T) {
+	ctx := context.Background()
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector},
+		},
+	}
+	req := &internalpb.ImportRequest{CollectionName: "test"}
+	loader := globalImportAutoLoader
+	defer func() { globalImportAutoLoader = loader }()
+
+	dc := mocks.NewMockDataCoordClient(t)
+	node := &Proxy{dataCoord: dc}
+	assert.NoError(t, node.checkImportAutoLoad(ctx, req, 1, schema, false, false))
+
+	// the auto load is not persisted
+	globalImportAutoLoader = &importAutoLoader{}
+	assert.ErrorIs(t, node.checkImportAutoLoad(ctx, req, 1, schema, true, true), merr.ErrServiceUnavailable)
+
+	// the index of the vector field is not declared
+	globalImportAutoLoader = &importAutoLoader{kv: memkv.NewMemoryKV()}
+	dc.EXPECT().DescribeIndex(mock.Anything, mock.Anything).Return(&indexpb.DescribeIndexResponse{
+		Status: merr.Status(merr.WrapErrIndexNotFoundForCollection("test")),
+	}, nil).Once()
+	assert.ErrorIs(t, node.checkImportAutoLoad(ctx, req, 1, schema, true, true), merr.ErrImportFailed)
+
+	dc.EXPECT().DescribeIndex(mock.Anything, mock.Anything).Return(&indexpb.DescribeIndexResponse{
+		Status:     merr.Success(),
+		IndexInfos: []*indexpb.IndexInfo{{FieldID: 101}},
+	}, nil).Once()
+	assert.NoError(t, node.checkImportAutoLoad(ctx, req, 1, schema, true, true))
+}
@@ -0,0 +1,111 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/util/importutilv2"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// getImportPartitionID returns the id of the partition to import into, the partition is created if not exist
+// and requested by the import options, so the import of a new tenant never touches the existing partitions.
+// The caller must be privileged to create the partition, as the import privilege doesn't cover it.
+func (node *Proxy) getImportPartitionID(ctx context.Context, dbName, collectionName, partitionName string, create bool) (int64, error) {
+	partitionID, err := globalMetaCache.GetPartitionID(ctx, dbName, collectionName, partitionName)
+	if err == nil || !create || !errors.Is(err, merr.ErrPartitionNotFound) {
+		return partitionID, err
+	}
+	if err := validatePartitionTag(partitionName, true); err != nil {
+		return 0, err
+	}
+	createReq := &milvuspb.CreatePartitionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		PartitionName:  partitionName,
+	}
+	if _, err := PrivilegeInterceptor(ctx, createReq); err != nil {
+		return 0, err
+	}
+	log.Ctx(ctx).Info("create partition to import into",
+		zap.String("collection", collectionName), zap.String("partition", partitionName))
+	err = merr.CheckRPCCall(node.CreatePartition(ctx, createReq))
+	if err != nil {
+		return 0, err
+	}
+	globalMetaCache.RemoveCollection(ctx, dbName, collectionName)
+	return globalMetaCache.GetPartitionID(ctx, dbName, collectionName, partitionName)
+}
+
+// getImportPartitionIDsByColumn returns the ids of the partitions to import into by the partition column,
+// in the order of the partition names in the import options, which the datanodes route the rows by.
+func (node *Proxy) getImportPartitionIDsByColumn(ctx context.Context, dbName, collectionName string,
+	schema *schemapb.CollectionSchema, column string, partitionNames []string, create bool,
+) ([]int64, error) {
+	field := typeutil.GetFieldByName(schema, column)
+	if field == nil {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("partition column %s not found in collection %s", column, collectionName))
+	}
+	if field.GetDataType() != schemapb.DataType_Int64 && field.GetDataType() != schemapb.DataType_VarChar {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("partition column %s should be Int64 or VarChar, but got %s",
+			column, field.GetDataType()))
+	}
+	if field.GetIsPrimaryKey() && field.GetAutoID() {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("partition column %s should not be the auto id primary key", column))
+	}
+	if len(partitionNames) > Params.RootCoordCfg.MaxPartitionNum.GetAsInt() {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("the number of partitions to import into should not exceed %d, but got %d",
+			Params.RootCoordCfg.MaxPartitionNum.GetAsInt(), len(partitionNames)))
+	}
+
+	partitionIDs := make([]int64, 0, len(partitionNames))
+	for _, partitionName := range partitionNames {
+		partitionID, err := node.getImportPartitionID(ctx, dbName, collectionName, partitionName, create)
+		if err != nil {
+			return nil, err
+		}
+		partitionIDs = append(partitionIDs, partitionID)
+	}
+	return partitionIDs, nil
+}
+
+// checkImportPartitionOptions checks the partition options are not used with the partition key or the backup.
+func checkImportPartitionOptions(options importutilv2.Options, hasPartitionKey, isBackup bool) error {
+	column, _, err := importutilv2.ParsePartitionColumn(options)
+	if err != nil {
+		return err
+	}
+	if column == "" && !importutilv2.IsCreatePartition(options) {
+		return nil
+	}
+	if hasPartitionKey {
+		return merr.WrapErrImportFailed("not allow to import by partition column or create partition for collection with partition key")
+	}
+	if isBackup {
+		return merr.WrapErrImportFailed("not allow to import backup by partition column or create partition")
+	}
+	return nil
+}
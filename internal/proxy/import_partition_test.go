@@ -0,0 +1,94 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/util/importutilv2"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestCheckImportPartitionOptions(t *testing.T) {
+	byColumn := []*commonpb.KeyValuePair{
+		{Key: importutilv2.PartitionColumn, Value: "tenant"},
+		{Key: importutilv2.PartitionNames, Value: "a,b"},
+	}
+	create := []*commonpb.KeyValuePair{{Key: importutilv2.CreatePartitionFlag, Value: "true"}}
+
+	assert.NoError(t, checkImportPartitionOptions(nil, true, true))
+	assert.NoError(t, checkImportPartitionOptions(byColumn, false, false))
+	assert.NoError(t, checkImportPartitionOptions(create, false, false))
+	assert.ErrorIs(t, checkImportPartitionOptions(byColumn, true, false), merr.ErrImportFailed)
+	assert.ErrorIs(t, checkImportPartitionOptions(create, true, false), merr.ErrImportFailed)
+	assert.ErrorIs(t, checkImportPartitionOptions(byColumn, false, true), merr.ErrImportFailed)
+	assert.ErrorIs(t, checkImportPartitionOptions(byColumn[:1], false, false), merr.ErrImportFailed)
+}
+
+func TestGetImportPartitionIDsByColumn(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+
+	mc := NewMockCache(t)
+	mc.EXPECT().GetPartitionID(mock.Anything, "db", "coll", "a").Return(10, nil)
+	mc.EXPECT().GetPartitionID(mock.Anything, "db", "coll", "b").Return(20, nil)
+	mc.EXPECT().GetPartitionID(mock.Anything, "db", "coll", "c").Return(0, merr.WrapErrPartitionNotFound("c"))
+	globalMetaCache = mc
+
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true, AutoID: true},
+			{FieldID: 101, Name: "tenant", DataType: schemapb.DataType_VarChar},
+			{FieldID: 102, Name: "score", DataType: schemapb.DataType_Float},
+		},
+	}
+	node := &Proxy{}
+
+	partitionIDs, err := node.getImportPartitionIDsByColumn(ctx, "db", "coll", schema, "tenant", []string{"b", "a"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{20, 10}, partitionIDs)
+
+	// partition not found and not to create
+	_, err = node.getImportPartitionIDsByColumn(ctx, "db", "coll", schema, "tenant", []string{"a", "c"}, false)
+	assert.ErrorIs(t, err, merr.ErrPartitionNotFound)
+
+	// invalid partition column
+	for _, column := range []string{"unknown", "score", "pk"} {
+		_, err = node.getImportPartitionIDsByColumn(ctx, "db", "coll", schema, column, []string{"a"}, false)
+		assert.ErrorIs(t, err, merr.ErrImportFailed)
+	}
+
+	// failed to create the partition, the proxy is not healthy
+	_, err = node.getImportPartitionID(ctx, "db", "coll", "c", true)
+	assert.ErrorIs(t, err, merr.ErrServiceNotReady)
+
+	// not privileged to create the partition
+	paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+	_, err = node.getImportPartitionID(ctx, "db", "coll", "c", true)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, merr.ErrServiceNotReady)
+}
@@ -0,0 +1,256 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/importutilv2"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	defaultImportValidationSampleRows = 10000
+	// maxImportValidationSampleRows bounds the rows read by the proxy for each file.
+	maxImportValidationSampleRows = 100000
+)
+
+// ImportValidationRequest is the request to validate the import files before importing them.
+type ImportValidationRequest struct {
+	DbName         string `json:"db_name"`
+	CollectionName string `json:"collection_name"`
+	PartitionName  string `json:"partition_name"`
+	// Files are the paths of the import files, each of which is the paths of the numpy files
+	// of the fields or the path of the single json or parquet file, the same as the import request.
+	Files   [][]string        `json:"files"`
+	Options map[string]string `json:"options"`
+	// SampleRows is the number of rows to read from each file, 10000 by default and 100000 at most.
+	SampleRows int `json:"sample_rows"`
+}
+
+// ImportFileValidation is the validation result of the import file.
+type ImportFileValidation struct {
+	Paths       []string `json:"paths"`
+	FileSize    int64    `json:"file_size"`
+	SampledRows int64    `json:"sampled_rows"`
+	// EstimatedRows is the number of rows of the file, exact if the whole file is sampled,
+	// otherwise estimated by the file size and the size of the sampled rows.
+	EstimatedRows int64  `json:"estimated_rows"`
+	EstimatedSize int64  `json:"estimated_size"`
+	FullySampled  bool   `json:"fully_sampled"`
+	DuplicatedPKs int64  `json:"duplicated_pks"`
+	Error         string `json:"error,omitempty"`
+}
+
+// ImportValidationResult is the report of the import files, the import is expected to fail if not valid.
+type ImportValidationResult struct {
+	CollectionName    string                  `json:"collection_name"`
+	Valid             bool                    `json:"valid"`
+	Files             []*ImportFileValidation `json:"files"`
+	EstimatedRows     int64                   `json:"estimated_rows"`
+	EstimatedSize     int64                   `json:"estimated_size"`
+	EstimatedSegments int64                   `json:"estimated_segments"`
+}
+
+// validateImport samples the import files to check whether they are compatible with the collection schema
+// and the primary keys are unique within the samples, and estimates the rows and the segments to import,
+// without importing anything.
+func (node *Proxy) validateImport(ctx context.Context, request *ImportValidationRequest) (*ImportValidationResult, error) {
+	if request.CollectionName == "" {
+		return nil, merr.WrapErrParameterMissing("collection_name")
+	}
+	files := lo.FilterMap(request.Files, func(paths []string, _ int) (*internalpb.ImportFile, bool) {
+		return &internalpb.ImportFile{Paths: paths}, len(paths) > 0
+	})
+	if len(files) == 0 {
+		return nil, merr.WrapErrParameterInvalidMsg("import request is empty")
+	}
+	if len(files) > Params.DataCoordCfg.MaxFilesPerImportReq.GetAsInt() {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("The max number of import files should not exceed %d, but got %d",
+			Params.DataCoordCfg.MaxFilesPerImportReq.GetAsInt(), len(files)))
+	}
+	options := funcutil.Map2KeyValuePair(request.Options)
+	if importutilv2.IsBackup(options) {
+		return nil, merr.WrapErrParameterInvalidMsg("not allow to validate backup import")
+	}
+	sampleRows := request.SampleRows
+	if sampleRows <= 0 {
+		sampleRows = defaultImportValidationSampleRows
+	}
+	if sampleRows > maxImportValidationSampleRows {
+		return nil, merr.WrapErrParameterInvalidRange(1, maxImportValidationSampleRows, sampleRows, "too many rows to sample")
+	}
+	importReq := &milvuspb.ImportRequest{
+		DbName:         request.DbName,
+		CollectionName: request.CollectionName,
+		PartitionName:  request.PartitionName,
+	}
+	if err := checkMgrPrivilege(ctx, importReq); err != nil {
+		return nil, err
+	}
+
+	collectionID, err := globalMetaCache.GetCollectionID(ctx, request.DbName, request.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := globalMetaCache.GetCollectionSchema(ctx, request.DbName, request.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := node.chMgr.getVChannels(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	partitionNum := 1
+	if typeutil.HasPartitionKey(schema.CollectionSchema) {
+		if request.PartitionName != "" {
+			return nil, merr.WrapErrImportFailed("not allow to set partition name for collection with partition key")
+		}
+		partitions, err := globalMetaCache.GetPartitions(ctx, request.DbName, request.CollectionName)
+		if err != nil {
+			return nil, err
+		}
+		partitionNum = len(partitions)
+	} else if request.PartitionName != "" {
+		if _, err := globalMetaCache.GetPartitionID(ctx, request.DbName, request.CollectionName, request.PartitionName); err != nil {
+			return nil, err
+		}
+	}
+
+	var cm storage.ChunkManager
+	if importutilv2.IsExternalStorage(options) {
+		if !Params.DataCoordCfg.ImportExternalStorage.GetAsBool() {
+			return nil, merr.WrapErrImportFailed("import from external storage is disabled")
+		}
+		cm, err = importutilv2.NewExternalChunkManager(ctx, options)
+	} else {
+		cm, err = node.factory.NewPersistentStorageChunkManager(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportValidationResult{
+		CollectionName: request.CollectionName,
+		Files:          validateImportFiles(ctx, cm, schema.CollectionSchema, files, options, sampleRows),
+	}
+	result.Valid = true
+	for _, file := range result.Files {
+		if file.Error != "" || file.DuplicatedPKs > 0 {
+			result.Valid = false
+		}
+		result.EstimatedRows += file.EstimatedRows
+		result.EstimatedSize += file.EstimatedSize
+	}
+	result.EstimatedSegments = estimateImportSegments(result.EstimatedSize, result.EstimatedRows, len(channels)*partitionNum)
+	return result, nil
+}
+
+// validateImportFiles reads at most sampleRows rows from each of the import files.
+func validateImportFiles(ctx context.Context, cm storage.ChunkManager, schema *schemapb.CollectionSchema,
+	files []*internalpb.ImportFile, options importutilv2.Options, sampleRows int,
+) []*ImportFileValidation {
+	bufferSize := Params.DataNodeCfg.ReadBufferSizeInMB.GetAsInt() * 1024 * 1024
+	pkField, _ := typeutil.GetPrimaryFieldSchema(schema)
+	return lo.Map(files, func(file *internalpb.ImportFile, _ int) *ImportFileValidation {
+		validation := &ImportFileValidation{Paths: file.GetPaths()}
+		if err := validation.sample(ctx, cm, schema, pkField, file, options, bufferSize, sampleRows); err != nil {
+			validation.Error = err.Error()
+		}
+		return validation
+	})
+}
+
+func (v *ImportFileValidation) sample(ctx context.Context, cm storage.ChunkManager, schema *schemapb.CollectionSchema,
+	pkField *schemapb.FieldSchema, file *internalpb.ImportFile, options importutilv2.Options, bufferSize int, sampleRows int,
+) error {
+	if _, err := importutilv2.GetFileType(file); err != nil {
+		return err
+	}
+	reader, err := importutilv2.NewReader(ctx, cm, schema, file, options, bufferSize)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	v.FileSize, err = reader.Size()
+	if err != nil {
+		return err
+	}
+
+	checkPK := pkField != nil && !pkField.GetAutoID()
+	pks := make(map[any]struct{})
+	var sampledSize int64
+	for v.SampledRows < int64(sampleRows) {
+		data, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				v.FullySampled = true
+				break
+			}
+			return err
+		}
+		if checkPK {
+			pkData, ok := data.Data[pkField.GetFieldID()]
+			if !ok {
+				return merr.WrapErrImportFailed(fmt.Sprintf("primary key field '%s' not found in the file", pkField.GetName()))
+			}
+			for i := 0; i < pkData.RowNum(); i++ {
+				pk := pkData.GetRow(i)
+				if _, ok := pks[pk]; ok {
+					v.DuplicatedPKs++
+				}
+				pks[pk] = struct{}{}
+			}
+		}
+		v.SampledRows += int64(data.GetRowNum())
+		sampledSize += int64(data.GetMemorySize())
+	}
+
+	v.EstimatedRows, v.EstimatedSize = v.SampledRows, sampledSize
+	if !v.FullySampled && v.SampledRows > 0 && v.FileSize > 0 {
+		// assume the rows not sampled are of the same size as the sampled ones, the rows of the file encoded
+		// in text are usually larger than in memory, so the estimation is the upper bound of the rows.
+		bytesPerRow := float64(sampledSize) / float64(v.SampledRows)
+		v.EstimatedRows = int64(math.Max(float64(v.SampledRows), float64(v.FileSize)/bytesPerRow))
+		v.EstimatedSize = int64(float64(v.EstimatedRows) * bytesPerRow)
+	}
+	return nil
+}
+
+// estimateImportSegments estimates the number of segments the import creates, the rows are hashed into
+// the groups of the vchannels and the partitions, each of which is written into the segments of the max size.
+func estimateImportSegments(size int64, rows int64, groups int) int64 {
+	if rows == 0 || groups == 0 {
+		return 0
+	}
+	groups = int(math.Min(float64(groups), float64(rows)))
+	maxSize := Params.DataCoordCfg.SegmentMaxSize.GetAsFloat() * 1024 * 1024
+	perGroup := math.Ceil(float64(size) / float64(groups) / maxSize)
+	return int64(groups) * int64(math.Max(perGroup, 1))
+}
@@ -0,0 +1,129 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestValidateImportFiles(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	dir := t.TempDir()
+	cm := storage.NewLocalChunkManager(storage.RootPath(dir))
+
+	schema := &schemapb.CollectionSchema{
+		Name: "test",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "id", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{
+				{Key: common.DimKey, Value: "2"},
+			}},
+		},
+	}
+	files := map[string]string{
+		"valid.json":     `[{"id": 1, "vec": [0.1, 0.2]}, {"id": 2, "vec": [0.3, 0.4]}, {"id": 3, "vec": [0.5, 0.6]}]`,
+		"duplicate.json": `[{"id": 1, "vec": [0.1, 0.2]}, {"id": 1, "vec": [0.3, 0.4]}]`,
+		"mismatch.json":  `[{"id": 1, "vector": [0.1, 0.2]}]`,
+	}
+	for name, content := range files {
+		assert.NoError(t, cm.Write(ctx, path.Join(dir, name), []byte(content)))
+	}
+	newFile := func(name string) *internalpb.ImportFile {
+		return &internalpb.ImportFile{Paths: []string{path.Join(dir, name)}}
+	}
+
+	validations := validateImportFiles(ctx, cm, schema, []*internalpb.ImportFile{
+		newFile("valid.json"),
+		newFile("duplicate.json"),
+		newFile("mismatch.json"),
+		newFile("missing.json"),
+		newFile("invalid.cpp"),
+	}, nil, 100)
+	assert.Len(t, validations, 5)
+
+	valid := validations[0]
+	assert.Empty(t, valid.Error)
+	assert.True(t, valid.FullySampled)
+	assert.EqualValues(t, 3, valid.SampledRows)
+	assert.EqualValues(t, 3, valid.EstimatedRows)
+	assert.EqualValues(t, len(files["valid.json"]), valid.FileSize)
+	assert.Greater(t, valid.EstimatedSize, int64(0))
+	assert.Zero(t, valid.DuplicatedPKs)
+
+	assert.Empty(t, validations[1].Error)
+	assert.EqualValues(t, 1, validations[1].DuplicatedPKs)
+	for _, validation := range validations[2:] {
+		assert.NotEmpty(t, validation.Error)
+	}
+
+	// sample the first rows only
+	validations = validateImportFiles(ctx, cm, schema, []*internalpb.ImportFile{newFile("valid.json")}, nil, 1)
+	assert.Empty(t, validations[0].Error)
+	if !validations[0].FullySampled {
+		assert.GreaterOrEqual(t, validations[0].EstimatedRows, validations[0].SampledRows)
+	}
+}
+
+func TestEstimateImportSegments(t *testing.T) {
+	paramtable.Init()
+	maxSize := int64(Params.DataCoordCfg.SegmentMaxSize.GetAsFloat() * 1024 * 1024)
+
+	assert.EqualValues(t, 0, estimateImportSegments(0, 0, 2))
+	// fewer rows than the groups
+	assert.EqualValues(t, 1, estimateImportSegments(100, 1, 2))
+	assert.EqualValues(t, 2, estimateImportSegments(100, 100, 2))
+	assert.EqualValues(t, 4, estimateImportSegments(3*maxSize, 100, 2))
+}
+
+func TestValidateImport_InvalidRequest(t *testing.T) {
+	paramtable.Init()
+	node := &Proxy{}
+	ctx := context.Background()
+
+	_, err := node.validateImport(ctx, &ImportValidationRequest{})
+	assert.ErrorIs(t, err, merr.ErrParameterMissing)
+
+	_, err = node.validateImport(ctx, &ImportValidationRequest{CollectionName: "test", Files: [][]string{{}}})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	_, err = node.validateImport(ctx, &ImportValidationRequest{
+		CollectionName: "test",
+		Files:          [][]string{{"a.json"}},
+		Options:        map[string]string{"backup": "true"},
+	})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	_, err = node.validateImport(ctx, &ImportValidationRequest{
+		CollectionName: "test",
+		Files:          [][]string{{"a.json"}},
+		SampleRows:     maxImportValidationSampleRows + 1,
+	})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
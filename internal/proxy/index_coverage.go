@@ -0,0 +1,194 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// IndexedSegmentsRequest is the request to list the sealed segments of the collection and their built indexes.
+type IndexedSegmentsRequest struct {
+	DbName         string `json:"db_name"`
+	CollectionName string `json:"collection_name"`
+}
+
+// IndexedSegment is a sealed segment with the ids of the indexes built on it,
+// the segment is searched by brute force if no index is built.
+type IndexedSegment struct {
+	SegmentID   int64   `json:"segment_id"`
+	PartitionID int64   `json:"partition_id"`
+	State       string  `json:"state"`
+	NumRows     int64   `json:"num_rows"`
+	Indexed     bool    `json:"indexed"`
+	IndexIDs    []int64 `json:"index_ids"`
+}
+
+// IndexedSegmentsResult is the sealed segments of the collection and the fraction of the sealed rows
+// covered by a built index.
+type IndexedSegmentsResult struct {
+	CollectionName string            `json:"collection_name"`
+	CollectionID   int64             `json:"collection_id"`
+	SealedRows     int64             `json:"sealed_rows"`
+	IndexedRows    int64             `json:"indexed_rows"`
+	Coverage       float64           `json:"coverage"`
+	Segments       []*IndexedSegment `json:"segments"`
+}
+
+// indexCoverage returns the fraction of the sealed rows covered by a built index, 1 if there is no sealed row.
+func indexCoverage(sealedRows, indexedRows int64) float64 {
+	if sealedRows == 0 {
+		return 1
+	}
+	return float64(indexedRows) / float64(sealedRows)
+}
+
+// listIndexedSegments lists the sealed segments of the collection with their finished indexes.
+func (node *Proxy) listIndexedSegments(ctx context.Context, request *IndexedSegmentsRequest) (*IndexedSegmentsResult, error) {
+	if request.CollectionName == "" {
+		return nil, merr.WrapErrParameterMissing("collection_name")
+	}
+	indexReq := &milvuspb.DescribeIndexRequest{
+		DbName:         request.DbName,
+		CollectionName: request.CollectionName,
+	}
+	if err := checkMgrPrivilege(ctx, indexReq); err != nil {
+		return nil, err
+	}
+	return node.getIndexedSegments(ctx, request.DbName, request.CollectionName)
+}
+
+// getIndexedSegments returns the sealed segments of the collection and the index coverage, without the privilege check.
+func (node *Proxy) getIndexedSegments(ctx context.Context, dbName, collectionName string) (*IndexedSegmentsResult, error) {
+	collectionID, err := globalMetaCache.GetCollectionID(ctx, dbName, collectionName)
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := globalMetaCache.GetPartitions(ctx, dbName, collectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := getSegmentInfos(ctx, node.dataCoord, collectionID, lo.Values(partitions))
+	if err != nil {
+		return nil, err
+	}
+	// only the finished indexes of the flushed segments are returned
+	indexInfos, err := getSegmentIndexInfos(ctx, node.dataCoord, collectionID, segments)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &IndexedSegmentsResult{
+		CollectionName: collectionName,
+		CollectionID:   collectionID,
+		Segments:       make([]*IndexedSegment, 0, len(segments)),
+	}
+	for _, segment := range segments {
+		if segment.GetState() == commonpb.SegmentState_Growing {
+			continue
+		}
+		indexIDs := lo.Map(indexInfos[segment.GetID()].GetIndexInfos(), func(info *indexpb.IndexFilePathInfo, _ int) int64 {
+			return info.GetIndexID()
+		})
+		indexed := len(indexIDs) > 0
+		result.Segments = append(result.Segments, &IndexedSegment{
+			SegmentID:   segment.GetID(),
+			PartitionID: segment.GetPartitionID(),
+			State:       segment.GetState().String(),
+			NumRows:     segment.GetNumOfRows(),
+			Indexed:     indexed,
+			IndexIDs:    indexIDs,
+		})
+		result.SealedRows += segment.GetNumOfRows()
+		if indexed {
+			result.IndexedRows += segment.GetNumOfRows()
+		}
+	}
+	sort.Slice(result.Segments, func(i, j int) bool {
+		return result.Segments[i].SegmentID < result.Segments[j].SegmentID
+	})
+	result.Coverage = indexCoverage(result.SealedRows, result.IndexedRows)
+	return result, nil
+}
+
+// indexCoverageLoop updates the index coverage metric of the loaded collections periodically,
+// independent of the management requests.
+func (node *Proxy) indexCoverageLoop() {
+	defer node.wg.Done()
+	interval := Params.ProxyCfg.IndexCoverageInterval.GetAsDuration(time.Second)
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-node.ctx.Done():
+			return
+		case <-ticker.C:
+			node.updateIndexCoverage(node.ctx)
+		}
+	}
+}
+
+// updateIndexCoverage sets the index coverage metric of each collection loaded by queryCoord.
+func (node *Proxy) updateIndexCoverage(ctx context.Context) {
+	resp, err := node.queryCoord.ShowCollections(ctx, &querypb.ShowCollectionsRequest{
+		Base: commonpbutil.NewMsgBase(
+			commonpbutil.WithMsgType(commonpb.MsgType_ShowCollections),
+			commonpbutil.WithSourceID(paramtable.GetNodeID()),
+		),
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		log.Warn("failed to show loaded collections for index coverage", zap.Error(err))
+		return
+	}
+	if len(resp.GetCollectionIDs()) == 0 {
+		return
+	}
+	dbNames, collectionNames, err := globalMetaCache.GetCollectionNamesByID(ctx, resp.GetCollectionIDs())
+	if err != nil {
+		log.Warn("failed to get the names of loaded collections for index coverage", zap.Error(err))
+		return
+	}
+	nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	for i := range collectionNames {
+		result, err := node.getIndexedSegments(ctx, dbNames[i], collectionNames[i])
+		if err != nil {
+			log.Warn("failed to update index coverage", zap.String("db", dbNames[i]),
+				zap.String("collection", collectionNames[i]), zap.Error(err))
+			continue
+		}
+		metrics.ProxyIndexCoverage.WithLabelValues(nodeID, dbNames[i], collectionNames[i]).Set(result.Coverage)
+	}
+}
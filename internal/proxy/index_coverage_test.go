@@ -0,0 +1,129 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestIndexCoverage(t *testing.T) {
+	assert.Equal(t, float64(1), indexCoverage(0, 0))
+	assert.Equal(t, 0.25, indexCoverage(400, 100))
+}
+
+func TestListIndexedSegments(t *testing.T) {
+	ctx := context.Background()
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetCollectionID(mock.Anything, mock.Anything, mock.Anything).Return(1, nil)
+	mockCache.EXPECT().GetPartitions(mock.Anything, mock.Anything, mock.Anything).Return(map[string]int64{"_default": 10}, nil)
+	globalMetaCache = mockCache
+
+	dc := mocks.NewMockDataCoordClient(t)
+	dc.EXPECT().GetSegmentsByStates(mock.Anything, mock.Anything).Return(&datapb.GetSegmentsByStatesResponse{
+		Status:   merr.Success(),
+		Segments: []int64{100, 101, 102, 103},
+	}, nil)
+	dc.EXPECT().GetSegmentInfo(mock.Anything, mock.Anything).Return(&datapb.GetSegmentInfoResponse{
+		Status: merr.Success(),
+		Infos: []*datapb.SegmentInfo{
+			{ID: 103, PartitionID: 10, State: commonpb.SegmentState_Flushed, NumOfRows: 300},
+			{ID: 100, PartitionID: 10, State: commonpb.SegmentState_Growing, NumOfRows: 10},
+			{ID: 101, PartitionID: 10, State: commonpb.SegmentState_Sealed, NumOfRows: 100},
+			{ID: 102, PartitionID: 10, State: commonpb.SegmentState_Flushed, NumOfRows: 600},
+		},
+	}, nil)
+	dc.EXPECT().GetIndexInfos(mock.Anything, mock.Anything).Return(&indexpb.GetIndexInfoResponse{
+		Status: merr.Success(),
+		SegmentInfo: map[int64]*indexpb.SegmentInfo{
+			102: {IndexInfos: []*indexpb.IndexFilePathInfo{{IndexID: 1}, {IndexID: 2}}},
+			103: {},
+		},
+	}, nil)
+
+	node := &Proxy{dataCoord: dc}
+	result, err := node.listIndexedSegments(ctx, &IndexedSegmentsRequest{CollectionName: "coll"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), result.SealedRows)
+	assert.Equal(t, int64(600), result.IndexedRows)
+	assert.Equal(t, 0.6, result.Coverage)
+	assert.Len(t, result.Segments, 3)
+	assert.Equal(t, int64(101), result.Segments[0].SegmentID)
+	assert.False(t, result.Segments[0].Indexed)
+	assert.Equal(t, []int64{1, 2}, result.Segments[1].IndexIDs)
+	assert.True(t, result.Segments[1].Indexed)
+	assert.False(t, result.Segments[2].Indexed)
+
+	_, err = node.listIndexedSegments(ctx, &IndexedSegmentsRequest{})
+	assert.ErrorIs(t, err, merr.ErrParameterMissing)
+}
+
+func TestUpdateIndexCoverage(t *testing.T) {
+	ctx := context.Background()
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetCollectionNamesByID(mock.Anything, []int64{1}).Return([]string{"db"}, []string{"coll"}, nil)
+	mockCache.EXPECT().GetCollectionID(mock.Anything, "db", "coll").Return(1, nil)
+	mockCache.EXPECT().GetPartitions(mock.Anything, "db", "coll").Return(map[string]int64{"_default": 10}, nil)
+	globalMetaCache = mockCache
+
+	qc := mocks.NewMockQueryCoordClient(t)
+	qc.EXPECT().ShowCollections(mock.Anything, mock.Anything).Return(&querypb.ShowCollectionsResponse{
+		Status:        merr.Success(),
+		CollectionIDs: []int64{1},
+	}, nil)
+	dc := mocks.NewMockDataCoordClient(t)
+	dc.EXPECT().GetSegmentsByStates(mock.Anything, mock.Anything).Return(&datapb.GetSegmentsByStatesResponse{
+		Status:   merr.Success(),
+		Segments: []int64{100, 101},
+	}, nil)
+	dc.EXPECT().GetSegmentInfo(mock.Anything, mock.Anything).Return(&datapb.GetSegmentInfoResponse{
+		Status: merr.Success(),
+		Infos: []*datapb.SegmentInfo{
+			{ID: 100, PartitionID: 10, State: commonpb.SegmentState_Flushed, NumOfRows: 300},
+			{ID: 101, PartitionID: 10, State: commonpb.SegmentState_Flushed, NumOfRows: 100},
+		},
+	}, nil)
+	dc.EXPECT().GetIndexInfos(mock.Anything, mock.Anything).Return(&indexpb.GetIndexInfoResponse{
+		Status: merr.Success(),
+		SegmentInfo: map[int64]*indexpb.SegmentInfo{
+			100: {IndexInfos: []*indexpb.IndexFilePathInfo{{IndexID: 1}}},
+		},
+	}, nil)
+
+	node := &Proxy{dataCoord: dc, queryCoord: qc}
+	node.updateIndexCoverage(ctx)
+	gauge := metrics.ProxyIndexCoverage.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), "db", "coll")
+	assert.Equal(t, 0.75, testutil.ToFloat64(gauge))
+}
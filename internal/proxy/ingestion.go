@@ -0,0 +1,734 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/internal/storage"
+	jsonparser "github.com/milvus-io/milvus/internal/util/importutilv2/json"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper"
+	kafkawrapper "github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper/kafka"
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/contextutil"
+	"github.com/milvus-io/milvus/pkg/util/crypto"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/retry"
+)
+
+const (
+	ingestionSourcePrefix     = "proxy/ingestion/source"
+	ingestionCheckpointPrefix = "proxy/ingestion/checkpoint"
+	ingestionOwnerPrefix      = "proxy/ingestion/owner"
+
+	ingestionErrorProperty  = "ingestion_error"
+	ingestionSourceProperty = "ingestion_source"
+
+	ingestionInsertAttempts = 3
+)
+
+// errIngestionSourceOwned means the source is consumed by another proxy.
+var errIngestionSourceOwned = errors.New("ingestion source is owned by another proxy")
+
+// IngestionSource declares a kafka topic consumed by the proxy and inserted into a collection,
+// each message of the topic is a json object of a row.
+type IngestionSource struct {
+	Name string `json:"name"`
+	// Address is the bootstrap servers of the kafka, the kafka of milvus is used if empty,
+	// otherwise each of the servers must be allowed by proxy.ingestion.allowedAddresses.
+	Address        string `json:"address,omitempty"`
+	Topic          string `json:"topic"`
+	DbName         string `json:"db_name,omitempty"`
+	CollectionName string `json:"collection_name"`
+	PartitionName  string `json:"partition_name,omitempty"`
+	// FieldMapping maps the keys of the messages to the field names, the keys not in the mapping are kept as is.
+	FieldMapping map[string]string `json:"field_mapping,omitempty"`
+	// ErrorTopic receives the messages failed to parse or insert, they are dropped if empty.
+	ErrorTopic string `json:"error_topic,omitempty"`
+	BatchRows  int    `json:"batch_rows,omitempty"`
+	// Owner is the user created the source, the rows are inserted with the privileges of the user.
+	Owner string `json:"owner,omitempty"`
+}
+
+func (s *IngestionSource) validate() error {
+	if s.Name == "" || s.Topic == "" || s.CollectionName == "" {
+		return merr.WrapErrParameterInvalidMsg("name, topic and collection_name of the ingestion source are required")
+	}
+	if s.ErrorTopic == s.Topic {
+		return merr.WrapErrParameterInvalidMsg("the error topic must differ from the topic %s", s.Topic)
+	}
+	if s.BatchRows < 0 {
+		return merr.WrapErrParameterInvalidMsg("invalid batch_rows %d", s.BatchRows)
+	}
+	if s.Address != "" {
+		allowed := Params.ProxyCfg.IngestionAllowedAddresses.GetAsStrings()
+		for _, server := range strings.Split(s.Address, ",") {
+			if !funcutil.IsAddressAllowed(strings.TrimSpace(server), allowed) {
+				return merr.WrapErrParameterInvalidMsg("kafka address %s is not allowed", server)
+			}
+		}
+	}
+	return nil
+}
+
+// ownerContext returns the context carrying the identity of the owner, which the privilege of the inserts is checked against.
+func (s *IngestionSource) ownerContext(ctx context.Context) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(util.HeaderAuthorize, crypto.Base64Encode(s.Owner+util.CredentialSeperator)))
+}
+
+// subscription is the subscription name of the source, different per cluster.
+func (s *IngestionSource) subscription() string {
+	return fmt.Sprintf("%s-ingestion-%s", Params.CommonCfg.ClusterPrefix.GetValue(), s.Name)
+}
+
+// mapRow renames the keys of the message by the field mapping.
+func (s *IngestionSource) mapRow(raw map[string]any) map[string]any {
+	if len(s.FieldMapping) == 0 {
+		return raw
+	}
+	row := make(map[string]any, len(raw))
+	for key, value := range raw {
+		if fieldName, ok := s.FieldMapping[key]; ok {
+			key = fieldName
+		}
+		row[key] = value
+	}
+	return row
+}
+
+// IngestionSourceStatus is the source with the consuming statistics of this proxy,
+// Owned is whether this proxy is the one consuming the source.
+type IngestionSourceStatus struct {
+	*IngestionSource
+	Owned     bool   `json:"owned"`
+	Consumed  int64  `json:"consumed"`
+	Inserted  int64  `json:"inserted"`
+	Failed    int64  `json:"failed"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// ingestionBatch accumulates the parsed rows and the position of the last consumed message.
+type ingestionBatch struct {
+	schema   *schemapb.CollectionSchema
+	data     *storage.InsertData
+	payloads [][]byte
+	lastID   mqwrapper.MessageID
+}
+
+func newIngestionBatch(schema *schemapb.CollectionSchema) (*ingestionBatch, error) {
+	// the auto id primary key is not provided by the rows
+	fields := make([]*schemapb.FieldSchema, 0, len(schema.GetFields()))
+	for _, field := range schema.GetFields() {
+		if !(field.GetIsPrimaryKey() && field.GetAutoID()) {
+			fields = append(fields, field)
+		}
+	}
+	schema = proto.Clone(schema).(*schemapb.CollectionSchema)
+	schema.Fields = fields
+	data, err := storage.NewInsertData(schema)
+	if err != nil {
+		return nil, err
+	}
+	return &ingestionBatch{schema: schema, data: data}, nil
+}
+
+func (b *ingestionBatch) append(row jsonparser.Row, payload []byte) error {
+	if err := b.data.Append(row); err != nil {
+		return err
+	}
+	b.payloads = append(b.payloads, payload)
+	return nil
+}
+
+func (b *ingestionBatch) rows() int {
+	return len(b.payloads)
+}
+
+// fieldsData converts the rows to the columns of the insert request.
+func (b *ingestionBatch) fieldsData() ([]*schemapb.FieldData, error) {
+	record, err := storage.TransferInsertDataToInsertRecord(b.data)
+	if err != nil {
+		return nil, err
+	}
+	id2Field := make(map[int64]*schemapb.FieldSchema, len(b.schema.GetFields()))
+	for _, field := range b.schema.GetFields() {
+		id2Field[field.GetFieldID()] = field
+	}
+	for _, fieldData := range record.GetFieldsData() {
+		field := id2Field[fieldData.GetFieldId()]
+		fieldData.FieldName = field.GetName()
+		fieldData.IsDynamic = field.GetIsDynamic()
+	}
+	return record.GetFieldsData(), nil
+}
+
+// ingestionWorker consumes the topic of a source from the checkpoint, the checkpoint is saved after the rows
+// consumed before it are inserted or sent to the error topic, so the rows are inserted at least once.
+type ingestionWorker struct {
+	source  *IngestionSource
+	manager *ingestionManager
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu        sync.Mutex
+	owned     bool
+	consumed  int64
+	inserted  int64
+	failed    int64
+	lastError string
+}
+
+func (w *ingestionWorker) status() *IngestionSourceStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return &IngestionSourceStatus{
+		IngestionSource: w.source,
+		Owned:           w.owned,
+		Consumed:        w.consumed,
+		Inserted:        w.inserted,
+		Failed:          w.failed,
+		LastError:       w.lastError,
+	}
+}
+
+func (w *ingestionWorker) record(fn func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fn()
+}
+
+// run consumes the topic until stopped once it takes the ownership of the source, and retries after the retry interval
+// once failed or the source is owned by another proxy.
+func (w *ingestionWorker) run(ctx context.Context) {
+	defer close(w.done)
+	log := log.With(zap.String("source", w.source.Name), zap.String("topic", w.source.Topic))
+	for {
+		err := w.consumeOwned(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, errIngestionSourceOwned) {
+			log.Debug("ingestion source is owned by another proxy, retry later")
+		} else {
+			log.Warn("ingestion source stopped consuming, retry later", zap.Error(err))
+			w.record(func() { w.lastError = err.Error() })
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(Params.ProxyCfg.IngestionRetryInterval.GetAsDuration(time.Second)):
+		}
+	}
+}
+
+// consumeOwned consumes the topic while this proxy owns the source, so that the source is consumed by only one proxy.
+func (w *ingestionWorker) consumeOwned(ctx context.Context) error {
+	lost, release, err := w.manager.claim(ctx, w.source.Name)
+	if err != nil {
+		return err
+	}
+	defer release()
+	w.record(func() { w.owned = true })
+	defer w.record(func() { w.owned = false })
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-lost:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	err = w.consume(ctx)
+	select {
+	case <-lost:
+		return merr.WrapErrServiceInternal(fmt.Sprintf("lost the ownership of ingestion source %s", w.source.Name))
+	default:
+		return err
+	}
+}
+
+func (w *ingestionWorker) consume(ctx context.Context) error {
+	source := w.source
+	schema, err := w.manager.getSchema(ctx, source.DbName, source.CollectionName)
+	if err != nil {
+		return err
+	}
+	parser, err := jsonparser.NewRowParser(schema)
+	if err != nil {
+		return err
+	}
+	batch, err := newIngestionBatch(schema)
+	if err != nil {
+		return err
+	}
+
+	client, err := w.manager.newClient(ctx, source.Address)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	checkpointKey := path.Join(ingestionCheckpointPrefix, source.Name)
+	checkpoint, err := w.manager.kv.Load(checkpointKey)
+	if err != nil && !errors.Is(err, merr.ErrIoKeyNotFound) {
+		return err
+	}
+	position := mqwrapper.SubscriptionPositionEarliest
+	if checkpoint != "" {
+		position = mqwrapper.SubscriptionPositionUnknown
+	}
+	consumer, err := client.Subscribe(mqwrapper.ConsumerOptions{
+		Topic:                       source.Topic,
+		SubscriptionName:            source.subscription(),
+		SubscriptionInitialPosition: position,
+		BufSize:                     1024,
+	})
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+	if checkpoint != "" {
+		id, err := client.BytesToMsgID([]byte(checkpoint))
+		if err != nil {
+			return err
+		}
+		if err := consumer.Seek(id, false); err != nil {
+			return err
+		}
+	}
+
+	var errorProducer mqwrapper.Producer
+	defer func() {
+		if errorProducer != nil {
+			errorProducer.Close()
+		}
+	}()
+	// sendError sends the failed message to the error topic if any, the consuming stops if failed to send.
+	sendError := func(payload []byte, cause error) error {
+		w.record(func() {
+			w.failed++
+			w.lastError = cause.Error()
+		})
+		if source.ErrorTopic == "" {
+			log.RatedWarn(60, "drop the message failed to ingest", zap.String("source", source.Name), zap.Error(cause))
+			return nil
+		}
+		if errorProducer == nil {
+			errorProducer, err = client.CreateProducer(mqwrapper.ProducerOptions{Topic: source.ErrorTopic})
+			if err != nil {
+				return err
+			}
+		}
+		_, err := errorProducer.Send(ctx, &mqwrapper.ProducerMessage{
+			Payload: payload,
+			Properties: map[string]string{
+				ingestionSourceProperty: source.Name,
+				ingestionErrorProperty:  cause.Error(),
+			},
+		})
+		return err
+	}
+
+	batchRows := source.BatchRows
+	if batchRows <= 0 {
+		batchRows = Params.ProxyCfg.IngestionBatchRows.GetAsInt()
+	}
+	flush := func() error {
+		if batch.rows() > 0 {
+			err := w.insert(ctx, batch)
+			if err != nil {
+				for _, payload := range batch.payloads {
+					if err := sendError(payload, err); err != nil {
+						return err
+					}
+				}
+			} else {
+				w.record(func() { w.inserted += int64(batch.rows()) })
+			}
+		}
+		if batch.lastID != nil {
+			// the checkpoint of the dropped source is not saved again
+			if _, err := w.manager.kv.Load(path.Join(ingestionSourcePrefix, source.Name)); err != nil {
+				return err
+			}
+			if err := w.manager.kv.Save(checkpointKey, string(batch.lastID.Serialize())); err != nil {
+				return err
+			}
+		}
+		batch, err = newIngestionBatch(schema)
+		return err
+	}
+
+	ticker := time.NewTicker(Params.ProxyCfg.IngestionFlushInterval.GetAsDuration(time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// the rows not flushed are consumed again from the checkpoint
+			return ctx.Err()
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		case msg, ok := <-consumer.Chan():
+			if !ok {
+				return merr.WrapErrServiceInternal(fmt.Sprintf("consumer of topic %s closed", source.Topic))
+			}
+			consumer.Ack(msg)
+			w.record(func() { w.consumed++ })
+			payload := msg.Payload()
+			if err := w.parse(parser, batch, payload); err != nil {
+				if err := sendError(payload, err); err != nil {
+					return err
+				}
+			}
+			batch.lastID = msg.ID()
+			if batch.rows() >= batchRows {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (w *ingestionWorker) parse(parser jsonparser.RowParser, batch *ingestionBatch, payload []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	raw := make(map[string]any)
+	if err := decoder.Decode(&raw); err != nil {
+		return merr.WrapErrParameterInvalidMsg("invalid json message: %s", err.Error())
+	}
+	row, err := parser.Parse(w.source.mapRow(raw))
+	if err != nil {
+		return err
+	}
+	return batch.append(row, payload)
+}
+
+// insert writes the batch through the insert path of the proxy, retried for the transient failures.
+func (w *ingestionWorker) insert(ctx context.Context, batch *ingestionBatch) error {
+	fieldsData, err := batch.fieldsData()
+	if err != nil {
+		return err
+	}
+	req := &milvuspb.InsertRequest{
+		DbName:         w.source.DbName,
+		CollectionName: w.source.CollectionName,
+		PartitionName:  w.source.PartitionName,
+		FieldsData:     fieldsData,
+		NumRows:        uint32(batch.rows()),
+	}
+	ctx = w.source.ownerContext(ctx)
+	if _, err := PrivilegeInterceptor(ctx, req); err != nil {
+		return err
+	}
+	return retry.Do(ctx, func() error {
+		resp, err := w.manager.insert(ctx, req)
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			if errors.Is(err, merr.ErrParameterInvalid) || errors.Is(err, merr.ErrCollectionNotFound) {
+				return retry.Unrecoverable(err)
+			}
+			return err
+		}
+		return nil
+	}, retry.Attempts(ingestionInsertAttempts))
+}
+
+func (w *ingestionWorker) stop() {
+	w.cancel()
+	<-w.done
+}
+
+// ingestionManager runs the ingestion sources if the ingestion is enabled on this proxy,
+// the sources are persisted in the meta kv and synced by every enabled proxy, each source is consumed by the proxy
+// holding its ownership, so that it is taken over by another proxy once the owner is down.
+type ingestionManager struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	kv      kv.BaseKV
+	workers map[string]*ingestionWorker
+	closed  bool
+
+	// claim takes the ownership of the source, the returned channel is closed once the ownership is lost.
+	claim     func(ctx context.Context, name string) (<-chan struct{}, func(), error)
+	newClient func(ctx context.Context, address string) (mqwrapper.Client, error)
+	getSchema func(ctx context.Context, dbName, collectionName string) (*schemapb.CollectionSchema, error)
+	insert    func(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error)
+}
+
+var globalIngestionManager = &ingestionManager{workers: make(map[string]*ingestionWorker)}
+
+func newIngestionKafkaClient(ctx context.Context, address string) (mqwrapper.Client, error) {
+	if address == "" {
+		return kafkawrapper.NewKafkaClientInstanceWithConfig(ctx, &paramtable.Get().KafkaCfg)
+	}
+	return kafkawrapper.NewKafkaClientInstance(address), nil
+}
+
+// newIngestionClaim returns the claim of the sources by the etcd mutex under the lease of the proxy,
+// the lease expires after the session ttl once the proxy is down.
+func newIngestionClaim(etcdCli *clientv3.Client, rootPath string) func(ctx context.Context, name string) (<-chan struct{}, func(), error) {
+	return func(ctx context.Context, name string) (<-chan struct{}, func(), error) {
+		session, err := concurrency.NewSession(etcdCli,
+			concurrency.WithTTL(Params.CommonCfg.SessionTTL.GetAsInt()), concurrency.WithContext(ctx))
+		if err != nil {
+			return nil, nil, err
+		}
+		mutex := concurrency.NewMutex(session, path.Join(rootPath, ingestionOwnerPrefix, name))
+		if err := mutex.TryLock(ctx); err != nil {
+			session.Close()
+			if errors.Is(err, concurrency.ErrLocked) {
+				return nil, nil, errIngestionSourceOwned
+			}
+			return nil, nil, err
+		}
+		// closing the session revokes the lease, which releases the mutex
+		return session.Done(), func() { session.Close() }, nil
+	}
+}
+
+// init binds the manager to the proxy, it is a no-op if the ingestion is disabled.
+func (m *ingestionManager) init(node *Proxy, metaKV kv.BaseKV) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ctx = node.ctx
+	m.kv = metaKV
+	m.claim = newIngestionClaim(node.etcdCli, Params.EtcdCfg.MetaRootPath.GetValue())
+	m.newClient = newIngestionKafkaClient
+	m.getSchema = func(ctx context.Context, dbName, collectionName string) (*schemapb.CollectionSchema, error) {
+		schema, err := globalMetaCache.GetCollectionSchema(ctx, dbName, collectionName)
+		if err != nil {
+			return nil, err
+		}
+		return schema.CollectionSchema, nil
+	}
+	m.insert = node.Insert
+}
+
+func (m *ingestionManager) enabled() bool {
+	return m.kv != nil && Params.ProxyCfg.IngestionEnabled.GetAsBool()
+}
+
+// start resumes the persisted sources, and keeps syncing them with the sources created or dropped on the other proxies.
+func (m *ingestionManager) start() error {
+	if !m.enabled() {
+		return nil
+	}
+	if err := m.sync(); err != nil {
+		return err
+	}
+	go m.syncLoop()
+	return nil
+}
+
+func (m *ingestionManager) syncLoop() {
+	ticker := time.NewTicker(Params.ProxyCfg.IngestionRetryInterval.GetAsDuration(time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.sync(); err != nil {
+				log.Warn("failed to sync the ingestion sources", zap.Error(err))
+			}
+		}
+	}
+}
+
+// sync runs the persisted sources not running yet, and stops the ones dropped.
+func (m *ingestionManager) sync() error {
+	_, values, err := m.kv.LoadWithPrefix(ingestionSourcePrefix)
+	if err != nil {
+		return err
+	}
+	sources := make(map[string]*IngestionSource, len(values))
+	for _, value := range values {
+		source := &IngestionSource{}
+		if err := json.Unmarshal([]byte(value), source); err != nil {
+			log.Warn("skip the invalid ingestion source", zap.String("source", value), zap.Error(err))
+			continue
+		}
+		sources[source.Name] = source
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil
+	}
+	for name, worker := range m.workers {
+		if _, ok := sources[name]; !ok {
+			worker.stop()
+			delete(m.workers, name)
+		}
+	}
+	for name, source := range sources {
+		if _, ok := m.workers[name]; !ok {
+			m.run(source)
+		}
+	}
+	return nil
+}
+
+func (m *ingestionManager) run(source *IngestionSource) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	worker := &ingestionWorker{
+		source:  source,
+		manager: m,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	m.workers[source.Name] = worker
+	go worker.run(ctx)
+	log.Info("ingestion source started", zap.String("source", source.Name), zap.String("topic", source.Topic),
+		zap.String("collection", source.CollectionName))
+}
+
+// create persists the source and starts consuming it, the user in ctx must be able to insert into the collection
+// and is recorded as the owner of the source.
+func (m *ingestionManager) create(ctx context.Context, source *IngestionSource) error {
+	if !m.enabled() {
+		return merr.WrapErrServiceUnavailable("ingestion is disabled on this proxy")
+	}
+	if err := source.validate(); err != nil {
+		return err
+	}
+	if err := checkMgrPrivilege(ctx, &milvuspb.InsertRequest{DbName: source.DbName, CollectionName: source.CollectionName}); err != nil {
+		return err
+	}
+	source.Owner = ""
+	if Params.CommonCfg.AuthorizationEnabled.GetAsBool() {
+		owner, err := contextutil.GetCurUserFromContext(ctx)
+		if err != nil {
+			return err
+		}
+		source.Owner = owner
+	}
+	if _, err := m.getSchema(ctx, source.DbName, source.CollectionName); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sourceKey := path.Join(ingestionSourcePrefix, source.Name)
+	if _, ok := m.workers[source.Name]; ok {
+		return merr.WrapErrParameterInvalidMsg("ingestion source %s already exists", source.Name)
+	}
+	if exist, err := m.kv.Has(sourceKey); err != nil || exist {
+		if err != nil {
+			return err
+		}
+		return merr.WrapErrParameterInvalidMsg("ingestion source %s already exists", source.Name)
+	}
+	value, err := json.Marshal(source)
+	if err != nil {
+		return err
+	}
+	if err := m.kv.Save(sourceKey, string(value)); err != nil {
+		return err
+	}
+	m.run(source)
+	return nil
+}
+
+// drop stops consuming the source and removes it along with its checkpoint, only the owner and the admin are allowed,
+// the proxy consuming it stops once it syncs the sources.
+func (m *ingestionManager) drop(ctx context.Context, name string) error {
+	if !m.enabled() {
+		return merr.WrapErrServiceUnavailable("ingestion is disabled on this proxy")
+	}
+	sourceKey := path.Join(ingestionSourcePrefix, name)
+	value, err := m.kv.Load(sourceKey)
+	if err != nil {
+		if errors.Is(err, merr.ErrIoKeyNotFound) {
+			return merr.WrapErrParameterInvalidMsg("ingestion source %s not found", name)
+		}
+		return err
+	}
+	source := &IngestionSource{}
+	if err := json.Unmarshal([]byte(value), source); err != nil {
+		return err
+	}
+	if !m.accessible(ctx, source) {
+		return status.Error(codes.PermissionDenied, fmt.Sprintf("permission deny to drop ingestion source %s", name))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if worker, ok := m.workers[name]; ok {
+		worker.stop()
+		delete(m.workers, name)
+	}
+	return m.kv.MultiRemove([]string{sourceKey, path.Join(ingestionCheckpointPrefix, name)})
+}
+
+// accessible returns whether the user in ctx is the owner of the source or the admin.
+func (m *ingestionManager) accessible(ctx context.Context, source *IngestionSource) bool {
+	if checkMgrAdmin(ctx) == nil {
+		return true
+	}
+	user, err := contextutil.GetCurUserFromContext(ctx)
+	return err == nil && user == source.Owner
+}
+
+// list returns the sources accessible to the user in ctx.
+func (m *ingestionManager) list(ctx context.Context) []*IngestionSourceStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ret := make([]*IngestionSourceStatus, 0, len(m.workers))
+	for _, worker := range m.workers {
+		if m.accessible(ctx, worker.source) {
+			ret = append(ret, worker.status())
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+func (m *ingestionManager) close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	for name, worker := range m.workers {
+		worker.stop()
+		delete(m.workers, name)
+	}
+}
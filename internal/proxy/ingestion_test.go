@@ -0,0 +1,370 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/binary"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	memkv "github.com/milvus-io/milvus/internal/kv/mem"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type testIngestionID uint64
+
+func (id testIngestionID) Serialize() []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(id))
+}
+
+func (id testIngestionID) AtEarliestPosition() bool { return id == 0 }
+
+func (id testIngestionID) LessOrEqualThan(msgID []byte) (bool, error) {
+	return uint64(id) <= binary.BigEndian.Uint64(msgID), nil
+}
+
+func (id testIngestionID) Equal(msgID []byte) (bool, error) {
+	return uint64(id) == binary.BigEndian.Uint64(msgID), nil
+}
+
+type testIngestionMessage struct {
+	id      testIngestionID
+	payload []byte
+}
+
+func (m *testIngestionMessage) Topic() string                 { return "" }
+func (m *testIngestionMessage) Properties() map[string]string { return nil }
+func (m *testIngestionMessage) Payload() []byte               { return m.payload }
+func (m *testIngestionMessage) ID() mqwrapper.MessageID       { return m.id }
+
+// testIngestionClient serves the messages of a topic from the seek position, and collects the produced messages.
+type testIngestionClient struct {
+	mqwrapper.Client
+	mqwrapper.Consumer
+
+	payloads []string
+	ch       chan mqwrapper.Message
+	once     sync.Once
+
+	mu       sync.Mutex
+	seekID   testIngestionID
+	produced []*mqwrapper.ProducerMessage
+}
+
+func (c *testIngestionClient) Subscribe(options mqwrapper.ConsumerOptions) (mqwrapper.Consumer, error) {
+	c.ch = make(chan mqwrapper.Message, len(c.payloads))
+	return c, nil
+}
+
+func (c *testIngestionClient) BytesToMsgID(id []byte) (mqwrapper.MessageID, error) {
+	return testIngestionID(binary.BigEndian.Uint64(id)), nil
+}
+
+func (c *testIngestionClient) Seek(id mqwrapper.MessageID, inclusive bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seekID = id.(testIngestionID)
+	return nil
+}
+
+func (c *testIngestionClient) Chan() <-chan mqwrapper.Message {
+	c.once.Do(func() {
+		for i, payload := range c.payloads {
+			if id := testIngestionID(i + 1); id > c.seekID {
+				c.ch <- &testIngestionMessage{id: id, payload: []byte(payload)}
+			}
+		}
+	})
+	return c.ch
+}
+
+func (c *testIngestionClient) Ack(mqwrapper.Message) {}
+
+func (c *testIngestionClient) CreateProducer(options mqwrapper.ProducerOptions) (mqwrapper.Producer, error) {
+	return c, nil
+}
+
+func (c *testIngestionClient) Send(ctx context.Context, message *mqwrapper.ProducerMessage) (mqwrapper.MessageID, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.produced = append(c.produced, message)
+	return testIngestionID(0), nil
+}
+
+func (c *testIngestionClient) getProduced() []*mqwrapper.ProducerMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.produced
+}
+
+func (c *testIngestionClient) Close() {}
+
+func newTestIngestionManager(client *testIngestionClient, insert func(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error)) *ingestionManager {
+	schema := constructCollectionSchema("pk", "vec", 2, "coll")
+	return &ingestionManager{
+		ctx:     context.Background(),
+		kv:      memkv.NewMemoryKV(),
+		workers: make(map[string]*ingestionWorker),
+		claim: func(ctx context.Context, name string) (<-chan struct{}, func(), error) {
+			return make(chan struct{}), func() {}, nil
+		},
+		newClient: func(ctx context.Context, address string) (mqwrapper.Client, error) {
+			return client, nil
+		},
+		getSchema: func(ctx context.Context, dbName, collectionName string) (*schemapb.CollectionSchema, error) {
+			if collectionName != "coll" {
+				return nil, merr.WrapErrCollectionNotFound(collectionName)
+			}
+			return schema, nil
+		},
+		insert: insert,
+	}
+}
+
+func TestIngestionSource(t *testing.T) {
+	source := &IngestionSource{Name: "s", Topic: "t", CollectionName: "coll"}
+	assert.NoError(t, source.validate())
+	assert.Error(t, (&IngestionSource{Name: "s", CollectionName: "coll"}).validate())
+	assert.Error(t, (&IngestionSource{Name: "s", Topic: "t", CollectionName: "coll", ErrorTopic: "t"}).validate())
+	assert.Error(t, (&IngestionSource{Name: "s", Topic: "t", CollectionName: "coll", BatchRows: -1}).validate())
+
+	paramtable.Init()
+	paramtable.Get().Save(Params.ProxyCfg.IngestionAllowedAddresses.Key, "kafka:9092,*.example.com")
+	defer paramtable.Get().Reset(Params.ProxyCfg.IngestionAllowedAddresses.Key)
+	assert.NoError(t, (&IngestionSource{Name: "s", Topic: "t", CollectionName: "coll", Address: "kafka:9092, k1.example.com:9092"}).validate())
+	assert.Error(t, (&IngestionSource{Name: "s", Topic: "t", CollectionName: "coll", Address: "kafka:9092,169.254.169.254:80"}).validate())
+
+	raw := map[string]any{"a": 1, "b": 2}
+	assert.Equal(t, raw, source.mapRow(raw))
+	source.FieldMapping = map[string]string{"a": "c"}
+	assert.Equal(t, map[string]any{"c": 1, "b": 2}, source.mapRow(raw))
+}
+
+func TestIngestionManager(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(Params.ProxyCfg.IngestionEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.IngestionEnabled.Key)
+
+	t.Run("ingest", func(t *testing.T) {
+		client := &testIngestionClient{payloads: []string{
+			`{"embedding": [0.1, 0.2]}`,
+			`{"embedding": [0.1]}`,
+			`not json`,
+			`{"embedding": [0.3, 0.4]}`,
+		}}
+		mu := sync.Mutex{}
+		inserted := 0
+		m := newTestIngestionManager(client, func(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, "coll", req.GetCollectionName())
+			assert.Equal(t, 1, len(req.GetFieldsData()))
+			assert.Equal(t, "vec", req.GetFieldsData()[0].GetFieldName())
+			inserted += int(req.GetNumRows())
+			return &milvuspb.MutationResult{Status: merr.Success()}, nil
+		})
+		defer m.close()
+
+		source := &IngestionSource{
+			Name:           "s",
+			Topic:          "t",
+			CollectionName: "coll",
+			FieldMapping:   map[string]string{"embedding": "vec"},
+			ErrorTopic:     "errors",
+			BatchRows:      1,
+		}
+		assert.NoError(t, m.create(context.Background(), source))
+		assert.Error(t, m.create(context.Background(), source))
+		assert.Error(t, m.create(context.Background(), &IngestionSource{Name: "x", Topic: "t", CollectionName: "unknown"}))
+
+		assert.Eventually(t, func() bool {
+			status := m.list(context.Background())
+			return len(status) == 1 && status[0].Consumed == 4 && status[0].Inserted == 2
+		}, 5*time.Second, 10*time.Millisecond)
+		mu.Lock()
+		assert.Equal(t, 2, inserted)
+		mu.Unlock()
+		status := m.list(context.Background())[0]
+		assert.Equal(t, int64(2), status.Failed)
+		produced := client.getProduced()
+		assert.Equal(t, 2, len(produced))
+		assert.Equal(t, `not json`, string(produced[1].Payload))
+		assert.Equal(t, "s", produced[1].Properties[ingestionSourceProperty])
+		assert.NotEmpty(t, produced[1].Properties[ingestionErrorProperty])
+
+		checkpoint, err := m.kv.Load(path.Join(ingestionCheckpointPrefix, "s"))
+		assert.NoError(t, err)
+		assert.Equal(t, string(testIngestionID(4).Serialize()), checkpoint)
+
+		assert.NoError(t, m.drop(context.Background(), "s"))
+		assert.Error(t, m.drop(context.Background(), "s"))
+		assert.Empty(t, m.list(context.Background()))
+		_, err = m.kv.Load(path.Join(ingestionCheckpointPrefix, "s"))
+		assert.Error(t, err)
+	})
+
+	t.Run("resume from checkpoint", func(t *testing.T) {
+		client := &testIngestionClient{payloads: []string{
+			`{"vec": [0.1, 0.2]}`,
+			`{"vec": [0.3, 0.4]}`,
+		}}
+		m := newTestIngestionManager(client, func(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+			return &milvuspb.MutationResult{Status: merr.Success()}, nil
+		})
+		defer m.close()
+		source := &IngestionSource{Name: "s", Topic: "t", CollectionName: "coll"}
+		value := `{"name": "s", "topic": "t", "collection_name": "coll"}`
+		assert.NoError(t, m.kv.Save(path.Join(ingestionSourcePrefix, source.Name), value))
+		assert.NoError(t, m.kv.Save(path.Join(ingestionCheckpointPrefix, source.Name), string(testIngestionID(1).Serialize())))
+
+		assert.NoError(t, m.start())
+		assert.Eventually(t, func() bool {
+			status := m.list(context.Background())
+			return len(status) == 1 && status[0].Inserted == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, int64(1), m.list(context.Background())[0].Consumed)
+	})
+
+	t.Run("insert failed", func(t *testing.T) {
+		client := &testIngestionClient{payloads: []string{`{"vec": [0.1, 0.2]}`}}
+		m := newTestIngestionManager(client, func(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+			return &milvuspb.MutationResult{Status: merr.Status(merr.WrapErrParameterInvalidMsg("mock"))}, nil
+		})
+		defer m.close()
+		assert.NoError(t, m.create(context.Background(), &IngestionSource{Name: "s", Topic: "t", CollectionName: "coll", ErrorTopic: "errors"}))
+		assert.Eventually(t, func() bool {
+			return len(client.getProduced()) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, int64(0), m.list(context.Background())[0].Inserted)
+		assert.Equal(t, int64(1), m.list(context.Background())[0].Failed)
+	})
+
+	t.Run("owned by another proxy", func(t *testing.T) {
+		client := &testIngestionClient{payloads: []string{`{"vec": [0.1, 0.2]}`}}
+		m := newTestIngestionManager(client, nil)
+		defer m.close()
+		claimed := make(chan struct{}, 1)
+		m.claim = func(ctx context.Context, name string) (<-chan struct{}, func(), error) {
+			select {
+			case claimed <- struct{}{}:
+			default:
+			}
+			return nil, nil, errIngestionSourceOwned
+		}
+		assert.NoError(t, m.create(context.Background(), &IngestionSource{Name: "s", Topic: "t", CollectionName: "coll"}))
+		<-claimed
+		status := m.list(context.Background())[0]
+		assert.False(t, status.Owned)
+		assert.Equal(t, int64(0), status.Consumed)
+		assert.Empty(t, status.LastError)
+	})
+
+	t.Run("ownership lost", func(t *testing.T) {
+		client := &testIngestionClient{payloads: []string{`{"vec": [0.1, 0.2]}`}}
+		m := newTestIngestionManager(client, func(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+			return &milvuspb.MutationResult{Status: merr.Success()}, nil
+		})
+		defer m.close()
+		lost := make(chan struct{})
+		released := make(chan struct{})
+		m.claim = func(ctx context.Context, name string) (<-chan struct{}, func(), error) {
+			select {
+			case <-released:
+				return nil, nil, errIngestionSourceOwned
+			default:
+			}
+			return lost, func() { close(released) }, nil
+		}
+		assert.NoError(t, m.create(context.Background(), &IngestionSource{Name: "s", Topic: "t", CollectionName: "coll"}))
+		assert.Eventually(t, func() bool {
+			status := m.list(context.Background())
+			return status[0].Owned && status[0].Inserted == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		close(lost)
+		<-released
+		assert.Eventually(t, func() bool {
+			return !m.list(context.Background())[0].Owned
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		m := newTestIngestionManager(&testIngestionClient{}, nil)
+		defer m.close()
+		m.claim = func(ctx context.Context, name string) (<-chan struct{}, func(), error) {
+			return nil, nil, errIngestionSourceOwned
+		}
+		assert.NoError(t, m.start())
+		assert.Empty(t, m.list(context.Background()))
+
+		// the sources created and dropped on the other proxies
+		assert.NoError(t, m.kv.Save(path.Join(ingestionSourcePrefix, "s"), `{"name": "s", "topic": "t", "collection_name": "coll"}`))
+		assert.NoError(t, m.sync())
+		assert.Equal(t, 1, len(m.list(context.Background())))
+		assert.Error(t, m.create(context.Background(), &IngestionSource{Name: "s", Topic: "t", CollectionName: "coll"}))
+		assert.NoError(t, m.kv.Remove(path.Join(ingestionSourcePrefix, "s")))
+		assert.NoError(t, m.sync())
+		assert.Empty(t, m.list(context.Background()))
+	})
+
+	t.Run("owner", func(t *testing.T) {
+		paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+		defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+		cache := globalMetaCache
+		defer func() { globalMetaCache = cache }()
+		mockCache := NewMockCache(t)
+		mockCache.EXPECT().GetUserRole(mock.Anything).Return(nil).Maybe()
+		globalMetaCache = mockCache
+
+		m := newTestIngestionManager(&testIngestionClient{}, nil)
+		defer m.close()
+		m.claim = func(ctx context.Context, name string) (<-chan struct{}, func(), error) {
+			return nil, nil, errIngestionSourceOwned
+		}
+		ctx := context.Background()
+		assert.NoError(t, m.create(GetContext(ctx, "root:pwd"), &IngestionSource{Name: "r", Topic: "t", CollectionName: "coll"}))
+		assert.Equal(t, "root", m.list(GetContext(ctx, "root:pwd"))[0].Owner)
+		assert.NoError(t, m.kv.Save(path.Join(ingestionSourcePrefix, "b"), `{"name": "b", "topic": "t", "collection_name": "coll", "owner": "bob"}`))
+		assert.NoError(t, m.sync())
+
+		assert.Equal(t, 2, len(m.list(GetContext(ctx, "root:pwd"))))
+		assert.Equal(t, 1, len(m.list(GetContext(ctx, "bob:pwd"))))
+		assert.Empty(t, m.list(GetContext(ctx, "alice:pwd")))
+		assert.Error(t, m.drop(GetContext(ctx, "alice:pwd"), "b"))
+		assert.Error(t, m.drop(GetContext(ctx, "bob:pwd"), "r"))
+		assert.NoError(t, m.drop(GetContext(ctx, "bob:pwd"), "b"))
+		assert.NoError(t, m.drop(GetContext(ctx, "root:pwd"), "r"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		paramtable.Get().Save(Params.ProxyCfg.IngestionEnabled.Key, "false")
+		defer paramtable.Get().Save(Params.ProxyCfg.IngestionEnabled.Key, "true")
+		m := newTestIngestionManager(&testIngestionClient{}, nil)
+		assert.NoError(t, m.start())
+		assert.Error(t, m.create(context.Background(), &IngestionSource{Name: "s", Topic: "t", CollectionName: "coll"}))
+		assert.Error(t, m.drop(context.Background(), "s"))
+	})
+}
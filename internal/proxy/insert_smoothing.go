@@ -0,0 +1,232 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+const (
+	// insertSmoothingAdjustInterval is the interval to adjust the channel rates by the lag of the data nodes.
+	insertSmoothingAdjustInterval = 10 * time.Second
+	// insertSmoothingMinFactor is the lowest factor of the channel rate lowered by the lag.
+	insertSmoothingMinFactor = 0.125
+)
+
+// channelBucket is the token bucket of the bytes inserted into a dml channel, holding up to one second of the rate.
+// The tokens go negative for the inserts larger than the tokens left, which wait until the tokens are refilled.
+type channelBucket struct {
+	tokens float64
+	last   time.Time
+	factor float64
+}
+
+// insertSmoother smooths the bursts of the inserts by the token buckets of their dml channels, so the data nodes
+// consuming the channels are not flooded.
+type insertSmoother struct {
+	mu      sync.Mutex
+	buckets map[string]*channelBucket
+	now     func() time.Time
+}
+
+var globalInsertSmoother = newInsertSmoother()
+
+func newInsertSmoother() *insertSmoother {
+	return &insertSmoother{buckets: make(map[string]*channelBucket), now: time.Now}
+}
+
+// reserve takes the tokens of the bytes inserted into the channel, returns how long the insert waits for them.
+func (s *insertSmoother) reserve(channel string, bytes int) time.Duration {
+	rate := Params.ProxyCfg.InsertSmoothingChannelRate.GetAsFloat() * 1024 * 1024
+	if rate <= 0 {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	bucket, ok := s.buckets[channel]
+	if !ok {
+		bucket = &channelBucket{tokens: rate, last: now, factor: 1}
+		s.buckets[channel] = bucket
+	}
+	rate *= bucket.factor
+	bucket.tokens += now.Sub(bucket.last).Seconds() * rate
+	if bucket.tokens > rate {
+		bucket.tokens = rate
+	}
+	bucket.last = now
+	bucket.tokens -= float64(bytes)
+	if bucket.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-bucket.tokens / rate * float64(time.Second))
+}
+
+// adjust halves the rates of the channels the data nodes lag behind by more than the max lag, and doubles the rates
+// back once the lags are below half of the max lag.
+func (s *insertSmoother) adjust(lags map[string]time.Duration) {
+	maxLag := Params.ProxyCfg.InsertSmoothingMaxLag.GetAsDuration(time.Second)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for channel, bucket := range s.buckets {
+		lag := lags[channel]
+		factor := bucket.factor
+		switch {
+		case lag > maxLag:
+			factor /= 2
+			if factor < insertSmoothingMinFactor {
+				factor = insertSmoothingMinFactor
+			}
+		case lag < maxLag/2:
+			factor *= 2
+			if factor > 1 {
+				factor = 1
+			}
+		}
+		if factor != bucket.factor {
+			log.Info("adjust the insert rate of the dml channel by the lag", zap.String("channel", channel),
+				zap.Duration("lag", lag), zap.Float64("factor", factor))
+			bucket.factor = factor
+		}
+		metrics.ProxyInsertSmoothingRateFactor.WithLabelValues(paramtable.GetStringNodeID(), channel).Set(factor)
+	}
+}
+
+// refund returns the tokens of the bytes not inserted into the channel.
+func (s *insertSmoother) refund(channel string, bytes int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if bucket, ok := s.buckets[channel]; ok {
+		bucket.tokens += float64(bytes)
+	}
+}
+
+// throttle waits for the tokens of the bytes inserted into the channels, spread over the channels evenly, the
+// channels are refilled in parallel so the insert waits for the slowest one. The insert waiting longer than the max
+// wait is rejected without taking the tokens.
+func (s *insertSmoother) throttle(ctx context.Context, channels []string, bytes int) error {
+	if Params.ProxyCfg.InsertSmoothingChannelRate.GetAsFloat() <= 0 || len(channels) == 0 {
+		return nil
+	}
+	size := bytes / len(channels)
+	var delay time.Duration
+	for _, channel := range channels {
+		if d := s.reserve(channel, size); d > delay {
+			delay = d
+		}
+	}
+	if delay <= 0 {
+		return nil
+	}
+	if maxWait := Params.ProxyCfg.InsertSmoothingMaxWait.GetAsDuration(time.Second); delay > maxWait {
+		for _, channel := range channels {
+			s.refund(channel, size)
+		}
+		return merr.WrapErrServiceRateLimit(Params.ProxyCfg.InsertSmoothingChannelRate.GetAsFloat(),
+			fmt.Sprintf("the insert waits %v for the smoothed rate of the dml channels, over the max wait %v", delay, maxWait))
+	}
+	metrics.ProxyInsertSmoothingDelay.WithLabelValues(paramtable.GetStringNodeID()).Observe(float64(delay.Milliseconds()))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// throttleInsert throttles the insert of the bytes into the collection before it is enqueued, so the waiting inserts
+// never hold the dml queue. The insert of the unknown collection is left to the task to report.
+func (node *Proxy) throttleInsert(ctx context.Context, dbName string, collectionName string, bytes int) error {
+	if Params.ProxyCfg.InsertSmoothingChannelRate.GetAsFloat() <= 0 {
+		return nil
+	}
+	collectionID, err := globalMetaCache.GetCollectionID(ctx, dbName, collectionName)
+	if err != nil {
+		return nil
+	}
+	channels, err := node.chMgr.getVChannels(collectionID)
+	if err != nil {
+		return nil
+	}
+	return globalInsertSmoother.throttle(ctx, channels, bytes)
+}
+
+// getDataNodeChannelLags returns the lag of the slowest channel of each data node behind now.
+func getDataNodeChannelLags(ctx context.Context, dc types.DataCoordClient) (map[string]time.Duration, error) {
+	req, err := metricsinfo.ConstructRequestByMetricType(metricsinfo.SystemInfoMetrics)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := dc.GetMetrics(ctx, req)
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	topology := metricsinfo.DataCoordTopology{}
+	if err := metricsinfo.UnmarshalTopology(resp.GetResponse(), &topology); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	lags := make(map[string]time.Duration)
+	for _, node := range topology.Cluster.ConnectedDataNodes {
+		if node.HasError || node.QuotaMetrics == nil {
+			continue
+		}
+		fgm := node.QuotaMetrics.Fgm
+		if fgm.MinFlowGraphChannel == "" || fgm.MinFlowGraphTt == 0 {
+			continue
+		}
+		lags[fgm.MinFlowGraphChannel] = now.Sub(tsoutil.PhysicalTime(fgm.MinFlowGraphTt))
+	}
+	return lags, nil
+}
+
+func (node *Proxy) insertSmoothingLoop() {
+	defer node.wg.Done()
+	ticker := time.NewTicker(insertSmoothingAdjustInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-node.ctx.Done():
+			return
+		case <-ticker.C:
+			if Params.ProxyCfg.InsertSmoothingChannelRate.GetAsFloat() <= 0 {
+				continue
+			}
+			lags, err := getDataNodeChannelLags(node.ctx, node.dataCoord)
+			if err != nil {
+				log.Warn("failed to get the channel lags of the data nodes", zap.Error(err))
+				continue
+			}
+			globalInsertSmoother.adjust(lags)
+		}
+	}
+}
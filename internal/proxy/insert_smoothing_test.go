@@ -0,0 +1,109 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+func TestInsertSmoother(t *testing.T) {
+	paramtable.Init()
+	s := newInsertSmoother()
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	// disabled
+	assert.Zero(t, s.reserve("ch1", 1<<30))
+
+	paramtable.Get().Save(Params.ProxyCfg.InsertSmoothingChannelRate.Key, "1")
+	defer paramtable.Get().Reset(Params.ProxyCfg.InsertSmoothingChannelRate.Key)
+	// the burst of one second of the rate is allowed
+	assert.Zero(t, s.reserve("ch1", 1<<20))
+	assert.Equal(t, time.Second/2, s.reserve("ch1", 1<<19))
+	assert.Zero(t, s.reserve("ch2", 1<<19))
+
+	now = now.Add(2 * time.Second)
+	assert.Zero(t, s.reserve("ch1", 1<<20))
+
+	// the rate of the lagging channel is halved down to the min factor, and restored once the lag drops
+	for i := 0; i < 5; i++ {
+		s.adjust(map[string]time.Duration{"ch1": time.Minute})
+	}
+	assert.Equal(t, insertSmoothingMinFactor, s.buckets["ch1"].factor)
+	assert.Equal(t, 1.0, s.buckets["ch2"].factor)
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, 4*time.Second, s.reserve("ch1", 1<<19+1<<17))
+	s.adjust(map[string]time.Duration{"ch1": 4 * time.Second})
+	assert.Equal(t, 2*insertSmoothingMinFactor, s.buckets["ch1"].factor)
+	s.adjust(map[string]time.Duration{"ch1": 7 * time.Second})
+	assert.Equal(t, 2*insertSmoothingMinFactor, s.buckets["ch1"].factor)
+}
+
+func TestInsertSmootherThrottle(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(Params.ProxyCfg.InsertSmoothingChannelRate.Key, "1")
+	defer paramtable.Get().Reset(Params.ProxyCfg.InsertSmoothingChannelRate.Key)
+	s := newInsertSmoother()
+	channels := []string{"ch1", "ch2"}
+	assert.NoError(t, s.throttle(context.Background(), channels, 1<<20))
+
+	// the insert waiting over the max wait is rejected without taking the tokens
+	tokens := s.buckets["ch1"].tokens
+	assert.ErrorIs(t, s.throttle(context.Background(), channels, 1<<24), merr.ErrServiceRateLimit)
+	assert.InDelta(t, tokens, s.buckets["ch1"].tokens, 1<<10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.throttle(ctx, channels, 1<<22), context.DeadlineExceeded)
+}
+
+func TestGetDataNodeChannelLags(t *testing.T) {
+	topology, err := metricsinfo.MarshalTopology(metricsinfo.DataCoordTopology{
+		Cluster: metricsinfo.DataClusterTopology{
+			ConnectedDataNodes: []metricsinfo.DataNodeInfos{
+				{QuotaMetrics: &metricsinfo.DataNodeQuotaMetrics{Fgm: metricsinfo.FlowGraphMetric{
+					MinFlowGraphChannel: "ch1",
+					MinFlowGraphTt:      tsoutil.ComposeTSByTime(time.Now().Add(-time.Minute), 0),
+				}}},
+				{BaseComponentInfos: metricsinfo.BaseComponentInfos{HasError: true}},
+				{},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	dc := mocks.NewMockDataCoordClient(t)
+	dc.EXPECT().GetMetrics(mock.Anything, mock.Anything).Return(&milvuspb.GetMetricsResponse{
+		Status:   merr.Success(),
+		Response: topology,
+	}, nil)
+	lags, err := getDataNodeChannelLags(context.Background(), dc)
+	assert.NoError(t, err)
+	assert.Len(t, lags, 1)
+	assert.GreaterOrEqual(t, lags["ch1"], time.Minute)
+}
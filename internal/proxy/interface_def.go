@@ -18,6 +18,7 @@ package proxy
 
 import (
 	"context"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -28,6 +29,9 @@ import (
 // include: channelsTimeTickerImpl, baseTaskQueue, taskScheduler
 type tsoAllocator interface {
 	AllocOne(ctx context.Context) (Timestamp, error)
+	// AllocCached returns the latest allocated timestamp if it's allocated within maxStaleness,
+	// otherwise allocates a new one.
+	AllocCached(ctx context.Context, maxStaleness time.Duration) (Timestamp, error)
 }
 
 // use timestampAllocatorInterface to keep other components testable
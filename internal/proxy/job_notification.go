@@ -0,0 +1,425 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper"
+	"github.com/milvus-io/milvus/pkg/util/contextutil"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/retry"
+)
+
+const (
+	jobCallbackPrefix = "proxy/job_notification/callback"
+
+	// jobSignatureHeader is the hex encoded hmac-sha256 of the body signed by the secret of the callback.
+	jobSignatureHeader   = "X-Milvus-Signature"
+	jobSignatureProperty = "signature"
+
+	jobNotificationWebhookTimeout = 10 * time.Second
+)
+
+// JobCallback is registered by the clients to be notified of the completion of the jobs initiated through the proxy,
+// the notifications are posted to the URL, or produced to the kafka topic, or both.
+type JobCallback struct {
+	Name  string `json:"name"`
+	URL   string `json:"url,omitempty"`
+	Topic string `json:"topic,omitempty"`
+	// Address is the bootstrap servers of the kafka of the topic, the kafka of milvus is used if empty.
+	Address string `json:"address,omitempty"`
+	// Secret signs the notifications by hmac-sha256 if not empty.
+	Secret string `json:"secret,omitempty"`
+	// JobTypes filters the jobs notified, all the jobs if empty.
+	JobTypes []JobType `json:"job_types,omitempty"`
+	// DbName and CollectionName filter the jobs notified by the collection if not empty.
+	DbName         string `json:"db_name,omitempty"`
+	CollectionName string `json:"collection_name,omitempty"`
+	// Owner is the user registered the callback, only the owner and the admin are allowed to manage it.
+	Owner string `json:"owner,omitempty"`
+}
+
+func (c *JobCallback) validate() error {
+	if c.Name == "" {
+		return merr.WrapErrParameterMissing("name")
+	}
+	if c.URL == "" && c.Topic == "" {
+		return merr.WrapErrParameterInvalidMsg("either url or topic of the job callback is required")
+	}
+	for _, jobType := range c.JobTypes {
+		if !lo.Contains(jobTypes, jobType) {
+			return merr.WrapErrParameterInvalidMsg("unknown job type %s", jobType)
+		}
+	}
+	allowed := Params.ProxyCfg.JobNotificationAllowedAddresses.GetAsStrings()
+	if c.URL != "" {
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return merr.WrapErrParameterInvalidMsg("invalid url %s of the job callback", c.URL)
+		}
+		if !funcutil.IsAddressAllowed(u.Host, allowed) {
+			return merr.WrapErrParameterInvalidMsg("webhook address %s is not allowed", u.Host)
+		}
+	}
+	if c.Topic != "" {
+		if c.Address == "" && strings.HasPrefix(c.Topic, Params.CommonCfg.ClusterPrefix.GetValue()) {
+			return merr.WrapErrParameterInvalidMsg("topic %s is reserved by milvus", c.Topic)
+		}
+		for _, server := range strings.Split(c.Address, ",") {
+			if server = strings.TrimSpace(server); server != "" && !funcutil.IsAddressAllowed(server, allowed) {
+				return merr.WrapErrParameterInvalidMsg("kafka address %s is not allowed", server)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *JobCallback) match(info *JobInfo) bool {
+	return (len(c.JobTypes) == 0 || lo.Contains(c.JobTypes, info.JobType)) &&
+		(c.CollectionName == "" || (c.CollectionName == info.CollectionName && jobDbName(c.DbName) == info.DbName))
+}
+
+// sign returns the hex encoded hmac-sha256 of the payload, or empty if no secret.
+func (c *JobCallback) sign(payload []byte) string {
+	if c.Secret == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(c.Secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// jobNotifier polls the jobs of the registry matched by the callbacks until done, and notifies the callbacks of the
+// job info, a notification is retried with backoff until delivered or the attempts exhausted. The callbacks are
+// persisted in the meta kv and reloaded on every check, so a callback registered through any proxy applies to all.
+type jobNotifier struct {
+	mu        sync.Mutex
+	kv        kv.BaseKV
+	registry  *jobRegistry
+	callbacks map[string]*JobCallback
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+
+	newClient func(ctx context.Context, address string) (mqwrapper.Client, error)
+	httpCli   *http.Client
+}
+
+var globalJobNotifier = &jobNotifier{callbacks: make(map[string]*JobCallback)}
+
+func (n *jobNotifier) enabled() bool {
+	return Params.ProxyCfg.JobNotificationEnabled.GetAsBool()
+}
+
+// init binds the notifier to the meta kv, the callbacks are kept in memory only if the kv is nil.
+func (n *jobNotifier) init(metaKV kv.BaseKV, registry *jobRegistry) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.kv = metaKV
+	n.registry = registry
+	n.newClient = newIngestionKafkaClient
+	n.httpCli = newWebhookClient()
+}
+
+// newWebhookClient returns the http client posting to the webhooks, which doesn't follow the redirects that may
+// lead to the addresses not allowed, the redirect responses are failures.
+func newWebhookClient() *http.Client {
+	return &http.Client{
+		Timeout: jobNotificationWebhookTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func (n *jobNotifier) start(ctx context.Context) {
+	if !n.enabled() {
+		return
+	}
+	ctx, n.cancel = context.WithCancel(ctx)
+	n.wg.Add(1)
+	go n.loop(ctx)
+}
+
+func (n *jobNotifier) close() {
+	if n.cancel != nil {
+		n.cancel()
+	}
+	n.wg.Wait()
+}
+
+// register registers the callback as the user in ctx. The callbacks notified of the jobs of all the collections are
+// allowed to the admin only, the others require the privilege to describe the collection.
+func (n *jobNotifier) register(ctx context.Context, callback *JobCallback) error {
+	if !n.enabled() {
+		return merr.WrapErrServiceUnavailable("job notification is disabled on this proxy")
+	}
+	if err := callback.validate(); err != nil {
+		return err
+	}
+	if callback.CollectionName == "" {
+		if err := checkMgrAdmin(ctx); err != nil {
+			return err
+		}
+	} else if err := checkMgrPrivilege(ctx, &milvuspb.DescribeCollectionRequest{DbName: callback.DbName, CollectionName: callback.CollectionName}); err != nil {
+		return err
+	}
+	callback.Owner = ""
+	if Params.CommonCfg.AuthorizationEnabled.GetAsBool() {
+		owner, err := contextutil.GetCurUserFromContext(ctx)
+		if err != nil {
+			return err
+		}
+		callback.Owner = owner
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if existing, ok := n.callbacks[callback.Name]; ok && !n.accessible(ctx, existing) {
+		return status.Error(codes.PermissionDenied, fmt.Sprintf("permission deny to replace job callback %s", callback.Name))
+	}
+	if n.kv != nil {
+		value, err := json.Marshal(callback)
+		if err != nil {
+			return err
+		}
+		if err := n.kv.Save(path.Join(jobCallbackPrefix, callback.Name), string(value)); err != nil {
+			return err
+		}
+	}
+	n.callbacks[callback.Name] = callback
+	return nil
+}
+
+func (n *jobNotifier) unregister(ctx context.Context, name string) error {
+	if !n.enabled() {
+		return merr.WrapErrServiceUnavailable("job notification is disabled on this proxy")
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	callback, ok := n.callbacks[name]
+	if !ok {
+		return merr.WrapErrParameterInvalidMsg("job callback %s not found", name)
+	}
+	if !n.accessible(ctx, callback) {
+		return status.Error(codes.PermissionDenied, fmt.Sprintf("permission deny to unregister job callback %s", name))
+	}
+	if n.kv != nil {
+		if err := n.kv.Remove(path.Join(jobCallbackPrefix, name)); err != nil {
+			return err
+		}
+	}
+	delete(n.callbacks, name)
+	return nil
+}
+
+func (n *jobNotifier) accessible(ctx context.Context, callback *JobCallback) bool {
+	if checkMgrAdmin(ctx) == nil {
+		return true
+	}
+	user, err := contextutil.GetCurUserFromContext(ctx)
+	return err == nil && user == callback.Owner
+}
+
+// list returns the callbacks accessible to the user in ctx without the secrets.
+func (n *jobNotifier) list(ctx context.Context) []*JobCallback {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	ret := make([]*JobCallback, 0, len(n.callbacks))
+	for _, callback := range n.callbacks {
+		if !n.accessible(ctx, callback) {
+			continue
+		}
+		c := *callback
+		c.Secret = ""
+		ret = append(ret, &c)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// reload refreshes the callbacks by the meta kv.
+func (n *jobNotifier) reload() error {
+	if n.kv == nil {
+		return nil
+	}
+	_, values, err := n.kv.LoadWithPrefix(jobCallbackPrefix)
+	if err != nil {
+		return err
+	}
+	callbacks := make(map[string]*JobCallback, len(values))
+	for _, value := range values {
+		callback := &JobCallback{}
+		if err := json.Unmarshal([]byte(value), callback); err != nil {
+			log.Warn("skip the invalid job callback", zap.Error(err))
+			continue
+		}
+		callbacks[callback.Name] = callback
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.callbacks = callbacks
+	return nil
+}
+
+func (n *jobNotifier) loop(ctx context.Context) {
+	defer n.wg.Done()
+	if err := n.reload(); err != nil {
+		log.Warn("failed to load the job callbacks", zap.Error(err))
+	}
+	ticker := time.NewTicker(Params.ProxyCfg.JobNotificationCheckInterval.GetAsDuration(time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := n.reload(); err != nil {
+				log.RatedWarn(60, "failed to reload the job callbacks", zap.Error(err))
+			}
+			n.checkJobs(ctx)
+		}
+	}
+}
+
+// checkJobs notifies the callbacks of the jobs done, the jobs not matched by any callback are not polled.
+func (n *jobNotifier) checkJobs(ctx context.Context) {
+	timeout := Params.ProxyCfg.JobNotificationTimeout.GetAsDuration(time.Second)
+	for _, job := range n.registry.pending() {
+		if len(n.matched(job.snapshot())) == 0 {
+			continue
+		}
+		if err := n.registry.refresh(ctx, job); err != nil {
+			log.RatedWarn(60, "failed to check the job state", zap.String("jobID", job.snapshot().JobID), zap.Error(err))
+		}
+		if job.timeout(timeout) {
+			n.registry.save(job)
+		}
+		info := job.snapshot()
+		if !info.State.done() {
+			continue
+		}
+		job.mu.Lock()
+		job.notified = true
+		job.mu.Unlock()
+		n.notify(ctx, info)
+	}
+}
+
+func (n *jobNotifier) matched(info *JobInfo) []*JobCallback {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return lo.Filter(lo.Values(n.callbacks), func(c *JobCallback, _ int) bool { return c.match(info) })
+}
+
+func (n *jobNotifier) notify(ctx context.Context, info *JobInfo) {
+	payload, err := json.Marshal(info)
+	if err != nil {
+		return
+	}
+	for _, callback := range n.matched(info) {
+		callback := callback
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			err := retry.Do(ctx, func() error {
+				return n.deliver(ctx, callback, payload)
+			}, retry.Attempts(Params.ProxyCfg.JobNotificationMaxRetries.GetAsUint()),
+				retry.Sleep(Params.ProxyCfg.JobNotificationRetryInterval.GetAsDuration(time.Millisecond)))
+			if err != nil {
+				log.Warn("failed to notify the job callback", zap.String("callback", callback.Name),
+					zap.String("jobID", info.JobID), zap.Error(err))
+			}
+		}()
+	}
+}
+
+func (n *jobNotifier) deliver(ctx context.Context, callback *JobCallback, payload []byte) error {
+	signature := callback.sign(payload)
+	if callback.Topic != "" {
+		client, err := n.newClient(ctx, callback.Address)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		producer, err := client.CreateProducer(mqwrapper.ProducerOptions{Topic: callback.Topic})
+		if err != nil {
+			return err
+		}
+		defer producer.Close()
+		msg := &mqwrapper.ProducerMessage{Payload: payload, Properties: map[string]string{}}
+		if signature != "" {
+			msg.Properties[jobSignatureProperty] = signature
+		}
+		if _, err := producer.Send(ctx, msg); err != nil {
+			return err
+		}
+	}
+	if callback.URL != "" {
+		header := http.Header{}
+		if signature != "" {
+			header.Set(jobSignatureHeader, signature)
+		}
+		if err := postWebhook(ctx, n.httpCli, callback.URL, payload, header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postWebhook posts the json payload to the url, the responses other than 2xx are failures.
+func postWebhook(ctx context.Context, cli *http.Client, url string, payload []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for key := range header {
+		req.Header.Set(key, header.Get(key))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := cli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Newf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
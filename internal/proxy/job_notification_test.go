@@ -0,0 +1,233 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	memkv "github.com/milvus-io/milvus/internal/kv/mem"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestJobCallback(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(Params.ProxyCfg.JobNotificationAllowedAddresses.Key, "localhost,*.example.com,kafka:9092")
+	defer paramtable.Get().Reset(Params.ProxyCfg.JobNotificationAllowedAddresses.Key)
+	assert.NoError(t, (&JobCallback{Name: "c", URL: "http://localhost"}).validate())
+	assert.NoError(t, (&JobCallback{Name: "c", URL: "https://hook.example.com:8443/jobs"}).validate())
+	assert.NoError(t, (&JobCallback{Name: "c", Topic: "jobs"}).validate())
+	assert.NoError(t, (&JobCallback{Name: "c", Topic: "jobs", Address: "kafka:9092"}).validate())
+	assert.Error(t, (&JobCallback{Name: "c", URL: "http://169.254.169.254/latest"}).validate())
+	assert.Error(t, (&JobCallback{Name: "c", URL: "file:///etc/passwd"}).validate())
+	assert.Error(t, (&JobCallback{Name: "c", Topic: "jobs", Address: "kafka:9092,evil:9092"}).validate())
+	assert.Error(t, (&JobCallback{Name: "c", Topic: Params.CommonCfg.ClusterPrefix.GetValue() + "-rootcoord-dml_0"}).validate())
+	assert.Error(t, (&JobCallback{URL: "http://localhost"}).validate())
+	assert.Error(t, (&JobCallback{Name: "c"}).validate())
+	assert.Error(t, (&JobCallback{Name: "c", Topic: "t", JobTypes: []JobType{"unknown"}}).validate())
+
+	callback := &JobCallback{Name: "c", JobTypes: []JobType{JobTypeIndex}, CollectionName: "coll"}
+	assert.True(t, callback.match(&JobInfo{JobType: JobTypeIndex, DbName: "default", CollectionName: "coll"}))
+	assert.False(t, callback.match(&JobInfo{JobType: JobTypeLoad, DbName: "default", CollectionName: "coll"}))
+	assert.False(t, callback.match(&JobInfo{JobType: JobTypeIndex, DbName: "db", CollectionName: "coll"}))
+	assert.False(t, callback.match(&JobInfo{JobType: JobTypeIndex, DbName: "default", CollectionName: "other"}))
+	assert.True(t, (&JobCallback{}).match(&JobInfo{JobType: JobTypeFlush, CollectionName: "other"}))
+
+	assert.Empty(t, callback.sign([]byte("payload")))
+	callback.Secret = "secret"
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("payload"))
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), callback.sign([]byte("payload")))
+}
+
+func TestPostWebhook_NoRedirect(t *testing.T) {
+	redirected := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	err := postWebhook(context.Background(), newWebhookClient(), server.URL, []byte("{}"), http.Header{})
+	assert.ErrorContains(t, err, "status 307")
+	assert.False(t, redirected)
+}
+
+func TestJobNotifier(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(Params.ProxyCfg.JobNotificationEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.JobNotificationEnabled.Key)
+	paramtable.Get().Save(Params.ProxyCfg.JobNotificationRetryInterval.Key, "10")
+	defer paramtable.Get().Reset(Params.ProxyCfg.JobNotificationRetryInterval.Key)
+	paramtable.Get().Save(Params.ProxyCfg.JobNotificationAllowedAddresses.Key, "localhost,127.0.0.1")
+	defer paramtable.Get().Reset(Params.ProxyCfg.JobNotificationAllowedAddresses.Key)
+	ctx := context.Background()
+
+	t.Run("register", func(t *testing.T) {
+		n := &jobNotifier{callbacks: make(map[string]*JobCallback)}
+		n.init(memkv.NewMemoryKV(), &jobRegistry{jobs: make(map[string]*asyncJob)})
+		assert.NoError(t, n.register(ctx, &JobCallback{Name: "a", URL: "http://localhost", Secret: "secret"}))
+		assert.NoError(t, n.register(ctx, &JobCallback{Name: "b", Topic: "t"}))
+		assert.Error(t, n.register(ctx, &JobCallback{Name: "c"}))
+		callbacks := n.list(ctx)
+		assert.Equal(t, 2, len(callbacks))
+		assert.Equal(t, "a", callbacks[0].Name)
+		assert.Empty(t, callbacks[0].Secret)
+
+		// reloaded by another proxy
+		other := &jobNotifier{callbacks: make(map[string]*JobCallback)}
+		other.init(n.kv, n.registry)
+		assert.NoError(t, other.reload())
+		assert.Equal(t, 2, len(other.list(ctx)))
+
+		assert.NoError(t, n.unregister(ctx, "a"))
+		assert.Error(t, n.unregister(ctx, "a"))
+		assert.NoError(t, other.reload())
+		assert.Equal(t, 1, len(other.list(ctx)))
+	})
+
+	t.Run("notify", func(t *testing.T) {
+		mu := sync.Mutex{}
+		received := make([]*JobInfo, 0)
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts++
+			// fails once to retry
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			bs, _ := io.ReadAll(r.Body)
+			assert.Equal(t, (&JobCallback{Secret: "secret"}).sign(bs), r.Header.Get(jobSignatureHeader))
+			info := &JobInfo{}
+			assert.NoError(t, json.Unmarshal(bs, info))
+			received = append(received, info)
+		}))
+		defer server.Close()
+
+		client := &testIngestionClient{}
+		registry := &jobRegistry{jobs: make(map[string]*asyncJob)}
+		n := &jobNotifier{callbacks: make(map[string]*JobCallback)}
+		n.init(nil, registry)
+		n.newClient = func(ctx context.Context, address string) (mqwrapper.Client, error) {
+			return client, nil
+		}
+		assert.NoError(t, n.register(ctx, &JobCallback{Name: "hook", URL: server.URL, Secret: "secret", JobTypes: []JobType{JobTypeIndex}}))
+		assert.NoError(t, n.register(ctx, &JobCallback{Name: "topic", Topic: "jobs", CollectionName: "coll"}))
+
+		done := false
+		registry.add(newAsyncJob(JobTypeIndex, "index-default-coll-idx", "default", "coll", func(ctx context.Context) (JobState, int64, string, error) {
+			if done {
+				return JobStateCompleted, 100, "", nil
+			}
+			return JobStateRunning, 50, "", nil
+		}))
+		registry.add(newAsyncJob(JobTypeLoad, "load-default-coll", "default", "coll", func(ctx context.Context) (JobState, int64, string, error) {
+			return "", 0, "", errors.New("mock")
+		}))
+		// no callback is interested in, so never polled
+		registry.add(newAsyncJob(JobTypeLoad, "load-default-other", "default", "other", nil))
+
+		n.checkJobs(context.Background())
+		assert.Equal(t, 3, len(registry.pending()))
+		done = true
+		n.checkJobs(context.Background())
+		assert.Equal(t, 2, len(registry.pending()))
+		n.close()
+
+		assert.Equal(t, 1, len(received))
+		assert.Equal(t, JobTypeIndex, received[0].JobType)
+		assert.Equal(t, JobStateCompleted, received[0].State)
+		assert.Equal(t, int64(100), received[0].Progress)
+		produced := client.getProduced()
+		assert.Equal(t, 1, len(produced))
+		assert.Empty(t, produced[0].Properties[jobSignatureProperty])
+		info := &JobInfo{}
+		assert.NoError(t, json.Unmarshal(produced[0].Payload, info))
+		assert.Equal(t, "index-default-coll-idx", info.JobID)
+
+		// timeout
+		paramtable.Get().Save(Params.ProxyCfg.JobNotificationTimeout.Key, "0")
+		defer paramtable.Get().Reset(Params.ProxyCfg.JobNotificationTimeout.Key)
+		n.checkJobs(context.Background())
+		n.close()
+		assert.Equal(t, 1, len(registry.pending()))
+		produced = client.getProduced()
+		assert.Equal(t, 2, len(produced))
+		assert.NoError(t, json.Unmarshal(produced[1].Payload, info))
+		assert.Equal(t, JobTypeLoad, info.JobType)
+		assert.Equal(t, JobStateTimeout, info.State)
+	})
+
+	t.Run("owner", func(t *testing.T) {
+		paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+		defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+		cache := globalMetaCache
+		defer func() { globalMetaCache = cache }()
+		mockCache := NewMockCache(t)
+		mockCache.EXPECT().GetUserRole(mock.Anything).Return(nil).Maybe()
+		globalMetaCache = mockCache
+
+		n := &jobNotifier{callbacks: make(map[string]*JobCallback)}
+		n.init(memkv.NewMemoryKV(), &jobRegistry{jobs: make(map[string]*asyncJob)})
+		assert.NoError(t, n.register(GetContext(ctx, "root:pwd"), &JobCallback{Name: "r", URL: "http://localhost", Owner: "bob"}))
+		assert.Equal(t, "root", n.list(GetContext(ctx, "root:pwd"))[0].Owner)
+		// the callbacks of all the collections are allowed to the admin only
+		assert.Error(t, n.register(GetContext(ctx, "bob:pwd"), &JobCallback{Name: "b", URL: "http://localhost"}))
+		assert.Error(t, n.register(ctx, &JobCallback{Name: "b", URL: "http://localhost"}))
+		assert.NoError(t, n.kv.Save(path.Join(jobCallbackPrefix, "b"), `{"name": "b", "url": "http://localhost", "collection_name": "coll", "owner": "bob"}`))
+		assert.NoError(t, n.reload())
+
+		assert.Equal(t, 2, len(n.list(GetContext(ctx, "root:pwd"))))
+		assert.Equal(t, 1, len(n.list(GetContext(ctx, "bob:pwd"))))
+		assert.Empty(t, n.list(GetContext(ctx, "alice:pwd")))
+		assert.Error(t, n.unregister(GetContext(ctx, "alice:pwd"), "b"))
+		assert.Error(t, n.unregister(GetContext(ctx, "bob:pwd"), "r"))
+		assert.NoError(t, n.unregister(GetContext(ctx, "bob:pwd"), "b"))
+		assert.NoError(t, n.unregister(GetContext(ctx, "root:pwd"), "r"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		paramtable.Get().Save(Params.ProxyCfg.JobNotificationEnabled.Key, "false")
+		defer paramtable.Get().Save(Params.ProxyCfg.JobNotificationEnabled.Key, "true")
+		n := &jobNotifier{callbacks: make(map[string]*JobCallback)}
+		n.init(nil, &jobRegistry{jobs: make(map[string]*asyncJob)})
+		n.start(context.Background())
+		assert.Error(t, n.register(ctx, &JobCallback{Name: "a", URL: "http://localhost"}))
+		assert.Error(t, n.unregister(ctx, "a"))
+		n.close()
+	})
+}
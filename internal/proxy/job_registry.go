@@ -0,0 +1,621 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/contextutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const jobPrefix = "proxy/jobs"
+
+type JobType string
+
+const (
+	JobTypeFlush      JobType = "flush"
+	JobTypeIndex      JobType = "index"
+	JobTypeLoad       JobType = "load"
+	JobTypeImport     JobType = "import"
+	JobTypeCompaction JobType = "compaction"
+)
+
+var jobTypes = []JobType{JobTypeFlush, JobTypeIndex, JobTypeLoad, JobTypeImport, JobTypeCompaction}
+
+type JobState string
+
+const (
+	JobStateRunning   JobState = "running"
+	JobStateCompleted JobState = "completed"
+	JobStateFailed    JobState = "failed"
+	JobStateTimeout   JobState = "timeout"
+)
+
+func (s JobState) done() bool {
+	return s != JobStateRunning
+}
+
+// JobInfo is the uniform view of the async jobs, the job id is prefixed by the job type,
+// e.g. import-<import job id>, compaction-<compaction id>, index-<db>-<collection>-<index name>.
+type JobInfo struct {
+	JobID          string   `json:"job_id"`
+	JobType        JobType  `json:"job_type"`
+	DbName         string   `json:"db_name,omitempty"`
+	CollectionName string   `json:"collection_name,omitempty"`
+	PartitionNames []string `json:"partition_names,omitempty"`
+	State          JobState `json:"state"`
+	// Progress is in percentage.
+	Progress int64  `json:"progress"`
+	Reason   string `json:"reason,omitempty"`
+	// CreateTime and UpdateTime are the unix time in milliseconds.
+	CreateTime int64 `json:"create_time"`
+	UpdateTime int64 `json:"update_time"`
+}
+
+// JobFilter selects the jobs listed, the empty fields match all.
+type JobFilter struct {
+	JobType        JobType
+	DbName         string
+	CollectionName string
+	State          JobState
+}
+
+func (f *JobFilter) match(info *JobInfo) bool {
+	return (f.JobType == "" || f.JobType == info.JobType) &&
+		(f.DbName == "" || f.DbName == info.DbName) &&
+		(f.CollectionName == "" || f.CollectionName == info.CollectionName) &&
+		(f.State == "" || f.State == info.State)
+}
+
+// jobSpec is the arguments of the state rpc of the job, persisted with the job so any proxy is able to poll it.
+type jobSpec struct {
+	SegmentIDs   []int64 `json:"segment_ids,omitempty"`
+	FlushTs      uint64  `json:"flush_ts,omitempty"`
+	FieldName    string  `json:"field_name,omitempty"`
+	IndexName    string  `json:"index_name,omitempty"`
+	ImportJobID  string  `json:"import_job_id,omitempty"`
+	CompactionID int64   `json:"compaction_id,omitempty"`
+}
+
+// jobRecord is the job persisted in the meta kv.
+type jobRecord struct {
+	Info JobInfo `json:"info"`
+	Spec jobSpec `json:"spec"`
+}
+
+// asyncJob polls the state of the job by the divergent state rpc of the job type.
+type asyncJob struct {
+	mu       sync.Mutex
+	info     JobInfo
+	spec     jobSpec
+	notified bool
+	// local is whether the job is initiated through this proxy, only which notifies the callbacks of the job.
+	local bool
+	poll  func(ctx context.Context) (JobState, int64, string, error)
+}
+
+func newAsyncJob(jobType JobType, jobID, dbName, collectionName string, poll func(ctx context.Context) (JobState, int64, string, error)) *asyncJob {
+	now := time.Now().UnixMilli()
+	return &asyncJob{
+		info: JobInfo{
+			JobID:          jobID,
+			JobType:        jobType,
+			DbName:         dbName,
+			CollectionName: collectionName,
+			State:          JobStateRunning,
+			CreateTime:     now,
+			UpdateTime:     now,
+		},
+		local: true,
+		poll:  poll,
+	}
+}
+
+// refresh polls the state if the job is not done yet.
+func (j *asyncJob) refresh(ctx context.Context) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.info.State.done() {
+		return nil
+	}
+	state, progress, reason, err := j.poll(ctx)
+	if err != nil {
+		return err
+	}
+	j.info.State, j.info.Progress, j.info.Reason = state, progress, reason
+	if state == JobStateCompleted {
+		j.info.Progress = 100
+	}
+	j.info.UpdateTime = time.Now().UnixMilli()
+	return nil
+}
+
+// timeout marks the job timeout if it is not done in the duration.
+func (j *asyncJob) timeout(timeout time.Duration) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.info.State.done() || time.Since(time.UnixMilli(j.info.CreateTime)) <= timeout {
+		return false
+	}
+	j.info.State, j.info.Reason = JobStateTimeout, fmt.Sprintf("the job is not done in %s", timeout)
+	j.info.UpdateTime = time.Now().UnixMilli()
+	return true
+}
+
+func (j *asyncJob) snapshot() *JobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	info := j.info
+	return &info
+}
+
+// jobRegistry keeps the async jobs initiated through the proxies, and replaces the divergent state rpcs of
+// the import, flush, load, index building and compaction with a single get and list of the jobs.
+// The jobs are persisted in the meta kv, so the jobs initiated through any proxy are listed by all the proxies,
+// they are kept in memory only if the kv is nil. The state of a job is polled on demand and kept once done,
+// the jobs done are removed after the retention.
+type jobRegistry struct {
+	mu   sync.Mutex
+	kv   kv.BaseKV
+	jobs map[string]*asyncJob
+	// resolve builds the job not registered by the job id, only the import and the compaction are resolvable
+	// since their ids are global.
+	resolve func(jobID string) *asyncJob
+	// build builds the job persisted by another proxy.
+	build func(record *jobRecord) *asyncJob
+}
+
+var globalJobRegistry = &jobRegistry{jobs: make(map[string]*asyncJob)}
+
+func (r *jobRegistry) init(node *Proxy, metaKV kv.BaseKV) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.kv = metaKV
+	r.resolve = node.resolveJob
+	r.build = node.buildJob
+}
+
+// add tracks the job, the job of the same id is replaced, e.g. the same partitions loaded again.
+func (r *jobRegistry) add(job *asyncJob) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[job.info.JobID] = job
+	r.save(job)
+	r.evict()
+}
+
+// save persists the job, the failure is logged only since the job is still tracked by this proxy.
+func (r *jobRegistry) save(job *asyncJob) {
+	if r.kv == nil {
+		return
+	}
+	job.mu.Lock()
+	value, err := json.Marshal(&jobRecord{Info: job.info, Spec: job.spec})
+	job.mu.Unlock()
+	if err == nil {
+		err = r.kv.Save(path.Join(jobPrefix, job.info.JobID), string(value))
+	}
+	if err != nil {
+		log.RatedWarn(60, "failed to persist the job", zap.String("jobID", job.info.JobID), zap.Error(err))
+	}
+}
+
+// load merges the jobs persisted by the other proxies, the jobs removed from the kv are removed unless local.
+func (r *jobRegistry) load() {
+	if r.kv == nil || r.build == nil {
+		return
+	}
+	_, values, err := r.kv.LoadWithPrefix(jobPrefix)
+	if err != nil {
+		log.RatedWarn(60, "failed to load the jobs", zap.Error(err))
+		return
+	}
+	persisted := typeutil.NewSet[string]()
+	for _, value := range values {
+		record := &jobRecord{}
+		if err := json.Unmarshal([]byte(value), record); err != nil {
+			log.Warn("skip the invalid job", zap.Error(err))
+			continue
+		}
+		persisted.Insert(record.Info.JobID)
+		if job, ok := r.jobs[record.Info.JobID]; ok && (job.local || job.snapshot().UpdateTime >= record.Info.UpdateTime) {
+			continue
+		}
+		if job := r.build(record); job != nil {
+			r.jobs[record.Info.JobID] = job
+		}
+	}
+	for jobID, job := range r.jobs {
+		if !job.local && !persisted.Contain(jobID) {
+			delete(r.jobs, jobID)
+		}
+	}
+}
+
+// evict removes the jobs done for longer than the retention, and the oldest jobs over the limit.
+func (r *jobRegistry) evict() {
+	evicted := make([]string, 0)
+	retention := Params.ProxyCfg.JobRetention.GetAsDuration(time.Second)
+	for jobID, job := range r.jobs {
+		info := job.snapshot()
+		if info.State.done() && time.Since(time.UnixMilli(info.UpdateTime)) > retention {
+			delete(r.jobs, jobID)
+			evicted = append(evicted, jobID)
+		}
+	}
+	limit := Params.ProxyCfg.JobMaxTracked.GetAsInt()
+	if len(r.jobs) > limit {
+		infos := lo.MapToSlice(r.jobs, func(_ string, job *asyncJob) *JobInfo { return job.snapshot() })
+		// the jobs done go first
+		sort.Slice(infos, func(i, j int) bool {
+			if infos[i].State.done() != infos[j].State.done() {
+				return infos[i].State.done()
+			}
+			return infos[i].CreateTime < infos[j].CreateTime
+		})
+		for _, info := range infos[:len(infos)-limit] {
+			delete(r.jobs, info.JobID)
+			evicted = append(evicted, info.JobID)
+		}
+	}
+	if r.kv == nil || len(evicted) == 0 {
+		return
+	}
+	keys := lo.Map(evicted, func(jobID string, _ int) string { return path.Join(jobPrefix, jobID) })
+	if err := r.kv.MultiRemove(keys); err != nil {
+		log.RatedWarn(60, "failed to remove the jobs evicted", zap.Error(err))
+	}
+}
+
+func (r *jobRegistry) getJob(jobID string) *asyncJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[jobID]
+	if !ok {
+		r.load()
+		job, ok = r.jobs[jobID]
+	}
+	if !ok && r.resolve != nil {
+		if job = r.resolve(jobID); job != nil {
+			job.local = false
+			r.jobs[jobID] = job
+			r.save(job)
+		}
+	}
+	return job
+}
+
+// refresh polls the state of the job, and persists it if changed.
+func (r *jobRegistry) refresh(ctx context.Context, job *asyncJob) error {
+	prev := job.snapshot()
+	if err := job.refresh(ctx); err != nil {
+		return err
+	}
+	if info := job.snapshot(); info.State != prev.State || info.Progress != prev.Progress {
+		r.save(job)
+	}
+	return nil
+}
+
+func (r *jobRegistry) get(ctx context.Context, jobID string) (*JobInfo, error) {
+	job := r.getJob(jobID)
+	if job == nil || !jobAccessible(ctx, job.snapshot()) {
+		return nil, merr.WrapErrParameterInvalidMsg("job %s not found", jobID)
+	}
+	if err := r.refresh(ctx, job); err != nil {
+		return nil, err
+	}
+	return job.snapshot(), nil
+}
+
+// list returns the jobs matched and accessible to the user in ctx ordered by the create time, the jobs failed to
+// refresh are listed by the last state.
+func (r *jobRegistry) list(ctx context.Context, filter *JobFilter) []*JobInfo {
+	r.mu.Lock()
+	r.load()
+	r.evict()
+	jobs := lo.Values(r.jobs)
+	r.mu.Unlock()
+
+	// the state is matched after refreshed
+	stateless := *filter
+	stateless.State = ""
+	infos := make([]*JobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		if info := job.snapshot(); !stateless.match(info) || !jobAccessible(ctx, info) {
+			continue
+		}
+		if err := r.refresh(ctx, job); err != nil {
+			log.Ctx(ctx).Warn("failed to refresh the job state", zap.String("jobID", job.snapshot().JobID), zap.Error(err))
+		}
+		if info := job.snapshot(); filter.match(info) {
+			infos = append(infos, info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].CreateTime < infos[j].CreateTime })
+	return infos
+}
+
+// pending returns the jobs initiated through this proxy and not notified yet.
+func (r *jobRegistry) pending() []*asyncJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return lo.Filter(lo.Values(r.jobs), func(job *asyncJob, _ int) bool {
+		job.mu.Lock()
+		defer job.mu.Unlock()
+		return job.local && !job.notified
+	})
+}
+
+// jobAccessible returns whether the user in ctx is allowed to see the job, which requires the privilege to
+// describe the collection of the job, or the admin if the collection is unknown.
+func jobAccessible(ctx context.Context, info *JobInfo) bool {
+	if info.CollectionName == "" {
+		return checkMgrAdmin(ctx) == nil
+	}
+	ctx = contextutil.AppendToIncomingContext(ctx, strings.ToLower(util.HeaderDBName), info.DbName)
+	return checkMgrPrivilege(ctx, &milvuspb.DescribeCollectionRequest{DbName: info.DbName, CollectionName: info.CollectionName}) == nil
+}
+
+func jobDbName(dbName string) string {
+	if dbName == "" {
+		return "default"
+	}
+	return dbName
+}
+
+// buildJob builds the job persisted by the state rpc of the job type.
+func (node *Proxy) buildJob(record *jobRecord) *asyncJob {
+	info, spec := record.Info, record.Spec
+	var poll func(ctx context.Context) (JobState, int64, string, error)
+	switch info.JobType {
+	case JobTypeFlush:
+		poll = node.pollFlushJob(info.DbName, info.CollectionName, spec.SegmentIDs, spec.FlushTs)
+	case JobTypeIndex:
+		poll = node.pollIndexJob(info.DbName, info.CollectionName, spec.FieldName, spec.IndexName)
+	case JobTypeLoad:
+		poll = node.pollLoadJob(info.DbName, info.CollectionName, info.PartitionNames)
+	case JobTypeImport:
+		poll = node.pollImportJob(spec.ImportJobID)
+	case JobTypeCompaction:
+		poll = node.pollCompactionJob(spec.CompactionID)
+	default:
+		return nil
+	}
+	return &asyncJob{info: info, spec: spec, poll: poll}
+}
+
+// addFlushJobs tracks the flush of each collection flushed.
+func (node *Proxy) addFlushJobs(resp *milvuspb.FlushResponse) {
+	dbName := jobDbName(resp.GetDbName())
+	for collectionName, flushTs := range resp.GetCollFlushTs() {
+		segmentIDs := resp.GetCollSegIDs()[collectionName].GetData()
+		jobID := fmt.Sprintf("%s-%s-%s-%d", JobTypeFlush, dbName, collectionName, flushTs)
+		job := newAsyncJob(JobTypeFlush, jobID, dbName, collectionName, node.pollFlushJob(dbName, collectionName, segmentIDs, flushTs))
+		job.spec = jobSpec{SegmentIDs: segmentIDs, FlushTs: flushTs}
+		globalJobRegistry.add(job)
+	}
+}
+
+func (node *Proxy) pollFlushJob(dbName, collectionName string, segmentIDs []int64, flushTs uint64) func(ctx context.Context) (JobState, int64, string, error) {
+	return func(ctx context.Context) (JobState, int64, string, error) {
+		state, err := node.GetFlushState(ctx, &milvuspb.GetFlushStateRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			SegmentIDs:     segmentIDs,
+			FlushTs:        flushTs,
+		})
+		if err := merr.CheckRPCCall(state, err); err != nil {
+			return "", 0, "", err
+		}
+		if state.GetFlushed() {
+			return JobStateCompleted, 100, "", nil
+		}
+		return JobStateRunning, 0, "", nil
+	}
+}
+
+func (node *Proxy) addIndexJob(req *milvuspb.CreateIndexRequest) {
+	dbName := jobDbName(req.GetDbName())
+	jobID := fmt.Sprintf("%s-%s-%s-%s", JobTypeIndex, dbName, req.GetCollectionName(), req.GetIndexName())
+	job := newAsyncJob(JobTypeIndex, jobID, dbName, req.GetCollectionName(),
+		node.pollIndexJob(dbName, req.GetCollectionName(), req.GetFieldName(), req.GetIndexName()))
+	job.spec = jobSpec{FieldName: req.GetFieldName(), IndexName: req.GetIndexName()}
+	globalJobRegistry.add(job)
+}
+
+func (node *Proxy) pollIndexJob(dbName, collectionName, fieldName, indexName string) func(ctx context.Context) (JobState, int64, string, error) {
+	return func(ctx context.Context) (JobState, int64, string, error) {
+		resp, err := node.DescribeIndex(ctx, &milvuspb.DescribeIndexRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			IndexName:      indexName,
+		})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return "", 0, "", err
+		}
+		desc, ok := lo.Find(resp.GetIndexDescriptions(), func(desc *milvuspb.IndexDescription) bool {
+			return desc.GetFieldName() == fieldName
+		})
+		if !ok {
+			return JobStateFailed, 0, "index dropped", nil
+		}
+		switch desc.GetState() {
+		case commonpb.IndexState_Finished:
+			return JobStateCompleted, 100, "", nil
+		case commonpb.IndexState_Failed:
+			return JobStateFailed, 0, desc.GetIndexStateFailReason(), nil
+		}
+		progress := int64(0)
+		if desc.GetTotalRows() > 0 {
+			progress = desc.GetIndexedRows() * 100 / desc.GetTotalRows()
+		}
+		return JobStateRunning, progress, "", nil
+	}
+}
+
+// addLoadJob tracks the load of the partitions, or the collection if no partition. The job id contains the sorted
+// partitions, so the loads of different partitions are different jobs.
+func (node *Proxy) addLoadJob(dbName, collectionName string, partitionNames []string) {
+	dbName = jobDbName(dbName)
+	partitionNames = lo.Uniq(partitionNames)
+	sort.Strings(partitionNames)
+	jobID := fmt.Sprintf("%s-%s-%s", JobTypeLoad, dbName, collectionName)
+	if len(partitionNames) > 0 {
+		jobID = fmt.Sprintf("%s-%s", jobID, strings.Join(partitionNames, ","))
+	}
+	job := newAsyncJob(JobTypeLoad, jobID, dbName, collectionName, node.pollLoadJob(dbName, collectionName, partitionNames))
+	job.info.PartitionNames = partitionNames
+	globalJobRegistry.add(job)
+}
+
+func (node *Proxy) pollLoadJob(dbName, collectionName string, partitionNames []string) func(ctx context.Context) (JobState, int64, string, error) {
+	return func(ctx context.Context) (JobState, int64, string, error) {
+		resp, err := node.GetLoadingProgress(ctx, &milvuspb.GetLoadingProgressRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			PartitionNames: partitionNames,
+		})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return "", 0, "", err
+		}
+		if resp.GetProgress() >= 100 {
+			return JobStateCompleted, 100, "", nil
+		}
+		return JobStateRunning, resp.GetProgress(), "", nil
+	}
+}
+
+func (node *Proxy) newImportJob(importJobID, dbName, collectionName string) *asyncJob {
+	jobID := fmt.Sprintf("%s-%s", JobTypeImport, importJobID)
+	job := newAsyncJob(JobTypeImport, jobID, dbName, collectionName, node.pollImportJob(importJobID))
+	job.spec = jobSpec{ImportJobID: importJobID}
+	return job
+}
+
+func (node *Proxy) pollImportJob(importJobID string) func(ctx context.Context) (JobState, int64, string, error) {
+	return func(ctx context.Context) (JobState, int64, string, error) {
+		// the state is composite with the auto load if requested
+		resp, err := node.GetImportProgress(ctx, &internalpb.GetImportProgressRequest{JobID: importJobID})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return "", 0, "", err
+		}
+		switch resp.GetState() {
+		case internalpb.ImportJobState_Completed:
+			return JobStateCompleted, 100, "", nil
+		case internalpb.ImportJobState_Failed:
+			return JobStateFailed, resp.GetProgress(), resp.GetReason(), nil
+		}
+		return JobStateRunning, resp.GetProgress(), "", nil
+	}
+}
+
+func (node *Proxy) addImportJob(importJobID, dbName, collectionName string) {
+	globalJobRegistry.add(node.newImportJob(importJobID, jobDbName(dbName), collectionName))
+}
+
+func (node *Proxy) newCompactionJob(compactionID int64, dbName, collectionName string) *asyncJob {
+	jobID := fmt.Sprintf("%s-%d", JobTypeCompaction, compactionID)
+	job := newAsyncJob(JobTypeCompaction, jobID, dbName, collectionName, node.pollCompactionJob(compactionID))
+	job.spec = jobSpec{CompactionID: compactionID}
+	return job
+}
+
+func (node *Proxy) pollCompactionJob(compactionID int64) func(ctx context.Context) (JobState, int64, string, error) {
+	return func(ctx context.Context) (JobState, int64, string, error) {
+		resp, err := node.GetCompactionState(ctx, &milvuspb.GetCompactionStateRequest{CompactionID: compactionID})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return "", 0, "", err
+		}
+		total := resp.GetExecutingPlanNo() + resp.GetCompletedPlanNo() + resp.GetFailedPlanNo() + resp.GetTimeoutPlanNo()
+		if resp.GetState() != commonpb.CompactionState_Completed {
+			progress := int64(0)
+			if total > 0 {
+				progress = (total - resp.GetExecutingPlanNo()) * 100 / total
+			}
+			return JobStateRunning, progress, "", nil
+		}
+		if failed := resp.GetFailedPlanNo() + resp.GetTimeoutPlanNo(); failed > 0 {
+			return JobStateFailed, 100, fmt.Sprintf("%d of %d compaction plans failed", failed, total), nil
+		}
+		return JobStateCompleted, 100, "", nil
+	}
+}
+
+func (node *Proxy) addCompactionJob(ctx context.Context, compactionID, collectionID int64) {
+	dbName, collectionName := "", ""
+	if dbNames, collectionNames, err := globalMetaCache.GetCollectionNamesByID(ctx, []UniqueID{collectionID}); err == nil {
+		dbName, collectionName = dbNames[0], collectionNames[0]
+	}
+	globalJobRegistry.add(node.newCompactionJob(compactionID, dbName, collectionName))
+}
+
+// resolveJob builds the import or compaction job by the global id in the job id.
+func (node *Proxy) resolveJob(jobID string) *asyncJob {
+	jobType, id, ok := strings.Cut(jobID, "-")
+	if !ok {
+		return nil
+	}
+	switch JobType(jobType) {
+	case JobTypeImport:
+		if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+			return nil
+		}
+		return node.newImportJob(id, "", "")
+	case JobTypeCompaction:
+		compactionID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil
+		}
+		return node.newCompactionJob(compactionID, "", "")
+	}
+	return nil
+}
+
+func parseJobType(s string) (JobType, error) {
+	if s == "" || lo.Contains(jobTypes, JobType(s)) {
+		return JobType(s), nil
+	}
+	return "", merr.WrapErrParameterInvalidMsg("unknown job type %s, expected one of %v", s, jobTypes)
+}
+
+func parseJobState(s string) (JobState, error) {
+	states := typeutil.NewSet(JobStateRunning, JobStateCompleted, JobStateFailed, JobStateTimeout)
+	if s == "" || states.Contain(JobState(s)) {
+		return JobState(s), nil
+	}
+	return "", merr.WrapErrParameterInvalidMsg("unknown job state %s", s)
+}
@@ -0,0 +1,205 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	memkv "github.com/milvus-io/milvus/internal/kv/mem"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestJobRegistry(t *testing.T) {
+	paramtable.Init()
+
+	newJob := func(jobType JobType, jobID, collectionName string, states ...JobState) *asyncJob {
+		polled := 0
+		return newAsyncJob(jobType, jobID, "default", collectionName, func(ctx context.Context) (JobState, int64, string, error) {
+			state := states[polled]
+			polled++
+			if state == JobStateFailed {
+				return state, 10, "mock", nil
+			}
+			return state, 50, "", nil
+		})
+	}
+
+	t.Run("list and get", func(t *testing.T) {
+		r := &jobRegistry{jobs: make(map[string]*asyncJob)}
+		r.add(newJob(JobTypeLoad, "load-default-a", "a", JobStateRunning, JobStateCompleted))
+		r.add(newJob(JobTypeIndex, "index-default-b-idx", "b", JobStateFailed))
+
+		infos := r.list(context.Background(), &JobFilter{})
+		assert.Equal(t, 2, len(infos))
+		infos = r.list(context.Background(), &JobFilter{JobType: JobTypeLoad})
+		assert.Equal(t, 1, len(infos))
+		assert.Equal(t, JobStateCompleted, infos[0].State)
+		assert.Equal(t, int64(100), infos[0].Progress)
+
+		infos = r.list(context.Background(), &JobFilter{State: JobStateFailed, CollectionName: "b", DbName: "default"})
+		assert.Equal(t, 1, len(infos))
+		assert.Equal(t, "mock", infos[0].Reason)
+		assert.Empty(t, r.list(context.Background(), &JobFilter{CollectionName: "c"}))
+
+		// the jobs done are not polled again
+		info, err := r.get(context.Background(), "index-default-b-idx")
+		assert.NoError(t, err)
+		assert.Equal(t, JobStateFailed, info.State)
+		_, err = r.get(context.Background(), "index-default-c-idx")
+		assert.Error(t, err)
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		r := &jobRegistry{jobs: make(map[string]*asyncJob)}
+		r.resolve = func(jobID string) *asyncJob {
+			if jobID != "import-1" {
+				return nil
+			}
+			return newJob(JobTypeImport, jobID, "", JobStateRunning, JobStateRunning)
+		}
+		info, err := r.get(context.Background(), "import-1")
+		assert.NoError(t, err)
+		assert.Equal(t, JobTypeImport, info.JobType)
+		assert.Equal(t, int64(50), info.Progress)
+		assert.Equal(t, 1, len(r.list(context.Background(), &JobFilter{JobType: JobTypeImport})))
+		_, err = r.get(context.Background(), "import-2")
+		assert.Error(t, err)
+	})
+
+	t.Run("refresh failed", func(t *testing.T) {
+		r := &jobRegistry{jobs: make(map[string]*asyncJob)}
+		r.add(newAsyncJob(JobTypeFlush, "flush-default-a-1", "default", "a", func(ctx context.Context) (JobState, int64, string, error) {
+			return "", 0, "", errors.New("mock")
+		}))
+		_, err := r.get(context.Background(), "flush-default-a-1")
+		assert.Error(t, err)
+		infos := r.list(context.Background(), &JobFilter{})
+		assert.Equal(t, JobStateRunning, infos[0].State)
+	})
+
+	t.Run("evict", func(t *testing.T) {
+		paramtable.Get().Save(Params.ProxyCfg.JobMaxTracked.Key, "2")
+		defer paramtable.Get().Reset(Params.ProxyCfg.JobMaxTracked.Key)
+		r := &jobRegistry{jobs: make(map[string]*asyncJob)}
+		r.add(newJob(JobTypeLoad, "load-default-a", "a", JobStateCompleted))
+		assert.Equal(t, 1, len(r.list(context.Background(), &JobFilter{State: JobStateCompleted})))
+		time.Sleep(time.Millisecond)
+		r.add(newJob(JobTypeLoad, "load-default-b", "b", JobStateCompleted))
+		time.Sleep(time.Millisecond)
+		r.add(newJob(JobTypeLoad, "load-default-c", "c", JobStateRunning))
+		// the job done is evicted first
+		assert.Equal(t, 2, len(r.jobs))
+		assert.Nil(t, r.jobs["load-default-a"])
+
+		paramtable.Get().Save(Params.ProxyCfg.JobRetention.Key, "0")
+		defer paramtable.Get().Reset(Params.ProxyCfg.JobRetention.Key)
+		r.jobs["load-default-b"].refresh(context.Background())
+		time.Sleep(time.Millisecond)
+		r.list(context.Background(), &JobFilter{})
+		assert.Equal(t, 1, len(r.jobs))
+	})
+
+	t.Run("persisted", func(t *testing.T) {
+		metaKV := memkv.NewMemoryKV()
+		build := func(record *jobRecord) *asyncJob {
+			return &asyncJob{info: record.Info, spec: record.Spec, poll: func(ctx context.Context) (JobState, int64, string, error) {
+				return JobStateCompleted, 100, "", nil
+			}}
+		}
+		r1 := &jobRegistry{jobs: make(map[string]*asyncJob), kv: metaKV, build: build}
+		r2 := &jobRegistry{jobs: make(map[string]*asyncJob), kv: metaKV, build: build}
+		job := newJob(JobTypeCompaction, "compaction-1", "a", JobStateRunning, JobStateRunning)
+		job.spec = jobSpec{CompactionID: 1}
+		r1.add(job)
+		assert.Equal(t, 1, len(r1.list(context.Background(), &JobFilter{State: JobStateRunning})))
+
+		// listed and polled by another proxy, but notified by the proxy initiated it only
+		infos := r2.list(context.Background(), &JobFilter{})
+		assert.Equal(t, 1, len(infos))
+		assert.Equal(t, JobStateCompleted, infos[0].State)
+		assert.Equal(t, int64(1), r2.jobs["compaction-1"].spec.CompactionID)
+		assert.Empty(t, r2.pending())
+		assert.Equal(t, 1, len(r1.pending()))
+		info, err := r2.get(context.Background(), "compaction-1")
+		assert.NoError(t, err)
+		assert.Equal(t, JobStateCompleted, info.State)
+
+		// removed by another proxy
+		paramtable.Get().Save(Params.ProxyCfg.JobRetention.Key, "0")
+		defer paramtable.Get().Reset(Params.ProxyCfg.JobRetention.Key)
+		time.Sleep(time.Millisecond)
+		assert.Empty(t, r2.list(context.Background(), &JobFilter{}))
+		_, values, err := metaKV.LoadWithPrefix(jobPrefix)
+		assert.NoError(t, err)
+		assert.Empty(t, values)
+		r1.load()
+		assert.Equal(t, 1, len(r1.jobs))
+	})
+
+	t.Run("accessible", func(t *testing.T) {
+		paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+		defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+		cache := globalMetaCache
+		defer func() { globalMetaCache = cache }()
+		mockCache := NewMockCache(t)
+		mockCache.EXPECT().GetUserRole(mock.Anything).Return(nil).Maybe()
+		mockCache.EXPECT().GetPrivilegeInfo(mock.Anything).Return(nil).Maybe()
+		globalMetaCache = mockCache
+
+		r := &jobRegistry{jobs: make(map[string]*asyncJob)}
+		r.add(newJob(JobTypeLoad, "load-default-a", "a", JobStateRunning))
+		r.add(newJob(JobTypeImport, "import-1", "", JobStateRunning))
+		ctx := context.Background()
+		assert.Equal(t, 2, len(r.list(GetContext(ctx, "root:pwd"), &JobFilter{})))
+		assert.Empty(t, r.list(GetContext(ctx, "bob:pwd"), &JobFilter{}))
+		_, err := r.get(GetContext(ctx, "bob:pwd"), "load-default-a")
+		assert.Error(t, err)
+	})
+
+	t.Run("load job", func(t *testing.T) {
+		registry := globalJobRegistry
+		defer func() { globalJobRegistry = registry }()
+		globalJobRegistry = &jobRegistry{jobs: make(map[string]*asyncJob)}
+		node := &Proxy{}
+		node.addLoadJob("", "coll", []string{"p2", "p1", "p2"})
+		job := globalJobRegistry.jobs["load-default-coll-p1,p2"]
+		assert.NotNil(t, job)
+		assert.Equal(t, []string{"p1", "p2"}, job.info.PartitionNames)
+		node.addLoadJob("", "coll", []string{"p3"})
+		node.addLoadJob("", "coll", nil)
+		assert.Equal(t, 3, len(globalJobRegistry.jobs))
+	})
+
+	t.Run("parse", func(t *testing.T) {
+		jobType, err := parseJobType("import")
+		assert.NoError(t, err)
+		assert.Equal(t, JobTypeImport, jobType)
+		_, err = parseJobType("unknown")
+		assert.Error(t, err)
+		state, err := parseJobState("")
+		assert.NoError(t, err)
+		assert.Equal(t, JobState(""), state)
+		_, err = parseJobState("unknown")
+		assert.Error(t, err)
+	})
+}
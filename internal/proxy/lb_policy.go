@@ -17,6 +17,8 @@ package proxy
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/samber/lo"
@@ -43,6 +45,7 @@ type ChannelWorkload struct {
 	nq             int64
 	exec           executeFunc
 	retryTimes     uint
+	readPreference *readPreference
 }
 
 type CollectionWorkLoad struct {
@@ -111,7 +114,7 @@ func (lb *LBPolicyImpl) selectNode(ctx context.Context, workload ChannelWorkload
 	}
 
 	availableNodes := lo.Filter(workload.shardLeaders, filterAvailableNodes)
-	targetNode, err := lb.balancer.SelectNode(ctx, availableNodes, workload.nq)
+	targetNode, err := lb.selectPreferredNode(ctx, workload, availableNodes)
 	if err != nil {
 		globalMetaCache.DeprecateShardCache(workload.db, workload.collectionName)
 		nodes, err := getShardLeaders()
@@ -129,7 +132,7 @@ func (lb *LBPolicyImpl) selectNode(ctx context.Context, workload ChannelWorkload
 			return -1, merr.WrapErrChannelNotAvailable("no available shard delegator found")
 		}
 
-		targetNode, err = lb.balancer.SelectNode(ctx, availableNodes, workload.nq)
+		targetNode, err = lb.selectPreferredNode(ctx, workload, availableNodes)
 		if err != nil {
 			log.Warn("failed to select shard",
 				zap.Int64s("availableNodes", availableNodes),
@@ -141,6 +144,21 @@ func (lb *LBPolicyImpl) selectNode(ctx context.Context, workload ChannelWorkload
 	return targetNode, nil
 }
 
+// selectPreferredNode selects the node among the ones matching the read preference if any,
+// and falls back to all the available nodes unless the fallback is disabled.
+func (lb *LBPolicyImpl) selectPreferredNode(ctx context.Context, workload ChannelWorkload, availableNodes []int64) (int64, error) {
+	if pref := workload.readPreference; pref != nil && len(availableNodes) > 0 {
+		preferred := pref.prefer(globalReplicaTopology, availableNodes)
+		switch {
+		case len(preferred) > 0:
+			availableNodes = preferred
+		case pref.strict:
+			return -1, merr.WrapErrChannelNotAvailable(workload.channel, fmt.Sprintf("no shard delegator matches read preference %s", pref))
+		}
+	}
+	return lb.balancer.SelectNode(ctx, availableNodes, workload.nq)
+}
+
 // ExecuteWithRetry will choose a qn to execute the workload, and retry if failed, until reach the max retryTimes.
 func (lb *LBPolicyImpl) ExecuteWithRetry(ctx context.Context, workload ChannelWorkload) error {
 	excludeNodes := typeutil.NewUniqueSet()
@@ -177,6 +195,7 @@ func (lb *LBPolicyImpl) ExecuteWithRetry(ctx context.Context, workload ChannelWo
 			return lastErr
 		}
 
+		start := time.Now()
 		err = workload.exec(ctx, targetNode, client, workload.channel)
 		if err != nil {
 			log.Warn("search/query channel failed",
@@ -189,6 +208,7 @@ func (lb *LBPolicyImpl) ExecuteWithRetry(ctx context.Context, workload ChannelWo
 			return lastErr
 		}
 
+		globalReplicaTopology.recordLatency(targetNode, time.Since(start))
 		lb.balancer.CancelWorkload(targetNode, workload.nq)
 		return nil
 	}, retry.Attempts(workload.retryTimes))
@@ -198,6 +218,10 @@ func (lb *LBPolicyImpl) ExecuteWithRetry(ctx context.Context, workload ChannelWo
 
 // Execute will execute collection workload in parallel
 func (lb *LBPolicyImpl) Execute(ctx context.Context, workload CollectionWorkLoad) error {
+	readPreference, err := getReadPreference(ctx)
+	if err != nil {
+		return err
+	}
 	dml2leaders, err := globalMetaCache.GetShards(ctx, true, workload.db, workload.collectionName, workload.collectionID)
 	if err != nil {
 		log.Ctx(ctx).Warn("failed to get shards", zap.Error(err))
@@ -224,6 +248,7 @@ func (lb *LBPolicyImpl) Execute(ctx context.Context, workload CollectionWorkLoad
 				nq:             workload.nq,
 				exec:           workload.exec,
 				retryTimes:     uint(channelRetryTimes),
+				readPreference: readPreference,
 			})
 		})
 	}
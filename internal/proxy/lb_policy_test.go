@@ -216,6 +216,51 @@ func (s *LBPolicySuite) TestSelectNode() {
 	s.ErrorIs(err, merr.ErrChannelNotAvailable)
 	s.Equal(int64(-1), targetNode)
 
+	// test read preference, the matched nodes are selected, and falls back to all unless strict
+	nodeGroups := globalReplicaTopology.nodeGroups
+	globalReplicaTopology.nodeGroups = map[int64]string{s.nodes[0]: "rg1"}
+	defer func() { globalReplicaTopology.nodeGroups = nodeGroups }()
+	s.lbBalancer.ExpectedCalls = nil
+	s.lbBalancer.EXPECT().SelectNode(mock.Anything, []int64{s.nodes[0]}, mock.Anything).Return(s.nodes[0], nil)
+	targetNode, err = s.lbPolicy.selectNode(ctx, ChannelWorkload{
+		db:             dbName,
+		collectionName: s.collectionName,
+		collectionID:   s.collectionID,
+		channel:        s.channels[0],
+		shardLeaders:   s.nodes,
+		nq:             1,
+		readPreference: &readPreference{mode: readPreferenceGroupPrefix, target: "rg1", strict: true},
+	}, typeutil.NewUniqueSet())
+	s.NoError(err)
+	s.Equal(s.nodes[0], targetNode)
+
+	s.lbBalancer.ExpectedCalls = nil
+	s.lbBalancer.EXPECT().SelectNode(mock.Anything, s.nodes, mock.Anything).Return(s.nodes[1], nil)
+	targetNode, err = s.lbPolicy.selectNode(ctx, ChannelWorkload{
+		db:             dbName,
+		collectionName: s.collectionName,
+		collectionID:   s.collectionID,
+		channel:        s.channels[0],
+		shardLeaders:   s.nodes,
+		nq:             1,
+		readPreference: &readPreference{mode: readPreferenceGroupPrefix, target: "rg2"},
+	}, typeutil.NewUniqueSet())
+	s.NoError(err)
+	s.Equal(s.nodes[1], targetNode)
+
+	s.lbBalancer.ExpectedCalls = nil
+	targetNode, err = s.lbPolicy.selectNode(ctx, ChannelWorkload{
+		db:             dbName,
+		collectionName: s.collectionName,
+		collectionID:   s.collectionID,
+		channel:        s.channels[0],
+		shardLeaders:   s.nodes,
+		nq:             1,
+		readPreference: &readPreference{mode: readPreferenceGroupPrefix, target: "rg2", strict: true},
+	}, typeutil.NewUniqueSet())
+	s.ErrorIs(err, merr.ErrChannelNotAvailable)
+	s.Equal(int64(-1), targetNode)
+
 	// test get shard leaders failed, retry to select node failed
 	s.lbBalancer.ExpectedCalls = nil
 	s.lbBalancer.EXPECT().SelectNode(mock.Anything, mock.Anything, mock.Anything).Return(-1, merr.ErrNodeNotAvailable)
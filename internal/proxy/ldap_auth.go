@@ -0,0 +1,230 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-ldap/ldap/v3"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/crypto"
+)
+
+const ldapUsernamePlaceholder = "{username}"
+
+type ldapBind struct {
+	dn             string
+	sha256Password string
+	expireTime     time.Time
+}
+
+// ldapAuthenticator authenticates users by binding to the LDAP server, the successful binds are cached.
+// The LDAP groups of the authenticated users are synced periodically and mapped to roles.
+type ldapAuthenticator struct {
+	mu    sync.RWMutex
+	dial  ldapDialer
+	binds map[string]*ldapBind
+	roles map[string][]string
+}
+
+var globalLDAPAuthenticator = newLDAPAuthenticator(func(ctx context.Context) (ldapConn, error) {
+	return dialLDAP(ctx, Params.ProxyCfg.LDAPAddress.GetValue(), Params.ProxyCfg.LDAPTimeout.GetAsDuration(time.Second))
+})
+
+func newLDAPAuthenticator(dial ldapDialer) *ldapAuthenticator {
+	return &ldapAuthenticator{
+		dial:  dial,
+		binds: make(map[string]*ldapBind),
+		roles: make(map[string][]string),
+	}
+}
+
+func (a *ldapAuthenticator) Enabled() bool {
+	return Params.ProxyCfg.LDAPEnabled.GetAsBool() && Params.ProxyCfg.LDAPAddress.GetValue() != ""
+}
+
+// start starts the loop which syncs the groups of LDAP users.
+func (a *ldapAuthenticator) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(Params.ProxyCfg.LDAPSyncInterval.GetAsDuration(time.Second))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("ldap group sync loop exit")
+				return
+			case <-ticker.C:
+				if a.Enabled() {
+					a.sync(ctx)
+				}
+			}
+		}
+	}()
+}
+
+// Authenticate returns whether the username and password are accepted by the LDAP server,
+// error is returned if the LDAP server is unavailable.
+func (a *ldapAuthenticator) Authenticate(ctx context.Context, username, password string) (bool, error) {
+	// empty password is an unauthenticated bind, which always succeeds
+	if username == "" || password == "" {
+		return false, nil
+	}
+
+	sha256Password := crypto.SHA256(password, username)
+	a.mu.RLock()
+	bind, ok := a.binds[username]
+	a.mu.RUnlock()
+	if ok && bind.sha256Password == sha256Password && time.Now().Before(bind.expireTime) {
+		return true, nil
+	}
+
+	dn := ldapUserDN(username)
+	conn, err := a.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if err := conn.Bind(dn, password); err != nil {
+		if errors.Is(err, errLDAPInvalidCredentials) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	_, synced := a.getRoles(username)
+	a.mu.Lock()
+	a.binds[username] = &ldapBind{
+		dn:             dn,
+		sha256Password: sha256Password,
+		expireTime:     time.Now().Add(Params.ProxyCfg.LDAPCacheTTL.GetAsDuration(time.Second)),
+	}
+	a.mu.Unlock()
+	if !synced {
+		a.syncUsers(ctx, map[string]string{username: dn})
+	}
+	return true, nil
+}
+
+// Roles returns the roles mapped from the LDAP groups of user.
+func (a *ldapAuthenticator) Roles(username string) []string {
+	roles, _ := a.getRoles(username)
+	return roles
+}
+
+func (a *ldapAuthenticator) getRoles(username string) ([]string, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	roles, ok := a.roles[username]
+	return roles, ok
+}
+
+// sync evicts the expired binds, and syncs the groups of the other users.
+func (a *ldapAuthenticator) sync(ctx context.Context) {
+	users := make(map[string]string)
+	a.mu.Lock()
+	for username, bind := range a.binds {
+		if time.Now().After(bind.expireTime) {
+			delete(a.binds, username)
+			delete(a.roles, username)
+			continue
+		}
+		users[username] = bind.dn
+	}
+	a.mu.Unlock()
+	if len(users) > 0 {
+		a.syncUsers(ctx, users)
+	}
+}
+
+// syncUsers searches the groups of users and maps them to roles, the roles are kept if failed to search.
+func (a *ldapAuthenticator) syncUsers(ctx context.Context, users map[string]string) {
+	baseDN := Params.ProxyCfg.LDAPGroupBaseDN.GetValue()
+	if baseDN == "" {
+		return
+	}
+	log := log.Ctx(ctx)
+	conn, err := a.dial(ctx)
+	if err != nil {
+		log.Warn("failed to connect ldap server to sync groups", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+	if bindDN := Params.ProxyCfg.LDAPBindDN.GetValue(); bindDN != "" {
+		if err := conn.Bind(bindDN, Params.ProxyCfg.LDAPBindPassword.GetValue()); err != nil {
+			log.Warn("failed to bind ldap service account", zap.String("bindDN", bindDN), zap.Error(err))
+			return
+		}
+	}
+
+	mapping := parseLDAPGroupRoleMapping(Params.ProxyCfg.LDAPGroupRoleMapping.GetValue())
+	for username, dn := range users {
+		groups, err := conn.SearchGroups(baseDN, Params.ProxyCfg.LDAPGroupMemberAttribute.GetValue(), dn, Params.ProxyCfg.LDAPGroupNameAttribute.GetValue())
+		if err != nil {
+			log.Warn("failed to search ldap groups", zap.String("username", username), zap.Error(err))
+			continue
+		}
+		roles := make([]string, 0)
+		for _, group := range groups {
+			if role, ok := mapping[group]; ok {
+				roles = append(roles, role)
+			}
+		}
+		a.mu.Lock()
+		a.roles[username] = roles
+		a.mu.Unlock()
+		log.Debug("ldap groups synced", zap.String("username", username), zap.Strings("groups", groups), zap.Strings("roles", roles))
+	}
+}
+
+// ldapUserDN returns the user DN from the template, the special characters in username are escaped.
+func ldapUserDN(username string) string {
+	return strings.ReplaceAll(Params.ProxyCfg.LDAPUserDNTemplate.GetValue(), ldapUsernamePlaceholder, ldap.EscapeDN(username))
+}
+
+// parseLDAPGroupRoleMapping parses the mapping like group1:role1,group2:role2.
+func parseLDAPGroupRoleMapping(value string) map[string]string {
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		group, role, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || group == "" || role == "" {
+			continue
+		}
+		mapping[group] = role
+	}
+	return mapping
+}
+
+// authenticate verifies the username and password against LDAP if enabled, the result of LDAP is final, the internal
+// credentials are verified only if LDAP is unavailable. The root user is always verified by the internal credentials,
+// so that the cluster is still manageable if LDAP is misconfigured.
+func authenticate(ctx context.Context, username, password string) bool {
+	if globalLDAPAuthenticator.Enabled() && username != util.UserRoot {
+		ok, err := globalLDAPAuthenticator.Authenticate(ctx, username, password)
+		if err == nil {
+			return ok
+		}
+		log.Ctx(ctx).RatedWarn(10, "ldap unavailable, fallback to internal credentials", zap.String("username", username), zap.Error(err))
+	}
+	return passwordVerify(ctx, username, password, globalMetaCache)
+}
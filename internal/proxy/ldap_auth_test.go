@@ -0,0 +1,180 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/crypto"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type fakeLDAPConn struct {
+	binds    *int
+	groups   []string
+	bindDNs  []string
+	password string
+}
+
+func (c *fakeLDAPConn) Bind(dn, password string) error {
+	*c.binds++
+	c.bindDNs = append(c.bindDNs, dn)
+	if password != c.password {
+		return errLDAPInvalidCredentials
+	}
+	return nil
+}
+
+func (c *fakeLDAPConn) SearchGroups(baseDN, memberAttr, member, nameAttr string) ([]string, error) {
+	return c.groups, nil
+}
+
+func (c *fakeLDAPConn) Close() error {
+	return nil
+}
+
+func TestLDAPAuthenticator(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	params := map[string]string{
+		Params.ProxyCfg.LDAPEnabled.Key:          "true",
+		Params.ProxyCfg.LDAPAddress.Key:          "ldap://localhost:389",
+		Params.ProxyCfg.LDAPUserDNTemplate.Key:   "uid={username},dc=example,dc=com",
+		Params.ProxyCfg.LDAPGroupBaseDN.Key:      "ou=groups,dc=example,dc=com",
+		Params.ProxyCfg.LDAPGroupRoleMapping.Key: "g1:role1, g3:role3",
+	}
+	for key, value := range params {
+		paramtable.Get().Save(key, value)
+		defer paramtable.Get().Reset(key)
+	}
+
+	binds := 0
+	var dialErr error
+	auth := newLDAPAuthenticator(func(ctx context.Context) (ldapConn, error) {
+		if dialErr != nil {
+			return nil, dialErr
+		}
+		return &fakeLDAPConn{binds: &binds, groups: []string{"g1", "g2"}, password: "secret"}, nil
+	})
+	assert.True(t, auth.Enabled())
+
+	ok, err := auth.Authenticate(ctx, "alice", "")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = auth.Authenticate(ctx, "alice", "wrong")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Empty(t, auth.Roles("alice"))
+
+	ok, err = auth.Authenticate(ctx, "alice", "secret")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"role1"}, auth.Roles("alice"))
+
+	// cached bind doesn't reach the server
+	binds = 0
+	dialErr = errors.New("mock")
+	ok, err = auth.Authenticate(ctx, "alice", "secret")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 0, binds)
+
+	// server unavailable
+	_, err = auth.Authenticate(ctx, "bob", "secret")
+	assert.Error(t, err)
+
+	// groups are synced for cached users, and expired binds are evicted
+	dialErr = nil
+	auth.sync(ctx)
+	assert.Equal(t, []string{"role1"}, auth.Roles("alice"))
+	auth.binds["alice"].expireTime = time.Now()
+	auth.sync(ctx)
+	assert.Empty(t, auth.Roles("alice"))
+}
+
+func TestAuthenticateWithLDAP(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	params := map[string]string{
+		Params.ProxyCfg.LDAPEnabled.Key:        "true",
+		Params.ProxyCfg.LDAPAddress.Key:        "ldap://localhost:389",
+		Params.ProxyCfg.LDAPUserDNTemplate.Key: "uid={username},dc=example,dc=com",
+	}
+	for key, value := range params {
+		paramtable.Get().Save(key, value)
+		defer paramtable.Get().Reset(key)
+	}
+
+	binds := 0
+	var dialErr error
+	ldapAuth := globalLDAPAuthenticator
+	defer func() { globalLDAPAuthenticator = ldapAuth }()
+	globalLDAPAuthenticator = newLDAPAuthenticator(func(ctx context.Context) (ldapConn, error) {
+		if dialErr != nil {
+			return nil, dialErr
+		}
+		return &fakeLDAPConn{binds: &binds, password: "secret"}, nil
+	})
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	mockCache := NewMockCache(t)
+	globalMetaCache = mockCache
+
+	assert.True(t, authenticate(ctx, "alice", "secret"))
+	// rejected by ldap, the internal credentials are not verified
+	assert.False(t, authenticate(ctx, "bob", "local"))
+
+	// ldap unavailable
+	dialErr = errors.New("mock")
+	mockCache.EXPECT().GetCredentialInfo(mock.Anything, "bob").Return(&internalpb.CredentialInfo{
+		Username:       "bob",
+		Sha256Password: crypto.SHA256("local", "bob"),
+	}, nil).Once()
+	assert.True(t, authenticate(ctx, "bob", "local"))
+
+	// root is always verified by the internal credentials
+	dialErr = nil
+	mockCache.EXPECT().GetCredentialInfo(mock.Anything, util.UserRoot).Return(&internalpb.CredentialInfo{
+		Username:       util.UserRoot,
+		Sha256Password: crypto.SHA256("local", util.UserRoot),
+	}, nil).Once()
+	assert.True(t, authenticate(ctx, util.UserRoot, "local"))
+	assert.Equal(t, 2, binds)
+}
+
+func TestLDAPUserDN(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(Params.ProxyCfg.LDAPUserDNTemplate.Key, "uid={username},dc=example,dc=com")
+	defer paramtable.Get().Reset(Params.ProxyCfg.LDAPUserDNTemplate.Key)
+
+	assert.Equal(t, "uid=alice,dc=example,dc=com", ldapUserDN("alice"))
+	assert.Equal(t, `uid=alice\,dc=evil,dc=example,dc=com`, ldapUserDN("alice,dc=evil"))
+}
+
+func TestParseLDAPGroupRoleMapping(t *testing.T) {
+	assert.Equal(t, map[string]string{"g1": "r1", "g2": "r2"}, parseLDAPGroupRoleMapping("g1:r1, g2:r2,invalid,:r3"))
+	assert.Empty(t, parseLDAPGroupRoleMapping(""))
+}
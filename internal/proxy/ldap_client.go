@@ -0,0 +1,103 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-ldap/ldap/v3"
+)
+
+var errLDAPInvalidCredentials = errors.New("ldap: invalid credentials")
+
+// ldapConn is the connection to LDAP server.
+type ldapConn interface {
+	// Bind authenticates with the dn and password, returns errLDAPInvalidCredentials if they are not correct.
+	Bind(dn, password string) error
+	// SearchGroups returns the nameAttr of entries under baseDN whose memberAttr equals to member.
+	SearchGroups(baseDN, memberAttr, member, nameAttr string) ([]string, error)
+	Close() error
+}
+
+type ldapDialer func(ctx context.Context) (ldapConn, error)
+
+type ldapClient struct {
+	conn *ldap.Conn
+}
+
+// dialLDAP connects to the LDAP server with address like ldap://host:389 or ldaps://host:636.
+func dialLDAP(ctx context.Context, address string, timeout time.Duration) (ldapConn, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return nil, fmt.Errorf("ldap: unsupported scheme %s", u.Scheme)
+	}
+	conn, err := ldap.DialURL(address,
+		ldap.DialWithDialer(&net.Dialer{Timeout: timeout}),
+		ldap.DialWithTLSConfig(&tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(timeout)
+	return &ldapClient{conn: conn}, nil
+}
+
+func newLDAPClient(conn net.Conn, timeout time.Duration) *ldapClient {
+	c := ldap.NewConn(conn, false)
+	c.Start()
+	c.SetTimeout(timeout)
+	return &ldapClient{conn: c}
+}
+
+func (c *ldapClient) Bind(dn, password string) error {
+	err := c.conn.Bind(dn, password)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return errLDAPInvalidCredentials
+	}
+	return err
+}
+
+func (c *ldapClient) SearchGroups(baseDN, memberAttr, member, nameAttr string) ([]string, error) {
+	result, err := c.conn.Search(ldap.NewSearchRequest(baseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf("(%s=%s)", ldap.EscapeFilter(memberAttr), ldap.EscapeFilter(member)),
+		[]string{nameAttr}, nil))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		names = append(names, entry.GetAttributeValues(nameAttr)...)
+	}
+	return names, nil
+}
+
+func (c *ldapClient) Close() error {
+	// unbind is best effort
+	if err := c.conn.Unbind(); err != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveFakeLDAP serves the ldap requests, accepts the bind with password "secret",
+// and returns group g1 and g2 for any search.
+func serveFakeLDAP(t *testing.T, conn net.Conn) {
+	response := func(messageID int64, ops ...*ber.Packet) {
+		for _, op := range ops {
+			envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, ""))
+			envelope.AppendChild(op)
+			_, err := conn.Write(envelope.Bytes())
+			assert.NoError(t, err)
+		}
+	}
+	ldapResult := func(tag ber.Tag, code int64) *ber.Packet {
+		result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+		result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, ""))
+		result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+		result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+		return result
+	}
+	attribute := func(name, value string) *ber.Packet {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, ""))
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+		values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, ""))
+		attr.AppendChild(values)
+		return attr
+	}
+	entry := func(dn, group string) *ber.Packet {
+		result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "")
+		result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, ""))
+		attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+		attrs.AppendChild(attribute("objectClass", "groupOfNames"))
+		attrs.AppendChild(attribute("cn", group))
+		result.AppendChild(attrs)
+		return result
+	}
+
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		messageID := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			assert.EqualValues(t, 3, op.Children[0].Value)
+			if op.Children[2].Data.String() == "secret" {
+				response(messageID, ldapResult(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess))
+			} else {
+				response(messageID, ldapResult(ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials))
+			}
+		case ldap.ApplicationSearchRequest:
+			assert.Equal(t, "ou=groups,dc=example,dc=com", op.Children[0].Value)
+			response(messageID,
+				entry("cn=g1,ou=groups,dc=example,dc=com", "g1"),
+				entry("cn=g2,ou=groups,dc=example,dc=com", "g2"),
+				ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess))
+		case ldap.ApplicationUnbindRequest:
+			conn.Close()
+			return
+		}
+	}
+}
+
+func TestLDAPClient(t *testing.T) {
+	server, client := net.Pipe()
+	go serveFakeLDAP(t, server)
+
+	conn := newLDAPClient(client, time.Second)
+	assert.ErrorIs(t, conn.Bind("uid=alice,dc=example,dc=com", "wrong"), errLDAPInvalidCredentials)
+	assert.NoError(t, conn.Bind("uid=alice,dc=example,dc=com", "secret"))
+
+	groups, err := conn.SearchGroups("ou=groups,dc=example,dc=com", "member", "uid=alice,dc=example,dc=com", "cn")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"g1", "g2"}, groups)
+	assert.NoError(t, conn.Close())
+}
+
+func TestDialLDAP(t *testing.T) {
+	_, err := dialLDAP(context.Background(), "http://localhost:389", time.Second)
+	assert.Error(t, err)
+	_, err = dialLDAP(context.Background(), "ldap://localhost:1", time.Second)
+	assert.Error(t, err)
+}
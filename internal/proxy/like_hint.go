@@ -0,0 +1,187 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// likeHintsInfoKey is set in the extra info of the search and query result status if a like pattern can not use the
+// index of its field, the value is the json of the hints.
+const likeHintsInfoKey = "like_hints"
+
+// scalarIndexTypesTTL is how long the index types of the fields of a collection are cached for the like checks.
+const scalarIndexTypesTTL = 10 * time.Second
+
+// prefixIndexTypes are the scalar index types serving the prefix match.
+var prefixIndexTypes = map[string]struct{}{
+	"TRIE":        {},
+	"MARISA-TRIE": {},
+	"INVERTED":    {},
+	"AUTOINDEX":   {},
+}
+
+type scalarIndexTypesEntry struct {
+	types   map[int64]string
+	updated time.Time
+}
+
+// scalarIndexTypes caches the index types of the fields of the collections, only the collections filtered by the
+// like patterns are described.
+type scalarIndexTypes struct {
+	mu      sync.Mutex
+	entries map[int64]*scalarIndexTypesEntry
+}
+
+var globalScalarIndexTypes = &scalarIndexTypes{entries: make(map[int64]*scalarIndexTypesEntry)}
+
+// get returns the index types of the fields of the collection by field id.
+func (s *scalarIndexTypes) get(ctx context.Context, dc types.DataCoordClient, collectionID int64) (map[int64]string, error) {
+	s.mu.Lock()
+	entry, ok := s.entries[collectionID]
+	s.mu.Unlock()
+	if ok && time.Since(entry.updated) < scalarIndexTypesTTL {
+		return entry.types, nil
+	}
+
+	resp, err := dc.DescribeIndex(ctx, &indexpb.DescribeIndexRequest{CollectionID: collectionID})
+	if err := merr.CheckRPCCall(resp, err); err != nil && !errors.Is(err, merr.ErrIndexNotFound) {
+		return nil, err
+	}
+	indexTypes := make(map[int64]string)
+	for _, index := range resp.GetIndexInfos() {
+		indexTypes[index.GetFieldID()] = funcutil.KeyValuePair2Map(index.GetIndexParams())[common.IndexTypeKey]
+	}
+	s.mu.Lock()
+	s.entries[collectionID] = &scalarIndexTypesEntry{types: indexTypes, updated: time.Now()}
+	s.mu.Unlock()
+	return indexTypes, nil
+}
+
+// likeLiteralPrefix returns the literal prefix of the like pattern before its first wildcard, and false if the prefix
+// has the escaped wildcards.
+func likeLiteralPrefix(pattern string) (string, bool) {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			return "", false
+		case '%', '_':
+			return pattern[:i], true
+		}
+	}
+	return pattern, true
+}
+
+// rewriteLikeExprs walks the expression and rewrites the like patterns on the fields with a prefix index into the
+// prefix match and the like pattern, so the index narrows the rows matched against the pattern. Returns the hints of
+// the patterns starting with a wildcard, which scan all the rows even if their fields are indexed.
+func rewriteLikeExprs(expr *planpb.Expr, indexTypes map[int64]string) []string {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_BinaryExpr:
+		return append(rewriteLikeExprs(e.BinaryExpr.GetLeft(), indexTypes), rewriteLikeExprs(e.BinaryExpr.GetRight(), indexTypes)...)
+	case *planpb.Expr_UnaryExpr:
+		return rewriteLikeExprs(e.UnaryExpr.GetChild(), indexTypes)
+	case *planpb.Expr_UnaryRangeExpr:
+		column := e.UnaryRangeExpr.GetColumnInfo()
+		if e.UnaryRangeExpr.GetOp() != planpb.OpType_Match || !typeutil.IsStringType(column.GetDataType()) {
+			return nil
+		}
+		indexType, ok := indexTypes[column.GetFieldId()]
+		if _, prefixIndexed := prefixIndexTypes[strings.ToUpper(indexType)]; !ok || !prefixIndexed {
+			return nil
+		}
+		pattern := e.UnaryRangeExpr.GetValue().GetStringVal()
+		prefix, ok := likeLiteralPrefix(pattern)
+		if !ok {
+			return nil
+		}
+		if prefix == "" {
+			return []string{fmt.Sprintf("the like pattern %q starts with a wildcard and can not use the %s index of field %d, "+
+				"all the rows are scanned, use a pattern with a literal prefix such as \"abc%%\" instead", pattern, indexType, column.GetFieldId())}
+		}
+		expr.Expr = &planpb.Expr_BinaryExpr{
+			BinaryExpr: &planpb.BinaryExpr{
+				Op: planpb.BinaryExpr_LogicalAnd,
+				Left: &planpb.Expr{Expr: &planpb.Expr_UnaryRangeExpr{UnaryRangeExpr: &planpb.UnaryRangeExpr{
+					ColumnInfo: column,
+					Op:         planpb.OpType_PrefixMatch,
+					Value:      &planpb.GenericValue{Val: &planpb.GenericValue_StringVal{StringVal: prefix}},
+				}}},
+				Right: &planpb.Expr{Expr: e},
+			},
+		}
+	}
+	return nil
+}
+
+// checkLikePatterns rewrites the like patterns of the predicates for the indexes of their fields, returns the hints
+// of the patterns scanning all the rows, or the error if they are rejected.
+func checkLikePatterns(ctx context.Context, dc types.DataCoordClient, collectionID int64, predicates *planpb.Expr) ([]string, error) {
+	if dc == nil || !hasLikePattern(predicates) {
+		return nil, nil
+	}
+	indexTypes, err := globalScalarIndexTypes.get(ctx, dc, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	hints := rewriteLikeExprs(predicates, indexTypes)
+	if len(hints) > 0 && Params.ProxyCfg.RejectUnindexableLike.GetAsBool() {
+		return nil, merr.WrapErrParameterInvalidMsg(strings.Join(hints, "; "))
+	}
+	return hints, nil
+}
+
+// hasLikePattern returns whether the expression has a like pattern which is not a prefix match.
+func hasLikePattern(expr *planpb.Expr) bool {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_BinaryExpr:
+		return hasLikePattern(e.BinaryExpr.GetLeft()) || hasLikePattern(e.BinaryExpr.GetRight())
+	case *planpb.Expr_UnaryExpr:
+		return hasLikePattern(e.UnaryExpr.GetChild())
+	case *planpb.Expr_UnaryRangeExpr:
+		return e.UnaryRangeExpr.GetOp() == planpb.OpType_Match
+	}
+	return false
+}
+
+// setLikeHints sets the like hints in the extra info of the result status.
+func setLikeHints(status *commonpb.Status, hints []string) {
+	bs, err := json.Marshal(hints)
+	if err != nil {
+		return
+	}
+	if status.ExtraInfo == nil {
+		status.ExtraInfo = make(map[string]string)
+	}
+	status.ExtraInfo[likeHintsInfoKey] = string(bs)
+}
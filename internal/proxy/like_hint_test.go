@@ -0,0 +1,119 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+func TestLikeLiteralPrefix(t *testing.T) {
+	prefix, ok := likeLiteralPrefix("abc%def")
+	assert.True(t, ok)
+	assert.Equal(t, "abc", prefix)
+	prefix, ok = likeLiteralPrefix("%abc")
+	assert.True(t, ok)
+	assert.Equal(t, "", prefix)
+	prefix, ok = likeLiteralPrefix("ab_c")
+	assert.True(t, ok)
+	assert.Equal(t, "ab", prefix)
+	_, ok = likeLiteralPrefix(`a\%b%c`)
+	assert.False(t, ok)
+}
+
+func TestCheckLikePatterns(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	schema := &schemapb.CollectionSchema{
+		Name: "coll",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "title", DataType: schemapb.DataType_VarChar},
+			{FieldID: 102, Name: "body", DataType: schemapb.DataType_VarChar},
+		},
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	assert.NoError(t, err)
+	predicates := func(expr string) *planpb.Expr {
+		plan, err := planparserv2.CreateRetrievePlan(helper, expr)
+		assert.NoError(t, err)
+		return plan.GetQuery().GetPredicates()
+	}
+
+	dc := mocks.NewMockDataCoordClient(t)
+	dc.EXPECT().DescribeIndex(mock.Anything, mock.Anything).Return(&indexpb.DescribeIndexResponse{
+		Status: merr.Success(),
+		IndexInfos: []*indexpb.IndexInfo{
+			{FieldID: 101, IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: "Trie"}}},
+		},
+	}, nil).Once()
+	globalScalarIndexTypes = &scalarIndexTypes{entries: make(map[int64]*scalarIndexTypesEntry)}
+
+	// the prefix match needs no check
+	hints, err := checkLikePatterns(ctx, dc, 1, predicates(`title like "abc%"`))
+	assert.NoError(t, err)
+	assert.Empty(t, hints)
+
+	expr := predicates(`title like "abc%def" and body like "%x"`)
+	hints, err = checkLikePatterns(ctx, dc, 1, expr)
+	assert.NoError(t, err)
+	assert.Empty(t, hints)
+	rewritten := expr.GetBinaryExpr().GetLeft().GetBinaryExpr()
+	assert.Equal(t, planpb.BinaryExpr_LogicalAnd, rewritten.GetOp())
+	assert.Equal(t, planpb.OpType_PrefixMatch, rewritten.GetLeft().GetUnaryRangeExpr().GetOp())
+	assert.Equal(t, "abc", rewritten.GetLeft().GetUnaryRangeExpr().GetValue().GetStringVal())
+	assert.Equal(t, planpb.OpType_Match, rewritten.GetRight().GetUnaryRangeExpr().GetOp())
+	// the field without index is kept
+	assert.Equal(t, planpb.OpType_Match, expr.GetBinaryExpr().GetRight().GetUnaryRangeExpr().GetOp())
+
+	// cached
+	hints, err = checkLikePatterns(ctx, dc, 1, predicates(`not (title like "%abc")`))
+	assert.NoError(t, err)
+	assert.Len(t, hints, 1)
+	assert.Contains(t, hints[0], "Trie")
+
+	status := merr.Success()
+	setLikeHints(status, hints)
+	var decoded []string
+	assert.NoError(t, json.Unmarshal([]byte(status.GetExtraInfo()[likeHintsInfoKey]), &decoded))
+	assert.Equal(t, hints, decoded)
+
+	paramtable.Get().Save(Params.ProxyCfg.RejectUnindexableLike.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.RejectUnindexableLike.Key)
+	_, err = checkLikePatterns(ctx, dc, 1, predicates(`title like "_abc"`))
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	// no datacoord to describe the indexes
+	hints, err = checkLikePatterns(ctx, nil, 1, predicates(`title like "%abc"`))
+	assert.NoError(t, err)
+	assert.Empty(t, hints)
+}
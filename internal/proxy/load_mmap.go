@@ -0,0 +1,112 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// loadMmapKey is the request metadata key of LoadCollection and LoadPartitions to load the collection with mmap
+// enabled or disabled, overriding the mmap config of the query nodes. The clients able to set the msg base
+// properties of the requests set the mmap.enabled property instead.
+const loadMmapKey = "mmap_enabled"
+
+// getLoadMmap returns the mmap option in the msg base properties or the metadata of the request, nil if not set.
+func getLoadMmap(ctx context.Context, base *commonpb.MsgBase) (*bool, error) {
+	value, ok := base.GetProperties()[common.MmapEnabledKey]
+	if !ok {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(loadMmapKey)
+		if len(values) == 0 {
+			return nil, nil
+		}
+		value = values[0]
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, merr.WrapErrParameterInvalid("true or false", value, "invalid mmap load option")
+	}
+	return &enabled, nil
+}
+
+// validateMmapProperty checks the mmap property of the collection is a bool.
+func validateMmapProperty(properties []*commonpb.KeyValuePair) error {
+	value, err := funcutil.GetAttrByKeyFromRepeatedKV(common.MmapEnabledKey, properties)
+	if err != nil {
+		return nil
+	}
+	if _, err := strconv.ParseBool(value); err != nil {
+		return merr.WrapErrParameterInvalidMsg("invalid %s value %s, only true or false is allowed", common.MmapEnabledKey, value)
+	}
+	return nil
+}
+
+// applyLoadMmap persists the mmap option of the load request as the mmap property of the collection, which the
+// querycoord passes to the query nodes on loading the segments. The option of a loaded collection can not be changed
+// without releasing it first, as the loaded segments would be left in the former mode. Altering the property
+// requires the AlterCollection privilege besides the load privilege. Returns whether the collection property is
+// altered.
+func applyLoadMmap(ctx context.Context, rc types.RootCoordClient, qc types.QueryCoordClient, base *commonpb.MsgBase,
+	dbName, collectionName string, collectionID int64, properties []*commonpb.KeyValuePair,
+) (bool, error) {
+	enabled, err := getLoadMmap(ctx, base)
+	if err != nil || enabled == nil {
+		return false, err
+	}
+	if value, err := funcutil.GetAttrByKeyFromRepeatedKV(common.MmapEnabledKey, properties); err == nil {
+		if current, err := strconv.ParseBool(value); err == nil && current == *enabled {
+			return false, nil
+		}
+	}
+
+	loaded, err := isCollectionLoaded(ctx, qc, collectionID)
+	if err != nil {
+		return false, err
+	}
+	if loaded {
+		return false, merr.WrapErrCollectionLoaded(collectionName, "can not change the mmap option of a loaded collection, release it first")
+	}
+	req := &milvuspb.AlterCollectionRequest{
+		Base:           commonpbutil.NewMsgBase(commonpbutil.WithMsgType(commonpb.MsgType_AlterCollection)),
+		DbName:         dbName,
+		CollectionName: collectionName,
+		CollectionID:   collectionID,
+		Properties: []*commonpb.KeyValuePair{
+			{Key: common.MmapEnabledKey, Value: strconv.FormatBool(*enabled)},
+		},
+	}
+	if _, err := PrivilegeInterceptor(ctx, req); err != nil {
+		return false, err
+	}
+	status, err := rc.AlterCollection(ctx, req)
+	if err := merr.CheckRPCCall(status, err); err != nil {
+		return false, err
+	}
+	globalMetaCache.RemoveCollection(ctx, dbName, collectionName)
+	return true, nil
+}
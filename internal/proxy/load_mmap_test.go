@@ -0,0 +1,114 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestGetLoadMmap(t *testing.T) {
+	enabled, err := getLoadMmap(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Nil(t, enabled)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(loadMmapKey, "true"))
+	enabled, err = getLoadMmap(ctx, nil)
+	assert.NoError(t, err)
+	assert.True(t, *enabled)
+
+	// the msg base property takes precedence over the metadata
+	enabled, err = getLoadMmap(ctx, &commonpb.MsgBase{Properties: map[string]string{common.MmapEnabledKey: "false"}})
+	assert.NoError(t, err)
+	assert.False(t, *enabled)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(loadMmapKey, "yes"))
+	_, err = getLoadMmap(ctx, nil)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestValidateMmapProperty(t *testing.T) {
+	assert.NoError(t, validateMmapProperty(nil))
+	assert.NoError(t, validateMmapProperty([]*commonpb.KeyValuePair{{Key: common.MmapEnabledKey, Value: "false"}}))
+	assert.ErrorIs(t, validateMmapProperty([]*commonpb.KeyValuePair{{Key: common.MmapEnabledKey, Value: "on"}}), merr.ErrParameterInvalid)
+}
+
+func TestApplyLoadMmap(t *testing.T) {
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	mockCache := NewMockCache(t)
+	globalMetaCache = mockCache
+
+	rc := mocks.NewMockRootCoordClient(t)
+	qc := mocks.NewMockQueryCoordClient(t)
+	mmapCtx := func(value string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(loadMmapKey, value))
+	}
+	enabledProps := []*commonpb.KeyValuePair{{Key: common.MmapEnabledKey, Value: "true"}}
+
+	// not set
+	altered, err := applyLoadMmap(context.Background(), rc, qc, nil, "db", "coll", 1, nil)
+	assert.NoError(t, err)
+	assert.False(t, altered)
+
+	// same as the collection property
+	altered, err = applyLoadMmap(mmapCtx("true"), rc, qc, nil, "db", "coll", 1, enabledProps)
+	assert.NoError(t, err)
+	assert.False(t, altered)
+
+	qc.EXPECT().ShowCollections(mock.Anything, mock.Anything).Return(&querypb.ShowCollectionsResponse{
+		Status:        merr.Success(),
+		CollectionIDs: []int64{2},
+	}, nil)
+	rc.EXPECT().AlterCollection(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, req *milvuspb.AlterCollectionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+			assert.Equal(t, int64(1), req.GetCollectionID())
+			assert.Equal(t, []*commonpb.KeyValuePair{{Key: common.MmapEnabledKey, Value: "false"}}, req.GetProperties())
+			return merr.Success(), nil
+		})
+	mockCache.EXPECT().RemoveCollection(mock.Anything, "db", "coll").Return()
+	altered, err = applyLoadMmap(mmapCtx("false"), rc, qc, nil, "db", "coll", 1, enabledProps)
+	assert.NoError(t, err)
+	assert.True(t, altered)
+
+	// the loaded collection
+	_, err = applyLoadMmap(mmapCtx("false"), rc, qc, nil, "db", "coll2", 2, enabledProps)
+	assert.ErrorIs(t, err, merr.ErrCollectionLoaded)
+
+	// altering the property requires the AlterCollection privilege
+	paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+	mockCache.EXPECT().GetUserRole(mock.Anything).Return(nil).Maybe()
+	mockCache.EXPECT().GetPrivilegeInfo(mock.Anything).Return(nil).Maybe()
+	base := &commonpb.MsgBase{Properties: map[string]string{common.MmapEnabledKey: "false"}}
+	_, err = applyLoadMmap(GetContext(context.Background(), "bob:pwd"), rc, qc, base, "db", "coll", 1, enabledProps)
+	assert.Error(t, err)
+	rc.AssertNumberOfCalls(t, "AlterCollection", 1)
+}
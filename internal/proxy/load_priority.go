@@ -0,0 +1,202 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+const (
+	// loadPriorityKey is the request metadata key of LoadCollection to choose the priority of the loading. The
+	// clients able to set the msg base properties of the requests set the load_priority property instead.
+	loadPriorityKey = common.LoadPriorityKey
+	// loadWarmupKey is the request metadata key of LoadCollection to run sample queries once the collection loaded,
+	// so that the first query of the users does not pay for the cold cache.
+	loadWarmupKey = "load_warmup"
+
+	// loadPriorityForeground loads the collection in the normal priority, which is the default.
+	loadPriorityForeground = "foreground"
+	// loadPriorityBackground makes the querycoord load the segments of the collection after the ones of the
+	// collections loaded in the foreground.
+	loadPriorityBackground = common.LoadPriorityBackground
+
+	loadWarmupCheckInterval = time.Second
+	loadWarmupQueryLimit    = 100
+)
+
+// getLoadPriority returns the load priority in the msg base properties or the metadata of the request, foreground
+// if not set.
+func getLoadPriority(ctx context.Context, base *commonpb.MsgBase) (string, error) {
+	priority, ok := base.GetProperties()[common.LoadPriorityKey]
+	if !ok {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(loadPriorityKey)
+		if len(values) == 0 {
+			return loadPriorityForeground, nil
+		}
+		priority = values[0]
+	}
+	switch priority {
+	case loadPriorityForeground, loadPriorityBackground:
+		return priority, nil
+	default:
+		return "", merr.WrapErrParameterInvalid("foreground or background", priority, "invalid load priority")
+	}
+}
+
+// setLoadPriority sets the load priority in the msg base properties of the request passed to the querycoord.
+func setLoadPriority(request *milvuspb.LoadCollectionRequest, priority string) {
+	if priority == loadPriorityForeground {
+		return
+	}
+	if request.Base == nil {
+		request.Base = commonpbutil.NewMsgBase()
+	}
+	if request.Base.Properties == nil {
+		request.Base.Properties = make(map[string]string)
+	}
+	request.Base.Properties[common.LoadPriorityKey] = priority
+}
+
+// loadWarmups tracks the collections loading through this proxy to warm up once loaded.
+type loadWarmups struct {
+	mu       sync.Mutex
+	requests []*milvuspb.LoadCollectionRequest
+}
+
+var globalLoadWarmups = &loadWarmups{}
+
+func (l *loadWarmups) add(request *milvuspb.LoadCollectionRequest) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.requests = append(l.requests, request)
+}
+
+// pending returns the number of the loads not warmed up.
+func (l *loadWarmups) pending() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.requests)
+}
+
+// advance warms up the collections loaded and drops them. The rpcs are called without holding the lock.
+func (l *loadWarmups) advance(ctx context.Context,
+	progress func(ctx context.Context, request *milvuspb.LoadCollectionRequest) (int64, error),
+	warmup func(ctx context.Context, request *milvuspb.LoadCollectionRequest),
+) {
+	l.mu.Lock()
+	requests := append([]*milvuspb.LoadCollectionRequest{}, l.requests...)
+	l.mu.Unlock()
+
+	done := make(map[*milvuspb.LoadCollectionRequest]struct{})
+	for _, request := range requests {
+		p, err := progress(ctx, request)
+		if err != nil {
+			log.Ctx(ctx).Warn("failed to get loading progress, stop tracking the load",
+				zap.String("collection", request.GetCollectionName()), zap.Error(err))
+			done[request] = struct{}{}
+			continue
+		}
+		if p >= 100 {
+			done[request] = struct{}{}
+			warmup(ctx, request)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ret := l.requests[:0]
+	for _, request := range l.requests {
+		if _, ok := done[request]; !ok {
+			ret = append(ret, request)
+		}
+	}
+	l.requests = ret
+}
+
+// warmupCollection runs a count and a sample query on the loaded collection to fill the caches of the query nodes.
+func (node *Proxy) warmupCollection(ctx context.Context, dbName, collectionName string) {
+	start := time.Now()
+	log := log.Ctx(ctx).With(zap.String("db", dbName), zap.String("collection", collectionName))
+	queries := []*milvuspb.QueryRequest{
+		{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			OutputFields:   []string{"count(*)"},
+		},
+		{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			OutputFields:   []string{"*"},
+			QueryParams:    []*commonpb.KeyValuePair{{Key: LimitKey, Value: strconv.Itoa(loadWarmupQueryLimit)}},
+		},
+	}
+	for _, query := range queries {
+		query.ConsistencyLevel = commonpb.ConsistencyLevel_Eventually
+		resp, err := node.Query(ctx, query)
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			log.Warn("failed to warm up the loaded collection", zap.Error(err))
+			return
+		}
+	}
+	log.Info("loaded collection warmed up", zap.Duration("duration", time.Since(start)))
+}
+
+// loadWarmupLoop warms up the collections loaded through this proxy if requested.
+func (node *Proxy) loadWarmupLoop() {
+	defer node.wg.Done()
+	ticker := time.NewTicker(loadWarmupCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-node.ctx.Done():
+			return
+		case <-ticker.C:
+			if globalLoadWarmups.pending() == 0 {
+				continue
+			}
+			globalLoadWarmups.advance(node.ctx,
+				func(ctx context.Context, request *milvuspb.LoadCollectionRequest) (int64, error) {
+					resp, err := node.GetLoadingProgress(ctx, &milvuspb.GetLoadingProgressRequest{
+						DbName:         request.GetDbName(),
+						CollectionName: request.GetCollectionName(),
+					})
+					if err := merr.CheckRPCCall(resp, err); err != nil {
+						return 0, err
+					}
+					return resp.GetProgress(), nil
+				},
+				func(ctx context.Context, request *milvuspb.LoadCollectionRequest) {
+					node.warmupCollection(ctx, request.GetDbName(), request.GetCollectionName())
+				},
+			)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestGetLoadPriority(t *testing.T) {
+	priority, err := getLoadPriority(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, loadPriorityForeground, priority)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(loadPriorityKey, loadPriorityBackground))
+	priority, err = getLoadPriority(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, loadPriorityBackground, priority)
+
+	// the msg base property takes precedence over the metadata
+	base := &commonpb.MsgBase{Properties: map[string]string{common.LoadPriorityKey: loadPriorityForeground}}
+	priority, err = getLoadPriority(ctx, base)
+	assert.NoError(t, err)
+	assert.Equal(t, loadPriorityForeground, priority)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(loadPriorityKey, "urgent"))
+	_, err = getLoadPriority(ctx, nil)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestSetLoadPriority(t *testing.T) {
+	request := &milvuspb.LoadCollectionRequest{}
+	setLoadPriority(request, loadPriorityForeground)
+	assert.Nil(t, request.GetBase())
+
+	setLoadPriority(request, loadPriorityBackground)
+	assert.Equal(t, common.LoadPriorityBackground, request.GetBase().GetProperties()[common.LoadPriorityKey])
+}
+
+func TestLoadWarmups(t *testing.T) {
+	ctx := context.Background()
+	l := &loadWarmups{}
+	progress := map[string]int64{}
+	warmedUp := []string{}
+	advance := func() {
+		l.advance(ctx,
+			func(ctx context.Context, request *milvuspb.LoadCollectionRequest) (int64, error) {
+				if request.GetCollectionName() == "err" {
+					return 0, errors.New("mock")
+				}
+				return progress[request.GetCollectionName()], nil
+			},
+			func(ctx context.Context, request *milvuspb.LoadCollectionRequest) {
+				warmedUp = append(warmedUp, request.GetCollectionName())
+			},
+		)
+	}
+
+	l.add(&milvuspb.LoadCollectionRequest{CollectionName: "c1"})
+	l.add(&milvuspb.LoadCollectionRequest{CollectionName: "c2"})
+	advance()
+	assert.Empty(t, warmedUp)
+	assert.Equal(t, 2, l.pending())
+
+	progress["c2"] = 100
+	advance()
+	assert.Equal(t, []string{"c2"}, warmedUp)
+	assert.Equal(t, 1, l.pending())
+
+	progress["c1"] = 100
+	advance()
+	assert.Equal(t, []string{"c2", "c1"}, warmedUp)
+	assert.Equal(t, 0, l.pending())
+
+	// the load failed to get the progress is dropped
+	l.add(&milvuspb.LoadCollectionRequest{CollectionName: "err"})
+	advance()
+	assert.Equal(t, 0, l.pending())
+	assert.Equal(t, []string{"c2", "c1"}, warmedUp)
+}
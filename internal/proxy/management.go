@@ -21,12 +21,15 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	management "github.com/milvus-io/milvus/internal/http"
 	"github.com/milvus-io/milvus/internal/proto/datapb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util"
 	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 )
@@ -47,6 +50,55 @@ const (
 	mgrListQueryNode              = `/management/querycoord/node/list`
 	mgrGetQueryNodeDistribution   = `/management/querycoord/distribution/get`
 	mgrCheckQueryNodeDistribution = `/management/querycoord/distribution/check`
+
+	mgrSuspendOperations = `/management/proxy/operations/suspend`
+	mgrResumeOperations  = `/management/proxy/operations/resume`
+	mgrListSuspendRules  = `/management/proxy/operations/list`
+
+	mgrValidateCollectionSchema = `/management/proxy/collection/validate`
+	mgrApplyCollectionSpec      = `/management/proxy/collection/apply`
+
+	mgrGetUsage = `/management/proxy/usage`
+
+	mgrListDroppedCollections = `/management/proxy/collection/dropped/list`
+	mgrRestoreCollection      = `/management/proxy/collection/dropped/restore`
+
+	mgrSetMaskingRule    = `/management/proxy/masking/set`
+	mgrRemoveMaskingRule = `/management/proxy/masking/remove`
+	mgrListMaskingRules  = `/management/proxy/masking/list`
+
+	mgrSetNetworkPolicy    = `/management/proxy/network_policy/set`
+	mgrRemoveNetworkPolicy = `/management/proxy/network_policy/remove`
+	mgrListNetworkPolicies = `/management/proxy/network_policy/list`
+
+	mgrGetRates = `/management/proxy/rates`
+
+	mgrTuneSearchParams = `/management/proxy/search/tune`
+
+	mgrSwapAlias = `/management/proxy/alias/swap`
+
+	mgrWaitMutationVisibility = `/management/proxy/mutation/wait`
+
+	mgrGetCollectionStorageInfo = `/management/proxy/collection/storage`
+
+	mgrListIndexedSegments = `/management/proxy/collection/indexed_segments`
+
+	mgrDumpState = `/debug/proxy/state`
+
+	mgrCancelRequest = `/management/proxy/request/cancel`
+
+	mgrValidateImport = `/management/proxy/import/validate`
+
+	mgrCreateIngestionSource = `/management/proxy/ingestion/create`
+	mgrDropIngestionSource   = `/management/proxy/ingestion/drop`
+	mgrListIngestionSources  = `/management/proxy/ingestion/list`
+
+	mgrRegisterJobCallback   = `/management/proxy/job_notification/register`
+	mgrUnregisterJobCallback = `/management/proxy/job_notification/unregister`
+	mgrListJobCallbacks      = `/management/proxy/job_notification/list`
+
+	mgrListJobs = `/management/proxy/jobs/list`
+	mgrGetJob   = `/management/proxy/jobs/get`
 )
 
 var mgrRouteRegisterOnce sync.Once
@@ -97,6 +149,130 @@ func RegisterMgrRoute(proxy *Proxy) {
 			Path:        mgrCheckQueryNodeDistribution,
 			HandlerFunc: proxy.CheckQueryNodeDistribution,
 		})
+		management.Register(&management.Handler{
+			Path:        mgrSuspendOperations,
+			HandlerFunc: proxy.SuspendOperations,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrResumeOperations,
+			HandlerFunc: proxy.ResumeOperations,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrListSuspendRules,
+			HandlerFunc: proxy.ListSuspendRules,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrValidateCollectionSchema,
+			HandlerFunc: proxy.ValidateCollectionSchema,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrApplyCollectionSpec,
+			HandlerFunc: proxy.ApplyCollectionSpec,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrGetUsage,
+			HandlerFunc: proxy.GetUsage,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrListDroppedCollections,
+			HandlerFunc: proxy.ListDroppedCollections,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRestoreCollection,
+			HandlerFunc: proxy.RestoreCollection,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrSetMaskingRule,
+			HandlerFunc: proxy.SetMaskingRule,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRemoveMaskingRule,
+			HandlerFunc: proxy.RemoveMaskingRule,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrListMaskingRules,
+			HandlerFunc: proxy.ListMaskingRules,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrSetNetworkPolicy,
+			HandlerFunc: proxy.SetNetworkPolicy,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRemoveNetworkPolicy,
+			HandlerFunc: proxy.RemoveNetworkPolicy,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrListNetworkPolicies,
+			HandlerFunc: proxy.ListNetworkPolicies,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrGetRates,
+			HandlerFunc: proxy.GetRates,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrTuneSearchParams,
+			HandlerFunc: proxy.TuneSearchParams,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrSwapAlias,
+			HandlerFunc: proxy.AtomicSwapAlias,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrWaitMutationVisibility,
+			HandlerFunc: proxy.WaitForMutationVisibility,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrGetCollectionStorageInfo,
+			HandlerFunc: proxy.GetCollectionStorageInfo,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrListIndexedSegments,
+			HandlerFunc: proxy.ListIndexedSegments,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrDumpState,
+			HandlerFunc: proxy.DumpState,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrCancelRequest,
+			HandlerFunc: proxy.CancelRequest,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrValidateImport,
+			HandlerFunc: proxy.ValidateImport,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrCreateIngestionSource,
+			HandlerFunc: proxy.CreateIngestionSource,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrDropIngestionSource,
+			HandlerFunc: proxy.DropIngestionSource,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrListIngestionSources,
+			HandlerFunc: proxy.ListIngestionSources,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrRegisterJobCallback,
+			HandlerFunc: proxy.RegisterJobCallback,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrUnregisterJobCallback,
+			HandlerFunc: proxy.UnregisterJobCallback,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrListJobCallbacks,
+			HandlerFunc: proxy.ListJobCallbacks,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrListJobs,
+			HandlerFunc: proxy.ListJobs,
+		})
+		management.Register(&management.Handler{
+			Path:        mgrGetJob,
+			HandlerFunc: proxy.GetJob,
+		})
 	})
 }
 
@@ -501,3 +677,928 @@ func (node *Proxy) CheckQueryNodeDistribution(w http.ResponseWriter, req *http.R
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"msg": "OK"}`))
 }
+
+// SuspendOperations suspends the class of operations on all proxies, only the admin is allowed.
+func (node *Proxy) SuspendOperations(w http.ResponseWriter, req *http.Request) {
+	if _, err := mgrAuthAdmin(req); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to suspend operations, %s"}`, err.Error())))
+		return
+	}
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to suspend operations, %s"}`, err.Error())))
+		return
+	}
+
+	class, err := parseOperationClass(req.FormValue("class"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to suspend operations, %s"}`, err.Error())))
+		return
+	}
+
+	err = globalOperationSuspender.Suspend(&SuspendRule{
+		Class:          class,
+		DBName:         req.FormValue("db_name"),
+		CollectionName: req.FormValue("collection_name"),
+		Message:        req.FormValue("message"),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to suspend operations, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+// ResumeOperations removes the suspend rule of the scope, only the admin is allowed.
+func (node *Proxy) ResumeOperations(w http.ResponseWriter, req *http.Request) {
+	if _, err := mgrAuthAdmin(req); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to resume operations, %s"}`, err.Error())))
+		return
+	}
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to resume operations, %s"}`, err.Error())))
+		return
+	}
+
+	class, err := parseOperationClass(req.FormValue("class"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to resume operations, %s"}`, err.Error())))
+		return
+	}
+
+	err = globalOperationSuspender.Resume(class, req.FormValue("db_name"), req.FormValue("collection_name"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to resume operations, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+// ListSuspendRules lists all the suspend rules, only the admin is allowed.
+func (node *Proxy) ListSuspendRules(w http.ResponseWriter, req *http.Request) {
+	if _, err := mgrAuthAdmin(req); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list suspend rules, %s"}`, err.Error())))
+		return
+	}
+	bytes, err := json.Marshal(globalOperationSuspender.List())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list suspend rules, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// ValidateCollectionSchema runs the CreateCollection and CreateIndex validations on the schema in request body
+// without creating anything.
+func (node *Proxy) ValidateCollectionSchema(w http.ResponseWriter, req *http.Request) {
+	request := &ValidateSchemaRequest{}
+	if err := json.NewDecoder(req.Body).Decode(request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to validate collection schema, %s"}`, err.Error())))
+		return
+	}
+
+	ctx, err := mgrAuthContext(req, request.DbName)
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to validate collection schema, %s"}`, err.Error())))
+		return
+	}
+	violations, err := ValidateCollectionSchema(ctx, request)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to validate collection schema, %s"}`, err.Error())))
+		return
+	}
+
+	bytes, err := json.Marshal(map[string]any{
+		"valid":      len(violations) == 0,
+		"violations": violations,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to validate collection schema, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// ApplyCollectionSpec reconciles the collection to the spec in request body, and returns the change report.
+func (node *Proxy) ApplyCollectionSpec(w http.ResponseWriter, req *http.Request) {
+	spec := &CollectionSpec{}
+	if err := json.NewDecoder(req.Body).Decode(spec); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to apply collection spec, %s"}`, err.Error())))
+		return
+	}
+
+	ctx, err := mgrAuthContext(req, spec.DbName)
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to apply collection spec, %s"}`, err.Error())))
+		return
+	}
+	report, err := node.applyCollectionSpec(ctx, spec)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to apply collection spec, %s"}`, err.Error())))
+		return
+	}
+
+	bytes, err := json.Marshal(report)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to apply collection spec, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// GetUsage returns the usage of each database and user, the usage of all proxies is merged if scope is cluster.
+// Only the admin is allowed.
+func (node *Proxy) GetUsage(w http.ResponseWriter, req *http.Request) {
+	if _, err := mgrAuthAdmin(req); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get usage, %s"}`, err.Error())))
+		return
+	}
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get usage, %s"}`, err.Error())))
+		return
+	}
+
+	dbName, user := req.FormValue("db_name"), req.FormValue("user")
+	var usages []*Usage
+	switch req.FormValue("scope") {
+	case "", "node":
+		usages = globalUsageAccountant.List(dbName, user)
+	case "cluster":
+		usages, err = globalUsageAccountant.ListCluster(dbName, user)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get usage, %s"}`, err.Error())))
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"msg": "failed to get usage, scope should be node or cluster"}`))
+		return
+	}
+
+	bytes, err := json.Marshal(usages)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get usage, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// ListDroppedCollections lists the dropped collections kept in the recycle bin, the user should be able to
+// create collections in the database, or be the admin to list all the databases.
+func (node *Proxy) ListDroppedCollections(w http.ResponseWriter, req *http.Request) {
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list dropped collections, %s"}`, err.Error())))
+		return
+	}
+	dbName := req.FormValue("db_name")
+	ctx, err := mgrAuthContext(req, dbName)
+	if err == nil {
+		if dbName == "" {
+			err = checkMgrAdmin(ctx)
+		} else {
+			err = checkMgrPrivilege(ctx, &milvuspb.CreateCollectionRequest{DbName: dbName})
+		}
+	}
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list dropped collections, %s"}`, err.Error())))
+		return
+	}
+	if globalCollectionRecycleBin.kv == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"msg": "failed to list dropped collections, recycle bin not initialized"}`))
+		return
+	}
+
+	collections, err := globalCollectionRecycleBin.List(dbName)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list dropped collections, %s"}`, err.Error())))
+		return
+	}
+	bytes, err := json.Marshal(collections)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list dropped collections, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// RestoreCollection renames the latest dropped collection back, the user should be able to drop the collection
+// and create the restored one, as the restore undoes the drop.
+func (node *Proxy) RestoreCollection(w http.ResponseWriter, req *http.Request) {
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to restore collection, %s"}`, err.Error())))
+		return
+	}
+	collectionName := req.FormValue("collection_name")
+	if collectionName == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"msg": "failed to restore collection, collection_name is required"}`))
+		return
+	}
+
+	dbName := req.FormValue("db_name")
+	if dbName == "" {
+		dbName = util.DefaultDBName
+	}
+	newName := req.FormValue("new_name")
+	restoredName := newName
+	if restoredName == "" {
+		restoredName = collectionName
+	}
+	ctx, err := mgrAuthContext(req, dbName)
+	if err == nil {
+		err = checkMgrPrivilege(ctx,
+			&milvuspb.DropCollectionRequest{DbName: dbName, CollectionName: collectionName},
+			&milvuspb.CreateCollectionRequest{DbName: dbName, CollectionName: restoredName})
+	}
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to restore collection, %s"}`, err.Error())))
+		return
+	}
+	if globalCollectionRecycleBin.kv == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"msg": "failed to restore collection, recycle bin not initialized"}`))
+		return
+	}
+	_, err = globalCollectionRecycleBin.Restore(ctx, dbName, collectionName, newName)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to restore collection, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+// SetMaskingRule sets the masking rule of the role on the field, only the admin is allowed.
+func (node *Proxy) SetMaskingRule(w http.ResponseWriter, req *http.Request) {
+	if _, err := mgrAuthAdmin(req); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to set masking rule, %s"}`, err.Error())))
+		return
+	}
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to set masking rule, %s"}`, err.Error())))
+		return
+	}
+
+	rule := &MaskingRule{
+		Role:           req.FormValue("role"),
+		DBName:         req.FormValue("db_name"),
+		CollectionName: req.FormValue("collection_name"),
+		FieldName:      req.FormValue("field_name"),
+		Mode:           MaskingMode(req.FormValue("mode")),
+	}
+	if showLast := req.FormValue("show_last"); showLast != "" {
+		rule.ShowLast, err = strconv.Atoi(showLast)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf(`{"msg": "failed to set masking rule, %s"}`, err.Error())))
+			return
+		}
+	}
+
+	err = globalMaskingPolicy.Set(rule)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to set masking rule, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+// RemoveMaskingRule removes the masking rule of the role on the field, only the admin is allowed.
+func (node *Proxy) RemoveMaskingRule(w http.ResponseWriter, req *http.Request) {
+	if _, err := mgrAuthAdmin(req); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to remove masking rule, %s"}`, err.Error())))
+		return
+	}
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to remove masking rule, %s"}`, err.Error())))
+		return
+	}
+
+	err = globalMaskingPolicy.Remove(req.FormValue("role"), req.FormValue("db_name"), req.FormValue("collection_name"), req.FormValue("field_name"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to remove masking rule, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+// ListMaskingRules lists the masking rules of the role, only the admin is allowed.
+func (node *Proxy) ListMaskingRules(w http.ResponseWriter, req *http.Request) {
+	if _, err := mgrAuthAdmin(req); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list masking rules, %s"}`, err.Error())))
+		return
+	}
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list masking rules, %s"}`, err.Error())))
+		return
+	}
+	bytes, err := json.Marshal(globalMaskingPolicy.List(req.FormValue("role")))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list masking rules, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+func splitFormList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// SetNetworkPolicy sets the network policy of user or role, only the admin is allowed.
+func (node *Proxy) SetNetworkPolicy(w http.ResponseWriter, req *http.Request) {
+	if _, err := mgrAuthAdmin(req); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to set network policy, %s"}`, err.Error())))
+		return
+	}
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to set network policy, %s"}`, err.Error())))
+		return
+	}
+
+	err = globalNetworkPolicy.Set(&NetworkPolicy{
+		Subject: NetworkPolicySubject(req.FormValue("subject")),
+		Name:    req.FormValue("name"),
+		Allow:   splitFormList(req.FormValue("allow")),
+		Deny:    splitFormList(req.FormValue("deny")),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to set network policy, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+// RemoveNetworkPolicy removes the network policy of user or role, only the admin is allowed.
+func (node *Proxy) RemoveNetworkPolicy(w http.ResponseWriter, req *http.Request) {
+	if _, err := mgrAuthAdmin(req); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to remove network policy, %s"}`, err.Error())))
+		return
+	}
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to remove network policy, %s"}`, err.Error())))
+		return
+	}
+
+	err = globalNetworkPolicy.Remove(NetworkPolicySubject(req.FormValue("subject")), req.FormValue("name"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to remove network policy, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+// ListNetworkPolicies lists all the network policies, only the admin is allowed.
+func (node *Proxy) ListNetworkPolicies(w http.ResponseWriter, req *http.Request) {
+	if _, err := mgrAuthAdmin(req); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list network policies, %s"}`, err.Error())))
+		return
+	}
+	bytes, err := json.Marshal(globalNetworkPolicy.List())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list network policies, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// GetRates returns the effective rate limits of all the scopes on this proxy.
+func (node *Proxy) GetRates(w http.ResponseWriter, req *http.Request) {
+	if _, err := mgrAuthAdmin(req); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get rates, %s"}`, err.Error())))
+		return
+	}
+	bytes, err := json.Marshal(node.simpleLimiter.GetRates())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get rates, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// TuneSearchParams sweeps the search param of the index to reach the target recall in request body,
+// and returns the trials and the recommended param.
+func (node *Proxy) TuneSearchParams(w http.ResponseWriter, req *http.Request) {
+	request := &SearchTuneRequest{}
+	if err := json.NewDecoder(req.Body).Decode(request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to tune search params, %s"}`, err.Error())))
+		return
+	}
+
+	ctx, err := mgrAuthContext(req, request.DbName)
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to tune search params, %s"}`, err.Error())))
+		return
+	}
+	report, err := node.tuneSearchParams(ctx, request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to tune search params, %s"}`, err.Error())))
+		return
+	}
+
+	bytes, err := json.Marshal(report)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to tune search params, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// AtomicSwapAlias retargets the alias in request body to the target collection without any gap,
+// and optionally releases or drops the source collection after the grace period.
+func (node *Proxy) AtomicSwapAlias(w http.ResponseWriter, req *http.Request) {
+	request := &AliasSwapRequest{}
+	if err := json.NewDecoder(req.Body).Decode(request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to swap alias, %s"}`, err.Error())))
+		return
+	}
+
+	ctx, err := mgrAuthContext(req, request.DbName)
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to swap alias, %s"}`, err.Error())))
+		return
+	}
+	result, err := node.atomicSwapAlias(ctx, request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to swap alias, %s"}`, err.Error())))
+		return
+	}
+
+	bytes, err := json.Marshal(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to swap alias, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// WaitForMutationVisibility blocks until the mutations before the timestamp in request body
+// are serviceable on all the loaded shards of the collection.
+func (node *Proxy) WaitForMutationVisibility(w http.ResponseWriter, req *http.Request) {
+	request := &MutationVisibilityRequest{}
+	if err := json.NewDecoder(req.Body).Decode(request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to wait for mutation visibility, %s"}`, err.Error())))
+		return
+	}
+
+	ctx, err := mgrAuthContext(req, request.DbName)
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to wait for mutation visibility, %s"}`, err.Error())))
+		return
+	}
+	result, err := node.waitForMutationVisibility(ctx, request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to wait for mutation visibility, %s"}`, err.Error())))
+		return
+	}
+
+	bytes, err := json.Marshal(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to wait for mutation visibility, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// GetCollectionStorageInfo returns the binlog, deltalog, statslog and index file sizes
+// of the collection in request body and its partitions.
+func (node *Proxy) GetCollectionStorageInfo(w http.ResponseWriter, req *http.Request) {
+	request := &CollectionStorageInfoRequest{}
+	if err := json.NewDecoder(req.Body).Decode(request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get collection storage info, %s"}`, err.Error())))
+		return
+	}
+
+	ctx, err := mgrAuthContext(req, request.DbName)
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get collection storage info, %s"}`, err.Error())))
+		return
+	}
+	result, err := node.getCollectionStorageInfo(ctx, request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get collection storage info, %s"}`, err.Error())))
+		return
+	}
+
+	bytes, err := json.Marshal(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get collection storage info, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// ListIndexedSegments lists the sealed segments of the collection in request body with their built indexes,
+// and the fraction of the sealed rows covered by the indexes.
+func (node *Proxy) ListIndexedSegments(w http.ResponseWriter, req *http.Request) {
+	request := &IndexedSegmentsRequest{}
+	if err := json.NewDecoder(req.Body).Decode(request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list indexed segments, %s"}`, err.Error())))
+		return
+	}
+
+	ctx, err := mgrAuthContext(req, request.DbName)
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list indexed segments, %s"}`, err.Error())))
+		return
+	}
+	result, err := node.listIndexedSegments(ctx, request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list indexed segments, %s"}`, err.Error())))
+		return
+	}
+
+	bytes, err := json.Marshal(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list indexed segments, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// DumpState returns the json of the proxy internal state, including the task queues, the meta cache entries,
+// the shard leader cache, the dml streams and the rate limiter, with the credentials and request payloads redacted.
+func (node *Proxy) DumpState(w http.ResponseWriter, req *http.Request) {
+	if _, err := mgrAuthAdmin(req); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to dump proxy state, %s"}`, err.Error())))
+		return
+	}
+	bytes, err := json.Marshal(node.dumpState())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to dump proxy state, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// CancelRequest cancels the in-flight tasks of the trace id or the msg id in request body.
+func (node *Proxy) CancelRequest(w http.ResponseWriter, req *http.Request) {
+	request := &CancelRequestRequest{}
+	if err := json.NewDecoder(req.Body).Decode(request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to cancel request, %s"}`, err.Error())))
+		return
+	}
+
+	ctx, err := mgrAuthContext(req, "")
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to cancel request, %s"}`, err.Error())))
+		return
+	}
+	result, err := node.cancelRequest(ctx, request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to cancel request, %s"}`, err.Error())))
+		return
+	}
+
+	bytes, err := json.Marshal(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to cancel request, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// ValidateImport samples the import files in request body and returns the report of the schema compatibility,
+// the primary key uniqueness within the samples and the estimated rows and segments, without importing anything.
+func (node *Proxy) ValidateImport(w http.ResponseWriter, req *http.Request) {
+	request := &ImportValidationRequest{}
+	if err := json.NewDecoder(req.Body).Decode(request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to validate import, %s"}`, err.Error())))
+		return
+	}
+
+	ctx, err := mgrAuthContext(req, request.DbName)
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to validate import, %s"}`, err.Error())))
+		return
+	}
+	result, err := node.validateImport(ctx, request)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to validate import, %s"}`, err.Error())))
+		return
+	}
+
+	bytes, err := json.Marshal(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to validate import, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+func (node *Proxy) CreateIngestionSource(w http.ResponseWriter, req *http.Request) {
+	source := &IngestionSource{}
+	if err := json.NewDecoder(req.Body).Decode(source); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to create ingestion source, %s"}`, err.Error())))
+		return
+	}
+	ctx, err := mgrAuthContext(req, source.DbName)
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to create ingestion source, %s"}`, err.Error())))
+		return
+	}
+
+	if err := globalIngestionManager.create(ctx, source); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to create ingestion source, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+func (node *Proxy) DropIngestionSource(w http.ResponseWriter, req *http.Request) {
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to drop ingestion source, %s"}`, err.Error())))
+		return
+	}
+	ctx, err := mgrAuthContext(req, "")
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to drop ingestion source, %s"}`, err.Error())))
+		return
+	}
+
+	if err := globalIngestionManager.drop(ctx, req.FormValue("name")); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to drop ingestion source, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+func (node *Proxy) ListIngestionSources(w http.ResponseWriter, req *http.Request) {
+	ctx, err := mgrAuthContext(req, "")
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list ingestion sources, %s"}`, err.Error())))
+		return
+	}
+	bytes, err := json.Marshal(globalIngestionManager.list(ctx))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list ingestion sources, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+func (node *Proxy) RegisterJobCallback(w http.ResponseWriter, req *http.Request) {
+	callback := &JobCallback{}
+	if err := json.NewDecoder(req.Body).Decode(callback); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to register job callback, %s"}`, err.Error())))
+		return
+	}
+	ctx, err := mgrAuthContext(req, callback.DbName)
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to register job callback, %s"}`, err.Error())))
+		return
+	}
+
+	if err := globalJobNotifier.register(ctx, callback); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to register job callback, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+func (node *Proxy) UnregisterJobCallback(w http.ResponseWriter, req *http.Request) {
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to unregister job callback, %s"}`, err.Error())))
+		return
+	}
+	ctx, err := mgrAuthContext(req, "")
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to unregister job callback, %s"}`, err.Error())))
+		return
+	}
+
+	if err := globalJobNotifier.unregister(ctx, req.FormValue("name")); err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to unregister job callback, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"msg": "OK"}`))
+}
+
+func (node *Proxy) ListJobCallbacks(w http.ResponseWriter, req *http.Request) {
+	ctx, err := mgrAuthContext(req, "")
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list job callbacks, %s"}`, err.Error())))
+		return
+	}
+	bytes, err := json.Marshal(globalJobNotifier.list(ctx))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list job callbacks, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// ListJobs lists the async jobs initiated through the proxies, filtered by the job_type, db_name, collection_name
+// and state if specified, the jobs of the collections the user is not allowed to describe are not listed.
+func (node *Proxy) ListJobs(w http.ResponseWriter, req *http.Request) {
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list jobs, %s"}`, err.Error())))
+		return
+	}
+	jobType, err := parseJobType(req.FormValue("job_type"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list jobs, %s"}`, err.Error())))
+		return
+	}
+	state, err := parseJobState(req.FormValue("state"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list jobs, %s"}`, err.Error())))
+		return
+	}
+	filter := &JobFilter{
+		JobType:        jobType,
+		DbName:         req.FormValue("db_name"),
+		CollectionName: req.FormValue("collection_name"),
+		State:          state,
+	}
+	if filter.CollectionName != "" {
+		filter.DbName = jobDbName(filter.DbName)
+	}
+	ctx, err := mgrAuthContext(req, "")
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list jobs, %s"}`, err.Error())))
+		return
+	}
+
+	bytes, err := json.Marshal(globalJobRegistry.list(ctx, filter))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to list jobs, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+// GetJob returns the job by the job_id, the import and compaction jobs not initiated through the proxies are
+// resolvable as well.
+func (node *Proxy) GetJob(w http.ResponseWriter, req *http.Request) {
+	err := req.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get job, %s"}`, err.Error())))
+		return
+	}
+	ctx, err := mgrAuthContext(req, "")
+	if err != nil {
+		w.WriteHeader(mgrAuthStatus(err))
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get job, %s"}`, err.Error())))
+		return
+	}
+
+	info, err := globalJobRegistry.get(ctx, req.FormValue("job_id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get job, %s"}`, err.Error())))
+		return
+	}
+	bytes, err := json.Marshal(info)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{"msg": "failed to get job, %s"}`, err.Error())))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
@@ -686,6 +686,63 @@ func (s *ProxyManagementSuite) TestCheckQueryNodeDistribution() {
 	})
 }
 
+func (s *ProxyManagementSuite) TestValidateCollectionSchema() {
+	s.Run("normal", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		cache := globalMetaCache
+		defer func() { globalMetaCache = cache }()
+		globalMetaCache = &MetaCache{}
+
+		body := `{"schema": {"name": "c1", "fields": [{"name": "pk", "is_primary_key": true, "data_type": "Int64"}, {"name": "vec", "data_type": "FloatVector"}]}}`
+		req, err := http.NewRequest(http.MethodPost, mgrValidateCollectionSchema, strings.NewReader(body))
+		s.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+		s.proxy.ValidateCollectionSchema(recorder, req)
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Contains(recorder.Body.String(), `"valid":false`)
+	})
+
+	s.Run("invalid body", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+		cache := globalMetaCache
+		defer func() { globalMetaCache = cache }()
+		globalMetaCache = &MetaCache{}
+
+		req, err := http.NewRequest(http.MethodPost, mgrValidateCollectionSchema, strings.NewReader("invalid"))
+		s.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+		s.proxy.ValidateCollectionSchema(recorder, req)
+		s.Equal(http.StatusBadRequest, recorder.Code)
+
+		req, err = http.NewRequest(http.MethodPost, mgrValidateCollectionSchema, strings.NewReader("{}"))
+		s.Require().NoError(err)
+		recorder = httptest.NewRecorder()
+		s.proxy.ValidateCollectionSchema(recorder, req)
+		s.Equal(http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func (s *ProxyManagementSuite) TestApplyCollectionSpec() {
+	s.Run("invalid body", func() {
+		s.SetupTest()
+		defer s.TearDownTest()
+
+		req, err := http.NewRequest(http.MethodPost, mgrApplyCollectionSpec, strings.NewReader("invalid"))
+		s.Require().NoError(err)
+		recorder := httptest.NewRecorder()
+		s.proxy.ApplyCollectionSpec(recorder, req)
+		s.Equal(http.StatusBadRequest, recorder.Code)
+
+		req, err = http.NewRequest(http.MethodPost, mgrApplyCollectionSpec, strings.NewReader("{}"))
+		s.Require().NoError(err)
+		recorder = httptest.NewRecorder()
+		s.proxy.ApplyCollectionSpec(recorder, req)
+		s.Equal(http.StatusInternalServerError, recorder.Code)
+	})
+}
+
 func TestProxyManagement(t *testing.T) {
 	suite.Run(t, new(ProxyManagementSuite))
 }
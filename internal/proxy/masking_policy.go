@@ -0,0 +1,349 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// MaskingMode is how the values of a masked field are rewritten.
+type MaskingMode string
+
+const (
+	// MaskingModeRedact replaces the whole value.
+	MaskingModeRedact MaskingMode = "redact"
+	// MaskingModeHash replaces the value with its sha256 digest, so the masked values are still comparable.
+	MaskingModeHash MaskingMode = "hash"
+	// MaskingModePartial keeps the last ShowLast characters.
+	MaskingModePartial MaskingMode = "partial"
+
+	// maskingPolicyPrefix is the etcd prefix(under meta root path) of the persisted masking rules.
+	maskingPolicyPrefix = "proxy/masking-policies"
+	maskingRuleAnyScope = "*"
+	maskedValue         = "***"
+)
+
+// the stricter mode wins if multiple roles of a user mask the same field
+var maskingModeStrictness = map[MaskingMode]int{
+	MaskingModePartial: 1,
+	MaskingModeHash:    2,
+	MaskingModeRedact:  3,
+}
+
+// MaskingRule masks the output field of the users granted the role.
+// Empty DBName or CollectionName means the rule applies to all of them.
+type MaskingRule struct {
+	Role           string      `json:"role"`
+	DBName         string      `json:"db_name,omitempty"`
+	CollectionName string      `json:"collection_name,omitempty"`
+	FieldName      string      `json:"field_name"`
+	Mode           MaskingMode `json:"mode"`
+	ShowLast       int         `json:"show_last,omitempty"`
+}
+
+func (r *MaskingRule) key() string {
+	return maskingRuleKey(r.Role, r.DBName, r.CollectionName, r.FieldName)
+}
+
+func (r *MaskingRule) match(dbName, collectionName string) bool {
+	return (r.DBName == "" || r.DBName == dbName) &&
+		(r.CollectionName == "" || r.CollectionName == collectionName)
+}
+
+func (r *MaskingRule) validate() error {
+	if r.Role == "" || r.FieldName == "" {
+		return merr.WrapErrParameterMissing("role and field_name", "masking rule must specify the role and field")
+	}
+	if r.CollectionName != "" && r.DBName == "" {
+		return merr.WrapErrParameterMissing("db_name", "collection scoped masking rule must specify the database")
+	}
+	if _, ok := maskingModeStrictness[r.Mode]; !ok {
+		return merr.WrapErrParameterInvalidMsg("invalid masking mode %s", r.Mode)
+	}
+	if r.Mode == MaskingModePartial && r.ShowLast <= 0 {
+		return merr.WrapErrParameterInvalidMsg("show_last must be positive for partial masking")
+	}
+	return nil
+}
+
+func maskingRuleKey(role, dbName, collectionName, fieldName string) string {
+	if dbName == "" {
+		dbName = maskingRuleAnyScope
+	}
+	if collectionName == "" {
+		collectionName = maskingRuleAnyScope
+	}
+	return path.Join(maskingPolicyPrefix, role, dbName, collectionName, fieldName)
+}
+
+// maskingPolicy keeps the masking rules, which are persisted in etcd and synchronized to all proxies.
+type maskingPolicy struct {
+	mu    sync.RWMutex
+	rules map[string]*MaskingRule
+	kv    kv.WatchKV
+}
+
+var globalMaskingPolicy = newMaskingPolicy()
+
+func newMaskingPolicy() *maskingPolicy {
+	return &maskingPolicy{
+		rules: make(map[string]*MaskingRule),
+	}
+}
+
+// init loads the persisted rules and starts watching the rule changes made by other proxies.
+func (p *maskingPolicy) init(ctx context.Context, watchKV kv.WatchKV) error {
+	p.mu.Lock()
+	p.kv = watchKV
+	p.mu.Unlock()
+
+	if err := p.reload(); err != nil {
+		return err
+	}
+	go watchPrefix(ctx, watchKV, maskingPolicyPrefix, "masking policy", p)
+	return nil
+}
+
+func (p *maskingPolicy) reload() error {
+	_, values, err := p.kv.LoadWithPrefix(maskingPolicyPrefix)
+	if err != nil {
+		return err
+	}
+	rules := make(map[string]*MaskingRule, len(values))
+	for _, value := range values {
+		rule := &MaskingRule{}
+		if err := json.Unmarshal([]byte(value), rule); err != nil {
+			log.Warn("skip invalid masking rule", zap.String("value", value), zap.Error(err))
+			continue
+		}
+		rules[rule.key()] = rule
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rules = rules
+	return nil
+}
+
+func (p *maskingPolicy) handleEvent(eventType mvccpb.Event_EventType, key, value []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch eventType {
+	case mvccpb.PUT:
+		rule := &MaskingRule{}
+		if err := json.Unmarshal(value, rule); err != nil {
+			log.Warn("skip invalid masking rule", zap.ByteString("key", key), zap.Error(err))
+			return
+		}
+		p.rules[rule.key()] = rule
+		log.Info("masking rule set", zap.Any("rule", rule))
+	case mvccpb.DELETE:
+		ruleKey, ok := trimRootPath(key, maskingPolicyPrefix)
+		if !ok {
+			return
+		}
+		delete(p.rules, ruleKey)
+		log.Info("masking rule removed", zap.String("rule", ruleKey))
+	}
+}
+
+// Set adds or replaces the rule, it will be applied to all proxies once persisted.
+func (p *maskingPolicy) Set(rule *MaskingRule) error {
+	if err := rule.validate(); err != nil {
+		return err
+	}
+	value, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.kv != nil {
+		if err := p.kv.Save(rule.key(), string(value)); err != nil {
+			return err
+		}
+	}
+	p.rules[rule.key()] = rule
+	return nil
+}
+
+// Remove removes the rule which exactly matches the given scope.
+func (p *maskingPolicy) Remove(role, dbName, collectionName, fieldName string) error {
+	key := maskingRuleKey(role, dbName, collectionName, fieldName)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.rules[key]; !ok {
+		return merr.WrapErrParameterInvalidMsg("no masking rule found for %s", key)
+	}
+	if p.kv != nil {
+		if err := p.kv.Remove(key); err != nil {
+			return err
+		}
+	}
+	delete(p.rules, key)
+	return nil
+}
+
+// RemoveRole removes all the rules of the dropped role.
+func (p *maskingPolicy) RemoveRole(role string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	keys := make([]string, 0)
+	for key, rule := range p.rules {
+		if rule.Role == role {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if p.kv != nil {
+		if err := p.kv.MultiRemove(keys); err != nil {
+			return err
+		}
+	}
+	for _, key := range keys {
+		delete(p.rules, key)
+	}
+	return nil
+}
+
+// List returns the rules of role sorted by key, all rules if role is empty.
+func (p *maskingPolicy) List(role string) []*MaskingRule {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	keys := make([]string, 0, len(p.rules))
+	for key, rule := range p.rules {
+		if role == "" || rule.Role == role {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	rules := make([]*MaskingRule, 0, len(keys))
+	for _, key := range keys {
+		rules = append(rules, p.rules[key])
+	}
+	return rules
+}
+
+// fieldRules returns the strictest rule of each field applied to the roles.
+func (p *maskingPolicy) fieldRules(roles []string, dbName, collectionName string) map[string]*MaskingRule {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.rules) == 0 {
+		return nil
+	}
+	roleSet := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		roleSet[role] = struct{}{}
+	}
+	fieldRules := make(map[string]*MaskingRule)
+	for _, rule := range p.rules {
+		if _, ok := roleSet[rule.Role]; !ok || !rule.match(dbName, collectionName) {
+			continue
+		}
+		if old, ok := fieldRules[rule.FieldName]; !ok || maskingModeStrictness[rule.Mode] > maskingModeStrictness[old.Mode] ||
+			(rule.Mode == old.Mode && rule.ShowLast < old.ShowLast) {
+			fieldRules[rule.FieldName] = rule
+		}
+	}
+	return fieldRules
+}
+
+// Apply masks the output fields data of current user in place, the root user is never masked.
+// The rules are matched against the collection name in schema, so that querying through an alias is masked as well.
+func (p *maskingPolicy) Apply(ctx context.Context, dbName string, schema *schemapb.CollectionSchema, fieldsData []*schemapb.FieldData) error {
+	p.mu.RLock()
+	empty := len(p.rules) == 0
+	p.mu.RUnlock()
+	if empty || !Params.CommonCfg.AuthorizationEnabled.GetAsBool() {
+		return nil
+	}
+	username, err := GetCurUserFromContext(ctx)
+	if err != nil || username == util.UserRoot {
+		return nil
+	}
+	roles, err := GetRole(username)
+	if err != nil {
+		return err
+	}
+	if dbName == "" {
+		dbName = util.DefaultDBName
+	}
+	fieldRules := p.fieldRules(roles, dbName, schema.GetName())
+	if len(fieldRules) == 0 {
+		return nil
+	}
+	for _, fieldData := range fieldsData {
+		if rule, ok := fieldRules[fieldData.GetFieldName()]; ok {
+			maskFieldData(fieldData, rule)
+		}
+	}
+	return nil
+}
+
+func maskFieldData(fieldData *schemapb.FieldData, rule *MaskingRule) {
+	switch fieldData.GetType() {
+	case schemapb.DataType_VarChar, schemapb.DataType_String:
+		data := fieldData.GetScalars().GetStringData().GetData()
+		for i, value := range data {
+			data[i] = maskValue(value, rule)
+		}
+	case schemapb.DataType_JSON:
+		// keep the masked value a valid json
+		data := fieldData.GetScalars().GetJsonData().GetData()
+		for i, value := range data {
+			data[i], _ = json.Marshal(maskValue(string(value), rule))
+		}
+	default:
+		log.RatedWarn(10, "masking is not supported for the field", zap.String("field", fieldData.GetFieldName()), zap.String("type", fieldData.GetType().String()))
+	}
+}
+
+func maskValue(value string, rule *MaskingRule) string {
+	switch rule.Mode {
+	case MaskingModeHash:
+		digest := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(digest[:])
+	case MaskingModePartial:
+		runes := []rune(value)
+		if len(runes) <= rule.ShowLast {
+			return strings.Repeat("*", len(runes))
+		}
+		return strings.Repeat("*", len(runes)-rule.ShowLast) + string(runes[len(runes)-rule.ShowLast:])
+	default:
+		return maskedValue
+	}
+}
@@ -0,0 +1,152 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/kv/mocks"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func newMaskingTestFieldsData() []*schemapb.FieldData {
+	return []*schemapb.FieldData{
+		{FieldName: "email", Type: schemapb.DataType_VarChar, Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"alice@example.com", "bob"}}},
+		}}},
+		{FieldName: "profile", Type: schemapb.DataType_JSON, Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_JsonData{JsonData: &schemapb.JSONArray{Data: [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`)}}},
+		}}},
+		{FieldName: "name", Type: schemapb.DataType_VarChar, Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"alice", "bob"}}},
+		}}},
+	}
+}
+
+func TestMaskingPolicy(t *testing.T) {
+	t.Run("set and remove", func(t *testing.T) {
+		p := newMaskingPolicy()
+		assert.Error(t, p.Set(&MaskingRule{Role: "r1", Mode: MaskingModeRedact}))
+		assert.Error(t, p.Set(&MaskingRule{Role: "r1", FieldName: "email", Mode: "unknown"}))
+		assert.Error(t, p.Set(&MaskingRule{Role: "r1", FieldName: "email", Mode: MaskingModePartial}))
+		assert.Error(t, p.Set(&MaskingRule{Role: "r1", CollectionName: "c1", FieldName: "email", Mode: MaskingModeRedact}))
+
+		assert.NoError(t, p.Set(&MaskingRule{Role: "r1", FieldName: "email", Mode: MaskingModeRedact}))
+		assert.NoError(t, p.Set(&MaskingRule{Role: "r2", DBName: "default", CollectionName: "c1", FieldName: "email", Mode: MaskingModePartial, ShowLast: 4}))
+		assert.Len(t, p.List(""), 2)
+		assert.Len(t, p.List("r1"), 1)
+
+		assert.NoError(t, p.Remove("r1", "", "", "email"))
+		assert.Error(t, p.Remove("r1", "", "", "email"))
+		assert.NoError(t, p.RemoveRole("r2"))
+		assert.Empty(t, p.List(""))
+	})
+
+	t.Run("field rules", func(t *testing.T) {
+		p := newMaskingPolicy()
+		assert.NoError(t, p.Set(&MaskingRule{Role: "r1", FieldName: "email", Mode: MaskingModePartial, ShowLast: 4}))
+		assert.NoError(t, p.Set(&MaskingRule{Role: "r2", DBName: "default", CollectionName: "c1", FieldName: "email", Mode: MaskingModeHash}))
+		assert.NoError(t, p.Set(&MaskingRule{Role: "r3", FieldName: "email", Mode: MaskingModePartial, ShowLast: 2}))
+
+		rules := p.fieldRules([]string{"r1"}, "default", "c1")
+		assert.Equal(t, MaskingModePartial, rules["email"].Mode)
+		rules = p.fieldRules([]string{"r1", "r2"}, "default", "c1")
+		assert.Equal(t, MaskingModeHash, rules["email"].Mode)
+		rules = p.fieldRules([]string{"r1", "r2"}, "default", "c2")
+		assert.Equal(t, MaskingModePartial, rules["email"].Mode)
+		rules = p.fieldRules([]string{"r1", "r3"}, "default", "c2")
+		assert.Equal(t, 2, rules["email"].ShowLast)
+		assert.Empty(t, p.fieldRules([]string{"public"}, "default", "c1"))
+	})
+
+	t.Run("persist", func(t *testing.T) {
+		watchKV := mocks.NewWatchKV(t)
+		rule := &MaskingRule{Role: "r1", FieldName: "email", Mode: MaskingModeRedact}
+		value, _ := json.Marshal(rule)
+		watchKV.EXPECT().LoadWithPrefix(maskingPolicyPrefix).Return([]string{rule.key()}, []string{string(value), "invalid"}, nil)
+		watchKV.EXPECT().Save(maskingRuleKey("r2", "", "", "email"), mock.Anything).Return(nil)
+		watchKV.EXPECT().MultiRemove([]string{maskingRuleKey("r2", "", "", "email")}).Return(nil)
+
+		p := newMaskingPolicy()
+		p.kv = watchKV
+		assert.NoError(t, p.reload())
+		assert.Len(t, p.List(""), 1)
+
+		assert.NoError(t, p.Set(&MaskingRule{Role: "r2", FieldName: "email", Mode: MaskingModeHash}))
+		assert.Len(t, p.List(""), 2)
+		assert.NoError(t, p.RemoveRole("r2"))
+		assert.Len(t, p.List(""), 1)
+	})
+
+	t.Run("watch event", func(t *testing.T) {
+		p := newMaskingPolicy()
+		rule := &MaskingRule{Role: "r1", FieldName: "email", Mode: MaskingModeRedact}
+		value, _ := json.Marshal(rule)
+		p.handleEvent(mvccpb.PUT, []byte("by-dev/meta/"+rule.key()), value)
+		assert.Len(t, p.List(""), 1)
+
+		p.handleEvent(mvccpb.DELETE, []byte("by-dev/meta/"+rule.key()), nil)
+		assert.Empty(t, p.List(""))
+	})
+}
+
+func TestMaskingPolicy_Apply(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetUserRole("alice").Return([]string{"analyst"}).Maybe()
+	globalMetaCache = mockCache
+	defer func() { globalMetaCache = nil }()
+
+	p := newMaskingPolicy()
+	assert.NoError(t, p.Set(&MaskingRule{Role: "analyst", FieldName: "email", Mode: MaskingModePartial, ShowLast: 4}))
+	assert.NoError(t, p.Set(&MaskingRule{Role: "analyst", FieldName: "profile", Mode: MaskingModeRedact}))
+	assert.NoError(t, p.Set(&MaskingRule{Role: "analyst", DBName: "default", CollectionName: "c2", FieldName: "name", Mode: MaskingModeRedact}))
+	schema := &schemapb.CollectionSchema{Name: "c1"}
+
+	// root is never masked
+	fieldsData := newMaskingTestFieldsData()
+	assert.NoError(t, p.Apply(GetContext(context.Background(), "root:123456"), "default", schema, fieldsData))
+	assert.Equal(t, newMaskingTestFieldsData(), fieldsData)
+
+	assert.NoError(t, p.Apply(GetContext(context.Background(), "alice:123456"), "", schema, fieldsData))
+	assert.Equal(t, []string{"*************.com", "***"}, fieldsData[0].GetScalars().GetStringData().GetData())
+	assert.Equal(t, [][]byte{[]byte(`"***"`), []byte(`"***"`)}, fieldsData[1].GetScalars().GetJsonData().GetData())
+	assert.Equal(t, []string{"alice", "bob"}, fieldsData[2].GetScalars().GetStringData().GetData())
+
+	// the collection scoped rule is matched by the collection name in schema, whatever the alias is used
+	fieldsData = newMaskingTestFieldsData()
+	assert.NoError(t, p.Apply(GetContext(context.Background(), "alice:123456"), "", &schemapb.CollectionSchema{Name: "c2"}, fieldsData))
+	assert.Equal(t, []string{"***", "***"}, fieldsData[2].GetScalars().GetStringData().GetData())
+}
+
+func TestMaskValue(t *testing.T) {
+	assert.Equal(t, maskedValue, maskValue("secret", &MaskingRule{Mode: MaskingModeRedact}))
+	assert.Equal(t, "**3456", maskValue("123456", &MaskingRule{Mode: MaskingModePartial, ShowLast: 4}))
+	assert.Equal(t, "**鉴定", maskValue("测试鉴定", &MaskingRule{Mode: MaskingModePartial, ShowLast: 2}))
+	assert.Len(t, maskValue("secret", &MaskingRule{Mode: MaskingModeHash}), 64)
+	assert.Equal(t, maskValue("secret", &MaskingRule{Mode: MaskingModeHash}), maskValue("secret", &MaskingRule{Mode: MaskingModeHash}))
+}
@@ -0,0 +1,144 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/eventlog"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/hardware"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// the stage of the queued tasks shed before scheduled.
+const taskStageQueued = "queued"
+
+// shedUnissuedTasks removes all the queued tasks and fails them with the error, returns the number of tasks shed.
+func (queue *baseTaskQueue) shedUnissuedTasks(err error) int {
+	queue.utLock.Lock()
+	shed := make([]task, 0, queue.unissuedTasks.Len())
+	for e := queue.unissuedTasks.Front(); e != nil; e = e.Next() {
+		shed = append(shed, e.Value.(task))
+	}
+	queue.unissuedTasks.Init()
+	queue.unissuedNum.Sub(int64(len(shed)))
+	queue.utLock.Unlock()
+
+	for _, t := range shed {
+		enqueueTime, _ := queue.enqueueTimes.GetAndRemove(t.ID())
+		queue.events.record(newTaskDoneEvent(t, taskStageQueued, err, time.Since(enqueueTime)))
+		t.Notify(err)
+	}
+	return len(shed)
+}
+
+// memoryUsage returns the used memory of the proxy, the larger of the rss of the process and the go heap in use,
+// and the memory limit of the container or the host.
+func memoryUsage() (uint64, uint64) {
+	stats := &runtime.MemStats{}
+	runtime.ReadMemStats(stats)
+	used := hardware.GetUsedMemoryCount()
+	if stats.HeapInuse > used {
+		used = stats.HeapInuse
+	}
+	return used, hardware.GetMemoryCount()
+}
+
+// memoryWatchdog lowers the dml and dql rates while the memory usage is above the high watermark, and restores the
+// rates once the usage falls below the low watermark. The queued searches and queries, the lowest priority requests
+// in the proxy, are shed only while the usage is above the shed watermark.
+type memoryWatchdog struct {
+	node     *Proxy
+	usage    func() (uint64, uint64)
+	shedding bool
+}
+
+func newMemoryWatchdog(node *Proxy) *memoryWatchdog {
+	return &memoryWatchdog{node: node, usage: memoryUsage}
+}
+
+func (w *memoryWatchdog) check() {
+	if !Params.ProxyCfg.MemoryWatchdogEnabled.GetAsBool() {
+		if w.shedding {
+			w.setShedding(false, 0)
+		}
+		return
+	}
+	used, total := w.usage()
+	if total == 0 {
+		return
+	}
+	ratio := float64(used) / float64(total)
+	if ratio >= Params.ProxyCfg.MemoryWatchdogHighWatermark.GetAsFloat() {
+		if !w.shedding {
+			w.setShedding(true, ratio)
+		}
+		if ratio >= Params.ProxyCfg.MemoryWatchdogShedWatermark.GetAsFloat() {
+			w.shed(used, total)
+		}
+	} else if w.shedding && ratio < Params.ProxyCfg.MemoryWatchdogLowWatermark.GetAsFloat() {
+		w.setShedding(false, ratio)
+	}
+}
+
+func (w *memoryWatchdog) shed(used, total uint64) {
+	if w.node.sched == nil {
+		return
+	}
+	err := merr.WrapErrServiceMemoryLimitExceeded(float32(used), float32(total), "the proxy sheds the queued requests under memory pressure")
+	if n := w.node.sched.dqQueue.shedUnissuedTasks(err); n > 0 {
+		log.Warn("shed the queued tasks under memory pressure", zap.Int("tasks", n), zap.Uint64("used", used), zap.Uint64("total", total))
+		metrics.ProxyMemoryShedTaskCount.WithLabelValues(paramtable.GetStringNodeID()).Add(float64(n))
+	}
+}
+
+func (w *memoryWatchdog) setShedding(shedding bool, ratio float64) {
+	w.shedding = shedding
+	factor, state, gauge := 1.0, "stops", 0.0
+	if shedding {
+		factor, state, gauge = Params.ProxyCfg.MemoryWatchdogRateFactor.GetAsFloat(), "starts", 1.0
+	}
+	if w.node.simpleLimiter != nil {
+		w.node.simpleLimiter.SetEmergencyFactor(factor)
+	}
+	msg := fmt.Sprintf("proxy %s shedding under memory pressure, memory usage %.2f, rate factor %.2f", state, ratio, factor)
+	log.Warn(msg)
+	eventlog.Record(eventlog.NewRawEvt(eventlog.Level_Warn, msg))
+	metrics.ProxyMemoryShedding.WithLabelValues(paramtable.GetStringNodeID()).Set(gauge)
+}
+
+func (node *Proxy) memoryWatchdogLoop() {
+	defer node.wg.Done()
+	watchdog := newMemoryWatchdog(node)
+	ticker := time.NewTicker(Params.ProxyCfg.MemoryWatchdogInterval.GetAsDuration(time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-node.ctx.Done():
+			return
+		case <-ticker.C:
+			watchdog.check()
+		}
+	}
+}
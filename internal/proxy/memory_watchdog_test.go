@@ -0,0 +1,128 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/ratelimitutil"
+)
+
+func newQueuedSearchTask(id int64) *searchTask {
+	return &searchTask{
+		Condition:     NewTaskCondition(context.Background()),
+		SearchRequest: &internalpb.SearchRequest{Base: &commonpb.MsgBase{MsgID: id}},
+	}
+}
+
+func TestShedUnissuedTasks(t *testing.T) {
+	paramtable.Init()
+	queue := newDqTaskQueue(nil)
+	tasks := []*searchTask{newQueuedSearchTask(1), newQueuedSearchTask(2)}
+	for _, task := range tasks {
+		assert.NoError(t, queue.addUnissuedTask(task))
+	}
+
+	err := merr.WrapErrServiceMemoryLimitExceeded(10, 8)
+	assert.Equal(t, 2, queue.shedUnissuedTasks(err))
+	assert.True(t, queue.utEmpty())
+	for _, task := range tasks {
+		assert.ErrorIs(t, task.WaitToFinish(), merr.ErrServiceMemoryLimitExceeded)
+	}
+	assert.Equal(t, 0, queue.shedUnissuedTasks(err))
+}
+
+func TestSimpleLimiterEmergencyFactor(t *testing.T) {
+	paramtable.Init()
+	limiter := NewSimpleLimiter()
+	search, ok := limiter.rateLimiter.GetRootLimiters().GetLimiters().Get(internalpb.RateType_DQLSearch)
+	assert.True(t, ok)
+	search.SetLimit(100)
+	ddl, _ := limiter.rateLimiter.GetRootLimiters().GetLimiters().Get(internalpb.RateType_DDLCollection)
+	ddl.SetLimit(10)
+
+	limiter.SetEmergencyFactor(0.5)
+	assert.Equal(t, ratelimitutil.Limit(50), search.Limit())
+	assert.Equal(t, ratelimitutil.Limit(10), ddl.Limit())
+	assert.Equal(t, float64(50), limiter.GetRootRates().Rates[internalpb.RateType_DQLSearch.String()])
+	// not scaled twice
+	limiter.SetEmergencyFactor(0.5)
+	assert.Equal(t, ratelimitutil.Limit(50), search.Limit())
+
+	limiter.SetEmergencyFactor(1)
+	assert.Equal(t, ratelimitutil.Limit(100), search.Limit())
+}
+
+func TestMemoryWatchdog(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(Params.ProxyCfg.MemoryWatchdogEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.MemoryWatchdogEnabled.Key)
+
+	node := &Proxy{
+		simpleLimiter: NewSimpleLimiter(),
+		sched:         &taskScheduler{dqQueue: newDqTaskQueue(nil)},
+	}
+	search, _ := node.simpleLimiter.rateLimiter.GetRootLimiters().GetLimiters().Get(internalpb.RateType_DQLSearch)
+	search.SetLimit(100)
+	queued := newQueuedSearchTask(1)
+	assert.NoError(t, node.sched.dqQueue.addUnissuedTask(queued))
+
+	used := uint64(50)
+	watchdog := newMemoryWatchdog(node)
+	watchdog.usage = func() (uint64, uint64) { return used, 100 }
+	watchdog.check()
+	assert.False(t, watchdog.shedding)
+	assert.False(t, node.sched.dqQueue.utEmpty())
+
+	// lowers the rates without shedding the queued tasks below the shed watermark
+	used = 92
+	watchdog.check()
+	assert.True(t, watchdog.shedding)
+	assert.False(t, node.sched.dqQueue.utEmpty())
+	assert.Equal(t, ratelimitutil.Limit(100*Params.ProxyCfg.MemoryWatchdogRateFactor.GetAsFloat()), search.Limit())
+
+	used = 96
+	watchdog.check()
+	assert.True(t, watchdog.shedding)
+	assert.True(t, node.sched.dqQueue.utEmpty())
+	assert.ErrorIs(t, queued.WaitToFinish(), merr.ErrServiceMemoryLimitExceeded)
+	assert.Equal(t, ratelimitutil.Limit(100*Params.ProxyCfg.MemoryWatchdogRateFactor.GetAsFloat()), search.Limit())
+
+	// keeps shedding between the watermarks
+	used = 85
+	watchdog.check()
+	assert.True(t, watchdog.shedding)
+
+	used = 70
+	watchdog.check()
+	assert.False(t, watchdog.shedding)
+	assert.Equal(t, ratelimitutil.Limit(100), search.Limit())
+
+	used = 95
+	watchdog.check()
+	paramtable.Get().Save(Params.ProxyCfg.MemoryWatchdogEnabled.Key, "false")
+	watchdog.check()
+	assert.False(t, watchdog.shedding)
+	assert.Equal(t, ratelimitutil.Limit(100), search.Limit())
+}
@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/samber/lo"
@@ -71,6 +72,8 @@ type Cache interface {
 	GetPartitionsIndex(ctx context.Context, database, collectionName string) ([]string, error)
 	// GetCollectionSchema get collection's schema.
 	GetCollectionSchema(ctx context.Context, database, collectionName string) (*schemaInfo, error)
+	// GetAliasInfo resolves the name to the collection and lists the aliases of the collection for the privilege check.
+	GetAliasInfo(ctx context.Context, database, name string) (*aliasInfo, error)
 	GetShards(ctx context.Context, withCache bool, database, collectionName string, collectionID int64) (map[string][]nodeInfo, error)
 	DeprecateShardCache(database, collectionName string)
 	RemoveCollection(ctx context.Context, database, collectionName string)
@@ -107,6 +110,7 @@ type collectionInfo struct {
 	createdTimestamp    uint64
 	createdUtcTimestamp uint64
 	consistencyLevel    commonpb.ConsistencyLevel
+	updateTime          time.Time // when the info is cached
 }
 
 type databaseInfo struct {
@@ -114,6 +118,20 @@ type databaseInfo struct {
 	createdTimestamp uint64
 }
 
+// aliasInfo is the alias resolution of a name, collectionName is empty if the name doesn't exist.
+// The creation of alias doesn't expire the cache, so it's cached only for aliasInfoTTL,
+// and the names not found are cached as well, so the denied requests can't hammer the rootcoord.
+type aliasInfo struct {
+	collectionName string
+	aliases        []string
+	updateTime     time.Time
+}
+
+const (
+	aliasInfoTTL      = 30 * time.Second
+	aliasInfoCapacity = 10000
+)
+
 // schemaInfo is a helper function wraps *schemapb.CollectionSchema
 // with extra fields mapping and methods
 type schemaInfo struct {
@@ -122,6 +140,9 @@ type schemaInfo struct {
 	hasPartitionKeyField bool
 	pkField              *schemapb.FieldSchema
 	schemaHelper         *typeutil.SchemaHelper
+	version              string // stamped in the search, query and insert requests
+	// partitionStats is the clustering key statistics of the partitions, to prune the partitions of the reads
+	partitionStats *partitionStatsCache
 }
 
 func newSchemaInfo(schema *schemapb.CollectionSchema) *schemaInfo {
@@ -145,6 +166,8 @@ func newSchemaInfo(schema *schemapb.CollectionSchema) *schemaInfo {
 		hasPartitionKeyField: hasPartitionkey,
 		pkField:              pkField,
 		schemaHelper:         schemaHelper,
+		version:              typeutil.SchemaVersion(schema),
+		partitionStats:       newPartitionStatsCache(),
 	}
 }
 
@@ -156,6 +179,11 @@ func (s *schemaInfo) IsPartitionKeyCollection() bool {
 	return s.hasPartitionKeyField
 }
 
+// IsReadOnly returns whether the collection rejects DML requests.
+func (s *schemaInfo) IsReadOnly() bool {
+	return common.IsCollectionReadOnly(s.GetProperties()...)
+}
+
 func (s *schemaInfo) GetPkField() (*schemapb.FieldSchema, error) {
 	if s.pkField == nil {
 		return nil, merr.WrapErrServiceInternal("pk field not found")
@@ -200,6 +228,9 @@ func (info *collectionInfo) isCollectionCached() bool {
 type shardLeaders struct {
 	idx        *atomic.Int64
 	deprecated *atomic.Bool
+	updateTime time.Time // when the leaders are cached
+	// partial is set if some shards are partially loaded, the leaders of which are cached shortly.
+	partial bool
 
 	shardLeaders map[string][]nodeInfo
 }
@@ -251,6 +282,9 @@ type MetaCache struct {
 
 	dbInfo           map[string]*databaseInfo                // database -> db_info
 	collInfo         map[string]map[string]*collectionInfo   // database -> collectionName -> collection_info
+	collAlias        map[string]map[string]string            // database -> alias -> collectionName
+	aliasInfos       map[string]map[string]*aliasInfo        // database -> name -> alias resolution
+	aliasInfoNum     int                                     // number of the alias resolutions
 	collLeader       map[string]map[string]*shardLeaders     // database -> collectionName -> collection_leaders
 	dbCollectionInfo map[string]map[typeutil.UniqueID]string // database -> collectionID -> collectionName
 	credMap          map[string]*internalpb.CredentialInfo   // cache for credential, lazy load
@@ -262,6 +296,7 @@ type MetaCache struct {
 	shardMgr         shardClientMgr
 	sfGlobal         conc.Singleflight[*collectionInfo]
 	sfDB             conc.Singleflight[*databaseInfo]
+	sfAlias          conc.Singleflight[*aliasInfo]
 
 	IDStart int64
 	IDCount int64
@@ -298,6 +333,8 @@ func NewMetaCache(rootCoord types.RootCoordClient, queryCoord types.QueryCoordCl
 		queryCoord:       queryCoord,
 		dbInfo:           map[string]*databaseInfo{},
 		collInfo:         map[string]map[string]*collectionInfo{},
+		collAlias:        map[string]map[string]string{},
+		aliasInfos:       map[string]map[string]*aliasInfo{},
 		collLeader:       map[string]map[string]*shardLeaders{},
 		dbCollectionInfo: map[string]map[typeutil.UniqueID]string{},
 		credMap:          map[string]*internalpb.CredentialInfo{},
@@ -322,9 +359,18 @@ func (m *MetaCache) getCollection(database, collectionName string, collectionID
 			}
 		}
 	} else {
+		if isRecycledCollection(collectionName) {
+			return nil, false
+		}
 		if collection, ok := db[collectionName]; ok {
 			return collection, collection.isCollectionCached()
 		}
+		// access through alias shares the cache of the aliased collection
+		if name, ok := m.collAlias[database][collectionName]; ok {
+			if collection, ok := db[name]; ok {
+				return collection, collection.isCollectionCached()
+			}
+		}
 	}
 
 	return nil, false
@@ -349,6 +395,10 @@ func (m *MetaCache) update(ctx context.Context, database, collectionName string,
 	if collInfo, ok := m.getCollection(database, collectionName, collectionID); ok {
 		return collInfo, nil
 	}
+	// the collections in recycle bin are hidden from the requests by name
+	if collectionName != "" && isRecycledCollection(collectionName) {
+		return nil, merr.WrapErrCollectionNotFoundWithDB(database, collectionName)
+	}
 
 	collection, err := m.describeCollection(ctx, database, collectionName, collectionID)
 	if err != nil {
@@ -374,6 +424,7 @@ func (m *MetaCache) update(ctx context.Context, database, collectionName string,
 		}
 	})
 
+	alias := collectionName
 	collectionName = collection.Schema.GetName()
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -381,6 +432,12 @@ func (m *MetaCache) update(ctx context.Context, database, collectionName string,
 	if !dbOk {
 		m.collInfo[database] = make(map[string]*collectionInfo)
 	}
+	if alias != "" && alias != collectionName {
+		if _, ok := m.collAlias[database]; !ok {
+			m.collAlias[database] = make(map[string]string)
+		}
+		m.collAlias[database][alias] = collectionName
+	}
 
 	schemaInfo := newSchemaInfo(collection.Schema)
 	m.collInfo[database][collectionName] = &collectionInfo{
@@ -390,6 +447,7 @@ func (m *MetaCache) update(ctx context.Context, database, collectionName string,
 		createdTimestamp:    collection.CreatedTimestamp,
 		createdUtcTimestamp: collection.CreatedUtcTimestamp,
 		consistencyLevel:    collection.ConsistencyLevel,
+		updateTime:          time.Now(),
 	}
 
 	log.Info("meta update success", zap.String("database", database), zap.String("collectionName", collectionName), zap.Int64("collectionID", collection.CollectionID))
@@ -615,6 +673,54 @@ func (m *MetaCache) GetCollectionSchema(ctx context.Context, database, collectio
 	return collInfo.schema, nil
 }
 
+func (m *MetaCache) GetAliasInfo(ctx context.Context, database, name string) (*aliasInfo, error) {
+	m.mu.RLock()
+	info, ok := m.aliasInfos[database][name]
+	m.mu.RUnlock()
+	if ok && time.Since(info.updateTime) < aliasInfoTTL {
+		return info, nil
+	}
+
+	info, err, _ := m.sfAlias.Do(buildSfKeyByName(database, name), func() (*aliasInfo, error) {
+		info := &aliasInfo{updateTime: time.Now()}
+		schema, err := m.GetCollectionSchema(ctx, database, name)
+		if err != nil {
+			if !errors.Is(err, merr.ErrCollectionNotFound) {
+				return nil, err
+			}
+		} else {
+			info.collectionName = schema.GetName()
+		}
+		if info.collectionName == name {
+			resp, err := m.rootCoord.ListAliases(ctx, &milvuspb.ListAliasesRequest{
+				Base:           commonpbutil.NewMsgBase(commonpbutil.WithMsgType(commonpb.MsgType_ListAliases)),
+				DbName:         database,
+				CollectionName: name,
+			})
+			if err := merr.CheckRPCCall(resp, err); err != nil {
+				return nil, err
+			}
+			info.aliases = resp.GetAliases()
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.aliasInfoNum >= aliasInfoCapacity {
+			m.aliasInfos = make(map[string]map[string]*aliasInfo)
+			m.aliasInfoNum = 0
+		}
+		if _, ok := m.aliasInfos[database]; !ok {
+			m.aliasInfos[database] = make(map[string]*aliasInfo)
+		}
+		if _, ok := m.aliasInfos[database][name]; !ok {
+			m.aliasInfoNum++
+		}
+		m.aliasInfos[database][name] = info
+		return info, nil
+	})
+	return info, err
+}
+
 func (m *MetaCache) GetPartitionID(ctx context.Context, database, collectionName string, partitionName string) (typeutil.UniqueID, error) {
 	partInfo, err := m.GetPartitionInfo(ctx, database, collectionName, partitionName)
 	if err != nil {
@@ -703,6 +809,8 @@ func (m *MetaCache) describeCollection(ctx context.Context, database, collection
 			AutoID:             coll.Schema.AutoID,
 			Fields:             make([]*schemapb.FieldSchema, 0),
 			EnableDynamicField: coll.Schema.EnableDynamicField,
+			// collection properties are cached along with the schema
+			Properties: coll.Properties,
 		},
 		CollectionID:         coll.CollectionID,
 		VirtualChannelNames:  coll.VirtualChannelNames,
@@ -802,15 +910,24 @@ func parsePartitionsInfo(infos []*partitionInfo, hasPartitionKey bool) *partitio
 	return result
 }
 
+// RemoveCollection removes the collection from cache, if collectionName is an alias,
+// only the alias is removed so that the re-pointed alias is resolved again without evicting the collection.
 func (m *MetaCache) RemoveCollection(ctx context.Context, database, collectionName string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.removeAliasInfosLocked(database, collectionName)
+	if _, ok := m.collAlias[database][collectionName]; ok {
+		delete(m.collAlias[database], collectionName)
+		return
+	}
 	_, dbOk := m.collInfo[database]
 	if dbOk {
 		delete(m.collInfo[database], collectionName)
 	}
+	m.removeAliasesLocked(database, collectionName)
 }
 
+// RemoveCollectionsByID removes the collection from cache, returns the removed collection names and aliases.
 func (m *MetaCache) RemoveCollectionsByID(ctx context.Context, collectionID UniqueID) []string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -820,12 +937,36 @@ func (m *MetaCache) RemoveCollectionsByID(ctx context.Context, collectionID Uniq
 			if v.collID == collectionID {
 				delete(m.collInfo[database], k)
 				collNames = append(collNames, k)
+				collNames = append(collNames, m.removeAliasesLocked(database, k)...)
+				m.removeAliasInfosLocked(database, k)
 			}
 		}
 	}
 	return collNames
 }
 
+// removeAliasInfosLocked removes the alias resolutions of the name, and the ones resolved to or listing the name.
+func (m *MetaCache) removeAliasInfosLocked(database, name string) {
+	for key, info := range m.aliasInfos[database] {
+		if key == name || info.collectionName == name || lo.Contains(info.aliases, name) {
+			delete(m.aliasInfos[database], key)
+			m.aliasInfoNum--
+		}
+	}
+}
+
+// removeAliasesLocked removes the aliases of the collection, returns the removed aliases.
+func (m *MetaCache) removeAliasesLocked(database, collectionName string) []string {
+	var aliases []string
+	for alias, name := range m.collAlias[database] {
+		if name == collectionName {
+			delete(m.collAlias[database], alias)
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
 func (m *MetaCache) RemovePartition(ctx context.Context, database, collectionName, partitionName string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -916,7 +1057,7 @@ func (m *MetaCache) GetShards(ctx context.Context, withCache bool, database, col
 
 	cacheShardLeaders, ok := m.getCollectionShardLeader(database, collectionName)
 	if withCache {
-		if ok {
+		if ok && !(cacheShardLeaders.partial && time.Since(cacheShardLeaders.updateTime) > partialShardLeadersTTL) {
 			metrics.ProxyCacheStatsCounter.WithLabelValues(fmt.Sprint(paramtable.GetNodeID()), method, metrics.CacheHitLabel).Inc()
 			iterator := cacheShardLeaders.GetReader()
 			return iterator.Shuffle(), nil
@@ -947,6 +1088,8 @@ func (m *MetaCache) GetShards(ctx context.Context, withCache bool, database, col
 		shardLeaders: shards,
 		deprecated:   atomic.NewBool(false),
 		idx:          atomic.NewInt64(0),
+		updateTime:   time.Now(),
+		partial:      updateShardCoverage(info.collID, resp.GetStatus()),
 	}
 
 	// lock leader
@@ -1105,6 +1248,9 @@ func (m *MetaCache) RemoveDatabase(ctx context.Context, database string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	delete(m.collInfo, database)
+	delete(m.collAlias, database)
+	m.aliasInfoNum -= len(m.aliasInfos[database])
+	delete(m.aliasInfos, database)
 	delete(m.dbInfo, database)
 }
 
@@ -761,6 +761,86 @@ func TestMetaCache_RemoveCollection(t *testing.T) {
 	assert.Equal(t, rootCoord.GetAccessCount(), 4)
 }
 
+func TestMetaCache_Alias(t *testing.T) {
+	ctx := context.Background()
+	rootCoord := mocks.NewMockRootCoordClient(t)
+	cache, err := NewMetaCache(rootCoord, nil, nil)
+	assert.NoError(t, err)
+
+	describe := func(collectionID int64, collectionName string) {
+		rootCoord.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+			Status:       merr.Success(),
+			CollectionID: collectionID,
+			Schema:       &schemapb.CollectionSchema{Name: collectionName},
+		}, nil).Once()
+		rootCoord.EXPECT().ShowPartitions(mock.Anything, mock.Anything).Return(&milvuspb.ShowPartitionsResponse{
+			Status: merr.Success(),
+		}, nil).Once()
+	}
+
+	describe(1, "collection1")
+	collectionID, err := cache.GetCollectionID(ctx, dbName, "alias1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, collectionID)
+
+	// both the alias and the collection hit the cache
+	collectionID, err = cache.GetCollectionID(ctx, dbName, "alias1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, collectionID)
+	collectionID, err = cache.GetCollectionID(ctx, dbName, "collection1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, collectionID)
+
+	// alias re-pointed, only the alias is invalidated
+	cache.RemoveCollection(ctx, dbName, "alias1")
+	collectionID, err = cache.GetCollectionID(ctx, dbName, "collection1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, collectionID)
+
+	describe(2, "collection2")
+	collectionID, err = cache.GetCollectionID(ctx, dbName, "alias1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, collectionID)
+
+	removed := cache.RemoveCollectionsByID(ctx, 2)
+	assert.ElementsMatch(t, []string{"collection2", "alias1"}, removed)
+
+	rootCoord.EXPECT().ListAliases(mock.Anything, mock.Anything).Return(&milvuspb.ListAliasesResponse{
+		Status:  merr.Success(),
+		Aliases: []string{"alias2"},
+	}, nil).Once()
+	info, err := cache.GetAliasInfo(ctx, dbName, "collection1")
+	assert.NoError(t, err)
+	assert.Equal(t, "collection1", info.collectionName)
+	assert.Equal(t, []string{"alias2"}, info.aliases)
+	// the alias resolution is cached
+	info, err = cache.GetAliasInfo(ctx, dbName, "collection1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alias2"}, info.aliases)
+
+	// the name not found is cached as well
+	rootCoord.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+		Status: merr.Status(merr.WrapErrCollectionNotFound("collection3")),
+	}, nil).Once()
+	for i := 0; i < 2; i++ {
+		info, err = cache.GetAliasInfo(ctx, dbName, "collection3")
+		assert.NoError(t, err)
+		assert.Empty(t, info.collectionName)
+	}
+
+	// dropping the alias expires the resolution listing it
+	cache.RemoveCollection(ctx, dbName, "alias2")
+	rootCoord.EXPECT().ListAliases(mock.Anything, mock.Anything).Return(&milvuspb.ListAliasesResponse{
+		Status: merr.Success(),
+	}, nil).Once()
+	info, err = cache.GetAliasInfo(ctx, dbName, "collection1")
+	assert.NoError(t, err)
+	assert.Empty(t, info.aliases)
+
+	cache.RemoveDatabase(ctx, dbName)
+	assert.Equal(t, 0, cache.aliasInfoNum)
+}
+
 func TestGlobalMetaCache_ShuffleShardLeaders(t *testing.T) {
 	shards := map[string][]nodeInfo{
 		"channel-1": {
@@ -77,8 +77,9 @@ func getQuotaMetrics() (*metricsinfo.ProxyQuotaMetrics, error) {
 		return nil, err
 	}
 	return &metricsinfo.ProxyQuotaMetrics{
-		Hms: metricsinfo.HardwareMetrics{},
-		Rms: rms,
+		Hms:                 metricsinfo.HardwareMetrics{},
+		Rms:                 rms,
+		CollectionLastReads: globalCollectionReads.snapshot(),
 	}, nil
 }
 
@@ -0,0 +1,114 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/contextutil"
+	"github.com/milvus-io/milvus/pkg/util/crypto"
+)
+
+// mgrAuthContext authenticates the management request in the same way as the sdk requests, by the Authorization
+// header of "Bearer username:password", "Bearer apikey" or the basic auth. The returned context carries the identity,
+// the database and the client address, so checkMgrPrivilege checks it as the PrivilegeInterceptor does.
+func mgrAuthContext(req *http.Request, dbName string) (context.Context, error) {
+	md := metadata.MD{}
+	if username, password, ok := req.BasicAuth(); ok {
+		md.Set(util.HeaderAuthorize, crypto.Base64Encode(username+util.CredentialSeperator+password))
+	} else if token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "); token != "" {
+		md.Set(util.HeaderAuthorize, crypto.Base64Encode(token))
+	}
+	if dbName != "" {
+		md.Set(util.HeaderDBName, dbName)
+	}
+
+	ctx := metadata.NewIncomingContext(req.Context(), md)
+	if host, port, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		if ip := net.ParseIP(host); ip != nil {
+			portNum, _ := strconv.Atoi(port)
+			ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: ip, Port: portNum}})
+		}
+	}
+	return AuthenticationInterceptor(ctx)
+}
+
+// checkMgrPrivilege checks the privileges of the user in ctx for the requests, which are the milvus requests the
+// management operation is equivalent to.
+func checkMgrPrivilege(ctx context.Context, reqs ...any) error {
+	for _, req := range reqs {
+		if _, err := PrivilegeInterceptor(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkMgrAdmin allows only the root and the users of the admin role, for the management operations affecting the
+// whole cluster which no privilege covers.
+func checkMgrAdmin(ctx context.Context) error {
+	if !Params.CommonCfg.AuthorizationEnabled.GetAsBool() {
+		return nil
+	}
+	username, err := contextutil.GetCurUserFromContext(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	isAdmin, err := IsAdminUser(username)
+	if err != nil {
+		return err
+	}
+	if isAdmin {
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, fmt.Sprintf("permission deny to %s, only the admin is allowed", username))
+}
+
+// mgrAuthAdmin authenticates the management request and checks the user is the admin.
+func mgrAuthAdmin(req *http.Request) (context.Context, error) {
+	ctx, err := mgrAuthContext(req, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMgrAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// mgrAuthStatus returns the http status of the authentication or the privilege check error.
+func mgrAuthStatus(err error) int {
+	switch status.Code(err) {
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,119 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/milvus-io/milvus/pkg/util"
+	"github.com/milvus-io/milvus/pkg/util/crypto"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestMgrAuthContext(t *testing.T) {
+	paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "false")
+	defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	_, err := mgrAuthContext(req, "")
+	assert.Error(t, err)
+
+	cache := globalMetaCache
+	globalMetaCache = &MetaCache{}
+	defer func() { globalMetaCache = cache }()
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.SetBasicAuth("user", "pwd")
+	ctx, err := mgrAuthContext(req, "db1")
+	assert.NoError(t, err)
+	md, ok := metadata.FromIncomingContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, []string{crypto.Base64Encode("user" + util.CredentialSeperator + "pwd")}, md.Get(util.HeaderAuthorize))
+	assert.Equal(t, []string{"db1"}, md.Get(util.HeaderDBName))
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer user:pwd")
+	ctx, err = mgrAuthContext(req, "")
+	assert.NoError(t, err)
+	md, _ = metadata.FromIncomingContext(ctx)
+	assert.Equal(t, []string{crypto.Base64Encode("user:pwd")}, md.Get(util.HeaderAuthorize))
+	assert.Empty(t, md.Get(util.HeaderDBName))
+
+	assert.NoError(t, checkMgrAdmin(ctx))
+}
+
+func TestMgrAuthStatus(t *testing.T) {
+	assert.Equal(t, http.StatusUnauthorized, mgrAuthStatus(status.Error(codes.Unauthenticated, "")))
+	assert.Equal(t, http.StatusForbidden, mgrAuthStatus(status.Error(codes.PermissionDenied, "")))
+	assert.Equal(t, http.StatusInternalServerError, mgrAuthStatus(errors.New("mock")))
+}
+
+func TestMgrAuthAdmin(t *testing.T) {
+	paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "false")
+	defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	globalMetaCache = nil
+	_, err := mgrAuthAdmin(httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Error(t, err)
+
+	globalMetaCache = &MetaCache{}
+	_, err = mgrAuthAdmin(httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.NoError(t, err)
+}
+
+func TestMgrRoutesUnauthenticated(t *testing.T) {
+	paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	globalMetaCache = &MetaCache{}
+
+	node := &Proxy{}
+	routes := map[string]http.HandlerFunc{
+		mgrSuspendOperations:                         node.SuspendOperations,
+		mgrResumeOperations:                          node.ResumeOperations,
+		mgrListSuspendRules:                          node.ListSuspendRules,
+		mgrValidateCollectionSchema:                  node.ValidateCollectionSchema,
+		mgrGetUsage:                                  node.GetUsage,
+		mgrListDroppedCollections:                    node.ListDroppedCollections,
+		mgrRestoreCollection + "?collection_name=c1": node.RestoreCollection,
+		mgrSetMaskingRule:                            node.SetMaskingRule,
+		mgrRemoveMaskingRule:                         node.RemoveMaskingRule,
+		mgrListMaskingRules:                          node.ListMaskingRules,
+		mgrGetRates:                                  node.GetRates,
+		mgrDumpState:                                 node.DumpState,
+	}
+	for path, handler := range routes {
+		t.Run(path, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler(recorder, httptest.NewRequest(http.MethodPost, path, strings.NewReader("{}")))
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		})
+	}
+}
@@ -110,6 +110,62 @@ func (_c *MockCache_DeprecateShardCache_Call) RunAndReturn(run func(string, stri
 	return _c
 }
 
+// GetAliasInfo provides a mock function with given fields: ctx, database, name
+func (_m *MockCache) GetAliasInfo(ctx context.Context, database string, name string) (*aliasInfo, error) {
+	ret := _m.Called(ctx, database, name)
+
+	var r0 *aliasInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*aliasInfo, error)); ok {
+		return rf(ctx, database, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *aliasInfo); ok {
+		r0 = rf(ctx, database, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*aliasInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, database, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCache_GetAliasInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAliasInfo'
+type MockCache_GetAliasInfo_Call struct {
+	*mock.Call
+}
+
+// GetAliasInfo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - database string
+//   - name string
+func (_e *MockCache_Expecter) GetAliasInfo(ctx interface{}, database interface{}, name interface{}) *MockCache_GetAliasInfo_Call {
+	return &MockCache_GetAliasInfo_Call{Call: _e.mock.On("GetAliasInfo", ctx, database, name)}
+}
+
+func (_c *MockCache_GetAliasInfo_Call) Run(run func(ctx context.Context, database string, name string)) *MockCache_GetAliasInfo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockCache_GetAliasInfo_Call) Return(_a0 *aliasInfo, _a1 error) *MockCache_GetAliasInfo_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCache_GetAliasInfo_Call) RunAndReturn(run func(context.Context, string, string) (*aliasInfo, error)) *MockCache_GetAliasInfo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCollectionID provides a mock function with given fields: ctx, database, collectionName
 func (_m *MockCache) GetCollectionID(ctx context.Context, database string, collectionName string) (int64, error) {
 	ret := _m.Called(ctx, database, collectionName)
@@ -80,6 +80,10 @@ func (tso *mockTsoAllocator) AllocOne(ctx context.Context) (Timestamp, error) {
 	return (physical << 18) + uint64(tso.logicPart), nil
 }
 
+func (tso *mockTsoAllocator) AllocCached(ctx context.Context, maxStaleness time.Duration) (Timestamp, error) {
+	return tso.AllocOne(ctx)
+}
+
 func newMockTsoAllocator() tsoAllocator {
 	return &mockTsoAllocator{}
 }
@@ -110,6 +114,10 @@ func (m *mockTask) CanSkipAllocTimestamp() bool {
 	return false
 }
 
+func (m *mockTask) CanUseCachedTimestamp() bool {
+	return false
+}
+
 func (m *mockTask) TraceCtx() context.Context {
 	return m.TaskCondition.ctx
 }
@@ -64,6 +64,7 @@ func genInsertMsgsByPartition(ctx context.Context,
 			ShardName:      channelName,
 			Version:        msgpb.InsertDataVersion_ColumnBased,
 		}
+		copySchemaVersion(insertReq.Base, insertMsg.GetBase())
 		insertReq.FieldsData = make([]*schemapb.FieldData, len(insertMsg.GetFieldsData()))
 
 		msg := &msgstream.InsertMsg{
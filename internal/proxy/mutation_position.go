@@ -0,0 +1,93 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// channelPositionsKey is set in the extra info of the mutation result status,
+// the value is the json of the positions the mutation is published at by vchannel.
+const channelPositionsKey = "channel_positions"
+
+// channelPosition is the json format of the position in the mutation result,
+// the msg id is encoded in base64.
+type channelPosition struct {
+	Channel   string `json:"channel"`
+	MsgID     []byte `json:"msg_id"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// collectChannelPositions returns the last positions the dml messages are published at by vchannel.
+func collectChannelPositions(msgs []msgstream.TsMsg) map[string]*msgpb.MsgPosition {
+	positions := make(map[string]*msgpb.MsgPosition)
+	for _, msg := range msgs {
+		var vchannel string
+		switch msg := msg.(type) {
+		case *msgstream.InsertMsg:
+			vchannel = msg.GetShardName()
+		case *msgstream.DeleteMsg:
+			vchannel = msg.GetShardName()
+		}
+		mergeChannelPosition(positions, vchannel, msg.Position())
+	}
+	return positions
+}
+
+// mergeChannelPositions merges the positions of src into dst, the later positions are kept.
+func mergeChannelPositions(dst, src map[string]*msgpb.MsgPosition) {
+	for vchannel, position := range src {
+		mergeChannelPosition(dst, vchannel, position)
+	}
+}
+
+func mergeChannelPosition(positions map[string]*msgpb.MsgPosition, vchannel string, position *msgpb.MsgPosition) {
+	if vchannel == "" || position == nil {
+		return
+	}
+	if old, ok := positions[vchannel]; !ok || old.GetTimestamp() <= position.GetTimestamp() {
+		positions[vchannel] = position
+	}
+}
+
+// setChannelPositions sets the positions in the successful mutation result status.
+func setChannelPositions(status *commonpb.Status, positions map[string]*msgpb.MsgPosition) {
+	if len(positions) == 0 || !merr.Ok(status) {
+		return
+	}
+	result := make(map[string]channelPosition, len(positions))
+	for vchannel, position := range positions {
+		result[vchannel] = channelPosition{
+			Channel:   position.GetChannelName(),
+			MsgID:     position.GetMsgID(),
+			Timestamp: position.GetTimestamp(),
+		}
+	}
+	bs, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	if status.ExtraInfo == nil {
+		status.ExtraInfo = make(map[string]string)
+	}
+	status.ExtraInfo[channelPositionsKey] = string(bs)
+}
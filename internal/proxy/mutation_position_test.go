@@ -0,0 +1,59 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestChannelPositions(t *testing.T) {
+	newMsg := func(vchannel string, ts uint64) msgstream.TsMsg {
+		msg := &msgstream.DeleteMsg{DeleteRequest: msgpb.DeleteRequest{ShardName: vchannel}}
+		msg.SetPosition(&msgpb.MsgPosition{ChannelName: "pchannel", MsgID: []byte{byte(ts)}, Timestamp: ts})
+		return msg
+	}
+	positions := collectChannelPositions([]msgstream.TsMsg{
+		newMsg("v1", 1),
+		newMsg("v1", 3),
+		newMsg("v2", 2),
+		// not produced
+		&msgstream.InsertMsg{InsertRequest: msgpb.InsertRequest{ShardName: "v3"}},
+	})
+	assert.Len(t, positions, 2)
+	assert.Equal(t, uint64(3), positions["v1"].GetTimestamp())
+
+	mergeChannelPositions(positions, collectChannelPositions([]msgstream.TsMsg{newMsg("v2", 4), newMsg("v1", 2)}))
+	assert.Equal(t, uint64(3), positions["v1"].GetTimestamp())
+	assert.Equal(t, uint64(4), positions["v2"].GetTimestamp())
+
+	status := merr.Success()
+	setChannelPositions(status, positions)
+	result := make(map[string]channelPosition)
+	assert.NoError(t, json.Unmarshal([]byte(status.GetExtraInfo()[channelPositionsKey]), &result))
+	assert.Equal(t, channelPosition{Channel: "pchannel", MsgID: []byte{4}, Timestamp: 4}, result["v2"])
+
+	failed := merr.Status(merr.ErrServiceInternal)
+	setChannelPositions(failed, positions)
+	assert.Empty(t, failed.GetExtraInfo())
+}
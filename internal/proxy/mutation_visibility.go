@@ -0,0 +1,104 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+const defaultMutationVisibilityTimeout = time.Minute
+
+// MutationVisibilityRequest is the request to wait for the mutations before the timestamp to be visible,
+// the timestamp is the one returned in the mutation result.
+type MutationVisibilityRequest struct {
+	DbName         string `json:"db_name"`
+	CollectionName string `json:"collection_name"`
+	Timestamp      uint64 `json:"timestamp"`
+	TimeoutSeconds int64  `json:"timeout_seconds"`
+}
+
+func (r *MutationVisibilityRequest) validate() error {
+	if r.CollectionName == "" {
+		return merr.WrapErrParameterMissing("collection_name")
+	}
+	// 0 and 2 are reserved for the strong and bounded consistency
+	if r.Timestamp <= boundedTS {
+		return merr.WrapErrParameterInvalidMsg("invalid mutation timestamp %d", r.Timestamp)
+	}
+	if r.TimeoutSeconds < 0 {
+		return merr.WrapErrParameterInvalidMsg("timeout_seconds should not be negative")
+	}
+	return nil
+}
+
+// MutationVisibilityResult is the result of waiting for the mutation visibility.
+type MutationVisibilityResult struct {
+	CollectionName string `json:"collection_name"`
+	Timestamp      uint64 `json:"timestamp"`
+	WaitedMs       int64  `json:"waited_ms"`
+}
+
+// waitForMutationVisibility blocks until the mutations before the timestamp are serviceable on all the loaded shards
+// of the collection. It issues a count query with the timestamp as the guarantee timestamp,
+// which is only served after the tsafe of every shard delegator passes it.
+func (node *Proxy) waitForMutationVisibility(ctx context.Context, request *MutationVisibilityRequest) (*MutationVisibilityResult, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+	timeout := defaultMutationVisibilityTimeout
+	if request.TimeoutSeconds > 0 {
+		timeout = time.Duration(request.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	queryReq := &milvuspb.QueryRequest{
+		DbName:             request.DbName,
+		CollectionName:     request.CollectionName,
+		OutputFields:       []string{"count(*)"},
+		GuaranteeTimestamp: request.Timestamp,
+		ConsistencyLevel:   commonpb.ConsistencyLevel_Customized,
+	}
+	if err := checkMgrPrivilege(ctx, queryReq); err != nil {
+		return nil, err
+	}
+
+	state, err := node.getLoadState(ctx, request.DbName, request.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+	if state != commonpb.LoadState_LoadStateLoaded {
+		return nil, merr.WrapErrCollectionNotLoaded(request.CollectionName, "mutation visibility is only available for loaded collections")
+	}
+
+	start := time.Now()
+	// internal request, neither mirrored nor routed to the canary collection
+	resp, err := node.Query(context.WithValue(ctx, mirroredRequestKey{}, true), queryReq)
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	return &MutationVisibilityResult{
+		CollectionName: request.CollectionName,
+		Timestamp:      request.Timestamp,
+		WaitedMs:       time.Since(start).Milliseconds(),
+	}, nil
+}
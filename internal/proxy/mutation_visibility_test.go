@@ -0,0 +1,64 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestMutationVisibilityRequest_validate(t *testing.T) {
+	assert.NoError(t, (&MutationVisibilityRequest{CollectionName: "coll", Timestamp: 100}).validate())
+	assert.Error(t, (&MutationVisibilityRequest{Timestamp: 100}).validate())
+	assert.Error(t, (&MutationVisibilityRequest{CollectionName: "coll", Timestamp: boundedTS}).validate())
+	assert.Error(t, (&MutationVisibilityRequest{CollectionName: "coll", Timestamp: 100, TimeoutSeconds: -1}).validate())
+}
+
+func TestProxy_WaitForMutationVisibility(t *testing.T) {
+	node := &Proxy{}
+	node.UpdateStateCode(commonpb.StateCode_Abnormal)
+
+	req, _ := http.NewRequest(http.MethodPost, mgrWaitMutationVisibility, strings.NewReader("invalid"))
+	recorder := httptest.NewRecorder()
+	node.WaitForMutationVisibility(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, mgrWaitMutationVisibility, strings.NewReader(`{"collection_name": "coll"}`))
+	recorder = httptest.NewRecorder()
+	node.WaitForMutationVisibility(recorder, req)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "timestamp")
+
+	_, err := node.waitForMutationVisibility(context.Background(), &MutationVisibilityRequest{CollectionName: "coll", Timestamp: 100})
+	assert.ErrorIs(t, err, merr.ErrServiceNotReady)
+
+	paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+	req, _ = http.NewRequest(http.MethodPost, mgrWaitMutationVisibility, strings.NewReader(`{"collection_name": "coll", "timestamp": 100}`))
+	recorder = httptest.NewRecorder()
+	node.WaitForMutationVisibility(recorder, req)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
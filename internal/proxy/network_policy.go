@@ -0,0 +1,339 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/peer"
+
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// NetworkPolicySubject is the kind of principal a network policy is attached to.
+type NetworkPolicySubject string
+
+const (
+	NetworkPolicySubjectUser NetworkPolicySubject = "user"
+	NetworkPolicySubjectRole NetworkPolicySubject = "role"
+
+	// networkPolicyPrefix is the etcd prefix(under meta root path) of the persisted network policies.
+	networkPolicyPrefix = "proxy/network-policies"
+)
+
+// NetworkPolicy restricts the client addresses of a user or the users granted a role.
+// The request is rejected if the address matches any Deny range of the user and its roles,
+// or if any of them has Allow ranges and none of those matches.
+type NetworkPolicy struct {
+	Subject NetworkPolicySubject `json:"subject"`
+	Name    string               `json:"name"`
+	Allow   []string             `json:"allow,omitempty"`
+	Deny    []string             `json:"deny,omitempty"`
+
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+func (p *NetworkPolicy) key() string {
+	return networkPolicyKey(p.Subject, p.Name)
+}
+
+// parse validates the policy and parses the CIDR ranges, a single address is treated as a full length prefix.
+func (p *NetworkPolicy) parse() error {
+	if p.Subject != NetworkPolicySubjectUser && p.Subject != NetworkPolicySubjectRole {
+		return merr.WrapErrParameterInvalidMsg("invalid network policy subject %s, should be user or role", p.Subject)
+	}
+	if p.Name == "" {
+		return merr.WrapErrParameterMissing("name", "network policy must specify the user or role name")
+	}
+	var err error
+	if p.allow, err = parseCIDRs(p.Allow); err != nil {
+		return err
+	}
+	if p.deny, err = parseCIDRs(p.Deny); err != nil {
+		return err
+	}
+	return nil
+}
+
+func parseCIDRs(values []string) ([]*net.IPNet, error) {
+	ipNets := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, merr.WrapErrParameterInvalidMsg("invalid ip address %s", value)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			ipNets = append(ipNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, merr.WrapErrParameterInvalidMsg("invalid cidr %s", value)
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	return ipNets, nil
+}
+
+func containsIP(ipNets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range ipNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func networkPolicyKey(subject NetworkPolicySubject, name string) string {
+	return path.Join(networkPolicyPrefix, string(subject), name)
+}
+
+// networkPolicyManager keeps the network policies, which are persisted in etcd and synchronized to all proxies.
+type networkPolicyManager struct {
+	mu       sync.RWMutex
+	policies map[string]*NetworkPolicy
+	kv       kv.WatchKV
+}
+
+var globalNetworkPolicy = newNetworkPolicyManager()
+
+func newNetworkPolicyManager() *networkPolicyManager {
+	return &networkPolicyManager{
+		policies: make(map[string]*NetworkPolicy),
+	}
+}
+
+// init loads the persisted policies and starts watching the policy changes made by other proxies.
+func (m *networkPolicyManager) init(ctx context.Context, watchKV kv.WatchKV) error {
+	m.mu.Lock()
+	m.kv = watchKV
+	m.mu.Unlock()
+
+	if err := m.reload(); err != nil {
+		return err
+	}
+	go watchPrefix(ctx, watchKV, networkPolicyPrefix, "network policy", m)
+	return nil
+}
+
+func unmarshalNetworkPolicy(value []byte) (*NetworkPolicy, error) {
+	policy := &NetworkPolicy{}
+	if err := json.Unmarshal(value, policy); err != nil {
+		return nil, err
+	}
+	if err := policy.parse(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (m *networkPolicyManager) reload() error {
+	_, values, err := m.kv.LoadWithPrefix(networkPolicyPrefix)
+	if err != nil {
+		return err
+	}
+	policies := make(map[string]*NetworkPolicy, len(values))
+	for _, value := range values {
+		policy, err := unmarshalNetworkPolicy([]byte(value))
+		if err != nil {
+			log.Warn("skip invalid network policy", zap.String("value", value), zap.Error(err))
+			continue
+		}
+		policies[policy.key()] = policy
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policies = policies
+	return nil
+}
+
+func (m *networkPolicyManager) handleEvent(eventType mvccpb.Event_EventType, key, value []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch eventType {
+	case mvccpb.PUT:
+		policy, err := unmarshalNetworkPolicy(value)
+		if err != nil {
+			log.Warn("skip invalid network policy", zap.ByteString("key", key), zap.Error(err))
+			return
+		}
+		m.policies[policy.key()] = policy
+		log.Info("network policy set", zap.Any("policy", policy))
+	case mvccpb.DELETE:
+		policyKey, ok := trimRootPath(key, networkPolicyPrefix)
+		if !ok {
+			return
+		}
+		delete(m.policies, policyKey)
+		log.Info("network policy removed", zap.String("policy", policyKey))
+	}
+}
+
+// Set adds or replaces the policy of the user or role, it will be applied to all proxies once persisted.
+func (m *networkPolicyManager) Set(policy *NetworkPolicy) error {
+	if err := policy.parse(); err != nil {
+		return err
+	}
+	value, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.kv != nil {
+		if err := m.kv.Save(policy.key(), string(value)); err != nil {
+			return err
+		}
+	}
+	m.policies[policy.key()] = policy
+	return nil
+}
+
+// Remove removes the policy of the user or role.
+func (m *networkPolicyManager) Remove(subject NetworkPolicySubject, name string) error {
+	key := networkPolicyKey(subject, name)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.policies[key]; !ok {
+		return merr.WrapErrParameterInvalidMsg("no network policy found for %s", key)
+	}
+	if m.kv != nil {
+		if err := m.kv.Remove(key); err != nil {
+			return err
+		}
+	}
+	delete(m.policies, key)
+	return nil
+}
+
+// List returns all the policies sorted by key.
+func (m *networkPolicyManager) List() []*NetworkPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := make([]string, 0, len(m.policies))
+	for key := range m.policies {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	policies := make([]*NetworkPolicy, 0, len(keys))
+	for _, key := range keys {
+		policies = append(policies, m.policies[key])
+	}
+	return policies
+}
+
+// check returns error if the ip is not allowed for the user with roles.
+func (m *networkPolicyManager) check(username string, roles []string, ip net.IP) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	policies := make([]*NetworkPolicy, 0)
+	if policy, ok := m.policies[networkPolicyKey(NetworkPolicySubjectUser, username)]; ok {
+		policies = append(policies, policy)
+	}
+	for _, role := range roles {
+		if policy, ok := m.policies[networkPolicyKey(NetworkPolicySubjectRole, role)]; ok {
+			policies = append(policies, policy)
+		}
+	}
+
+	restricted, allowed := false, false
+	for _, policy := range policies {
+		if containsIP(policy.deny, ip) {
+			return merr.WrapErrPrivilegeNotPermitted("address %s is denied by the network policy of %s %s", ip, policy.Subject, policy.Name)
+		}
+		if len(policy.allow) > 0 {
+			restricted = true
+			allowed = allowed || containsIP(policy.allow, ip)
+		}
+	}
+	if restricted && !allowed {
+		return merr.WrapErrPrivilegeNotPermitted("address %s is not in the allowed ranges of user %s", ip, username)
+	}
+	return nil
+}
+
+// Check returns error if the client address of the request is not allowed for the user,
+// the rejected attempts are audited.
+func (m *networkPolicyManager) Check(ctx context.Context, username string) error {
+	m.mu.RLock()
+	empty := len(m.policies) == 0
+	m.mu.RUnlock()
+	if empty {
+		return nil
+	}
+
+	ip := getClientIP(ctx)
+	if ip == nil {
+		// the address is unknown for the in-process calls
+		return nil
+	}
+	roles, err := GetRole(username)
+	if err != nil {
+		return err
+	}
+	if err := m.check(username, roles, ip); err != nil {
+		auditLog(ctx, auditNetworkDenied, nil, zap.String("username", username), zap.String("address", ip.String()), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// CheckNetworkPolicy checks the client address of the restful request, which isn't carried by the grpc peer.
+func CheckNetworkPolicy(ctx context.Context, username string, clientIP string) error {
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		globalNetworkPolicy.mu.RLock()
+		empty := len(globalNetworkPolicy.policies) == 0
+		globalNetworkPolicy.mu.RUnlock()
+		if empty {
+			return nil
+		}
+		return merr.WrapErrPrivilegeNotPermitted("unknown client address %s", clientIP)
+	}
+	return globalNetworkPolicy.Check(peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: ip}}), username)
+}
+
+func getClientIP(ctx context.Context) net.IP {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	return net.ParseIP(host)
+}
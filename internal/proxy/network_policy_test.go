@@ -0,0 +1,129 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"google.golang.org/grpc/peer"
+
+	"github.com/milvus-io/milvus/internal/kv/mocks"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestNetworkPolicy(t *testing.T) {
+	t.Run("set and remove", func(t *testing.T) {
+		m := newNetworkPolicyManager()
+		assert.Error(t, m.Set(&NetworkPolicy{Subject: "group", Name: "g1"}))
+		assert.Error(t, m.Set(&NetworkPolicy{Subject: NetworkPolicySubjectUser}))
+		assert.Error(t, m.Set(&NetworkPolicy{Subject: NetworkPolicySubjectUser, Name: "alice", Allow: []string{"10.0.0.0/33"}}))
+		assert.Error(t, m.Set(&NetworkPolicy{Subject: NetworkPolicySubjectUser, Name: "alice", Deny: []string{"invalid"}}))
+
+		assert.NoError(t, m.Set(&NetworkPolicy{Subject: NetworkPolicySubjectUser, Name: "alice", Allow: []string{"10.0.0.0/8"}}))
+		assert.NoError(t, m.Set(&NetworkPolicy{Subject: NetworkPolicySubjectRole, Name: "r1", Deny: []string{"10.0.0.1", "::1"}}))
+		assert.Len(t, m.List(), 2)
+
+		assert.NoError(t, m.Remove(NetworkPolicySubjectUser, "alice"))
+		assert.Error(t, m.Remove(NetworkPolicySubjectUser, "alice"))
+		assert.Len(t, m.List(), 1)
+	})
+
+	t.Run("check", func(t *testing.T) {
+		m := newNetworkPolicyManager()
+		assert.NoError(t, m.check("alice", nil, net.ParseIP("1.1.1.1")))
+
+		assert.NoError(t, m.Set(&NetworkPolicy{Subject: NetworkPolicySubjectUser, Name: "alice", Allow: []string{"10.0.0.0/8"}}))
+		assert.NoError(t, m.Set(&NetworkPolicy{Subject: NetworkPolicySubjectRole, Name: "r1", Allow: []string{"192.168.0.0/16"}, Deny: []string{"10.0.0.1"}}))
+
+		assert.NoError(t, m.check("alice", nil, net.ParseIP("10.1.1.1")))
+		assert.ErrorIs(t, m.check("alice", nil, net.ParseIP("192.168.1.1")), merr.ErrPrivilegeNotPermitted)
+		// any allowed range of the user and roles is accepted
+		assert.NoError(t, m.check("alice", []string{"r1"}, net.ParseIP("192.168.1.1")))
+		assert.Error(t, m.check("alice", []string{"r1"}, net.ParseIP("1.1.1.1")))
+		// deny wins
+		assert.Error(t, m.check("alice", []string{"r1"}, net.ParseIP("10.0.0.1")))
+		assert.NoError(t, m.check("bob", nil, net.ParseIP("1.1.1.1")))
+		assert.Error(t, m.check("bob", []string{"r1"}, net.ParseIP("1.1.1.1")))
+	})
+
+	t.Run("check request", func(t *testing.T) {
+		mockCache := NewMockCache(t)
+		mockCache.EXPECT().GetUserRole("alice").Return([]string{}).Maybe()
+		globalMetaCache = mockCache
+		defer func() { globalMetaCache = nil }()
+
+		m := newNetworkPolicyManager()
+		assert.NoError(t, m.Set(&NetworkPolicy{Subject: NetworkPolicySubjectUser, Name: "alice", Allow: []string{"10.0.0.0/8"}}))
+
+		ctx := context.Background()
+		assert.NoError(t, m.Check(ctx, "alice"))
+		allowed := peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 19530}})
+		assert.NoError(t, m.Check(allowed, "alice"))
+		denied := peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("172.16.0.2"), Port: 19530}})
+		assert.Error(t, m.Check(denied, "alice"))
+	})
+
+	t.Run("check restful request", func(t *testing.T) {
+		mockCache := NewMockCache(t)
+		mockCache.EXPECT().GetUserRole("alice").Return([]string{}).Maybe()
+		globalMetaCache = mockCache
+		defer func() { globalMetaCache = nil }()
+		policy := globalNetworkPolicy
+		defer func() { globalNetworkPolicy = policy }()
+		globalNetworkPolicy = newNetworkPolicyManager()
+
+		ctx := context.Background()
+		assert.NoError(t, CheckNetworkPolicy(ctx, "alice", ""))
+		assert.NoError(t, globalNetworkPolicy.Set(&NetworkPolicy{Subject: NetworkPolicySubjectUser, Name: "alice", Allow: []string{"10.0.0.0/8"}}))
+		assert.NoError(t, CheckNetworkPolicy(ctx, "alice", "10.0.0.2"))
+		assert.Error(t, CheckNetworkPolicy(ctx, "alice", "172.16.0.2"))
+		assert.Error(t, CheckNetworkPolicy(ctx, "alice", ""))
+	})
+
+	t.Run("persist", func(t *testing.T) {
+		watchKV := mocks.NewWatchKV(t)
+		policy := &NetworkPolicy{Subject: NetworkPolicySubjectUser, Name: "alice", Deny: []string{"10.0.0.0/8"}}
+		value, _ := json.Marshal(policy)
+		watchKV.EXPECT().LoadWithPrefix(networkPolicyPrefix).Return([]string{policy.key()}, []string{string(value), "invalid"}, nil)
+		watchKV.EXPECT().Save(networkPolicyKey(NetworkPolicySubjectRole, "r1"), mock.Anything).Return(nil)
+
+		m := newNetworkPolicyManager()
+		m.kv = watchKV
+		assert.NoError(t, m.reload())
+		assert.Error(t, m.check("alice", nil, net.ParseIP("10.0.0.1")))
+
+		assert.NoError(t, m.Set(&NetworkPolicy{Subject: NetworkPolicySubjectRole, Name: "r1", Allow: []string{"10.0.0.0/8"}}))
+		assert.Len(t, m.List(), 2)
+	})
+
+	t.Run("watch event", func(t *testing.T) {
+		m := newNetworkPolicyManager()
+		policy := &NetworkPolicy{Subject: NetworkPolicySubjectUser, Name: "alice", Deny: []string{"10.0.0.0/8"}}
+		value, _ := json.Marshal(policy)
+		m.handleEvent(mvccpb.PUT, []byte("by-dev/meta/"+policy.key()), value)
+		assert.Error(t, m.check("alice", nil, net.ParseIP("10.0.0.1")))
+
+		m.handleEvent(mvccpb.DELETE, []byte("by-dev/meta/"+policy.key()), nil)
+		assert.NoError(t, m.check("alice", nil, net.ParseIP("10.0.0.1")))
+	})
+}
@@ -0,0 +1,106 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// nullPredicatePattern matches the IS NULL and IS NOT NULL operators of the filter expressions.
+var nullPredicatePattern = regexp.MustCompile(`(?i)\bis\s+(not\s+)?null\b`)
+
+// nullOperandPattern matches the IS NULL and IS NOT NULL operators with their operands, the fields and the json
+// keys such as meta["user"]["age"], in the expressions of the masked string literals.
+var nullOperandPattern = regexp.MustCompile(`(?i)(^|[^\w$])([A-Za-z_$][\w$]*((?:\s*\[[^\[\]]*\])*))\s+is\s+(not\s+)?null\b`)
+
+// maskStringLiterals returns the expression with the contents of its string literals blanked, of the same length.
+func maskStringLiterals(expr string) string {
+	var sb strings.Builder
+	splitStringLiterals(expr, func(part string, literal bool) {
+		if !literal {
+			sb.WriteString(part)
+			return
+		}
+		sb.WriteByte(part[0])
+		if len(part) > 1 {
+			sb.WriteString(strings.Repeat(" ", len(part)-2))
+			sb.WriteByte(part[len(part)-1])
+		}
+	})
+	return sb.String()
+}
+
+// nullPredicateExpr returns the expression of the IS NULL or IS NOT NULL operator of the field or the json key. The
+// fields are not nullable in this version, the missing values are inserted as the default values of the fields, so
+// IS NULL matches no row of the scalar fields, and the json keys are null if absent.
+func nullPredicateExpr(schema *schemapb.CollectionSchema, operand, path string, not bool) (string, error) {
+	name := strings.TrimSpace(strings.TrimSuffix(operand, path))
+	field := typeutil.GetFieldByName(schema, name)
+	isJSONKey := path != "" && (field == nil || typeutil.IsJSONType(field.GetDataType()))
+	switch {
+	case field == nil && !schema.GetEnableDynamicField():
+		return "", merr.WrapErrFieldNotFound(name, "the field of IS NULL not found")
+	case field == nil || isJSONKey:
+		if not {
+			return fmt.Sprintf("(exists %s)", operand), nil
+		}
+		return fmt.Sprintf("(not exists %s)", operand), nil
+	case path == "" && (typeutil.IsBoolType(field.GetDataType()) || typeutil.IsIntegerType(field.GetDataType()) ||
+		typeutil.IsFloatingType(field.GetDataType()) || typeutil.IsStringType(field.GetDataType())):
+		if not {
+			return fmt.Sprintf("(%s == %s)", name, name), nil
+		}
+		return fmt.Sprintf("(not (%s == %s))", name, name), nil
+	default:
+		return "", merr.WrapErrParameterInvalidMsg("IS NULL is not supported on the %s field %s, "+
+			"only on the scalar fields and the json keys", field.GetDataType().String(), name)
+	}
+}
+
+// expandNullPredicates replaces the IS NULL and IS NOT NULL operators outside the string literals of the expression
+// with the expressions the plan parser accepts, and rejects those of the operands other than the fields and the json
+// keys, which the plan parser otherwise fails with a syntax error.
+func expandNullPredicates(schema *schemapb.CollectionSchema, expr string) (string, error) {
+	if !strings.Contains(strings.ToLower(expr), "null") {
+		return expr, nil
+	}
+	var sb strings.Builder
+	end := 0
+	for _, match := range nullOperandPattern.FindAllStringSubmatchIndex(maskStringLiterals(expr), -1) {
+		operand, path := expr[match[4]:match[5]], expr[match[6]:match[7]]
+		replaced, err := nullPredicateExpr(schema, operand, path, match[8] >= 0)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(expr[end:match[4]])
+		sb.WriteString(replaced)
+		end = match[1]
+	}
+	sb.WriteString(expr[end:])
+	expanded := sb.String()
+	if match := nullPredicatePattern.FindString(maskStringLiterals(expanded)); match != "" {
+		return "", merr.WrapErrParameterInvalidMsg("%q is only supported on the fields and the json keys",
+			strings.ToUpper(strings.Join(strings.Fields(match), " ")))
+	}
+	return expanded, nil
+}
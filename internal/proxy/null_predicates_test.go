@@ -0,0 +1,76 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+func TestExpandNullPredicates(t *testing.T) {
+	assert.Equal(t, `a == "       " and b == '    '`, maskStringLiterals(`a == "is null" and b == 'x\'y'`))
+
+	schema := &schemapb.CollectionSchema{
+		EnableDynamicField: true,
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "title", DataType: schemapb.DataType_VarChar},
+			{FieldID: 102, Name: "meta", DataType: schemapb.DataType_JSON},
+			{FieldID: 103, Name: "tags", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int64},
+			{FieldID: 104, Name: "vec", DataType: schemapb.DataType_FloatVector,
+				TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "4"}}},
+			{FieldID: 105, Name: common.MetaFieldName, DataType: schemapb.DataType_JSON, IsDynamic: true},
+		},
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		expr     string
+		expanded string
+	}{
+		{`title is null`, `(not (title == title))`},
+		{`pk > 1 and title IS  NOT NULL`, `pk > 1 and (title == title)`},
+		{`meta["user"]["age"] is null or meta["a b"] is not null`, `(not exists meta["user"]["age"]) or (exists meta["a b"])`},
+		{`(color Is Null)`, `((not exists color))`},
+		{`title == "is null" and this_null == 1`, `title == "is null" and this_null == 1`},
+	}
+	for _, c := range cases {
+		expanded, err := expandNullPredicates(schema, c.expr)
+		assert.NoError(t, err, c.expr)
+		assert.Equal(t, c.expanded, expanded, c.expr)
+		_, err = planparserv2.CreateRetrievePlan(helper, expanded)
+		assert.NoError(t, err, c.expr)
+	}
+
+	for _, expr := range []string{`meta is null`, `tags is null`, `vec is not null`, `(pk + 1) is null`} {
+		_, err := expandNullPredicates(schema, expr)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid, expr)
+	}
+
+	schema.EnableDynamicField = false
+	_, err = expandNullPredicates(schema, `color is null`)
+	assert.ErrorIs(t, err, merr.ErrFieldNotFound)
+}
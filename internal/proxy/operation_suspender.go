@@ -0,0 +1,316 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/requestutil"
+)
+
+// OperationClass is the class of requests which could be suspended together.
+type OperationClass string
+
+const (
+	OperationClassDDL OperationClass = "ddl"
+	OperationClassDML OperationClass = "dml"
+	OperationClassDQL OperationClass = "dql"
+
+	// suspendOperationPrefix is the etcd prefix(under meta root path) of the persisted suspend rules.
+	suspendOperationPrefix = "proxy/suspended-operations"
+	suspendRuleAnyScope    = "*"
+)
+
+// SuspendRule describes a class of operations rejected by all proxies.
+// Empty DBName or CollectionName means the rule applies to all of them.
+type SuspendRule struct {
+	Class          OperationClass `json:"class"`
+	DBName         string         `json:"db_name,omitempty"`
+	CollectionName string         `json:"collection_name,omitempty"`
+	Message        string         `json:"message,omitempty"`
+	CreateTime     int64          `json:"create_time"`
+}
+
+func (r *SuspendRule) key() string {
+	return suspendRuleKey(r.Class, r.DBName, r.CollectionName)
+}
+
+func (r *SuspendRule) match(class OperationClass, dbName, collectionName string) bool {
+	if r.Class != class {
+		return false
+	}
+	if r.DBName != "" && r.DBName != dbName {
+		return false
+	}
+	if r.CollectionName != "" && r.CollectionName != collectionName {
+		return false
+	}
+	return true
+}
+
+func suspendRuleKey(class OperationClass, dbName, collectionName string) string {
+	if dbName == "" {
+		dbName = suspendRuleAnyScope
+	}
+	if collectionName == "" {
+		collectionName = suspendRuleAnyScope
+	}
+	return path.Join(suspendOperationPrefix, string(class), dbName, collectionName)
+}
+
+func parseOperationClass(class string) (OperationClass, error) {
+	switch OperationClass(strings.ToLower(class)) {
+	case OperationClassDDL:
+		return OperationClassDDL, nil
+	case OperationClassDML:
+		return OperationClassDML, nil
+	case OperationClassDQL:
+		return OperationClassDQL, nil
+	default:
+		return "", merr.WrapErrParameterInvalid("ddl/dml/dql", class, "invalid operation class")
+	}
+}
+
+// getOperationClass returns the operation class of the request, false if the request can't be suspended.
+func getOperationClass(req any) (OperationClass, bool) {
+	switch req.(type) {
+	case *milvuspb.CreateCollectionRequest, *milvuspb.DropCollectionRequest, *milvuspb.AlterCollectionRequest,
+		*milvuspb.LoadCollectionRequest, *milvuspb.ReleaseCollectionRequest,
+		*milvuspb.CreatePartitionRequest, *milvuspb.DropPartitionRequest,
+		*milvuspb.LoadPartitionsRequest, *milvuspb.ReleasePartitionsRequest,
+		*milvuspb.CreateIndexRequest, *milvuspb.DropIndexRequest, *milvuspb.AlterIndexRequest,
+		*milvuspb.CreateAliasRequest, *milvuspb.DropAliasRequest, *milvuspb.AlterAliasRequest,
+		*milvuspb.CreateDatabaseRequest, *milvuspb.DropDatabaseRequest:
+		return OperationClassDDL, true
+	case *milvuspb.InsertRequest, *milvuspb.UpsertRequest, *milvuspb.DeleteRequest, *milvuspb.ImportRequest:
+		return OperationClassDML, true
+	case *milvuspb.SearchRequest, *milvuspb.HybridSearchRequest, *milvuspb.QueryRequest:
+		return OperationClassDQL, true
+	default:
+		return "", false
+	}
+}
+
+// operationSuspender keeps the suspend rules, which are persisted in etcd and synchronized to all proxies.
+type operationSuspender struct {
+	mu    sync.RWMutex
+	rules map[string]*SuspendRule
+	kv    kv.WatchKV
+}
+
+var globalOperationSuspender = newOperationSuspender()
+
+func newOperationSuspender() *operationSuspender {
+	return &operationSuspender{
+		rules: make(map[string]*SuspendRule),
+	}
+}
+
+// init loads the persisted rules and starts watching the rule changes made by other proxies.
+func (s *operationSuspender) init(ctx context.Context, watchKV kv.WatchKV) error {
+	s.mu.Lock()
+	s.kv = watchKV
+	s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		return err
+	}
+	go watchPrefix(ctx, watchKV, suspendOperationPrefix, "operation suspender", s)
+	return nil
+}
+
+func (s *operationSuspender) reload() error {
+	_, values, err := s.kv.LoadWithPrefix(suspendOperationPrefix)
+	if err != nil {
+		return err
+	}
+	rules := make(map[string]*SuspendRule, len(values))
+	for _, value := range values {
+		rule := &SuspendRule{}
+		if err := json.Unmarshal([]byte(value), rule); err != nil {
+			log.Warn("skip invalid suspend rule", zap.String("value", value), zap.Error(err))
+			continue
+		}
+		rules[rule.key()] = rule
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = rules
+	return nil
+}
+
+func (s *operationSuspender) handleEvent(eventType mvccpb.Event_EventType, key, value []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch eventType {
+	case mvccpb.PUT:
+		rule := &SuspendRule{}
+		if err := json.Unmarshal(value, rule); err != nil {
+			log.Warn("skip invalid suspend rule", zap.ByteString("key", key), zap.Error(err))
+			return
+		}
+		s.rules[rule.key()] = rule
+		log.Info("operation suspended", zap.Any("rule", rule))
+	case mvccpb.DELETE:
+		ruleKey, ok := trimRootPath(key, suspendOperationPrefix)
+		if !ok {
+			return
+		}
+		delete(s.rules, ruleKey)
+		log.Info("operation resumed", zap.String("rule", ruleKey))
+	}
+}
+
+// Suspend adds the rule, it will be applied to all proxies once persisted.
+func (s *operationSuspender) Suspend(rule *SuspendRule) error {
+	if rule.CollectionName != "" && rule.DBName == "" {
+		return merr.WrapErrParameterMissing("db_name", "collection scoped suspend rule must specify the database")
+	}
+	rule.CreateTime = time.Now().Unix()
+	value, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.kv != nil {
+		if err := s.kv.Save(rule.key(), string(value)); err != nil {
+			return err
+		}
+	}
+	s.rules[rule.key()] = rule
+	return nil
+}
+
+// Resume removes the rule which exactly matches the given scope.
+func (s *operationSuspender) Resume(class OperationClass, dbName, collectionName string) error {
+	key := suspendRuleKey(class, dbName, collectionName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.rules[key]; !ok {
+		return merr.WrapErrParameterInvalidMsg("no suspend rule found for %s", key)
+	}
+	if s.kv != nil {
+		if err := s.kv.Remove(key); err != nil {
+			return err
+		}
+	}
+	delete(s.rules, key)
+	return nil
+}
+
+// List returns all the rules sorted by key.
+func (s *operationSuspender) List() []*SuspendRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.rules))
+	for key := range s.rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	rules := make([]*SuspendRule, 0, len(keys))
+	for _, key := range keys {
+		rules = append(rules, s.rules[key])
+	}
+	return rules
+}
+
+// Check returns ErrServiceOperationSuspended if any rule matches the operation.
+func (s *operationSuspender) Check(class OperationClass, dbName, collectionName string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, rule := range s.rules {
+		if rule.match(class, dbName, collectionName) {
+			msg := rule.Message
+			if msg == "" {
+				msg = "under maintenance"
+			}
+			return merr.WrapErrServiceOperationSuspended(msg,
+				fmt.Sprintf("%s operations suspended on db=%s collection=%s", class, dbName, collectionName))
+		}
+	}
+	return nil
+}
+
+// hasCollectionRule returns whether any collection scoped rule of the class applies to the database.
+func (s *operationSuspender) hasCollectionRule(class OperationClass, dbName string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, rule := range s.rules {
+		if rule.Class == class && rule.CollectionName != "" && rule.DBName == dbName {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckOperationSuspended returns ErrServiceOperationSuspended if the request is suspended. The collection name in
+// request may be an alias, so it's resolved to the collection name in schema before matching the collection scoped rules.
+func CheckOperationSuspended(ctx context.Context, req any) error {
+	class, ok := getOperationClass(req)
+	if !ok {
+		return nil
+	}
+
+	dbName := GetCurDBNameFromContextOrDefault(ctx)
+	if name, ok := requestutil.GetDbNameFromRequest(req); ok && name.(string) != "" {
+		dbName = name.(string)
+	}
+	collectionName := ""
+	if name, ok := requestutil.GetCollectionNameFromRequest(req); ok {
+		collectionName = name.(string)
+	}
+	if collectionName != "" && globalMetaCache != nil && globalOperationSuspender.hasCollectionRule(class, dbName) {
+		if schema, err := globalMetaCache.GetCollectionSchema(ctx, dbName, collectionName); err == nil {
+			collectionName = schema.GetName()
+		}
+	}
+	return globalOperationSuspender.Check(class, dbName, collectionName)
+}
+
+// OperationSuspendInterceptor returns a new unary server interceptor that rejects the suspended operations.
+func OperationSuspendInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := CheckOperationSuspended(ctx, req); err != nil {
+			log.Ctx(ctx).RatedInfo(10, "reject suspended operation", zap.String("method", info.FullMethod), zap.Error(err))
+			if rsp := getFailedResponse(req, err); rsp != nil {
+				return rsp, nil
+			}
+			return nil, errors.Wrap(err, info.FullMethod)
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,157 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/kv/mocks"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestOperationSuspender(t *testing.T) {
+	t.Run("suspend and resume", func(t *testing.T) {
+		s := newOperationSuspender()
+		err := s.Suspend(&SuspendRule{Class: OperationClassDML, DBName: "default", CollectionName: "c1", Message: "migrating"})
+		assert.NoError(t, err)
+
+		err = s.Check(OperationClassDML, "default", "c1")
+		assert.ErrorIs(t, err, merr.ErrServiceOperationSuspended)
+		assert.Contains(t, err.Error(), "migrating")
+		assert.NoError(t, s.Check(OperationClassDML, "default", "c2"))
+		assert.NoError(t, s.Check(OperationClassDQL, "default", "c1"))
+		assert.Len(t, s.List(), 1)
+
+		assert.NoError(t, s.Resume(OperationClassDML, "default", "c1"))
+		assert.NoError(t, s.Check(OperationClassDML, "default", "c1"))
+		assert.Error(t, s.Resume(OperationClassDML, "default", "c1"))
+	})
+
+	t.Run("global rule", func(t *testing.T) {
+		s := newOperationSuspender()
+		assert.NoError(t, s.Suspend(&SuspendRule{Class: OperationClassDDL}))
+		assert.Error(t, s.Check(OperationClassDDL, "db1", "c1"))
+		assert.Error(t, s.Check(OperationClassDDL, "db2", ""))
+	})
+
+	t.Run("collection rule without db", func(t *testing.T) {
+		s := newOperationSuspender()
+		assert.Error(t, s.Suspend(&SuspendRule{Class: OperationClassDDL, CollectionName: "c1"}))
+	})
+
+	t.Run("persist", func(t *testing.T) {
+		watchKV := mocks.NewWatchKV(t)
+		rule := &SuspendRule{Class: OperationClassDQL, DBName: "db1"}
+		value, _ := json.Marshal(rule)
+		watchKV.EXPECT().LoadWithPrefix(suspendOperationPrefix).Return([]string{rule.key()}, []string{string(value), "invalid"}, nil)
+		watchKV.EXPECT().Save(suspendRuleKey(OperationClassDML, "", ""), mock.Anything).Return(nil)
+
+		s := newOperationSuspender()
+		s.kv = watchKV
+		assert.NoError(t, s.reload())
+		assert.Error(t, s.Check(OperationClassDQL, "db1", "c1"))
+
+		assert.NoError(t, s.Suspend(&SuspendRule{Class: OperationClassDML}))
+		assert.Len(t, s.List(), 2)
+	})
+
+	t.Run("watch event", func(t *testing.T) {
+		s := newOperationSuspender()
+		rule := &SuspendRule{Class: OperationClassDML, DBName: "db1"}
+		value, _ := json.Marshal(rule)
+		s.handleEvent(mvccpb.PUT, []byte("by-dev/meta/"+rule.key()), value)
+		assert.Error(t, s.Check(OperationClassDML, "db1", "c1"))
+
+		s.handleEvent(mvccpb.DELETE, []byte("by-dev/meta/"+rule.key()), nil)
+		assert.NoError(t, s.Check(OperationClassDML, "db1", "c1"))
+	})
+}
+
+func TestParseOperationClass(t *testing.T) {
+	class, err := parseOperationClass("DML")
+	assert.NoError(t, err)
+	assert.Equal(t, OperationClassDML, class)
+
+	_, err = parseOperationClass("unknown")
+	assert.Error(t, err)
+}
+
+func TestOperationSuspendInterceptor(t *testing.T) {
+	defer func() {
+		globalOperationSuspender = newOperationSuspender()
+	}()
+	globalOperationSuspender = newOperationSuspender()
+	assert.NoError(t, globalOperationSuspender.Suspend(&SuspendRule{Class: OperationClassDML, DBName: "default", CollectionName: "c1"}))
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	globalMetaCache = nil
+
+	interceptor := OperationSuspendInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/milvus.proto.milvus.MilvusService/Insert"}
+
+	resp, err := interceptor(context.Background(), &milvuspb.InsertRequest{DbName: "default", CollectionName: "c1"}, info, handler)
+	assert.NoError(t, err)
+	result, ok := resp.(*milvuspb.MutationResult)
+	assert.True(t, ok)
+	assert.Equal(t, merr.Code(merr.ErrServiceOperationSuspended), result.GetStatus().GetCode())
+
+	resp, err = interceptor(context.Background(), &milvuspb.InsertRequest{DbName: "default", CollectionName: "c2"}, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	resp, err = interceptor(context.Background(), &milvuspb.QueryRequest{DbName: "default", CollectionName: "c1"}, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	resp, err = interceptor(context.Background(), &milvuspb.DescribeCollectionRequest{CollectionName: "c1"}, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+}
+
+func TestCheckOperationSuspendedByAlias(t *testing.T) {
+	suspender, cache := globalOperationSuspender, globalMetaCache
+	defer func() {
+		globalOperationSuspender, globalMetaCache = suspender, cache
+	}()
+	globalOperationSuspender = newOperationSuspender()
+	assert.NoError(t, globalOperationSuspender.Suspend(&SuspendRule{Class: OperationClassDML, DBName: "default", CollectionName: "c1"}))
+
+	mc := NewMockCache(t)
+	mc.EXPECT().GetCollectionSchema(mock.Anything, "default", "a1").Return(newSchemaInfo(&schemapb.CollectionSchema{Name: "c1"}), nil)
+	mc.EXPECT().GetCollectionSchema(mock.Anything, "default", "c2").Return(newSchemaInfo(&schemapb.CollectionSchema{Name: "c2"}), nil)
+	globalMetaCache = mc
+
+	err := CheckOperationSuspended(context.Background(), &milvuspb.InsertRequest{DbName: "default", CollectionName: "a1"})
+	assert.ErrorIs(t, err, merr.ErrServiceOperationSuspended)
+	assert.NoError(t, CheckOperationSuspended(context.Background(), &milvuspb.InsertRequest{DbName: "default", CollectionName: "c2"}))
+
+	// no collection scoped rule of the class or the database, the alias is not resolved
+	assert.NoError(t, CheckOperationSuspended(context.Background(), &milvuspb.QueryRequest{DbName: "default", CollectionName: "a1"}))
+	assert.NoError(t, CheckOperationSuspended(context.Background(), &milvuspb.InsertRequest{DbName: "db1", CollectionName: "a1"}))
+}
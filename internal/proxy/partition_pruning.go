@@ -0,0 +1,399 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/exprutil"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	// maxCachedPartitionStats bounds the partition statistics cached per collection.
+	maxCachedPartitionStats = 1024
+	// partitionStatsLoadTimeout bounds the loading of the partition statistics in the background.
+	partitionStatsLoadTimeout = 30 * time.Second
+)
+
+// partitionKeyStats is the min and max of the clustering key in the partition statistics of a partition, written by
+// the clustering compaction. The partition may be pruned by them only if covered, that is the statistics include all
+// the segments of the partition except the l0 ones of the deletes.
+type partitionKeyStats struct {
+	min     storage.ScalarFieldValue
+	max     storage.ScalarFieldValue
+	covered bool
+	loaded  time.Time
+}
+
+type partitionStatsKey struct {
+	partitionID UniqueID
+	fieldID     UniqueID
+}
+
+// partitionStatsStorage is the object storage the partition statistics are read from.
+type partitionStatsStorage struct {
+	mu      sync.Mutex
+	factory storage.Factory
+	cm      storage.ChunkManager
+}
+
+var globalPartitionStatsStorage = &partitionStatsStorage{}
+
+func (s *partitionStatsStorage) init(factory storage.Factory) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.factory = factory
+}
+
+// getChunkManager creates the chunk manager of the object storage at the first use.
+func (s *partitionStatsStorage) getChunkManager(ctx context.Context) (storage.ChunkManager, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cm == nil {
+		if s.factory == nil {
+			return nil, merr.WrapErrServiceNotReady(paramtable.GetRole(), paramtable.GetNodeID(), "partition stats storage not initialized")
+		}
+		cm, err := s.factory.NewPersistentStorageChunkManager(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.cm = cm
+	}
+	return s.cm, nil
+}
+
+// partitionStatsCache caches the clustering key statistics of the partitions of a collection in its meta cache entry,
+// so they are dropped with the collection. The statistics are loaded in the background, the reads never wait for
+// them and read the partitions the statistics of which are not loaded.
+type partitionStatsCache struct {
+	mu      sync.Mutex
+	entries map[partitionStatsKey]*partitionKeyStats
+	loading map[partitionStatsKey]struct{}
+}
+
+func newPartitionStatsCache() *partitionStatsCache {
+	return &partitionStatsCache{
+		entries: make(map[partitionStatsKey]*partitionKeyStats),
+		loading: make(map[partitionStatsKey]struct{}),
+	}
+}
+
+// get returns the clustering key statistics of the partition, false if not loaded within the ttl. The statistics are
+// loaded again in the background once older than half of the ttl.
+func (c *partitionStatsCache) get(dc types.DataCoordClient, collectionID, partitionID, fieldID UniqueID) (*partitionKeyStats, bool) {
+	key := partitionStatsKey{partitionID: partitionID, fieldID: fieldID}
+	ttl := Params.ProxyCfg.PartitionPruningStatsTTL.GetAsDuration(time.Second)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats, ok := c.entries[key]
+	if _, loading := c.loading[key]; !loading && (!ok || time.Since(stats.loaded) > ttl/2) {
+		c.loading[key] = struct{}{}
+		go c.load(dc, collectionID, key)
+	}
+	if !ok || time.Since(stats.loaded) > ttl {
+		return nil, false
+	}
+	return stats, true
+}
+
+func (c *partitionStatsCache) load(dc types.DataCoordClient, collectionID UniqueID, key partitionStatsKey) {
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.loading, key)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), partitionStatsLoadTimeout)
+	defer cancel()
+	cm, err := globalPartitionStatsStorage.getChunkManager(ctx)
+	if err != nil {
+		log.RatedWarn(30, "failed to load the partition stats", zap.Int64("partitionID", key.partitionID), zap.Error(err))
+		return
+	}
+	stats, err := loadPartitionKeyStats(ctx, cm, dc, collectionID, key.partitionID, key.fieldID)
+	if err != nil {
+		log.RatedWarn(30, "failed to load the partition stats", zap.Int64("partitionID", key.partitionID), zap.Error(err))
+		return
+	}
+	c.put(key, stats)
+}
+
+// put caches the statistics, the expired ones are evicted if the cache is full, and any one if still full.
+func (c *partitionStatsCache) put(key partitionStatsKey, stats *partitionKeyStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCachedPartitionStats {
+		ttl := Params.ProxyCfg.PartitionPruningStatsTTL.GetAsDuration(time.Second)
+		for k, entry := range c.entries {
+			if time.Since(entry.loaded) > ttl {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < maxCachedPartitionStats {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = stats
+}
+
+// loadPartitionKeyStats reads the latest partition statistics of each vchannel of the partition, and checks whether
+// they cover all the segments of the partition.
+func loadPartitionKeyStats(ctx context.Context, cm storage.ChunkManager, dc types.DataCoordClient, collectionID, partitionID, fieldID UniqueID) (*partitionKeyStats, error) {
+	stats := &partitionKeyStats{loaded: time.Now()}
+	prefix := path.Join(cm.RootPath(), common.PartitionStatsPath, metautil.JoinIDPath(collectionID, partitionID))
+	files, _, err := storage.ListAllChunkWithPrefix(ctx, cm, prefix, true)
+	if err != nil {
+		return nil, err
+	}
+	// the files are <prefix>/<vchannel>/<version>
+	latest := make(map[string]int64)
+	latestFiles := make(map[string]string)
+	for _, file := range files {
+		version, err := strconv.ParseInt(path.Base(file), 10, 64)
+		if err != nil {
+			continue
+		}
+		if dir := path.Dir(file); version > latest[dir] || latestFiles[dir] == "" {
+			latest[dir] = version
+			latestFiles[dir] = file
+		}
+	}
+	if len(latestFiles) == 0 {
+		return stats, nil
+	}
+
+	segments := typeutil.NewUniqueSet()
+	for _, file := range latestFiles {
+		data, err := cm.Read(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		snapshot, err := storage.DeserializePartitionsStatsSnapshot(data)
+		if err != nil {
+			return nil, err
+		}
+		for segmentID, segmentStats := range snapshot.SegmentStats {
+			segments.Insert(segmentID)
+			fieldStats, ok := lo.Find(segmentStats.FieldStats, func(fieldStats storage.FieldStats) bool {
+				return fieldStats.FieldID == fieldID
+			})
+			if !ok || fieldStats.Min == nil || fieldStats.Max == nil {
+				return stats, nil
+			}
+			if stats.min == nil || fieldStats.Min.LT(stats.min) {
+				stats.min = fieldStats.Min
+			}
+			if stats.max == nil || fieldStats.Max.GT(stats.max) {
+				stats.max = fieldStats.Max
+			}
+		}
+	}
+
+	resp, err := dc.GetSegmentsByStates(ctx, &datapb.GetSegmentsByStatesRequest{
+		CollectionID: collectionID,
+		PartitionID:  partitionID,
+		States: []commonpb.SegmentState{
+			commonpb.SegmentState_Growing,
+			commonpb.SegmentState_Sealed,
+			commonpb.SegmentState_Flushing,
+			commonpb.SegmentState_Flushed,
+		},
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	uncovered := lo.Filter(resp.GetSegments(), func(segmentID int64, _ int) bool {
+		return !segments.Contain(segmentID)
+	})
+	if len(uncovered) > 0 {
+		infos, err := dc.GetSegmentInfo(ctx, &datapb.GetSegmentInfoRequest{SegmentIDs: uncovered})
+		if err := merr.CheckRPCCall(infos, err); err != nil {
+			return nil, err
+		}
+		if len(infos.GetInfos()) != len(uncovered) || lo.ContainsBy(infos.GetInfos(), func(info *datapb.SegmentInfo) bool {
+			return info.GetLevel() != datapb.SegmentLevel_L0
+		}) {
+			return stats, nil
+		}
+	}
+	stats.covered = stats.min != nil && stats.max != nil
+	return stats, nil
+}
+
+// scalarToInt64 returns the integer value of the statistics as int64.
+func scalarToInt64(value storage.ScalarFieldValue) (int64, bool) {
+	switch v := value.GetValue().(type) {
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// keyRangesOverlap returns whether any of the ranges of the clustering key in the filter overlaps [min, max], true if
+// the type of the key is not supported.
+func keyRangesOverlap(stats *partitionKeyStats, ranges []*exprutil.PlanRange, dataType schemapb.DataType) bool {
+	for _, keyRange := range ranges {
+		switch dataType {
+		case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32, schemapb.DataType_Int64:
+			minValue, ok1 := scalarToInt64(stats.min)
+			maxValue, ok2 := scalarToInt64(stats.max)
+			if !ok1 || !ok2 || exprutil.IntRangeOverlap(exprutil.NewIntRange(minValue, maxValue, true, true), keyRange.ToIntRange()) {
+				return true
+			}
+		case schemapb.DataType_VarChar, schemapb.DataType_String:
+			minValue, ok1 := stats.min.GetValue().(string)
+			maxValue, ok2 := stats.max.GetValue().(string)
+			// the statistics range goes first, as the unbounded upper of the filter range is empty
+			if !ok1 || !ok2 || exprutil.StrRangeOverlap(exprutil.NewStrRange(minValue, maxValue, true, true), keyRange.ToStrRange()) {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// getLoadedPartitions returns the loaded partitions of the collection.
+func getLoadedPartitions(ctx context.Context, qc types.QueryCoordClient, collectionID UniqueID) (typeutil.UniqueSet, error) {
+	resp, err := qc.ShowPartitions(ctx, &querypb.ShowPartitionsRequest{CollectionID: collectionID})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	loaded := typeutil.NewUniqueSet()
+	for i, partitionID := range resp.GetPartitionIDs() {
+		if i < len(resp.GetInMemoryPercentages()) && resp.GetInMemoryPercentages()[i] >= 100 {
+			loaded.Insert(partitionID)
+		}
+	}
+	return loaded, nil
+}
+
+// partitionPruner prunes the partitions of a read before fan-out by the ranges of the clustering key in the filter.
+type partitionPruner struct {
+	qc             types.QueryCoordClient
+	dc             types.DataCoordClient
+	dbName         string
+	collectionName string
+	collectionID   UniqueID
+	schema         *schemaInfo
+	// consistencyLevel returns the consistency level of the read, the rows written within the stats ttl may be missed
+	// by the pruned read, so only the bounded and eventually consistent reads are pruned.
+	consistencyLevel func() (commonpb.ConsistencyLevel, bool)
+	queryType        string
+}
+
+// prune returns the partitions to read, the partitions read, all the loaded ones if empty, are returned as is on any
+// failure. At least one partition is kept, as reading no partition reads all of them.
+func (p *partitionPruner) prune(ctx context.Context, partitionIDs []UniqueID, predicates *planpb.Expr) []UniqueID {
+	if !Params.ProxyCfg.PartitionPruningEnabled.GetAsBool() || predicates == nil || p.qc == nil || p.dc == nil ||
+		p.schema == nil || p.schema.partitionStats == nil {
+		return partitionIDs
+	}
+	keyField, ok := lo.Find(p.schema.GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetIsClusteringKey()
+	})
+	if !ok || typeutil.IsVectorType(keyField.GetDataType()) {
+		return partitionIDs
+	}
+	ranges, matchAll := exprutil.ParseRanges(predicates, exprutil.ClusteringKey)
+	if matchAll || ranges == nil {
+		return partitionIDs
+	}
+	if level, ok := p.consistencyLevel(); !ok ||
+		(level != commonpb.ConsistencyLevel_Bounded && level != commonpb.ConsistencyLevel_Eventually) {
+		return partitionIDs
+	}
+
+	candidates := partitionIDs
+	if len(candidates) == 0 {
+		partitions, err := globalMetaCache.GetPartitions(ctx, p.dbName, p.collectionName)
+		if err != nil {
+			return partitionIDs
+		}
+		candidates = lo.Values(partitions)
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+	}
+	kept := make([]UniqueID, 0, len(candidates))
+	for _, partitionID := range candidates {
+		stats, ok := p.schema.partitionStats.get(p.dc, p.collectionID, partitionID, keyField.GetFieldID())
+		if !ok || !stats.covered || keyRangesOverlap(stats, ranges, keyField.GetDataType()) {
+			kept = append(kept, partitionID)
+		}
+	}
+	if len(candidates) > 0 {
+		metrics.ProxyPartitionPruneRatio.WithLabelValues(paramtable.GetStringNodeID(), p.queryType).
+			Observe(float64(len(candidates)-len(kept)) / float64(len(candidates)))
+	}
+	if len(kept) == len(candidates) {
+		return partitionIDs
+	}
+	if len(partitionIDs) == 0 {
+		// all the partitions are listed from the meta, the ones not loaded are not readable
+		loaded, err := getLoadedPartitions(ctx, p.qc, p.collectionID)
+		if err != nil {
+			return partitionIDs
+		}
+		kept = lo.Filter(kept, func(partitionID UniqueID, _ int) bool {
+			return loaded.Contain(partitionID)
+		})
+		if len(kept) == 0 {
+			kept = lo.Filter(candidates, func(partitionID UniqueID, _ int) bool {
+				return loaded.Contain(partitionID)
+			})
+			if len(kept) == 0 {
+				return partitionIDs
+			}
+			kept = kept[:1]
+		}
+	}
+	if len(kept) == 0 {
+		kept = candidates[:1]
+	}
+	return kept
+}
@@ -0,0 +1,287 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/planpb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/exprutil"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metautil"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func clusteringKeyExpr(op planpb.OpType, value *planpb.GenericValue) *planpb.Expr {
+	return &planpb.Expr{
+		Expr: &planpb.Expr_UnaryRangeExpr{
+			UnaryRangeExpr: &planpb.UnaryRangeExpr{
+				ColumnInfo: &planpb.ColumnInfo{FieldId: 101, IsClusteringKey: true},
+				Op:         op,
+				Value:      value,
+			},
+		},
+	}
+}
+
+func TestScalarToInt64(t *testing.T) {
+	for _, value := range []storage.ScalarFieldValue{
+		storage.NewInt8FieldValue(7),
+		storage.NewInt16FieldValue(7),
+		storage.NewInt32FieldValue(7),
+		storage.NewInt64FieldValue(7),
+	} {
+		v, ok := scalarToInt64(value)
+		assert.True(t, ok)
+		assert.EqualValues(t, 7, v)
+	}
+	_, ok := scalarToInt64(storage.NewVarCharFieldValue("7"))
+	assert.False(t, ok)
+}
+
+func TestKeyRangesOverlap(t *testing.T) {
+	intStats := &partitionKeyStats{min: storage.NewInt64FieldValue(10), max: storage.NewInt64FieldValue(20)}
+	parse := func(op planpb.OpType, value *planpb.GenericValue) []*exprutil.PlanRange {
+		ranges, matchAll := exprutil.ParseRanges(clusteringKeyExpr(op, value), exprutil.ClusteringKey)
+		assert.False(t, matchAll)
+		return ranges
+	}
+	intValue := func(v int64) *planpb.GenericValue {
+		return &planpb.GenericValue{Val: &planpb.GenericValue_Int64Val{Int64Val: v}}
+	}
+	assert.True(t, keyRangesOverlap(intStats, parse(planpb.OpType_Equal, intValue(15)), schemapb.DataType_Int64))
+	assert.True(t, keyRangesOverlap(intStats, parse(planpb.OpType_GreaterThan, intValue(19)), schemapb.DataType_Int64))
+	assert.False(t, keyRangesOverlap(intStats, parse(planpb.OpType_GreaterThan, intValue(25)), schemapb.DataType_Int64))
+	assert.False(t, keyRangesOverlap(intStats, parse(planpb.OpType_LessEqual, intValue(5)), schemapb.DataType_Int64))
+
+	strStats := &partitionKeyStats{min: storage.NewVarCharFieldValue("b"), max: storage.NewVarCharFieldValue("d")}
+	strValue := func(v string) *planpb.GenericValue {
+		return &planpb.GenericValue{Val: &planpb.GenericValue_StringVal{StringVal: v}}
+	}
+	assert.True(t, keyRangesOverlap(strStats, parse(planpb.OpType_Equal, strValue("c")), schemapb.DataType_VarChar))
+	assert.True(t, keyRangesOverlap(strStats, parse(planpb.OpType_GreaterEqual, strValue("a")), schemapb.DataType_VarChar))
+	assert.False(t, keyRangesOverlap(strStats, parse(planpb.OpType_GreaterThan, strValue("e")), schemapb.DataType_VarChar))
+	assert.False(t, keyRangesOverlap(strStats, parse(planpb.OpType_LessThan, strValue("a")), schemapb.DataType_VarChar))
+
+	// the statistics of an unexpected type never prune
+	assert.True(t, keyRangesOverlap(strStats, parse(planpb.OpType_Equal, intValue(15)), schemapb.DataType_Int64))
+	assert.True(t, keyRangesOverlap(intStats, parse(planpb.OpType_Equal, intValue(15)), schemapb.DataType_Float))
+}
+
+func TestLoadPartitionKeyStats(t *testing.T) {
+	ctx := context.Background()
+	cm := storage.NewLocalChunkManager(storage.RootPath(t.TempDir()))
+	const collectionID, partitionID, fieldID = int64(1), int64(2), int64(101)
+
+	writeStats := func(channel string, version int64, segments map[int64][2]int64) {
+		snapshot := storage.NewPartitionStatsSnapshot()
+		snapshot.SetVersion(version)
+		for segmentID, minMax := range segments {
+			snapshot.UpdateSegmentStats(segmentID, *storage.NewSegmentStats([]storage.FieldStats{{
+				FieldID: fieldID,
+				Type:    schemapb.DataType_Int64,
+				Min:     storage.NewInt64FieldValue(minMax[0]),
+				Max:     storage.NewInt64FieldValue(minMax[1]),
+			}}, 10))
+		}
+		data, err := storage.SerializePartitionStatsSnapshot(snapshot)
+		assert.NoError(t, err)
+		file := path.Join(cm.RootPath(), common.PartitionStatsPath, metautil.JoinIDPath(collectionID, partitionID), channel, metautil.JoinIDPath(version))
+		assert.NoError(t, cm.Write(ctx, file, data))
+	}
+	// the older version is ignored
+	writeStats("ch-0", 1, map[int64][2]int64{100: {0, 1000}})
+	writeStats("ch-0", 2, map[int64][2]int64{101: {10, 20}})
+	writeStats("ch-1", 3, map[int64][2]int64{102: {30, 40}})
+
+	t.Run("covered", func(t *testing.T) {
+		dc := mocks.NewMockDataCoordClient(t)
+		dc.EXPECT().GetSegmentsByStates(mock.Anything, mock.Anything).Return(&datapb.GetSegmentsByStatesResponse{
+			Status:   merr.Success(),
+			Segments: []int64{101, 102, 103},
+		}, nil)
+		dc.EXPECT().GetSegmentInfo(mock.Anything, mock.Anything).Return(&datapb.GetSegmentInfoResponse{
+			Status: merr.Success(),
+			Infos:  []*datapb.SegmentInfo{{ID: 103, Level: datapb.SegmentLevel_L0}},
+		}, nil)
+		stats, err := loadPartitionKeyStats(ctx, cm, dc, collectionID, partitionID, fieldID)
+		assert.NoError(t, err)
+		assert.True(t, stats.covered)
+		minValue, _ := scalarToInt64(stats.min)
+		maxValue, _ := scalarToInt64(stats.max)
+		assert.EqualValues(t, 10, minValue)
+		assert.EqualValues(t, 40, maxValue)
+	})
+
+	t.Run("not covered", func(t *testing.T) {
+		dc := mocks.NewMockDataCoordClient(t)
+		dc.EXPECT().GetSegmentsByStates(mock.Anything, mock.Anything).Return(&datapb.GetSegmentsByStatesResponse{
+			Status:   merr.Success(),
+			Segments: []int64{101, 102, 104},
+		}, nil)
+		dc.EXPECT().GetSegmentInfo(mock.Anything, mock.Anything).Return(&datapb.GetSegmentInfoResponse{
+			Status: merr.Success(),
+			Infos:  []*datapb.SegmentInfo{{ID: 104, Level: datapb.SegmentLevel_L1}},
+		}, nil)
+		stats, err := loadPartitionKeyStats(ctx, cm, dc, collectionID, partitionID, fieldID)
+		assert.NoError(t, err)
+		assert.False(t, stats.covered)
+	})
+
+	t.Run("no stats of the field", func(t *testing.T) {
+		dc := mocks.NewMockDataCoordClient(t)
+		stats, err := loadPartitionKeyStats(ctx, cm, dc, collectionID, partitionID, fieldID+1)
+		assert.NoError(t, err)
+		assert.False(t, stats.covered)
+	})
+
+	t.Run("no stats", func(t *testing.T) {
+		dc := mocks.NewMockDataCoordClient(t)
+		stats, err := loadPartitionKeyStats(ctx, cm, dc, collectionID, partitionID+1, fieldID)
+		assert.NoError(t, err)
+		assert.False(t, stats.covered)
+	})
+}
+
+func TestPartitionPrunerSkip(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	predicates := clusteringKeyExpr(planpb.OpType_Equal, &planpb.GenericValue{Val: &planpb.GenericValue_Int64Val{Int64Val: 1}})
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, DataType: schemapb.DataType_Int64, IsClusteringKey: true},
+		},
+	}
+	level := commonpb.ConsistencyLevel_Bounded
+	newPruner := func(schema *schemapb.CollectionSchema) *partitionPruner {
+		return &partitionPruner{
+			qc:     mocks.NewMockQueryCoordClient(t),
+			dc:     mocks.NewMockDataCoordClient(t),
+			schema: newSchemaInfo(schema),
+			consistencyLevel: func() (commonpb.ConsistencyLevel, bool) {
+				return level, true
+			},
+		}
+	}
+	partitionIDs := []int64{1, 2}
+
+	// disabled by default
+	assert.Equal(t, partitionIDs, newPruner(schema).prune(ctx, partitionIDs, predicates))
+	paramtable.Get().Save(Params.ProxyCfg.PartitionPruningEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.PartitionPruningEnabled.Key)
+
+	// the strict mocks fail the test if the pruning reads any statistics
+	assert.Equal(t, partitionIDs, newPruner(schema).prune(ctx, partitionIDs, nil))
+	assert.Equal(t, partitionIDs, newPruner(&schemapb.CollectionSchema{Fields: schema.Fields[:1]}).prune(ctx, partitionIDs, predicates))
+
+	level = commonpb.ConsistencyLevel_Strong
+	assert.Equal(t, partitionIDs, newPruner(schema).prune(ctx, partitionIDs, predicates))
+	level = commonpb.ConsistencyLevel_Session
+	assert.Equal(t, partitionIDs, newPruner(schema).prune(ctx, partitionIDs, predicates))
+}
+
+func TestPartitionPrunerPrune(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, DataType: schemapb.DataType_Int64, IsClusteringKey: true},
+		},
+	}
+	paramtable.Get().Save(Params.ProxyCfg.PartitionPruningEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.PartitionPruningEnabled.Key)
+	pruner := &partitionPruner{
+		qc:     mocks.NewMockQueryCoordClient(t),
+		dc:     mocks.NewMockDataCoordClient(t),
+		schema: newSchemaInfo(schema),
+		consistencyLevel: func() (commonpb.ConsistencyLevel, bool) {
+			return commonpb.ConsistencyLevel_Eventually, true
+		},
+		queryType: "test",
+	}
+	cache := pruner.schema.partitionStats
+	now := time.Now()
+	cache.entries[partitionStatsKey{partitionID: 1, fieldID: 101}] = &partitionKeyStats{
+		min: storage.NewInt64FieldValue(10), max: storage.NewInt64FieldValue(20), covered: true, loaded: now,
+	}
+	cache.entries[partitionStatsKey{partitionID: 2, fieldID: 101}] = &partitionKeyStats{
+		min: storage.NewInt64FieldValue(30), max: storage.NewInt64FieldValue(40), covered: true, loaded: now,
+	}
+	cache.entries[partitionStatsKey{partitionID: 3, fieldID: 101}] = &partitionKeyStats{
+		min: storage.NewInt64FieldValue(30), max: storage.NewInt64FieldValue(40), loaded: now,
+	}
+	equal := func(v int64) *planpb.Expr {
+		return clusteringKeyExpr(planpb.OpType_Equal, &planpb.GenericValue{Val: &planpb.GenericValue_Int64Val{Int64Val: v}})
+	}
+
+	assert.Equal(t, []int64{2}, pruner.prune(ctx, []int64{1, 2}, equal(35)))
+	assert.Equal(t, []int64{1, 2}, pruner.prune(ctx, []int64{1, 2}, clusteringKeyExpr(planpb.OpType_GreaterThan,
+		&planpb.GenericValue{Val: &planpb.GenericValue_Int64Val{Int64Val: 15}})))
+	// the partition not covered by the statistics is always read
+	assert.Equal(t, []int64{1, 3}, pruner.prune(ctx, []int64{1, 2, 3}, equal(15)))
+	// at least one partition is read
+	assert.Equal(t, []int64{1}, pruner.prune(ctx, []int64{1, 2}, equal(100)))
+}
+
+func TestPartitionStatsCache(t *testing.T) {
+	paramtable.Init()
+	saved := globalPartitionStatsStorage
+	defer func() { globalPartitionStatsStorage = saved }()
+	globalPartitionStatsStorage = &partitionStatsStorage{}
+
+	// the statistics not loaded are loaded in the background, the storage not initialized fails the loading
+	cache := newPartitionStatsCache()
+	dc := mocks.NewMockDataCoordClient(t)
+	_, ok := cache.get(dc, 1, 1, 101)
+	assert.False(t, ok)
+	assert.Eventually(t, func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return len(cache.loading) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	stats := &partitionKeyStats{covered: true, loaded: time.Now()}
+	cache.put(partitionStatsKey{partitionID: 1, fieldID: 101}, stats)
+	got, ok := cache.get(dc, 1, 1, 101)
+	assert.True(t, ok)
+	assert.Equal(t, stats, got)
+
+	// the expired statistics are not used
+	stats.loaded = time.Now().Add(-2 * Params.ProxyCfg.PartitionPruningStatsTTL.GetAsDuration(time.Second))
+	_, ok = cache.get(dc, 1, 1, 101)
+	assert.False(t, ok)
+
+	// bounded
+	for i := 0; i < maxCachedPartitionStats+10; i++ {
+		cache.put(partitionStatsKey{partitionID: int64(i + 2), fieldID: 101}, &partitionKeyStats{loaded: time.Now()})
+	}
+	assert.Len(t, cache.entries, maxCachedPartitionStats)
+}
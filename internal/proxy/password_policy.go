@@ -0,0 +1,345 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	// credentialRecordPrefix is the etcd prefix(under meta root path) of the password records, one key per user.
+	credentialRecordPrefix = "proxy/credential-record"
+	// maxAuthFailureEntries bounds the number of usernames whose authentication failures are tracked, since the
+	// failures of any username, existing or not, are tracked.
+	maxAuthFailureEntries = 10000
+)
+
+// credentialRecord is the password history of a user, which is used to enforce the history and expiration policy.
+type credentialRecord struct {
+	// History is the encrypted recent passwords, the latest first.
+	History    []string `json:"history"`
+	UpdateTime int64    `json:"update_time"`
+}
+
+type authFailure struct {
+	mu          sync.Mutex
+	count       int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+func (f *authFailure) getLockedUntil() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lockedUntil
+}
+
+func (f *authFailure) getLastFailure() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastFailure
+}
+
+// passwordPolicy enforces the password policies on credential changes,
+// and locks the account with exponential delay after repeated authentication failures.
+// The checks on each authentication only read the concurrent maps, no global lock is taken.
+type passwordPolicy struct {
+	mu       sync.RWMutex
+	kv       kv.BaseKV
+	records  *typeutil.ConcurrentMap[string, *credentialRecord]
+	failures *typeutil.ConcurrentMap[string, *authFailure]
+	// evictMu serializes the eviction of failures when the entries exceed maxAuthFailureEntries
+	evictMu sync.Mutex
+}
+
+var globalPasswordPolicy = newPasswordPolicy()
+
+func newPasswordPolicy() *passwordPolicy {
+	return &passwordPolicy{
+		records:  typeutil.NewConcurrentMap[string, *credentialRecord](),
+		failures: typeutil.NewConcurrentMap[string, *authFailure](),
+	}
+}
+
+func (p *passwordPolicy) init(baseKV kv.BaseKV) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.kv = baseKV
+}
+
+func (p *passwordPolicy) getKV() kv.BaseKV {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.kv
+}
+
+func credentialRecordKey(username string) string {
+	return path.Join(credentialRecordPrefix, username)
+}
+
+// Validate checks the new password of user against the length, complexity and history policies.
+func (p *passwordPolicy) Validate(username, password string) error {
+	if err := ValidatePassword(password); err != nil {
+		return err
+	}
+
+	if complexity := Params.ProxyCfg.PasswordComplexity.GetAsInt(); complexity > 0 {
+		var upper, lower, digit, special int
+		for _, c := range password {
+			switch {
+			case unicode.IsUpper(c):
+				upper = 1
+			case unicode.IsLower(c):
+				lower = 1
+			case unicode.IsDigit(c):
+				digit = 1
+			default:
+				special = 1
+			}
+		}
+		if classes := upper + lower + digit + special; classes < complexity {
+			return merr.WrapErrParameterInvalidMsg("password must contain at least %d of uppercase letters, lowercase letters, digits and special characters, but got %d",
+				complexity, classes)
+		}
+	}
+
+	if historySize := Params.ProxyCfg.PasswordHistorySize.GetAsInt(); historySize > 0 {
+		record, err := p.getRecord(username)
+		if err != nil {
+			return err
+		}
+		for i, encrypted := range record.History {
+			if i >= historySize {
+				break
+			}
+			if bcrypt.CompareHashAndPassword([]byte(encrypted), []byte(password)) == nil {
+				return merr.WrapErrParameterInvalidMsg("password can't be the same as the recent %d passwords", historySize)
+			}
+		}
+	}
+	return nil
+}
+
+// Record records the new encrypted password of user after the credential is created or updated.
+func (p *passwordPolicy) Record(ctx context.Context, username, encryptedPassword string) {
+	record, err := p.getRecord(username)
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to get credential record", zap.String("username", username), zap.Error(err))
+		record = &credentialRecord{}
+	}
+	history := append([]string{encryptedPassword}, record.History...)
+	if historySize := Params.ProxyCfg.PasswordHistorySize.GetAsInt(); len(history) > historySize+1 {
+		history = history[:historySize+1]
+	}
+	record = &credentialRecord{
+		History:    history,
+		UpdateTime: time.Now().Unix(),
+	}
+	if err := p.saveRecord(username, record); err != nil {
+		log.Ctx(ctx).Warn("failed to save credential record", zap.String("username", username), zap.Error(err))
+	}
+	auditLog(ctx, auditPasswordChanged, nil, zap.String("username", username), zap.String("operator", GetCurUserFromContextOrDefault(ctx)))
+}
+
+// Remove removes the records of user after the credential is deleted.
+func (p *passwordPolicy) Remove(username string) error {
+	p.records.Remove(username)
+	p.failures.Remove(username)
+	baseKV := p.getKV()
+	if baseKV == nil {
+		return nil
+	}
+	return baseKV.Remove(credentialRecordKey(username))
+}
+
+// Invalidate removes the cached record of user, since the password may be updated through other proxies.
+func (p *passwordPolicy) Invalidate(username string) {
+	p.records.Remove(username)
+}
+
+// getRecord returns the cached record of user, or loads it from etcd. The records are only loaded for the
+// authenticated users, so they are bounded by the number of users.
+func (p *passwordPolicy) getRecord(username string) (*credentialRecord, error) {
+	if record, ok := p.records.Get(username); ok {
+		return record, nil
+	}
+	record := &credentialRecord{}
+	baseKV := p.getKV()
+	if baseKV == nil {
+		return record, nil
+	}
+	value, err := baseKV.Load(credentialRecordKey(username))
+	if err != nil {
+		if errors.Is(err, merr.ErrIoKeyNotFound) {
+			record, _ = p.records.GetOrInsert(username, record)
+			return record, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(value), record); err != nil {
+		return nil, err
+	}
+	record, _ = p.records.GetOrInsert(username, record)
+	return record, nil
+}
+
+func (p *passwordPolicy) saveRecord(username string, record *credentialRecord) error {
+	p.records.Insert(username, record)
+	baseKV := p.getKV()
+	if baseKV == nil {
+		return nil
+	}
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return baseKV.Save(credentialRecordKey(username), string(value))
+}
+
+// CheckExpired returns error if the password of user is expired.
+// The users without record, which are created before the policy enabled, never expire.
+func (p *passwordPolicy) CheckExpired(ctx context.Context, username string) error {
+	expiration := Params.ProxyCfg.PasswordExpiration.GetAsInt64()
+	if expiration <= 0 {
+		return nil
+	}
+	record, err := p.getRecord(username)
+	if err != nil {
+		// never reject authentication because of the record
+		log.Ctx(ctx).Warn("failed to get credential record", zap.String("username", username), zap.Error(err))
+		return nil
+	}
+	if record.UpdateTime == 0 {
+		return nil
+	}
+	expireTime := time.Unix(record.UpdateTime, 0).Add(time.Duration(expiration) * 24 * time.Hour)
+	if time.Now().After(expireTime) {
+		auditLog(ctx, auditPasswordExpired, nil, zap.String("username", username), zap.Time("expireTime", expireTime))
+		return merr.WrapErrPrivilegeNotAuthenticated("password of %s expired at %s, please update the password", username, expireTime.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// CheckLocked returns error if the account is locked because of authentication failures.
+func (p *passwordPolicy) CheckLocked(username string) error {
+	failure, ok := p.failures.Get(username)
+	if !ok {
+		return nil
+	}
+	if remain := time.Until(failure.getLockedUntil()); remain > 0 {
+		return merr.WrapErrPrivilegeNotAuthenticated("account %s is locked because of too many authentication failures, please retry after %s",
+			username, remain.Round(time.Second))
+	}
+	return nil
+}
+
+// OnAuthFailure records the authentication failure, and locks the account if the failures exceed the limit.
+// The lockout duration doubles on each following failure, up to the max delay.
+func (p *passwordPolicy) OnAuthFailure(ctx context.Context, username string) {
+	nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	metrics.ProxyAuthFailureCount.WithLabelValues(nodeID).Inc()
+	auditLog(ctx, auditAuthFailure, nil, zap.String("username", username))
+
+	maxFailures := Params.ProxyCfg.AuthLockoutMaxFailures.GetAsInt()
+	if maxFailures <= 0 {
+		return
+	}
+	failure, ok := p.failures.Get(username)
+	if !ok {
+		p.evictFailures()
+		failure, _ = p.failures.GetOrInsert(username, &authFailure{})
+	}
+	failure.mu.Lock()
+	defer failure.mu.Unlock()
+	failure.count++
+	failure.lastFailure = time.Now()
+	if failure.count < maxFailures {
+		return
+	}
+
+	baseDelay := Params.ProxyCfg.AuthLockoutBaseDelay.GetAsDuration(time.Second)
+	maxDelay := Params.ProxyCfg.AuthLockoutMaxDelay.GetAsDuration(time.Second)
+	delay := baseDelay
+	for i := maxFailures; i < failure.count && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	failure.lockedUntil = time.Now().Add(delay)
+	metrics.ProxyAccountLockoutCount.WithLabelValues(nodeID).Inc()
+	auditLog(ctx, auditAccountLocked, nil, zap.String("username", username),
+		zap.Int("failures", failure.count), zap.Duration("duration", delay))
+}
+
+// OnAuthSuccess resets the authentication failures of user.
+func (p *passwordPolicy) OnAuthSuccess(username string) {
+	p.failures.Remove(username)
+}
+
+// evictFailures makes room for a new username if the failures reach maxAuthFailureEntries. The one failed least
+// recently is evicted, and the locked ones are kept unless all are locked, so the usernames made up by an attacker
+// can't flush the failures tracked of the other users, and can't grow without bound.
+func (p *passwordPolicy) evictFailures() {
+	if p.failures.Len() < maxAuthFailureEntries {
+		return
+	}
+	p.evictMu.Lock()
+	defer p.evictMu.Unlock()
+	if p.failures.Len() < maxAuthFailureEntries {
+		return
+	}
+
+	now := time.Now()
+	var oldest, oldestLocked string
+	var oldestTime, oldestLockedTime time.Time
+	p.failures.Range(func(username string, failure *authFailure) bool {
+		lastFailure := failure.getLastFailure()
+		if failure.getLockedUntil().After(now) {
+			if oldestLocked == "" || lastFailure.Before(oldestLockedTime) {
+				oldestLocked, oldestLockedTime = username, lastFailure
+			}
+		} else if oldest == "" || lastFailure.Before(oldestTime) {
+			oldest, oldestTime = username, lastFailure
+		}
+		return true
+	})
+	if oldest == "" {
+		oldest = oldestLocked
+	}
+	if oldest != "" {
+		p.failures.Remove(oldest)
+	}
+	log.RatedInfo(60, "too many usernames with authentication failures, evicted the least recent one", zap.String("username", oldest))
+}
@@ -0,0 +1,172 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	kvmocks "github.com/milvus-io/milvus/internal/kv/mocks"
+	"github.com/milvus-io/milvus/pkg/util/crypto"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	policy := newPasswordPolicy()
+
+	assert.Error(t, policy.Validate("user", "a"))
+	assert.NoError(t, policy.Validate("user", "password"))
+
+	paramtable.Get().Save(Params.ProxyCfg.PasswordComplexity.Key, "3")
+	defer paramtable.Get().Reset(Params.ProxyCfg.PasswordComplexity.Key)
+	assert.Error(t, policy.Validate("user", "password"))
+	assert.Error(t, policy.Validate("user", "Password"))
+	assert.NoError(t, policy.Validate("user", "Password1"))
+	assert.NoError(t, policy.Validate("user", "password_1"))
+
+	paramtable.Get().Save(Params.ProxyCfg.PasswordHistorySize.Key, "2")
+	defer paramtable.Get().Reset(Params.ProxyCfg.PasswordHistorySize.Key)
+	for _, password := range []string{"Password1", "Password2", "Password3"} {
+		encrypted, err := crypto.PasswordEncrypt(password)
+		assert.NoError(t, err)
+		policy.Record(ctx, "user", encrypted)
+	}
+	assert.NoError(t, policy.Validate("user", "Password1"))
+	assert.Error(t, policy.Validate("user", "Password2"))
+	assert.Error(t, policy.Validate("user", "Password3"))
+	assert.NoError(t, policy.Validate("other", "Password3"))
+}
+
+func TestPasswordPolicy_Expiration(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	watchKV := kvmocks.NewWatchKV(t)
+	policy := newPasswordPolicy()
+	policy.init(watchKV)
+
+	assert.NoError(t, policy.CheckExpired(ctx, "user"))
+
+	paramtable.Get().Save(Params.ProxyCfg.PasswordExpiration.Key, "1")
+	defer paramtable.Get().Reset(Params.ProxyCfg.PasswordExpiration.Key)
+
+	// users without record never expire
+	watchKV.EXPECT().Load("proxy/credential-record/user").Return("", merr.WrapErrIoKeyNotFound("user")).Once()
+	assert.NoError(t, policy.CheckExpired(ctx, "user"))
+
+	expired, _ := json.Marshal(&credentialRecord{UpdateTime: time.Now().Add(-48 * time.Hour).Unix()})
+	watchKV.EXPECT().Load("proxy/credential-record/expired").Return(string(expired), nil).Once()
+	assert.Error(t, policy.CheckExpired(ctx, "expired"))
+
+	watchKV.EXPECT().Save("proxy/credential-record/expired", mock.Anything).Return(nil).Once()
+	policy.Record(ctx, "expired", "encrypted")
+	assert.NoError(t, policy.CheckExpired(ctx, "expired"))
+
+	watchKV.EXPECT().Remove("proxy/credential-record/expired").Return(nil).Once()
+	assert.NoError(t, policy.Remove("expired"))
+}
+
+func TestPasswordPolicy_Lockout(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	policy := newPasswordPolicy()
+
+	for i := 0; i < 10; i++ {
+		policy.OnAuthFailure(ctx, "user")
+	}
+	assert.NoError(t, policy.CheckLocked("user"))
+
+	paramtable.Get().Save(Params.ProxyCfg.AuthLockoutMaxFailures.Key, "3")
+	defer paramtable.Get().Reset(Params.ProxyCfg.AuthLockoutMaxFailures.Key)
+	paramtable.Get().Save(Params.ProxyCfg.AuthLockoutMaxDelay.Key, "4")
+	defer paramtable.Get().Reset(Params.ProxyCfg.AuthLockoutMaxDelay.Key)
+	policy.OnAuthSuccess("user")
+
+	policy.OnAuthFailure(ctx, "user")
+	policy.OnAuthFailure(ctx, "user")
+	assert.NoError(t, policy.CheckLocked("user"))
+	policy.OnAuthFailure(ctx, "user")
+	assert.Error(t, policy.CheckLocked("user"))
+	lockedUntil := func(username string) time.Time {
+		failure, ok := policy.failures.Get(username)
+		assert.True(t, ok)
+		return failure.getLockedUntil()
+	}
+	assert.WithinDuration(t, time.Now().Add(time.Second), lockedUntil("user"), 100*time.Millisecond)
+
+	// exponential delay up to the max delay
+	policy.OnAuthFailure(ctx, "user")
+	assert.WithinDuration(t, time.Now().Add(2*time.Second), lockedUntil("user"), 100*time.Millisecond)
+	for i := 0; i < 5; i++ {
+		policy.OnAuthFailure(ctx, "user")
+	}
+	assert.WithinDuration(t, time.Now().Add(4*time.Second), lockedUntil("user"), 100*time.Millisecond)
+
+	assert.NoError(t, policy.CheckLocked("other"))
+	policy.OnAuthSuccess("user")
+	assert.NoError(t, policy.CheckLocked("user"))
+}
+
+func TestPasswordPolicy_EvictFailures(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	policy := newPasswordPolicy()
+
+	paramtable.Get().Save(Params.ProxyCfg.AuthLockoutMaxFailures.Key, "1")
+	defer paramtable.Get().Reset(Params.ProxyCfg.AuthLockoutMaxFailures.Key)
+
+	now := time.Now()
+	for i := 0; i < maxAuthFailureEntries-1; i++ {
+		policy.failures.Insert(fmt.Sprint(i), &authFailure{count: 1, lastFailure: now.Add(time.Duration(i-maxAuthFailureEntries) * time.Second)})
+	}
+	policy.failures.Insert("user", &authFailure{count: 1, lastFailure: now.Add(-time.Second)})
+
+	// the least recent one is evicted, the partial failures of user are kept
+	policy.OnAuthFailure(ctx, "sprayed0")
+	assert.Equal(t, maxAuthFailureEntries, policy.failures.Len())
+	_, ok := policy.failures.Get("0")
+	assert.False(t, ok)
+	failure, ok := policy.failures.Get("user")
+	assert.True(t, ok)
+	assert.Equal(t, 1, failure.count)
+
+	// the locked ones are kept
+	policy.failures.Insert("1", &authFailure{count: 1, lastFailure: now.Add(-24 * time.Hour), lockedUntil: now.Add(time.Minute)})
+	policy.OnAuthFailure(ctx, "sprayed1")
+	assert.Equal(t, maxAuthFailureEntries, policy.failures.Len())
+	assert.Error(t, policy.CheckLocked("1"))
+	_, ok = policy.failures.Get("2")
+	assert.False(t, ok)
+
+	// the least recent locked one is evicted if all are locked
+	policy.failures.Range(func(username string, failure *authFailure) bool {
+		failure.lockedUntil = now.Add(time.Minute)
+		return true
+	})
+	policy.OnAuthFailure(ctx, "sprayed2")
+	assert.Equal(t, maxAuthFailureEntries, policy.failures.Len())
+	assert.NoError(t, policy.CheckLocked("1"))
+	assert.Error(t, policy.CheckLocked("sprayed2"))
+}
@@ -0,0 +1,73 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/pkg/log"
+)
+
+// prefixHandler keeps an in-memory copy of the values under an etcd prefix.
+type prefixHandler interface {
+	// reload replaces the copy with all the values under the prefix.
+	reload() error
+	// handleEvent applies a change made by the other proxies.
+	handleEvent(eventType mvccpb.Event_EventType, key, value []byte)
+}
+
+// watchPrefix synchronizes the handler with the changes under prefix until ctx is done,
+// the handler is reloaded whenever the watch is broken, so that no change is missed.
+func watchPrefix(ctx context.Context, watchKV kv.WatchKV, prefix string, name string, handler prefixHandler) {
+	log := log.With(zap.String("watcher", name), zap.String("prefix", prefix))
+	watchCh := watchKV.WatchWithPrefix(prefix)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("watch loop exit")
+			return
+		case resp, ok := <-watchCh:
+			if !ok || resp.Err() != nil {
+				log.Warn("watch channel closed, rewatch", zap.Error(resp.Err()))
+				if err := handler.reload(); err != nil {
+					log.Warn("failed to reload", zap.Error(err))
+				}
+				time.Sleep(time.Second)
+				watchCh = watchKV.WatchWithPrefix(prefix)
+				continue
+			}
+			for _, event := range resp.Events {
+				handler.handleEvent(event.Type, event.Kv.Key, event.Kv.Value)
+			}
+		}
+	}
+}
+
+// trimRootPath returns the key relative to the meta root path, which starts with prefix.
+func trimRootPath(key []byte, prefix string) (string, bool) {
+	idx := strings.Index(string(key), prefix)
+	if idx < 0 {
+		return "", false
+	}
+	return string(key)[idx:], true
+}
@@ -0,0 +1,83 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus/internal/kv/mocks"
+)
+
+type fakePrefixHandler struct {
+	reloads atomic.Int32
+	keys    chan string
+}
+
+func (h *fakePrefixHandler) reload() error {
+	h.reloads.Inc()
+	return nil
+}
+
+func (h *fakePrefixHandler) handleEvent(eventType mvccpb.Event_EventType, key, value []byte) {
+	h.keys <- string(key)
+}
+
+func TestWatchPrefix(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broken := make(chan clientv3.WatchResponse, 1)
+	broken <- clientv3.WatchResponse{Events: []*clientv3.Event{{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte("by-dev/meta/prefix/k1")}}}}
+	close(broken)
+	watchCh := make(chan clientv3.WatchResponse, 1)
+
+	watchKV := mocks.NewWatchKV(t)
+	watchKV.EXPECT().WatchWithPrefix("prefix").Return(broken).Once()
+	watchKV.EXPECT().WatchWithPrefix("prefix").Return(watchCh).Once()
+
+	handler := &fakePrefixHandler{keys: make(chan string, 2)}
+	done := make(chan struct{})
+	go func() {
+		watchPrefix(ctx, watchKV, "prefix", "test", handler)
+		close(done)
+	}()
+
+	assert.Equal(t, "by-dev/meta/prefix/k1", <-handler.keys)
+	// rewatch and reload after the channel is closed
+	assert.Eventually(t, func() bool { return handler.reloads.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+	watchCh <- clientv3.WatchResponse{Events: []*clientv3.Event{{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte("by-dev/meta/prefix/k2")}}}}
+	assert.Equal(t, "by-dev/meta/prefix/k2", <-handler.keys)
+
+	cancel()
+	<-done
+}
+
+func TestTrimRootPath(t *testing.T) {
+	key, ok := trimRootPath([]byte("by-dev/meta/prefix/k1"), "prefix")
+	assert.True(t, ok)
+	assert.Equal(t, "prefix/k1", key)
+
+	_, ok = trimRootPath([]byte("by-dev/meta/other/k1"), "prefix")
+	assert.False(t, ok)
+}
@@ -15,6 +15,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/federpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util"
@@ -45,6 +46,24 @@ m = r.sub == p.sub && globMatch(r.obj, p.obj) && globMatch(r.act, p.act) || r.su
 
 var templateModel = getPolicyModel(ModelStr)
 
+// extraPrivilegeExts are the privileges of the requests whose messages are not annotated with the privilege ext.
+var extraPrivilegeExts = map[reflect.Type]commonpb.PrivilegeExt{
+	// the same as DescribeIndex, the collection name is the 2nd field
+	reflect.TypeOf(&federpb.DescribeSegmentIndexDataRequest{}): {
+		ObjectType:      commonpb.ObjectType_Collection,
+		ObjectPrivilege: commonpb.ObjectPrivilege_PrivilegeIndexDetail,
+		ObjectNameIndex: 2,
+	},
+}
+
+// getPrivilegeExt returns the privilege ext of the request.
+func getPrivilegeExt(req interface{}) (commonpb.PrivilegeExt, error) {
+	if privilegeExt, ok := extraPrivilegeExts[reflect.TypeOf(req)]; ok {
+		return privilegeExt, nil
+	}
+	return funcutil.GetPrivilegeExtObj(req)
+}
+
 var (
 	enforcer *casbin.SyncedEnforcer
 	initOnce sync.Once
@@ -90,7 +109,7 @@ func PrivilegeInterceptor(ctx context.Context, req interface{}) (context.Context
 	}
 	log := log.Ctx(ctx)
 	log.RatedDebug(60, "PrivilegeInterceptor", zap.String("type", reflect.TypeOf(req).String()))
-	privilegeExt, err := funcutil.GetPrivilegeExtObj(req)
+	privilegeExt, err := getPrivilegeExt(req)
 	if err != nil {
 		log.RatedInfo(60, "GetPrivilegeExtObj err", zap.Error(err))
 		return ctx, nil
@@ -132,8 +151,9 @@ func PrivilegeInterceptor(ctx context.Context, req interface{}) (context.Context
 		zap.Int32("object_indexs", objectNameIndexs), zap.Strings("object_names", objectNames))
 
 	e := getEnforcer()
+	aliasNames := make(map[string][]string)
 	for _, roleName := range roleNames {
-		permitFunc := func(resName string) (bool, error) {
+		enforceFunc := func(resName string) (bool, error) {
 			object := funcutil.PolicyForResource(dbName, objectType, resName)
 			isPermit, err := e.Enforce(roleName, object, objectPrivilege)
 			if err != nil {
@@ -141,6 +161,23 @@ func PrivilegeInterceptor(ctx context.Context, req interface{}) (context.Context
 			}
 			return isPermit, nil
 		}
+		permitFunc := func(resName string) (bool, error) {
+			isPermit, err := enforceFunc(resName)
+			if err != nil || isPermit || objectType != commonpb.ObjectType_Collection.String() {
+				return isPermit, err
+			}
+			names, ok := aliasNames[resName]
+			if !ok {
+				names = getAliasPrivilegeNames(ctx, dbName, resName)
+				aliasNames[resName] = names
+			}
+			for _, name := range names {
+				if isPermit, err := enforceFunc(name); err != nil || isPermit {
+					return isPermit, err
+				}
+			}
+			return false, nil
+		}
 
 		if objectNameIndex != 0 {
 			// handle the api which refers one resource
@@ -184,6 +221,38 @@ func PrivilegeInterceptor(ctx context.Context, req interface{}) (context.Context
 		fmt.Sprintf("%s: permission deny to %s in the `%s` database", objectPrivilege, username, dbName))
 }
 
+const (
+	aliasPrivilegeModeAlias         = "alias"
+	aliasPrivilegeModeBidirectional = "bidirectional"
+)
+
+// getAliasPrivilegeNames returns the other names of the collection whose privileges also apply to the name in request,
+// which are the aliased collection if the name is an alias, or the aliases if the name is a collection in bidirectional mode.
+func getAliasPrivilegeNames(ctx context.Context, dbName string, name string) []string {
+	mode := Params.ProxyCfg.AliasPrivilegeMode.GetValue()
+	if mode != aliasPrivilegeModeAlias && mode != aliasPrivilegeModeBidirectional {
+		return nil
+	}
+	if name == "" || util.IsAnyWord(name) {
+		return nil
+	}
+	info, err := globalMetaCache.GetAliasInfo(ctx, dbName, name)
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to resolve alias for privilege check", zap.String("name", name), zap.Error(err))
+		return nil
+	}
+	if info.collectionName == "" {
+		return nil
+	}
+	if info.collectionName != name {
+		return []string{info.collectionName}
+	}
+	if mode != aliasPrivilegeModeBidirectional {
+		return nil
+	}
+	return info.aliases
+}
+
 // isCurUserObject Determine whether it is an Object of type User that operates on its own user information,
 // like updating password or viewing your own role information.
 // make users operate their own user information when the related privileges are not granted.
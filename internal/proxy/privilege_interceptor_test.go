@@ -7,8 +7,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/federpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/internal/mocks"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
@@ -126,6 +128,10 @@ func TestPrivilegeInterceptor(t *testing.T) {
 			CollectionName: "col1",
 		})
 		assert.Error(t, err)
+		_, err = PrivilegeInterceptor(ctx, &federpb.DescribeSegmentIndexDataRequest{
+			CollectionName: "col1",
+		})
+		assert.Error(t, err)
 		_, err = PrivilegeInterceptor(fooCtx, &milvuspb.GetLoadingProgressRequest{
 			CollectionName: "col1",
 		})
@@ -174,6 +180,46 @@ func TestPrivilegeInterceptor(t *testing.T) {
 	})
 }
 
+func TestAliasPrivilege(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+	defer paramtable.Get().Reset(Params.ProxyCfg.AliasPrivilegeMode.Key)
+
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	mockCache := NewMockCache(t)
+	globalMetaCache = mockCache
+	mockCache.EXPECT().GetPrivilegeInfo(mock.Anything).Return([]string{
+		funcutil.PolicyForPrivilege("role1", commonpb.ObjectType_Collection.String(), "col1", commonpb.ObjectPrivilege_PrivilegeLoad.String(), "default"),
+		funcutil.PolicyForPrivilege("role1", commonpb.ObjectType_Collection.String(), "alias2", commonpb.ObjectPrivilege_PrivilegeLoad.String(), "default"),
+	}).Maybe()
+	mockCache.EXPECT().GetUserRole("alice").Return([]string{"role1"}).Maybe()
+	mockCache.EXPECT().GetAliasInfo(mock.Anything, "default", "alias1").Return(&aliasInfo{collectionName: "col1"}, nil).Maybe()
+	mockCache.EXPECT().GetAliasInfo(mock.Anything, "default", "col2").Return(&aliasInfo{collectionName: "col2", aliases: []string{"alias2"}}, nil).Maybe()
+	mockCache.EXPECT().GetAliasInfo(mock.Anything, "default", "col3").Return(&aliasInfo{}, nil).Maybe()
+	assert.NoError(t, getEnforcer().LoadPolicy())
+
+	ctx := GetContext(context.Background(), "alice:123456")
+	load := func(collectionName string) error {
+		_, err := PrivilegeInterceptor(ctx, &milvuspb.LoadCollectionRequest{CollectionName: collectionName})
+		return err
+	}
+
+	assert.NoError(t, load("col1"))
+	assert.Error(t, load("alias1"))
+	assert.Error(t, load("col2"))
+
+	paramtable.Get().Save(Params.ProxyCfg.AliasPrivilegeMode.Key, aliasPrivilegeModeAlias)
+	assert.NoError(t, load("alias1"))
+	assert.Error(t, load("col2"))
+
+	paramtable.Get().Save(Params.ProxyCfg.AliasPrivilegeMode.Key, aliasPrivilegeModeBidirectional)
+	assert.NoError(t, load("alias1"))
+	assert.NoError(t, load("col2"))
+	assert.Error(t, load("col3"))
+}
+
 func TestResourceGroupPrivilege(t *testing.T) {
 	ctx := context.Background()
 
@@ -0,0 +1,160 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/retry"
+)
+
+// produceWithRetry produces the dml messages with bounded attempts and backoff.
+// The messages are produced one by one, so that the messages produced successfully are not retried. The retry is
+// at least once: a message may be duplicated if the failed attempt was persisted by the mq anyway, e.g. timed out
+// waiting for the ack, or produced to some of the channels it's repacked to. The duplicated deletes are harmless,
+// while the duplicated inserts are the rows of the same primary key, which are deduplicated by query and search
+// but counted twice by the row count statistics.
+// The message failed after all the attempts is sent to the dead letter channel if enabled.
+func produceWithRetry(ctx context.Context, stream msgstream.MsgStream, msgPack *msgstream.MsgPack, msgType string) error {
+	collectionName := msgPackCollectionName(msgPack)
+	produce := func(pack *msgstream.MsgPack) error {
+		// the dropped messages are lost silently
+		if dropped, err := globalFaultInjector.inject(ctx, faultPointProduce, collectionName); dropped || err != nil {
+			return err
+		}
+		return stream.Produce(pack)
+	}
+
+	attempts := Params.ProxyCfg.ProduceRetryMaxAttempts.GetAsInt()
+	if attempts <= 1 {
+		return produce(msgPack)
+	}
+
+	nodeID := paramtable.GetStringNodeID()
+	backoff := Params.ProxyCfg.ProduceRetryBackoff.GetAsDuration(time.Millisecond)
+	for _, msg := range msgPack.Msgs {
+		pack := &msgstream.MsgPack{
+			BeginTs: msgPack.BeginTs,
+			EndTs:   msgPack.EndTs,
+			Msgs:    []msgstream.TsMsg{msg},
+		}
+		tried := 0
+		err := retry.Do(ctx, func() error {
+			if tried > 0 {
+				metrics.ProxyProduceFailureCount.WithLabelValues(nodeID, msgType, metrics.ProduceRetriedLabel).Inc()
+			}
+			tried++
+			return produce(pack)
+		}, retry.Attempts(uint(attempts)), retry.Sleep(backoff), retry.MaxSleepTime(10*backoff), retry.RetryErr(isProduceRetryable))
+		if err == nil {
+			continue
+		}
+
+		log.Ctx(ctx).Warn("failed to produce dml message after retry",
+			zap.String("msgType", msgType),
+			zap.Int64("msgID", msg.ID()),
+			zap.Int("attempts", tried),
+			zap.Error(err))
+		metrics.ProxyProduceFailureCount.WithLabelValues(nodeID, msgType, metrics.ProduceAbandonedLabel).Inc()
+		if isProduceRetryable(err) {
+			globalDeadLetterQueue.send(ctx, msg, msgType)
+		}
+		return err
+	}
+	return nil
+}
+
+// isProduceRetryable returns false for the errors that never succeed by retry.
+func isProduceRetryable(err error) bool {
+	return !errors.IsAny(err, merr.ErrDenyProduceMsg, context.Canceled)
+}
+
+// deadLetterQueue keeps the dml messages failed to produce to the dml channels for the manual recovery.
+type deadLetterQueue struct {
+	mu      sync.Mutex
+	factory msgstream.Factory
+	stream  msgstream.MsgStream
+}
+
+var globalDeadLetterQueue = &deadLetterQueue{}
+
+func (q *deadLetterQueue) init(factory msgstream.Factory) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.factory = factory
+}
+
+// getStream creates the producer of the dead letter channel at the first use.
+func (q *deadLetterQueue) getStream(ctx context.Context) (msgstream.MsgStream, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stream != nil {
+		return q.stream, nil
+	}
+	if q.factory == nil {
+		return nil, merr.WrapErrServiceNotReady(paramtable.GetRole(), paramtable.GetNodeID(), "dead letter queue")
+	}
+	stream, err := q.factory.NewMsgStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stream.SetRepackFunc(defaultInsertRepackFunc)
+	stream.EnableProduce(true)
+	stream.AsProducer([]string{Params.CommonCfg.ClusterPrefix.GetValue() + "-" + Params.ProxyCfg.ProduceDeadLetterChannel.GetValue()})
+	q.stream = stream
+	return stream, nil
+}
+
+// send produces the message to the dead letter channel if enabled, the failure is only logged.
+func (q *deadLetterQueue) send(ctx context.Context, msg msgstream.TsMsg, msgType string) {
+	if !Params.ProxyCfg.ProduceDeadLetterEnabled.GetAsBool() {
+		return
+	}
+	stream, err := q.getStream(ctx)
+	if err == nil {
+		err = stream.Produce(&msgstream.MsgPack{
+			BeginTs: msg.BeginTs(),
+			EndTs:   msg.EndTs(),
+			Msgs:    []msgstream.TsMsg{msg},
+		})
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to produce dml message to dead letter channel",
+			zap.String("msgType", msgType), zap.Int64("msgID", msg.ID()), zap.Error(err))
+		return
+	}
+	metrics.ProxyProduceFailureCount.WithLabelValues(paramtable.GetStringNodeID(), msgType, metrics.ProduceDeadLetterLabel).Inc()
+}
+
+func (q *deadLetterQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stream != nil {
+		q.stream.Close()
+		q.stream = nil
+	}
+}
@@ -0,0 +1,108 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestProduceWithRetry(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(Params.ProxyCfg.ProduceRetryBackoff.Key, "1")
+	defer params.Reset(Params.ProxyCfg.ProduceRetryBackoff.Key)
+
+	ctx := context.Background()
+	msgPack := &msgstream.MsgPack{
+		Msgs: []msgstream.TsMsg{newMockDeleteMsg(1), newMockDeleteMsg(2)},
+	}
+
+	t.Run("retry until success", func(t *testing.T) {
+		stream := msgstream.NewMockMsgStream(t)
+		stream.EXPECT().Produce(mock.Anything).Return(errors.New("mock")).Once()
+		stream.EXPECT().Produce(mock.Anything).RunAndReturn(func(pack *msgstream.MsgPack) error {
+			assert.Len(t, pack.Msgs, 1)
+			return nil
+		}).Twice()
+		assert.NoError(t, produceWithRetry(ctx, stream, msgPack, metrics.DeleteLabel))
+	})
+
+	t.Run("abandoned after attempts", func(t *testing.T) {
+		stream := msgstream.NewMockMsgStream(t)
+		stream.EXPECT().Produce(mock.Anything).Return(errors.New("mock")).Times(3)
+		assert.Error(t, produceWithRetry(ctx, stream, msgPack, metrics.DeleteLabel))
+	})
+
+	t.Run("not retry denied produce", func(t *testing.T) {
+		stream := msgstream.NewMockMsgStream(t)
+		stream.EXPECT().Produce(mock.Anything).Return(merr.ErrDenyProduceMsg).Once()
+		assert.ErrorIs(t, produceWithRetry(ctx, stream, msgPack, metrics.DeleteLabel), merr.ErrDenyProduceMsg)
+	})
+
+	t.Run("retry disabled", func(t *testing.T) {
+		params.Save(Params.ProxyCfg.ProduceRetryMaxAttempts.Key, "1")
+		defer params.Reset(Params.ProxyCfg.ProduceRetryMaxAttempts.Key)
+		stream := msgstream.NewMockMsgStream(t)
+		stream.EXPECT().Produce(msgPack).Return(errors.New("mock")).Once()
+		assert.Error(t, produceWithRetry(ctx, stream, msgPack, metrics.DeleteLabel))
+	})
+}
+
+func newMockDeleteMsg(id int64) *msgstream.DeleteMsg {
+	return &msgstream.DeleteMsg{
+		DeleteRequest: msgpb.DeleteRequest{Base: &commonpb.MsgBase{MsgType: commonpb.MsgType_Delete, MsgID: id}},
+	}
+}
+
+func TestDeadLetterQueue(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(Params.ProxyCfg.ProduceDeadLetterEnabled.Key, "true")
+	defer params.Reset(Params.ProxyCfg.ProduceDeadLetterEnabled.Key)
+
+	ctx := context.Background()
+	// not initialized, only logged
+	q := &deadLetterQueue{}
+	q.send(ctx, newMockDeleteMsg(1), metrics.DeleteLabel)
+
+	stream := msgstream.NewMockMsgStream(t)
+	stream.EXPECT().SetRepackFunc(mock.Anything).Return()
+	stream.EXPECT().EnableProduce(true).Return()
+	stream.EXPECT().AsProducer([]string{Params.CommonCfg.ClusterPrefix.GetValue() + "-" + Params.ProxyCfg.ProduceDeadLetterChannel.GetValue()}).Return()
+	stream.EXPECT().Produce(mock.Anything).Return(nil).Twice()
+	stream.EXPECT().Close().Return()
+	factory := msgstream.NewMockMqFactory()
+	factory.NewMsgStreamFunc = func(ctx context.Context) (msgstream.MsgStream, error) {
+		return stream, nil
+	}
+	q.init(factory)
+	q.send(ctx, newMockDeleteMsg(1), metrics.DeleteLabel)
+	q.send(ctx, newMockDeleteMsg(1), metrics.DeleteLabel)
+	q.close()
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"path"
 	"strconv"
 	"sync"
 	"time"
@@ -34,6 +35,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/hook"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/internal/allocator"
+	etcdkv "github.com/milvus-io/milvus/internal/kv/etcd"
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proxy/accesslog"
 	"github.com/milvus-io/milvus/internal/proxy/connection"
@@ -277,6 +279,22 @@ func (node *Proxy) Init() error {
 	}
 	node.replicateMsgStream.EnableProduce(true)
 	node.replicateMsgStream.AsProducer([]string{replicateMsgChannel})
+	globalDeadLetterQueue.init(node.factory)
+	globalResultSpiller.init(node.factory)
+	globalPartitionStatsStorage.init(node.factory)
+	globalChangeStreamer.init(node.factory, node.chMgr, node.dataCoord)
+	if node.etcdCli != nil {
+		metaKV := etcdkv.NewEtcdKV(node.etcdCli, Params.EtcdCfg.MetaRootPath.GetValue())
+		globalJobRegistry.init(node, metaKV)
+		globalIngestionManager.init(node, metaKV)
+		globalJobNotifier.init(metaKV, globalJobRegistry)
+		node.simpleLimiter.SetDistributedLimiter(newDistributedRateLimiter(
+			newEtcdRateBackend(node.etcdCli, path.Join(Params.EtcdCfg.MetaRootPath.GetValue(), distributedRateLimitPrefix),
+				path.Join(Params.EtcdCfg.MetaRootPath.GetValue(), sessionutil.DefaultServiceRoot))))
+	} else {
+		globalJobRegistry.init(node, nil)
+		globalJobNotifier.init(nil, globalJobRegistry)
+	}
 
 	node.sched, err = newTaskScheduler(node.ctx, node.tsoAllocator, node.factory)
 	if err != nil {
@@ -297,6 +315,43 @@ func (node *Proxy) Init() error {
 		return err
 	}
 	log.Debug("init meta cache done", zap.String("role", typeutil.ProxyRole))
+	globalReplicaTopology.init(node.queryCoord)
+
+	globalSearchWorkLimiter = newSearchWorkLimiter(newDataCoordRowCountFetcher(node.dataCoord))
+	globalLDAPAuthenticator.start(node.ctx)
+
+	if node.etcdCli != nil {
+		watchKV := etcdkv.NewEtcdKV(node.etcdCli, Params.EtcdCfg.MetaRootPath.GetValue())
+		if err := globalOperationSuspender.init(node.ctx, watchKV); err != nil {
+			log.Warn("failed to init operation suspender", zap.String("role", typeutil.ProxyRole), zap.Error(err))
+			return err
+		}
+		log.Debug("init operation suspender done", zap.String("role", typeutil.ProxyRole))
+
+		globalUsageAccountant.init(node.ctx, watchKV)
+		globalPasswordPolicy.init(watchKV)
+
+		if err := globalMaskingPolicy.init(node.ctx, watchKV); err != nil {
+			log.Warn("failed to init masking policy", zap.String("role", typeutil.ProxyRole), zap.Error(err))
+			return err
+		}
+
+		if err := globalNetworkPolicy.init(node.ctx, watchKV); err != nil {
+			log.Warn("failed to init network policy", zap.String("role", typeutil.ProxyRole), zap.Error(err))
+			return err
+		}
+
+		globalRatesSnapshot.init(watchKV)
+		node.recoverRates()
+
+		globalCollectionRecycleBin = newCollectionRecycleBin(watchKV, node.rootCoord, node.queryCoord)
+		globalCollectionRecycleBin.start(node.ctx)
+
+		globalAliasSwapCleaner = newAliasSwapCleaner(watchKV, node.rootCoord, node.queryCoord)
+		globalAliasSwapCleaner.start(node.ctx)
+
+		globalImportAutoLoader = node.newImportAutoLoader(watchKV)
+	}
 
 	node.enableMaterializedView = Params.CommonCfg.EnableMaterializedView.GetAsBool()
 
@@ -415,6 +470,28 @@ func (node *Proxy) Start() error {
 
 	node.sendChannelsTimeTickLoop()
 
+	node.wg.Add(1)
+	go node.importAutoLoadLoop()
+	node.wg.Add(1)
+	go node.memoryWatchdogLoop()
+	node.wg.Add(1)
+	go node.loadWarmupLoop()
+	node.wg.Add(1)
+	go node.insertSmoothingLoop()
+	node.wg.Add(1)
+	go node.indexCoverageLoop()
+	node.wg.Add(1)
+	go node.resultSpillGCLoop()
+	node.wg.Add(1)
+	go node.snowflakeWorkerLoop()
+
+	if err := globalIngestionManager.start(); err != nil {
+		log.Warn("failed to start ingestion sources", zap.String("role", typeutil.ProxyRole), zap.Error(err))
+		return err
+	}
+	globalJobNotifier.start(node.ctx)
+	globalReplicaTopology.start(node.ctx)
+
 	// Start callbacks
 	for _, cb := range node.startCallbacks {
 		cb()
@@ -459,6 +536,11 @@ func (node *Proxy) Stop() error {
 		log.Info("close channels time ticker", zap.String("role", typeutil.ProxyRole))
 	}
 
+	if node.tsoAllocator != nil {
+		node.tsoAllocator.close()
+		log.Info("close timestamp allocator", zap.String("role", typeutil.ProxyRole))
+	}
+
 	for _, cb := range node.closeCallbacks {
 		cb()
 	}
@@ -474,6 +556,10 @@ func (node *Proxy) Stop() error {
 	if node.chMgr != nil {
 		node.chMgr.removeAllDMLStream()
 	}
+	globalDeadLetterQueue.close()
+	globalIngestionManager.close()
+	globalJobNotifier.close()
+	globalReplicaTopology.close()
 
 	if node.lbPolicy != nil {
 		node.lbPolicy.Close()
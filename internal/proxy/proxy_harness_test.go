@@ -0,0 +1,399 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/allocator"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/dependency"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const harnessDim = 8
+
+// proxyHarness runs a proxy against the fake coordinators and query nodes, so the rpc paths of the proxy are tested
+// as a black box through a grpc client, with the interceptors of the milvus service in the chain and the behaviors
+// of the coordinators and the query nodes scripted by the tests.
+type proxyHarness struct {
+	ctx    context.Context
+	node   *Proxy
+	client milvuspb.MilvusServiceClient
+	rc     *fakeRootCoord
+	qc     *fakeQueryCoord
+	dc     *fakeDataCoord
+
+	mu         sync.Mutex
+	queryNodes map[int64]*fakeQueryNode
+}
+
+func newProxyHarness(t *testing.T) *proxyHarness {
+	ctx := context.Background()
+	factory := dependency.NewDefaultFactory(true)
+	node, err := NewProxy(ctx, factory)
+	require.NoError(t, err)
+
+	h := &proxyHarness{
+		ctx:        ctx,
+		node:       node,
+		rc:         newFakeRootCoord(),
+		qc:         newFakeQueryCoord(),
+		dc:         newFakeDataCoord(),
+		queryNodes: make(map[int64]*fakeQueryNode),
+	}
+	node.session = &sessionutil.Session{SessionRaw: sessionutil.SessionRaw{ServerID: paramtable.GetNodeID()}}
+	node.SetRootCoordClient(h.rc)
+	node.SetQueryCoordClient(h.qc)
+	node.SetDataCoordClient(h.dc)
+	node.SetQueryNodeCreator(func(ctx context.Context, addr string, nodeID int64) (types.QueryNodeClient, error) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		qn, ok := h.queryNodes[nodeID]
+		if !ok {
+			return nil, merr.WrapErrNodeNotFound(nodeID)
+		}
+		return qn, nil
+	})
+
+	// the allocators and the channels of the dml, the messages are produced to the mock streams
+	node.rowIDAllocator, err = allocator.NewIDAllocator(node.ctx, h.rc, paramtable.GetNodeID())
+	require.NoError(t, err)
+	require.NoError(t, node.rowIDAllocator.Start())
+	node.tsoAllocator, err = newTimestampAllocator(h.rc, paramtable.GetNodeID())
+	require.NoError(t, err)
+	node.segAssigner, err = newSegIDAssigner(node.ctx, h.dc, func() Timestamp { return 0 })
+	require.NoError(t, err)
+	node.segAssigner.PeerID = paramtable.GetNodeID()
+	require.NoError(t, node.segAssigner.Start())
+	node.chMgr = newChannelsMgrImpl(getDmlChannelsFunc(node.ctx, h.rc), defaultInsertRepackFunc, newSimpleMockMsgStreamFactory())
+
+	node.sched, err = newTaskScheduler(node.ctx, newMockTsoAllocator(), factory)
+	require.NoError(t, err)
+	require.NoError(t, node.sched.Start())
+	require.NoError(t, InitMetaCache(ctx, h.rc, h.qc, node.shardMgr))
+	node.UpdateStateCode(commonpb.StateCode_Healthy)
+
+	// the interceptors of the proxy in the chain of the milvus service
+	limiter, err := node.GetRateLimiter()
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+		FailureReasonInterceptor(),
+		grpc_auth.UnaryServerInterceptor(AuthenticationInterceptor),
+		DatabaseInterceptor(),
+		UnaryServerHookInterceptor(),
+		UnaryServerInterceptor(PrivilegeInterceptor),
+		OperationSuspendInterceptor(),
+		RequestSizeLimitInterceptor(),
+		RateLimitInterceptor(limiter),
+		UsageAccountingInterceptor(),
+		SessionAffinityInterceptor(),
+		TraceLogInterceptor,
+	)))
+	milvuspb.RegisterMilvusServiceServer(server, node)
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	go server.Serve(lis)
+	conn, err := grpc.DialContext(ctx, lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	h.client = milvuspb.NewMilvusServiceClient(conn)
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+		node.sched.Close()
+		node.segAssigner.Close()
+		node.rowIDAllocator.Close()
+		node.chMgr.removeAllDMLStream()
+		node.cancel()
+	})
+	return h
+}
+
+// addQueryNode adds a query node for the proxy to connect to as a shard leader.
+func (h *proxyHarness) addQueryNode(nodeID int64) *fakeQueryNode {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	qn := newFakeQueryNode(nodeID)
+	h.queryNodes[nodeID] = qn
+	return qn
+}
+
+// load loads the collection through the query coord and makes the query node the shard leader of all its channels,
+// the proxy requires the indexes to load. It returns the collection id and the channels.
+func (h *proxyHarness) load(t *testing.T, collectionName string, qn *fakeQueryNode) (int64, []string) {
+	collectionID, err := globalMetaCache.GetCollectionID(h.ctx, "", collectionName)
+	require.NoError(t, err)
+	channels, err := h.node.chMgr.getVChannels(collectionID)
+	require.NoError(t, err)
+
+	status, err := h.qc.LoadCollection(h.ctx, &querypb.LoadCollectionRequest{CollectionID: collectionID})
+	require.NoError(t, merr.CheckRPCCall(status, err))
+	for _, channel := range channels {
+		h.qc.setShardLeaders(collectionID, channel, qn.nodeID)
+	}
+	qn.lead(channels...)
+	return collectionID, channels
+}
+
+// createCollection creates a collection of an int64 primary key and a float vector through the proxy.
+func (h *proxyHarness) createCollection(ctx context.Context, collectionName string) (*commonpb.Status, error) {
+	schema := constructCollectionSchema("pk", "vec", harnessDim, collectionName)
+	bytes, err := proto.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	return h.client.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		CollectionName: collectionName,
+		Schema:         bytes,
+		ShardsNum:      1,
+	})
+}
+
+func TestProxyHarness_CollectionLifecycle(t *testing.T) {
+	h := newProxyHarness(t)
+	collectionName := "harness_" + funcutil.GenRandomStr()
+
+	status, err := h.createCollection(h.ctx, collectionName)
+	assert.NoError(t, merr.CheckRPCCall(status, err))
+
+	hasResp, err := h.client.HasCollection(h.ctx, &milvuspb.HasCollectionRequest{CollectionName: collectionName})
+	assert.NoError(t, merr.CheckRPCCall(hasResp, err))
+	assert.True(t, hasResp.GetValue())
+
+	describeResp, err := h.client.DescribeCollection(h.ctx, &milvuspb.DescribeCollectionRequest{CollectionName: collectionName})
+	assert.NoError(t, merr.CheckRPCCall(describeResp, err))
+	assert.Equal(t, collectionName, describeResp.GetSchema().GetName())
+
+	showResp, err := h.client.ShowCollections(h.ctx, &milvuspb.ShowCollectionsRequest{})
+	assert.NoError(t, merr.CheckRPCCall(showResp, err))
+	assert.Contains(t, showResp.GetCollectionNames(), collectionName)
+
+	status, err = h.client.DropCollection(h.ctx, &milvuspb.DropCollectionRequest{CollectionName: collectionName})
+	assert.NoError(t, merr.CheckRPCCall(status, err))
+
+	hasResp, err = h.client.HasCollection(h.ctx, &milvuspb.HasCollectionRequest{CollectionName: collectionName})
+	assert.NoError(t, merr.CheckRPCCall(hasResp, err))
+	assert.False(t, hasResp.GetValue())
+
+	assert.Equal(t, 1, h.rc.script.callCount("CreateCollection"))
+	assert.Equal(t, 1, h.rc.script.callCount("DropCollection"))
+}
+
+func TestProxyHarness_ErrorSequence(t *testing.T) {
+	h := newProxyHarness(t)
+	collectionName := "harness_" + funcutil.GenRandomStr()
+
+	mockErr := errors.New("mock create collection error")
+	h.rc.script.failNext("CreateCollection", mockErr, merr.WrapErrServiceInternal("mock"), nil)
+
+	status, err := h.createCollection(h.ctx, collectionName)
+	assert.Error(t, merr.CheckRPCCall(status, err))
+	status, err = h.createCollection(h.ctx, collectionName)
+	assert.ErrorIs(t, merr.CheckRPCCall(status, err), merr.ErrServiceInternal)
+	status, err = h.createCollection(h.ctx, collectionName)
+	assert.NoError(t, merr.CheckRPCCall(status, err))
+	assert.Equal(t, 3, h.rc.script.callCount("CreateCollection"))
+
+	// the errors of any method fail the calls of the methods without their own errors
+	h.dc.script.failNext(anyMethod, mockErr)
+	healthResp, err := h.client.CheckHealth(h.ctx, &milvuspb.CheckHealthRequest{})
+	assert.NoError(t, merr.CheckRPCCall(healthResp, err))
+	assert.False(t, healthResp.GetIsHealthy())
+	healthResp, err = h.client.CheckHealth(h.ctx, &milvuspb.CheckHealthRequest{})
+	assert.NoError(t, merr.CheckRPCCall(healthResp, err))
+	assert.True(t, healthResp.GetIsHealthy())
+}
+
+func TestProxyHarness_Latency(t *testing.T) {
+	h := newProxyHarness(t)
+	collectionName := "harness_" + funcutil.GenRandomStr()
+	status, err := h.createCollection(h.ctx, collectionName)
+	require.NoError(t, merr.CheckRPCCall(status, err))
+
+	h.rc.script.setLatency("ShowCollections", time.Minute)
+	ctx, cancel := context.WithTimeout(h.ctx, 100*time.Millisecond)
+	defer cancel()
+	showResp, err := h.client.ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{})
+	assert.Error(t, merr.CheckRPCCall(showResp, err))
+
+	h.rc.script.setLatency("ShowCollections", 10*time.Millisecond)
+	start := time.Now()
+	showResp, err = h.client.ShowCollections(h.ctx, &milvuspb.ShowCollectionsRequest{})
+	assert.NoError(t, merr.CheckRPCCall(showResp, err))
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	assert.Contains(t, showResp.GetCollectionNames(), collectionName)
+}
+
+func TestProxyHarness_LeaderChange(t *testing.T) {
+	h := newProxyHarness(t)
+
+	oldLeader := h.qc.script.getLeaderID()
+	newLeader := h.qc.script.changeLeader(2)
+	assert.NotEqual(t, oldLeader, newLeader)
+
+	for i := 0; i < 2; i++ {
+		healthResp, err := h.client.CheckHealth(h.ctx, &milvuspb.CheckHealthRequest{})
+		assert.NoError(t, merr.CheckRPCCall(healthResp, err))
+		assert.False(t, healthResp.GetIsHealthy())
+	}
+	healthResp, err := h.client.CheckHealth(h.ctx, &milvuspb.CheckHealthRequest{})
+	assert.NoError(t, merr.CheckRPCCall(healthResp, err))
+	assert.True(t, healthResp.GetIsHealthy())
+
+	states, err := h.qc.GetComponentStates(h.ctx, &milvuspb.GetComponentStatesRequest{})
+	assert.NoError(t, merr.CheckRPCCall(states, err))
+	assert.Equal(t, newLeader, states.GetState().GetNodeID())
+}
+
+func TestProxyHarness_LoadState(t *testing.T) {
+	h := newProxyHarness(t)
+	collectionName := "harness_" + funcutil.GenRandomStr()
+	status, err := h.createCollection(h.ctx, collectionName)
+	require.NoError(t, merr.CheckRPCCall(status, err))
+	collectionID, err := globalMetaCache.GetCollectionID(h.ctx, "", collectionName)
+	require.NoError(t, err)
+
+	loadState, err := h.client.GetLoadState(h.ctx, &milvuspb.GetLoadStateRequest{CollectionName: collectionName})
+	assert.NoError(t, merr.CheckRPCCall(loadState, err))
+	assert.Equal(t, commonpb.LoadState_LoadStateNotLoad, loadState.GetState())
+
+	// load through the query coord directly, the proxy requires the indexes to load
+	_, err = h.qc.LoadCollection(h.ctx, &querypb.LoadCollectionRequest{CollectionID: collectionID})
+	assert.NoError(t, err)
+	loadState, err = h.client.GetLoadState(h.ctx, &milvuspb.GetLoadStateRequest{CollectionName: collectionName})
+	assert.NoError(t, merr.CheckRPCCall(loadState, err))
+	assert.Equal(t, commonpb.LoadState_LoadStateLoaded, loadState.GetState())
+
+	status, err = h.client.ReleaseCollection(h.ctx, &milvuspb.ReleaseCollectionRequest{CollectionName: collectionName})
+	assert.NoError(t, merr.CheckRPCCall(status, err))
+	assert.Equal(t, 1, h.qc.script.callCount("ReleaseCollection"))
+	loadState, err = h.client.GetLoadState(h.ctx, &milvuspb.GetLoadStateRequest{CollectionName: collectionName})
+	assert.NoError(t, merr.CheckRPCCall(loadState, err))
+	assert.Equal(t, commonpb.LoadState_LoadStateNotLoad, loadState.GetState())
+}
+
+func TestProxyHarness_InsertDelete(t *testing.T) {
+	h := newProxyHarness(t)
+	collectionName := "harness_" + funcutil.GenRandomStr()
+	status, err := h.createCollection(h.ctx, collectionName)
+	require.NoError(t, merr.CheckRPCCall(status, err))
+
+	// the primary keys are auto ids
+	rowNum := 10
+	insertResp, err := h.client.Insert(h.ctx, &milvuspb.InsertRequest{
+		CollectionName: collectionName,
+		FieldsData:     []*schemapb.FieldData{newFloatVectorFieldData("vec", rowNum, harnessDim)},
+		HashKeys:       generateHashKeys(rowNum),
+		NumRows:        uint32(rowNum),
+	})
+	assert.NoError(t, merr.CheckRPCCall(insertResp, err))
+	assert.Equal(t, int64(rowNum), insertResp.GetInsertCnt())
+	assert.Len(t, insertResp.GetIDs().GetIntId().GetData(), rowNum)
+	assert.Equal(t, 1, h.dc.script.callCount("AssignSegmentID"))
+
+	pks := insertResp.GetIDs().GetIntId().GetData()[:2]
+	deleteResp, err := h.client.Delete(h.ctx, &milvuspb.DeleteRequest{
+		CollectionName: collectionName,
+		Expr:           fmt.Sprintf("pk in [%d, %d]", pks[0], pks[1]),
+	})
+	assert.NoError(t, merr.CheckRPCCall(deleteResp, err))
+	assert.Equal(t, int64(2), deleteResp.GetDeleteCnt())
+}
+
+func TestProxyHarness_SearchQuery(t *testing.T) {
+	h := newProxyHarness(t)
+	collectionName := "harness_" + funcutil.GenRandomStr()
+	status, err := h.createCollection(h.ctx, collectionName)
+	require.NoError(t, merr.CheckRPCCall(status, err))
+	qn := h.addQueryNode(1)
+	qn.setRows(1, 2, 3, 4, 5)
+	h.load(t, collectionName, qn)
+
+	searchResp, err := h.client.Search(h.ctx, constructSearchRequest("", collectionName, "", "vec", 2, harnessDim, 10, 3, -1))
+	assert.NoError(t, merr.CheckRPCCall(searchResp, err))
+	assert.Equal(t, []int64{3, 3}, searchResp.GetResults().GetTopks())
+	assert.Equal(t, []int64{1, 2, 3, 1, 2, 3}, searchResp.GetResults().GetIds().GetIntId().GetData())
+
+	queryResp, err := h.client.Query(h.ctx, &milvuspb.QueryRequest{
+		CollectionName: collectionName,
+		Expr:           "pk >= 0",
+		OutputFields:   []string{"pk"},
+	})
+	assert.NoError(t, merr.CheckRPCCall(queryResp, err))
+	require.Len(t, queryResp.GetFieldsData(), 1)
+	assert.Equal(t, "pk", queryResp.GetFieldsData()[0].GetFieldName())
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, queryResp.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+
+	assert.Equal(t, 1, qn.script.callCount("Search"))
+	assert.Equal(t, 1, qn.script.callCount("Query"))
+}
+
+func TestProxyHarness_ShardLeaderChange(t *testing.T) {
+	h := newProxyHarness(t)
+	collectionName := "harness_" + funcutil.GenRandomStr()
+	status, err := h.createCollection(h.ctx, collectionName)
+	require.NoError(t, merr.CheckRPCCall(status, err))
+	oldLeader, newLeader := h.addQueryNode(1), h.addQueryNode(2)
+	oldLeader.setRows(1, 2, 3)
+	newLeader.setRows(1, 2, 3)
+	collectionID, channels := h.load(t, collectionName, oldLeader)
+
+	searchReq := constructSearchRequest("", collectionName, "", "vec", 1, harnessDim, 10, 3, -1)
+	searchResp, err := h.client.Search(h.ctx, searchReq)
+	assert.NoError(t, merr.CheckRPCCall(searchResp, err))
+	assert.Equal(t, 1, oldLeader.script.callCount("Search"))
+
+	// the shard leaders move to the new leader, the proxy retries on it after the old one fails
+	oldLeader.resign(channels...)
+	newLeader.lead(channels...)
+	for _, channel := range channels {
+		h.qc.setShardLeaders(collectionID, channel, newLeader.nodeID)
+	}
+	searchResp, err = h.client.Search(h.ctx, searchReq)
+	assert.NoError(t, merr.CheckRPCCall(searchResp, err))
+	assert.Equal(t, []int64{1, 2, 3}, searchResp.GetResults().GetIds().GetIntId().GetData())
+	assert.Equal(t, 2, oldLeader.script.callCount("Search"))
+	assert.Equal(t, 1, newLeader.script.callCount("Search"))
+
+	// the new leaders are cached
+	searchResp, err = h.client.Search(h.ctx, searchReq)
+	assert.NoError(t, merr.CheckRPCCall(searchResp, err))
+	assert.Equal(t, 2, oldLeader.script.callCount("Search"))
+	assert.Equal(t, 2, newLeader.script.callCount("Search"))
+}
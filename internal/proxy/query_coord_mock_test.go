@@ -0,0 +1,259 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/atomic"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+	"github.com/milvus-io/milvus/pkg/util/uniquegenerator"
+)
+
+// QueryCoordMock keeps the load states of the collections and the partitions, and the shard leaders set by the tests
+// for the loaded collections. The methods it doesn't implement fail as the unexpected calls of the mockery mock.
+type QueryCoordMock struct {
+	*mocks.MockQueryCoordClient
+
+	nodeID typeutil.UniqueID
+	state  atomic.Int32
+
+	mu sync.RWMutex
+	// the collections loaded as a whole
+	loadedCollections typeutil.UniqueSet
+	// the partitions loaded by collection
+	loadedPartitions map[typeutil.UniqueID]typeutil.UniqueSet
+	// the node ids of the shard leaders by channel by collection
+	shardLeaders map[typeutil.UniqueID]map[string][]typeutil.UniqueID
+}
+
+func NewQueryCoordMock() *QueryCoordMock {
+	qc := &QueryCoordMock{
+		MockQueryCoordClient: &mocks.MockQueryCoordClient{},
+		nodeID:               typeutil.UniqueID(uniquegenerator.GetUniqueIntGeneratorIns().GetInt()),
+		loadedCollections:    typeutil.NewUniqueSet(),
+		loadedPartitions:     make(map[typeutil.UniqueID]typeutil.UniqueSet),
+		shardLeaders:         make(map[typeutil.UniqueID]map[string][]typeutil.UniqueID),
+	}
+	qc.updateState(commonpb.StateCode_Healthy)
+	return qc
+}
+
+func (coord *QueryCoordMock) updateState(state commonpb.StateCode) {
+	coord.state.Store(int32(state))
+}
+
+func (coord *QueryCoordMock) getState() commonpb.StateCode {
+	return commonpb.StateCode(coord.state.Load())
+}
+
+func (coord *QueryCoordMock) healthy() bool {
+	return coord.getState() == commonpb.StateCode_Healthy
+}
+
+// setShardLeaders sets the shard leaders of the channel of the collection, which replace the former ones.
+func (coord *QueryCoordMock) setShardLeaders(collectionID typeutil.UniqueID, channel string, nodeIDs ...typeutil.UniqueID) {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	leaders, ok := coord.shardLeaders[collectionID]
+	if !ok {
+		leaders = make(map[string][]typeutil.UniqueID)
+		coord.shardLeaders[collectionID] = leaders
+	}
+	leaders[channel] = nodeIDs
+}
+
+func (coord *QueryCoordMock) isLoaded(collectionID typeutil.UniqueID) bool {
+	return coord.loadedCollections.Contain(collectionID) || coord.loadedPartitions[collectionID].Len() > 0
+}
+
+func (coord *QueryCoordMock) Close() error {
+	return nil
+}
+
+func (coord *QueryCoordMock) GetComponentStates(ctx context.Context, req *milvuspb.GetComponentStatesRequest, opts ...grpc.CallOption) (*milvuspb.ComponentStates, error) {
+	return &milvuspb.ComponentStates{
+		State: &milvuspb.ComponentInfo{
+			NodeID:    coord.nodeID,
+			Role:      typeutil.QueryCoordRole,
+			StateCode: coord.getState(),
+		},
+		Status: merr.Success(),
+	}, nil
+}
+
+func (coord *QueryCoordMock) CheckHealth(ctx context.Context, req *milvuspb.CheckHealthRequest, opts ...grpc.CallOption) (*milvuspb.CheckHealthResponse, error) {
+	if !coord.healthy() {
+		return &milvuspb.CheckHealthResponse{
+			Status:    merr.Status(merr.WrapErrServiceNotReady(typeutil.QueryCoordRole, coord.nodeID, coord.getState().String())),
+			IsHealthy: false,
+		}, nil
+	}
+	return &milvuspb.CheckHealthResponse{Status: merr.Success(), IsHealthy: true}, nil
+}
+
+func (coord *QueryCoordMock) LoadCollection(ctx context.Context, req *querypb.LoadCollectionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if !coord.healthy() {
+		return merr.Status(merr.WrapErrServiceNotReady(typeutil.QueryCoordRole, coord.nodeID, coord.getState().String())), nil
+	}
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	coord.loadedCollections.Insert(req.GetCollectionID())
+	return merr.Success(), nil
+}
+
+func (coord *QueryCoordMock) LoadPartitions(ctx context.Context, req *querypb.LoadPartitionsRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if !coord.healthy() {
+		return merr.Status(merr.WrapErrServiceNotReady(typeutil.QueryCoordRole, coord.nodeID, coord.getState().String())), nil
+	}
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	partitions, ok := coord.loadedPartitions[req.GetCollectionID()]
+	if !ok {
+		partitions = typeutil.NewUniqueSet()
+		coord.loadedPartitions[req.GetCollectionID()] = partitions
+	}
+	partitions.Insert(req.GetPartitionIDs()...)
+	return merr.Success(), nil
+}
+
+func (coord *QueryCoordMock) ReleaseCollection(ctx context.Context, req *querypb.ReleaseCollectionRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if !coord.healthy() {
+		return merr.Status(merr.WrapErrServiceNotReady(typeutil.QueryCoordRole, coord.nodeID, coord.getState().String())), nil
+	}
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	coord.loadedCollections.Remove(req.GetCollectionID())
+	delete(coord.loadedPartitions, req.GetCollectionID())
+	delete(coord.shardLeaders, req.GetCollectionID())
+	return merr.Success(), nil
+}
+
+func (coord *QueryCoordMock) ReleasePartitions(ctx context.Context, req *querypb.ReleasePartitionsRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+	if !coord.healthy() {
+		return merr.Status(merr.WrapErrServiceNotReady(typeutil.QueryCoordRole, coord.nodeID, coord.getState().String())), nil
+	}
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	coord.loadedPartitions[req.GetCollectionID()].Remove(req.GetPartitionIDs()...)
+	return merr.Success(), nil
+}
+
+func (coord *QueryCoordMock) ShowCollections(ctx context.Context, req *querypb.ShowCollectionsRequest, opts ...grpc.CallOption) (*querypb.ShowCollectionsResponse, error) {
+	if !coord.healthy() {
+		return &querypb.ShowCollectionsResponse{
+			Status: merr.Status(merr.WrapErrServiceNotReady(typeutil.QueryCoordRole, coord.nodeID, coord.getState().String())),
+		}, nil
+	}
+	coord.mu.RLock()
+	defer coord.mu.RUnlock()
+
+	collectionIDs := req.GetCollectionIDs()
+	if len(collectionIDs) == 0 {
+		loaded := typeutil.NewUniqueSet(coord.loadedCollections.Collect()...)
+		for collectionID := range coord.loadedPartitions {
+			if coord.isLoaded(collectionID) {
+				loaded.Insert(collectionID)
+			}
+		}
+		collectionIDs = loaded.Collect()
+	}
+	resp := &querypb.ShowCollectionsResponse{Status: merr.Success()}
+	for _, collectionID := range collectionIDs {
+		if !coord.isLoaded(collectionID) {
+			return &querypb.ShowCollectionsResponse{
+				Status: merr.Status(merr.WrapErrCollectionNotLoaded(collectionID)),
+			}, nil
+		}
+		resp.CollectionIDs = append(resp.CollectionIDs, collectionID)
+		resp.InMemoryPercentages = append(resp.InMemoryPercentages, 100)
+		resp.QueryServiceAvailable = append(resp.QueryServiceAvailable, true)
+		resp.RefreshProgress = append(resp.RefreshProgress, 100)
+	}
+	return resp, nil
+}
+
+func (coord *QueryCoordMock) ShowPartitions(ctx context.Context, req *querypb.ShowPartitionsRequest, opts ...grpc.CallOption) (*querypb.ShowPartitionsResponse, error) {
+	if !coord.healthy() {
+		return &querypb.ShowPartitionsResponse{
+			Status: merr.Status(merr.WrapErrServiceNotReady(typeutil.QueryCoordRole, coord.nodeID, coord.getState().String())),
+		}, nil
+	}
+	coord.mu.RLock()
+	defer coord.mu.RUnlock()
+
+	collectionID := req.GetCollectionID()
+	if !coord.isLoaded(collectionID) {
+		return &querypb.ShowPartitionsResponse{
+			Status: merr.Status(merr.WrapErrCollectionNotLoaded(collectionID)),
+		}, nil
+	}
+	partitionIDs := req.GetPartitionIDs()
+	if len(partitionIDs) == 0 {
+		partitionIDs = coord.loadedPartitions[collectionID].Collect()
+	}
+	resp := &querypb.ShowPartitionsResponse{Status: merr.Success()}
+	for _, partitionID := range partitionIDs {
+		// all the partitions of a collection loaded as a whole are loaded
+		if !coord.loadedCollections.Contain(collectionID) && !coord.loadedPartitions[collectionID].Contain(partitionID) {
+			return &querypb.ShowPartitionsResponse{
+				Status: merr.Status(merr.WrapErrPartitionNotLoaded(partitionID)),
+			}, nil
+		}
+		resp.PartitionIDs = append(resp.PartitionIDs, partitionID)
+		resp.InMemoryPercentages = append(resp.InMemoryPercentages, 100)
+		resp.RefreshProgress = append(resp.RefreshProgress, 100)
+	}
+	return resp, nil
+}
+
+func (coord *QueryCoordMock) GetShardLeaders(ctx context.Context, req *querypb.GetShardLeadersRequest, opts ...grpc.CallOption) (*querypb.GetShardLeadersResponse, error) {
+	coord.mu.RLock()
+	defer coord.mu.RUnlock()
+	if !coord.isLoaded(req.GetCollectionID()) {
+		return &querypb.GetShardLeadersResponse{
+			Status: merr.Status(merr.WrapErrCollectionNotLoaded(req.GetCollectionID())),
+		}, nil
+	}
+	leaders := coord.shardLeaders[req.GetCollectionID()]
+	resp := &querypb.GetShardLeadersResponse{Status: merr.Success()}
+	for channel, nodeIDs := range leaders {
+		shard := &querypb.ShardLeadersList{ChannelName: channel, NodeIds: nodeIDs}
+		for _, nodeID := range nodeIDs {
+			shard.NodeAddrs = append(shard.NodeAddrs, fmt.Sprintf("querynode-%d", nodeID))
+		}
+		resp.Shards = append(resp.Shards, shard)
+	}
+	sort.Slice(resp.Shards, func(i, j int) bool {
+		return resp.Shards[i].GetChannelName() < resp.Shards[j].GetChannelName()
+	})
+	return resp, nil
+}
+
+func (coord *QueryCoordMock) GetReplicas(ctx context.Context, req *milvuspb.GetReplicasRequest, opts ...grpc.CallOption) (*milvuspb.GetReplicasResponse, error) {
+	return &milvuspb.GetReplicasResponse{Status: merr.Success()}, nil
+}
@@ -0,0 +1,243 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// queryEnrichment enriches the query results with the fields of the rows of another collection in the same database,
+// whose primary keys are the values of the key field of the results. The enriched fields are named as
+// <collection>.<field>, and are the zero values for the rows of no matched row.
+type queryEnrichment struct {
+	collectionName string
+	keyField       string
+	outputFields   []string
+}
+
+// parseQueryEnrichment returns the enrichment of the query params, or nil if not enriched.
+func parseQueryEnrichment(params []*commonpb.KeyValuePair) (*queryEnrichment, error) {
+	collectionName, err := funcutil.GetAttrByKeyFromRepeatedKV(EnrichCollectionKey, params)
+	if err != nil || collectionName == "" {
+		return nil, nil
+	}
+	keyField, err := funcutil.GetAttrByKeyFromRepeatedKV(EnrichKeyFieldKey, params)
+	if err != nil || keyField == "" {
+		return nil, merr.WrapErrParameterMissing(EnrichKeyFieldKey)
+	}
+	outputFields, err := funcutil.GetAttrByKeyFromRepeatedKV(EnrichOutputFieldsKey, params)
+	if err != nil || strings.TrimSpace(outputFields) == "" {
+		return nil, merr.WrapErrParameterMissing(EnrichOutputFieldsKey)
+	}
+	return &queryEnrichment{
+		collectionName: collectionName,
+		keyField:       keyField,
+		outputFields: lo.Uniq(lo.FilterMap(strings.Split(outputFields, ","), func(field string, _ int) (string, bool) {
+			field = strings.TrimSpace(field)
+			return field, field != ""
+		})),
+	}, nil
+}
+
+// enrichRow is the idx-th row of the fields data looked up in the enrich collection.
+type enrichRow struct {
+	fieldsData []*schemapb.FieldData
+	idx        int64
+}
+
+// enrichKeys returns the keys of the rows as the ids of the enrich collection, and the distinct ones.
+func enrichKeys(keyData *schemapb.FieldData, pkType schemapb.DataType) (*schemapb.IDs, *schemapb.IDs, error) {
+	switch {
+	case pkType == schemapb.DataType_Int64 && keyData.GetScalars().GetLongData() != nil:
+		keys := keyData.GetScalars().GetLongData().GetData()
+		return &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: keys}}},
+			&schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: lo.Uniq(keys)}}}, nil
+	case pkType == schemapb.DataType_Int64 && keyData.GetScalars().GetIntData() != nil:
+		keys := lo.Map(keyData.GetScalars().GetIntData().GetData(), func(key int32, _ int) int64 { return int64(key) })
+		return &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: keys}}},
+			&schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: lo.Uniq(keys)}}}, nil
+	case pkType == schemapb.DataType_VarChar && keyData.GetScalars().GetStringData() != nil:
+		keys := keyData.GetScalars().GetStringData().GetData()
+		return &schemapb.IDs{IdField: &schemapb.IDs_StrId{StrId: &schemapb.StringArray{Data: keys}}},
+			&schemapb.IDs{IdField: &schemapb.IDs_StrId{StrId: &schemapb.StringArray{Data: lo.Uniq(keys)}}}, nil
+	}
+	return nil, nil, merr.WrapErrParameterInvalidMsg("the type %s of the enrich key field %s mismatches the primary key type %s",
+		keyData.GetType(), keyData.GetFieldName(), pkType)
+}
+
+// appendZeroValue appends the zero value to the scalar field data.
+func appendZeroValue(fieldData *schemapb.FieldData) {
+	switch data := fieldData.GetScalars().GetData().(type) {
+	case *schemapb.ScalarField_BoolData:
+		data.BoolData.Data = append(data.BoolData.Data, false)
+	case *schemapb.ScalarField_IntData:
+		data.IntData.Data = append(data.IntData.Data, 0)
+	case *schemapb.ScalarField_LongData:
+		data.LongData.Data = append(data.LongData.Data, 0)
+	case *schemapb.ScalarField_FloatData:
+		data.FloatData.Data = append(data.FloatData.Data, 0)
+	case *schemapb.ScalarField_DoubleData:
+		data.DoubleData.Data = append(data.DoubleData.Data, 0)
+	case *schemapb.ScalarField_StringData:
+		data.StringData.Data = append(data.StringData.Data, "")
+	case *schemapb.ScalarField_JsonData:
+		data.JsonData.Data = append(data.JsonData.Data, []byte("{}"))
+	case *schemapb.ScalarField_ArrayData:
+		data.ArrayData.Data = append(data.ArrayData.Data, &schemapb.ScalarField{})
+	}
+}
+
+// enrichQueryResults looks up the rows of the enrich collection in batches, and appends the enriched fields to the
+// results. The results are replaced by the error if the enrichment fails.
+func (node *Proxy) enrichQueryResults(ctx context.Context, request *milvuspb.QueryRequest, rsp *milvuspb.QueryResults) *milvuspb.QueryResults {
+	if !merr.Ok(rsp.GetStatus()) {
+		return rsp
+	}
+	enrichment, err := parseQueryEnrichment(request.GetQueryParams())
+	if err != nil {
+		return &milvuspb.QueryResults{Status: merr.Status(err)}
+	}
+	if enrichment == nil {
+		return rsp
+	}
+	fieldsData, err := node.enrich(ctx, request, enrichment, rsp.GetFieldsData())
+	if err != nil {
+		return &milvuspb.QueryResults{Status: merr.Status(err)}
+	}
+	rsp.FieldsData = append(rsp.FieldsData, fieldsData...)
+	rsp.OutputFields = append(rsp.OutputFields, lo.Map(fieldsData, func(fieldData *schemapb.FieldData, _ int) string {
+		return fieldData.GetFieldName()
+	})...)
+	return rsp
+}
+
+func (node *Proxy) enrich(ctx context.Context, request *milvuspb.QueryRequest, enrichment *queryEnrichment, results []*schemapb.FieldData) ([]*schemapb.FieldData, error) {
+	keyData, ok := lo.Find(results, func(fieldData *schemapb.FieldData) bool {
+		return fieldData.GetFieldName() == enrichment.keyField
+	})
+	if !ok {
+		return nil, merr.WrapErrParameterInvalidMsg("the enrich key field %s must be in the output fields", enrichment.keyField)
+	}
+	schema, err := globalMetaCache.GetCollectionSchema(ctx, request.GetDbName(), enrichment.collectionName)
+	if err != nil {
+		return nil, err
+	}
+	pkField, err := schema.GetPkField()
+	if err != nil {
+		return nil, err
+	}
+	keys, distinct, err := enrichKeys(keyData, pkField.GetDataType())
+	if err != nil {
+		return nil, err
+	}
+	if maxKeys := Params.ProxyCfg.QueryEnrichmentMaxKeys.GetAsInt(); typeutil.GetSizeOfIDs(distinct) > maxKeys {
+		return nil, merr.WrapErrParameterInvalidMsg("the query results have %d distinct enrich keys, more than the max %d",
+			typeutil.GetSizeOfIDs(distinct), maxKeys)
+	}
+
+	enriched := make([]*schemapb.FieldData, 0, len(enrichment.outputFields))
+	for _, name := range enrichment.outputFields {
+		field, ok := lo.Find(schema.GetFields(), func(field *schemapb.FieldSchema) bool { return field.GetName() == name })
+		if !ok {
+			return nil, merr.WrapErrFieldNotFound(name, "the enrich field not found in collection "+enrichment.collectionName)
+		}
+		if typeutil.IsVectorType(field.GetDataType()) {
+			return nil, merr.WrapErrParameterInvalidMsg("the vector field %s could not be enriched", name)
+		}
+		fieldData, err := typeutil.GenEmptyFieldData(field)
+		if err != nil {
+			return nil, err
+		}
+		fieldData.FieldName = enrichment.collectionName + "." + name
+		enriched = append(enriched, fieldData)
+	}
+
+	// the rows looked up by the key
+	rows := make(map[any]enrichRow)
+	batchSize := Params.ProxyCfg.QueryEnrichmentBatchSize.GetAsInt()
+	for start := 0; start < typeutil.GetSizeOfIDs(distinct); start += batchSize {
+		end := lo.Min([]int{start + batchSize, typeutil.GetSizeOfIDs(distinct)})
+		batch := &schemapb.IDs{}
+		for i := start; i < end; i++ {
+			typeutil.AppendIDs(batch, distinct, i)
+		}
+		lookup := &milvuspb.QueryRequest{
+			DbName:                request.GetDbName(),
+			CollectionName:        enrichment.collectionName,
+			Expr:                  IDs2Expr(pkField.GetName(), batch),
+			OutputFields:          lo.Uniq(append([]string{pkField.GetName()}, enrichment.outputFields...)),
+			GuaranteeTimestamp:    request.GetGuaranteeTimestamp(),
+			ConsistencyLevel:      request.GetConsistencyLevel(),
+			UseDefaultConsistency: request.GetUseDefaultConsistency(),
+		}
+		// the enrich collection is read by the privilege of the user as well
+		if _, err := PrivilegeInterceptor(ctx, lookup); err != nil {
+			return nil, err
+		}
+		rsp, err := node.query(ctx, node.newQueryTask(ctx, lookup))
+		if err := merr.CheckRPCCall(rsp, err); err != nil {
+			return nil, err
+		}
+		pkData, ok := lo.Find(rsp.GetFieldsData(), func(fieldData *schemapb.FieldData) bool { return fieldData.GetFieldName() == pkField.GetName() })
+		if !ok {
+			// none of the batch found
+			continue
+		}
+		fieldsData := make([]*schemapb.FieldData, 0, len(enrichment.outputFields))
+		for _, name := range enrichment.outputFields {
+			fieldData, ok := lo.Find(rsp.GetFieldsData(), func(fieldData *schemapb.FieldData) bool { return fieldData.GetFieldName() == name })
+			if !ok {
+				return nil, merr.WrapErrServiceInternal("the enrich field " + name + " is not returned")
+			}
+			fieldsData = append(fieldsData, fieldData)
+		}
+		pks, _, err := enrichKeys(pkData, pkField.GetDataType())
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < typeutil.GetSizeOfIDs(pks); i++ {
+			rows[typeutil.GetPK(pks, int64(i))] = enrichRow{fieldsData: fieldsData, idx: int64(i)}
+		}
+	}
+
+	fillEnrichedFields(enriched, keys, rows)
+	return enriched, nil
+}
+
+// fillEnrichedFields appends the rows looked up by the keys to the enriched fields in the order of the keys.
+func fillEnrichedFields(enriched []*schemapb.FieldData, keys *schemapb.IDs, rows map[any]enrichRow) {
+	for i := 0; i < typeutil.GetSizeOfIDs(keys); i++ {
+		row, ok := rows[typeutil.GetPK(keys, int64(i))]
+		if !ok {
+			for _, fieldData := range enriched {
+				appendZeroValue(fieldData)
+			}
+			continue
+		}
+		typeutil.AppendFieldData(enriched, row.fieldsData, row.idx)
+	}
+}
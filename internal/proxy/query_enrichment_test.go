@@ -0,0 +1,192 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+func TestParseQueryEnrichment(t *testing.T) {
+	enrichment, err := parseQueryEnrichment([]*commonpb.KeyValuePair{{Key: LimitKey, Value: "10"}})
+	assert.NoError(t, err)
+	assert.Nil(t, enrichment)
+
+	enrichment, err = parseQueryEnrichment([]*commonpb.KeyValuePair{
+		{Key: EnrichCollectionKey, Value: "authors"},
+		{Key: EnrichKeyFieldKey, Value: "author_id"},
+		{Key: EnrichOutputFieldsKey, Value: "name, country,,name"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &queryEnrichment{collectionName: "authors", keyField: "author_id", outputFields: []string{"name", "country"}}, enrichment)
+
+	_, err = parseQueryEnrichment([]*commonpb.KeyValuePair{
+		{Key: EnrichCollectionKey, Value: "authors"},
+		{Key: EnrichOutputFieldsKey, Value: "name"},
+	})
+	assert.ErrorIs(t, err, merr.ErrParameterMissing)
+
+	_, err = parseQueryEnrichment([]*commonpb.KeyValuePair{
+		{Key: EnrichCollectionKey, Value: "authors"},
+		{Key: EnrichKeyFieldKey, Value: "author_id"},
+		{Key: EnrichOutputFieldsKey, Value: " "},
+	})
+	assert.ErrorIs(t, err, merr.ErrParameterMissing)
+}
+
+func TestEnrichKeys(t *testing.T) {
+	keyData := &schemapb.FieldData{
+		Type:      schemapb.DataType_Int32,
+		FieldName: "k",
+		Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{Data: []int32{3, 1, 3}}},
+		}},
+	}
+	keys, distinct, err := enrichKeys(keyData, schemapb.DataType_Int64)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 1, 3}, keys.GetIntId().GetData())
+	assert.Equal(t, []int64{3, 1}, distinct.GetIntId().GetData())
+
+	keyData = &schemapb.FieldData{
+		Type:      schemapb.DataType_VarChar,
+		FieldName: "k",
+		Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"a", "b", "a"}}},
+		}},
+	}
+	keys, distinct, err = enrichKeys(keyData, schemapb.DataType_VarChar)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "a"}, keys.GetStrId().GetData())
+	assert.Equal(t, []string{"a", "b"}, distinct.GetStrId().GetData())
+
+	_, _, err = enrichKeys(keyData, schemapb.DataType_Int64)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestFillEnrichedFields(t *testing.T) {
+	name, err := typeutil.GenEmptyFieldData(&schemapb.FieldSchema{Name: "name", DataType: schemapb.DataType_VarChar})
+	assert.NoError(t, err)
+	age, err := typeutil.GenEmptyFieldData(&schemapb.FieldSchema{Name: "age", DataType: schemapb.DataType_Int64})
+	assert.NoError(t, err)
+	enriched := []*schemapb.FieldData{name, age}
+
+	looked := []*schemapb.FieldData{
+		{
+			Type:      schemapb.DataType_VarChar,
+			FieldName: "name",
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"alice", "bob"}}},
+			}},
+		},
+		{
+			Type:      schemapb.DataType_Int64,
+			FieldName: "age",
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{30, 40}}},
+			}},
+		},
+	}
+	rows := map[any]enrichRow{
+		int64(1): {fieldsData: looked, idx: 0},
+		int64(2): {fieldsData: looked, idx: 1},
+	}
+	keys := &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{2, 3, 1, 2}}}}
+	fillEnrichedFields(enriched, keys, rows)
+	assert.Equal(t, []string{"bob", "", "alice", "bob"}, enriched[0].GetScalars().GetStringData().GetData())
+	assert.Equal(t, []int64{40, 0, 30, 40}, enriched[1].GetScalars().GetLongData().GetData())
+	assert.Equal(t, "name", enriched[0].GetFieldName())
+}
+
+func TestEnrichQueryResults(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetCollectionSchema(mock.Anything, mock.Anything, "authors").Return(newSchemaInfo(&schemapb.CollectionSchema{
+		Name: "authors",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "id", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "name", DataType: schemapb.DataType_VarChar},
+			{FieldID: 102, Name: "embedding", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: "dim", Value: "8"}}},
+		},
+	}), nil).Maybe()
+	mockCache.EXPECT().GetCollectionSchema(mock.Anything, mock.Anything, mock.Anything).Return(nil, merr.WrapErrCollectionNotFound("unknown")).Maybe()
+	globalMetaCache = mockCache
+	defer func() { globalMetaCache = nil }()
+
+	node := &Proxy{}
+	newResults := func() *milvuspb.QueryResults {
+		return &milvuspb.QueryResults{
+			Status:       merr.Success(),
+			OutputFields: []string{"author_id"},
+			FieldsData: []*schemapb.FieldData{{
+				Type:      schemapb.DataType_Int64,
+				FieldName: "author_id",
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{1, 2, 3}}},
+				}},
+			}},
+		}
+	}
+	newRequest := func(params ...*commonpb.KeyValuePair) *milvuspb.QueryRequest {
+		return &milvuspb.QueryRequest{CollectionName: "books", QueryParams: params}
+	}
+
+	rsp := node.enrichQueryResults(ctx, newRequest(), newResults())
+	assert.Equal(t, newResults(), rsp)
+
+	failed := &milvuspb.QueryResults{Status: merr.Status(merr.ErrServiceInternal)}
+	assert.Equal(t, failed, node.enrichQueryResults(ctx, newRequest(&commonpb.KeyValuePair{Key: EnrichCollectionKey, Value: "authors"}), failed))
+
+	cases := []struct {
+		name   string
+		params []*commonpb.KeyValuePair
+		err    error
+	}{
+		{"missing key field", []*commonpb.KeyValuePair{{Key: EnrichCollectionKey, Value: "authors"}, {Key: EnrichOutputFieldsKey, Value: "name"}}, merr.ErrParameterMissing},
+		{"key field not output", []*commonpb.KeyValuePair{{Key: EnrichCollectionKey, Value: "authors"}, {Key: EnrichKeyFieldKey, Value: "book_id"}, {Key: EnrichOutputFieldsKey, Value: "name"}}, merr.ErrParameterInvalid},
+		{"collection not found", []*commonpb.KeyValuePair{{Key: EnrichCollectionKey, Value: "unknown"}, {Key: EnrichKeyFieldKey, Value: "author_id"}, {Key: EnrichOutputFieldsKey, Value: "name"}}, merr.ErrCollectionNotFound},
+		{"field not found", []*commonpb.KeyValuePair{{Key: EnrichCollectionKey, Value: "authors"}, {Key: EnrichKeyFieldKey, Value: "author_id"}, {Key: EnrichOutputFieldsKey, Value: "age"}}, merr.ErrFieldNotFound},
+		{"vector field", []*commonpb.KeyValuePair{{Key: EnrichCollectionKey, Value: "authors"}, {Key: EnrichKeyFieldKey, Value: "author_id"}, {Key: EnrichOutputFieldsKey, Value: "embedding"}}, merr.ErrParameterInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rsp := node.enrichQueryResults(ctx, newRequest(c.params...), newResults())
+			assert.ErrorIs(t, merr.Error(rsp.GetStatus()), c.err)
+		})
+	}
+
+	t.Run("too many keys", func(t *testing.T) {
+		paramtable.Get().Save(Params.ProxyCfg.QueryEnrichmentMaxKeys.Key, "2")
+		defer paramtable.Get().Reset(Params.ProxyCfg.QueryEnrichmentMaxKeys.Key)
+		rsp := node.enrichQueryResults(ctx, newRequest(
+			&commonpb.KeyValuePair{Key: EnrichCollectionKey, Value: "authors"},
+			&commonpb.KeyValuePair{Key: EnrichKeyFieldKey, Value: "author_id"},
+			&commonpb.KeyValuePair{Key: EnrichOutputFieldsKey, Value: "name"},
+		), newResults())
+		assert.ErrorIs(t, merr.Error(rsp.GetStatus()), merr.ErrParameterInvalid)
+	})
+}
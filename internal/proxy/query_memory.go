@@ -0,0 +1,181 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// resultRowOverhead is the bytes of the id and the score of a result row besides the output fields.
+const resultRowOverhead = 12
+
+// memoryEstimator is the task estimating the approximate memory it uses to execute, the received payload, the
+// results buffered from the shards and the reduce buffers.
+type memoryEstimator interface {
+	estimateMemory(ctx context.Context) int64
+}
+
+// queryMemoryBudget limits the approximate memory of the in-flight searches and queries of the proxy, the tasks
+// exceeding the budget wait for the memory released by the others, or get rejected.
+type queryMemoryBudget struct {
+	mu       sync.Mutex
+	used     int64
+	released chan struct{} // closed and renewed on each release to wake up the waiting tasks
+}
+
+func newQueryMemoryBudget() *queryMemoryBudget {
+	return &queryMemoryBudget{released: make(chan struct{})}
+}
+
+// limit returns the bytes of the budget, zero if unlimited.
+func (b *queryMemoryBudget) limit() int64 {
+	return Params.ProxyCfg.QueryMemoryBudget.GetAsInt64() * 1024 * 1024
+}
+
+// acquire acquires the memory of the size, returns the function to release it. It waits up to the configured
+// timeout if the budget is used up by the other tasks, the size larger than the whole budget is rejected at once.
+func (b *queryMemoryBudget) acquire(ctx context.Context, size int64) (func(), error) {
+	limit := b.limit()
+	if limit <= 0 || size <= 0 {
+		return func() {}, nil
+	}
+	if size > limit {
+		return nil, merr.WrapErrServiceMemoryLimitExceeded(float32(size), float32(limit), "the request needs more memory than the query memory budget")
+	}
+
+	var timeout <-chan time.Time
+	if wait := Params.ProxyCfg.QueryMemoryWaitTimeout.GetAsDuration(time.Millisecond); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for {
+		b.mu.Lock()
+		if b.used+size <= limit {
+			b.used += size
+			b.setGauge()
+			b.mu.Unlock()
+			var once sync.Once
+			return func() { once.Do(func() { b.release(size) }) }, nil
+		}
+		released := b.released
+		b.mu.Unlock()
+
+		if timeout == nil {
+			return nil, b.exceeded(size, limit)
+		}
+		select {
+		case <-released:
+		case <-timeout:
+			return nil, b.exceeded(size, limit)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (b *queryMemoryBudget) release(size int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used -= size
+	b.setGauge()
+	close(b.released)
+	b.released = make(chan struct{})
+}
+
+func (b *queryMemoryBudget) exceeded(size, limit int64) error {
+	b.mu.Lock()
+	used := b.used
+	b.mu.Unlock()
+	return merr.WrapErrServiceMemoryLimitExceeded(float32(used+size), float32(limit), "the query memory budget is used up by the in-flight requests")
+}
+
+// setGauge must be called with the lock held.
+func (b *queryMemoryBudget) setGauge() {
+	metrics.ProxyQueryMemoryInUse.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10)).Set(float64(b.used))
+}
+
+// estimateResultRowSize returns the approximate bytes of a result row with the output fields.
+func estimateResultRowSize(schema *schemapb.CollectionSchema, outputFieldIDs []int64) int64 {
+	fields := make([]*schemapb.FieldSchema, 0, len(outputFieldIDs))
+	for _, field := range schema.GetFields() {
+		for _, id := range outputFieldIDs {
+			if field.GetFieldID() == id {
+				fields = append(fields, field)
+				break
+			}
+		}
+	}
+	size, err := typeutil.EstimateSizePerRecord(&schemapb.CollectionSchema{Fields: fields})
+	if err != nil {
+		return resultRowOverhead
+	}
+	return int64(size) + resultRowOverhead
+}
+
+// estimateShardNum returns the number of the shards of the collection from the cached shard leaders, one if unknown.
+func estimateShardNum(ctx context.Context, dbName, collectionName string, collectionID int64) int64 {
+	shards, err := globalMetaCache.GetShards(ctx, true, dbName, collectionName, collectionID)
+	if err != nil || len(shards) == 0 {
+		return 1
+	}
+	return int64(len(shards))
+}
+
+// estimateQueryMemory returns the approximate memory of the request payload, the results of the shards buffered
+// before reduced and the reduced result.
+func estimateQueryMemory(payload int64, rows int64, rowSize int64, shardNum int64) int64 {
+	return payload + rows*rowSize*(shardNum+1)
+}
+
+func (t *searchTask) estimateMemory(ctx context.Context) int64 {
+	rows := t.SearchRequest.GetNq() * (t.SearchRequest.GetTopk() + t.SearchRequest.GetOffset())
+	if t.SearchRequest.GetIsAdvanced() {
+		rows = 0
+		for _, sub := range t.SearchRequest.GetSubReqs() {
+			rows += sub.GetNq() * (sub.GetTopk() + sub.GetOffset())
+		}
+	}
+	rowSize := estimateResultRowSize(t.schema.CollectionSchema, t.SearchRequest.GetOutputFieldsId())
+	shardNum := estimateShardNum(ctx, t.request.GetDbName(), t.collectionName, t.SearchRequest.GetCollectionID())
+	return estimateQueryMemory(int64(proto.Size(t.request)), rows, rowSize, shardNum)
+}
+
+func (t *queryTask) estimateMemory(ctx context.Context) int64 {
+	// the count queries only return the counts of the shards
+	if t.RetrieveRequest.GetIsCount() {
+		return 0
+	}
+	limit := t.RetrieveRequest.GetLimit()
+	if limit == typeutil.Unlimited {
+		limit = Params.QuotaConfig.MaxQueryResultWindow.GetAsInt64()
+	}
+	rowSize := estimateResultRowSize(t.schema.CollectionSchema, t.RetrieveRequest.GetOutputFieldsId())
+	shardNum := estimateShardNum(ctx, t.request.GetDbName(), t.collectionName, t.RetrieveRequest.GetCollectionID())
+	return estimateQueryMemory(int64(proto.Size(t.request)), limit, rowSize, shardNum)
+}
@@ -0,0 +1,129 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+func TestQueryMemoryBudget(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	budget := newQueryMemoryBudget()
+
+	// unlimited by default
+	release, err := budget.acquire(ctx, 1<<40)
+	assert.NoError(t, err)
+	release()
+	assert.Equal(t, int64(0), budget.used)
+
+	paramtable.Get().Save(Params.ProxyCfg.QueryMemoryBudget.Key, "1")
+	defer paramtable.Get().Reset(Params.ProxyCfg.QueryMemoryBudget.Key)
+	paramtable.Get().Save(Params.ProxyCfg.QueryMemoryWaitTimeout.Key, "0")
+	defer paramtable.Get().Reset(Params.ProxyCfg.QueryMemoryWaitTimeout.Key)
+
+	_, err = budget.acquire(ctx, 2<<20)
+	assert.ErrorIs(t, err, merr.ErrServiceMemoryLimitExceeded)
+
+	release, err = budget.acquire(ctx, 768<<10)
+	assert.NoError(t, err)
+	_, err = budget.acquire(ctx, 512<<10)
+	assert.ErrorIs(t, err, merr.ErrServiceMemoryLimitExceeded)
+
+	// queued until the memory is released
+	paramtable.Get().Save(Params.ProxyCfg.QueryMemoryWaitTimeout.Key, "10000")
+	done := make(chan error, 1)
+	go func() {
+		release2, err := budget.acquire(ctx, 512<<10)
+		if err == nil {
+			release2()
+		}
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	release()
+	release() // released once
+	assert.NoError(t, <-done)
+	assert.Equal(t, int64(0), budget.used)
+
+	release, err = budget.acquire(ctx, 1<<20)
+	assert.NoError(t, err)
+	defer release()
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = budget.acquire(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestEstimateQueryMemory(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "128"}}},
+		},
+	}
+	assert.Equal(t, int64(resultRowOverhead), estimateResultRowSize(schema, nil))
+	assert.Equal(t, int64(resultRowOverhead+8), estimateResultRowSize(schema, []int64{100}))
+	assert.Equal(t, int64(resultRowOverhead+8+512), estimateResultRowSize(schema, []int64{100, 101}))
+
+	assert.Equal(t, int64(100+10*20*3), estimateQueryMemory(100, 10, 20, 2))
+}
+
+func TestQueryTaskEstimateMemory(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetShards(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(map[string][]nodeInfo{"ch1": {}, "ch2": {}}, nil).Maybe()
+	globalMetaCache = mockCache
+
+	schema := newSchemaInfo(&schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{{FieldID: 100, DataType: schemapb.DataType_Int64}},
+	})
+	request := &milvuspb.QueryRequest{}
+	payload := int64(0)
+
+	task := &queryTask{
+		RetrieveRequest: &internalpb.RetrieveRequest{IsCount: true},
+		request:         request,
+		schema:          schema,
+	}
+	assert.Equal(t, int64(0), task.estimateMemory(ctx))
+
+	task.RetrieveRequest = &internalpb.RetrieveRequest{Limit: 10, OutputFieldsId: []int64{100}}
+	assert.Equal(t, estimateQueryMemory(payload, 10, resultRowOverhead+8, 2), task.estimateMemory(ctx))
+
+	task.RetrieveRequest = &internalpb.RetrieveRequest{Limit: typeutil.Unlimited, OutputFieldsId: []int64{100}}
+	window := Params.QuotaConfig.MaxQueryResultWindow.GetAsInt64()
+	assert.Equal(t, estimateQueryMemory(payload, window, resultRowOverhead+8, 2), task.estimateMemory(ctx))
+}
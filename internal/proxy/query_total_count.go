@@ -0,0 +1,114 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// totalCountInfoKey is set in the extra info of the query result status if the total count is requested, the value is
+// the number of the rows matching the expr.
+const totalCountInfoKey = "total_count"
+
+// countOmittedQueryParams are the query params of the page only, not passed to the count query.
+var countOmittedQueryParams = typeutil.NewSet(LimitKey, OffsetKey, WithTotalCountKey, IteratorField, ReduceStopForBestKey,
+	EnrichCollectionKey, EnrichKeyFieldKey, EnrichOutputFieldsKey)
+
+// newCountRequest returns the count(*) query of the same expr, partitions and consistency as the paged query.
+func newCountRequest(request *milvuspb.QueryRequest) *milvuspb.QueryRequest {
+	countRequest := proto.Clone(request).(*milvuspb.QueryRequest)
+	countRequest.OutputFields = []string{"count(*)"}
+	countRequest.QueryParams = lo.Filter(countRequest.GetQueryParams(), func(kv *commonpb.KeyValuePair, _ int) bool {
+		return !countOmittedQueryParams.Contain(kv.GetKey())
+	})
+	return countRequest
+}
+
+// getCountResult returns the count in the fields data of the count(*) query results.
+func getCountResult(fieldsData []*schemapb.FieldData) (int64, error) {
+	for _, fieldData := range fieldsData {
+		if counts := fieldData.GetScalars().GetLongData().GetData(); len(counts) > 0 {
+			return counts[0], nil
+		}
+	}
+	return 0, merr.WrapErrServiceInternal("no count in the count query results")
+}
+
+// queryTotalCount is the count query running along with the paged query.
+type queryTotalCount struct {
+	done  chan struct{}
+	count int64
+	err   error
+}
+
+// startQueryTotalCount starts the count query in parallel with the paged query if the total count is requested,
+// returns nil if not requested. The count query is executed at its own timestamp, so the total count may include the
+// mutations not visible to the page under the weak consistency levels.
+func (node *Proxy) startQueryTotalCount(ctx context.Context, request *milvuspb.QueryRequest) (*queryTotalCount, error) {
+	withTotalCount, err := parseBoolSearchParam(WithTotalCountKey, request.GetQueryParams())
+	if err != nil || !withTotalCount {
+		return nil, err
+	}
+	if matchCountRule(request.GetOutputFields()) {
+		return nil, merr.WrapErrParameterInvalidMsg("%s is not supported for the count(*) query", WithTotalCountKey)
+	}
+	countRequest := newCountRequest(request)
+	totalCount := &queryTotalCount{done: make(chan struct{})}
+	go func() {
+		defer close(totalCount.done)
+		rsp, err := node.query(ctx, node.newQueryTask(ctx, countRequest))
+		if err := merr.CheckRPCCall(rsp, err); err != nil {
+			totalCount.err = err
+			return
+		}
+		totalCount.count, totalCount.err = getCountResult(rsp.GetFieldsData())
+	}()
+	return totalCount, nil
+}
+
+// attach waits the count query and sets the total count in the extra info of the query results, the results are
+// replaced by the error if the count query fails.
+func (c *queryTotalCount) attach(rsp *milvuspb.QueryResults) *milvuspb.QueryResults {
+	if c == nil {
+		return rsp
+	}
+	<-c.done
+	if !merr.Ok(rsp.GetStatus()) {
+		return rsp
+	}
+	if c.err != nil {
+		return &milvuspb.QueryResults{Status: merr.Status(c.err)}
+	}
+	if rsp.Status == nil {
+		rsp.Status = merr.Success()
+	}
+	if rsp.Status.ExtraInfo == nil {
+		rsp.Status.ExtraInfo = make(map[string]string)
+	}
+	rsp.Status.ExtraInfo[totalCountInfoKey] = strconv.FormatInt(c.count, 10)
+	return rsp
+}
@@ -0,0 +1,98 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestNewCountRequest(t *testing.T) {
+	request := &milvuspb.QueryRequest{
+		CollectionName: "coll",
+		Expr:           "a > 1",
+		OutputFields:   []string{"a"},
+		PartitionNames: []string{"p1"},
+		QueryParams: []*commonpb.KeyValuePair{
+			{Key: LimitKey, Value: "10"},
+			{Key: OffsetKey, Value: "20"},
+			{Key: WithTotalCountKey, Value: "true"},
+			{Key: IgnoreGrowingKey, Value: "true"},
+		},
+	}
+	countRequest := newCountRequest(request)
+	assert.Equal(t, []string{"count(*)"}, countRequest.GetOutputFields())
+	assert.Equal(t, "a > 1", countRequest.GetExpr())
+	assert.Equal(t, []string{"p1"}, countRequest.GetPartitionNames())
+	assert.Equal(t, []*commonpb.KeyValuePair{{Key: IgnoreGrowingKey, Value: "true"}}, countRequest.GetQueryParams())
+	// the paged request is untouched
+	assert.Len(t, request.GetQueryParams(), 4)
+	assert.Equal(t, []string{"a"}, request.GetOutputFields())
+}
+
+func TestGetCountResult(t *testing.T) {
+	count, err := getCountResult([]*schemapb.FieldData{{
+		FieldName: "count(*)",
+		Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{42}}},
+		}},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), count)
+
+	_, err = getCountResult(nil)
+	assert.Error(t, err)
+}
+
+func TestQueryTotalCount(t *testing.T) {
+	node := &Proxy{}
+	ctx := context.Background()
+	totalCount, err := node.startQueryTotalCount(ctx, &milvuspb.QueryRequest{})
+	assert.NoError(t, err)
+	assert.Nil(t, totalCount)
+	rsp := &milvuspb.QueryResults{Status: merr.Success()}
+	assert.Equal(t, rsp, totalCount.attach(rsp))
+
+	_, err = node.startQueryTotalCount(ctx, &milvuspb.QueryRequest{
+		QueryParams: []*commonpb.KeyValuePair{{Key: WithTotalCountKey, Value: "yes"}},
+	})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	_, err = node.startQueryTotalCount(ctx, &milvuspb.QueryRequest{
+		OutputFields: []string{"count(*)"},
+		QueryParams:  []*commonpb.KeyValuePair{{Key: WithTotalCountKey, Value: "true"}},
+	})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	done := make(chan struct{})
+	close(done)
+	rsp = (&queryTotalCount{done: done, count: 100}).attach(&milvuspb.QueryResults{Status: merr.Success()})
+	assert.Equal(t, "100", rsp.GetStatus().GetExtraInfo()[totalCountInfoKey])
+
+	// the failure of the paged query is returned as is
+	failed := &milvuspb.QueryResults{Status: merr.Status(merr.ErrServiceNotReady)}
+	assert.Equal(t, failed, (&queryTotalCount{done: done, count: 100}).attach(failed))
+
+	rsp = (&queryTotalCount{done: done, err: merr.ErrServiceInternal}).attach(&milvuspb.QueryResults{Status: merr.Success()})
+	assert.ErrorIs(t, merr.Error(rsp.GetStatus()), merr.ErrServiceInternal)
+}
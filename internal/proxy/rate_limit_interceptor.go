@@ -46,6 +46,9 @@ func RateLimitInterceptor(limiter types.Limiter) grpc.UnaryServerInterceptor {
 		}
 
 		err = limiter.Check(dbID, collectionIDToPartIDs, rt, n)
+		if err == nil && rt == internalpb.RateType_DQLSearch {
+			err = globalSearchWorkLimiter.Check(ctx, collectionIDToPartIDs, n)
+		}
 		nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
 		metrics.ProxyRateLimitReqCount.WithLabelValues(nodeID, rt.String(), metrics.TotalLabel).Inc()
 		if err != nil {
@@ -222,7 +225,7 @@ func getFailedResponse(req any, err error) any {
 		return &milvuspb.ImportResponse{
 			Status: merr.Status(err),
 		}
-	case *milvuspb.SearchRequest:
+	case *milvuspb.SearchRequest, *milvuspb.HybridSearchRequest:
 		return &milvuspb.SearchResults{
 			Status: merr.Status(err),
 		}
@@ -234,7 +237,10 @@ func getFailedResponse(req any, err error) any {
 		*milvuspb.LoadCollectionRequest, *milvuspb.ReleaseCollectionRequest,
 		*milvuspb.CreatePartitionRequest, *milvuspb.DropPartitionRequest,
 		*milvuspb.LoadPartitionsRequest, *milvuspb.ReleasePartitionsRequest,
-		*milvuspb.CreateIndexRequest, *milvuspb.DropIndexRequest:
+		*milvuspb.CreateIndexRequest, *milvuspb.DropIndexRequest,
+		*milvuspb.AlterCollectionRequest, *milvuspb.AlterIndexRequest,
+		*milvuspb.CreateAliasRequest, *milvuspb.DropAliasRequest, *milvuspb.AlterAliasRequest,
+		*milvuspb.CreateDatabaseRequest, *milvuspb.DropDatabaseRequest:
 		return merr.Status(err)
 	case *milvuspb.FlushRequest:
 		return &milvuspb.FlushResponse{
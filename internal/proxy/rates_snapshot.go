@@ -0,0 +1,130 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/internal/proto/proxypb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// ratesSnapshotKey is the etcd key(under meta root path) of the latest rates pushed by quota center,
+// which are shared by all proxies.
+const ratesSnapshotKey = "proxy/rates-snapshot"
+
+type ratesSnapshot struct {
+	UpdateTime int64  `json:"update_time"`
+	Limiter    []byte `json:"limiter"`
+}
+
+// ratesSnapshotStore persists the latest rates, so that the restarted proxy could apply them
+// before the next SetRates from quota center.
+type ratesSnapshotStore struct {
+	mu   sync.Mutex
+	kv   kv.BaseKV
+	last *proxypb.LimiterNode
+}
+
+var globalRatesSnapshot = &ratesSnapshotStore{}
+
+func (s *ratesSnapshotStore) init(baseKV kv.BaseKV) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kv = baseKV
+}
+
+// Save persists the rates if they are changed since the last save.
+func (s *ratesSnapshotStore) Save(rootLimiter *proxypb.LimiterNode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.kv == nil || (s.last != nil && proto.Equal(s.last, rootLimiter)) {
+		return nil
+	}
+	limiter, err := proto.Marshal(rootLimiter)
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(&ratesSnapshot{UpdateTime: time.Now().Unix(), Limiter: limiter})
+	if err != nil {
+		return err
+	}
+	if err := s.kv.Save(ratesSnapshotKey, string(value)); err != nil {
+		return err
+	}
+	s.last = proto.Clone(rootLimiter).(*proxypb.LimiterNode)
+	return nil
+}
+
+// Load returns the persisted rates and the time they were pushed, nil if there is no snapshot.
+func (s *ratesSnapshotStore) Load() (*proxypb.LimiterNode, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.kv == nil {
+		return nil, time.Time{}, nil
+	}
+	value, err := s.kv.Load(ratesSnapshotKey)
+	if errors.Is(err, merr.ErrIoKeyNotFound) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	snapshot := &ratesSnapshot{}
+	if err := json.Unmarshal([]byte(value), snapshot); err != nil {
+		return nil, time.Time{}, err
+	}
+	rootLimiter := &proxypb.LimiterNode{}
+	if err := proto.Unmarshal(snapshot.Limiter, rootLimiter); err != nil {
+		return nil, time.Time{}, err
+	}
+	return rootLimiter, time.Unix(snapshot.UpdateTime, 0), nil
+}
+
+// recoverRates applies the persisted rates if they are not older than proxy.ratesRecoveryMaxAge,
+// otherwise the proxy keeps the configured limits until the next SetRates from quota center.
+func (node *Proxy) recoverRates() {
+	maxAge := Params.ProxyCfg.RatesRecoveryMaxAge.GetAsDuration(time.Second)
+	if maxAge <= 0 {
+		return
+	}
+	rootLimiter, updateTime, err := globalRatesSnapshot.Load()
+	if err != nil {
+		log.Warn("failed to load persisted rates", zap.Error(err))
+		return
+	}
+	if rootLimiter == nil {
+		return
+	}
+	if age := time.Since(updateTime); age > maxAge {
+		log.Info("skip recovering the expired rates", zap.Duration("age", age))
+		return
+	}
+	if err := node.simpleLimiter.SetRates(rootLimiter); err != nil {
+		log.Warn("failed to recover persisted rates", zap.Error(err))
+		return
+	}
+	log.Info("recover persisted rates done", zap.Time("updateTime", updateTime))
+}
@@ -0,0 +1,174 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/kv/mocks"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/internal/proto/proxypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func newTestRootLimiter() *proxypb.LimiterNode {
+	return &proxypb.LimiterNode{
+		Limiter: &proxypb.Limiter{
+			Rates: []*internalpb.Rate{{Rt: internalpb.RateType_DMLInsert, R: 100}},
+		},
+		Children: map[int64]*proxypb.LimiterNode{
+			1: {
+				Limiter: &proxypb.Limiter{
+					Rates:  []*internalpb.Rate{{Rt: internalpb.RateType_DQLSearch, R: 10}},
+					States: []milvuspb.QuotaState{milvuspb.QuotaState_DenyToRead},
+					Codes:  []commonpb.ErrorCode{commonpb.ErrorCode_ForceDeny},
+				},
+				Children: map[int64]*proxypb.LimiterNode{},
+			},
+		},
+	}
+}
+
+func TestRatesSnapshot(t *testing.T) {
+	t.Run("no kv", func(t *testing.T) {
+		s := &ratesSnapshotStore{}
+		assert.NoError(t, s.Save(newTestRootLimiter()))
+		rootLimiter, _, err := s.Load()
+		assert.NoError(t, err)
+		assert.Nil(t, rootLimiter)
+	})
+
+	t.Run("save and load", func(t *testing.T) {
+		var saved string
+		watchKV := mocks.NewWatchKV(t)
+		watchKV.EXPECT().Save(ratesSnapshotKey, mock.Anything).RunAndReturn(func(_ string, value string) error {
+			saved = value
+			return nil
+		}).Once()
+		watchKV.EXPECT().Load(ratesSnapshotKey).RunAndReturn(func(string) (string, error) {
+			return saved, nil
+		})
+
+		s := &ratesSnapshotStore{}
+		s.init(watchKV)
+		assert.NoError(t, s.Save(newTestRootLimiter()))
+		// unchanged rates are not persisted again
+		assert.NoError(t, s.Save(newTestRootLimiter()))
+
+		rootLimiter, updateTime, err := s.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, float64(100), rootLimiter.GetLimiter().GetRates()[0].GetR())
+		assert.Contains(t, rootLimiter.GetChildren(), int64(1))
+		assert.WithinDuration(t, time.Now(), updateTime, time.Minute)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		watchKV := mocks.NewWatchKV(t)
+		watchKV.EXPECT().Load(ratesSnapshotKey).Return("", merr.WrapErrIoKeyNotFound(ratesSnapshotKey))
+		s := &ratesSnapshotStore{}
+		s.init(watchKV)
+		rootLimiter, _, err := s.Load()
+		assert.NoError(t, err)
+		assert.Nil(t, rootLimiter)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		watchKV := mocks.NewWatchKV(t)
+		watchKV.EXPECT().Load(ratesSnapshotKey).Return("invalid", nil)
+		s := &ratesSnapshotStore{}
+		s.init(watchKV)
+		_, _, err := s.Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestProxy_RecoverRates(t *testing.T) {
+	paramtable.Init()
+	defer func() { globalRatesSnapshot = &ratesSnapshotStore{} }()
+
+	snapshot := func(updateTime time.Time) string {
+		limiter, _ := proto.Marshal(newTestRootLimiter())
+		value, _ := json.Marshal(&ratesSnapshot{UpdateTime: updateTime.Unix(), Limiter: limiter})
+		return string(value)
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		watchKV := mocks.NewWatchKV(t)
+		watchKV.EXPECT().Load(ratesSnapshotKey).Return(snapshot(time.Now().Add(-time.Hour)), nil)
+		globalRatesSnapshot = &ratesSnapshotStore{}
+		globalRatesSnapshot.init(watchKV)
+
+		node := &Proxy{simpleLimiter: NewSimpleLimiter()}
+		node.recoverRates()
+		assert.Empty(t, node.simpleLimiter.GetRates().Children)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		paramtable.Get().Save(Params.ProxyCfg.RatesRecoveryMaxAge.Key, "0")
+		defer paramtable.Get().Reset(Params.ProxyCfg.RatesRecoveryMaxAge.Key)
+		globalRatesSnapshot = &ratesSnapshotStore{}
+		globalRatesSnapshot.init(mocks.NewWatchKV(t))
+
+		node := &Proxy{simpleLimiter: NewSimpleLimiter()}
+		node.recoverRates()
+		assert.Empty(t, node.simpleLimiter.GetRates().Children)
+	})
+
+	t.Run("recovered", func(t *testing.T) {
+		watchKV := mocks.NewWatchKV(t)
+		watchKV.EXPECT().Load(ratesSnapshotKey).Return(snapshot(time.Now()), nil)
+		globalRatesSnapshot = &ratesSnapshotStore{}
+		globalRatesSnapshot.init(watchKV)
+
+		node := &Proxy{simpleLimiter: NewSimpleLimiter()}
+		node.recoverRates()
+		rates := node.simpleLimiter.GetRates()
+		assert.Equal(t, float64(100), rates.Rates[internalpb.RateType_DMLInsert.String()])
+		assert.Len(t, rates.Children, 1)
+		assert.Equal(t, int64(1), rates.Children[0].ID)
+		assert.Equal(t, float64(10), rates.Children[0].Rates[internalpb.RateType_DQLSearch.String()])
+		assert.Contains(t, rates.Children[0].States, milvuspb.QuotaState_DenyToRead.String())
+	})
+}
+
+func TestProxy_GetRates(t *testing.T) {
+	paramtable.Init()
+	node := &Proxy{simpleLimiter: NewSimpleLimiter()}
+	assert.NoError(t, node.simpleLimiter.SetRates(newTestRootLimiter()))
+
+	req, _ := http.NewRequest(http.MethodGet, mgrGetRates, nil)
+	recorder := httptest.NewRecorder()
+	node.GetRates(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	rates := &LimiterRates{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), rates))
+	assert.Equal(t, internalpb.RateScope_Cluster.String(), rates.Scope)
+	assert.Equal(t, float64(100), rates.Rates[internalpb.RateType_DMLInsert.String()])
+	assert.Len(t, rates.Children, 1)
+}
@@ -0,0 +1,248 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+const (
+	// readPreferenceKey is the request metadata key of the read preference of the search and query.
+	readPreferenceKey = "read_preference"
+	// readPreferenceFallbackKey set to none fails the request if no shard delegator matches the read preference,
+	// otherwise any shard delegator is used then.
+	readPreferenceFallbackKey = "read_preference_fallback"
+
+	readPreferenceAny           = "any"
+	readPreferenceLocalZone     = "local_zone"
+	readPreferenceLowestLatency = "lowest_latency"
+	readPreferenceGroupPrefix   = "group:"
+	readPreferenceZonePrefix    = "zone:"
+
+	readPreferenceFallbackAny  = "any"
+	readPreferenceFallbackNone = "none"
+
+	// readPreferenceLatencyWeight is the weight of the latest latency in the moving average.
+	readPreferenceLatencyWeight = 0.2
+)
+
+// readPreference selects the shard delegators of the replicas by the zone or the resource group of their nodes,
+// the zones are labeled to the resource groups by proxy.readPreference.resourceGroupZones.
+type readPreference struct {
+	mode   string
+	target string
+	strict bool
+}
+
+func parseReadPreference(value string) (*readPreference, error) {
+	switch {
+	case value == "" || value == readPreferenceAny:
+		return nil, nil
+	case value == readPreferenceLocalZone || value == readPreferenceLowestLatency:
+		return &readPreference{mode: value}, nil
+	case strings.HasPrefix(value, readPreferenceGroupPrefix) && len(value) > len(readPreferenceGroupPrefix):
+		return &readPreference{mode: readPreferenceGroupPrefix, target: strings.TrimPrefix(value, readPreferenceGroupPrefix)}, nil
+	case strings.HasPrefix(value, readPreferenceZonePrefix) && len(value) > len(readPreferenceZonePrefix):
+		return &readPreference{mode: readPreferenceZonePrefix, target: strings.TrimPrefix(value, readPreferenceZonePrefix)}, nil
+	}
+	return nil, merr.WrapErrParameterInvalid("any, local_zone, lowest_latency, group:<name> or zone:<name>", value, "invalid read preference")
+}
+
+// getReadPreference returns the read preference of the request metadata, or the default one of the proxy.
+func getReadPreference(ctx context.Context) (*readPreference, error) {
+	value := Params.ProxyCfg.ReadPreferenceDefault.GetValue()
+	fallback := readPreferenceFallbackAny
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(readPreferenceKey); len(values) > 0 {
+			value = values[0]
+		}
+		if values := md.Get(readPreferenceFallbackKey); len(values) > 0 {
+			fallback = values[0]
+		}
+	}
+	pref, err := parseReadPreference(value)
+	if err != nil || pref == nil {
+		return nil, err
+	}
+	switch fallback {
+	case readPreferenceFallbackAny:
+	case readPreferenceFallbackNone:
+		pref.strict = true
+	default:
+		return nil, merr.WrapErrParameterInvalid("any or none", fallback, "invalid read preference fallback")
+	}
+	return pref, nil
+}
+
+func (p *readPreference) String() string {
+	return p.mode + p.target
+}
+
+// prefer returns the nodes matching the preference, the one of the lowest latency for lowest_latency.
+func (p *readPreference) prefer(topology *replicaTopology, nodes []int64) []int64 {
+	switch p.mode {
+	case readPreferenceLocalZone:
+		zone := Params.ProxyCfg.ReadPreferenceLocalZone.GetValue()
+		if zone == "" {
+			return nil
+		}
+		return lo.Filter(nodes, func(node int64, _ int) bool { return topology.zoneOf(node) == zone })
+	case readPreferenceZonePrefix:
+		return lo.Filter(nodes, func(node int64, _ int) bool { return topology.zoneOf(node) == p.target })
+	case readPreferenceGroupPrefix:
+		return lo.Filter(nodes, func(node int64, _ int) bool { return topology.groupOf(node) == p.target })
+	case readPreferenceLowestLatency:
+		if len(nodes) == 0 {
+			return nil
+		}
+		// the nodes never measured go first to be measured
+		return []int64{lo.MinBy(nodes, func(a, b int64) bool { return topology.latencyOf(a) < topology.latencyOf(b) })}
+	}
+	return nodes
+}
+
+// replicaTopology tracks the resource groups of the query nodes and the observed latencies of the shard delegators.
+type replicaTopology struct {
+	mu         sync.RWMutex
+	qc         types.QueryCoordClient
+	nodeGroups map[int64]string
+	// latencies are the moving average of the search and query latencies in milliseconds.
+	latencies map[int64]float64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var globalReplicaTopology = &replicaTopology{
+	nodeGroups: make(map[int64]string),
+	latencies:  make(map[int64]float64),
+}
+
+func (t *replicaTopology) init(qc types.QueryCoordClient) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.qc = qc
+}
+
+func (t *replicaTopology) start(ctx context.Context) {
+	ctx, t.cancel = context.WithCancel(ctx)
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(Params.ProxyCfg.ReadPreferenceRefreshInterval.GetAsDuration(time.Second))
+		defer ticker.Stop()
+		for {
+			if err := t.refresh(ctx); err != nil {
+				log.RatedWarn(60, "failed to refresh the resource groups of the query nodes", zap.Error(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (t *replicaTopology) close() {
+	if t.cancel != nil {
+		t.cancel()
+		t.wg.Wait()
+	}
+}
+
+// refresh reloads the query nodes of the resource groups.
+func (t *replicaTopology) refresh(ctx context.Context) error {
+	t.mu.RLock()
+	qc := t.qc
+	t.mu.RUnlock()
+	if qc == nil {
+		return nil
+	}
+	groups, err := qc.ListResourceGroups(ctx, &milvuspb.ListResourceGroupsRequest{})
+	if err := merr.CheckRPCCall(groups, err); err != nil {
+		return err
+	}
+	nodeGroups := make(map[int64]string)
+	for _, group := range groups.GetResourceGroups() {
+		resp, err := qc.DescribeResourceGroup(ctx, &querypb.DescribeResourceGroupRequest{ResourceGroup: group})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return err
+		}
+		for _, node := range resp.GetResourceGroup().GetNodes() {
+			nodeGroups[node.GetNodeId()] = group
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nodeGroups = nodeGroups
+	// the latencies of the nodes gone are dropped
+	for node := range t.latencies {
+		if _, ok := nodeGroups[node]; !ok {
+			delete(t.latencies, node)
+		}
+	}
+	return nil
+}
+
+func (t *replicaTopology) groupOf(node int64) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.nodeGroups[node]
+}
+
+func (t *replicaTopology) zoneOf(node int64) string {
+	group := t.groupOf(node)
+	if group == "" {
+		return ""
+	}
+	return Params.ProxyCfg.ReadPreferenceResourceGroupZones.GetAsJSONMap()[group]
+}
+
+func (t *replicaTopology) latencyOf(node int64) float64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	latency, ok := t.latencies[node]
+	if !ok {
+		return -math.MaxFloat64
+	}
+	return latency
+}
+
+func (t *replicaTopology) recordLatency(node int64, latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ms := float64(latency.Microseconds()) / 1000
+	if avg, ok := t.latencies[node]; ok {
+		ms = avg*(1-readPreferenceLatencyWeight) + ms*readPreferenceLatencyWeight
+	}
+	t.latencies[node] = ms
+}
@@ -0,0 +1,141 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestGetReadPreference(t *testing.T) {
+	paramtable.Init()
+
+	pref, err := getReadPreference(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, pref)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(readPreferenceKey, "zone:us-east-1a", readPreferenceFallbackKey, "none"))
+	pref, err = getReadPreference(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &readPreference{mode: readPreferenceZonePrefix, target: "us-east-1a", strict: true}, pref)
+	assert.Equal(t, "zone:us-east-1a", pref.String())
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(readPreferenceKey, "group:rg1"))
+	pref, err = getReadPreference(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &readPreference{mode: readPreferenceGroupPrefix, target: "rg1"}, pref)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(readPreferenceKey, "any"))
+	pref, err = getReadPreference(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, pref)
+
+	for _, md := range []metadata.MD{
+		metadata.Pairs(readPreferenceKey, "nearest"),
+		metadata.Pairs(readPreferenceKey, "zone:"),
+		metadata.Pairs(readPreferenceKey, "local_zone", readPreferenceFallbackKey, "never"),
+	} {
+		_, err = getReadPreference(metadata.NewIncomingContext(context.Background(), md))
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	}
+
+	paramtable.Get().Save(Params.ProxyCfg.ReadPreferenceDefault.Key, readPreferenceLowestLatency)
+	defer paramtable.Get().Reset(Params.ProxyCfg.ReadPreferenceDefault.Key)
+	pref, err = getReadPreference(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &readPreference{mode: readPreferenceLowestLatency}, pref)
+}
+
+func TestReadPreferencePrefer(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(Params.ProxyCfg.ReadPreferenceResourceGroupZones.Key, `{"rg1": "zone-a", "rg2": "zone-b"}`)
+	defer paramtable.Get().Reset(Params.ProxyCfg.ReadPreferenceResourceGroupZones.Key)
+
+	topology := &replicaTopology{
+		nodeGroups: map[int64]string{1: "rg1", 2: "rg2", 3: "rg2", 4: "rg3"},
+		latencies:  make(map[int64]float64),
+	}
+	nodes := []int64{1, 2, 3, 4}
+
+	assert.Equal(t, []int64{2, 3}, (&readPreference{mode: readPreferenceZonePrefix, target: "zone-b"}).prefer(topology, nodes))
+	assert.Equal(t, []int64{4}, (&readPreference{mode: readPreferenceGroupPrefix, target: "rg3"}).prefer(topology, nodes))
+	assert.Empty(t, (&readPreference{mode: readPreferenceZonePrefix, target: "zone-c"}).prefer(topology, nodes))
+
+	local := &readPreference{mode: readPreferenceLocalZone}
+	assert.Empty(t, local.prefer(topology, nodes))
+	paramtable.Get().Save(Params.ProxyCfg.ReadPreferenceLocalZone.Key, "zone-a")
+	defer paramtable.Get().Reset(Params.ProxyCfg.ReadPreferenceLocalZone.Key)
+	assert.Equal(t, []int64{1}, local.prefer(topology, nodes))
+
+	lowest := &readPreference{mode: readPreferenceLowestLatency}
+	topology.recordLatency(1, 30*time.Millisecond)
+	topology.recordLatency(2, 10*time.Millisecond)
+	topology.recordLatency(3, 20*time.Millisecond)
+	assert.Equal(t, []int64{2}, lowest.prefer(topology, []int64{1, 2, 3}))
+	// the node never measured goes first
+	assert.Equal(t, []int64{4}, lowest.prefer(topology, nodes))
+	// the moving average moves towards the latest latency
+	topology.recordLatency(2, 70*time.Millisecond)
+	assert.InDelta(t, 22, topology.latencyOf(2), 0.001)
+	assert.Equal(t, []int64{3}, lowest.prefer(topology, []int64{1, 2, 3}))
+	assert.Empty(t, lowest.prefer(topology, nil))
+}
+
+func TestReplicaTopologyRefresh(t *testing.T) {
+	paramtable.Init()
+	qc := mocks.NewMockQueryCoordClient(t)
+	qc.EXPECT().ListResourceGroups(mock.Anything, mock.Anything).Return(&milvuspb.ListResourceGroupsResponse{
+		Status:         merr.Success(),
+		ResourceGroups: []string{"rg1", "rg2"},
+	}, nil)
+	qc.EXPECT().DescribeResourceGroup(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, req *querypb.DescribeResourceGroupRequest, opts ...grpc.CallOption) (*querypb.DescribeResourceGroupResponse, error) {
+			nodes := map[string][]*commonpb.NodeInfo{
+				"rg1": {{NodeId: 1}},
+				"rg2": {{NodeId: 2}, {NodeId: 3}},
+			}[req.GetResourceGroup()]
+			return &querypb.DescribeResourceGroupResponse{
+				Status:        merr.Success(),
+				ResourceGroup: &querypb.ResourceGroupInfo{Name: req.GetResourceGroup(), Nodes: nodes},
+			}, nil
+		})
+
+	topology := &replicaTopology{nodeGroups: make(map[int64]string), latencies: make(map[int64]float64)}
+	assert.NoError(t, topology.refresh(context.Background()))
+	topology.init(qc)
+	topology.recordLatency(5, time.Millisecond)
+	assert.NoError(t, topology.refresh(context.Background()))
+	assert.Equal(t, "rg1", topology.groupOf(1))
+	assert.Equal(t, "rg2", topology.groupOf(3))
+	assert.Equal(t, "", topology.groupOf(4))
+	// the latency of the node gone is dropped
+	assert.Equal(t, -math.MaxFloat64, topology.latencyOf(5))
+}
@@ -0,0 +1,106 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// forceReleaseKey is the request metadata key to release the collection which served reads recently.
+const forceReleaseKey = "force_release"
+
+// collectionReads records the last time the collections served searches or queries through this proxy, reported
+// in the quota metrics to the rootcoord.
+type collectionReads struct {
+	lastReads *typeutil.ConcurrentMap[int64, int64]
+}
+
+var globalCollectionReads = &collectionReads{lastReads: typeutil.NewConcurrentMap[int64, int64]()}
+
+// record records the read of the collection if the release check is enabled.
+func (r *collectionReads) record(collectionID int64) {
+	if Params.ProxyCfg.ReleaseRecentReadWindow.GetAsInt64() <= 0 {
+		return
+	}
+	r.lastReads.Insert(collectionID, time.Now().UnixMilli())
+}
+
+// snapshot returns the last reads within the window, and removes the older ones.
+func (r *collectionReads) snapshot() map[int64]int64 {
+	window := Params.ProxyCfg.ReleaseRecentReadWindow.GetAsDuration(time.Minute)
+	ret := make(map[int64]int64)
+	r.lastReads.Range(func(collectionID int64, lastRead int64) bool {
+		if time.Since(time.UnixMilli(lastRead)) > window {
+			r.lastReads.Remove(collectionID)
+			return true
+		}
+		ret[collectionID] = lastRead
+		return true
+	})
+	return ret
+}
+
+// getClusterLastRead returns the last time the collection served reads through any proxy, merged by the rootcoord.
+func getClusterLastRead(ctx context.Context, rc types.RootCoordClient, collectionID int64) (int64, error) {
+	req, err := metricsinfo.ConstructRequestByMetricType(metricsinfo.CollectionReadsMetrics)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := rc.GetMetrics(ctx, req)
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return 0, err
+	}
+	reads := metricsinfo.CollectionReads{}
+	if err := json.Unmarshal([]byte(resp.GetResponse()), &reads); err != nil {
+		return 0, err
+	}
+	return reads.LastReads[collectionID], nil
+}
+
+// checkRecentReads rejects releasing the collection which served reads within the window unless forced, to prevent
+// the accidental outage of the collections in use, including the ones read by the aliases.
+func checkRecentReads(ctx context.Context, rc types.RootCoordClient, collectionName string, collectionID int64) error {
+	window := Params.ProxyCfg.ReleaseRecentReadWindow.GetAsDuration(time.Minute)
+	if window <= 0 || isConfirmedByMetadata(ctx, forceReleaseKey) {
+		return nil
+	}
+	lastRead := globalCollectionReads.snapshot()[collectionID]
+	if clusterLastRead, err := getClusterLastRead(ctx, rc, collectionID); err != nil {
+		log.Ctx(ctx).Warn("failed to get the reads of the collection through other proxies, check the reads of this proxy only",
+			zap.String("collection", collectionName), zap.Error(err))
+	} else if clusterLastRead > lastRead {
+		lastRead = clusterLastRead
+	}
+	if lastRead == 0 {
+		return nil
+	}
+	if elapsed := time.Since(time.UnixMilli(lastRead)); elapsed < window {
+		return merr.WrapErrParameterInvalidMsg("collection %s served reads %s ago, within the last %s, set %s to confirm the release",
+			collectionName, elapsed.Truncate(time.Second), window, forceReleaseKey)
+	}
+	return nil
+}
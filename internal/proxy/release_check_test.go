@@ -0,0 +1,92 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+func TestCollectionReads(t *testing.T) {
+	paramtable.Init()
+	reads := &collectionReads{lastReads: typeutil.NewConcurrentMap[int64, int64]()}
+
+	// disabled
+	reads.record(1)
+	assert.Empty(t, reads.snapshot())
+
+	key := Params.ProxyCfg.ReleaseRecentReadWindow.Key
+	paramtable.Get().Save(key, "10")
+	defer paramtable.Get().Reset(key)
+	reads.record(1)
+	reads.lastReads.Insert(2, time.Now().Add(-time.Hour).UnixMilli())
+	snapshot := reads.snapshot()
+	assert.Len(t, snapshot, 1)
+	assert.Contains(t, snapshot, int64(1))
+	_, ok := reads.lastReads.Get(2)
+	assert.False(t, ok)
+}
+
+func TestCheckRecentReads(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	rc := mocks.NewMockRootCoordClient(t)
+	lastReads := func(reads map[int64]int64) *milvuspb.GetMetricsResponse {
+		bs, err := json.Marshal(metricsinfo.CollectionReads{LastReads: reads})
+		assert.NoError(t, err)
+		return &milvuspb.GetMetricsResponse{Status: merr.Success(), Response: string(bs)}
+	}
+
+	// disabled
+	assert.NoError(t, checkRecentReads(ctx, rc, "coll", 1))
+
+	key := Params.ProxyCfg.ReleaseRecentReadWindow.Key
+	paramtable.Get().Save(key, "10")
+	defer paramtable.Get().Reset(key)
+
+	// read through another proxy
+	rc.EXPECT().GetMetrics(mock.Anything, mock.Anything).Return(lastReads(map[int64]int64{1: time.Now().UnixMilli()}), nil).Once()
+	assert.ErrorIs(t, checkRecentReads(ctx, rc, "coll", 1), merr.ErrParameterInvalid)
+
+	// read before the window
+	rc.EXPECT().GetMetrics(mock.Anything, mock.Anything).Return(lastReads(map[int64]int64{1: time.Now().Add(-time.Hour).UnixMilli()}), nil).Once()
+	assert.NoError(t, checkRecentReads(ctx, rc, "coll", 1))
+
+	// read through this proxy, while the rootcoord is unavailable
+	globalCollectionReads.record(2)
+	defer globalCollectionReads.lastReads.Remove(2)
+	rc.EXPECT().GetMetrics(mock.Anything, mock.Anything).Return(nil, errors.New("mock")).Once()
+	assert.ErrorIs(t, checkRecentReads(ctx, rc, "coll2", 2), merr.ErrParameterInvalid)
+
+	// forced
+	forceCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(forceReleaseKey, "true"))
+	assert.NoError(t, checkRecentReads(forceCtx, rc, "coll2", 2))
+}
@@ -0,0 +1,140 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// requestSizeAdvices are the alternatives suggested to the requests exceeding the size limit of their methods.
+var requestSizeAdvices = map[string]string{
+	"insert":       "split the rows into smaller batches, or write them into files and load the files by the bulk import (Import)",
+	"upsert":       "split the rows into smaller batches",
+	"search":       "split the query vectors into the searches of smaller nq",
+	"hybridsearch": "split the query vectors into the searches of smaller nq",
+	"query":        "split the filter, such as a long in list, into smaller queries, or page through the results by the query iterator",
+	"delete":       "split the filter, such as a long in list, into smaller deletes",
+}
+
+// requestSizeLimiter limits the request size of the methods, the limits are read once at the start, as the limit of
+// the grpc server, the max of them, is fixed once the server starts.
+type requestSizeLimiter struct {
+	// limits are keyed by the method name in lower case
+	limits       map[string]int
+	defaultLimit int
+}
+
+func newRequestSizeLimiter() *requestSizeLimiter {
+	params := paramtable.Get()
+	limiter := &requestSizeLimiter{
+		limits:       make(map[string]int),
+		defaultLimit: params.ProxyGrpcServerCfg.ServerMaxRecvSize.GetAsInt(),
+	}
+	for method, value := range params.ProxyCfg.MaxRequestSizePerMethod.GetValue() {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			log.Warn("invalid max request size of method, ignored", zap.String("method", method), zap.String("value", value))
+			continue
+		}
+		limiter.limits[strings.ToLower(method)] = limit
+	}
+	return limiter
+}
+
+// limit returns the max request size of the method.
+func (l *requestSizeLimiter) limit(method string) int {
+	if limit, ok := l.limits[strings.ToLower(method)]; ok {
+		return limit
+	}
+	return l.defaultLimit
+}
+
+// maxLimit returns the max of the limits, which the grpc server receives at most.
+func (l *requestSizeLimiter) maxLimit() int {
+	maxLimit := l.defaultLimit
+	for _, limit := range l.limits {
+		if limit > maxLimit {
+			maxLimit = limit
+		}
+	}
+	return maxLimit
+}
+
+// check returns ErrParameterTooLarge with the advice of the method if the request exceeds its limit.
+func (l *requestSizeLimiter) check(method string, req any) error {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil
+	}
+	limit := l.limit(method)
+	size := proto.Size(msg)
+	if size <= limit {
+		return nil
+	}
+	advice, ok := requestSizeAdvices[strings.ToLower(method)]
+	if !ok {
+		advice = "split it into smaller requests"
+	}
+	return merr.WrapErrParameterTooLarge("request size", fmt.Sprintf("the size (%d bytes) of the %s request exceeds the limit (%d bytes), %s",
+		size, method, limit, advice))
+}
+
+// requestSizeHeadroom is how far above the largest limit the grpc server still receives the requests, so that the
+// requests a bit over the limits reach RequestSizeLimitInterceptor and are rejected with the advice, instead of
+// the ResourceExhausted error of grpc.
+const requestSizeHeadroom = 16 << 20
+
+// MaxRequestSize returns the max of the request size limits of the methods.
+func MaxRequestSize() int {
+	return newRequestSizeLimiter().maxLimit()
+}
+
+// MaxRecvMsgSize returns the max message size of the proxy grpc server when the limits are enforced by
+// RequestSizeLimitInterceptor, which is the max of the limits plus the headroom.
+func MaxRecvMsgSize() int {
+	return MaxRequestSize() + requestSizeHeadroom
+}
+
+// RequestSizeLimitInterceptor returns a new unary server interceptor that rejects the requests exceeding the size
+// limit of their methods with the advice of the alternatives, instead of the ResourceExhausted error of grpc.
+func RequestSizeLimitInterceptor() grpc.UnaryServerInterceptor {
+	limiter := newRequestSizeLimiter()
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method := path.Base(info.FullMethod)
+		if err := limiter.check(method, req); err != nil {
+			log.Ctx(ctx).RatedInfo(10, "reject oversized request", zap.String("method", info.FullMethod), zap.Error(err))
+			if rsp := getFailedResponse(req, err); rsp != nil {
+				return rsp, nil
+			}
+			return nil, errors.Wrap(err, info.FullMethod)
+		}
+		return handler(ctx, req)
+	}
+}
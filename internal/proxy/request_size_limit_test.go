@@ -0,0 +1,80 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestRequestSizeLimiter(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.ProxyGrpcServerCfg.ServerMaxRecvSize.Key, "1024")
+	defer params.Reset(params.ProxyGrpcServerCfg.ServerMaxRecvSize.Key)
+	params.ProxyCfg.MaxRequestSizePerMethod.GetFunc = func() map[string]string {
+		return map[string]string{"insert": "128", "Search": "4096", "query": "invalid"}
+	}
+	defer func() { params.ProxyCfg.MaxRequestSizePerMethod.GetFunc = nil }()
+
+	limiter := newRequestSizeLimiter()
+	assert.Equal(t, 128, limiter.limit("Insert"))
+	assert.Equal(t, 4096, limiter.limit("Search"))
+	assert.Equal(t, 1024, limiter.limit("Query"))
+	assert.Equal(t, 1024, limiter.limit("Upsert"))
+	assert.Equal(t, 4096, limiter.maxLimit())
+	assert.Equal(t, 4096, MaxRequestSize())
+	assert.Equal(t, 4096+requestSizeHeadroom, MaxRecvMsgSize())
+
+	small := &milvuspb.InsertRequest{CollectionName: "coll"}
+	large := &milvuspb.InsertRequest{CollectionName: strings.Repeat("c", 256)}
+	assert.NoError(t, limiter.check("Insert", small))
+	err := limiter.check("Insert", large)
+	assert.ErrorIs(t, err, merr.ErrParameterTooLarge)
+	assert.Contains(t, err.Error(), "Import")
+	assert.NoError(t, limiter.check("Upsert", &milvuspb.UpsertRequest{CollectionName: strings.Repeat("c", 256)}))
+	err = limiter.check("Upsert", &milvuspb.UpsertRequest{CollectionName: strings.Repeat("c", 2048)})
+	assert.ErrorIs(t, err, merr.ErrParameterTooLarge)
+	err = limiter.check("CreateAlias", &milvuspb.CreateAliasRequest{Alias: strings.Repeat("c", 2048)})
+	assert.ErrorIs(t, err, merr.ErrParameterTooLarge)
+	// not a proto message
+	assert.NoError(t, limiter.check("Insert", strings.Repeat("c", 256)))
+
+	interceptor := RequestSizeLimitInterceptor()
+	handler := func(ctx context.Context, req any) (any, error) {
+		return &milvuspb.MutationResult{Status: merr.Success()}, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/milvus.proto.milvus.MilvusService/Insert"}
+	rsp, err := interceptor(context.Background(), small, info, handler)
+	assert.NoError(t, err)
+	assert.NoError(t, merr.Error(rsp.(*milvuspb.MutationResult).GetStatus()))
+	rsp, err = interceptor(context.Background(), large, info, handler)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, merr.Error(rsp.(*milvuspb.MutationResult).GetStatus()), merr.ErrParameterTooLarge)
+
+	info = &grpc.UnaryServerInfo{FullMethod: "/milvus.proto.milvus.MilvusService/ListAliases"}
+	_, err = interceptor(context.Background(), &milvuspb.ListAliasesRequest{CollectionName: strings.Repeat("c", 2048)}, info, handler)
+	assert.ErrorIs(t, err, merr.ErrParameterTooLarge)
+}
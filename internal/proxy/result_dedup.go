@@ -0,0 +1,51 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// deduplicateResultsByPKKey is the request metadata key to deduplicate the search and query results by primary key,
+// overriding proxy.deduplicateResultsByPK for the request.
+const deduplicateResultsByPKKey = "deduplicate_results_by_pk"
+
+// isResultDedupEnabled returns whether the hits sharing a primary key, returned by both the growing and the sealed
+// segments after upserts or by several replicas, are deduplicated in the whole reduce, including the skipped offset.
+// Otherwise only the returned page is deduplicated.
+func isResultDedupEnabled(ctx context.Context) bool {
+	return paramtable.Get().ProxyCfg.DeduplicateResultsByPK.GetAsBool() ||
+		isConfirmedByMetadata(ctx, deduplicateResultsByPKKey)
+}
+
+// retrieveResultTimestamp returns the timestamp of the idx-th row of the retrieve result,
+// 0 if the timestamp field is not retrieved.
+func retrieveResultTimestamp(result *internalpb.RetrieveResults, idx int64) uint64 {
+	for _, fieldData := range result.GetFieldsData() {
+		if fieldData.GetFieldId() == common.TimeStampField {
+			data := fieldData.GetScalars().GetLongData().GetData()
+			if idx < int64(len(data)) {
+				return uint64(data[idx])
+			}
+		}
+	}
+	return 0
+}
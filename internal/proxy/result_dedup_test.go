@@ -0,0 +1,120 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/metric"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func dedupContext() context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(deduplicateResultsByPKKey, "true"))
+}
+
+func TestIsResultDedupEnabled(t *testing.T) {
+	assert.False(t, isResultDedupEnabled(context.Background()))
+	assert.True(t, isResultDedupEnabled(dedupContext()))
+
+	paramtable.Get().Save(paramtable.Get().ProxyCfg.DeduplicateResultsByPK.Key, "true")
+	defer paramtable.Get().Reset(paramtable.Get().ProxyCfg.DeduplicateResultsByPK.Key)
+	assert.True(t, isResultDedupEnabled(context.Background()))
+}
+
+func TestReduceSearchResult_DedupOffset(t *testing.T) {
+	genResults := func() []*schemapb.SearchResultData {
+		r1 := genSearchResultData(1, 3, []int64{1, 2, 3}, []float32{10, 9, 8})
+		r1.Topks = []int64{3}
+		r2 := genSearchResultData(1, 3, []int64{1, 4, 5}, []float32{9.5, 7, 6})
+		r2.Topks = []int64{3}
+		return []*schemapb.SearchResultData{r1, r2}
+	}
+
+	// the duplicate of the skipped hit is returned on the page
+	result, err := reduceSearchResultDataNoGroupBy(context.Background(), genResults(), 1, 3, metric.IP, schemapb.DataType_Int64, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, result.GetResults().GetIds().GetIntId().GetData())
+
+	result, err = reduceSearchResultDataNoGroupBy(dedupContext(), genResults(), 1, 3, metric.IP, schemapb.DataType_Int64, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, result.GetResults().GetIds().GetIntId().GetData())
+	assert.Equal(t, []float32{9, 8}, result.GetResults().GetScores())
+	assert.Equal(t, []int64{2}, result.GetResults().GetTopks())
+}
+
+func TestRetrieveResultTimestamp(t *testing.T) {
+	result := &internalpb.RetrieveResults{
+		FieldsData: []*schemapb.FieldData{
+			getFieldData("int64", common.StartOfUserFieldID, schemapb.DataType_Int64, []int64{1, 2}, 1),
+			getFieldData(common.TimeStampFieldName, common.TimeStampField, schemapb.DataType_Int64, []int64{100, 200}, 1),
+		},
+	}
+	assert.EqualValues(t, 200, retrieveResultTimestamp(result, 1))
+	assert.EqualValues(t, 0, retrieveResultTimestamp(result, 2))
+	assert.EqualValues(t, 0, retrieveResultTimestamp(&internalpb.RetrieveResults{}, 0))
+}
+
+func TestReduceRetrieveResults_Dedup(t *testing.T) {
+	genResult := func(ids []int64, values []int64, ts []int64) *internalpb.RetrieveResults {
+		return &internalpb.RetrieveResults{
+			Ids: &schemapb.IDs{
+				IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}},
+			},
+			FieldsData: []*schemapb.FieldData{
+				getFieldData("int64", common.StartOfUserFieldID, schemapb.DataType_Int64, values, 1),
+				getFieldData(common.TimeStampFieldName, common.TimeStampField, schemapb.DataType_Int64, ts, 1),
+			},
+		}
+	}
+	genResults := func() []*internalpb.RetrieveResults {
+		return []*internalpb.RetrieveResults{
+			genResult([]int64{1, 2}, []int64{10, 20}, []int64{100, 100}),
+			genResult([]int64{1, 3}, []int64{11, 30}, []int64{200, 200}),
+		}
+	}
+
+	t.Run("latest version", func(t *testing.T) {
+		result, err := reduceRetrieveResults(context.Background(), genResults(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{10, 20, 30}, result.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+
+		result, err = reduceRetrieveResults(dedupContext(), genResults(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{11, 20, 30}, result.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+		assert.Equal(t, []int64{200, 100, 200}, result.GetFieldsData()[1].GetScalars().GetLongData().GetData())
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		params := &queryParams{limit: 2, offset: 1}
+		result, err := reduceRetrieveResults(context.Background(), genResults(), params)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{11, 20}, result.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+
+		result, err = reduceRetrieveResults(dedupContext(), genResults(), params)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{20, 30}, result.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+	})
+}
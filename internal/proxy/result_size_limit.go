@@ -0,0 +1,256 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	resultSizeLimitError = "error"
+	resultSizeLimitSpill = "spill"
+)
+
+// the extra info keys of the status of the spilled result, the result data is replaced by the url to download
+// the serialized result, in the protobuf format of the message type.
+const (
+	spilledResultURLKey    = "spilled_result_url"
+	spilledResultSchemaKey = "spilled_result_schema"
+	spilledResultFormatKey = "spilled_result_format"
+	spilledResultSizeKey   = "spilled_result_size"
+)
+
+type spilledFieldSchema struct {
+	FieldID int64  `json:"field_id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+}
+
+func spilledResultSchema(fieldsData []*schemapb.FieldData) []*spilledFieldSchema {
+	schema := make([]*spilledFieldSchema, 0, len(fieldsData))
+	for _, fieldData := range fieldsData {
+		schema = append(schema, &spilledFieldSchema{
+			FieldID: fieldData.GetFieldId(),
+			Name:    fieldData.GetFieldName(),
+			Type:    fieldData.GetType().String(),
+		})
+	}
+	return schema
+}
+
+// presignedChunkManager is the chunk manager able to presign the urls, like the remote one.
+type presignedChunkManager interface {
+	storage.ChunkManager
+	PresignedURL(ctx context.Context, filePath string, expires time.Duration) (string, error)
+}
+
+// resultSpiller writes the oversized results to the object storage.
+type resultSpiller struct {
+	mu      sync.Mutex
+	factory storage.Factory
+	cm      storage.ChunkManager
+}
+
+var globalResultSpiller = &resultSpiller{}
+
+func (s *resultSpiller) init(factory storage.Factory) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.factory = factory
+}
+
+// getChunkManager creates the chunk manager of the object storage at the first use.
+func (s *resultSpiller) getChunkManager(ctx context.Context) (presignedChunkManager, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cm == nil {
+		if s.factory == nil {
+			return nil, merr.WrapErrServiceNotReady(paramtable.GetRole(), paramtable.GetNodeID(), "result spiller not initialized")
+		}
+		cm, err := s.factory.NewPersistentStorageChunkManager(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.cm = cm
+	}
+	cm, ok := s.cm.(presignedChunkManager)
+	if !ok {
+		return nil, merr.WrapErrServiceUnimplemented(fmt.Errorf("presigned url is not supported by the %T storage", s.cm))
+	}
+	return cm, nil
+}
+
+// spill writes the serialized result to the object storage and returns the status with the url to download it.
+func (s *resultSpiller) spill(ctx context.Context, result proto.Message, fieldsData []*schemapb.FieldData) (*commonpb.Status, error) {
+	cm, err := s.getChunkManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := proto.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	filePath := path.Join(cm.RootPath(), Params.ProxyCfg.ResultSpillPath.GetValue(),
+		fmt.Sprintf("%d-%d", paramtable.GetNodeID(), time.Now().UnixNano()))
+	if err := cm.Write(ctx, filePath, bs); err != nil {
+		return nil, err
+	}
+	url, err := cm.PresignedURL(ctx, filePath, Params.ProxyCfg.ResultSpillURLExpiration.GetAsDuration(time.Second))
+	if err != nil {
+		return nil, err
+	}
+	schema, err := json.Marshal(spilledResultSchema(fieldsData))
+	if err != nil {
+		return nil, err
+	}
+
+	status := merr.Success()
+	status.ExtraInfo = map[string]string{
+		spilledResultURLKey:    url,
+		spilledResultSchemaKey: string(schema),
+		spilledResultFormatKey: "protobuf:" + proto.MessageName(result),
+		spilledResultSizeKey:   strconv.Itoa(len(bs)),
+	}
+	log.Ctx(ctx).Info("spill oversized result to object storage", zap.String("path", filePath), zap.Int("size", len(bs)))
+	return status, nil
+}
+
+// gc removes the spilled results older than the expiration of their urls, which are not downloadable any more.
+// The spilled results of all the proxies are removed, so the ones of the proxies gone are not leaked.
+func (s *resultSpiller) gc(ctx context.Context) {
+	cm, err := s.getChunkManager(ctx)
+	if err != nil {
+		return
+	}
+	prefix := path.Join(cm.RootPath(), Params.ProxyCfg.ResultSpillPath.GetValue()) + "/"
+	deadline := time.Now().Add(-Params.ProxyCfg.ResultSpillURLExpiration.GetAsDuration(time.Second))
+	expired := make([]string, 0)
+	err = cm.WalkWithPrefix(ctx, prefix, true, func(info *storage.ChunkObjectInfo) bool {
+		if info.ModifyTime.Before(deadline) {
+			expired = append(expired, info.FilePath)
+		}
+		return true
+	})
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to list spilled results", zap.String("prefix", prefix), zap.Error(err))
+		return
+	}
+	if len(expired) == 0 {
+		return
+	}
+	if err := cm.MultiRemove(ctx, expired); err != nil {
+		log.Ctx(ctx).Warn("failed to remove expired spilled results", zap.Int("num", len(expired)), zap.Error(err))
+		return
+	}
+	log.Ctx(ctx).Info("removed expired spilled results", zap.Int("num", len(expired)))
+}
+
+// resultSpillGCLoop removes the expired spilled results periodically while spilling is enabled.
+func (node *Proxy) resultSpillGCLoop() {
+	defer node.wg.Done()
+	ticker := time.NewTicker(Params.ProxyCfg.ResultSpillGCInterval.GetAsDuration(time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-node.ctx.Done():
+			return
+		case <-ticker.C:
+			if Params.ProxyCfg.ResultSizeLimitBehavior.GetValue() == resultSizeLimitSpill {
+				globalResultSpiller.gc(node.ctx)
+			}
+		}
+	}
+}
+
+// limitResultSize checks the serialized size of the result against the max result size, it returns the status of
+// the spilled result if the result is oversized, spilling is enabled and the client opts in by AllowSpillKey.
+// The clients not aware of the spilled results always get the error, instead of a successful empty result.
+func limitResultSize(ctx context.Context, result proto.Message, fieldsData []*schemapb.FieldData, allowSpill bool) (*commonpb.Status, error) {
+	limit := Params.ProxyCfg.MaxResultSize.GetAsInt64() * 1024 * 1024
+	if limit <= 0 {
+		return nil, nil
+	}
+	size := int64(proto.Size(result))
+	if size <= limit {
+		return nil, nil
+	}
+	if Params.ProxyCfg.ResultSizeLimitBehavior.GetValue() != resultSizeLimitSpill {
+		return nil, merr.WrapErrServiceResultSizeExceeded(size, limit,
+			"reduce the limit or the output fields, or enable spilling the results to the object storage")
+	}
+	if !allowSpill {
+		return nil, merr.WrapErrServiceResultSizeExceeded(size, limit,
+			fmt.Sprintf("reduce the limit or the output fields, or set %s to receive the result spilled to the object storage", AllowSpillKey))
+	}
+	status, err := globalResultSpiller.spill(ctx, result, fieldsData)
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to spill oversized result", zap.Int64("size", size), zap.Error(err))
+		return nil, merr.WrapErrServiceResultSizeExceeded(size, limit, fmt.Sprintf("failed to spill the result, %s", err.Error()))
+	}
+	return status, nil
+}
+
+// limitSearchResultSize replaces the oversized search results with the error or the spilled result,
+// the params are the search params of the request.
+func limitSearchResultSize(ctx context.Context, rsp *milvuspb.SearchResults, params []*commonpb.KeyValuePair) *milvuspb.SearchResults {
+	if !merr.Ok(rsp.GetStatus()) {
+		return rsp
+	}
+	allowSpill, _ := parseBoolSearchParam(AllowSpillKey, params)
+	status, err := limitResultSize(ctx, rsp, rsp.GetResults().GetFieldsData(), allowSpill)
+	if err != nil {
+		return &milvuspb.SearchResults{Status: merr.Status(err)}
+	}
+	if status != nil {
+		return &milvuspb.SearchResults{Status: status, CollectionName: rsp.GetCollectionName()}
+	}
+	return rsp
+}
+
+// limitQueryResultSize replaces the oversized query results with the error or the spilled result,
+// the params are the query params of the request.
+func limitQueryResultSize(ctx context.Context, rsp *milvuspb.QueryResults, params []*commonpb.KeyValuePair) *milvuspb.QueryResults {
+	if !merr.Ok(rsp.GetStatus()) {
+		return rsp
+	}
+	allowSpill, _ := parseBoolSearchParam(AllowSpillKey, params)
+	status, err := limitResultSize(ctx, rsp, rsp.GetFieldsData(), allowSpill)
+	if err != nil {
+		return &milvuspb.QueryResults{Status: merr.Status(err)}
+	}
+	if status != nil {
+		return &milvuspb.QueryResults{Status: status, CollectionName: rsp.GetCollectionName(), OutputFields: rsp.GetOutputFields()}
+	}
+	return rsp
+}
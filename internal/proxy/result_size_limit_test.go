@@ -0,0 +1,136 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type presignedLocalChunkManager struct {
+	*storage.LocalChunkManager
+}
+
+func (cm *presignedLocalChunkManager) PresignedURL(ctx context.Context, filePath string, expires time.Duration) (string, error) {
+	return "file://" + filePath, nil
+}
+
+func newOversizedQueryResults() *milvuspb.QueryResults {
+	return &milvuspb.QueryResults{
+		Status:         merr.Success(),
+		CollectionName: "coll",
+		OutputFields:   []string{"text"},
+		FieldsData: []*schemapb.FieldData{{
+			Type:      schemapb.DataType_VarChar,
+			FieldName: "text",
+			FieldId:   100,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{
+					Data: []string{strings.Repeat("a", 2*1024*1024)},
+				}},
+			}},
+		}},
+	}
+}
+
+func TestLimitResultSize(t *testing.T) {
+	ctx := context.Background()
+	params := paramtable.Get()
+	allowSpill := []*commonpb.KeyValuePair{{Key: AllowSpillKey, Value: "true"}}
+
+	t.Run("no limit", func(t *testing.T) {
+		res := newOversizedQueryResults()
+		assert.Equal(t, res, limitQueryResultSize(ctx, res, nil))
+	})
+
+	params.Save(params.ProxyCfg.MaxResultSize.Key, "1")
+	defer params.Reset(params.ProxyCfg.MaxResultSize.Key)
+
+	t.Run("error", func(t *testing.T) {
+		res := limitQueryResultSize(ctx, newOversizedQueryResults(), allowSpill)
+		assert.ErrorIs(t, merr.Error(res.GetStatus()), merr.ErrServiceResultSizeExceeded)
+
+		small := &milvuspb.SearchResults{Status: merr.Success(), CollectionName: "coll"}
+		assert.Equal(t, small, limitSearchResultSize(ctx, small, nil))
+	})
+
+	params.Save(params.ProxyCfg.ResultSizeLimitBehavior.Key, resultSizeLimitSpill)
+	defer params.Reset(params.ProxyCfg.ResultSizeLimitBehavior.Key)
+	spiller := globalResultSpiller
+	defer func() { globalResultSpiller = spiller }()
+
+	t.Run("spill not supported", func(t *testing.T) {
+		globalResultSpiller = &resultSpiller{cm: storage.NewLocalChunkManager(storage.RootPath(t.TempDir()))}
+		res := limitQueryResultSize(ctx, newOversizedQueryResults(), allowSpill)
+		assert.ErrorIs(t, merr.Error(res.GetStatus()), merr.ErrServiceResultSizeExceeded)
+	})
+
+	t.Run("spill", func(t *testing.T) {
+		dir := t.TempDir()
+		globalResultSpiller = &resultSpiller{cm: &presignedLocalChunkManager{storage.NewLocalChunkManager(storage.RootPath(dir))}}
+
+		// the clients not opted in get the error
+		res := limitQueryResultSize(ctx, newOversizedQueryResults(), nil)
+		assert.ErrorIs(t, merr.Error(res.GetStatus()), merr.ErrServiceResultSizeExceeded)
+		assert.Contains(t, res.GetStatus().GetReason(), AllowSpillKey)
+
+		res = limitQueryResultSize(ctx, newOversizedQueryResults(), allowSpill)
+		assert.True(t, merr.Ok(res.GetStatus()))
+		assert.Empty(t, res.GetFieldsData())
+		assert.Equal(t, []string{"text"}, res.GetOutputFields())
+
+		extraInfo := res.GetStatus().GetExtraInfo()
+		assert.Equal(t, "protobuf:milvus.proto.milvus.QueryResults", extraInfo[spilledResultFormatKey])
+		var schema []*spilledFieldSchema
+		assert.NoError(t, json.Unmarshal([]byte(extraInfo[spilledResultSchemaKey]), &schema))
+		assert.Equal(t, []*spilledFieldSchema{{FieldID: 100, Name: "text", Type: "VarChar"}}, schema)
+
+		filePath := strings.TrimPrefix(extraInfo[spilledResultURLKey], "file://")
+		assert.True(t, strings.HasPrefix(filePath, path.Join(dir, params.ProxyCfg.ResultSpillPath.GetValue())))
+		bs, err := globalResultSpiller.cm.Read(ctx, filePath)
+		assert.NoError(t, err)
+		spilled := &milvuspb.QueryResults{}
+		assert.NoError(t, proto.Unmarshal(bs, spilled))
+		assert.Equal(t, 2*1024*1024, len(spilled.GetFieldsData()[0].GetScalars().GetStringData().GetData()[0]))
+
+		// removed once the url expires
+		globalResultSpiller.gc(ctx)
+		exist, err := globalResultSpiller.cm.Exist(ctx, filePath)
+		assert.NoError(t, err)
+		assert.True(t, exist)
+		params.Save(params.ProxyCfg.ResultSpillURLExpiration.Key, "0")
+		defer params.Reset(params.ProxyCfg.ResultSpillURLExpiration.Key)
+		globalResultSpiller.gc(ctx)
+		exist, err = globalResultSpiller.cm.Exist(ctx, filePath)
+		assert.NoError(t, err)
+		assert.False(t, exist)
+	})
+}
@@ -38,6 +38,8 @@ func rpcFailedToEnqueue(method string) string {
 	return fmt.Sprintf("%s failed to enqueue", method)
 }
 
+// rpcFailedToWaitToFinish is logged when the task of the rpc failed, the failed stage of the task
+// is recorded in the task events.
 func rpcFailedToWaitToFinish(method string) string {
-	return fmt.Sprintf("%s failed to WaitToFinish", method)
+	return fmt.Sprintf("%s task failed", method)
 }
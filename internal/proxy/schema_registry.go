@@ -0,0 +1,191 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/jsonpb"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// maxSchemaDocumentSize limits the size of schema document fetched from schema registry.
+const maxSchemaDocumentSize = 4 << 20
+
+// schemaDocument is the schema document stored in schema registry,
+// a bare json format schemapb.CollectionSchema is also accepted.
+type schemaDocument struct {
+	Version string          `json:"version"`
+	Schema  json.RawMessage `json:"schema"`
+}
+
+func getSchemaRegistryRef(properties []*commonpb.KeyValuePair) string {
+	for _, kv := range properties {
+		if kv.GetKey() == common.CollectionSchemaRegistryRefKey {
+			return strings.TrimSpace(kv.GetValue())
+		}
+	}
+	return ""
+}
+
+// resolveSchemaRegistryURL returns the url of the schema document, ref is either an url or an id in the configured registry.
+// The url refs are restricted to the scheme and host of the configured registry, so the proxy never fetches from the
+// hosts chosen by the users.
+func resolveSchemaRegistryURL(ref string) (string, error) {
+	address := Params.ProxyCfg.SchemaRegistryAddress.GetValue()
+	if address == "" {
+		return "", merr.WrapErrParameterInvalidMsg("schema registry address not configured, cannot resolve schema ref %s", ref)
+	}
+	registry, err := url.Parse(address)
+	if err != nil || registry.Host == "" {
+		return "", merr.WrapErrParameterInvalidMsg("invalid schema registry address %s", address)
+	}
+	if !strings.HasPrefix(ref, "http://") && !strings.HasPrefix(ref, "https://") {
+		return strings.TrimSuffix(address, "/") + "/" + url.PathEscape(ref), nil
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", merr.WrapErrParameterInvalidMsg("invalid schema ref %s, %s", ref, err.Error())
+	}
+	if !sameOrigin(refURL, registry) || refURL.User != nil {
+		return "", merr.WrapErrParameterInvalidMsg("schema ref %s is not in the schema registry %s", ref, address)
+	}
+	return refURL.String(), nil
+}
+
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host)
+}
+
+// newSchemaRegistryClient returns the http client fetching from schema registry, which follows no redirect out of the
+// registry and times out by itself besides the context.
+func newSchemaRegistryClient(registry *url.URL) *http.Client {
+	return &http.Client{
+		Timeout: Params.ProxyCfg.SchemaRegistryTimeout.GetAsDuration(time.Second),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !sameOrigin(req.URL, registry) {
+				return fmt.Errorf("redirect to %s out of schema registry", req.URL.Redacted())
+			}
+			if len(via) >= 3 {
+				return errors.New("too many redirects")
+			}
+			return nil
+		},
+	}
+}
+
+// fetchRegistrySchema fetches the schema document referenced by ref and converts it to collection schema,
+// the version of the document is returned along with the schema.
+func fetchRegistrySchema(ctx context.Context, ref string) (*schemapb.CollectionSchema, string, error) {
+	if !Params.ProxyCfg.SchemaRegistryEnabled.GetAsBool() {
+		return nil, "", merr.WrapErrParameterInvalidMsg("schema registry is disabled, set proxy.schemaRegistry.enabled to enable it")
+	}
+	docURL, err := resolveSchemaRegistryURL(ref)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, Params.ProxyCfg.SchemaRegistryTimeout.GetAsDuration(time.Second))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, docURL, nil)
+	if err != nil {
+		return nil, "", merr.WrapErrParameterInvalidMsg("invalid schema registry url %s, %s", docURL, err.Error())
+	}
+	resp, err := newSchemaRegistryClient(req.URL).Do(req)
+	if err != nil {
+		return nil, "", merr.WrapErrServiceUnavailable(err.Error(), fmt.Sprintf("failed to fetch schema from %s", docURL))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", merr.WrapErrServiceUnavailable(resp.Status, fmt.Sprintf("failed to fetch schema from %s", docURL))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSchemaDocumentSize+1))
+	if err != nil {
+		return nil, "", merr.WrapErrServiceUnavailable(err.Error(), fmt.Sprintf("failed to fetch schema from %s", docURL))
+	}
+	if len(body) > maxSchemaDocumentSize {
+		return nil, "", merr.WrapErrParameterInvalidMsg("schema document %s exceeds %d bytes", docURL, maxSchemaDocumentSize)
+	}
+	return parseSchemaDocument(body)
+}
+
+func parseSchemaDocument(body []byte) (*schemapb.CollectionSchema, string, error) {
+	doc := &schemaDocument{}
+	if err := json.Unmarshal(body, doc); err != nil {
+		return nil, "", merr.WrapErrParameterInvalidMsg("invalid schema document, %s", err.Error())
+	}
+	raw := doc.Schema
+	if len(raw) == 0 {
+		raw = body
+		doc.Version = ""
+	}
+
+	schema := &schemapb.CollectionSchema{}
+	if err := jsonpb.UnmarshalString(string(raw), schema); err != nil {
+		return nil, "", merr.WrapErrParameterInvalidMsg("invalid schema document, %s", err.Error())
+	}
+
+	version := doc.Version
+	if version == "" {
+		// documents without version are identified by content digest
+		digest := sha256.Sum256(raw)
+		version = "sha256:" + hex.EncodeToString(digest[:])
+	}
+	return schema, version, nil
+}
+
+// applyRegistrySchema replaces the schema of the request with the one referenced in schema registry,
+// and records the fetched version in collection properties.
+func (t *createCollectionTask) applyRegistrySchema(ctx context.Context) error {
+	ref := getSchemaRegistryRef(t.GetProperties())
+	if ref == "" {
+		return nil
+	}
+	if len(t.GetSchema()) > 0 {
+		return merr.WrapErrParameterInvalidMsg("schema and %s cannot be specified at the same time", common.CollectionSchemaRegistryRefKey)
+	}
+
+	schema, version, err := fetchRegistrySchema(ctx, ref)
+	if err != nil {
+		return err
+	}
+	schema.Name = t.GetCollectionName()
+	t.schema = schema
+
+	properties := make([]*commonpb.KeyValuePair, 0, len(t.Properties)+1)
+	for _, kv := range t.Properties {
+		if kv.GetKey() != common.CollectionSchemaRegistryVersionKey {
+			properties = append(properties, kv)
+		}
+	}
+	t.Properties = append(properties, &commonpb.KeyValuePair{Key: common.CollectionSchemaRegistryVersionKey, Value: version})
+	return nil
+}
@@ -0,0 +1,152 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const testRegistrySchema = `{
+	"name": "ignored",
+	"fields": [
+		{"fieldID": 100, "name": "pk", "is_primary_key": true, "data_type": "Int64"},
+		{"fieldID": 101, "name": "vec", "data_type": "FloatVector", "type_params": [{"key": "dim", "value": "8"}]}
+	]
+}`
+
+func TestParseSchemaDocument(t *testing.T) {
+	schema, version, err := parseSchemaDocument([]byte(`{"version": "v2", "schema": ` + testRegistrySchema + `}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", version)
+	assert.Len(t, schema.GetFields(), 2)
+	assert.Equal(t, schemapb.DataType_FloatVector, schema.GetFields()[1].GetDataType())
+
+	schema, version, err = parseSchemaDocument([]byte(testRegistrySchema))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(version, "sha256:"))
+	assert.Len(t, schema.GetFields(), 2)
+
+	_, _, err = parseSchemaDocument([]byte(`invalid`))
+	assert.Error(t, err)
+}
+
+func TestCreateCollectionTask_RegistrySchema(t *testing.T) {
+	paramtable.Init()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schemas/s1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"version": "3", "schema": ` + testRegistrySchema + `}`))
+	}))
+	defer server.Close()
+
+	newTask := func(ref string) *createCollectionTask {
+		return &createCollectionTask{
+			ctx: context.Background(),
+			CreateCollectionRequest: &milvuspb.CreateCollectionRequest{
+				Base:           &commonpb.MsgBase{},
+				CollectionName: "test_registry",
+				ShardsNum:      common.DefaultShardsNum,
+				Properties:     []*commonpb.KeyValuePair{{Key: common.CollectionSchemaRegistryRefKey, Value: ref}},
+			},
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		task := newTask(server.URL + "/schemas/s1")
+		assert.Error(t, task.PreExecute(context.Background()))
+	})
+
+	paramtable.Get().Save(Params.ProxyCfg.SchemaRegistryEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.SchemaRegistryEnabled.Key)
+
+	t.Run("url without address", func(t *testing.T) {
+		task := newTask(server.URL + "/schemas/s1")
+		assert.Error(t, task.PreExecute(context.Background()))
+	})
+
+	paramtable.Get().Save(Params.ProxyCfg.SchemaRegistryAddress.Key, server.URL+"/schemas/")
+	defer paramtable.Get().Reset(Params.ProxyCfg.SchemaRegistryAddress.Key)
+
+	t.Run("fetch by url", func(t *testing.T) {
+		task := newTask(server.URL + "/schemas/s1")
+		assert.NoError(t, task.PreExecute(context.Background()))
+		assert.Equal(t, "test_registry", task.schema.GetName())
+		assert.NotEmpty(t, task.GetSchema())
+		version := ""
+		for _, kv := range task.GetProperties() {
+			if kv.GetKey() == common.CollectionSchemaRegistryVersionKey {
+				version = kv.GetValue()
+			}
+		}
+		assert.Equal(t, "3", version)
+	})
+
+	t.Run("fetch by id", func(t *testing.T) {
+		task := newTask("s1")
+		assert.NoError(t, task.PreExecute(context.Background()))
+		assert.Len(t, task.schema.GetFields(), 2)
+	})
+
+	t.Run("url out of registry", func(t *testing.T) {
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"version": "3", "schema": ` + testRegistrySchema + `}`))
+		}))
+		defer other.Close()
+		task := newTask(other.URL + "/schemas/s1")
+		assert.Error(t, task.PreExecute(context.Background()))
+	})
+
+	t.Run("redirect out of registry", func(t *testing.T) {
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"version": "3", "schema": ` + testRegistrySchema + `}`))
+		}))
+		defer other.Close()
+		redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, other.URL+"/schemas/s1", http.StatusFound)
+		}))
+		defer redirect.Close()
+		paramtable.Get().Save(Params.ProxyCfg.SchemaRegistryAddress.Key, redirect.URL)
+		defer paramtable.Get().Save(Params.ProxyCfg.SchemaRegistryAddress.Key, server.URL+"/schemas/")
+		task := newTask("s1")
+		assert.Error(t, task.PreExecute(context.Background()))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		task := newTask(server.URL + "/schemas/s2")
+		assert.Error(t, task.PreExecute(context.Background()))
+	})
+
+	t.Run("both schema and ref", func(t *testing.T) {
+		task := newTask(server.URL + "/schemas/s1")
+		task.Schema = []byte{1}
+		assert.Error(t, task.PreExecute(context.Background()))
+	})
+}
@@ -0,0 +1,151 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// IndexSpec describes an index to be created on the validated schema.
+type IndexSpec struct {
+	FieldName string            `json:"field_name"`
+	IndexName string            `json:"index_name,omitempty"`
+	Params    map[string]string `json:"params,omitempty"`
+}
+
+// ValidateSchemaRequest is the request of the dry-run DDL validation.
+// Schema is the json format of schemapb.CollectionSchema.
+type ValidateSchemaRequest struct {
+	DbName        string          `json:"db_name,omitempty"`
+	Schema        json.RawMessage `json:"schema"`
+	ShardsNum     int32           `json:"shards_num,omitempty"`
+	NumPartitions int64           `json:"num_partitions,omitempty"`
+	Indexes       []*IndexSpec    `json:"indexes,omitempty"`
+}
+
+// SchemaViolation is a single failed validation.
+type SchemaViolation struct {
+	Check   string `json:"check"`
+	Field   string `json:"field,omitempty"`
+	Index   string `json:"index,omitempty"`
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+}
+
+func newSchemaViolation(check string, err error) *SchemaViolation {
+	return &SchemaViolation{
+		Check:   check,
+		Code:    merr.Code(err),
+		Message: err.Error(),
+	}
+}
+
+// ValidateCollectionSchema runs all the CreateCollection and CreateIndex validations without creating anything,
+// all violations are returned instead of the first one. Error is returned only if the request is malformed.
+func ValidateCollectionSchema(ctx context.Context, req *ValidateSchemaRequest) ([]*SchemaViolation, error) {
+	if len(req.Schema) == 0 {
+		return nil, merr.WrapErrParameterMissing("schema")
+	}
+	schema := &schemapb.CollectionSchema{}
+	if err := jsonpb.UnmarshalString(string(req.Schema), schema); err != nil {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid schema, %s", err.Error())
+	}
+	schema.AutoID = false
+
+	shardsNum := req.ShardsNum
+	if shardsNum <= 0 {
+		shardsNum = common.DefaultShardsNum
+	}
+	t := &createCollectionTask{
+		ctx: ctx,
+		CreateCollectionRequest: &milvuspb.CreateCollectionRequest{
+			DbName:         req.DbName,
+			CollectionName: schema.GetName(),
+			ShardsNum:      shardsNum,
+			NumPartitions:  req.NumPartitions,
+		},
+		schema: schema,
+	}
+
+	violations := make([]*SchemaViolation, 0)
+	for _, c := range t.schemaChecks() {
+		if err := c.check(); err != nil {
+			violation := newSchemaViolation(c.name, err)
+			violation.Field = c.field
+			violations = append(violations, violation)
+		}
+	}
+
+	for _, spec := range req.Indexes {
+		violations = append(violations, validateIndexSpec(ctx, schema, spec)...)
+	}
+	return violations, nil
+}
+
+func validateIndexSpec(ctx context.Context, schema *schemapb.CollectionSchema, spec *IndexSpec) []*SchemaViolation {
+	newViolation := func(check string, err error) *SchemaViolation {
+		violation := newSchemaViolation(check, err)
+		violation.Field = spec.FieldName
+		violation.Index = spec.IndexName
+		return violation
+	}
+
+	var fieldSchema *schemapb.FieldSchema
+	for _, field := range schema.GetFields() {
+		if field.GetName() == spec.FieldName {
+			fieldSchema = proto.Clone(field).(*schemapb.FieldSchema)
+			break
+		}
+	}
+	if fieldSchema == nil {
+		return []*SchemaViolation{newViolation("index_field", merr.WrapErrFieldNotFound(spec.FieldName))}
+	}
+
+	extraParams := make([]*commonpb.KeyValuePair, 0, len(spec.Params))
+	for key, value := range spec.Params {
+		extraParams = append(extraParams, &commonpb.KeyValuePair{Key: key, Value: value})
+	}
+	cit := &createIndexTask{
+		ctx: ctx,
+		req: &milvuspb.CreateIndexRequest{
+			CollectionName: schema.GetName(),
+			FieldName:      spec.FieldName,
+			IndexName:      spec.IndexName,
+			ExtraParams:    extraParams,
+		},
+		fieldSchema: fieldSchema,
+	}
+
+	violations := make([]*SchemaViolation, 0)
+	if err := validateIndexName(spec.IndexName); err != nil {
+		violations = append(violations, newViolation("index_name", err))
+	}
+	if err := cit.parseIndexParams(); err != nil {
+		violations = append(violations, newViolation("index_params", err))
+	}
+	return violations
+}
@@ -0,0 +1,86 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestValidateCollectionSchema(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+
+	t.Run("valid schema", func(t *testing.T) {
+		violations, err := ValidateCollectionSchema(ctx, &ValidateSchemaRequest{
+			Schema: []byte(`{
+				"name": "test_validate",
+				"fields": [
+					{"fieldID": 100, "name": "pk", "is_primary_key": true, "data_type": "Int64"},
+					{"fieldID": 101, "name": "vec", "data_type": "FloatVector", "type_params": [{"key": "dim", "value": "8"}]}
+				]
+			}`),
+			Indexes: []*IndexSpec{
+				{FieldName: "vec", IndexName: "vec_idx", Params: map[string]string{"index_type": "FLAT", "metric_type": "L2"}},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, violations)
+	})
+
+	t.Run("collect all violations", func(t *testing.T) {
+		violations, err := ValidateCollectionSchema(ctx, &ValidateSchemaRequest{
+			ShardsNum: 1024,
+			Schema: []byte(`{
+				"name": "test_validate",
+				"fields": [
+					{"fieldID": 100, "name": "pk", "data_type": "Int64"},
+					{"fieldID": 101, "name": "vec", "data_type": "FloatVector"},
+					{"fieldID": 102, "name": "str", "data_type": "VarChar"}
+				]
+			}`),
+			Indexes: []*IndexSpec{
+				{FieldName: "vec", Params: map[string]string{"index_type": "FLAT", "metric_type": "HAMMING"}},
+				{FieldName: "not_exist"},
+			},
+		})
+		assert.NoError(t, err)
+
+		checks := make(map[string]int)
+		for _, violation := range violations {
+			checks[violation.Check]++
+			assert.NotEmpty(t, violation.Message)
+		}
+		assert.Equal(t, 1, checks["shard_num"])
+		assert.Equal(t, 1, checks["primary_key"])
+		assert.Equal(t, 2, checks["field"])
+		assert.Equal(t, 1, checks["index_field"])
+		assert.Equal(t, 1, checks["index_params"])
+	})
+
+	t.Run("malformed request", func(t *testing.T) {
+		_, err := ValidateCollectionSchema(ctx, &ValidateSchemaRequest{})
+		assert.Error(t, err)
+
+		_, err = ValidateCollectionSchema(ctx, &ValidateSchemaRequest{Schema: []byte(`{"fields": 1}`)})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,72 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// setSchemaVersion stamps the version of the cached schema the request is planned with in the msg base,
+// the querynodes reject the search and query requests planned with a different schema.
+func setSchemaVersion(base *commonpb.MsgBase, schema *schemaInfo) {
+	if base == nil || schema == nil || schema.version == "" {
+		return
+	}
+	if base.Properties == nil {
+		base.Properties = make(map[string]string)
+	}
+	base.Properties[common.SchemaVersionKey] = schema.version
+}
+
+// copySchemaVersion copies the schema version of src to dst, if any.
+func copySchemaVersion(dst, src *commonpb.MsgBase) {
+	version, ok := src.GetProperties()[common.SchemaVersionKey]
+	if !ok || dst == nil {
+		return
+	}
+	if dst.Properties == nil {
+		dst.Properties = make(map[string]string)
+	}
+	dst.Properties[common.SchemaVersionKey] = version
+}
+
+// schemaMismatchRetrier retries the request once with the collection meta refreshed,
+// if the querynodes have loaded a schema different from the cached one, e.g. after the collection is altered.
+type schemaMismatchRetrier struct {
+	retried bool
+}
+
+// shouldRetry removes the cached collection and returns true on the first schema mismatch.
+func (r *schemaMismatchRetrier) shouldRetry(ctx context.Context, dbName, collectionName string, status *commonpb.Status) bool {
+	err := merr.Error(status)
+	if r.retried || !errors.Is(err, merr.ErrCollectionSchemaMismatch) {
+		return false
+	}
+	r.retried = true
+	log.Ctx(ctx).Info("schema version mismatch, retry with the collection meta refreshed",
+		zap.String("db", dbName), zap.String("collection", collectionName), zap.Error(err))
+	globalMetaCache.RemoveCollection(ctx, dbName, collectionName)
+	return true
+}
@@ -0,0 +1,77 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+func TestSetSchemaVersion(t *testing.T) {
+	schema := newSchemaInfo(&schemapb.CollectionSchema{
+		Name: "test",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+		},
+	})
+	assert.Equal(t, typeutil.SchemaVersion(schema.CollectionSchema), schema.version)
+
+	// no panic
+	setSchemaVersion(nil, schema)
+
+	base := &commonpb.MsgBase{}
+	setSchemaVersion(base, &schemaInfo{})
+	assert.Empty(t, base.GetProperties())
+
+	setSchemaVersion(base, schema)
+	assert.Equal(t, schema.version, base.GetProperties()[common.SchemaVersionKey])
+
+	dst := &commonpb.MsgBase{}
+	copySchemaVersion(dst, &commonpb.MsgBase{})
+	assert.Empty(t, dst.GetProperties())
+	copySchemaVersion(nil, base)
+	copySchemaVersion(dst, base)
+	assert.Equal(t, schema.version, dst.GetProperties()[common.SchemaVersionKey])
+}
+
+func TestSchemaMismatchRetrier(t *testing.T) {
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().RemoveCollection(mock.Anything, "db", "coll").Return().Once()
+	globalMetaCache = mockCache
+
+	ctx := context.Background()
+	retrier := &schemaMismatchRetrier{}
+	assert.False(t, retrier.shouldRetry(ctx, "db", "coll", merr.Success()))
+	assert.False(t, retrier.shouldRetry(ctx, "db", "coll", merr.Status(merr.ErrServiceInternal)))
+
+	mismatch := merr.Status(merr.WrapErrCollectionSchemaMismatch("coll", "v1", "v2"))
+	assert.True(t, retrier.shouldRetry(ctx, "db", "coll", mismatch))
+	// retry only once
+	assert.False(t, retrier.shouldRetry(ctx, "db", "coll", mismatch))
+}
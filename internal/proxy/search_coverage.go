@@ -0,0 +1,89 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// searchCoverageInfoKey is set in the extra info of the search result status if some shards of the collection are
+// partially loaded, the value is the json of the searchCoverage.
+const searchCoverageInfoKey = "coverage"
+
+// partialShardLeadersTTL is how long the leaders of the partially loaded shards are cached, so that the searches
+// move to the leaders serving all the segments soon after they are loaded.
+const partialShardLeadersTTL = 3 * time.Second
+
+// searchCoverage is the percentage of the sealed segments searched, of the collection and of the partially loaded
+// shards, the shards not listed are searched fully.
+type searchCoverage struct {
+	Percentage float64            `json:"percentage"`
+	Shards     map[string]float64 `json:"shards"`
+}
+
+// globalShardCoverages is the coverage of the partially loaded shards by collection, reported by the querycoord
+// along with the shard leaders.
+var globalShardCoverages = typeutil.NewConcurrentMap[int64, map[string]float64]()
+
+// updateShardCoverage records the coverage of the partially loaded shards in the GetShardLeaders response status,
+// and returns whether any shard is partially loaded.
+func updateShardCoverage(collectionID int64, status *commonpb.Status) bool {
+	coverage := make(map[string]float64)
+	if value := status.GetExtraInfo()[common.ShardCoverageKey]; value != "" {
+		// the coverage is only informative, the leaders are readable anyway
+		_ = json.Unmarshal([]byte(value), &coverage)
+	}
+	if len(coverage) == 0 {
+		globalShardCoverages.Remove(collectionID)
+		return false
+	}
+	globalShardCoverages.Insert(collectionID, coverage)
+	return true
+}
+
+// getSearchCoverage returns the coverage of the search over the shards, nil if all the shards are fully loaded.
+func getSearchCoverage(collectionID int64, numShards int) *searchCoverage {
+	shards, ok := globalShardCoverages.Get(collectionID)
+	if !ok || numShards == 0 {
+		return nil
+	}
+	total := float64(100 * (numShards - len(shards)))
+	for _, percentage := range shards {
+		total += percentage
+	}
+	return &searchCoverage{
+		Percentage: total / float64(numShards),
+		Shards:     shards,
+	}
+}
+
+// setSearchCoverage sets the search coverage in the extra info of the search result status.
+func setSearchCoverage(status *commonpb.Status, coverage *searchCoverage) {
+	bs, err := json.Marshal(coverage)
+	if err != nil {
+		return
+	}
+	if status.ExtraInfo == nil {
+		status.ExtraInfo = make(map[string]string)
+	}
+	status.ExtraInfo[searchCoverageInfoKey] = string(bs)
+}
@@ -0,0 +1,52 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestSearchCoverage(t *testing.T) {
+	collectionID := int64(1000)
+	defer globalShardCoverages.Remove(collectionID)
+
+	assert.False(t, updateShardCoverage(collectionID, merr.Success()))
+	assert.Nil(t, getSearchCoverage(collectionID, 2))
+
+	status := merr.Success()
+	status.ExtraInfo = map[string]string{common.ShardCoverageKey: `{"ch1":50}`}
+	assert.True(t, updateShardCoverage(collectionID, status))
+	coverage := getSearchCoverage(collectionID, 2)
+	assert.Equal(t, float64(75), coverage.Percentage)
+	assert.Equal(t, map[string]float64{"ch1": 50}, coverage.Shards)
+
+	result := merr.Success()
+	setSearchCoverage(result, coverage)
+	decoded := &searchCoverage{}
+	assert.NoError(t, json.Unmarshal([]byte(result.GetExtraInfo()[searchCoverageInfoKey]), decoded))
+	assert.Equal(t, coverage, decoded)
+
+	// fully loaded
+	assert.False(t, updateShardCoverage(collectionID, merr.Success()))
+	assert.Nil(t, getSearchCoverage(collectionID, 2))
+}
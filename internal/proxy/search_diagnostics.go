@@ -0,0 +1,122 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+// consistencyDiagnosticsInfoKey is set in the extra info of the search result status if the consistency diagnostics
+// is requested, the value is the json of the consistencyDiagnostics.
+const consistencyDiagnosticsInfoKey = "consistency_diagnostics"
+
+// shardConsistency is the timestamp a shard is searched at, the shard delegator waits the tsafe to reach the
+// guarantee timestamp if the tsafe is behind.
+type shardConsistency struct {
+	Channel          string `json:"channel"`
+	ServiceTimestamp uint64 `json:"service_timestamp"`
+	ServiceTimeMs    int64  `json:"service_time_ms"`
+	WaitedTSafe      bool   `json:"waited_tsafe"`
+}
+
+// consistencyDiagnostics is the guarantee timestamp of the search and the timestamps its shards are searched at,
+// to debug the search results staler than expected.
+type consistencyDiagnostics struct {
+	ConsistencyLevel   string              `json:"consistency_level"`
+	GuaranteeTimestamp uint64              `json:"guarantee_timestamp"`
+	GuaranteeTimeMs    int64               `json:"guarantee_time_ms,omitempty"`
+	WaitedTSafe        bool                `json:"waited_tsafe"`
+	Shards             []*shardConsistency `json:"shards"`
+}
+
+// parseBoolSearchParam returns the bool value of the key in the search params, false if the key is absent.
+func parseBoolSearchParam(key string, searchParams []*commonpb.KeyValuePair) (bool, error) {
+	value, err := funcutil.GetAttrByKeyFromRepeatedKV(key, searchParams)
+	if err != nil {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, merr.WrapErrParameterInvalidMsg("invalid %s: %s", key, value)
+	}
+	return enabled, nil
+}
+
+// collectConsistencyDiagnostics returns the consistency diagnostics by the mvcc timestamps of the channels and the
+// channels waited the tsafe, reported in the search results of the shards.
+func collectConsistencyDiagnostics(level commonpb.ConsistencyLevel, guaranteeTs uint64, results []*internalpb.SearchResults) *consistencyDiagnostics {
+	diagnostics := &consistencyDiagnostics{
+		ConsistencyLevel:   level.String(),
+		GuaranteeTimestamp: guaranteeTs,
+		Shards:             make([]*shardConsistency, 0, len(results)),
+	}
+	// the small guarantee timestamps of the eventually consistency are not hybrid timestamps
+	if physical, _ := tsoutil.ParseHybridTs(guaranteeTs); physical > 0 {
+		diagnostics.GuaranteeTimeMs = physical
+	}
+	waited := make(map[string]struct{})
+	for _, result := range results {
+		if channels := result.GetStatus().GetExtraInfo()[common.TSafeWaitedChannelsKey]; channels != "" {
+			for _, channel := range strings.Split(channels, ",") {
+				waited[channel] = struct{}{}
+			}
+		}
+	}
+	for _, result := range results {
+		for channel, ts := range result.GetChannelsMvcc() {
+			physical, _ := tsoutil.ParseHybridTs(ts)
+			_, ok := waited[channel]
+			diagnostics.Shards = append(diagnostics.Shards, &shardConsistency{
+				Channel:          channel,
+				ServiceTimestamp: ts,
+				ServiceTimeMs:    physical,
+				WaitedTSafe:      ok,
+			})
+		}
+	}
+	sort.Slice(diagnostics.Shards, func(i, j int) bool {
+		return diagnostics.Shards[i].Channel < diagnostics.Shards[j].Channel
+	})
+	diagnostics.WaitedTSafe = lo.ContainsBy(diagnostics.Shards, func(shard *shardConsistency) bool {
+		return shard.WaitedTSafe
+	})
+	return diagnostics
+}
+
+// setConsistencyDiagnostics sets the consistency diagnostics in the extra info of the search result status.
+func setConsistencyDiagnostics(status *commonpb.Status, diagnostics *consistencyDiagnostics) {
+	bs, err := json.Marshal(diagnostics)
+	if err != nil {
+		return
+	}
+	if status.ExtraInfo == nil {
+		status.ExtraInfo = make(map[string]string)
+	}
+	status.ExtraInfo[consistencyDiagnosticsInfoKey] = string(bs)
+}
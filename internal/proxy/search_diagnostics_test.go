@@ -0,0 +1,82 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+func TestParseBoolSearchParam(t *testing.T) {
+	enabled, err := parseBoolSearchParam(ConsistencyDiagnosticsKey, nil)
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+
+	enabled, err = parseBoolSearchParam(ConsistencyDiagnosticsKey, []*commonpb.KeyValuePair{{Key: ConsistencyDiagnosticsKey, Value: "true"}})
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+
+	_, err = parseBoolSearchParam(ConsistencyDiagnosticsKey, []*commonpb.KeyValuePair{{Key: ConsistencyDiagnosticsKey, Value: "yes please"}})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestConsistencyDiagnostics(t *testing.T) {
+	now := time.Now()
+	guaranteeTs := tsoutil.ComposeTSByTime(now, 0)
+	results := []*internalpb.SearchResults{
+		{
+			Status: &commonpb.Status{ExtraInfo: map[string]string{common.TSafeWaitedChannelsKey: "ch2"}},
+			ChannelsMvcc: map[string]uint64{
+				"ch2": tsoutil.ComposeTSByTime(now.Add(time.Second), 0),
+			},
+		},
+		{
+			Status:       merr.Success(),
+			ChannelsMvcc: map[string]uint64{"ch1": tsoutil.ComposeTSByTime(now.Add(2*time.Second), 0)},
+		},
+	}
+	diagnostics := collectConsistencyDiagnostics(commonpb.ConsistencyLevel_Strong, guaranteeTs, results)
+	assert.Equal(t, commonpb.ConsistencyLevel_Strong.String(), diagnostics.ConsistencyLevel)
+	assert.Equal(t, guaranteeTs, diagnostics.GuaranteeTimestamp)
+	assert.Equal(t, now.UnixMilli(), diagnostics.GuaranteeTimeMs)
+	assert.True(t, diagnostics.WaitedTSafe)
+	assert.Len(t, diagnostics.Shards, 2)
+	assert.Equal(t, "ch1", diagnostics.Shards[0].Channel)
+	assert.False(t, diagnostics.Shards[0].WaitedTSafe)
+	assert.Equal(t, now.Add(2*time.Second).UnixMilli(), diagnostics.Shards[0].ServiceTimeMs)
+	assert.True(t, diagnostics.Shards[1].WaitedTSafe)
+
+	status := merr.Success()
+	setConsistencyDiagnostics(status, diagnostics)
+	decoded := &consistencyDiagnostics{}
+	assert.NoError(t, json.Unmarshal([]byte(status.GetExtraInfo()[consistencyDiagnosticsInfoKey]), decoded))
+	assert.Equal(t, diagnostics, decoded)
+
+	// eventually
+	diagnostics = collectConsistencyDiagnostics(commonpb.ConsistencyLevel_Eventually, 1, results[1:])
+	assert.Zero(t, diagnostics.GuaranteeTimeMs)
+	assert.False(t, diagnostics.WaitedTSafe)
+}
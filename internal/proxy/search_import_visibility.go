@@ -0,0 +1,63 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
+)
+
+// getImportingSegments returns the segments of the import jobs of the collection not committed yet. The segments of
+// a job are made visible one by one when the job commits, so the searches see the partially imported data meanwhile.
+func getImportingSegments(ctx context.Context, dc types.DataCoordClient, collectionID int64) ([]int64, error) {
+	req, err := metricsinfo.ConstructRequestByMetricType(metricsinfo.ImportSegmentsMetrics)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := dc.GetMetrics(ctx, req)
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	importSegments := metricsinfo.ImportSegments{}
+	if err := json.Unmarshal([]byte(resp.GetResponse()), &importSegments); err != nil {
+		return nil, err
+	}
+	return importSegments.Collections[collectionID], nil
+}
+
+// setExcludedSegments sets the segments the shard delegators skip in the search in the msg base.
+func setExcludedSegments(base *commonpb.MsgBase, segmentIDs []int64) {
+	if base == nil || len(segmentIDs) == 0 {
+		return
+	}
+	if base.Properties == nil {
+		base.Properties = make(map[string]string)
+	}
+	base.Properties[common.ExcludedSegmentsKey] = strings.Join(lo.Map(segmentIDs, func(segmentID int64, _ int) string {
+		return strconv.FormatInt(segmentID, 10)
+	}), ",")
+}
@@ -0,0 +1,68 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
+)
+
+func TestGetImportingSegments(t *testing.T) {
+	ctx := context.Background()
+	bs, err := json.Marshal(metricsinfo.ImportSegments{
+		Collections: map[int64][]int64{100: {1001, 1002}},
+	})
+	assert.NoError(t, err)
+
+	dc := mocks.NewMockDataCoordClient(t)
+	dc.EXPECT().GetMetrics(mock.Anything, mock.Anything).Return(&milvuspb.GetMetricsResponse{
+		Status:   merr.Success(),
+		Response: string(bs),
+	}, nil).Twice()
+	segmentIDs, err := getImportingSegments(ctx, dc, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1001, 1002}, segmentIDs)
+	segmentIDs, err = getImportingSegments(ctx, dc, 200)
+	assert.NoError(t, err)
+	assert.Empty(t, segmentIDs)
+
+	dc = mocks.NewMockDataCoordClient(t)
+	dc.EXPECT().GetMetrics(mock.Anything, mock.Anything).Return(nil, errors.New("mock"))
+	_, err = getImportingSegments(ctx, dc, 100)
+	assert.Error(t, err)
+}
+
+func TestSetExcludedSegments(t *testing.T) {
+	base := &commonpb.MsgBase{}
+	setExcludedSegments(base, nil)
+	assert.Empty(t, base.GetProperties())
+
+	setExcludedSegments(base, []int64{1001, 1002})
+	assert.Equal(t, "1001,1002", base.GetProperties()[common.ExcludedSegmentsKey])
+}
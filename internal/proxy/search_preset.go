@@ -0,0 +1,170 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/indexparamcheck"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// searchPreset is the named search parameters stored in the collection properties,
+// the search request references it by the search_preset search param.
+type searchPreset struct {
+	Params           map[string]any `json:"params,omitempty"`
+	MetricType       string         `json:"metric_type,omitempty"`
+	ConsistencyLevel string         `json:"consistency_level,omitempty"`
+}
+
+func parseSearchPreset(name, value string) (*searchPreset, error) {
+	if name == "" {
+		return nil, merr.WrapErrParameterInvalidMsg("search preset name should not be empty")
+	}
+	preset := &searchPreset{}
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(preset); err != nil {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid search preset %s: %s", name, err.Error())
+	}
+	if preset.ConsistencyLevel != "" {
+		if _, ok := commonpb.ConsistencyLevel_value[preset.ConsistencyLevel]; !ok {
+			return nil, merr.WrapErrParameterInvalidMsg("invalid consistency level %s of search preset %s", preset.ConsistencyLevel, name)
+		}
+	}
+	return preset, nil
+}
+
+// validateSearchPresets checks the search presets in the collection properties. The preset is not bound to an anns
+// field, so its params are checked against all the index types here, and against the index of the anns field once
+// applied to the search.
+func validateSearchPresets(props []*commonpb.KeyValuePair) error {
+	for _, p := range props {
+		if name, ok := strings.CutPrefix(p.GetKey(), common.CollectionSearchPresetKeyPrefix); ok {
+			preset, err := parseSearchPreset(name, p.GetValue())
+			if err != nil {
+				return err
+			}
+			if err := indexparamcheck.ValidateSearchParams("", preset.Params); err != nil {
+				return merr.WrapErrParameterInvalidMsg("invalid search preset %s: %s", name, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// merge returns the search params with the preset applied, the ones specified in the request take precedence.
+func (p *searchPreset) merge(searchParams []*commonpb.KeyValuePair) ([]*commonpb.KeyValuePair, error) {
+	ret := make([]*commonpb.KeyValuePair, 0, len(searchParams)+2)
+	hasParams, hasMetricType := false, false
+	for _, kv := range searchParams {
+		switch kv.GetKey() {
+		case SearchParamsKey:
+			hasParams = true
+			params := make(map[string]any, len(p.Params))
+			for k, v := range p.Params {
+				params[k] = v
+			}
+			if kv.GetValue() != "" {
+				decoder := json.NewDecoder(bytes.NewReader([]byte(kv.GetValue())))
+				decoder.UseNumber()
+				if err := decoder.Decode(&params); err != nil {
+					return nil, merr.WrapErrParameterInvalidMsg("invalid search params %s", kv.GetValue())
+				}
+			}
+			value, err := json.Marshal(params)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, &commonpb.KeyValuePair{Key: SearchParamsKey, Value: string(value)})
+			continue
+		case MetricTypeKey:
+			hasMetricType = true
+		}
+		ret = append(ret, kv)
+	}
+	if !hasParams && len(p.Params) > 0 {
+		value, err := json.Marshal(p.Params)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &commonpb.KeyValuePair{Key: SearchParamsKey, Value: string(value)})
+	}
+	if !hasMetricType && p.MetricType != "" {
+		ret = append(ret, &commonpb.KeyValuePair{Key: MetricTypeKey, Value: p.MetricType})
+	}
+	return ret, nil
+}
+
+// applySearchPreset replaces the search_preset search param with the preset stored in the collection properties.
+// The consistency level of the preset is only used if the request uses the default one.
+func applySearchPreset(ctx context.Context, request *milvuspb.SearchRequest) error {
+	name, found := "", false
+	searchParams := make([]*commonpb.KeyValuePair, 0, len(request.GetSearchParams()))
+	for _, kv := range request.GetSearchParams() {
+		if kv.GetKey() == SearchPresetKey {
+			name, found = kv.GetValue(), true
+			continue
+		}
+		searchParams = append(searchParams, kv)
+	}
+	if !found {
+		return nil
+	}
+
+	schema, err := globalMetaCache.GetCollectionSchema(ctx, request.GetDbName(), request.GetCollectionName())
+	if err != nil {
+		return err
+	}
+	var preset *searchPreset
+	for _, p := range schema.GetProperties() {
+		if p.GetKey() == common.CollectionSearchPresetKeyPrefix+name {
+			preset, err = parseSearchPreset(name, p.GetValue())
+			if err != nil {
+				return err
+			}
+			break
+		}
+	}
+	if preset == nil {
+		return merr.WrapErrParameterInvalidMsg("search preset %s not found in collection %s", name, request.GetCollectionName())
+	}
+
+	// the search params of the advanced search are the rank params, the preset applies to the sub requests
+	request.SearchParams = searchParams
+	if len(request.GetSubReqs()) == 0 {
+		if request.SearchParams, err = preset.merge(searchParams); err != nil {
+			return err
+		}
+	}
+	for _, subReq := range request.GetSubReqs() {
+		if subReq.SearchParams, err = preset.merge(subReq.GetSearchParams()); err != nil {
+			return err
+		}
+	}
+	if preset.ConsistencyLevel != "" && request.GetUseDefaultConsistency() {
+		request.ConsistencyLevel = commonpb.ConsistencyLevel(commonpb.ConsistencyLevel_value[preset.ConsistencyLevel])
+		request.UseDefaultConsistency = false
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestValidateSearchPresets(t *testing.T) {
+	presetKey := common.CollectionSearchPresetKeyPrefix + "fast"
+	assert.NoError(t, validateSearchPresets([]*commonpb.KeyValuePair{
+		{Key: common.CollectionTTLConfigKey, Value: "100"},
+		{Key: presetKey, Value: `{"params": {"ef": 32}, "metric_type": "L2", "consistency_level": "Eventually"}`},
+	}))
+	assert.Error(t, validateSearchPresets([]*commonpb.KeyValuePair{
+		{Key: common.CollectionSearchPresetKeyPrefix, Value: `{}`},
+	}))
+	assert.Error(t, validateSearchPresets([]*commonpb.KeyValuePair{
+		{Key: presetKey, Value: `invalid`},
+	}))
+	assert.Error(t, validateSearchPresets([]*commonpb.KeyValuePair{
+		{Key: presetKey, Value: `{"unknown": 1}`},
+	}))
+	assert.Error(t, validateSearchPresets([]*commonpb.KeyValuePair{
+		{Key: presetKey, Value: `{"consistency_level": "Unknown"}`},
+	}))
+}
+
+func TestSearchPreset_merge(t *testing.T) {
+	preset, err := parseSearchPreset("fast", `{"params": {"ef": 32, "nprobe": 8}, "metric_type": "L2"}`)
+	assert.NoError(t, err)
+
+	searchParams, err := preset.merge([]*commonpb.KeyValuePair{
+		{Key: TopKKey, Value: "10"},
+		{Key: SearchParamsKey, Value: `{"ef": 64}`},
+		{Key: MetricTypeKey, Value: "IP"},
+	})
+	assert.NoError(t, err)
+	params, _ := funcutil.GetAttrByKeyFromRepeatedKV(SearchParamsKey, searchParams)
+	assert.JSONEq(t, `{"ef": 64, "nprobe": 8}`, params)
+	metricType, _ := funcutil.GetAttrByKeyFromRepeatedKV(MetricTypeKey, searchParams)
+	assert.Equal(t, "IP", metricType)
+	topK, _ := funcutil.GetAttrByKeyFromRepeatedKV(TopKKey, searchParams)
+	assert.Equal(t, "10", topK)
+
+	searchParams, err = preset.merge([]*commonpb.KeyValuePair{{Key: TopKKey, Value: "10"}})
+	assert.NoError(t, err)
+	params, _ = funcutil.GetAttrByKeyFromRepeatedKV(SearchParamsKey, searchParams)
+	assert.JSONEq(t, `{"ef": 32, "nprobe": 8}`, params)
+	metricType, _ = funcutil.GetAttrByKeyFromRepeatedKV(MetricTypeKey, searchParams)
+	assert.Equal(t, "L2", metricType)
+
+	_, err = preset.merge([]*commonpb.KeyValuePair{{Key: SearchParamsKey, Value: "invalid"}})
+	assert.Error(t, err)
+}
+
+func TestApplySearchPreset(t *testing.T) {
+	ctx := context.Background()
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetCollectionSchema(mock.Anything, mock.Anything, "c1").Return(newSchemaInfo(&schemapb.CollectionSchema{
+		Name: "c1",
+		Properties: []*commonpb.KeyValuePair{
+			{Key: common.CollectionSearchPresetKeyPrefix + "fast", Value: `{"params": {"ef": 32}, "consistency_level": "Eventually"}`},
+		},
+	}), nil).Maybe()
+	globalMetaCache = mockCache
+	defer func() { globalMetaCache = nil }()
+
+	t.Run("no preset", func(t *testing.T) {
+		request := &milvuspb.SearchRequest{
+			CollectionName: "c1",
+			SearchParams:   []*commonpb.KeyValuePair{{Key: TopKKey, Value: "10"}},
+		}
+		assert.NoError(t, applySearchPreset(ctx, request))
+		assert.Len(t, request.GetSearchParams(), 1)
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		request := &milvuspb.SearchRequest{
+			CollectionName:        "c1",
+			UseDefaultConsistency: true,
+			SearchParams: []*commonpb.KeyValuePair{
+				{Key: TopKKey, Value: "10"},
+				{Key: SearchPresetKey, Value: "fast"},
+			},
+		}
+		assert.NoError(t, applySearchPreset(ctx, request))
+		_, err := funcutil.GetAttrByKeyFromRepeatedKV(SearchPresetKey, request.GetSearchParams())
+		assert.Error(t, err)
+		params, _ := funcutil.GetAttrByKeyFromRepeatedKV(SearchParamsKey, request.GetSearchParams())
+		assert.JSONEq(t, `{"ef": 32}`, params)
+		assert.False(t, request.GetUseDefaultConsistency())
+		assert.Equal(t, commonpb.ConsistencyLevel_Eventually, request.GetConsistencyLevel())
+
+		// applying again changes nothing, as the search may be retried
+		assert.NoError(t, applySearchPreset(ctx, request))
+		assert.Len(t, request.GetSearchParams(), 2)
+	})
+
+	t.Run("request consistency level", func(t *testing.T) {
+		request := &milvuspb.SearchRequest{
+			CollectionName:   "c1",
+			ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+			SearchParams:     []*commonpb.KeyValuePair{{Key: SearchPresetKey, Value: "fast"}},
+		}
+		assert.NoError(t, applySearchPreset(ctx, request))
+		assert.Equal(t, commonpb.ConsistencyLevel_Strong, request.GetConsistencyLevel())
+	})
+
+	t.Run("sub requests", func(t *testing.T) {
+		request := &milvuspb.SearchRequest{
+			CollectionName: "c1",
+			SearchParams:   []*commonpb.KeyValuePair{{Key: SearchPresetKey, Value: "fast"}},
+			SubReqs: []*milvuspb.SubSearchRequest{
+				{SearchParams: []*commonpb.KeyValuePair{{Key: SearchParamsKey, Value: `{"nprobe": 8}`}}},
+			},
+		}
+		assert.NoError(t, applySearchPreset(ctx, request))
+		assert.Empty(t, request.GetSearchParams())
+		params, _ := funcutil.GetAttrByKeyFromRepeatedKV(SearchParamsKey, request.GetSubReqs()[0].GetSearchParams())
+		assert.JSONEq(t, `{"ef": 32, "nprobe": 8}`, params)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		request := &milvuspb.SearchRequest{
+			CollectionName: "c1",
+			SearchParams:   []*commonpb.KeyValuePair{{Key: SearchPresetKey, Value: "slow"}},
+		}
+		assert.Error(t, applySearchPreset(ctx, request))
+	})
+}
+
+func TestValidateSearchPresets_InvalidParams(t *testing.T) {
+	err := validateSearchPresets([]*commonpb.KeyValuePair{{Key: common.CollectionSearchPresetKeyPrefix + "fast", Value: `{"params": {"nprobe": 0}}`}})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	assert.ErrorContains(t, err, "nprobe should be an integer")
+
+	assert.NoError(t, validateSearchPresets([]*commonpb.KeyValuePair{{Key: common.CollectionSearchPresetKeyPrefix + "fast", Value: `{"params": {"ef": 64}}`}}))
+}
@@ -146,6 +146,7 @@ func reduceSearchResultDataWithGroupBy(ctx context.Context, subSearchResultData
 
 	var retSize int64
 	maxOutputSize := paramtable.Get().QuotaConfig.MaxOutputSize.GetAsInt64()
+	dedupByPK := isResultDedupEnabled(ctx)
 
 	// reducing nq * topk results
 	for i := int64(0); i < nq; i++ {
@@ -184,8 +185,12 @@ func reduceSearchResultDataWithGroupBy(ctx context.Context, subSearchResultData
 				if !groupByValExist {
 					groupByValSet[groupByVal] = struct{}{}
 					if int64(len(groupByValSet)) <= offset {
-						continue
 						// skip offset groups
+						if dedupByPK {
+							idSet[id] = struct{}{}
+						}
+						cursors[subSearchIdx]++
+						continue
 					}
 					retSize += typeutil.AppendFieldData(ret.Results.FieldsData, subSearchResultData[subSearchIdx].FieldsData, resultDataIdx)
 					typeutil.AppendPKs(ret.Results.Ids, id)
@@ -310,6 +315,7 @@ func reduceSearchResultDataNoGroupBy(ctx context.Context, subSearchResultData []
 
 	var retSize int64
 	maxOutputSize := paramtable.Get().QuotaConfig.MaxOutputSize.GetAsInt64()
+	dedupByPK := isResultDedupEnabled(ctx)
 
 	// reducing nq * topk results
 	for i := int64(0); i < nq; i++ {
@@ -322,14 +328,23 @@ func reduceSearchResultDataNoGroupBy(ctx context.Context, subSearchResultData []
 			idSet = make(map[interface{}]struct{})
 		)
 
-		// skip offset results
-		for k := int64(0); k < offset; k++ {
-			subSearchIdx, _ := selectHighestScoreIndex(subSearchResultData, subSearchNqOffset, cursors, i)
+		// skip offset results, the duplicates are not counted in the offset if deduplicated by pk
+		for k := int64(0); k < offset; {
+			subSearchIdx, resultDataIdx := selectHighestScoreIndex(subSearchResultData, subSearchNqOffset, cursors, i)
 			if subSearchIdx == -1 {
 				break
 			}
 
 			cursors[subSearchIdx]++
+			if dedupByPK {
+				id := typeutil.GetPK(subSearchResultData[subSearchIdx].GetIds(), resultDataIdx)
+				if _, ok := idSet[id]; ok {
+					skipDupCnt++
+					continue
+				}
+				idSet[id] = struct{}{}
+			}
+			k++
 		}
 
 		// keep limit results
@@ -0,0 +1,452 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+const (
+	weightedFieldsRerankType = "weighted_fields"
+	decayRerankType          = "decay"
+	externalRerankType       = "external"
+
+	// maxExternalScorerResponseSize limits the size of the response of external scorer.
+	maxExternalScorerResponseSize = 16 << 20
+)
+
+// rerankFunction computes the new score of each hit in the reduced search results,
+// the hits of each query are reordered by the new score in descending order.
+type rerankFunction interface {
+	// inputFields returns the fields required to compute the score.
+	inputFields() []string
+	score(ctx context.Context, data *schemapb.SearchResultData) ([]float32, error)
+}
+
+// rerankSpec is the json value of the `rerank` search param.
+type rerankSpec struct {
+	Type   string          `json:"type"`
+	Params json.RawMessage `json:"params"`
+}
+
+func decodeRerankParams(raw json.RawMessage, params any) error {
+	if len(raw) == 0 {
+		raw = json.RawMessage("{}")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(params); err != nil {
+		return merr.WrapErrParameterInvalidMsg("invalid rerank params, %s", err.Error())
+	}
+	return nil
+}
+
+// parseRerankFunction parses the rerank function declared by the `rerank` search param, nil if not declared.
+func parseRerankFunction(value string, schema *schemaInfo) (rerankFunction, error) {
+	if value == "" {
+		return nil, nil
+	}
+	spec := &rerankSpec{}
+	if err := json.Unmarshal([]byte(value), spec); err != nil {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid %s, %s", RerankKey, err.Error())
+	}
+
+	var (
+		fn        rerankFunction
+		isNumeric = true
+	)
+	switch spec.Type {
+	case weightedFieldsRerankType:
+		f := &weightedFieldsRerank{ScoreWeight: 1}
+		if err := decodeRerankParams(spec.Params, f); err != nil {
+			return nil, err
+		}
+		if len(f.Fields) == 0 {
+			return nil, merr.WrapErrParameterMissing("fields", "weighted_fields rerank requires at least one field")
+		}
+		fn = f
+	case decayRerankType:
+		f := &decayRerank{Function: "gauss", Decay: 0.5}
+		if err := decodeRerankParams(spec.Params, f); err != nil {
+			return nil, err
+		}
+		if err := f.validate(); err != nil {
+			return nil, err
+		}
+		fn = f
+	case externalRerankType:
+		if !Params.ProxyCfg.RerankExternalEnabled.GetAsBool() {
+			return nil, merr.WrapErrParameterInvalidMsg("external rerank is disabled, set proxy.rerank.external.enabled to enable it")
+		}
+		f := &externalRerank{}
+		if err := decodeRerankParams(spec.Params, f); err != nil {
+			return nil, err
+		}
+		url, err := getExternalScorerURL(f.Scorer)
+		if err != nil {
+			return nil, err
+		}
+		f.url = url
+		if f.TimeoutMs < 0 {
+			return nil, merr.WrapErrParameterInvalidMsg("invalid external scorer timeout %d", f.TimeoutMs)
+		}
+		fn, isNumeric = f, false
+	default:
+		return nil, merr.WrapErrParameterInvalidMsg("unknown rerank type %s", spec.Type)
+	}
+
+	for _, name := range fn.inputFields() {
+		field, err := schema.schemaHelper.GetFieldFromName(name)
+		if err != nil {
+			return nil, err
+		}
+		if isNumeric && !typeutil.IsArithmetic(field.GetDataType()) {
+			return nil, merr.WrapErrParameterInvalidMsg("rerank field %s should be numeric, got %s", name, field.GetDataType())
+		}
+		if !typeutil.IsArithmetic(field.GetDataType()) && !typeutil.IsBoolType(field.GetDataType()) && !typeutil.IsStringType(field.GetDataType()) {
+			return nil, merr.WrapErrParameterInvalidMsg("rerank field %s of type %s is not supported", name, field.GetDataType())
+		}
+	}
+	return fn, nil
+}
+
+// getRerankFieldValues returns the values of the field as float64.
+func getRerankFieldValues(data *schemapb.SearchResultData, name string) ([]float64, error) {
+	for _, fieldData := range data.GetFieldsData() {
+		if fieldData.GetFieldName() != name {
+			continue
+		}
+		values := make([]float64, len(data.GetScores()))
+		for i := range values {
+			switch v := typeutil.GetData(fieldData, i).(type) {
+			case int32:
+				values[i] = float64(v)
+			case int64:
+				values[i] = float64(v)
+			case float32:
+				values[i] = float64(v)
+			case float64:
+				values[i] = v
+			default:
+				return nil, merr.WrapErrParameterInvalidMsg("rerank field %s should be numeric", name)
+			}
+		}
+		return values, nil
+	}
+	return nil, merr.WrapErrFieldNotFound(name, "rerank field not found in search results")
+}
+
+// weightedFieldsRerank scores the hit by the weighted sum of the original score and the field values.
+type weightedFieldsRerank struct {
+	ScoreWeight float64            `json:"score_weight"`
+	Fields      map[string]float64 `json:"fields"`
+}
+
+func (r *weightedFieldsRerank) inputFields() []string {
+	return lo.Keys(r.Fields)
+}
+
+func (r *weightedFieldsRerank) score(ctx context.Context, data *schemapb.SearchResultData) ([]float32, error) {
+	scores := make([]float64, len(data.GetScores()))
+	for i, s := range data.GetScores() {
+		scores[i] = r.ScoreWeight * float64(s)
+	}
+	for name, weight := range r.Fields {
+		values, err := getRerankFieldValues(data, name)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			scores[i] += weight * v
+		}
+	}
+	return toFloat32s(scores), nil
+}
+
+// decayRerank multiplies the original score by the decay of the field value distance to origin,
+// the decay functions follow the ones of elasticsearch function score query.
+type decayRerank struct {
+	Field    string   `json:"field"`
+	Function string   `json:"function"`
+	Origin   *float64 `json:"origin"`
+	Scale    float64  `json:"scale"`
+	Offset   float64  `json:"offset"`
+	Decay    float64  `json:"decay"`
+}
+
+func (r *decayRerank) validate() error {
+	if r.Field == "" {
+		return merr.WrapErrParameterMissing("field", "decay rerank requires the field")
+	}
+	if r.Function != "gauss" && r.Function != "exp" && r.Function != "linear" {
+		return merr.WrapErrParameterInvalidMsg("unknown decay function %s", r.Function)
+	}
+	if r.Scale <= 0 {
+		return merr.WrapErrParameterInvalidMsg("decay scale should be positive, got %v", r.Scale)
+	}
+	if r.Offset < 0 {
+		return merr.WrapErrParameterInvalidMsg("decay offset should not be negative, got %v", r.Offset)
+	}
+	if r.Decay <= 0 || r.Decay >= 1 {
+		return merr.WrapErrParameterInvalidMsg("decay should be in (0, 1), got %v", r.Decay)
+	}
+	return nil
+}
+
+func (r *decayRerank) inputFields() []string {
+	return []string{r.Field}
+}
+
+// decayOf returns the decay of the distance, which is 1 within offset and decay at offset + scale.
+func (r *decayRerank) decayOf(distance float64) float64 {
+	d := math.Max(0, math.Abs(distance)-r.Offset)
+	switch r.Function {
+	case "exp":
+		return math.Pow(r.Decay, d/r.Scale)
+	case "linear":
+		s := r.Scale / (1 - r.Decay)
+		return math.Max(0, (s-d)/s)
+	default:
+		sigmaSquare := -r.Scale * r.Scale / (2 * math.Log(r.Decay))
+		return math.Exp(-d * d / (2 * sigmaSquare))
+	}
+}
+
+func (r *decayRerank) score(ctx context.Context, data *schemapb.SearchResultData) ([]float32, error) {
+	values, err := getRerankFieldValues(data, r.Field)
+	if err != nil {
+		return nil, err
+	}
+	// origin defaults to now in unix seconds, for the field of timestamp
+	origin := float64(time.Now().Unix())
+	if r.Origin != nil {
+		origin = *r.Origin
+	}
+	scores := make([]float64, len(values))
+	for i, v := range values {
+		scores[i] = float64(data.GetScores()[i]) * r.decayOf(v-origin)
+	}
+	return toFloat32s(scores), nil
+}
+
+// externalRerank calls the external http scorer to score the hits, the scorer is referred by name,
+// only the scorers in proxy.rerank.external.scorers are allowed, so the request can't send the hits anywhere else.
+type externalRerank struct {
+	Scorer    string   `json:"scorer"`
+	TimeoutMs int64    `json:"timeout_ms"`
+	Fields    []string `json:"fields"`
+
+	url string
+}
+
+// externalScorerClient doesn't follow the redirects, which may lead to the endpoints not allowed.
+var externalScorerClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// getExternalScorerURL returns the url of the scorer configured in proxy.rerank.external.scorers.
+func getExternalScorerURL(scorer string) (string, error) {
+	if scorer == "" {
+		return "", merr.WrapErrParameterMissing("scorer", "external rerank requires the scorer")
+	}
+	for _, pair := range strings.Split(Params.ProxyCfg.RerankExternalScorers.GetValue(), ",") {
+		name, url, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || name != scorer {
+			continue
+		}
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return "", merr.WrapErrServiceInternal(fmt.Sprintf("invalid url of external scorer %s", scorer))
+		}
+		return url, nil
+	}
+	return "", merr.WrapErrParameterInvalidMsg("external scorer %s is not configured", scorer)
+}
+
+// externalRerankRequest is posted to the external scorer, ids, scores and field values are flattened
+// in the order of the queries, the hits of the i-th query are the topks[i] ones after those of the former queries.
+type externalRerankRequest struct {
+	Topks  []int64          `json:"topks"`
+	IDs    []any            `json:"ids"`
+	Scores []float32        `json:"scores"`
+	Fields map[string][]any `json:"fields,omitempty"`
+}
+
+type externalRerankResponse struct {
+	Scores []float32 `json:"scores"`
+}
+
+func (r *externalRerank) inputFields() []string {
+	return r.Fields
+}
+
+func (r *externalRerank) timeout() time.Duration {
+	timeout := Params.ProxyCfg.RerankExternalTimeout.GetAsDuration(time.Millisecond)
+	if r.TimeoutMs > 0 && time.Duration(r.TimeoutMs)*time.Millisecond < timeout {
+		timeout = time.Duration(r.TimeoutMs) * time.Millisecond
+	}
+	return timeout
+}
+
+func (r *externalRerank) score(ctx context.Context, data *schemapb.SearchResultData) ([]float32, error) {
+	nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	start := time.Now()
+	scores, err := r.call(ctx, data)
+	metrics.ProxyRerankExternalLatency.WithLabelValues(nodeID).Observe(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		metrics.ProxyRerankExternalCallCount.WithLabelValues(nodeID, metrics.FailLabel).Inc()
+		return nil, err
+	}
+	metrics.ProxyRerankExternalCallCount.WithLabelValues(nodeID, metrics.SuccessLabel).Inc()
+	return scores, nil
+}
+
+func (r *externalRerank) call(ctx context.Context, data *schemapb.SearchResultData) ([]float32, error) {
+	request := &externalRerankRequest{
+		Topks:  data.GetTopks(),
+		IDs:    make([]any, 0, len(data.GetScores())),
+		Scores: data.GetScores(),
+		Fields: make(map[string][]any),
+	}
+	for i := range data.GetScores() {
+		request.IDs = append(request.IDs, typeutil.GetPK(data.GetIds(), int64(i)))
+	}
+	for _, name := range r.Fields {
+		for _, fieldData := range data.GetFieldsData() {
+			if fieldData.GetFieldName() != name {
+				continue
+			}
+			values := make([]any, len(data.GetScores()))
+			for i := range values {
+				values[i] = typeutil.GetData(fieldData, i)
+			}
+			request.Fields[name] = values
+		}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, merr.WrapErrServiceInternal(fmt.Sprintf("invalid url of external scorer %s, %s", r.Scorer, err.Error()))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := externalScorerClient.Do(req)
+	if err != nil {
+		return nil, merr.WrapErrServiceUnavailable(err.Error(), fmt.Sprintf("failed to call external scorer %s", r.Scorer))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, merr.WrapErrServiceUnavailable(resp.Status, fmt.Sprintf("failed to call external scorer %s", r.Scorer))
+	}
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxExternalScorerResponseSize))
+	if err != nil {
+		return nil, merr.WrapErrServiceUnavailable(err.Error(), fmt.Sprintf("failed to call external scorer %s", r.Scorer))
+	}
+	response := &externalRerankResponse{}
+	if err := json.Unmarshal(respBody, response); err != nil {
+		return nil, merr.WrapErrServiceInternal(fmt.Sprintf("invalid response of external scorer %s, %s", r.Scorer, err.Error()))
+	}
+	return response.Scores, nil
+}
+
+// applyRerank reorders the hits of each query by the scores of the rerank function in descending order.
+func applyRerank(ctx context.Context, fn rerankFunction, data *schemapb.SearchResultData) error {
+	scores, err := fn.score(ctx, data)
+	if err != nil {
+		return err
+	}
+	if len(scores) != len(data.GetScores()) {
+		return merr.WrapErrServiceInternal(fmt.Sprintf("rerank returns %d scores for %d hits", len(scores), len(data.GetScores())))
+	}
+
+	ids := &schemapb.IDs{}
+	reordered := make([]float32, 0, len(scores))
+	var distances []float32
+	if len(data.GetDistances()) == len(scores) {
+		distances = make([]float32, 0, len(scores))
+	}
+	fieldsData := typeutil.PrepareResultFieldData(data.GetFieldsData(), int64(len(scores)))
+	var groupByValue []*schemapb.FieldData
+	if data.GetGroupByFieldValue() != nil {
+		groupByValue = typeutil.PrepareResultFieldData([]*schemapb.FieldData{data.GetGroupByFieldValue()}, int64(len(scores)))
+	}
+	offset := 0
+	for _, topk := range data.GetTopks() {
+		indexes := make([]int, topk)
+		for i := range indexes {
+			indexes[i] = offset + i
+		}
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return scores[indexes[i]] > scores[indexes[j]]
+		})
+		for _, idx := range indexes {
+			typeutil.AppendPKs(ids, typeutil.GetPK(data.GetIds(), int64(idx)))
+			reordered = append(reordered, scores[idx])
+			if distances != nil {
+				distances = append(distances, data.GetDistances()[idx])
+			}
+			typeutil.AppendFieldData(fieldsData, data.GetFieldsData(), int64(idx))
+			if groupByValue != nil {
+				typeutil.AppendFieldData(groupByValue, []*schemapb.FieldData{data.GetGroupByFieldValue()}, int64(idx))
+			}
+		}
+		offset += int(topk)
+	}
+
+	data.Ids = ids
+	data.Scores = reordered
+	if distances != nil {
+		data.Distances = distances
+	}
+	data.FieldsData = fieldsData
+	if groupByValue != nil {
+		data.GroupByFieldValue = groupByValue[0]
+	}
+	return nil
+}
+
+func toFloat32s(values []float64) []float32 {
+	result := make([]float32, len(values))
+	for i, v := range values {
+		result[i] = float32(v)
+	}
+	return result
+}
@@ -0,0 +1,214 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/metric"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func newRerankTestSchema() *schemaInfo {
+	return newSchemaInfo(&schemapb.CollectionSchema{
+		Name: "test",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "price", DataType: schemapb.DataType_Float},
+			{FieldID: 102, Name: "ts", DataType: schemapb.DataType_Int64},
+			{FieldID: 103, Name: "title", DataType: schemapb.DataType_VarChar},
+			{FieldID: 104, Name: "meta", DataType: schemapb.DataType_JSON},
+		},
+	})
+}
+
+// newRerankTestResult returns the results of 2 queries with 3 hits each.
+func newRerankTestResult() *schemapb.SearchResultData {
+	return &schemapb.SearchResultData{
+		NumQueries: 2,
+		TopK:       3,
+		Topks:      []int64{3, 3},
+		Scores:     []float32{0.9, 0.8, 0.7, 0.6, 0.5, 0.4},
+		Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2, 3, 4, 5, 6}}}},
+		FieldsData: []*schemapb.FieldData{
+			{
+				FieldName: "price",
+				Type:      schemapb.DataType_Float,
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{Data: &schemapb.ScalarField_FloatData{
+					FloatData: &schemapb.FloatArray{Data: []float32{0, 0, 1, 1, 0, 0}},
+				}}},
+			},
+			{
+				FieldName: "ts",
+				Type:      schemapb.DataType_Int64,
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{Data: &schemapb.ScalarField_LongData{
+					LongData: &schemapb.LongArray{Data: []int64{100, 50, 100, 0, 100, 100}},
+				}}},
+			},
+		},
+	}
+}
+
+func TestParseRerankFunction(t *testing.T) {
+	paramtable.Init()
+	schema := newRerankTestSchema()
+
+	fn, err := parseRerankFunction("", schema)
+	assert.NoError(t, err)
+	assert.Nil(t, fn)
+
+	fn, err = parseRerankFunction(`{"type": "weighted_fields", "params": {"fields": {"price": -0.5}}}`, schema)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"price"}, fn.inputFields())
+	assert.Equal(t, float64(1), fn.(*weightedFieldsRerank).ScoreWeight)
+
+	fn, err = parseRerankFunction(`{"type": "decay", "params": {"field": "ts", "scale": 10}}`, schema)
+	assert.NoError(t, err)
+	assert.Equal(t, "gauss", fn.(*decayRerank).Function)
+
+	for _, value := range []string{
+		`invalid`,
+		`{"type": "unknown"}`,
+		`{"type": "weighted_fields", "params": {}}`,
+		`{"type": "weighted_fields", "params": {"fields": {"title": 1}}}`,
+		`{"type": "weighted_fields", "params": {"fields": {"not_exist": 1}}}`,
+		`{"type": "weighted_fields", "params": {"fields": {"price": 1}, "unknown": 1}}`,
+		`{"type": "decay", "params": {"field": "ts"}}`,
+		`{"type": "decay", "params": {"field": "ts", "scale": 10, "decay": 1}}`,
+		`{"type": "decay", "params": {"field": "ts", "scale": 10, "function": "unknown"}}`,
+		`{"type": "decay", "params": {"field": "ts", "scale": 10, "offset": -1}}`,
+		`{"type": "external", "params": {"scorer": "s1"}}`,
+	} {
+		_, err = parseRerankFunction(value, schema)
+		assert.Error(t, err, value)
+	}
+
+	paramtable.Get().Save(Params.ProxyCfg.RerankExternalEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.RerankExternalEnabled.Key)
+	paramtable.Get().Save(Params.ProxyCfg.RerankExternalScorers.Key, "s1:http://localhost:8080/score,s2:localhost")
+	defer paramtable.Get().Reset(Params.ProxyCfg.RerankExternalScorers.Key)
+	fn, err = parseRerankFunction(`{"type": "external", "params": {"scorer": "s1", "fields": ["title"]}}`, schema)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"title"}, fn.inputFields())
+	assert.Equal(t, "http://localhost:8080/score", fn.(*externalRerank).url)
+	for _, value := range []string{
+		`{"type": "external", "params": {}}`,
+		`{"type": "external", "params": {"scorer": "s2"}}`,
+		`{"type": "external", "params": {"scorer": "s3"}}`,
+		`{"type": "external", "params": {"url": "http://localhost"}}`,
+		`{"type": "external", "params": {"scorer": "s1", "fields": ["meta"]}}`,
+	} {
+		_, err = parseRerankFunction(value, schema)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestApplyRerank_weightedFields(t *testing.T) {
+	data := newRerankTestResult()
+	fillCosineDistances(data, metric.COSINE)
+	fn := &weightedFieldsRerank{ScoreWeight: 1, Fields: map[string]float64{"price": 0.5}}
+	assert.NoError(t, applyRerank(context.Background(), fn, data))
+
+	assert.Equal(t, []int64{3, 1, 2, 4, 5, 6}, data.GetIds().GetIntId().GetData())
+	assert.InDeltaSlice(t, []float32{1.2, 0.9, 0.8, 1.1, 0.5, 0.4}, data.GetScores(), 1e-6)
+	// the distances follow the reordered hits
+	assert.InDeltaSlice(t, []float32{0.3, 0.1, 0.2, 0.4, 0.5, 0.6}, data.GetDistances(), 1e-6)
+	assert.Equal(t, []float32{1, 0, 0, 1, 0, 0}, data.GetFieldsData()[0].GetScalars().GetFloatData().GetData())
+	assert.Equal(t, []int64{100, 100, 50, 0, 100, 100}, data.GetFieldsData()[1].GetScalars().GetLongData().GetData())
+
+	_, err := (&weightedFieldsRerank{Fields: map[string]float64{"not_exist": 1}}).score(context.Background(), data)
+	assert.Error(t, err)
+}
+
+func TestApplyRerank_decay(t *testing.T) {
+	origin := float64(100)
+	for _, function := range []string{"gauss", "exp", "linear"} {
+		r := &decayRerank{Field: "ts", Function: function, Origin: &origin, Scale: 50, Offset: 10, Decay: 0.5}
+		assert.NoError(t, r.validate())
+		assert.Equal(t, float64(1), r.decayOf(10), function)
+		assert.InDelta(t, 0.5, r.decayOf(-60), 1e-9, function)
+
+		data := newRerankTestResult()
+		assert.NoError(t, applyRerank(context.Background(), r, data))
+		// the hits far from origin are moved backward
+		assert.Equal(t, []int64{1, 3, 2, 5, 6, 4}, data.GetIds().GetIntId().GetData(), function)
+	}
+}
+
+func TestApplyRerank_external(t *testing.T) {
+	paramtable.Init()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &externalRerankRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		assert.Equal(t, []int64{3, 3}, request.Topks)
+		assert.Len(t, request.Fields["price"], 6)
+		// reverse the order
+		scores := make([]float32, len(request.Scores))
+		for i, s := range request.Scores {
+			scores[i] = -s
+		}
+		json.NewEncoder(w).Encode(&externalRerankResponse{Scores: scores})
+	}))
+	defer server.Close()
+
+	data := newRerankTestResult()
+	fn := &externalRerank{url: server.URL, Fields: []string{"price"}}
+	assert.NoError(t, applyRerank(context.Background(), fn, data))
+	assert.Equal(t, []int64{3, 2, 1, 6, 5, 4}, data.GetIds().GetIntId().GetData())
+
+	t.Run("mismatched scores", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(&externalRerankResponse{Scores: []float32{1}})
+		}))
+		defer server.Close()
+		assert.Error(t, applyRerank(context.Background(), &externalRerank{url: server.URL}, newRerankTestResult()))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(time.Second)
+		}))
+		defer server.Close()
+		fn := &externalRerank{url: server.URL, TimeoutMs: 10}
+		assert.Equal(t, 10*time.Millisecond, fn.timeout())
+		assert.Error(t, applyRerank(context.Background(), fn, newRerankTestResult()))
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "http://localhost:1/score", http.StatusFound)
+		}))
+		defer server.Close()
+		assert.Error(t, applyRerank(context.Background(), &externalRerank{url: server.URL}, newRerankTestResult()))
+	})
+
+	t.Run("bad status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		assert.Error(t, applyRerank(context.Background(), &externalRerank{url: server.URL}, newRerankTestResult()))
+	})
+}
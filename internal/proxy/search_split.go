@@ -0,0 +1,169 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// searchSplitKey marks the context of the sub searches, the value is the timestamp all the sub searches of a split
+// search are pinned to, so they see the same snapshot of the collection.
+type searchSplitKey struct{}
+
+// splitSearchRequest splits the search of the nq or the vectors over the limits into the sub searches of the
+// consecutive vectors, so the messages to the shards are kept under the limits. It returns nil if not split.
+func splitSearchRequest(request *milvuspb.SearchRequest) []*milvuspb.SearchRequest {
+	if !Params.ProxyCfg.SearchSplitEnabled.GetAsBool() {
+		return nil
+	}
+	maxNQ := Params.QuotaConfig.NQLimit.GetAsInt()
+	if splitNQ := Params.ProxyCfg.SearchSplitMaxNQ.GetAsInt(); splitNQ > 0 && splitNQ < maxNQ {
+		maxNQ = splitNQ
+	}
+	maxBytes := Params.ProxyCfg.SearchSplitMaxBytes.GetAsInt()
+	if request.GetNq() <= int64(maxNQ) && len(request.GetPlaceholderGroup()) <= maxBytes {
+		return nil
+	}
+	placeholder := batchSearchPlaceholder(request)
+	if placeholder == nil {
+		return nil
+	}
+
+	chunks := make([][][]byte, 0)
+	chunk, chunkBytes := make([][]byte, 0), 0
+	for _, value := range placeholder.GetValues() {
+		if len(chunk) > 0 && (len(chunk) >= maxNQ || chunkBytes+len(value) > maxBytes) {
+			chunks = append(chunks, chunk)
+			chunk, chunkBytes = make([][]byte, 0), 0
+		}
+		chunk = append(chunk, value)
+		chunkBytes += len(value)
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	if len(chunks) <= 1 {
+		return nil
+	}
+
+	requests := make([]*milvuspb.SearchRequest, 0, len(chunks))
+	for _, chunk := range chunks {
+		placeholderGroup, err := proto.Marshal(&commonpb.PlaceholderGroup{Placeholders: []*commonpb.PlaceholderValue{{
+			Tag:    placeholder.GetTag(),
+			Type:   placeholder.GetType(),
+			Values: chunk,
+		}}})
+		if err != nil {
+			return nil
+		}
+		req := proto.Clone(request).(*milvuspb.SearchRequest)
+		req.PlaceholderGroup = placeholderGroup
+		req.Nq = int64(len(chunk))
+		requests = append(requests, req)
+	}
+	return requests
+}
+
+// mergeSplitSearchResults concatenates the results of the sub searches in the order of their vectors.
+func mergeSplitSearchResults(results []*milvuspb.SearchResults) *milvuspb.SearchResults {
+	// the fields data of the sub searches of no result may be empty
+	sample, _ := lo.Find(results, func(rsp *milvuspb.SearchResults) bool {
+		return len(rsp.GetResults().GetFieldsData()) > 0
+	})
+	total := lo.SumBy(results, func(rsp *milvuspb.SearchResults) int {
+		return typeutil.GetSizeOfIDs(rsp.GetResults().GetIds())
+	})
+	data := &schemapb.SearchResultData{
+		FieldsData: typeutil.PrepareResultFieldData(sample.GetResults().GetFieldsData(), int64(total)),
+		Ids:        &schemapb.IDs{},
+		Topks:      make([]int64, 0),
+		Scores:     make([]float32, 0, total),
+	}
+	for _, rsp := range results {
+		sub := rsp.GetResults()
+		data.NumQueries += sub.GetNumQueries()
+		data.TopK = lo.Max([]int64{data.GetTopK(), sub.GetTopK()})
+		data.AllSearchCount += sub.GetAllSearchCount()
+		data.Topks = append(data.Topks, sub.GetTopks()...)
+		data.Scores = append(data.Scores, sub.GetScores()...)
+		if len(sub.GetOutputFields()) > 0 {
+			data.OutputFields = sub.GetOutputFields()
+		}
+		for idx := 0; idx < typeutil.GetSizeOfIDs(sub.GetIds()); idx++ {
+			typeutil.AppendIDs(data.Ids, sub.GetIds(), idx)
+			if len(sub.GetFieldsData()) > 0 {
+				typeutil.AppendFieldData(data.FieldsData, sub.GetFieldsData(), int64(idx))
+			}
+		}
+	}
+	return &milvuspb.SearchResults{
+		Status:         merr.Success(),
+		Results:        data,
+		CollectionName: results[0].GetCollectionName(),
+	}
+}
+
+// searchSplit executes the sub searches with the bounded parallelism and merges their results. The sub searches are
+// served at the timestamp allocated once for all, which guarantees the data up to it like the strong consistency.
+// The search fails if any of the sub searches fails.
+func (node *Proxy) searchSplit(ctx context.Context, request *milvuspb.SearchRequest, requests []*milvuspb.SearchRequest) (*milvuspb.SearchResults, error) {
+	ts, err := node.tsoAllocator.AllocOne(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to allocate the timestamp of the split search", zap.Error(err))
+		return &milvuspb.SearchResults{Status: merr.Status(err)}, nil
+	}
+	nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	metrics.ProxySearchSplitCount.WithLabelValues(nodeID).Inc()
+	metrics.ProxySearchSplitSubRequestCount.WithLabelValues(nodeID).Add(float64(len(requests)))
+	log.Ctx(ctx).Info("split the search into sub searches", zap.String("collection", request.GetCollectionName()),
+		zap.Int64("nq", request.GetNq()), zap.Int("subSearches", len(requests)), zap.Uint64("ts", ts))
+
+	results := make([]*milvuspb.SearchResults, len(requests))
+	group, gctx := errgroup.WithContext(ctx)
+	group.SetLimit(Params.ProxyCfg.SearchSplitConcurrency.GetAsInt())
+	for i, req := range requests {
+		i, req := i, req
+		group.Go(func() error {
+			rsp, err := node.Search(context.WithValue(gctx, searchSplitKey{}, ts), req)
+			if err := merr.CheckRPCCall(rsp, err); err != nil {
+				return err
+			}
+			results[i] = rsp
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		log.Ctx(ctx).Warn("failed to execute the split search", zap.Error(err))
+		return &milvuspb.SearchResults{Status: merr.Status(err)}, nil
+	}
+	return limitSearchResultSize(ctx, mergeSplitSearchResults(results), request.GetSearchParams()), nil
+}
@@ -0,0 +1,116 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestSplitSearchRequest(t *testing.T) {
+	paramtable.Init()
+	vectors := [][]byte{[]byte("v0"), []byte("v1"), []byte("v2"), []byte("v3"), []byte("v4")}
+	request := newBatchSearchRequest(t, "a > 1", vectors...)
+	paramtable.Get().Save(Params.ProxyCfg.SearchSplitMaxNQ.Key, "2")
+	defer paramtable.Get().Reset(Params.ProxyCfg.SearchSplitMaxNQ.Key)
+	// disabled by default
+	assert.Nil(t, splitSearchRequest(request))
+	paramtable.Get().Save(Params.ProxyCfg.SearchSplitEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.SearchSplitEnabled.Key)
+	paramtable.Get().Reset(Params.ProxyCfg.SearchSplitMaxNQ.Key)
+	assert.Nil(t, splitSearchRequest(request))
+
+	// split by the nq
+	paramtable.Get().Save(Params.ProxyCfg.SearchSplitMaxNQ.Key, "2")
+	requests := splitSearchRequest(request)
+	assert.Equal(t, 3, len(requests))
+	assert.Equal(t, []int64{2, 2, 1}, []int64{requests[0].GetNq(), requests[1].GetNq(), requests[2].GetNq()})
+	placeholders := &commonpb.PlaceholderGroup{}
+	assert.NoError(t, proto.Unmarshal(requests[1].GetPlaceholderGroup(), placeholders))
+	assert.Equal(t, [][]byte{[]byte("v2"), []byte("v3")}, placeholders.GetPlaceholders()[0].GetValues())
+	assert.Equal(t, commonpb.PlaceholderType_FloatVector, placeholders.GetPlaceholders()[0].GetType())
+	assert.Equal(t, "a > 1", requests[2].GetDsl())
+	assert.Equal(t, int64(5), request.GetNq())
+
+	// the grouped search is not split
+	grouped := proto.Clone(request).(*milvuspb.SearchRequest)
+	grouped.SearchParams = append(grouped.SearchParams, &commonpb.KeyValuePair{Key: GroupByFieldKey, Value: "a"})
+	assert.Nil(t, splitSearchRequest(grouped))
+
+	paramtable.Get().Save(Params.ProxyCfg.SearchSplitEnabled.Key, "false")
+	assert.Nil(t, splitSearchRequest(request))
+	paramtable.Get().Save(Params.ProxyCfg.SearchSplitEnabled.Key, "true")
+
+	// split by the bytes
+	paramtable.Get().Save(Params.ProxyCfg.SearchSplitMaxNQ.Key, "0")
+	paramtable.Get().Save(Params.ProxyCfg.SearchSplitMaxBytes.Key, "7")
+	defer paramtable.Get().Reset(Params.ProxyCfg.SearchSplitMaxBytes.Key)
+	requests = splitSearchRequest(request)
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, int64(3), requests[0].GetNq())
+	assert.Equal(t, int64(2), requests[1].GetNq())
+}
+
+func TestMergeSplitSearchResults(t *testing.T) {
+	newResults := func(topks []int64, ids []int64, scores []float32) *milvuspb.SearchResults {
+		fieldsData := []*schemapb.FieldData{}
+		if len(ids) > 0 {
+			fieldsData = append(fieldsData, &schemapb.FieldData{
+				Type:      schemapb.DataType_Int64,
+				FieldName: "a",
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: ids}},
+				}},
+			})
+		}
+		return &milvuspb.SearchResults{
+			Status:         merr.Success(),
+			CollectionName: "c1",
+			Results: &schemapb.SearchResultData{
+				NumQueries:   int64(len(topks)),
+				TopK:         2,
+				Topks:        topks,
+				Scores:       scores,
+				Ids:          &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+				FieldsData:   fieldsData,
+				OutputFields: []string{"a"},
+			},
+		}
+	}
+	rsp := mergeSplitSearchResults([]*milvuspb.SearchResults{
+		newResults([]int64{0}, nil, nil),
+		newResults([]int64{2, 1}, []int64{1, 2, 3}, []float32{0.9, 0.8, 0.7}),
+		newResults([]int64{2}, []int64{4, 5}, []float32{0.6, 0.5}),
+	})
+	assert.True(t, merr.Ok(rsp.GetStatus()))
+	assert.Equal(t, "c1", rsp.GetCollectionName())
+	assert.Equal(t, int64(4), rsp.GetResults().GetNumQueries())
+	assert.Equal(t, int64(2), rsp.GetResults().GetTopK())
+	assert.Equal(t, []int64{0, 2, 1, 2}, rsp.GetResults().GetTopks())
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, rsp.GetResults().GetIds().GetIntId().GetData())
+	assert.Equal(t, []float32{0.9, 0.8, 0.7, 0.6, 0.5}, rsp.GetResults().GetScores())
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, rsp.GetResults().GetFieldsData()[0].GetScalars().GetLongData().GetData())
+	assert.Equal(t, []string{"a"}, rsp.GetResults().GetOutputFields())
+}
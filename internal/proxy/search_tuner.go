@@ -0,0 +1,324 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+const (
+	defaultTuneTopK   = 10
+	defaultTuneNList  = 128
+	maxTuneSearchList = 2048
+)
+
+// SearchTuneRequest asks to find the cheapest search param of the index reaching the target recall,
+// the recall is measured against the ground truth primary keys of the sampled queries.
+type SearchTuneRequest struct {
+	DbName         string              `json:"db_name,omitempty"`
+	CollectionName string              `json:"collection_name"`
+	AnnsField      string              `json:"anns_field"`
+	TopK           int                 `json:"topk,omitempty"`
+	TargetRecall   float64             `json:"target_recall"`
+	Queries        [][]float32         `json:"queries"`
+	GroundTruth    [][]json.RawMessage `json:"ground_truth"`
+	// Candidates overrides the values of the search param to sweep, in ascending cost order.
+	Candidates []int `json:"candidates,omitempty"`
+	// PresetName is the search preset to record the recommended param if the target recall is reached.
+	PresetName string `json:"preset_name,omitempty"`
+}
+
+// SearchTuneTrial is the recall and latency of a search param value.
+type SearchTuneTrial struct {
+	Value     int     `json:"value"`
+	Recall    float64 `json:"recall"`
+	LatencyMs int64   `json:"latency_ms"`
+}
+
+// SearchTuneReport is the result of tuneSearchParams.
+type SearchTuneReport struct {
+	IndexType     string             `json:"index_type"`
+	Param         string             `json:"param"`
+	Trials        []*SearchTuneTrial `json:"trials"`
+	TargetReached bool               `json:"target_reached"`
+	Recommended   map[string]any     `json:"recommended,omitempty"`
+	Preset        string             `json:"preset,omitempty"`
+}
+
+func (r *SearchTuneRequest) validate() error {
+	if r.CollectionName == "" {
+		return merr.WrapErrParameterMissing("collection_name")
+	}
+	if r.AnnsField == "" {
+		return merr.WrapErrParameterMissing("anns_field")
+	}
+	if r.TopK == 0 {
+		r.TopK = defaultTuneTopK
+	}
+	if r.TopK < 0 {
+		return merr.WrapErrParameterInvalidMsg("invalid topk %d", r.TopK)
+	}
+	if r.TargetRecall <= 0 || r.TargetRecall > 1 {
+		return merr.WrapErrParameterInvalidMsg("target recall should be in (0, 1], got %v", r.TargetRecall)
+	}
+	if len(r.Queries) == 0 {
+		return merr.WrapErrParameterMissing("queries")
+	}
+	if len(r.GroundTruth) != len(r.Queries) {
+		return merr.WrapErrParameterInvalidMsg("the ground truth of %d queries is expected, got %d", len(r.Queries), len(r.GroundTruth))
+	}
+	for _, query := range r.Queries {
+		if len(query) == 0 || len(query) != len(r.Queries[0]) {
+			return merr.WrapErrParameterInvalidMsg("the queries should be the float vectors of the same dim")
+		}
+	}
+	return nil
+}
+
+// searchTuneCandidates returns the search param of the index type and the values to sweep in ascending cost order.
+func searchTuneCandidates(indexType string, indexParams map[string]string, topK int) (string, []int, error) {
+	switch {
+	case strings.HasPrefix(indexType, "IVF"), strings.HasPrefix(indexType, "GPU_IVF"), indexType == "SCANN":
+		nlist, err := strconv.Atoi(indexParams["nlist"])
+		if err != nil || nlist <= 0 {
+			nlist = defaultTuneNList
+		}
+		candidates := make([]int, 0)
+		for nprobe := 1; nprobe < nlist; nprobe *= 2 {
+			candidates = append(candidates, nprobe)
+		}
+		return "nprobe", append(candidates, nlist), nil
+	case indexType == "HNSW", indexType == "DISKANN":
+		param := "ef"
+		if indexType == "DISKANN" {
+			param = "search_list"
+		}
+		candidates := make([]int, 0)
+		for value := topK; value < maxTuneSearchList; value *= 2 {
+			candidates = append(candidates, value)
+		}
+		if topK > maxTuneSearchList {
+			return param, append(candidates, topK), nil
+		}
+		return param, append(candidates, maxTuneSearchList), nil
+	default:
+		return "", nil, merr.WrapErrParameterInvalidMsg("index type %s has no search param to tune", indexType)
+	}
+}
+
+// getIndexParams returns the index type and the flattened index params of the field.
+func (node *Proxy) getIndexParams(ctx context.Context, dbName, collectionName, fieldName string) (string, map[string]string, error) {
+	req := &milvuspb.DescribeIndexRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldName:      fieldName,
+	}
+	if err := checkMgrPrivilege(ctx, req); err != nil {
+		return "", nil, err
+	}
+	resp, err := node.DescribeIndex(ctx, req)
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return "", nil, err
+	}
+	for _, index := range resp.GetIndexDescriptions() {
+		if index.GetFieldName() != fieldName {
+			continue
+		}
+		params := make(map[string]string)
+		for _, kv := range index.GetParams() {
+			if kv.GetKey() != common.IndexParamsKey {
+				params[kv.GetKey()] = kv.GetValue()
+				continue
+			}
+			nested := make(map[string]any)
+			if err := json.Unmarshal([]byte(kv.GetValue()), &nested); err != nil {
+				return "", nil, err
+			}
+			for k, v := range nested {
+				params[k] = fmt.Sprint(v)
+			}
+		}
+		return params[common.IndexTypeKey], params, nil
+	}
+	return "", nil, merr.WrapErrIndexNotFoundForCollection(collectionName)
+}
+
+// primaryKeyString returns the primary key in the ground truth as string, both the number and string are accepted.
+func primaryKeyString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// computeRecall returns the average recall@topK of the search results against the ground truth.
+func computeRecall(results *schemapb.SearchResultData, groundTruth [][]json.RawMessage, topK int) float64 {
+	ids := make([]string, 0)
+	switch data := results.GetIds().GetIdField().(type) {
+	case *schemapb.IDs_IntId:
+		for _, id := range data.IntId.GetData() {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+	case *schemapb.IDs_StrId:
+		ids = append(ids, data.StrId.GetData()...)
+	}
+
+	total := 0.0
+	offset := int64(0)
+	for i, truth := range groundTruth {
+		if len(truth) > topK {
+			truth = truth[:topK]
+		}
+		if len(truth) == 0 {
+			total += 1
+			continue
+		}
+		expected := make(map[string]struct{}, len(truth))
+		for _, pk := range truth {
+			expected[primaryKeyString(pk)] = struct{}{}
+		}
+		hits := 0
+		if i < len(results.GetTopks()) && offset+results.GetTopks()[i] <= int64(len(ids)) {
+			k := results.GetTopks()[i]
+			for j, id := range ids[offset : offset+k] {
+				if _, ok := expected[id]; ok && j < topK {
+					hits++
+				}
+			}
+			offset += k
+		}
+		total += float64(hits) / float64(len(expected))
+	}
+	return total / float64(len(groundTruth))
+}
+
+// tuneSearchParams sweeps the search param of the index on the live collection, the first value reaching the
+// target recall is recommended and recorded as the search preset if requested.
+func (node *Proxy) tuneSearchParams(ctx context.Context, r *SearchTuneRequest) (*SearchTuneReport, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	log := log.Ctx(ctx).With(zap.String("collection", r.CollectionName), zap.String("field", r.AnnsField))
+
+	indexType, indexParams, err := node.getIndexParams(ctx, r.DbName, r.CollectionName, r.AnnsField)
+	if err != nil {
+		return nil, err
+	}
+	param, candidates, err := searchTuneCandidates(indexType, indexParams, r.TopK)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Candidates) > 0 {
+		candidates = r.Candidates
+	}
+
+	vectors := make([]float32, 0, len(r.Queries)*len(r.Queries[0]))
+	for _, query := range r.Queries {
+		vectors = append(vectors, query...)
+	}
+	placeholderGroup, err := funcutil.FieldDataToPlaceholderGroupBytes(&schemapb.FieldData{
+		Type: schemapb.DataType_FloatVector,
+		Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+			Dim:  int64(len(r.Queries[0])),
+			Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: vectors}},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SearchTuneReport{IndexType: indexType, Param: param}
+	var best *SearchTuneTrial
+	for _, value := range candidates {
+		searchParams, _ := json.Marshal(map[string]int{param: value})
+		searchReq := &milvuspb.SearchRequest{
+			DbName:           r.DbName,
+			CollectionName:   r.CollectionName,
+			PlaceholderGroup: placeholderGroup,
+			DslType:          commonpb.DslType_BoolExprV1,
+			Nq:               int64(len(r.Queries)),
+			SearchParams: []*commonpb.KeyValuePair{
+				{Key: AnnsFieldKey, Value: r.AnnsField},
+				{Key: TopKKey, Value: strconv.Itoa(r.TopK)},
+				{Key: SearchParamsKey, Value: string(searchParams)},
+			},
+			UseDefaultConsistency: true,
+		}
+		if err := checkMgrPrivilege(ctx, searchReq); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		resp, err := node.Search(ctx, searchReq)
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return nil, err
+		}
+		trial := &SearchTuneTrial{
+			Value:     value,
+			Recall:    computeRecall(resp.GetResults(), r.GroundTruth, r.TopK),
+			LatencyMs: time.Since(start).Milliseconds(),
+		}
+		report.Trials = append(report.Trials, trial)
+		log.Info("search param trial done", zap.String("param", param), zap.Int("value", value), zap.Float64("recall", trial.Recall))
+		if best == nil || trial.Recall > best.Recall {
+			best = trial
+		}
+		if trial.Recall >= r.TargetRecall {
+			report.TargetReached = true
+			best = trial
+			break
+		}
+	}
+	if best == nil {
+		return report, nil
+	}
+	report.Recommended = map[string]any{param: best.Value}
+
+	if report.TargetReached && r.PresetName != "" {
+		preset, _ := json.Marshal(&searchPreset{Params: report.Recommended})
+		alterReq := &milvuspb.AlterCollectionRequest{
+			DbName:         r.DbName,
+			CollectionName: r.CollectionName,
+			Properties: []*commonpb.KeyValuePair{
+				{Key: common.CollectionSearchPresetKeyPrefix + r.PresetName, Value: string(preset)},
+			},
+		}
+		if err := checkMgrPrivilege(ctx, alterReq); err != nil {
+			return nil, err
+		}
+		if err := merr.CheckRPCCall(node.AlterCollection(ctx, alterReq)); err != nil {
+			return nil, err
+		}
+		report.Preset = r.PresetName
+	}
+	return report, nil
+}
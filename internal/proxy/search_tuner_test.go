@@ -0,0 +1,126 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestSearchTuneRequest_validate(t *testing.T) {
+	newRequest := func() *SearchTuneRequest {
+		return &SearchTuneRequest{
+			CollectionName: "test",
+			AnnsField:      "vector",
+			TargetRecall:   0.95,
+			Queries:        [][]float32{{1, 2}, {3, 4}},
+			GroundTruth:    [][]json.RawMessage{{json.RawMessage(`1`)}, {json.RawMessage(`2`)}},
+		}
+	}
+
+	r := newRequest()
+	assert.NoError(t, r.validate())
+	assert.Equal(t, defaultTuneTopK, r.TopK)
+
+	for name, mutate := range map[string]func(r *SearchTuneRequest){
+		"no collection":         func(r *SearchTuneRequest) { r.CollectionName = "" },
+		"no anns field":         func(r *SearchTuneRequest) { r.AnnsField = "" },
+		"negative topk":         func(r *SearchTuneRequest) { r.TopK = -1 },
+		"zero recall":           func(r *SearchTuneRequest) { r.TargetRecall = 0 },
+		"recall over 1":         func(r *SearchTuneRequest) { r.TargetRecall = 1.1 },
+		"no queries":            func(r *SearchTuneRequest) { r.Queries, r.GroundTruth = nil, nil },
+		"ground truth mismatch": func(r *SearchTuneRequest) { r.GroundTruth = r.GroundTruth[:1] },
+		"dim mismatch":          func(r *SearchTuneRequest) { r.Queries[1] = []float32{1} },
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := newRequest()
+			mutate(r)
+			assert.Error(t, r.validate())
+		})
+	}
+}
+
+func TestSearchTuneCandidates(t *testing.T) {
+	param, candidates, err := searchTuneCandidates("IVF_FLAT", map[string]string{"nlist": "100"}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "nprobe", param)
+	assert.Equal(t, []int{1, 2, 4, 8, 16, 32, 64, 100}, candidates)
+
+	_, candidates, err = searchTuneCandidates("SCANN", map[string]string{}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultTuneNList, candidates[len(candidates)-1])
+
+	param, candidates, err = searchTuneCandidates("HNSW", nil, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, "ef", param)
+	assert.Equal(t, []int{100, 200, 400, 800, 1600, 2048}, candidates)
+
+	param, _, err = searchTuneCandidates("DISKANN", nil, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "search_list", param)
+
+	_, _, err = searchTuneCandidates("FLAT", nil, 10)
+	assert.Error(t, err)
+}
+
+func TestComputeRecall(t *testing.T) {
+	groundTruth := [][]json.RawMessage{
+		{json.RawMessage(`1`), json.RawMessage(`2`)},
+		{json.RawMessage(`3`), json.RawMessage(`4`)},
+	}
+	results := &schemapb.SearchResultData{
+		Topks: []int64{2, 2},
+		Ids: &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{
+			2, 1, 3, 5,
+		}}}},
+	}
+	assert.Equal(t, 0.75, computeRecall(results, groundTruth, 2))
+
+	// only the first topk of the results and ground truth are counted
+	assert.Equal(t, 0.5, computeRecall(results, groundTruth, 1))
+
+	strGroundTruth := [][]json.RawMessage{{json.RawMessage(`"a"`), json.RawMessage(`"b"`)}}
+	strResults := &schemapb.SearchResultData{
+		Topks: []int64{1},
+		Ids:   &schemapb.IDs{IdField: &schemapb.IDs_StrId{StrId: &schemapb.StringArray{Data: []string{"b"}}}},
+	}
+	assert.Equal(t, 0.5, computeRecall(strResults, strGroundTruth, 2))
+
+	// empty results
+	assert.Equal(t, float64(0), computeRecall(&schemapb.SearchResultData{}, groundTruth, 2))
+}
+
+func TestProxy_TuneSearchParams(t *testing.T) {
+	node := &Proxy{}
+
+	req, _ := http.NewRequest(http.MethodPost, mgrTuneSearchParams, strings.NewReader("invalid"))
+	recorder := httptest.NewRecorder()
+	node.TuneSearchParams(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, mgrTuneSearchParams, strings.NewReader(`{"collection_name": "test"}`))
+	recorder = httptest.NewRecorder()
+	node.TuneSearchParams(recorder, req)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -16,7 +17,9 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/planpb"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/indexparamcheck"
 	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metric"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
@@ -26,8 +29,9 @@ type rankParams struct {
 	roundDecimal int64
 }
 
-// parseSearchInfo returns QueryInfo and offset
-func parseSearchInfo(searchParamsPair []*commonpb.KeyValuePair, schema *schemapb.CollectionSchema, ignoreOffset bool) (*planpb.QueryInfo, int64, error) {
+// parseSearchInfo returns QueryInfo and offset, the search params are checked against the index type of the anns field,
+// or all the index types if unknown.
+func parseSearchInfo(searchParamsPair []*commonpb.KeyValuePair, schema *schemapb.CollectionSchema, ignoreOffset bool, indexType indexparamcheck.IndexType) (*planpb.QueryInfo, int64, error) {
 	// 1. parse offset and real topk
 	topKStr, err := funcutil.GetAttrByKeyFromRepeatedKV(TopKKey, searchParamsPair)
 	if err != nil {
@@ -89,6 +93,11 @@ func parseSearchInfo(searchParamsPair []*commonpb.KeyValuePair, schema *schemapb
 	if err != nil {
 		searchParamStr = ""
 	}
+	if searchParamStr != "" {
+		if err := validateSearchParams(searchParamStr, indexType); err != nil {
+			return nil, 0, err
+		}
+	}
 
 	// 5. parse group by field
 	groupByFieldName, err := funcutil.GetAttrByKeyFromRepeatedKV(GroupByFieldKey, searchParamsPair)
@@ -129,6 +138,19 @@ func parseSearchInfo(searchParamsPair []*commonpb.KeyValuePair, schema *schemapb
 	}, offset, nil
 }
 
+// validateSearchParams checks the types and ranges of the search params of the index type before they reach the segcore,
+// the search params of all the index types are checked if the index type is unknown, e.g. no index built yet.
+func validateSearchParams(searchParamStr string, indexType indexparamcheck.IndexType) error {
+	params := make(map[string]any)
+	if err := json.Unmarshal([]byte(searchParamStr), &params); err != nil {
+		return merr.WrapErrParameterInvalidMsg("invalid search params %s: %s", searchParamStr, err.Error())
+	}
+	if err := indexparamcheck.ValidateSearchParams(indexType, params); err != nil {
+		return merr.WrapErrParameterInvalidMsg(err.Error())
+	}
+	return nil
+}
+
 func getOutputFieldIDs(schema *schemaInfo, outputFields []string) (outputFieldIDs []UniqueID, err error) {
 	outputFieldIDs = make([]UniqueID, 0, len(outputFields))
 	for _, name := range outputFields {
@@ -304,3 +326,31 @@ func convertHybridSearchToSearch(req *milvuspb.HybridSearchRequest) *milvuspb.Se
 	}
 	return ret
 }
+
+// validateSearchMetricType checks the metric type of search against the anns field type,
+// and returns the metric type in upper case. Empty metric type is left to the index one.
+func validateSearchMetricType(annsField *schemapb.FieldSchema, metricType string) (string, error) {
+	if metricType == "" || annsField == nil {
+		return metricType, nil
+	}
+	metricType = strings.ToUpper(metricType)
+	if err := validateMetricType(annsField.GetDataType(), metricType); err != nil {
+		return "", merr.WrapErrParameterInvalidMsg(err.Error())
+	}
+	if typeutil.IsSparseFloatVectorType(annsField.GetDataType()) && metricType != metric.IP {
+		return "", merr.WrapErrParameterInvalidMsg("only IP is the supported metric type for sparse float vector field %s", annsField.GetName())
+	}
+	return metricType, nil
+}
+
+// fillCosineDistances annotates the results of COSINE search with the cosine distances, i.e. 1 - cosine similarity,
+// the scores are left as the similarity where larger is closer.
+func fillCosineDistances(data *schemapb.SearchResultData, metricType string) {
+	if !strings.EqualFold(metricType, metric.COSINE) {
+		return
+	}
+	data.Distances = make([]float32, len(data.GetScores()))
+	for i, score := range data.GetScores() {
+		data.Distances[i] = 1 - score
+	}
+}
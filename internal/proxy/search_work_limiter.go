@@ -0,0 +1,171 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/ratelimitutil"
+)
+
+const (
+	// rowCountTTL is the expiration of the cached row count of collection.
+	rowCountTTL = 30 * time.Second
+	// rowCountFetchTimeout is the timeout of fetching the row count of collection.
+	rowCountFetchTimeout = 10 * time.Second
+)
+
+type rowCountFetcher func(ctx context.Context, collectionID int64) (int64, error)
+
+type rowCountEntry struct {
+	rowCount   int64
+	updateTime time.Time
+	refreshing bool
+}
+
+// searchWorkLimiter limits the estimated work of search, which is nq × number of rows in the collection,
+// so that a few giant searches can't hide inside a low QPS budget.
+// The row counts are cached and refreshed in background, the search is never blocked by fetching them.
+type searchWorkLimiter struct {
+	mu        sync.Mutex
+	limiters  map[int64]*ratelimitutil.Limiter
+	rowCounts map[int64]*rowCountEntry
+	fetcher   rowCountFetcher
+}
+
+var globalSearchWorkLimiter = newSearchWorkLimiter(nil)
+
+func newSearchWorkLimiter(fetcher rowCountFetcher) *searchWorkLimiter {
+	return &searchWorkLimiter{
+		limiters:  make(map[int64]*ratelimitutil.Limiter),
+		rowCounts: make(map[int64]*rowCountEntry),
+		fetcher:   fetcher,
+	}
+}
+
+// newDataCoordRowCountFetcher returns the fetcher which gets the row count of collection from datacoord.
+func newDataCoordRowCountFetcher(dataCoord types.DataCoordClient) rowCountFetcher {
+	return func(ctx context.Context, collectionID int64) (int64, error) {
+		resp, err := dataCoord.GetCollectionStatistics(ctx, &datapb.GetCollectionStatisticsRequest{
+			Base: commonpbutil.NewMsgBase(
+				commonpbutil.WithMsgType(commonpb.MsgType_GetCollectionStatistics),
+				commonpbutil.WithSourceID(paramtable.GetNodeID()),
+			),
+			CollectionID: collectionID,
+		})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return 0, err
+		}
+		for _, kv := range resp.GetStats() {
+			if kv.GetKey() == "row_count" {
+				return strconv.ParseInt(kv.GetValue(), 10, 64)
+			}
+		}
+		return 0, nil
+	}
+}
+
+// getRowCount returns the cached row count of collection, and false if it is not fetched yet.
+// The expired or missing row count is refreshed in background, the expired one is returned meanwhile.
+func (l *searchWorkLimiter) getRowCount(collectionID int64) (int64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, ok := l.rowCounts[collectionID]
+	if !ok {
+		entry = &rowCountEntry{}
+		l.rowCounts[collectionID] = entry
+	}
+	if time.Since(entry.updateTime) >= rowCountTTL && !entry.refreshing {
+		entry.refreshing = true
+		go l.refreshRowCount(collectionID)
+	}
+	return entry.rowCount, !entry.updateTime.IsZero()
+}
+
+func (l *searchWorkLimiter) refreshRowCount(collectionID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), rowCountFetchTimeout)
+	defer cancel()
+	rowCount, err := l.fetcher(ctx, collectionID)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry := l.rowCounts[collectionID]
+	entry.refreshing = false
+	if err != nil {
+		// estimation is best effort, never reject requests because of it
+		log.RatedWarn(10, "failed to get row count for search work estimation",
+			zap.Int64("collectionID", collectionID), zap.Error(err))
+		return
+	}
+	entry.rowCount = rowCount
+	entry.updateTime = time.Now()
+}
+
+func (l *searchWorkLimiter) getLimiter(collectionID int64, limit ratelimitutil.Limit) *ratelimitutil.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limiter, ok := l.limiters[collectionID]
+	if !ok {
+		limiter = ratelimitutil.NewLimiter(limit, float64(limit))
+		l.limiters[collectionID] = limiter
+	} else if limiter.Limit() != limit {
+		limiter.SetLimit(limit)
+	}
+	return limiter
+}
+
+// Check returns ErrServiceRateLimit if the estimated work of search with nq exceeds the limit of any collection.
+// The work of a single search is clamped to the burst, so a search larger than the limit is allowed with the full
+// budget, and occupies the budget for at most one second instead of being rejected or starving the others for long.
+func (l *searchWorkLimiter) Check(ctx context.Context, collectionIDToPartIDs map[int64][]int64, nq int) error {
+	maxWork := paramtable.Get().QuotaConfig.DQLMaxSearchWorkPerCollection.GetAsFloat()
+	if l.fetcher == nil || maxWork >= math.MaxFloat64 {
+		return nil
+	}
+
+	for collectionID := range collectionIDToPartIDs {
+		rowCount, ok := l.getRowCount(collectionID)
+		if !ok {
+			continue
+		}
+		work := int64(nq) * rowCount
+		cost := float64(work)
+		if cost > maxWork {
+			cost = maxWork
+		}
+		limiter := l.getLimiter(collectionID, ratelimitutil.Limit(maxWork))
+		if !limiter.AllowN(time.Now(), int(cost)) {
+			return merr.WrapErrServiceRateLimit(maxWork,
+				fmt.Sprintf("search work %d (nq %d × rows %d) of collection %d exceeds the limit", work, nq, rowCount, collectionID))
+		}
+	}
+	return nil
+}
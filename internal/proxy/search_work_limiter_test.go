@@ -0,0 +1,100 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestSearchWorkLimiter(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+
+	fetchCount := atomic.NewInt32(0)
+	limiter := newSearchWorkLimiter(func(ctx context.Context, collectionID int64) (int64, error) {
+		fetchCount.Inc()
+		if collectionID == 2 {
+			return 0, errors.New("mock error")
+		}
+		return 1000, nil
+	})
+
+	// no limit by default
+	assert.NoError(t, limiter.Check(ctx, map[int64][]int64{1: {}}, 100))
+	assert.EqualValues(t, 0, fetchCount.Load())
+
+	paramtable.Get().Save(Params.QuotaConfig.DQLLimitEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.QuotaConfig.DQLLimitEnabled.Key)
+	paramtable.Get().Save(Params.QuotaConfig.DQLMaxSearchWorkPerCollection.Key, "10000")
+	defer paramtable.Get().Reset(Params.QuotaConfig.DQLMaxSearchWorkPerCollection.Key)
+
+	// the row count is not fetched yet, allowed
+	assert.NoError(t, limiter.Check(ctx, map[int64][]int64{1: {}}, 100))
+	assert.Eventually(t, func() bool {
+		_, ok := limiter.getRowCount(1)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	// the giant search larger than the limit is allowed and consumes the budget
+	assert.NoError(t, limiter.Check(ctx, map[int64][]int64{1: {}}, 100))
+	assert.NoError(t, limiter.Check(ctx, map[int64][]int64{1: {}}, 1))
+	err := limiter.Check(ctx, map[int64][]int64{1: {}}, 1)
+	assert.ErrorIs(t, err, merr.ErrServiceRateLimit)
+	// row count is cached
+	assert.EqualValues(t, 1, fetchCount.Load())
+	// and the budget is back soon, instead of 10 seconds without clamping
+	assert.Eventually(t, func() bool {
+		return limiter.Check(ctx, map[int64][]int64{1: {}}, 1) == nil
+	}, time.Second, 10*time.Millisecond)
+
+	// failed to estimate, allowed
+	assert.NoError(t, limiter.Check(ctx, map[int64][]int64{2: {}}, 100))
+	assert.Eventually(t, func() bool {
+		return fetchCount.Load() == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, limiter.Check(ctx, map[int64][]int64{2: {}}, 100))
+}
+
+func TestDataCoordRowCountFetcher(t *testing.T) {
+	dataCoord := mocks.NewMockDataCoordClient(t)
+	fetcher := newDataCoordRowCountFetcher(dataCoord)
+
+	dataCoord.EXPECT().GetCollectionStatistics(mock.Anything, mock.Anything).Return(&datapb.GetCollectionStatisticsResponse{
+		Status: merr.Success(),
+		Stats:  []*commonpb.KeyValuePair{{Key: "row_count", Value: "100"}},
+	}, nil).Once()
+	rowCount, err := fetcher(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 100, rowCount)
+
+	dataCoord.EXPECT().GetCollectionStatistics(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+	_, err = fetcher(context.Background(), 1)
+	assert.Error(t, err)
+}
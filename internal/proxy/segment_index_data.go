@@ -0,0 +1,176 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/federpb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// SegmentIndexData is the data of an index on a segment, marshaled as the index data of the segment in the
+// DescribeSegmentIndexData response. The segment is searched by brute force on the field if the index is not finished,
+// or finished without the index files, for the flat index or the segments too small to be indexed.
+type SegmentIndexData struct {
+	SegmentState        string   `json:"segment_state"`
+	NumRows             int64    `json:"num_rows"`
+	IndexID             int64    `json:"index_id"`
+	IndexName           string   `json:"index_name"`
+	FieldID             int64    `json:"field_id"`
+	IndexType           string   `json:"index_type"`
+	State               string   `json:"state"`
+	FailReason          string   `json:"fail_reason,omitempty"`
+	BuildID             int64    `json:"build_id,omitempty"`
+	IndexVersion        int64    `json:"index_version,omitempty"`
+	CurrentIndexVersion int32    `json:"current_index_version,omitempty"`
+	IndexedRows         int64    `json:"indexed_rows,omitempty"`
+	SerializedSize      uint64   `json:"serialized_size"`
+	IndexFiles          []string `json:"index_files,omitempty"`
+	BruteForce          bool     `json:"brute_force"`
+	BruteForceReason    string   `json:"brute_force_reason,omitempty"`
+}
+
+// segmentIndexData returns the data of the index on the segment, by the index state of the segment and the index
+// files if finished.
+func segmentIndexData(segment *datapb.SegmentInfo, index *indexpb.IndexInfo, state *indexpb.SegmentIndexState, files *indexpb.IndexFilePathInfo) *SegmentIndexData {
+	data := &SegmentIndexData{
+		SegmentState: segment.GetState().String(),
+		NumRows:      segment.GetNumOfRows(),
+		IndexID:      index.GetIndexID(),
+		IndexName:    index.GetIndexName(),
+		FieldID:      index.GetFieldID(),
+		IndexType:    funcutil.KeyValuePair2Map(index.GetIndexParams())[common.IndexTypeKey],
+		State:        commonpb.IndexState_IndexStateNone.String(),
+	}
+	if state != nil {
+		data.State = state.GetState().String()
+		data.FailReason = state.GetFailReason()
+	}
+	if files != nil {
+		data.BuildID = files.GetBuildID()
+		data.IndexVersion = files.GetIndexVersion()
+		data.CurrentIndexVersion = files.GetCurrentIndexVersion()
+		data.IndexedRows = files.GetNumRows()
+		data.SerializedSize = files.GetSerializedSize()
+		data.IndexFiles = files.GetIndexFilePaths()
+	}
+
+	switch {
+	case segment.GetState() == commonpb.SegmentState_Growing:
+		data.BruteForce = true
+		data.BruteForceReason = "the growing segment is not indexed"
+	case data.State == commonpb.IndexState_Failed.String():
+		data.BruteForce = true
+		data.BruteForceReason = "the index build failed: " + data.FailReason
+	case data.State != commonpb.IndexState_Finished.String():
+		data.BruteForce = true
+		data.BruteForceReason = fmt.Sprintf("the index is %s", data.State)
+	case len(data.IndexFiles) == 0:
+		data.BruteForce = true
+		data.BruteForceReason = "the index is not built for the flat index or the segment too small to be indexed"
+	}
+	return data
+}
+
+// describeSegmentIndexData returns the data of the index on the segments of the collection, to diagnose why the
+// segments are searched by brute force. The build durations are not recorded in the segment index meta.
+func (node *Proxy) describeSegmentIndexData(ctx context.Context, request *federpb.DescribeSegmentIndexDataRequest) (*federpb.DescribeSegmentIndexDataResponse, error) {
+	if request.GetCollectionName() == "" {
+		return nil, merr.WrapErrParameterMissing("collection_name")
+	}
+	if len(request.GetSegmentsIDs()) == 0 {
+		return nil, merr.WrapErrParameterMissing("segmentsIDs")
+	}
+	collectionID, err := globalMetaCache.GetCollectionID(ctx, GetCurDBNameFromContextOrDefault(ctx), request.GetCollectionName())
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err := node.dataCoord.DescribeIndex(ctx, &indexpb.DescribeIndexRequest{
+		CollectionID: collectionID,
+		IndexName:    request.GetIndexName(),
+	})
+	if err := merr.CheckRPCCall(indexes, err); err != nil {
+		return nil, err
+	}
+	if len(indexes.GetIndexInfos()) != 1 {
+		return nil, merr.WrapErrParameterInvalidMsg("index_name is required for the collection with %d indexes", len(indexes.GetIndexInfos()))
+	}
+	index := indexes.GetIndexInfos()[0]
+
+	segments, err := node.dataCoord.GetSegmentInfo(ctx, &datapb.GetSegmentInfoRequest{
+		SegmentIDs:       request.GetSegmentsIDs(),
+		IncludeUnHealthy: true,
+	})
+	if err := merr.CheckRPCCall(segments, err); err != nil {
+		return nil, err
+	}
+	infos := lo.SliceToMap(segments.GetInfos(), func(segment *datapb.SegmentInfo) (int64, *datapb.SegmentInfo) {
+		return segment.GetID(), segment
+	})
+	for _, segmentID := range request.GetSegmentsIDs() {
+		if infos[segmentID].GetCollectionID() != collectionID {
+			return nil, merr.WrapErrSegmentNotFound(segmentID, "not a segment of collection "+request.GetCollectionName())
+		}
+	}
+
+	indexInfos, err := getSegmentIndexInfos(ctx, node.dataCoord, collectionID, segments.GetInfos())
+	if err != nil {
+		return nil, err
+	}
+	states, err := node.dataCoord.GetSegmentIndexState(ctx, &indexpb.GetSegmentIndexStateRequest{
+		CollectionID: collectionID,
+		IndexName:    index.GetIndexName(),
+		SegmentIDs:   request.GetSegmentsIDs(),
+	})
+	if err := merr.CheckRPCCall(states, err); err != nil {
+		return nil, err
+	}
+	segmentStates := lo.SliceToMap(states.GetStates(), func(state *indexpb.SegmentIndexState) (int64, *indexpb.SegmentIndexState) {
+		return state.GetSegmentID(), state
+	})
+
+	resp := &federpb.DescribeSegmentIndexDataResponse{
+		Status:      merr.Success(),
+		IndexData:   make(map[int64]*federpb.SegmentIndexData, len(request.GetSegmentsIDs())),
+		IndexParams: index.GetIndexParams(),
+	}
+	for _, segmentID := range request.GetSegmentsIDs() {
+		files, _ := lo.Find(indexInfos[segmentID].GetIndexInfos(), func(info *indexpb.IndexFilePathInfo) bool {
+			return info.GetIndexID() == index.GetIndexID()
+		})
+		data, err := json.Marshal(segmentIndexData(infos[segmentID], index, segmentStates[segmentID], files))
+		if err != nil {
+			return nil, err
+		}
+		resp.IndexData[segmentID] = &federpb.SegmentIndexData{
+			SegmentID: segmentID,
+			IndexData: string(data),
+		}
+	}
+	return resp, nil
+}
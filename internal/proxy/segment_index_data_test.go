@@ -0,0 +1,154 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/federpb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestSegmentIndexData(t *testing.T) {
+	flushed := &datapb.SegmentInfo{ID: 1, State: commonpb.SegmentState_Flushed}
+	index := &indexpb.IndexInfo{
+		IndexID:     10,
+		IndexName:   "idx",
+		FieldID:     101,
+		IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: "HNSW"}},
+	}
+	finished := &indexpb.SegmentIndexState{SegmentID: 1, State: commonpb.IndexState_Finished}
+
+	data := segmentIndexData(flushed, index, finished, &indexpb.IndexFilePathInfo{
+		IndexID:        10,
+		BuildID:        1000,
+		IndexVersion:   2,
+		SerializedSize: 4096,
+		IndexFilePaths: []string{"files/index/1000/2/HNSW"},
+	})
+	assert.Equal(t, "HNSW", data.IndexType)
+	assert.Equal(t, commonpb.IndexState_Finished.String(), data.State)
+	assert.Equal(t, int64(1000), data.BuildID)
+	assert.Equal(t, uint64(4096), data.SerializedSize)
+	assert.False(t, data.BruteForce)
+
+	// finished without the index files
+	data = segmentIndexData(flushed, index, finished, nil)
+	assert.True(t, data.BruteForce)
+
+	data = segmentIndexData(flushed, index, &indexpb.SegmentIndexState{SegmentID: 1, State: commonpb.IndexState_Failed, FailReason: "oom"}, nil)
+	assert.True(t, data.BruteForce)
+	assert.Equal(t, "oom", data.FailReason)
+	assert.Contains(t, data.BruteForceReason, "oom")
+
+	data = segmentIndexData(flushed, index, nil, nil)
+	assert.Equal(t, commonpb.IndexState_IndexStateNone.String(), data.State)
+	assert.True(t, data.BruteForce)
+
+	data = segmentIndexData(&datapb.SegmentInfo{ID: 1, State: commonpb.SegmentState_Growing}, index, nil, nil)
+	assert.True(t, data.BruteForce)
+}
+
+func TestDescribeSegmentIndexData(t *testing.T) {
+	ctx := context.Background()
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetCollectionID(mock.Anything, mock.Anything, "coll").Return(1, nil)
+	globalMetaCache = mockCache
+
+	dc := mocks.NewMockDataCoordClient(t)
+	dc.EXPECT().DescribeIndex(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, req *indexpb.DescribeIndexRequest, opts ...grpc.CallOption) (*indexpb.DescribeIndexResponse, error) {
+			indexes := []*indexpb.IndexInfo{
+				{IndexID: 1, IndexName: "vec", IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: "IVF_FLAT"}}},
+				{IndexID: 2, IndexName: "scalar", IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: "INVERTED"}}},
+			}
+			if req.GetIndexName() != "" {
+				indexes = lo.Filter(indexes, func(index *indexpb.IndexInfo, _ int) bool {
+					return index.GetIndexName() == req.GetIndexName()
+				})
+			}
+			return &indexpb.DescribeIndexResponse{Status: merr.Success(), IndexInfos: indexes}, nil
+		})
+	dc.EXPECT().GetSegmentInfo(mock.Anything, mock.Anything).Return(&datapb.GetSegmentInfoResponse{
+		Status: merr.Success(),
+		Infos: []*datapb.SegmentInfo{
+			{ID: 100, CollectionID: 1, State: commonpb.SegmentState_Flushed, NumOfRows: 300},
+			{ID: 101, CollectionID: 1, State: commonpb.SegmentState_Flushed, NumOfRows: 200},
+		},
+	}, nil)
+	dc.EXPECT().GetIndexInfos(mock.Anything, mock.Anything).Return(&indexpb.GetIndexInfoResponse{
+		Status: merr.Success(),
+		SegmentInfo: map[int64]*indexpb.SegmentInfo{
+			100: {IndexInfos: []*indexpb.IndexFilePathInfo{{IndexID: 1, BuildID: 7, IndexFilePaths: []string{"f1", "f2"}, SerializedSize: 10}}},
+		},
+	}, nil)
+	dc.EXPECT().GetSegmentIndexState(mock.Anything, mock.Anything).Return(&indexpb.GetSegmentIndexStateResponse{
+		Status: merr.Success(),
+		States: []*indexpb.SegmentIndexState{
+			{SegmentID: 100, State: commonpb.IndexState_Finished},
+			{SegmentID: 101, State: commonpb.IndexState_InProgress},
+		},
+	}, nil)
+
+	node := &Proxy{dataCoord: dc}
+	node.UpdateStateCode(commonpb.StateCode_Healthy)
+	resp, err := node.DescribeSegmentIndexData(ctx, &federpb.DescribeSegmentIndexDataRequest{
+		CollectionName: "coll",
+		IndexName:      "vec",
+		SegmentsIDs:    []int64{100, 101},
+	})
+	assert.NoError(t, merr.CheckRPCCall(resp, err))
+	assert.Equal(t, "IVF_FLAT", resp.GetIndexParams()[0].GetValue())
+	assert.Len(t, resp.GetIndexData(), 2)
+
+	data := &SegmentIndexData{}
+	assert.NoError(t, json.Unmarshal([]byte(resp.GetIndexData()[100].GetIndexData()), data))
+	assert.False(t, data.BruteForce)
+	assert.Equal(t, int64(300), data.NumRows)
+	assert.Equal(t, []string{"f1", "f2"}, data.IndexFiles)
+	assert.NoError(t, json.Unmarshal([]byte(resp.GetIndexData()[101].GetIndexData()), data))
+	assert.True(t, data.BruteForce)
+	assert.Equal(t, commonpb.IndexState_InProgress.String(), data.State)
+
+	// the index name is required for the collection with several indexes
+	resp, err = node.DescribeSegmentIndexData(ctx, &federpb.DescribeSegmentIndexDataRequest{CollectionName: "coll", SegmentsIDs: []int64{100}})
+	assert.ErrorIs(t, merr.CheckRPCCall(resp, err), merr.ErrParameterInvalid)
+
+	resp, err = node.DescribeSegmentIndexData(ctx, &federpb.DescribeSegmentIndexDataRequest{CollectionName: "coll", IndexName: "vec", SegmentsIDs: []int64{102}})
+	assert.ErrorIs(t, merr.CheckRPCCall(resp, err), merr.ErrSegmentNotFound)
+
+	resp, err = node.DescribeSegmentIndexData(ctx, &federpb.DescribeSegmentIndexDataRequest{CollectionName: "coll"})
+	assert.ErrorIs(t, merr.CheckRPCCall(resp, err), merr.ErrParameterMissing)
+
+	node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	resp, err = node.DescribeSegmentIndexData(ctx, &federpb.DescribeSegmentIndexDataRequest{CollectionName: "coll"})
+	assert.ErrorIs(t, merr.CheckRPCCall(resp, err), merr.ErrServiceNotReady)
+}
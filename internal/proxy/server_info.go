@@ -0,0 +1,133 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	// serverVersionKey, serverFeaturesKey and the limit keys are set in the reserved of the server info returned by
+	// Connect, for the clients to detect the features and the limits of the server.
+	serverVersionKey   = "version"
+	serverFeaturesKey  = "features"
+	serverLimitsPrefix = "limit."
+)
+
+// serverFeatureParams returns the optional features of the server by the params enabling them.
+func serverFeatureParams(params *paramtable.ComponentParam) map[string]*paramtable.ParamItem {
+	return map[string]*paramtable.ParamItem{
+		"authorization":          &params.CommonCfg.AuthorizationEnabled,
+		"ldap":                   &params.ProxyCfg.LDAPEnabled,
+		"grpc_reflection":        &params.ProxyCfg.GrpcReflectionEnabled,
+		"quota_and_limits":       &params.QuotaConfig.QuotaAndLimitsEnabled,
+		"partition_pruning":      &params.ProxyCfg.PartitionPruningEnabled,
+		"search_split":           &params.ProxyCfg.SearchSplitEnabled,
+		"external_rerank":        &params.ProxyCfg.RerankExternalEnabled,
+		"collection_recycle_bin": &params.ProxyCfg.CollectionRecycleBinEnabled,
+		"full_scan_delete_guard": &params.ProxyCfg.FullScanDeleteGuardEnabled,
+		"change_stream":          &params.ProxyCfg.ChangeStreamEnabled,
+		"ingestion":              &params.ProxyCfg.IngestionEnabled,
+		"job_notification":       &params.ProxyCfg.JobNotificationEnabled,
+		"usage_accounting":       &params.ProxyCfg.UsageAccountingEnabled,
+		"session_affinity":       &params.ProxyCfg.SessionAffinityEnabled,
+	}
+}
+
+// serverInfo is the version, the build, the enabled features and the limits of the proxy.
+type serverInfo struct {
+	Version    string           `json:"version"`
+	BuildTags  string           `json:"build_tags"`
+	BuildTime  string           `json:"build_time"`
+	GitCommit  string           `json:"git_commit"`
+	GoVersion  string           `json:"go_version"`
+	DeployMode string           `json:"deploy_mode"`
+	Features   []string         `json:"features"`
+	Limits     map[string]int64 `json:"limits"`
+}
+
+// getServerInfo returns the server info by the current config.
+func getServerInfo() *serverInfo {
+	params := paramtable.Get()
+	features := make([]string, 0)
+	for feature, param := range serverFeatureParams(params) {
+		if param.GetAsBool() {
+			features = append(features, feature)
+		}
+	}
+	if params.ProxyGrpcServerCfg.TLSMode.GetAsInt() > 0 {
+		features = append(features, "tls")
+	}
+	sort.Strings(features)
+
+	limits := map[string]int64{
+		"max_response_size":     params.ProxyGrpcServerCfg.ServerMaxSendSize.GetAsInt64(),
+		"max_dimension":         params.ProxyCfg.MaxDimension.GetAsInt64(),
+		"max_field_num":         params.ProxyCfg.MaxFieldNum.GetAsInt64(),
+		"max_vector_field_num":  params.ProxyCfg.MaxVectorFieldNum.GetAsInt64(),
+		"max_shard_num":         params.ProxyCfg.MaxShardNum.GetAsInt64(),
+		"max_name_length":       params.ProxyCfg.MaxNameLength.GetAsInt64(),
+		"topk_limit":            params.QuotaConfig.TopKLimit.GetAsInt64(),
+		"nq_limit":              params.QuotaConfig.NQLimit.GetAsInt64(),
+		"max_query_result_size": params.QuotaConfig.MaxOutputSize.GetAsInt64(),
+		"max_insert_size":       params.QuotaConfig.MaxInsertSize.GetAsInt64(),
+	}
+	// max_request_size is the limit of the methods without their own, which are max_request_size.<method>
+	sizeLimiter := newRequestSizeLimiter()
+	limits["max_request_size"] = int64(sizeLimiter.defaultLimit)
+	for method, limit := range sizeLimiter.limits {
+		limits["max_request_size."+method] = int64(limit)
+	}
+
+	return &serverInfo{
+		Version:    common.Version.String(),
+		BuildTags:  os.Getenv(metricsinfo.GitBuildTagsEnvKey),
+		BuildTime:  os.Getenv(metricsinfo.MilvusBuildTimeEnvKey),
+		GitCommit:  os.Getenv(metricsinfo.GitCommitEnvKey),
+		GoVersion:  os.Getenv(metricsinfo.MilvusUsedGoVersion),
+		DeployMode: os.Getenv(metricsinfo.DeployModeEnvKey),
+		Features:   features,
+		Limits:     limits,
+	}
+}
+
+// toProto returns the server info of Connect, the version, the features and the limits are set in the reserved.
+func (s *serverInfo) toProto() *commonpb.ServerInfo {
+	reserved := map[string]string{
+		serverVersionKey:  s.Version,
+		serverFeaturesKey: strings.Join(s.Features, ","),
+	}
+	for name, limit := range s.Limits {
+		reserved[serverLimitsPrefix+name] = strconv.FormatInt(limit, 10)
+	}
+	return &commonpb.ServerInfo{
+		BuildTags:  s.BuildTags,
+		BuildTime:  s.BuildTime,
+		GitCommit:  s.GitCommit,
+		GoVersion:  s.GoVersion,
+		DeployMode: s.DeployMode,
+		Reserved:   reserved,
+	}
+}
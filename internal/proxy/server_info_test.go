@@ -0,0 +1,58 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestGetServerInfo(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.ProxyCfg.MaxRequestSizePerMethod.GetFunc = func() map[string]string {
+		return map[string]string{"Insert": "4096"}
+	}
+	defer func() { params.ProxyCfg.MaxRequestSizePerMethod.GetFunc = nil }()
+
+	info := getServerInfo()
+	assert.Equal(t, common.Version.String(), info.Version)
+	assert.NotContains(t, info.Features, "grpc_reflection")
+	assert.Equal(t, params.ProxyCfg.MaxDimension.GetAsInt64(), info.Limits["max_dimension"])
+	assert.Equal(t, params.ProxyGrpcServerCfg.ServerMaxRecvSize.GetAsInt64(), info.Limits["max_request_size"])
+	assert.Equal(t, int64(4096), info.Limits["max_request_size.insert"])
+
+	params.Save(params.ProxyCfg.GrpcReflectionEnabled.Key, "true")
+	defer params.Reset(params.ProxyCfg.GrpcReflectionEnabled.Key)
+	params.Save(params.ProxyCfg.SearchSplitEnabled.Key, "true")
+	defer params.Reset(params.ProxyCfg.SearchSplitEnabled.Key)
+	info = getServerInfo()
+	assert.Contains(t, info.Features, "grpc_reflection")
+	assert.Contains(t, info.Features, "search_split")
+	assert.IsIncreasing(t, info.Features)
+
+	pb := info.toProto()
+	assert.Equal(t, info.Version, pb.GetReserved()[serverVersionKey])
+	assert.ElementsMatch(t, info.Features, strings.Split(pb.GetReserved()[serverFeaturesKey], ","))
+	assert.Equal(t, params.ProxyCfg.MaxDimension.GetValue(), pb.GetReserved()[serverLimitsPrefix+"max_dimension"])
+	assert.Equal(t, "4096", pb.GetReserved()[serverLimitsPrefix+"max_request_size.insert"])
+}
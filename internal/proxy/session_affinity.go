@@ -0,0 +1,73 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	// SessionAffinityProxyKey is the response header of the id of the proxy, which the client load balancer sends back
+	// in the request metadata or the http header to report the proxy the session is kept on.
+	SessionAffinityProxyKey = "milvus-affinity-proxy"
+	// SessionAffinityTTLKey is the response header of the recommended seconds to keep the session on the proxy.
+	SessionAffinityTTLKey = "milvus-affinity-ttl"
+)
+
+// SessionAffinity records whether the request is on the proxy the client keeps the session on, and returns the
+// affinity headers of the response, or nil if disabled.
+func SessionAffinity(requestedProxy string) map[string]string {
+	if !Params.ProxyCfg.SessionAffinityEnabled.GetAsBool() {
+		return nil
+	}
+	proxyID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	if requestedProxy != "" {
+		result := metrics.CacheHitLabel
+		if requestedProxy != proxyID {
+			result = metrics.CacheMissLabel
+		}
+		metrics.ProxySessionAffinityCount.WithLabelValues(proxyID, result).Inc()
+	}
+	return map[string]string{
+		SessionAffinityProxyKey: proxyID,
+		SessionAffinityTTLKey:   Params.ProxyCfg.SessionAffinityTTL.GetValue(),
+	}
+}
+
+// SessionAffinityInterceptor returns a new unary server interceptor that hints the session affinity in the headers
+// of the responses.
+func SessionAffinityInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		requestedProxy := ""
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(SessionAffinityProxyKey); len(values) > 0 {
+				requestedProxy = values[0]
+			}
+		}
+		if headers := SessionAffinity(requestedProxy); len(headers) > 0 {
+			_ = grpc.SetHeader(ctx, metadata.New(headers))
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,91 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+type affinityServerStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *affinityServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestSessionAffinity(t *testing.T) {
+	paramtable.Init()
+	proxyID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	hits := metrics.ProxySessionAffinityCount.WithLabelValues(proxyID, metrics.CacheHitLabel)
+	misses := metrics.ProxySessionAffinityCount.WithLabelValues(proxyID, metrics.CacheMissLabel)
+	hit, miss := testutil.ToFloat64(hits), testutil.ToFloat64(misses)
+
+	headers := SessionAffinity("")
+	assert.Equal(t, proxyID, headers[SessionAffinityProxyKey])
+	assert.Equal(t, Params.ProxyCfg.SessionAffinityTTL.GetValue(), headers[SessionAffinityTTLKey])
+	assert.Equal(t, hit, testutil.ToFloat64(hits))
+
+	SessionAffinity(proxyID)
+	SessionAffinity("-1")
+	assert.Equal(t, hit+1, testutil.ToFloat64(hits))
+	assert.Equal(t, miss+1, testutil.ToFloat64(misses))
+
+	paramtable.Get().Save(Params.ProxyCfg.SessionAffinityEnabled.Key, "false")
+	defer paramtable.Get().Reset(Params.ProxyCfg.SessionAffinityEnabled.Key)
+	assert.Nil(t, SessionAffinity(proxyID))
+	assert.Equal(t, hit+1, testutil.ToFloat64(hits))
+}
+
+func TestSessionAffinityInterceptor(t *testing.T) {
+	paramtable.Init()
+	proxyID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	hits := metrics.ProxySessionAffinityCount.WithLabelValues(proxyID, metrics.CacheHitLabel)
+	hit := testutil.ToFloat64(hits)
+
+	stream := &affinityServerStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(SessionAffinityProxyKey, proxyID))
+	interceptor := SessionAffinityInterceptor()
+	resp, err := interceptor(ctx, "req", &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (interface{}, error) {
+		return "resp", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "resp", resp)
+	assert.Equal(t, []string{proxyID}, stream.header.Get(SessionAffinityProxyKey))
+	assert.Equal(t, []string{Params.ProxyCfg.SessionAffinityTTL.GetValue()}, stream.header.Get(SessionAffinityTTLKey))
+	assert.Equal(t, hit+1, testutil.ToFloat64(hits))
+
+	// no server stream, the headers are not set
+	resp, err = interceptor(context.Background(), "req", &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (interface{}, error) {
+		return "resp", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "resp", resp)
+}
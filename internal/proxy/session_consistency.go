@@ -0,0 +1,140 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proxy/connection"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// sessionIDKey is the request metadata key of the client session, the reads and writes of the same session are
+// consistent with each other under the Session consistency level. The identifier of the connection is used if absent.
+const sessionIDKey = "session_id"
+
+// getSessionKey returns the key of the client session of the request, empty if the session is unknown.
+func getSessionKey(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(sessionIDKey); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	if identifier, err := connection.GetIdentifierFromContext(ctx); err == nil {
+		return strconv.FormatInt(identifier, 10)
+	}
+	return ""
+}
+
+type sessionCollection struct {
+	session      string
+	collectionID UniqueID
+}
+
+type sessionMutation struct {
+	ts         Timestamp
+	lastActive time.Time
+}
+
+// sessionMutationTracker tracks the timestamp of the last successful mutation per client session and collection,
+// to read the writes of the session without the application passing the timestamps of the mutations.
+type sessionMutationTracker struct {
+	mu        sync.Mutex
+	mutations map[sessionCollection]*sessionMutation
+	lastSweep time.Time
+}
+
+func newSessionMutationTracker() *sessionMutationTracker {
+	return &sessionMutationTracker{
+		mutations: make(map[sessionCollection]*sessionMutation),
+		lastSweep: time.Now(),
+	}
+}
+
+var globalSessionMutationTracker = newSessionMutationTracker()
+
+// record records the timestamp of the successful mutation of the session on the collection, the sessions idle for
+// the ttl are swept.
+func (t *sessionMutationTracker) record(session string, collectionID UniqueID, ts Timestamp, now time.Time) {
+	ttl := Params.ProxyCfg.SessionMutationTTL.GetAsDuration(time.Second)
+	if session == "" || ts == 0 || ttl <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := sessionCollection{session: session, collectionID: collectionID}
+	mutation, ok := t.mutations[key]
+	if !ok {
+		mutation = &sessionMutation{}
+		t.mutations[key] = mutation
+	}
+	if ts > mutation.ts {
+		mutation.ts = ts
+	}
+	mutation.lastActive = now
+
+	if now.Sub(t.lastSweep) < ttl {
+		return
+	}
+	for key, mutation := range t.mutations {
+		if now.Sub(mutation.lastActive) > ttl {
+			delete(t.mutations, key)
+		}
+	}
+	t.lastSweep = now
+}
+
+// get returns the timestamp of the last mutation of the session on the collection, 0 if none within the ttl.
+func (t *sessionMutationTracker) get(session string, collectionID UniqueID, now time.Time) Timestamp {
+	ttl := Params.ProxyCfg.SessionMutationTTL.GetAsDuration(time.Second)
+	if session == "" || ttl <= 0 {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	mutation, ok := t.mutations[sessionCollection{session: session, collectionID: collectionID}]
+	if !ok || now.Sub(mutation.lastActive) > ttl {
+		return 0
+	}
+	return mutation.ts
+}
+
+// recordSessionMutation records the timestamp of the successful mutation for the later reads of the same session.
+func recordSessionMutation(ctx context.Context, collectionID UniqueID, status *commonpb.Status, ts Timestamp) {
+	if !merr.Ok(status) {
+		return
+	}
+	globalSessionMutationTracker.record(getSessionKey(ctx), collectionID, ts, time.Now())
+}
+
+// sessionGuaranteeTs raises the guarantee timestamp of the Session consistency read to the last mutation of the
+// same session on the collection, so the read observes the writes of the session.
+func sessionGuaranteeTs(ctx context.Context, collectionID UniqueID, level commonpb.ConsistencyLevel, guaranteeTs Timestamp) Timestamp {
+	if level != commonpb.ConsistencyLevel_Session {
+		return guaranteeTs
+	}
+	if ts := globalSessionMutationTracker.get(getSessionKey(ctx), collectionID, time.Now()); ts > guaranteeTs {
+		return ts
+	}
+	return guaranteeTs
+}
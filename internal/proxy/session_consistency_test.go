@@ -0,0 +1,78 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestGetSessionKey(t *testing.T) {
+	assert.Empty(t, getSessionKey(context.Background()))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(sessionIDKey, "s1"))
+	assert.Equal(t, "s1", getSessionKey(ctx))
+}
+
+func TestSessionMutationTracker(t *testing.T) {
+	paramtable.Init()
+	tracker := newSessionMutationTracker()
+	now := time.Now()
+	tracker.record("s1", 1, 100, now)
+	tracker.record("s1", 1, 90, now)
+	tracker.record("s1", 2, 200, now)
+	tracker.record("", 1, 300, now)
+	assert.Equal(t, Timestamp(100), tracker.get("s1", 1, now))
+	assert.Equal(t, Timestamp(200), tracker.get("s1", 2, now))
+	assert.Zero(t, tracker.get("s2", 1, now))
+	assert.Zero(t, tracker.get("", 1, now))
+
+	// idle sessions expire after the ttl and are swept by the later records
+	later := now.Add(Params.ProxyCfg.SessionMutationTTL.GetAsDuration(time.Second) + time.Second)
+	assert.Zero(t, tracker.get("s1", 1, later))
+	tracker.record("s2", 1, 300, later)
+	assert.Len(t, tracker.mutations, 1)
+
+	paramtable.Get().Save(Params.ProxyCfg.SessionMutationTTL.Key, "0")
+	defer paramtable.Get().Reset(Params.ProxyCfg.SessionMutationTTL.Key)
+	assert.Zero(t, tracker.get("s2", 1, later))
+}
+
+func TestSessionGuaranteeTs(t *testing.T) {
+	paramtable.Init()
+	tracker := globalSessionMutationTracker
+	defer func() { globalSessionMutationTracker = tracker }()
+	globalSessionMutationTracker = newSessionMutationTracker()
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(sessionIDKey, "s1"))
+	recordSessionMutation(ctx, 1, merr.Status(merr.ErrServiceRateLimit), 100)
+	assert.Equal(t, Timestamp(0), sessionGuaranteeTs(ctx, 1, commonpb.ConsistencyLevel_Session, 0))
+
+	recordSessionMutation(ctx, 1, merr.Success(), 100)
+	assert.Equal(t, Timestamp(100), sessionGuaranteeTs(ctx, 1, commonpb.ConsistencyLevel_Session, 0))
+	assert.Equal(t, Timestamp(150), sessionGuaranteeTs(ctx, 1, commonpb.ConsistencyLevel_Session, 150))
+	assert.Equal(t, Timestamp(0), sessionGuaranteeTs(ctx, 2, commonpb.ConsistencyLevel_Session, 0))
+	assert.Equal(t, Timestamp(1), sessionGuaranteeTs(ctx, 1, commonpb.ConsistencyLevel_Eventually, 1))
+	assert.Equal(t, Timestamp(0), sessionGuaranteeTs(context.Background(), 1, commonpb.ConsistencyLevel_Session, 0))
+}
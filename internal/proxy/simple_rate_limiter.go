@@ -19,9 +19,11 @@ package proxy
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
 
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
@@ -41,12 +43,30 @@ import (
 type SimpleLimiter struct {
 	quotaStatesMu sync.RWMutex
 	rateLimiter   *rlinternal.RateLimiterTree
+
+	// collectionRateSources are the collections whose effective rates are reported in ProxyLimiterRate.
+	collectionRateSources typeutil.Set[string]
+
+	// distributed enforces the cluster level rates across the proxies if enabled.
+	distributed *distributedRateLimiter
+
+	// emergencyFactor scales down the cluster level rates of the dml and dql requests while the proxy sheds the
+	// load under memory pressure, emergencyBase keeps the rates before scaled to restore them.
+	emergencyFactor float64
+	emergencyBase   map[internalpb.RateType]ratelimitutil.Limit
+
+	// rootRates is the cluster level rates and quota states refreshed on updates, read without the quotaStatesMu.
+	rootRates atomic.Pointer[LimiterRates]
 }
 
 // NewSimpleLimiter returns a new SimpleLimiter.
 func NewSimpleLimiter() *SimpleLimiter {
 	rootRateLimiter := newClusterLimiter()
-	m := &SimpleLimiter{rateLimiter: rlinternal.NewRateLimiterTree(rootRateLimiter)}
+	m := &SimpleLimiter{
+		rateLimiter:           rlinternal.NewRateLimiterTree(rootRateLimiter),
+		collectionRateSources: typeutil.NewSet[string](),
+	}
+	m.refreshRootRates()
 	return m
 }
 
@@ -61,7 +81,7 @@ func (m *SimpleLimiter) Check(dbID int64, collectionIDToPartIDs map[int64][]int6
 
 	// 1. check global(cluster) level rate limits
 	clusterRateLimiters := m.rateLimiter.GetRootLimiters()
-	ret := clusterRateLimiters.Check(rt, n)
+	ret := m.checkClusterLimit(clusterRateLimiters, rt, n)
 
 	if ret != nil {
 		clusterRateLimiters.Cancel(rt, n)
@@ -123,6 +143,37 @@ func (m *SimpleLimiter) Check(dbID int64, collectionIDToPartIDs map[int64][]int6
 	return ret
 }
 
+// SetDistributedLimiter sets the limiter enforcing the cluster level rates across the proxies.
+func (m *SimpleLimiter) SetDistributedLimiter(distributed *distributedRateLimiter) {
+	m.distributed = distributed
+}
+
+// checkClusterLimit checks the cluster level rate limits in the token buckets shared by the proxies if enabled,
+// otherwise or if the shared buckets fail, in the local limiters of the share of the proxy.
+func (m *SimpleLimiter) checkClusterLimit(clusterRateLimiters *rlinternal.RateLimiterNode, rt internalpb.RateType, n int) error {
+	if m.distributed == nil || !Params.ProxyCfg.DistributedRateLimitEnabled.GetAsBool() {
+		return clusterRateLimiters.Check(rt, n)
+	}
+	limiter, ok := clusterRateLimiters.GetLimiters().Get(rt)
+	if !ok || limiter.Limit() == ratelimitutil.Inf {
+		return nil
+	}
+	// the requests denied by the quota center are rejected by the local limiters
+	if limiter.Limit() == 0 {
+		return clusterRateLimiters.GetQuotaExceededError(rt)
+	}
+	// the share of the proxy follows the rates throttled by the quota center
+	share := float64(limiter.Limit())
+	limited, err := m.distributed.check(rt, n, share)
+	if err != nil {
+		return clusterRateLimiters.Check(rt, n)
+	}
+	if limited {
+		return clusterRateLimiters.GetRateLimitError(share)
+	}
+	return nil
+}
+
 func isNotCollectionLevelLimitRequest(rt internalpb.RateType) bool {
 	// Most ddl is global level, only DDLFlush will be applied at collection
 	switch rt {
@@ -163,10 +214,73 @@ func (m *SimpleLimiter) GetQuotaStates() ([]milvuspb.QuotaState, []string) {
 	return states, reasons
 }
 
+// LimiterRates is the view of the effective limits of a rate limiter node and its children.
+// Only the limited rate types are listed in Rates, the absent ones are unlimited.
+type LimiterRates struct {
+	Scope    string             `json:"scope"`
+	ID       int64              `json:"id"`
+	Rates    map[string]float64 `json:"rates,omitempty"`
+	States   map[string]string  `json:"states,omitempty"`
+	Children []*LimiterRates    `json:"children,omitempty"`
+}
+
+// GetRates returns the effective limits of all the scopes.
+func (m *SimpleLimiter) GetRates() *LimiterRates {
+	m.quotaStatesMu.RLock()
+	defer m.quotaStatesMu.RUnlock()
+	return getLimiterRates(m.rateLimiter.GetRootLimiters(), 0)
+}
+
+// GetRootRates returns the cluster level rates and quota states without the children.
+func (m *SimpleLimiter) GetRootRates() *LimiterRates {
+	return m.rootRates.Load()
+}
+
+// refreshRootRates must be called with the quotaStatesMu held or before the limiter is shared.
+func (m *SimpleLimiter) refreshRootRates() {
+	m.rootRates.Store(getNodeRates(m.rateLimiter.GetRootLimiters(), 0))
+}
+
+func getLimiterRates(node *rlinternal.RateLimiterNode, id int64) *LimiterRates {
+	rates := getNodeRates(node, id)
+	node.GetChildren().Range(func(childID int64, child *rlinternal.RateLimiterNode) bool {
+		rates.Children = append(rates.Children, getLimiterRates(child, childID))
+		return true
+	})
+	sort.Slice(rates.Children, func(i, j int) bool {
+		return rates.Children[i].ID < rates.Children[j].ID
+	})
+	return rates
+}
+
+func getNodeRates(node *rlinternal.RateLimiterNode, id int64) *LimiterRates {
+	rates := &LimiterRates{
+		Scope:  node.Level().String(),
+		ID:     id,
+		Rates:  make(map[string]float64),
+		States: make(map[string]string),
+	}
+	node.GetLimiters().Range(func(rt internalpb.RateType, limiter *ratelimitutil.Limiter) bool {
+		if limit := limiter.Limit(); limit != ratelimitutil.Inf {
+			rates.Rates[rt.String()] = float64(limit)
+		}
+		return true
+	})
+	node.GetQuotaStates().Range(func(state milvuspb.QuotaState, errCode commonpb.ErrorCode) bool {
+		rates.States[state.String()] = ratelimitutil.GetQuotaErrorString(errCode)
+		return true
+	})
+	return rates
+}
+
 // SetRates sets quota states for SimpleLimiter.
 func (m *SimpleLimiter) SetRates(rootLimiter *proxypb.LimiterNode) error {
 	m.quotaStatesMu.Lock()
 	defer m.quotaStatesMu.Unlock()
+	defer m.refreshRootRates()
+	// the rates absent in the request are kept, restore them to scale the updated rates once
+	m.restoreEmergencyLimits()
+	defer m.scaleEmergencyLimits()
 
 	// Reset the limiter rates due to potential changes in configurations.
 	var (
@@ -194,9 +308,103 @@ func (m *SimpleLimiter) SetRates(rootLimiter *proxypb.LimiterNode) error {
 	}
 
 	m.rateLimiter.ClearInvalidLimiterNode(rootLimiter)
+	m.updateCollectionRateMetrics()
 	return nil
 }
 
+// SetEmergencyFactor scales the cluster level rates of the dml and dql requests by the factor, until reset by the
+// factor 1. The unlimited rates are not scaled.
+func (m *SimpleLimiter) SetEmergencyFactor(factor float64) {
+	m.quotaStatesMu.Lock()
+	defer m.quotaStatesMu.Unlock()
+	m.restoreEmergencyLimits()
+	m.emergencyFactor = factor
+	m.scaleEmergencyLimits()
+	m.refreshRootRates()
+}
+
+func isEmergencyScaledRateType(rt internalpb.RateType) bool {
+	switch rt {
+	case internalpb.RateType_DMLInsert,
+		internalpb.RateType_DMLUpsert,
+		internalpb.RateType_DMLDelete,
+		internalpb.RateType_DMLBulkLoad,
+		internalpb.RateType_DQLSearch,
+		internalpb.RateType_DQLQuery:
+		return true
+	default:
+		return false
+	}
+}
+
+// scaleEmergencyLimits must be called with the quotaStatesMu held.
+func (m *SimpleLimiter) scaleEmergencyLimits() {
+	if m.emergencyFactor <= 0 || m.emergencyFactor >= 1 {
+		return
+	}
+	m.emergencyBase = make(map[internalpb.RateType]ratelimitutil.Limit)
+	m.rateLimiter.GetRootLimiters().GetLimiters().Range(func(rt internalpb.RateType, limiter *ratelimitutil.Limiter) bool {
+		if !isEmergencyScaledRateType(rt) || limiter.Limit() == ratelimitutil.Inf {
+			return true
+		}
+		m.emergencyBase[rt] = limiter.Limit()
+		limiter.SetLimit(limiter.Limit() * ratelimitutil.Limit(m.emergencyFactor))
+		return true
+	})
+}
+
+// restoreEmergencyLimits must be called with the quotaStatesMu held.
+func (m *SimpleLimiter) restoreEmergencyLimits() {
+	limiters := m.rateLimiter.GetRootLimiters().GetLimiters()
+	for rt, limit := range m.emergencyBase {
+		if limiter, ok := limiters.Get(rt); ok {
+			limiter.SetLimit(limit)
+		}
+	}
+	m.emergencyBase = nil
+}
+
+// updateCollectionRateMetrics reports the effective rates of the collections, which are the min of
+// the cluster, database and collection limits, and removes the rates of the collections cleared.
+func (m *SimpleLimiter) updateCollectionRateMetrics() {
+	nodeID := paramtable.GetNodeID()
+	clusterLimiters := m.rateLimiter.GetRootLimiters()
+	sources := typeutil.NewSet[string]()
+	clusterLimiters.GetChildren().Range(func(_ int64, dbLimiters *rlinternal.RateLimiterNode) bool {
+		dbLimiters.GetChildren().Range(func(collectionID int64, collectionLimiters *rlinternal.RateLimiterNode) bool {
+			sourceID := getCollectionSourceID(collectionID)
+			sources.Insert(sourceID)
+			collectionLimiters.GetLimiters().Range(func(rt internalpb.RateType, limiter *ratelimitutil.Limiter) bool {
+				rate := getEffectiveRate(rt, limiter.Limit(), clusterLimiters, dbLimiters)
+				if rate == ratelimitutil.Inf {
+					metrics.ProxyLimiterRate.DeleteLabelValues(strconv.FormatInt(nodeID, 10), sourceID, rt.String())
+					return true
+				}
+				setRateGaugeByRateType(rt, nodeID, sourceID, float64(rate))
+				return true
+			})
+			return true
+		})
+		return true
+	})
+	for sourceID := range m.collectionRateSources {
+		if !sources.Contain(sourceID) {
+			metrics.CleanupProxyLimiterRateMetrics(nodeID, sourceID)
+		}
+	}
+	m.collectionRateSources = sources
+}
+
+// getEffectiveRate returns the min of the limit and the limits of the same rate type in the ancestors.
+func getEffectiveRate(rt internalpb.RateType, limit ratelimitutil.Limit, ancestors ...*rlinternal.RateLimiterNode) ratelimitutil.Limit {
+	for _, ancestor := range ancestors {
+		if limiter, ok := ancestor.GetLimiters().Get(rt); ok && limiter.Limit() < limit {
+			limit = limiter.Limit()
+		}
+	}
+	return limit
+}
+
 func initLimiter(rln *rlinternal.RateLimiterNode, rateLimiterConfigs map[internalpb.RateType]*paramtable.ParamItem) {
 	log := log.Ctx(context.TODO()).WithRateGroup("proxy.rateLimiter", 1.0, 60.0)
 	for rt, p := range rateLimiterConfigs {
@@ -249,7 +457,9 @@ func (m *SimpleLimiter) updateLimiterNode(req *proxypb.Limiter, node *rlinternal
 			return fmt.Errorf("unregister rateLimiter for rateType %s", rate.GetRt().String())
 		}
 		limit.SetLimit(ratelimitutil.Limit(rate.GetR()))
-		setRateGaugeByRateType(rate.GetRt(), paramtable.GetNodeID(), sourceID, rate.GetR())
+		if node.Level() != internalpb.RateScope_Collection {
+			setRateGaugeByRateType(rate.GetRt(), paramtable.GetNodeID(), sourceID, rate.GetR())
+		}
 	}
 	quotaStates := typeutil.NewConcurrentMap[milvuspb.QuotaState, commonpb.ErrorCode]()
 	states := req.GetStates()
@@ -273,9 +483,6 @@ func (m *SimpleLimiter) updateRateLimiter(reqRootLimiterNode *proxypb.LimiterNod
 	getDBSourceID := func(dbID int64) string {
 		return fmt.Sprintf("db.%d", dbID)
 	}
-	getCollectionSourceID := func(collectionID int64) string {
-		return fmt.Sprintf("collection.%d", collectionID)
-	}
 	getPartitionSourceID := func(partitionID int64) string {
 		return fmt.Sprintf("partition.%d", partitionID)
 	}
@@ -318,6 +525,10 @@ func (m *SimpleLimiter) updateRateLimiter(reqRootLimiterNode *proxypb.LimiterNod
 	return nil
 }
 
+func getCollectionSourceID(collectionID int64) string {
+	return fmt.Sprintf("collection.%d", collectionID)
+}
+
 // setRateGaugeByRateType sets ProxyLimiterRate metrics.
 func setRateGaugeByRateType(rateType internalpb.RateType, nodeID int64, sourceID string, rate float64) {
 	if ratelimitutil.Limit(rate) == ratelimitutil.Inf {
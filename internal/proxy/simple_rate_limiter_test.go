@@ -21,6 +21,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
@@ -28,6 +29,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/proxypb"
 	rlinternal "github.com/milvus-io/milvus/internal/util/ratelimitutil"
+	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/ratelimitutil"
@@ -207,6 +209,42 @@ func TestSimpleRateLimiter(t *testing.T) {
 		assert.Contains(t, codes, ratelimitutil.GetQuotaErrorString(commonpb.ErrorCode_DiskQuotaExhausted))
 		assert.Contains(t, codes, ratelimitutil.GetQuotaErrorString(commonpb.ErrorCode_ForceDeny))
 	})
+
+	t.Run("test collection rate metrics", func(t *testing.T) {
+		simpleLimiter := NewSimpleLimiter()
+		nodeID := paramtable.GetStringNodeID()
+		rootLimiter := newCollectionLimiterNode(map[int64]*proxypb.LimiterNode{
+			1: {
+				Limiter: &proxypb.Limiter{
+					Rates: []*internalpb.Rate{{Rt: internalpb.RateType_DMLInsert, R: 100}},
+				},
+				Children: make(map[int64]*proxypb.LimiterNode),
+			},
+			2: {
+				Limiter: &proxypb.Limiter{
+					Rates: []*internalpb.Rate{{Rt: internalpb.RateType_DMLInsert, R: 10}},
+				},
+				Children: make(map[int64]*proxypb.LimiterNode),
+			},
+		})
+		rootLimiter.GetChildren()[0].Limiter.Rates = []*internalpb.Rate{{Rt: internalpb.RateType_DMLInsert, R: 50}}
+		assert.NoError(t, simpleLimiter.SetRates(rootLimiter))
+
+		// the effective rate is the min of the database and collection rates
+		insertRate := func(collectionID int64) float64 {
+			return testutil.ToFloat64(metrics.ProxyLimiterRate.WithLabelValues(nodeID,
+				getCollectionSourceID(collectionID), internalpb.RateType_DMLInsert.String()))
+		}
+		assert.Equal(t, float64(50), insertRate(1))
+		assert.Equal(t, float64(10), insertRate(2))
+		assert.True(t, simpleLimiter.collectionRateSources.Contain(getCollectionSourceID(1), getCollectionSourceID(2)))
+
+		// the rates of the removed collection are cleaned up
+		delete(rootLimiter.GetChildren()[0].GetChildren(), 2)
+		assert.NoError(t, simpleLimiter.SetRates(rootLimiter))
+		assert.False(t, simpleLimiter.collectionRateSources.Contain(getCollectionSourceID(2)))
+		assert.Equal(t, float64(0), insertRate(2))
+	})
 }
 
 func getZeroRates() []*internalpb.Rate {
@@ -0,0 +1,131 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	snowflakeWorkerPrefix = "proxy/snowflake-worker"
+	// snowflakeWorkerTTL is the ttl in seconds of the lease holding the snowflake worker id.
+	snowflakeWorkerTTL = 15
+	// snowflakeWorkerRetryInterval is the interval to retry acquiring the snowflake worker id.
+	snowflakeWorkerRetryInterval = time.Second
+)
+
+// snowflakeWorker holds the snowflake worker id of the proxy by the key of the id in etcd, put with a lease of the
+// proxy only if absent, so no two live proxies hold the same id. The generator stops generating the ids once the
+// lease is not kept alive for 2/3 of its ttl, before another proxy may acquire the id after the lease expires.
+type snowflakeWorker struct {
+	cli       *clientv3.Client
+	root      string
+	generator *snowflakeGenerator
+}
+
+func newSnowflakeWorker(cli *clientv3.Client, root string, generator *snowflakeGenerator) *snowflakeWorker {
+	return &snowflakeWorker{cli: cli, root: root, generator: generator}
+}
+
+// acquire acquires a free worker id with a new lease, from the one of the node id.
+func (w *snowflakeWorker) acquire(ctx context.Context) (int64, clientv3.LeaseID, error) {
+	lease, err := w.cli.Grant(ctx, snowflakeWorkerTTL)
+	if err != nil {
+		return -1, 0, err
+	}
+	nodeID := paramtable.GetNodeID()
+	mask := int64(1<<snowflakeNodeBits - 1)
+	for i := int64(0); i <= mask; i++ {
+		id := (nodeID + i) & mask
+		key := path.Join(w.root, strconv.FormatInt(id, 10))
+		txn, err := w.cli.Txn(ctx).
+			If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+			Then(clientv3.OpPut(key, strconv.FormatInt(nodeID, 10), clientv3.WithLease(lease.ID))).
+			Commit()
+		if err != nil {
+			w.revoke(lease.ID)
+			return -1, 0, err
+		}
+		if txn.Succeeded {
+			return id, lease.ID, nil
+		}
+	}
+	w.revoke(lease.ID)
+	return -1, 0, merr.WrapErrServiceUnavailable("all the snowflake worker ids are held by the other proxies")
+}
+
+func (w *snowflakeWorker) revoke(leaseID clientv3.LeaseID) {
+	ctx, cancel := context.WithTimeout(context.Background(), rateBackendTimeout)
+	defer cancel()
+	if _, err := w.cli.Revoke(ctx, leaseID); err != nil {
+		log.Warn("failed to revoke the lease of the snowflake worker id", zap.Error(err))
+	}
+}
+
+// keep holds a worker id until the context is done, and acquires another one once the lease is lost.
+func (w *snowflakeWorker) keep(ctx context.Context) {
+	for {
+		id, leaseID, err := w.acquire(ctx)
+		if err == nil {
+			w.hold(ctx, id, leaseID)
+			w.revoke(leaseID)
+		} else {
+			log.Warn("failed to acquire the snowflake worker id", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(snowflakeWorkerRetryInterval):
+		}
+	}
+}
+
+// hold keeps the lease of the worker id alive, and releases the id from the generator once the lease is lost.
+func (w *snowflakeWorker) hold(ctx context.Context, id int64, leaseID clientv3.LeaseID) {
+	defer w.generator.setWorker(-1, time.Time{})
+	ch, err := w.cli.KeepAlive(ctx, leaseID)
+	if err != nil {
+		log.Warn("failed to keep alive the lease of the snowflake worker id", zap.Int64("workerID", id), zap.Error(err))
+		return
+	}
+	log.Info("snowflake worker id acquired", zap.Int64("workerID", id))
+	for resp := range ch {
+		w.generator.setWorker(id, time.Now().Add(time.Duration(resp.TTL)*time.Second*2/3))
+	}
+	log.Warn("snowflake worker id released as the lease is lost", zap.Int64("workerID", id))
+}
+
+// snowflakeWorkerLoop holds the snowflake worker id of the proxy, the node id is the worker id without etcd.
+func (node *Proxy) snowflakeWorkerLoop() {
+	defer node.wg.Done()
+	if node.etcdCli == nil {
+		globalSnowflakeGenerator.setWorker(paramtable.GetNodeID()&(1<<snowflakeNodeBits-1), time.Time{})
+		return
+	}
+	newSnowflakeWorker(node.etcdCli, path.Join(Params.EtcdCfg.MetaRootPath.GetValue(), snowflakeWorkerPrefix),
+		globalSnowflakeGenerator).keep(node.ctx)
+}
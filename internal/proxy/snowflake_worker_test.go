@@ -0,0 +1,80 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/milvus-io/milvus/pkg/util/etcd"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestSnowflakeWorker(t *testing.T) {
+	paramtable.Init()
+	cli, err := etcd.GetEtcdClient(
+		Params.EtcdCfg.UseEmbedEtcd.GetAsBool(),
+		Params.EtcdCfg.EtcdUseSSL.GetAsBool(),
+		Params.EtcdCfg.Endpoints.GetAsStrings(),
+		Params.EtcdCfg.EtcdTLSCert.GetValue(),
+		Params.EtcdCfg.EtcdTLSKey.GetValue(),
+		Params.EtcdCfg.EtcdTLSCACert.GetValue(),
+		Params.EtcdCfg.EtcdTLSMinVersion.GetValue())
+	assert.NoError(t, err)
+	defer cli.Close()
+	root := fmt.Sprintf("/test/proxy/snowflake-worker/%d", time.Now().UnixNano())
+	defer cli.Delete(context.Background(), root, clientv3.WithPrefix())
+
+	// the proxies of the same node id hold the different worker ids
+	ctx := context.Background()
+	worker1, worker2 := newSnowflakeWorker(cli, root, newSnowflakeGenerator()), newSnowflakeWorker(cli, root, newSnowflakeGenerator())
+	id1, lease1, err := worker1.acquire(ctx)
+	assert.NoError(t, err)
+	id2, lease2, err := worker2.acquire(ctx)
+	assert.NoError(t, err)
+	assert.NotEqual(t, id1, id2)
+
+	// the worker id is free again once the lease is revoked
+	worker1.revoke(lease1)
+	id3, lease3, err := worker1.acquire(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, id1, id3)
+	worker1.revoke(lease3)
+	worker2.revoke(lease2)
+
+	// the generator holds the worker id while it is kept
+	generator := newSnowflakeGenerator()
+	keepCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		newSnowflakeWorker(cli, root, generator).keep(keepCtx)
+	}()
+	assert.Eventually(t, func() bool {
+		_, err := generator.next(defaultSnowflakeEpoch, 1)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+	_, err = generator.next(defaultSnowflakeEpoch, 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,204 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// statisticsBreakdownKey is the request metadata key to return the statistics breakdown by partition and segment state.
+const statisticsBreakdownKey = "statistics_breakdown"
+
+// statisticsBreakdownStatKey is the key of the breakdown json in the statistics.
+const statisticsBreakdownStatKey = "breakdown"
+
+// segment states in the statistics breakdown, the flushed segments with any index built are indexed.
+const (
+	breakdownStateGrowing = "growing"
+	breakdownStateSealed  = "sealed"
+	breakdownStateFlushed = "flushed"
+	breakdownStateIndexed = "indexed"
+)
+
+type segmentStateBreakdown struct {
+	NumSegments int   `json:"num_segments"`
+	RowCount    int64 `json:"row_count"`
+	Size        int64 `json:"size"`
+}
+
+type partitionBreakdown struct {
+	PartitionName string                            `json:"partition_name"`
+	PartitionID   int64                             `json:"partition_id"`
+	RowCount      int64                             `json:"row_count"`
+	Size          int64                             `json:"size"`
+	States        map[string]*segmentStateBreakdown `json:"states"`
+}
+
+// getSegmentInfos returns the healthy segments of the collection in the partitions from dataCoord.
+func getSegmentInfos(ctx context.Context, dc types.DataCoordClient, collectionID UniqueID, partitionIDs []UniqueID) ([]*datapb.SegmentInfo, error) {
+	segmentIDs := make([]int64, 0)
+	for _, partitionID := range partitionIDs {
+		resp, err := dc.GetSegmentsByStates(ctx, &datapb.GetSegmentsByStatesRequest{
+			CollectionID: collectionID,
+			PartitionID:  partitionID,
+			States: []commonpb.SegmentState{
+				commonpb.SegmentState_Growing,
+				commonpb.SegmentState_Sealed,
+				commonpb.SegmentState_Flushing,
+				commonpb.SegmentState_Flushed,
+			},
+		})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return nil, err
+		}
+		segmentIDs = append(segmentIDs, resp.GetSegments()...)
+	}
+	if len(segmentIDs) == 0 {
+		return nil, nil
+	}
+
+	resp, err := dc.GetSegmentInfo(ctx, &datapb.GetSegmentInfoRequest{
+		SegmentIDs: segmentIDs,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	return resp.GetInfos(), nil
+}
+
+// getSegmentIndexInfos returns the finished index files of the flushed segments.
+func getSegmentIndexInfos(ctx context.Context, dc types.DataCoordClient, collectionID UniqueID, segments []*datapb.SegmentInfo) (map[int64]*indexpb.SegmentInfo, error) {
+	flushed := lo.FilterMap(segments, func(segment *datapb.SegmentInfo, _ int) (int64, bool) {
+		return segment.GetID(), segment.GetState() == commonpb.SegmentState_Flushed
+	})
+	if len(flushed) == 0 {
+		return nil, nil
+	}
+	resp, err := dc.GetIndexInfos(ctx, &indexpb.GetIndexInfoRequest{
+		CollectionID: collectionID,
+		SegmentIDs:   flushed,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	return resp.GetSegmentInfo(), nil
+}
+
+// binlogsSize returns the total size of the binlog files.
+func binlogsSize(fieldBinlogs []*datapb.FieldBinlog) int64 {
+	var size int64
+	for _, fieldBinlog := range fieldBinlogs {
+		for _, binlog := range fieldBinlog.GetBinlogs() {
+			size += binlog.GetLogSize()
+		}
+	}
+	return size
+}
+
+func breakdownState(segment *datapb.SegmentInfo, indexInfos map[int64]*indexpb.SegmentInfo) string {
+	switch segment.GetState() {
+	case commonpb.SegmentState_Growing:
+		return breakdownStateGrowing
+	case commonpb.SegmentState_Sealed, commonpb.SegmentState_Flushing:
+		return breakdownStateSealed
+	default:
+		if len(indexInfos[segment.GetID()].GetIndexInfos()) > 0 {
+			return breakdownStateIndexed
+		}
+		return breakdownStateFlushed
+	}
+}
+
+// getStatisticsBreakdown aggregates the row count and binlog size of the segments by partition and segment state.
+// All the partitions of the collection are returned if no partition name is specified.
+func getStatisticsBreakdown(ctx context.Context, dc types.DataCoordClient, dbName, collectionName string, partitionNames []string) ([]*partitionBreakdown, error) {
+	collectionID, err := globalMetaCache.GetCollectionID(ctx, dbName, collectionName)
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := globalMetaCache.GetPartitions(ctx, dbName, collectionName)
+	if err != nil {
+		return nil, err
+	}
+	if len(partitionNames) > 0 {
+		for _, name := range partitionNames {
+			if _, ok := partitions[name]; !ok {
+				return nil, merr.WrapErrPartitionNotFound(name)
+			}
+		}
+		partitions = lo.PickByKeys(partitions, partitionNames)
+	}
+
+	breakdowns := make(map[int64]*partitionBreakdown, len(partitions))
+	for name, id := range partitions {
+		breakdowns[id] = &partitionBreakdown{
+			PartitionName: name,
+			PartitionID:   id,
+			States:        make(map[string]*segmentStateBreakdown),
+		}
+	}
+
+	segments, err := getSegmentInfos(ctx, dc, collectionID, lo.Values(partitions))
+	if err != nil {
+		return nil, err
+	}
+	indexInfos, err := getSegmentIndexInfos(ctx, dc, collectionID, segments)
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range segments {
+		partition, ok := breakdowns[segment.GetPartitionID()]
+		if !ok {
+			continue
+		}
+		size := binlogsSize(segment.GetBinlogs()) + binlogsSize(segment.GetDeltalogs())
+		state := breakdownState(segment, indexInfos)
+		if _, ok := partition.States[state]; !ok {
+			partition.States[state] = &segmentStateBreakdown{}
+		}
+		partition.States[state].NumSegments++
+		partition.States[state].RowCount += segment.GetNumOfRows()
+		partition.States[state].Size += size
+		partition.RowCount += segment.GetNumOfRows()
+		partition.Size += size
+	}
+
+	result := lo.Values(breakdowns)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PartitionID < result[j].PartitionID
+	})
+	return result, nil
+}
+
+// withStatisticsBreakdown attaches the json of the breakdown to the statistics.
+func withStatisticsBreakdown(stats []*commonpb.KeyValuePair, breakdown []*partitionBreakdown) ([]*commonpb.KeyValuePair, error) {
+	bs, err := json.Marshal(breakdown)
+	if err != nil {
+		return nil, err
+	}
+	return append(stats, &commonpb.KeyValuePair{Key: statisticsBreakdownStatKey, Value: string(bs)}), nil
+}
@@ -0,0 +1,90 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestGetStatisticsBreakdown(t *testing.T) {
+	ctx := context.Background()
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetCollectionID(mock.Anything, mock.Anything, mock.Anything).Return(1, nil)
+	mockCache.EXPECT().GetPartitions(mock.Anything, mock.Anything, mock.Anything).Return(map[string]int64{"_default": 10, "p1": 11}, nil)
+	globalMetaCache = mockCache
+
+	binlogs := func(size int64) []*datapb.FieldBinlog {
+		return []*datapb.FieldBinlog{{Binlogs: []*datapb.Binlog{{LogSize: size}}}}
+	}
+	dc := mocks.NewMockDataCoordClient(t)
+	dc.EXPECT().GetSegmentsByStates(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, req *datapb.GetSegmentsByStatesRequest, opts ...grpc.CallOption) (*datapb.GetSegmentsByStatesResponse, error) {
+			if req.GetPartitionID() == 10 {
+				return &datapb.GetSegmentsByStatesResponse{Status: merr.Success(), Segments: []int64{100, 101, 102}}, nil
+			}
+			return &datapb.GetSegmentsByStatesResponse{Status: merr.Success()}, nil
+		})
+	dc.EXPECT().GetSegmentInfo(mock.Anything, mock.Anything).Return(&datapb.GetSegmentInfoResponse{
+		Status: merr.Success(),
+		Infos: []*datapb.SegmentInfo{
+			{ID: 100, PartitionID: 10, State: commonpb.SegmentState_Growing, NumOfRows: 10},
+			{ID: 101, PartitionID: 10, State: commonpb.SegmentState_Flushed, NumOfRows: 100, Binlogs: binlogs(1000), Deltalogs: binlogs(10)},
+			{ID: 102, PartitionID: 10, State: commonpb.SegmentState_Flushed, NumOfRows: 200, Binlogs: binlogs(2000)},
+		},
+	}, nil)
+	dc.EXPECT().GetIndexInfos(mock.Anything, mock.Anything).Return(&indexpb.GetIndexInfoResponse{
+		Status: merr.Success(),
+		SegmentInfo: map[int64]*indexpb.SegmentInfo{
+			101: {},
+			102: {IndexInfos: []*indexpb.IndexFilePathInfo{{IndexID: 1}}},
+		},
+	}, nil)
+
+	breakdown, err := getStatisticsBreakdown(ctx, dc, "", "coll", nil)
+	assert.NoError(t, err)
+	assert.Len(t, breakdown, 2)
+	assert.Equal(t, "_default", breakdown[0].PartitionName)
+	assert.Equal(t, int64(310), breakdown[0].RowCount)
+	assert.Equal(t, int64(3010), breakdown[0].Size)
+	assert.Equal(t, &segmentStateBreakdown{NumSegments: 1, RowCount: 10}, breakdown[0].States[breakdownStateGrowing])
+	assert.Equal(t, &segmentStateBreakdown{NumSegments: 1, RowCount: 100, Size: 1010}, breakdown[0].States[breakdownStateFlushed])
+	assert.Equal(t, &segmentStateBreakdown{NumSegments: 1, RowCount: 200, Size: 2000}, breakdown[0].States[breakdownStateIndexed])
+	assert.Empty(t, breakdown[1].States)
+
+	stats, err := withStatisticsBreakdown(nil, breakdown)
+	assert.NoError(t, err)
+	var decoded []*partitionBreakdown
+	assert.NoError(t, json.Unmarshal([]byte(stats[0].GetValue()), &decoded))
+	assert.Equal(t, breakdown, decoded)
+
+	_, err = getStatisticsBreakdown(ctx, dc, "", "coll", []string{"p2"})
+	assert.ErrorIs(t, err, merr.ErrPartitionNotFound)
+}
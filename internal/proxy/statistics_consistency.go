@@ -0,0 +1,112 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// statisticsConsistencyKey is the request metadata key to choose how the collection or partition statistics are computed.
+const statisticsConsistencyKey = "statistics_consistency"
+
+const (
+	// statisticsConsistencyFast returns the row count in the dataCoord view, which may lag behind the recent mutations
+	// and does not exclude the deleted rows.
+	statisticsConsistencyFast = "fast"
+	// statisticsConsistencyAccurate counts the rows on the querynodes, including the growing segments not flushed yet,
+	// with all the mutations before the returned timestamp applied.
+	statisticsConsistencyAccurate = "accurate"
+)
+
+const (
+	statisticsRowCountKey    = "row_count"
+	statisticsConsistencyTag = "consistency"
+	statisticsTimestampKey   = "timestamp"
+)
+
+// getStatisticsConsistency returns the statistics consistency in the request metadata, empty if not set.
+func getStatisticsConsistency(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	values := md.Get(statisticsConsistencyKey)
+	if len(values) == 0 {
+		return "", nil
+	}
+	switch values[0] {
+	case statisticsConsistencyFast, statisticsConsistencyAccurate:
+		return values[0], nil
+	default:
+		return "", merr.WrapErrParameterInvalid("fast or accurate", values[0], "invalid statistics consistency")
+	}
+}
+
+// withStatisticsTimestamp attaches the consistency and the timestamp the statistics are computed at.
+func withStatisticsTimestamp(stats []*commonpb.KeyValuePair, consistency string, ts Timestamp) []*commonpb.KeyValuePair {
+	return append(stats,
+		&commonpb.KeyValuePair{Key: statisticsConsistencyTag, Value: consistency},
+		&commonpb.KeyValuePair{Key: statisticsTimestampKey, Value: strconv.FormatUint(ts, 10)},
+	)
+}
+
+// getAccurateStatistics counts the rows of the loaded collection or partitions by a count query,
+// with a newly allocated timestamp as the guarantee timestamp.
+func (node *Proxy) getAccurateStatistics(ctx context.Context, dbName, collectionName string, partitionNames []string) ([]*commonpb.KeyValuePair, error) {
+	loadState, err := node.GetLoadState(ctx, &milvuspb.GetLoadStateRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		PartitionNames: partitionNames,
+	})
+	if err := merr.CheckRPCCall(loadState, err); err != nil {
+		return nil, err
+	}
+	if loadState.GetState() != commonpb.LoadState_LoadStateLoaded {
+		return nil, merr.WrapErrCollectionNotLoaded(collectionName, "accurate statistics are only available for loaded collections and partitions")
+	}
+
+	ts, err := node.tsoAllocator.AllocOne(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// internal request, neither mirrored nor routed to the canary collection
+	resp, err := node.Query(context.WithValue(ctx, mirroredRequestKey{}, true), &milvuspb.QueryRequest{
+		DbName:             dbName,
+		CollectionName:     collectionName,
+		PartitionNames:     partitionNames,
+		OutputFields:       []string{"count(*)"},
+		GuaranteeTimestamp: ts,
+		ConsistencyLevel:   commonpb.ConsistencyLevel_Customized,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	if len(resp.GetFieldsData()) == 0 || len(resp.GetFieldsData()[0].GetScalars().GetLongData().GetData()) == 0 {
+		return nil, merr.WrapErrServiceInternal("no count in the query result")
+	}
+	counts := resp.GetFieldsData()[0].GetScalars().GetLongData().GetData()
+
+	stats := []*commonpb.KeyValuePair{{Key: statisticsRowCountKey, Value: strconv.FormatInt(counts[0], 10)}}
+	return withStatisticsTimestamp(stats, statisticsConsistencyAccurate, ts), nil
+}
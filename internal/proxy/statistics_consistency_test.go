@@ -0,0 +1,59 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestGetStatisticsConsistency(t *testing.T) {
+	consistency, err := getStatisticsConsistency(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, consistency)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(statisticsConsistencyKey, statisticsConsistencyAccurate))
+	consistency, err = getStatisticsConsistency(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, statisticsConsistencyAccurate, consistency)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(statisticsConsistencyKey, "strong"))
+	_, err = getStatisticsConsistency(ctx)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestWithStatisticsTimestamp(t *testing.T) {
+	stats := withStatisticsTimestamp([]*commonpb.KeyValuePair{{Key: statisticsRowCountKey, Value: "10"}}, statisticsConsistencyFast, 100)
+	assert.Equal(t, []*commonpb.KeyValuePair{
+		{Key: statisticsRowCountKey, Value: "10"},
+		{Key: statisticsConsistencyTag, Value: statisticsConsistencyFast},
+		{Key: statisticsTimestampKey, Value: "100"},
+	}, stats)
+}
+
+func TestProxy_GetAccurateStatistics(t *testing.T) {
+	node := &Proxy{}
+	node.UpdateStateCode(commonpb.StateCode_Abnormal)
+	_, err := node.getAccurateStatistics(context.Background(), "", "coll", nil)
+	assert.ErrorIs(t, err, merr.ErrServiceNotReady)
+}
@@ -0,0 +1,127 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"sort"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// CollectionStorageInfoRequest is the request to get the storage usage of the collection.
+type CollectionStorageInfoRequest struct {
+	DbName         string `json:"db_name"`
+	CollectionName string `json:"collection_name"`
+}
+
+// StorageUsage is the total size of the files in the object storage, in bytes.
+type StorageUsage struct {
+	BinlogSize   int64 `json:"binlog_size"`
+	DeltalogSize int64 `json:"deltalog_size"`
+	StatslogSize int64 `json:"statslog_size"`
+	IndexSize    int64 `json:"index_size"`
+}
+
+func (u *StorageUsage) add(other StorageUsage) {
+	u.BinlogSize += other.BinlogSize
+	u.DeltalogSize += other.DeltalogSize
+	u.StatslogSize += other.StatslogSize
+	u.IndexSize += other.IndexSize
+}
+
+// PartitionStorageInfo is the storage usage of the partition.
+type PartitionStorageInfo struct {
+	PartitionName string `json:"partition_name"`
+	PartitionID   int64  `json:"partition_id"`
+	StorageUsage
+}
+
+// CollectionStorageInfo is the storage usage of the collection and its partitions.
+type CollectionStorageInfo struct {
+	CollectionName string `json:"collection_name"`
+	CollectionID   int64  `json:"collection_id"`
+	StorageUsage
+	Partitions []*PartitionStorageInfo `json:"partitions"`
+}
+
+// getCollectionStorageInfo aggregates the sizes of the binlog, deltalog, statslog and index files
+// of the healthy segments from dataCoord, by partition.
+func (node *Proxy) getCollectionStorageInfo(ctx context.Context, request *CollectionStorageInfoRequest) (*CollectionStorageInfo, error) {
+	if request.CollectionName == "" {
+		return nil, merr.WrapErrParameterMissing("collection_name")
+	}
+	statsReq := &milvuspb.GetCollectionStatisticsRequest{
+		DbName:         request.DbName,
+		CollectionName: request.CollectionName,
+	}
+	if err := checkMgrPrivilege(ctx, statsReq); err != nil {
+		return nil, err
+	}
+	collectionID, err := globalMetaCache.GetCollectionID(ctx, request.DbName, request.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := globalMetaCache.GetPartitions(ctx, request.DbName, request.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make(map[int64]*PartitionStorageInfo, len(partitions))
+	for name, id := range partitions {
+		infos[id] = &PartitionStorageInfo{PartitionName: name, PartitionID: id}
+	}
+
+	segments, err := getSegmentInfos(ctx, node.dataCoord, collectionID, lo.Values(partitions))
+	if err != nil {
+		return nil, err
+	}
+	indexInfos, err := getSegmentIndexInfos(ctx, node.dataCoord, collectionID, segments)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CollectionStorageInfo{
+		CollectionName: request.CollectionName,
+		CollectionID:   collectionID,
+	}
+	for _, segment := range segments {
+		partition, ok := infos[segment.GetPartitionID()]
+		if !ok {
+			continue
+		}
+		usage := StorageUsage{
+			BinlogSize:   binlogsSize(segment.GetBinlogs()),
+			DeltalogSize: binlogsSize(segment.GetDeltalogs()),
+			StatslogSize: binlogsSize(segment.GetStatslogs()),
+		}
+		for _, indexInfo := range indexInfos[segment.GetID()].GetIndexInfos() {
+			usage.IndexSize += int64(indexInfo.GetSerializedSize())
+		}
+		partition.add(usage)
+		result.add(usage)
+	}
+
+	result.Partitions = lo.Values(infos)
+	sort.Slice(result.Partitions, func(i, j int) bool {
+		return result.Partitions[i].PartitionID < result.Partitions[j].PartitionID
+	})
+	return result, nil
+}
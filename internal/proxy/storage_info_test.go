@@ -0,0 +1,82 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestGetCollectionStorageInfo(t *testing.T) {
+	ctx := context.Background()
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetCollectionID(mock.Anything, mock.Anything, mock.Anything).Return(1, nil)
+	mockCache.EXPECT().GetPartitions(mock.Anything, mock.Anything, mock.Anything).Return(map[string]int64{"_default": 10, "p1": 11}, nil)
+	globalMetaCache = mockCache
+
+	binlogs := func(size int64) []*datapb.FieldBinlog {
+		return []*datapb.FieldBinlog{{Binlogs: []*datapb.Binlog{{LogSize: size}}}}
+	}
+	dc := mocks.NewMockDataCoordClient(t)
+	dc.EXPECT().GetSegmentsByStates(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, req *datapb.GetSegmentsByStatesRequest, opts ...grpc.CallOption) (*datapb.GetSegmentsByStatesResponse, error) {
+			if req.GetPartitionID() == 10 {
+				return &datapb.GetSegmentsByStatesResponse{Status: merr.Success(), Segments: []int64{100, 101}}, nil
+			}
+			return &datapb.GetSegmentsByStatesResponse{Status: merr.Success(), Segments: []int64{102}}, nil
+		})
+	dc.EXPECT().GetSegmentInfo(mock.Anything, mock.Anything).Return(&datapb.GetSegmentInfoResponse{
+		Status: merr.Success(),
+		Infos: []*datapb.SegmentInfo{
+			{ID: 100, PartitionID: 10, State: commonpb.SegmentState_Growing},
+			{ID: 101, PartitionID: 10, State: commonpb.SegmentState_Flushed, Binlogs: binlogs(1000), Deltalogs: binlogs(10), Statslogs: binlogs(1)},
+			{ID: 102, PartitionID: 11, State: commonpb.SegmentState_Flushed, Binlogs: binlogs(2000)},
+		},
+	}, nil)
+	dc.EXPECT().GetIndexInfos(mock.Anything, mock.Anything).Return(&indexpb.GetIndexInfoResponse{
+		Status: merr.Success(),
+		SegmentInfo: map[int64]*indexpb.SegmentInfo{
+			101: {IndexInfos: []*indexpb.IndexFilePathInfo{{IndexID: 1, SerializedSize: 500}}},
+			102: {IndexInfos: []*indexpb.IndexFilePathInfo{{IndexID: 1, SerializedSize: 800}, {IndexID: 2, SerializedSize: 200}}},
+		},
+	}, nil)
+
+	node := &Proxy{dataCoord: dc}
+	info, err := node.getCollectionStorageInfo(ctx, &CollectionStorageInfoRequest{CollectionName: "coll"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), info.CollectionID)
+	assert.Equal(t, StorageUsage{BinlogSize: 3000, DeltalogSize: 10, StatslogSize: 1, IndexSize: 1500}, info.StorageUsage)
+	assert.Len(t, info.Partitions, 2)
+	assert.Equal(t, "_default", info.Partitions[0].PartitionName)
+	assert.Equal(t, StorageUsage{BinlogSize: 1000, DeltalogSize: 10, StatslogSize: 1, IndexSize: 500}, info.Partitions[0].StorageUsage)
+	assert.Equal(t, StorageUsage{BinlogSize: 2000, IndexSize: 1000}, info.Partitions[1].StorageUsage)
+
+	_, err = node.getCollectionStorageInfo(ctx, &CollectionStorageInfoRequest{})
+	assert.ErrorIs(t, err, merr.ErrParameterMissing)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strconv"
 
 	"github.com/cockroachdb/errors"
 	"github.com/golang/protobuf/proto"
@@ -38,6 +39,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
@@ -56,6 +58,28 @@ const (
 	RoundDecimalKey      = "round_decimal"
 	OffsetKey            = "offset"
 	LimitKey             = "limit"
+	SearchPresetKey      = "search_preset"
+	RerankKey            = "rerank"
+
+	// ConsistencyDiagnosticsKey in the search params reports the guarantee timestamp and the timestamps the shards
+	// are searched at in the extra info of the search result status.
+	ConsistencyDiagnosticsKey = "consistency_diagnostics"
+	// ExcludeImportingKey in the search params excludes the segments of the import jobs not committed yet.
+	ExcludeImportingKey = "exclude_importing"
+	// FilterStatsKey in the search params reports the rows of the shards kept by the filter in the extra info of the
+	// search result status, with the advice on the search params for the selective filters.
+	FilterStatsKey = "filter_stats"
+	// AllowSpillKey in the search or query params opts in to the oversized result spilled to the object storage,
+	// which is returned as the url to download in the extra info of the status, with no result data.
+	AllowSpillKey = "allow_spill"
+
+	EnrichCollectionKey   = "enrich_collection"
+	EnrichKeyFieldKey     = "enrich_key_field"
+	EnrichOutputFieldsKey = "enrich_output_fields"
+
+	// WithTotalCountKey in the query params also counts the rows matching the expr regardless of the limit and offset,
+	// returned in the extra info of the query result status.
+	WithTotalCountKey = "with_total_count"
 
 	InsertTaskName                = "InsertTask"
 	CreateCollectionTaskName      = "CreateCollectionTask"
@@ -118,6 +142,7 @@ type task interface {
 	WaitToFinish() error
 	Notify(err error)
 	CanSkipAllocTimestamp() bool
+	CanUseCachedTimestamp() bool
 }
 
 type baseTask struct{}
@@ -126,6 +151,11 @@ func (bt *baseTask) CanSkipAllocTimestamp() bool {
 	return false
 }
 
+// CanUseCachedTimestamp returns whether the task could use a recently allocated timestamp instead of a new one.
+func (bt *baseTask) CanUseCachedTimestamp() bool {
+	return false
+}
+
 type dmlTask interface {
 	task
 	setChannels() error
@@ -274,101 +304,137 @@ func (t *createCollectionTask) validateClusteringKey() error {
 	return nil
 }
 
-func (t *createCollectionTask) PreExecute(ctx context.Context) error {
-	t.Base.MsgType = commonpb.MsgType_CreateCollection
-	t.Base.SourceID = paramtable.GetNodeID()
-
-	t.schema = &schemapb.CollectionSchema{}
-	err := proto.Unmarshal(t.Schema, t.schema)
-	if err != nil {
-		return err
-	}
-	t.schema.AutoID = false
-
-	if t.ShardsNum > Params.ProxyCfg.MaxShardNum.GetAsInt32() {
-		return fmt.Errorf("maximum shards's number should be limited to %d", Params.ProxyCfg.MaxShardNum.GetAsInt())
-	}
+// schemaCheck is a named validation applied to the schema of the collection to be created.
+type schemaCheck struct {
+	name  string
+	field string
+	check func() error
+}
 
-	if len(t.schema.Fields) > Params.ProxyCfg.MaxFieldNum.GetAsInt() {
-		return fmt.Errorf("maximum field's number should be limited to %d", Params.ProxyCfg.MaxFieldNum.GetAsInt())
+// schemaChecks returns the validations of CreateCollection in order, the dry-run schema validation shares them.
+func (t *createCollectionTask) schemaChecks() []schemaCheck {
+	checks := []schemaCheck{
+		{name: "shard_num", check: func() error {
+			if t.ShardsNum > Params.ProxyCfg.MaxShardNum.GetAsInt32() {
+				return fmt.Errorf("maximum shards's number should be limited to %d", Params.ProxyCfg.MaxShardNum.GetAsInt())
+			}
+			return nil
+		}},
+		{name: "field_num", check: func() error {
+			if len(t.schema.Fields) > Params.ProxyCfg.MaxFieldNum.GetAsInt() {
+				return fmt.Errorf("maximum field's number should be limited to %d", Params.ProxyCfg.MaxFieldNum.GetAsInt())
+			}
+			return nil
+		}},
+		{name: "vector_field_num", check: func() error {
+			if len(typeutil.GetVectorFieldSchemas(t.schema)) > Params.ProxyCfg.MaxVectorFieldNum.GetAsInt() {
+				return fmt.Errorf("maximum vector field's number should be limited to %d", Params.ProxyCfg.MaxVectorFieldNum.GetAsInt())
+			}
+			return nil
+		}},
+		// validate collection name
+		{name: "collection_name", check: func() error { return validateCollectionName(t.schema.Name) }},
+		// validate whether field names duplicates
+		{name: "duplicated_field_name", check: func() error { return validateDuplicatedFieldName(t.schema.Fields) }},
+		// validate primary key definition
+		{name: "primary_key", check: func() error { return validatePrimaryKey(t.schema) }},
+		// validate dynamic field
+		{name: "dynamic_field", check: func() error { return validateDynamicField(t.schema) }},
+		// validate auto id definition
+		{name: "auto_id", check: func() error { return ValidateFieldAutoID(t.schema) }},
+		// validate field type definition
+		{name: "field_type", check: func() error { return validateFieldType(t.schema) }},
+		// validate partition key mode
+		{name: "partition_key", check: t.validatePartitionKey},
+		// validate clustering key
+		{name: "clustering_key", check: t.validateClusteringKey},
+		// validate field encryption metadata
+		{name: "field_encryption", check: func() error {
+			for _, field := range t.schema.Fields {
+				if err := validateFieldEncryption(field); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		// validate vector normalization
+		{name: "normalize", check: func() error { return validateNormalize(t.schema, t.GetProperties()) }},
+		// validate collection mmap
+		{name: "mmap", check: func() error { return validateMmapProperty(t.GetProperties()) }},
+		// validate composite primary key
+		{name: "composite_primary_key", check: func() error {
+			_, err := getCompositePrimaryKey(t.schema, t.GetProperties())
+			return err
+		}},
+		// validate auto id strategy
+		{name: "auto_id_strategy", check: func() error { return validateAutoIDStrategy(t.schema, t.GetProperties()) }},
+		// validate default output fields
+		{name: "default_output_fields", check: func() error { return validateDefaultOutputFields(t.schema, t.GetProperties()) }},
 	}
 
-	if len(typeutil.GetVectorFieldSchemas(t.schema)) > Params.ProxyCfg.MaxVectorFieldNum.GetAsInt() {
-		return fmt.Errorf("maximum vector field's number should be limited to %d", Params.ProxyCfg.MaxVectorFieldNum.GetAsInt())
+	for _, field := range t.schema.Fields {
+		field := field
+		checks = append(checks, schemaCheck{name: "field", field: field.GetName(), check: func() error {
+			return validateCollectionField(t.schema.Name, field)
+		}})
 	}
 
-	// validate collection name
-	if err := validateCollectionName(t.schema.Name); err != nil {
-		return err
-	}
+	checks = append(checks, schemaCheck{name: "multiple_vector_fields", check: func() error {
+		return validateMultipleVectorFields(t.schema)
+	}})
+	return checks
+}
 
-	// validate whether field names duplicates
-	if err := validateDuplicatedFieldName(t.schema.Fields); err != nil {
+// validateCollectionField validates the name and type parameters of a field.
+func validateCollectionField(collectionName string, field *schemapb.FieldSchema) error {
+	// validate field name
+	if err := validateFieldName(field.Name); err != nil {
 		return err
 	}
-
-	// validate primary key definition
-	if err := validatePrimaryKey(t.schema); err != nil {
-		return err
+	// validate dense vector field type parameters
+	if typeutil.IsVectorType(field.DataType) {
+		if err := validateDimension(field); err != nil {
+			return err
+		}
 	}
-
-	// validate dynamic field
-	if err := validateDynamicField(t.schema); err != nil {
-		return err
+	// valid max length per row parameters
+	// if max_length not specified, return error
+	if field.DataType == schemapb.DataType_VarChar ||
+		(field.GetDataType() == schemapb.DataType_Array && field.GetElementType() == schemapb.DataType_VarChar) {
+		if err := validateMaxLengthPerRow(collectionName, field); err != nil {
+			return err
+		}
 	}
-
-	// validate auto id definition
-	if err := ValidateFieldAutoID(t.schema); err != nil {
-		return err
+	// valid max capacity for array per row parameters
+	// if max_capacity not specified, return error
+	if field.DataType == schemapb.DataType_Array {
+		if err := validateMaxCapacityPerRow(collectionName, field); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	// validate field type definition
-	if err := validateFieldType(t.schema); err != nil {
-		return err
-	}
+func (t *createCollectionTask) PreExecute(ctx context.Context) error {
+	t.Base.MsgType = commonpb.MsgType_CreateCollection
+	t.Base.SourceID = paramtable.GetNodeID()
 
-	// validate partition key mode
-	if err := t.validatePartitionKey(); err != nil {
-		return err
+	var err error
+	if getSchemaRegistryRef(t.GetProperties()) != "" {
+		err = t.applyRegistrySchema(ctx)
+	} else {
+		t.schema = &schemapb.CollectionSchema{}
+		err = proto.Unmarshal(t.Schema, t.schema)
 	}
-
-	// validate clustering key
-	if err := t.validateClusteringKey(); err != nil {
+	if err != nil {
 		return err
 	}
+	t.schema.AutoID = false
 
-	for _, field := range t.schema.Fields {
-		// validate field name
-		if err := validateFieldName(field.Name); err != nil {
+	for _, c := range t.schemaChecks() {
+		if err := c.check(); err != nil {
 			return err
 		}
-		// validate dense vector field type parameters
-		if typeutil.IsVectorType(field.DataType) {
-			err = validateDimension(field)
-			if err != nil {
-				return err
-			}
-		}
-		// valid max length per row parameters
-		// if max_length not specified, return error
-		if field.DataType == schemapb.DataType_VarChar ||
-			(field.GetDataType() == schemapb.DataType_Array && field.GetElementType() == schemapb.DataType_VarChar) {
-			err = validateMaxLengthPerRow(t.schema.Name, field)
-			if err != nil {
-				return err
-			}
-		}
-		// valid max capacity for array per row parameters
-		// if max_capacity not specified, return error
-		if field.DataType == schemapb.DataType_Array {
-			if err = validateMaxCapacityPerRow(t.schema.Name, field); err != nil {
-				return err
-			}
-		}
-	}
-
-	if err := validateMultipleVectorFields(t.schema); err != nil {
-		return err
 	}
 
 	t.CreateCollectionRequest.Schema, err = proto.Marshal(t.schema)
@@ -380,7 +446,21 @@ func (t *createCollectionTask) PreExecute(ctx context.Context) error {
 }
 
 func (t *createCollectionTask) Execute(ctx context.Context) error {
+	if isConfirmedByMetadata(ctx, ifNotExistsKey) {
+		exists, err := t.checkExistingCollection(ctx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			t.result = merr.Success()
+			return nil
+		}
+	}
+
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.rootCoord.CreateCollection(ctx, t.CreateCollectionRequest)
 	return err
 }
@@ -450,7 +530,16 @@ func (t *dropCollectionTask) PreExecute(ctx context.Context) error {
 }
 
 func (t *dropCollectionTask) Execute(ctx context.Context) error {
+	if globalCollectionRecycleBin.Enabled() {
+		err := globalCollectionRecycleBin.Recycle(ctx, t.GetDbName(), t.GetCollectionName())
+		t.result = merr.Status(err)
+		return err
+	}
+
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.rootCoord.DropCollection(ctx, t.DropCollectionRequest)
 	return err
 }
@@ -517,6 +606,9 @@ func (t *hasCollectionTask) PreExecute(ctx context.Context) error {
 
 func (t *hasCollectionTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.rootCoord.HasCollection(ctx, t.HasCollectionRequest)
 	if err != nil {
 		return err
@@ -608,6 +700,9 @@ func (t *describeCollectionTask) Execute(ctx context.Context) error {
 		DbName:               t.GetDbName(),
 	}
 
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	result, err := t.rootCoord.DescribeCollection(ctx, t.DescribeCollectionRequest)
 	if err != nil {
 		return err
@@ -734,6 +829,9 @@ func (t *showCollectionsTask) PreExecute(ctx context.Context) error {
 
 func (t *showCollectionsTask) Execute(ctx context.Context) error {
 	ctx = AppendUserInfoForRPC(ctx)
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	respFromRootCoord, err := t.rootCoord.ShowCollections(ctx, t.ShowCollectionsRequest)
 	if err != nil {
 		return err
@@ -765,6 +863,9 @@ func (t *showCollectionsTask) Execute(ctx context.Context) error {
 			IDs2Names[collectionID] = collectionName
 		}
 
+		if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+			return err
+		}
 		resp, err := t.queryCoord.ShowCollections(ctx, &querypb.ShowCollectionsRequest{
 			Base: commonpbutil.UpdateMsgBase(
 				t.Base,
@@ -823,6 +924,7 @@ func (t *showCollectionsTask) Execute(ctx context.Context) error {
 		}
 	} else {
 		t.result = respFromRootCoord
+		filterRecycledCollections(t.result)
 	}
 
 	return nil
@@ -919,11 +1021,51 @@ func (t *alterCollectionTask) PreExecute(ctx context.Context) error {
 		}
 	}
 
+	for _, p := range t.Properties {
+		if p.GetKey() != common.CollectionReadOnlyKey && p.GetKey() != common.NormalizeKey && p.GetKey() != common.MmapEnabledKey {
+			continue
+		}
+		if _, err := strconv.ParseBool(p.GetValue()); err != nil {
+			return merr.WrapErrParameterInvalidMsg("invalid %s value %s, only true or false is allowed", p.GetKey(), p.GetValue())
+		}
+	}
+
+	if _, err := funcutil.GetAttrByKeyFromRepeatedKV(common.CollectionDefaultOutputFieldsKey, t.Properties); err == nil {
+		schema, err := globalMetaCache.GetCollectionSchema(ctx, t.GetDbName(), t.CollectionName)
+		if err != nil {
+			return err
+		}
+		if err := validateDefaultOutputFields(schema.CollectionSchema, t.Properties); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range []string{common.CollectionCompositePrimaryKeyKey, common.CollectionAutoIDStrategyKey, common.CollectionAutoIDSnowflakeEpochKey} {
+		if _, err := funcutil.GetAttrByKeyFromRepeatedKV(key, t.Properties); err == nil {
+			return merr.WrapErrParameterInvalidMsg("%s can only be set on creating the collection", key)
+		}
+	}
+
+	if err := validateSearchPresets(t.Properties); err != nil {
+		return err
+	}
+
+	if err := validateMirrorConfig(t.CollectionName, t.Properties); err != nil {
+		return err
+	}
+
+	if err := validateCanaryConfig(t.CollectionName, t.Properties); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (t *alterCollectionTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.rootCoord.AlterCollection(ctx, t.AlterCollectionRequest)
 	return err
 }
@@ -1006,6 +1148,13 @@ func (t *createPartitionTask) PreExecute(ctx context.Context) error {
 }
 
 func (t *createPartitionTask) Execute(ctx context.Context) (err error) {
+	if isConfirmedByMetadata(ctx, ifNotExistsKey) && t.checkExistingPartition(ctx) {
+		t.result = merr.Success()
+		return nil
+	}
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.rootCoord.CreatePartition(ctx, t.CreatePartitionRequest)
 	if err != nil {
 		return err
@@ -1020,6 +1169,9 @@ func (t *createPartitionTask) PostExecute(ctx context.Context) error {
 	return nil
 }
 
+// forceDropPartitionKey is the request metadata key to release the loaded partition before dropping it.
+const forceDropPartitionKey = "force_drop_partition"
+
 type dropPartitionTask struct {
 	baseTask
 	Condition
@@ -1028,6 +1180,11 @@ type dropPartitionTask struct {
 	rootCoord  types.RootCoordClient
 	queryCoord types.QueryCoordClient
 	result     *commonpb.Status
+
+	// releaseFirst is set when the loaded partition is forced to drop, it will be released before dropping.
+	collectionID int64
+	partitionID  int64
+	releaseFirst bool
 }
 
 func (t *dropPartitionTask) TraceCtx() context.Context {
@@ -1113,7 +1270,13 @@ func (t *dropPartitionTask) PreExecute(ctx context.Context) error {
 			return err
 		}
 		if loaded {
-			return errors.New("partition cannot be dropped, partition is loaded, please release it first")
+			if !isConfirmedByMetadata(ctx, forceDropPartitionKey) {
+				return merr.WrapErrPartitionLoaded(partitionTag,
+					fmt.Sprintf("partition cannot be dropped, partition is loaded, please release it first or set %s to release it automatically", forceDropPartitionKey))
+			}
+			t.collectionID = collID
+			t.partitionID = partID
+			t.releaseFirst = true
 		}
 	}
 
@@ -1121,6 +1284,29 @@ func (t *dropPartitionTask) PreExecute(ctx context.Context) error {
 }
 
 func (t *dropPartitionTask) Execute(ctx context.Context) (err error) {
+	if t.releaseFirst {
+		log.Ctx(ctx).Info("release loaded partition before dropping",
+			zap.String("collection", t.GetCollectionName()),
+			zap.String("partition", t.GetPartitionName()))
+		if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+			return err
+		}
+		err = merr.CheckRPCCall(t.queryCoord.ReleasePartitions(ctx, &querypb.ReleasePartitionsRequest{
+			Base: commonpbutil.UpdateMsgBase(
+				t.Base,
+				commonpbutil.WithMsgType(commonpb.MsgType_ReleasePartitions),
+			),
+			CollectionID: t.collectionID,
+			PartitionIDs: []int64{t.partitionID},
+		}))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.rootCoord.DropPartition(ctx, t.DropPartitionRequest)
 	if err != nil {
 		return err
@@ -1198,6 +1384,9 @@ func (t *hasPartitionTask) PreExecute(ctx context.Context) error {
 }
 
 func (t *hasPartitionTask) Execute(ctx context.Context) (err error) {
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.rootCoord.HasPartition(ctx, t.HasPartitionRequest)
 	if err != nil {
 		return err
@@ -1279,6 +1468,9 @@ func (t *showPartitionsTask) PreExecute(ctx context.Context) error {
 }
 
 func (t *showPartitionsTask) Execute(ctx context.Context) error {
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	respFromRootCoord, err := t.rootCoord.ShowPartitions(ctx, t.ShowPartitionsRequest)
 	if err != nil {
 		return err
@@ -1316,6 +1508,9 @@ func (t *showPartitionsTask) Execute(ctx context.Context) error {
 			partitionIDs = append(partitionIDs, partitionID)
 			IDs2Names[partitionID] = partitionName
 		}
+		if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+			return err
+		}
 		resp, err := t.queryCoord.ShowPartitions(ctx, &querypb.ShowPartitionsRequest{
 			Base: commonpbutil.UpdateMsgBase(
 				t.Base,
@@ -1449,6 +1644,9 @@ func (t *flushTask) Execute(ctx context.Context) error {
 			),
 			CollectionID: collID,
 		}
+		if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+			return err
+		}
 		resp, err := t.dataCoord.Flush(ctx, flushReq)
 		if err != nil {
 			return fmt.Errorf("failed to call flush to data coordinator: %s", err.Error())
@@ -1484,6 +1682,7 @@ type loadCollectionTask struct {
 	Condition
 	*milvuspb.LoadCollectionRequest
 	ctx        context.Context
+	rootCoord  types.RootCoordClient
 	queryCoord types.QueryCoordClient
 	datacoord  types.DataCoordClient
 	result     *commonpb.Status
@@ -1548,6 +1747,10 @@ func (t *loadCollectionTask) PreExecute(ctx context.Context) error {
 		t.ReplicaNumber = 1
 	}
 
+	if _, err := getLoadMmap(ctx, t.GetBase()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -1568,6 +1771,19 @@ func (t *loadCollectionTask) Execute(ctx context.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	altered, err := applyLoadMmap(ctx, t.rootCoord, t.queryCoord, t.GetBase(), t.GetDbName(), t.CollectionName, collID, collSchema.GetProperties())
+	if err != nil {
+		return err
+	}
+	if altered {
+		collSchema, err = globalMetaCache.GetCollectionSchema(ctx, t.GetDbName(), t.CollectionName)
+		if err != nil {
+			return err
+		}
+	}
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	// check index
 	indexResponse, err := t.datacoord.DescribeIndex(ctx, &indexpb.DescribeIndexRequest{
 		CollectionID: collID,
@@ -1618,6 +1834,9 @@ func (t *loadCollectionTask) Execute(ctx context.Context) (err error) {
 	}
 	log.Debug("send LoadCollectionRequest to query coordinator",
 		zap.Any("schema", request.Schema))
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.queryCoord.LoadCollection(ctx, request)
 	if err != nil {
 		return fmt.Errorf("call query coordinator LoadCollection: %s", err)
@@ -1642,6 +1861,7 @@ type releaseCollectionTask struct {
 	Condition
 	*milvuspb.ReleaseCollectionRequest
 	ctx        context.Context
+	rootCoord  types.RootCoordClient
 	queryCoord types.QueryCoordClient
 	result     *commonpb.Status
 
@@ -1707,6 +1927,9 @@ func (t *releaseCollectionTask) Execute(ctx context.Context) (err error) {
 		return err
 	}
 	t.collectionID = collID
+	if err := checkRecentReads(ctx, t.rootCoord, t.CollectionName, collID); err != nil {
+		return err
+	}
 	request := &querypb.ReleaseCollectionRequest{
 		Base: commonpbutil.UpdateMsgBase(
 			t.Base,
@@ -1716,6 +1939,9 @@ func (t *releaseCollectionTask) Execute(ctx context.Context) (err error) {
 		CollectionID: collID,
 	}
 
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.queryCoord.ReleaseCollection(ctx, request)
 	if err != nil {
 		return err
@@ -1735,6 +1961,7 @@ type loadPartitionsTask struct {
 	Condition
 	*milvuspb.LoadPartitionsRequest
 	ctx        context.Context
+	rootCoord  types.RootCoordClient
 	queryCoord types.QueryCoordClient
 	datacoord  types.DataCoordClient
 	result     *commonpb.Status
@@ -1800,6 +2027,10 @@ func (t *loadPartitionsTask) PreExecute(ctx context.Context) error {
 		return errors.New("disable load partitions if partition key mode is used")
 	}
 
+	if _, err := getLoadMmap(ctx, t.GetBase()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -1814,6 +2045,19 @@ func (t *loadPartitionsTask) Execute(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	altered, err := applyLoadMmap(ctx, t.rootCoord, t.queryCoord, t.GetBase(), t.GetDbName(), t.CollectionName, collID, collSchema.GetProperties())
+	if err != nil {
+		return err
+	}
+	if altered {
+		collSchema, err = globalMetaCache.GetCollectionSchema(ctx, t.GetDbName(), t.CollectionName)
+		if err != nil {
+			return err
+		}
+	}
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	// check index
 	indexResponse, err := t.datacoord.DescribeIndex(ctx, &indexpb.DescribeIndexRequest{
 		CollectionID: collID,
@@ -1868,6 +2112,9 @@ func (t *loadPartitionsTask) Execute(ctx context.Context) error {
 		Refresh:        t.Refresh,
 		ResourceGroups: t.ResourceGroups,
 	}
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.queryCoord.LoadPartitions(ctx, request)
 	if err != nil {
 		return err
@@ -1976,6 +2223,9 @@ func (t *releasePartitionsTask) Execute(ctx context.Context) (err error) {
 		CollectionID: collID,
 		PartitionIDs: partitionIDs,
 	}
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.queryCoord.ReleasePartitions(ctx, request)
 	if err != nil {
 		return err
@@ -2046,6 +2296,9 @@ func (t *CreateResourceGroupTask) PreExecute(ctx context.Context) error {
 
 func (t *CreateResourceGroupTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.queryCoord.CreateResourceGroup(ctx, t.CreateResourceGroupRequest)
 	return err
 }
@@ -2111,6 +2364,9 @@ func (t *UpdateResourceGroupsTask) PreExecute(ctx context.Context) error {
 
 func (t *UpdateResourceGroupsTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.queryCoord.UpdateResourceGroups(ctx, &querypb.UpdateResourceGroupsRequest{
 		Base:           t.UpdateResourceGroupsRequest.GetBase(),
 		ResourceGroups: t.UpdateResourceGroupsRequest.GetResourceGroups(),
@@ -2179,6 +2435,9 @@ func (t *DropResourceGroupTask) PreExecute(ctx context.Context) error {
 
 func (t *DropResourceGroupTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.queryCoord.DropResourceGroup(ctx, t.DropResourceGroupRequest)
 	return err
 }
@@ -2242,6 +2501,9 @@ func (t *DescribeResourceGroupTask) PreExecute(ctx context.Context) error {
 
 func (t *DescribeResourceGroupTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	resp, err := t.queryCoord.DescribeResourceGroup(ctx, &querypb.DescribeResourceGroupRequest{
 		ResourceGroup: t.ResourceGroup,
 	})
@@ -2368,6 +2630,9 @@ func (t *TransferNodeTask) PreExecute(ctx context.Context) error {
 
 func (t *TransferNodeTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.queryCoord.TransferNode(ctx, t.TransferNodeRequest)
 	return err
 }
@@ -2437,6 +2702,9 @@ func (t *TransferReplicaTask) Execute(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.queryCoord.TransferReplica(ctx, &querypb.TransferReplicaRequest{
 		SourceResourceGroup: t.SourceResourceGroup,
 		TargetResourceGroup: t.TargetResourceGroup,
@@ -2505,6 +2773,9 @@ func (t *ListResourceGroupsTask) PreExecute(ctx context.Context) error {
 
 func (t *ListResourceGroupsTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.queryCoord.ListResourceGroups(ctx, t.ListResourceGroupsRequest)
 	return err
 }
@@ -2577,6 +2848,9 @@ func (t *alterDatabaseTask) Execute(ctx context.Context) error {
 		DbId:       t.AlterDatabaseRequest.GetDbId(),
 		Properties: t.AlterDatabaseRequest.GetProperties(),
 	}
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.rootCoord.AlterDatabase(ctx, req)
 	return err
 }
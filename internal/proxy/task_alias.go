@@ -105,6 +105,9 @@ func (t *CreateAliasTask) PreExecute(ctx context.Context) error {
 // Execute defines the tual execution of create alias
 func (t *CreateAliasTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.rootCoord.CreateAlias(ctx, t.CreateAliasRequest)
 	return err
 }
@@ -179,6 +182,9 @@ func (t *DropAliasTask) PreExecute(ctx context.Context) error {
 
 func (t *DropAliasTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.rootCoord.DropAlias(ctx, t.DropAliasRequest)
 	return err
 }
@@ -256,6 +262,9 @@ func (t *AlterAliasTask) PreExecute(ctx context.Context) error {
 
 func (t *AlterAliasTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.rootCoord.AlterAlias(ctx, t.AlterAliasRequest)
 	return err
 }
@@ -324,6 +333,9 @@ func (a *DescribeAliasTask) PreExecute(ctx context.Context) error {
 
 func (a *DescribeAliasTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(a)); err != nil {
+		return err
+	}
 	a.result, err = a.rootCoord.DescribeAlias(ctx, a.DescribeAliasRequest)
 	return err
 }
@@ -394,6 +406,9 @@ func (a *ListAliasesTask) PreExecute(ctx context.Context) error {
 
 func (a *ListAliasesTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(a)); err != nil {
+		return err
+	}
 	a.result, err = a.rootCoord.ListAliases(ctx, a.ListAliasesRequest)
 	return err
 }
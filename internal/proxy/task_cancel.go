@@ -0,0 +1,188 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/contextutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// CancelRequestRequest is the request to cancel the in-flight tasks by the trace id or the msg id of the task.
+type CancelRequestRequest struct {
+	TraceID string `json:"trace_id"`
+	MsgID   int64  `json:"msg_id"`
+}
+
+// CancelRequestResult is the ids of the tasks canceled, the queued ones are canceled once scheduled.
+type CancelRequestResult struct {
+	Canceled []int64 `json:"canceled"`
+}
+
+func taskTraceID(t task) string {
+	traceID := trace.SpanFromContext(t.TraceCtx()).SpanContext().TraceID()
+	if !traceID.IsValid() {
+		return ""
+	}
+	return traceID.String()
+}
+
+// taskOwner returns the user issuing the task, empty if the request is not authenticated.
+func taskOwner(t task) string {
+	username, _ := contextutil.GetCurUserFromContext(t.TraceCtx())
+	return username
+}
+
+// cancelFilter matches the tasks of the trace id or the msg id, and of the owner if the owner is not empty.
+type cancelFilter struct {
+	traceID string
+	msgID   int64
+	owner   string
+}
+
+func (f cancelFilter) match(id UniqueID, traceID, owner string) bool {
+	if f.owner != "" && f.owner != owner {
+		return false
+	}
+	return id == f.msgID || (f.traceID != "" && traceID == f.traceID)
+}
+
+type runningTask struct {
+	traceID string
+	owner   string
+	cancel  context.CancelFunc
+}
+
+// taskCanceler tracks the cancel functions of the running tasks and the queued tasks canceled before being scheduled.
+type taskCanceler struct {
+	mu      sync.Mutex
+	running map[UniqueID]*runningTask
+	pending map[UniqueID]struct{}
+}
+
+func newTaskCanceler() *taskCanceler {
+	return &taskCanceler{
+		running: make(map[UniqueID]*runningTask),
+		pending: make(map[UniqueID]struct{}),
+	}
+}
+
+// register tracks the running task, it's canceled immediately if canceled while queued.
+func (c *taskCanceler) register(t task, cancel context.CancelFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.pending[t.ID()]; ok {
+		delete(c.pending, t.ID())
+		cancel()
+	}
+	c.running[t.ID()] = &runningTask{traceID: taskTraceID(t), owner: taskOwner(t), cancel: cancel}
+}
+
+func (c *taskCanceler) unregister(taskID UniqueID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.running, taskID)
+}
+
+// cancelRunning cancels the running tasks matched and returns their ids.
+func (c *taskCanceler) cancelRunning(filter cancelFilter) []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	canceled := make([]int64, 0)
+	for id, running := range c.running {
+		if filter.match(id, running.traceID, running.owner) {
+			running.cancel()
+			canceled = append(canceled, id)
+		}
+	}
+	return canceled
+}
+
+func (c *taskCanceler) cancelPending(taskID UniqueID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending[taskID] = struct{}{}
+}
+
+// cancelUnissuedTasks marks the queued tasks matched as canceled and returns their ids. The queue lock is held
+// until they are marked, so a task popped concurrently is always seen as canceled when registered.
+func (queue *baseTaskQueue) cancelUnissuedTasks(canceler *taskCanceler, filter cancelFilter) []int64 {
+	queue.utLock.RLock()
+	defer queue.utLock.RUnlock()
+	ids := make([]int64, 0)
+	for e := queue.unissuedTasks.Front(); e != nil; e = e.Next() {
+		t := e.Value.(task)
+		if filter.match(t.ID(), taskTraceID(t), taskOwner(t)) {
+			canceler.cancelPending(t.ID())
+			ids = append(ids, t.ID())
+		}
+	}
+	return ids
+}
+
+// cancelTasks cancels the running and queued tasks matched by the filter,
+// the context cancellation is propagated to the downstream calls of the running tasks.
+func (sched *taskScheduler) cancelTasks(filter cancelFilter) []int64 {
+	canceled := sched.canceler.cancelRunning(filter)
+	for _, queue := range []*baseTaskQueue{
+		sched.ddQueue.baseTaskQueue,
+		sched.dmQueue.baseTaskQueue,
+		sched.dqQueue.baseTaskQueue,
+		sched.dcQueue.baseTaskQueue,
+	} {
+		canceled = append(canceled, queue.cancelUnissuedTasks(sched.canceler, filter)...)
+	}
+	sort.Slice(canceled, func(i, j int) bool {
+		return canceled[i] < canceled[j]
+	})
+	return canceled
+}
+
+// cancelRequest cancels the in-flight tasks of the request in the scheduler. The tasks of the other users
+// are only canceled for the admin, they are left running as not found for the others.
+func (node *Proxy) cancelRequest(ctx context.Context, request *CancelRequestRequest) (*CancelRequestResult, error) {
+	if request.TraceID == "" && request.MsgID == 0 {
+		return nil, merr.WrapErrParameterMissing("trace_id or msg_id")
+	}
+	if node.sched == nil {
+		return nil, merr.WrapErrServiceNotReady(typeutil.ProxyRole, paramtable.GetNodeID(), "task scheduler not initialized")
+	}
+	filter := cancelFilter{traceID: request.TraceID, msgID: request.MsgID}
+	if err := checkMgrAdmin(ctx); err != nil {
+		username, err := contextutil.GetCurUserFromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		filter.owner = username
+	}
+	canceled := node.sched.cancelTasks(filter)
+	log.Ctx(ctx).Info("cancel in-flight request",
+		zap.String("traceID", request.TraceID),
+		zap.Int64("msgID", request.MsgID),
+		zap.String("owner", filter.owner),
+		zap.Int64s("canceled", canceled))
+	return &CancelRequestResult{Canceled: canceled}, nil
+}
@@ -0,0 +1,121 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestCancelRequest(t *testing.T) {
+	ctx := context.Background()
+	sched, err := newTaskScheduler(ctx, newMockTsoAllocator(), nil)
+	assert.NoError(t, err)
+	node := &Proxy{sched: sched}
+
+	_, err = node.cancelRequest(ctx, &CancelRequestRequest{})
+	assert.ErrorIs(t, err, merr.ErrParameterMissing)
+
+	t.Run("running task", func(t *testing.T) {
+		taskCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		sched.canceler.register(newTestCreateCollectionTask(ctx, 1), cancel)
+		defer sched.canceler.unregister(1)
+
+		result, err := node.cancelRequest(ctx, &CancelRequestRequest{MsgID: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1}, result.Canceled)
+		assert.ErrorIs(t, taskCtx.Err(), context.Canceled)
+	})
+
+	t.Run("queued task", func(t *testing.T) {
+		task := newTestCreateCollectionTask(ctx, 2)
+		assert.NoError(t, sched.ddQueue.addUnissuedTask(task))
+
+		result, err := node.cancelRequest(ctx, &CancelRequestRequest{MsgID: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{2}, result.Canceled)
+
+		// canceled once scheduled
+		sched.processTask(sched.scheduleDdTask(), sched.ddQueue)
+		assert.ErrorIs(t, task.WaitToFinish(), context.Canceled)
+		events := sched.events.list()
+		assert.Equal(t, taskEventCanceled, events[len(events)-1].Event)
+	})
+
+	t.Run("client context closed", func(t *testing.T) {
+		taskCtx, cancel := context.WithCancel(ctx)
+		task := newTestCreateCollectionTask(taskCtx, 3)
+		assert.NoError(t, sched.ddQueue.addUnissuedTask(task))
+		cancel()
+
+		sched.processTask(sched.scheduleDdTask(), sched.ddQueue)
+		events := sched.events.list()
+		assert.Equal(t, taskEventCanceled, events[len(events)-1].Event)
+		assert.Equal(t, taskStagePreExecute, events[len(events)-1].Stage)
+	})
+
+	t.Run("owner", func(t *testing.T) {
+		paramtable.Get().Save(Params.CommonCfg.AuthorizationEnabled.Key, "true")
+		defer paramtable.Get().Reset(Params.CommonCfg.AuthorizationEnabled.Key)
+		cache := globalMetaCache
+		defer func() { globalMetaCache = cache }()
+		mockCache := NewMockCache(t)
+		mockCache.EXPECT().GetUserRole(mock.Anything).Return(nil)
+		globalMetaCache = mockCache
+
+		taskCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		sched.canceler.register(newTestCreateCollectionTask(GetContext(ctx, "bob:pwd"), 5), cancel)
+		defer sched.canceler.unregister(5)
+		queued := newTestCreateCollectionTask(GetContext(ctx, "bob:pwd"), 6)
+		assert.NoError(t, sched.ddQueue.addUnissuedTask(queued))
+		defer sched.ddQueue.PopUnissuedTask()
+
+		// the tasks of the others are not found
+		result, err := node.cancelRequest(GetContext(ctx, "alice:pwd"), &CancelRequestRequest{MsgID: 5})
+		assert.NoError(t, err)
+		assert.Empty(t, result.Canceled)
+		assert.NoError(t, taskCtx.Err())
+		result, err = node.cancelRequest(GetContext(ctx, "alice:pwd"), &CancelRequestRequest{MsgID: 6})
+		assert.NoError(t, err)
+		assert.Empty(t, result.Canceled)
+
+		result, err = node.cancelRequest(GetContext(ctx, "bob:pwd"), &CancelRequestRequest{MsgID: 5})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{5}, result.Canceled)
+		assert.ErrorIs(t, taskCtx.Err(), context.Canceled)
+		result, err = node.cancelRequest(GetContext(ctx, "root:pwd"), &CancelRequestRequest{MsgID: 6})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{6}, result.Canceled)
+
+		_, err = node.cancelRequest(ctx, &CancelRequestRequest{MsgID: 6})
+		assert.Error(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		result, err := node.cancelRequest(ctx, &CancelRequestRequest{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736"})
+		assert.NoError(t, err)
+		assert.Empty(t, result.Canceled)
+	})
+}
@@ -69,6 +69,9 @@ func (cdt *createDatabaseTask) PreExecute(ctx context.Context) error {
 
 func (cdt *createDatabaseTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(cdt)); err != nil {
+		return err
+	}
 	cdt.result, err = cdt.rootCoord.CreateDatabase(ctx, cdt.CreateDatabaseRequest)
 	if cdt.result != nil && cdt.result.ErrorCode == commonpb.ErrorCode_Success {
 		SendReplicateMessagePack(ctx, cdt.replicateMsgStream, cdt.CreateDatabaseRequest)
@@ -138,6 +141,9 @@ func (ddt *dropDatabaseTask) PreExecute(ctx context.Context) error {
 
 func (ddt *dropDatabaseTask) Execute(ctx context.Context) error {
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(ddt)); err != nil {
+		return err
+	}
 	ddt.result, err = ddt.rootCoord.DropDatabase(ctx, ddt.DropDatabaseRequest)
 
 	if ddt.result != nil && ddt.result.ErrorCode == commonpb.ErrorCode_Success {
@@ -206,6 +212,9 @@ func (ldt *listDatabaseTask) PreExecute(ctx context.Context) error {
 func (ldt *listDatabaseTask) Execute(ctx context.Context) error {
 	var err error
 	ctx = AppendUserInfoForRPC(ctx)
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(ldt)); err != nil {
+		return err
+	}
 	ldt.result, err = ldt.rootCoord.ListDatabases(ctx, ldt.ListDatabasesRequest)
 	return err
 }
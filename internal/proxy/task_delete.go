@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 
 	"github.com/cockroachdb/errors"
 	"github.com/golang/protobuf/proto"
@@ -24,6 +25,7 @@ import (
 	"github.com/milvus-io/milvus/internal/util/exprutil"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
 	"github.com/milvus-io/milvus/pkg/util/merr"
@@ -63,6 +65,7 @@ type deleteTask struct {
 	// result
 	count       int64
 	allQueryCnt int64
+	positions   map[string]*msgpb.MsgPosition
 }
 
 func (dt *deleteTask) TraceCtx() context.Context {
@@ -180,11 +183,12 @@ func (dt *deleteTask) Execute(ctx context.Context) (err error) {
 		zap.Int64("taskID", dt.ID()),
 		zap.Duration("prepare duration", dt.tr.RecordSpan()))
 
-	err = stream.Produce(msgPack)
+	err = produceWithRetry(ctx, stream, msgPack, metrics.DeleteLabel)
 	if err != nil {
 		return err
 	}
 	dt.count += numRows
+	dt.positions = collectChannelPositions(msgPack.Msgs)
 	return nil
 }
 
@@ -249,6 +253,8 @@ type deleteRunner struct {
 	queue *dmTaskQueue
 
 	allQueryCnt atomic.Int64
+	// positions the delete messages are published at by vchannel
+	positions map[string]*msgpb.MsgPosition
 }
 
 func (dr *deleteRunner) Init(ctx context.Context) error {
@@ -268,6 +274,13 @@ func (dr *deleteRunner) Init(ctx context.Context) error {
 	if err != nil {
 		return ErrWithLog(log, "Failed to get collection schema", err)
 	}
+	if dr.schema.IsReadOnly() {
+		return ErrWithLog(log, "Delete from read-only collection", merr.WrapErrCollectionReadOnly(collName, "delete is not allowed"))
+	}
+	dr.req.Expr, err = expandCompositeKeyDelete(dr.schema, dr.req.GetExpr())
+	if err != nil {
+		return ErrWithLog(log, "Failed to expand the composite primary keys", err)
+	}
 
 	dr.partitionKeyMode = dr.schema.IsPartitionKeyCollection()
 	// get partitionIDs of delete
@@ -314,6 +327,10 @@ func (dr *deleteRunner) Run(ctx context.Context) error {
 		return merr.WrapErrParameterInvalidMsg("delete plan can't be empty or always true : %s", dr.req.GetExpr())
 	}
 
+	if err := checkFullScanDelete(ctx, dr.req, plan); err != nil {
+		return err
+	}
+
 	isSimple, pk, numRow := getPrimaryKeysFromPlan(dr.schema.CollectionSchema, plan)
 	if isSimple {
 		// if could get delete.primaryKeys from delete expr
@@ -437,6 +454,13 @@ func (dr *deleteRunner) getStreamingQueryAndDelteFunc(plan *planpb.PlanNode) exe
 				return err
 			}
 			dr.count.Add(task.count)
+			if task.ts > dr.result.Timestamp {
+				dr.result.Timestamp = task.ts
+			}
+			if dr.positions == nil {
+				dr.positions = make(map[string]*msgpb.MsgPosition)
+			}
+			mergeChannelPositions(dr.positions, task.positions)
 			allQueryCnt += task.allQueryCnt
 		}
 
@@ -533,10 +557,88 @@ func (dr *deleteRunner) simpleDelete(ctx context.Context, pk *schemapb.IDs, numR
 	err = task.WaitToFinish()
 	if err == nil {
 		dr.result.DeleteCnt = task.count
+		dr.result.Timestamp = task.ts
+		dr.positions = task.positions
 	}
 	return err
 }
 
+// allowFullScanDeleteKey is the request metadata key to confirm the delete which matches the entire collection.
+const allowFullScanDeleteKey = "allow_full_scan_delete"
+
+// checkFullScanDelete rejects the delete whose expression matches the entire primary key space without confirmation,
+// the confirmed ones are written into the audit log.
+func checkFullScanDelete(ctx context.Context, req *milvuspb.DeleteRequest, plan *planpb.PlanNode) error {
+	if !Params.ProxyCfg.FullScanDeleteGuardEnabled.GetAsBool() {
+		return nil
+	}
+	if !isFullPrimaryKeyRangeExpr(plan.GetQuery().GetPredicates()) {
+		return nil
+	}
+
+	if !isConfirmedByMetadata(ctx, allowFullScanDeleteKey) {
+		return merr.WrapErrParameterInvalidMsg("delete expression %s matches the entire collection, set %s to confirm",
+			req.GetExpr(), allowFullScanDeleteKey)
+	}
+	auditLog(ctx, auditFullScanDelete, req,
+		zap.String("user", GetCurUserFromContextOrDefault(ctx)),
+		zap.String("db", req.GetDbName()),
+		zap.String("collection", req.GetCollectionName()),
+		zap.String("partition", req.GetPartitionName()),
+		zap.String("expr", req.GetExpr()))
+	return nil
+}
+
+// isFullPrimaryKeyRangeExpr returns whether the expr matches the entire primary key space, like `pk >= 0`.
+// The primary keys are assumed to be non-negative, which is always true for auto id.
+func isFullPrimaryKeyRangeExpr(expr *planpb.Expr) bool {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_AlwaysTrueExpr:
+		return true
+	case *planpb.Expr_UnaryRangeExpr:
+		if !e.UnaryRangeExpr.GetColumnInfo().GetIsPrimaryKey() {
+			return false
+		}
+		value := e.UnaryRangeExpr.GetValue()
+		switch e.UnaryRangeExpr.GetOp() {
+		case planpb.OpType_GreaterEqual:
+			return isMinPrimaryKeyValue(value, true)
+		case planpb.OpType_GreaterThan:
+			return isMinPrimaryKeyValue(value, false)
+		case planpb.OpType_PrefixMatch:
+			return value.GetStringVal() == ""
+		}
+	case *planpb.Expr_BinaryRangeExpr:
+		if !e.BinaryRangeExpr.GetColumnInfo().GetIsPrimaryKey() {
+			return false
+		}
+		upper := e.BinaryRangeExpr.GetUpperValue()
+		return isMinPrimaryKeyValue(e.BinaryRangeExpr.GetLowerValue(), e.BinaryRangeExpr.GetLowerInclusive()) &&
+			upper.GetVal() != nil && upper.GetInt64Val() == math.MaxInt64 && e.BinaryRangeExpr.GetUpperInclusive()
+	case *planpb.Expr_BinaryExpr:
+		switch e.BinaryExpr.GetOp() {
+		case planpb.BinaryExpr_LogicalOr:
+			return isFullPrimaryKeyRangeExpr(e.BinaryExpr.GetLeft()) || isFullPrimaryKeyRangeExpr(e.BinaryExpr.GetRight())
+		case planpb.BinaryExpr_LogicalAnd:
+			return isFullPrimaryKeyRangeExpr(e.BinaryExpr.GetLeft()) && isFullPrimaryKeyRangeExpr(e.BinaryExpr.GetRight())
+		}
+	}
+	return false
+}
+
+// isMinPrimaryKeyValue returns whether the lower bound includes all the primary keys.
+func isMinPrimaryKeyValue(value *planpb.GenericValue, inclusive bool) bool {
+	switch v := value.GetVal().(type) {
+	case *planpb.GenericValue_Int64Val:
+		return v.Int64Val < 0 || (inclusive && v.Int64Val == 0)
+	case *planpb.GenericValue_FloatVal:
+		return v.FloatVal < 0 || (inclusive && v.FloatVal == 0)
+	case *planpb.GenericValue_StringVal:
+		return v.StringVal == "" && inclusive
+	}
+	return false
+}
+
 func getPrimaryKeysFromPlan(schema *schemapb.CollectionSchema, plan *planpb.PlanNode) (bool, *schemapb.IDs, int64) {
 	// simple delete request need expr with "pk in [a, b]"
 	termExpr, ok := plan.Node.(*planpb.PlanNode_Query).Query.Predicates.Expr.(*planpb.Expr_TermExpr)
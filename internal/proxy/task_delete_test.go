@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
@@ -108,6 +109,58 @@ func Test_getPrimaryKeysFromPlan(t *testing.T) {
 	})
 }
 
+func Test_checkFullScanDelete(t *testing.T) {
+	paramtable.Init()
+	collSchema := &schemapb.CollectionSchema{
+		Name: "test_delete",
+		Fields: []*schemapb.FieldSchema{
+			{
+				FieldID:      common.StartOfUserFieldID,
+				Name:         "pk",
+				IsPrimaryKey: true,
+				DataType:     schemapb.DataType_Int64,
+			},
+			{
+				FieldID:  common.StartOfUserFieldID + 1,
+				Name:     "non_pk",
+				DataType: schemapb.DataType_Int64,
+			},
+		},
+	}
+	schema, err := typeutil.CreateSchemaHelper(collSchema)
+	require.NoError(t, err)
+
+	cases := map[string]bool{
+		"pk >= 0":                  true,
+		"pk > -1":                  true,
+		"pk > 0":                   false,
+		"pk >= 0 and pk < 100":     false,
+		"pk >= 0 or non_pk > 10":   true,
+		"non_pk >= 0":              false,
+		"pk in [1, 2]":             false,
+		"pk >= 10 or non_pk >= 10": false,
+	}
+	for expr, full := range cases {
+		plan, err := planparserv2.CreateRetrievePlan(schema, expr)
+		require.NoError(t, err)
+		assert.Equal(t, full, isFullPrimaryKeyRangeExpr(plan.GetQuery().GetPredicates()), expr)
+	}
+
+	plan, err := planparserv2.CreateRetrievePlan(schema, "pk >= 0")
+	require.NoError(t, err)
+	req := &milvuspb.DeleteRequest{CollectionName: "test_delete", Expr: "pk >= 0"}
+
+	// guard disabled by default
+	assert.NoError(t, checkFullScanDelete(context.Background(), req, plan))
+
+	paramtable.Get().Save(Params.ProxyCfg.FullScanDeleteGuardEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.FullScanDeleteGuardEnabled.Key)
+	assert.ErrorIs(t, checkFullScanDelete(context.Background(), req, plan), merr.ErrParameterInvalid)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(allowFullScanDeleteKey, "true"))
+	assert.NoError(t, checkFullScanDelete(ctx, req, plan))
+}
+
 func TestDeleteTask_GetChannels(t *testing.T) {
 	collectionID := UniqueID(0)
 	collectionName := "col-0"
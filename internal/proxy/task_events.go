@@ -0,0 +1,148 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// the lifecycle events of the tasks in the scheduler.
+const (
+	taskEventEnqueued = "enqueued"
+	taskEventStarted  = "started"
+	taskEventFinished = "finished"
+	taskEventFailed   = "failed"
+	taskEventCanceled = "canceled"
+)
+
+// the stages a task may fail at.
+const (
+	taskStagePreExecute  = "pre_execute"
+	taskStageExecute     = "execute"
+	taskStagePostExecute = "post_execute"
+)
+
+type taskEvent struct {
+	Time     time.Time `json:"time"`
+	Event    string    `json:"event"`
+	TaskType string    `json:"task_type"`
+	TaskID   int64     `json:"task_id"`
+	BeginTs  uint64    `json:"begin_ts"`
+	Stage    string    `json:"stage,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	// QueueWaitMs is set for the started events, DurationMs for the finished, failed and canceled events.
+	QueueWaitMs int64 `json:"queue_wait_ms,omitempty"`
+	DurationMs  int64 `json:"duration_ms,omitempty"`
+}
+
+func newTaskEvent(t task, event string) *taskEvent {
+	return &taskEvent{
+		Time:     time.Now(),
+		Event:    event,
+		TaskType: t.Name(),
+		TaskID:   t.ID(),
+		BeginTs:  t.BeginTs(),
+	}
+}
+
+// newTaskDoneEvent returns the finished event if err is nil, otherwise the failed or canceled event
+// with the stage the task failed at.
+func newTaskDoneEvent(t task, stage string, err error, duration time.Duration) *taskEvent {
+	event := newTaskEvent(t, taskEventFinished)
+	event.DurationMs = duration.Milliseconds()
+	if err == nil {
+		return event
+	}
+	event.Event = taskEventFailed
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		event.Event = taskEventCanceled
+	}
+	event.Stage = stage
+	event.Error = err.Error()
+	return event
+}
+
+// taskEventBuffer is a ring buffer keeping the latest task events, all the methods are nil-safe.
+type taskEventBuffer struct {
+	mu     sync.Mutex
+	events []*taskEvent
+	next   int
+	full   bool
+}
+
+func newTaskEventBuffer(capacity int) *taskEventBuffer {
+	if capacity <= 0 {
+		return nil
+	}
+	return &taskEventBuffer{
+		events: make([]*taskEvent, capacity),
+	}
+}
+
+func (b *taskEventBuffer) record(event *taskEvent) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events[b.next] = event
+	b.next = (b.next + 1) % len(b.events)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// list returns the events in the buffer from the oldest to the latest.
+func (b *taskEventBuffer) list() []*taskEvent {
+	if b == nil {
+		return []*taskEvent{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.full {
+		return append([]*taskEvent{}, b.events[:b.next]...)
+	}
+	return append(append([]*taskEvent{}, b.events[b.next:]...), b.events[:b.next]...)
+}
+
+// getTaskEventsMetrics returns the json of the latest task events in the scheduler.
+func getTaskEventsMetrics(node *Proxy) (*milvuspb.GetMetricsResponse, error) {
+	events := []*taskEvent{}
+	if node.sched != nil {
+		events = node.sched.events.list()
+	}
+	bs, err := json.Marshal(events)
+	if err != nil {
+		return nil, err
+	}
+	return &milvuspb.GetMetricsResponse{
+		Status:        merr.Success(),
+		Response:      string(bs),
+		ComponentName: metricsinfo.ConstructComponentName(typeutil.ProxyRole, paramtable.GetNodeID()),
+	}, nil
+}
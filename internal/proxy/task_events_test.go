@@ -0,0 +1,111 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func newTestCreateCollectionTask(ctx context.Context, id int64) *createCollectionTask {
+	return &createCollectionTask{
+		ctx:       ctx,
+		Condition: NewTaskCondition(ctx),
+		CreateCollectionRequest: &milvuspb.CreateCollectionRequest{
+			Base: &commonpb.MsgBase{MsgID: id, MsgType: commonpb.MsgType_CreateCollection},
+		},
+	}
+}
+
+func TestTaskEventBuffer(t *testing.T) {
+	ctx := context.Background()
+	var nilBuffer *taskEventBuffer
+	nilBuffer.record(&taskEvent{})
+	assert.Empty(t, nilBuffer.list())
+	assert.Nil(t, newTaskEventBuffer(0))
+
+	buffer := newTaskEventBuffer(3)
+	assert.Empty(t, buffer.list())
+	for i := int64(1); i <= 2; i++ {
+		buffer.record(newTaskEvent(newTestCreateCollectionTask(ctx, i), taskEventEnqueued))
+	}
+	events := buffer.list()
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(1), events[0].TaskID)
+
+	for i := int64(3); i <= 5; i++ {
+		buffer.record(newTaskEvent(newTestCreateCollectionTask(ctx, i), taskEventEnqueued))
+	}
+	events = buffer.list()
+	assert.Len(t, events, 3)
+	assert.Equal(t, []int64{3, 4, 5}, []int64{events[0].TaskID, events[1].TaskID, events[2].TaskID})
+	assert.Equal(t, CreateCollectionTaskName, events[0].TaskType)
+}
+
+func TestNewTaskDoneEvent(t *testing.T) {
+	task := newTestCreateCollectionTask(context.Background(), 1)
+
+	event := newTaskDoneEvent(task, taskStagePostExecute, nil, time.Second)
+	assert.Equal(t, taskEventFinished, event.Event)
+	assert.Equal(t, int64(1000), event.DurationMs)
+	assert.Empty(t, event.Stage)
+
+	event = newTaskDoneEvent(task, taskStageExecute, merr.ErrCollectionNotFound, time.Second)
+	assert.Equal(t, taskEventFailed, event.Event)
+	assert.Equal(t, taskStageExecute, event.Stage)
+	assert.NotEmpty(t, event.Error)
+
+	event = newTaskDoneEvent(task, taskStagePreExecute, context.DeadlineExceeded, time.Second)
+	assert.Equal(t, taskEventCanceled, event.Event)
+}
+
+func TestTaskQueueEvents(t *testing.T) {
+	ctx := context.Background()
+	sched, err := newTaskScheduler(ctx, newMockTsoAllocator(), nil)
+	assert.NoError(t, err)
+
+	task := newTestCreateCollectionTask(ctx, 1)
+	assert.NoError(t, sched.ddQueue.addUnissuedTask(task))
+	assert.Equal(t, task, sched.ddQueue.PopUnissuedTask())
+	sched.ddQueue.AddActiveTask(task)
+	sched.ddQueue.PopActiveTask(task.ID())
+
+	events := sched.events.list()
+	assert.Len(t, events, 2)
+	assert.Equal(t, taskEventEnqueued, events[0].Event)
+	assert.Equal(t, taskEventStarted, events[1].Event)
+	assert.False(t, sched.ddQueue.enqueueTimes.Contain(task.ID()))
+
+	resp, err := getTaskEventsMetrics(&Proxy{sched: sched})
+	assert.NoError(t, err)
+	assert.True(t, merr.Ok(resp.GetStatus()))
+	var decoded []*taskEvent
+	assert.NoError(t, json.Unmarshal([]byte(resp.GetResponse()), &decoded))
+	assert.Len(t, decoded, 2)
+
+	resp, err = getTaskEventsMetrics(&Proxy{})
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", resp.GetResponse())
+}
@@ -19,6 +19,7 @@ package proxy
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 	"go.uber.org/zap"
@@ -240,6 +241,11 @@ func (cit *createIndexTask) parseIndexParams() error {
 			}
 		}
 
+		// metric type is case insensitive, e.g. cosine is accepted as COSINE
+		if metricType, ok := indexParamsMap[common.MetricTypeKey]; ok {
+			indexParamsMap[common.MetricTypeKey] = strings.ToUpper(metricType)
+		}
+
 		indexType, exist := indexParamsMap[common.IndexTypeKey]
 		if !exist {
 			return fmt.Errorf("IndexType not specified")
@@ -349,6 +355,11 @@ func checkTrain(field *schemapb.FieldSchema, indexParams map[string]string) erro
 		return err
 	}
 
+	if err := indexparamcheck.ValidateBuildParams(indexType, indexParams); err != nil {
+		log.Info("create index with invalid parameters", zap.Error(err))
+		return merr.WrapErrParameterInvalidMsg(err.Error())
+	}
+
 	if err := checker.CheckTrain(indexParams); err != nil {
 		log.Info("create index with invalid parameters", zap.Error(err))
 		return err
@@ -394,6 +405,17 @@ func (cit *createIndexTask) Execute(ctx context.Context) error {
 		zap.Any("newExtraParams", cit.newExtraParams),
 	)
 
+	if isConfirmedByMetadata(ctx, ifNotExistsKey) {
+		exists, err := cit.checkExistingIndex(ctx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			cit.result = merr.Success()
+			return nil
+		}
+	}
+
 	var err error
 	req := &indexpb.CreateIndexRequest{
 		CollectionID:    cit.collectionID,
@@ -405,6 +427,9 @@ func (cit *createIndexTask) Execute(ctx context.Context) error {
 		UserIndexParams: cit.newExtraParams,
 		Timestamp:       cit.BeginTs(),
 	}
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(cit)); err != nil {
+		return err
+	}
 	cit.result, err = cit.datacoord.CreateIndex(ctx, req)
 	if err != nil {
 		return err
@@ -525,6 +550,9 @@ func (t *alterIndexTask) Execute(ctx context.Context) error {
 		IndexName:    t.req.GetIndexName(),
 		Params:       t.req.GetExtraParams(),
 	}
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(t)); err != nil {
+		return err
+	}
 	t.result, err = t.datacoord.AlterIndex(ctx, req)
 	if err != nil {
 		return err
@@ -616,6 +644,9 @@ func (dit *describeIndexTask) Execute(ctx context.Context) error {
 		return fmt.Errorf("failed to parse collection schema: %s", err)
 	}
 
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(dit)); err != nil {
+		return err
+	}
 	resp, err := dit.datacoord.DescribeIndex(ctx, &indexpb.DescribeIndexRequest{CollectionID: dit.collectionID, IndexName: dit.IndexName, Timestamp: dit.Timestamp})
 	if err != nil {
 		return err
@@ -741,6 +772,9 @@ func (dit *getIndexStatisticsTask) Execute(ctx context.Context) error {
 		return fmt.Errorf("failed to parse collection schema: %s", dit.GetCollectionName())
 	}
 
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(dit)); err != nil {
+		return err
+	}
 	resp, err := dit.datacoord.GetIndexStatistics(ctx, &indexpb.GetIndexStatisticsRequest{
 		CollectionID: dit.collectionID, IndexName: dit.IndexName,
 	})
@@ -877,6 +911,9 @@ func (dit *dropIndexTask) Execute(ctx context.Context) error {
 	)
 
 	var err error
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(dit)); err != nil {
+		return err
+	}
 	dit.result, err = dit.dataCoord.DropIndex(ctx, &indexpb.DropIndexRequest{
 		CollectionID: dit.collectionID,
 		PartitionIDs: nil,
@@ -970,6 +1007,9 @@ func (gibpt *getIndexBuildProgressTask) Execute(ctx context.Context) error {
 	}
 	gibpt.collectionID = collectionID
 
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(gibpt)); err != nil {
+		return err
+	}
 	resp, err := gibpt.dataCoord.GetIndexBuildProgress(ctx, &indexpb.GetIndexBuildProgressRequest{
 		CollectionID: collectionID,
 		IndexName:    gibpt.IndexName,
@@ -1058,6 +1098,9 @@ func (gist *getIndexStateTask) Execute(ctx context.Context) error {
 		return err
 	}
 
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(gist)); err != nil {
+		return err
+	}
 	state, err := gist.dataCoord.GetIndexState(ctx, &indexpb.GetIndexStateRequest{
 		CollectionID: collectionID,
 		IndexName:    gist.IndexName,
@@ -1095,3 +1095,28 @@ func newTestSchema() *schemapb.CollectionSchema {
 		EnableDynamicField: true,
 	}
 }
+
+func Test_checkTrain_paramSchema(t *testing.T) {
+	field := &schemapb.FieldSchema{
+		FieldID:    101,
+		Name:       "vec",
+		DataType:   schemapb.DataType_FloatVector,
+		TypeParams: []*commonpb.KeyValuePair{{Key: DimKey, Value: "128"}},
+	}
+
+	err := checkTrain(field, map[string]string{
+		common.IndexTypeKey: "HNSW", common.MetricTypeKey: "L2", "M": "16", "efConstruction": "200",
+	})
+	assert.NoError(t, err)
+
+	err = checkTrain(field, map[string]string{
+		common.IndexTypeKey: "HNSW", common.MetricTypeKey: "L2", "M": "4096", "efConstruction": "200",
+	})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	assert.ErrorContains(t, err, "M should be an integer in range [1, 2048], got 4096")
+
+	err = checkTrain(field, map[string]string{
+		common.IndexTypeKey: "IVF_FLAT", common.MetricTypeKey: "L2", "nlist": "1.5",
+	})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
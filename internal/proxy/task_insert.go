@@ -124,7 +124,12 @@ func (it *insertTask) PreExecute(ctx context.Context) error {
 		log.Warn("get collection schema from global meta cache failed", zap.String("collectionName", collectionName), zap.Error(err))
 		return err
 	}
+	if schema.IsReadOnly() {
+		log.Warn("insert into read-only collection", zap.String("collectionName", collectionName))
+		return merr.WrapErrCollectionReadOnly(collectionName, "insert is not allowed")
+	}
 	it.schema = schema.CollectionSchema
+	setSchemaVersion(it.insertMsg.GetBase(), schema)
 
 	rowNums := uint32(it.insertMsg.NRows())
 	// set insertTask.rowIDs
@@ -160,6 +165,10 @@ func (it *insertTask) PreExecute(ctx context.Context) error {
 		}
 	}
 
+	if err := fillCompositePrimaryKey(it.schema, it.insertMsg); err != nil {
+		return err
+	}
+
 	// check primaryFieldData whether autoID is true or not
 	// set rowIDs as primary data if autoID == true
 	// TODO(dragondriver): in fact, NumRows is not trustable, we should check all input fields
@@ -206,11 +215,22 @@ func (it *insertTask) PreExecute(ctx context.Context) error {
 		}
 	}
 
+	// validate the encrypted data, which is the one stored
+	if err := encryptFieldsData(ctx, schema.CollectionSchema, it.insertMsg.GetFieldsData()); err != nil {
+		log.Warn("encrypt fields data failed", zap.Error(err))
+		return err
+	}
+
 	if err := newValidateUtil(withNANCheck(), withOverflowCheck(), withMaxLenCheck(), withMaxCapCheck()).
 		Validate(it.insertMsg.GetFieldsData(), schema.CollectionSchema, it.insertMsg.NRows()); err != nil {
 		return err
 	}
 
+	if err := normalizeFieldsData(schema.CollectionSchema, it.insertMsg.GetFieldsData()); err != nil {
+		log.Warn("normalize fields data failed", zap.Error(err))
+		return err
+	}
+
 	log.Debug("Proxy Insert PreExecute done")
 
 	return nil
@@ -269,12 +289,13 @@ func (it *insertTask) Execute(ctx context.Context) error {
 
 	log.Debug("assign segmentID for insert data success",
 		zap.Duration("assign segmentID duration", assignSegmentIDDur))
-	err = stream.Produce(msgPack)
+	err = produceWithRetry(ctx, stream, msgPack, metrics.InsertLabel)
 	if err != nil {
 		log.Warn("fail to produce insert msg", zap.Error(err))
 		it.result.Status = merr.Status(err)
 		return err
 	}
+	setChannelPositions(it.result.GetStatus(), collectChannelPositions(msgPack.Msgs))
 	sendMsgDur := tr.RecordSpan()
 	metrics.ProxySendMutationReqLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), metrics.InsertLabel).Observe(float64(sendMsgDur.Milliseconds()))
 	totalExecDur := tr.ElapseSpan()
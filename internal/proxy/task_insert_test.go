@@ -10,6 +10,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
@@ -307,3 +308,24 @@ func TestMaxInsertSize(t *testing.T) {
 		assert.ErrorIs(t, err, merr.ErrParameterTooLarge)
 	})
 }
+
+func TestInsertTask_ReadOnlyCollection(t *testing.T) {
+	cache := NewMockCache(t)
+	cache.EXPECT().GetCollectionSchema(mock.Anything, mock.Anything, mock.Anything).Return(newSchemaInfo(&schemapb.CollectionSchema{
+		Name:       "read_only_collection",
+		Properties: []*commonpb.KeyValuePair{{Key: common.CollectionReadOnlyKey, Value: "true"}},
+	}), nil)
+	globalMetaCache = cache
+
+	it := insertTask{
+		ctx: context.Background(),
+		insertMsg: &msgstream.InsertMsg{
+			InsertRequest: msgpb.InsertRequest{
+				DbName:         "default",
+				CollectionName: "read_only_collection",
+			},
+		},
+	}
+	err := it.PreExecute(context.Background())
+	assert.ErrorIs(t, err, merr.ErrCollectionReadOnly)
+}
@@ -58,6 +58,8 @@ type queryTask struct {
 	userOutputFields []string
 
 	resultBuf *typeutil.ConcurrentSet[*internalpb.RetrieveResults]
+	// channelCounts are the counts of the count(*) query by the dml channel of the shards.
+	channelCounts *typeutil.ConcurrentMap[string, int64]
 
 	plan             *planpb.PlanNode
 	partitionKeyMode bool
@@ -69,6 +71,10 @@ type queryTask struct {
 	allQueryCnt          int64
 	totalRelatedDataSize int64
 	mustUsePartitionKey  bool
+
+	dc types.DataCoordClient
+	// likeHints are the like patterns of the filter scanning all the rows of their indexed fields.
+	likeHints []string
 }
 
 type queryParams struct {
@@ -227,6 +233,7 @@ func (t *queryTask) createPlan(ctx context.Context) error {
 		}
 	}
 
+	t.request.OutputFields = withDefaultOutputFields(t.request.GetOutputFields(), t.schema)
 	t.request.OutputFields, t.userOutputFields, err = translateOutputFields(t.request.OutputFields, t.schema, true)
 	if err != nil {
 		return err
@@ -246,29 +253,35 @@ func (t *queryTask) createPlan(ctx context.Context) error {
 	return nil
 }
 
-func (t *queryTask) CanSkipAllocTimestamp() bool {
-	var consistencyLevel commonpb.ConsistencyLevel
-	useDefaultConsistency := t.request.GetUseDefaultConsistency()
-	if !useDefaultConsistency {
-		consistencyLevel = t.request.GetConsistencyLevel()
-	} else {
-		collID, err := globalMetaCache.GetCollectionID(context.Background(), t.request.GetDbName(), t.request.GetCollectionName())
-		if err != nil { // err is not nil if collection not exists
-			log.Warn("query task get collectionID failed, can't skip alloc timestamp",
-				zap.String("collectionName", t.request.GetCollectionName()), zap.Error(err))
-			return false
-		}
+// getConsistencyLevel returns the consistency level of the request, or the default one of the collection.
+func (t *queryTask) getConsistencyLevel() (commonpb.ConsistencyLevel, bool) {
+	if !t.request.GetUseDefaultConsistency() {
+		return t.request.GetConsistencyLevel(), true
+	}
+	collID, err := globalMetaCache.GetCollectionID(context.Background(), t.request.GetDbName(), t.request.GetCollectionName())
+	if err != nil { // err is not nil if collection not exists
+		log.Warn("query task get collectionID failed, can't skip alloc timestamp",
+			zap.String("collectionName", t.request.GetCollectionName()), zap.Error(err))
+		return 0, false
+	}
 
-		collectionInfo, err2 := globalMetaCache.GetCollectionInfo(context.Background(), t.request.GetDbName(), t.request.GetCollectionName(), collID)
-		if err2 != nil {
-			log.Warn("query task get collection info failed, can't skip alloc timestamp",
-				zap.String("collectionName", t.request.GetCollectionName()), zap.Error(err))
-			return false
-		}
-		consistencyLevel = collectionInfo.consistencyLevel
+	collectionInfo, err2 := globalMetaCache.GetCollectionInfo(context.Background(), t.request.GetDbName(), t.request.GetCollectionName(), collID)
+	if err2 != nil {
+		log.Warn("query task get collection info failed, can't skip alloc timestamp",
+			zap.String("collectionName", t.request.GetCollectionName()), zap.Error(err2))
+		return 0, false
 	}
+	return collectionInfo.consistencyLevel, true
+}
+
+func (t *queryTask) CanSkipAllocTimestamp() bool {
+	consistencyLevel, ok := t.getConsistencyLevel()
+	return ok && consistencyLevel != commonpb.ConsistencyLevel_Strong
+}
 
-	return consistencyLevel != commonpb.ConsistencyLevel_Strong
+func (t *queryTask) CanUseCachedTimestamp() bool {
+	consistencyLevel, ok := t.getConsistencyLevel()
+	return ok && consistencyLevel == commonpb.ConsistencyLevel_Eventually
 }
 
 func (t *queryTask) PreExecute(ctx context.Context) error {
@@ -294,6 +307,7 @@ func (t *queryTask) PreExecute(ctx context.Context) error {
 		return err
 	}
 	t.CollectionID = collID
+	globalCollectionReads.record(collID)
 	log.Debug("Get collection ID by name", zap.Int64("collectionID", t.CollectionID))
 
 	t.partitionKeyMode, err = isPartitionKeyMode(ctx, t.request.GetDbName(), collectionName)
@@ -346,6 +360,7 @@ func (t *queryTask) PreExecute(ctx context.Context) error {
 		return err
 	}
 	t.schema = schema
+	setSchemaVersion(t.RetrieveRequest.GetBase(), schema)
 
 	if t.ids != nil {
 		pkField := ""
@@ -356,10 +371,17 @@ func (t *queryTask) PreExecute(ctx context.Context) error {
 		}
 		t.request.Expr = IDs2Expr(pkField, t.ids)
 	}
+	t.request.Expr, err = expandFilterFunctions(ctx, schema.CollectionSchema, t.request.GetExpr())
+	if err != nil {
+		return err
+	}
 
 	if err := t.createPlan(ctx); err != nil {
 		return err
 	}
+	if t.likeHints, err = checkLikePatterns(ctx, t.dc, t.CollectionID, t.plan.GetQuery().GetPredicates()); err != nil {
+		return err
+	}
 	t.plan.Node.(*planpb.PlanNode_Query).Query.Limit = t.RetrieveRequest.Limit
 
 	if planparserv2.IsAlwaysTruePlan(t.plan) && t.RetrieveRequest.Limit == typeutil.Unlimited {
@@ -386,6 +408,17 @@ func (t *queryTask) PreExecute(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+		pruner := &partitionPruner{
+			qc:               t.qc,
+			dc:               t.dc,
+			dbName:           t.request.GetDbName(),
+			collectionName:   t.request.GetCollectionName(),
+			collectionID:     t.CollectionID,
+			schema:           t.schema,
+			consistencyLevel: t.getConsistencyLevel,
+			queryType:        metrics.QueryLabel,
+		}
+		t.RetrieveRequest.PartitionIDs = pruner.prune(ctx, t.RetrieveRequest.GetPartitionIDs(), t.plan.GetQuery().GetPredicates())
 	}
 
 	// count with pagination
@@ -428,6 +461,7 @@ func (t *queryTask) PreExecute(ctx context.Context) error {
 			guaranteeTs = parseGuaranteeTsFromConsistency(guaranteeTs, t.BeginTs(), consistencyLevel)
 		}
 	}
+	guaranteeTs = sessionGuaranteeTs(ctx, t.CollectionID, consistencyLevel, guaranteeTs)
 	t.GuaranteeTimestamp = guaranteeTs
 
 	deadline, ok := t.TraceCtx().Deadline()
@@ -451,6 +485,7 @@ func (t *queryTask) Execute(ctx context.Context) error {
 		zap.String("requestType", "query"))
 
 	t.resultBuf = typeutil.NewConcurrentSet[*internalpb.RetrieveResults]()
+	t.channelCounts = typeutil.NewConcurrentMap[string, int64]()
 	err := t.lb.Execute(ctx, CollectionWorkLoad{
 		db:             t.request.GetDbName(),
 		collectionID:   t.CollectionID,
@@ -507,7 +542,24 @@ func (t *queryTask) PostExecute(ctx context.Context) error {
 		log.Warn("fail to reduce query result", zap.Error(err))
 		return err
 	}
+	if err := decryptFieldsData(ctx, t.schema.CollectionSchema, t.result.GetFieldsData()); err != nil {
+		log.Warn("fail to decrypt query result", zap.Error(err))
+		return err
+	}
+	// the requery results are masked by search
+	if !t.reQuery {
+		if err := globalMaskingPolicy.Apply(ctx, t.request.GetDbName(), t.schema.CollectionSchema, t.result.GetFieldsData()); err != nil {
+			log.Warn("fail to mask query result", zap.Error(err))
+			return err
+		}
+	}
 	t.result.OutputFields = t.userOutputFields
+	if len(t.likeHints) > 0 {
+		if t.result.Status == nil {
+			t.result.Status = merr.Success()
+		}
+		setLikeHints(t.result.Status, t.likeHints)
+	}
 	metrics.ProxyReduceResultLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), metrics.QueryLabel).Observe(float64(tr.RecordSpan().Milliseconds()))
 
 	log.Debug("Query PostExecute done")
@@ -560,6 +612,11 @@ func (t *queryTask) queryShard(ctx context.Context, nodeID int64, qn types.Query
 
 	log.Debug("get query result")
 	t.resultBuf.Insert(result)
+	if t.plan.GetQuery().GetIsCount() {
+		if count, err := getCountResult(result.GetFieldsData()); err == nil {
+			t.channelCounts.Insert(channel, count)
+		}
+	}
 	t.lb.UpdateCostMetrics(nodeID, result.CostAggregation)
 	return nil
 }
@@ -605,6 +662,8 @@ func reduceRetrieveResults(ctx context.Context, retrieveResults []*internalpb.Re
 
 	ret.FieldsData = make([]*schemapb.FieldData, len(validRetrieveResults[0].GetFieldsData()))
 	idSet := make(map[interface{}]struct{})
+	// the timestamps of the returned rows by pk, only kept if deduplicated by pk
+	idTsMap := make(map[interface{}]uint64)
 	cursors := make([]int64, len(validRetrieveResults))
 
 	retrieveLimit := typeutil.Unlimited
@@ -615,14 +674,25 @@ func reduceRetrieveResults(ctx context.Context, retrieveResults []*internalpb.Re
 		}
 	}
 
-	// handle offset
+	dedupByPK := isResultDedupEnabled(ctx)
+
+	// handle offset, the duplicates are not counted in the offset if deduplicated by pk
 	if queryParams != nil && queryParams.offset > 0 {
-		for i := int64(0); i < queryParams.offset; i++ {
+		for i := int64(0); i < queryParams.offset; {
 			sel, drainOneResult := typeutil.SelectMinPK(retrieveLimit, validRetrieveResults, cursors)
 			if sel == -1 || (queryParams.reduceStopForBest && drainOneResult) {
 				return ret, nil
 			}
+			pk := typeutil.GetPK(validRetrieveResults[sel].GetIds(), cursors[sel])
 			cursors[sel]++
+			if dedupByPK {
+				if _, ok := idSet[pk]; ok {
+					skipDupCnt++
+					continue
+				}
+				idSet[pk] = struct{}{}
+			}
+			i++
 		}
 	}
 
@@ -633,7 +703,7 @@ func reduceRetrieveResults(ctx context.Context, retrieveResults []*internalpb.Re
 
 	var retSize int64
 	maxOutputSize := paramtable.Get().QuotaConfig.MaxOutputSize.GetAsInt64()
-	for j := 0; j < loopEnd; j++ {
+	for j := 0; j < loopEnd; {
 		sel, drainOneResult := typeutil.SelectMinPK(retrieveLimit, validRetrieveResults, cursors)
 		if sel == -1 || (reduceStopForBest && drainOneResult) {
 			break
@@ -643,9 +713,25 @@ func reduceRetrieveResults(ctx context.Context, retrieveResults []*internalpb.Re
 		if _, ok := idSet[pk]; !ok {
 			retSize += typeutil.AppendFieldData(ret.FieldsData, validRetrieveResults[sel].GetFieldsData(), cursors[sel])
 			idSet[pk] = struct{}{}
+			if dedupByPK {
+				idTsMap[pk] = retrieveResultTimestamp(validRetrieveResults[sel], cursors[sel])
+			}
+			j++
 		} else {
-			// primary keys duplicate
+			// primary keys duplicate, not counted in the limit if deduplicated by pk
 			skipDupCnt++
+			if !dedupByPK {
+				j++
+			}
+			// the rows are merged in pk order, so the row of the same pk is the last one appended,
+			// replace it with the latest version
+			if ts, ok := idTsMap[pk]; ok {
+				if newTs := retrieveResultTimestamp(validRetrieveResults[sel], cursors[sel]); newTs > ts {
+					idTsMap[pk] = newTs
+					typeutil.DeleteFieldData(ret.FieldsData)
+					retSize += typeutil.AppendFieldData(ret.FieldsData, validRetrieveResults[sel].GetFieldsData(), cursors[sel])
+				}
+			}
 		}
 
 		// limit retrieve result to avoid oom
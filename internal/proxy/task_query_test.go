@@ -965,6 +965,24 @@ func TestQueryTask_IDs2Expr(t *testing.T) {
 	assert.Equal(t, expectStrExpr, strExpr)
 }
 
+func TestQueryTask_CanUseCachedTimestamp(t *testing.T) {
+	for _, level := range []commonpb.ConsistencyLevel{
+		commonpb.ConsistencyLevel_Strong,
+		commonpb.ConsistencyLevel_Bounded,
+		commonpb.ConsistencyLevel_Session,
+		commonpb.ConsistencyLevel_Eventually,
+	} {
+		qt := &queryTask{
+			request: &milvuspb.QueryRequest{
+				DbName:           "test_db",
+				CollectionName:   "test_cached_timestamp",
+				ConsistencyLevel: level,
+			},
+		}
+		assert.Equal(t, level == commonpb.ConsistencyLevel_Eventually, qt.CanUseCachedTimestamp(), level.String())
+	}
+}
+
 func TestQueryTask_CanSkipAllocTimestamp(t *testing.T) {
 	dbName := "test_query"
 	collName := "test_skip_alloc_timestamp"
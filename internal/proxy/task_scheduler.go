@@ -19,13 +19,16 @@ package proxy
 import (
 	"container/list"
 	"context"
+	"strconv"
 	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/util/conc"
 	"github.com/milvus-io/milvus/pkg/util/merr"
@@ -59,6 +62,10 @@ type baseTaskQueue struct {
 	utLock        sync.RWMutex
 	atLock        sync.RWMutex
 
+	// unissuedNum and activeNum count the tasks to report the depths without the locks.
+	unissuedNum atomic.Int64
+	activeNum   atomic.Int64
+
 	// maxTaskNum should keep still
 	maxTaskNum    int64
 	maxTaskNumMtx sync.RWMutex
@@ -66,6 +73,10 @@ type baseTaskQueue struct {
 	utBufChan chan int // to block scheduler
 
 	tsoAllocatorIns tsoAllocator
+
+	// enqueueTimes is the time the unissued tasks are enqueued, to measure the queue wait.
+	enqueueTimes *typeutil.ConcurrentMap[UniqueID, time.Time]
+	events       *taskEventBuffer
 }
 
 func (queue *baseTaskQueue) utChan() <-chan int {
@@ -90,6 +101,9 @@ func (queue *baseTaskQueue) addUnissuedTask(t task) error {
 		return merr.WrapErrServiceRequestLimitExceeded(int32(queue.getMaxTaskNum()))
 	}
 	queue.unissuedTasks.PushBack(t)
+	queue.unissuedNum.Inc()
+	queue.enqueueTimes.Insert(t.ID(), time.Now())
+	queue.events.record(newTaskEvent(t, taskEventEnqueued))
 	queue.utBufChan <- 1
 	return nil
 }
@@ -115,6 +129,7 @@ func (queue *baseTaskQueue) PopUnissuedTask() task {
 
 	ft := queue.unissuedTasks.Front()
 	queue.unissuedTasks.Remove(ft)
+	queue.unissuedNum.Dec()
 
 	return ft.Value.(task)
 }
@@ -126,9 +141,20 @@ func (queue *baseTaskQueue) AddActiveTask(t task) {
 	_, ok := queue.activeTasks[tID]
 	if ok {
 		log.Warn("Proxy task with tID already in active task list!", zap.Int64("ID", tID))
+	} else {
+		queue.activeNum.Inc()
 	}
 
 	queue.activeTasks[tID] = t
+
+	event := newTaskEvent(t, taskEventStarted)
+	if enqueueTime, ok := queue.enqueueTimes.GetAndRemove(tID); ok {
+		wait := time.Since(enqueueTime)
+		event.QueueWaitMs = wait.Milliseconds()
+		metrics.ProxyTaskQueueWaitLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), t.Name()).
+			Observe(float64(wait.Milliseconds()))
+	}
+	queue.events.record(event)
 }
 
 func (queue *baseTaskQueue) PopActiveTask(taskID UniqueID) task {
@@ -137,6 +163,7 @@ func (queue *baseTaskQueue) PopActiveTask(taskID UniqueID) task {
 	t, ok := queue.activeTasks[taskID]
 	if ok {
 		delete(queue.activeTasks, taskID)
+		queue.activeNum.Dec()
 		return t
 	}
 
@@ -166,6 +193,14 @@ func (queue *baseTaskQueue) getTaskByReqID(reqID UniqueID) task {
 }
 
 func (queue *baseTaskQueue) Enqueue(t task) error {
+	if err := injectFault(t.TraceCtx(), faultPointEnqueue, taskCollectionName(t)); err != nil {
+		return err
+	}
+	return queue.enqueue(t)
+}
+
+// enqueue allocates the timestamp and the id of the task and adds it to the unissued tasks.
+func (queue *baseTaskQueue) enqueue(t task) error {
 	err := t.OnEnqueue()
 	if err != nil {
 		return err
@@ -173,12 +208,22 @@ func (queue *baseTaskQueue) Enqueue(t task) error {
 
 	var ts Timestamp
 	var id UniqueID
+	// the cached timestamp is only for the eventually read which can't skip the allocation
 	if t.CanSkipAllocTimestamp() {
 		ts = tsoutil.ComposeTS(time.Now().UnixMilli(), 0)
 		id, err = globalMetaCache.AllocID(t.TraceCtx())
 		if err != nil {
 			return err
 		}
+	} else if staleness := Params.ProxyCfg.EventuallyReadMaxStaleness.GetAsDuration(time.Millisecond); staleness > 0 && t.CanUseCachedTimestamp() {
+		ts, err = queue.tsoAllocatorIns.AllocCached(t.TraceCtx(), staleness)
+		if err != nil {
+			return err
+		}
+		id, err = globalMetaCache.AllocID(t.TraceCtx())
+		if err != nil {
+			return err
+		}
 	} else {
 		ts, err = queue.tsoAllocatorIns.AllocOne(t.TraceCtx())
 		if err != nil {
@@ -216,6 +261,7 @@ func newBaseTaskQueue(tsoAllocatorIns tsoAllocator) *baseTaskQueue {
 		maxTaskNum:      Params.ProxyCfg.MaxTaskNum.GetAsInt64(),
 		utBufChan:       make(chan int, Params.ProxyCfg.MaxTaskNum.GetAsInt()),
 		tsoAllocatorIns: tsoAllocatorIns,
+		enqueueTimes:    typeutil.NewConcurrentMap[UniqueID, time.Time](),
 	}
 }
 
@@ -237,6 +283,11 @@ type dmTaskQueue struct {
 }
 
 func (queue *dmTaskQueue) Enqueue(t task) error {
+	// the fault is injected out of the lock, which would block the enqueue of all the dml tasks otherwise
+	if err := injectFault(t.TraceCtx(), faultPointEnqueue, taskCollectionName(t)); err != nil {
+		return err
+	}
+
 	// This statsLock has two functions:
 	//	1) Protect member pChanStatisticsInfos
 	//	2) Serialize the timestamp allocation for dml tasks
@@ -252,7 +303,7 @@ func (queue *dmTaskQueue) Enqueue(t task) error {
 	// 2. enqueue dml task
 	queue.statsLock.Lock()
 	defer queue.statsLock.Unlock()
-	err = queue.baseTaskQueue.Enqueue(t)
+	err = queue.baseTaskQueue.enqueue(t)
 	if err != nil {
 		return err
 	}
@@ -274,6 +325,7 @@ func (queue *dmTaskQueue) PopActiveTask(taskID UniqueID) task {
 		defer queue.statsLock.Unlock()
 
 		delete(queue.activeTasks, taskID)
+		queue.activeNum.Dec()
 		log.Debug("Proxy dmTaskQueue popPChanStats", zap.Int64("taskID", t.ID()))
 		queue.popPChanStats(t)
 	} else {
@@ -356,9 +408,12 @@ type dqTaskQueue struct {
 }
 
 func (queue *ddTaskQueue) Enqueue(t task) error {
+	if err := injectFault(t.TraceCtx(), faultPointEnqueue, taskCollectionName(t)); err != nil {
+		return err
+	}
 	queue.lock.Lock()
 	defer queue.lock.Unlock()
-	return queue.baseTaskQueue.Enqueue(t)
+	return queue.baseTaskQueue.enqueue(t)
 }
 
 func newDdTaskQueue(tsoAllocatorIns tsoAllocator) *ddTaskQueue {
@@ -394,6 +449,12 @@ type taskScheduler struct {
 	cancel context.CancelFunc
 
 	msFactory msgstream.Factory
+
+	// events keeps the latest lifecycle events of the tasks in all the queues.
+	events   *taskEventBuffer
+	canceler *taskCanceler
+	// queryMemory limits the memory of the dql tasks in execution.
+	queryMemory *queryMemoryBudget
 }
 
 type schedOpt func(*taskScheduler)
@@ -415,6 +476,14 @@ func newTaskScheduler(ctx context.Context,
 
 	s.dcQueue = newDdTaskQueue(tsoAllocatorIns)
 
+	s.events = newTaskEventBuffer(Params.ProxyCfg.TaskEventBufferSize.GetAsInt())
+	s.ddQueue.events = s.events
+	s.dmQueue.events = s.events
+	s.dqQueue.events = s.events
+	s.dcQueue.events = s.events
+	s.canceler = newTaskCanceler()
+	s.queryMemory = newQueryMemoryBudget()
+
 	for _, opt := range opts {
 		opt(s)
 	}
@@ -451,30 +520,63 @@ func (sched *taskScheduler) processTask(t task, q taskQueue) {
 	}()
 	span.AddEvent("scheduler process PreExecute")
 
-	err := t.PreExecute(ctx)
+	// the task could be canceled by the cancel request, or by the client closing the request context while queued
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	sched.canceler.register(t, cancel)
+	defer sched.canceler.unregister(t.ID())
+
+	start := time.Now()
+	stage := taskStagePreExecute
+	err := ctx.Err()
+	if err == nil {
+		err = t.PreExecute(ctx)
+	}
 
 	defer func() {
 		t.Notify(err)
 	}()
+	defer func() {
+		sched.events.record(newTaskDoneEvent(t, stage, err, time.Since(start)))
+	}()
+	log := log.Ctx(ctx).With(zap.String("taskType", t.Name()), zap.Int64("taskID", t.ID()))
 	if err != nil {
 		span.RecordError(err)
-		log.Ctx(ctx).Warn("Failed to pre-execute task: " + err.Error())
+		log.Warn("Failed to pre-execute task", zap.Error(err))
 		return
 	}
 
+	if estimator, ok := t.(memoryEstimator); ok && sched.queryMemory != nil && sched.queryMemory.limit() > 0 {
+		var release func()
+		release, err = sched.queryMemory.acquire(ctx, estimator.estimateMemory(ctx))
+		if err != nil {
+			span.RecordError(err)
+			log.Warn("Failed to acquire the query memory", zap.Error(err))
+			return
+		}
+		defer release()
+	}
+
 	span.AddEvent("scheduler process Execute")
+	stage = taskStageExecute
 	err = t.Execute(ctx)
 	if err != nil {
 		span.RecordError(err)
-		log.Ctx(ctx).Warn("Failed to execute task: ", zap.Error(err))
+		log.Warn("Failed to execute task", zap.Error(err))
 		return
 	}
 
 	span.AddEvent("scheduler process PostExecute")
-	err = t.PostExecute(ctx)
+	stage = taskStagePostExecute
+	if q == sched.dqQueue {
+		err = injectFault(ctx, faultPointReduce, taskCollectionName(t))
+	}
+	if err == nil {
+		err = t.PostExecute(ctx)
+	}
 	if err != nil {
 		span.RecordError(err)
-		log.Ctx(ctx).Warn("Failed to post-execute task: ", zap.Error(err))
+		log.Warn("Failed to post-execute task", zap.Error(err))
 		return
 	}
 }
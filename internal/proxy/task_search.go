@@ -68,6 +68,7 @@ type searchTask struct {
 	partitionIDsSet *typeutil.ConcurrentSet[UniqueID]
 
 	qc              types.QueryCoordClient
+	dc              types.DataCoordClient
 	node            types.ProxyComponent
 	lb              LBPolicy
 	queryChannelsTs map[string]Timestamp
@@ -76,31 +77,48 @@ type searchTask struct {
 
 	reScorers  []reScorer
 	rankParams *rankParams
+
+	rerank rerankFunction
+	// rerankOnlyFields are the input fields of rerank not requested as output, they are removed after rerank.
+	rerankOnlyFields []string
+
+	// consistencyDiagnostics reports the guarantee timestamp and the timestamps the shards are searched at.
+	consistencyDiagnostics bool
+	// likeHints are the like patterns of the filters scanning all the rows of their indexed fields.
+	likeHints []string
+	// reduceTopK is the topk the results are reduced to if the shards are searched for more by the over-fetch.
+	reduceTopK int64
 }
 
-func (t *searchTask) CanSkipAllocTimestamp() bool {
-	var consistencyLevel commonpb.ConsistencyLevel
-	useDefaultConsistency := t.request.GetUseDefaultConsistency()
-	if !useDefaultConsistency {
-		consistencyLevel = t.request.GetConsistencyLevel()
-	} else {
-		collID, err := globalMetaCache.GetCollectionID(context.Background(), t.request.GetDbName(), t.request.GetCollectionName())
-		if err != nil { // err is not nil if collection not exists
-			log.Warn("search task get collectionID failed, can't skip alloc timestamp",
-				zap.String("collectionName", t.request.GetCollectionName()), zap.Error(err))
-			return false
-		}
+// getConsistencyLevel returns the consistency level of the request, or the default one of the collection.
+func (t *searchTask) getConsistencyLevel() (commonpb.ConsistencyLevel, bool) {
+	if !t.request.GetUseDefaultConsistency() {
+		return t.request.GetConsistencyLevel(), true
+	}
+	collID, err := globalMetaCache.GetCollectionID(context.Background(), t.request.GetDbName(), t.request.GetCollectionName())
+	if err != nil { // err is not nil if collection not exists
+		log.Warn("search task get collectionID failed, can't skip alloc timestamp",
+			zap.String("collectionName", t.request.GetCollectionName()), zap.Error(err))
+		return 0, false
+	}
 
-		collectionInfo, err2 := globalMetaCache.GetCollectionInfo(context.Background(), t.request.GetDbName(), t.request.GetCollectionName(), collID)
-		if err2 != nil {
-			log.Warn("search task get collection info failed, can't skip alloc timestamp",
-				zap.String("collectionName", t.request.GetCollectionName()), zap.Error(err))
-			return false
-		}
-		consistencyLevel = collectionInfo.consistencyLevel
+	collectionInfo, err2 := globalMetaCache.GetCollectionInfo(context.Background(), t.request.GetDbName(), t.request.GetCollectionName(), collID)
+	if err2 != nil {
+		log.Warn("search task get collection info failed, can't skip alloc timestamp",
+			zap.String("collectionName", t.request.GetCollectionName()), zap.Error(err2))
+		return 0, false
 	}
+	return collectionInfo.consistencyLevel, true
+}
 
-	return consistencyLevel != commonpb.ConsistencyLevel_Strong
+func (t *searchTask) CanSkipAllocTimestamp() bool {
+	consistencyLevel, ok := t.getConsistencyLevel()
+	return ok && consistencyLevel != commonpb.ConsistencyLevel_Strong
+}
+
+func (t *searchTask) CanUseCachedTimestamp() bool {
+	consistencyLevel, ok := t.getConsistencyLevel()
+	return ok && consistencyLevel == commonpb.ConsistencyLevel_Eventually
 }
 
 func (t *searchTask) PreExecute(ctx context.Context) error {
@@ -119,12 +137,14 @@ func (t *searchTask) PreExecute(ctx context.Context) error {
 
 	t.SearchRequest.DbID = 0 // todo
 	t.SearchRequest.CollectionID = collID
+	globalCollectionReads.record(collID)
 	log := log.Ctx(ctx).With(zap.Int64("collID", collID), zap.String("collName", collectionName))
 	t.schema, err = globalMetaCache.GetCollectionSchema(ctx, t.request.GetDbName(), collectionName)
 	if err != nil {
 		log.Warn("get collection schema failed", zap.Error(err))
 		return err
 	}
+	setSchemaVersion(t.SearchRequest.GetBase(), t.schema)
 
 	t.partitionKeyMode, err = isPartitionKeyMode(ctx, t.request.GetDbName(), collectionName)
 	if err != nil {
@@ -148,6 +168,18 @@ func (t *searchTask) PreExecute(ctx context.Context) error {
 		}
 	}
 
+	t.request.Dsl, err = expandFilterFunctions(ctx, t.schema.CollectionSchema, t.request.GetDsl())
+	if err != nil {
+		return err
+	}
+	for _, subReq := range t.request.GetSubReqs() {
+		subReq.Dsl, err = expandFilterFunctions(ctx, t.schema.CollectionSchema, subReq.GetDsl())
+		if err != nil {
+			return err
+		}
+	}
+
+	t.request.OutputFields = withDefaultOutputFields(t.request.GetOutputFields(), t.schema)
 	t.request.OutputFields, t.userOutputFields, err = translateOutputFields(t.request.OutputFields, t.schema, false)
 	if err != nil {
 		log.Warn("translate output fields failed", zap.Error(err))
@@ -156,6 +188,35 @@ func (t *searchTask) PreExecute(ctx context.Context) error {
 	log.Debug("translate output fields",
 		zap.Strings("output fields", t.request.GetOutputFields()))
 
+	rerankValue, _ := funcutil.GetAttrByKeyFromRepeatedKV(RerankKey, t.request.GetSearchParams())
+	t.rerank, err = parseRerankFunction(rerankValue, t.schema)
+	if err != nil {
+		log.Warn("parse rerank function failed", zap.Error(err))
+		return err
+	}
+	if t.rerank != nil {
+		t.rerankOnlyFields = lo.Without(lo.Uniq(t.rerank.inputFields()), t.request.GetOutputFields()...)
+		t.request.OutputFields = append(t.request.OutputFields, t.rerankOnlyFields...)
+	}
+	t.consistencyDiagnostics, err = parseBoolSearchParam(ConsistencyDiagnosticsKey, t.request.GetSearchParams())
+	if err != nil {
+		log.Warn("parse consistency diagnostics failed", zap.Error(err))
+		return err
+	}
+	excludeImporting, err := parseBoolSearchParam(ExcludeImportingKey, t.request.GetSearchParams())
+	if err != nil {
+		log.Warn("parse exclude importing failed", zap.Error(err))
+		return err
+	}
+	if excludeImporting {
+		importingSegments, err := getImportingSegments(ctx, t.dc, collID)
+		if err != nil {
+			log.Warn("failed to get the segments of the importing jobs", zap.Error(err))
+			return err
+		}
+		setExcludedSegments(t.SearchRequest.GetBase(), importingSegments)
+	}
+
 	if t.SearchRequest.GetIsAdvanced() {
 		if len(t.request.GetSubReqs()) > defaultMaxSearchRequest {
 			return errors.New(fmt.Sprintf("maximum of ann search requests is %d", defaultMaxSearchRequest))
@@ -236,6 +297,12 @@ func (t *searchTask) PreExecute(ctx context.Context) error {
 			guaranteeTs = parseGuaranteeTsFromConsistency(guaranteeTs, t.BeginTs(), consistencyLevel)
 		}
 	}
+	guaranteeTs = sessionGuaranteeTs(ctx, t.CollectionID, consistencyLevel, guaranteeTs)
+	// the sub search of a split search reads the snapshot at the timestamp shared by all the sub searches
+	if ts, ok := ctx.Value(searchSplitKey{}).(Timestamp); ok {
+		guaranteeTs = ts
+		t.SearchRequest.MvccTimestamp = ts
+	}
 	t.SearchRequest.GuaranteeTimestamp = guaranteeTs
 	t.SearchRequest.ConsistencyLevel = consistencyLevel
 
@@ -326,9 +393,20 @@ func (t *searchTask) initAdvancedSearchRequest(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+		hints, err := checkLikePatterns(ctx, t.dc, t.GetCollectionID(), plan.GetVectorAnns().GetPredicates())
+		if err != nil {
+			return err
+		}
+		t.likeHints = append(t.likeHints, hints...)
 		if queryInfo.GetGroupByFieldId() != -1 {
 			return errors.New("not support search_group_by operation in the hybrid search")
 		}
+		if err := validateNormalizedSearch(t.schema.CollectionSchema, plan.GetVectorAnns().GetFieldId(), queryInfo.GetMetricType(), subReq.GetPlaceholderGroup()); err != nil {
+			return err
+		}
+		if err := checkVectorCompatibility(ctx, t.dc, t.schema.CollectionSchema, t.GetCollectionID(), plan.GetVectorAnns().GetFieldId(), queryInfo.GetMetricType(), subReq.GetPlaceholderGroup()); err != nil {
+			return err
+		}
 		internalSubReq := &internalpb.SubSearchRequest{
 			Dsl:                subReq.GetDsl(),
 			PlaceholderGroup:   subReq.GetPlaceholderGroup(),
@@ -396,9 +474,27 @@ func (t *searchTask) initSearchRequest(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if t.likeHints, err = checkLikePatterns(ctx, t.dc, t.GetCollectionID(), plan.GetVectorAnns().GetPredicates()); err != nil {
+		return err
+	}
+	if err := validateNormalizedSearch(t.schema.CollectionSchema, plan.GetVectorAnns().GetFieldId(), queryInfo.GetMetricType(), t.request.GetPlaceholderGroup()); err != nil {
+		return err
+	}
+	if err := checkVectorCompatibility(ctx, t.dc, t.schema.CollectionSchema, t.GetCollectionID(), plan.GetVectorAnns().GetFieldId(), queryInfo.GetMetricType(), t.request.GetPlaceholderGroup()); err != nil {
+		return err
+	}
 
 	t.SearchRequest.Offset = offset
 
+	// the searches with filter search the shards for more to improve the recall, and reduce to the topk
+	if plan.GetVectorAnns().GetPredicates() != nil && queryInfo.GetGroupByFieldId() <= 0 {
+		if topK := overFetchTopK(t.request.GetDbName(), t.request.GetCollectionName(), t.request.GetDsl(),
+			queryInfo.GetTopk(), queryInfo.GetSearchParams()); topK > queryInfo.GetTopk() {
+			t.reduceTopK = queryInfo.GetTopk()
+			queryInfo.Topk = topK
+		}
+	}
+
 	if t.partitionKeyMode {
 		partitionIDs, err2 := t.tryParsePartitionIDsFromPlan(plan)
 		if err2 != nil {
@@ -409,6 +505,17 @@ func (t *searchTask) initSearchRequest(ctx context.Context) error {
 			setQueryInfoIfMvEnable(queryInfo, t)
 		}
 	}
+	pruner := &partitionPruner{
+		qc:               t.qc,
+		dc:               t.dc,
+		dbName:           t.request.GetDbName(),
+		collectionName:   t.collectionName,
+		collectionID:     t.GetCollectionID(),
+		schema:           t.schema,
+		consistencyLevel: t.getConsistencyLevel,
+		queryType:        metrics.SearchLabel,
+	}
+	t.SearchRequest.PartitionIDs = pruner.prune(ctx, t.SearchRequest.GetPartitionIDs(), plan.GetVectorAnns().GetPredicates())
 
 	if t.requery {
 		plan.OutputFieldIds = nil
@@ -446,11 +553,15 @@ func (t *searchTask) tryGeneratePlan(params []*commonpb.KeyValuePair, dsl string
 		}
 		annsFieldName = vecFields[0].Name
 	}
-	queryInfo, offset, parseErr := parseSearchInfo(params, t.schema.CollectionSchema, ignoreOffset)
+	annField := typeutil.GetFieldByName(t.schema.CollectionSchema, annsFieldName)
+	indexType := vectorIndexType(t.ctx, t.dc, t.GetCollectionID(), annField.GetFieldID())
+	queryInfo, offset, parseErr := parseSearchInfo(params, t.schema.CollectionSchema, ignoreOffset, indexType)
 	if parseErr != nil {
 		return nil, nil, 0, parseErr
 	}
-	annField := typeutil.GetFieldByName(t.schema.CollectionSchema, annsFieldName)
+	if queryInfo.MetricType, parseErr = validateSearchMetricType(annField, queryInfo.GetMetricType()); parseErr != nil {
+		return nil, nil, 0, parseErr
+	}
 	if queryInfo.GetGroupByFieldId() != -1 && annField.GetDataType() == schemapb.DataType_BinaryVector {
 		return nil, nil, 0, errors.New("not support search_group_by operation based on binary vector column")
 	}
@@ -637,10 +748,17 @@ func (t *searchTask) PostExecute(ctx context.Context) error {
 			return err
 		}
 	} else {
-		t.result, err = t.reduceResults(t.ctx, toReduceResults, t.SearchRequest.Nq, t.SearchRequest.GetTopk(), t.SearchRequest.GetOffset(), t.queryInfos[0])
+		topK := t.SearchRequest.GetTopk()
+		if t.reduceTopK > 0 {
+			topK = t.reduceTopK
+		}
+		t.result, err = t.reduceResults(t.ctx, toReduceResults, t.SearchRequest.Nq, topK, t.SearchRequest.GetOffset(), t.queryInfos[0])
 		if err != nil {
 			return err
 		}
+		if len(toReduceResults) > 0 {
+			fillCosineDistances(t.result.GetResults(), toReduceResults[0].GetMetricType())
+		}
 	}
 
 	t.result.CollectionName = t.collectionName
@@ -652,9 +770,45 @@ func (t *searchTask) PostExecute(ctx context.Context) error {
 			log.Warn("failed to requery", zap.Error(err))
 			return err
 		}
+	} else if err := decryptFieldsData(ctx, t.schema.CollectionSchema, t.result.GetResults().GetFieldsData()); err != nil {
+		// the requery results are decrypted by query
+		log.Warn("failed to decrypt search result", zap.Error(err))
+		return err
+	}
+	if t.rerank != nil {
+		if err := applyRerank(ctx, t.rerank, t.result.GetResults()); err != nil {
+			log.Warn("failed to rerank search result", zap.Error(err))
+			return err
+		}
+		t.result.Results.FieldsData = lo.Filter(t.result.Results.FieldsData, func(fieldData *schemapb.FieldData, _ int) bool {
+			return !lo.Contains(t.rerankOnlyFields, fieldData.GetFieldName())
+		})
+	}
+	if err := globalMaskingPolicy.Apply(ctx, t.request.GetDbName(), t.schema.CollectionSchema, t.result.GetResults().GetFieldsData()); err != nil {
+		log.Warn("failed to mask search result", zap.Error(err))
+		return err
 	}
 	t.result.Results.OutputFields = t.userOutputFields
 	t.result.CollectionName = t.request.GetCollectionName()
+	if t.consistencyDiagnostics {
+		if t.result.Status == nil {
+			t.result.Status = merr.Success()
+		}
+		setConsistencyDiagnostics(t.result.Status, collectConsistencyDiagnostics(t.SearchRequest.GetConsistencyLevel(),
+			t.SearchRequest.GetGuaranteeTimestamp(), toReduceResults))
+	}
+	if coverage := getSearchCoverage(t.GetCollectionID(), len(toReduceResults)); coverage != nil {
+		if t.result.Status == nil {
+			t.result.Status = merr.Success()
+		}
+		setSearchCoverage(t.result.Status, coverage)
+	}
+	if len(t.likeHints) > 0 {
+		if t.result.Status == nil {
+			t.result.Status = merr.Success()
+		}
+		setLikeHints(t.result.Status, t.likeHints)
+	}
 
 	metrics.ProxyReduceResultLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), metrics.SearchLabel).Observe(float64(tr.RecordSpan().Milliseconds()))
 
@@ -295,6 +295,23 @@ func TestSearchTask_PreExecute(t *testing.T) {
 		task.request.OutputFields = []string{testFloatVecField}
 		assert.NoError(t, task.PreExecute(ctx))
 	})
+
+	t.Run("sub search of split search", func(t *testing.T) {
+		collName := "search_split" + funcutil.GenRandomStr()
+		createColl(t, collName, rc)
+
+		task := getSearchTask(t, collName)
+		task.request.SearchParams = getValidSearchParams()
+		task.request.DslType = commonpb.DslType_BoolExprV1
+		task.request.UseDefaultConsistency = false
+		task.request.ConsistencyLevel = commonpb.ConsistencyLevel_Eventually
+		assert.NoError(t, task.PreExecute(ctx))
+		assert.Equal(t, Timestamp(0), task.SearchRequest.GetMvccTimestamp())
+
+		assert.NoError(t, task.PreExecute(context.WithValue(ctx, searchSplitKey{}, Timestamp(100))))
+		assert.Equal(t, Timestamp(100), task.SearchRequest.GetMvccTimestamp())
+		assert.Equal(t, Timestamp(100), task.SearchRequest.GetGuaranteeTimestamp())
+	})
 }
 
 func getQueryCoord() *mocks.MockQueryCoord {
@@ -1965,7 +1982,7 @@ func TestTaskSearch_parseQueryInfo(t *testing.T) {
 
 		for _, test := range tests {
 			t.Run(test.description, func(t *testing.T) {
-				info, offset, err := parseSearchInfo(test.validParams, nil, false)
+				info, offset, err := parseSearchInfo(test.validParams, nil, false, "")
 				assert.NoError(t, err)
 				assert.NotNil(t, info)
 				if test.description == "offsetParam" {
@@ -2037,6 +2054,21 @@ func TestTaskSearch_parseQueryInfo(t *testing.T) {
 			Value: "16386",
 		})
 
+		spInvalidSearchParams := append(spNoSearchParams, &commonpb.KeyValuePair{
+			Key:   SearchParamsKey,
+			Value: `{"nprobe": 0}`,
+		})
+
+		spInvalidSearchParamsType := append(spNoSearchParams, &commonpb.KeyValuePair{
+			Key:   SearchParamsKey,
+			Value: `{"ef": "large"}`,
+		})
+
+		spInvalidSearchParamsJSON := append(spNoSearchParams, &commonpb.KeyValuePair{
+			Key:   SearchParamsKey,
+			Value: `nprobe=10`,
+		})
+
 		tests := []struct {
 			description   string
 			invalidParams []*commonpb.KeyValuePair
@@ -2050,11 +2082,14 @@ func TestTaskSearch_parseQueryInfo(t *testing.T) {
 			{"Invalid_offset_not_int", spInvalidOffsetNoInt},
 			{"Invalid_offset_negative", spInvalidOffsetNegative},
 			{"Invalid_offset_too_large", spInvalidOffsetTooLarge},
+			{"Invalid_search_params", spInvalidSearchParams},
+			{"Invalid_search_params_type", spInvalidSearchParamsType},
+			{"Invalid_search_params_json", spInvalidSearchParamsJSON},
 		}
 
 		for _, test := range tests {
 			t.Run(test.description, func(t *testing.T) {
-				info, offset, err := parseSearchInfo(test.invalidParams, nil, false)
+				info, offset, err := parseSearchInfo(test.invalidParams, nil, false, "")
 				assert.Error(t, err)
 				assert.Nil(t, info)
 				assert.Zero(t, offset)
@@ -2081,7 +2116,7 @@ func TestTaskSearch_parseQueryInfo(t *testing.T) {
 		schema := &schemapb.CollectionSchema{
 			Fields: fields,
 		}
-		info, _, err := parseSearchInfo(normalParam, schema, false)
+		info, _, err := parseSearchInfo(normalParam, schema, false, "")
 		assert.Nil(t, info)
 		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
 	})
@@ -2100,7 +2135,7 @@ func TestTaskSearch_parseQueryInfo(t *testing.T) {
 		schema := &schemapb.CollectionSchema{
 			Fields: fields,
 		}
-		info, _, err := parseSearchInfo(normalParam, schema, false)
+		info, _, err := parseSearchInfo(normalParam, schema, false, "")
 		assert.Nil(t, info)
 		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
 	})
@@ -2461,6 +2496,24 @@ func TestGetPartitionIDs(t *testing.T) {
 	suite.Run(t, new(GetPartitionIDsSuite))
 }
 
+func TestSearchTask_CanUseCachedTimestamp(t *testing.T) {
+	for _, level := range []commonpb.ConsistencyLevel{
+		commonpb.ConsistencyLevel_Strong,
+		commonpb.ConsistencyLevel_Bounded,
+		commonpb.ConsistencyLevel_Session,
+		commonpb.ConsistencyLevel_Eventually,
+	} {
+		st := &searchTask{
+			request: &milvuspb.SearchRequest{
+				DbName:           "test_db",
+				CollectionName:   "test_cached_timestamp",
+				ConsistencyLevel: level,
+			},
+		}
+		assert.Equal(t, level == commonpb.ConsistencyLevel_Eventually, st.CanUseCachedTimestamp(), level.String())
+	}
+}
+
 func TestSearchTask_CanSkipAllocTimestamp(t *testing.T) {
 	dbName := "test_query"
 	collName := "test_skip_alloc_timestamp"
@@ -2714,3 +2767,36 @@ func (s *MaterializedViewTestSuite) TestMvEnabledPartitionKeyOnVarChar() {
 func TestMaterializedView(t *testing.T) {
 	suite.Run(t, new(MaterializedViewTestSuite))
 }
+
+func TestValidateSearchMetricType(t *testing.T) {
+	floatVecField := &schemapb.FieldSchema{Name: "vec", DataType: schemapb.DataType_FloatVector}
+	binaryVecField := &schemapb.FieldSchema{Name: "bvec", DataType: schemapb.DataType_BinaryVector}
+	sparseVecField := &schemapb.FieldSchema{Name: "svec", DataType: schemapb.DataType_SparseFloatVector}
+
+	metricType, err := validateSearchMetricType(floatVecField, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", metricType)
+
+	metricType, err = validateSearchMetricType(floatVecField, "cosine")
+	assert.NoError(t, err)
+	assert.Equal(t, metric.COSINE, metricType)
+
+	_, err = validateSearchMetricType(floatVecField, metric.JACCARD)
+	assert.Error(t, err)
+	_, err = validateSearchMetricType(binaryVecField, metric.COSINE)
+	assert.Error(t, err)
+	_, err = validateSearchMetricType(sparseVecField, metric.COSINE)
+	assert.Error(t, err)
+	metricType, err = validateSearchMetricType(sparseVecField, metric.IP)
+	assert.NoError(t, err)
+	assert.Equal(t, metric.IP, metricType)
+}
+
+func TestFillCosineDistances(t *testing.T) {
+	data := &schemapb.SearchResultData{Scores: []float32{1, 0.5, -0.5}}
+	fillCosineDistances(data, metric.L2)
+	assert.Empty(t, data.GetDistances())
+
+	fillCosineDistances(data, metric.COSINE)
+	assert.Equal(t, []float32{0, 0.5, 1.5}, data.GetDistances())
+}
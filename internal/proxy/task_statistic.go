@@ -49,6 +49,8 @@ type getStatisticsTask struct {
 
 	fromDataCoord bool
 	fromQueryNode bool
+	// breakdown is true to return the statistics by partition and segment state
+	breakdown bool
 
 	// if query from shard
 	*internalpb.GetStatisticsRequest
@@ -216,6 +218,15 @@ func (g *getStatisticsTask) PostExecute(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if g.breakdown {
+		breakdown, err := getStatisticsBreakdown(ctx, g.dc, g.request.GetDbName(), g.collectionName, g.partitionNames)
+		if err != nil {
+			return err
+		}
+		if result, err = withStatisticsBreakdown(result, breakdown); err != nil {
+			return err
+		}
+	}
 	g.result = &milvuspb.GetStatisticsResponse{
 		Status: merr.Success(),
 		Stats:  result,
@@ -657,6 +668,9 @@ func (g *getCollectionStatisticsTask) Execute(ctx context.Context) error {
 		CollectionID: collID,
 	}
 
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(g)); err != nil {
+		return err
+	}
 	result, err := g.dataCoord.GetCollectionStatistics(ctx, req)
 	if err != nil {
 		return err
@@ -748,6 +762,9 @@ func (g *getPartitionStatisticsTask) Execute(ctx context.Context) error {
 		PartitionIDs: []int64{partitionID},
 	}
 
+	if err := injectFault(ctx, faultPointCoordCall, taskCollectionName(g)); err != nil {
+		return err
+	}
 	result, _ := g.dataCoord.GetPartitionStatistics(ctx, req)
 	if result == nil {
 		return errors.New("get partition statistics resp is nil")
@@ -31,6 +31,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
@@ -1476,6 +1477,45 @@ func TestDropPartitionTask(t *testing.T) {
 		err = task.PreExecute(ctx)
 		assert.Error(t, err)
 	})
+
+	t.Run("partition loaded", func(t *testing.T) {
+		task.PartitionName = "partition4"
+
+		mockCache := NewMockCache(t)
+		mockCache.EXPECT().GetCollectionSchema(mock.Anything, mock.Anything, mock.Anything).Return(newSchemaInfo(&schemapb.CollectionSchema{}), nil)
+		mockCache.EXPECT().GetCollectionID(mock.Anything, mock.Anything, mock.Anything).Return(UniqueID(1), nil)
+		mockCache.EXPECT().GetPartitionID(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(UniqueID(2), nil)
+		globalMetaCache = mockCache
+
+		qc := mocks.NewMockQueryCoordClient(t)
+		qc.EXPECT().ShowCollections(mock.Anything, mock.Anything).Return(&querypb.ShowCollectionsResponse{
+			Status:        merr.Success(),
+			CollectionIDs: []int64{1},
+		}, nil)
+		qc.EXPECT().ShowPartitions(mock.Anything, mock.Anything).Return(&querypb.ShowPartitionsResponse{
+			Status:       merr.Success(),
+			PartitionIDs: []int64{2},
+		}, nil)
+		task.queryCoord = qc
+		task.releaseFirst = false
+
+		err = task.PreExecute(ctx)
+		assert.ErrorIs(t, err, merr.ErrPartitionLoaded)
+		assert.False(t, task.releaseFirst)
+
+		forceCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(forceDropPartitionKey, "true"))
+		err = task.PreExecute(forceCtx)
+		assert.NoError(t, err)
+		assert.True(t, task.releaseFirst)
+
+		qc.EXPECT().ReleasePartitions(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *querypb.ReleasePartitionsRequest, opts ...grpc.CallOption) (*commonpb.Status, error) {
+			assert.EqualValues(t, 1, req.GetCollectionID())
+			assert.Equal(t, []int64{2}, req.GetPartitionIDs())
+			return merr.Status(errors.New("mock")), nil
+		}).Once()
+		err = task.Execute(forceCtx)
+		assert.Error(t, err)
+	})
 }
 
 func TestHasPartitionTask(t *testing.T) {
@@ -3663,6 +3703,34 @@ func TestAlterCollectionCheckLoaded(t *testing.T) {
 	assert.Equal(t, merr.Code(merr.ErrCollectionLoaded), merr.Code(err))
 }
 
+func TestAlterCollectionReadOnly(t *testing.T) {
+	rc := NewRootCoordMock()
+	rc.state.Store(commonpb.StateCode_Healthy)
+	qc := &mocks.MockQueryCoordClient{}
+	InitMetaCache(context.Background(), rc, qc, nil)
+	collectionName := "test_alter_collection_read_only"
+	rc.CreateCollection(context.Background(), &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		ShardsNum:      1,
+	})
+
+	task := &alterCollectionTask{
+		AlterCollectionRequest: &milvuspb.AlterCollectionRequest{
+			Base:           &commonpb.MsgBase{},
+			CollectionName: collectionName,
+			Properties:     []*commonpb.KeyValuePair{{Key: common.CollectionReadOnlyKey, Value: "yes"}},
+		},
+		queryCoord: qc,
+	}
+	err := task.PreExecute(context.Background())
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	task.Properties = []*commonpb.KeyValuePair{{Key: common.CollectionReadOnlyKey, Value: "true"}}
+	err = task.PreExecute(context.Background())
+	assert.NoError(t, err)
+}
+
 func TestAlterDatabase(t *testing.T) {
 	rc := mocks.NewMockRootCoordClient(t)
 
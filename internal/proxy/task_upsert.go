@@ -179,6 +179,10 @@ func (it *upsertTask) insertPreExecute(ctx context.Context) error {
 		}
 	}
 
+	if err := fillCompositePrimaryKey(it.schema.CollectionSchema, it.upsertMsg.InsertMsg); err != nil {
+		return err
+	}
+
 	// check primaryFieldData whether autoID is true or not
 	// only allow support autoID == false
 	var err error
@@ -214,11 +218,22 @@ func (it *upsertTask) insertPreExecute(ctx context.Context) error {
 		}
 	}
 
+	// validate the encrypted data, which is the one stored
+	if err := encryptFieldsData(ctx, it.schema.CollectionSchema, it.upsertMsg.InsertMsg.GetFieldsData()); err != nil {
+		log.Warn("encrypt fields data failed", zap.Error(err))
+		return err
+	}
+
 	if err := newValidateUtil(withNANCheck(), withOverflowCheck(), withMaxLenCheck()).
 		Validate(it.upsertMsg.InsertMsg.GetFieldsData(), it.schema.CollectionSchema, it.upsertMsg.InsertMsg.NRows()); err != nil {
 		return err
 	}
 
+	if err := normalizeFieldsData(it.schema.CollectionSchema, it.upsertMsg.InsertMsg.GetFieldsData()); err != nil {
+		log.Warn("normalize fields data failed", zap.Error(err))
+		return err
+	}
+
 	log.Debug("Proxy Upsert insertPreExecute done")
 
 	return nil
@@ -291,6 +306,10 @@ func (it *upsertTask) PreExecute(ctx context.Context) error {
 			zap.Error(err))
 		return err
 	}
+	if schema.IsReadOnly() {
+		log.Warn("upsert into read-only collection")
+		return merr.WrapErrCollectionReadOnly(collectionName, "upsert is not allowed")
+	}
 	it.schema = schema
 
 	it.partitionKeyMode, err = isPartitionKeyMode(ctx, it.req.GetDbName(), collectionName)
@@ -343,6 +362,7 @@ func (it *upsertTask) PreExecute(ctx context.Context) error {
 			},
 		},
 	}
+	setSchemaVersion(it.upsertMsg.InsertMsg.GetBase(), it.schema)
 	err = it.insertPreExecute(ctx)
 	if err != nil {
 		log.Warn("Fail to insertPreExecute", zap.Error(err))
@@ -535,11 +555,12 @@ func (it *upsertTask) Execute(ctx context.Context) (err error) {
 	}
 
 	tr.RecordSpan()
-	err = stream.Produce(msgPack)
+	err = produceWithRetry(ctx, stream, msgPack, metrics.UpsertLabel)
 	if err != nil {
 		it.result.Status = merr.Status(err)
 		return err
 	}
+	setChannelPositions(it.result.GetStatus(), collectChannelPositions(msgPack.Msgs))
 	sendMsgDur := tr.RecordSpan()
 	metrics.ProxySendMutationReqLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), metrics.UpsertLabel).Observe(float64(sendMsgDur.Milliseconds()))
 	totalDur := tr.ElapseSpan()
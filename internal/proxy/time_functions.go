@@ -0,0 +1,198 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// timezoneKey is the request metadata key of the IANA timezone, such as Asia/Shanghai, to evaluate the time functions
+// of the filter expressions in, UTC by default.
+const timezoneKey = "timezone"
+
+// the time functions of the filter expressions, evaluated to the epoch milliseconds by the proxy before the plan is
+// created, so the clients compare the timestamp fields without computing the epoch milliseconds themselves.
+const (
+	timeFuncNow      = "now"
+	timeFuncToday    = "today"
+	timeFuncDatetime = "datetime"
+	timeFuncInterval = "interval"
+)
+
+// datetimeLayouts are the layouts accepted by datetime(), the layouts without an offset are of the request timezone.
+var datetimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+var intervalPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)(ms|s|m|h|d|w)`)
+
+// getRequestTimezone returns the timezone in the request metadata, UTC if not set.
+func getRequestTimezone(ctx context.Context) (*time.Location, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return time.UTC, nil
+	}
+	values := md.Get(timezoneKey)
+	if len(values) == 0 || values[0] == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(values[0])
+	if err != nil {
+		return nil, merr.WrapErrParameterInvalid("IANA timezone", values[0], "invalid timezone")
+	}
+	return loc, nil
+}
+
+// expandRequestTimeFunctions evaluates the time functions of the expression in the timezone of the request.
+func expandRequestTimeFunctions(ctx context.Context, expr string) (string, error) {
+	if !strings.Contains(expr, "(") {
+		return expr, nil
+	}
+	loc, err := getRequestTimezone(ctx)
+	if err != nil {
+		return "", err
+	}
+	return expandTimeFunctions(expr, time.Now(), loc)
+}
+
+// parseInterval returns the milliseconds of the interval such as 1d12h or 90m, in the units of w, d, h, m, s and ms.
+func parseInterval(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	sign := 1.0
+	if strings.HasPrefix(value, "-") {
+		sign, value = -1, value[1:]
+	}
+	units := map[string]time.Duration{
+		"ms": time.Millisecond,
+		"s":  time.Second,
+		"m":  time.Minute,
+		"h":  time.Hour,
+		"d":  24 * time.Hour,
+		"w":  7 * 24 * time.Hour,
+	}
+	matches := intervalPattern.FindAllStringSubmatchIndex(value, -1)
+	if len(matches) == 0 {
+		return 0, merr.WrapErrParameterInvalidMsg("invalid interval %q, expect such as 1d12h", value)
+	}
+	var ms float64
+	end := 0
+	for _, match := range matches {
+		if match[0] != end {
+			return 0, merr.WrapErrParameterInvalidMsg("invalid interval %q, expect such as 1d12h", value)
+		}
+		n, _ := strconv.ParseFloat(value[match[2]:match[3]], 64)
+		ms += n * float64(units[value[match[4]:match[5]]]/time.Millisecond)
+		end = match[1]
+	}
+	if end != len(value) {
+		return 0, merr.WrapErrParameterInvalidMsg("invalid interval %q, expect such as 1d12h", value)
+	}
+	return int64(sign * ms), nil
+}
+
+// parseDatetime returns the epoch milliseconds of the datetime, in the timezone if no offset is given.
+func parseDatetime(value string, loc *time.Location) (int64, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range datetimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UnixMilli(), nil
+		}
+	}
+	return 0, merr.WrapErrParameterInvalidMsg("invalid datetime %q, expect such as 2024-01-02 15:04:05", value)
+}
+
+// evalTimeFunction returns the epoch milliseconds, or the milliseconds of the interval, of the time function.
+func evalTimeFunction(name string, args []string, now time.Time, loc *time.Location) (int64, error) {
+	expectArgs := func(n int) error {
+		if len(args) != n {
+			return merr.WrapErrParameterInvalidMsg("%s() expects %d arguments, got %d", name, n, len(args))
+		}
+		return nil
+	}
+	switch name {
+	case timeFuncNow:
+		if err := expectArgs(0); err != nil {
+			return 0, err
+		}
+		return now.UnixMilli(), nil
+	case timeFuncToday:
+		if err := expectArgs(0); err != nil {
+			return 0, err
+		}
+		local := now.In(loc)
+		return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc).UnixMilli(), nil
+	case timeFuncDatetime:
+		if err := expectArgs(1); err != nil {
+			return 0, err
+		}
+		return parseDatetime(args[0], loc)
+	default:
+		if err := expectArgs(1); err != nil {
+			return 0, err
+		}
+		return parseInterval(args[0])
+	}
+}
+
+// expandTimeFunctions replaces the time functions outside the string literals of the expression with their values,
+// the arithmetic of the constants such as now() - interval("1d") is folded by the plan parser. The functions are:
+//
+//	now()                              the current time
+//	today()                            the start of the current day in the timezone
+//	datetime("2024-01-02 15:04:05")    the datetime in the timezone, or of the offset if given
+//	interval("1d12h")                  the milliseconds of the interval
+func expandTimeFunctions(expr string, now time.Time, loc *time.Location) (string, error) {
+	isFunc := func(name string) bool {
+		switch name {
+		case timeFuncNow, timeFuncToday, timeFuncDatetime, timeFuncInterval:
+			return true
+		}
+		return false
+	}
+	return replaceExprFunctions(expr, isFunc, func(name string, j int) (string, int, error) {
+		var args []string
+		j = skipExprSpaces(expr, j)
+		if j < len(expr) && (expr[j] == '"' || expr[j] == '\'') {
+			arg, end, ok := readStringLiteral(expr, j)
+			if !ok {
+				return "", 0, merr.WrapErrParameterInvalidMsg("unterminated string argument of %s()", name)
+			}
+			args = append(args, arg)
+			j = skipExprSpaces(expr, end)
+		}
+		if j >= len(expr) || expr[j] != ')' {
+			return "", 0, merr.WrapErrParameterInvalidMsg("invalid arguments of %s(), expect a string literal", name)
+		}
+		value, err := evalTimeFunction(name, args, now, loc)
+		if err != nil {
+			return "", 0, err
+		}
+		return strconv.FormatInt(value, 10), j + 1, nil
+	})
+}
@@ -0,0 +1,100 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestParseInterval(t *testing.T) {
+	for value, expected := range map[string]int64{
+		"1d":      86400000,
+		"1d12h":   129600000,
+		"90m":     5400000,
+		"1.5s":    1500,
+		"1w":      604800000,
+		"-2h":     -7200000,
+		" 500ms ": 500,
+	} {
+		ms, err := parseInterval(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, ms, value)
+	}
+	for _, value := range []string{"", "1", "1y", "d1", "1d x"} {
+		_, err := parseInterval(value)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid, value)
+	}
+}
+
+func TestExpandTimeFunctions(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	assert.NoError(t, err)
+	// 2024-01-02 01:00:00 in Shanghai
+	now := time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC)
+
+	expr, err := expandTimeFunctions(`ts > now() - interval("1h") and ts < now ( )`, now, shanghai)
+	assert.NoError(t, err)
+	assert.Equal(t, "ts > 1704128400000 - 3600000 and ts < 1704128400000", expr)
+
+	expr, err = expandTimeFunctions(`ts >= today()`, now, shanghai)
+	assert.NoError(t, err)
+	assert.Equal(t, "ts >= 1704124800000", expr)
+	expr, err = expandTimeFunctions(`ts >= today()`, now, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, "ts >= 1704067200000", expr)
+
+	expr, err = expandTimeFunctions(`ts < datetime('2024-01-02') and ts > datetime("2024-01-01T00:00:00Z")`, now, shanghai)
+	assert.NoError(t, err)
+	assert.Equal(t, "ts < 1704124800000 and ts > 1704067200000", expr)
+
+	// the string literals and the fields named as the functions are kept
+	for _, kept := range []string{`title == "now()"`, `now > 10`, `my_now() == 1`, `$meta["today"] == 'interval("1d")'`, `id in [1, 2]`} {
+		expr, err = expandTimeFunctions(kept, now, time.UTC)
+		assert.NoError(t, err)
+		assert.Equal(t, kept, expr)
+	}
+
+	for _, invalid := range []string{`ts > now(1)`, `ts > interval()`, `ts > interval("1y")`, `ts > datetime("yesterday")`, `ts > now("1d")`, `ts > interval("1d`} {
+		_, err = expandTimeFunctions(invalid, now, time.UTC)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid, invalid)
+	}
+}
+
+func TestExpandRequestTimeFunctions(t *testing.T) {
+	expr, err := expandRequestTimeFunctions(context.Background(), "id > 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "id > 1", expr)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(timezoneKey, "Asia/Shanghai"))
+	loc, err := getRequestTimezone(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Shanghai", loc.String())
+	expr, err = expandRequestTimeFunctions(ctx, "ts > datetime('2024-01-02')")
+	assert.NoError(t, err)
+	assert.Equal(t, "ts > 1704124800000", expr)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(timezoneKey, "Mars/Olympus"))
+	_, err = expandRequestTimeFunctions(ctx, "ts > now()")
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
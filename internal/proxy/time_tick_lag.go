@@ -0,0 +1,69 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// maxPChanLag returns the pchan lagging behind the most with its lag.
+func maxPChanLag(lags map[pChan]time.Duration) (pChan, time.Duration) {
+	var maxChannel pChan
+	var maxLag time.Duration
+	for pchan, lag := range lags {
+		if lag > maxLag || (lag == maxLag && pchan < maxChannel) {
+			maxChannel, maxLag = pchan, lag
+		}
+	}
+	return maxChannel, maxLag
+}
+
+// timeTickLagReasons returns the unhealthy reasons of the pchans whose time ticks lag behind the health threshold,
+// the dml requests on them are pending for the lag.
+func (node *Proxy) timeTickLagReasons() []string {
+	threshold := Params.ProxyCfg.TimeTickLagHealthThreshold.GetAsDuration(time.Second)
+	if node.chTicker == nil || threshold <= 0 {
+		return nil
+	}
+	reasons := make([]string, 0)
+	for pchan, lag := range node.chTicker.getPChanLags() {
+		if lag > threshold {
+			reasons = append(reasons, fmt.Sprintf("the time tick of pchannel %s lags behind %s on proxy %d", pchan, lag, paramtable.GetNodeID()))
+		}
+	}
+	sort.Strings(reasons)
+	return reasons
+}
+
+// checkTimeTickLagAdmission rejects the dml requests while the time tick of a pchan lags behind the critical
+// threshold, the rate limit error makes the clients back off until the pending dml requests are done.
+func (node *Proxy) checkTimeTickLagAdmission() error {
+	threshold := Params.ProxyCfg.TimeTickLagCriticalThreshold.GetAsDuration(time.Second)
+	if node.chTicker == nil || threshold <= 0 {
+		return nil
+	}
+	pchan, lag := maxPChanLag(node.chTicker.getPChanLags())
+	if lag <= threshold {
+		return nil
+	}
+	return merr.WrapErrServiceRateLimit(0, fmt.Sprintf("the time tick of pchannel %s lags behind %s", pchan, lag))
+}
@@ -0,0 +1,72 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/tsoutil"
+)
+
+func TestMaxPChanLag(t *testing.T) {
+	pchan, lag := maxPChanLag(nil)
+	assert.Empty(t, pchan)
+	assert.Zero(t, lag)
+	pchan, lag = maxPChanLag(map[pChan]time.Duration{"p1": time.Second, "p2": time.Minute, "p3": time.Minute})
+	assert.Equal(t, "p2", pchan)
+	assert.Equal(t, time.Minute, lag)
+}
+
+func TestTimeTickLagHealth(t *testing.T) {
+	paramtable.Init()
+	node := &Proxy{}
+	assert.Empty(t, node.timeTickLagReasons())
+	assert.NoError(t, node.checkTimeTickLagAdmission())
+
+	now := time.Now()
+	stats := map[pChan]*pChanStatistics{
+		"p1": {minTs: tsoutil.ComposeTSByTime(now.Add(-2*time.Minute), 0), maxTs: tsoutil.ComposeTSByTime(now, 0)},
+		"p2": {minTs: tsoutil.ComposeTSByTime(now, 0), maxTs: tsoutil.ComposeTSByTime(now, 0)},
+	}
+	ticker := newChannelsTimeTicker(context.Background(), time.Hour, nil, func() (map[pChan]*pChanStatistics, error) {
+		return stats, nil
+	}, newMockTsoAllocator())
+	assert.NoError(t, ticker.tick())
+	node.chTicker = ticker
+
+	reasons := node.timeTickLagReasons()
+	assert.Len(t, reasons, 1)
+	assert.Contains(t, reasons[0], "p1")
+	// the critical threshold is disabled by default
+	assert.NoError(t, node.checkTimeTickLagAdmission())
+
+	paramtable.Get().Save(Params.ProxyCfg.TimeTickLagCriticalThreshold.Key, "30")
+	defer paramtable.Get().Reset(Params.ProxyCfg.TimeTickLagCriticalThreshold.Key)
+	err := node.checkTimeTickLagAdmission()
+	assert.ErrorIs(t, err, merr.ErrServiceRateLimit)
+	assert.Contains(t, err.Error(), "p1")
+
+	paramtable.Get().Save(Params.ProxyCfg.TimeTickLagHealthThreshold.Key, "0")
+	defer paramtable.Get().Reset(Params.ProxyCfg.TimeTickLagHealthThreshold.Key)
+	assert.Empty(t, node.timeTickLagReasons())
+}
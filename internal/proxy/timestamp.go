@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
@@ -30,10 +31,31 @@ import (
 	"github.com/milvus-io/milvus/pkg/util/timerecord"
 )
 
+// timestampRequestBufferSize is the max number of the timestamp allocations waiting to be batched.
+const timestampRequestBufferSize = 4096
+
 // timestampAllocator implements tsoAllocator.
 type timestampAllocator struct {
 	tso    timestampAllocatorInterface
 	peerID UniqueID
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	pending   chan *timestampRequest
+	closeCh   chan struct{}
+
+	// the latest allocated timestamp and the time its allocation was requested
+	cacheMu       sync.RWMutex
+	lastTs        Timestamp
+	lastAllocTime time.Time
+}
+
+// timestampRequest is a single timestamp allocation to be merged into a batch.
+type timestampRequest struct {
+	submitTime time.Time
+	ts         Timestamp
+	err        error
+	done       chan struct{}
 }
 
 // newTimestampAllocator creates a new timestampAllocator
@@ -57,6 +79,7 @@ func (ta *timestampAllocator) alloc(ctx context.Context, count uint32) ([]Timest
 		Count: count,
 	}
 
+	requestTime := time.Now()
 	resp, err := ta.tso.AllocTimestamp(ctx, req)
 	defer func() {
 		metrics.ProxyApplyTimestampLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10)).Observe(float64(tr.ElapseSpan().Milliseconds()))
@@ -76,15 +99,151 @@ func (ta *timestampAllocator) alloc(ctx context.Context, count uint32) ([]Timest
 	for i := uint32(0); i < cnt; i++ {
 		ret[i] = start + uint64(i)
 	}
+	if cnt > 0 {
+		ta.updateCache(ret[cnt-1], requestTime)
+	}
 
 	return ret, nil
 }
 
-// AllocOne allocates a timestamp.
+func (ta *timestampAllocator) updateCache(ts Timestamp, allocTime time.Time) {
+	ta.cacheMu.Lock()
+	defer ta.cacheMu.Unlock()
+	if ts > ta.lastTs {
+		ta.lastTs = ts
+		ta.lastAllocTime = allocTime
+	}
+}
+
+// AllocCached returns the latest allocated timestamp if it's allocated within maxStaleness, otherwise
+// allocates a new one. The timestamp returned is not unique, and may be earlier than the events happened
+// within maxStaleness, it's only suitable for the reads tolerating staleness.
+func (ta *timestampAllocator) AllocCached(ctx context.Context, maxStaleness time.Duration) (Timestamp, error) {
+	ta.cacheMu.RLock()
+	ts, allocTime := ta.lastTs, ta.lastAllocTime
+	ta.cacheMu.RUnlock()
+	if ts != 0 && time.Since(allocTime) <= maxStaleness {
+		return ts, nil
+	}
+	return ta.AllocOne(ctx)
+}
+
+// AllocOne allocates a timestamp, the concurrent allocations are merged into one request if
+// proxy.timestampBatch.enabled is set.
 func (ta *timestampAllocator) AllocOne(ctx context.Context) (Timestamp, error) {
+	if Params.ProxyCfg.TimestampBatchEnabled.GetAsBool() {
+		return ta.batchAllocOne(ctx)
+	}
 	ret, err := ta.alloc(ctx, 1)
 	if err != nil {
 		return 0, err
 	}
 	return ret[0], nil
 }
+
+func (ta *timestampAllocator) start() {
+	ta.startOnce.Do(func() {
+		ta.pending = make(chan *timestampRequest, timestampRequestBufferSize)
+		ta.closeCh = make(chan struct{})
+		go ta.batchLoop()
+	})
+}
+
+// close stops the batch loop, the allocations not done yet will fail.
+func (ta *timestampAllocator) close() {
+	ta.start()
+	ta.closeOnce.Do(func() {
+		close(ta.closeCh)
+	})
+}
+
+func (ta *timestampAllocator) batchAllocOne(ctx context.Context) (Timestamp, error) {
+	ta.start()
+	req := &timestampRequest{submitTime: time.Now(), done: make(chan struct{})}
+	select {
+	case ta.pending <- req:
+	case <-ta.closeCh:
+		return 0, fmt.Errorf("timestamp allocator closed")
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	select {
+	case <-req.done:
+		return req.ts, req.err
+	case <-ta.closeCh:
+		return 0, fmt.Errorf("timestamp allocator closed")
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// batchLoop sends one request at a time, so the allocations submitted while a request is in flight are
+// merged into the next one. A batch is only allowed to wait for more allocations if the previous one
+// merged concurrent allocations, which keeps the latency unchanged under low load.
+// Every allocation in a batch is submitted before the request is sent, so the timestamps allocated are
+// still greater than any event happened before the allocation.
+func (ta *timestampAllocator) batchLoop() {
+	lastBatchSize := 0
+	for {
+		select {
+		case <-ta.closeCh:
+			return
+		case req := <-ta.pending:
+			batch := ta.collect(req, lastBatchSize > 1)
+			lastBatchSize = len(batch)
+			ta.dispatch(batch)
+		}
+	}
+}
+
+func (ta *timestampAllocator) collect(first *timestampRequest, wait bool) []*timestampRequest {
+	maxSize := Params.ProxyCfg.TimestampBatchMaxSize.GetAsInt()
+	batch := []*timestampRequest{first}
+
+	var timeout <-chan time.Time
+	if maxWait := Params.ProxyCfg.TimestampBatchMaxWait.GetAsDuration(time.Millisecond); wait && maxWait > 0 {
+		timer := time.NewTimer(maxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for len(batch) < maxSize {
+		select {
+		case req := <-ta.pending:
+			batch = append(batch, req)
+			continue
+		default:
+		}
+		if timeout == nil {
+			break
+		}
+		select {
+		case req := <-ta.pending:
+			batch = append(batch, req)
+		case <-timeout:
+			return batch
+		case <-ta.closeCh:
+			return batch
+		}
+	}
+	return batch
+}
+
+func (ta *timestampAllocator) dispatch(batch []*timestampRequest) {
+	nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	metrics.ProxyTimestampBatchSize.WithLabelValues(nodeID).Observe(float64(len(batch)))
+
+	ret, err := ta.alloc(context.Background(), uint32(len(batch)))
+	if err == nil && len(ret) < len(batch) {
+		err = fmt.Errorf("syncTimestamp Failed: %d timestamps allocated, %d expected", len(ret), len(batch))
+	}
+	for i, req := range batch {
+		if err != nil {
+			req.err = err
+		} else {
+			req.ts = ret[i]
+		}
+		close(req.done)
+		metrics.ProxyTimestampBatchWaitLatency.WithLabelValues(nodeID).Observe(float64(time.Since(req.submitTime).Milliseconds()))
+	}
+}
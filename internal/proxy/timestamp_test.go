@@ -19,10 +19,18 @@ package proxy
 import (
 	"context"
 	"math/rand"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
 
+	"github.com/milvus-io/milvus/internal/proto/rootcoordpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/util/uniquegenerator"
 )
 
@@ -62,3 +70,138 @@ func TestTimestampAllocator_AllocOne(t *testing.T) {
 	_, err = tsAllocator.AllocOne(ctx)
 	assert.NoError(t, err)
 }
+
+type blockingTimestampAllocatorInterface struct {
+	mu      sync.Mutex
+	next    Timestamp
+	counts  []uint32
+	entered chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func (tso *blockingTimestampAllocatorInterface) AllocTimestamp(ctx context.Context, req *rootcoordpb.AllocTimestampRequest, opts ...grpc.CallOption) (*rootcoordpb.AllocTimestampResponse, error) {
+	tso.entered <- struct{}{}
+	<-tso.release
+
+	tso.mu.Lock()
+	defer tso.mu.Unlock()
+	if tso.err != nil {
+		return nil, tso.err
+	}
+	tso.counts = append(tso.counts, req.GetCount())
+	ts := tso.next
+	tso.next += Timestamp(req.GetCount())
+	return &rootcoordpb.AllocTimestampResponse{
+		Status:    merr.Success(),
+		Timestamp: ts,
+		Count:     req.GetCount(),
+	}, nil
+}
+
+func TestTimestampAllocator_BatchAllocOne(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+
+	t.Run("merge concurrent allocations", func(t *testing.T) {
+		tso := &blockingTimestampAllocatorInterface{
+			next:    1000,
+			entered: make(chan struct{}, 10),
+			release: make(chan struct{}, 10),
+		}
+		tsAllocator, err := newTimestampAllocator(tso, 1)
+		assert.NoError(t, err)
+		defer tsAllocator.close()
+
+		const concurrency = 10
+		results := make(chan Timestamp, concurrency+1)
+		alloc := func() {
+			ts, err := tsAllocator.AllocOne(ctx)
+			assert.NoError(t, err)
+			results <- ts
+		}
+
+		// the allocations submitted while the first request is in flight are merged into one
+		go alloc()
+		<-tso.entered
+		for i := 0; i < concurrency; i++ {
+			go alloc()
+		}
+		assert.Eventually(t, func() bool {
+			return len(tsAllocator.pending) == concurrency
+		}, 5*time.Second, 10*time.Millisecond)
+		tso.release <- struct{}{}
+		<-tso.entered
+		tso.release <- struct{}{}
+
+		allocated := typeutil.NewSet[Timestamp]()
+		for i := 0; i < concurrency+1; i++ {
+			allocated.Insert(<-results)
+		}
+		assert.Equal(t, concurrency+1, allocated.Len())
+		assert.Equal(t, []uint32{1, concurrency}, tso.counts)
+	})
+
+	t.Run("alloc failed", func(t *testing.T) {
+		tso := &blockingTimestampAllocatorInterface{
+			entered: make(chan struct{}, 1),
+			release: make(chan struct{}, 1),
+			err:     errors.New("mock"),
+		}
+		tso.release <- struct{}{}
+		tsAllocator, err := newTimestampAllocator(tso, 1)
+		assert.NoError(t, err)
+		defer tsAllocator.close()
+
+		_, err = tsAllocator.AllocOne(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		tsAllocator, err := newTimestampAllocator(newMockTimestampAllocatorInterface(), 1)
+		assert.NoError(t, err)
+		tsAllocator.close()
+
+		_, err = tsAllocator.AllocOne(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("batch disabled", func(t *testing.T) {
+		paramtable.Get().Save(Params.ProxyCfg.TimestampBatchEnabled.Key, "false")
+		defer paramtable.Get().Reset(Params.ProxyCfg.TimestampBatchEnabled.Key)
+
+		tsAllocator, err := newTimestampAllocator(newMockTimestampAllocatorInterface(), 1)
+		assert.NoError(t, err)
+		_, err = tsAllocator.AllocOne(ctx)
+		assert.NoError(t, err)
+		assert.Nil(t, tsAllocator.pending)
+	})
+}
+
+func TestTimestampAllocator_AllocCached(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	tsAllocator, err := newTimestampAllocator(newMockTimestampAllocatorInterface(), 1)
+	assert.NoError(t, err)
+	defer tsAllocator.close()
+
+	// nothing cached yet
+	ts1, err := tsAllocator.AllocCached(ctx, time.Minute)
+	assert.NoError(t, err)
+
+	ts2, err := tsAllocator.AllocCached(ctx, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, ts1, ts2)
+
+	ts3, err := tsAllocator.AllocOne(ctx)
+	assert.NoError(t, err)
+	ts4, err := tsAllocator.AllocCached(ctx, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, ts3, ts4)
+
+	// the stale one is not used
+	tsAllocator.lastAllocTime = time.Now().Add(-2 * time.Minute)
+	ts5, err := tsAllocator.AllocCached(ctx, time.Minute)
+	assert.NoError(t, err)
+	assert.Greater(t, ts5, ts4)
+}
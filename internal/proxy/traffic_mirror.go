@@ -0,0 +1,322 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// mirrorConfig is the traffic mirroring configured in the collection properties.
+type mirrorConfig struct {
+	target      string
+	sampleRatio float64
+	writes      bool
+}
+
+// parseMirrorConfig returns nil if the mirror target is not set in the properties.
+func parseMirrorConfig(props []*commonpb.KeyValuePair) (*mirrorConfig, error) {
+	config := &mirrorConfig{sampleRatio: 1}
+	for _, p := range props {
+		var err error
+		switch p.GetKey() {
+		case common.CollectionMirrorTargetKey:
+			config.target = p.GetValue()
+		case common.CollectionMirrorSampleRatioKey:
+			config.sampleRatio, err = strconv.ParseFloat(p.GetValue(), 64)
+			if err != nil || config.sampleRatio < 0 || config.sampleRatio > 1 {
+				return nil, merr.WrapErrParameterInvalidMsg("invalid %s value %s, should be in [0, 1]", p.GetKey(), p.GetValue())
+			}
+		case common.CollectionMirrorWritesKey:
+			config.writes, err = strconv.ParseBool(p.GetValue())
+			if err != nil {
+				return nil, merr.WrapErrParameterInvalidMsg("invalid %s value %s, only true or false is allowed", p.GetKey(), p.GetValue())
+			}
+		}
+	}
+	if config.target == "" {
+		return nil, nil
+	}
+	return config, nil
+}
+
+// validateMirrorConfig checks the mirror properties to alter, the collection cannot be mirrored to itself.
+func validateMirrorConfig(collectionName string, props []*commonpb.KeyValuePair) error {
+	config, err := parseMirrorConfig(props)
+	if err != nil {
+		return err
+	}
+	if config != nil && config.target == collectionName {
+		return merr.WrapErrParameterInvalidMsg("collection %s cannot be mirrored to itself", collectionName)
+	}
+	return nil
+}
+
+type mirroredRequestKey struct{}
+
+func isMirroredRequest(ctx context.Context) bool {
+	return ctx.Value(mirroredRequestKey{}) != nil
+}
+
+var (
+	mirrorSlots     chan struct{}
+	mirrorSlotsOnce sync.Once
+)
+
+func getMirrorSlots() chan struct{} {
+	mirrorSlotsOnce.Do(func() {
+		mirrorSlots = make(chan struct{}, Params.ProxyCfg.MirrorMaxConcurrency.GetAsInt())
+	})
+	return mirrorSlots
+}
+
+// trafficMirror duplicates a sampled request to the mirror target collection asynchronously,
+// and records whether the mirrored result differs from the one of the request.
+// The mirrored request is executed as the caller, and dropped if the caller has no privilege on the target.
+type trafficMirror struct {
+	node       *Proxy
+	msgType    string
+	dbName     string
+	collection string
+	// request is the copy of the request with collection name replaced by the mirror target
+	request proto.Message
+	// md is the incoming metadata carrying the identity of the caller
+	md metadata.MD
+}
+
+// newTrafficMirror returns nil if the request is not sampled to mirror,
+// the request is copied before executing as the tasks may modify it.
+func (node *Proxy) newTrafficMirror(ctx context.Context, request proto.Message) *trafficMirror {
+	if isMirroredRequest(ctx) {
+		return nil
+	}
+	var dbName, collectionName, msgType string
+	write := false
+	switch r := request.(type) {
+	case *milvuspb.SearchRequest:
+		dbName, collectionName, msgType = r.GetDbName(), r.GetCollectionName(), metrics.SearchLabel
+	case *milvuspb.QueryRequest:
+		dbName, collectionName, msgType = r.GetDbName(), r.GetCollectionName(), metrics.QueryLabel
+	case *milvuspb.InsertRequest:
+		dbName, collectionName, msgType, write = r.GetDbName(), r.GetCollectionName(), metrics.InsertLabel, true
+	case *milvuspb.UpsertRequest:
+		dbName, collectionName, msgType, write = r.GetDbName(), r.GetCollectionName(), metrics.UpsertLabel, true
+	case *milvuspb.DeleteRequest:
+		dbName, collectionName, msgType, write = r.GetDbName(), r.GetCollectionName(), metrics.DeleteLabel, true
+	default:
+		return nil
+	}
+	if globalMetaCache == nil || collectionName == "" {
+		return nil
+	}
+
+	schema, err := globalMetaCache.GetCollectionSchema(ctx, dbName, collectionName)
+	if err != nil {
+		return nil
+	}
+	config, err := parseMirrorConfig(schema.GetProperties())
+	if err != nil || config == nil || (write && !config.writes) || rand.Float64() >= config.sampleRatio {
+		return nil
+	}
+
+	mirrored := proto.Clone(request)
+	switch r := mirrored.(type) {
+	case *milvuspb.SearchRequest:
+		r.CollectionName = config.target
+	case *milvuspb.QueryRequest:
+		r.CollectionName = config.target
+	case *milvuspb.InsertRequest:
+		r.CollectionName = config.target
+	case *milvuspb.UpsertRequest:
+		r.CollectionName = config.target
+	case *milvuspb.DeleteRequest:
+		r.CollectionName = config.target
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return &trafficMirror{
+		node:       node,
+		msgType:    msgType,
+		dbName:     dbName,
+		collection: collectionName,
+		request:    mirrored,
+		md:         md.Copy(),
+	}
+}
+
+// run executes the mirrored request in background if the request succeeded, nil mirror is a no-op.
+// The mirrored request is dropped if there are too many in-flight ones.
+func (m *trafficMirror) run(result proto.Message, err error) {
+	if m == nil || err != nil {
+		return
+	}
+	var status *commonpb.Status
+	switch r := result.(type) {
+	case *milvuspb.SearchResults:
+		status = r.GetStatus()
+	case *milvuspb.QueryResults:
+		status = r.GetStatus()
+	case *milvuspb.MutationResult:
+		status = r.GetStatus()
+	}
+	if !merr.Ok(status) {
+		return
+	}
+
+	nodeID := paramtable.GetStringNodeID()
+	select {
+	case getMirrorSlots() <- struct{}{}:
+	default:
+		metrics.ProxyMirrorRequestCount.WithLabelValues(nodeID, m.msgType, m.collection, metrics.AbandonLabel).Inc()
+		return
+	}
+
+	go func() {
+		defer func() { <-getMirrorSlots() }()
+		ctx := metadata.NewIncomingContext(context.WithValue(context.Background(), mirroredRequestKey{}, true), m.md)
+		ctx, cancel := context.WithTimeout(ctx, Params.ProxyCfg.MirrorTimeout.GetAsDuration(time.Millisecond))
+		defer cancel()
+
+		mirroredResult, err := m.execute(ctx)
+		if err != nil {
+			log.Ctx(ctx).RatedWarn(10, "failed to execute mirrored request",
+				zap.String("collection", m.collection), zap.String("type", m.msgType), zap.Error(err))
+			metrics.ProxyMirrorRequestCount.WithLabelValues(nodeID, m.msgType, m.collection, metrics.FailLabel).Inc()
+			return
+		}
+		metrics.ProxyMirrorRequestCount.WithLabelValues(nodeID, m.msgType, m.collection, metrics.SuccessLabel).Inc()
+
+		if overlap, ok := m.overlap(ctx, result, mirroredResult); ok {
+			metrics.ProxyMirrorResultOverlap.WithLabelValues(nodeID, m.msgType, m.collection).Observe(overlap)
+		}
+	}()
+}
+
+func (m *trafficMirror) execute(ctx context.Context) (proto.Message, error) {
+	// the request is executed without the interceptors
+	ctx, err := PrivilegeInterceptor(ctx, m.request)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		result proto.Message
+		status *commonpb.Status
+	)
+	switch r := m.request.(type) {
+	case *milvuspb.SearchRequest:
+		var resp *milvuspb.SearchResults
+		resp, err = m.node.Search(ctx, r)
+		result, status = resp, resp.GetStatus()
+	case *milvuspb.QueryRequest:
+		var resp *milvuspb.QueryResults
+		resp, err = m.node.Query(ctx, r)
+		result, status = resp, resp.GetStatus()
+	case *milvuspb.InsertRequest:
+		var resp *milvuspb.MutationResult
+		resp, err = m.node.Insert(ctx, r)
+		result, status = resp, resp.GetStatus()
+	case *milvuspb.UpsertRequest:
+		var resp *milvuspb.MutationResult
+		resp, err = m.node.Upsert(ctx, r)
+		result, status = resp, resp.GetStatus()
+	case *milvuspb.DeleteRequest:
+		var resp *milvuspb.MutationResult
+		resp, err = m.node.Delete(ctx, r)
+		result, status = resp, resp.GetStatus()
+	default:
+		return nil, fmt.Errorf("unsupported mirrored request %T", m.request)
+	}
+	if err == nil {
+		err = merr.Error(status)
+	}
+	return result, err
+}
+
+// overlap returns the ratio of the primary keys in both results to the larger result,
+// only the search and query results are compared.
+func (m *trafficMirror) overlap(ctx context.Context, result, mirroredResult proto.Message) (float64, bool) {
+	var ids, mirroredIDs *schemapb.IDs
+	switch r := result.(type) {
+	case *milvuspb.SearchResults:
+		ids, mirroredIDs = r.GetResults().GetIds(), mirroredResult.(*milvuspb.SearchResults).GetResults().GetIds()
+	case *milvuspb.QueryResults:
+		schema, err := globalMetaCache.GetCollectionSchema(ctx, m.dbName, m.collection)
+		if err != nil {
+			return 0, false
+		}
+		ids = queryResultIDs(schema.CollectionSchema, r.GetFieldsData())
+		mirroredIDs = queryResultIDs(schema.CollectionSchema, mirroredResult.(*milvuspb.QueryResults).GetFieldsData())
+	default:
+		return 0, false
+	}
+	return idsOverlap(ids, mirroredIDs), true
+}
+
+func queryResultIDs(schema *schemapb.CollectionSchema, fieldsData []*schemapb.FieldData) *schemapb.IDs {
+	pkField, err := typeutil.GetPrimaryFieldSchema(schema)
+	if err != nil {
+		return nil
+	}
+	pkData, err := typeutil.GetPrimaryFieldData(fieldsData, pkField)
+	if err != nil {
+		return nil
+	}
+	ids, err := parsePrimaryFieldData2IDs(pkData)
+	if err != nil {
+		return nil
+	}
+	return ids
+}
+
+// idsOverlap returns the number of the ids in both a and b divided by the larger size, 1 if both are empty.
+func idsOverlap(a, b *schemapb.IDs) float64 {
+	sizeA, sizeB := typeutil.GetSizeOfIDs(a), typeutil.GetSizeOfIDs(b)
+	if sizeA == 0 && sizeB == 0 {
+		return 1
+	}
+	set := typeutil.NewSet[any]()
+	for i := 0; i < sizeA; i++ {
+		set.Insert(typeutil.GetPK(a, int64(i)))
+	}
+	matched := 0
+	for i := 0; i < sizeB; i++ {
+		if set.Contain(typeutil.GetPK(b, int64(i))) {
+			matched++
+		}
+	}
+	if sizeA < sizeB {
+		sizeA = sizeB
+	}
+	return float64(matched) / float64(sizeA)
+}
@@ -0,0 +1,126 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+func TestParseMirrorConfig(t *testing.T) {
+	config, err := parseMirrorConfig(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	config, err = parseMirrorConfig([]*commonpb.KeyValuePair{
+		{Key: common.CollectionMirrorTargetKey, Value: "green"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &mirrorConfig{target: "green", sampleRatio: 1}, config)
+
+	config, err = parseMirrorConfig([]*commonpb.KeyValuePair{
+		{Key: common.CollectionMirrorTargetKey, Value: "green"},
+		{Key: common.CollectionMirrorSampleRatioKey, Value: "0.1"},
+		{Key: common.CollectionMirrorWritesKey, Value: "true"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &mirrorConfig{target: "green", sampleRatio: 0.1, writes: true}, config)
+
+	for _, kv := range []*commonpb.KeyValuePair{
+		{Key: common.CollectionMirrorSampleRatioKey, Value: "1.5"},
+		{Key: common.CollectionMirrorSampleRatioKey, Value: "abc"},
+		{Key: common.CollectionMirrorWritesKey, Value: "yes"},
+	} {
+		_, err = parseMirrorConfig([]*commonpb.KeyValuePair{kv})
+		assert.Error(t, err, kv.GetValue())
+	}
+
+	assert.NoError(t, validateMirrorConfig("blue", []*commonpb.KeyValuePair{{Key: common.CollectionMirrorTargetKey, Value: "green"}}))
+	assert.Error(t, validateMirrorConfig("blue", []*commonpb.KeyValuePair{{Key: common.CollectionMirrorTargetKey, Value: "blue"}}))
+}
+
+func TestNewTrafficMirror(t *testing.T) {
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetCollectionSchema(mock.Anything, mock.Anything, "blue").Return(newSchemaInfo(&schemapb.CollectionSchema{
+		Properties: []*commonpb.KeyValuePair{{Key: common.CollectionMirrorTargetKey, Value: "green"}},
+	}), nil)
+	mockCache.EXPECT().GetCollectionSchema(mock.Anything, mock.Anything, "other").Return(newSchemaInfo(&schemapb.CollectionSchema{}), nil)
+	globalMetaCache = mockCache
+
+	node := &Proxy{}
+	request := &milvuspb.SearchRequest{CollectionName: "blue"}
+	mirror := node.newTrafficMirror(GetContext(context.Background(), "alice:123456"), request)
+	assert.NotNil(t, mirror)
+	assert.Equal(t, "green", mirror.request.(*milvuspb.SearchRequest).GetCollectionName())
+	assert.Equal(t, "blue", request.GetCollectionName())
+	// the mirrored request is executed as the caller
+	username, err := GetCurUserFromContext(metadata.NewIncomingContext(context.Background(), mirror.md))
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", username)
+
+	// writes are not mirrored by default
+	assert.Nil(t, node.newTrafficMirror(context.Background(), &milvuspb.InsertRequest{CollectionName: "blue"}))
+	assert.Nil(t, node.newTrafficMirror(context.Background(), &milvuspb.SearchRequest{CollectionName: "other"}))
+	// the mirrored requests are not mirrored again
+	ctx := context.WithValue(context.Background(), mirroredRequestKey{}, true)
+	assert.Nil(t, node.newTrafficMirror(ctx, request))
+
+	// nil mirror is a no-op
+	var nilMirror *trafficMirror
+	nilMirror.run(&milvuspb.SearchResults{}, nil)
+}
+
+func TestIDsOverlap(t *testing.T) {
+	intIDs := func(ids ...int64) *schemapb.IDs {
+		return &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}}
+	}
+	assert.Equal(t, float64(1), idsOverlap(nil, nil))
+	assert.Equal(t, float64(1), idsOverlap(intIDs(1, 2), intIDs(2, 1)))
+	assert.Equal(t, 0.5, idsOverlap(intIDs(1, 2), intIDs(2, 3)))
+	assert.Equal(t, 0.25, idsOverlap(intIDs(1), intIDs(1, 2, 3, 4)))
+	assert.Equal(t, float64(0), idsOverlap(intIDs(1), nil))
+
+	strIDs := &schemapb.IDs{IdField: &schemapb.IDs_StrId{StrId: &schemapb.StringArray{Data: []string{"a", "b"}}}}
+	assert.Equal(t, float64(1), idsOverlap(strIDs, strIDs))
+}
+
+func TestQueryResultIDs(t *testing.T) {
+	schema := &schemapb.CollectionSchema{Fields: []*schemapb.FieldSchema{
+		{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+	}}
+	ids := queryResultIDs(schema, []*schemapb.FieldData{{
+		FieldName: "pk",
+		Type:      schemapb.DataType_Int64,
+		Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{Data: &schemapb.ScalarField_LongData{
+			LongData: &schemapb.LongArray{Data: []int64{1, 2}},
+		}}},
+	}})
+	assert.Equal(t, []int64{1, 2}, ids.GetIntId().GetData())
+	assert.Nil(t, queryResultIDs(schema, nil))
+}
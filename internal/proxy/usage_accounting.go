@@ -0,0 +1,459 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/internal/kv/predicates"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/util/requestutil"
+)
+
+const (
+	// usageCheckpointPrefix is the etcd prefix(under meta root path) of the usage checkpoints, one key per proxy.
+	usageCheckpointPrefix = "proxy/usage"
+	// usageCheckpointStaleIntervals is the number of checkpoint intervals after which the checkpoint of a proxy is
+	// considered left behind by a stopped proxy, and adopted by a running one.
+	usageCheckpointStaleIntervals = 3
+
+	usageRequests       = "requests"
+	usageBytesIn        = "bytes_in"
+	usageBytesOut       = "bytes_out"
+	usageInsertedRows   = "inserted_rows"
+	usageUpsertedRows   = "upserted_rows"
+	usageDeletedRows    = "deleted_rows"
+	usageScannedVectors = "scanned_vectors"
+)
+
+// Usage is the accumulated usage of a user in a database.
+type Usage struct {
+	DBName         string `json:"db_name"`
+	User           string `json:"user"`
+	Requests       int64  `json:"requests"`
+	BytesIn        int64  `json:"bytes_in"`
+	BytesOut       int64  `json:"bytes_out"`
+	InsertedRows   int64  `json:"inserted_rows"`
+	UpsertedRows   int64  `json:"upserted_rows"`
+	DeletedRows    int64  `json:"deleted_rows"`
+	ScannedVectors int64  `json:"scanned_vectors"`
+}
+
+func (u *Usage) add(other *Usage) {
+	u.Requests += other.Requests
+	u.BytesIn += other.BytesIn
+	u.BytesOut += other.BytesOut
+	u.InsertedRows += other.InsertedRows
+	u.UpsertedRows += other.UpsertedRows
+	u.DeletedRows += other.DeletedRows
+	u.ScannedVectors += other.ScannedVectors
+}
+
+type usageKey struct {
+	dbName string
+	user   string
+}
+
+// usageCheckpoint is the persisted usage of a proxy.
+type usageCheckpoint struct {
+	// UpdatedAt is the unix time of the checkpoint in seconds
+	UpdatedAt int64    `json:"updated_at"`
+	Usages    []*Usage `json:"usages"`
+}
+
+// usageAccountant accumulates the usage of each database and user in this proxy,
+// and checkpoints them to etcd periodically so that the usage survives proxy restarts.
+// The checkpoint left behind by a stopped proxy is adopted by a running proxy, which merges it into its own usage.
+type usageAccountant struct {
+	mu     sync.Mutex
+	usages map[usageKey]*Usage
+	kv     kv.TxnKV
+}
+
+var globalUsageAccountant = newUsageAccountant()
+
+func newUsageAccountant() *usageAccountant {
+	return &usageAccountant{
+		usages: make(map[usageKey]*Usage),
+	}
+}
+
+// init restores the checkpoint of this proxy and starts the checkpoint loop.
+func (a *usageAccountant) init(ctx context.Context, txnKV kv.TxnKV) {
+	a.mu.Lock()
+	a.kv = txnKV
+	a.mu.Unlock()
+
+	if err := a.restore(); err != nil {
+		log.Warn("failed to restore usage checkpoint", zap.Error(err))
+	}
+
+	go func() {
+		ticker := time.NewTicker(paramtable.Get().ProxyCfg.UsageAccountingCheckpointInterval.GetAsDuration(time.Second))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				if err := a.checkpoint(); err != nil {
+					log.Warn("failed to checkpoint usage", zap.Error(err))
+				}
+				return
+			case <-ticker.C:
+				if err := a.adoptStaleCheckpoints(); err != nil {
+					log.Warn("failed to adopt stale usage checkpoints", zap.Error(err))
+				}
+				if err := a.checkpoint(); err != nil {
+					log.Warn("failed to checkpoint usage", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+func (a *usageAccountant) getKV() kv.TxnKV {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.kv
+}
+
+func (a *usageAccountant) checkpointKey() string {
+	return path.Join(usageCheckpointPrefix, strconv.FormatInt(paramtable.GetNodeID(), 10))
+}
+
+func (a *usageAccountant) marshal(usages []*Usage) (string, error) {
+	value, err := json.Marshal(&usageCheckpoint{UpdatedAt: time.Now().Unix(), Usages: usages})
+	return string(value), err
+}
+
+func (a *usageAccountant) merge(usages []*Usage) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, usage := range usages {
+		key := usageKey{dbName: usage.DBName, user: usage.User}
+		if _, ok := a.usages[key]; !ok {
+			a.usages[key] = &Usage{DBName: usage.DBName, User: usage.User}
+		}
+		a.usages[key].add(usage)
+	}
+}
+
+// restore merges the checkpoint of this proxy, which exists if the proxy restarts with the same node id.
+func (a *usageAccountant) restore() error {
+	txnKV := a.getKV()
+	if txnKV == nil {
+		return nil
+	}
+	value, err := txnKV.Load(a.checkpointKey())
+	if err != nil {
+		if errors.Is(err, merr.ErrIoKeyNotFound) {
+			return nil
+		}
+		return err
+	}
+	checkpoint := &usageCheckpoint{}
+	if err := json.Unmarshal([]byte(value), checkpoint); err != nil {
+		return err
+	}
+	a.merge(checkpoint.Usages)
+	return nil
+}
+
+// checkpoint persists the usage of this proxy.
+func (a *usageAccountant) checkpoint() error {
+	txnKV := a.getKV()
+	if txnKV == nil {
+		return nil
+	}
+	usages := a.List("", "")
+	if len(usages) == 0 {
+		return nil
+	}
+	value, err := a.marshal(usages)
+	if err != nil {
+		return err
+	}
+	return txnKV.Save(a.checkpointKey(), value)
+}
+
+// adoptStaleCheckpoints merges the checkpoints not updated for usageCheckpointStaleIntervals into the usage of this
+// proxy and removes them. The removal is conditioned on the value of the stale checkpoint in the same transaction as
+// the checkpoint of this proxy, so a stale checkpoint is adopted by only one proxy.
+func (a *usageAccountant) adoptStaleCheckpoints() error {
+	txnKV := a.getKV()
+	if txnKV == nil {
+		return nil
+	}
+	keys, values, err := txnKV.LoadWithPrefix(usageCheckpointPrefix)
+	if err != nil {
+		return err
+	}
+	interval := paramtable.Get().ProxyCfg.UsageAccountingCheckpointInterval.GetAsDuration(time.Second)
+	staleBefore := time.Now().Add(-usageCheckpointStaleIntervals * interval).Unix()
+	for i, value := range values {
+		if path.Base(keys[i]) == strconv.FormatInt(paramtable.GetNodeID(), 10) {
+			continue
+		}
+		checkpoint := &usageCheckpoint{}
+		if err := json.Unmarshal([]byte(value), checkpoint); err != nil {
+			log.Warn("skip invalid usage checkpoint", zap.String("key", keys[i]), zap.Error(err))
+			continue
+		}
+		if checkpoint.UpdatedAt >= staleBefore {
+			continue
+		}
+
+		adopted := newUsageAccountant()
+		adopted.merge(a.List("", ""))
+		adopted.merge(checkpoint.Usages)
+		ownValue, err := a.marshal(adopted.List("", ""))
+		if err != nil {
+			return err
+		}
+		err = txnKV.MultiSaveAndRemove(map[string]string{a.checkpointKey(): ownValue}, []string{keys[i]},
+			predicates.ValueEqual(keys[i], value))
+		if err != nil {
+			// adopted by another proxy, or the stopped proxy came back
+			log.Info("skip adopting usage checkpoint", zap.String("key", keys[i]), zap.Error(err))
+			continue
+		}
+		a.merge(checkpoint.Usages)
+		log.Info("adopt stale usage checkpoint", zap.String("key", keys[i]), zap.Int64("updatedAt", checkpoint.UpdatedAt))
+	}
+	return nil
+}
+
+func (a *usageAccountant) record(dbName, user string, fn func(usage *Usage)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	key := usageKey{dbName: dbName, user: user}
+	usage, ok := a.usages[key]
+	if !ok {
+		usage = &Usage{DBName: dbName, User: user}
+		a.usages[key] = usage
+	}
+	fn(usage)
+}
+
+// List returns the usage of this proxy filtered by database and user, empty filter matches all.
+func (a *usageAccountant) List(dbName, user string) []*Usage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	usages := make([]*Usage, 0, len(a.usages))
+	for key, usage := range a.usages {
+		if (dbName == "" || key.dbName == dbName) && (user == "" || key.user == user) {
+			u := *usage
+			usages = append(usages, &u)
+		}
+	}
+	sortUsages(usages)
+	return usages
+}
+
+// ListCluster returns the usage of all proxies, merged from the checkpoints and the usage of this proxy.
+func (a *usageAccountant) ListCluster(dbName, user string) ([]*Usage, error) {
+	txnKV := a.getKV()
+	if txnKV == nil {
+		return a.List(dbName, user), nil
+	}
+	keys, values, err := txnKV.LoadWithPrefix(usageCheckpointPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[usageKey]*Usage)
+	merge := func(usages []*Usage) {
+		for _, usage := range usages {
+			if (dbName != "" && usage.DBName != dbName) || (user != "" && usage.User != user) {
+				continue
+			}
+			key := usageKey{dbName: usage.DBName, user: usage.User}
+			if _, ok := merged[key]; !ok {
+				merged[key] = &Usage{DBName: usage.DBName, User: usage.User}
+			}
+			merged[key].add(usage)
+		}
+	}
+	for i, value := range values {
+		// the checkpoint of this proxy may be stale, use the in-memory one instead
+		if path.Base(keys[i]) == strconv.FormatInt(paramtable.GetNodeID(), 10) {
+			continue
+		}
+		checkpoint := &usageCheckpoint{}
+		if err := json.Unmarshal([]byte(value), checkpoint); err != nil {
+			log.Warn("skip invalid usage checkpoint", zap.String("key", keys[i]), zap.Error(err))
+			continue
+		}
+		merge(checkpoint.Usages)
+	}
+	merge(a.List(dbName, user))
+
+	usages := make([]*Usage, 0, len(merged))
+	for _, usage := range merged {
+		usages = append(usages, usage)
+	}
+	sortUsages(usages)
+	return usages, nil
+}
+
+func sortUsages(usages []*Usage) {
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].DBName != usages[j].DBName {
+			return usages[i].DBName < usages[j].DBName
+		}
+		return usages[i].User < usages[j].User
+	})
+}
+
+func recordUsageMetrics(dbName, user string, values map[string]int64) {
+	nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	for usageType, value := range values {
+		if value > 0 {
+			metrics.ProxyTenantUsage.WithLabelValues(nodeID, dbName, user, usageType).Add(float64(value))
+		}
+	}
+}
+
+type usageRPCKey struct{}
+
+// usageRPC is the usage of an rpc, the bytes are counted by the stats handler from the wire lengths grpc computes,
+// so the messages are not measured again. The database and the user are set by the interceptor.
+type usageRPC struct {
+	bytesIn  atomic.Int64
+	bytesOut atomic.Int64
+	identity atomic.Pointer[usageKey]
+}
+
+// UsageStatsHandler is the grpc stats handler accounting the bytes received and sent of each database and user.
+type UsageStatsHandler struct{}
+
+// TagRPC attaches the usageRPC to the context of the rpc.
+func (UsageStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	if !paramtable.Get().ProxyCfg.UsageAccountingEnabled.GetAsBool() {
+		return ctx
+	}
+	return context.WithValue(ctx, usageRPCKey{}, &usageRPC{})
+}
+
+// HandleRPC counts the payload bytes and records them at the end of the rpc.
+func (UsageStatsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	rpc, ok := ctx.Value(usageRPCKey{}).(*usageRPC)
+	if !ok {
+		return
+	}
+	switch s := rs.(type) {
+	case *stats.InPayload:
+		rpc.bytesIn.Add(int64(s.WireLength))
+	case *stats.OutPayload:
+		rpc.bytesOut.Add(int64(s.WireLength))
+	case *stats.End:
+		identity := rpc.identity.Load()
+		if identity == nil {
+			return
+		}
+		bytesIn, bytesOut := rpc.bytesIn.Load(), rpc.bytesOut.Load()
+		globalUsageAccountant.record(identity.dbName, identity.user, func(usage *Usage) {
+			usage.BytesIn += bytesIn
+			usage.BytesOut += bytesOut
+		})
+		recordUsageMetrics(identity.dbName, identity.user, map[string]int64{
+			usageBytesIn:  bytesIn,
+			usageBytesOut: bytesOut,
+		})
+	}
+}
+
+// TagConn implements stats.Handler.
+func (UsageStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn implements stats.Handler.
+func (UsageStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// UsageAccountingInterceptor returns a new unary server interceptor that accounts the usage of each database and user.
+// The bytes are accounted by UsageStatsHandler, which must be installed to the same server.
+func UsageAccountingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !paramtable.Get().ProxyCfg.UsageAccountingEnabled.GetAsBool() {
+			return handler(ctx, req)
+		}
+
+		resp, err := handler(ctx, req)
+
+		dbName := GetCurDBNameFromContextOrDefault(ctx)
+		if name, ok := requestutil.GetDbNameFromRequest(req); ok && name.(string) != "" {
+			dbName = name.(string)
+		}
+		user := GetCurUserFromContextOrDefault(ctx)
+		if rpc, ok := ctx.Value(usageRPCKey{}).(*usageRPC); ok {
+			rpc.identity.Store(&usageKey{dbName: dbName, user: user})
+		}
+		delta := &Usage{
+			Requests: 1,
+		}
+		if err == nil {
+			switch req.(type) {
+			case *milvuspb.InsertRequest:
+				if result, ok := resp.(*milvuspb.MutationResult); ok && merr.Ok(result.GetStatus()) {
+					delta.InsertedRows = result.GetInsertCnt()
+				}
+			case *milvuspb.UpsertRequest:
+				if result, ok := resp.(*milvuspb.MutationResult); ok && merr.Ok(result.GetStatus()) {
+					delta.UpsertedRows = result.GetUpsertCnt()
+				}
+			case *milvuspb.DeleteRequest:
+				if result, ok := resp.(*milvuspb.MutationResult); ok && merr.Ok(result.GetStatus()) {
+					delta.DeletedRows = result.GetDeleteCnt()
+				}
+			case *milvuspb.SearchRequest, *milvuspb.HybridSearchRequest:
+				// the number of entities the search compares with, reported by the query nodes
+				if result, ok := resp.(*milvuspb.SearchResults); ok && merr.Ok(result.GetStatus()) {
+					delta.ScannedVectors = result.GetResults().GetAllSearchCount()
+				}
+			}
+		}
+
+		globalUsageAccountant.record(dbName, user, func(usage *Usage) {
+			usage.add(delta)
+		})
+		recordUsageMetrics(dbName, user, map[string]int64{
+			usageRequests:       delta.Requests,
+			usageInsertedRows:   delta.InsertedRows,
+			usageUpsertedRows:   delta.UpsertedRows,
+			usageDeletedRows:    delta.DeletedRows,
+			usageScannedVectors: delta.ScannedVectors,
+		})
+		return resp, err
+	}
+}
@@ -0,0 +1,202 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/kv/mocks"
+	"github.com/milvus-io/milvus/internal/kv/predicates"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestUsageAccountant(t *testing.T) {
+	paramtable.Init()
+
+	a := newUsageAccountant()
+	a.record("db1", "u1", func(usage *Usage) { usage.add(&Usage{Requests: 1, InsertedRows: 10}) })
+	a.record("db1", "u1", func(usage *Usage) { usage.add(&Usage{Requests: 1, DeletedRows: 2}) })
+	a.record("db2", "u1", func(usage *Usage) { usage.add(&Usage{Requests: 1}) })
+
+	usages := a.List("", "")
+	assert.Len(t, usages, 2)
+	assert.Equal(t, "db1", usages[0].DBName)
+	assert.EqualValues(t, 2, usages[0].Requests)
+	assert.EqualValues(t, 10, usages[0].InsertedRows)
+	assert.EqualValues(t, 2, usages[0].DeletedRows)
+	assert.Len(t, a.List("db2", ""), 1)
+	assert.Len(t, a.List("", "u2"), 0)
+
+	// no kv, cluster usage is the same as node usage
+	usages, err := a.ListCluster("", "")
+	assert.NoError(t, err)
+	assert.Len(t, usages, 2)
+	assert.NoError(t, a.checkpoint())
+
+	watchKV := mocks.NewWatchKV(t)
+	a.kv = watchKV
+	watchKV.EXPECT().Save(a.checkpointKey(), mock.Anything).Return(nil)
+	assert.NoError(t, a.checkpoint())
+
+	other, _ := json.Marshal(&usageCheckpoint{
+		UpdatedAt: time.Now().Unix(),
+		Usages:    []*Usage{{DBName: "db1", User: "u1", Requests: 3}, {DBName: "db3", User: "u2", Requests: 1}},
+	})
+	watchKV.EXPECT().LoadWithPrefix(usageCheckpointPrefix).Return(
+		[]string{"proxy/usage/-1", a.checkpointKey()},
+		[]string{string(other), "stale"}, nil).Once()
+	usages, err = a.ListCluster("", "")
+	assert.NoError(t, err)
+	assert.Len(t, usages, 3)
+	assert.EqualValues(t, 5, usages[0].Requests)
+}
+
+func TestUsageAccountantRestore(t *testing.T) {
+	paramtable.Init()
+
+	a := newUsageAccountant()
+	assert.NoError(t, a.restore())
+
+	watchKV := mocks.NewWatchKV(t)
+	a.kv = watchKV
+	watchKV.EXPECT().Load(a.checkpointKey()).Return("", merr.WrapErrIoKeyNotFound(a.checkpointKey())).Once()
+	assert.NoError(t, a.restore())
+	assert.Empty(t, a.List("", ""))
+
+	watchKV.EXPECT().Load(a.checkpointKey()).Return("invalid", nil).Once()
+	assert.Error(t, a.restore())
+
+	value, _ := json.Marshal(&usageCheckpoint{Usages: []*Usage{{DBName: "db1", User: "u1", Requests: 3}}})
+	watchKV.EXPECT().Load(a.checkpointKey()).Return(string(value), nil).Once()
+	assert.NoError(t, a.restore())
+	usages := a.List("", "")
+	assert.Len(t, usages, 1)
+	assert.EqualValues(t, 3, usages[0].Requests)
+}
+
+func TestUsageAccountantAdoptStaleCheckpoints(t *testing.T) {
+	paramtable.Init()
+
+	a := newUsageAccountant()
+	assert.NoError(t, a.adoptStaleCheckpoints())
+	a.record("db1", "u1", func(usage *Usage) { usage.add(&Usage{Requests: 1}) })
+
+	watchKV := mocks.NewWatchKV(t)
+	a.kv = watchKV
+	stale, _ := json.Marshal(&usageCheckpoint{UpdatedAt: 1, Usages: []*Usage{{DBName: "db1", User: "u1", Requests: 2}}})
+	staleByOther, _ := json.Marshal(&usageCheckpoint{UpdatedAt: 1, Usages: []*Usage{{DBName: "db2", User: "u1", Requests: 5}}})
+	alive, _ := json.Marshal(&usageCheckpoint{UpdatedAt: time.Now().Unix(), Usages: []*Usage{{DBName: "db1", User: "u1", Requests: 4}}})
+	watchKV.EXPECT().LoadWithPrefix(usageCheckpointPrefix).Return(
+		[]string{"proxy/usage/-1", "proxy/usage/-2", "proxy/usage/-3", "proxy/usage/-4", a.checkpointKey()},
+		[]string{string(stale), string(staleByOther), string(alive), "invalid", "{}"}, nil)
+	watchKV.EXPECT().MultiSaveAndRemove(mock.Anything, []string{"proxy/usage/-1"}, mock.Anything).
+		RunAndReturn(func(saves map[string]string, removals []string, preds ...predicates.Predicate) error {
+			checkpoint := &usageCheckpoint{}
+			assert.NoError(t, json.Unmarshal([]byte(saves[a.checkpointKey()]), checkpoint))
+			assert.EqualValues(t, 3, checkpoint.Usages[0].Requests)
+			assert.Len(t, preds, 1)
+			return nil
+		})
+	// adopted by another proxy
+	watchKV.EXPECT().MultiSaveAndRemove(mock.Anything, []string{"proxy/usage/-2"}, mock.Anything).
+		Return(merr.WrapErrIoFailedReason("predicate failed"))
+
+	assert.NoError(t, a.adoptStaleCheckpoints())
+	usages := a.List("", "")
+	assert.Len(t, usages, 1)
+	assert.EqualValues(t, 3, usages[0].Requests)
+}
+
+func TestUsageAccountingInterceptor(t *testing.T) {
+	paramtable.Init()
+	defer func() {
+		globalUsageAccountant = newUsageAccountant()
+	}()
+	globalUsageAccountant = newUsageAccountant()
+
+	interceptor := UsageAccountingInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/milvus.proto.milvus.MilvusService/Insert"}
+	insertHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &milvuspb.MutationResult{Status: merr.Success(), InsertCnt: 10}, nil
+	}
+
+	// disabled by default
+	_, err := interceptor(context.Background(), &milvuspb.InsertRequest{DbName: "db1"}, info, insertHandler)
+	assert.NoError(t, err)
+	assert.Empty(t, globalUsageAccountant.List("", ""))
+
+	paramtable.Get().Save(Params.ProxyCfg.UsageAccountingEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.UsageAccountingEnabled.Key)
+
+	_, err = interceptor(context.Background(), &milvuspb.InsertRequest{DbName: "db1"}, info, insertHandler)
+	assert.NoError(t, err)
+	_, err = interceptor(context.Background(), &milvuspb.SearchRequest{DbName: "db1", Nq: 5}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &milvuspb.SearchResults{Status: merr.Success(), Results: &schemapb.SearchResultData{AllSearchCount: 1000}}, nil
+	})
+	assert.NoError(t, err)
+
+	usages := globalUsageAccountant.List("db1", "")
+	assert.Len(t, usages, 1)
+	assert.EqualValues(t, 2, usages[0].Requests)
+	assert.EqualValues(t, 10, usages[0].InsertedRows)
+	assert.EqualValues(t, 1000, usages[0].ScannedVectors)
+}
+
+func TestUsageStatsHandler(t *testing.T) {
+	paramtable.Init()
+	defer func() {
+		globalUsageAccountant = newUsageAccountant()
+	}()
+	globalUsageAccountant = newUsageAccountant()
+
+	handler := UsageStatsHandler{}
+	ctx := handler.TagRPC(context.Background(), &stats.RPCTagInfo{})
+	assert.Nil(t, ctx.Value(usageRPCKey{}))
+	handler.HandleRPC(ctx, &stats.End{})
+
+	paramtable.Get().Save(Params.ProxyCfg.UsageAccountingEnabled.Key, "true")
+	defer paramtable.Get().Reset(Params.ProxyCfg.UsageAccountingEnabled.Key)
+
+	ctx = handler.TagRPC(context.Background(), &stats.RPCTagInfo{})
+	handler.HandleRPC(ctx, &stats.InPayload{WireLength: 100})
+	_, err := UsageAccountingInterceptor()(ctx, &milvuspb.DeleteRequest{DbName: "db1"},
+		&grpc.UnaryServerInfo{FullMethod: "/milvus.proto.milvus.MilvusService/Delete"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &milvuspb.MutationResult{Status: merr.Success(), DeleteCnt: 3}, nil
+		})
+	assert.NoError(t, err)
+	handler.HandleRPC(ctx, &stats.OutPayload{WireLength: 20})
+	handler.HandleRPC(ctx, &stats.End{})
+
+	usages := globalUsageAccountant.List("db1", "")
+	assert.Len(t, usages, 1)
+	assert.EqualValues(t, 1, usages[0].Requests)
+	assert.EqualValues(t, 3, usages[0].DeletedRows)
+	assert.EqualValues(t, 100, usages[0].BytesIn)
+	assert.EqualValues(t, 20, usages[0].BytesOut)
+}
@@ -39,6 +39,7 @@ import (
 	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/util"
 	"github.com/milvus-io/milvus/pkg/util/commonpbutil"
@@ -209,6 +210,9 @@ func ValidateCollectionAlias(collAlias string) error {
 }
 
 func validateCollectionName(collName string) error {
+	if err := checkNotRecycledCollection(collName); err != nil {
+		return err
+	}
 	return validateCollectionNameOrAlias(collName, "name")
 }
 
@@ -916,11 +920,32 @@ func GetRole(username string) ([]string, error) {
 	if globalMetaCache == nil {
 		return []string{}, merr.WrapErrServiceUnavailable("internal: Milvus Proxy is not ready yet. please wait")
 	}
-	return globalMetaCache.GetUserRole(username), nil
+	roles := globalMetaCache.GetUserRole(username)
+	if globalLDAPAuthenticator.Enabled() {
+		roles = append(roles, globalLDAPAuthenticator.Roles(username)...)
+	}
+	return roles, nil
+}
+
+// IsAdminUser returns whether the user is the root user or granted the admin role.
+func IsAdminUser(username string) (bool, error) {
+	if username == util.UserRoot {
+		return true, nil
+	}
+	roles, err := GetRole(username)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roles {
+		if role == util.RoleAdmin {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func PasswordVerify(ctx context.Context, username, rawPwd string) bool {
-	return passwordVerify(ctx, username, rawPwd, globalMetaCache)
+	return authenticate(ctx, username, rawPwd)
 }
 
 func VerifyAPIKey(rawToken string) (string, error) {
@@ -1080,6 +1105,20 @@ func validateIndexName(indexName string) error {
 	return nil
 }
 
+// isConfirmedByMetadata returns whether the incoming request metadata sets key to true.
+func isConfirmedByMetadata(ctx context.Context, key string) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, value := range md.Get(key) {
+		if v, err := strconv.ParseBool(value); err == nil && v {
+			return true
+		}
+	}
+	return false
+}
+
 func isCollectionLoaded(ctx context.Context, qc types.QueryCoordClient, collID int64) (bool, error) {
 	// get all loading collections
 	resp, err := qc.ShowCollections(ctx, &querypb.ShowCollectionsRequest{
@@ -1222,7 +1261,7 @@ func checkPrimaryFieldData(schema *schemapb.CollectionSchema, result *milvuspb.M
 				return nil, fmt.Errorf("can not assign primary field data when auto id enabled %v", primaryFieldSchema.Name)
 			}
 			// if autoID == true, currently support autoID for int64 and varchar PrimaryField
-			primaryFieldData, err = autoGenPrimaryFieldData(primaryFieldSchema, insertMsg.GetRowIDs())
+			primaryFieldData, err = autoGenPrimaryFieldDataByStrategy(schema, primaryFieldSchema, insertMsg.GetRowIDs())
 			if err != nil {
 				log.Info("generate primary field data failed when autoID == true", zap.String("collectionName", insertMsg.CollectionName), zap.Error(err))
 				return nil, err
@@ -1642,3 +1681,15 @@ func SetReportValue(status *commonpb.Status, value int) {
 	}
 	status.ExtraInfo["report_value"] = strconv.Itoa(value)
 }
+
+// observeMutationBatch records the rows, the payload bytes and the vector dimensions of an insert or upsert request.
+func observeMutationBatch(msgType string, collectionName string, numRows uint32, size int, fields []*schemapb.FieldData) {
+	nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	metrics.ProxyMutationBatchRows.WithLabelValues(nodeID, msgType, collectionName).Observe(float64(numRows))
+	metrics.ProxyMutationBatchBytes.WithLabelValues(nodeID, msgType, collectionName).Observe(float64(size))
+	for _, field := range fields {
+		if dim := field.GetVectors().GetDim(); typeutil.IsVectorType(field.GetType()) && dim > 0 {
+			metrics.ProxyMutationVectorDim.WithLabelValues(nodeID, msgType).Observe(float64(dim))
+		}
+	}
+}
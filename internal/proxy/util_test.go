@@ -26,6 +26,8 @@ import (
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -42,6 +44,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/rootcoordpb"
 	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/util"
 	"github.com/milvus-io/milvus/pkg/util/crypto"
@@ -919,6 +922,33 @@ func TestGetRole(t *testing.T) {
 	assert.Equal(t, 1, len(roles))
 }
 
+func TestIsAdminUser(t *testing.T) {
+	globalMetaCache = nil
+	isAdmin, err := IsAdminUser("root")
+	assert.NoError(t, err)
+	assert.True(t, isAdmin)
+	_, err = IsAdminUser("foo")
+	assert.Error(t, err)
+
+	mockCache := NewMockCache(t)
+	mockCache.On("GetUserRole",
+		mock.AnythingOfType("string"),
+	).Return(func(username string) []string {
+		if username == "foo" {
+			return []string{"role1", "admin"}
+		}
+		return []string{"role1"}
+	})
+	globalMetaCache = mockCache
+	isAdmin, err = IsAdminUser("foo")
+	assert.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	isAdmin, err = IsAdminUser("bar")
+	assert.NoError(t, err)
+	assert.False(t, isAdmin)
+}
+
 func TestPasswordVerify(t *testing.T) {
 	username := "user-test00"
 	password := "PasswordVerify"
@@ -2209,3 +2239,23 @@ func TestAppendUserInfoForRPC(t *testing.T) {
 	expectAuth := crypto.Base64Encode("root:root")
 	assert.Equal(t, expectAuth, authorization[0])
 }
+
+func TestObserveMutationBatch(t *testing.T) {
+	paramtable.Init()
+	nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	sampleCount := func(observer prometheus.Observer) uint64 {
+		m := &dto.Metric{}
+		assert.NoError(t, observer.(prometheus.Histogram).Write(m))
+		return m.GetHistogram().GetSampleCount()
+	}
+
+	fields := []*schemapb.FieldData{
+		{Type: schemapb.DataType_Int64, FieldName: "pk"},
+		{Type: schemapb.DataType_FloatVector, FieldName: "vec", Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{Dim: 128}}},
+	}
+	observeMutationBatch("test_mutation", "test_observe_mutation_batch", 10, 4096, fields)
+	observeMutationBatch("test_mutation", "test_observe_mutation_batch", 1, 512, fields[:1])
+	assert.Equal(t, uint64(2), sampleCount(metrics.ProxyMutationBatchRows.WithLabelValues(nodeID, "test_mutation", "test_observe_mutation_batch")))
+	assert.Equal(t, uint64(2), sampleCount(metrics.ProxyMutationBatchBytes.WithLabelValues(nodeID, "test_mutation", "test_observe_mutation_batch")))
+	assert.Equal(t, uint64(1), sampleCount(metrics.ProxyMutationVectorDim.WithLabelValues(nodeID, "test_mutation")))
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"strconv"
+	"unicode/utf8"
 
 	"go.uber.org/zap"
 
@@ -62,6 +64,10 @@ func (v *validateUtil) Validate(data []*schemapb.FieldData, schema *schemapb.Col
 		return err
 	}
 
+	if err := v.checkFieldDataIntegrity(data, helper); err != nil {
+		return err
+	}
+
 	for _, field := range data {
 		fieldSchema, err := helper.GetFieldFromName(field.GetFieldName())
 		if err != nil {
@@ -134,6 +140,49 @@ func (v *validateUtil) Validate(data []*schemapb.FieldData, schema *schemapb.Col
 	return nil
 }
 
+// checkFieldDataIntegrity checks the malformed payloads the type specific checks do not expect: the fields set more
+// than once, the fields without data, the data of another type than the schema and the non-positive vector dims.
+func (v *validateUtil) checkFieldDataIntegrity(data []*schemapb.FieldData, schema *typeutil.SchemaHelper) error {
+	fieldNames := make(map[string]struct{}, len(data))
+	for _, field := range data {
+		if _, ok := fieldNames[field.GetFieldName()]; ok {
+			return merr.WrapErrParameterInvalidMsg("field %s is set more than once", field.GetFieldName())
+		}
+		fieldNames[field.GetFieldName()] = struct{}{}
+
+		fieldSchema, err := schema.GetFieldFromName(field.GetFieldName())
+		if err != nil {
+			return err
+		}
+		if field.GetType() != schemapb.DataType_None && field.GetType() != fieldSchema.GetDataType() {
+			msg := fmt.Sprintf("the data type (%s) of field data (%s) is not equal to schema data type (%s)",
+				field.GetType().String(), field.GetFieldName(), fieldSchema.GetDataType().String())
+			return merr.WrapErrParameterInvalid(fieldSchema.GetDataType().String(), field.GetType().String(), msg)
+		}
+
+		switch field.GetField().(type) {
+		case *schemapb.FieldData_Scalars:
+			if typeutil.IsVectorType(fieldSchema.GetDataType()) {
+				msg := fmt.Sprintf("vector field '%v' is illegal, got scalar data", field.GetFieldName())
+				return merr.WrapErrParameterInvalid("need vector data", "got scalar data", msg)
+			}
+		case *schemapb.FieldData_Vectors:
+			if !typeutil.IsVectorType(fieldSchema.GetDataType()) {
+				msg := fmt.Sprintf("scalar field '%v' is illegal, got vector data", field.GetFieldName())
+				return merr.WrapErrParameterInvalid("need scalar data", "got vector data", msg)
+			}
+			if dim := field.GetVectors().GetDim(); dim <= 0 && !typeutil.IsSparseFloatVectorType(fieldSchema.GetDataType()) {
+				msg := fmt.Sprintf("the dim (%d) of field data (%s) should be positive", dim, field.GetFieldName())
+				return merr.WrapErrParameterInvalid("positive dim", strconv.FormatInt(dim, 10), msg)
+			}
+		default:
+			msg := fmt.Sprintf("field '%v' is illegal, no data set", field.GetFieldName())
+			return merr.WrapErrParameterInvalid("need field data", "got nil", msg)
+		}
+	}
+	return nil
+}
+
 func (v *validateUtil) checkAligned(data []*schemapb.FieldData, schema *typeutil.SchemaHelper, numRows uint64) error {
 	errNumRowsMismatch := func(fieldName string, fieldNumRows uint64) error {
 		msg := fmt.Sprintf("the num_rows (%d) of field (%s) is not equal to passed num_rows (%d)", fieldNumRows, fieldName, numRows)
@@ -324,7 +373,8 @@ func (v *validateUtil) fillWithDefaultValue(data []*schemapb.FieldData, schema *
 				return merr.WrapErrParameterInvalid("not set default value", "", "json type not support default value")
 
 			default:
-				panic("undefined data type " + field.Type.String())
+				msg := fmt.Sprintf("field '%v' is illegal, no scalar data set", field.GetFieldName())
+				return merr.WrapErrParameterInvalid("need scalar data", "got nil", msg)
 			}
 
 		case *schemapb.FieldData_Vectors:
@@ -332,7 +382,8 @@ func (v *validateUtil) fillWithDefaultValue(data []*schemapb.FieldData, schema *
 			return merr.WrapErrParameterInvalid("not set default value", "", "vector type not support default value")
 
 		default:
-			panic("undefined data type " + field.Type.String())
+			msg := fmt.Sprintf("field '%v' is illegal, no data set", field.GetFieldName())
+			return merr.WrapErrParameterInvalid("need field data", "got nil", msg)
 		}
 	}
 
@@ -346,8 +397,8 @@ func (v *validateUtil) checkFloatVectorFieldData(field *schemapb.FieldData, fiel
 		return merr.WrapErrParameterInvalid("need float vector", "got nil", msg)
 	}
 
-	if v.checkNAN {
-		return typeutil.VerifyFloats32(floatArray)
+	if v.checkNonFiniteVectors() {
+		return wrapNonFiniteVectorErr(field, typeutil.VerifyFloats32(floatArray))
 	}
 
 	return nil
@@ -359,8 +410,8 @@ func (v *validateUtil) checkFloat16VectorFieldData(field *schemapb.FieldData, fi
 		msg := fmt.Sprintf("float16 float field '%v' is illegal, nil Vector_Float16 type", field.GetFieldName())
 		return merr.WrapErrParameterInvalid("need vector_float16 array", "got nil", msg)
 	}
-	if v.checkNAN {
-		return typeutil.VerifyFloats16(float16VecArray)
+	if v.checkNonFiniteVectors() {
+		return wrapNonFiniteVectorErr(field, typeutil.VerifyFloats16(float16VecArray))
 	}
 	return nil
 }
@@ -371,12 +422,24 @@ func (v *validateUtil) checkBFloat16VectorFieldData(field *schemapb.FieldData, f
 		msg := fmt.Sprintf("bfloat16 float field '%v' is illegal, nil Vector_BFloat16 type", field.GetFieldName())
 		return merr.WrapErrParameterInvalid("need vector_bfloat16 array", "got nil", msg)
 	}
-	if v.checkNAN {
-		return typeutil.VerifyBFloats16(bfloat16VecArray)
+	if v.checkNonFiniteVectors() {
+		return wrapNonFiniteVectorErr(field, typeutil.VerifyBFloats16(bfloat16VecArray))
 	}
 	return nil
 }
 
+// checkNonFiniteVectors returns whether the float vectors are checked for the NaN and Inf values.
+func (v *validateUtil) checkNonFiniteVectors() bool {
+	return v.checkNAN && paramtable.Get().ProxyCfg.RejectNonFiniteVectors.GetAsBool()
+}
+
+func wrapNonFiniteVectorErr(field *schemapb.FieldData, err error) error {
+	if err == nil {
+		return nil
+	}
+	return merr.WrapErrParameterInvalidMsg("vector field '%v' is illegal, %s", field.GetFieldName(), err.Error())
+}
+
 func (v *validateUtil) checkBinaryVectorFieldData(field *schemapb.FieldData, fieldSchema *schemapb.FieldSchema) error {
 	bVecArray := field.GetVectors().GetBinaryVector()
 	if bVecArray == nil {
@@ -405,6 +468,9 @@ func (v *validateUtil) checkVarCharFieldData(field *schemapb.FieldData, fieldSch
 		msg := fmt.Sprintf("varchar field '%v' is illegal, array type mismatch", field.GetFieldName())
 		return merr.WrapErrParameterInvalid("need string array", "got nil", msg)
 	}
+	if i, ok := verifyUTF8PerRow(strArr); !ok {
+		return merr.WrapErrParameterInvalidMsg("the %dth VarChar %s is not valid utf-8", i, fieldSchema.GetName())
+	}
 
 	// fieldSchema autoID is true means that field is pk and primaryData is auto generated
 	// no need to do max length check
@@ -604,6 +670,13 @@ func (v *validateUtil) checkArrayFieldData(field *schemapb.FieldData, fieldSchem
 			return err
 		}
 	}
+	if typeutil.IsStringType(data.GetElementType()) {
+		for rowCnt, row := range data.GetData() {
+			if i, ok := verifyUTF8PerRow(row.GetStringData().GetData()); !ok {
+				return merr.WrapErrParameterInvalidMsg("the %dth %s %s[%d] is not valid utf-8", rowCnt, fieldSchema.GetDataType().String(), fieldSchema.GetName(), i)
+			}
+		}
+	}
 	if typeutil.IsStringType(data.GetElementType()) && v.checkMaxLen {
 		maxLength, err := parameterutil.GetMaxLength(fieldSchema)
 		if err != nil {
@@ -629,6 +702,16 @@ func verifyLengthPerRow[E interface{ ~string | ~[]byte }](strArr []E, maxLength
 	return 0, true
 }
 
+func verifyUTF8PerRow(strArr []string) (int, bool) {
+	for i, s := range strArr {
+		if !utf8.ValidString(s) {
+			return i, false
+		}
+	}
+
+	return 0, true
+}
+
 func verifyCapacityPerRow(arrayArray []*schemapb.ScalarField, maxCapacity int64, elementType schemapb.DataType) error {
 	for i, array := range arrayArray {
 		arrayLen := 0
@@ -11,6 +11,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
@@ -3959,3 +3960,178 @@ func Test_validateUtil_checkDoubleFieldData(t *testing.T) {
 		},
 	}, nil))
 }
+
+func Test_verifyUTF8PerRow(t *testing.T) {
+	_, ok := verifyUTF8PerRow(nil)
+	assert.True(t, ok)
+
+	_, ok = verifyUTF8PerRow([]string{"abc", "中文"})
+	assert.True(t, ok)
+
+	row, ok := verifyUTF8PerRow([]string{"abc", string([]byte{0xff, 0xfe})})
+	assert.False(t, ok)
+	assert.Equal(t, 1, row)
+}
+
+func Test_validateUtil_checkFieldDataIntegrity(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}}},
+			{FieldID: 102, Name: "sparse", DataType: schemapb.DataType_SparseFloatVector},
+		},
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	assert.NoError(t, err)
+	pkData := func() *schemapb.FieldData {
+		return &schemapb.FieldData{
+			FieldName: "pk",
+			Type:      schemapb.DataType_Int64,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{Data: &schemapb.ScalarField_LongData{
+				LongData: &schemapb.LongArray{Data: []int64{1}},
+			}}},
+		}
+	}
+	vecData := func(dim int64) *schemapb.FieldData {
+		return &schemapb.FieldData{
+			FieldName: "vec",
+			Type:      schemapb.DataType_FloatVector,
+			Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{Dim: dim, Data: &schemapb.VectorField_FloatVector{
+				FloatVector: &schemapb.FloatArray{Data: []float32{1, 2}},
+			}}},
+		}
+	}
+	sparseData := &schemapb.FieldData{
+		FieldName: "sparse",
+		Type:      schemapb.DataType_SparseFloatVector,
+		Field:     &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{Data: &schemapb.VectorField_SparseFloatVector{}}},
+	}
+	v := newValidateUtil()
+
+	t.Run("normal case", func(t *testing.T) {
+		assert.NoError(t, v.checkFieldDataIntegrity([]*schemapb.FieldData{pkData(), vecData(2), sparseData}, helper))
+	})
+
+	t.Run("unset type", func(t *testing.T) {
+		data := pkData()
+		data.Type = schemapb.DataType_None
+		assert.NoError(t, v.checkFieldDataIntegrity([]*schemapb.FieldData{data}, helper))
+	})
+
+	t.Run("duplicated field", func(t *testing.T) {
+		err := v.checkFieldDataIntegrity([]*schemapb.FieldData{pkData(), pkData()}, helper)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		data := pkData()
+		data.FieldName = "unknown"
+		assert.Error(t, v.checkFieldDataIntegrity([]*schemapb.FieldData{data}, helper))
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		data := pkData()
+		data.Type = schemapb.DataType_VarChar
+		err := v.checkFieldDataIntegrity([]*schemapb.FieldData{data}, helper)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("vector data of scalar field", func(t *testing.T) {
+		data := vecData(2)
+		data.FieldName = "pk"
+		data.Type = schemapb.DataType_None
+		err := v.checkFieldDataIntegrity([]*schemapb.FieldData{data}, helper)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("scalar data of vector field", func(t *testing.T) {
+		data := pkData()
+		data.FieldName = "vec"
+		data.Type = schemapb.DataType_None
+		err := v.checkFieldDataIntegrity([]*schemapb.FieldData{data}, helper)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		err := v.checkFieldDataIntegrity([]*schemapb.FieldData{{FieldName: "pk", Type: schemapb.DataType_Int64}}, helper)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("non-positive dim", func(t *testing.T) {
+		err := v.checkFieldDataIntegrity([]*schemapb.FieldData{vecData(-2)}, helper)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+		err = v.checkFieldDataIntegrity([]*schemapb.FieldData{vecData(0)}, helper)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+}
+
+func Test_validateUtil_checkInvalidUTF8(t *testing.T) {
+	invalid := string([]byte{0xff, 0xfe})
+	v := newValidateUtil()
+
+	t.Run("varchar", func(t *testing.T) {
+		f := &schemapb.FieldData{
+			FieldName: "str",
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{Data: &schemapb.ScalarField_StringData{
+				StringData: &schemapb.StringArray{Data: []string{"abc", invalid}},
+			}}},
+		}
+		err := v.checkVarCharFieldData(f, &schemapb.FieldSchema{Name: "str", DataType: schemapb.DataType_VarChar})
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+
+	t.Run("varchar array", func(t *testing.T) {
+		f := &schemapb.FieldData{
+			FieldName: "arr",
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{Data: &schemapb.ScalarField_ArrayData{
+				ArrayData: &schemapb.ArrayArray{
+					ElementType: schemapb.DataType_VarChar,
+					Data: []*schemapb.ScalarField{{Data: &schemapb.ScalarField_StringData{
+						StringData: &schemapb.StringArray{Data: []string{invalid}},
+					}}},
+				},
+			}}},
+		}
+		err := v.checkArrayFieldData(f, &schemapb.FieldSchema{Name: "arr", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_VarChar})
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	})
+}
+
+func Test_validateUtil_nonFiniteVectorPolicy(t *testing.T) {
+	f := &schemapb.FieldData{
+		FieldName: "vec",
+		Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{Data: &schemapb.VectorField_FloatVector{
+			FloatVector: &schemapb.FloatArray{Data: []float32{float32(math.Inf(1))}},
+		}}},
+	}
+	v := newValidateUtil(withNANCheck())
+	err := v.checkFloatVectorFieldData(f, nil)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	assert.Contains(t, err.Error(), "vec")
+
+	paramtable.Get().Save(paramtable.Get().ProxyCfg.RejectNonFiniteVectors.Key, "false")
+	defer paramtable.Get().Reset(paramtable.Get().ProxyCfg.RejectNonFiniteVectors.Key)
+	assert.NoError(t, v.checkFloatVectorFieldData(f, nil))
+}
+
+func Test_validateUtil_fillWithDefaultValueMalformed(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{
+				FieldID:      100,
+				Name:         "num",
+				DataType:     schemapb.DataType_Int64,
+				DefaultValue: &schemapb.ValueField{Data: &schemapb.ValueField_LongData{LongData: 1}},
+			},
+		},
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	assert.NoError(t, err)
+	v := newValidateUtil()
+
+	// the malformed payloads are rejected rather than panic
+	err = v.fillWithDefaultValue([]*schemapb.FieldData{{FieldName: "num", Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{}}}}, helper, 1)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	err = v.fillWithDefaultValue([]*schemapb.FieldData{{FieldName: "num"}}, helper, 1)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
@@ -0,0 +1,173 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// vectorIndexMetasTTL is how long the vector index metas of a collection are cached for the search checks.
+const vectorIndexMetasTTL = 10 * time.Second
+
+// vectorIndexMeta is the index type, the metric type and the dim the vector index of a field is built with.
+type vectorIndexMeta struct {
+	indexName  string
+	indexType  string
+	metricType string
+	dim        int64
+}
+
+type vectorIndexMetasEntry struct {
+	metas   map[int64]*vectorIndexMeta
+	updated time.Time
+}
+
+// vectorIndexMetas caches the vector index metas of the collections by field id.
+type vectorIndexMetas struct {
+	mu      sync.Mutex
+	entries map[int64]*vectorIndexMetasEntry
+}
+
+var globalVectorIndexMetas = &vectorIndexMetas{entries: make(map[int64]*vectorIndexMetasEntry)}
+
+// get returns the vector index metas of the collection by field id, empty if no index built.
+func (v *vectorIndexMetas) get(ctx context.Context, dc types.DataCoordClient, collectionID int64) (map[int64]*vectorIndexMeta, error) {
+	v.mu.Lock()
+	entry, ok := v.entries[collectionID]
+	v.mu.Unlock()
+	if ok && time.Since(entry.updated) < vectorIndexMetasTTL {
+		return entry.metas, nil
+	}
+
+	resp, err := dc.DescribeIndex(ctx, &indexpb.DescribeIndexRequest{CollectionID: collectionID})
+	if err := merr.CheckRPCCall(resp, err); err != nil && !errors.Is(err, merr.ErrIndexNotFound) {
+		return nil, err
+	}
+	metas := make(map[int64]*vectorIndexMeta)
+	for _, index := range resp.GetIndexInfos() {
+		indexParams := funcutil.KeyValuePair2Map(index.GetIndexParams())
+		metricType, ok := indexParams[common.MetricTypeKey]
+		if !ok {
+			continue
+		}
+		meta := &vectorIndexMeta{indexName: index.GetIndexName(), indexType: indexParams[common.IndexTypeKey], metricType: metricType}
+		if dim, err := strconv.ParseInt(funcutil.KeyValuePair2Map(index.GetTypeParams())[common.DimKey], 10, 64); err == nil {
+			meta.dim = dim
+		}
+		metas[index.GetFieldID()] = meta
+	}
+	v.mu.Lock()
+	v.entries[collectionID] = &vectorIndexMetasEntry{metas: metas, updated: time.Now()}
+	v.mu.Unlock()
+	return metas, nil
+}
+
+// vectorIndexType returns the index type of the vector index on the field, empty if no index built or failed to
+// describe the index.
+func vectorIndexType(ctx context.Context, dc types.DataCoordClient, collectionID, fieldID int64) string {
+	if dc == nil {
+		return ""
+	}
+	metas, err := globalVectorIndexMetas.get(ctx, dc, collectionID)
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to describe the vector index, check the search params of all the index types",
+			zap.Int64("collectionID", collectionID), zap.Error(err))
+		return ""
+	}
+	if meta, ok := metas[fieldID]; ok {
+		return meta.indexType
+	}
+	return ""
+}
+
+// placeholderDim returns the dim of a query vector of the placeholder, false if the placeholder is not of the dense
+// vectors.
+func placeholderDim(placeholderType commonpb.PlaceholderType, vector []byte) (int64, bool) {
+	switch placeholderType {
+	case commonpb.PlaceholderType_FloatVector:
+		return int64(len(vector)) / 4, true
+	case commonpb.PlaceholderType_Float16Vector, commonpb.PlaceholderType_BFloat16Vector:
+		return int64(len(vector)) / 2, true
+	case commonpb.PlaceholderType_BinaryVector:
+		return int64(len(vector)) * 8, true
+	default:
+		return 0, false
+	}
+}
+
+// checkVectorCompatibility checks the dim of the query vectors and the metric type of the search against the vector
+// field and its index before the search is dispatched, a mismatch otherwise fails in segcore or returns the scores of
+// another metric.
+func checkVectorCompatibility(ctx context.Context, dc types.DataCoordClient, schema *schemapb.CollectionSchema, collectionID, fieldID int64, metricType string, placeholderGroup []byte) error {
+	field := typeutil.GetField(schema, fieldID)
+	if field == nil || !typeutil.IsVectorType(field.GetDataType()) || typeutil.IsSparseFloatVectorType(field.GetDataType()) {
+		return nil
+	}
+	dim, err := typeutil.GetDim(field)
+	if err != nil {
+		return nil
+	}
+
+	var index *vectorIndexMeta
+	if dc != nil {
+		metas, err := globalVectorIndexMetas.get(ctx, dc, collectionID)
+		if err != nil {
+			log.Ctx(ctx).Warn("failed to describe the vector index, skip checking the metric type", zap.Int64("collectionID", collectionID), zap.Error(err))
+		}
+		index = metas[fieldID]
+	}
+	if index != nil && index.dim > 0 {
+		dim = index.dim
+	}
+
+	group := &commonpb.PlaceholderGroup{}
+	if err := proto.Unmarshal(placeholderGroup, group); err != nil {
+		return merr.WrapErrParameterInvalidMsg("invalid placeholder group: %s", err.Error())
+	}
+	for _, placeholder := range group.GetPlaceholders() {
+		for _, vector := range placeholder.GetValues() {
+			if vectorDim, ok := placeholderDim(placeholder.GetType(), vector); ok && vectorDim != dim {
+				return merr.WrapErrParameterInvalidMsg("the dim %d of the query vectors does not match the dim %d of vector field %s",
+					vectorDim, dim, field.GetName())
+			}
+		}
+	}
+
+	if index != nil && metricType != "" && !strings.EqualFold(metricType, index.metricType) {
+		return merr.WrapErrParameterInvalidMsg("the metric type %s of the search does not match the metric type %s of index %s on vector field %s, "+
+			"search with metric type %s or rebuild the index", metricType, index.metricType, index.indexName, field.GetName(), index.metricType)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metric"
+)
+
+func TestPlaceholderDim(t *testing.T) {
+	dim, ok := placeholderDim(commonpb.PlaceholderType_FloatVector, make([]byte, 32))
+	assert.True(t, ok)
+	assert.EqualValues(t, 8, dim)
+	dim, ok = placeholderDim(commonpb.PlaceholderType_Float16Vector, make([]byte, 32))
+	assert.True(t, ok)
+	assert.EqualValues(t, 16, dim)
+	dim, ok = placeholderDim(commonpb.PlaceholderType_BinaryVector, make([]byte, 32))
+	assert.True(t, ok)
+	assert.EqualValues(t, 256, dim)
+	_, ok = placeholderDim(commonpb.PlaceholderType_SparseFloatVector, make([]byte, 32))
+	assert.False(t, ok)
+}
+
+func TestCheckVectorCompatibility(t *testing.T) {
+	ctx := context.Background()
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "4"}}},
+		},
+	}
+	placeholderGroup := func(dim int) []byte {
+		data, err := proto.Marshal(&commonpb.PlaceholderGroup{Placeholders: []*commonpb.PlaceholderValue{{
+			Tag:    "$0",
+			Type:   commonpb.PlaceholderType_FloatVector,
+			Values: [][]byte{make([]byte, dim*4)},
+		}}})
+		assert.NoError(t, err)
+		return data
+	}
+
+	t.Run("without index", func(t *testing.T) {
+		assert.NoError(t, checkVectorCompatibility(ctx, nil, schema, 1, 101, metric.L2, placeholderGroup(4)))
+		err := checkVectorCompatibility(ctx, nil, schema, 1, 101, metric.L2, placeholderGroup(8))
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+		assert.Contains(t, err.Error(), "dim 8")
+		// not a vector field
+		assert.NoError(t, checkVectorCompatibility(ctx, nil, schema, 1, 100, "", placeholderGroup(8)))
+	})
+
+	t.Run("with index", func(t *testing.T) {
+		dc := mocks.NewMockDataCoordClient(t)
+		dc.EXPECT().DescribeIndex(mock.Anything, mock.Anything).Return(&indexpb.DescribeIndexResponse{
+			Status: merr.Success(),
+			IndexInfos: []*indexpb.IndexInfo{{
+				FieldID:     101,
+				IndexName:   "vec_index",
+				TypeParams:  []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "4"}},
+				IndexParams: []*commonpb.KeyValuePair{{Key: common.MetricTypeKey, Value: metric.COSINE}, {Key: common.IndexTypeKey, Value: "HNSW"}},
+			}},
+		}, nil).Once()
+		assert.NoError(t, checkVectorCompatibility(ctx, dc, schema, 2, 101, "cosine", placeholderGroup(4)))
+		assert.NoError(t, checkVectorCompatibility(ctx, dc, schema, 2, 101, "", placeholderGroup(4)))
+		// the index metas are cached
+		err := checkVectorCompatibility(ctx, dc, schema, 2, 101, metric.L2, placeholderGroup(4))
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+		assert.Contains(t, err.Error(), "vec_index")
+		assert.Equal(t, "HNSW", vectorIndexType(ctx, dc, 2, 101))
+		assert.Equal(t, "", vectorIndexType(ctx, dc, 2, 100))
+		assert.Equal(t, "", vectorIndexType(ctx, nil, 2, 101))
+	})
+
+	t.Run("describe index failed", func(t *testing.T) {
+		dc := mocks.NewMockDataCoordClient(t)
+		dc.EXPECT().DescribeIndex(mock.Anything, mock.Anything).Return(nil, merr.WrapErrServiceNotReady("datacoord", 0, "init"))
+		assert.NoError(t, checkVectorCompatibility(ctx, dc, schema, 3, 101, metric.L2, placeholderGroup(4)))
+		assert.Error(t, checkVectorCompatibility(ctx, dc, schema, 3, 101, metric.L2, placeholderGroup(3)))
+	})
+}
@@ -0,0 +1,135 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/metric"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// normalizedNormTolerance is the max difference to 1 of the norm of a normalized vector.
+const normalizedNormTolerance = 1e-3
+
+// isFieldNormalized returns whether the float vectors of the field are L2-normalized on insert.
+func isFieldNormalized(schema *schemapb.CollectionSchema, field *schemapb.FieldSchema) bool {
+	if field.GetDataType() != schemapb.DataType_FloatVector {
+		return false
+	}
+	if enabled, ok := common.IsNormalizeEnabled(field.GetTypeParams()...); ok {
+		return enabled
+	}
+	enabled, _ := common.IsNormalizeEnabled(schema.GetProperties()...)
+	return enabled
+}
+
+// validateNormalize checks the normalize value of collection properties and field type params,
+// only the float vector fields are allowed to be normalized.
+func validateNormalize(schema *schemapb.CollectionSchema, properties []*commonpb.KeyValuePair) error {
+	if value, err := funcutil.GetAttrByKeyFromRepeatedKV(common.NormalizeKey, properties); err == nil {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return merr.WrapErrParameterInvalidMsg("invalid %s value %s, only true or false is allowed", common.NormalizeKey, value)
+		}
+	}
+	for _, field := range schema.GetFields() {
+		value, err := funcutil.GetAttrByKeyFromRepeatedKV(common.NormalizeKey, field.GetTypeParams())
+		if err != nil {
+			continue
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return merr.WrapErrParameterInvalidMsg("invalid %s value %s of field %s, only true or false is allowed", common.NormalizeKey, value, field.GetName())
+		}
+		if enabled && field.GetDataType() != schemapb.DataType_FloatVector {
+			return merr.WrapErrParameterInvalidMsg("%s is only supported by float vector field, field %s is %s", common.NormalizeKey, field.GetName(), field.GetDataType())
+		}
+	}
+	return nil
+}
+
+// normalizeFieldsData L2-normalizes the float vectors of the normalized fields in place.
+func normalizeFieldsData(schema *schemapb.CollectionSchema, fieldsData []*schemapb.FieldData) error {
+	for _, fieldData := range fieldsData {
+		field := getSchemaField(schema, fieldData)
+		if field == nil || !isFieldNormalized(schema, field) {
+			continue
+		}
+		dim := int(fieldData.GetVectors().GetDim())
+		data := fieldData.GetVectors().GetFloatVector().GetData()
+		if dim <= 0 {
+			continue
+		}
+		for start := 0; start+dim <= len(data); start += dim {
+			vector := data[start : start+dim]
+			norm := l2Norm(vector)
+			if norm == 0 {
+				return merr.WrapErrParameterInvalidMsg("cannot normalize zero vector of field %s", field.GetName())
+			}
+			for i := range vector {
+				vector[i] = float32(float64(vector[i]) / norm)
+			}
+		}
+	}
+	return nil
+}
+
+func l2Norm(vector []float32) float64 {
+	sum := 0.0
+	for _, v := range vector {
+		sum += float64(v) * float64(v)
+	}
+	return math.Sqrt(sum)
+}
+
+// validateNormalizedSearch rejects the IP search on the normalized field with the query vectors not normalized,
+// whose scores are not the cosine similarity as expected.
+func validateNormalizedSearch(schema *schemapb.CollectionSchema, fieldID int64, metricType string, placeholderGroup []byte) error {
+	field := typeutil.GetField(schema, fieldID)
+	if field == nil || !isFieldNormalized(schema, field) || !strings.EqualFold(metricType, metric.IP) {
+		return nil
+	}
+	group := &commonpb.PlaceholderGroup{}
+	if err := proto.Unmarshal(placeholderGroup, group); err != nil {
+		return err
+	}
+	for _, placeholder := range group.GetPlaceholders() {
+		if placeholder.GetType() != commonpb.PlaceholderType_FloatVector {
+			continue
+		}
+		for _, value := range placeholder.GetValues() {
+			vector := make([]float32, len(value)/4)
+			for i := range vector {
+				vector[i] = typeutil.BytesToFloat32(value[i*4 : i*4+4])
+			}
+			if math.Abs(l2Norm(vector)-1) > normalizedNormTolerance {
+				return merr.WrapErrParameterInvalidMsg("field %s is normalized but the query vectors are not, "+
+					"the %s scores are not cosine similarity, normalize the query vectors or use %s metric", field.GetName(), metric.IP, metric.COSINE)
+			}
+		}
+	}
+	return nil
+}
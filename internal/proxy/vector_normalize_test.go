@@ -0,0 +1,110 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/metric"
+)
+
+func newNormalizeTestSchema(collectionNormalize string, fieldNormalize string) *schemapb.CollectionSchema {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{
+				{Key: common.DimKey, Value: "2"},
+			}},
+		},
+	}
+	if collectionNormalize != "" {
+		schema.Properties = []*commonpb.KeyValuePair{{Key: common.NormalizeKey, Value: collectionNormalize}}
+	}
+	if fieldNormalize != "" {
+		schema.Fields[1].TypeParams = append(schema.Fields[1].TypeParams, &commonpb.KeyValuePair{Key: common.NormalizeKey, Value: fieldNormalize})
+	}
+	return schema
+}
+
+func newNormalizeTestFieldsData(data ...float32) []*schemapb.FieldData {
+	return []*schemapb.FieldData{{
+		FieldName: "vec",
+		Type:      schemapb.DataType_FloatVector,
+		Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+			Dim:  2,
+			Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: data}},
+		}},
+	}}
+}
+
+func TestIsFieldNormalized(t *testing.T) {
+	for _, c := range []struct {
+		collection, field string
+		expected          bool
+	}{
+		{"", "", false},
+		{"true", "", true},
+		{"", "true", true},
+		{"true", "false", false},
+		{"false", "true", true},
+	} {
+		schema := newNormalizeTestSchema(c.collection, c.field)
+		assert.Equal(t, c.expected, isFieldNormalized(schema, schema.Fields[1]), c)
+		assert.False(t, isFieldNormalized(schema, schema.Fields[0]))
+	}
+}
+
+func TestValidateNormalize(t *testing.T) {
+	assert.NoError(t, validateNormalize(newNormalizeTestSchema("", "true"), []*commonpb.KeyValuePair{{Key: common.NormalizeKey, Value: "true"}}))
+	assert.Error(t, validateNormalize(newNormalizeTestSchema("", ""), []*commonpb.KeyValuePair{{Key: common.NormalizeKey, Value: "yes"}}))
+	assert.Error(t, validateNormalize(newNormalizeTestSchema("", "yes"), nil))
+
+	schema := newNormalizeTestSchema("", "")
+	schema.Fields[0].TypeParams = []*commonpb.KeyValuePair{{Key: common.NormalizeKey, Value: "true"}}
+	assert.Error(t, validateNormalize(schema, nil))
+}
+
+func TestNormalizeFieldsData(t *testing.T) {
+	fieldsData := newNormalizeTestFieldsData(3, 4, 0, 2)
+	assert.NoError(t, normalizeFieldsData(newNormalizeTestSchema("", ""), fieldsData))
+	assert.Equal(t, []float32{3, 4, 0, 2}, fieldsData[0].GetVectors().GetFloatVector().GetData())
+
+	assert.NoError(t, normalizeFieldsData(newNormalizeTestSchema("true", ""), fieldsData))
+	assert.InDeltaSlice(t, []float32{0.6, 0.8, 0, 1}, fieldsData[0].GetVectors().GetFloatVector().GetData(), 1e-6)
+
+	assert.Error(t, normalizeFieldsData(newNormalizeTestSchema("true", ""), newNormalizeTestFieldsData(0, 0)))
+}
+
+func TestValidateNormalizedSearch(t *testing.T) {
+	normalized, err := funcutil.FieldDataToPlaceholderGroupBytes(newNormalizeTestFieldsData(0.6, 0.8)[0])
+	assert.NoError(t, err)
+	notNormalized, err := funcutil.FieldDataToPlaceholderGroupBytes(newNormalizeTestFieldsData(3, 4)[0])
+	assert.NoError(t, err)
+
+	schema := newNormalizeTestSchema("true", "")
+	assert.NoError(t, validateNormalizedSearch(schema, 101, metric.IP, normalized))
+	assert.Error(t, validateNormalizedSearch(schema, 101, metric.IP, notNormalized))
+	assert.NoError(t, validateNormalizedSearch(schema, 101, metric.COSINE, notNormalized))
+	assert.NoError(t, validateNormalizedSearch(schema, 101, metric.L2, notNormalized))
+	assert.NoError(t, validateNormalizedSearch(newNormalizeTestSchema("", ""), 101, metric.IP, notNormalized))
+}
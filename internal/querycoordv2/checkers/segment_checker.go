@@ -86,11 +86,18 @@ func (c *SegmentChecker) Check(ctx context.Context) []task.Task {
 	}
 	collectionIDs := c.meta.CollectionManager.GetAll()
 	results := make([]task.Task, 0)
+	lowPriorityResults := make([]task.Task, 0)
 	for _, cid := range collectionIDs {
 		if c.readyToCheck(cid) {
+			// the segments of the collections loaded in the background priority are loaded after the others
+			collection := c.meta.CollectionManager.GetCollection(cid)
 			replicas := c.meta.ReplicaManager.GetByCollection(cid)
 			for _, r := range replicas {
-				results = append(results, c.checkReplica(ctx, r)...)
+				if collection != nil && collection.LowPriority {
+					lowPriorityResults = append(lowPriorityResults, c.checkReplica(ctx, r)...)
+				} else {
+					results = append(results, c.checkReplica(ctx, r)...)
+				}
 			}
 		}
 	}
@@ -102,7 +109,8 @@ func (c *SegmentChecker) Check(ctx context.Context) []task.Task {
 	task.SetReason("collection released", reduceTasks...)
 	results = append(results, reduceTasks...)
 	task.SetPriority(task.TaskPriorityNormal, results...)
-	return results
+	task.SetPriority(task.TaskPriorityLow, lowPriorityResults...)
+	return append(results, lowPriorityResults...)
 }
 
 func (c *SegmentChecker) checkReplica(ctx context.Context, replica *meta.Replica) []task.Task {
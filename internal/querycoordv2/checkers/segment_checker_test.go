@@ -167,6 +167,14 @@ func (suite *SegmentCheckerTestSuite) TestLoadSegments() {
 	suite.True(checker.IsActive())
 	tasks = checker.Check(context.TODO())
 	suite.Len(tasks, 1)
+
+	// the segments of the collection loaded in the background priority
+	collection := checker.meta.CollectionManager.GetCollection(1).Clone()
+	collection.LowPriority = true
+	checker.meta.CollectionManager.PutCollection(collection)
+	tasks = checker.Check(context.TODO())
+	suite.Len(tasks, 1)
+	suite.Equal(task.TaskPriorityLow, tasks[0].Priority())
 }
 
 func (suite *SegmentCheckerTestSuite) TestLoadL0Segments() {
@@ -79,3 +79,22 @@ func CheckLeaderAvailable(nodeMgr *session.NodeManager, leader *meta.LeaderView,
 	}
 	return nil
 }
+
+// LeaderSegmentCoverage returns the fraction of the sealed segments of the leader channel in the current target served
+// by the leader, 1 if the channel has no sealed segment.
+func LeaderSegmentCoverage(leader *meta.LeaderView, currentTargets map[int64]*datapb.SegmentInfo) float64 {
+	total, served := 0, 0
+	for segmentID, info := range currentTargets {
+		if info.GetInsertChannel() != leader.Channel {
+			continue
+		}
+		total++
+		if _, ok := leader.Segments[segmentID]; ok {
+			served++
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(served) / float64(total)
+}
@@ -122,6 +122,20 @@ func (suite *UtilTestSuite) TestCheckLeaderAvaliableFailed() {
 	})
 }
 
+func (suite *UtilTestSuite) TestLeaderSegmentCoverage() {
+	leadview := &meta.LeaderView{
+		ID:       1,
+		Channel:  "test",
+		Segments: map[int64]*querypb.SegmentDist{1: {NodeID: 1}, 2: {NodeID: 2}},
+	}
+	suite.Equal(float64(1), LeaderSegmentCoverage(leadview, nil))
+	suite.Equal(0.5, LeaderSegmentCoverage(leadview, map[int64]*datapb.SegmentInfo{
+		1: {ID: 1, InsertChannel: "test"},
+		3: {ID: 3, InsertChannel: "test"},
+		4: {ID: 4, InsertChannel: "other"},
+	}))
+}
+
 func TestUtilSuite(t *testing.T) {
 	suite.Run(t, new(UtilTestSuite))
 }
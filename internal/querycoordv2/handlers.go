@@ -332,6 +332,9 @@ func (s *Server) fillMetricsWithNodes(topo *metricsinfo.QueryClusterTopology, no
 			})
 			continue
 		}
+		if node := s.nodeMgr.Get(infos.ID); node != nil {
+			infos.LastHeartbeat = node.LastHeartbeat().UnixMilli()
+		}
 		topo.ConnectedNodes = append(topo.ConnectedNodes, infos)
 	}
 }
@@ -427,6 +430,55 @@ func (s *Server) fillReplicaInfo(replica *meta.Replica, withShardNodes bool) *mi
 	return info
 }
 
+// ShardLeaderHealth is the health of the leader of a shard of a replica.
+type ShardLeaderHealth struct {
+	ChannelName   string `json:"channel_name"`
+	LeaderID      int64  `json:"leader_id"`
+	Healthy       bool   `json:"healthy"`
+	Reason        string `json:"reason,omitempty"`
+	LastHeartbeat int64  `json:"last_heartbeat,omitempty"`
+}
+
+// ReplicaHealth is the health of a replica, which is readable if the leaders of all its shards are healthy.
+type ReplicaHealth struct {
+	Readable bool                 `json:"readable"`
+	Shards   []*ShardLeaderHealth `json:"shards"`
+}
+
+// replicaHealth returns the health of the shard leaders of the replica by their states and last heartbeats.
+func (s *Server) replicaHealth(replica *milvuspb.ReplicaInfo) *ReplicaHealth {
+	health := &ReplicaHealth{
+		Readable: len(replica.GetShardReplicas()) > 0,
+		Shards:   make([]*ShardLeaderHealth, 0, len(replica.GetShardReplicas())),
+	}
+	lag := paramtable.Get().QueryCoordCfg.HeartBeatWarningLag.GetAsDuration(time.Millisecond)
+	for _, shard := range replica.GetShardReplicas() {
+		shardHealth := &ShardLeaderHealth{
+			ChannelName: shard.GetDmChannelName(),
+			LeaderID:    shard.GetLeaderID(),
+		}
+		node := s.nodeMgr.Get(shard.GetLeaderID())
+		switch {
+		case node == nil:
+			shardHealth.Reason = "the shard leader is offline"
+		case node.IsStoppingState():
+			shardHealth.Reason = "the shard leader is stopping"
+		case node.LastHeartbeat().UnixNano() <= 0:
+			shardHealth.Reason = "no heartbeat of the shard leader"
+		default:
+			shardHealth.LastHeartbeat = node.LastHeartbeat().UnixMilli()
+			if time.Since(node.LastHeartbeat()) > lag {
+				shardHealth.Reason = "the last heartbeat of the shard leader is older than " + lag.String()
+			} else {
+				shardHealth.Healthy = true
+			}
+		}
+		health.Readable = health.Readable && shardHealth.Healthy
+		health.Shards = append(health.Shards, shardHealth)
+	}
+	return health
+}
+
 func filterDupLeaders(replicaManager *meta.ReplicaManager, leaders map[int64]*meta.LeaderView) map[int64]*meta.LeaderView {
 	type leaderID struct {
 		ReplicaID int64
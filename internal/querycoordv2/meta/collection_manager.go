@@ -47,6 +47,10 @@ type Collection struct {
 	mut             sync.RWMutex
 	refreshNotifier chan struct{}
 	LoadSpan        trace.Span
+
+	// LowPriority is set if the collection is loaded in the background priority, not persisted so the collections
+	// recovered are loaded in the normal priority.
+	LowPriority bool
 }
 
 func (collection *Collection) SetRefreshNotifier(notifier chan struct{}) {
@@ -82,6 +86,7 @@ func (collection *Collection) Clone() *Collection {
 		UpdatedAt:          collection.UpdatedAt,
 		refreshNotifier:    collection.refreshNotifier,
 		LoadSpan:           collection.LoadSpan,
+		LowPriority:        collection.LowPriority,
 	}
 }
 
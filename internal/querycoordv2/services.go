@@ -18,7 +18,9 @@ package querycoordv2
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"sync"
 
 	"github.com/cockroachdb/errors"
@@ -35,6 +37,7 @@ import (
 	"github.com/milvus-io/milvus/internal/querycoordv2/job"
 	"github.com/milvus-io/milvus/internal/querycoordv2/meta"
 	"github.com/milvus-io/milvus/internal/querycoordv2/utils"
+	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/util/merr"
@@ -850,6 +853,20 @@ func (s *Server) GetReplicas(ctx context.Context, req *milvuspb.GetReplicasReque
 	for _, replica := range replicas {
 		resp.Replicas = append(resp.Replicas, s.fillReplicaInfo(replica, req.GetWithShardNodes()))
 	}
+
+	if req.GetWithShardNodes() {
+		health := make(map[int64]*ReplicaHealth, len(resp.Replicas))
+		for _, replica := range resp.Replicas {
+			health[replica.GetReplicaID()] = s.replicaHealth(replica)
+		}
+		bs, err := json.Marshal(health)
+		if err != nil {
+			return &milvuspb.GetReplicasResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+		resp.Status.ExtraInfo = map[string]string{common.ReplicaHealthKey: string(bs)}
+	}
 	return resp, nil
 }
 
@@ -883,7 +900,9 @@ func (s *Server) GetShardLeaders(ctx context.Context, req *querypb.GetShardLeade
 		// when collection is loaded, regard collection as readable, set percentage == 100
 		percentage = 100
 	}
-	if percentage < 100 {
+	// with the progressive load, the collection loading is readable once the leaders serve enough of the segments
+	minSegmentRatio := Params.QueryCoordCfg.ProgressiveLoadMinSegmentRatio.GetAsFloat()
+	if percentage < 100 && (minSegmentRatio >= 1 || float64(percentage) < minSegmentRatio*100) {
 		err := merr.WrapErrCollectionNotFullyLoaded(req.GetCollectionID())
 		msg := fmt.Sprintf("collection %v is not fully loaded", req.GetCollectionID())
 		log.Warn(msg)
@@ -891,7 +910,13 @@ func (s *Server) GetShardLeaders(ctx context.Context, req *querypb.GetShardLeade
 		return resp, nil
 	}
 
-	channels := s.targetMgr.GetDmChannelsByCollection(req.GetCollectionID(), meta.CurrentTarget)
+	scope := meta.CurrentTarget
+	channels := s.targetMgr.GetDmChannelsByCollection(req.GetCollectionID(), scope)
+	if len(channels) == 0 && percentage < 100 {
+		// the current target is not updated until the collection is loaded, read the next target meanwhile
+		scope = meta.NextTarget
+		channels = s.targetMgr.GetDmChannelsByCollection(req.GetCollectionID(), scope)
+	}
 	if len(channels) == 0 {
 		err := merr.WrapErrCollectionOnRecovering(req.GetCollectionID(),
 			"loaded collection do not found any channel in target, may be in recovery")
@@ -900,13 +925,17 @@ func (s *Server) GetShardLeaders(ctx context.Context, req *querypb.GetShardLeade
 		return resp, nil
 	}
 
-	currentTargets := s.targetMgr.GetSealedSegmentsByCollection(req.GetCollectionID(), meta.CurrentTarget)
+	currentTargets := s.targetMgr.GetSealedSegmentsByCollection(req.GetCollectionID(), scope)
+	shardCoverage := make(map[string]float64)
 	for _, channel := range channels {
 		log := log.With(zap.String("channel", channel.GetChannelName()))
 
 		leaders := s.dist.LeaderViewManager.GetByFilter(meta.WithChannelName2LeaderView(channel.GetChannelName()))
 
 		readableLeaders := make(map[int64]*meta.LeaderView)
+		// the leaders serving part of the segments, readable if no leader serves all of them
+		partialLeaders := make(map[int64]*meta.LeaderView)
+		coverage := 1.0
 
 		var channelErr error
 		if len(leaders) == 0 {
@@ -916,12 +945,25 @@ func (s *Server) GetShardLeaders(ctx context.Context, req *querypb.GetShardLeade
 		for _, leader := range leaders {
 			if err := checkers.CheckLeaderAvailable(s.nodeMgr, leader, currentTargets); err != nil {
 				multierr.AppendInto(&channelErr, err)
+				if minSegmentRatio < 1 && errors.Is(err, merr.ErrSegmentLack) {
+					if ratio := checkers.LeaderSegmentCoverage(leader, currentTargets); ratio >= minSegmentRatio {
+						partialLeaders[leader.ID] = leader
+						coverage = math.Min(coverage, ratio)
+					}
+				}
 				continue
 			}
 
 			readableLeaders[leader.ID] = leader
 		}
 
+		if len(readableLeaders) == 0 && len(partialLeaders) > 0 {
+			log.RatedInfo(10, "channel is partially loaded, read the leaders serving part of the segments",
+				zap.Float64("coverage", coverage))
+			readableLeaders = partialLeaders
+			shardCoverage[channel.GetChannelName()] = coverage * 100
+		}
+
 		if len(readableLeaders) == 0 {
 			msg := fmt.Sprintf("channel %s is not available in any replica", channel.GetChannelName())
 			log.Warn(msg, zap.Error(channelErr))
@@ -959,6 +1001,15 @@ func (s *Server) GetShardLeaders(ctx context.Context, req *querypb.GetShardLeade
 		})
 	}
 
+	if len(shardCoverage) > 0 {
+		bs, err := json.Marshal(shardCoverage)
+		if err != nil {
+			resp.Status = merr.Status(err)
+			resp.Shards = nil
+			return resp, nil
+		}
+		resp.Status.ExtraInfo = map[string]string{common.ShardCoverageKey: string(bs)}
+	}
 	return resp, nil
 }
 
@@ -49,6 +49,7 @@ import (
 	"github.com/milvus-io/milvus/internal/querycoordv2/task"
 	"github.com/milvus-io/milvus/internal/querycoordv2/utils"
 	"github.com/milvus-io/milvus/internal/util/sessionutil"
+	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/util/etcd"
 	"github.com/milvus-io/milvus/pkg/util/merr"
@@ -1529,6 +1530,7 @@ func (suite *ServiceSuite) TestGetReplicas() {
 	}
 
 	// Test get with shard nodes
+	suite.fetchHeartbeats(time.Now())
 	for _, collection := range suite.collections {
 		replicas := suite.meta.ReplicaManager.GetByCollection(collection)
 		for _, replica := range replicas {
@@ -1557,6 +1559,32 @@ func (suite *ServiceSuite) TestGetReplicas() {
 
 			suite.Equal(len(replica.GetNodeIds()), len(suite.meta.ReplicaManager.Get(replica.ReplicaID).GetNodes()))
 		}
+
+		// Test the health of the shard leaders
+		health := make(map[int64]*ReplicaHealth)
+		suite.NoError(json.Unmarshal([]byte(resp.GetStatus().GetExtraInfo()[common.ReplicaHealthKey]), &health))
+		suite.Len(health, len(resp.GetReplicas()))
+		for _, replica := range resp.GetReplicas() {
+			suite.Equal(len(replica.GetShardReplicas()) > 0, health[replica.GetReplicaID()].Readable)
+			suite.Len(health[replica.GetReplicaID()].Shards, len(replica.GetShardReplicas()))
+		}
+	}
+
+	// Test the shard leaders of stale heartbeats
+	suite.fetchHeartbeats(time.Now().Add(-time.Hour))
+	resp, err := server.GetReplicas(ctx, &milvuspb.GetReplicasRequest{
+		CollectionID:   suite.collections[0],
+		WithShardNodes: true,
+	})
+	suite.NoError(err)
+	health := make(map[int64]*ReplicaHealth)
+	suite.NoError(json.Unmarshal([]byte(resp.GetStatus().GetExtraInfo()[common.ReplicaHealthKey]), &health))
+	for _, replica := range health {
+		suite.False(replica.Readable)
+		for _, shard := range replica.Shards {
+			suite.False(shard.Healthy)
+			suite.NotEmpty(shard.Reason)
+		}
 	}
 
 	// Test when server is not healthy
@@ -1564,7 +1592,7 @@ func (suite *ServiceSuite) TestGetReplicas() {
 	req := &milvuspb.GetReplicasRequest{
 		CollectionID: suite.collections[0],
 	}
-	resp, err := server.GetReplicas(ctx, req)
+	resp, err = server.GetReplicas(ctx, req)
 	suite.NoError(err)
 	suite.Equal(resp.GetStatus().GetCode(), merr.Code(merr.ErrServiceNotReady))
 }
@@ -1652,6 +1680,29 @@ func (suite *ServiceSuite) TestGetShardLeaders() {
 		for _, shard := range resp.Shards {
 			suite.Len(shard.NodeIds, int(suite.replicaNumber[collection]))
 		}
+
+		// the loading collection is readable once the load percentage reaches the progressive load ratio
+		suite.updateCollectionStatus(collection, querypb.LoadStatus_Loading)
+		for _, partition := range suite.meta.GetPartitionsByCollection(collection) {
+			partition := partition.Clone()
+			partition.LoadPercentage = 50
+			suite.meta.PutPartition(partition)
+		}
+		resp, err = server.GetShardLeaders(ctx, req)
+		suite.NoError(err)
+		suite.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrCollectionNotFullyLoaded)
+
+		paramtable.Get().Save(Params.QueryCoordCfg.ProgressiveLoadMinSegmentRatio.Key, "0.6")
+		resp, err = server.GetShardLeaders(ctx, req)
+		suite.NoError(err)
+		suite.ErrorIs(merr.Error(resp.GetStatus()), merr.ErrCollectionNotFullyLoaded)
+
+		paramtable.Get().Save(Params.QueryCoordCfg.ProgressiveLoadMinSegmentRatio.Key, "0.5")
+		resp, err = server.GetShardLeaders(ctx, req)
+		paramtable.Get().Reset(Params.QueryCoordCfg.ProgressiveLoadMinSegmentRatio.Key)
+		suite.NoError(err)
+		suite.NoError(merr.Error(resp.GetStatus()))
+		suite.Len(resp.Shards, len(suite.channels[collection]))
 	}
 
 	// Test when server is not healthy
@@ -1703,6 +1754,19 @@ func (suite *ServiceSuite) TestGetShardLeadersFailed() {
 		resp, err = server.GetShardLeaders(ctx, req)
 		suite.NoError(err)
 		suite.Equal(commonpb.ErrorCode_NoReplicaAvailable, resp.GetStatus().GetErrorCode())
+
+		// read the partially loaded shards
+		paramtable.Get().Save(Params.QueryCoordCfg.ProgressiveLoadMinSegmentRatio.Key, "0")
+		resp, err = server.GetShardLeaders(ctx, req)
+		paramtable.Get().Reset(Params.QueryCoordCfg.ProgressiveLoadMinSegmentRatio.Key)
+		suite.NoError(err)
+		suite.NoError(merr.Error(resp.GetStatus()))
+		suite.Len(resp.Shards, len(suite.channels[collection]))
+		coverage := make(map[string]float64)
+		suite.NoError(json.Unmarshal([]byte(resp.GetStatus().GetExtraInfo()[common.ShardCoverageKey]), &coverage))
+		for _, percentage := range coverage {
+			suite.Less(percentage, float64(100))
+		}
 	}
 
 	// channel not subscribed
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"path"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -78,6 +79,7 @@ type ShardDelegator interface {
 	ReleaseSegments(ctx context.Context, req *querypb.ReleaseSegmentsRequest, force bool) error
 	SyncTargetVersion(newVersion int64, growingInTarget []int64, sealedInTarget []int64, droppedInTarget []int64, checkpoint *msgpb.MsgPosition)
 	GetTargetVersion() int64
+	GetTSafe() uint64
 
 	// manage exclude segments
 	AddExcludedSegments(excludeInfo map[int64]uint64)
@@ -199,6 +201,29 @@ func (sd *shardDelegator) modifyQueryRequest(req *querypb.QueryRequest, scope qu
 	return nodeReq
 }
 
+// excludeSealedSegments removes the sealed segments excluded in the msg base of the request, like the segments of the
+// import jobs not committed yet. The pinned snapshot items are not modified.
+func excludeSealedSegments(sealed []SnapshotItem, base *commonpb.MsgBase) []SnapshotItem {
+	value := base.GetProperties()[common.ExcludedSegmentsKey]
+	if value == "" {
+		return sealed
+	}
+	excluded := typeutil.NewSet[int64]()
+	for _, id := range strings.Split(value, ",") {
+		if segmentID, err := strconv.ParseInt(id, 10, 64); err == nil {
+			excluded.Insert(segmentID)
+		}
+	}
+	return lo.Map(sealed, func(item SnapshotItem, _ int) SnapshotItem {
+		return SnapshotItem{
+			NodeID: item.NodeID,
+			Segments: lo.Filter(item.Segments, func(segment SegmentEntry, _ int) bool {
+				return !excluded.Contain(segment.SegmentID)
+			}),
+		}
+	})
+}
+
 // Search preforms search operation on shard.
 func (sd *shardDelegator) search(ctx context.Context, req *querypb.SearchRequest, sealed []SnapshotItem, growing []SegmentEntry) ([]*internalpb.SearchResults, error) {
 	log := sd.getLogger(ctx)
@@ -288,6 +313,7 @@ func (sd *shardDelegator) Search(ctx context.Context, req *querypb.SearchRequest
 	growing = lo.Filter(growing, func(segment SegmentEntry, _ int) bool {
 		return funcutil.SliceContain(existPartitions, segment.PartitionID)
 	})
+	sealed = excludeSealedSegments(sealed, req.GetReq().GetBase())
 
 	if req.GetReq().GetIsAdvanced() {
 		futures := make([]*conc.Future[*internalpb.SearchResults], len(req.GetReq().GetSubReqs()))
@@ -670,6 +696,12 @@ func executeSubTasks[T any, R interface {
 	return results, nil
 }
 
+// GetTSafe returns the latest tsafe of the delegator, the search and query with a greater guarantee timestamp
+// wait the tsafe.
+func (sd *shardDelegator) GetTSafe() uint64 {
+	return sd.latestTsafe.Load()
+}
+
 // waitTSafe returns when tsafe listener notifies a timestamp which meet the guarantee ts.
 func (sd *shardDelegator) waitTSafe(ctx context.Context, ts uint64) (uint64, error) {
 	ctx, sp := otel.Tracer(typeutil.QueryNodeRole).Start(ctx, "Delegator-waitTSafe")
@@ -1150,6 +1150,7 @@ func TestDelegatorWatchTsafe(t *testing.T) {
 	assert.Eventually(t, func() bool {
 		return sd.latestTsafe.Load() == 200
 	}, time.Second*10, time.Millisecond*10)
+	assert.Equal(t, uint64(200), sd.GetTSafe())
 }
 
 func TestDelegatorTSafeListenerClosed(t *testing.T) {
@@ -1193,3 +1194,20 @@ func TestDelegatorTSafeListenerClosed(t *testing.T) {
 	assert.Equal(t, sd.Serviceable(), false)
 	assert.Equal(t, sd.Stopped(), true)
 }
+
+func TestExcludeSealedSegments(t *testing.T) {
+	sealed := []SnapshotItem{
+		{NodeID: 1, Segments: []SegmentEntry{{SegmentID: 100}, {SegmentID: 101}}},
+		{NodeID: 2, Segments: []SegmentEntry{{SegmentID: 102}}},
+	}
+	assert.Equal(t, sealed, excludeSealedSegments(sealed, &commonpb.MsgBase{}))
+
+	result := excludeSealedSegments(sealed, &commonpb.MsgBase{
+		Properties: map[string]string{common.ExcludedSegmentsKey: "101,102"},
+	})
+	assert.Len(t, result, 2)
+	assert.Equal(t, []SegmentEntry{{SegmentID: 100}}, result[0].Segments)
+	assert.Empty(t, result[1].Segments)
+	// the pinned snapshot is not modified
+	assert.Len(t, sealed[0].Segments, 2)
+}
@@ -245,6 +245,47 @@ func (_c *MockShardDelegator_GetStatistics_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
+// GetTSafe provides a mock function with given fields:
+func (_m *MockShardDelegator) GetTSafe() uint64 {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
+// MockShardDelegator_GetTSafe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTSafe'
+type MockShardDelegator_GetTSafe_Call struct {
+	*mock.Call
+}
+
+// GetTSafe is a helper method to define mock.On call
+func (_e *MockShardDelegator_Expecter) GetTSafe() *MockShardDelegator_GetTSafe_Call {
+	return &MockShardDelegator_GetTSafe_Call{Call: _e.mock.On("GetTSafe")}
+}
+
+func (_c *MockShardDelegator_GetTSafe_Call) Run(run func()) *MockShardDelegator_GetTSafe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockShardDelegator_GetTSafe_Call) Return(_a0 uint64) *MockShardDelegator_GetTSafe_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockShardDelegator_GetTSafe_Call) RunAndReturn(run func() uint64) *MockShardDelegator_GetTSafe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTargetVersion provides a mock function with given fields:
 func (_m *MockShardDelegator) GetTargetVersion() int64 {
 	ret := _m.Called()
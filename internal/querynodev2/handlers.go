@@ -19,7 +19,9 @@ package querynodev2
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/samber/lo"
 	"go.opentelemetry.io/otel/trace"
@@ -33,6 +35,7 @@ import (
 	"github.com/milvus-io/milvus/internal/querynodev2/segments"
 	"github.com/milvus-io/milvus/internal/querynodev2/tasks"
 	"github.com/milvus-io/milvus/internal/util/streamrpc"
+	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/util/funcutil"
@@ -370,6 +373,8 @@ func (node *QueryNode) searchChannel(ctx context.Context, req *querypb.SearchReq
 		log.Warn("Query failed, failed to get shard delegator for search", zap.Error(err))
 		return nil, err
 	}
+	// the delegator waits the tsafe to reach the guarantee timestamp
+	waitTSafe := sd.GetTSafe() < req.GetReq().GetGuaranteeTimestamp()
 	// do search
 	results, err := sd.Search(searchCtx, req)
 	if err != nil {
@@ -394,6 +399,10 @@ func (node *QueryNode) searchChannel(ctx context.Context, req *querypb.SearchReq
 		return nil, err
 	}
 
+	if waitTSafe {
+		setTSafeWaitedChannels(resp, channel)
+	}
+
 	tr.CtxElapse(ctx, fmt.Sprintf("do search with channel done , vChannel = %s, segmentIDs = %v",
 		channel,
 		req.GetSegmentIDs(),
@@ -408,6 +417,31 @@ func (node *QueryNode) searchChannel(ctx context.Context, req *querypb.SearchReq
 	return resp, nil
 }
 
+// tsafeWaitedChannels returns the channels whose delegator waited the tsafe for the search result.
+func tsafeWaitedChannels(result *internalpb.SearchResults) []string {
+	channels := result.GetStatus().GetExtraInfo()[common.TSafeWaitedChannelsKey]
+	if channels == "" {
+		return nil
+	}
+	return strings.Split(channels, ",")
+}
+
+// setTSafeWaitedChannels adds the channels whose delegator waited the tsafe to the extra info of the result status.
+func setTSafeWaitedChannels(result *internalpb.SearchResults, channels ...string) {
+	channels = lo.Uniq(append(tsafeWaitedChannels(result), channels...))
+	if len(channels) == 0 {
+		return
+	}
+	sort.Strings(channels)
+	if result.Status == nil {
+		result.Status = merr.Success()
+	}
+	if result.Status.ExtraInfo == nil {
+		result.Status.ExtraInfo = make(map[string]string)
+	}
+	result.Status.ExtraInfo[common.TSafeWaitedChannelsKey] = strings.Join(channels, ",")
+}
+
 func (node *QueryNode) getChannelStatistics(ctx context.Context, req *querypb.GetStatisticsRequest, channel string) (*internalpb.GetStatisticsResponse, error) {
 	log := log.Ctx(ctx).With(
 		zap.Int64("collectionID", req.Req.GetCollectionID()),
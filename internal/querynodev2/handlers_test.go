@@ -26,11 +26,14 @@ import (
 	clientv3 "go.etcd.io/etcd/client/v3"
 
 	"github.com/milvus-io/milvus/internal/proto/datapb"
+	"github.com/milvus-io/milvus/internal/proto/internalpb"
 	"github.com/milvus-io/milvus/internal/proto/querypb"
 	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/util/dependency"
+	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util/etcd"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 )
 
@@ -134,6 +137,19 @@ func (suite *HandlersSuite) TestLoadGrowingSegments() {
 	suite.Equal(1, len(loadSegmetns))
 }
 
+func (suite *HandlersSuite) TestTSafeWaitedChannels() {
+	result := &internalpb.SearchResults{}
+	suite.Empty(tsafeWaitedChannels(result))
+	setTSafeWaitedChannels(result)
+	suite.Nil(result.GetStatus())
+
+	setTSafeWaitedChannels(result, "ch2")
+	setTSafeWaitedChannels(result, "ch1", "ch2")
+	suite.True(merr.Ok(result.GetStatus()))
+	suite.Equal("ch1,ch2", result.GetStatus().GetExtraInfo()[common.TSafeWaitedChannelsKey])
+	suite.Equal([]string{"ch1", "ch2"}, tsafeWaitedChannels(result))
+}
+
 func TestHandlersSuite(t *testing.T) {
 	suite.Run(t, new(HandlersSuite))
 }
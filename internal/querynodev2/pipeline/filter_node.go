@@ -115,6 +115,12 @@ func (fNode *filterNode) filtrate(c *Collection, msg msgstream.TsMsg) error {
 			}
 		}
 
+		// the data is kept whatever the schema version, only report the mismatch
+		if err := c.CheckSchemaVersion(insertMsg.GetBase()); err != nil {
+			log.RatedWarn(60, "insert with mismatched schema version",
+				zap.String("channel", fNode.channel), zap.Int64("segmentID", insertMsg.GetSegmentID()), zap.Error(err))
+		}
+
 		// check segment whether excluded
 		ok := fNode.delegator.VerifyExcludedSegments(insertMsg.SegmentID, insertMsg.EndTimestamp)
 		if !ok {
@@ -41,6 +41,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/log"
 	"github.com/milvus-io/milvus/pkg/metrics"
 	"github.com/milvus-io/milvus/pkg/util/indexparamcheck"
+	"github.com/milvus-io/milvus/pkg/util/merr"
 	"github.com/milvus-io/milvus/pkg/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
@@ -170,6 +171,19 @@ func (c *Collection) Schema() *schemapb.CollectionSchema {
 	return c.schema.Load()
 }
 
+// CheckSchemaVersion returns ErrCollectionSchemaMismatch if the request is planned with a schema version different from
+// the loaded one, e.g. by a proxy with the stale collection meta cached. The requests without version are not checked.
+func (c *Collection) CheckSchemaVersion(base *commonpb.MsgBase) error {
+	expected := base.GetProperties()[common.SchemaVersionKey]
+	if expected == "" {
+		return nil
+	}
+	if actual := typeutil.SchemaVersion(c.Schema()); actual != expected {
+		return merr.WrapErrCollectionSchemaMismatch(c.id, expected, actual)
+	}
+	return nil
+}
+
 // IsGpuIndex returns a boolean value indicating whether the collection is using a GPU index.
 func (c *Collection) IsGpuIndex() bool {
 	return c.isGpuIndex
@@ -729,6 +729,11 @@ func (node *QueryNode) Search(ctx context.Context, req *querypb.SearchRequest) (
 		resp.Status = merr.Status(merr.WrapErrCollectionNotFound(req.GetReq().GetCollectionID()))
 		return resp, nil
 	}
+	if err := collection.CheckSchemaVersion(req.GetReq().GetBase()); err != nil {
+		log.Warn("search with mismatched schema version", zap.Error(err))
+		resp.Status = merr.Status(err)
+		return resp, nil
+	}
 
 	toReduceResults := make([]*internalpb.SearchResults, len(req.GetDmlChannels()))
 	runningGp, runningCtx := errgroup.WithContext(ctx)
@@ -776,6 +781,10 @@ func (node *QueryNode) Search(ctx context.Context, req *querypb.SearchRequest) (
 		return resp, nil
 	}
 
+	setTSafeWaitedChannels(result, lo.FlatMap(toReduceResults, func(result *internalpb.SearchResults, _ int) []string {
+		return tsafeWaitedChannels(result)
+	})...)
+
 	reduceLatency := tr.RecordSpan()
 	metrics.QueryNodeReduceLatency.
 		WithLabelValues(fmt.Sprint(node.GetNodeID()), metrics.SearchLabel, metrics.ReduceShards, metrics.BatchReduce).
@@ -885,6 +894,15 @@ func (node *QueryNode) Query(ctx context.Context, req *querypb.QueryRequest) (*i
 	}
 	defer node.lifetime.Done()
 
+	if collection := node.manager.Collection.Get(req.GetReq().GetCollectionID()); collection != nil {
+		if err := collection.CheckSchemaVersion(req.GetReq().GetBase()); err != nil {
+			log.Warn("query with mismatched schema version", zap.Error(err))
+			return &internalpb.RetrieveResults{
+				Status: merr.Status(err),
+			}, nil
+		}
+	}
+
 	toMergeResults := make([]*internalpb.RetrieveResults, len(req.GetDmlChannels()))
 	runningGp, runningCtx := errgroup.WithContext(ctx)
 
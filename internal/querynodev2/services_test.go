@@ -1484,6 +1484,49 @@ func (suite *ServiceSuite) TestQuery_Failed() {
 	suite.Equal(commonpb.ErrorCode_NotReadyServe, resp.Status.GetErrorCode())
 }
 
+func (suite *ServiceSuite) TestSearchAndQuery_SchemaVersion() {
+	ctx := context.Background()
+	// pre
+	suite.TestWatchDmChannelsInt64()
+	suite.TestLoadSegments_Int64()
+
+	version := typeutil.SchemaVersion(suite.node.manager.Collection.Get(suite.collectionID).Schema())
+
+	creq, err := suite.genCSearchRequest(10, schemapb.DataType_FloatVector, 107, defaultMetricType)
+	suite.NoError(err)
+	searchReq := &querypb.SearchRequest{
+		Req:             creq,
+		DmlChannels:     []string{suite.vchannel},
+		TotalChannelNum: 2,
+	}
+	creq.Base.Properties = map[string]string{common.SchemaVersionKey: "stale"}
+	rsp, err := suite.node.Search(ctx, searchReq)
+	suite.NoError(err)
+	suite.ErrorIs(merr.Error(rsp.GetStatus()), merr.ErrCollectionSchemaMismatch)
+
+	creq.Base.Properties[common.SchemaVersionKey] = version
+	rsp, err = suite.node.Search(ctx, searchReq)
+	suite.NoError(err)
+	suite.NoError(merr.Error(rsp.GetStatus()))
+
+	schema := segments.GenTestCollectionSchema(suite.collectionName, schemapb.DataType_Int64, false)
+	qreq, err := suite.genCQueryRequest(10, IndexFaissIDMap, schema)
+	suite.NoError(err)
+	queryReq := &querypb.QueryRequest{
+		Req:         qreq,
+		DmlChannels: []string{suite.vchannel},
+	}
+	qreq.Base.Properties = map[string]string{common.SchemaVersionKey: "stale"}
+	qrsp, err := suite.node.Query(ctx, queryReq)
+	suite.NoError(err)
+	suite.ErrorIs(merr.Error(qrsp.GetStatus()), merr.ErrCollectionSchemaMismatch)
+
+	qreq.Base.Properties[common.SchemaVersionKey] = version
+	qrsp, err = suite.node.Query(ctx, queryReq)
+	suite.NoError(err)
+	suite.NoError(merr.Error(qrsp.GetStatus()))
+}
+
 func (suite *ServiceSuite) TestQuerySegments_Failed() {
 	ctx := context.Background()
 
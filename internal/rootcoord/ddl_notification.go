@@ -0,0 +1,55 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rootcoord
+
+import (
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/util/ddlnotify"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+)
+
+const ddlNotificationPrefix = "rootcoord/ddl_notification/pending"
+
+// newDDLEvent returns the event of the ddl task executed successfully, or nil if the task is not notified.
+func newDDLEvent(t task) *ddlnotify.Event {
+	event := &ddlnotify.Event{Timestamp: t.GetTs()}
+	switch t := t.(type) {
+	case *createCollectionTask:
+		event.Type = commonpb.MsgType_CreateCollection.String()
+		event.DbName, event.CollectionID, event.CollectionName = t.Req.GetDbName(), t.collID, t.Req.GetCollectionName()
+		event.Properties = funcutil.KeyValuePair2Map(t.Req.GetProperties())
+	case *dropCollectionTask:
+		event.Type = commonpb.MsgType_DropCollection.String()
+		event.DbName, event.CollectionName = t.Req.GetDbName(), t.Req.GetCollectionName()
+	case *alterCollectionTask:
+		event.Type = commonpb.MsgType_AlterCollection.String()
+		event.DbName, event.CollectionName = t.Req.GetDbName(), t.Req.GetCollectionName()
+		event.Properties = funcutil.KeyValuePair2Map(t.Req.GetProperties())
+	case *createAliasTask:
+		event.Type = commonpb.MsgType_CreateAlias.String()
+		event.DbName, event.CollectionName, event.Alias = t.Req.GetDbName(), t.Req.GetCollectionName(), t.Req.GetAlias()
+	case *dropAliasTask:
+		event.Type = commonpb.MsgType_DropAlias.String()
+		event.DbName, event.Alias = t.Req.GetDbName(), t.Req.GetAlias()
+	case *alterAliasTask:
+		event.Type = commonpb.MsgType_AlterAlias.String()
+		event.DbName, event.CollectionName, event.Alias = t.Req.GetDbName(), t.Req.GetCollectionName(), t.Req.GetAlias()
+	default:
+		return nil
+	}
+	return event
+}
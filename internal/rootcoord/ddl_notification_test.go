@@ -0,0 +1,56 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rootcoord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+)
+
+func TestNewDDLEvent(t *testing.T) {
+	cct := &createCollectionTask{
+		baseTask: newBaseTask(context.Background(), nil),
+		Req: &milvuspb.CreateCollectionRequest{
+			DbName:         "db",
+			CollectionName: "coll",
+			Properties:     []*commonpb.KeyValuePair{{Key: "k", Value: "v"}},
+		},
+		collID: 1,
+	}
+	cct.SetTs(100)
+	event := newDDLEvent(cct)
+	assert.Equal(t, "CreateCollection", event.Type)
+	assert.Equal(t, uint64(100), event.Timestamp)
+	assert.Equal(t, "db", event.DbName)
+	assert.Equal(t, int64(1), event.CollectionID)
+	assert.Equal(t, "coll", event.CollectionName)
+	assert.Equal(t, map[string]string{"k": "v"}, event.Properties)
+
+	dat := &dropAliasTask{baseTask: newBaseTask(context.Background(), nil), Req: &milvuspb.DropAliasRequest{Alias: "a"}}
+	dat.SetTs(101)
+	event = newDDLEvent(dat)
+	assert.Equal(t, "DropAlias", event.Type)
+	assert.Equal(t, uint64(101), event.Timestamp)
+	assert.Equal(t, "a", event.Alias)
+
+	assert.Nil(t, newDDLEvent(newMockNormalTask()))
+}
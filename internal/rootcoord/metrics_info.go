@@ -18,6 +18,7 @@ package rootcoord
 
 import (
 	"context"
+	"encoding/json"
 
 	"go.uber.org/zap"
 
@@ -80,3 +81,38 @@ func (c *Core) getSystemInfoMetrics(ctx context.Context, req *milvuspb.GetMetric
 		ComponentName: metricsinfo.ConstructComponentName(typeutil.RootCoordRole, c.session.ServerID),
 	}, nil
 }
+
+// getCollectionReadsMetrics merges the last time the collections served searches or queries reported by the proxies,
+// for the proxies to check the recent read traffic of the collections through other proxies.
+func (c *Core) getCollectionReadsMetrics(ctx context.Context) (*milvuspb.GetMetricsResponse, error) {
+	rsps, err := c.proxyClientManager.GetProxyMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reads := metricsinfo.CollectionReads{
+		LastReads: make(map[int64]int64),
+	}
+	for _, rsp := range rsps {
+		proxyMetric := &metricsinfo.ProxyInfos{}
+		if err := metricsinfo.UnmarshalComponentInfos(rsp.GetResponse(), proxyMetric); err != nil {
+			return nil, err
+		}
+		if proxyMetric.QuotaMetrics == nil {
+			continue
+		}
+		for collectionID, lastRead := range proxyMetric.QuotaMetrics.CollectionLastReads {
+			if lastRead > reads.LastReads[collectionID] {
+				reads.LastReads[collectionID] = lastRead
+			}
+		}
+	}
+	bs, err := json.Marshal(reads)
+	if err != nil {
+		return nil, err
+	}
+	return &milvuspb.GetMetricsResponse{
+		Status:        merr.Success(),
+		Response:      string(bs),
+		ComponentName: metricsinfo.ConstructComponentName(typeutil.RootCoordRole, c.session.ServerID),
+	}, nil
+}
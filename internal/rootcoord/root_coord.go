@@ -48,6 +48,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proto/rootcoordpb"
 	tso2 "github.com/milvus-io/milvus/internal/tso"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/ddlnotify"
 	"github.com/milvus-io/milvus/internal/util/dependency"
 	"github.com/milvus-io/milvus/internal/util/proxyutil"
 	"github.com/milvus-io/milvus/internal/util/sessionutil"
@@ -98,6 +99,7 @@ type Core struct {
 	ddlTsLockManager DdlTsLockManager
 	garbageCollector GarbageCollector
 	stepExecutor     StepExecutor
+	ddlNotifier      *ddlnotify.Notifier
 
 	metaKVCreator metaKVCreator
 
@@ -443,7 +445,14 @@ func (c *Core) initInternal() error {
 		return err
 	}
 
-	c.scheduler = newScheduler(c.ctx, c.idAllocator, c.tsoAllocator)
+	ddlNotificationKV, err := c.metaKVCreator()
+	if err != nil {
+		return err
+	}
+	c.ddlNotifier = ddlnotify.NewNotifier(ddlNotificationKV, ddlNotificationPrefix)
+	scheduler := newScheduler(c.ctx, c.idAllocator, c.tsoAllocator)
+	scheduler.ddlNotifier = c.ddlNotifier
+	c.scheduler = scheduler
 
 	c.factory.Init(Params)
 	chanMap := c.meta.ListCollectionPhysicalChannels()
@@ -679,6 +688,9 @@ func (c *Core) startInternal() error {
 		c.quotaCenter.Start()
 	}
 
+	if err := c.ddlNotifier.Start(c.ctx); err != nil {
+		return err
+	}
 	c.scheduler.Start()
 	c.stepExecutor.Start()
 	go func() {
@@ -757,6 +769,7 @@ func (c *Core) Stop() error {
 	c.UpdateStateCode(commonpb.StateCode_Abnormal)
 	c.stopExecutor()
 	c.stopScheduler()
+	c.ddlNotifier.Close()
 	if c.proxyWatcher != nil {
 		c.proxyWatcher.Stop()
 	}
@@ -1711,6 +1724,17 @@ func (c *Core) GetMetrics(ctx context.Context, in *milvuspb.GetMetricsRequest) (
 		return metrics, err
 	}
 
+	if metricType == metricsinfo.CollectionReadsMetrics {
+		metrics, err := c.getCollectionReadsMetrics(ctx)
+		if err != nil {
+			log.Warn("GetCollectionReadsMetrics failed", zap.String("role", typeutil.RootCoordRole), zap.Error(err))
+			return &milvuspb.GetMetricsResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+		return metrics, nil
+	}
+
 	log.RatedWarn(60, "GetMetrics failed, metric type not implemented", zap.String("role", typeutil.RootCoordRole),
 		zap.String("metricType", metricType))
 
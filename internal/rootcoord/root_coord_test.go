@@ -18,6 +18,7 @@ package rootcoord
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"os"
@@ -39,6 +40,7 @@ import (
 	mockrootcoord "github.com/milvus-io/milvus/internal/rootcoord/mocks"
 	"github.com/milvus-io/milvus/internal/util/dependency"
 	kvfactory "github.com/milvus-io/milvus/internal/util/dependency/kv"
+	"github.com/milvus-io/milvus/internal/util/proxyutil"
 	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/pkg/util"
 	"github.com/milvus-io/milvus/pkg/util/etcd"
@@ -1140,6 +1142,37 @@ func TestRootCoord_GetMetrics(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, commonpb.ErrorCode_Success, resp.GetStatus().GetErrorCode())
 	})
+
+	t.Run("get collection reads metrics", func(t *testing.T) {
+		req, err := metricsinfo.ConstructRequestByMetricType(metricsinfo.CollectionReadsMetrics)
+		assert.NoError(t, err)
+		ctx := context.Background()
+		proxyMetrics := func(lastReads map[int64]int64) *milvuspb.GetMetricsResponse {
+			resp, err := metricsinfo.MarshalComponentInfos(metricsinfo.ProxyInfos{
+				QuotaMetrics: &metricsinfo.ProxyQuotaMetrics{CollectionLastReads: lastReads},
+			})
+			assert.NoError(t, err)
+			return &milvuspb.GetMetricsResponse{Status: merr.Success(), Response: resp}
+		}
+		proxies := proxyutil.NewMockProxyClientManager(t)
+		proxies.EXPECT().GetProxyMetrics(mock.Anything).Return([]*milvuspb.GetMetricsResponse{
+			proxyMetrics(map[int64]int64{1: 100, 2: 300}),
+			proxyMetrics(map[int64]int64{1: 200}),
+			proxyMetrics(nil),
+		}, nil).Once()
+		c := newTestCore(withHealthyCode())
+		c.proxyClientManager = proxies
+		resp, err := c.GetMetrics(ctx, req)
+		assert.NoError(t, merr.CheckRPCCall(resp, err))
+		reads := metricsinfo.CollectionReads{}
+		assert.NoError(t, json.Unmarshal([]byte(resp.GetResponse()), &reads))
+		assert.Equal(t, map[int64]int64{1: 200, 2: 300}, reads.LastReads)
+
+		proxies.EXPECT().GetProxyMetrics(mock.Anything).Return(nil, errors.New("mock")).Once()
+		resp, err = c.GetMetrics(ctx, req)
+		assert.NoError(t, err)
+		assert.Error(t, merr.Error(resp.GetStatus()))
+	})
 }
 
 func TestCore_Rbac(t *testing.T) {
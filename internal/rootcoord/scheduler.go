@@ -26,6 +26,7 @@ import (
 
 	"github.com/milvus-io/milvus/internal/allocator"
 	"github.com/milvus-io/milvus/internal/tso"
+	"github.com/milvus-io/milvus/internal/util/ddlnotify"
 	"github.com/milvus-io/milvus/pkg/log"
 )
 
@@ -49,6 +50,9 @@ type scheduler struct {
 	lock sync.Mutex
 
 	minDdlTs atomic.Uint64
+
+	// ddlNotifier emits the ddl executed successfully, the tasks are executed one by one in the order of the ts.
+	ddlNotifier *ddlnotify.Notifier
 }
 
 func newScheduler(ctx context.Context, idAllocator allocator.Interface, tsoAllocator tso.Allocator) *scheduler {
@@ -86,6 +90,11 @@ func (s *scheduler) execute(task task) {
 		return
 	}
 	err := task.Execute(task.GetCtx())
+	if err == nil {
+		if event := newDDLEvent(task); event != nil {
+			s.ddlNotifier.Notify(event)
+		}
+	}
 	task.NotifyDone(err)
 }
 
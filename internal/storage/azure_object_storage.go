@@ -20,6 +20,7 @@ import (
 	"context"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -52,6 +53,10 @@ func newAzureObjectStorageWithConfig(ctx context.Context, c *config) (*AzureObje
 			return nil, credErr
 		}
 		client, err = service.NewClient("https://"+c.accessKeyID+".blob."+c.address+"/", cred, &service.ClientOptions{})
+	} else if c.sessionToken != "" {
+		// the session token is the SAS token of the storage account
+		client, err = service.NewClientWithNoCredential("https://"+c.accessKeyID+".blob."+c.address+"/?"+
+			strings.TrimPrefix(c.sessionToken, "?"), &service.ClientOptions{})
 	} else {
 		connectionString := os.Getenv("AZURE_STORAGE_CONNECTION_STRING")
 		if connectionString == "" {
@@ -60,18 +60,18 @@ func newMinioClient(ctx context.Context, c *config) (*minio.Client, error) {
 		if c.useIAM {
 			newMinioFn = aliyun.NewMinioClient
 		} else {
-			creds = credentials.NewStaticV4(c.accessKeyID, c.secretAccessKeyID, "")
+			creds = credentials.NewStaticV4(c.accessKeyID, c.secretAccessKeyID, c.sessionToken)
 		}
 	case CloudProviderGCP:
 		newMinioFn = gcp.NewMinioClient
 		if !c.useIAM {
-			creds = credentials.NewStaticV2(c.accessKeyID, c.secretAccessKeyID, "")
+			creds = credentials.NewStaticV2(c.accessKeyID, c.secretAccessKeyID, c.sessionToken)
 		}
 	case CloudProviderTencent:
 		bucketLookupType = minio.BucketLookupDNS
 		newMinioFn = tencent.NewMinioClient
 		if !c.useIAM {
-			creds = credentials.NewStaticV4(c.accessKeyID, c.secretAccessKeyID, "")
+			creds = credentials.NewStaticV4(c.accessKeyID, c.secretAccessKeyID, c.sessionToken)
 		}
 
 	default: // aws, minio
@@ -86,7 +86,7 @@ func newMinioClient(ctx context.Context, c *config) (*minio.Client, error) {
 		case strings.Contains(c.address, gcp.GcsDefaultAddress):
 			newMinioFn = gcp.NewMinioClient
 			if !c.useIAM {
-				creds = credentials.NewStaticV2(c.accessKeyID, c.secretAccessKeyID, "")
+				creds = credentials.NewStaticV2(c.accessKeyID, c.secretAccessKeyID, c.sessionToken)
 			}
 		case strings.Contains(c.address, aliyun.OSSAddressFeatureString):
 			// auto doesn't work for aliyun, so we set to dns deliberately
@@ -94,7 +94,7 @@ func newMinioClient(ctx context.Context, c *config) (*minio.Client, error) {
 			if c.useIAM {
 				newMinioFn = aliyun.NewMinioClient
 			} else {
-				creds = credentials.NewStaticV4(c.accessKeyID, c.secretAccessKeyID, "")
+				creds = credentials.NewStaticV4(c.accessKeyID, c.secretAccessKeyID, c.sessionToken)
 			}
 		default:
 			matchedDefault = true
@@ -106,7 +106,7 @@ func newMinioClient(ctx context.Context, c *config) (*minio.Client, error) {
 		if c.useIAM {
 			creds = credentials.NewIAM("")
 		} else {
-			creds = credentials.NewStaticV4(c.accessKeyID, c.secretAccessKeyID, "")
+			creds = credentials.NewStaticV4(c.accessKeyID, c.secretAccessKeyID, c.sessionToken)
 		}
 	}
 
@@ -6,6 +6,7 @@ type config struct {
 	bucketName        string
 	accessKeyID       string
 	secretAccessKeyID string
+	sessionToken      string
 	useSSL            bool
 	sslCACert         string
 	createBucket      bool
@@ -49,6 +50,13 @@ func SecretAccessKeyID(secretAccessKeyID string) Option {
 	}
 }
 
+// SessionToken sets the token of the temporary credentials, e.g. the STS session token, or the SAS token of azure.
+func SessionToken(sessionToken string) Option {
+	return func(c *config) {
+		c.sessionToken = sessionToken
+	}
+}
+
 func UseSSL(useSSL bool) Option {
 	return func(c *config) {
 		c.useSSL = useSSL
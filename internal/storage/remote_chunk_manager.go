@@ -20,7 +20,9 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
@@ -62,6 +64,11 @@ type ObjectStorage interface {
 	RemoveObject(ctx context.Context, bucketName, objectName string) error
 }
 
+// objectPresigner is implemented by the object storages supporting presigned urls, like minio.
+type objectPresigner interface {
+	PresignedGetObject(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values) (*url.URL, error)
+}
+
 // RemoteChunkManager is responsible for read and write data stored in minio.
 type RemoteChunkManager struct {
 	client ObjectStorage
@@ -237,6 +244,20 @@ func (mcm *RemoteChunkManager) MultiRead(ctx context.Context, keys []string) ([]
 	return objectsValues, el
 }
 
+// PresignedURL returns a presigned url to download @filePath without credentials, valid for @expires.
+func (mcm *RemoteChunkManager) PresignedURL(ctx context.Context, filePath string, expires time.Duration) (string, error) {
+	presigner, ok := mcm.client.(objectPresigner)
+	if !ok {
+		return "", merr.WrapErrServiceUnimplemented(errors.New("presigned url is not supported by the object storage"))
+	}
+	u, err := presigner.PresignedGetObject(ctx, mcm.bucketName, filePath, expires, nil)
+	if err != nil {
+		log.Warn("failed to presign object", zap.String("bucket", mcm.bucketName), zap.String("path", filePath), zap.Error(err))
+		return "", err
+	}
+	return u.String(), nil
+}
+
 func (mcm *RemoteChunkManager) Mmap(ctx context.Context, filePath string) (*mmap.ReaderAt, error) {
 	return nil, errors.New("this method has not been implemented")
 }
@@ -20,6 +20,7 @@ import (
 	"context"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/assert"
@@ -432,6 +433,24 @@ func TestMinioChunkManager(t *testing.T) {
 		assert.Nil(t, r)
 	})
 
+	t.Run("test PresignedURL", func(t *testing.T) {
+		testPresignRoot := path.Join(testMinIOKVRoot, "presign")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		testCM, err := newMinioChunkManager(ctx, testBucket, testPresignRoot)
+		require.NoError(t, err)
+		defer testCM.RemoveWithPrefix(ctx, testPresignRoot)
+
+		key := path.Join(testPresignRoot, "TestMinIOKV_Presign_key")
+		err = testCM.Write(ctx, key, []byte("TestMinIOKV_Presign_value"))
+		assert.NoError(t, err)
+
+		u, err := testCM.(*RemoteChunkManager).PresignedURL(ctx, key, time.Minute)
+		assert.NoError(t, err)
+		assert.Contains(t, u, key)
+	})
+
 	t.Run("test Prefix", func(t *testing.T) {
 		testPrefix := path.Join(testMinIOKVRoot, "prefix")
 		ctx, cancel := context.WithCancel(context.Background())
@@ -886,6 +905,18 @@ func TestAzureChunkManager(t *testing.T) {
 		assert.Nil(t, r)
 	})
 
+	t.Run("test PresignedURL", func(t *testing.T) {
+		testPresignRoot := path.Join(testMinIOKVRoot, "presign")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		testCM, err := newAzureChunkManager(ctx, testBucket, testPresignRoot)
+		require.NoError(t, err)
+
+		_, err = testCM.(*RemoteChunkManager).PresignedURL(ctx, path.Join(testPresignRoot, "key"), time.Minute)
+		assert.ErrorIs(t, err, merr.ErrServiceUnimplemented)
+	})
+
 	t.Run("test Prefix", func(t *testing.T) {
 		testPrefix := path.Join(testMinIOKVRoot, "prefix")
 		ctx, cancel := context.WithCancel(context.Background())
@@ -0,0 +1,275 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ddlnotify emits the successful ddl operations to a notification topic or webhook, so that the external
+// services could invalidate their caches of the collections. The events are emitted by the coordinators executing
+// the ddl operations, the rootcoord for the collections and aliases and the datacoord for the indexes, so each of
+// them is emitted once whichever proxy the request went through.
+package ddlnotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/pkg/log"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper"
+	kafkawrapper "github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper/kafka"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	TypeProperty = "ddl_type"
+
+	webhookTimeout = 10 * time.Second
+)
+
+// Event is a successful ddl operation emitted as json.
+type Event struct {
+	Type           string            `json:"type"`
+	Timestamp      uint64            `json:"timestamp"`
+	NodeID         int64             `json:"node_id"`
+	Role           string            `json:"role"`
+	DbName         string            `json:"db_name,omitempty"`
+	CollectionID   int64             `json:"collection_id,omitempty"`
+	CollectionName string            `json:"collection_name,omitempty"`
+	FieldName      string            `json:"field_name,omitempty"`
+	IndexName      string            `json:"index_name,omitempty"`
+	Alias          string            `json:"alias,omitempty"`
+	Properties     map[string]string `json:"properties,omitempty"`
+}
+
+// Notifier delivers the ddl events of a coordinator in the order of the timestamp. The events are persisted in the
+// meta kv until delivered, and an event is retried until delivered to all the targets, so the delivery is at least
+// once, the consumers should dedup the events by the timestamp. The events of the rootcoord and the datacoord are
+// delivered independently, the consumers should order them by the timestamp if needed.
+type Notifier struct {
+	mu      sync.Mutex
+	kv      kv.BaseKV
+	prefix  string
+	pending []*Event
+	notifyC chan struct{}
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	newClient func(ctx context.Context, address string) (mqwrapper.Client, error)
+	httpCli   *http.Client
+	client    mqwrapper.Client
+	producer  mqwrapper.Producer
+}
+
+// NewNotifier returns the notifier persisting the pending events under the prefix of the meta kv.
+func NewNotifier(metaKV kv.BaseKV, prefix string) *Notifier {
+	return &Notifier{
+		kv:        metaKV,
+		prefix:    prefix,
+		notifyC:   make(chan struct{}, 1),
+		newClient: newKafkaClient,
+		httpCli:   &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func newKafkaClient(ctx context.Context, address string) (mqwrapper.Client, error) {
+	if address == "" {
+		return kafkawrapper.NewKafkaClientInstanceWithConfig(ctx, &paramtable.Get().KafkaCfg)
+	}
+	return kafkawrapper.NewKafkaClientInstance(address), nil
+}
+
+func (n *Notifier) enabled() bool {
+	return paramtable.Get().CommonCfg.DDLNotificationEnabled.GetAsBool()
+}
+
+func (n *Notifier) key(event *Event) string {
+	// the zero padded timestamp keeps the pending events ordered by the key
+	return path.Join(n.prefix, fmt.Sprintf("%020d", event.Timestamp))
+}
+
+// Start loads the events left pending by the coordinator stopped before delivering them, and starts the delivery.
+func (n *Notifier) Start(ctx context.Context) error {
+	if n == nil || !n.enabled() {
+		return nil
+	}
+	_, values, err := n.kv.LoadWithPrefix(n.prefix)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	pending := lo.SliceToMap(n.pending, func(event *Event) (uint64, *Event) { return event.Timestamp, event })
+	for _, value := range values {
+		event := &Event{}
+		if err := json.Unmarshal([]byte(value), event); err != nil {
+			log.Warn("skip the invalid pending ddl event", zap.String("event", value), zap.Error(err))
+			continue
+		}
+		pending[event.Timestamp] = event
+	}
+	n.pending = lo.Values(pending)
+	sort.Slice(n.pending, func(i, j int) bool { return n.pending[i].Timestamp < n.pending[j].Timestamp })
+	n.mu.Unlock()
+
+	ctx, n.cancel = context.WithCancel(ctx)
+	n.wg.Add(1)
+	go n.loop(ctx)
+	n.signal()
+	return nil
+}
+
+func (n *Notifier) signal() {
+	select {
+	case n.notifyC <- struct{}{}:
+	default:
+	}
+}
+
+// Notify persists and queues the event of a ddl operation executed successfully, the caller should notify the
+// events in the order of the timestamp.
+func (n *Notifier) Notify(event *Event) {
+	if n == nil || !n.enabled() {
+		return
+	}
+	event.NodeID = paramtable.GetNodeID()
+	event.Role = paramtable.GetRole()
+	bs, _ := json.Marshal(event)
+	if err := n.kv.Save(n.key(event), string(bs)); err != nil {
+		// still delivered unless the coordinator stops before that
+		log.Warn("failed to persist the ddl event", zap.String("type", event.Type), zap.Uint64("ts", event.Timestamp), zap.Error(err))
+	}
+	n.mu.Lock()
+	n.pending = append(n.pending, event)
+	n.mu.Unlock()
+	n.signal()
+}
+
+func (n *Notifier) head() *Event {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.pending) == 0 {
+		return nil
+	}
+	return n.pending[0]
+}
+
+func (n *Notifier) loop(ctx context.Context) {
+	defer n.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-n.notifyC:
+		}
+		for event := n.head(); event != nil; event = n.head() {
+			// the later events wait for the head to keep the order
+			if err := n.deliver(ctx, event); err != nil {
+				log.Warn("failed to deliver the ddl event, retry later", zap.String("type", event.Type), zap.Uint64("ts", event.Timestamp), zap.Error(err))
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(paramtable.Get().CommonCfg.DDLNotificationRetryInterval.GetAsDuration(time.Millisecond)):
+				}
+				continue
+			}
+			if err := n.kv.Remove(n.key(event)); err != nil {
+				log.Warn("failed to remove the delivered ddl event", zap.Uint64("ts", event.Timestamp), zap.Error(err))
+			}
+			n.mu.Lock()
+			n.pending = n.pending[1:]
+			n.mu.Unlock()
+		}
+	}
+}
+
+func (n *Notifier) deliver(ctx context.Context, event *Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if topic := paramtable.Get().CommonCfg.DDLNotificationTopic.GetValue(); topic != "" {
+		if err := n.produce(ctx, topic, event, payload); err != nil {
+			return err
+		}
+	}
+	if url := paramtable.Get().CommonCfg.DDLNotificationWebhookURL.GetValue(); url != "" {
+		if err := n.post(ctx, url, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *Notifier) produce(ctx context.Context, topic string, event *Event, payload []byte) error {
+	if n.producer == nil {
+		client, err := n.newClient(ctx, paramtable.Get().CommonCfg.DDLNotificationKafkaAddress.GetValue())
+		if err != nil {
+			return err
+		}
+		producer, err := client.CreateProducer(mqwrapper.ProducerOptions{Topic: topic})
+		if err != nil {
+			client.Close()
+			return err
+		}
+		n.client, n.producer = client, producer
+	}
+	_, err := n.producer.Send(ctx, &mqwrapper.ProducerMessage{
+		Payload:    payload,
+		Properties: map[string]string{TypeProperty: event.Type},
+	})
+	return err
+}
+
+func (n *Notifier) post(ctx context.Context, url string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.httpCli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Newf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close stops the delivery, the pending events are delivered by the next active coordinator.
+func (n *Notifier) Close() {
+	if n == nil {
+		return
+	}
+	if n.cancel != nil {
+		n.cancel()
+		n.wg.Wait()
+	}
+	if n.producer != nil {
+		n.producer.Close()
+		n.client.Close()
+		n.producer, n.client = nil, nil
+	}
+}
@@ -0,0 +1,175 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddlnotify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	memkv "github.com/milvus-io/milvus/internal/kv/mem"
+	"github.com/milvus-io/milvus/pkg/mq/msgstream/mqwrapper"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// testClient collects the produced messages.
+type testClient struct {
+	mqwrapper.Client
+	mqwrapper.Producer
+
+	mu       sync.Mutex
+	produced []*mqwrapper.ProducerMessage
+}
+
+func (c *testClient) CreateProducer(options mqwrapper.ProducerOptions) (mqwrapper.Producer, error) {
+	return c, nil
+}
+
+func (c *testClient) Send(ctx context.Context, message *mqwrapper.ProducerMessage) (mqwrapper.MessageID, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.produced = append(c.produced, message)
+	return nil, nil
+}
+
+func (c *testClient) getProduced() []*mqwrapper.ProducerMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.produced
+}
+
+func (c *testClient) Close() {}
+
+func TestNotifier(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.CommonCfg.DDLNotificationEnabled.Key, "true")
+	defer params.Reset(params.CommonCfg.DDLNotificationEnabled.Key)
+	params.Save(params.CommonCfg.DDLNotificationRetryInterval.Key, "10")
+	defer params.Reset(params.CommonCfg.DDLNotificationRetryInterval.Key)
+
+	newEvent := func(ts uint64, collectionName string) *Event {
+		return &Event{Type: "DropCollection", Timestamp: ts, CollectionName: collectionName}
+	}
+
+	t.Run("webhook and topic", func(t *testing.T) {
+		mu := sync.Mutex{}
+		received := make([]*Event, 0)
+		failed := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			// fails once to retry
+			if !failed {
+				failed = true
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			bs, _ := io.ReadAll(r.Body)
+			event := &Event{}
+			assert.NoError(t, json.Unmarshal(bs, event))
+			received = append(received, event)
+		}))
+		defer server.Close()
+		params.Save(params.CommonCfg.DDLNotificationWebhookURL.Key, server.URL)
+		defer params.Reset(params.CommonCfg.DDLNotificationWebhookURL.Key)
+		params.Save(params.CommonCfg.DDLNotificationTopic.Key, "ddl")
+		defer params.Reset(params.CommonCfg.DDLNotificationTopic.Key)
+
+		client := &testClient{}
+		n := NewNotifier(memkv.NewMemoryKV(), "rootcoord/ddl_notification")
+		n.newClient = func(ctx context.Context, address string) (mqwrapper.Client, error) {
+			return client, nil
+		}
+		assert.NoError(t, n.Start(context.Background()))
+		defer n.Close()
+
+		n.Notify(newEvent(100, "a"))
+		n.Notify(newEvent(101, "b"))
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(received) == 2
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, "a", received[0].CollectionName)
+		assert.Equal(t, "b", received[1].CollectionName)
+		assert.Equal(t, "DropCollection", received[1].Type)
+		assert.Equal(t, paramtable.GetRole(), received[1].Role)
+
+		// the head is produced again on the retry
+		produced := client.getProduced()
+		assert.Equal(t, 3, len(produced))
+		assert.Equal(t, "DropCollection", produced[2].Properties[TypeProperty])
+		assert.Eventually(t, func() bool {
+			_, values, err := n.kv.LoadWithPrefix(n.prefix)
+			return err == nil && len(values) == 0
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("resume pending", func(t *testing.T) {
+		mu := sync.Mutex{}
+		received := make([]uint64, 0)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			event := &Event{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(event))
+			received = append(received, event.Timestamp)
+		}))
+		defer server.Close()
+		params.Save(params.CommonCfg.DDLNotificationWebhookURL.Key, server.URL)
+		defer params.Reset(params.CommonCfg.DDLNotificationWebhookURL.Key)
+
+		n := NewNotifier(memkv.NewMemoryKV(), "rootcoord/ddl_notification")
+		for _, ts := range []uint64{300, 200} {
+			event := newEvent(ts, "c")
+			bs, _ := json.Marshal(event)
+			assert.NoError(t, n.kv.Save(n.key(event), string(bs)))
+		}
+		assert.NoError(t, n.Start(context.Background()))
+		defer n.Close()
+		n.Notify(newEvent(400, "c"))
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(received) == 3
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, []uint64{200, 300, 400}, received)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		params.Save(params.CommonCfg.DDLNotificationEnabled.Key, "false")
+		defer params.Save(params.CommonCfg.DDLNotificationEnabled.Key, "true")
+		n := NewNotifier(memkv.NewMemoryKV(), "rootcoord/ddl_notification")
+		assert.NoError(t, n.Start(context.Background()))
+		n.Notify(newEvent(100, "a"))
+		assert.Empty(t, n.pending)
+		n.Close()
+
+		// the notifier of the coordinator without the meta kv
+		var nilNotifier *Notifier
+		nilNotifier.Notify(newEvent(100, "a"))
+		nilNotifier.Close()
+	})
+}
@@ -22,6 +22,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/samber/lo"
+
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/pkg/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/util/merr"
@@ -34,6 +36,20 @@ const (
 	EndTs      = "end_ts"
 	EndTs2     = "endTs"
 	BackupFlag = "backup"
+
+	// WaitForIndexFlag makes the import job completed only after the indexes of the imported segments are built.
+	WaitForIndexFlag = "wait_for_index"
+	// AutoLoadFlag makes the collection or the partition loaded after the import job is completed,
+	// the job waits for the indexes built as well.
+	AutoLoadFlag = "auto_load"
+
+	// CreatePartitionFlag creates the partitions to import into if not exist.
+	CreatePartitionFlag = "create_partition"
+	// PartitionColumn is the name of the field, whose value is the name of the partition to import the row into.
+	PartitionColumn = "partition_column"
+	// PartitionNames are the comma separated names of the partitions to import into by the partition column,
+	// the rows with the value not in the partitions fail the import.
+	PartitionNames = "partition_names"
 )
 
 type Options []*commonpb.KeyValuePair
@@ -76,3 +92,49 @@ func IsBackup(options Options) bool {
 	}
 	return true
 }
+
+func isOptionEnabled(key string, options Options) bool {
+	value, err := funcutil.GetAttrByKeyFromRepeatedKV(key, options)
+	if err != nil {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	return err == nil && enabled
+}
+
+func IsWaitForIndex(options Options) bool {
+	return isOptionEnabled(WaitForIndexFlag, options) || IsAutoLoad(options)
+}
+
+func IsAutoLoad(options Options) bool {
+	return isOptionEnabled(AutoLoadFlag, options)
+}
+
+func IsCreatePartition(options Options) bool {
+	return isOptionEnabled(CreatePartitionFlag, options)
+}
+
+// ParsePartitionColumn returns the partition column and the partitions to import into by the column,
+// the column is empty if the rows are not imported by the partition column.
+func ParsePartitionColumn(options Options) (string, []string, error) {
+	column, err := funcutil.GetAttrByKeyFromRepeatedKV(PartitionColumn, options)
+	if err != nil || strings.TrimSpace(column) == "" {
+		return "", nil, nil
+	}
+	value, _ := funcutil.GetAttrByKeyFromRepeatedKV(PartitionNames, options)
+	names := make([]string, 0)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if lo.Contains(names, name) {
+			return "", nil, merr.WrapErrImportFailed(fmt.Sprintf("duplicated partition %s in %s", name, PartitionNames))
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", nil, merr.WrapErrImportFailed(fmt.Sprintf("%s is required to import by %s", PartitionNames, PartitionColumn))
+	}
+	return strings.TrimSpace(column), names, nil
+}
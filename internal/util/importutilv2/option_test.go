@@ -0,0 +1,62 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importutilv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestOptionFlags(t *testing.T) {
+	assert.False(t, IsAutoLoad(nil))
+	assert.False(t, IsWaitForIndex(nil))
+	assert.False(t, IsCreatePartition(Options{{Key: CreatePartitionFlag, Value: "invalid"}}))
+	assert.True(t, IsCreatePartition(Options{{Key: CreatePartitionFlag, Value: "true"}}))
+
+	// auto load waits for index
+	options := Options{{Key: AutoLoadFlag, Value: "True"}}
+	assert.True(t, IsAutoLoad(options))
+	assert.True(t, IsWaitForIndex(options))
+	assert.True(t, IsWaitForIndex(Options{{Key: WaitForIndexFlag, Value: "true"}}))
+}
+
+func TestParsePartitionColumn(t *testing.T) {
+	column, names, err := ParsePartitionColumn(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, column)
+	assert.Empty(t, names)
+
+	column, names, err = ParsePartitionColumn(Options{
+		{Key: PartitionColumn, Value: "tenant"},
+		{Key: PartitionNames, Value: " a, b ,,c"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant", column)
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+
+	_, _, err = ParsePartitionColumn(Options{{Key: PartitionColumn, Value: "tenant"}})
+	assert.ErrorIs(t, err, merr.ErrImportFailed)
+
+	_, _, err = ParsePartitionColumn(Options{
+		{Key: PartitionColumn, Value: "tenant"},
+		{Key: PartitionNames, Value: "a,b,a"},
+	})
+	assert.ErrorIs(t, err, merr.ErrImportFailed)
+}
@@ -0,0 +1,242 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importutilv2
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+// The options to read the import files from the object storage specified in the request,
+// instead of the bucket of the cluster.
+const (
+	StorageCloudProvider   = "storage_cloud_provider"
+	StorageAddress         = "storage_address"
+	StorageBucket          = "storage_bucket"
+	StorageRegion          = "storage_region"
+	StorageUseSSL          = "storage_use_ssl"
+	StorageUseVirtualHost  = "storage_use_virtual_host"
+	StorageAccessKeyID     = "storage_access_key_id"
+	StorageSecretAccessKey = "storage_secret_access_key"
+	// StorageSessionToken is the token of the temporary credentials, e.g. the STS session token of s3,
+	// or the SAS token of azure.
+	StorageSessionToken = "storage_session_token"
+
+	storageOptionPrefix = "storage_"
+	// encryptedCredentialPrefix marks the credentials encrypted by the proxy, which are persisted in the import job.
+	encryptedCredentialPrefix = "enc:v1:"
+	// CloudProviderMinio is the s3 compatible storage other than the cloud providers.
+	CloudProviderMinio = "minio"
+)
+
+var (
+	storageOptionKeys = []string{
+		StorageCloudProvider, StorageAddress, StorageBucket, StorageRegion, StorageUseSSL, StorageUseVirtualHost,
+		StorageAccessKeyID, StorageSecretAccessKey, StorageSessionToken,
+	}
+	storageCredentialKeys = []string{StorageAccessKeyID, StorageSecretAccessKey, StorageSessionToken}
+	storageCloudProviders = []string{
+		CloudProviderMinio, storage.CloudProviderAWS, storage.CloudProviderGCP, storage.CloudProviderAzure,
+		storage.CloudProviderAliyun, storage.CloudProviderTencent,
+	}
+)
+
+// IsExternalStorage returns whether the import files are in the object storage specified in the options.
+func IsExternalStorage(options Options) bool {
+	return lo.ContainsBy(options, func(option *commonpb.KeyValuePair) bool {
+		return strings.HasPrefix(strings.ToLower(option.GetKey()), storageOptionPrefix)
+	})
+}
+
+// checkStorageAddress checks the address against dataCoord.import.externalStorageAllowedAddresses, so the import
+// requests can't make the cluster connect to the internal endpoints.
+func checkStorageAddress(address string) error {
+	if funcutil.IsAddressAllowed(address, paramtable.Get().DataCoordCfg.ImportExternalStorageAllowedAddresses.GetAsStrings()) {
+		return nil
+	}
+	return merr.WrapErrImportFailed(fmt.Sprintf("external storage address %s is not allowed", address))
+}
+
+func credentialCipher() (cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(paramtable.Get().DataCoordCfg.ImportExternalStorageCredentialKey.GetValue())
+	if err != nil || len(key) == 0 {
+		return nil, merr.WrapErrImportFailed("invalid or empty credential key to import from external storage")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("invalid credential key to import from external storage, err=%s", err))
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptStorageCredentials returns the options with the credentials of the external storage encrypted by
+// dataCoord.import.externalStorageCredentialKey, so they are never persisted in plaintext.
+func EncryptStorageCredentials(options Options) (Options, error) {
+	gcm, err := credentialCipher()
+	if err != nil {
+		return nil, err
+	}
+	encrypted := make(Options, 0, len(options))
+	for _, option := range options {
+		if !lo.Contains(storageCredentialKeys, strings.ToLower(option.GetKey())) ||
+			strings.HasPrefix(option.GetValue(), encryptedCredentialPrefix) {
+			encrypted = append(encrypted, option)
+			continue
+		}
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		sealed := gcm.Seal(nonce, nonce, []byte(option.GetValue()), nil)
+		encrypted = append(encrypted, &commonpb.KeyValuePair{
+			Key:   option.GetKey(),
+			Value: encryptedCredentialPrefix + base64.StdEncoding.EncodeToString(sealed),
+		})
+	}
+	return encrypted, nil
+}
+
+func decryptStorageCredential(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedCredentialPrefix) {
+		return value, nil
+	}
+	gcm, err := credentialCipher()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedCredentialPrefix))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", merr.WrapErrImportFailed("invalid encrypted credential of external storage")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", merr.WrapErrImportFailed(fmt.Sprintf("decrypt the credential of external storage failed, err=%s", err))
+	}
+	return string(plaintext), nil
+}
+
+// ParseExternalStorage validates the external storage options and returns the options of the chunk manager.
+// The credentials must be given by the request, the identity of the cluster is never used to access the external storage.
+// The credentials may be encrypted by EncryptStorageCredentials.
+func ParseExternalStorage(options Options) ([]storage.Option, error) {
+	kvs := make(map[string]string)
+	for _, option := range options {
+		key := strings.ToLower(option.GetKey())
+		if !strings.HasPrefix(key, storageOptionPrefix) {
+			continue
+		}
+		if !lo.Contains(storageOptionKeys, key) {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("unknown external storage option %s", option.GetKey()))
+		}
+		value, err := decryptStorageCredential(strings.TrimSpace(option.GetValue()))
+		if err != nil {
+			return nil, err
+		}
+		kvs[key] = value
+	}
+
+	cloudProvider := strings.ToLower(kvs[StorageCloudProvider])
+	if cloudProvider == "" {
+		cloudProvider = CloudProviderMinio
+	}
+	if !lo.Contains(storageCloudProviders, cloudProvider) {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("unsupported cloud provider %s of external storage, supported: %v",
+			kvs[StorageCloudProvider], storageCloudProviders))
+	}
+	for _, key := range []string{StorageAddress, StorageBucket, StorageAccessKeyID} {
+		if kvs[key] == "" {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("%s is required to import from external storage", key))
+		}
+	}
+	if kvs[StorageSecretAccessKey] == "" && kvs[StorageSessionToken] == "" {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("either %s or %s is required to import from external storage",
+			StorageSecretAccessKey, StorageSessionToken))
+	}
+	if err := checkStorageAddress(kvs[StorageAddress]); err != nil {
+		return nil, err
+	}
+	parseBool := func(key string, defaultValue bool) (bool, error) {
+		value, ok := kvs[key]
+		if !ok {
+			return defaultValue, nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, merr.WrapErrImportFailed(fmt.Sprintf("parse %s failed, value=%s, err=%s", key, value, err))
+		}
+		return b, nil
+	}
+	useSSL, err := parseBool(StorageUseSSL, true)
+	if err != nil {
+		return nil, err
+	}
+	useVirtualHost, err := parseBool(StorageUseVirtualHost, false)
+	if err != nil {
+		return nil, err
+	}
+	if cloudProvider == CloudProviderMinio {
+		cloudProvider = ""
+	}
+	return []storage.Option{
+		storage.CloudProvider(cloudProvider),
+		storage.Address(kvs[StorageAddress]),
+		storage.BucketName(kvs[StorageBucket]),
+		storage.Region(kvs[StorageRegion]),
+		storage.UseSSL(useSSL),
+		storage.UseVirtualHost(useVirtualHost),
+		storage.AccessKeyID(kvs[StorageAccessKeyID]),
+		storage.SecretAccessKeyID(kvs[StorageSecretAccessKey]),
+		storage.SessionToken(kvs[StorageSessionToken]),
+		storage.UseIAM(false),
+		storage.CreateBucket(false),
+	}, nil
+}
+
+// NewExternalChunkManager creates the chunk manager of the external storage specified in the options.
+func NewExternalChunkManager(ctx context.Context, options Options) (storage.ChunkManager, error) {
+	opts, err := ParseExternalStorage(options)
+	if err != nil {
+		return nil, err
+	}
+	cm, err := storage.NewChunkManagerFactory("remote", opts...).NewPersistentStorageChunkManager(ctx)
+	if err != nil {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("connect to external storage failed, err=%s", err))
+	}
+	return cm, nil
+}
+
+// RemoveStorageCredentials returns the options without the credentials of the external storage,
+// which are not kept once the import is done.
+func RemoveStorageCredentials(options Options) Options {
+	return lo.Filter(options, func(option *commonpb.KeyValuePair, _ int) bool {
+		return !lo.Contains(storageCredentialKeys, strings.ToLower(option.GetKey()))
+	})
+}
@@ -0,0 +1,124 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importutilv2
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestExternalStorage(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.DataCoordCfg.ImportExternalStorageAllowedAddresses.Key, "*.amazonaws.com,localhost:9000")
+	defer params.Reset(params.DataCoordCfg.ImportExternalStorageAllowedAddresses.Key)
+
+	newOptions := func(kvs map[string]string) Options {
+		return funcutil.Map2KeyValuePair(kvs)
+	}
+	valid := map[string]string{
+		StorageCloudProvider:   "aws",
+		StorageAddress:         "s3.us-west-2.amazonaws.com",
+		StorageBucket:          "bucket",
+		StorageRegion:          "us-west-2",
+		StorageAccessKeyID:     "ak",
+		StorageSecretAccessKey: "sk",
+		StorageSessionToken:    "token",
+		"timeout":              "300s",
+	}
+
+	assert.False(t, IsExternalStorage(newOptions(map[string]string{"timeout": "300s"})))
+	assert.True(t, IsExternalStorage(newOptions(valid)))
+
+	opts, err := ParseExternalStorage(newOptions(valid))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, opts)
+
+	// the case of keys is ignored
+	_, err = ParseExternalStorage(Options{
+		{Key: "STORAGE_ADDRESS", Value: "localhost:9000"},
+		{Key: "Storage_Bucket", Value: "bucket"},
+		{Key: StorageAccessKeyID, Value: "ak"},
+		{Key: StorageSecretAccessKey, Value: "sk"},
+	})
+	assert.NoError(t, err)
+
+	invalidCases := []map[string]string{
+		{StorageCloudProvider: "unknown"},
+		{StorageAddress: ""},
+		{StorageBucket: ""},
+		{StorageAccessKeyID: ""},
+		{StorageSecretAccessKey: "", StorageSessionToken: ""},
+		{StorageUseSSL: "yes please"},
+		{StorageUseVirtualHost: "maybe"},
+		{"storage_use_iam": "true"},
+		{StorageAddress: "169.254.169.254"},
+		{StorageAddress: "localhost:9001"},
+		{StorageAddress: "s3.amazonaws.com.evil.io"},
+		{StorageSecretAccessKey: encryptedCredentialPrefix + "invalid"},
+	}
+	for _, invalid := range invalidCases {
+		kvs := make(map[string]string)
+		for k, v := range valid {
+			kvs[k] = v
+		}
+		for k, v := range invalid {
+			kvs[k] = v
+		}
+		_, err = ParseExternalStorage(newOptions(kvs))
+		assert.ErrorIs(t, err, merr.ErrImportFailed, invalid)
+	}
+
+	_, err = NewExternalChunkManager(context.Background(), newOptions(map[string]string{StorageBucket: "bucket"}))
+	assert.ErrorIs(t, err, merr.ErrImportFailed)
+
+	// the credentials are encrypted by the key
+	_, err = EncryptStorageCredentials(newOptions(valid))
+	assert.ErrorIs(t, err, merr.ErrImportFailed)
+	params.Save(params.DataCoordCfg.ImportExternalStorageCredentialKey.Key, base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	defer params.Reset(params.DataCoordCfg.ImportExternalStorageCredentialKey.Key)
+	encrypted, err := EncryptStorageCredentials(newOptions(valid))
+	assert.NoError(t, err)
+	assert.Len(t, encrypted, len(valid))
+	for _, option := range encrypted {
+		if lo.Contains(storageCredentialKeys, option.GetKey()) {
+			assert.True(t, strings.HasPrefix(option.GetValue(), encryptedCredentialPrefix))
+			value, err := decryptStorageCredential(option.GetValue())
+			assert.NoError(t, err)
+			assert.Equal(t, valid[option.GetKey()], value)
+		} else {
+			assert.Equal(t, valid[option.GetKey()], option.GetValue())
+		}
+	}
+	_, err = ParseExternalStorage(encrypted)
+	assert.NoError(t, err)
+
+	removed := RemoveStorageCredentials(newOptions(valid))
+	assert.Len(t, removed, len(valid)-3)
+	for _, option := range removed {
+		assert.NotContains(t, []string{StorageAccessKeyID, StorageSecretAccessKey, StorageSessionToken}, option.GetKey())
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * # Licensed to the LF AI & Data foundation under one
+ * # or more contributor license agreements. See the NOTICE file
+ * # distributed with this work for additional information
+ * # regarding copyright ownership. The ASF licenses this file
+ * # to you under the Apache License, Version 2.0 (the
+ * # "License"); you may not use this file except in compliance
+ * # with the License. You may obtain a copy of the License at
+ * #
+ * #     http://www.apache.org/licenses/LICENSE-2.0
+ * #
+ * # Unless required by applicable law or agreed to in writing, software
+ * # distributed under the License is distributed on an "AS IS" BASIS,
+ * # WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * # See the License for the specific language governing permissions and
+ * # limitations under the License.
+ */
+
+package metrics
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+)
+
+type mockMetricsService struct {
+	collectorpb.UnimplementedMetricsServiceServer
+	requests chan *collectorpb.ExportMetricsServiceRequest
+}
+
+func (s *mockMetricsService) Export(ctx context.Context, req *collectorpb.ExportMetricsServiceRequest) (*collectorpb.ExportMetricsServiceResponse, error) {
+	s.requests <- req
+	return &collectorpb.ExportMetricsServiceResponse{}, nil
+}
+
+func newTestRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_counter", Help: "counter"}, []string{"node_id"})
+	counter.WithLabelValues("1").Add(3)
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge", Help: "gauge"})
+	gauge.Set(5)
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_histogram", Help: "histogram", Buckets: []float64{1, 10}})
+	histogram.Observe(0.5)
+	histogram.Observe(5)
+	histogram.Observe(50)
+	summary := prometheus.NewSummary(prometheus.SummaryOpts{Name: "test_summary", Help: "summary", Objectives: map[float64]float64{0.5: 0.05}})
+	summary.Observe(2)
+	registry.MustRegister(counter, gauge, histogram, summary)
+	return registry
+}
+
+func TestConvertMetricFamilies(t *testing.T) {
+	families, err := newTestRegistry().Gather()
+	assert.NoError(t, err)
+	now := time.Now()
+	metrics := convertMetricFamilies(families, now.Add(-time.Minute), now)
+	assert.Len(t, metrics, 4)
+
+	byName := make(map[string]*metricspb.Metric)
+	for _, metric := range metrics {
+		byName[metric.GetName()] = metric
+	}
+	sum := byName["test_counter"].GetSum()
+	assert.True(t, sum.GetIsMonotonic())
+	assert.Equal(t, 3.0, sum.GetDataPoints()[0].GetAsDouble())
+	assert.Equal(t, "node_id", sum.GetDataPoints()[0].GetAttributes()[0].GetKey())
+	assert.Equal(t, 5.0, byName["test_gauge"].GetGauge().GetDataPoints()[0].GetAsDouble())
+
+	point := byName["test_histogram"].GetHistogram().GetDataPoints()[0]
+	assert.Equal(t, uint64(3), point.GetCount())
+	assert.Equal(t, 55.5, point.GetSum())
+	assert.Equal(t, []float64{1, 10}, point.GetExplicitBounds())
+	assert.Equal(t, []uint64{1, 1, 1}, point.GetBucketCounts())
+
+	summary := byName["test_summary"].GetSummary().GetDataPoints()[0]
+	assert.Equal(t, uint64(1), summary.GetCount())
+	assert.Len(t, summary.GetQuantileValues(), 1)
+}
+
+func TestOTLPExporter(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	service := &mockMetricsService{requests: make(chan *collectorpb.ExportMetricsServiceRequest, 10)}
+	server := grpc.NewServer()
+	collectorpb.RegisterMetricsServiceServer(server, service)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	exporter, err := NewOTLPExporter(newTestRegistry(), lis.Addr().String(), false, 10*time.Millisecond, func() map[string]string {
+		return map[string]string{"role": "proxy", "node_id": "1"}
+	})
+	assert.NoError(t, err)
+	exporter.Start()
+	defer exporter.Stop()
+
+	select {
+	case req := <-service.requests:
+		resource := req.GetResourceMetrics()[0]
+		assert.Len(t, resource.GetResource().GetAttributes(), 2)
+		assert.Len(t, resource.GetScopeMetrics()[0].GetMetrics(), 4)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no metrics exported")
+	}
+}
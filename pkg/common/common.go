@@ -18,6 +18,7 @@ package common
 
 import (
 	"encoding/binary"
+	"strconv"
 	"strings"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
@@ -112,6 +113,14 @@ const (
 	DropRatioBuildKey = "drop_ratio_build"
 )
 
+// Field encryption keys in type params
+const (
+	// FieldEncryptionKeyIDKey is the id of master key in KMS to envelope-encrypt the field, the field is not encrypted if absent.
+	FieldEncryptionKeyIDKey = "encryption.key_id"
+	// FieldEncryptionDecryptRolesKey is the roles allowed to read the plaintext of the field split by comma, all users if empty.
+	FieldEncryptionDecryptRolesKey = "encryption.decrypt_roles"
+)
+
 //  Collection properties key
 
 const (
@@ -134,12 +143,90 @@ const (
 	CollectionDiskQuotaKey       = "collection.diskProtection.diskQuota.mb"
 
 	PartitionDiskQuotaKey = "partition.diskProtection.diskQuota.mb"
+
+	// CollectionReadOnlyKey rejects all the DML requests of the collection while queries continue
+	CollectionReadOnlyKey = "read_only"
+
+	// CollectionSchemaRegistryRefKey references the schema document in schema registry, by url or id
+	CollectionSchemaRegistryRefKey = "schema_registry.ref"
+	// CollectionSchemaRegistryVersionKey records the version of the fetched schema document
+	CollectionSchemaRegistryVersionKey = "schema_registry.version"
+
+	// CollectionSearchPresetKeyPrefix is the prefix of the named search parameter presets,
+	// e.g. "search.preset.fast" = `{"params": {"ef": 32}, "consistency_level": "Eventually"}`
+	CollectionSearchPresetKeyPrefix = "search.preset."
+
+	// CollectionMirrorTargetKey is the collection to duplicate the sampled requests of the collection to
+	CollectionMirrorTargetKey = "mirror.target"
+	// CollectionMirrorSampleRatioKey is the ratio in [0, 1] of the requests to mirror, 1 by default
+	CollectionMirrorSampleRatioKey = "mirror.sample_ratio"
+	// CollectionMirrorWritesKey mirrors the insert, upsert and delete requests as well if true
+	CollectionMirrorWritesKey = "mirror.writes"
+
+	// CollectionCanaryTargetKey is the candidate collection to route a part of the search and query requests
+	// through the aliases of the collection to
+	CollectionCanaryTargetKey = "canary.target"
+	// CollectionCanaryPercentageKey is the percentage in [0, 100] of the clients routed to the candidate collection
+	CollectionCanaryPercentageKey = "canary.percentage"
+	// CollectionCanaryRecallSampleRatioKey is the ratio in [0, 1] of the canary searches to compute the recall against
+	// the stable collection, 0 by default
+	CollectionCanaryRecallSampleRatioKey = "canary.recall_sample_ratio"
+
+	// CollectionDefaultOutputFieldsKey is the comma separated output fields of the searches and queries of the
+	// collection requesting no output field
+	CollectionDefaultOutputFieldsKey = "default_output_fields"
+
+	// CollectionCompositePrimaryKeyKey is the comma separated scalar fields composing the varchar primary key, which is
+	// filled by the proxy on inserting
+	CollectionCompositePrimaryKeyKey = "composite_primary_key"
+
+	// CollectionAutoIDStrategyKey is the strategy to generate the auto ids, one of allocator, uuidv7 for the varchar
+	// primary key and snowflake for the int64 primary key, allocator by default
+	CollectionAutoIDStrategyKey = "auto_id.strategy"
+	// CollectionAutoIDSnowflakeEpochKey is the epoch in unix milliseconds of the snowflake auto ids
+	CollectionAutoIDSnowflakeEpochKey = "auto_id.snowflake_epoch"
 )
 
 // common properties
 const (
 	MmapEnabledKey    = "mmap.enabled"
 	LazyLoadEnableKey = "lazyload.enabled"
+
+	// NormalizeKey L2-normalizes the float vectors on insert, set in collection properties for all the float
+	// vector fields or in field type params, the one of field takes precedence.
+	NormalizeKey = "normalize"
+)
+
+// Msg base properties
+const (
+	// SchemaVersionKey is the version of the collection schema the proxy plans the request with,
+	// set in the msg base properties of the search, query and insert requests.
+	SchemaVersionKey = "schema_version"
+
+	// ExcludedSegmentsKey is the comma separated ids of the sealed segments the shard delegators skip in the search,
+	// set in the msg base properties of the search requests.
+	ExcludedSegmentsKey = "excluded_segments"
+
+	// LoadPriorityKey is the priority of loading the collection, set in the msg base properties of the
+	// LoadCollection requests. The segments of the collections loaded in the background priority are loaded after
+	// the ones of the others.
+	LoadPriorityKey        = "load_priority"
+	LoadPriorityBackground = "background"
+)
+
+// Result status extra info
+const (
+	// TSafeWaitedChannelsKey is the comma separated vchannels whose shard delegator waited the tsafe to reach the
+	// guarantee timestamp, set in the extra info of the search result status of the query node.
+	TSafeWaitedChannelsKey = "tsafe_waited_channels"
+
+	// ShardCoverageKey is the json of the percentages of the sealed segments served by the partially loaded shards by
+	// vchannel, set in the extra info of the GetShardLeaders response status of the querycoord.
+	ShardCoverageKey = "shard_coverage"
+
+	// ReplicaHealthKey is the json of the health of the shard leaders by replica id, set in the extra info of the
+	// GetReplicas response status of the querycoord when the shard nodes are requested.
+	ReplicaHealthKey = "replica_health"
 )
 
 const (
@@ -201,6 +288,27 @@ func IsCollectionLazyLoadEnabled(kvs ...*commonpb.KeyValuePair) bool {
 	return false
 }
 
+// IsNormalizeEnabled returns whether the normalize is enabled and whether the normalize key is set.
+func IsNormalizeEnabled(kvs ...*commonpb.KeyValuePair) (bool, bool) {
+	for _, kv := range kvs {
+		if kv.Key == NormalizeKey {
+			enabled, err := strconv.ParseBool(kv.Value)
+			return err == nil && enabled, true
+		}
+	}
+	return false, false
+}
+
+func IsCollectionReadOnly(kvs ...*commonpb.KeyValuePair) bool {
+	for _, kv := range kvs {
+		if kv.Key == CollectionReadOnlyKey {
+			readOnly, err := strconv.ParseBool(kv.Value)
+			return err == nil && readOnly
+		}
+	}
+	return false
+}
+
 const (
 	// LatestVerision is the magic number for watch latest revision
 	LatestRevision = int64(-1)
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 )
 
 func TestIsSystemField(t *testing.T) {
@@ -38,3 +40,31 @@ func TestIsSystemField(t *testing.T) {
 		})
 	}
 }
+
+func TestIsCollectionReadOnly(t *testing.T) {
+	assert.False(t, IsCollectionReadOnly())
+	assert.False(t, IsCollectionReadOnly(&commonpb.KeyValuePair{Key: CollectionReadOnlyKey, Value: "false"}))
+	assert.False(t, IsCollectionReadOnly(&commonpb.KeyValuePair{Key: MmapEnabledKey, Value: "true"}))
+	assert.True(t, IsCollectionReadOnly(&commonpb.KeyValuePair{Key: CollectionReadOnlyKey, Value: "True"}))
+	assert.True(t, IsCollectionReadOnly(&commonpb.KeyValuePair{Key: CollectionReadOnlyKey, Value: "1"}))
+	assert.True(t, IsCollectionReadOnly(&commonpb.KeyValuePair{Key: CollectionReadOnlyKey, Value: "t"}))
+	assert.False(t, IsCollectionReadOnly(&commonpb.KeyValuePair{Key: CollectionReadOnlyKey, Value: "yes"}))
+}
+
+func TestIsNormalizeEnabled(t *testing.T) {
+	enabled, ok := IsNormalizeEnabled()
+	assert.False(t, enabled)
+	assert.False(t, ok)
+	enabled, ok = IsNormalizeEnabled(&commonpb.KeyValuePair{Key: NormalizeKey, Value: "false"})
+	assert.False(t, enabled)
+	assert.True(t, ok)
+	enabled, ok = IsNormalizeEnabled(&commonpb.KeyValuePair{Key: NormalizeKey, Value: "True"})
+	assert.True(t, enabled)
+	assert.True(t, ok)
+	enabled, ok = IsNormalizeEnabled(&commonpb.KeyValuePair{Key: NormalizeKey, Value: "1"})
+	assert.True(t, enabled)
+	assert.True(t, ok)
+	enabled, ok = IsNormalizeEnabled(&commonpb.KeyValuePair{Key: NormalizeKey, Value: "invalid"})
+	assert.False(t, enabled)
+	assert.True(t, ok)
+}
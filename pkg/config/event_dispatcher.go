@@ -42,16 +42,12 @@ func (ed *EventDispatcher) Get(key string) []EventHandler {
 func (ed *EventDispatcher) Dispatch(event *Event) {
 	ed.mut.RLock()
 	defer ed.mut.RUnlock()
-	var hs []EventHandler
 	realKey := formatKey(event.Key)
-	hs, ok := ed.registry[realKey]
-	if !ok {
-		for _, v := range ed.keyPrefix {
-			if strings.HasPrefix(realKey, v) {
-				if _, exist := ed.registry[v]; exist {
-					hs = append(hs, ed.registry[v]...)
-				}
-			}
+	hs := append([]EventHandler{}, ed.registry[realKey]...)
+	// the prefix handlers watch the keys registered by others too
+	for _, v := range ed.keyPrefix {
+		if v != realKey && strings.HasPrefix(realKey, v) {
+			hs = append(hs, ed.registry[v]...)
 		}
 	}
 	for _, h := range hs {
@@ -134,6 +134,22 @@ func (s *EventDispatcherSuite) TestDispatch() {
 
 		s.True(called.Load())
 	})
+
+	s.Run("dispatch_prefix_event_of_registered_key", func() {
+		keyCalled := atomic.NewInt32(0)
+		prefixCalled := atomic.NewInt32(0)
+
+		dispatcher.Register("cc", NewHandler("handler_1", func(*Event) { keyCalled.Inc() }))
+		dispatcher.RegisterForKeyPrefix("c", NewHandler("handler_2", func(*Event) { prefixCalled.Inc() }))
+
+		dispatcher.Dispatch(newEvent("test", "test", "cc", "b"))
+		s.EqualValues(1, keyCalled.Load())
+		s.EqualValues(1, prefixCalled.Load())
+
+		dispatcher.Dispatch(newEvent("test", "test", "c", "b"))
+		s.EqualValues(1, keyCalled.Load())
+		s.EqualValues(2, prefixCalled.Load())
+	})
 }
 
 func TestEventDispatcher(t *testing.T) {
@@ -59,6 +59,13 @@ const (
 	Leader     = "OnLeader"
 	FromLeader = "FromLeader"
 
+	ProduceRetriedLabel    = "retried"
+	ProduceAbandonedLabel  = "abandoned"
+	ProduceDeadLetterLabel = "dead_letter"
+
+	CanaryStableLabel    = "stable"
+	CanaryCandidateLabel = "candidate"
+
 	HookBefore = "before"
 	HookAfter  = "after"
 	HookMock   = "mock"
@@ -82,6 +89,7 @@ const (
 	partitionIDLabelName     = "partition_id"
 	channelNameLabelName     = "channel_name"
 	functionLabelName        = "function_name"
+	failureReasonLabelName   = "reason"
 	queryTypeLabelName       = "query_type"
 	collectionName           = "collection_name"
 	databaseLabelName        = "db_name"
@@ -105,6 +113,10 @@ const (
 	lockType                 = "lock_type"
 	lockOp                   = "lock_op"
 	loadTypeName             = "load_type"
+	usageTypeLabelName       = "usage_type"
+	shardLabelName           = "shard"
+	aliasLabelName           = "alias"
+	routeTargetLabelName     = "route_target"
 
 	// entities label
 	LoadedLabel         = "loaded"
@@ -226,6 +226,275 @@ var (
 			Buckets:   buckets, // unit: ms
 		}, []string{nodeIDLabelName})
 
+	// ProxyTimestampBatchSize records the number of timestamp allocations merged into one request.
+	ProxyTimestampBatchSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "timestamp_batch_size",
+			Help:      "number of timestamp allocations merged into one request",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 11), // 1 ~ 1024
+		}, []string{nodeIDLabelName})
+
+	// ProxyTimestampBatchWaitLatency records the latency from a timestamp allocation is submitted to it's done.
+	ProxyTimestampBatchWaitLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "timestamp_batch_wait_latency",
+			Help:      "latency from a batched timestamp allocation is submitted to it's done",
+			Buckets:   buckets, // unit: ms
+		}, []string{nodeIDLabelName})
+
+	// ProxyTimeTickLag records the lag between the latest timestamp and the min time tick of the channels in each shard.
+	ProxyTimeTickLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "time_tick_lag",
+			Help:      "lag in milliseconds between the latest timestamp and the min time tick of the channels in the shard",
+		}, []string{nodeIDLabelName, shardLabelName})
+
+	// ProxyPChannelTimeTickLag records the lag between the latest timestamp and the last synced time tick of each pchannel.
+	ProxyPChannelTimeTickLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "pchannel_time_tick_lag",
+			Help:      "lag in milliseconds between the latest timestamp and the last synced time tick of the pchannel",
+		}, []string{nodeIDLabelName, channelNameLabelName})
+
+	// ProxyRerankExternalLatency records the latency of calling the external scorer to rerank the search results.
+	ProxyRerankExternalLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "rerank_external_latency",
+			Help:      "latency of calling the external scorer to rerank the search results",
+			Buckets:   buckets, // unit: ms
+		}, []string{nodeIDLabelName})
+
+	// ProxyRerankExternalCallCount records the number of calls to the external scorer by status.
+	ProxyRerankExternalCallCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "rerank_external_call_count",
+			Help:      "count of calls to the external scorer to rerank the search results",
+		}, []string{nodeIDLabelName, statusLabelName})
+
+	// ProxyMirrorRequestCount records the number of the requests mirrored to the mirror target collection by status.
+	ProxyMirrorRequestCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "mirror_request_count",
+			Help:      "count of requests mirrored to the mirror target collection",
+		}, []string{nodeIDLabelName, msgTypeLabelName, collectionName, statusLabelName})
+
+	// ProxyMirrorResultOverlap records the ratio of the primary keys in both results of the request and the mirrored one.
+	ProxyMirrorResultOverlap = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "mirror_result_overlap",
+			Help:      "ratio of the primary keys in both results of the request and the mirrored one",
+			Buckets:   prometheus.LinearBuckets(0, 0.1, 11),
+		}, []string{nodeIDLabelName, msgTypeLabelName, collectionName})
+
+	// ProxyCanaryRequestLatency records the latency of the requests routed by the alias with canary, by route target.
+	ProxyCanaryRequestLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "canary_request_latency",
+			Help:      "latency of the requests routed by the alias with canary",
+			Buckets:   buckets, // unit: ms
+		}, []string{nodeIDLabelName, msgTypeLabelName, aliasLabelName, routeTargetLabelName, statusLabelName})
+
+	// ProxyCanarySearchRecall records the recall of the search results of candidate collection against the stable one.
+	ProxyCanarySearchRecall = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "canary_search_recall",
+			Help:      "recall of the search results of candidate collection against the stable one",
+			Buckets:   prometheus.LinearBuckets(0, 0.1, 11),
+		}, []string{nodeIDLabelName, aliasLabelName})
+
+	// ProxyCircuitBreakerState records the state of the circuit breaker of the collection, 0 closed, 1 open and 2 half open.
+	ProxyCircuitBreakerState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "circuit_breaker_state",
+			Help:      "state of the circuit breaker of the collection, 0 closed, 1 open and 2 half open",
+		}, []string{nodeIDLabelName, databaseLabelName, collectionName})
+
+	// ProxyCircuitBreakerRejectCount records the number of the requests failed fast by the open circuit breaker.
+	ProxyCircuitBreakerRejectCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "circuit_breaker_reject_count",
+			Help:      "count of requests failed fast by the open circuit breaker",
+		}, []string{nodeIDLabelName, msgTypeLabelName, databaseLabelName, collectionName})
+
+	// ProxyProduceFailureCount records the number of the dml messages failed to produce by the action taken,
+	// retried, abandoned after all the attempts or produced to the dead letter channel.
+	ProxyProduceFailureCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "produce_failure_count",
+			Help:      "count of dml messages failed to produce by the action taken",
+		}, []string{nodeIDLabelName, msgTypeLabelName, statusLabelName})
+
+	// ProxyIndexCoverage records the fraction of the sealed rows covered by a built index, by collection.
+	ProxyIndexCoverage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "index_coverage",
+			Help:      "fraction of sealed rows covered by a built index",
+		}, []string{nodeIDLabelName, databaseLabelName, collectionName})
+
+	// ProxyTaskQueueWaitLatency records the time the tasks wait in the scheduler queue before being scheduled.
+	ProxyTaskQueueWaitLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "task_queue_wait_latency",
+			Help:      "latency of tasks waiting in the scheduler queue",
+			Buckets:   buckets, // unit: ms
+		}, []string{nodeIDLabelName, taskTypeLabel})
+
+	// ProxySearchSplitCount records the searches split into the sub searches by the nq or the size of the vectors.
+	ProxySearchSplitCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "search_split_count",
+			Help:      "count of searches split into sub searches",
+		}, []string{nodeIDLabelName})
+
+	// ProxySearchSplitSubRequestCount records the sub searches of the split searches.
+	ProxySearchSplitSubRequestCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "search_split_sub_request_count",
+			Help:      "count of sub searches of the split searches",
+		}, []string{nodeIDLabelName})
+
+	// ProxyDistributedRateLimitFallbackCount records the fallbacks to the local limiters on the failures of the
+	// shared token buckets.
+	ProxyDistributedRateLimitFallbackCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "distributed_rate_limit_fallback_count",
+			Help:      "count of fallbacks to the local rate limiters on the failures of the shared token buckets",
+		}, []string{nodeIDLabelName})
+
+	// ProxySessionAffinityCount records the requests of the clients keeping the session on a proxy, hit if the
+	// request is on the proxy of the session.
+	ProxySessionAffinityCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "session_affinity_count",
+			Help:      "count of requests with the session affinity hint, hit or miss",
+		}, []string{nodeIDLabelName, statusLabelName})
+
+	// ProxyMutationBatchRows records the rows per insert and upsert request, for the capacity planning and finding the
+	// clients sending tiny batches at high rates.
+	ProxyMutationBatchRows = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "mutation_batch_rows",
+			Help:      "number of rows per insert and upsert request",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 10), // 1 ~ 262144
+		}, []string{nodeIDLabelName, msgTypeLabelName, collectionName})
+
+	// ProxyMutationBatchBytes records the payload bytes per insert and upsert request.
+	ProxyMutationBatchBytes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "mutation_batch_bytes",
+			Help:      "payload bytes per insert and upsert request",
+			Buckets:   prometheus.ExponentialBuckets(1024, 4, 10), // 1KB ~ 256MB
+		}, []string{nodeIDLabelName, msgTypeLabelName, collectionName})
+
+	// ProxyMutationVectorDim records the dimensions of the vector fields of the insert and upsert requests, not
+	// labeled by the collection as the dimension is fixed by the schema.
+	ProxyMutationVectorDim = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "mutation_vector_dim",
+			Help:      "dimension of the vector fields of insert and upsert requests",
+			Buckets:   prometheus.ExponentialBuckets(2, 2, 15), // 2 ~ 32768
+		}, []string{nodeIDLabelName, msgTypeLabelName})
+
+	// ProxyQueryMemoryInUse records the approximate memory acquired by the in-flight searches and queries.
+	ProxyQueryMemoryInUse = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "query_memory_in_use",
+			Help:      "approximate bytes of memory used by the in-flight searches and queries",
+		}, []string{nodeIDLabelName})
+
+	// ProxyMemoryShedding records whether the proxy sheds the load under memory pressure.
+	ProxyMemoryShedding = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "memory_shedding",
+			Help:      "whether the proxy sheds the load under memory pressure, 1 if shedding",
+		}, []string{nodeIDLabelName})
+
+	// ProxyMemoryShedTaskCount records the queued tasks shed under memory pressure.
+	ProxyMemoryShedTaskCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "memory_shed_task_count",
+			Help:      "count of queued tasks shed under memory pressure",
+		}, []string{nodeIDLabelName})
+
+	// ProxyInsertSmoothingDelay records the delay of the inserts smoothed by the channel rates.
+	ProxyInsertSmoothingDelay = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "insert_smoothing_delay",
+			Help:      "delay in milliseconds of the inserts waiting for the rate of their dml channels",
+			Buckets:   buckets,
+		}, []string{nodeIDLabelName})
+
+	// ProxyInsertSmoothingRateFactor records the factor of the insert rate of the dml channels lowered by the lag.
+	ProxyInsertSmoothingRateFactor = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "insert_smoothing_rate_factor",
+			Help:      "factor of the insert rate of the dml channel, lowered while the data nodes lag behind",
+		}, []string{nodeIDLabelName, channelNameLabelName})
+
+	// ProxyPartitionPruneRatio records the ratio of the partitions pruned by the partition statistics before fan-out.
+	ProxyPartitionPruneRatio = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "partition_prune_ratio",
+			Help:      "ratio of the partitions pruned by the min and max of the clustering key in the partition statistics",
+			Buckets:   prometheus.LinearBuckets(0, 0.1, 11),
+		}, []string{nodeIDLabelName, queryTypeLabelName})
+
 	// ProxyFunctionCall records the number of times the function of the DDL operation was executed, like `CreateCollection`.
 	ProxyFunctionCall = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -235,6 +504,16 @@ var (
 			Help:      "count of operation executed",
 		}, []string{nodeIDLabelName, functionLabelName, statusLabelName, databaseLabelName, collectionName})
 
+	// ProxyFunctionFailureCount records the failed requests by the class of the failure reason, like `rate_limited`
+	// and `not_found`, the classes are a fixed set to bound the label cardinality.
+	ProxyFunctionFailureCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "req_failure_count",
+			Help:      "count of failed requests by the class of the failure reason",
+		}, []string{nodeIDLabelName, functionLabelName, failureReasonLabelName})
+
 	// ProxyReqLatency records the latency that for all requests, like "CreateCollection".
 	ProxyReqLatency = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -297,6 +576,25 @@ var (
 			Help:      "the rpc count of a user",
 		}, []string{usernameLabelName})
 
+	// ProxyAuthFailureCount records the authentication failures, the usernames are in the audit log only, since
+	// the failures could come with any username.
+	ProxyAuthFailureCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "auth_failure_count",
+			Help:      "the authentication failure count",
+		}, []string{nodeIDLabelName})
+
+	// ProxyAccountLockoutCount records the times accounts are locked out because of authentication failures.
+	ProxyAccountLockoutCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "account_lockout_count",
+			Help:      "the account lockout count",
+		}, []string{nodeIDLabelName})
+
 	// ProxyWorkLoadScore record the score that measured query node's workload.
 	ProxyWorkLoadScore = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -334,6 +632,15 @@ var (
 			Name:      "slow_query_count",
 			Help:      "count of slow query executed",
 		}, []string{nodeIDLabelName, msgTypeLabelName})
+
+	// ProxyTenantUsage records the usage of each database and user, for tenant accounting.
+	ProxyTenantUsage = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "tenant_usage",
+			Help:      "usage of each database and user",
+		}, []string{nodeIDLabelName, databaseLabelName, usernameLabelName, usageTypeLabelName})
 )
 
 // RegisterProxy registers Proxy metrics
@@ -366,8 +673,37 @@ func RegisterProxy(registry *prometheus.Registry) {
 	registry.MustRegister(ProxySyncTimeTickLag)
 	registry.MustRegister(ProxyApplyPrimaryKeyLatency)
 	registry.MustRegister(ProxyApplyTimestampLatency)
+	registry.MustRegister(ProxyTimestampBatchSize)
+	registry.MustRegister(ProxyTimestampBatchWaitLatency)
+	registry.MustRegister(ProxyTimeTickLag)
+	registry.MustRegister(ProxyPChannelTimeTickLag)
+	registry.MustRegister(ProxyRerankExternalLatency)
+	registry.MustRegister(ProxyRerankExternalCallCount)
+	registry.MustRegister(ProxyMirrorRequestCount)
+	registry.MustRegister(ProxyMirrorResultOverlap)
+	registry.MustRegister(ProxyCanaryRequestLatency)
+	registry.MustRegister(ProxyCanarySearchRecall)
+	registry.MustRegister(ProxyCircuitBreakerState)
+	registry.MustRegister(ProxyCircuitBreakerRejectCount)
+	registry.MustRegister(ProxyProduceFailureCount)
+	registry.MustRegister(ProxyIndexCoverage)
+	registry.MustRegister(ProxyTaskQueueWaitLatency)
+	registry.MustRegister(ProxySearchSplitCount)
+	registry.MustRegister(ProxySearchSplitSubRequestCount)
+	registry.MustRegister(ProxyDistributedRateLimitFallbackCount)
+	registry.MustRegister(ProxySessionAffinityCount)
+	registry.MustRegister(ProxyMutationBatchRows)
+	registry.MustRegister(ProxyMutationBatchBytes)
+	registry.MustRegister(ProxyMutationVectorDim)
+	registry.MustRegister(ProxyQueryMemoryInUse)
+	registry.MustRegister(ProxyMemoryShedding)
+	registry.MustRegister(ProxyMemoryShedTaskCount)
+	registry.MustRegister(ProxyInsertSmoothingDelay)
+	registry.MustRegister(ProxyInsertSmoothingRateFactor)
+	registry.MustRegister(ProxyPartitionPruneRatio)
 
 	registry.MustRegister(ProxyFunctionCall)
+	registry.MustRegister(ProxyFunctionFailureCount)
 	registry.MustRegister(ProxyReqLatency)
 
 	registry.MustRegister(ProxyReceiveBytes)
@@ -376,6 +712,8 @@ func RegisterProxy(registry *prometheus.Registry) {
 	registry.MustRegister(ProxyLimiterRate)
 	registry.MustRegister(ProxyHookFunc)
 	registry.MustRegister(UserRPCCounter)
+	registry.MustRegister(ProxyAuthFailureCount)
+	registry.MustRegister(ProxyAccountLockoutCount)
 
 	registry.MustRegister(ProxyWorkLoadScore)
 	registry.MustRegister(ProxyExecutingTotalNq)
@@ -383,6 +721,7 @@ func RegisterProxy(registry *prometheus.Registry) {
 
 	registry.MustRegister(ProxySlowQueryCount)
 	registry.MustRegister(ProxyReportValue)
+	registry.MustRegister(ProxyTenantUsage)
 }
 
 func CleanupProxyDBMetrics(nodeID int64, dbName string) {
@@ -414,6 +753,18 @@ func CleanupProxyDBMetrics(nodeID int64, dbName string) {
 		nodeIDLabelName:   strconv.FormatInt(nodeID, 10),
 		databaseLabelName: dbName,
 	})
+	ProxyTenantUsage.DeletePartialMatch(prometheus.Labels{
+		nodeIDLabelName:   strconv.FormatInt(nodeID, 10),
+		databaseLabelName: dbName,
+	})
+}
+
+// CleanupProxyLimiterRateMetrics removes the limiter rates of the source, e.g. the dropped collection.
+func CleanupProxyLimiterRateMetrics(nodeID int64, sourceID string) {
+	ProxyLimiterRate.DeletePartialMatch(prometheus.Labels{
+		nodeIDLabelName:       strconv.FormatInt(nodeID, 10),
+		collectionIDLabelName: sourceID,
+	})
 }
 
 func CleanupProxyCollectionMetrics(nodeID int64, collection string) {
@@ -441,6 +792,18 @@ func CleanupProxyCollectionMetrics(nodeID int64, collection string) {
 		nodeIDLabelName: strconv.FormatInt(nodeID, 10),
 		collectionName:  collection,
 	})
+	ProxyIndexCoverage.DeletePartialMatch(prometheus.Labels{
+		nodeIDLabelName: strconv.FormatInt(nodeID, 10),
+		collectionName:  collection,
+	})
+	ProxyMutationBatchRows.DeletePartialMatch(prometheus.Labels{
+		nodeIDLabelName: strconv.FormatInt(nodeID, 10),
+		collectionName:  collection,
+	})
+	ProxyMutationBatchBytes.DeletePartialMatch(prometheus.Labels{
+		nodeIDLabelName: strconv.FormatInt(nodeID, 10),
+		collectionName:  collection,
+	})
 
 	ProxyCollectionSQLatency.Delete(prometheus.Labels{
 		nodeIDLabelName:    strconv.FormatInt(nodeID, 10),
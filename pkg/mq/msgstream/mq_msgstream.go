@@ -323,12 +323,21 @@ func (ms *mqMsgStream) Produce(msgPack *MsgPack) error {
 			InjectCtx(spanCtx, msg.Properties)
 
 			ms.producerLock.RLock()
-			if _, err := ms.producers[channel].Send(spanCtx, msg); err != nil {
+			id, err := ms.producers[channel].Send(spanCtx, msg)
+			if err != nil {
 				ms.producerLock.RUnlock()
 				sp.RecordError(err)
 				return err
 			}
 			ms.producerLock.RUnlock()
+			// set the position the message is published at, for the producers to wait on
+			if id != nil {
+				v.Msgs[i].SetPosition(&MsgPosition{
+					ChannelName: channel,
+					MsgID:       id.Serialize(),
+					Timestamp:   v.Msgs[i].EndTs(),
+				})
+			}
 		}
 	}
 	return nil
@@ -426,6 +426,29 @@ func IsGrpcErr(err error, targets ...codes.Code) bool {
 	}
 }
 
+// IsAddressAllowed returns whether the address, host or host:port, matches any of the allowed addresses,
+// which are a host, a host:port or a domain suffix like *.example.com. Nothing is allowed if the list is empty.
+func IsAddressAllowed(address string, allowed []string) bool {
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+	address, host = strings.ToLower(address), strings.ToLower(host)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if strings.HasPrefix(pattern, "*.") && strings.HasSuffix(host, pattern[1:]) {
+			return true
+		}
+		if pattern == address || pattern == host {
+			return true
+		}
+	}
+	return false
+}
+
 func IsEmptyString(str string) bool {
 	return strings.TrimSpace(str) == ""
 }
@@ -467,6 +467,18 @@ func TestHandleTenantForEtcdKey(t *testing.T) {
 	assert.Equal(t, "a", HandleTenantForEtcdKey("a", "", ""))
 }
 
+func TestIsAddressAllowed(t *testing.T) {
+	allowed := []string{"minio", "s3.amazonaws.com:443", "*.example.com", " "}
+	assert.True(t, IsAddressAllowed("minio:9000", allowed))
+	assert.True(t, IsAddressAllowed("MINIO", allowed))
+	assert.True(t, IsAddressAllowed("s3.amazonaws.com:443", allowed))
+	assert.False(t, IsAddressAllowed("s3.amazonaws.com:80", allowed))
+	assert.True(t, IsAddressAllowed("kafka.example.com:9092", allowed))
+	assert.False(t, IsAddressAllowed("example.com", allowed))
+	assert.False(t, IsAddressAllowed("169.254.169.254", allowed))
+	assert.False(t, IsAddressAllowed("minio", nil))
+}
+
 func TestIsRevoke(t *testing.T) {
 	assert.Equal(t, true, IsRevoke(milvuspb.OperatePrivilegeType_Revoke))
 	assert.Equal(t, false, IsRevoke(milvuspb.OperatePrivilegeType_Grant))
@@ -0,0 +1,309 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexparamcheck
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// ParamType is the value type of the index or search param.
+type ParamType int
+
+const (
+	ParamTypeInt ParamType = iota
+	ParamTypeFloat
+	ParamTypeBool
+	ParamTypeString
+)
+
+func (t ParamType) String() string {
+	switch t {
+	case ParamTypeInt:
+		return "an integer"
+	case ParamTypeFloat:
+		return "a number"
+	case ParamTypeBool:
+		return "a boolean"
+	default:
+		return "a string"
+	}
+}
+
+// search params
+const (
+	NPROBE                = "nprobe"
+	MaxEmptyResultBuckets = "max_empty_result_buckets"
+	ReorderK              = "reorder_k"
+	EF                    = "ef"
+	SearchList            = "search_list"
+	SearchLevel           = "level"
+	Radius                = "radius"
+	RangeFilter           = "range_filter"
+	SparseDropRatioSearch = "drop_ratio_search"
+
+	CagraITopKSize     = "itopk_size"
+	CagraSearchWidth   = "search_width"
+	CagraMinIterations = "min_iterations"
+	CagraMaxIterations = "max_iterations"
+	CagraTeamSize      = "team_size"
+
+	ScaNNWithRawData = "with_raw_data"
+)
+
+// ParamSchema is the type and range of the index or search param.
+type ParamSchema struct {
+	Name string
+	Type ParamType
+	// Min and Max are the inclusive range of the int and float params, Max is exclusive if MaxExclusive is set.
+	Min          float64
+	Max          float64
+	MaxExclusive bool
+	// Options are the allowed values of the string params, any value is allowed if empty.
+	Options []string
+}
+
+func intParam(name string, min, max float64) *ParamSchema {
+	return &ParamSchema{Name: name, Type: ParamTypeInt, Min: min, Max: max}
+}
+
+func floatParam(name string, min, max float64) *ParamSchema {
+	return &ParamSchema{Name: name, Type: ParamTypeFloat, Min: min, Max: max}
+}
+
+func ratioParam(name string) *ParamSchema {
+	return &ParamSchema{Name: name, Type: ParamTypeFloat, Min: 0, Max: 1, MaxExclusive: true}
+}
+
+func boolParam(name string) *ParamSchema {
+	return &ParamSchema{Name: name, Type: ParamTypeBool}
+}
+
+func stringParam(name string, options ...string) *ParamSchema {
+	return &ParamSchema{Name: name, Type: ParamTypeString, Options: options}
+}
+
+func (s *ParamSchema) expected() string {
+	switch s.Type {
+	case ParamTypeInt, ParamTypeFloat:
+		if s.MaxExclusive {
+			return fmt.Sprintf("%s in range [%v, %v)", s.Type, s.Min, s.Max)
+		}
+		return fmt.Sprintf("%s in range [%v, %v]", s.Type, s.Min, s.Max)
+	case ParamTypeString:
+		if len(s.Options) > 0 {
+			return fmt.Sprintf("one of %v", s.Options)
+		}
+	}
+	return s.Type.String()
+}
+
+// parse converts the value of the json number, bool, string or the string in the index params to the param type.
+func (s *ParamSchema) parse(value any) (any, bool) {
+	str, isString := value.(string)
+	switch s.Type {
+	case ParamTypeInt, ParamTypeFloat:
+		var v float64
+		switch value := value.(type) {
+		case float64:
+			v = value
+		case int:
+			v = float64(value)
+		case int64:
+			v = float64(value)
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return nil, false
+			}
+			v = f
+		default:
+			return nil, false
+		}
+		if s.Type == ParamTypeInt && v != math.Trunc(v) {
+			return nil, false
+		}
+		return v, true
+	case ParamTypeBool:
+		if b, ok := value.(bool); ok {
+			return b, true
+		}
+		if !isString {
+			return nil, false
+		}
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, false
+		}
+		return b, true
+	default:
+		return str, isString
+	}
+}
+
+// Validate checks the type and range of the param value.
+func (s *ParamSchema) Validate(value any) error {
+	v, ok := s.parse(value)
+	if !ok {
+		return fmt.Errorf("%s should be %s, got %v", s.Name, s.expected(), value)
+	}
+	switch s.Type {
+	case ParamTypeInt, ParamTypeFloat:
+		f := v.(float64)
+		if f < s.Min || f > s.Max || (s.MaxExclusive && f == s.Max) {
+			return fmt.Errorf("%s should be %s, got %v", s.Name, s.expected(), value)
+		}
+	case ParamTypeString:
+		if len(s.Options) > 0 && !lo.Contains(s.Options, v.(string)) {
+			return fmt.Errorf("%s should be %s, got %v", s.Name, s.expected(), value)
+		}
+	}
+	return nil
+}
+
+var (
+	ivfBuildParams = []*ParamSchema{intParam(NLIST, MinNList, MaxNList)}
+	pqBuildParams  = []*ParamSchema{intParam(NLIST, MinNList, MaxNList), intParam(IVFM, 1, math.MaxInt32), intParam(NBITS, MinNBits, MaxNBits)}
+
+	ivfSearchParams = []*ParamSchema{intParam(NPROBE, MinNList, MaxNList), intParam(MaxEmptyResultBuckets, 1, MaxNList)}
+	sparseParams    = []*ParamSchema{ratioParam(SparseDropRatioBuild)}
+)
+
+// buildParamSchemas are the index params to build the index by index type.
+var buildParamSchemas = map[IndexType][]*ParamSchema{
+	IndexFaissIDMap:      {},
+	IndexFaissBinIDMap:   {},
+	IndexFaissIvfFlat:    ivfBuildParams,
+	IndexFaissIvfSQ8:     ivfBuildParams,
+	IndexFaissBinIvfFlat: ivfBuildParams,
+	IndexFaissIvfPQ:      pqBuildParams,
+	IndexScaNN:           append([]*ParamSchema{boolParam(ScaNNWithRawData)}, ivfBuildParams...),
+	IndexHNSW: {
+		intParam(HNSWM, HNSWMinM, HNSWMaxM),
+		intParam(EFConstruction, HNSWMinEfConstruction, HNSWMaxEfConstruction),
+	},
+	IndexDISKANN:        {},
+	IndexSparseInverted: sparseParams,
+	IndexSparseWand:     sparseParams,
+	IndexRaftIvfFlat:    append([]*ParamSchema{boolParam(RaftCacheDatasetOnDevice)}, ivfBuildParams...),
+	IndexRaftIvfPQ:      append([]*ParamSchema{boolParam(RaftCacheDatasetOnDevice)}, pqBuildParams...),
+	IndexRaftCagra: {
+		intParam(CagraInterDegree, 1, math.MaxInt32),
+		intParam(CagraGraphDegree, 1, math.MaxInt32),
+		stringParam(CagraBuildAlgo, CagraBuildAlgoTypes...),
+		boolParam(RaftCacheDatasetOnDevice),
+	},
+	IndexRaftBruteForce: {boolParam(RaftCacheDatasetOnDevice)},
+}
+
+// searchParamSchemas are the search params by index type, the range search params are allowed for all index types.
+var searchParamSchemas = map[IndexType][]*ParamSchema{
+	IndexFaissIDMap:      {},
+	IndexFaissBinIDMap:   {},
+	IndexFaissIvfFlat:    ivfSearchParams,
+	IndexFaissIvfSQ8:     ivfSearchParams,
+	IndexFaissIvfPQ:      ivfSearchParams,
+	IndexFaissBinIvfFlat: ivfSearchParams,
+	IndexScaNN:           append([]*ParamSchema{intParam(ReorderK, 1, math.MaxInt32)}, ivfSearchParams...),
+	IndexHNSW:            {intParam(EF, 1, math.MaxInt32)},
+	IndexDISKANN:         {intParam(SearchList, 1, math.MaxInt32)},
+	IndexSparseInverted:  {ratioParam(SparseDropRatioSearch)},
+	IndexSparseWand:      {ratioParam(SparseDropRatioSearch)},
+	IndexRaftIvfFlat:     ivfSearchParams,
+	IndexRaftIvfPQ:       ivfSearchParams,
+	IndexRaftCagra: {
+		intParam(CagraITopKSize, 1, math.MaxInt32),
+		intParam(CagraSearchWidth, 1, math.MaxInt32),
+		intParam(CagraMinIterations, 0, math.MaxInt32),
+		intParam(CagraMaxIterations, 0, math.MaxInt32),
+		intParam(CagraTeamSize, 0, 32),
+	},
+	IndexRaftBruteForce: {},
+	AutoIndex:           {intParam(SearchLevel, 1, 5)},
+}
+
+var rangeSearchParams = []*ParamSchema{
+	floatParam(Radius, -math.MaxFloat32, math.MaxFloat32),
+	floatParam(RangeFilter, -math.MaxFloat32, math.MaxFloat32),
+}
+
+// schemasByName returns the schemas of all the index types by param name, the params of the same name
+// share the same type and range across the index types, otherwise the one of the first index type in order is used.
+func schemasByName(registry map[IndexType][]*ParamSchema, extra ...*ParamSchema) map[string]*ParamSchema {
+	indexTypes := lo.Keys(registry)
+	sort.Strings(indexTypes)
+	ret := make(map[string]*ParamSchema)
+	for _, indexType := range indexTypes {
+		for _, schema := range registry[indexType] {
+			if _, ok := ret[schema.Name]; !ok {
+				ret[schema.Name] = schema
+			}
+		}
+	}
+	for _, schema := range extra {
+		ret[schema.Name] = schema
+	}
+	return ret
+}
+
+var allSearchParams = schemasByName(searchParamSchemas, rangeSearchParams...)
+
+func validateParams[V any](kind string, indexType IndexType, registry map[IndexType][]*ParamSchema, all map[string]*ParamSchema, params map[string]V, extra ...*ParamSchema) error {
+	schemas, known := registry[indexType]
+	allowed := all
+	if known {
+		allowed = make(map[string]*ParamSchema, len(schemas)+len(extra))
+		for _, schema := range schemas {
+			allowed[schema.Name] = schema
+		}
+		for _, schema := range extra {
+			allowed[schema.Name] = schema
+		}
+	}
+	for name, value := range params {
+		schema, ok := allowed[name]
+		if !ok {
+			// not a param of the index type, left to the index
+			continue
+		}
+		if err := schema.Validate(value); err != nil {
+			if known {
+				return fmt.Errorf("invalid %s param of index %s: %w", kind, indexType, err)
+			}
+			return fmt.Errorf("invalid %s param: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// ValidateBuildParams checks the types and ranges of the index params of the index type in the registry,
+// the other params and the index types not in the registry, e.g. the scalar indexes, are left to the index checker.
+func ValidateBuildParams(indexType IndexType, params map[string]string) error {
+	return validateParams("index", indexType, buildParamSchemas, nil, params)
+}
+
+// ValidateSearchParams checks the types and ranges of the search params of the index type in the registry,
+// the other params are ignored as the index does. The search params of all the index types are checked
+// if the index type is unknown, e.g. the proxy does not know the index of the anns field.
+func ValidateSearchParams(indexType IndexType, params map[string]any) error {
+	return validateParams("search", indexType, searchParamSchemas, allSearchParams, params, rangeSearchParams...)
+}
@@ -0,0 +1,105 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexparamcheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamSchema_Validate(t *testing.T) {
+	cases := []struct {
+		schema *ParamSchema
+		value  any
+		valid  bool
+	}{
+		{intParam(NPROBE, 1, 10), float64(5), true},
+		{intParam(NPROBE, 1, 10), "5", true},
+		{intParam(NPROBE, 1, 10), float64(1.5), false},
+		{intParam(NPROBE, 1, 10), float64(0), false},
+		{intParam(NPROBE, 1, 10), float64(11), false},
+		{intParam(NPROBE, 1, 10), "abc", false},
+		{intParam(NPROBE, 1, 10), true, false},
+		{ratioParam(SparseDropRatioSearch), float64(0.5), true},
+		{ratioParam(SparseDropRatioSearch), "0", true},
+		{ratioParam(SparseDropRatioSearch), float64(1), false},
+		{boolParam(ScaNNWithRawData), true, true},
+		{boolParam(ScaNNWithRawData), "false", true},
+		{boolParam(ScaNNWithRawData), "no", false},
+		{boolParam(ScaNNWithRawData), float64(1), false},
+		{stringParam(CagraBuildAlgo, CagraBuildAlgoTypes...), CargaBuildAlgoIVFPQ, true},
+		{stringParam(CagraBuildAlgo, CagraBuildAlgoTypes...), "unknown", false},
+		{stringParam(CagraBuildAlgo), "unknown", true},
+		{stringParam(CagraBuildAlgo), float64(1), false},
+	}
+	for _, c := range cases {
+		err := c.schema.Validate(c.value)
+		assert.Equal(t, c.valid, err == nil, "%s: %v", c.schema.Name, c.value)
+	}
+
+	err := intParam(NPROBE, 1, 10).Validate(float64(0))
+	assert.ErrorContains(t, err, "nprobe should be an integer in range [1, 10], got 0")
+	err = ratioParam(SparseDropRatioSearch).Validate(float64(1))
+	assert.ErrorContains(t, err, "drop_ratio_search should be a number in range [0, 1), got 1")
+}
+
+func TestValidateBuildParams(t *testing.T) {
+	assert.NoError(t, ValidateBuildParams(IndexHNSW, map[string]string{HNSWM: "16", EFConstruction: "200", Metric: "L2"}))
+	assert.ErrorContains(t, ValidateBuildParams(IndexHNSW, map[string]string{HNSWM: "4096"}), "invalid index param of index HNSW: M should be")
+	assert.Error(t, ValidateBuildParams(IndexHNSW, map[string]string{EFConstruction: "abc"}))
+	// the params of the other index types are left to the checker
+	assert.NoError(t, ValidateBuildParams(IndexHNSW, map[string]string{NLIST: "0"}))
+
+	assert.NoError(t, ValidateBuildParams(IndexFaissIvfPQ, map[string]string{NLIST: "128", IVFM: "8", NBITS: "8"}))
+	assert.Error(t, ValidateBuildParams(IndexFaissIvfPQ, map[string]string{NBITS: "32"}))
+	assert.Error(t, ValidateBuildParams(IndexSparseInverted, map[string]string{SparseDropRatioBuild: "1.5"}))
+	assert.Error(t, ValidateBuildParams(IndexRaftCagra, map[string]string{CagraBuildAlgo: "unknown"}))
+
+	// scalar indexes are not in the registry
+	assert.NoError(t, ValidateBuildParams(IndexINVERTED, map[string]string{NLIST: "0"}))
+}
+
+func TestValidateSearchParams(t *testing.T) {
+	assert.NoError(t, ValidateSearchParams(IndexFaissIvfFlat, map[string]any{NPROBE: float64(16), Radius: 0.5, RangeFilter: 0.1}))
+	assert.ErrorContains(t, ValidateSearchParams(IndexFaissIvfFlat, map[string]any{NPROBE: float64(0)}), "invalid search param of index IVF_FLAT: nprobe should be")
+	assert.Error(t, ValidateSearchParams(IndexFaissIvfFlat, map[string]any{Radius: "abc"}))
+	// ignored by the index
+	assert.NoError(t, ValidateSearchParams(IndexHNSW, map[string]any{NPROBE: float64(0)}))
+	assert.NoError(t, ValidateSearchParams(IndexHNSW, map[string]any{"unknown": "value"}))
+	assert.Error(t, ValidateSearchParams(IndexHNSW, map[string]any{EF: float64(-1)}))
+	assert.Error(t, ValidateSearchParams(IndexDISKANN, map[string]any{SearchList: "ten"}))
+	assert.Error(t, ValidateSearchParams(AutoIndex, map[string]any{SearchLevel: float64(6)}))
+
+	// all the search params are checked if the index type is unknown
+	assert.NoError(t, ValidateSearchParams("", map[string]any{NPROBE: float64(10), EF: float64(64), "unknown": "value"}))
+	assert.ErrorContains(t, ValidateSearchParams("", map[string]any{NPROBE: float64(0)}), "invalid search param: nprobe should be")
+	assert.Error(t, ValidateSearchParams("", map[string]any{SparseDropRatioSearch: float64(1)}))
+}
+
+func TestSchemasByName(t *testing.T) {
+	registry := map[IndexType][]*ParamSchema{
+		"B": {intParam(NPROBE, 1, 100)},
+		"A": {intParam(NPROBE, 1, 10), intParam(EF, 1, 10)},
+		"C": {intParam(NPROBE, 1, 1000)},
+	}
+	for i := 0; i < 10; i++ {
+		schemas := schemasByName(registry, floatParam(Radius, 0, 1))
+		assert.Equal(t, float64(10), schemas[NPROBE].Max)
+		assert.Len(t, schemas, 3)
+	}
+}
@@ -0,0 +1,201 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+const (
+	CallerRoleKey      = "Caller-Role"
+	CallerNodeIDKey    = "Caller-NodeID"
+	SignTimestampKey   = "Sign-Timestamp"
+	SignNonceKey       = "Sign-Nonce"
+	SignatureKey       = "Signature"
+	signatureSeparator = "|"
+)
+
+// sign returns the HMAC-SHA256 signature of the method, caller, timestamp, nonce and request digest
+// with the internal rpc secret.
+func sign(secret, method, role, nodeID, timestamp, nonce, digest string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + signatureSeparator + role + signatureSeparator + nodeID + signatureSeparator +
+		timestamp + signatureSeparator + nonce + signatureSeparator + digest))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// requestDigest returns the SHA256 of the deterministic marshalled request, the request not being a proto message
+// is digested as empty.
+func requestDigest(req any) (string, error) {
+	var bytes []byte
+	if msg, ok := req.(proto.Message); ok && msg != nil {
+		buf := proto.NewBuffer(nil)
+		buf.SetDeterministic(true)
+		if err := buf.Marshal(msg); err != nil {
+			return "", err
+		}
+		bytes = buf.Bytes()
+	}
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func newNonce() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// nonceCache records the nonces seen within the clock skew window to reject the replayed requests.
+type nonceCache struct {
+	mu        sync.Mutex
+	expires   map[string]int64
+	lastPurge int64
+}
+
+// use returns false if the nonce has been used and not expired yet.
+func (c *nonceCache) use(nonce string, now, expire int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.expires == nil {
+		c.expires = make(map[string]int64)
+	}
+	if now != c.lastPurge {
+		for n, e := range c.expires {
+			if e < now {
+				delete(c.expires, n)
+			}
+		}
+		c.lastPurge = now
+	}
+	if e, ok := c.expires[nonce]; ok && e >= now {
+		return false
+	}
+	c.expires[nonce] = expire
+	return true
+}
+
+// SignatureInjectionUnaryClientInterceptor returns a new unary client interceptor that signs the request
+// with the role and node id of the current component, nothing is injected if the secret is not configured.
+func SignatureInjectionUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		secret := paramtable.Get().CommonCfg.InternalRPCSecret.GetValue()
+		if secret != "" {
+			role := paramtable.GetRole()
+			nodeID := paramtable.GetStringNodeID()
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+			nonce, err := newNonce()
+			if err != nil {
+				return err
+			}
+			digest, err := requestDigest(req)
+			if err != nil {
+				return err
+			}
+			ctx = metadata.AppendToOutgoingContext(ctx,
+				CallerRoleKey, role,
+				CallerNodeIDKey, nodeID,
+				SignTimestampKey, timestamp,
+				SignNonceKey, nonce,
+				SignatureKey, sign(secret, method, role, nodeID, timestamp, nonce, digest))
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// SignatureValidationUnaryServerInterceptor returns a new unary server interceptor that rejects the request
+// without valid signature, and the request to the method in authorized from the roles not listed.
+// The methods absent from authorized are allowed for all the components, and the nonce of the signature is
+// rejected if reused within the clock skew window. The signature is not verified if the secret is not configured.
+func SignatureValidationUnaryServerInterceptor(authorized map[string][]string) grpc.UnaryServerInterceptor {
+	nonces := &nonceCache{}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		secret := paramtable.Get().CommonCfg.InternalRPCSecret.GetValue()
+		if secret == "" {
+			return handler(ctx, req)
+		}
+		role, err := verifySignature(ctx, secret, info.FullMethod, req, nonces)
+		if err != nil {
+			return nil, err
+		}
+		if roles, ok := authorized[info.FullMethod]; ok {
+			permitted := false
+			for _, r := range roles {
+				permitted = permitted || r == role
+			}
+			if !permitted {
+				return nil, merr.WrapErrPrivilegeNotPermitted("%s is not permitted to call %s", role, info.FullMethod)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// verifySignature returns the caller role if the signature is valid and the nonce is not replayed.
+func verifySignature(ctx context.Context, secret, method string, req any, nonces *nonceCache) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", merr.WrapErrPrivilegeNotAuthenticated("missing signature of internal rpc %s", method)
+	}
+	get := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	role, nodeID, timestamp, nonce, signature := get(CallerRoleKey), get(CallerNodeIDKey), get(SignTimestampKey), get(SignNonceKey), get(SignatureKey)
+	if signature == "" || nonce == "" {
+		return "", merr.WrapErrPrivilegeNotAuthenticated("missing signature of internal rpc %s", method)
+	}
+	signTime, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", merr.WrapErrPrivilegeNotAuthenticated("invalid sign timestamp %s", timestamp)
+	}
+	maxSkew := paramtable.Get().CommonCfg.InternalRPCMaxClockSkew.GetAsInt64()
+	now := time.Now().Unix()
+	if skew := now - signTime; skew > maxSkew || skew < -maxSkew {
+		return "", merr.WrapErrPrivilegeNotAuthenticated("signature of internal rpc %s expired", method)
+	}
+	digest, err := requestDigest(req)
+	if err != nil {
+		return "", merr.WrapErrPrivilegeNotAuthenticated("invalid request of internal rpc %s", method)
+	}
+	if !hmac.Equal([]byte(signature), []byte(sign(secret, method, role, nodeID, timestamp, nonce, digest))) {
+		return "", merr.WrapErrPrivilegeNotAuthenticated("invalid signature of internal rpc %s from %s-%s", method, role, nodeID)
+	}
+	// the signature is accepted until signTime+maxSkew, so the nonce is kept until then
+	if !nonces.use(nonce, now, signTime+maxSkew) {
+		return "", merr.WrapErrPrivilegeNotAuthenticated("replayed signature of internal rpc %s from %s-%s", method, role, nodeID)
+	}
+	return role, nil
+}
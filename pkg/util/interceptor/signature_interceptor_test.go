@@ -0,0 +1,141 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/paramtable"
+)
+
+func TestSignatureInterceptor(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	method := "/milvus.proto.proxy.Proxy/SetRates"
+	req := &commonpb.MsgBase{SourceID: 1}
+
+	// sign the request with the client interceptor, and pass it to the server
+	signedContext := func(role string, method string) context.Context {
+		paramtable.SetRole(role)
+		var outgoing context.Context
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			outgoing = ctx
+			return nil
+		}
+		err := SignatureInjectionUnaryClientInterceptor()(context.Background(), method, req, nil, nil, invoker)
+		assert.NoError(t, err)
+		md, _ := metadata.FromOutgoingContext(outgoing)
+		return metadata.NewIncomingContext(context.Background(), md)
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	serverInterceptor := SignatureValidationUnaryServerInterceptor(map[string][]string{method: {"rootcoord"}})
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	t.Run("secret not configured", func(t *testing.T) {
+		resp, err := serverInterceptor(context.Background(), req, info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+	})
+
+	params.Save(params.CommonCfg.InternalRPCSecret.Key, "secret")
+	defer params.Reset(params.CommonCfg.InternalRPCSecret.Key)
+
+	t.Run("valid signature", func(t *testing.T) {
+		resp, err := serverInterceptor(signedContext("rootcoord", method), req, info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+
+		// methods not listed are allowed for all components
+		resp, err = serverInterceptor(signedContext("querycoord", "/milvus.proto.proxy.Proxy/GetComponentStates"), req, &grpc.UnaryServerInfo{FullMethod: "/milvus.proto.proxy.Proxy/GetComponentStates"}, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+	})
+
+	t.Run("not permitted", func(t *testing.T) {
+		_, err := serverInterceptor(signedContext("querycoord", method), req, info, handler)
+		assert.ErrorIs(t, err, merr.ErrPrivilegeNotPermitted)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		_, err := serverInterceptor(context.Background(), req, info, handler)
+		assert.ErrorIs(t, err, merr.ErrPrivilegeNotAuthenticated)
+
+		ctx := signedContext("querycoord", method)
+		md, _ := metadata.FromIncomingContext(ctx)
+		md.Set(CallerRoleKey, "rootcoord")
+		_, err = serverInterceptor(metadata.NewIncomingContext(context.Background(), md), req, info, handler)
+		assert.ErrorIs(t, err, merr.ErrPrivilegeNotAuthenticated)
+
+		// signed with another secret
+		params.Save(params.CommonCfg.InternalRPCSecret.Key, "another")
+		ctx = signedContext("rootcoord", method)
+		params.Save(params.CommonCfg.InternalRPCSecret.Key, "secret")
+		_, err = serverInterceptor(ctx, req, info, handler)
+		assert.ErrorIs(t, err, merr.ErrPrivilegeNotAuthenticated)
+	})
+
+	t.Run("tampered request", func(t *testing.T) {
+		ctx := signedContext("rootcoord", method)
+		_, err := serverInterceptor(ctx, &commonpb.MsgBase{SourceID: 2}, info, handler)
+		assert.ErrorIs(t, err, merr.ErrPrivilegeNotAuthenticated)
+	})
+
+	t.Run("replayed nonce", func(t *testing.T) {
+		ctx := signedContext("rootcoord", method)
+		_, err := serverInterceptor(ctx, req, info, handler)
+		assert.NoError(t, err)
+		_, err = serverInterceptor(ctx, req, info, handler)
+		assert.ErrorIs(t, err, merr.ErrPrivilegeNotAuthenticated)
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		md = md.Copy()
+		md.Delete(SignNonceKey)
+		_, err = serverInterceptor(metadata.NewIncomingContext(context.Background(), md), req, info, handler)
+		assert.ErrorIs(t, err, merr.ErrPrivilegeNotAuthenticated)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		timestamp := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+		digest, err := requestDigest(req)
+		assert.NoError(t, err)
+		md := metadata.Pairs(CallerRoleKey, "rootcoord", CallerNodeIDKey, "1", SignTimestampKey, timestamp, SignNonceKey, "nonce",
+			SignatureKey, sign("secret", method, "rootcoord", "1", timestamp, "nonce", digest))
+		_, err = serverInterceptor(metadata.NewIncomingContext(context.Background(), md), req, info, handler)
+		assert.ErrorIs(t, err, merr.ErrPrivilegeNotAuthenticated)
+	})
+}
+
+func TestNonceCache(t *testing.T) {
+	cache := &nonceCache{}
+	assert.True(t, cache.use("a", 100, 110))
+	assert.False(t, cache.use("a", 105, 115))
+	assert.True(t, cache.use("b", 105, 115))
+	// the expired nonces are purged
+	assert.True(t, cache.use("a", 111, 121))
+	assert.Equal(t, 2, len(cache.expires))
+}
@@ -44,6 +44,9 @@ var (
 	ErrServiceUnimplemented        = newMilvusError("service unimplemented", 10, false)
 	ErrServiceTimeTickLongDelay    = newMilvusError("time tick long delay", 11, false)
 	ErrServiceResourceInsufficient = newMilvusError("service resource insufficient", 12, true)
+	ErrServiceOperationSuspended   = newMilvusError("operation suspended", 13, true)
+	ErrServiceCircuitBreakerOpen   = newMilvusError("circuit breaker open", 14, false)
+	ErrServiceResultSizeExceeded   = newMilvusError("result size exceeded", 15, false)
 
 	// Collection related
 	ErrCollectionNotFound         = newMilvusError("collection not found", 100, false)
@@ -53,11 +56,15 @@ var (
 	ErrCollectionLoaded           = newMilvusError("collection already loaded", 104, false)
 	ErrCollectionIllegalSchema    = newMilvusError("illegal collection schema", 105, false)
 	ErrCollectionOnRecovering     = newMilvusError("collection on recovering", 106, true)
+	ErrCollectionReadOnly         = newMilvusError("collection is read-only", 107, false)
+	ErrCollectionConflict         = newMilvusError("collection already exists with different spec", 108, false)
+	ErrCollectionSchemaMismatch   = newMilvusError("collection schema version mismatch", 109, false)
 
 	// Partition related
 	ErrPartitionNotFound       = newMilvusError("partition not found", 200, false)
 	ErrPartitionNotLoaded      = newMilvusError("partition not loaded", 201, false)
 	ErrPartitionNotFullyLoaded = newMilvusError("partition not fully loaded", 202, true)
+	ErrPartitionLoaded         = newMilvusError("partition already loaded", 203, false)
 
 	// General capacity related
 	ErrGeneralCapacityExceeded = newMilvusError("general capacity exceeded", 250, false)
@@ -80,6 +80,9 @@ func (s *ErrSuite) TestWrap() {
 	s.ErrorIs(WrapErrServiceDiskLimitExceeded(110, 100, "DLE"), ErrServiceDiskLimitExceeded)
 	s.ErrorIs(WrapErrNodeNotMatch(0, 1, "SIM"), ErrNodeNotMatch)
 	s.ErrorIs(WrapErrServiceUnimplemented(errors.New("mock grpc err")), ErrServiceUnimplemented)
+	s.ErrorIs(WrapErrServiceOperationSuspended("maintenance", "dml suspended"), ErrServiceOperationSuspended)
+	s.ErrorIs(WrapErrServiceCircuitBreakerOpen("too many errors", "search rejected"), ErrServiceCircuitBreakerOpen)
+	s.ErrorIs(WrapErrServiceResultSizeExceeded(1024, 512, "query result too large"), ErrServiceResultSizeExceeded)
 
 	// Collection related
 	s.ErrorIs(WrapErrCollectionNotFound("test_collection", "failed to get collection"), ErrCollectionNotFound)
@@ -87,11 +90,15 @@ func (s *ErrSuite) TestWrap() {
 	s.ErrorIs(WrapErrCollectionNotFullyLoaded("test_collection", "failed to query"), ErrCollectionNotFullyLoaded)
 	s.ErrorIs(WrapErrCollectionNotLoaded("test_collection", "failed to alter index %s", "hnsw"), ErrCollectionNotLoaded)
 	s.ErrorIs(WrapErrCollectionOnRecovering("test_collection", "channel lost %s", "dev"), ErrCollectionOnRecovering)
+	s.ErrorIs(WrapErrCollectionReadOnly("test_collection", "failed to insert"), ErrCollectionReadOnly)
+	s.ErrorIs(WrapErrCollectionConflict("test_collection", "field dim: existing 128, requested 256"), ErrCollectionConflict)
+	s.ErrorIs(WrapErrCollectionSchemaMismatch("test_collection", "1a", "2b", "failed to search"), ErrCollectionSchemaMismatch)
 
 	// Partition related
 	s.ErrorIs(WrapErrPartitionNotFound("test_partition", "failed to get partition"), ErrPartitionNotFound)
 	s.ErrorIs(WrapErrPartitionNotLoaded("test_partition", "failed to query"), ErrPartitionNotLoaded)
 	s.ErrorIs(WrapErrPartitionNotFullyLoaded("test_partition", "failed to query"), ErrPartitionNotFullyLoaded)
+	s.ErrorIs(WrapErrPartitionLoaded("test_partition", "failed to drop"), ErrPartitionLoaded)
 
 	// ResourceGroup related
 	s.ErrorIs(WrapErrResourceGroupNotFound("test_ResourceGroup", "failed to get ResourceGroup"), ErrResourceGroupNotFound)
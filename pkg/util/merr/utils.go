@@ -380,6 +380,33 @@ func WrapErrServiceQuotaExceeded(reason string, msg ...string) error {
 	return err
 }
 
+func WrapErrServiceOperationSuspended(reason string, msg ...string) error {
+	err := wrapFieldsWithDesc(ErrServiceOperationSuspended, reason)
+	if len(msg) > 0 {
+		err = errors.Wrap(err, strings.Join(msg, "->"))
+	}
+	return err
+}
+
+func WrapErrServiceCircuitBreakerOpen(reason string, msg ...string) error {
+	err := wrapFieldsWithDesc(ErrServiceCircuitBreakerOpen, reason)
+	if len(msg) > 0 {
+		err = errors.Wrap(err, strings.Join(msg, "->"))
+	}
+	return err
+}
+
+func WrapErrServiceResultSizeExceeded(size, limit int64, msg ...string) error {
+	err := wrapFields(ErrServiceResultSizeExceeded,
+		value("size", size),
+		value("limit", limit),
+	)
+	if len(msg) > 0 {
+		err = errors.Wrap(err, strings.Join(msg, "->"))
+	}
+	return err
+}
+
 func WrapErrServiceUnimplemented(grpcErr error) error {
 	return wrapFieldsWithDesc(ErrServiceUnimplemented, grpcErr.Error())
 }
@@ -489,6 +516,35 @@ func WrapErrCollectionOnRecovering(collection any, msgAndArgs ...any) error {
 	return err
 }
 
+func WrapErrCollectionReadOnly(collection any, msg ...string) error {
+	err := wrapFields(ErrCollectionReadOnly, value("collection", collection))
+	if len(msg) > 0 {
+		err = errors.Wrap(err, strings.Join(msg, "->"))
+	}
+	return err
+}
+
+// WrapErrCollectionConflict wraps ErrCollectionConflict with collection and the differences of spec
+func WrapErrCollectionConflict(collection any, diffs ...string) error {
+	err := wrapFields(ErrCollectionConflict, value("collection", collection))
+	if len(diffs) > 0 {
+		err = errors.Wrap(err, strings.Join(diffs, "; "))
+	}
+	return err
+}
+
+func WrapErrCollectionSchemaMismatch(collection any, expected, actual string, msg ...string) error {
+	err := wrapFields(ErrCollectionSchemaMismatch,
+		value("collection", collection),
+		value("expectedVersion", expected),
+		value("actualVersion", actual),
+	)
+	if len(msg) > 0 {
+		err = errors.Wrap(err, strings.Join(msg, "->"))
+	}
+	return err
+}
+
 func WrapErrAliasNotFound(db any, alias any, msg ...string) error {
 	err := wrapFields(ErrAliasNotFound,
 		value("database", db),
@@ -547,6 +603,14 @@ func WrapErrPartitionNotFullyLoaded(partition any, msg ...string) error {
 	return err
 }
 
+func WrapErrPartitionLoaded(partition any, msg ...string) error {
+	err := wrapFields(ErrPartitionLoaded, value("partition", partition))
+	if len(msg) > 0 {
+		err = errors.Wrap(err, strings.Join(msg, "->"))
+	}
+	return err
+}
+
 func WrapGeneralCapacityExceed(newGeneralSize any, generalCapacity any, msg ...string) error {
 	err := wrapFields(ErrGeneralCapacityExceeded, value("newGeneralSize", newGeneralSize),
 		value("generalCapacity", generalCapacity))
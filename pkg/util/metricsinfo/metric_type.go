@@ -30,6 +30,16 @@ const (
 
 	// CollectionStorageMetrics means users request for collection storage metrics.
 	CollectionStorageMetrics = "collection_storage"
+
+	// TaskEventsMetrics means users request for the latest lifecycle events of the proxy tasks.
+	TaskEventsMetrics = "task_events"
+
+	// ImportSegmentsMetrics means users request for the segments of the import jobs not committed yet.
+	ImportSegmentsMetrics = "import_segments"
+
+	// CollectionReadsMetrics means users request for the last time the collections served searches or queries
+	// through any proxy.
+	CollectionReadsMetrics = "collection_reads"
 )
 
 // ParseMetricType returns the metric type of req
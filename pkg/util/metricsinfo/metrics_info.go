@@ -104,6 +104,8 @@ type QueryNodeInfos struct {
 	SystemConfigurations QueryNodeConfiguration      `json:"system_configurations"`
 	QuotaMetrics         *QueryNodeQuotaMetrics      `json:"quota_metrics"`
 	CollectionMetrics    *QueryNodeCollectionMetrics `json:"collection_metrics"`
+	// LastHeartbeat is the unix milliseconds of the last distribution heartbeat of the node to the querycoord
+	LastHeartbeat int64 `json:"last_heartbeat,omitempty"`
 }
 
 // QueryCoordConfiguration records the configuration of QueryCoord.
@@ -195,6 +197,18 @@ type DataCoordInfos struct {
 	CollectionMetrics    *DataCoordCollectionMetrics `json:"collection_metrics"`
 }
 
+// ImportSegments records the segments of the import jobs not committed yet by collection, the segments of a job
+// become visible together once the job completes.
+type ImportSegments struct {
+	Collections map[int64][]int64 `json:"collections"`
+}
+
+// CollectionReads records the unix time in milliseconds of the last search or query of the collections, merged from
+// the proxies by the rootcoord.
+type CollectionReads struct {
+	LastReads map[int64]int64 `json:"last_reads"`
+}
+
 // RootCoordConfiguration records the configuration of RootCoord.
 type RootCoordConfiguration struct {
 	MinSegmentSizeToEnableIndex int64 `json:"min_segment_size_to_enable_index"`
@@ -102,4 +102,7 @@ type DataNodeQuotaMetrics struct {
 type ProxyQuotaMetrics struct {
 	Hms HardwareMetrics
 	Rms []RateMetric
+	// CollectionLastReads is the unix time in milliseconds of the last search or query of the collections served
+	// by the proxy.
+	CollectionLastReads map[int64]int64 `json:",omitempty"`
 }
@@ -62,6 +62,7 @@ type ComponentParam struct {
 	AutoIndexConfig autoIndexConfig
 	GpuConfig       gpuConfig
 	TraceCfg        traceConfig
+	MetricsCfg      metricsConfig
 
 	RootCoordCfg  rootCoordConfig
 	ProxyCfg      proxyConfig
@@ -112,6 +113,7 @@ func (p *ComponentParam) init(bt *BaseTable) {
 	p.QuotaConfig.init(bt)
 	p.AutoIndexConfig.init(bt)
 	p.TraceCfg.init(bt)
+	p.MetricsCfg.init(bt)
 
 	p.RootCoordCfg.init(bt)
 	p.ProxyCfg.init(bt)
@@ -245,6 +247,15 @@ type commonConfig struct {
 	BloomFilterSize       ParamItem `refreshable:"true"`
 	MaxBloomFalsePositive ParamItem `refreshable:"true"`
 	PanicWhenPluginFail   ParamItem `refreshable:"false"`
+
+	InternalRPCSecret       ParamItem `refreshable:"true"`
+	InternalRPCMaxClockSkew ParamItem `refreshable:"true"`
+
+	DDLNotificationEnabled       ParamItem `refreshable:"false"`
+	DDLNotificationTopic         ParamItem `refreshable:"false"`
+	DDLNotificationKafkaAddress  ParamItem `refreshable:"false"`
+	DDLNotificationWebhookURL    ParamItem `refreshable:"false"`
+	DDLNotificationRetryInterval ParamItem `refreshable:"true"`
 }
 
 func (p *commonConfig) init(base *BaseTable) {
@@ -732,6 +743,62 @@ like the old password verification when updating the credential`,
 		Doc:          "panic or not when plugin fail to init",
 	}
 	p.PanicWhenPluginFail.Init(base.mgr)
+
+	p.InternalRPCSecret = ParamItem{
+		Key:          "common.security.internalRPCSecret",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "shared secret to sign the internal rpc requests between components, the signature is not verified if empty",
+	}
+	p.InternalRPCSecret.Init(base.mgr)
+
+	p.InternalRPCMaxClockSkew = ParamItem{
+		Key:          "common.security.internalRPCMaxClockSkew",
+		Version:      "2.4.3",
+		DefaultValue: "300",
+		Doc:          "max difference in seconds between the signing time of internal rpc request and the server time",
+	}
+	p.InternalRPCMaxClockSkew.Init(base.mgr)
+
+	p.DDLNotificationEnabled = ParamItem{
+		Key:          "common.ddlNotification.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether the rootcoord and the datacoord emit the successful ddl operations to the notification topic or webhook",
+	}
+	p.DDLNotificationEnabled.Init(base.mgr)
+
+	p.DDLNotificationTopic = ParamItem{
+		Key:          "common.ddlNotification.topic",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "the kafka topic the ddl events are produced to, disabled if empty",
+	}
+	p.DDLNotificationTopic.Init(base.mgr)
+
+	p.DDLNotificationKafkaAddress = ParamItem{
+		Key:          "common.ddlNotification.kafkaAddress",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "the kafka brokers of the notification topic, the kafka of the cluster is used if empty",
+	}
+	p.DDLNotificationKafkaAddress.Init(base.mgr)
+
+	p.DDLNotificationWebhookURL = ParamItem{
+		Key:          "common.ddlNotification.webhookURL",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "the url the ddl events are posted to, disabled if empty",
+	}
+	p.DDLNotificationWebhookURL.Init(base.mgr)
+
+	p.DDLNotificationRetryInterval = ParamItem{
+		Key:          "common.ddlNotification.retryInterval",
+		Version:      "2.4.3",
+		DefaultValue: "1000",
+		Doc:          "the interval in milliseconds to retry delivering a ddl event",
+	}
+	p.DDLNotificationRetryInterval.Init(base.mgr)
 }
 
 type gpuConfig struct {
@@ -812,6 +879,49 @@ Fractions >= 1 will always sample. Fractions < 0 are treated as zero.`,
 	t.OtlpSecure.Init(base.mgr)
 }
 
+type metricsConfig struct {
+	OtlpEnable   ParamItem `refreshable:"false"`
+	OtlpEndpoint ParamItem `refreshable:"false"`
+	OtlpSecure   ParamItem `refreshable:"false"`
+	OtlpInterval ParamItem `refreshable:"false"`
+}
+
+func (t *metricsConfig) init(base *BaseTable) {
+	t.OtlpEnable = ParamItem{
+		Key:          "metrics.otlp.enable",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether to push the metrics to the OpenTelemetry collector, alongside the prometheus endpoint",
+		Export:       true,
+	}
+	t.OtlpEnable.Init(base.mgr)
+
+	t.OtlpEndpoint = ParamItem{
+		Key:     "metrics.otlp.endpoint",
+		Version: "2.4.3",
+		Doc:     "the grpc endpoint of the OpenTelemetry collector, example: \"127.0.0.1:4317\"",
+		Export:  true,
+	}
+	t.OtlpEndpoint.Init(base.mgr)
+
+	t.OtlpSecure = ParamItem{
+		Key:          "metrics.otlp.secure",
+		Version:      "2.4.3",
+		DefaultValue: "true",
+		Export:       true,
+	}
+	t.OtlpSecure.Init(base.mgr)
+
+	t.OtlpInterval = ParamItem{
+		Key:          "metrics.otlp.interval",
+		Version:      "2.4.3",
+		DefaultValue: "30",
+		Doc:          "the interval in seconds to push the metrics",
+		Export:       true,
+	}
+	t.OtlpInterval.Init(base.mgr)
+}
+
 type logConfig struct {
 	Level        ParamItem `refreshable:"false"`
 	RootPath     ParamItem `refreshable:"false"`
@@ -1034,6 +1144,164 @@ type proxyConfig struct {
 	GracefulStopTimeout ParamItem `refreshable:"true"`
 
 	SlowQuerySpanInSeconds ParamItem `refreshable:"true"`
+
+	SchemaRegistryEnabled ParamItem `refreshable:"true"`
+	SchemaRegistryAddress ParamItem `refreshable:"true"`
+	SchemaRegistryTimeout ParamItem `refreshable:"true"`
+
+	UsageAccountingEnabled            ParamItem `refreshable:"true"`
+	UsageAccountingCheckpointInterval ParamItem `refreshable:"false"`
+
+	FullScanDeleteGuardEnabled ParamItem `refreshable:"true"`
+
+	CollectionRecycleBinEnabled   ParamItem `refreshable:"true"`
+	CollectionRecycleBinRetention ParamItem `refreshable:"true"`
+
+	AliasPrivilegeMode ParamItem `refreshable:"true"`
+
+	PasswordComplexity     ParamItem `refreshable:"true"`
+	PasswordHistorySize    ParamItem `refreshable:"true"`
+	PasswordExpiration     ParamItem `refreshable:"true"`
+	AuthLockoutMaxFailures ParamItem `refreshable:"true"`
+	AuthLockoutBaseDelay   ParamItem `refreshable:"true"`
+	AuthLockoutMaxDelay    ParamItem `refreshable:"true"`
+
+	LDAPEnabled              ParamItem `refreshable:"true"`
+	LDAPAddress              ParamItem `refreshable:"true"`
+	LDAPUserDNTemplate       ParamItem `refreshable:"true"`
+	LDAPBindDN               ParamItem `refreshable:"true"`
+	LDAPBindPassword         ParamItem `refreshable:"true"`
+	LDAPGroupBaseDN          ParamItem `refreshable:"true"`
+	LDAPGroupMemberAttribute ParamItem `refreshable:"true"`
+	LDAPGroupNameAttribute   ParamItem `refreshable:"true"`
+	LDAPGroupRoleMapping     ParamItem `refreshable:"true"`
+	LDAPSyncInterval         ParamItem `refreshable:"false"`
+	LDAPCacheTTL             ParamItem `refreshable:"true"`
+	LDAPTimeout              ParamItem `refreshable:"true"`
+
+	EncryptionLocalMasterKeys ParamItem `refreshable:"true"`
+
+	RatesRecoveryMaxAge ParamItem `refreshable:"true"`
+
+	TimestampBatchEnabled ParamItem `refreshable:"true"`
+	TimestampBatchMaxWait ParamItem `refreshable:"true"`
+	TimestampBatchMaxSize ParamItem `refreshable:"true"`
+
+	EventuallyReadMaxStaleness ParamItem `refreshable:"true"`
+
+	TimeTickShardNum ParamItem `refreshable:"false"`
+
+	RerankExternalEnabled ParamItem `refreshable:"true"`
+	RerankExternalTimeout ParamItem `refreshable:"true"`
+	RerankExternalScorers ParamItem `refreshable:"true"`
+
+	MirrorMaxConcurrency ParamItem `refreshable:"false"`
+	MirrorTimeout        ParamItem `refreshable:"true"`
+
+	CircuitBreakerEnabled            ParamItem `refreshable:"true"`
+	CircuitBreakerErrorRateThreshold ParamItem `refreshable:"true"`
+	CircuitBreakerMinRequests        ParamItem `refreshable:"true"`
+	CircuitBreakerWindow             ParamItem `refreshable:"true"`
+	CircuitBreakerCooldown           ParamItem `refreshable:"true"`
+
+	ProduceRetryMaxAttempts  ParamItem `refreshable:"true"`
+	ProduceRetryBackoff      ParamItem `refreshable:"true"`
+	ProduceDeadLetterEnabled ParamItem `refreshable:"true"`
+	ProduceDeadLetterChannel ParamItem `refreshable:"false"`
+
+	TaskEventBufferSize      ParamItem `refreshable:"false"`
+	MaxResultSize            ParamItem `refreshable:"true"`
+	ResultSizeLimitBehavior  ParamItem `refreshable:"true"`
+	ResultSpillPath          ParamItem `refreshable:"true"`
+	ResultSpillURLExpiration ParamItem `refreshable:"true"`
+	ResultSpillGCInterval    ParamItem `refreshable:"false"`
+	DeduplicateResultsByPK   ParamItem `refreshable:"true"`
+
+	IngestionEnabled          ParamItem `refreshable:"false"`
+	IngestionBatchRows        ParamItem `refreshable:"true"`
+	IngestionFlushInterval    ParamItem `refreshable:"false"`
+	IngestionRetryInterval    ParamItem `refreshable:"true"`
+	IngestionAllowedAddresses ParamItem `refreshable:"true"`
+
+	ChangeStreamEnabled          ParamItem `refreshable:"true"`
+	ChangeStreamMaxSubscriptions ParamItem `refreshable:"true"`
+
+	ReadPreferenceDefault            ParamItem `refreshable:"true"`
+	ReadPreferenceLocalZone          ParamItem `refreshable:"true"`
+	ReadPreferenceResourceGroupZones ParamItem `refreshable:"true"`
+	ReadPreferenceRefreshInterval    ParamItem `refreshable:"false"`
+
+	JobRetention  ParamItem `refreshable:"true"`
+	JobMaxTracked ParamItem `refreshable:"true"`
+
+	JobNotificationEnabled          ParamItem `refreshable:"false"`
+	JobNotificationCheckInterval    ParamItem `refreshable:"false"`
+	JobNotificationTimeout          ParamItem `refreshable:"true"`
+	JobNotificationMaxRetries       ParamItem `refreshable:"true"`
+	JobNotificationRetryInterval    ParamItem `refreshable:"true"`
+	JobNotificationAllowedAddresses ParamItem `refreshable:"true"`
+
+	QueryEnrichmentMaxKeys   ParamItem `refreshable:"true"`
+	QueryEnrichmentBatchSize ParamItem `refreshable:"true"`
+
+	BatchSearchMaxRequests ParamItem `refreshable:"true"`
+	BatchSearchConcurrency ParamItem `refreshable:"true"`
+
+	SearchSplitEnabled     ParamItem `refreshable:"true"`
+	SearchSplitMaxNQ       ParamItem `refreshable:"true"`
+	SearchSplitMaxBytes    ParamItem `refreshable:"true"`
+	SearchSplitConcurrency ParamItem `refreshable:"true"`
+
+	DistributedRateLimitEnabled          ParamItem `refreshable:"true"`
+	DistributedRateLimitWindow           ParamItem `refreshable:"true"`
+	DistributedRateLimitBatchRatio       ParamItem `refreshable:"true"`
+	DistributedRateLimitFallbackInterval ParamItem `refreshable:"true"`
+
+	SessionAffinityEnabled ParamItem `refreshable:"true"`
+	SessionAffinityTTL     ParamItem `refreshable:"true"`
+
+	QueryMemoryBudget      ParamItem `refreshable:"true"`
+	QueryMemoryWaitTimeout ParamItem `refreshable:"true"`
+
+	MemoryWatchdogEnabled       ParamItem `refreshable:"true"`
+	MemoryWatchdogInterval      ParamItem `refreshable:"false"`
+	MemoryWatchdogHighWatermark ParamItem `refreshable:"true"`
+	MemoryWatchdogLowWatermark  ParamItem `refreshable:"true"`
+	MemoryWatchdogShedWatermark ParamItem `refreshable:"true"`
+	MemoryWatchdogRateFactor    ParamItem `refreshable:"true"`
+
+	IndexCoverageInterval ParamItem `refreshable:"false"`
+
+	ReleaseRecentReadWindow ParamItem `refreshable:"true"`
+
+	RejectUnindexableLike ParamItem `refreshable:"true"`
+
+	InsertSmoothingChannelRate ParamItem `refreshable:"true"`
+	InsertSmoothingMaxLag      ParamItem `refreshable:"true"`
+	InsertSmoothingMaxWait     ParamItem `refreshable:"true"`
+
+	TimeTickLagHealthThreshold   ParamItem `refreshable:"true"`
+	TimeTickLagCriticalThreshold ParamItem `refreshable:"true"`
+
+	SessionMutationTTL ParamItem `refreshable:"true"`
+
+	FilterStatsAdvisorSelectivity ParamItem `refreshable:"true"`
+
+	FilterOverFetchMinFactor  ParamItem `refreshable:"true"`
+	FilterOverFetchMaxFactor  ParamItem `refreshable:"true"`
+	FilterSelectivityCacheTTL ParamItem `refreshable:"true"`
+
+	PartitionPruningEnabled  ParamItem `refreshable:"true"`
+	PartitionPruningStatsTTL ParamItem `refreshable:"true"`
+
+	RejectNonFiniteVectors ParamItem `refreshable:"true"`
+
+	MaxRequestSizePerMethod ParamGroup `refreshable:"false"`
+
+	GrpcReflectionEnabled ParamItem `refreshable:"false"`
+
+	FaultInjectionEnabled ParamItem `refreshable:"true"`
+	FaultInjectionRules   ParamItem `refreshable:"true"`
 }
 
 func (p *proxyConfig) init(base *BaseTable) {
@@ -1419,6 +1687,962 @@ please adjust in embedded Milvus: false`,
 		Export:       true,
 	}
 	p.SlowQuerySpanInSeconds.Init(base.mgr)
+
+	p.SchemaRegistryEnabled = ParamItem{
+		Key:          "proxy.schemaRegistry.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether to allow CreateCollection to reference a schema in schema registry",
+	}
+	p.SchemaRegistryEnabled.Init(base.mgr)
+
+	p.SchemaRegistryAddress = ParamItem{
+		Key:          "proxy.schemaRegistry.address",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "http address of schema registry, the schema referenced by id is fetched from {address}/{id}, the schema referenced by url must be in the same scheme and host",
+	}
+	p.SchemaRegistryAddress.Init(base.mgr)
+
+	p.SchemaRegistryTimeout = ParamItem{
+		Key:          "proxy.schemaRegistry.timeout",
+		Version:      "2.4.3",
+		DefaultValue: "10",
+		Doc:          "timeout of fetching schema from schema registry, in seconds",
+	}
+	p.SchemaRegistryTimeout.Init(base.mgr)
+
+	p.UsageAccountingEnabled = ParamItem{
+		Key:          "proxy.usageAccounting.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether to account the usage of each database and user",
+	}
+	p.UsageAccountingEnabled.Init(base.mgr)
+
+	p.UsageAccountingCheckpointInterval = ParamItem{
+		Key:          "proxy.usageAccounting.checkpointInterval",
+		Version:      "2.4.3",
+		DefaultValue: "60",
+		Doc:          "interval of persisting the usage counters to etcd, in seconds",
+	}
+	p.UsageAccountingCheckpointInterval.Init(base.mgr)
+
+	p.FullScanDeleteGuardEnabled = ParamItem{
+		Key:          "proxy.fullScanDeleteGuard.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc: `whether to reject the delete whose expression matches the entire primary key space(e.g. pk >= 0),
+unless allow_full_scan_delete is set in the request metadata`,
+	}
+	p.FullScanDeleteGuardEnabled.Init(base.mgr)
+
+	p.CollectionRecycleBinEnabled = ParamItem{
+		Key:          "proxy.collectionRecycleBin.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether to move the dropped collection into recycle bin, which could be restored before retention",
+	}
+	p.CollectionRecycleBinEnabled.Init(base.mgr)
+
+	p.CollectionRecycleBinRetention = ParamItem{
+		Key:          "proxy.collectionRecycleBin.retention",
+		Version:      "2.4.3",
+		DefaultValue: "86400",
+		Doc:          "retention of the collections in recycle bin before dropped permanently, in seconds",
+	}
+	p.CollectionRecycleBinRetention.Init(base.mgr)
+
+	p.AliasPrivilegeMode = ParamItem{
+		Key:          "proxy.aliasPrivilegeMode",
+		Version:      "2.4.3",
+		DefaultValue: "strict",
+		Doc: `how the privileges of collection are checked when it's accessed through alias,
+strict: only the privileges granted on the name in request are checked,
+alias: the privileges granted on the aliased collection also apply when accessed through alias,
+bidirectional: additionally, the privileges granted on any alias also apply when accessed through the collection name`,
+	}
+	p.AliasPrivilegeMode.Init(base.mgr)
+
+	p.PasswordComplexity = ParamItem{
+		Key:          "proxy.passwordPolicy.complexity",
+		Version:      "2.4.3",
+		DefaultValue: "0",
+		Doc:          "the number of character classes(uppercase, lowercase, digit and special character) a password must contain, 0 means no requirement",
+	}
+	p.PasswordComplexity.Init(base.mgr)
+
+	p.PasswordHistorySize = ParamItem{
+		Key:          "proxy.passwordPolicy.historySize",
+		Version:      "2.4.3",
+		DefaultValue: "0",
+		Doc:          "the number of recent passwords which can't be reused when updating the credential, 0 means no limit",
+	}
+	p.PasswordHistorySize.Init(base.mgr)
+
+	p.PasswordExpiration = ParamItem{
+		Key:          "proxy.passwordPolicy.expiration",
+		Version:      "2.4.3",
+		DefaultValue: "0",
+		Doc:          "the password must be updated after expiration, in days, 0 means never expire",
+	}
+	p.PasswordExpiration.Init(base.mgr)
+
+	p.AuthLockoutMaxFailures = ParamItem{
+		Key:          "proxy.authLockout.maxFailures",
+		Version:      "2.4.3",
+		DefaultValue: "0",
+		Doc:          "the account is locked after the number of consecutive authentication failures, 0 means never lock",
+	}
+	p.AuthLockoutMaxFailures.Init(base.mgr)
+
+	p.AuthLockoutBaseDelay = ParamItem{
+		Key:          "proxy.authLockout.baseDelay",
+		Version:      "2.4.3",
+		DefaultValue: "1",
+		Doc:          "the lockout duration of the first lock, doubled on each following failure, in seconds",
+	}
+	p.AuthLockoutBaseDelay.Init(base.mgr)
+
+	p.AuthLockoutMaxDelay = ParamItem{
+		Key:          "proxy.authLockout.maxDelay",
+		Version:      "2.4.3",
+		DefaultValue: "300",
+		Doc:          "the max lockout duration, in seconds",
+	}
+	p.AuthLockoutMaxDelay.Init(base.mgr)
+
+	p.LDAPEnabled = ParamItem{
+		Key:          "proxy.ldap.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc: `whether to authenticate users against the LDAP server, the users rejected by LDAP are rejected,
+the internal credentials are used only if the LDAP server is unavailable, and always used for the root user`,
+	}
+	p.LDAPEnabled.Init(base.mgr)
+
+	p.LDAPAddress = ParamItem{
+		Key:          "proxy.ldap.address",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "address of the LDAP server, like ldap://localhost:389 or ldaps://localhost:636",
+	}
+	p.LDAPAddress.Init(base.mgr)
+
+	p.LDAPUserDNTemplate = ParamItem{
+		Key:          "proxy.ldap.userDNTemplate",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "template of the user DN to bind, {username} is replaced by the username, like uid={username},ou=users,dc=example,dc=com",
+	}
+	p.LDAPUserDNTemplate.Init(base.mgr)
+
+	p.LDAPBindDN = ParamItem{
+		Key:          "proxy.ldap.bindDN",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "DN of the service account to search groups, anonymous if empty",
+	}
+	p.LDAPBindDN.Init(base.mgr)
+
+	p.LDAPBindPassword = ParamItem{
+		Key:          "proxy.ldap.bindPassword",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "password of the service account to search groups",
+	}
+	p.LDAPBindPassword.Init(base.mgr)
+
+	p.LDAPGroupBaseDN = ParamItem{
+		Key:          "proxy.ldap.groupBaseDN",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "base DN to search groups, group to role mapping is disabled if empty",
+	}
+	p.LDAPGroupBaseDN.Init(base.mgr)
+
+	p.LDAPGroupMemberAttribute = ParamItem{
+		Key:          "proxy.ldap.groupMemberAttribute",
+		Version:      "2.4.3",
+		DefaultValue: "member",
+		Doc:          "attribute of group which contains the member DN",
+	}
+	p.LDAPGroupMemberAttribute.Init(base.mgr)
+
+	p.LDAPGroupNameAttribute = ParamItem{
+		Key:          "proxy.ldap.groupNameAttribute",
+		Version:      "2.4.3",
+		DefaultValue: "cn",
+		Doc:          "attribute of group which is the group name",
+	}
+	p.LDAPGroupNameAttribute.Init(base.mgr)
+
+	p.LDAPGroupRoleMapping = ParamItem{
+		Key:          "proxy.ldap.groupRoleMapping",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "mapping from LDAP group to role, like group1:role1,group2:role2",
+	}
+	p.LDAPGroupRoleMapping.Init(base.mgr)
+
+	p.LDAPSyncInterval = ParamItem{
+		Key:          "proxy.ldap.syncInterval",
+		Version:      "2.4.3",
+		DefaultValue: "300",
+		Doc:          "interval to sync the groups of LDAP users, in seconds",
+	}
+	p.LDAPSyncInterval.Init(base.mgr)
+
+	p.LDAPCacheTTL = ParamItem{
+		Key:          "proxy.ldap.cacheTTL",
+		Version:      "2.4.3",
+		DefaultValue: "300",
+		Doc:          "expiration of the successful LDAP binds cache, in seconds",
+	}
+	p.LDAPCacheTTL.Init(base.mgr)
+
+	p.LDAPTimeout = ParamItem{
+		Key:          "proxy.ldap.timeout",
+		Version:      "2.4.3",
+		DefaultValue: "5",
+		Doc:          "timeout of the LDAP requests, in seconds",
+	}
+	p.LDAPTimeout.Init(base.mgr)
+
+	p.EncryptionLocalMasterKeys = ParamItem{
+		Key:          "proxy.encryption.localMasterKeys",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "master keys of the built-in KMS for field encryption, like key1:<base64 of 32 bytes key>,key2:<...>, only for testing purpose",
+	}
+	p.EncryptionLocalMasterKeys.Init(base.mgr)
+
+	p.RatesRecoveryMaxAge = ParamItem{
+		Key:          "proxy.ratesRecoveryMaxAge",
+		Version:      "2.4.3",
+		DefaultValue: "300",
+		Doc:          "max age in seconds of the persisted rates which are applied when proxy starts, 0 to disable the recovery",
+	}
+	p.RatesRecoveryMaxAge.Init(base.mgr)
+
+	p.TimestampBatchEnabled = ParamItem{
+		Key:          "proxy.timestampBatch.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "true",
+		Doc:          "whether to merge the concurrent timestamp allocations into one rootcoord request",
+	}
+	p.TimestampBatchEnabled.Init(base.mgr)
+
+	p.TimestampBatchMaxWait = ParamItem{
+		Key:          "proxy.timestampBatch.maxWait",
+		Version:      "2.4.3",
+		DefaultValue: "1",
+		Doc: `max time in milliseconds to wait for more allocations before sending the batch,
+the wait only happens when the previous batch merged concurrent allocations, 0 to never wait`,
+	}
+	p.TimestampBatchMaxWait.Init(base.mgr)
+
+	p.TimestampBatchMaxSize = ParamItem{
+		Key:          "proxy.timestampBatch.maxSize",
+		Version:      "2.4.3",
+		DefaultValue: "1024",
+		Doc:          "max number of allocations merged into one rootcoord request",
+	}
+	p.TimestampBatchMaxSize.Init(base.mgr)
+
+	p.EventuallyReadMaxStaleness = ParamItem{
+		Key:          "proxy.eventuallyReadMaxStaleness",
+		Version:      "2.4.3",
+		DefaultValue: "1000",
+		Doc: `max staleness in milliseconds of the cached timestamp used by the search and query with Eventually consistency,
+a new timestamp is allocated if the cached one is older, 0 to disable the cache`,
+	}
+	p.EventuallyReadMaxStaleness.Init(base.mgr)
+
+	p.TimeTickShardNum = ParamItem{
+		Key:          "proxy.timeTickShardNum",
+		Version:      "2.4.3",
+		DefaultValue: "16",
+		Doc:          "number of the shards the physical channels are distributed to in the channels time ticker, to reduce the lock contention",
+	}
+	p.TimeTickShardNum.Init(base.mgr)
+
+	p.RerankExternalEnabled = ParamItem{
+		Key:          "proxy.rerank.external.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether to allow the search request to rerank the results with an external http scorer",
+	}
+	p.RerankExternalEnabled.Init(base.mgr)
+
+	p.RerankExternalTimeout = ParamItem{
+		Key:          "proxy.rerank.external.timeout",
+		Version:      "2.4.3",
+		DefaultValue: "1000",
+		Doc:          "max timeout in milliseconds of calling the external http scorer, the smaller one of the request is used",
+	}
+	p.RerankExternalTimeout.Init(base.mgr)
+
+	p.RerankExternalScorers = ParamItem{
+		Key:          "proxy.rerank.external.scorers",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "the external http scorers allowed to use, like scorer1:http://host1/score,scorer2:<...>, the search request refers to the scorer by name",
+	}
+	p.RerankExternalScorers.Init(base.mgr)
+
+	p.MirrorMaxConcurrency = ParamItem{
+		Key:          "proxy.mirror.maxConcurrency",
+		Version:      "2.4.3",
+		DefaultValue: "16",
+		Doc:          "max number of the in-flight mirrored requests, the requests exceeding it are not mirrored",
+	}
+	p.MirrorMaxConcurrency.Init(base.mgr)
+
+	p.MirrorTimeout = ParamItem{
+		Key:          "proxy.mirror.timeout",
+		Version:      "2.4.3",
+		DefaultValue: "10000",
+		Doc:          "timeout in milliseconds of the mirrored requests",
+	}
+	p.MirrorTimeout.Init(base.mgr)
+
+	p.CircuitBreakerEnabled = ParamItem{
+		Key:          "proxy.circuitBreaker.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether to fail the search and query requests of the collection fast when its downstream error rate is too high",
+	}
+	p.CircuitBreakerEnabled.Init(base.mgr)
+
+	p.CircuitBreakerErrorRateThreshold = ParamItem{
+		Key:          "proxy.circuitBreaker.errorRateThreshold",
+		Version:      "2.4.3",
+		DefaultValue: "0.5",
+		Doc:          "the breaker of the collection trips when the ratio of downstream errors in the window exceeds it",
+	}
+	p.CircuitBreakerErrorRateThreshold.Init(base.mgr)
+
+	p.CircuitBreakerMinRequests = ParamItem{
+		Key:          "proxy.circuitBreaker.minRequests",
+		Version:      "2.4.3",
+		DefaultValue: "20",
+		Doc:          "min number of requests in the window before the breaker could trip",
+	}
+	p.CircuitBreakerMinRequests.Init(base.mgr)
+
+	p.CircuitBreakerWindow = ParamItem{
+		Key:          "proxy.circuitBreaker.window",
+		Version:      "2.4.3",
+		DefaultValue: "10",
+		Doc:          "seconds, the window to count the error rate",
+	}
+	p.CircuitBreakerWindow.Init(base.mgr)
+
+	p.CircuitBreakerCooldown = ParamItem{
+		Key:          "proxy.circuitBreaker.cooldown",
+		Version:      "2.4.3",
+		DefaultValue: "30",
+		Doc:          "seconds, the requests are failed fast in the cooldown after the breaker trips, then a probe request is allowed",
+	}
+	p.CircuitBreakerCooldown.Init(base.mgr)
+
+	p.ProduceRetryMaxAttempts = ParamItem{
+		Key:          "proxy.produceRetry.maxAttempts",
+		Version:      "2.4.3",
+		DefaultValue: "3",
+		Doc:          "max attempts to produce each dml message to msgstream, 1 disables the retry, the message may be duplicated by the retry if the failed attempt was persisted anyway",
+	}
+	p.ProduceRetryMaxAttempts.Init(base.mgr)
+
+	p.ProduceRetryBackoff = ParamItem{
+		Key:          "proxy.produceRetry.backoff",
+		Version:      "2.4.3",
+		DefaultValue: "100",
+		Doc:          "ms, the initial backoff between the produce attempts, doubled after each attempt up to 10 times of it",
+	}
+	p.ProduceRetryBackoff.Init(base.mgr)
+
+	p.ProduceDeadLetterEnabled = ParamItem{
+		Key:          "proxy.produceRetry.deadLetter.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether to produce the dml messages failed after all the attempts to the dead letter channel",
+	}
+	p.ProduceDeadLetterEnabled.Init(base.mgr)
+
+	p.ProduceDeadLetterChannel = ParamItem{
+		Key:          "proxy.produceRetry.deadLetter.channel",
+		Version:      "2.4.3",
+		DefaultValue: "proxy-dead-letter",
+		Doc:          "name of the dead letter channel, prefixed by the cluster channel name prefix",
+	}
+	p.ProduceDeadLetterChannel.Init(base.mgr)
+
+	p.TaskEventBufferSize = ParamItem{
+		Key:          "proxy.taskEvents.bufferSize",
+		Version:      "2.4.3",
+		DefaultValue: "1024",
+		Doc:          "the number of the latest task lifecycle events kept in memory, queried by the task_events metric type",
+	}
+	p.TaskEventBufferSize.Init(base.mgr)
+
+	p.MaxResultSize = ParamItem{
+		Key:          "proxy.resultSizeLimit.maxSize",
+		Version:      "2.4.3",
+		DefaultValue: "0",
+		Doc:          "MB, the max serialized size of the search and query results returned by the proxy, 0 means no limit",
+	}
+	p.MaxResultSize.Init(base.mgr)
+
+	p.ResultSizeLimitBehavior = ParamItem{
+		Key:          "proxy.resultSizeLimit.behavior",
+		Version:      "2.4.3",
+		DefaultValue: "error",
+		Doc: `the behavior when the result exceeds the max size,
+error: fail the request with the result size exceeded error,
+spill: write the result to the object storage and return a presigned url to download it`,
+	}
+	p.ResultSizeLimitBehavior.Init(base.mgr)
+
+	p.ResultSpillPath = ParamItem{
+		Key:          "proxy.resultSizeLimit.spillPath",
+		Version:      "2.4.3",
+		DefaultValue: "proxy_result_spill",
+		Doc:          "the path under the root path of the object storage to write the spilled results to",
+	}
+	p.ResultSpillPath.Init(base.mgr)
+
+	p.ResultSpillURLExpiration = ParamItem{
+		Key:          "proxy.resultSizeLimit.urlExpiration",
+		Version:      "2.4.3",
+		DefaultValue: "3600",
+		Doc:          "seconds, the expiration of the presigned url of the spilled results",
+	}
+	p.ResultSpillURLExpiration.Init(base.mgr)
+
+	p.ResultSpillGCInterval = ParamItem{
+		Key:          "proxy.resultSizeLimit.gcInterval",
+		Version:      "2.4.3",
+		DefaultValue: "600",
+		Doc:          "seconds between the removals of the spilled results older than the url expiration",
+	}
+	p.ResultSpillGCInterval.Init(base.mgr)
+
+	p.DeduplicateResultsByPK = ParamItem{
+		Key:          "proxy.deduplicateResultsByPK",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc: `whether to deduplicate the hits sharing a primary key across the segments and replicas in the whole reduce,
+including the skipped offset, the hit with the best score or the row with the latest timestamp is kept`,
+	}
+	p.DeduplicateResultsByPK.Init(base.mgr)
+
+	p.IngestionEnabled = ParamItem{
+		Key:          "proxy.ingestion.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc: `whether to consume the kafka ingestion sources on this proxy,
+each source is consumed by only one of the enabled proxies, which holds its ownership lease in etcd`,
+	}
+	p.IngestionEnabled.Init(base.mgr)
+
+	p.IngestionBatchRows = ParamItem{
+		Key:          "proxy.ingestion.batchRows",
+		Version:      "2.4.3",
+		DefaultValue: "1000",
+		Doc:          "the default number of the consumed rows inserted as a batch, overridden by the batch rows of the source",
+	}
+	p.IngestionBatchRows.Init(base.mgr)
+
+	p.IngestionFlushInterval = ParamItem{
+		Key:          "proxy.ingestion.flushInterval",
+		Version:      "2.4.3",
+		DefaultValue: "1000",
+		Doc:          "ms, the interval to insert the consumed rows and save the checkpoint even if the batch is not full",
+	}
+	p.IngestionFlushInterval.Init(base.mgr)
+
+	p.IngestionRetryInterval = ParamItem{
+		Key:          "proxy.ingestion.retryInterval",
+		Version:      "2.4.3",
+		DefaultValue: "10",
+		Doc:          "seconds, the interval to restart consuming the source after it failed, and to take over the sources not owned by any proxy",
+	}
+	p.IngestionRetryInterval.Init(base.mgr)
+
+	p.IngestionAllowedAddresses = ParamItem{
+		Key:          "proxy.ingestion.allowedAddresses",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc: `comma separated addresses of the external kafka the ingestion sources are allowed to consume,
+each is a host, a host:port or a domain suffix like *.example.com, only the kafka of milvus is allowed if empty`,
+	}
+	p.IngestionAllowedAddresses.Init(base.mgr)
+
+	p.ChangeStreamEnabled = ParamItem{
+		Key:          "proxy.changeStream.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether to allow subscribing the inserts and deletes of the collections, each subscription consumes the dml channels of the collection",
+	}
+	p.ChangeStreamEnabled.Init(base.mgr)
+
+	p.ChangeStreamMaxSubscriptions = ParamItem{
+		Key:          "proxy.changeStream.maxSubscriptions",
+		Version:      "2.4.3",
+		DefaultValue: "16",
+		Doc:          "the max number of the concurrent change subscriptions of a proxy",
+	}
+	p.ChangeStreamMaxSubscriptions.Init(base.mgr)
+
+	p.ReadPreferenceDefault = ParamItem{
+		Key:          "proxy.readPreference.default",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc: `the read preference of the search and query without the read_preference hint,
+any, local_zone, lowest_latency, group:<resource group> or zone:<zone>`,
+	}
+	p.ReadPreferenceDefault.Init(base.mgr)
+
+	p.ReadPreferenceLocalZone = ParamItem{
+		Key:          "proxy.readPreference.localZone",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "the zone the proxy runs in",
+	}
+	p.ReadPreferenceLocalZone.Init(base.mgr)
+
+	p.ReadPreferenceResourceGroupZones = ParamItem{
+		Key:          "proxy.readPreference.resourceGroupZones",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc:          "the zone labels of the resource groups in json, e.g. {\"rg1\": \"us-east-1a\"}",
+	}
+	p.ReadPreferenceResourceGroupZones.Init(base.mgr)
+
+	p.ReadPreferenceRefreshInterval = ParamItem{
+		Key:          "proxy.readPreference.refreshInterval",
+		Version:      "2.4.3",
+		DefaultValue: "30",
+		Doc:          "the interval in seconds to refresh the query nodes of the resource groups",
+	}
+	p.ReadPreferenceRefreshInterval.Init(base.mgr)
+
+	p.JobRetention = ParamItem{
+		Key:          "proxy.jobs.retention",
+		Version:      "2.4.3",
+		DefaultValue: "3600",
+		Doc:          "the seconds to keep the jobs done in the job registry of the proxy",
+	}
+	p.JobRetention.Init(base.mgr)
+
+	p.JobMaxTracked = ParamItem{
+		Key:          "proxy.jobs.maxTracked",
+		Version:      "2.4.3",
+		DefaultValue: "10000",
+		Doc:          "the max number of the jobs kept in the job registry of the proxy, the oldest jobs done are removed first",
+	}
+	p.JobMaxTracked.Init(base.mgr)
+
+	p.JobNotificationEnabled = ParamItem{
+		Key:          "proxy.jobNotification.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether to notify the registered callbacks of the completion of the jobs initiated through the proxy",
+	}
+	p.JobNotificationEnabled.Init(base.mgr)
+
+	p.JobNotificationCheckInterval = ParamItem{
+		Key:          "proxy.jobNotification.checkInterval",
+		Version:      "2.4.3",
+		DefaultValue: "3000",
+		Doc:          "the interval in milliseconds to check the state of the tracked jobs",
+	}
+	p.JobNotificationCheckInterval.Init(base.mgr)
+
+	p.JobNotificationTimeout = ParamItem{
+		Key:          "proxy.jobNotification.timeout",
+		Version:      "2.4.3",
+		DefaultValue: "86400",
+		Doc:          "the max seconds to track a job, the callbacks are notified of the timeout after that",
+	}
+	p.JobNotificationTimeout.Init(base.mgr)
+
+	p.JobNotificationMaxRetries = ParamItem{
+		Key:          "proxy.jobNotification.maxRetries",
+		Version:      "2.4.3",
+		DefaultValue: "5",
+		Doc:          "the max attempts to deliver a notification to a callback",
+	}
+	p.JobNotificationMaxRetries.Init(base.mgr)
+
+	p.JobNotificationRetryInterval = ParamItem{
+		Key:          "proxy.jobNotification.retryInterval",
+		Version:      "2.4.3",
+		DefaultValue: "1000",
+		Doc:          "the initial interval in milliseconds to retry delivering a notification, doubled on each retry",
+	}
+	p.JobNotificationRetryInterval.Init(base.mgr)
+
+	p.JobNotificationAllowedAddresses = ParamItem{
+		Key:          "proxy.jobNotification.allowedAddresses",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc: `comma separated addresses of the webhooks and the external kafka the job callbacks are allowed to notify,
+each is a host, a host:port or a domain suffix like *.example.com, only the kafka of milvus is allowed if empty`,
+	}
+	p.JobNotificationAllowedAddresses.Init(base.mgr)
+
+	p.QueryEnrichmentMaxKeys = ParamItem{
+		Key:          "proxy.queryEnrichment.maxKeys",
+		Version:      "2.4.3",
+		DefaultValue: "10000",
+		Doc:          "the max distinct keys of a query looked up in the enrich collection, the query fails if exceeded",
+	}
+	p.QueryEnrichmentMaxKeys.Init(base.mgr)
+
+	p.QueryEnrichmentBatchSize = ParamItem{
+		Key:          "proxy.queryEnrichment.batchSize",
+		Version:      "2.4.3",
+		DefaultValue: "1000",
+		Doc:          "the max keys looked up in the enrich collection by a single query",
+	}
+	p.QueryEnrichmentBatchSize.Init(base.mgr)
+
+	p.BatchSearchMaxRequests = ParamItem{
+		Key:          "proxy.batchSearch.maxRequests",
+		Version:      "2.4.3",
+		DefaultValue: "1000",
+		Doc:          "the max search requests of a batch search",
+	}
+	p.BatchSearchMaxRequests.Init(base.mgr)
+
+	p.BatchSearchConcurrency = ParamItem{
+		Key:          "proxy.batchSearch.concurrency",
+		Version:      "2.4.3",
+		DefaultValue: "8",
+		Doc:          "the max searches of a batch search executed concurrently, after the searches only differing in the vectors merged",
+	}
+	p.BatchSearchConcurrency.Init(base.mgr)
+
+	p.SearchSplitEnabled = ParamItem{
+		Key:          "proxy.search.split.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc: `whether to split the searches of the nq or the vectors over the limits into the sub searches, instead of failing them,
+the sub searches are served at the same timestamp allocated on split, which waits for the data up to it like the strong consistency`,
+	}
+	p.SearchSplitEnabled.Init(base.mgr)
+
+	p.SearchSplitMaxNQ = ParamItem{
+		Key:          "proxy.search.split.maxNQ",
+		Version:      "2.4.3",
+		DefaultValue: "0",
+		Doc:          "the max nq of a sub search, the nq limit of quotaAndLimits.limits.maxNQ if 0 or larger",
+	}
+	p.SearchSplitMaxNQ.Init(base.mgr)
+
+	p.SearchSplitMaxBytes = ParamItem{
+		Key:          "proxy.search.split.maxBytes",
+		Version:      "2.4.3",
+		DefaultValue: "67108864",
+		Doc:          "the max bytes of the vectors of a sub search, to keep the messages to the shards under the grpc limits",
+	}
+	p.SearchSplitMaxBytes.Init(base.mgr)
+
+	p.SearchSplitConcurrency = ParamItem{
+		Key:          "proxy.search.split.concurrency",
+		Version:      "2.4.3",
+		DefaultValue: "4",
+		Doc:          "the max sub searches of a split search executed concurrently",
+	}
+	p.SearchSplitConcurrency.Init(base.mgr)
+
+	p.DistributedRateLimitEnabled = ParamItem{
+		Key:          "proxy.distributedRateLimit.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc: `whether to enforce the cluster level rates in the token buckets shared by the proxies in etcd,
+instead of the even share of the rates of each proxy. The proxy falls back to its local limiters if etcd fails`,
+	}
+	p.DistributedRateLimitEnabled.Init(base.mgr)
+
+	p.DistributedRateLimitWindow = ParamItem{
+		Key:          "proxy.distributedRateLimit.window",
+		Version:      "2.4.3",
+		DefaultValue: "1000",
+		Doc:          "the window of the shared token buckets in milliseconds, the tokens of a window are the rate times the window",
+	}
+	p.DistributedRateLimitWindow.Init(base.mgr)
+
+	p.DistributedRateLimitBatchRatio = ParamItem{
+		Key:          "proxy.distributedRateLimit.batchRatio",
+		Version:      "2.4.3",
+		DefaultValue: "0.1",
+		Doc:          "the ratio of the tokens of a window acquired by a proxy from etcd at a time",
+	}
+	p.DistributedRateLimitBatchRatio.Init(base.mgr)
+
+	p.DistributedRateLimitFallbackInterval = ParamItem{
+		Key:          "proxy.distributedRateLimit.fallbackInterval",
+		Version:      "2.4.3",
+		DefaultValue: "10",
+		Doc:          "the seconds the proxy uses its local limiters after etcd fails, before retrying the shared token buckets",
+	}
+	p.DistributedRateLimitFallbackInterval.Init(base.mgr)
+
+	p.SessionAffinityEnabled = ParamItem{
+		Key:          "proxy.sessionAffinity.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "true",
+		Doc:          "whether to hint the id of the proxy and the affinity ttl in the responses, for the client load balancers to keep the sessions on a proxy",
+	}
+	p.SessionAffinityEnabled.Init(base.mgr)
+
+	p.SessionAffinityTTL = ParamItem{
+		Key:          "proxy.sessionAffinity.ttl",
+		Version:      "2.4.3",
+		DefaultValue: "300",
+		Doc:          "the recommended seconds for the clients to keep a session on the proxy",
+	}
+	p.SessionAffinityTTL.Init(base.mgr)
+
+	p.QueryMemoryBudget = ParamItem{
+		Key:          "proxy.queryMemory.budget",
+		Version:      "2.4.3",
+		DefaultValue: "0",
+		Doc:          "the MB of the approximate memory the in-flight searches and queries may use on the proxy, 0 means unlimited",
+	}
+	p.QueryMemoryBudget.Init(base.mgr)
+
+	p.QueryMemoryWaitTimeout = ParamItem{
+		Key:          "proxy.queryMemory.waitTimeout",
+		Version:      "2.4.3",
+		DefaultValue: "1000",
+		Doc:          "the milliseconds a search or query waits for the memory budget before rejected, 0 means rejected immediately",
+	}
+	p.QueryMemoryWaitTimeout.Init(base.mgr)
+
+	p.MemoryWatchdogEnabled = ParamItem{
+		Key:          "proxy.memoryWatchdog.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether to shed the queued searches and queries and lower the rates of the proxy under memory pressure",
+	}
+	p.MemoryWatchdogEnabled.Init(base.mgr)
+
+	p.MemoryWatchdogInterval = ParamItem{
+		Key:          "proxy.memoryWatchdog.interval",
+		Version:      "2.4.3",
+		DefaultValue: "1000",
+		Doc:          "the milliseconds between the checks of the memory usage",
+	}
+	p.MemoryWatchdogInterval.Init(base.mgr)
+
+	p.MemoryWatchdogHighWatermark = ParamItem{
+		Key:          "proxy.memoryWatchdog.highWatermark",
+		Version:      "2.4.3",
+		DefaultValue: "0.9",
+		Doc:          "the ratio of the memory usage to the memory limit to start shedding by lowering the rates",
+	}
+	p.MemoryWatchdogHighWatermark.Init(base.mgr)
+
+	p.MemoryWatchdogLowWatermark = ParamItem{
+		Key:          "proxy.memoryWatchdog.lowWatermark",
+		Version:      "2.4.3",
+		DefaultValue: "0.8",
+		Doc:          "the ratio of the memory usage to the memory limit to stop shedding and restore the rates",
+	}
+	p.MemoryWatchdogLowWatermark.Init(base.mgr)
+
+	p.MemoryWatchdogShedWatermark = ParamItem{
+		Key:          "proxy.memoryWatchdog.shedWatermark",
+		Version:      "2.4.3",
+		DefaultValue: "0.95",
+		Doc:          "the ratio of the memory usage to the memory limit to shed the queued searches and queries while shedding",
+	}
+	p.MemoryWatchdogShedWatermark.Init(base.mgr)
+
+	p.MemoryWatchdogRateFactor = ParamItem{
+		Key:          "proxy.memoryWatchdog.rateFactor",
+		Version:      "2.4.3",
+		DefaultValue: "0.5",
+		Doc:          "the factor the dml and dql rates are scaled by while shedding",
+	}
+	p.MemoryWatchdogRateFactor.Init(base.mgr)
+
+	p.IndexCoverageInterval = ParamItem{
+		Key:          "proxy.indexCoverage.interval",
+		Version:      "2.4.3",
+		DefaultValue: "60",
+		Doc:          "seconds between the updates of the index coverage metric of the loaded collections, 0 to disable",
+	}
+	p.IndexCoverageInterval.Init(base.mgr)
+
+	p.ReleaseRecentReadWindow = ParamItem{
+		Key:          "proxy.releaseCheck.recentReadWindow",
+		Version:      "2.4.3",
+		DefaultValue: "0",
+		Doc: `the collection served searches or queries within the window in minutes can not be released
+without the force_release request metadata, 0 to disable the check`,
+	}
+	p.ReleaseRecentReadWindow.Init(base.mgr)
+
+	p.RejectUnindexableLike = ParamItem{
+		Key:          "proxy.likeCheck.rejectUnindexable",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc: `reject the like patterns starting with a wildcard on the fields with a trie or inverted index,
+which scan all the rows, instead of returning the hints in the result status`,
+	}
+	p.RejectUnindexableLike.Init(base.mgr)
+
+	p.InsertSmoothingChannelRate = ParamItem{
+		Key:          "proxy.insertSmoothing.channelRate",
+		Version:      "2.4.3",
+		DefaultValue: "0",
+		Doc: `MB/s, the rate of the inserted data the proxy produces to each dml virtual channel, the bursts above
+the rate wait before produced, 0 to disable the smoothing`,
+	}
+	p.InsertSmoothingChannelRate.Init(base.mgr)
+
+	p.InsertSmoothingMaxLag = ParamItem{
+		Key:          "proxy.insertSmoothing.maxLag",
+		Version:      "2.4.3",
+		DefaultValue: "10",
+		Doc: `seconds, the rate of the channel the data nodes consume behind the max lag is halved, down to 1/8 of
+the channel rate, and doubled back once the lag is below half of the max lag`,
+	}
+	p.InsertSmoothingMaxLag.Init(base.mgr)
+
+	p.InsertSmoothingMaxWait = ParamItem{
+		Key:          "proxy.insertSmoothing.maxWait",
+		Version:      "2.4.3",
+		DefaultValue: "5",
+		Doc: `seconds, the max time an insert waits for the smoothed rate of the dml channels before it is enqueued,
+the inserts waiting longer are rejected as rate limited`,
+	}
+	p.InsertSmoothingMaxWait.Init(base.mgr)
+
+	p.TimeTickLagHealthThreshold = ParamItem{
+		Key:          "proxy.timeTickLag.healthThreshold",
+		Version:      "2.4.3",
+		DefaultValue: "60",
+		Doc:          "seconds, the proxy reports unhealthy if the time tick of a pchannel lags behind more than the threshold, 0 to disable",
+	}
+	p.TimeTickLagHealthThreshold.Init(base.mgr)
+
+	p.TimeTickLagCriticalThreshold = ParamItem{
+		Key:          "proxy.timeTickLag.criticalThreshold",
+		Version:      "2.4.3",
+		DefaultValue: "0",
+		Doc: `seconds, the proxy rejects the dml requests with the rate limit error for the clients to back off if the
+time tick of a pchannel lags behind more than the threshold, 0 to disable`,
+	}
+	p.TimeTickLagCriticalThreshold.Init(base.mgr)
+
+	p.SessionMutationTTL = ParamItem{
+		Key:          "proxy.sessionConsistency.ttl",
+		Version:      "2.4.3",
+		DefaultValue: "600",
+		Doc: `seconds, the proxy keeps the timestamp of the last mutation of a client session on a collection for the ttl,
+to fill the guarantee timestamp of the Session consistency reads of the session, 0 to disable`,
+	}
+	p.SessionMutationTTL.Init(base.mgr)
+
+	p.FilterStatsAdvisorSelectivity = ParamItem{
+		Key:          "proxy.filterStats.advisorSelectivity",
+		Version:      "2.4.3",
+		DefaultValue: "0.05",
+		Doc: `the search with filter_stats suggests raising the search params of the index if the filter keeps less than
+the ratio of the rows of a shard`,
+	}
+	p.FilterStatsAdvisorSelectivity.Init(base.mgr)
+
+	p.FilterOverFetchMinFactor = ParamItem{
+		Key:          "proxy.filterOverFetch.minFactor",
+		Version:      "2.4.3",
+		DefaultValue: "1",
+		Doc:          "the min factor of the topk the shards are searched for by the search with filter, the results are reduced to the topk by the proxy",
+	}
+	p.FilterOverFetchMinFactor.Init(base.mgr)
+
+	p.FilterOverFetchMaxFactor = ParamItem{
+		Key:          "proxy.filterOverFetch.maxFactor",
+		Version:      "2.4.3",
+		DefaultValue: "1",
+		Doc: `the max factor of the topk the shards are searched for by the search with filter, the factor grows as the
+estimated selectivity of the filter drops, 1 to disable the over-fetch`,
+	}
+	p.FilterOverFetchMaxFactor.Init(base.mgr)
+
+	p.FilterSelectivityCacheTTL = ParamItem{
+		Key:          "proxy.filterOverFetch.selectivityTTL",
+		Version:      "2.4.3",
+		DefaultValue: "300",
+		Doc:          "seconds, the estimated selectivity of a filter is cached for the ttl, and then counted again by the next search with the filter",
+	}
+	p.FilterSelectivityCacheTTL.Init(base.mgr)
+
+	p.PartitionPruningEnabled = ParamItem{
+		Key:          "proxy.partitionPruning.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc: `whether the proxy skips the partitions whose partition statistics show no row within the ranges of the
+clustering key in the filter, for the bounded and eventually consistent reads`,
+	}
+	p.PartitionPruningEnabled.Init(base.mgr)
+
+	p.PartitionPruningStatsTTL = ParamItem{
+		Key:          "proxy.partitionPruning.statsTTL",
+		Version:      "2.4.3",
+		DefaultValue: "5",
+		Doc: `seconds, the partition statistics are cached for the ttl, the rows written within the ttl may be missed
+by the pruned reads like by the bounded consistency`,
+	}
+	p.PartitionPruningStatsTTL.Init(base.mgr)
+
+	p.RejectNonFiniteVectors = ParamItem{
+		Key:          "proxy.rejectNonFiniteVectors",
+		Version:      "2.4.3",
+		DefaultValue: "true",
+		Doc:          "reject the inserted and upserted float vectors with NaN or Inf values, they are written as is if false",
+	}
+	p.RejectNonFiniteVectors.Init(base.mgr)
+
+	p.MaxRequestSizePerMethod = ParamGroup{
+		KeyPrefix: "proxy.maxRequestSize.",
+		Version:   "2.4.3",
+		Doc: `the max size in bytes of the requests of the specified methods, such as proxy.maxRequestSize.Insert,
+the methods not set are limited by proxy.grpc.serverMaxRecvSize`,
+	}
+	p.MaxRequestSizePerMethod.Init(base.mgr)
+
+	p.GrpcReflectionEnabled = ParamItem{
+		Key:          "proxy.grpcReflection.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether to serve the grpc reflection on the external grpc server, for the tools like grpcurl",
+	}
+	p.GrpcReflectionEnabled.Init(base.mgr)
+
+	p.FaultInjectionEnabled = ParamItem{
+		Key:          "proxy.faultInjection.enabled",
+		Version:      "2.4.3",
+		DefaultValue: "false",
+		Doc:          "whether to inject the faults of proxy.faultInjection.rules for the resilience tests, takes effect only in the builds with the test tag",
+	}
+	p.FaultInjectionEnabled.Init(base.mgr)
+
+	p.FaultInjectionRules = ParamItem{
+		Key:          "proxy.faultInjection.rules",
+		Version:      "2.4.3",
+		DefaultValue: "",
+		Doc: `the faults to inject in json, such as [{"point": "produce", "collection": "c1", "percentage": 50, "action": "error"}],
+the points are enqueue, coord_call, produce and reduce, the actions are latency with latencyMs, error with message and drop`,
+	}
+	p.FaultInjectionRules.Init(base.mgr)
 }
 
 // /////////////////////////////////////////////////////////////////////////////
@@ -1491,6 +2715,8 @@ type queryCoordConfig struct {
 	CheckNodeSessionInterval       ParamItem `refreshable:"false"`
 	GracefulStopTimeout            ParamItem `refreshable:"true"`
 	EnableStoppingBalance          ParamItem `refreshable:"true"`
+
+	ProgressiveLoadMinSegmentRatio ParamItem `refreshable:"true"`
 }
 
 func (p *queryCoordConfig) init(base *BaseTable) {
@@ -1967,6 +3193,16 @@ func (p *queryCoordConfig) init(base *BaseTable) {
 		Export:       true,
 	}
 	p.EnableStoppingBalance.Init(base.mgr)
+
+	p.ProgressiveLoadMinSegmentRatio = ParamItem{
+		Key:          "queryCoord.progressiveLoad.minSegmentRatio",
+		Version:      "2.4.3",
+		DefaultValue: "1",
+		Doc: `the shard leader serving at least the ratio of the sealed segments of its shard in the current target is
+readable if no leader of the shard serves all of them, e.g. after the query nodes restart, the searches report the
+coverage of the partially loaded shards. 1 to read the fully loaded shard leaders only`,
+	}
+	p.ProgressiveLoadMinSegmentRatio.Init(base.mgr)
 }
 
 // /////////////////////////////////////////////////////////////////////////////
@@ -2699,6 +3935,10 @@ type dataCoordConfig struct {
 	ImportCheckIntervalLow   ParamItem `refreshable:"true"`
 	MaxFilesPerImportReq     ParamItem `refreshable:"true"`
 	WaitForIndex             ParamItem `refreshable:"true"`
+	ImportExternalStorage    ParamItem `refreshable:"true"`
+
+	ImportExternalStorageAllowedAddresses ParamItem `refreshable:"true"`
+	ImportExternalStorageCredentialKey    ParamItem `refreshable:"true"`
 
 	GracefulStopTimeout ParamItem `refreshable:"true"`
 }
@@ -3276,6 +4516,33 @@ During compaction, the size of segment # of rows is able to exceed segment max #
 	}
 	p.WaitForIndex.Init(base.mgr)
 
+	p.ImportExternalStorage = ParamItem{
+		Key:          "dataCoord.import.enableExternalStorage",
+		Version:      "2.4.3",
+		Doc:          "Whether to allow the import requests to read the files from the object storage specified in the request with its own credentials.",
+		DefaultValue: "false",
+		PanicIfEmpty: false,
+	}
+	p.ImportExternalStorage.Init(base.mgr)
+
+	p.ImportExternalStorageAllowedAddresses = ParamItem{
+		Key:          "dataCoord.import.externalStorageAllowedAddresses",
+		Version:      "2.4.3",
+		Doc:          "Comma-separated addresses of the external storages allowed to import from, as host, host:port or *.domain, none is allowed if empty.",
+		DefaultValue: "",
+		PanicIfEmpty: false,
+	}
+	p.ImportExternalStorageAllowedAddresses.Init(base.mgr)
+
+	p.ImportExternalStorageCredentialKey = ParamItem{
+		Key:          "dataCoord.import.externalStorageCredentialKey",
+		Version:      "2.4.3",
+		Doc:          "Base64 AES key the credentials of the external storages are encrypted by in the import jobs, required to import from external storage.",
+		DefaultValue: "",
+		PanicIfEmpty: false,
+	}
+	p.ImportExternalStorageCredentialKey.Init(base.mgr)
+
 	p.GracefulStopTimeout = ParamItem{
 		Key:          "dataCoord.gracefulStopTimeout",
 		Version:      "2.3.7",
@@ -1,12 +1,17 @@
 package paramtable
 
 type httpConfig struct {
-	Enabled              ParamItem `refreshable:"false"`
-	DebugMode            ParamItem `refreshable:"false"`
-	Port                 ParamItem `refreshable:"false"`
-	AcceptTypeAllowInt64 ParamItem `refreshable:"true"`
-	EnablePprof          ParamItem `refreshable:"false"`
-	RequestTimeoutMs     ParamItem `refreshable:"false"`
+	Enabled                  ParamItem `refreshable:"false"`
+	DebugMode                ParamItem `refreshable:"false"`
+	Port                     ParamItem `refreshable:"false"`
+	AcceptTypeAllowInt64     ParamItem `refreshable:"true"`
+	EnablePprof              ParamItem `refreshable:"false"`
+	RequestTimeoutMs         ParamItem `refreshable:"false"`
+	EnableDebugEndpoints     ParamItem `refreshable:"false"`
+	StreamInsertChunkRows    ParamItem `refreshable:"true"`
+	StreamInsertAckChunks    ParamItem `refreshable:"true"`
+	StreamInsertMaxChunkRows ParamItem `refreshable:"true"`
+	StreamInsertMaxLineSize  ParamItem `refreshable:"true"`
 }
 
 func (p *httpConfig) init(base *BaseTable) {
@@ -55,4 +60,44 @@ func (p *httpConfig) init(base *BaseTable) {
 		Export:       true,
 	}
 	p.EnablePprof.Init(base.mgr)
+
+	p.EnableDebugEndpoints = ParamItem{
+		Key:          "proxy.http.enableDebugEndpoints",
+		DefaultValue: "false",
+		Version:      "2.4.3",
+		Doc:          "Whether to expose the pprof, trace and expvar endpoints on the restful port, only accessible by the admin users when authorization is enabled",
+	}
+	p.EnableDebugEndpoints.Init(base.mgr)
+
+	p.StreamInsertChunkRows = ParamItem{
+		Key:          "proxy.http.streamInsert.chunkRows",
+		DefaultValue: "1000",
+		Version:      "2.4.3",
+		Doc:          "The number of rows accumulated by the streaming insert endpoint before they are inserted as one chunk",
+	}
+	p.StreamInsertChunkRows.Init(base.mgr)
+
+	p.StreamInsertAckChunks = ParamItem{
+		Key:          "proxy.http.streamInsert.ackChunks",
+		DefaultValue: "1",
+		Version:      "2.4.3",
+		Doc:          "The number of inserted chunks between two watermark acknowledgements of the streaming insert endpoint",
+	}
+	p.StreamInsertAckChunks.Init(base.mgr)
+
+	p.StreamInsertMaxChunkRows = ParamItem{
+		Key:          "proxy.http.streamInsert.maxChunkRows",
+		DefaultValue: "10000",
+		Version:      "2.4.3",
+		Doc:          "The max number of rows of a chunk the streaming insert endpoint accepts from the request",
+	}
+	p.StreamInsertMaxChunkRows.Init(base.mgr)
+
+	p.StreamInsertMaxLineSize = ParamItem{
+		Key:          "proxy.http.streamInsert.maxLineSize",
+		DefaultValue: "4194304",
+		Version:      "2.4.3",
+		Doc:          "The max size in bytes of a line of the streaming insert endpoint, the stream is stopped once a line exceeds it",
+	}
+	p.StreamInsertMaxLineSize.Init(base.mgr)
 }
@@ -15,4 +15,9 @@ func TestHTTPConfig_Init(t *testing.T) {
 	assert.Equal(t, cfg.Port.GetValue(), "")
 	assert.Equal(t, cfg.AcceptTypeAllowInt64.GetValue(), "true")
 	assert.Equal(t, cfg.EnablePprof.GetAsBool(), true)
+	assert.Equal(t, cfg.EnableDebugEndpoints.GetAsBool(), false)
+	assert.Equal(t, 1000, cfg.StreamInsertChunkRows.GetAsInt())
+	assert.Equal(t, 1, cfg.StreamInsertAckChunks.GetAsInt())
+	assert.Equal(t, 10000, cfg.StreamInsertMaxChunkRows.GetAsInt())
+	assert.Equal(t, 4194304, cfg.StreamInsertMaxLineSize.GetAsInt())
 }
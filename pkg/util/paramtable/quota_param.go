@@ -121,6 +121,8 @@ type quotaConfig struct {
 	DQLMaxQueryRatePerPartition   ParamItem `refreshable:"true"`
 	DQLMinQueryRatePerPartition   ParamItem `refreshable:"true"`
 
+	DQLMaxSearchWorkPerCollection ParamItem `refreshable:"true"`
+
 	// limits
 	MaxCollectionNum               ParamItem `refreshable:"true"`
 	MaxCollectionNumPerDB          ParamItem `refreshable:"true"`
@@ -1481,6 +1483,24 @@ The maximum rate will not be greater than ` + "max" + `.`,
 	}
 	p.DQLMinQueryRatePerPartition.Init(base.mgr)
 
+	p.DQLMaxSearchWorkPerCollection = ParamItem{
+		Key:          "quotaAndLimits.dql.searchWork.collection.max",
+		Version:      "2.4.3",
+		DefaultValue: max,
+		Formatter: func(v string) string {
+			if !p.DQLLimitEnabled.GetAsBool() {
+				return max
+			}
+			// [0, inf)
+			if getAsFloat(v) < 0 {
+				return max
+			}
+			return v
+		},
+		Doc: "estimated vector comparisons (nq × number of rows) of search per second, default no limit",
+	}
+	p.DQLMaxSearchWorkPerCollection.Init(base.mgr)
+
 	// limits
 	p.MaxCollectionNum = ParamItem{
 		Key:          "quotaAndLimits.limits.maxCollectionNum",
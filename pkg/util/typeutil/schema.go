@@ -21,6 +21,7 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"math"
 	"sort"
 	"strconv"
@@ -1586,3 +1587,31 @@ func SparseFloatRowDim(row []byte) int64 {
 	}
 	return int64(SparseFloatRowIndexAt(row, SparseFloatRowElementCount(row)-1)) + 1
 }
+
+// schemaVersionTypeParams are the type params changing the layout of the field data.
+var schemaVersionTypeParams = []string{common.DimKey, common.MaxLengthKey, common.MaxCapacityKey}
+
+// SchemaVersion returns the version of the collection schema, derived from the layout of the fields,
+// so that it is the same on all the nodes for the same schema without any extra meta.
+// The properties, e.g. mmap and ttl, are not counted as they don't change the plan of the requests.
+// The system fields are skipped too, as they are only in the schemas of the coordinators, not the ones of the proxy.
+func SchemaVersion(schema *schemapb.CollectionSchema) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%t;", schema.GetEnableDynamicField())
+	for _, field := range schema.GetFields() {
+		if common.IsSystemField(field.GetFieldID()) {
+			continue
+		}
+		fmt.Fprintf(h, "%d,%s,%d,%d,%t,%t,%t,%t", field.GetFieldID(), field.GetName(), field.GetDataType(), field.GetElementType(),
+			field.GetIsPrimaryKey(), field.GetAutoID(), field.GetIsPartitionKey(), field.GetIsDynamic())
+		for _, key := range schemaVersionTypeParams {
+			for _, kv := range field.GetTypeParams() {
+				if kv.GetKey() == key {
+					fmt.Fprintf(h, ",%s=%s", key, kv.GetValue())
+				}
+			}
+		}
+		h.Write([]byte{';'})
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
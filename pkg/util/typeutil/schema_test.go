@@ -2225,3 +2225,46 @@ func TestParseJsonSparseFloatRow(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestSchemaVersion(t *testing.T) {
+	genSchema := func() *schemapb.CollectionSchema {
+		return &schemapb.CollectionSchema{
+			Name: "test",
+			Fields: []*schemapb.FieldSchema{
+				{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+				{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{
+					{Key: common.DimKey, Value: "128"},
+				}},
+			},
+		}
+	}
+	version := SchemaVersion(genSchema())
+	assert.NotEmpty(t, version)
+	assert.Equal(t, version, SchemaVersion(genSchema()))
+
+	// properties and the other type params don't change the version
+	schema := genSchema()
+	schema.Properties = []*commonpb.KeyValuePair{{Key: common.MmapEnabledKey, Value: "true"}}
+	schema.Fields[1].TypeParams = append(schema.Fields[1].TypeParams, &commonpb.KeyValuePair{Key: common.MmapEnabledKey, Value: "true"})
+	assert.Equal(t, version, SchemaVersion(schema))
+
+	schema = genSchema()
+	schema.Fields[1].TypeParams[0].Value = "256"
+	assert.NotEqual(t, version, SchemaVersion(schema))
+
+	schema = genSchema()
+	schema.Fields = append(schema.Fields, &schemapb.FieldSchema{FieldID: 102, Name: "age", DataType: schemapb.DataType_Int32})
+	assert.NotEqual(t, version, SchemaVersion(schema))
+
+	schema = genSchema()
+	schema.EnableDynamicField = true
+	assert.NotEqual(t, version, SchemaVersion(schema))
+
+	// the schema of the proxy has no system fields, but the one of the querycoord has
+	schema = genSchema()
+	schema.Fields = append([]*schemapb.FieldSchema{
+		{FieldID: common.RowIDField, Name: common.RowIDFieldName, DataType: schemapb.DataType_Int64},
+		{FieldID: common.TimeStampField, Name: common.TimeStampFieldName, DataType: schemapb.DataType_Int64},
+	}, schema.Fields...)
+	assert.Equal(t, version, SchemaVersion(schema))
+}
@@ -166,6 +166,11 @@ function test_cmd()
 go test -race -cover -tags dynamic "${ROOT_DIR}/cmd/tools/..." -failfast -count=1 -ldflags="-r ${RPATH}"
 }
 
+function test_embedded()
+{
+go test -race -cover -tags dynamic,embedded "${ROOT_DIR}/cmd/components/embedded/..." -failfast -count=1 -ldflags="-r ${RPATH}"
+}
+
 function test_all()
 {
 test_proxy
@@ -244,6 +249,9 @@ case "${TEST_TAG}" in
     cmd)
 	test_cmd
         ;;
+    embedded)
+	test_embedded
+        ;;
     *)   echo "Test All";
 	test_all
     ;;